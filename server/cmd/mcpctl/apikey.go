@@ -0,0 +1,299 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/config"
+	"github.com/mcpany/core/server/pkg/storage"
+	"github.com/mcpany/core/server/pkg/storage/sqlite"
+	"github.com/mcpany/core/server/pkg/webhooks"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// openStore opens the server's SQLite database.
+//
+// Parameters:
+//   - path: string. The database file.
+//
+// Returns:
+//   - storage.Storage: The store.
+//   - func() error: Closes the database.
+//   - error: An error if the database cannot be opened.
+func openStore(path string) (storage.Storage, func() error, error) {
+	db, err := sqlite.NewDB(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return sqlite.NewStore(db), db.Close, nil
+}
+
+// newAPIKeyCmd creates the apikey command group.
+//
+// These commands manage per-client API keys directly in the server's SQLite database.
+// Changes take effect immediately on a running server sharing that database.
+//
+// Returns:
+//   - *cobra.Command: The configured apikey command.
+func newAPIKeyCmd() *cobra.Command {
+	var dbPath string
+	apiKeyCmd := &cobra.Command{
+		Use:     "apikey",
+		Aliases: []string{"key"},
+		Short:   "Manage per-client API keys",
+	}
+	apiKeyCmd.PersistentFlags().StringVar(&dbPath, "db-path", envOr("MCPANY_DB_PATH", "data/mcpany.db"), "Path to the server's SQLite database file. Env: MCPANY_DB_PATH")
+
+	var (
+		scopes      []string
+		profile     string
+		rps         float64
+		burst       int64
+		owner       string
+		notifyURL   string
+		rotateEvery time.Duration
+		gracePeriod time.Duration
+	)
+	createCmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create an API key. The key is printed once and cannot be retrieved later",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if rotateEvery > 0 && notifyURL == "" {
+				return fmt.Errorf("--rotate-every requires --notify-url: the new keys are only sent there")
+			}
+			store, closeDB, err := openStore(dbPath)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = closeDB() }()
+
+			template := configv1.ClientApiKey_builder{
+				Name:              proto.String(args[0]),
+				Scopes:            scopes,
+				ProfileId:         proto.String(profile),
+				RequestsPerSecond: proto.Float64(rps),
+				Burst:             proto.Int64(burst),
+				Owner:             proto.String(owner),
+				NotifyUrl:         proto.String(notifyURL),
+			}.Build()
+			if rotateEvery < 0 || gracePeriod < 0 {
+				return fmt.Errorf("--rotate-every and --grace-period must not be negative")
+			}
+			if rotateEvery > 0 || gracePeriod > 0 {
+				policy := &configv1.ApiKeyRotationPolicy{}
+				if rotateEvery > 0 {
+					policy.SetInterval(durationpb.New(rotateEvery))
+				}
+				if gracePeriod > 0 {
+					policy.SetGracePeriod(durationpb.New(gracePeriod))
+				}
+				template.SetRotationPolicy(policy)
+			}
+			record, key, err := auth.CreateClientAPIKey(context.Background(), store, template)
+			if err != nil {
+				return fmt.Errorf("failed to create api key: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "ID:  %s\n", record.GetId())
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Key: %s\n", key)
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "\nStore this key now; it will not be shown again.")
+			return nil
+		},
+	}
+	createCmd.Flags().StringSliceVar(&scopes, "scope", nil, "Role granted to the key (e.g. viewer); admin is reserved. Can be specified multiple times")
+	createCmd.Flags().StringVar(&profile, "profile", "", "Profile applied to requests made with the key")
+	createCmd.Flags().Float64Var(&rps, "rate-limit", 0, "Maximum requests per second for the key (0 = unlimited)")
+	createCmd.Flags().Int64Var(&burst, "burst", 0, "Burst size for the rate limit (defaults to the rate limit)")
+	createCmd.Flags().StringVar(&owner, "owner", "", "Owner of the key (e.g. a team or email address), named in notifications")
+	createCmd.Flags().StringVar(&notifyURL, "notify-url", "", "https webhook notified when the key is rotated or its replaced key expires")
+	createCmd.Flags().DurationVar(&rotateEvery, "rotate-every", 0, "Rotate the key on this schedule (e.g. 2160h); the new key is sent to --notify-url")
+	createCmd.Flags().DurationVar(&gracePeriod, "grace-period", 0, "How long the replaced key stays valid after a rotation (default 24h)")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List API keys",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			store, closeDB, err := openStore(dbPath)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = closeDB() }()
+
+			keys, err := store.ListAPIKeys(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to list api keys: %w", err)
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "ID\tNAME\tSCOPES\tPROFILE\tRATE LIMIT\tCREATED\tSTATUS")
+			for _, k := range keys {
+				rateLimit := "-"
+				if k.GetRequestsPerSecond() > 0 {
+					rateLimit = fmt.Sprintf("%g/s", k.GetRequestsPerSecond())
+				}
+				state := "active"
+				switch {
+				case k.GetRevokedAt() != "":
+					state = "revoked " + k.GetRevokedAt()
+				case k.GetReplacedBy() != "":
+					state = fmt.Sprintf("deprecated until %s (replaced by %s)", k.GetExpiresAt(), k.GetReplacedBy())
+				case k.GetExpiresAt() != "":
+					state = "expires " + k.GetExpiresAt()
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					k.GetId(), k.GetName(), dashIfEmpty(strings.Join(k.GetScopes(), ",")), dashIfEmpty(k.GetProfileId()), rateLimit, k.GetCreatedAt(), state)
+			}
+			return w.Flush()
+		},
+	}
+
+	revokeCmd := &cobra.Command{
+		Use:   "revoke <id>",
+		Short: "Revoke an API key",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, closeDB, err := openStore(dbPath)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = closeDB() }()
+
+			if _, err := auth.RevokeClientAPIKey(context.Background(), store, args[0]); err != nil {
+				if errors.Is(err, auth.ErrAPIKeyNotFound) {
+					return fmt.Errorf("api key %q not found", args[0])
+				}
+				return fmt.Errorf("failed to revoke api key: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Revoked API key %s\n", args[0])
+			return nil
+		},
+	}
+
+	var rotateGrace time.Duration
+	rotateCmd := &cobra.Command{
+		Use:   "rotate <name|id>",
+		Short: "Issue a new API key and keep the old one valid for a grace period",
+		Long: `Issue a new API key with the scopes, profile, rate limit and owner of an
+existing key. The new key is printed once. The old key stays valid for the
+grace period so clients can switch over; requests made with it meanwhile are
+counted and logged, and it is rejected once the period ends.
+
+The owner's --notify-url webhook is told about the rotation, with a request
+signed with the global_settings.outbound_webhooks of the configuration given
+by --config-path. The new key is not sent to it.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if rotateGrace < 0 {
+				return fmt.Errorf("--grace-period must not be negative")
+			}
+			ctx := context.Background()
+			store, closeDB, err := openStore(dbPath)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = closeDB() }()
+
+			record, err := findActiveAPIKey(ctx, store, args[0])
+			if err != nil {
+				return err
+			}
+			rotation, err := auth.RotateClientAPIKey(ctx, store, record.GetId(), rotateGrace)
+			if err != nil {
+				return fmt.Errorf("failed to rotate api key: %w", err)
+			}
+			if rotation.Previous.GetNotifyUrl() != "" {
+				if err := notifyAPIKeyOwner(ctx, cmd, auth.NewAPIKeyEvent(auth.APIKeyEventRotated, rotation.Previous)); err != nil {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: the owner was not notified: %v\n", err)
+				}
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "ID:  %s\n", rotation.New.GetId())
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Key: %s\n", rotation.Key)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nThe previous key %s stays valid until %s.\n", rotation.Previous.GetId(), rotation.Previous.GetExpiresAt())
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Store this key now; it will not be shown again.")
+			return nil
+		},
+	}
+	rotateCmd.Flags().DurationVar(&rotateGrace, "grace-period", 0, "How long the old key stays valid (default: the key's rotation policy, or 24h)")
+
+	apiKeyCmd.AddCommand(createCmd, listCmd, revokeCmd, rotateCmd)
+	return apiKeyCmd
+}
+
+// notifyAPIKeyOwner sends an event to the webhook of a key, signed with the
+// outbound webhook settings of the configuration given by --config-path.
+func notifyAPIKeyOwner(ctx context.Context, cmd *cobra.Command, event auth.APIKeyEvent) error {
+	osFs := afero.NewOsFs()
+	cfg := config.GlobalSettings()
+	if err := cfg.Load(cmd, osFs); err != nil {
+		return fmt.Errorf("configuration load failed: %w", err)
+	}
+	paths := cfg.ConfigPaths()
+	if len(paths) == 0 {
+		return errors.New("set --config-path to the server configuration to sign the notification")
+	}
+	serverConfig, err := config.NewFileStore(osFs, paths).Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if err := webhooks.ConfigureOutbound(serverConfig.GetGlobalSettings().GetOutboundWebhooks()); err != nil {
+		return err
+	}
+	if !webhooks.OutboundSigned() {
+		return errors.New("the configuration sets no global_settings.outbound_webhooks.webhook_secret to sign the notification")
+	}
+	return auth.WebhookAPIKeyNotifier(webhooks.NewSafeOutboundClient(10*time.Second))(ctx, event)
+}
+
+// findActiveAPIKey looks up a key that can be rotated by ID, or by name
+// when the name is unique among such keys.
+func findActiveAPIKey(ctx context.Context, store storage.Storage, nameOrID string) (*configv1.ClientApiKey, error) {
+	keys, err := store.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	var matches []*configv1.ClientApiKey
+	for _, k := range keys {
+		if k.GetId() == nameOrID {
+			return k, nil
+		}
+		if k.GetName() == nameOrID && k.GetRevokedAt() == "" && k.GetReplacedBy() == "" {
+			matches = append(matches, k)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("api key %q not found", nameOrID)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("%d active api keys are named %q; rotate one by ID", len(matches), nameOrID)
+	}
+}
+
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func dashIfEmpty(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
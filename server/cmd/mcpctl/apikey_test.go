@@ -0,0 +1,110 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyCmd(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "mcpany.db")
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		b := bytes.NewBufferString("")
+		cmd.SetOut(b)
+		cmd.SetErr(b)
+		cmd.SetArgs(append(append([]string{"apikey"}, args...), "--db-path", dbPath))
+		err := cmd.Execute()
+		return b.String(), err
+	}
+
+	out, err := run("create", "ci", "--scope", "viewer", "--profile", "dev", "--rate-limit", "5")
+	require.NoError(t, err)
+	m := regexp.MustCompile(`ID:\s+(\S+)\nKey: (mcpany_\S+)`).FindStringSubmatch(out)
+	require.Len(t, m, 3, out)
+	id := m[1]
+	assert.Contains(t, m[2], id)
+
+	out, err = run("list")
+	require.NoError(t, err)
+	assert.Contains(t, out, id)
+	assert.Contains(t, out, "viewer")
+	assert.Contains(t, out, "dev")
+	assert.Contains(t, out, "5/s")
+	assert.Contains(t, out, "active")
+	assert.NotContains(t, out, m[2])
+
+	out, err = run("revoke", id)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Revoked API key "+id)
+
+	out, err = run("list")
+	require.NoError(t, err)
+	assert.Contains(t, out, "revoked")
+
+	_, err = run("revoke", "missing")
+	assert.ErrorContains(t, err, "not found")
+
+	_, err = run("create", "bad", "--rate-limit", "-1")
+	assert.Error(t, err)
+}
+
+func TestAPIKeyCmd_Rotate(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "mcpany.db")
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		b := bytes.NewBufferString("")
+		cmd.SetOut(b)
+		cmd.SetErr(b)
+		cmd.SetArgs(append(append([]string{"key"}, args...), "--db-path", dbPath))
+		err := cmd.Execute()
+		return b.String(), err
+	}
+	keyPattern := regexp.MustCompile(`ID:\s+(\S+)\nKey: (mcpany_\S+)`)
+
+	_, err := run("create", "ci", "--scope", "viewer", "--rotate-every", "720h")
+	assert.ErrorContains(t, err, "--rotate-every requires --notify-url")
+	_, err = run("create", "ci", "--scope", "viewer", "--notify-url", "http://hooks.example.com/keys", "--rotate-every", "720h")
+	assert.ErrorContains(t, err, "https")
+
+	out, err := run("create", "ci", "--scope", "viewer", "--owner", "platform-team",
+		"--notify-url", "https://hooks.example.com/keys", "--rotate-every", "720h", "--grace-period", "2h")
+	require.NoError(t, err)
+	m := keyPattern.FindStringSubmatch(out)
+	require.Len(t, m, 3, out)
+	oldID := m[1]
+
+	// Without the configuration to sign it, the owner is not notified.
+	out, err = run("rotate", "ci", "--grace-period", "1h")
+	require.NoError(t, err)
+	assert.Contains(t, out, "the owner was not notified")
+	m = keyPattern.FindStringSubmatch(out)
+	require.Len(t, m, 3, out)
+	newID := m[1]
+	assert.NotEqual(t, oldID, newID)
+	assert.Contains(t, out, "previous key "+oldID+" stays valid until")
+
+	out, err = run("list")
+	require.NoError(t, err)
+	assert.Contains(t, out, "deprecated until")
+	assert.Contains(t, out, "replaced by "+newID)
+
+	_, err = run("rotate", oldID)
+	assert.ErrorContains(t, err, "already been rotated")
+	_, err = run("rotate", "missing")
+	assert.ErrorContains(t, err, "not found")
+	_, err = run("rotate", "ci", "--grace-period", "-1h")
+	assert.Error(t, err)
+	// The name now resolves to the new key.
+	_, err = run("rotate", "ci")
+	require.NoError(t, err)
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/mcpany/core/server/pkg/audit"
+	"github.com/spf13/cobra"
+)
+
+// newAuditCmd creates the audit command group.
+//
+// Returns:
+//   - *cobra.Command: The configured audit command.
+func newAuditCmd() *cobra.Command {
+	auditCmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect the audit log",
+	}
+
+	var filePath, sqlitePath, publicKeyPath string
+	verifyCmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify the hash chain and signed checkpoints of an audit log",
+		Long: `Verify the hash chain and signed checkpoints of an audit log.
+
+Every entry of the file and sqlite audit stores carries the hash of the
+previous entry, so modifying, inserting or removing an entry breaks the
+chain. If signing is configured, the hash of every N-th entry is signed;
+with --public-key the signatures are verified too, which also reveals a
+truncated or fully rewritten log. The command exits non-zero at the first
+violation.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if (filePath == "") == (sqlitePath == "") {
+				return errors.New("exactly one of --file or --sqlite is required")
+			}
+			var pub ed25519.PublicKey
+			if publicKeyPath != "" {
+				data, err := os.ReadFile(publicKeyPath) //nolint:gosec // The path is given by the operator.
+				if err != nil {
+					return fmt.Errorf("failed to read public key: %w", err)
+				}
+				if pub, err = audit.ParseVerifyingKey(data); err != nil {
+					return err
+				}
+			}
+
+			var report *audit.VerifyReport
+			var err error
+			if filePath != "" {
+				report, err = audit.VerifyFile(filePath, pub)
+			} else {
+				report, err = audit.VerifySQLite(context.Background(), sqlitePath, pub)
+			}
+			if err != nil {
+				return fmt.Errorf("audit log verification failed: %w", err)
+			}
+			printVerifyReport(cmd, report)
+			return nil
+		},
+	}
+	verifyCmd.Flags().StringVar(&filePath, "file", "", "Path of an audit log written by the file storage type")
+	verifyCmd.Flags().StringVar(&sqlitePath, "sqlite", "", "Path of an audit database written by the sqlite storage type")
+	verifyCmd.Flags().StringVar(&publicKeyPath, "public-key", "", "PEM file with the Ed25519 public key verifying the checkpoint signatures")
+	auditCmd.AddCommand(verifyCmd)
+	auditCmd.AddCommand(newAuditListCmd(false))
+	auditCmd.AddCommand(newAuditListCmd(true))
+	auditCmd.AddCommand(newAuditGetCmd())
+
+	return auditCmd
+}
+
+func printVerifyReport(cmd *cobra.Command, report *audit.VerifyReport) {
+	out := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(out, "Audit log OK: %d chained entries", report.Entries)
+	if report.Unchained > 0 {
+		_, _ = fmt.Fprintf(out, " (%d earlier entries are not chained)", report.Unchained)
+	}
+	if report.Pruned > 0 {
+		_, _ = fmt.Fprintf(out, " (%d earlier entries were pruned by retention)", report.Pruned)
+	}
+	_, _ = fmt.Fprintln(out)
+	switch {
+	case report.Checkpoints == 0:
+		_, _ = fmt.Fprintln(out, "Checkpoints: none")
+	case report.SignaturesVerified:
+		_, _ = fmt.Fprintf(out, "Checkpoints: %d, signatures verified\n", report.Checkpoints)
+	default:
+		_, _ = fmt.Fprintf(out, "Checkpoints: %d, signatures not verified (no --public-key)\n", report.Checkpoints)
+	}
+	if report.LastHash != "" {
+		_, _ = fmt.Fprintf(out, "Last hash: %s\n", report.LastHash)
+	}
+}
@@ -0,0 +1,357 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/audit"
+	"github.com/spf13/cobra"
+)
+
+// auditOutputFormats are the values of the --output flag of the audit list
+// and search commands.
+var auditOutputFormats = []string{"table", "json", "csv"}
+
+// auditQueryFlags are the filters and paging flags shared by the audit list
+// and search commands.
+type auditQueryFlags struct {
+	serverURL string
+	apiKey    string
+	tool      string
+	user      string
+	profile   string
+	since     time.Duration
+	start     string
+	end       string
+	errors    bool
+	limit     int
+	offset    int
+	output    string
+}
+
+// bind registers the flags on cmd.
+func (f *auditQueryFlags) bind(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.serverURL, "server", envOr("MCPANY_SERVER_URL", "http://localhost:50050"), "Base URL of the running server. Env: MCPANY_SERVER_URL")
+	cmd.Flags().StringVar(&f.apiKey, "api-key", envOr("MCPANY_API_KEY", ""), "API key of the server, sent in the X-API-Key header. Env: MCPANY_API_KEY")
+	cmd.Flags().StringVar(&f.tool, "tool", "", "Only show the calls of this tool")
+	cmd.Flags().StringVar(&f.user, "user", "", "Only show the calls of this user")
+	cmd.Flags().StringVar(&f.profile, "profile", "", "Only show the calls made with this profile")
+	cmd.Flags().DurationVar(&f.since, "since", 0, "Only show the calls of this last duration, e.g. 1h")
+	cmd.Flags().StringVar(&f.start, "start", "", "Only show the calls at or after this RFC 3339 time")
+	cmd.Flags().StringVar(&f.end, "end", "", "Only show the calls at or before this RFC 3339 time")
+	cmd.Flags().BoolVar(&f.errors, "errors", false, "Only show the failed calls")
+	cmd.Flags().IntVar(&f.limit, "limit", 50, "Maximum number of entries to show")
+	cmd.Flags().IntVar(&f.offset, "offset", 0, "Number of entries to skip, for the next page")
+	cmd.Flags().StringVarP(&f.output, "output", "o", "table", "Output format: table, json or csv")
+}
+
+// query validates the flags and returns the query parameters of the audit
+// logs endpoint. It asks for one entry more than the limit, to tell whether
+// a next page exists.
+func (f *auditQueryFlags) query(now time.Time) (url.Values, error) {
+	if !slices.Contains(auditOutputFormats, f.output) {
+		return nil, fmt.Errorf("invalid output format %q: must be one of %s", f.output, strings.Join(auditOutputFormats, ", "))
+	}
+	if f.limit <= 0 {
+		return nil, errors.New("--limit must be positive")
+	}
+	if f.offset < 0 {
+		return nil, errors.New("--offset must not be negative")
+	}
+	if f.since < 0 {
+		return nil, errors.New("--since must not be negative")
+	}
+	if f.since > 0 && f.start != "" {
+		return nil, errors.New("--since and --start cannot be combined")
+	}
+
+	q := url.Values{}
+	for name, value := range map[string]string{"start_time": f.start, "end_time": f.end} {
+		if value == "" {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return nil, fmt.Errorf("invalid --%s %q: must be an RFC 3339 time such as 2026-01-02T15:04:05Z", strings.TrimSuffix(name, "_time"), value)
+		}
+		q.Set(name, value)
+	}
+	if f.since > 0 {
+		q.Set("start_time", now.Add(-f.since).UTC().Format(time.RFC3339))
+	}
+	if f.tool != "" {
+		q.Set("tool_name", f.tool)
+	}
+	if f.user != "" {
+		q.Set("user_id", f.user)
+	}
+	if f.profile != "" {
+		q.Set("profile_id", f.profile)
+	}
+	if f.errors {
+		q.Set("errors_only", "true")
+	}
+	q.Set("limit", strconv.Itoa(f.limit+1))
+	if f.offset > 0 {
+		q.Set("offset", strconv.Itoa(f.offset))
+	}
+	return q, nil
+}
+
+// newAuditListCmd creates the audit list command, or the audit search
+// command if search is true.
+//
+// Parameters:
+//   - search: bool. Whether to create the search command, which also takes
+//     the text to look for.
+//
+// Returns:
+//   - *cobra.Command: The configured command.
+func newAuditListCmd(search bool) *cobra.Command {
+	var flags auditQueryFlags
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the audited tool calls of the running server",
+		Long: `List the audited tool calls of the running server, newest first.
+
+--tool, --user and --profile keep the calls of one tool, user or profile,
+--since, --start and --end the calls of a time range, and --errors the failed
+calls. Entries are shown one page of --limit at a time; when more exist, the
+--offset of the next page is printed on stderr. Use "mcpctl audit get" to see
+the full arguments and result of an entry. Requires an admin API key.`,
+		Args: cobra.NoArgs,
+	}
+	if search {
+		cmd.Use = "search <text>"
+		cmd.Short = "Search the audited tool calls of the running server"
+		cmd.Long = `Search the audited tool calls of the running server for the calls whose
+tool name, arguments, result or error contain the text, ignoring case,
+newest first. Takes the same filters and paging flags as "mcpctl audit list".
+Requires an admin API key.`
+		cmd.Args = cobra.ExactArgs(1)
+	}
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		q, err := flags.query(time.Now())
+		if err != nil {
+			return err
+		}
+		if search {
+			if strings.TrimSpace(args[0]) == "" {
+				return errors.New("the search text must not be empty")
+			}
+			q.Set("q", args[0])
+		}
+		ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+		defer cancel()
+		body, err := callAdminAPI(ctx, &http.Client{}, http.MethodGet, flags.serverURL, "/api/v1/audit/logs?"+q.Encode(), flags.apiKey, nil)
+		if err != nil {
+			return err
+		}
+		var resp struct {
+			Entries []audit.Entry `json:"entries"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return fmt.Errorf("failed to decode the audit entries: %w", err)
+		}
+		entries := resp.Entries
+		more := len(entries) > flags.limit
+		if more {
+			entries = entries[:flags.limit]
+		}
+
+		out := cmd.OutOrStdout()
+		switch flags.output {
+		case "json":
+			if entries == nil {
+				entries = []audit.Entry{}
+			}
+			data, err := json.MarshalIndent(entries, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to encode the audit entries: %w", err)
+			}
+			_, _ = fmt.Fprintf(out, "%s\n", data)
+		case "csv":
+			if err := printAuditCSV(out, entries); err != nil {
+				return err
+			}
+		default:
+			if len(entries) == 0 {
+				_, _ = fmt.Fprintln(out, "No audit entries found.")
+			} else {
+				printAuditTable(out, entries)
+			}
+		}
+		if more {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "More entries exist: rerun with --offset %d\n", flags.offset+flags.limit)
+		}
+		return nil
+	}
+	flags.bind(cmd)
+	return cmd
+}
+
+// newAuditGetCmd creates the audit get command, which shows an audit entry
+// with its full arguments and result.
+//
+// Returns:
+//   - *cobra.Command: The configured get command.
+func newAuditGetCmd() *cobra.Command {
+	var (
+		serverURL string
+		apiKey    string
+		output    string
+	)
+	getCmd := &cobra.Command{
+		Use:   "get <id>",
+		Short: "Show an audited tool call with its full arguments and result",
+		Long: `Show an audited tool call of the running server with its full arguments,
+result and error. The ID is the one shown by "mcpctl audit list". Requires
+an admin API key.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != "table" && output != "json" {
+				return fmt.Errorf("invalid output format %q: must be table or json", output)
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+			defer cancel()
+			body, err := callAdminAPI(ctx, &http.Client{}, http.MethodGet, serverURL, "/api/v1/audit/logs/"+url.PathEscape(args[0]), apiKey, nil)
+			if err != nil {
+				return err
+			}
+			var entry audit.Entry
+			if err := json.Unmarshal(body, &entry); err != nil {
+				return fmt.Errorf("failed to decode the audit entry: %w", err)
+			}
+			if output == "json" {
+				data, err := json.MarshalIndent(entry, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to encode the audit entry: %w", err)
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+				return nil
+			}
+			printAuditEntry(cmd.OutOrStdout(), entry)
+			return nil
+		},
+	}
+	getCmd.Flags().StringVar(&serverURL, "server", envOr("MCPANY_SERVER_URL", "http://localhost:50050"), "Base URL of the running server. Env: MCPANY_SERVER_URL")
+	getCmd.Flags().StringVar(&apiKey, "api-key", envOr("MCPANY_API_KEY", ""), "API key of the server, sent in the X-API-Key header. Env: MCPANY_API_KEY")
+	getCmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table or json")
+	return getCmd
+}
+
+// auditStatus is "error" for a failed call and "ok" otherwise.
+func auditStatus(entry audit.Entry) string {
+	if entry.Error != "" {
+		return "error"
+	}
+	return "ok"
+}
+
+func printAuditTable(out io.Writer, entries []audit.Entry) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tTIME\tTOOL\tUSER\tPROFILE\tDURATION\tSTATUS")
+	for _, e := range entries {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			dashIfEmpty(e.ID), e.Timestamp.UTC().Format(time.RFC3339), e.ToolName, dashIfEmpty(e.UserID),
+			dashIfEmpty(e.ProfileID), time.Duration(e.DurationMs)*time.Millisecond, auditStatus(e))
+	}
+	_ = w.Flush()
+}
+
+// printAuditCSV prints the entries with the columns of the audit export of
+// the server, preceded by the entry ID.
+func printAuditCSV(out io.Writer, entries []audit.Entry) error {
+	w := csv.NewWriter(out)
+	_ = w.Write([]string{"ID", "Timestamp", "ToolName", "UserID", "ProfileID", "APIKeyID", "Arguments", "Result", "Error", "DurationMs"})
+	for _, e := range entries {
+		_ = w.Write([]string{
+			e.ID,
+			e.Timestamp.Format(time.RFC3339Nano),
+			e.ToolName,
+			e.UserID,
+			e.ProfileID,
+			e.APIKeyID,
+			string(e.Arguments),
+			auditResultText(e.Result),
+			e.Error,
+			strconv.FormatInt(e.DurationMs, 10),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write the CSV: %w", err)
+	}
+	return nil
+}
+
+// auditResultText returns a result as text: strings as they are and other
+// values as JSON.
+func auditResultText(result any) string {
+	if result == nil {
+		return ""
+	}
+	if s, ok := result.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Sprint(result)
+	}
+	return string(data)
+}
+
+func printAuditEntry(out io.Writer, e audit.Entry) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "ID:\t%s\n", dashIfEmpty(e.ID))
+	_, _ = fmt.Fprintf(w, "Time:\t%s\n", e.Timestamp.UTC().Format(time.RFC3339Nano))
+	_, _ = fmt.Fprintf(w, "Tool:\t%s\n", e.ToolName)
+	_, _ = fmt.Fprintf(w, "User:\t%s\n", dashIfEmpty(e.UserID))
+	_, _ = fmt.Fprintf(w, "Profile:\t%s\n", dashIfEmpty(e.ProfileID))
+	_, _ = fmt.Fprintf(w, "API key:\t%s\n", dashIfEmpty(e.APIKeyID))
+	if e.TraceID != "" {
+		_, _ = fmt.Fprintf(w, "Trace:\t%s\n", e.TraceID)
+	}
+	_, _ = fmt.Fprintf(w, "Duration:\t%s\n", time.Duration(e.DurationMs)*time.Millisecond)
+	_, _ = fmt.Fprintf(w, "Status:\t%s\n", auditStatus(e))
+	_ = w.Flush()
+
+	if e.Error != "" {
+		_, _ = fmt.Fprintf(out, "\nError:\n  %s\n", e.Error)
+	}
+	_, _ = fmt.Fprintf(out, "\nArguments:\n%s\n", indentJSON(e.Arguments))
+	_, _ = fmt.Fprintf(out, "\nResult:\n")
+	if s, ok := e.Result.(string); ok {
+		_, _ = fmt.Fprintf(out, "  %s\n", s)
+		return
+	}
+	data, _ := json.Marshal(e.Result)
+	_, _ = fmt.Fprintf(out, "%s\n", indentJSON(data))
+}
+
+// indentJSON indents a JSON document by two spaces, or returns "  -" if it
+// is empty or null.
+func indentJSON(data []byte) string {
+	if len(bytes.TrimSpace(data)) == 0 || string(bytes.TrimSpace(data)) == "null" {
+		return "  -"
+	}
+	var b bytes.Buffer
+	if err := json.Indent(&b, data, "  ", "  "); err != nil {
+		return "  " + string(data)
+	}
+	return "  " + b.String()
+}
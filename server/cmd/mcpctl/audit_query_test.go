@@ -0,0 +1,147 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const auditEntriesJSON = `{"entries":[
+{"id":"e2","timestamp":"2026-10-16T12:01:00Z","tool_name":"weather.get_forecast","user_id":"alice","arguments":{"city":"Paris"},"error":"upstream timed out","duration":"","duration_ms":1500},
+{"id":"e1","timestamp":"2026-10-16T12:00:00Z","tool_name":"weather.get_forecast","user_id":"alice","profile_id":"dev","arguments":{"city":"Oslo"},"result":{"temp":3},"duration":"","duration_ms":12}
+]}`
+
+func newAuditTestServer(t *testing.T, check func(r *http.Request)) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "admin-key" {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/audit/logs":
+			if check != nil {
+				check(r)
+			}
+			_, _ = w.Write([]byte(auditEntriesJSON))
+		case "/api/v1/audit/logs/e1":
+			_, _ = w.Write([]byte(`{"id":"e1","timestamp":"2026-10-16T12:00:00Z","tool_name":"weather.get_forecast","user_id":"alice","profile_id":"dev","trace_id":"4bf9","arguments":{"city":"Oslo"},"result":{"temp":3},"duration":"","duration_ms":12}`))
+		default:
+			http.Error(w, "audit entry not found", http.StatusNotFound)
+		}
+	}))
+}
+
+func runAuditCmd(args ...string) (string, string, error) {
+	cmd := newRootCmd()
+	out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+	cmd.SetOut(out)
+	cmd.SetErr(errOut)
+	cmd.SetArgs(append([]string{"audit"}, args...))
+	err := cmd.Execute()
+	return out.String(), errOut.String(), err
+}
+
+func TestAuditListCmd(t *testing.T) {
+	server := newAuditTestServer(t, func(r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "weather.get_forecast", q.Get("tool_name"))
+		assert.Equal(t, "alice", q.Get("user_id"))
+		assert.Equal(t, "true", q.Get("errors_only"))
+		assert.Equal(t, "2", q.Get("limit"), "one more than --limit")
+		assert.Equal(t, "3", q.Get("offset"))
+		start, err := time.Parse(time.RFC3339, q.Get("start_time"))
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(-time.Hour), start, time.Minute)
+	})
+	defer server.Close()
+	flags := []string{"list", "--server", server.URL, "--api-key", "admin-key",
+		"--tool", "weather.get_forecast", "--user", "alice", "--errors", "--since", "1h", "--limit", "1", "--offset", "3"}
+
+	out, errOut, err := runAuditCmd(flags...)
+	require.NoError(t, err)
+	assert.Contains(t, out, "ID  TIME                  TOOL                  USER   PROFILE  DURATION  STATUS")
+	assert.Contains(t, out, "e2  2026-10-16T12:01:00Z  weather.get_forecast  alice  -        1.5s      error")
+	assert.NotContains(t, out, "e1")
+	assert.Equal(t, "More entries exist: rerun with --offset 4\n", errOut)
+
+	out, _, err = runAuditCmd(append(flags, "-o", "json")...)
+	require.NoError(t, err)
+	var entries []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &entries))
+	require.Len(t, entries, 1)
+	assert.Equal(t, "e2", entries[0]["id"])
+
+	_, _, err = runAuditCmd("list", "--server", server.URL, "--api-key", "wrong")
+	assert.ErrorContains(t, err, "pass an admin --api-key")
+
+	for want, args := range map[string][]string{
+		`invalid output format "yaml"`:  {"-o", "yaml"},
+		"--limit must be positive":      {"--limit", "0"},
+		"invalid --start":               {"--start", "yesterday"},
+		"cannot be combined":            {"--since", "1h", "--start", "2026-10-16T00:00:00Z"},
+		"--offset must not be negative": {"--offset", "-1"},
+	} {
+		_, _, err := runAuditCmd(append([]string{"list", "--server", server.URL}, args...)...)
+		assert.ErrorContains(t, err, want)
+	}
+}
+
+func TestAuditListCmd_CSV(t *testing.T) {
+	server := newAuditTestServer(t, nil)
+	defer server.Close()
+
+	out, errOut, err := runAuditCmd("list", "--server", server.URL, "--api-key", "admin-key", "-o", "csv")
+	require.NoError(t, err)
+	assert.Empty(t, errOut)
+	assert.Equal(t, `ID,Timestamp,ToolName,UserID,ProfileID,APIKeyID,Arguments,Result,Error,DurationMs
+e2,2026-10-16T12:01:00Z,weather.get_forecast,alice,,,"{""city"":""Paris""}",,upstream timed out,1500
+e1,2026-10-16T12:00:00Z,weather.get_forecast,alice,dev,,"{""city"":""Oslo""}","{""temp"":3}",,12
+`, out)
+}
+
+func TestAuditSearchCmd(t *testing.T) {
+	server := newAuditTestServer(t, func(r *http.Request) {
+		assert.Equal(t, "timed out", r.URL.Query().Get("q"))
+		assert.Equal(t, "51", r.URL.Query().Get("limit"))
+	})
+	defer server.Close()
+
+	out, _, err := runAuditCmd("search", "timed out", "--server", server.URL, "--api-key", "admin-key")
+	require.NoError(t, err)
+	assert.Contains(t, out, "e2")
+
+	_, _, err = runAuditCmd("search", "--server", server.URL)
+	assert.ErrorContains(t, err, "accepts 1 arg")
+}
+
+func TestAuditGetCmd(t *testing.T) {
+	server := newAuditTestServer(t, nil)
+	defer server.Close()
+
+	out, _, err := runAuditCmd("get", "e1", "--server", server.URL, "--api-key", "admin-key")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Tool:      weather.get_forecast\n")
+	assert.Contains(t, out, "Trace:     4bf9\n")
+	assert.Contains(t, out, "Status:    ok\n")
+	assert.Contains(t, out, "\nArguments:\n  {\n    \"city\": \"Oslo\"\n  }\n")
+	assert.Contains(t, out, "\nResult:\n  {\n    \"temp\": 3\n  }\n")
+
+	out, _, err = runAuditCmd("get", "e1", "--server", server.URL, "--api-key", "admin-key", "-o", "json")
+	require.NoError(t, err)
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &entry))
+	assert.Equal(t, "dev", entry["profile_id"])
+
+	_, _, err = runAuditCmd("get", "missing", "--server", server.URL, "--api-key", "admin-key")
+	assert.ErrorContains(t, err, "404 Not Found")
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/audit"
+	"github.com/mcpany/core/server/pkg/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditVerifyCmd(t *testing.T) {
+	dir := t.TempDir()
+	validation.SetAllowedPaths([]string{dir})
+	defer validation.SetAllowedPaths(nil)
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	signer, err := audit.NewCheckpointSigner(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 2)
+	require.NoError(t, err)
+	der, err = x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	pubPath := filepath.Join(dir, "audit.pub")
+	require.NoError(t, os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	logPath := filepath.Join(dir, "audit.log")
+	store, err := audit.NewFileAuditStore(logPath)
+	require.NoError(t, err)
+	require.NoError(t, store.SetCheckpointSigner(signer))
+	for i := 0; i < 3; i++ {
+		require.NoError(t, store.Write(context.Background(), audit.Entry{Timestamp: time.Now(), ToolName: "weather.get"}))
+	}
+	require.NoError(t, store.Close())
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		b := bytes.NewBufferString("")
+		cmd.SetOut(b)
+		cmd.SetErr(b)
+		cmd.SetArgs(append([]string{"audit", "verify"}, args...))
+		err := cmd.Execute()
+		return b.String(), err
+	}
+
+	out, err := run("--file", logPath, "--public-key", pubPath)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Audit log OK: 3 chained entries\n")
+	assert.Contains(t, out, "Checkpoints: 1, signatures verified\n")
+
+	out, err = run("--file", logPath)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Checkpoints: 1, signatures not verified (no --public-key)\n")
+
+	content, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(logPath, bytes.Replace(content, []byte("weather.get"), []byte("weather.set"), 1), 0o600))
+	_, err = run("--file", logPath, "--public-key", pubPath)
+	assert.ErrorContains(t, err, "audit log verification failed: integrity violation at line 1: hash mismatch at seq 1")
+
+	_, err = run()
+	assert.ErrorContains(t, err, "exactly one of --file or --sqlite is required")
+}
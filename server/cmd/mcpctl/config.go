@@ -0,0 +1,148 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/audit"
+	"github.com/mcpany/core/server/pkg/config"
+	"github.com/mcpany/core/server/pkg/configpreview"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// newConfigCmd creates the config command group.
+//
+// The preview command replays recently audited calls against a proposed
+// configuration before it is rolled out; the apply command checks a candidate
+// configuration against the running server; the history and rollback
+// commands list and restore the configuration versions of the running server.
+//
+// Returns:
+//   - *cobra.Command: The configured config command.
+func newConfigCmd() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Work with configuration changes",
+	}
+	configCmd.AddCommand(newConfigPreviewCmd())
+	configCmd.AddCommand(newConfigApplyCmd())
+	configCmd.AddCommand(newConfigHistoryCmd())
+	configCmd.AddCommand(newConfigRollbackCmd())
+	return configCmd
+}
+
+func newConfigPreviewCmd() *cobra.Command {
+	var (
+		files     []string
+		replay    time.Duration
+		auditPath string
+		all       bool
+	)
+	previewCmd := &cobra.Command{
+		Use:   "preview -f <file>",
+		Short: "Show how a proposed configuration would have handled recent calls",
+		Long: `Show how a proposed configuration would have handled recent calls.
+
+The calls recorded in the SQLite audit log during the --replay window are
+evaluated against the proposed configuration, without calling any upstream.
+Each call is checked against the service and tool disable flags, the tool
+export policy, the call policies and the caller's profile, and reported as
+blocked, renamed, rerouted or unchanged.
+
+Renames and routing changes are found by comparing with the current
+configuration, loaded from --config-path. Argument rules only see arguments
+the audit log recorded, see audit.log_arguments.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := context.Background()
+			osFs := afero.NewOsFs()
+			cfg := config.GlobalSettings()
+			if err := cfg.Load(cmd, osFs); err != nil {
+				return fmt.Errorf("configuration load failed: %w", err)
+			}
+
+			proposed, err := config.NewFileStore(osFs, files).Load(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to load proposed configuration: %w", err)
+			}
+			var current *configv1.McpAnyServerConfig
+			if paths := cfg.ConfigPaths(); len(paths) > 0 {
+				if current, err = config.NewFileStore(osFs, paths).Load(ctx); err != nil {
+					return fmt.Errorf("failed to load current configuration: %w", err)
+				}
+			}
+
+			if auditPath == "" {
+				if a := current.GetGlobalSettings().GetAudit(); a.GetStorageType() == configv1.AuditConfig_STORAGE_TYPE_SQLITE {
+					auditPath = a.GetOutputPath()
+				}
+			}
+			if auditPath == "" {
+				return errors.New("no audit log to replay: set --audit-db, or --config-path to a configuration with a SQLite audit log")
+			}
+			auditStore, err := audit.NewSQLiteAuditStore(auditPath)
+			if err != nil {
+				return fmt.Errorf("failed to open audit database: %w", err)
+			}
+			defer func() { _ = auditStore.Close() }()
+
+			since := time.Now().Add(-replay)
+			entries, err := auditStore.Read(ctx, audit.Filter{StartTime: &since})
+			if err != nil {
+				return fmt.Errorf("failed to read audit log: %w", err)
+			}
+			// The audit log lists the newest calls first.
+			slices.Reverse(entries)
+
+			report := configpreview.Replay(current, proposed, entries)
+			printPreviewReport(cmd, report, replay, all)
+			return nil
+		},
+	}
+	previewCmd.Flags().StringSliceVarP(&files, "file", "f", nil, "Proposed configuration file or directory. Can be specified multiple times")
+	previewCmd.Flags().DurationVar(&replay, "replay", time.Hour, "How far back to replay audited calls")
+	previewCmd.Flags().StringVar(&auditPath, "audit-db", envOr("MCPANY_AUDIT_DB", ""), "Path to the SQLite audit log (audit.output_path). Defaults to the one of the current configuration. Env: MCPANY_AUDIT_DB")
+	previewCmd.Flags().BoolVar(&all, "all", false, "List unchanged calls too")
+	_ = previewCmd.MarkFlagRequired("file")
+	return previewCmd
+}
+
+func printPreviewReport(cmd *cobra.Command, report *configpreview.Report, replay time.Duration, all bool) {
+	out := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(out, "Replayed %d calls from the last %s: %d unchanged, %d blocked, %d renamed, %d rerouted\n",
+		len(report.Results), replay,
+		report.Counts[configpreview.OutcomeUnchanged],
+		report.Counts[configpreview.OutcomeBlocked],
+		report.Counts[configpreview.OutcomeRenamed],
+		report.Counts[configpreview.OutcomeRerouted])
+
+	results := report.Results
+	if !all {
+		results = report.Changed()
+	}
+	if len(results) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TIME\tTOOL\tPROFILE\tOUTCOME\tDETAIL")
+	for _, res := range results {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			res.Entry.Timestamp.Format(time.RFC3339),
+			res.Entry.ToolName,
+			dashIfEmpty(res.Entry.ProfileID),
+			strings.ToUpper(string(res.Outcome)),
+			dashIfEmpty(res.Reason))
+	}
+	_ = w.Flush()
+}
@@ -0,0 +1,171 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// dryRunVerdict is the verdict of the dry run endpoint of the server.
+type dryRunVerdict struct {
+	Verdict string `json:"verdict"`
+	Stages  []struct {
+		Name     string `json:"name"`
+		Status   string `json:"status"`
+		Findings []struct {
+			Severity string `json:"severity"`
+			Service  string `json:"service"`
+			Path     string `json:"path"`
+			Message  string `json:"message"`
+		} `json:"findings"`
+	} `json:"stages"`
+	Changes []struct {
+		Path string `json:"path"`
+		Kind string `json:"kind"`
+		Risk string `json:"risk"`
+	} `json:"changes"`
+}
+
+// newConfigApplyCmd creates the config apply command, which checks a
+// candidate configuration against the running server.
+//
+// Returns:
+//   - *cobra.Command: The configured apply command.
+func newConfigApplyCmd() *cobra.Command {
+	var (
+		file             string
+		serverURL        string
+		apiKey           string
+		dryRun           bool
+		skipConnectivity bool
+		asJSON           bool
+	)
+	applyCmd := &cobra.Command{
+		Use:   "apply -f <file> --dry-run",
+		Short: "Check a candidate configuration against the running server",
+		Long: `Check a candidate configuration against the running server, without
+applying it.
+
+The server runs the checks of a reload and more: it parses the candidate,
+validates it against the schema, loads and validates it merged with the
+configuration in its storage, checks that its upstreams are reachable and
+lints it. It reports the outcome of each check and the changes from the
+running configuration, with a verdict: pass, warn (unreachable upstreams or
+lint findings, which do not stop a reload) or fail (a reload would reject the
+candidate). The running server is not changed. The includes of the candidate
+are not resolved.
+
+Only --dry-run is supported: the server applies the configuration of its
+config sources, so update them to apply the candidate. Requires an admin API
+key.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if !dryRun {
+				return errors.New("only --dry-run is supported: the server applies the configuration of its config sources, so update them to apply the candidate")
+			}
+			content, err := os.ReadFile(file) //nolint:gosec // The file is chosen by the user.
+			if err != nil {
+				return fmt.Errorf("failed to read the candidate configuration: %w", err)
+			}
+			reqBody, err := json.Marshal(map[string]any{
+				"content":           string(content),
+				"format":            configFormat(file),
+				"skip_connectivity": skipConnectivity,
+			})
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), 2*time.Minute)
+			defer cancel()
+			body, err := callAdminAPI(ctx, &http.Client{}, http.MethodPost, serverURL, "/api/v1/config/dry-run", apiKey, reqBody)
+			if err != nil {
+				return err
+			}
+			var verdict dryRunVerdict
+			if err := json.Unmarshal(body, &verdict); err != nil {
+				return fmt.Errorf("failed to decode the verdict: %w", err)
+			}
+			if asJSON {
+				if _, err := cmd.OutOrStdout().Write(body); err != nil {
+					return err
+				}
+			} else {
+				printDryRunVerdict(cmd.OutOrStdout(), &verdict)
+			}
+			if verdict.Verdict == "fail" {
+				return errors.New("a reload would reject the configuration")
+			}
+			return nil
+		},
+	}
+	applyCmd.Flags().StringVarP(&file, "file", "f", "", "The candidate configuration file (YAML, JSON, TOML or textproto)")
+	applyCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Check the candidate without applying it")
+	applyCmd.Flags().BoolVar(&skipConnectivity, "skip-connectivity", false, "Do not check that the upstreams of the candidate are reachable")
+	applyCmd.Flags().BoolVar(&asJSON, "json", false, "Print the verdict as JSON")
+	applyCmd.Flags().StringVar(&serverURL, "server", envOr("MCPANY_SERVER_URL", "http://localhost:50050"), "Base URL of the running server. Env: MCPANY_SERVER_URL")
+	applyCmd.Flags().StringVar(&apiKey, "api-key", envOr("MCPANY_API_KEY", ""), "API key of the server, sent in the X-API-Key header. Env: MCPANY_API_KEY")
+	_ = applyCmd.MarkFlagRequired("file")
+	return applyCmd
+}
+
+// configFormat returns the format of a configuration file from its
+// extension, or empty to let the server detect it.
+func configFormat(path string) string {
+	if strings.HasSuffix(strings.ToLower(path), ".pb.txt") {
+		return "textproto"
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".json":
+		return "json"
+	case ".toml":
+		return "toml"
+	case ".textproto", ".prototxt", ".pb":
+		return "textproto"
+	default:
+		return ""
+	}
+}
+
+func printDryRunVerdict(out io.Writer, v *dryRunVerdict) {
+	_, _ = fmt.Fprintf(out, "Verdict: %s\n\n", strings.ToUpper(v.Verdict))
+	for _, s := range v.Stages {
+		_, _ = fmt.Fprintf(out, "  %-14s%s\n", s.Name, s.Status)
+		for _, f := range s.Findings {
+			where := strings.TrimSpace(f.Service + " " + f.Path)
+			if where != "" {
+				where = " (" + where + ")"
+			}
+			_, _ = fmt.Fprintf(out, "    %s%s: %s\n", f.Severity, where, f.Message)
+		}
+	}
+
+	if len(v.Changes) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(out, "\nChanges from the running configuration:")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, c := range v.Changes {
+		line := fmt.Sprintf("  %s\t%s", c.Kind, c.Path)
+		if c.Risk != "" {
+			line += "\t(risk: " + c.Risk + ")"
+		}
+		_, _ = fmt.Fprintln(w, line)
+	}
+	_ = w.Flush()
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigApplyCmd(t *testing.T) {
+	verdict := `{"verdict": "warn", "stages": [
+		{"name": "parse", "status": "passed"},
+		{"name": "connectivity", "status": "warning", "findings": [
+			{"severity": "error", "service": "weather", "message": "connection refused"}]}
+	], "changes": [
+		{"path": "tools[weather.get_alerts]", "kind": "added"},
+		{"path": "global_settings.api_key", "kind": "removed", "risk": "removes the API key of the server"}
+	]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "admin-key" {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/config/dry-run" {
+			http.NotFound(w, r)
+			return
+		}
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "yaml", req["format"])
+		assert.Equal(t, true, req["skip_connectivity"])
+		if req["content"] == "bad" {
+			_, _ = w.Write([]byte(`{"verdict": "fail", "stages": [{"name": "parse", "status": "failed",
+				"findings": [{"severity": "error", "message": "did not find expected node content"}]}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(verdict))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.yaml")
+	require.NoError(t, os.WriteFile(good, []byte("upstream_services: []"), 0o600))
+	bad := filepath.Join(dir, "bad.yml")
+	require.NoError(t, os.WriteFile(bad, []byte("bad"), 0o600))
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		b := bytes.NewBufferString("")
+		cmd.SetOut(b)
+		cmd.SetErr(b)
+		cmd.SetArgs(append(append([]string{"config", "apply"}, args...), "--server", server.URL, "--api-key", "admin-key"))
+		err := cmd.Execute()
+		return b.String(), err
+	}
+
+	out, err := run("-f", good, "--dry-run", "--skip-connectivity")
+	require.NoError(t, err, "a warning does not fail the command")
+	assert.Contains(t, out, "Verdict: WARN")
+	assert.Regexp(t, `connectivity\s+warning`, out)
+	assert.Contains(t, out, "error (weather): connection refused")
+	assert.Regexp(t, `added\s+tools\[weather.get_alerts\]`, out)
+	assert.Contains(t, out, "(risk: removes the API key of the server)")
+
+	out, err = run("-f", good, "--dry-run", "--skip-connectivity", "--json")
+	require.NoError(t, err)
+	assert.JSONEq(t, verdict, out)
+
+	out, err = run("-f", bad, "--dry-run", "--skip-connectivity")
+	assert.EqualError(t, err, "a reload would reject the configuration")
+	assert.Contains(t, out, "did not find expected node content")
+
+	_, err = run("-f", good)
+	assert.ErrorContains(t, err, "only --dry-run is supported")
+}
+
+func TestConfigFormat(t *testing.T) {
+	assert.Equal(t, "yaml", configFormat("a/config.YML"))
+	assert.Equal(t, "json", configFormat("config.json"))
+	assert.Equal(t, "toml", configFormat("config.toml"))
+	assert.Equal(t, "textproto", configFormat("config.pb.txt"))
+	assert.Equal(t, "", configFormat("config.txt"))
+}
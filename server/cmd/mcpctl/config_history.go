@@ -0,0 +1,222 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// newConfigHistoryCmd creates the config history command, which lists the
+// recorded reloads of the running server.
+//
+// Returns:
+//   - *cobra.Command: The configured history command.
+func newConfigHistoryCmd() *cobra.Command {
+	var (
+		serverURL string
+		apiKey    string
+		limit     int
+		asJSON    bool
+	)
+	historyCmd := &cobra.Command{
+		Use:   "history",
+		Short: "List the configuration versions of the running server",
+		Long: `List the configuration versions of the running server, newest first.
+
+The server records every reload of its configuration: at startup, when a
+config file changes, after a change through the admin API and on rollback.
+Each version shows who triggered the reload and what triggered it, and
+whether the configuration was applied or failed to load, with its error. The
+newest applied version is the one the server is running. Requires an admin
+API key.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+			defer cancel()
+			path := "/api/v1/config/versions"
+			if limit > 0 {
+				path += "?" + url.Values{"limit": {strconv.Itoa(limit)}}.Encode()
+			}
+			body, err := callAdminAPI(ctx, &http.Client{}, http.MethodGet, serverURL, path, apiKey, nil)
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				_, err := cmd.OutOrStdout().Write(body)
+				return err
+			}
+			versions, err := decodeConfigVersions(body)
+			if err != nil {
+				return err
+			}
+			printConfigHistory(cmd.OutOrStdout(), versions)
+			return nil
+		},
+	}
+	historyCmd.Flags().StringVar(&serverURL, "server", envOr("MCPANY_SERVER_URL", "http://localhost:50050"), "Base URL of the running server. Env: MCPANY_SERVER_URL")
+	historyCmd.Flags().StringVar(&apiKey, "api-key", envOr("MCPANY_API_KEY", ""), "API key of the server, sent in the X-API-Key header. Env: MCPANY_API_KEY")
+	historyCmd.Flags().IntVar(&limit, "limit", 20, "Number of versions to show, or 0 for all")
+	historyCmd.Flags().BoolVar(&asJSON, "json", false, "Print the versions as JSON")
+	return historyCmd
+}
+
+// newConfigRollbackCmd creates the config rollback command, which applies
+// the configuration of an earlier version again.
+//
+// Returns:
+//   - *cobra.Command: The configured rollback command.
+func newConfigRollbackCmd() *cobra.Command {
+	var (
+		serverURL string
+		apiKey    string
+	)
+	rollbackCmd := &cobra.Command{
+		Use:   "rollback <version>",
+		Short: "Restore an earlier configuration version on the running server",
+		Long: `Restore an earlier configuration version on the running server.
+
+The server applies the configuration recorded with the version again, and
+records the rollback as a new version. Only applied versions can be
+restored; see mcpctl config history. The restored configuration stays in
+effect until the next reload, e.g. when a config file changes, so fix or
+revert the configuration sources too. Requires an admin API key.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			version, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil || version <= 0 {
+				return fmt.Errorf("invalid version %q", args[0])
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 60*time.Second)
+			defer cancel()
+			path := fmt.Sprintf("/api/v1/config/versions/%d/rollback", version)
+			body, err := callAdminAPI(ctx, &http.Client{}, http.MethodPost, serverURL, path, apiKey, nil)
+			if err != nil {
+				return err
+			}
+			var recorded configv1.ConfigVersion
+			if err := protojson.Unmarshal(body, &recorded); err != nil {
+				return fmt.Errorf("failed to decode the response: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Rolled back to version %d, recorded as version %d.\n", version, recorded.GetVersion())
+			return nil
+		},
+	}
+	rollbackCmd.Flags().StringVar(&serverURL, "server", envOr("MCPANY_SERVER_URL", "http://localhost:50050"), "Base URL of the running server. Env: MCPANY_SERVER_URL")
+	rollbackCmd.Flags().StringVar(&apiKey, "api-key", envOr("MCPANY_API_KEY", ""), "API key of the server, sent in the X-API-Key header. Env: MCPANY_API_KEY")
+	return rollbackCmd
+}
+
+// callAdminAPI sends a request to the admin API of the server.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the request.
+//   - client: *http.Client. The HTTP client.
+//   - method: string. The HTTP method.
+//   - serverURL: string. The base URL of the server.
+//   - path: string. The path and query of the endpoint.
+//   - apiKey: string. The API key of the server, or empty.
+//   - body: []byte. The JSON body of the request, or nil.
+//
+// Returns:
+//   - []byte: The body of the response.
+//   - error: An error if the request fails or the server does not return a
+//     2xx status.
+func callAdminAPI(ctx context.Context, client *http.Client, method, serverURL, path, apiKey string, body []byte) ([]byte, error) {
+	return callAdminAPIWithContentType(ctx, client, method, serverURL, path, apiKey, "application/json", body)
+}
+
+// callAdminAPIWithContentType is callAdminAPI for a body of another content
+// type than JSON, such as a skill package.
+func callAdminAPIWithContentType(ctx context.Context, client *http.Client, method, serverURL, path, apiKey, contentType string, body []byte) ([]byte, error) {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(serverURL, "/")+path, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach the server: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("the server rejected the request (%s); pass an admin --api-key", resp.Status)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return nil, fmt.Errorf("the server returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return respBody, nil
+}
+
+func decodeConfigVersions(body []byte) ([]*configv1.ConfigVersion, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode the versions: %w", err)
+	}
+	versions := make([]*configv1.ConfigVersion, 0, len(raw))
+	for _, r := range raw {
+		var v configv1.ConfigVersion
+		if err := protojson.Unmarshal(r, &v); err != nil {
+			return nil, fmt.Errorf("failed to decode the versions: %w", err)
+		}
+		versions = append(versions, &v)
+	}
+	return versions, nil
+}
+
+func printConfigHistory(out io.Writer, versions []*configv1.ConfigVersion) {
+	if len(versions) == 0 {
+		_, _ = fmt.Fprintln(out, "No configuration versions recorded.")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "VERSION\tTIME\tACTOR\tTRIGGER\tSTATUS\tDETAIL")
+	running := false
+	for _, v := range versions {
+		status, detail := "applied", v.GetConfigHash()
+		if len(detail) > 12 {
+			detail = "sha256:" + detail[:12]
+		}
+		switch {
+		case v.GetStatus() == configv1.ConfigVersion_STATUS_FAILED:
+			status, detail = "failed", v.GetError()
+		case !running:
+			// The newest applied version is the one the server runs.
+			status, running = "applied (running)", true
+		}
+		if v.GetRollbackOf() != 0 {
+			detail = fmt.Sprintf("rollback of %d", v.GetRollbackOf())
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", v.GetVersion(), v.GetCreatedAt(),
+			dashIfEmpty(v.GetActor()), dashIfEmpty(v.GetTrigger()), status, dashIfEmpty(detail))
+	}
+	_ = w.Flush()
+}
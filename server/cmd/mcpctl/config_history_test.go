@@ -0,0 +1,74 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigHistoryAndRollbackCmds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "admin-key" {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/config/versions":
+			assert.Equal(t, "3", r.URL.Query().Get("limit"))
+			_, _ = w.Write([]byte(`[
+				{"version": "3", "created_at": "2026-01-02T03:10:00Z", "actor": "system", "trigger": "config change",
+					"status": "STATUS_FAILED", "error": "invalid service weather"},
+				{"version": "2", "created_at": "2026-01-02T03:05:00Z", "actor": "alice", "trigger": "admin api: POST /services",
+					"status": "STATUS_APPLIED", "config_hash": "0123456789abcdef0123"},
+				{"version": "1", "created_at": "2026-01-02T03:00:00Z", "actor": "system", "trigger": "startup",
+					"status": "STATUS_APPLIED", "config_hash": "fedcba9876543210fedc"}
+			]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/config/versions/1/rollback":
+			_, _ = w.Write([]byte(`{"version": "4", "actor": "admin", "trigger": "rollback", "status": "STATUS_APPLIED", "rollback_of": "1"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/config/versions/3/rollback":
+			http.Error(w, "config version 3 was not applied and cannot be restored", http.StatusConflict)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		b := bytes.NewBufferString("")
+		cmd.SetOut(b)
+		cmd.SetErr(b)
+		cmd.SetArgs(append(append([]string{"config"}, args...), "--server", server.URL, "--api-key", "admin-key"))
+		err := cmd.Execute()
+		return b.String(), err
+	}
+
+	out, err := run("history", "--limit", "3")
+	require.NoError(t, err)
+	assert.Regexp(t, `3\s+2026-01-02T03:10:00Z\s+system\s+config change\s+failed\s+invalid service weather`, out)
+	assert.Regexp(t, `2\s+2026-01-02T03:05:00Z\s+alice\s+admin api: POST /services\s+applied \(running\)\s+sha256:0123456789ab`, out)
+	assert.Regexp(t, `1\s+2026-01-02T03:00:00Z\s+system\s+startup\s+applied\s+sha256:fedcba987654`, out)
+
+	out, err = run("rollback", "1")
+	require.NoError(t, err)
+	assert.Equal(t, "Rolled back to version 1, recorded as version 4.\n", out)
+
+	_, err = run("rollback", "3")
+	assert.ErrorContains(t, err, "was not applied")
+
+	_, err = run("rollback", "latest")
+	assert.ErrorContains(t, err, `invalid version "latest"`)
+
+	cmd := newRootCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"config", "history", "--server", server.URL, "--api-key", "wrong"})
+	assert.ErrorContains(t, cmd.Execute(), "pass an admin --api-key")
+}
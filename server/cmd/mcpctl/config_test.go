@@ -0,0 +1,113 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/audit"
+	"github.com/mcpany/core/server/pkg/validation"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigPreviewCmd(t *testing.T) {
+	dir := t.TempDir()
+	validation.SetAllowedPaths([]string{dir})
+	defer validation.SetAllowedPaths(nil)
+	auditPath := filepath.Join(dir, "audit.db")
+	currentPath := filepath.Join(dir, "current.yaml")
+	proposedPath := filepath.Join(dir, "proposed.yaml")
+
+	require.NoError(t, os.WriteFile(currentPath, []byte(`
+global_settings:
+  audit:
+    enabled: true
+    storage_type: STORAGE_TYPE_SQLITE
+    output_path: "`+auditPath+`"
+upstream_services:
+  - name: "weather"
+    http_service:
+      address: "https://api.weather.example"
+      tools:
+        - name: "get_forecast"
+          call_id: "forecast"
+        - name: "delete_station"
+          call_id: "delete"
+      calls:
+        forecast:
+          method: "HTTP_METHOD_GET"
+          endpoint_path: "/forecast"
+        delete:
+          method: "HTTP_METHOD_DELETE"
+          endpoint_path: "/stations"
+`), 0o600))
+	require.NoError(t, os.WriteFile(proposedPath, []byte(`
+upstream_services:
+  - name: "weather"
+    http_service:
+      address: "https://api.weather.example"
+      tools:
+        - name: "forecast"
+          call_id: "forecast"
+        - name: "delete_station"
+          call_id: "delete"
+      calls:
+        forecast:
+          method: "HTTP_METHOD_GET"
+          endpoint_path: "/forecast"
+        delete:
+          method: "HTTP_METHOD_DELETE"
+          endpoint_path: "/stations"
+    call_policies:
+      - default_action: ALLOW
+        rules:
+          - action: DENY
+            name_regex: "delete_.*"
+`), 0o600))
+
+	store, err := audit.NewSQLiteAuditStore(auditPath)
+	require.NoError(t, err)
+	now := time.Now()
+	for _, e := range []audit.Entry{
+		{ID: "old", Timestamp: now.Add(-2 * time.Hour), ToolName: "weather.delete_station"},
+		{ID: "a", Timestamp: now.Add(-30 * time.Minute), ToolName: "weather.get_forecast", Arguments: json.RawMessage(`{}`)},
+		{ID: "b", Timestamp: now.Add(-20 * time.Minute), ToolName: "weather.delete_station", Arguments: json.RawMessage(`{}`)},
+	} {
+		require.NoError(t, store.Write(context.Background(), e))
+	}
+	require.NoError(t, store.Close())
+
+	run := func(args ...string) (string, error) {
+		viper.Reset()
+		cmd := newRootCmd()
+		b := bytes.NewBufferString("")
+		cmd.SetOut(b)
+		cmd.SetErr(b)
+		cmd.SetArgs(append([]string{"config", "preview"}, args...))
+		err := cmd.Execute()
+		return b.String(), err
+	}
+
+	out, err := run("-f", proposedPath, "--config-path", currentPath, "--replay", "1h")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Replayed 2 calls from the last 1h0m0s: 0 unchanged, 1 blocked, 1 renamed, 0 rerouted")
+	assert.Contains(t, out, "weather.get_forecast")
+	assert.Contains(t, out, "now weather.forecast")
+	assert.Contains(t, out, "denied by call policy")
+
+	out, err = run("-f", proposedPath, "--audit-db", auditPath, "--replay", "3h")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Replayed 3 calls from the last 3h0m0s: 1 unchanged, 2 blocked, 0 renamed, 0 rerouted")
+
+	_, err = run("-f", proposedPath)
+	assert.ErrorContains(t, err, "no audit log to replay")
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/storage/migrate"
+	"github.com/mcpany/core/server/pkg/storage/postgres"
+	"github.com/mcpany/core/server/pkg/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+// openMigrator opens the server's database without migrating it.
+//
+// Parameters:
+//   - driver: string. The database driver: sqlite (or empty) or postgres.
+//   - path: string. The SQLite database file.
+//   - dsn: string. The PostgreSQL connection string.
+//
+// Returns:
+//   - *migrate.Migrator: The migrator of the database.
+//   - func() error: Closes the database.
+//   - error: An error if the database cannot be opened.
+func openMigrator(driver, path, dsn string) (*migrate.Migrator, func() error, error) {
+	var (
+		m       *migrate.Migrator
+		closeDB func() error
+		err     error
+	)
+	switch driver {
+	case "", "sqlite":
+		var db *sqlite.DB
+		if db, err = sqlite.Open(path); err != nil {
+			return nil, nil, fmt.Errorf("failed to open database: %w", err)
+		}
+		m, err = db.Migrator()
+		closeDB = db.Close
+	case "postgres":
+		if dsn == "" {
+			return nil, nil, fmt.Errorf("--db-dsn is required with the postgres driver")
+		}
+		var db *postgres.DB
+		if db, err = postgres.Open(dsn); err != nil {
+			return nil, nil, fmt.Errorf("failed to open database: %w", err)
+		}
+		m, err = db.Migrator()
+		closeDB = db.Close
+	default:
+		return nil, nil, fmt.Errorf("unsupported db driver: %s", driver)
+	}
+	if err != nil {
+		_ = closeDB()
+		return nil, nil, err
+	}
+	return m, closeDB, nil
+}
+
+// newDBCmd creates the db command group.
+//
+// These commands inspect and change the schema version of the server's
+// database. The server applies the pending migrations when it starts, so
+// they are mostly needed to revert migrations before downgrading.
+//
+// Returns:
+//   - *cobra.Command: The configured db command.
+func newDBCmd() *cobra.Command {
+	var driver, dbPath, dsn string
+	dbCmd := &cobra.Command{
+		Use:   "db",
+		Short: "Manage the schema of the server's database",
+	}
+	dbCmd.PersistentFlags().StringVar(&driver, "db-driver", envOr("MCPANY_DB_DRIVER", "sqlite"), "Database driver: sqlite or postgres. Env: MCPANY_DB_DRIVER")
+	dbCmd.PersistentFlags().StringVar(&dbPath, "db-path", envOr("MCPANY_DB_PATH", "data/mcpany.db"), "Path to the server's SQLite database file. Env: MCPANY_DB_PATH")
+	dbCmd.PersistentFlags().StringVar(&dsn, "db-dsn", envOr("MCPANY_DB_DSN", ""), "Connection string of the server's PostgreSQL database. Env: MCPANY_DB_DSN")
+
+	var asJSON bool
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the applied and pending schema migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			m, closeDB, err := openMigrator(driver, dbPath, dsn)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = closeDB() }()
+
+			statuses, err := m.Status(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to read the schema version: %w", err)
+			}
+			if asJSON {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(statuses)
+			}
+			printMigrations(cmd.OutOrStdout(), statuses, m.Latest())
+			return nil
+		},
+	}
+	statusCmd.Flags().BoolVar(&asJSON, "json", false, "Print the migrations as JSON")
+
+	var target int
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply the pending schema migrations, or migrate to a version with --to",
+		Long: `Apply the pending schema migrations, or migrate to a version with --to.
+
+With --to, the migrations above the version are reverted, newest first.
+Revert the migrations of a release with its binary before downgrading to
+an older release. --to 0 reverts all migrations and drops all tables.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			m, closeDB, err := openMigrator(driver, dbPath, dsn)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = closeDB() }()
+
+			var n int
+			if cmd.Flags().Changed("to") {
+				n, err = m.To(cmd.Context(), target)
+			} else {
+				n, err = m.Up(cmd.Context())
+			}
+			if err != nil {
+				return fmt.Errorf("migration failed after %d migrations: %w", n, err)
+			}
+			if n == 0 {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "The schema is up to date.")
+				return nil
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Ran %d migrations.\n", n)
+			return nil
+		},
+	}
+	migrateCmd.Flags().IntVar(&target, "to", 0, "Target schema version; reverts the migrations above it")
+
+	dbCmd.AddCommand(statusCmd, migrateCmd)
+	return dbCmd
+}
+
+// printMigrations prints the migrations as a table.
+func printMigrations(out io.Writer, statuses []migrate.Status, latest int) {
+	current := 0
+	for _, s := range statuses {
+		if s.Applied {
+			current = s.Version
+		}
+	}
+	_, _ = fmt.Fprintf(out, "Schema version: %d (latest known: %d)\n\n", current, latest)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "VERSION\tNAME\tSTATUS")
+	for _, s := range statuses {
+		status := "pending"
+		switch {
+		case s.Unknown:
+			status = "applied " + s.AppliedAt.Format(time.RFC3339) + " (unknown to this release)"
+		case s.Applied:
+			status = "applied " + s.AppliedAt.Format(time.RFC3339)
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, status)
+	}
+	_ = w.Flush()
+}
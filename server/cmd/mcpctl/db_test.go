@@ -0,0 +1,64 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/mcpany/core/server/pkg/storage/migrate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBCmd(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "mcpany.db")
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		b := bytes.NewBufferString("")
+		cmd.SetOut(b)
+		cmd.SetErr(b)
+		cmd.SetArgs(append(append([]string{"db"}, args...), "--db-path", dbPath))
+		err := cmd.Execute()
+		return b.String(), err
+	}
+
+	out, err := run("status")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Schema version: 0")
+	assert.Contains(t, out, "initial_schema")
+	assert.Contains(t, out, "pending")
+
+	out, err = run("migrate")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Ran 6 migrations.")
+
+	out, err = run("migrate")
+	require.NoError(t, err)
+	assert.Contains(t, out, "up to date")
+
+	out, err = run("status", "--json")
+	require.NoError(t, err)
+	var statuses []migrate.Status
+	require.NoError(t, json.Unmarshal([]byte(out), &statuses))
+	require.NotEmpty(t, statuses)
+	assert.True(t, statuses[0].Applied)
+
+	out, err = run("migrate", "--to", "0")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Ran 6 migrations.")
+
+	out, err = run("status")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Schema version: 0")
+
+	_, err = run("status", "--db-driver", "postgres")
+	assert.ErrorContains(t, err, "--db-dsn is required")
+
+	_, err = run("status", "--db-driver", "mysql")
+	assert.ErrorContains(t, err, "unsupported db driver")
+}
@@ -0,0 +1,208 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/cobra"
+)
+
+// clientFormats are the MCP hosts that export client supports, with the
+// configuration file the stanza goes in.
+var clientFormats = map[string]string{
+	"claude-desktop": "claude_desktop_config.json",
+	"cursor":         "~/.cursor/mcp.json, or .cursor/mcp.json in a project",
+	"vscode":         ".vscode/mcp.json, or the MCP servers of your user settings",
+	"gemini-cli":     "~/.gemini/settings.json, or .gemini/settings.json in a project",
+	"codex":          "~/.codex/config.toml",
+}
+
+// clientExport describes how an MCP host reaches this mcpany instance.
+type clientExport struct {
+	Name       string
+	ServerURL  string
+	APIKey     string
+	Stdio      bool
+	Command    string
+	ConfigPath []string
+}
+
+// stdioArgs returns the arguments that run the server over stdio.
+func (e *clientExport) stdioArgs() []string {
+	args := []string{"run"}
+	for _, p := range e.ConfigPath {
+		args = append(args, "--config-path", p)
+	}
+	return append(args, "--stdio")
+}
+
+// headers returns the HTTP headers that a host sends to the server.
+func (e *clientExport) headers() map[string]string {
+	if e.APIKey == "" {
+		return nil
+	}
+	return map[string]string{"X-API-Key": e.APIKey}
+}
+
+// newExportCmd creates the export command, which prints configuration for
+// other tools.
+//
+// Returns:
+//   - *cobra.Command: The configured export command.
+func newExportCmd() *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export configuration for other tools",
+	}
+	exportCmd.AddCommand(newExportClientCmd())
+	return exportCmd
+}
+
+// newExportClientCmd creates the export client command, which prints the
+// stanza an MCP host needs to connect to this mcpany instance.
+//
+// Returns:
+//   - *cobra.Command: The configured export client command.
+func newExportClientCmd() *cobra.Command {
+	var (
+		format string
+		e      clientExport
+	)
+	cmd := &cobra.Command{
+		Use:   "client --format <host>",
+		Short: "Print the configuration an MCP host needs to connect to the server",
+		Long: fmt.Sprintf(`Print the stanza to add to the configuration of an MCP host so that it
+connects to this mcpany instance, and where it goes. Formats: %s.
+
+By default the host connects to the server at --server over streamable HTTP,
+sending --api-key in the X-API-Key header. Claude Desktop only runs local
+commands, so it connects through the mcp-remote bridge. With --stdio the host
+runs the server itself, with --config-path.
+
+The stanza holds the API key as given: keep the host configuration private.`, strings.Join(clientFormatNames(), ", ")),
+		Example: `  mcpctl export client --format cursor --api-key $MCPANY_API_KEY
+  mcpctl export client --format claude-desktop --stdio --config-path /etc/mcpany/config.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			where, ok := clientFormats[format]
+			if !ok {
+				return fmt.Errorf("unsupported format %q: use one of %s", format, strings.Join(clientFormatNames(), ", "))
+			}
+			if e.Stdio && len(e.ConfigPath) == 0 {
+				return fmt.Errorf("--stdio requires --config-path")
+			}
+			if !e.Stdio {
+				if u, err := url.Parse(e.ServerURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					return fmt.Errorf("invalid server URL %q", e.ServerURL)
+				}
+			}
+			out, err := renderClientConfig(format, &e)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Add this to %s:\n\n", where)
+			_, err = cmd.OutOrStdout().Write(out)
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "", "The MCP host: "+strings.Join(clientFormatNames(), ", "))
+	cmd.Flags().StringVar(&e.Name, "name", "mcpany", "The name of the server in the host")
+	cmd.Flags().StringVar(&e.ServerURL, "server", envOr("MCPANY_SERVER_URL", "http://localhost:50050"), "Base URL of the running server. Env: MCPANY_SERVER_URL")
+	cmd.Flags().StringVar(&e.APIKey, "api-key", envOr("MCPANY_API_KEY", ""), "API key of the server, sent in the X-API-Key header. Env: MCPANY_API_KEY")
+	cmd.Flags().BoolVar(&e.Stdio, "stdio", false, "Have the host run the server over stdio instead of connecting to --server")
+	cmd.Flags().StringVar(&e.Command, "command", "mcpany", "The server binary the host runs, with --stdio")
+	cmd.Flags().StringSliceVar(&e.ConfigPath, "config-path", nil, "The configuration of the server the host runs, with --stdio")
+	_ = cmd.MarkFlagRequired("format")
+	return cmd
+}
+
+// clientFormatNames returns the supported formats, sorted.
+func clientFormatNames() []string {
+	names := make([]string, 0, len(clientFormats))
+	for name := range clientFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// renderClientConfig renders the stanza of a host.
+func renderClientConfig(format string, e *clientExport) ([]byte, error) {
+	server := map[string]any{}
+	switch {
+	case e.Stdio:
+		server["command"] = e.Command
+		server["args"] = e.stdioArgs()
+		if format == "vscode" {
+			server["type"] = "stdio"
+		}
+	case format == "claude-desktop":
+		server = claudeDesktopBridge(e)
+	case format == "vscode":
+		server["type"] = "http"
+		server["url"] = e.ServerURL
+	case format == "gemini-cli":
+		server["httpUrl"] = e.ServerURL
+	default:
+		server["url"] = e.ServerURL
+	}
+	if headers := e.headers(); headers != nil && !e.Stdio && format != "claude-desktop" {
+		if format == "codex" {
+			server["http_headers"] = headers
+		} else {
+			server["headers"] = headers
+		}
+	}
+
+	switch format {
+	case "codex":
+		out, err := toml.Marshal(map[string]any{"mcp_servers": map[string]any{e.Name: server}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal to TOML: %w", err)
+		}
+		return out, nil
+	case "vscode":
+		return marshalClientJSON(map[string]any{"servers": map[string]any{e.Name: server}})
+	default:
+		return marshalClientJSON(map[string]any{"mcpServers": map[string]any{e.Name: server}})
+	}
+}
+
+// claudeDesktopBridge returns the server of Claude Desktop, which reaches a
+// remote server through the mcp-remote bridge. The API key is passed in the
+// environment, as mcp-remote mangles arguments with spaces on some
+// platforms.
+func claudeDesktopBridge(e *clientExport) map[string]any {
+	args := []string{"-y", "mcp-remote", e.ServerURL}
+	if u, err := url.Parse(e.ServerURL); err == nil && u.Scheme == "http" && u.Hostname() != "localhost" && u.Hostname() != "127.0.0.1" {
+		// mcp-remote refuses plain HTTP to other hosts unless told to.
+		args = append(args, "--allow-http")
+	}
+	server := map[string]any{"command": "npx"}
+	if e.APIKey != "" {
+		args = append(args, "--header", "X-API-Key:${MCPANY_API_KEY}")
+		server["env"] = map[string]string{"MCPANY_API_KEY": e.APIKey}
+	}
+	server["args"] = args
+	return server
+}
+
+// marshalClientJSON renders a stanza as indented JSON, without escaping the
+// characters that JSON escapes for HTML.
+func marshalClientJSON(v any) ([]byte, error) {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("failed to marshal to JSON: %w", err)
+	}
+	return []byte(b.String()), nil
+}
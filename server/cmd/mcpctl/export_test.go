@@ -0,0 +1,107 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportClientCmd(t *testing.T) {
+	run := func(args ...string) (string, string, error) {
+		cmd := newRootCmd()
+		stdout, stderr := bytes.NewBufferString(""), bytes.NewBufferString("")
+		cmd.SetOut(stdout)
+		cmd.SetErr(stderr)
+		cmd.SetArgs(append([]string{"export", "client"}, args...))
+		err := cmd.Execute()
+		return stdout.String(), stderr.String(), err
+	}
+	server := func(t *testing.T, out, key string) map[string]any {
+		t.Helper()
+		var doc map[string]map[string]map[string]any
+		require.NoError(t, json.Unmarshal([]byte(out), &doc), out)
+		require.Contains(t, doc[key], "mcpany")
+		return doc[key]["mcpany"]
+	}
+	remote := []string{"--server", "https://mcp.example.com", "--api-key", "secret"}
+
+	t.Run("cursor", func(t *testing.T) {
+		out, hint, err := run(append([]string{"--format", "cursor"}, remote...)...)
+		require.NoError(t, err)
+		assert.Contains(t, hint, "~/.cursor/mcp.json")
+		assert.Equal(t, map[string]any{
+			"url":     "https://mcp.example.com",
+			"headers": map[string]any{"X-API-Key": "secret"},
+		}, server(t, out, "mcpServers"))
+	})
+
+	t.Run("vscode", func(t *testing.T) {
+		out, _, err := run(append([]string{"--format", "vscode"}, remote...)...)
+		require.NoError(t, err)
+		s := server(t, out, "servers")
+		assert.Equal(t, "http", s["type"])
+		assert.Equal(t, "https://mcp.example.com", s["url"])
+	})
+
+	t.Run("gemini-cli", func(t *testing.T) {
+		out, _, err := run(append([]string{"--format", "gemini-cli"}, remote...)...)
+		require.NoError(t, err)
+		s := server(t, out, "mcpServers")
+		assert.Equal(t, "https://mcp.example.com", s["httpUrl"])
+		assert.Equal(t, map[string]any{"X-API-Key": "secret"}, s["headers"])
+	})
+
+	t.Run("claude-desktop", func(t *testing.T) {
+		out, _, err := run(append([]string{"--format", "claude-desktop"}, remote...)...)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"command": "npx",
+			"args":    []any{"-y", "mcp-remote", "https://mcp.example.com", "--header", "X-API-Key:${MCPANY_API_KEY}"},
+			"env":     map[string]any{"MCPANY_API_KEY": "secret"},
+		}, server(t, out, "mcpServers"))
+
+		out, _, err = run("--format", "claude-desktop", "--server", "http://mcp.internal:50050", "--api-key", "")
+		require.NoError(t, err)
+		assert.Equal(t, []any{"-y", "mcp-remote", "http://mcp.internal:50050", "--allow-http"}, server(t, out, "mcpServers")["args"])
+	})
+
+	t.Run("codex", func(t *testing.T) {
+		out, _, err := run(append([]string{"--format", "codex"}, remote...)...)
+		require.NoError(t, err)
+		var doc struct {
+			MCPServers map[string]struct {
+				URL         string            `toml:"url"`
+				HTTPHeaders map[string]string `toml:"http_headers"`
+			} `toml:"mcp_servers"`
+		}
+		require.NoError(t, toml.Unmarshal([]byte(out), &doc), out)
+		assert.Equal(t, "https://mcp.example.com", doc.MCPServers["mcpany"].URL)
+		assert.Equal(t, map[string]string{"X-API-Key": "secret"}, doc.MCPServers["mcpany"].HTTPHeaders)
+	})
+
+	t.Run("stdio", func(t *testing.T) {
+		out, _, err := run("--format", "vscode", "--stdio", "--command", "/usr/local/bin/mcpany", "--config-path", "/etc/mcpany/config.yaml", "--api-key", "secret")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"type":    "stdio",
+			"command": "/usr/local/bin/mcpany",
+			"args":    []any{"run", "--config-path", "/etc/mcpany/config.yaml", "--stdio"},
+		}, server(t, out, "servers"), "a local server needs no API key")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, _, err := run("--format", "zed")
+		assert.ErrorContains(t, err, `unsupported format "zed"`)
+		_, _, err = run("--format", "cursor", "--stdio")
+		assert.ErrorContains(t, err, "--stdio requires --config-path")
+		_, _, err = run("--format", "cursor", "--server", "mcp.example.com")
+		assert.ErrorContains(t, err, "invalid server URL")
+	})
+}
@@ -149,6 +149,7 @@ func newImportCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Path to write the output YAML file (default: stdout)")
+	cmd.AddCommand(newImportMCPCmd())
 
 	return cmd
 }
@@ -0,0 +1,257 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+// mcpInventory is what an MCP server lists: its tools, resources and prompts.
+type mcpInventory struct {
+	ServerName    string
+	ServerVersion string
+	Tools         []*mcp.Tool
+	Resources     []*mcp.Resource
+	Prompts       []*mcp.Prompt
+}
+
+// newImportMCPCmd creates the import mcp command, which generates the
+// upstream service of a running MCP server.
+//
+// Returns:
+//   - *cobra.Command: The configured import mcp command.
+func newImportMCPCmd() *cobra.Command {
+	var (
+		name       string
+		outputPath string
+		pin        bool
+		timeout    time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "mcp <url> | mcp -- <command> [args...]",
+		Short: "Import the tools of a running MCP server",
+		Long: `Connect to an MCP server, list its tools, resources and prompts, and
+generate its upstream service.
+
+Give the URL of a streamable HTTP endpoint, or a command that serves MCP over
+stdio after --. Each tool is listed in the service with its description.
+Resources and prompts are proxied from the server as they are, and are listed
+in a comment.
+
+With --pin, the SHA256 hash of each tool is pinned in its integrity, and tool
+auto-discovery is off: when the server changes a tool, or adds one, the
+change is not exposed until the service is imported again.`,
+		Example: `  mcpctl import mcp https://mcp.example.com/mcp --pin
+  mcpctl import mcp --name files -- npx -y @modelcontextprotocol/server-filesystem /srv`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				transport mcp.Transport
+				svc       = configv1.McpUpstreamService_builder{}
+			)
+			if isHTTPAddress(args[0]) {
+				if len(args) > 1 {
+					return fmt.Errorf("unexpected arguments after the URL: %s", strings.Join(args[1:], " "))
+				}
+				transport = &mcp.StreamableClientTransport{Endpoint: args[0]}
+				svc.HttpConnection = configv1.McpStreamableHttpConnection_builder{
+					HttpAddress: proto.String(args[0]),
+				}.Build()
+			} else {
+				c := exec.CommandContext(cmd.Context(), args[0], args[1:]...) //nolint:gosec // The command is chosen by the user.
+				c.Stderr = cmd.ErrOrStderr()
+				transport = &mcp.CommandTransport{Command: c}
+				svc.StdioConnection = configv1.McpStdioConnection_builder{
+					Command: proto.String(args[0]),
+					Args:    args[1:],
+				}.Build()
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+			inv, err := listMCPInventory(ctx, transport)
+			if err != nil {
+				return fmt.Errorf("failed to list the MCP server at %s: %w", strings.Join(args, " "), err)
+			}
+			if name == "" {
+				name = defaultImportName(args[0], inv)
+			}
+
+			tools, err := importedTools(inv.Tools, pin)
+			if err != nil {
+				return err
+			}
+			svc.Tools = tools
+			svc.ToolAutoDiscovery = proto.Bool(!pin)
+			service := configv1.UpstreamServiceConfig_builder{
+				Name:       proto.String(name),
+				McpService: svc.Build(),
+			}.Build()
+
+			out, err := renderImportedMCPConfig(strings.Join(args, " "), inv, service)
+			if err != nil {
+				return err
+			}
+			if outputPath != "" {
+				if err := os.WriteFile(outputPath, out, 0o600); err != nil {
+					return fmt.Errorf("failed to write output file: %w", err)
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Imported %d tools of %s to %s\n", len(inv.Tools), name, outputPath)
+				return nil
+			}
+			_, err = cmd.OutOrStdout().Write(out)
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&name, "name", "", "The name of the service (default: the name the server reports)")
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Path to write the output YAML file (default: stdout)")
+	cmd.Flags().BoolVar(&pin, "pin", false, "Pin the hash of each tool, and turn tool auto-discovery off, to detect drift")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "How long to wait for the server to connect and list its tools")
+	return cmd
+}
+
+// isHTTPAddress reports whether the address of an MCP server is a URL,
+// rather than a command.
+func isHTTPAddress(address string) bool {
+	return strings.HasPrefix(address, "http://") || strings.HasPrefix(address, "https://")
+}
+
+// listMCPInventory connects to an MCP server and lists its tools, resources
+// and prompts. Resources and prompts are only listed when the server has
+// the capability.
+func listMCPInventory(ctx context.Context, transport mcp.Transport) (*mcpInventory, error) {
+	client := mcp.NewClient(&mcp.Implementation{Name: "mcpctl", Version: Version}, nil)
+	cs, err := client.Connect(ctx, transport, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = cs.Close() }()
+
+	inv := &mcpInventory{}
+	caps := &mcp.ServerCapabilities{}
+	if res := cs.InitializeResult(); res != nil {
+		if res.ServerInfo != nil {
+			inv.ServerName = res.ServerInfo.Name
+			inv.ServerVersion = res.ServerInfo.Version
+		}
+		if res.Capabilities != nil {
+			caps = res.Capabilities
+		}
+	}
+	for t, err := range cs.Tools(ctx, nil) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
+		inv.Tools = append(inv.Tools, t)
+	}
+	if caps.Resources != nil {
+		for r, err := range cs.Resources(ctx, nil) {
+			if err != nil {
+				return nil, fmt.Errorf("failed to list resources: %w", err)
+			}
+			inv.Resources = append(inv.Resources, r)
+		}
+	}
+	if caps.Prompts != nil {
+		for p, err := range cs.Prompts(ctx, nil) {
+			if err != nil {
+				return nil, fmt.Errorf("failed to list prompts: %w", err)
+			}
+			inv.Prompts = append(inv.Prompts, p)
+		}
+	}
+	return inv, nil
+}
+
+// importedTools returns the tool definitions of the listed tools, with their
+// hashes pinned if pin is set.
+func importedTools(listed []*mcp.Tool, pin bool) ([]*configv1.ToolDefinition, error) {
+	tools := make([]*configv1.ToolDefinition, 0, len(listed))
+	for _, t := range listed {
+		def := configv1.ToolDefinition_builder{
+			Name:        proto.String(t.Name),
+			Description: proto.String(t.Description),
+		}
+		if pin {
+			hash, err := tool.CalculateMCPToolHash(t)
+			if err != nil {
+				return nil, fmt.Errorf("failed to hash tool %s: %w", t.Name, err)
+			}
+			def.Integrity = configv1.Integrity_builder{
+				Hash:      proto.String(hash),
+				Algorithm: proto.String("sha256"),
+			}.Build()
+		}
+		tools = append(tools, def.Build())
+	}
+	return tools, nil
+}
+
+// defaultImportName returns the name of an imported service: the name the
+// server reports, or else the host of its URL or the base name of its
+// command.
+func defaultImportName(address string, inv *mcpInventory) string {
+	if inv.ServerName != "" {
+		return inv.ServerName
+	}
+	if u, err := url.Parse(address); err == nil && isHTTPAddress(address) {
+		return strings.NewReplacer(".", "-", ":", "-").Replace(u.Host)
+	}
+	return filepath.Base(address)
+}
+
+// renderImportedMCPConfig renders an imported service as a configuration
+// file, with a comment on where it comes from.
+func renderImportedMCPConfig(source string, inv *mcpInventory, svc *configv1.UpstreamServiceConfig) ([]byte, error) {
+	cfg := configv1.McpAnyServerConfig_builder{
+		UpstreamServices: []*configv1.UpstreamServiceConfig{svc},
+	}.Build()
+	jsonData, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the service: %w", err)
+	}
+	yamlData, err := sigsyaml.JSONToYAML(jsonData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal to YAML: %w", err)
+	}
+
+	var b strings.Builder
+	server := strings.TrimSpace(inv.ServerName + " " + inv.ServerVersion)
+	if server != "" {
+		server = " (" + server + ")"
+	}
+	fmt.Fprintf(&b, "# Imported from %s%s by mcpctl import mcp.\n", source, server)
+	fmt.Fprintf(&b, "# Tools: %d\n", len(inv.Tools))
+	if len(inv.Resources) > 0 {
+		names := make([]string, len(inv.Resources))
+		for i, r := range inv.Resources {
+			names[i] = r.URI
+		}
+		fmt.Fprintf(&b, "# Resources, proxied as they are: %s\n", strings.Join(names, ", "))
+	}
+	if len(inv.Prompts) > 0 {
+		names := make([]string, len(inv.Prompts))
+		for i, p := range inv.Prompts {
+			names[i] = p.Name
+		}
+		fmt.Fprintf(&b, "# Prompts, proxied as they are: %s\n", strings.Join(names, ", "))
+	}
+	b.Write(yamlData)
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/config"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type weatherArgs struct {
+	City string `json:"city"`
+}
+
+func newWeatherMCPServer() *mcp.Server {
+	server := mcp.NewServer(&mcp.Implementation{Name: "weather-mcp", Version: "0.3.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "get_forecast", Description: "Get the forecast of a city"},
+		func(_ context.Context, _ *mcp.CallToolRequest, _ weatherArgs) (*mcp.CallToolResult, any, error) {
+			return &mcp.CallToolResult{}, nil, nil
+		})
+	mcp.AddTool(server, &mcp.Tool{Name: "get_alerts", Description: "Get the alerts of a city"},
+		func(_ context.Context, _ *mcp.CallToolRequest, _ weatherArgs) (*mcp.CallToolResult, any, error) {
+			return &mcp.CallToolResult{}, nil, nil
+		})
+	server.AddResource(&mcp.Resource{URI: "weather://stations", Name: "stations"},
+		func(_ context.Context, _ *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			return &mcp.ReadResourceResult{}, nil
+		})
+	server.AddPrompt(&mcp.Prompt{Name: "summarize_week"},
+		func(_ context.Context, _ *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return &mcp.GetPromptResult{}, nil
+		})
+	return server
+}
+
+func TestImportMCPCmd(t *testing.T) {
+	server := newWeatherMCPServer()
+	ts := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server { return server }, nil))
+	defer ts.Close()
+
+	load := func(t *testing.T, out []byte) *configv1.UpstreamServiceConfig {
+		t.Helper()
+		engine, err := config.NewEngine("imported.yaml")
+		require.NoError(t, err)
+		cfg := &configv1.McpAnyServerConfig{}
+		require.NoError(t, engine.Unmarshal(out, cfg), string(out))
+		require.Len(t, cfg.GetUpstreamServices(), 1)
+		return cfg.GetUpstreamServices()[0]
+	}
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		b := bytes.NewBufferString("")
+		cmd.SetOut(b)
+		cmd.SetErr(b)
+		cmd.SetArgs(append([]string{"import", "mcp"}, args...))
+		err := cmd.Execute()
+		return b.String(), err
+	}
+
+	t.Run("http", func(t *testing.T) {
+		out, err := run(ts.URL)
+		require.NoError(t, err)
+		assert.Contains(t, out, "# Imported from "+ts.URL+" (weather-mcp 0.3.0) by mcpctl import mcp.")
+		assert.Contains(t, out, "# Resources, proxied as they are: weather://stations")
+		assert.Contains(t, out, "# Prompts, proxied as they are: summarize_week")
+
+		svc := load(t, []byte(out))
+		assert.Equal(t, "weather-mcp", svc.GetName())
+		assert.Equal(t, ts.URL, svc.GetMcpService().GetHttpConnection().GetHttpAddress())
+		assert.True(t, svc.GetMcpService().GetToolAutoDiscovery())
+		require.Len(t, svc.GetMcpService().GetTools(), 2)
+		assert.ElementsMatch(t, []string{"get_forecast", "get_alerts"},
+			[]string{svc.GetMcpService().GetTools()[0].GetName(), svc.GetMcpService().GetTools()[1].GetName()})
+		for _, def := range svc.GetMcpService().GetTools() {
+			assert.NotEmpty(t, def.GetDescription())
+			assert.False(t, def.HasIntegrity())
+		}
+	})
+
+	t.Run("pin", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "weather.yaml")
+		out, err := run(ts.URL, "--pin", "--name", "weather", "-o", path)
+		require.NoError(t, err)
+		assert.Contains(t, out, "Imported 2 tools of weather to "+path)
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		svc := load(t, data)
+		assert.Equal(t, "weather", svc.GetName())
+		assert.False(t, svc.GetMcpService().GetToolAutoDiscovery())
+
+		// The pinned hashes match the tools the server lists, until one changes.
+		inv, err := listMCPInventory(context.Background(), &mcp.StreamableClientTransport{Endpoint: ts.URL})
+		require.NoError(t, err)
+		pins := make(map[string]*configv1.Integrity)
+		for _, def := range svc.GetMcpService().GetTools() {
+			assert.Equal(t, "sha256", def.GetIntegrity().GetAlgorithm())
+			pins[def.GetName()] = def.GetIntegrity()
+		}
+		for _, listed := range inv.Tools {
+			assert.NoError(t, tool.VerifyMCPToolIntegrity(listed, pins[listed.Name]), listed.Name)
+			listed.Description += " Changed."
+			assert.Error(t, tool.VerifyMCPToolIntegrity(listed, pins[listed.Name]), listed.Name)
+		}
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		_, err := run("http://127.0.0.1:1/mcp", "--timeout", "2s")
+		assert.ErrorContains(t, err, "failed to list the MCP server at http://127.0.0.1:1/mcp")
+	})
+
+	t.Run("arguments after the url", func(t *testing.T) {
+		_, err := run(ts.URL, "extra")
+		assert.ErrorContains(t, err, "unexpected arguments after the URL")
+	})
+}
+
+func TestListMCPInventory_Stdio(t *testing.T) {
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	session, err := newWeatherMCPServer().Connect(context.Background(), serverTransport, nil)
+	require.NoError(t, err)
+	defer func() { _ = session.Close() }()
+
+	inv, err := listMCPInventory(context.Background(), clientTransport)
+	require.NoError(t, err)
+	assert.Equal(t, "weather-mcp", inv.ServerName)
+	assert.Len(t, inv.Tools, 2)
+	assert.Len(t, inv.Resources, 1)
+	assert.Len(t, inv.Prompts, 1)
+}
+
+func TestDefaultImportName(t *testing.T) {
+	assert.Equal(t, "weather-mcp", defaultImportName("npx", &mcpInventory{ServerName: "weather-mcp"}))
+	assert.Equal(t, "mcp-example-com", defaultImportName("https://mcp.example.com/mcp", &mcpInventory{}))
+	assert.Equal(t, "server-filesystem", defaultImportName("/usr/local/bin/server-filesystem", &mcpInventory{}))
+}
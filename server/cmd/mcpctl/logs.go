@@ -0,0 +1,197 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+// maxLogLine caps the size of a streamed log entry.
+const maxLogLine = 1 << 20
+
+// newLogsCmd creates the logs command group.
+//
+// Returns:
+//   - *cobra.Command: The configured logs command.
+func newLogsCmd() *cobra.Command {
+	logsCmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Read the logs of the running server",
+	}
+
+	var (
+		serverURL string
+		apiKey    string
+		service   string
+		level     string
+		since     time.Duration
+		follow    bool
+		asJSON    bool
+	)
+	tailCmd := &cobra.Command{
+		Use:   "tail",
+		Short: "Stream the logs of the running server",
+		Long: `Stream the logs of the running server over its admin API, so that no
+shell access to the host or its database is needed.
+
+The stream starts with the recent entries the server keeps in memory (the
+last 1000, restored from its database at startup when log persistence is
+on), then follows new entries until interrupted; with --follow=false it
+stops after the recent entries. --service keeps the entries about one
+service, --level the entries at or above a level, and --since the entries
+of the given last duration. Requires an admin API key.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if level != "" {
+				if _, err := logging.ParseLevel(level); err != nil {
+					return err
+				}
+			}
+			if since < 0 {
+				return errors.New("--since must not be negative")
+			}
+			query := url.Values{}
+			if service != "" {
+				query.Set("service", service)
+			}
+			if level != "" {
+				query.Set("level", level)
+			}
+			if since > 0 {
+				query.Set("since", since.String())
+			}
+			if !follow {
+				query.Set("follow", "false")
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+			out := cmd.OutOrStdout()
+			return streamLogs(ctx, &http.Client{}, serverURL, apiKey, query, func(entry logging.LogEntry) {
+				if asJSON {
+					data, _ := json.Marshal(entry)
+					_, _ = fmt.Fprintf(out, "%s\n", data)
+					return
+				}
+				printLogEntry(out, entry)
+			})
+		},
+	}
+	tailCmd.Flags().StringVar(&serverURL, "server", envOr("MCPANY_SERVER_URL", "http://localhost:50050"), "Base URL of the running server. Env: MCPANY_SERVER_URL")
+	tailCmd.Flags().StringVar(&apiKey, "api-key", envOr("MCPANY_API_KEY", ""), "API key of the server, sent in the X-API-Key header. Env: MCPANY_API_KEY")
+	tailCmd.Flags().StringVar(&service, "service", "", "Only show the entries about the service with this ID or name")
+	tailCmd.Flags().StringVar(&level, "level", "", "Only show the entries at or above this level: debug, info, warn or error")
+	tailCmd.Flags().DurationVar(&since, "since", 0, "Only show the entries of this last duration, e.g. 10m")
+	tailCmd.Flags().BoolVarP(&follow, "follow", "f", true, "Keep streaming new entries")
+	tailCmd.Flags().BoolVar(&asJSON, "json", false, "Print each entry as a line of JSON")
+	logsCmd.AddCommand(tailCmd)
+
+	return logsCmd
+}
+
+// streamLogs calls fn with each entry of the log stream of the server until
+// the stream ends or the context is done.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the stream.
+//   - client: *http.Client. The HTTP client.
+//   - serverURL: string. The base URL of the server.
+//   - apiKey: string. The API key of the server, or empty.
+//   - query: url.Values. The filters of the stream.
+//   - fn: func(logging.LogEntry). Called with each entry.
+//
+// Returns:
+//   - error: An error if the stream cannot be opened or breaks.
+func streamLogs(ctx context.Context, client *http.Client, serverURL, apiKey string, query url.Values, fn func(logging.LogEntry)) error {
+	endpoint := strings.TrimSuffix(serverURL, "/") + "/api/v1/logs/stream"
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("failed to reach the server: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("the server rejected the request (%s); pass an admin --api-key", resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("the server returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogLine)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var entry logging.LogEntry
+		if err := json.Unmarshal([]byte(data), &entry); err == nil {
+			fn(entry)
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("log stream broken: %w", err)
+	}
+	return nil
+}
+
+// printLogEntry prints an entry on one line: its time, level, source and
+// message, then its attributes as key=value, sorted by key.
+func printLogEntry(out io.Writer, entry logging.LogEntry) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %-5s", entry.Timestamp, entry.Level)
+	if entry.Source != "" {
+		fmt.Fprintf(&b, " [%s]", entry.Source)
+	}
+	b.WriteString(" ")
+	b.WriteString(entry.Message)
+	keys := make([]string, 0, len(entry.Metadata))
+	for k := range entry.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := entry.Metadata[k]
+		if s, ok := v.(string); ok {
+			fmt.Fprintf(&b, " %s=%q", k, s)
+			continue
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			data = []byte(fmt.Sprint(v))
+		}
+		fmt.Fprintf(&b, " %s=%s", k, data)
+	}
+	_, _ = fmt.Fprintln(out, b.String())
+}
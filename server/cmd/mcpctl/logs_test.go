@@ -0,0 +1,61 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogsTailCmd(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "admin-key" {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/api/v1/logs/stream" {
+			http.NotFound(w, r)
+			return
+		}
+		q := r.URL.Query()
+		assert.Equal(t, "weather", q.Get("service"))
+		assert.Equal(t, "error", q.Get("level"))
+		assert.Equal(t, "10m0s", q.Get("since"))
+		assert.Equal(t, "false", q.Get("follow"))
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(": keep-alive\n\n" +
+			`data: {"id":"1","timestamp":"2026-10-16T12:00:00Z","level":"ERROR","message":"Tool execution failed","source":"weather.get_forecast","metadata":{"toolName":"weather.get_forecast","attempt":2}}` + "\n\n"))
+	}))
+	defer server.Close()
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		b := bytes.NewBufferString("")
+		cmd.SetOut(b)
+		cmd.SetErr(b)
+		cmd.SetArgs(append([]string{"logs", "tail", "--server", server.URL}, args...))
+		err := cmd.Execute()
+		return b.String(), err
+	}
+	filters := []string{"--service", "weather", "--level", "error", "--since", "10m", "--follow=false"}
+
+	out, err := run(append(filters, "--api-key", "admin-key")...)
+	require.NoError(t, err)
+	assert.Equal(t, `2026-10-16T12:00:00Z ERROR [weather.get_forecast] Tool execution failed attempt=2 toolName="weather.get_forecast"`+"\n", out)
+
+	out, err = run(append(filters, "--api-key", "admin-key", "--json")...)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"1","timestamp":"2026-10-16T12:00:00Z","level":"ERROR","message":"Tool execution failed","source":"weather.get_forecast","metadata":{"toolName":"weather.get_forecast","attempt":2}}`, out)
+
+	_, err = run(append(filters, "--api-key", "wrong")...)
+	assert.ErrorContains(t, err, "pass an admin --api-key")
+
+	_, err = run("--level", "loud")
+	assert.ErrorContains(t, err, `invalid log level "loud"`)
+}
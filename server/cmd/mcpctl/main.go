@@ -34,7 +34,7 @@ func main() {
 
 // newRootCmd creates the root Cobra command for the CLI.
 //
-// It configures the main entry point and registers all subcommands (validate, doctor, tool, version).
+// It configures the main entry point and registers all subcommands (validate, config, doctor, tool, import, apikey, seed, secret, version).
 //
 // Returns:
 //   - *cobra.Command: The configured root command.
@@ -83,9 +83,24 @@ func newRootCmd() *cobra.Command {
 	// Bind flags like --config, etc.
 	config.BindRootFlags(rootCmd)
 	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(newConfigCmd())
 	rootCmd.AddCommand(newDoctorCmd())
 	rootCmd.AddCommand(newToolCmd())
+	rootCmd.AddCommand(newServiceCmd())
+	rootCmd.AddCommand(newSessionCmd())
 	rootCmd.AddCommand(newImportCmd())
+	rootCmd.AddCommand(newExportCmd())
+	rootCmd.AddCommand(newAPIKeyCmd())
+	rootCmd.AddCommand(newSeedCmd())
+	rootCmd.AddCommand(newSecretCmd())
+	rootCmd.AddCommand(newAuditCmd())
+	rootCmd.AddCommand(newReplayCmd())
+	rootCmd.AddCommand(newStatsCmd())
+	rootCmd.AddCommand(newLogsCmd())
+	rootCmd.AddCommand(newDBCmd())
+	rootCmd.AddCommand(newWebhookCmd())
+	rootCmd.AddCommand(newSkillCmd())
+	rootCmd.AddCommand(newRegistryCmd())
 
 	versionCmd := &cobra.Command{
 		Use:   "version",
@@ -0,0 +1,409 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/marketplace"
+	"github.com/mcpany/core/server/pkg/skill"
+	"github.com/spf13/cobra"
+)
+
+// registryFlags are the flags selecting and verifying a registry.
+type registryFlags struct {
+	registry      string
+	trustedKeys   []string
+	allowUnsigned bool
+}
+
+func (f *registryFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.registry, "registry", envOr("MCPANY_REGISTRY", ""), "Registry: a Git repository (URL or path, with an optional #branch) or oci://<host>/<path>. Env: MCPANY_REGISTRY")
+	cmd.Flags().StringSliceVar(&f.trustedKeys, "trusted-key", splitEnvList("MCPANY_REGISTRY_TRUSTED_KEYS"), "PEM Ed25519 public key the bundles must be signed with; repeatable. Env: MCPANY_REGISTRY_TRUSTED_KEYS (comma separated)")
+	cmd.Flags().BoolVar(&f.allowUnsigned, "allow-unsigned", false, "Accept the bundles without a signature")
+}
+
+// open opens the registry and loads the trusted keys.
+func (f *registryFlags) open() (*marketplace.Client, error) {
+	if f.registry == "" {
+		return nil, errors.New("no registry given; pass --registry or set MCPANY_REGISTRY")
+	}
+	if len(f.trustedKeys) == 0 && !f.allowUnsigned {
+		return nil, errors.New("no trusted key given; pass --trusted-key, or --allow-unsigned to skip signature verification")
+	}
+	verifier := &marketplace.Verifier{AllowUnsigned: f.allowUnsigned}
+	for _, path := range f.trustedKeys {
+		key, err := marketplace.LoadPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+		verifier.Keys = append(verifier.Keys, key)
+	}
+	source, err := marketplace.Open(f.registry, marketplace.Options{
+		HTTPClient: &http.Client{Timeout: 60 * time.Second},
+		Username:   os.Getenv("MCPANY_REGISTRY_USERNAME"),
+		Password:   os.Getenv("MCPANY_REGISTRY_PASSWORD"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return marketplace.NewClient(source, verifier), nil
+}
+
+// newRegistryCmd creates the registry command group.
+//
+// Returns:
+//   - *cobra.Command: The configured registry command.
+func newRegistryCmd() *cobra.Command {
+	registryCmd := &cobra.Command{
+		Use:   "registry",
+		Short: "Install skills and upstream service definitions from a remote registry",
+		Long: `Install skills and upstream service definitions from a remote registry, by
+name and version. A registry is a Git repository or an OCI registry
+publishing signed bundles. Bundles are referenced as <kind>/<name>[@<version>],
+where kind is skill or service, e.g. skill/report-writer@1.2.0.`,
+	}
+	registryCmd.AddCommand(newRegistryVersionsCmd())
+	registryCmd.AddCommand(newRegistryInstallCmd())
+	registryCmd.AddCommand(newRegistryOutdatedCmd())
+	registryCmd.AddCommand(newRegistryPackageCmd())
+	registryCmd.AddCommand(newRegistrySignCmd())
+	return registryCmd
+}
+
+// newRegistryVersionsCmd creates the registry versions command.
+//
+// Returns:
+//   - *cobra.Command: The configured versions command.
+func newRegistryVersionsCmd() *cobra.Command {
+	var flags registryFlags
+	cmd := &cobra.Command{
+		Use:   "versions <kind>/<name>",
+		Short: "List the published versions of a bundle",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kind, name, _, err := marketplace.ParseRef(args[0])
+			if err != nil {
+				return err
+			}
+			// Listing versions verifies nothing.
+			flags.allowUnsigned = true
+			client, err := flags.open()
+			if err != nil {
+				return err
+			}
+			defer func() { _ = client.Close() }()
+			ctx, cancel := context.WithTimeout(cmd.Context(), 2*time.Minute)
+			defer cancel()
+			versions, err := client.Versions(ctx, kind, name)
+			if err != nil {
+				return err
+			}
+			for i := len(versions) - 1; i >= 0; i-- {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), versions[i])
+			}
+			return nil
+		},
+	}
+	flags.register(cmd)
+	return cmd
+}
+
+// newRegistryInstallCmd creates the registry install command.
+//
+// Returns:
+//   - *cobra.Command: The configured install command.
+func newRegistryInstallCmd() *cobra.Command {
+	var flags registryFlags
+	var serverURL, apiKey, catalogDir string
+	var force bool
+	cmd := &cobra.Command{
+		Use:   "install <kind>/<name>[@<version>]",
+		Short: "Install a bundle from the registry",
+		Long: `Install a bundle from the registry, at the given version or the latest
+release. The signature of the bundle is verified against the --trusted-key
+keys, and the checksums of its files against its manifest.
+
+Skills are installed on the running server, which serves them right away;
+this requires an admin API key. Services are installed in the service
+catalog directory (--catalog-dir) of the server.
+
+The bundle must be newer than the installed version unless --force is set.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kind, name, version, err := marketplace.ParseRef(args[0])
+			if err != nil {
+				return err
+			}
+			client, err := flags.open()
+			if err != nil {
+				return err
+			}
+			defer func() { _ = client.Close() }()
+			ctx, cancel := context.WithTimeout(cmd.Context(), 2*time.Minute)
+			defer cancel()
+			bundle, err := client.Fetch(ctx, kind, name, version)
+			if err != nil {
+				return err
+			}
+			signed := "unsigned"
+			if bundle.Signed {
+				signed = "signed"
+			}
+
+			switch kind {
+			case marketplace.KindSkill:
+				sum := sha256.Sum256(bundle.Package)
+				query := url.Values{"sha256": {hex.EncodeToString(sum[:])}}
+				if force {
+					query.Set("force", "true")
+				}
+				if _, err := callAdminAPIWithContentType(ctx, &http.Client{}, http.MethodPost, serverURL, "/api/v1/skill-packages?"+query.Encode(), apiKey, "application/gzip", bundle.Package); err != nil {
+					return fmt.Errorf("failed to install the skill: %w", err)
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Skill %s %s installed (%s).\n", name, bundle.Manifest.Version, signed)
+			case marketplace.KindService:
+				if installed, err := skill.ReadInstalledManifest(filepath.Join(catalogDir, name)); err == nil && !force && !marketplace.IsNewer(bundle.Manifest.Version, installed.Version) {
+					return fmt.Errorf("service %s %s is installed, the bundle is %s; pass --force to replace it", name, installed.Version, bundle.Manifest.Version)
+				}
+				if _, err := skill.Unpack(bundle.Package, catalogDir); err != nil {
+					return fmt.Errorf("failed to install the service: %w", err)
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Service %s %s installed in %s (%s).\n", name, bundle.Manifest.Version, filepath.Join(catalogDir, name), signed)
+			}
+			return nil
+		},
+	}
+	flags.register(cmd)
+	cmd.Flags().StringVar(&serverURL, "server", envOr("MCPANY_SERVER_URL", "http://localhost:50050"), "Base URL of the running server. Env: MCPANY_SERVER_URL")
+	cmd.Flags().StringVar(&apiKey, "api-key", envOr("MCPANY_API_KEY", ""), "API key of the server, sent in the X-API-Key header. Env: MCPANY_API_KEY")
+	cmd.Flags().StringVar(&catalogDir, "catalog-dir", "marketplace/catalog", "Service catalog directory of the server")
+	cmd.Flags().BoolVar(&force, "force", false, "Install the bundle even if it is not newer than the installed one")
+	return cmd
+}
+
+// newRegistryOutdatedCmd creates the registry outdated command.
+//
+// Returns:
+//   - *cobra.Command: The configured outdated command.
+func newRegistryOutdatedCmd() *cobra.Command {
+	var flags registryFlags
+	var serverURL, apiKey, catalogDir, kindFilter string
+	cmd := &cobra.Command{
+		Use:   "outdated",
+		Short: "List the installed bundles with a newer version in the registry",
+		Long: `List the installed bundles with a newer release in the registry: the skills
+of the running server, which requires an admin API key, and the services of
+the catalog directory. --kind checks one kind only. Bundles the registry does
+not publish are skipped.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if kindFilter != "" {
+				if _, err := marketplace.ParseKind(kindFilter); err != nil {
+					return err
+				}
+			}
+			// Versions are compared, no bundle is installed.
+			flags.allowUnsigned = true
+			client, err := flags.open()
+			if err != nil {
+				return err
+			}
+			defer func() { _ = client.Close() }()
+			ctx, cancel := context.WithTimeout(cmd.Context(), 2*time.Minute)
+			defer cancel()
+
+			type installedBundle struct {
+				kind          marketplace.Kind
+				name, version string
+			}
+			var installed []installedBundle
+			if kindFilter == "" || kindFilter == string(marketplace.KindSkill) {
+				body, err := callAdminAPI(ctx, &http.Client{}, http.MethodGet, serverURL, "/api/v1/skills", apiKey, nil)
+				if err != nil {
+					return fmt.Errorf("failed to list the skills: %w", err)
+				}
+				var skills []skill.Skill
+				if err := json.Unmarshal(body, &skills); err != nil {
+					return fmt.Errorf("failed to decode the skills: %w", err)
+				}
+				for _, s := range skills {
+					installed = append(installed, installedBundle{marketplace.KindSkill, s.Name, s.Version})
+				}
+			}
+			if kindFilter == "" || kindFilter == string(marketplace.KindService) {
+				entries, err := os.ReadDir(catalogDir)
+				if err != nil && !errors.Is(err, os.ErrNotExist) {
+					return fmt.Errorf("failed to list the catalog: %w", err)
+				}
+				for _, e := range entries {
+					// Only the services installed from a registry have a manifest.
+					if manifest, err := skill.ReadInstalledManifest(filepath.Join(catalogDir, e.Name())); err == nil {
+						installed = append(installed, installedBundle{marketplace.KindService, manifest.Name, manifest.Version})
+					}
+				}
+			}
+
+			var rows [][]string
+			for _, b := range installed {
+				latest, err := client.Latest(ctx, b.kind, b.name)
+				if errors.Is(err, marketplace.ErrNotFound) {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				if marketplace.IsNewer(latest, b.version) {
+					rows = append(rows, []string{string(b.kind), b.name, dashIfEmpty(b.version), latest})
+				}
+			}
+			printOutdated(cmd.OutOrStdout(), rows)
+			return nil
+		},
+	}
+	flags.register(cmd)
+	cmd.Flags().StringVar(&serverURL, "server", envOr("MCPANY_SERVER_URL", "http://localhost:50050"), "Base URL of the running server. Env: MCPANY_SERVER_URL")
+	cmd.Flags().StringVar(&apiKey, "api-key", envOr("MCPANY_API_KEY", ""), "API key of the server, sent in the X-API-Key header. Env: MCPANY_API_KEY")
+	cmd.Flags().StringVar(&catalogDir, "catalog-dir", "marketplace/catalog", "Service catalog directory of the server")
+	cmd.Flags().StringVar(&kindFilter, "kind", "", "Only check the bundles of this kind: skill or service")
+	return cmd
+}
+
+// newRegistryPackageCmd creates the registry package command.
+//
+// Returns:
+//   - *cobra.Command: The configured package command.
+func newRegistryPackageCmd() *cobra.Command {
+	var name, version, outputDir, signKey string
+	cmd := &cobra.Command{
+		Use:   "package <dir>",
+		Short: "Package a skill or service directory for a registry",
+		Long: `Package a directory as <name>-<version>.tar.gz for a registry. A directory
+with a SKILL.md is packaged as a skill, named and versioned by its SKILL.md.
+Any other directory, such as one holding the config.yaml of upstream
+services, is packaged as a service bundle named by --name (default: the
+directory name) at --version. --sign-key signs the package with a PEM
+Ed25519 private key, written next to it as <package>.sig.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var buf bytes.Buffer
+			var manifest *skill.Manifest
+			var err error
+			if _, statErr := os.Stat(filepath.Join(args[0], skill.SkillFileName)); statErr == nil {
+				if name != "" || version != "" {
+					return fmt.Errorf("the name and version of a skill are set in its %s", skill.SkillFileName)
+				}
+				manifest, err = skill.Package(args[0], &buf)
+			} else {
+				if name == "" {
+					abs, err := filepath.Abs(args[0])
+					if err != nil {
+						return err
+					}
+					name = filepath.Base(abs)
+				}
+				manifest, err = skill.PackageDir(args[0], name, version, &buf)
+			}
+			if err != nil {
+				return err
+			}
+			dest := filepath.Join(outputDir, manifest.FileName())
+			if err := os.WriteFile(dest, buf.Bytes(), 0o644); err != nil { //nolint:gosec // Packages are not secret.
+				return fmt.Errorf("failed to write the package: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Packaged %s %s (%d files) to %s\n", manifest.Name, manifest.Version, len(manifest.Files), dest)
+			if signKey != "" {
+				return signPackage(cmd.OutOrStdout(), dest, buf.Bytes(), signKey)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&name, "name", "", "Name of a service bundle (default: the directory name)")
+	cmd.Flags().StringVar(&version, "version", "", "Semantic version of a service bundle")
+	cmd.Flags().StringVarP(&outputDir, "output-dir", "o", ".", "Directory the package is written to")
+	cmd.Flags().StringVar(&signKey, "sign-key", "", "PEM Ed25519 private key to sign the package with")
+	return cmd
+}
+
+// newRegistrySignCmd creates the registry sign command.
+//
+// Returns:
+//   - *cobra.Command: The configured sign command.
+func newRegistrySignCmd() *cobra.Command {
+	var key string
+	cmd := &cobra.Command{
+		Use:   "sign <package.tar.gz>",
+		Short: "Sign a package for a registry",
+		Long: `Sign a package with a PEM Ed25519 private key, such as one created with
+"openssl genpkey -algorithm ed25519". The base64 signature is written to
+<package>.sig, to publish next to the package in a Git registry, or as the
+` + marketplace.SignatureAnnotation + ` annotation of an OCI artifact.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if key == "" {
+				return errors.New("no signing key given; pass --key")
+			}
+			pkg, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read the package: %w", err)
+			}
+			return signPackage(cmd.OutOrStdout(), args[0], pkg, key)
+		},
+	}
+	cmd.Flags().StringVar(&key, "key", "", "PEM Ed25519 private key")
+	return cmd
+}
+
+func signPackage(out io.Writer, path string, pkg []byte, keyPath string) error {
+	key, err := marketplace.LoadPrivateKey(keyPath)
+	if err != nil {
+		return err
+	}
+	sigPath := path + marketplace.SignatureFileSuffix
+	if err := os.WriteFile(sigPath, marketplace.Sign(key, pkg), 0o644); err != nil { //nolint:gosec // Signatures are public.
+		return fmt.Errorf("failed to write the signature: %w", err)
+	}
+	pub, _ := key.Public().(ed25519.PublicKey)
+	_, _ = fmt.Fprintf(out, "Signed %s with key %s to %s\n", path, hex.EncodeToString(pub[:8]), sigPath)
+	return nil
+}
+
+func printOutdated(out io.Writer, rows [][]string) {
+	if len(rows) == 0 {
+		_, _ = fmt.Fprintln(out, "All bundles are up to date.")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "KIND\tNAME\tINSTALLED\tLATEST")
+	for _, r := range rows {
+		_, _ = fmt.Fprintln(w, strings.Join(r, "\t"))
+	}
+	_ = w.Flush()
+}
+
+// splitEnvList splits a comma separated environment variable.
+func splitEnvList(key string) []string {
+	var list []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/mcpany/core/server/pkg/skill"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeRegistryKeys writes a PEM Ed25519 key pair.
+func writeRegistryKeys(t *testing.T) (pubPath, privPath string) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	dir := t.TempDir()
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	pubPath = filepath.Join(dir, "registry.pub")
+	privPath = filepath.Join(dir, "registry.key")
+	require.NoError(t, os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0600))
+	require.NoError(t, os.WriteFile(privPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}), 0600))
+	return pubPath, privPath
+}
+
+func TestRegistryCmds(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	pubKey, privKey := writeRegistryKeys(t)
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		b := bytes.NewBufferString("")
+		cmd.SetOut(b)
+		cmd.SetErr(b)
+		cmd.SetArgs(append([]string{"registry"}, args...))
+		err := cmd.Execute()
+		return b.String(), err
+	}
+
+	// Publish two versions of a service bundle and a skill in a Git registry.
+	repo := t.TempDir()
+	serviceDir := filepath.Join(t.TempDir(), "weather")
+	require.NoError(t, os.MkdirAll(serviceDir, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "services", "weather"), 0755))
+	for _, version := range []string{"1.0.0", "1.1.0"} {
+		require.NoError(t, os.WriteFile(filepath.Join(serviceDir, "config.yaml"), []byte("# "+version+"\nupstream_services: []\n"), 0644))
+		out, err := run("package", serviceDir, "--version", version, "--sign-key", privKey, "-o", filepath.Join(repo, "services", "weather"))
+		require.NoError(t, err)
+		assert.Contains(t, out, "Packaged weather "+version+" (1 files)")
+		assert.Contains(t, out, "weather-"+version+".tar.gz.sig")
+	}
+	skillDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, skill.SkillFileName), []byte("---\nname: report-writer\nversion: 2.0.0\n---\n\nWrite."), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "skills", "report-writer"), 0755))
+	_, err := run("package", skillDir, "-o", filepath.Join(repo, "skills", "report-writer"))
+	require.NoError(t, err)
+	_, err = run("sign", filepath.Join(repo, "skills", "report-writer", "report-writer-2.0.0.tar.gz"), "--key", privKey)
+	require.NoError(t, err)
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "publish"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	var uploaded []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "admin-key" {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/skills":
+			_, _ = w.Write([]byte(`[{"name": "report-writer", "version": "1.0.0"}, {"name": "local-only"}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/skill-packages":
+			assert.NotEmpty(t, r.URL.Query().Get("sha256"))
+			buf := new(bytes.Buffer)
+			_, _ = buf.ReadFrom(r.Body)
+			uploaded = buf.Bytes()
+			_ = json.NewEncoder(w).Encode(skill.Skill{Frontmatter: skill.Frontmatter{Name: "report-writer", Version: "2.0.0"}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	catalog := t.TempDir()
+	common := []string{"--registry", repo, "--trusted-key", pubKey, "--server", server.URL, "--api-key", "admin-key", "--catalog-dir", catalog}
+
+	out, err := run("versions", "service/weather", "--registry", repo)
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0\n1.0.0\n", out)
+
+	_, err = run("install", "service/weather@1.0.0", "--registry", repo)
+	assert.ErrorContains(t, err, "no trusted key given")
+	_, otherKey := writeRegistryKeys(t)
+	otherPub := otherKey[:len(otherKey)-len("registry.key")] + "registry.pub"
+	_, err = run("install", "service/weather@1.0.0", "--registry", repo, "--trusted-key", otherPub, "--catalog-dir", catalog)
+	assert.ErrorContains(t, err, "does not match any trusted key")
+
+	out, err = run(append([]string{"install", "service/weather@1.0.0"}, common...)...)
+	require.NoError(t, err)
+	assert.Equal(t, "Service weather 1.0.0 installed in "+filepath.Join(catalog, "weather")+" (signed).\n", out)
+
+	out, err = run(append([]string{"outdated"}, common...)...)
+	require.NoError(t, err)
+	assert.Regexp(t, `skill\s+report-writer\s+1.0.0\s+2.0.0`, out)
+	assert.Regexp(t, `service\s+weather\s+1.0.0\s+1.1.0`, out)
+	assert.NotContains(t, out, "local-only", "skills the registry does not publish are skipped")
+
+	out, err = run(append([]string{"install", "service/weather"}, common...)...)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Service weather 1.1.0 installed")
+	config, err := os.ReadFile(filepath.Join(catalog, "weather", "config.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "# 1.1.0\nupstream_services: []\n", string(config))
+	_, err = run(append([]string{"install", "service/weather@1.0.0"}, common...)...)
+	assert.ErrorContains(t, err, "pass --force to replace it")
+
+	out, err = run(append([]string{"install", "skill/report-writer"}, common...)...)
+	require.NoError(t, err)
+	assert.Equal(t, "Skill report-writer 2.0.0 installed (signed).\n", out)
+	packaged, err := os.ReadFile(filepath.Join(repo, "skills", "report-writer", "report-writer-2.0.0.tar.gz"))
+	require.NoError(t, err)
+	assert.Equal(t, packaged, uploaded)
+
+	out, err = run(append([]string{"outdated", "--kind", "service"}, common...)...)
+	require.NoError(t, err)
+	assert.Equal(t, "All bundles are up to date.\n", out)
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/capture"
+	"github.com/spf13/cobra"
+)
+
+// redactedMarker is the replacement of values redacted by the DLP settings.
+const redactedMarker = "***REDACTED***"
+
+// newReplayCmd creates the replay command.
+//
+// Returns:
+//   - *cobra.Command: The configured replay command.
+func newReplayCmd() *cobra.Command {
+	var (
+		dir       string
+		serverURL string
+		apiKey    string
+		limit     int
+		timeout   time.Duration
+	)
+	replayCmd := &cobra.Command{
+		Use:   "replay [capture-id]",
+		Short: "Re-execute a captured tool call against the running server",
+		Long: `Re-execute a captured tool call against the running server.
+
+With global_settings.capture enabled, the server records every tool call,
+with its arguments and result redacted by the DLP settings, in the capture
+directory. The ID of a capture is logged with the call. Replaying sends the
+captured arguments to the server, which executes the call with its current
+configuration, and compares the result with the captured one.
+
+Without a capture ID, the most recent captures are listed.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := capture.NewStore(dir, 0)
+			if err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				captures, err := store.List(limit)
+				if err != nil {
+					return err
+				}
+				printCaptures(cmd.OutOrStdout(), captures)
+				return nil
+			}
+
+			c, err := store.Load(args[0])
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+			return replayCapture(ctx, &http.Client{}, cmd.OutOrStdout(), serverURL, apiKey, c)
+		},
+	}
+	replayCmd.Flags().StringVar(&dir, "dir", envOr("MCPANY_CAPTURE_DIR", capture.DefaultDir), "Capture directory of the server. Env: MCPANY_CAPTURE_DIR")
+	replayCmd.Flags().StringVar(&serverURL, "server", envOr("MCPANY_SERVER_URL", "http://localhost:50050"), "Base URL of the running server. Env: MCPANY_SERVER_URL")
+	replayCmd.Flags().StringVar(&apiKey, "api-key", envOr("MCPANY_API_KEY", ""), "API key of the server, sent in the X-API-Key header. Env: MCPANY_API_KEY")
+	replayCmd.Flags().IntVar(&limit, "limit", 20, "Number of captures to list")
+	replayCmd.Flags().DurationVar(&timeout, "timeout", time.Minute, "Timeout of the replayed call")
+	return replayCmd
+}
+
+func printCaptures(out io.Writer, captures []*capture.Capture) {
+	if len(captures) == 0 {
+		_, _ = fmt.Fprintln(out, "No captures found.")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tTIME\tTOOL\tDURATION\tERROR")
+	for _, c := range captures {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%dms\t%s\n", c.ID, c.Timestamp.Format(time.RFC3339), c.ToolName, c.DurationMs, dashIfEmpty(c.Error))
+	}
+	_ = w.Flush()
+}
+
+// replayCapture executes a captured call on the server and prints how its
+// result compares with the captured one.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the call.
+//   - client: *http.Client. The HTTP client.
+//   - out: io.Writer. The output.
+//   - serverURL: string. The base URL of the server.
+//   - apiKey: string. The API key of the server, or empty.
+//   - c: *capture.Capture. The capture.
+//
+// Returns:
+//   - error: An error if the call cannot be made.
+func replayCapture(ctx context.Context, client *http.Client, out io.Writer, serverURL, apiKey string, c *capture.Capture) error {
+	_, _ = fmt.Fprintf(out, "Replaying %s (captured %s)\n", c.ToolName, c.Timestamp.Format(time.RFC3339))
+	if bytes.Contains(c.Arguments, []byte(redactedMarker)) {
+		_, _ = fmt.Fprintln(out, "Warning: the captured arguments contain redacted values, which are sent as captured.")
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"name":       c.ToolName,
+		"toolInputs": c.Arguments,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode the call: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(serverURL, "/")+"/api/v1/execute", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach the server: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read the response: %w", err)
+	}
+	duration := time.Since(start)
+
+	if c.Error != "" {
+		_, _ = fmt.Fprintf(out, "Captured error:  %s\n", c.Error)
+	} else {
+		_, _ = fmt.Fprintf(out, "Captured result: %s\n", c.Result)
+	}
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		_, _ = fmt.Fprintf(out, "Replay result:   %s\n", bytes.TrimSpace(respBody))
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("the server rejected the call (%s); pass --api-key", resp.Status)
+	default:
+		_, _ = fmt.Fprintf(out, "Replay error:    %s (%s)\n", strings.TrimSpace(string(respBody)), resp.Status)
+	}
+	_, _ = fmt.Fprintf(out, "Duration: %dms (captured %dms)\n", duration.Milliseconds(), c.DurationMs)
+
+	switch {
+	case resp.StatusCode != http.StatusOK && c.Error != "":
+		_, _ = fmt.Fprintln(out, "Outcome: the call still fails.")
+	case resp.StatusCode != http.StatusOK:
+		_, _ = fmt.Fprintln(out, "Outcome: the call now fails.")
+	case c.Error != "":
+		_, _ = fmt.Fprintln(out, "Outcome: the call now succeeds.")
+	case jsonEqual(c.Result, respBody):
+		_, _ = fmt.Fprintln(out, "Outcome: the result matches the capture.")
+	default:
+		_, _ = fmt.Fprintln(out, "Outcome: the result differs from the capture.")
+	}
+	return nil
+}
+
+// jsonEqual reports whether two JSON documents hold the same value.
+func jsonEqual(a, b []byte) bool {
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/capture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayCmd(t *testing.T) {
+	dir := t.TempDir()
+	store, err := capture.NewStore(dir, 0)
+	require.NoError(t, err)
+	failed := &capture.Capture{
+		Timestamp: time.Now(),
+		ToolName:  "weather.get",
+		Arguments: json.RawMessage(`{"city":"Paris"}`),
+		Error:     "upstream returned 502",
+	}
+	require.NoError(t, store.Save(failed))
+	ok := &capture.Capture{
+		Timestamp: time.Now(),
+		ToolName:  "weather.get",
+		Arguments: json.RawMessage(`{"city":"Oslo","token":"***REDACTED***"}`),
+		Result:    json.RawMessage(`{"temp":3}`),
+	}
+	require.NoError(t, store.Save(ok))
+
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/execute", r.URL.Path)
+		assert.Equal(t, "test-key", r.Header.Get("X-API-Key"))
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &received))
+		_, _ = w.Write([]byte(`{"temp":3}`))
+	}))
+	defer server.Close()
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		b := bytes.NewBufferString("")
+		cmd.SetOut(b)
+		cmd.SetErr(b)
+		cmd.SetArgs(append([]string{"replay", "--dir", dir, "--server", server.URL, "--api-key", "test-key"}, args...))
+		err := cmd.Execute()
+		return b.String(), err
+	}
+
+	out, err := run()
+	require.NoError(t, err)
+	assert.Contains(t, out, ok.ID)
+	assert.Contains(t, out, "upstream returned 502")
+
+	out, err = run(failed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "weather.get", received["name"])
+	assert.Equal(t, map[string]any{"city": "Paris"}, received["toolInputs"])
+	assert.Contains(t, out, "Captured error:  upstream returned 502\n")
+	assert.Contains(t, out, "Outcome: the call now succeeds.\n")
+
+	out, err = run(ok.ID)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Warning: the captured arguments contain redacted values")
+	assert.Contains(t, out, "Outcome: the result matches the capture.\n")
+
+	_, err = run("0190b6f2-0000-7000-8000-000000000000")
+	assert.ErrorContains(t, err, "capture not found")
+}
@@ -0,0 +1,206 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mcpany/core/server/pkg/config"
+	"github.com/mcpany/core/server/pkg/secretusage"
+	"github.com/mcpany/core/server/pkg/sops"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// newSecretCmd creates the secret command group.
+//
+// The usage command inspects the secrets stored in the server's SQLite
+// database; encrypt and decrypt handle SOPS encrypted configuration files.
+//
+// Returns:
+//   - *cobra.Command: The configured secret command.
+func newSecretCmd() *cobra.Command {
+	var dbPath string
+	secretCmd := &cobra.Command{
+		Use:   "secret",
+		Short: "Inspect stored secrets and encrypt configuration files",
+	}
+	secretCmd.PersistentFlags().StringVar(&dbPath, "db-path", envOr("MCPANY_DB_PATH", "data/mcpany.db"), "Path to the server's SQLite database file. Env: MCPANY_DB_PATH")
+
+	usageCmd := &cobra.Command{
+		Use:   "usage <name>",
+		Short: "Show where a secret is referenced and when it was last used",
+		Long: `Show where a secret is referenced and when it was last used.
+
+References are the secret values of the upstream services, stored in the
+database or loaded from --config-path, that name the secret's key as their
+environment variable. Reads are recorded by a running server sharing the database.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			osFs := afero.NewOsFs()
+			cfg := config.GlobalSettings()
+			if err := cfg.Load(cmd, osFs); err != nil {
+				return fmt.Errorf("configuration load failed: %w", err)
+			}
+
+			store, closeDB, err := openStore(dbPath)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = closeDB() }()
+
+			services, err := store.ListServices(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to list services: %w", err)
+			}
+			if paths := cfg.ConfigPaths(); len(paths) > 0 {
+				serverConfig, err := config.NewFileStore(osFs, paths).Load(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to load configuration: %w", err)
+				}
+				services = append(services, serverConfig.GetUpstreamServices()...)
+			}
+
+			report, err := secretusage.Lookup(ctx, store, args[0], services)
+			if err != nil {
+				return fmt.Errorf("failed to read secret: %w", err)
+			}
+			if report == nil {
+				return fmt.Errorf("secret %q not found", args[0])
+			}
+			printSecretUsage(cmd, report)
+			return nil
+		},
+	}
+
+	secretCmd.AddCommand(usageCmd, newSecretEncryptCmd(), newSecretDecryptCmd())
+	return secretCmd
+}
+
+func newSecretEncryptCmd() *cobra.Command {
+	var opts sops.EncryptOptions
+	var output string
+	var inPlace bool
+	cmd := &cobra.Command{
+		Use:   "encrypt <file>",
+		Short: "Encrypt a YAML or JSON configuration file with SOPS",
+		Long: `Encrypt a YAML or JSON configuration file with SOPS.
+
+Every value is encrypted, except those under a key ending with "_unencrypted"
+or, with --encrypted-regex, those under no matching key. The server decrypts
+the file in memory when it loads it. The result can also be edited with the
+sops CLI.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(opts.AgeRecipients) == 0 {
+				if recipients := os.Getenv("SOPS_AGE_RECIPIENTS"); recipients != "" {
+					opts.AgeRecipients = strings.Split(recipients, ",")
+				}
+			}
+			return transformFile(cmd, args[0], output, inPlace, func(data []byte, format sops.Format) ([]byte, error) {
+				return sops.Encrypt(data, format, opts)
+			})
+		},
+	}
+	cmd.Flags().StringSliceVar(&opts.AgeRecipients, "age", nil, "age recipients (age1...) to encrypt to. Env: SOPS_AGE_RECIPIENTS")
+	cmd.Flags().StringSliceVar(&opts.KMSARNs, "kms", nil, "AWS KMS key ARNs to encrypt with")
+	cmd.Flags().StringSliceVar(&opts.GCPKMSResourceIDs, "gcp-kms", nil, "Google Cloud KMS key resource IDs to encrypt with")
+	cmd.Flags().StringVar(&opts.EncryptedRegex, "encrypted-regex", "", "Only encrypt the values under keys matching this regex")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write the encrypted file here instead of stdout")
+	cmd.Flags().BoolVarP(&inPlace, "in-place", "i", false, "Replace the file with its encrypted version")
+	return cmd
+}
+
+func newSecretDecryptCmd() *cobra.Command {
+	var output string
+	var inPlace bool
+	cmd := &cobra.Command{
+		Use:   "decrypt <file>",
+		Short: "Decrypt a SOPS encrypted configuration file",
+		Long: `Decrypt a SOPS encrypted configuration file.
+
+The data key is decrypted with the same keys as the sops CLI: the age
+identities of SOPS_AGE_KEY, SOPS_AGE_KEY_FILE or the sops key file, or AWS
+KMS, Google Cloud KMS, Azure Key Vault, HashiCorp Vault or PGP with the
+credentials of the host.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return transformFile(cmd, args[0], output, inPlace, func(data []byte, format sops.Format) ([]byte, error) {
+				return sops.Decrypt(data, format)
+			})
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write the decrypted file here instead of stdout")
+	cmd.Flags().BoolVarP(&inPlace, "in-place", "i", false, "Replace the file with its decrypted version")
+	return cmd
+}
+
+// transformFile applies fn to the file at path and writes the result to
+// output, back to path, or to stdout.
+func transformFile(cmd *cobra.Command, path, output string, inPlace bool, fn func([]byte, sops.Format) ([]byte, error)) error {
+	if inPlace && output != "" {
+		return fmt.Errorf("--in-place and --output are mutually exclusive")
+	}
+	format, err := sops.FormatForPath(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // The path is given by the user.
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	result, err := fn(data, format)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if inPlace {
+		output = path
+	}
+	if output == "" {
+		_, err = cmd.OutOrStdout().Write(result)
+		return err
+	}
+	if err := os.WriteFile(output, result, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	return nil
+}
+
+func printSecretUsage(cmd *cobra.Command, report *secretusage.Report) {
+	out := cmd.OutOrStdout()
+	secret := report.Secret
+	_, _ = fmt.Fprintf(out, "Secret:        %s (%s)\n", secret.GetName(), secret.GetId())
+	_, _ = fmt.Fprintf(out, "Key:           %s\n", secret.GetKey())
+	_, _ = fmt.Fprintf(out, "Allowed roles: %s\n", dashIfEmpty(strings.Join(secret.GetAllowedRoles(), ",")))
+	_, _ = fmt.Fprintf(out, "Last used:     %s\n", dashIfEmpty(secret.GetLastUsed()))
+
+	_, _ = fmt.Fprintln(out, "\nReferences:")
+	if len(report.References) == 0 {
+		_, _ = fmt.Fprintln(out, "  none")
+	} else {
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "  SERVICE\tFIELD")
+		for _, ref := range report.References {
+			_, _ = fmt.Fprintf(w, "  %s\t%s\n", ref.Service, ref.Field)
+		}
+		_ = w.Flush()
+	}
+
+	_, _ = fmt.Fprintln(out, "\nUsage:")
+	if len(secret.GetUsage()) == 0 {
+		_, _ = fmt.Fprintln(out, "  never read")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "  CONSUMER\tCOUNT\tLAST USED")
+	for _, u := range secret.GetUsage() {
+		_, _ = fmt.Fprintf(w, "  %s\t%d\t%s\n", u.GetConsumer(), u.GetCount(), u.GetLastUsed())
+	}
+	_ = w.Flush()
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSecretUsageCmd(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "mcpany.db")
+
+	store, closeDB, err := openStore(dbPath)
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, store.SaveSecret(ctx, configv1.Secret_builder{
+		Id:           proto.String("gh"),
+		Name:         proto.String("github-token"),
+		Key:          proto.String("GITHUB_TOKEN"),
+		Value:        proto.String("s3cr3t"),
+		AllowedRoles: []string{"ops"},
+		LastUsed:     proto.String("2026-01-02T03:04:05Z"),
+		Usage: []*configv1.SecretUsage{configv1.SecretUsage_builder{
+			Consumer: proto.String("service:github"),
+			LastUsed: proto.String("2026-01-02T03:04:05Z"),
+			Count:    proto.Int64(7),
+		}.Build()},
+	}.Build()))
+	require.NoError(t, closeDB())
+
+	configPath := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+upstream_services:
+  - name: github
+    http_service:
+      address: https://api.github.com
+    upstream_auth:
+      bearer_token:
+        token:
+          environment_variable: GITHUB_TOKEN
+`), 0o600))
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		b := bytes.NewBufferString("")
+		cmd.SetOut(b)
+		cmd.SetErr(b)
+		cmd.SetArgs(append(append([]string{"secret"}, args...), "--db-path", dbPath))
+		err := cmd.Execute()
+		return b.String(), err
+	}
+
+	out, err := run("usage", "github-token", "--config-path", configPath)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Key:           GITHUB_TOKEN")
+	assert.Contains(t, out, "Allowed roles: ops")
+	assert.Contains(t, out, "Last used:     2026-01-02T03:04:05Z")
+	assert.Regexp(t, `github\s+upstream_auth\.bearer_token\.token`, out)
+	assert.Regexp(t, `service:github\s+7\s+2026-01-02T03:04:05Z`, out)
+	assert.NotContains(t, out, "s3cr3t")
+
+	out, err = run("usage", "gh")
+	require.NoError(t, err)
+	assert.Contains(t, out, "References:\n  none")
+
+	_, err = run("usage", "missing")
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestSecretEncryptDecryptCmd(t *testing.T) {
+	// sops reads SOPS_AGE_KEY_FILE whenever it is set, even to "".
+	t.Setenv("SOPS_AGE_KEY_FILE", "")
+	require.NoError(t, os.Unsetenv("SOPS_AGE_KEY_FILE"))
+	t.Setenv("SOPS_AGE_KEY", "AGE-SECRET-KEY-1GFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPQ4EGAEX")
+	t.Setenv("SOPS_AGE_RECIPIENTS", "age1zvkyg2lqzraa2lnjvqej32nkuu0ues2s82hzrye869xeexvn73equnujwj")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "secrets.yaml")
+	plain := "upstream_services:\n    - name: github\n      upstream_auth:\n        bearer_token:\n            token:\n                plain_text: s3cr3t\n"
+	require.NoError(t, os.WriteFile(path, []byte(plain), 0o600))
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		b := bytes.NewBufferString("")
+		cmd.SetOut(b)
+		cmd.SetErr(b)
+		cmd.SetArgs(append([]string{"secret"}, args...))
+		err := cmd.Execute()
+		return b.String(), err
+	}
+
+	_, err := run("encrypt", path, "--in-place")
+	require.NoError(t, err)
+	encrypted, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encrypted), "s3cr3t")
+	assert.Contains(t, string(encrypted), "ENC[AES256_GCM,")
+
+	out, err := run("decrypt", path)
+	require.NoError(t, err)
+	assert.Equal(t, plain, out)
+
+	decryptedPath := filepath.Join(dir, "decrypted.yaml")
+	_, err = run("decrypt", path, "-o", decryptedPath)
+	require.NoError(t, err)
+	decrypted, err := os.ReadFile(decryptedPath)
+	require.NoError(t, err)
+	assert.Equal(t, plain, string(decrypted))
+
+	_, err = run("encrypt", path, "-i", "-o", decryptedPath)
+	assert.ErrorContains(t, err, "mutually exclusive")
+
+	_, err = run("encrypt", path)
+	assert.ErrorContains(t, err, "already encrypted")
+}
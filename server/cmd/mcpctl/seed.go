@@ -0,0 +1,96 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mcpany/core/server/pkg/audit"
+	"github.com/mcpany/core/server/pkg/fixtures"
+	"github.com/mcpany/core/server/pkg/skill"
+	"github.com/spf13/cobra"
+)
+
+// newSeedCmd creates the seed command.
+//
+// It applies declarative fixtures (services, users, audit entries, skills, ...)
+// directly to the server's SQLite database. Records are upserted by name or ID,
+// so a scenario can be re-applied safely.
+//
+// Returns:
+//   - *cobra.Command: The configured seed command.
+func newSeedCmd() *cobra.Command {
+	var (
+		files     []string
+		scenario  string
+		dbPath    string
+		auditPath string
+		skillsDir string
+	)
+	seedCmd := &cobra.Command{
+		Use:   "seed -f <file or directory>",
+		Short: "Apply seed data fixtures to the server's database",
+		Long: `Apply seed data fixtures to the server's database.
+
+A fixture is a YAML or JSON file with upstream_services, credentials, secrets,
+profiles, users, service_templates, audit_entries and skills. A directory is
+read recursively in lexical order. Fixtures listing "scenarios" are only applied
+for those scenarios; fixtures without are applied for every scenario.
+
+Applying the same fixtures again leaves the database unchanged.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			var all []*fixtures.Fixture
+			for _, f := range files {
+				loaded, err := fixtures.Load(f, scenario)
+				if err != nil {
+					return fmt.Errorf("failed to load fixtures: %w", err)
+				}
+				all = append(all, loaded...)
+			}
+			if len(all) == 0 {
+				return fmt.Errorf("no fixtures found for scenario %q", scenario)
+			}
+
+			store, closeDB, err := openStore(dbPath)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = closeDB() }()
+			target := fixtures.Target{Storage: store}
+
+			if auditPath != "" {
+				auditStore, err := audit.NewSQLiteAuditStore(auditPath)
+				if err != nil {
+					return fmt.Errorf("failed to open audit database: %w", err)
+				}
+				defer func() { _ = auditStore.Close() }()
+				target.Audit = auditStore
+			}
+			if skillsDir != "" {
+				if target.Skills, err = skill.NewManager(skillsDir); err != nil {
+					return err
+				}
+			}
+
+			summary, err := fixtures.Apply(context.Background(), target, all)
+			if err != nil {
+				return fmt.Errorf("failed to apply fixtures: %w", err)
+			}
+			for _, f := range all {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Applied %s\n", f.Source)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Seeded scenario %q: %s\n", scenario, summary)
+			return nil
+		},
+	}
+	seedCmd.Flags().StringSliceVarP(&files, "file", "f", nil, "Fixture file or directory. Can be specified multiple times")
+	seedCmd.Flags().StringVar(&scenario, "scenario", "demo", "Scenario to apply (e.g. demo, load-test, docs)")
+	seedCmd.Flags().StringVar(&dbPath, "db-path", envOr("MCPANY_DB_PATH", "data/mcpany.db"), "Path to the server's SQLite database file. Env: MCPANY_DB_PATH")
+	seedCmd.Flags().StringVar(&auditPath, "audit-db", "", "Path to the SQLite audit log (audit.output_path). Audit entries are skipped if unset")
+	seedCmd.Flags().StringVar(&skillsDir, "skills-dir", "skills", "Directory of the server's skills. Skills are skipped if empty")
+	_ = seedCmd.MarkFlagRequired("file")
+	return seedCmd
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeedCmd(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "mcpany.db")
+	skillsDir := filepath.Join(dir, "skills")
+	fixtureDir := filepath.Join(dir, "fixtures")
+	require.NoError(t, os.MkdirAll(fixtureDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(fixtureDir, "demo.yaml"), []byte(`
+scenarios: ["demo"]
+upstream_services:
+  - name: "weather"
+    http_service:
+      address: "https://wttr.in"
+users:
+  - id: "alice"
+    roles: ["admin"]
+audit_entries:
+  - id: "a1"
+    tool_name: "weather.get_weather"
+skills:
+  - name: "weather-report"
+    description: "Summarize the weather."
+`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(fixtureDir, "load.yaml"), []byte(`
+scenarios: ["load-test"]
+upstream_services:
+  - name: "load-01"
+    http_service:
+      address: "http://localhost:9090"
+`), 0o600))
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		b := bytes.NewBufferString("")
+		cmd.SetOut(b)
+		cmd.SetErr(b)
+		cmd.SetArgs(append(append([]string{"seed"}, args...), "--db-path", dbPath, "--skills-dir", skillsDir))
+		err := cmd.Execute()
+		return b.String(), err
+	}
+
+	out, err := run("-f", fixtureDir)
+	require.NoError(t, err)
+	assert.Contains(t, out, "demo.yaml")
+	assert.NotContains(t, out, "load.yaml")
+	assert.Contains(t, out, `Seeded scenario "demo": services: 1, credentials: 0, secrets: 0, profiles: 0, users: 1`)
+	assert.Contains(t, out, "skills: 1; skipped: audit entries")
+	assert.FileExists(t, filepath.Join(skillsDir, "weather-report", "SKILL.md"))
+
+	// Re-applying is safe.
+	_, err = run("-f", fixtureDir)
+	require.NoError(t, err)
+
+	out, err = run("-f", fixtureDir, "--scenario", "load-test")
+	require.NoError(t, err)
+	assert.Contains(t, out, "load.yaml")
+	assert.Contains(t, out, "services: 1")
+
+	_, err = run("-f", fixtureDir, "--scenario", "docs")
+	assert.ErrorContains(t, err, `no fixtures found for scenario "docs"`)
+
+	_, err = run()
+	assert.Error(t, err, "--file is required")
+}
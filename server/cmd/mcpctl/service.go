@@ -0,0 +1,92 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	pb "github.com/mcpany/core/proto/admin/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// newServiceCmd creates the service command group.
+//
+// Returns:
+//   - *cobra.Command: The configured service command.
+func newServiceCmd() *cobra.Command {
+	serviceCmd := &cobra.Command{
+		Use:   "service",
+		Short: "Manage the upstream services of the running server",
+	}
+	serviceCmd.AddCommand(newServiceEnabledCmd(false))
+	serviceCmd.AddCommand(newServiceEnabledCmd(true))
+	return serviceCmd
+}
+
+// newServiceEnabledCmd creates the service enable command, or the service
+// disable command if enable is false.
+//
+// Parameters:
+//   - enable: bool. Whether to create the enable command.
+//
+// Returns:
+//   - *cobra.Command: The configured command.
+func newServiceEnabledCmd(enable bool) *cobra.Command {
+	var serverURL, apiKey string
+	cmd := &cobra.Command{
+		Use:   "disable <id>",
+		Short: "Take an upstream service out of the tool catalog",
+		Long: `Take an upstream service, by ID or name, out of the tool catalog of the
+running server, as if its configuration set disable: true, without editing
+or redeploying the configuration files.
+
+The service is added to global_settings.disabled_services in the server's
+database, so it stays disabled across restarts and wins over the profiles
+enabling it, until "mcpctl service enable". Requires an admin API key.`,
+		Args: cobra.ExactArgs(1),
+	}
+	action := "disable"
+	if enable {
+		action = "enable"
+		cmd.Use = "enable <id>"
+		cmd.Short = "Put an upstream service disabled with mcpctl back in the tool catalog"
+		cmd.Long = `Put an upstream service disabled with "mcpctl service disable" back in the
+tool catalog of the running server. A service turned off by its
+configuration, with disable: true or a profile, stays inactive. Requires an
+admin API key.`
+	}
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(cmd.Context(), time.Minute)
+		defer cancel()
+		path := "/v1/admin/services/" + url.PathEscape(args[0]) + "/" + action
+		body, err := callAdminAPI(ctx, &http.Client{}, http.MethodPost, serverURL, path, apiKey, nil)
+		if err != nil {
+			return err
+		}
+		// Both responses carry the service in the same field.
+		var resp pb.EnableServiceResponse
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, &resp); err != nil {
+			return fmt.Errorf("failed to decode the response: %w", err)
+		}
+		service := resp.GetService()
+		out := cmd.OutOrStdout()
+		switch {
+		case service.GetDisabled():
+			_, _ = fmt.Fprintf(out, "Service %s disabled; its tools, prompts and resources are out of the catalog.\n", service.GetServiceId())
+		case service.GetActive():
+			_, _ = fmt.Fprintf(out, "Service %s enabled.\n", service.GetServiceId())
+		default:
+			_, _ = fmt.Fprintf(out, "Service %s enabled, but still inactive: check its disable flag and profiles, and the server logs.\n", service.GetServiceId())
+		}
+		return nil
+	}
+	cmd.Flags().StringVar(&serverURL, "server", envOr("MCPANY_SERVER_URL", "http://localhost:50050"), "Base URL of the running server. Env: MCPANY_SERVER_URL")
+	cmd.Flags().StringVar(&apiKey, "api-key", envOr("MCPANY_API_KEY", ""), "API key of the server, sent in the X-API-Key header. Env: MCPANY_API_KEY")
+	return cmd
+}
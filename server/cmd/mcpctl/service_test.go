@@ -0,0 +1,69 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceEnabledCmd(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "admin-key" {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/admin/services/weather/disable":
+			_, _ = w.Write([]byte(`{"service":{"serviceId":"weather","disabled":true}}`))
+		case "/v1/admin/services/weather/enable":
+			_, _ = w.Write([]byte(`{"service":{"serviceId":"weather","active":true}}`))
+		case "/v1/admin/services/github/enable":
+			_, _ = w.Write([]byte(`{"service":{"serviceId":"github"}}`))
+		default:
+			http.Error(w, "service not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		b := new(bytes.Buffer)
+		cmd.SetOut(b)
+		cmd.SetErr(b)
+		cmd.SetArgs(append(append([]string{"service"}, args...), "--server", server.URL))
+		err := cmd.Execute()
+		return b.String(), err
+	}
+
+	out, err := run("disable", "weather", "--api-key", "admin-key")
+	require.NoError(t, err)
+	assert.Equal(t, "Service weather disabled; its tools, prompts and resources are out of the catalog.\n", out)
+
+	out, err = run("enable", "weather", "--api-key", "admin-key")
+	require.NoError(t, err)
+	assert.Equal(t, "Service weather enabled.\n", out)
+
+	out, err = run("enable", "github", "--api-key", "admin-key")
+	require.NoError(t, err)
+	assert.Contains(t, out, "still inactive")
+
+	_, err = run("disable", "missing", "--api-key", "admin-key")
+	assert.ErrorContains(t, err, "404 Not Found: service not found")
+
+	_, err = run("disable", "weather", "--api-key", "wrong")
+	assert.ErrorContains(t, err, "pass an admin --api-key")
+
+	_, err = run("disable")
+	assert.ErrorContains(t, err, "accepts 1 arg")
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/mcpany/core/proto/admin/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// newSessionCmd creates the session command group.
+//
+// Returns:
+//   - *cobra.Command: The configured session command.
+func newSessionCmd() *cobra.Command {
+	sessionCmd := &cobra.Command{
+		Use:   "session",
+		Short: "Manage the MCP sessions of the clients of the running server",
+	}
+	sessionCmd.AddCommand(newSessionListCmd())
+	sessionCmd.AddCommand(newSessionKillCmd())
+	return sessionCmd
+}
+
+// newSessionListCmd creates the session list command.
+//
+// Returns:
+//   - *cobra.Command: The configured list command.
+func newSessionListCmd() *cobra.Command {
+	var serverURL, apiKey, user, output string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the MCP sessions of the connected clients",
+		Long: `List the MCP sessions of the clients connected to the running server, oldest
+first, with their client, transport, authenticated user, start time and
+tool calls. --user keeps the sessions of one user. The wide output adds the
+profile, address, last activity and request count of each session.
+Requires an admin API key.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if output != "table" && output != "wide" && output != "json" {
+				return fmt.Errorf("invalid output format %q: must be one of table, wide, json", output)
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+			defer cancel()
+			body, err := callAdminAPI(ctx, &http.Client{}, http.MethodGet, serverURL, "/v1/admin/sessions", apiKey, nil)
+			if err != nil {
+				return err
+			}
+			var resp pb.ListSessionsResponse
+			if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, &resp); err != nil {
+				return fmt.Errorf("failed to decode the sessions: %w", err)
+			}
+			var sessions []*pb.Session
+			for _, s := range resp.GetSessions() {
+				if user == "" || s.GetUserId() == user {
+					sessions = append(sessions, s)
+				}
+			}
+			if output == "json" {
+				return printSessionsJSON(cmd.OutOrStdout(), sessions)
+			}
+			printSessions(cmd.OutOrStdout(), sessions, output == "wide")
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&serverURL, "server", envOr("MCPANY_SERVER_URL", "http://localhost:50050"), "Base URL of the running server. Env: MCPANY_SERVER_URL")
+	cmd.Flags().StringVar(&apiKey, "api-key", envOr("MCPANY_API_KEY", ""), "API key of the server, sent in the X-API-Key header. Env: MCPANY_API_KEY")
+	cmd.Flags().StringVar(&user, "user", "", "Only list the sessions of the user with this ID")
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table, wide or json")
+	return cmd
+}
+
+// newSessionKillCmd creates the session kill command.
+//
+// Returns:
+//   - *cobra.Command: The configured kill command.
+func newSessionKillCmd() *cobra.Command {
+	var serverURL, apiKey string
+	cmd := &cobra.Command{
+		Use:   "kill <session-id>...",
+		Short: "Disconnect MCP sessions of the running server",
+		Long: `Disconnect MCP sessions of the running server, e.g. of stuck or abusive
+clients. A client may start a new session unless its credentials are
+revoked too. Requires an admin API key.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+			defer cancel()
+			for _, id := range args {
+				if _, err := callAdminAPI(ctx, &http.Client{}, http.MethodDelete, serverURL, "/v1/admin/sessions/"+url.PathEscape(id), apiKey, nil); err != nil {
+					return fmt.Errorf("failed to kill session %s: %w", id, err)
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Session %s disconnected.\n", id)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&serverURL, "server", envOr("MCPANY_SERVER_URL", "http://localhost:50050"), "Base URL of the running server. Env: MCPANY_SERVER_URL")
+	cmd.Flags().StringVar(&apiKey, "api-key", envOr("MCPANY_API_KEY", ""), "API key of the server, sent in the X-API-Key header. Env: MCPANY_API_KEY")
+	return cmd
+}
+
+func printSessionsJSON(out io.Writer, sessions []*pb.Session) error {
+	list := make([]json.RawMessage, 0, len(sessions))
+	for _, s := range sessions {
+		raw, err := protojson.Marshal(s)
+		if err != nil {
+			return fmt.Errorf("failed to encode session %q: %w", s.GetId(), err)
+		}
+		list = append(list, raw)
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(list)
+}
+
+func printSessions(out io.Writer, sessions []*pb.Session, wide bool) {
+	if len(sessions) == 0 {
+		_, _ = fmt.Fprintln(out, "No sessions found.")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if wide {
+		_, _ = fmt.Fprintln(w, "ID\tCLIENT\tTRANSPORT\tUSER\tPROFILE\tREMOTE\tSTARTED\tLAST ACTIVE\tREQUESTS\tTOOL CALLS\tERRORS")
+	} else {
+		_, _ = fmt.Fprintln(w, "ID\tCLIENT\tTRANSPORT\tUSER\tSTARTED\tTOOL CALLS\tERRORS")
+	}
+	for _, s := range sessions {
+		client := s.GetClientName()
+		if client != "" && s.GetClientVersion() != "" {
+			client += "/" + s.GetClientVersion()
+		}
+		if wide {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\t%d\t%d\n", dashIfEmpty(s.GetId()), dashIfEmpty(client),
+				dashIfEmpty(s.GetTransport()), dashIfEmpty(s.GetUserId()), dashIfEmpty(s.GetProfileId()), dashIfEmpty(s.GetRemoteAddr()),
+				dashIfEmpty(s.GetStartedAt()), dashIfEmpty(s.GetLastActiveAt()), s.GetRequestCount(), s.GetToolCallCount(), s.GetErrorCount())
+		} else {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%d\n", dashIfEmpty(s.GetId()), dashIfEmpty(client),
+				dashIfEmpty(s.GetTransport()), dashIfEmpty(s.GetUserId()), dashIfEmpty(s.GetStartedAt()),
+				s.GetToolCallCount(), s.GetErrorCount())
+		}
+	}
+	_ = w.Flush()
+}
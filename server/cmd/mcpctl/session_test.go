@@ -0,0 +1,92 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionCmd(t *testing.T) {
+	var killed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "admin-key" {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/admin/sessions":
+			_, _ = w.Write([]byte(`{"sessions": [
+				{"id": "s1", "clientName": "claude", "clientVersion": "1.2.3", "transport": "http", "userId": "alice",
+					"profileId": "dev", "remoteAddr": "10.0.0.7", "startedAt": "2026-10-16T09:00:00Z",
+					"lastActiveAt": "2026-10-16T09:05:00Z", "requestCount": "40", "toolCallCount": "31", "errorCount": "2"},
+				{"id": "s2", "transport": "websocket", "userId": "bob", "startedAt": "2026-10-16T09:10:00Z"}
+			]}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/v1/admin/sessions/s1":
+			killed = append(killed, "s1")
+			_, _ = w.Write([]byte(`{}`))
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code": 5, "message": "session not found"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		b := new(bytes.Buffer)
+		cmd.SetOut(b)
+		cmd.SetErr(b)
+		cmd.SetArgs(append(append([]string{"session"}, args...), "--server", server.URL))
+		err := cmd.Execute()
+		return b.String(), err
+	}
+
+	out, err := run("list", "--api-key", "admin-key")
+	require.NoError(t, err)
+	assert.Regexp(t, `ID\s+CLIENT\s+TRANSPORT\s+USER\s+STARTED\s+TOOL CALLS\s+ERRORS`, out)
+	assert.Regexp(t, `s1\s+claude/1.2.3\s+http\s+alice\s+2026-10-16T09:00:00Z\s+31\s+2\n`, out)
+	assert.Regexp(t, `s2\s+-\s+websocket\s+bob\s+2026-10-16T09:10:00Z\s+0\s+0\n`, out)
+
+	out, err = run("list", "--api-key", "admin-key", "-o", "wide", "--user", "alice")
+	require.NoError(t, err)
+	assert.Regexp(t, `s1\s+claude/1.2.3\s+http\s+alice\s+dev\s+10.0.0.7\s+2026-10-16T09:00:00Z\s+2026-10-16T09:05:00Z\s+40\s+31\s+2\n`, out)
+	assert.NotContains(t, out, "bob")
+
+	out, err = run("list", "--api-key", "admin-key", "-o", "json", "--user", "bob")
+	require.NoError(t, err)
+	var sessions []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &sessions))
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "s2", sessions[0]["id"])
+
+	out, err = run("list", "--api-key", "admin-key", "--user", "carol")
+	require.NoError(t, err)
+	assert.Equal(t, "No sessions found.\n", out)
+
+	out, err = run("kill", "s1", "--api-key", "admin-key")
+	require.NoError(t, err)
+	assert.Equal(t, "Session s1 disconnected.\n", out)
+	assert.Equal(t, []string{"s1"}, killed)
+
+	_, err = run("kill", "missing", "--api-key", "admin-key")
+	assert.ErrorContains(t, err, "failed to kill session missing: the server returned 404 Not Found")
+
+	_, err = run("list", "--api-key", "wrong")
+	assert.ErrorContains(t, err, "pass an admin --api-key")
+
+	_, err = run("list", "-o", "yaml")
+	assert.ErrorContains(t, err, "invalid output format")
+
+	_, err = run("kill")
+	assert.ErrorContains(t, err, "requires at least 1 arg")
+}
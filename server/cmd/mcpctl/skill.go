@@ -0,0 +1,173 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/skill"
+	"github.com/spf13/cobra"
+)
+
+// newSkillCmd creates the skill command group.
+//
+// Returns:
+//   - *cobra.Command: The configured skill command.
+func newSkillCmd() *cobra.Command {
+	skillCmd := &cobra.Command{
+		Use:   "skill",
+		Short: "Package, install and remove the agent skills of the server",
+	}
+	skillCmd.AddCommand(newSkillPackageCmd())
+	skillCmd.AddCommand(newSkillAddCmd())
+	skillCmd.AddCommand(newSkillRemoveCmd())
+	return skillCmd
+}
+
+// newSkillPackageCmd creates the skill package command.
+//
+// Returns:
+//   - *cobra.Command: The configured package command.
+func newSkillPackageCmd() *cobra.Command {
+	var outputDir string
+	cmd := &cobra.Command{
+		Use:   "package <skill-dir>",
+		Short: "Package a skill directory as a versioned tar.gz",
+		Long: `Package a skill directory as <name>-<version>.tar.gz. The SKILL.md of the
+skill must set a name and a semantic version. The package holds a
+manifest.json with the SHA-256 checksum of every file, checked when the
+package is installed. Dot files and directories are left out. The checksum
+of the package itself is printed, to pass to "mcpctl skill add --sha256".`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var buf bytes.Buffer
+			manifest, err := skill.Package(args[0], &buf)
+			if err != nil {
+				return err
+			}
+			dest := filepath.Join(outputDir, manifest.FileName())
+			if err := os.WriteFile(dest, buf.Bytes(), 0o644); err != nil { //nolint:gosec // Packages are not secret.
+				return fmt.Errorf("failed to write the package: %w", err)
+			}
+			sum := sha256.Sum256(buf.Bytes())
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Packaged %s %s (%d files) to %s\nsha256: %s\n",
+				manifest.Name, manifest.Version, len(manifest.Files), dest, hex.EncodeToString(sum[:]))
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&outputDir, "output-dir", "o", ".", "Directory the package is written to")
+	return cmd
+}
+
+// newSkillAddCmd creates the skill add command.
+//
+// Returns:
+//   - *cobra.Command: The configured add command.
+func newSkillAddCmd() *cobra.Command {
+	var serverURL, apiKey, checksum string
+	var force bool
+	cmd := &cobra.Command{
+		Use:   "add <package.tar.gz|skill-dir>",
+		Short: "Install a skill package on the running server",
+		Long: `Install a skill package written by "mcpctl skill package" on the running
+server, which serves it to the MCP clients right away. A skill directory is
+packaged on the fly. The package must be newer than the installed version of
+the skill; --force installs it anyway, e.g. to roll back. --sha256 checks
+the checksum of the package before installing it. Requires an admin API key.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := readSkillPackage(args[0])
+			if err != nil {
+				return err
+			}
+			query := url.Values{}
+			if checksum != "" {
+				query.Set("sha256", checksum)
+			}
+			if force {
+				query.Set("force", "true")
+			}
+			path := "/api/v1/skill-packages"
+			if len(query) > 0 {
+				path += "?" + query.Encode()
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 60*time.Second)
+			defer cancel()
+			body, err := callAdminAPIWithContentType(ctx, &http.Client{}, http.MethodPost, serverURL, path, apiKey, "application/gzip", data)
+			if err != nil {
+				return fmt.Errorf("failed to install the skill: %w", err)
+			}
+			var installed skill.Skill
+			if err := json.Unmarshal(body, &installed); err != nil {
+				return fmt.Errorf("failed to decode the installed skill: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Skill %s %s installed.\n", installed.Name, installed.Version)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&serverURL, "server", envOr("MCPANY_SERVER_URL", "http://localhost:50050"), "Base URL of the running server. Env: MCPANY_SERVER_URL")
+	cmd.Flags().StringVar(&apiKey, "api-key", envOr("MCPANY_API_KEY", ""), "API key of the server, sent in the X-API-Key header. Env: MCPANY_API_KEY")
+	cmd.Flags().StringVar(&checksum, "sha256", "", "Expected hex SHA-256 checksum of the package")
+	cmd.Flags().BoolVar(&force, "force", false, "Install the package even if it is not newer than the installed skill")
+	return cmd
+}
+
+// newSkillRemoveCmd creates the skill remove command.
+//
+// Returns:
+//   - *cobra.Command: The configured remove command.
+func newSkillRemoveCmd() *cobra.Command {
+	var serverURL, apiKey string
+	cmd := &cobra.Command{
+		Use:   "remove <name>...",
+		Short: "Remove skills from the running server",
+		Long: `Remove skills from the running server, with their assets. The skills stop
+being served to the MCP clients right away. Requires an admin API key.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+			defer cancel()
+			for _, name := range args {
+				if _, err := callAdminAPI(ctx, &http.Client{}, http.MethodDelete, serverURL, "/api/v1/skills/"+url.PathEscape(name), apiKey, nil); err != nil {
+					return fmt.Errorf("failed to remove skill %s: %w", name, err)
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Skill %s removed.\n", name)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&serverURL, "server", envOr("MCPANY_SERVER_URL", "http://localhost:50050"), "Base URL of the running server. Env: MCPANY_SERVER_URL")
+	cmd.Flags().StringVar(&apiKey, "api-key", envOr("MCPANY_API_KEY", ""), "API key of the server, sent in the X-API-Key header. Env: MCPANY_API_KEY")
+	return cmd
+}
+
+// readSkillPackage reads a skill package, or packages a skill directory.
+func readSkillPackage(p string) ([]byte, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the package: %w", err)
+	}
+	if info.IsDir() {
+		var buf bytes.Buffer
+		if _, err := skill.Package(p, &buf); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	data, err := os.ReadFile(p) //nolint:gosec // The package is chosen by the user.
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the package: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mcpany/core/server/pkg/skill"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkillCmds(t *testing.T) {
+	manager, err := skill.NewManager(t.TempDir())
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "admin-key" {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/skill-packages":
+			assert.Equal(t, "application/gzip", r.Header.Get("Content-Type"))
+			sk, err := manager.InstallPackage(r.Body, skill.InstallOptions{SHA256: r.URL.Query().Get("sha256"), Force: r.URL.Query().Get("force") == "true"})
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(sk)
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/skills/report-writer":
+			require.NoError(t, manager.DeleteSkill("report-writer"))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		b := bytes.NewBufferString("")
+		cmd.SetOut(b)
+		cmd.SetErr(b)
+		cmd.SetArgs(append([]string{"skill"}, args...))
+		err := cmd.Execute()
+		return b.String(), err
+	}
+	writeSkill := func(version string) string {
+		dir := t.TempDir()
+		content := "---\nname: report-writer\ndescription: Writes reports\nversion: " + version + "\n---\n\nWrite the report."
+		require.NoError(t, os.WriteFile(filepath.Join(dir, skill.SkillFileName), []byte(content), 0644))
+		return dir
+	}
+	remote := []string{"--server", server.URL, "--api-key", "admin-key"}
+
+	outDir := t.TempDir()
+	out, err := run("package", writeSkill("1.0.0"), "-o", outDir)
+	require.NoError(t, err)
+	pkg := filepath.Join(outDir, "report-writer-1.0.0.tar.gz")
+	assert.Contains(t, out, "Packaged report-writer 1.0.0 (1 files) to "+pkg)
+	assert.Regexp(t, `sha256: [0-9a-f]{64}\n$`, out)
+
+	_, err = run(append([]string{"add", pkg, "--sha256", "00"}, remote...)...)
+	assert.ErrorContains(t, err, "package checksum mismatch")
+
+	out, err = run(append([]string{"add", pkg}, remote...)...)
+	require.NoError(t, err)
+	assert.Equal(t, "Skill report-writer 1.0.0 installed.\n", out)
+
+	_, err = run(append([]string{"add", writeSkill("0.1.0")}, remote...)...)
+	assert.ErrorContains(t, err, "not newer")
+	out, err = run(append([]string{"add", writeSkill("0.1.0"), "--force"}, remote...)...)
+	require.NoError(t, err)
+	assert.Equal(t, "Skill report-writer 0.1.0 installed.\n", out)
+
+	out, err = run(append([]string{"remove", "report-writer"}, remote...)...)
+	require.NoError(t, err)
+	assert.Equal(t, "Skill report-writer removed.\n", out)
+	skills, err := manager.ListSkills()
+	require.NoError(t, err)
+	assert.Empty(t, skills)
+
+	_, err = run("remove", "report-writer", "--server", server.URL)
+	assert.ErrorContains(t, err, "pass an admin --api-key")
+}
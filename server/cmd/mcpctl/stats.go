@@ -0,0 +1,145 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/slowcall"
+	"github.com/spf13/cobra"
+)
+
+// newStatsCmd creates the stats command group.
+//
+// Returns:
+//   - *cobra.Command: The configured stats command.
+func newStatsCmd() *cobra.Command {
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Inspect the runtime statistics of the running server",
+	}
+
+	var (
+		serverURL string
+		apiKey    string
+		limit     int
+		asJSON    bool
+	)
+	slowCmd := &cobra.Command{
+		Use:   "slow",
+		Short: "Show the slowest recent tool calls",
+		Long: `Show the slowest recent tool calls.
+
+With global_settings.slow_calls configured, the server logs every tool call
+slower than the threshold of its tool and keeps the slowest ones over a
+rolling window. Each call is broken down into the time spent waiting for an
+upstream connection (queue), in the pre- and post-call hooks (webhook), on
+the upstream service (upstream) and transforming the arguments and the
+result (transform). Requires an admin API key.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+			defer cancel()
+			report, err := fetchSlowCalls(ctx, &http.Client{}, serverURL, apiKey, limit)
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(report)
+			}
+			printSlowCalls(cmd.OutOrStdout(), report)
+			return nil
+		},
+	}
+	slowCmd.Flags().StringVar(&serverURL, "server", envOr("MCPANY_SERVER_URL", "http://localhost:50050"), "Base URL of the running server. Env: MCPANY_SERVER_URL")
+	slowCmd.Flags().StringVar(&apiKey, "api-key", envOr("MCPANY_API_KEY", ""), "API key of the server, sent in the X-API-Key header. Env: MCPANY_API_KEY")
+	slowCmd.Flags().IntVar(&limit, "limit", 0, "Number of calls to show. Defaults to the top_n of the server")
+	slowCmd.Flags().BoolVar(&asJSON, "json", false, "Print the report as JSON")
+	statsCmd.AddCommand(slowCmd)
+
+	return statsCmd
+}
+
+// fetchSlowCalls gets the slow call report of the server.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the request.
+//   - client: *http.Client. The HTTP client.
+//   - serverURL: string. The base URL of the server.
+//   - apiKey: string. The API key of the server, or empty.
+//   - limit: int. The number of calls, or 0 for the default of the server.
+//
+// Returns:
+//   - *slowcall.Report: The report.
+//   - error: An error if the report cannot be fetched.
+func fetchSlowCalls(ctx context.Context, client *http.Client, serverURL, apiKey string, limit int) (*slowcall.Report, error) {
+	endpoint := strings.TrimSuffix(serverURL, "/") + "/api/v1/stats/slow-calls"
+	if limit > 0 {
+		endpoint += "?" + url.Values{"limit": {strconv.Itoa(limit)}}.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach the server: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("the server rejected the request (%s); pass an admin --api-key", resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("the server returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var report slowcall.Report
+	if err := json.Unmarshal(body, &report); err != nil {
+		return nil, fmt.Errorf("failed to decode the report: %w", err)
+	}
+	return &report, nil
+}
+
+func printSlowCalls(out io.Writer, report *slowcall.Report) {
+	if len(report.Calls) == 0 {
+		_, _ = fmt.Fprintln(out, "No slow calls recorded.")
+		return
+	}
+	_, _ = fmt.Fprintf(out, "Slowest calls of the last %s:\n\n", report.Window)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TIME\tTOOL\tDURATION\tTHRESHOLD\tQUEUE\tWEBHOOK\tUPSTREAM\tTRANSFORM\tTRACE\tERROR")
+	for _, c := range report.Calls {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%dms\t%dms\t%dms\t%dms\t%dms\t%dms\t%s\t%s\n",
+			c.Timestamp.Format(time.RFC3339), c.Tool, c.DurationMs, c.ThresholdMs,
+			c.QueueMs, c.WebhookMs, c.UpstreamMs, c.TransformMs, dashIfEmpty(c.TraceID), dashIfEmpty(c.Error))
+	}
+	_ = w.Flush()
+
+	_, _ = fmt.Fprintln(out)
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TOOL\tSLOW CALLS\tMAX\tAVG")
+	for _, s := range report.Tools {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%dms\t%dms\n", s.Tool, s.Count, s.MaxMs, s.AvgMs)
+	}
+	_ = w.Flush()
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsSlowCmd(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "admin-key" {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		assert.Equal(t, "/api/v1/stats/slow-calls", r.URL.Path)
+		assert.Equal(t, "5", r.URL.Query().Get("limit"))
+		_, _ = w.Write([]byte(`{
+			"window": "1h0m0s",
+			"calls": [{"timestamp": "2026-01-02T03:04:05Z", "tool": "weather.get_forecast", "traceId": "abc123",
+				"durationMs": 7200, "thresholdMs": 2000, "queueMs": 10, "webhookMs": 150, "upstreamMs": 6900, "transformMs": 40}],
+			"tools": [{"tool": "weather.get_forecast", "count": 3, "maxMs": 7200, "avgMs": 4100}]
+		}`))
+	}))
+	defer server.Close()
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		b := bytes.NewBufferString("")
+		cmd.SetOut(b)
+		cmd.SetErr(b)
+		cmd.SetArgs(append([]string{"stats", "slow", "--server", server.URL, "--limit", "5"}, args...))
+		err := cmd.Execute()
+		return b.String(), err
+	}
+
+	out, err := run("--api-key", "admin-key")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Slowest calls of the last 1h0m0s")
+	assert.Regexp(t, `weather\.get_forecast\s+7200ms\s+2000ms\s+10ms\s+150ms\s+6900ms\s+40ms\s+abc123\s+-`, out)
+	assert.Regexp(t, `weather\.get_forecast\s+3\s+7200ms\s+4100ms`, out)
+
+	out, err = run("--api-key", "admin-key", "--json")
+	require.NoError(t, err)
+	assert.Contains(t, out, `"upstreamMs": 6900`)
+
+	_, err = run("--api-key", "wrong")
+	assert.ErrorContains(t, err, "pass an admin --api-key")
+}
@@ -17,7 +17,8 @@ import (
 // newToolCmd creates the tool command group.
 //
 // This command provides subcommands for managing and inspecting tools,
-// such as calculating integrity hashes for tool definitions.
+// such as listing and describing the tools of the running server and
+// calculating integrity hashes for tool definitions.
 //
 // Returns:
 //   - *cobra.Command: The configured tool command.
@@ -111,5 +112,7 @@ func newToolCmd() *cobra.Command {
 	}
 
 	toolCmd.AddCommand(hashCmd)
+	toolCmd.AddCommand(newToolListCmd())
+	toolCmd.AddCommand(newToolDescribeCmd())
 	return toolCmd
 }
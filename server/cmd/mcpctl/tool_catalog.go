@@ -0,0 +1,409 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/mcpany/core/proto/admin/v1"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	mcprouterv1 "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// toolOutputFormats are the values of the --output flag of the tool list and
+// describe commands.
+var toolOutputFormats = []string{"table", "wide", "json"}
+
+// toolCallStats are the calls and errors of a tool recorded by the metrics of
+// the server since it started.
+type toolCallStats struct {
+	Calls     float64 `json:"calls"`
+	Errors    float64 `json:"errors"`
+	ErrorRate float64 `json:"errorRate"`
+}
+
+// toolAuth describes what a caller of a tool needs and how the server
+// authenticates to the upstream service of the tool.
+type toolAuth struct {
+	// Client is the authentication method required from clients of the
+	// service, or empty if the service does not require one of its own.
+	Client string `json:"client,omitempty"`
+	// Upstream is the authentication method the server uses with the
+	// upstream service, or empty.
+	Upstream string `json:"upstream,omitempty"`
+	// Profiles are the profiles the tool is exposed to, or empty for all.
+	Profiles []string `json:"profiles,omitempty"`
+}
+
+// toolDescription is the JSON output of the tool describe command.
+type toolDescription struct {
+	Tool  json.RawMessage `json:"tool"`
+	Auth  toolAuth        `json:"auth"`
+	Stats *toolCallStats  `json:"stats,omitempty"`
+}
+
+// newToolListCmd creates the tool list command, which lists the tools of the
+// running server.
+//
+// Returns:
+//   - *cobra.Command: The configured list command.
+func newToolListCmd() *cobra.Command {
+	var (
+		serverURL string
+		apiKey    string
+		service   string
+		tags      []string
+		output    string
+	)
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the tools of the running server",
+		Long: `List the tools of the running server.
+
+--service keeps the tools of one upstream service and --tag the tools with
+the tag; with several --tag flags, a tool needs all of them. The wide output
+adds the tags, profiles and annotations of each tool, and the JSON output
+prints the full tool definitions, including their schemas. Requires an admin
+API key.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if !slices.Contains(toolOutputFormats, output) {
+				return fmt.Errorf("invalid output format %q: must be one of %s", output, strings.Join(toolOutputFormats, ", "))
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+			defer cancel()
+			tools, err := fetchTools(ctx, &http.Client{}, serverURL, apiKey)
+			if err != nil {
+				return err
+			}
+			tools = filterTools(tools, service, tags)
+			if output == "json" {
+				return printToolsJSON(cmd.OutOrStdout(), tools)
+			}
+			printTools(cmd.OutOrStdout(), tools, output == "wide")
+			return nil
+		},
+	}
+	listCmd.Flags().StringVar(&serverURL, "server", envOr("MCPANY_SERVER_URL", "http://localhost:50050"), "Base URL of the running server. Env: MCPANY_SERVER_URL")
+	listCmd.Flags().StringVar(&apiKey, "api-key", envOr("MCPANY_API_KEY", ""), "API key of the server, sent in the X-API-Key header. Env: MCPANY_API_KEY")
+	listCmd.Flags().StringVar(&service, "service", "", "Only list the tools of the service with this ID")
+	listCmd.Flags().StringArrayVar(&tags, "tag", nil, "Only list the tools with this tag. Can be repeated")
+	listCmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table, wide or json")
+	return listCmd
+}
+
+// newToolDescribeCmd creates the tool describe command, which shows the
+// details of a tool of the running server.
+//
+// Returns:
+//   - *cobra.Command: The configured describe command.
+func newToolDescribeCmd() *cobra.Command {
+	var (
+		serverURL string
+		apiKey    string
+		output    string
+	)
+	describeCmd := &cobra.Command{
+		Use:   "describe <tool-name>",
+		Short: "Show the schemas, service, auth and error rate of a tool",
+		Long: `Show the details of a tool of the running server: its input and output
+schemas, the upstream service providing it, the authentication required
+from clients of the service and used with the upstream, the profiles the
+tool is exposed to, and its calls and error rate since the server started,
+read from the /metrics endpoint. Requires an admin API key.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != "table" && output != "json" {
+				return fmt.Errorf("invalid output format %q: must be table or json", output)
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+			defer cancel()
+			client := &http.Client{}
+			tools, err := fetchTools(ctx, client, serverURL, apiKey)
+			if err != nil {
+				return err
+			}
+			idx := slices.IndexFunc(tools, func(t *mcprouterv1.Tool) bool { return t.GetName() == args[0] })
+			if idx < 0 {
+				return fmt.Errorf("tool %q not found", args[0])
+			}
+			t := tools[idx]
+
+			auth := toolAuth{Profiles: t.GetProfiles()}
+			// The service is only informational; a tool without a registered
+			// service config (e.g. a built-in tool) is still described.
+			if id := t.GetServiceId(); id != "" {
+				if svc, err := fetchService(ctx, client, serverURL, apiKey, id); err == nil {
+					auth.Client = authMethod(svc.GetAuthentication())
+					auth.Upstream = authMethod(svc.GetUpstreamAuth())
+				}
+			}
+			stats, statsErr := fetchToolCallStats(ctx, client, serverURL, apiKey, t.GetName())
+
+			if output == "json" {
+				raw, err := protojson.Marshal(t)
+				if err != nil {
+					return fmt.Errorf("failed to encode the tool: %w", err)
+				}
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(toolDescription{Tool: raw, Auth: auth, Stats: stats})
+			}
+			printToolDescription(cmd.OutOrStdout(), t, auth, stats, statsErr)
+			return nil
+		},
+	}
+	describeCmd.Flags().StringVar(&serverURL, "server", envOr("MCPANY_SERVER_URL", "http://localhost:50050"), "Base URL of the running server. Env: MCPANY_SERVER_URL")
+	describeCmd.Flags().StringVar(&apiKey, "api-key", envOr("MCPANY_API_KEY", ""), "API key of the server, sent in the X-API-Key header. Env: MCPANY_API_KEY")
+	describeCmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table or json")
+	return describeCmd
+}
+
+// fetchTools gets the tools of the server through the admin API, sorted by
+// name.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the request.
+//   - client: *http.Client. The HTTP client.
+//   - serverURL: string. The base URL of the server.
+//   - apiKey: string. The API key of the server, or empty.
+//
+// Returns:
+//   - []*mcprouterv1.Tool: The tools.
+//   - error: An error if the tools cannot be fetched.
+func fetchTools(ctx context.Context, client *http.Client, serverURL, apiKey string) ([]*mcprouterv1.Tool, error) {
+	body, err := callAdminAPI(ctx, client, http.MethodGet, serverURL, "/v1/admin/tools", apiKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp pb.ListToolsResponse
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode the tools: %w", err)
+	}
+	tools := resp.GetTools()
+	sort.Slice(tools, func(i, j int) bool { return tools[i].GetName() < tools[j].GetName() })
+	return tools, nil
+}
+
+func fetchService(ctx context.Context, client *http.Client, serverURL, apiKey, serviceID string) (*configv1.UpstreamServiceConfig, error) {
+	body, err := callAdminAPI(ctx, client, http.MethodGet, serverURL, "/v1/admin/services/"+url.PathEscape(serviceID), apiKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp pb.GetServiceResponse
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode the service: %w", err)
+	}
+	return resp.GetService(), nil
+}
+
+// fetchToolCallStats reads the calls and errors of a tool from the
+// mcpany_tools_call_total metric of the server. The series of the metric
+// carry a status label, and label enrichers may split them further, so all
+// the series of the tool are summed.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the request.
+//   - client: *http.Client. The HTTP client.
+//   - serverURL: string. The base URL of the server.
+//   - apiKey: string. The API key of the server, or empty.
+//   - toolName: string. The name of the tool.
+//
+// Returns:
+//   - *toolCallStats: The stats; zero calls if the tool was not called.
+//   - error: An error if the metrics cannot be fetched or parsed.
+func fetchToolCallStats(ctx context.Context, client *http.Client, serverURL, apiKey, toolName string) (*toolCallStats, error) {
+	body, err := callAdminAPI(ctx, client, http.MethodGet, serverURL, "/metrics", apiKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	parser := expfmt.NewTextParser(model.UTF8Validation)
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the metrics: %w", err)
+	}
+	stats := &toolCallStats{}
+	for _, m := range families["mcpany_tools_call_total"].GetMetric() {
+		labels := map[string]string{}
+		for _, l := range m.GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+		// Skip the series without a status; they count the same calls.
+		if labels["tool"] != toolName || labels["status"] == "" {
+			continue
+		}
+		stats.Calls += m.GetCounter().GetValue()
+		if labels["status"] == "error" {
+			stats.Errors += m.GetCounter().GetValue()
+		}
+	}
+	if stats.Calls > 0 {
+		stats.ErrorRate = stats.Errors / stats.Calls
+	}
+	return stats, nil
+}
+
+// filterTools keeps the tools of a service that have all the tags.
+func filterTools(tools []*mcprouterv1.Tool, service string, tags []string) []*mcprouterv1.Tool {
+	var kept []*mcprouterv1.Tool
+	for _, t := range tools {
+		if service != "" && t.GetServiceId() != service {
+			continue
+		}
+		if !slices.ContainsFunc(tags, func(tag string) bool { return !slices.Contains(t.GetTags(), tag) }) {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// authMethod names the method of an authentication config, or returns
+// empty if none is set.
+func authMethod(auth *configv1.Authentication) string {
+	switch auth.WhichAuthMethod() {
+	case configv1.Authentication_ApiKey_case:
+		return "api key"
+	case configv1.Authentication_BearerToken_case:
+		return "bearer token"
+	case configv1.Authentication_BasicAuth_case:
+		return "basic auth"
+	case configv1.Authentication_Oauth2_case:
+		return "oauth2"
+	case configv1.Authentication_Oidc_case:
+		return "oidc"
+	case configv1.Authentication_Mtls_case:
+		return "mtls"
+	case configv1.Authentication_TrustedHeader_case:
+		return "trusted header"
+	default:
+		return ""
+	}
+}
+
+// toolHints lists the annotations of a tool that are set.
+func toolHints(t *mcprouterv1.Tool) string {
+	var hints []string
+	a := t.GetAnnotations()
+	if a.GetReadOnlyHint() {
+		hints = append(hints, "read-only")
+	}
+	if a.GetDestructiveHint() {
+		hints = append(hints, "destructive")
+	}
+	if a.GetIdempotentHint() {
+		hints = append(hints, "idempotent")
+	}
+	if a.GetOpenWorldHint() {
+		hints = append(hints, "open-world")
+	}
+	if t.GetIsStream() {
+		hints = append(hints, "stream")
+	}
+	return strings.Join(hints, ",")
+}
+
+func printToolsJSON(out io.Writer, tools []*mcprouterv1.Tool) error {
+	list := make([]json.RawMessage, 0, len(tools))
+	for _, t := range tools {
+		raw, err := protojson.Marshal(t)
+		if err != nil {
+			return fmt.Errorf("failed to encode tool %q: %w", t.GetName(), err)
+		}
+		list = append(list, raw)
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(list)
+}
+
+func printTools(out io.Writer, tools []*mcprouterv1.Tool, wide bool) {
+	if len(tools) == 0 {
+		_, _ = fmt.Fprintln(out, "No tools found.")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if wide {
+		_, _ = fmt.Fprintln(w, "NAME\tSERVICE\tTAGS\tPROFILES\tHINTS\tDESCRIPTION")
+	} else {
+		_, _ = fmt.Fprintln(w, "NAME\tSERVICE\tDESCRIPTION")
+	}
+	for _, t := range tools {
+		// Only the first line of a description fits a table row.
+		description, _, _ := strings.Cut(t.GetDescription(), "\n")
+		if wide {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", t.GetName(), dashIfEmpty(t.GetServiceId()),
+				dashIfEmpty(strings.Join(t.GetTags(), ",")), dashIfEmpty(strings.Join(t.GetProfiles(), ",")),
+				dashIfEmpty(toolHints(t)), dashIfEmpty(description))
+		} else {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", t.GetName(), dashIfEmpty(t.GetServiceId()), dashIfEmpty(description))
+		}
+	}
+	_ = w.Flush()
+}
+
+func printToolDescription(out io.Writer, t *mcprouterv1.Tool, auth toolAuth, stats *toolCallStats, statsErr error) {
+	_, _ = fmt.Fprintf(out, "Name:         %s\n", t.GetName())
+	_, _ = fmt.Fprintf(out, "Service:      %s\n", dashIfEmpty(t.GetServiceId()))
+	if t.GetDescription() != "" {
+		_, _ = fmt.Fprintf(out, "Description:  %s\n", t.GetDescription())
+	}
+	_, _ = fmt.Fprintf(out, "Tags:         %s\n", dashIfEmpty(strings.Join(t.GetTags(), ", ")))
+	_, _ = fmt.Fprintf(out, "Hints:        %s\n", dashIfEmpty(toolHints(t)))
+
+	_, _ = fmt.Fprintln(out, "Auth:")
+	client := auth.Client
+	if client == "" {
+		client = "server authentication only"
+	}
+	_, _ = fmt.Fprintf(out, "  Client:     %s\n", client)
+	_, _ = fmt.Fprintf(out, "  Upstream:   %s\n", dashIfEmpty(auth.Upstream))
+	profiles := strings.Join(auth.Profiles, ", ")
+	if profiles == "" {
+		profiles = "all"
+	}
+	_, _ = fmt.Fprintf(out, "  Profiles:   %s\n", profiles)
+
+	switch {
+	case statsErr != nil:
+		_, _ = fmt.Fprintf(out, "Calls:        unavailable (%v)\n", statsErr)
+	case stats.Calls == 0:
+		_, _ = fmt.Fprintln(out, "Calls:        none since the server started")
+	default:
+		_, _ = fmt.Fprintf(out, "Calls:        %.0f since the server started, %.0f failed (%.1f%% error rate)\n",
+			stats.Calls, stats.Errors, stats.ErrorRate*100)
+	}
+
+	printSchema(out, "Input schema", t.GetInputSchema())
+	printSchema(out, "Output schema", t.GetOutputSchema())
+}
+
+func printSchema(out io.Writer, title string, schema *structpb.Struct) {
+	m := schema.AsMap()
+	if len(m) == 0 {
+		_, _ = fmt.Fprintf(out, "%s: none\n", title)
+		return
+	}
+	b, err := json.MarshalIndent(m, "  ", "  ")
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "%s: %v\n", title, err)
+		return
+	}
+	_, _ = fmt.Fprintf(out, "%s:\n  %s\n", title, b)
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const toolCatalogMetrics = `# HELP mcpany_tools_call_total Total number of tool calls.
+# TYPE mcpany_tools_call_total counter
+mcpany_tools_call_total{error_type="none",service_id="weather",status="success",tool="weather.get_forecast"} 18
+mcpany_tools_call_total{error_type="tool_error",service_id="weather",status="error",tool="weather.get_forecast"} 1
+mcpany_tools_call_total{error_type="deadline_exceeded",service_id="weather",status="error",tool="weather.get_forecast"} 1
+mcpany_tools_call_total{error_type="none",service_id="github",status="success",tool="github.create_issue"} 4
+`
+
+func newToolCatalogServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "admin-key" {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/admin/tools":
+			_, _ = w.Write([]byte(`{"tools": [
+				{"name": "weather.get_forecast", "serviceId": "weather", "description": "Get the forecast\nof a city",
+					"tags": ["weather", "public"],
+					"inputSchema": {"type": "object", "properties": {"city": {"type": "string"}}, "required": ["city"]},
+					"outputSchema": {"type": "object", "properties": {"forecast": {"type": "string"}}},
+					"annotations": {"readOnlyHint": true, "openWorldHint": true}},
+				{"name": "github.create_issue", "serviceId": "github", "description": "Create an issue",
+					"tags": ["github"], "profiles": ["dev"], "annotations": {"destructiveHint": true}}
+			]}`))
+		case "/v1/admin/services/weather":
+			_, _ = w.Write([]byte(`{"service": {"id": "weather", "name": "weather",
+				"authentication": {"api_key": {"param_name": "X-Weather-Key"}},
+				"upstream_auth": {"bearer_token": {}}}}`))
+		case "/metrics":
+			_, _ = w.Write([]byte(toolCatalogMetrics))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func runToolCmd(t *testing.T, serverURL string, args ...string) (string, error) {
+	t.Helper()
+	cmd := newRootCmd()
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	cmd.SetErr(b)
+	cmd.SetArgs(append(append([]string{"tool"}, args...), "--server", serverURL, "--api-key", "admin-key"))
+	err := cmd.Execute()
+	return b.String(), err
+}
+
+func TestToolListCmd(t *testing.T) {
+	server := newToolCatalogServer(t)
+	defer server.Close()
+
+	out, err := runToolCmd(t, server.URL, "list")
+	require.NoError(t, err)
+	assert.Regexp(t, `NAME\s+SERVICE\s+DESCRIPTION`, out)
+	assert.Regexp(t, `github.create_issue\s+github\s+Create an issue\n\s*weather.get_forecast\s+weather\s+Get the forecast\n`, out, "sorted by name, first line of the description")
+
+	out, err = runToolCmd(t, server.URL, "list", "-o", "wide", "--service", "github")
+	require.NoError(t, err)
+	assert.Regexp(t, `github.create_issue\s+github\s+github\s+dev\s+destructive\s+Create an issue`, out)
+	assert.NotContains(t, out, "weather.get_forecast")
+
+	out, err = runToolCmd(t, server.URL, "list", "--tag", "weather", "--tag", "public", "-o", "json")
+	require.NoError(t, err)
+	var tools []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &tools))
+	require.Len(t, tools, 1)
+	assert.Equal(t, "weather.get_forecast", tools[0]["name"])
+	assert.Contains(t, tools[0], "inputSchema")
+
+	out, err = runToolCmd(t, server.URL, "list", "--tag", "weather", "--tag", "github")
+	require.NoError(t, err)
+	assert.Equal(t, "No tools found.\n", out)
+
+	_, err = runToolCmd(t, server.URL, "list", "-o", "yaml")
+	assert.ErrorContains(t, err, `invalid output format "yaml"`)
+}
+
+func TestToolDescribeCmd(t *testing.T) {
+	server := newToolCatalogServer(t)
+	defer server.Close()
+
+	out, err := runToolCmd(t, server.URL, "describe", "weather.get_forecast")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Service:      weather\n")
+	assert.Contains(t, out, "Hints:        read-only,open-world\n")
+	assert.Contains(t, out, "  Client:     api key\n")
+	assert.Contains(t, out, "  Upstream:   bearer token\n")
+	assert.Contains(t, out, "  Profiles:   all\n")
+	assert.Contains(t, out, "Calls:        20 since the server started, 2 failed (10.0% error rate)\n")
+	assert.Contains(t, out, "Input schema:\n")
+	assert.Contains(t, out, `"required": [`)
+	assert.Contains(t, out, `"forecast"`)
+
+	out, err = runToolCmd(t, server.URL, "describe", "github.create_issue", "-o", "json")
+	require.NoError(t, err)
+	var desc toolDescription
+	require.NoError(t, json.Unmarshal([]byte(out), &desc))
+	assert.Equal(t, toolAuth{Profiles: []string{"dev"}}, desc.Auth, "the service is not found")
+	assert.Equal(t, &toolCallStats{Calls: 4}, desc.Stats)
+	assert.Contains(t, string(desc.Tool), `"github.create_issue"`)
+
+	_, err = runToolCmd(t, server.URL, "describe", "missing")
+	assert.ErrorContains(t, err, `tool "missing" not found`)
+
+	cmd := newRootCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"tool", "describe", "weather.get_forecast", "--server", server.URL, "--api-key", "wrong"})
+	assert.ErrorContains(t, cmd.Execute(), "pass an admin --api-key")
+}
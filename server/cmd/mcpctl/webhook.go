@@ -0,0 +1,186 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// newWebhookCmd creates the webhook command group.
+//
+// Returns:
+//   - *cobra.Command: The configured webhook command.
+func newWebhookCmd() *cobra.Command {
+	webhookCmd := &cobra.Command{
+		Use:   "webhook",
+		Short: "Manage the webhooks of the running server",
+	}
+	dlqCmd := &cobra.Command{
+		Use:   "dlq",
+		Short: "Inspect and replay the post-call webhook events that failed",
+		Long: `Inspect and replay the post-call webhook events that failed.
+
+When a post-call webhook still fails after its retries, the server keeps the
+event in its dead-letter queue instead of dropping it. Replaying an event
+sends it again to the webhook, with its current configuration; the event
+leaves the queue once the webhook accepts it. Requires a storage backend and
+an admin API key.`,
+	}
+	dlqCmd.AddCommand(newWebhookDLQListCmd())
+	dlqCmd.AddCommand(newWebhookDLQReplayCmd())
+	webhookCmd.AddCommand(dlqCmd)
+	return webhookCmd
+}
+
+// newWebhookDLQListCmd creates the webhook dlq list command.
+//
+// Returns:
+//   - *cobra.Command: The configured list command.
+func newWebhookDLQListCmd() *cobra.Command {
+	var (
+		serverURL string
+		apiKey    string
+		limit     int
+		asJSON    bool
+	)
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the dead-lettered webhook events, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+			defer cancel()
+			body, err := listWebhookDeadLetters(ctx, serverURL, apiKey, limit)
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				_, err := cmd.OutOrStdout().Write(body)
+				return err
+			}
+			letters, err := decodeWebhookDeadLetters(body)
+			if err != nil {
+				return err
+			}
+			printWebhookDeadLetters(cmd.OutOrStdout(), letters)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&serverURL, "server", envOr("MCPANY_SERVER_URL", "http://localhost:50050"), "Base URL of the running server. Env: MCPANY_SERVER_URL")
+	cmd.Flags().StringVar(&apiKey, "api-key", envOr("MCPANY_API_KEY", ""), "API key of the server, sent in the X-API-Key header. Env: MCPANY_API_KEY")
+	cmd.Flags().IntVar(&limit, "limit", 50, "Number of events to show, or 0 for all")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the events as JSON")
+	return cmd
+}
+
+// newWebhookDLQReplayCmd creates the webhook dlq replay command.
+//
+// Returns:
+//   - *cobra.Command: The configured replay command.
+func newWebhookDLQReplayCmd() *cobra.Command {
+	var (
+		serverURL string
+		apiKey    string
+		all       bool
+	)
+	cmd := &cobra.Command{
+		Use:   "replay [<id>...]",
+		Short: "Send dead-lettered webhook events to their webhooks again",
+		Long: `Send dead-lettered webhook events to their webhooks again, the given ones or
+all of them with --all. The events the webhook accepts leave the queue; the
+others stay with their new error. The response of the webhook is not applied,
+since the tool call it was made for is over. Requires an admin API key.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if all == (len(args) > 0) {
+				return errors.New("pass the IDs of the events to replay, or --all")
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Minute)
+			defer cancel()
+			ids := args
+			if all {
+				body, err := listWebhookDeadLetters(ctx, serverURL, apiKey, 0)
+				if err != nil {
+					return err
+				}
+				letters, err := decodeWebhookDeadLetters(body)
+				if err != nil {
+					return err
+				}
+				for _, letter := range letters {
+					ids = append(ids, letter.GetId())
+				}
+			}
+
+			failed := 0
+			for _, id := range ids {
+				path := "/api/v1/webhooks/dlq/" + url.PathEscape(id) + "/replay"
+				if _, err := callAdminAPI(ctx, &http.Client{}, http.MethodPost, serverURL, path, apiKey, nil); err != nil {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Event %s failed: %v\n", id, err)
+					failed++
+					continue
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Event %s replayed.\n", id)
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d events failed to replay", failed, len(ids))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&serverURL, "server", envOr("MCPANY_SERVER_URL", "http://localhost:50050"), "Base URL of the running server. Env: MCPANY_SERVER_URL")
+	cmd.Flags().StringVar(&apiKey, "api-key", envOr("MCPANY_API_KEY", ""), "API key of the server, sent in the X-API-Key header. Env: MCPANY_API_KEY")
+	cmd.Flags().BoolVar(&all, "all", false, "Replay all the dead-lettered events")
+	return cmd
+}
+
+func listWebhookDeadLetters(ctx context.Context, serverURL, apiKey string, limit int) ([]byte, error) {
+	path := "/api/v1/webhooks/dlq"
+	if limit > 0 {
+		path += "?" + url.Values{"limit": {strconv.Itoa(limit)}}.Encode()
+	}
+	return callAdminAPI(ctx, &http.Client{}, http.MethodGet, serverURL, path, apiKey, nil)
+}
+
+func decodeWebhookDeadLetters(body []byte) ([]*configv1.WebhookDeadLetter, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode the events: %w", err)
+	}
+	letters := make([]*configv1.WebhookDeadLetter, 0, len(raw))
+	for _, r := range raw {
+		var letter configv1.WebhookDeadLetter
+		if err := protojson.Unmarshal(r, &letter); err != nil {
+			return nil, fmt.Errorf("failed to decode the events: %w", err)
+		}
+		letters = append(letters, &letter)
+	}
+	return letters, nil
+}
+
+func printWebhookDeadLetters(out io.Writer, letters []*configv1.WebhookDeadLetter) {
+	if len(letters) == 0 {
+		_, _ = fmt.Fprintln(out, "No dead-lettered webhook events.")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tFAILED\tSERVICE\tHOOK\tTOOL\tATTEMPTS\tERROR")
+	for _, l := range letters {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", l.GetId(), l.GetFailedAt(), dashIfEmpty(l.GetServiceId()),
+			dashIfEmpty(l.GetHookName()), dashIfEmpty(l.GetToolName()), l.GetAttempts(), dashIfEmpty(l.GetError()))
+	}
+	_ = w.Flush()
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDLQCmds(t *testing.T) {
+	var replayed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "admin-key" {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/webhooks/dlq":
+			_, _ = w.Write([]byte(`[
+				{"id": "dl-2", "failed_at": "2026-01-02T03:10:00.000Z", "service_id": "weather", "hook_name": "audit",
+					"tool_name": "weather.forecast", "attempts": 3, "error": "503 Service Unavailable"},
+				{"id": "dl-1", "failed_at": "2026-01-02T03:00:00.000Z", "service_id": "weather",
+					"tool_name": "weather.alerts", "attempts": 1, "error": "connection refused"}
+			]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/webhooks/dlq/dl-1/replay":
+			replayed = append(replayed, "dl-1")
+			_, _ = w.Write([]byte(`{"id": "dl-1"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/webhooks/dlq/dl-2/replay":
+			http.Error(w, "replay failed: connection refused", http.StatusBadGateway)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	run := func(args ...string) (string, error) {
+		cmd := newRootCmd()
+		b := bytes.NewBufferString("")
+		cmd.SetOut(b)
+		cmd.SetErr(b)
+		cmd.SetArgs(append(append([]string{"webhook", "dlq"}, args...), "--server", server.URL, "--api-key", "admin-key"))
+		err := cmd.Execute()
+		return b.String(), err
+	}
+
+	out, err := run("list")
+	require.NoError(t, err)
+	assert.Regexp(t, `dl-2\s+2026-01-02T03:10:00.000Z\s+weather\s+audit\s+weather.forecast\s+3\s+503 Service Unavailable`, out)
+	assert.Regexp(t, `dl-1\s+2026-01-02T03:00:00.000Z\s+weather\s+-\s+weather.alerts\s+1\s+connection refused`, out)
+
+	out, err = run("replay", "dl-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Event dl-1 replayed.\n", out)
+
+	out, err = run("replay", "--all")
+	assert.ErrorContains(t, err, "1 of 2 events failed to replay")
+	assert.Contains(t, out, "Event dl-2 failed: the server returned 502 Bad Gateway: replay failed: connection refused")
+	assert.Equal(t, []string{"dl-1", "dl-1"}, replayed)
+
+	_, err = run("replay")
+	assert.ErrorContains(t, err, "or --all")
+}
@@ -0,0 +1,194 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/mcpany/core/server/pkg/config"
+	"github.com/mcpany/core/server/pkg/configdiff"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// newConfigDiffCmd creates the config diff command, which compares the
+// running configuration with a proposed one.
+//
+// Returns:
+//   - *cobra.Command: The configured diff command.
+func newConfigDiffCmd() *cobra.Command {
+	var (
+		serverURL  string
+		apiKey     string
+		failOnRisk bool
+	)
+	diffCmd := &cobra.Command{
+		Use:   "diff <new-file>",
+		Short: "Compare the running configuration with a proposed one",
+		Long: `Compare the running configuration with a proposed one.
+
+Both configurations are resolved the way the server loads them, and compared
+semantically: services, tools and users are matched by name, so reordering
+them is not a change. The report lists the configured tools added, removed
+or changed, the authentication changes and the other changed fields, and
+highlights the changes that weaken security, such as removing
+authentication or disabling TLS verification. Secret values are never
+printed.
+
+The running configuration is read from --config-path, or from the
+/debug/config endpoint of a running server with --server. Since that
+endpoint redacts secrets, a changed secret value only shows when the secret
+is added or removed.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			osFs := afero.NewOsFs()
+			cfg := config.GlobalSettings()
+			if err := cfg.Load(cmd, osFs); err != nil {
+				return fmt.Errorf("configuration load failed: %w", err)
+			}
+
+			proposed, err := config.LoadResolvedConfig(ctx, config.NewFileStore(osFs, []string{args[0]}))
+			if err != nil {
+				return fmt.Errorf("failed to load proposed configuration: %w", err)
+			}
+
+			var report *configdiff.Report
+			switch {
+			case serverURL != "":
+				current, err := fetchRunningConfig(ctx, &http.Client{}, serverURL, apiKey)
+				if err != nil {
+					return err
+				}
+				redacted, err := configdiff.RedactedJSON(proposed)
+				if err != nil {
+					return err
+				}
+				if report, err = configdiff.DiffJSON(current, redacted); err != nil {
+					return err
+				}
+			case len(cfg.ConfigPaths()) > 0:
+				current, err := config.LoadResolvedConfig(ctx, config.NewFileStore(osFs, cfg.ConfigPaths()))
+				if err != nil {
+					return fmt.Errorf("failed to load current configuration: %w", err)
+				}
+				if report, err = configdiff.Diff(current, proposed); err != nil {
+					return err
+				}
+			default:
+				return errors.New("no current configuration: set --config-path, or --server to the debug listener of a running server")
+			}
+
+			printConfigDiff(cmd.OutOrStdout(), report)
+			if failOnRisk && len(report.Risky()) > 0 {
+				return fmt.Errorf("the proposed configuration has %d risky changes", len(report.Risky()))
+			}
+			return nil
+		},
+	}
+	diffCmd.Flags().StringVar(&serverURL, "server", "", "Base URL of the debug listener of a running server, e.g. http://127.0.0.1:6060, to compare with its configuration instead of --config-path")
+	diffCmd.Flags().StringVar(&apiKey, "api-key", os.Getenv("MCPANY_API_KEY"), "Admin API key of the server, sent in the X-API-Key header. Env: MCPANY_API_KEY")
+	diffCmd.Flags().BoolVar(&failOnRisk, "fail-on-risk", false, "Exit with an error if a change weakens security")
+	return diffCmd
+}
+
+// fetchRunningConfig reads the configuration of a running server from the
+// /debug/config endpoint of its debug listener.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the request.
+//   - client: *http.Client. The HTTP client.
+//   - serverURL: string. The base URL of the debug listener.
+//   - apiKey: string. The admin API key of the server, or empty.
+//
+// Returns:
+//   - []byte: The redacted configuration, as JSON.
+//   - error: An error if the configuration cannot be fetched.
+func fetchRunningConfig(ctx context.Context, client *http.Client, serverURL, apiKey string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(serverURL, "/")+"/debug/config", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach the server: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("the server rejected the request (%s); pass an admin --api-key", resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("the server returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+var changeMarks = map[configdiff.Kind]string{
+	configdiff.KindAdded:   "+",
+	configdiff.KindRemoved: "-",
+	configdiff.KindChanged: "~",
+}
+
+func printConfigDiff(out io.Writer, report *configdiff.Report) {
+	if report.Empty() {
+		_, _ = fmt.Fprintln(out, "No differences.")
+		return
+	}
+
+	counts := map[configdiff.Kind]int{}
+	for _, t := range report.Tools {
+		counts[t.Kind]++
+	}
+	_, _ = fmt.Fprintf(out, "Tools: %d added, %d removed, %d changed\n",
+		counts[configdiff.KindAdded], counts[configdiff.KindRemoved], counts[configdiff.KindChanged])
+	for _, t := range report.Tools {
+		line := "  " + changeMarks[t.Kind] + " " + t.Name
+		if len(t.Fields) > 0 {
+			line += " (" + strings.Join(t.Fields, ", ") + ")"
+		}
+		_, _ = fmt.Fprintln(out, line)
+	}
+
+	var other []configdiff.Change
+	for _, c := range report.Changes {
+		if !c.Auth {
+			other = append(other, c)
+		}
+	}
+	printChanges(out, "Authentication changes", report.AuthChanges())
+	printChanges(out, "Other changes", other)
+
+	risky := report.Risky()
+	_, _ = fmt.Fprintf(out, "\nRisky changes: %d\n", len(risky))
+	for _, c := range risky {
+		_, _ = fmt.Fprintf(out, "  ! %s: %s\n", c.Path, c.Risk)
+	}
+}
+
+func printChanges(out io.Writer, title string, changes []configdiff.Change) {
+	_, _ = fmt.Fprintf(out, "\n%s: %d\n", title, len(changes))
+	for _, c := range changes {
+		detail := c.Before + " -> " + c.After
+		switch c.Kind {
+		case configdiff.KindAdded:
+			detail = c.After
+		case configdiff.KindRemoved:
+			detail = c.Before
+		}
+		_, _ = fmt.Fprintf(out, "  %s %s: %s\n", changeMarks[c.Kind], c.Path, detail)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const diffCurrentConfig = `
+upstream_services:
+  - name: weather
+    http_service:
+      address: https://weather.example.com
+      tools:
+        - name: get_forecast
+          call_id: forecast
+      calls:
+        forecast:
+          endpoint_path: /forecast
+    authentication:
+      api_key:
+        param_name: X-Key
+        in: HEADER
+        verification_value: weather-key-1234
+`
+
+const diffProposedConfig = `
+upstream_services:
+  - name: weather
+    http_service:
+      address: https://weather.example.com
+      tools:
+        - name: get_forecast
+          call_id: forecast
+        - name: get_alerts
+          call_id: forecast
+      calls:
+        forecast:
+          endpoint_path: /forecast
+`
+
+func runConfigDiff(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	viper.Reset()
+	rootCmd := newRootCmd()
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs(append([]string{"config", "diff"}, args...))
+	err := rootCmd.Execute()
+	return out.String(), err
+}
+
+func TestConfigDiffCmd(t *testing.T) {
+	dir := t.TempDir()
+	current := filepath.Join(dir, "current.yaml")
+	proposed := filepath.Join(dir, "proposed.yaml")
+	require.NoError(t, os.WriteFile(current, []byte(diffCurrentConfig), 0o600))
+	require.NoError(t, os.WriteFile(proposed, []byte(diffProposedConfig), 0o600))
+
+	out, err := runConfigDiff(t, proposed, "--config-path", current)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Tools: 1 added, 0 removed, 0 changed\n  + weather.get_alerts\n")
+	assert.Contains(t, out, "Authentication changes: 1\n  - upstream_services[weather].authentication: [REDACTED]\n")
+	assert.Contains(t, out, "Risky changes: 1\n  ! upstream_services[weather].authentication: removes authentication\n")
+	assert.NotContains(t, out, "weather-key-1234")
+
+	_, err = runConfigDiff(t, proposed, "--config-path", current, "--fail-on-risk")
+	assert.EqualError(t, err, "the proposed configuration has 1 risky changes")
+
+	out, err = runConfigDiff(t, current, "--config-path", current)
+	require.NoError(t, err)
+	assert.Equal(t, "No differences.\n", out)
+
+	_, err = runConfigDiff(t, proposed)
+	assert.ErrorContains(t, err, "no current configuration")
+}
+
+func TestConfigDiffCmd_Server(t *testing.T) {
+	dir := t.TempDir()
+
+	// The debug listener serves the configuration with its secrets redacted.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/debug/config" || r.Header.Get("X-API-Key") != "admin-key" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"upstream_services": [{"name": "weather", "http_service": {
+  "address": "https://weather.example.com",
+  "tools": [{"name": "get_forecast", "call_id": "forecast"}],
+  "calls": {"forecast": {"endpoint_path": "/forecast"}}
+}, "authentication": "[REDACTED]"}]}`))
+	}))
+	defer server.Close()
+
+	proposed := filepath.Join(dir, "proposed.yaml")
+	require.NoError(t, os.WriteFile(proposed, []byte(diffProposedConfig), 0o600))
+	out, err := runConfigDiff(t, proposed, "--server", server.URL, "--api-key", "admin-key")
+	require.NoError(t, err)
+	assert.Contains(t, out, "  + weather.get_alerts\n")
+	assert.Contains(t, out, "  ! upstream_services[weather].authentication: removes authentication\n")
+
+	_, err = runConfigDiff(t, proposed, "--server", server.URL)
+	assert.ErrorContains(t, err, "pass an admin --api-key")
+}
@@ -0,0 +1,227 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/mcpany/core/server/pkg/config"
+	"github.com/mcpany/core/server/pkg/doctor"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// newDoctorCmd creates the doctor command, which checks the upstream services
+// of the configuration and applies the fixes the checks offer.
+//
+// Returns:
+//   - *cobra.Command: The configured doctor command.
+func newDoctorCmd() *cobra.Command {
+	var (
+		fix        bool
+		yes        bool
+		patchFile  string
+		output     string
+		outputFile string
+		failOn     string
+	)
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the health and connectivity of upstream services",
+		Long: `Check the health and connectivity of upstream services.
+
+Besides connectivity, the doctor checks the DNS resolution of the hosts of
+the services, and, for the reachable ones, the expiry of their TLS
+certificates, the validity of their credentials and, when a latency budget
+is configured, their p95 latency.
+
+Some problems come with an automated fix: a placeholder in the environment
+file for a missing environment variable, the scheme of an address that lacks
+one or does not match its port, the OpenAPI spec of a service fetched and
+pinned in the configuration, and a stub for a missing credential file.
+
+With --fix, each fix is applied to the files after confirmation, or at once
+with --yes. With --patch, the changes are also written as a unified diff to
+the given file; --patch without --fix leaves the files untouched, so that
+the patch can be reviewed and applied with "git apply".
+
+With --output json or junit, the report is written to stdout, or to
+--output-file, for CI pipelines; the other messages go to stderr. The exit
+code is 0 when the checks pass, 1 when a check fails, or warns with
+--fail-on warning, and 2 when the checks cannot run, such as when the
+configuration cannot be loaded.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := context.Background()
+			threshold, err := parseReportFlags(output, failOn)
+			if err != nil {
+				return &exitError{code: exitCodeUsage, err: err}
+			}
+			osFs := afero.NewOsFs()
+			cfg := config.GlobalSettings()
+			if err := cfg.Load(cmd, osFs); err != nil {
+				return &exitError{code: exitCodeUsage, err: fmt.Errorf("configuration load failed: %w", err)}
+			}
+			store := config.NewFileStore(osFs, cfg.ConfigPaths())
+			store.SetIgnoreMissingEnv(true)
+			configs, err := config.LoadResolvedConfig(ctx, store)
+			if err != nil {
+				return &exitError{code: exitCodeUsage, err: fmt.Errorf("failed to load configurations: %w", err)}
+			}
+
+			// The messages go to stderr when stdout carries a report.
+			out := cmd.OutOrStdout()
+			if output != doctor.FormatText && outputFile == "" {
+				out = cmd.ErrOrStderr()
+			}
+			_, _ = fmt.Fprintln(out, "Running doctor checks...")
+			results := doctor.RunAllChecks(ctx, configs)
+
+			envFile, _ := cmd.Flags().GetString("env-file")
+			ws, err := doctor.NewWorkspace(osFs, cfg.ConfigPaths(), envFile)
+			if err != nil {
+				return &exitError{code: exitCodeUsage, err: fmt.Errorf("failed to read the configuration files: %w", err)}
+			}
+			results = doctor.OfferFixes(ws, configs, results)
+
+			if output == doctor.FormatText {
+				doctor.PrintResults(out, results)
+			}
+			if err := writeDoctorReport(cmd.OutOrStdout(), output, outputFile, results, threshold); err != nil {
+				return &exitError{code: exitCodeUsage, err: err}
+			}
+
+			if fix || patchFile != "" {
+				if err := applyFixes(ctx, cmd.InOrStdin(), out, ws, results, fix, yes, patchFile); err != nil {
+					return &exitError{code: exitCodeUsage, err: err}
+				}
+			} else if n := countFixes(results); n > 0 {
+				_, _ = fmt.Fprintf(out, "%d automated fixes are available: run with --fix to apply them.\n", n)
+			}
+
+			for _, res := range results {
+				if doctor.Failed(res, threshold) {
+					return &exitError{code: exitCodeChecksFailed, err: fmt.Errorf("doctor checks failed with %s", strings.ToLower(string(threshold))+"s")}
+				}
+			}
+			_, _ = fmt.Fprintln(out, "All checks passed!")
+			return nil
+		},
+	}
+	doctorCmd.Flags().BoolVar(&fix, "fix", false, "Apply the automated fixes offered by the checks")
+	doctorCmd.Flags().BoolVarP(&yes, "yes", "y", false, "Apply the fixes without asking for confirmation")
+	doctorCmd.Flags().StringVar(&patchFile, "patch", "", "Write the changes of the fixes as a unified diff to this file")
+	addReportFlags(doctorCmd, &output, &outputFile, &failOn)
+	return doctorCmd
+}
+
+// addReportFlags adds the flags of the reports of the doctor checks to a
+// command.
+func addReportFlags(cmd *cobra.Command, output, outputFile, failOn *string) {
+	cmd.Flags().StringVarP(output, "output", "o", doctor.FormatText, "Format of the report of the checks: "+strings.Join(doctor.Formats, ", "))
+	cmd.Flags().StringVar(outputFile, "output-file", "", "Write the report of the checks to this file instead of stdout")
+	cmd.Flags().StringVar(failOn, "fail-on", "error", "Lowest severity of the checks that fails the command: error or warning")
+}
+
+// parseReportFlags validates the --output flag and parses the --fail-on flag.
+func parseReportFlags(output, failOn string) (doctor.Status, error) {
+	if !slices.Contains(doctor.Formats, output) {
+		return "", fmt.Errorf("invalid output format %q: must be one of %s", output, strings.Join(doctor.Formats, ", "))
+	}
+	switch failOn {
+	case doctor.SeverityError:
+		return doctor.StatusError, nil
+	case doctor.SeverityWarning:
+		return doctor.StatusWarning, nil
+	}
+	return "", fmt.Errorf("invalid --fail-on %q: must be error or warning", failOn)
+}
+
+// writeDoctorReport writes the report of the checks to the output file, or
+// to w. The text format is only written to a file, since it is printed with
+// the other messages.
+func writeDoctorReport(w io.Writer, format, outputFile string, results []doctor.CheckResult, failOn doctor.Status) error {
+	if outputFile == "" {
+		if format == doctor.FormatText {
+			return nil
+		}
+		return doctor.WriteReport(w, format, results, failOn)
+	}
+	var buf bytes.Buffer
+	if err := doctor.WriteReport(&buf, format, results, failOn); err != nil {
+		return err
+	}
+	if err := afero.WriteFile(afero.NewOsFs(), outputFile, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write the report: %w", err)
+	}
+	return nil
+}
+
+func countFixes(results []doctor.CheckResult) int {
+	n := 0
+	for _, res := range results {
+		n += len(res.Fixes)
+	}
+	return n
+}
+
+// applyFixes applies the fixes of the results to the workspace, asking for
+// confirmation of each one unless yes is set, then writes the patch file and,
+// if write is set, the changed files.
+func applyFixes(ctx context.Context, in io.Reader, out io.Writer, ws *doctor.Workspace, results []doctor.CheckResult, write, yes bool, patchFile string) error {
+	if countFixes(results) == 0 {
+		_, _ = fmt.Fprintln(out, "No automated fixes are available.")
+		return nil
+	}
+	answers := bufio.NewScanner(in)
+fixes:
+	for _, res := range results {
+		for _, f := range res.Fixes {
+			if !yes {
+				_, _ = fmt.Fprintf(out, "Apply fix [%s] %s: %s? [y/N] ", f.ID, res.ServiceName, f.Description)
+				if !answers.Scan() {
+					_, _ = fmt.Fprintln(out)
+					break fixes
+				}
+				if answer := strings.ToLower(strings.TrimSpace(answers.Text())); answer != "y" && answer != "yes" {
+					continue
+				}
+			}
+			if err := f.Apply(ctx, ws); err != nil {
+				_, _ = fmt.Fprintf(out, "❌ Fix [%s] %s failed: %v\n", f.ID, res.ServiceName, err)
+				continue
+			}
+			_, _ = fmt.Fprintf(out, "🔧 Fix [%s] %s: %s\n", f.ID, res.ServiceName, f.Description)
+		}
+	}
+
+	changed := ws.Changed()
+	if len(changed) == 0 {
+		_, _ = fmt.Fprintln(out, "No files were changed.")
+		return nil
+	}
+	if patchFile != "" {
+		patch, err := ws.Patch()
+		if err != nil {
+			return err
+		}
+		if err := afero.WriteFile(afero.NewOsFs(), patchFile, []byte(patch), 0o600); err != nil {
+			return fmt.Errorf("failed to write the patch file: %w", err)
+		}
+		_, _ = fmt.Fprintf(out, "Wrote the changes to %s\n", patchFile)
+	}
+	if !write {
+		return nil
+	}
+	if err := ws.Write(); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "Changed %s. Run mcpany doctor again to verify.\n", strings.Join(changed, ", "))
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runDoctor(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+	viper.Reset()
+	rootCmd := newRootCmd()
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(io.Discard)
+	rootCmd.SetIn(strings.NewReader(stdin))
+	rootCmd.SetArgs(append([]string{"doctor"}, args...))
+	err := rootCmd.Execute()
+	return out.String(), err
+}
+
+func TestDoctorCmd_Fix(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	envPath := filepath.Join(dir, "doctor.env")
+	patchPath := filepath.Join(dir, "fixes.patch")
+	configYAML := `upstream_services:
+  - name: weather
+    http_service:
+      address: weather.invalid:443
+  - name: news
+    http_service:
+      address: https://news.invalid
+    upstream_auth:
+      bearer_token:
+        token:
+          file_path: ` + filepath.Join(dir, "news-token") + `
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(configYAML), 0o600))
+	require.NoError(t, os.WriteFile(envPath, nil, 0o600))
+
+	out, err := runDoctor(t, "", "--config-path", configPath, "--env-file", envPath)
+	require.Error(t, err)
+	assert.Contains(t, out, "fix available: Change http_service.address from \"weather.invalid:443\" to \"https://weather.invalid:443\"")
+	assert.Contains(t, out, "2 automated fixes are available: run with --fix to apply them.")
+
+	// A patch without --fix leaves the files untouched.
+	out, err = runDoctor(t, "", "--config-path", configPath, "--env-file", envPath, "--patch", patchPath, "--yes")
+	require.Error(t, err)
+	assert.Contains(t, out, "Wrote the changes to "+patchPath)
+	patch, err := os.ReadFile(patchPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(patch), "-      address: weather.invalid:443\n+      address: https://weather.invalid:443\n")
+	assert.Contains(t, string(patch), "+REPLACE_WITH_SECRET\n")
+	b, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, configYAML, string(b))
+
+	// Interactively, only the confirmed fixes are applied.
+	out, err = runDoctor(t, "n\ny\n", "--config-path", configPath, "--env-file", envPath, "--fix")
+	require.Error(t, err)
+	assert.Contains(t, out, "[address-scheme] weather")
+	b, err = os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "address: https://weather.invalid:443\n")
+	assert.NoFileExists(t, filepath.Join(dir, "news-token"))
+}
+
+func TestDoctorCmd_Output(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`upstream_services:
+  - name: news
+    http_service:
+      address: news.invalid
+`), 0o600))
+	warningPath := filepath.Join(dir, "warning.yaml")
+	require.NoError(t, os.WriteFile(warningPath, []byte(`upstream_services:
+  - name: weather
+    disable: true
+    http_service:
+      address: https://weather.invalid/$DOCTOR_OUTPUT_PATH
+`), 0o600))
+
+	out, err := runDoctor(t, "", "--config-path", configPath, "--output", "json")
+	var exitErr *exitError
+	require.True(t, errors.As(err, &exitErr), "%v", err)
+	assert.Equal(t, exitCodeChecksFailed, exitErr.code)
+	var report struct {
+		Status string `json:"status"`
+		Checks []struct {
+			ID       string `json:"id"`
+			Severity string `json:"severity"`
+			Fixes    []struct {
+				ID string `json:"id"`
+			} `json:"fixes"`
+		} `json:"checks"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &report), out)
+	assert.Equal(t, "ERROR", report.Status)
+	require.Len(t, report.Checks, 1)
+	assert.Equal(t, "upstream/news", report.Checks[0].ID)
+	assert.Equal(t, "error", report.Checks[0].Severity)
+	require.Len(t, report.Checks[0].Fixes, 1)
+	assert.Equal(t, "address-scheme", report.Checks[0].Fixes[0].ID)
+
+	reportPath := filepath.Join(dir, "doctor.xml")
+	_, err = runDoctor(t, "", "--config-path", configPath, "--output", "junit", "--output-file", reportPath)
+	require.Error(t, err)
+	junit, err := os.ReadFile(reportPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(junit), `<testcase name="news" classname="mcpany.doctor.upstream"`)
+
+	// A warning passes unless --fail-on warning.
+	out, err = runDoctor(t, "", "--config-path", warningPath, "--output", "json")
+	require.NoError(t, err)
+	assert.Contains(t, out, `"id": "config/environment"`)
+	_, err = runDoctor(t, "", "--config-path", warningPath, "--fail-on", "warning")
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, exitCodeChecksFailed, exitErr.code)
+
+	_, err = runDoctor(t, "", "--config-path", configPath, "--output", "yaml")
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, exitCodeUsage, exitErr.code)
+	_, err = runDoctor(t, "", "--config-path", configPath, "--fail-on", "info")
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, exitCodeUsage, exitErr.code)
+	_, err = runDoctor(t, "", "--config-path", filepath.Join(dir, "missing.yaml"))
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, exitCodeUsage, exitErr.code)
+}
@@ -6,6 +6,8 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -18,6 +20,7 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/mcpany/core/server/pkg/app"
 	"github.com/mcpany/core/server/pkg/appconsts"
+	"github.com/mcpany/core/server/pkg/buildinfo"
 	"github.com/mcpany/core/server/pkg/config"
 	"github.com/mcpany/core/server/pkg/doctor"
 	"github.com/mcpany/core/server/pkg/lint"
@@ -38,6 +41,24 @@ var (
 	appRunner app.Runner = app.NewApplication()
 )
 
+const (
+	// exitCodeChecksFailed is the exit code of the doctor and strict-mode checks that failed.
+	exitCodeChecksFailed = 1
+	// exitCodeUsage is the exit code of the commands that could not run, such as on invalid flags.
+	exitCodeUsage = 2
+)
+
+// exitError is an error that exits the process with a specific code, so that
+// scripts and CI pipelines can tell failed checks from other failures.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+
+func (e *exitError) Unwrap() error { return e.err }
+
 const (
 	iconOk      = "✅"
 	iconWarning = "⚠️ "
@@ -103,6 +124,7 @@ func newRootCmd() *cobra.Command { //nolint:gocyclo // Main entry point, expecte
 	}
 	rootCmd.PersistentFlags().String("env-file", "", "Path to .env file to load environment variables from")
 
+	var strictOutput, strictOutputFile, strictFailOn string
 	runCmd := &cobra.Command{
 		Use:   "run",
 		Short: "Run the MCP Any server",
@@ -122,15 +144,16 @@ func newRootCmd() *cobra.Command { //nolint:gocyclo // Main entry point, expecte
 			bindAddress := cfg.MCPListenAddress()
 			grpcPort := cfg.GRPCPort()
 			stdio := cfg.Stdio()
+			stdioWithNetwork := cfg.StdioWithNetwork()
 			configPaths := cfg.ConfigPaths()
 
-			log.Info("Configuration", "mcp-listen-address", bindAddress, "registration-port", grpcPort, "stdio", stdio, "config-path", configPaths)
+			log.Info("Configuration", "mcp-listen-address", bindAddress, "registration-port", grpcPort, "stdio", stdio, "stdio-with-network", stdioWithNetwork, "config-path", configPaths)
 
 			// Track 1: Friction Fighter - Verify config files exist before proceeding
 			if len(configPaths) > 0 {
 				log.Info("Attempting to load services from config path", "paths", configPaths)
 				for _, path := range configPaths {
-					if strings.HasPrefix(strings.ToLower(path), "http://") || strings.HasPrefix(strings.ToLower(path), "https://") {
+					if config.IsRemotePath(path) {
 						continue
 					}
 					if _, err := osFs.Stat(path); os.IsNotExist(err) {
@@ -161,17 +184,35 @@ func newRootCmd() *cobra.Command { //nolint:gocyclo // Main entry point, expecte
 				}
 			}()
 
+			// SIGUSR1 drains the server ahead of a termination, like POST /drain.
+			if drainer, ok := appRunner.(app.Drainer); ok {
+				go func() {
+					drainChan := make(chan os.Signal, 1)
+					signal.Notify(drainChan, syscall.SIGUSR1)
+					defer signal.Stop(drainChan)
+
+					select {
+					case <-drainChan:
+						log.Info("Received SIGUSR1, draining...")
+						status := drainer.Drain(ctx)
+						log.Info("Drain finished", "ready_to_terminate", status.ReadyToTerminate, "abandoned_calls", status.AbandonedCalls)
+					case <-ctx.Done():
+					}
+				}()
+			}
+
 			// Start file watcher
-			if !cfg.Stdio() {
+			if !stdio || stdioWithNetwork {
 				watcher, err := config.NewWatcher()
 				if err != nil {
 					return fmt.Errorf("failed to create file watcher: %w", err)
 				}
 				defer watcher.Close()
+				watcher.SetPollInterval(cfg.ConfigPollInterval())
 
 				go func() {
 					if err := watcher.Watch(configPaths, func() {
-						if err := appRunner.ReloadConfig(ctx, osFs, configPaths); err != nil {
+						if err := appRunner.ReloadConfig(app.WithReloadTrigger(ctx, "config change"), osFs, configPaths); err != nil {
 							log.Error("Failed to reload config", "error", err)
 						}
 					}); err != nil {
@@ -198,36 +239,44 @@ func newRootCmd() *cobra.Command { //nolint:gocyclo // Main entry point, expecte
 			// Track 1: Friction Fighter - Strict Mode
 			strict, _ := cmd.Flags().GetBool("strict")
 			if strict {
+				threshold, err := parseReportFlags(strictOutput, strictFailOn)
+				if err != nil {
+					return &exitError{code: exitCodeUsage, err: err}
+				}
 				log.Info("Running in strict mode: validating configuration and upstream connectivity...")
 				store := config.NewFileStore(osFs, configPaths)
 				configs, err := config.LoadResolvedConfig(ctx, store)
 				if err != nil {
-					return fmt.Errorf("failed to load configuration for strict validation: %w", err)
+					return &exitError{code: exitCodeUsage, err: fmt.Errorf("failed to load configuration for strict validation: %w", err)}
 				}
 
-				results := doctor.RunChecks(ctx, configs)
+				results := doctor.RunAllChecks(ctx, configs)
 				hasErrors := false
 				for _, res := range results {
 					var icon string
 					switch res.Status {
 					case doctor.StatusOk:
 						icon = iconOk
-						log.Info(fmt.Sprintf("%s [%s] %s: %s", icon, res.Status, res.ServiceName, res.Message))
+						log.Info(fmt.Sprintf("%s [%s] %s: %s", icon, res.Status, res.Label(), res.Message))
 					case doctor.StatusWarning:
 						icon = iconWarning
-						log.Warn(fmt.Sprintf("%s [%s] %s: %s", icon, res.Status, res.ServiceName, res.Message))
+						log.Warn(fmt.Sprintf("%s [%s] %s: %s", icon, res.Status, res.Label(), res.Message))
 					case doctor.StatusError:
 						icon = iconError
-						log.Error(fmt.Sprintf("%s [%s] %s: %s", icon, res.Status, res.ServiceName, res.Message))
-						hasErrors = true
+						log.Error(fmt.Sprintf("%s [%s] %s: %s", icon, res.Status, res.Label(), res.Message))
 					case doctor.StatusSkipped:
 						icon = iconSkipped
-						log.Info(fmt.Sprintf("%s [%s] %s: %s", icon, res.Status, res.ServiceName, res.Message))
+						log.Info(fmt.Sprintf("%s [%s] %s: %s", icon, res.Status, res.Label(), res.Message))
 					}
+					hasErrors = hasErrors || doctor.Failed(res, threshold)
+				}
+				// The report goes to stderr, since stdout serves the stdio transport.
+				if err := writeDoctorReport(cmd.ErrOrStderr(), strictOutput, strictOutputFile, results, threshold); err != nil {
+					return err
 				}
 
 				if hasErrors {
-					return fmt.Errorf("strict mode validation failed: one or more upstream services are unreachable or misconfigured")
+					return &exitError{code: exitCodeChecksFailed, err: fmt.Errorf("strict mode validation failed: one or more upstream services are unreachable or misconfigured")}
 				}
 				log.Info("Strict mode validation passed.")
 			}
@@ -248,16 +297,26 @@ func newRootCmd() *cobra.Command { //nolint:gocyclo // Main entry point, expecte
 				}
 			}
 
+			listenerTLS := cfg.ListenerTLS()
 			if err := appRunner.Run(app.RunOptions{
-				Ctx:             ctx,
-				Fs:              osFs,
-				Stdio:           stdio,
-				JSONRPCPort:     bindAddress,
-				GRPCPort:        grpcPort,
-				ConfigPaths:     configPaths,
-				APIKey:          cfg.APIKey(),
-				ShutdownTimeout: shutdownTimeout,
-				DBPath:          cfg.DBPath(),
+				Ctx:              ctx,
+				Fs:               osFs,
+				Stdio:            stdio,
+				StdioWithNetwork: stdioWithNetwork,
+				JSONRPCPort:      bindAddress,
+				GRPCPort:         grpcPort,
+				ConfigPaths:      configPaths,
+				APIKey:           cfg.APIKey(),
+				ShutdownTimeout:  shutdownTimeout,
+				TLSCert:          listenerTLS.CertFile,
+				TLSKey:           listenerTLS.KeyFile,
+				TLSClientCA:      listenerTLS.ClientCAFile,
+				TLSClientAuth:    listenerTLS.ClientAuth,
+				ACMEDomains:      listenerTLS.ACMEDomains,
+				ACMEEmail:        listenerTLS.ACMEEmail,
+				ACMECacheDir:     listenerTLS.ACMECacheDir,
+				ACMEDirectoryURL: listenerTLS.ACMEDirectoryURL,
+				DBPath:           cfg.DBPath(),
 			}); err != nil {
 				log.Error("Application failed", "error", err)
 				return err
@@ -267,6 +326,7 @@ func newRootCmd() *cobra.Command { //nolint:gocyclo // Main entry point, expecte
 		},
 	}
 	runCmd.Flags().Bool("strict", false, "Run in strict mode (validate upstream connectivity before starting)")
+	addReportFlags(runCmd, &strictOutput, &strictOutputFile, &strictFailOn)
 	config.BindServerFlags(runCmd)
 	rootCmd.AddCommand(runCmd)
 
@@ -274,6 +334,15 @@ func newRootCmd() *cobra.Command { //nolint:gocyclo // Main entry point, expecte
 		Use:   "version",
 		Short: "Print the version number of mcpany",
 		RunE: func(cmd *cobra.Command, _ []string) error {
+			detailed, _ := cmd.Flags().GetBool("detailed")
+			if detailed {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(buildinfo.Get(Version)); err != nil {
+					return fmt.Errorf("failed to print version: %w", err)
+				}
+				return nil
+			}
 			_, err := fmt.Fprintf(cmd.OutOrStdout(), "%s version %s\n", appconsts.Name, Version)
 			if err != nil {
 				return fmt.Errorf("failed to print version: %w", err)
@@ -281,6 +350,7 @@ func newRootCmd() *cobra.Command { //nolint:gocyclo // Main entry point, expecte
 			return nil
 		},
 	}
+	versionCmd.Flags().Bool("detailed", false, "Print build details as JSON, including Go module versions, SBOM and provenance")
 	rootCmd.AddCommand(versionCmd)
 
 	updateCmd := &cobra.Command{
@@ -366,43 +436,7 @@ func newRootCmd() *cobra.Command { //nolint:gocyclo // Main entry point, expecte
 	healthCmd.Flags().Duration("timeout", 5*time.Second, "Timeout for the health check.")
 	rootCmd.AddCommand(healthCmd)
 
-	doctorCmd := &cobra.Command{
-		Use:   "doctor",
-		Short: "Check the health and connectivity of upstream services",
-		RunE: func(cmd *cobra.Command, _ []string) error {
-			osFs := afero.NewOsFs()
-			cfg := config.GlobalSettings()
-			if err := cfg.Load(cmd, osFs); err != nil {
-				return fmt.Errorf("configuration load failed: %w", err)
-			}
-			store := config.NewFileStore(osFs, cfg.ConfigPaths())
-			store.SetIgnoreMissingEnv(true)
-			configs, err := config.LoadResolvedConfig(context.Background(), store)
-			if err != nil {
-				return fmt.Errorf("failed to load configurations: %w", err)
-			}
-
-			fmt.Println("Running doctor checks...")
-			results := doctor.RunChecks(context.Background(), configs)
-
-			doctor.PrintResults(cmd.OutOrStdout(), results)
-
-			hasErrors := false
-			for _, res := range results {
-				if res.Status == doctor.StatusError {
-					hasErrors = true
-					break
-				}
-			}
-
-			if hasErrors {
-				return fmt.Errorf("doctor checks failed with errors")
-			}
-			fmt.Println("All checks passed!")
-			return nil
-		},
-	}
-	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(newDoctorCmd())
 
 	configCmd := &cobra.Command{
 		Use:   "config",
@@ -553,6 +587,7 @@ func newRootCmd() *cobra.Command { //nolint:gocyclo // Main entry point, expecte
 	}
 	validateCmd.Flags().Bool("check-connection", false, "Run connectivity checks for upstream services")
 	configCmd.AddCommand(validateCmd)
+	configCmd.AddCommand(newConfigDiffCmd())
 
 	lintCmd := &cobra.Command{
 		Use:   "lint",
@@ -596,6 +631,7 @@ func newRootCmd() *cobra.Command { //nolint:gocyclo // Main entry point, expecte
 	}
 	rootCmd.AddCommand(lintCmd)
 	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(newTUICmd())
 
 	config.BindRootFlags(rootCmd)
 
@@ -611,6 +647,10 @@ func newRootCmd() *cobra.Command { //nolint:gocyclo // Main entry point, expecte
 //   - Exits the process.
 func main() {
 	if err := newRootCmd().Execute(); err != nil {
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		os.Exit(1)
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net"
@@ -15,6 +16,7 @@ import (
 	"os"
 	"os/exec"
 	"regexp"
+	"runtime"
 	"strings"
 	"syscall"
 	"testing"
@@ -22,6 +24,7 @@ import (
 
 	"github.com/mcpany/core/server/pkg/app"
 	"github.com/mcpany/core/server/pkg/appconsts"
+	"github.com/mcpany/core/server/pkg/buildinfo"
 	"github.com/mcpany/core/server/pkg/util"
 	"github.com/spf13/afero"
 	"github.com/spf13/viper"
@@ -172,6 +175,21 @@ func TestVersionCmd(t *testing.T) {
 	assert.Equal(t, expectedOutput, string(out))
 }
 
+func TestVersionCmd_Detailed(t *testing.T) {
+	viper.Reset()
+	rootCmd := newRootCmd()
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"version", "--detailed"})
+	require.NoError(t, rootCmd.Execute())
+
+	var info buildinfo.Info
+	require.NoError(t, json.Unmarshal(out.Bytes(), &info))
+	assert.Equal(t, Version, info.Version)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+	assert.NotEmpty(t, info.Modules)
+}
+
 // This test is for the main function, which is not easily testable.
 // We can, however, test the command execution.
 func TestMainExecution(t *testing.T) {
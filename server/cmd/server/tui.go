@@ -0,0 +1,59 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/tui"
+	"github.com/spf13/cobra"
+)
+
+// newTUICmd creates the tui command, a terminal UI of a running server.
+//
+// Returns:
+//   - *cobra.Command: The configured tui command.
+func newTUICmd() *cobra.Command {
+	var (
+		serverURL string
+		apiKey    string
+		refresh   time.Duration
+	)
+	tuiCmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Open a terminal UI of a running server",
+		Long: `Open a terminal UI of a running server: a live view of the recent tool
+calls, the health and circuit state of the upstreams, and the logs, with
+forms to call tools. It is meant for debugging over SSH, where the web
+dashboard is not reachable.
+
+Type the number of a view and Enter to switch to it, "i <tool>" to call a
+tool, and "q" to quit. The UI reads the built-in dashboard API of the
+server, so the API key needs the viewer or admin role. The size of the
+terminal is read from $COLUMNS and $LINES.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+			return tui.Run(ctx, tui.NewClient(serverURL, apiKey, nil), cmd.InOrStdin(), cmd.OutOrStdout(), tui.Options{
+				Refresh: refresh,
+				Width:   envInt("COLUMNS"),
+				Height:  envInt("LINES"),
+			})
+		},
+	}
+	tuiCmd.Flags().StringVar(&serverURL, "server", "http://localhost:50050", "Base URL of the HTTP listener of the server")
+	tuiCmd.Flags().StringVar(&apiKey, "api-key", os.Getenv("MCPANY_API_KEY"), "API key of the server, sent in the X-API-Key header. Env: MCPANY_API_KEY")
+	tuiCmd.Flags().DurationVar(&refresh, "refresh", 2*time.Second, "How often the current view is reloaded")
+	return tuiCmd
+}
+
+// envInt returns the integer value of an environment variable, or 0.
+func envInt(name string) int {
+	n, _ := strconv.Atoi(os.Getenv(name))
+	return n
+}
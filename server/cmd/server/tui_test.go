@@ -0,0 +1,53 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTUICmd(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "viewer-key" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/dashboard/api/me":
+			_, _ = w.Write([]byte(`{"user":"oncall","roles":["viewer"],"admin":false}`))
+		case "/dashboard/api/calls":
+			_, _ = w.Write([]byte(`{"audit_enabled":false,"calls":[]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	run := func(apiKey string) (string, error) {
+		viper.Reset()
+		rootCmd := newRootCmd()
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetIn(strings.NewReader("q\n"))
+		rootCmd.SetArgs([]string{"tui", "--server", server.URL, "--api-key", apiKey})
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("viewer-key")
+	require.NoError(t, err)
+	assert.Contains(t, out, "oncall (read-only)")
+	assert.Contains(t, out, "Audit logging is not enabled")
+
+	_, err = run("wrong")
+	assert.ErrorContains(t, err, "pass an --api-key")
+}
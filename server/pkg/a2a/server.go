@@ -0,0 +1,558 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package a2a serves the aggregated MCP toolset over the Agent2Agent (A2A)
+// protocol, so that agent frameworks speaking A2A can delegate work to the
+// upstream services. The agent card advertises every tool as a skill, and a
+// task is a call of one tool, run through the MCP server.
+package a2a
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// SkillIDKey is the metadata key of a message naming the skill to run.
+	SkillIDKey = "skillId"
+
+	// defaultTaskTTL is how long a finished task can be fetched.
+	defaultTaskTTL = time.Hour
+	// maxRequestBytes bounds the body of a JSON-RPC request.
+	maxRequestBytes = 5 << 20
+)
+
+// JSON-RPC error codes of the A2A protocol.
+const (
+	codeParseError            = -32700
+	codeInvalidRequest        = -32600
+	codeMethodNotFound        = -32601
+	codeInvalidParams         = -32602
+	codeTaskNotFound          = -32001
+	codeTaskNotCancelable     = -32002
+	codePushNotSupported      = -32003
+	codeUnsupportedOperation  = -32004
+	codeContentTypeNotAllowed = -32005
+)
+
+// ToolSet is the aggregated MCP toolset served by the agent.
+type ToolSet interface {
+	// ListTools lists the registered tools.
+	ListTools() []tool.Tool
+	// CallTool executes a tool, enforcing the profile of the context.
+	CallTool(ctx context.Context, req *tool.ExecutionRequest) (any, error)
+	// IsServiceAllowed reports whether a profile may use the tools of a
+	// service.
+	IsServiceAllowed(serviceID, profileID string) bool
+}
+
+// Options describes the agent.
+type Options struct {
+	// Name and Description are advertised in the agent card.
+	Name        string
+	Description string
+	// Version is the version of the agent.
+	Version string
+	// Path is the path of the JSON-RPC endpoint, e.g. "/a2a".
+	Path string
+	// TaskTTL is how long a finished task can be fetched. Defaults to one
+	// hour.
+	TaskTTL time.Duration
+}
+
+// Server is an A2A agent delegating its tasks to MCP tools.
+type Server struct {
+	tools ToolSet
+	opts  Options
+
+	mu    sync.Mutex
+	tasks map[string]*taskEntry
+}
+
+// taskEntry is a task and the caller it belongs to.
+type taskEntry struct {
+	task   Task
+	owner  string
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewServer creates an A2A agent.
+//
+// Parameters:
+//   - tools (ToolSet): The toolset the tasks run on.
+//   - opts (Options): The description of the agent.
+//
+// Returns:
+//   - *Server: The agent.
+func NewServer(tools ToolSet, opts Options) *Server {
+	if opts.TaskTTL <= 0 {
+		opts.TaskTTL = defaultTaskTTL
+	}
+	return &Server{tools: tools, opts: opts, tasks: make(map[string]*taskEntry)}
+}
+
+// ServeHTTP serves the agent card on GET and the JSON-RPC methods on POST.
+//
+// Parameters:
+//   - w (http.ResponseWriter): The response writer.
+//   - r (*http.Request): The request.
+//
+// Side Effects:
+//   - Runs the tools of the tasks sent to the agent.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, s.Card(r))
+	case http.MethodPost:
+		s.serveRPC(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Card returns the agent card, with the skills the caller may use.
+//
+// Parameters:
+//   - r (*http.Request): The request of the caller, which determines the URL
+//     of the endpoint and the profile of the caller.
+//
+// Returns:
+//   - *AgentCard: The agent card.
+func (s *Server) Card(r *http.Request) *AgentCard {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	card := &AgentCard{
+		ProtocolVersion:    ProtocolVersion,
+		Name:               s.opts.Name,
+		Description:        s.opts.Description,
+		URL:                scheme + "://" + r.Host + s.opts.Path,
+		PreferredTransport: "JSONRPC",
+		Version:            s.opts.Version,
+		DefaultInputModes:  []string{"application/json", "text/plain"},
+		DefaultOutputModes: []string{"application/json", "text/plain"},
+		Skills:             []AgentSkill{},
+	}
+	for _, t := range s.visibleTools(r.Context()) {
+		mcpTool := t.MCPTool()
+		card.Skills = append(card.Skills, AgentSkill{
+			ID:          mcpTool.Name,
+			Name:        mcpTool.Name,
+			Description: mcpTool.Description,
+			Tags:        []string{t.Tool().GetServiceId()},
+			InputModes:  []string{"application/json"},
+		})
+	}
+	return card
+}
+
+// visibleTools lists the tools the profile of the context may use.
+func (s *Server) visibleTools(ctx context.Context) []tool.Tool {
+	profileID, _ := auth.ProfileIDFromContext(ctx)
+	var visible []tool.Tool
+	for _, t := range s.tools.ListTools() {
+		if t.MCPTool() == nil || t.Tool() == nil {
+			continue
+		}
+		if profileID != "" && !s.tools.IsServiceAllowed(t.Tool().GetServiceId(), profileID) {
+			continue
+		}
+		visible = append(visible, t)
+	}
+	return visible
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func newRPCError(code int, format string, args ...any) *rpcError {
+	return &rpcError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func (s *Server) serveRPC(w http.ResponseWriter, r *http.Request) {
+	var req rpcRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req); err != nil {
+		writeRPC(w, nil, nil, newRPCError(codeParseError, "invalid JSON: %v", err))
+		return
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		writeRPC(w, req.ID, nil, newRPCError(codeInvalidRequest, "not a JSON-RPC 2.0 request"))
+		return
+	}
+	var result any
+	var err *rpcError
+	switch req.Method {
+	case "message/send":
+		result, err = s.sendMessage(r.Context(), req.Params)
+	case "tasks/get":
+		result, err = s.getTask(r.Context(), req.Params)
+	case "tasks/cancel":
+		result, err = s.cancelTask(r.Context(), req.Params)
+	case "message/stream", "tasks/resubscribe":
+		err = newRPCError(codeUnsupportedOperation, "streaming is not supported")
+	case "tasks/pushNotificationConfig/set", "tasks/pushNotificationConfig/get",
+		"tasks/pushNotificationConfig/list", "tasks/pushNotificationConfig/delete":
+		err = newRPCError(codePushNotSupported, "push notifications are not supported")
+	default:
+		err = newRPCError(codeMethodNotFound, "method %q not found", req.Method)
+	}
+	writeRPC(w, req.ID, result, err)
+}
+
+type sendMessageParams struct {
+	Message       *Message `json:"message"`
+	Configuration *struct {
+		Blocking      *bool `json:"blocking"`
+		HistoryLength *int  `json:"historyLength"`
+	} `json:"configuration"`
+	Metadata map[string]any `json:"metadata"`
+}
+
+// sendMessage starts a task running the skill named by the message.
+func (s *Server) sendMessage(ctx context.Context, raw json.RawMessage) (any, *rpcError) {
+	var params sendMessageParams
+	if err := json.Unmarshal(raw, &params); err != nil || params.Message == nil {
+		return nil, newRPCError(codeInvalidParams, "params must hold a message")
+	}
+	msg := params.Message
+	if msg.TaskID != "" {
+		if _, ok := s.lookup(ctx, msg.TaskID); !ok {
+			return nil, newRPCError(codeTaskNotFound, "task %q not found", msg.TaskID)
+		}
+		return nil, newRPCError(codeUnsupportedOperation, "task %q does not accept further messages: send a new message to start a task", msg.TaskID)
+	}
+	skillID, _ := msg.Metadata[SkillIDKey].(string)
+	if skillID == "" {
+		skillID, _ = params.Metadata[SkillIDKey].(string)
+	}
+	if skillID == "" {
+		return nil, newRPCError(codeInvalidParams, "the message must name the skill to run in its %q metadata", SkillIDKey)
+	}
+	var target tool.Tool
+	for _, t := range s.visibleTools(ctx) {
+		if t.MCPTool().Name == skillID {
+			target = t
+			break
+		}
+	}
+	if target == nil {
+		return nil, newRPCError(codeInvalidParams, "unknown skill %q", skillID)
+	}
+	args, rpcErr := arguments(msg.Parts)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	entry := s.start(ctx, msg, skillID, args)
+	if params.Configuration == nil || params.Configuration.Blocking == nil || *params.Configuration.Blocking {
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+		}
+	}
+	historyLength := -1
+	if params.Configuration != nil && params.Configuration.HistoryLength != nil {
+		historyLength = *params.Configuration.HistoryLength
+	}
+	return s.snapshot(entry, historyLength), nil
+}
+
+// start records a task and runs its tool in the background.
+func (s *Server) start(ctx context.Context, msg *Message, skillID string, args json.RawMessage) *taskEntry {
+	owner, _ := auth.UserFromContext(ctx)
+	// The task outlives the request when the client does not wait for it.
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	contextID := msg.ContextID
+	if contextID == "" {
+		contextID = uuid.NewString()
+	}
+	userMsg := *msg
+	userMsg.Kind = "message"
+	userMsg.ContextID = contextID
+	entry := &taskEntry{
+		task: Task{
+			ID:        uuid.NewString(),
+			ContextID: contextID,
+			Status:    TaskStatus{State: TaskStateSubmitted, Timestamp: time.Now().UTC()},
+			History:   []Message{userMsg},
+			Metadata:  map[string]any{SkillIDKey: skillID},
+			Kind:      "task",
+		},
+		owner:  owner,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	entry.task.History[0].TaskID = entry.task.ID
+
+	s.mu.Lock()
+	s.evictLocked()
+	s.tasks[entry.task.ID] = entry
+	s.mu.Unlock()
+
+	go s.run(runCtx, entry, skillID, args)
+	return entry
+}
+
+// run executes the tool of a task and records its outcome.
+func (s *Server) run(ctx context.Context, entry *taskEntry, skillID string, args json.RawMessage) {
+	defer close(entry.done)
+	defer entry.cancel()
+	if !s.transition(entry, TaskStateWorking, nil, nil) {
+		return
+	}
+	result, err := s.tools.CallTool(ctx, &tool.ExecutionRequest{ToolName: skillID, ToolInputs: args})
+	switch {
+	case err != nil:
+		s.transition(entry, TaskStateFailed, s.agentMessage(entry, []Part{textPart("Tool execution failed: " + err.Error())}), nil)
+	case isError(result):
+		s.transition(entry, TaskStateFailed, s.agentMessage(entry, resultParts(result)), nil)
+	default:
+		artifact := &Artifact{ArtifactID: uuid.NewString(), Name: "result", Parts: resultParts(result)}
+		s.transition(entry, TaskStateCompleted, nil, artifact)
+	}
+	logging.GetLogger().Info("A2A task done", "task", entry.task.ID, "skill", skillID, "state", s.state(entry))
+}
+
+// transition moves a task to a new state, unless it is already done.
+func (s *Server) transition(entry *taskEntry, state TaskState, msg *Message, artifact *Artifact) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry.task.Status.State.Terminal() {
+		return false
+	}
+	entry.task.Status = TaskStatus{State: state, Message: msg, Timestamp: time.Now().UTC()}
+	if msg != nil {
+		entry.task.History = append(entry.task.History, *msg)
+	}
+	if artifact != nil {
+		entry.task.Artifacts = append(entry.task.Artifacts, *artifact)
+	}
+	return true
+}
+
+func (s *Server) state(entry *taskEntry) TaskState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return entry.task.Status.State
+}
+
+func (s *Server) agentMessage(entry *taskEntry, parts []Part) *Message {
+	return &Message{
+		Role:      "agent",
+		Parts:     parts,
+		MessageID: uuid.NewString(),
+		TaskID:    entry.task.ID,
+		ContextID: entry.task.ContextID,
+		Kind:      "message",
+	}
+}
+
+type taskIDParams struct {
+	ID            string `json:"id"`
+	HistoryLength *int   `json:"historyLength"`
+}
+
+func (s *Server) getTask(ctx context.Context, raw json.RawMessage) (any, *rpcError) {
+	var params taskIDParams
+	if err := json.Unmarshal(raw, &params); err != nil || params.ID == "" {
+		return nil, newRPCError(codeInvalidParams, "params must hold the id of a task")
+	}
+	entry, ok := s.lookup(ctx, params.ID)
+	if !ok {
+		return nil, newRPCError(codeTaskNotFound, "task %q not found", params.ID)
+	}
+	historyLength := -1
+	if params.HistoryLength != nil {
+		historyLength = *params.HistoryLength
+	}
+	return s.snapshot(entry, historyLength), nil
+}
+
+func (s *Server) cancelTask(ctx context.Context, raw json.RawMessage) (any, *rpcError) {
+	var params taskIDParams
+	if err := json.Unmarshal(raw, &params); err != nil || params.ID == "" {
+		return nil, newRPCError(codeInvalidParams, "params must hold the id of a task")
+	}
+	entry, ok := s.lookup(ctx, params.ID)
+	if !ok {
+		return nil, newRPCError(codeTaskNotFound, "task %q not found", params.ID)
+	}
+	if !s.transition(entry, TaskStateCanceled, nil, nil) {
+		return nil, newRPCError(codeTaskNotCancelable, "task %q is already %s", params.ID, s.state(entry))
+	}
+	entry.cancel()
+	return s.snapshot(entry, -1), nil
+}
+
+// lookup finds a task of the caller. The tasks of other callers are not
+// found, so that their IDs do not leak.
+func (s *Server) lookup(ctx context.Context, id string) (*taskEntry, bool) {
+	owner, _ := auth.UserFromContext(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.tasks[id]
+	if !ok || entry.owner != owner {
+		return nil, false
+	}
+	return entry, true
+}
+
+// snapshot copies a task, with at most historyLength messages of its history,
+// or all of them if historyLength is negative.
+func (s *Server) snapshot(entry *taskEntry, historyLength int) *Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task := entry.task
+	history := task.History
+	if historyLength >= 0 && len(history) > historyLength {
+		history = history[len(history)-historyLength:]
+	}
+	task.History = append([]Message(nil), history...)
+	task.Artifacts = append([]Artifact(nil), task.Artifacts...)
+	return &task
+}
+
+// evictLocked forgets the tasks finished for longer than the TTL.
+func (s *Server) evictLocked() {
+	cutoff := time.Now().Add(-s.opts.TaskTTL)
+	for id, entry := range s.tasks {
+		if entry.task.Status.State.Terminal() && entry.task.Status.Timestamp.Before(cutoff) {
+			delete(s.tasks, id)
+		}
+	}
+}
+
+// arguments reads the arguments of a tool from the parts of a message: the
+// data parts, merged, or else a text part holding a JSON object.
+func arguments(parts []Part) (json.RawMessage, *rpcError) {
+	merged := map[string]any{}
+	hasData := false
+	var text []string
+	for _, part := range parts {
+		switch part.Kind {
+		case "data":
+			var data map[string]any
+			if err := json.Unmarshal(part.Data, &data); err != nil {
+				return nil, newRPCError(codeInvalidParams, "a data part must hold a JSON object")
+			}
+			for k, v := range data {
+				merged[k] = v
+			}
+			hasData = true
+		case "text":
+			text = append(text, part.Text)
+		default:
+			return nil, newRPCError(codeContentTypeNotAllowed, "%s parts are not supported: send the arguments of the skill as a data part", part.Kind)
+		}
+	}
+	if hasData {
+		b, err := json.Marshal(merged)
+		if err != nil {
+			return nil, newRPCError(codeInvalidParams, "invalid arguments: %v", err)
+		}
+		return b, nil
+	}
+	joined := strings.TrimSpace(strings.Join(text, "\n"))
+	if joined == "" {
+		return json.RawMessage("{}"), nil
+	}
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(joined), &obj); err != nil {
+		return nil, newRPCError(codeInvalidParams, "the arguments of the skill must be a data part or a text part holding a JSON object")
+	}
+	return json.RawMessage(joined), nil
+}
+
+func isError(result any) bool {
+	ctr, ok := result.(*mcp.CallToolResult)
+	return ok && ctr.IsError
+}
+
+// resultParts converts the result of a tool into parts: text contents as text
+// parts, images and audio as files, and structured results as data.
+func resultParts(result any) []Part {
+	ctr, ok := result.(*mcp.CallToolResult)
+	if !ok {
+		return []Part{valuePart(result)}
+	}
+	parts := make([]Part, 0, len(ctr.Content)+1)
+	for _, content := range ctr.Content {
+		switch c := content.(type) {
+		case *mcp.TextContent:
+			parts = append(parts, textPart(c.Text))
+		case *mcp.ImageContent:
+			parts = append(parts, Part{Kind: "file", File: &FileContent{MIMEType: c.MIMEType, Bytes: c.Data}})
+		case *mcp.AudioContent:
+			parts = append(parts, Part{Kind: "file", File: &FileContent{MIMEType: c.MIMEType, Bytes: c.Data}})
+		default:
+			parts = append(parts, valuePart(content))
+		}
+	}
+	if ctr.StructuredContent != nil {
+		parts = append(parts, valuePart(ctr.StructuredContent))
+	}
+	return parts
+}
+
+// valuePart converts a value into a data part if it is a JSON object, or a
+// text part otherwise.
+func valuePart(v any) Part {
+	if s, ok := v.(string); ok {
+		return textPart(s)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return textPart(fmt.Sprint(v))
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(b), []byte("{")) {
+		return Part{Kind: "data", Data: b}
+	}
+	return textPart(string(b))
+}
+
+func textPart(text string) Part {
+	return Part{Kind: "text", Text: text}
+}
+
+func writeRPC(w http.ResponseWriter, id json.RawMessage, result any, err *rpcError) {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	resp := map[string]any{"jsonrpc": "2.0", "id": id}
+	if err != nil {
+		resp["error"] = err
+	} else {
+		resp["result"] = result
+	}
+	writeJSON(w, resp)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logging.GetLogger().Error("Failed to encode A2A response", "error", err)
+	}
+}
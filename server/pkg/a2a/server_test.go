@@ -0,0 +1,209 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeToolSet serves a weather tool, a billing tool only the "finance"
+// profile may use, and a slow tool that runs until it is canceled.
+type fakeToolSet struct{}
+
+func (fakeToolSet) ListTools() []tool.Tool {
+	newTool := func(service, name string) tool.Tool {
+		return &tool.MockTool{
+			ToolFunc: func() *v1.Tool {
+				return v1.Tool_builder{ServiceId: proto.String(service), Name: proto.String(name)}.Build()
+			},
+			MCPToolFunc: func() *mcp.Tool {
+				return &mcp.Tool{Name: service + "." + name, Description: "The " + name + " tool"}
+			},
+		}
+	}
+	return []tool.Tool{newTool("weather", "get_forecast"), newTool("billing", "list_invoices"), newTool("reports", "build")}
+}
+
+func (fakeToolSet) CallTool(ctx context.Context, req *tool.ExecutionRequest) (any, error) {
+	switch req.ToolName {
+	case "billing.list_invoices":
+		return nil, errors.New("upstream unavailable")
+	case "reports.build":
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	var args struct{ City string }
+	_ = json.Unmarshal(req.ToolInputs, &args)
+	if args.City == "" {
+		return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: "city is required"}}}, nil
+	}
+	return &mcp.CallToolResult{
+		Content:           []mcp.Content{&mcp.TextContent{Text: "Sunny in " + args.City}},
+		StructuredContent: map[string]any{"city": args.City, "sky": "sunny"},
+	}, nil
+}
+
+func (fakeToolSet) IsServiceAllowed(serviceID, profileID string) bool {
+	return serviceID != "billing" || profileID == "finance"
+}
+
+// rpcResult is the response of a JSON-RPC call.
+type rpcResult struct {
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+func call(t *testing.T, s *Server, ctx context.Context, method string, params any) rpcResult {
+	t.Helper()
+	body, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/a2a", strings.NewReader(string(body))).WithContext(ctx))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var res rpcResult
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	return res
+}
+
+func task(t *testing.T, res rpcResult) Task {
+	t.Helper()
+	require.Nil(t, res.Error)
+	var task Task
+	require.NoError(t, json.Unmarshal(res.Result, &task))
+	return task
+}
+
+func message(skill string, parts ...Part) map[string]any {
+	return map[string]any{"message": Message{Role: "user", Parts: parts, MessageID: "m1", Metadata: map[string]any{SkillIDKey: skill}, Kind: "message"}}
+}
+
+func TestServer_Card(t *testing.T) {
+	s := NewServer(fakeToolSet{}, Options{Name: "MCP Any", Version: "1.2.3", Path: "/a2a"})
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://mcpany.test/.well-known/agent-card.json", nil)
+	s.ServeHTTP(rec, req.WithContext(auth.ContextWithProfileID(req.Context(), "support")))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var card AgentCard
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &card))
+	assert.Equal(t, "http://mcpany.test/a2a", card.URL)
+	assert.Equal(t, ProtocolVersion, card.ProtocolVersion)
+	assert.Equal(t, "1.2.3", card.Version)
+	require.Len(t, card.Skills, 2, "the profile cannot use the billing service")
+	assert.Equal(t, AgentSkill{ID: "weather.get_forecast", Name: "weather.get_forecast", Description: "The get_forecast tool", Tags: []string{"weather"}, InputModes: []string{"application/json"}}, card.Skills[0])
+}
+
+func TestServer_SendMessage(t *testing.T) {
+	s := NewServer(fakeToolSet{}, Options{Path: "/a2a"})
+	ctx := context.Background()
+
+	got := task(t, call(t, s, ctx, "message/send", message("weather.get_forecast", Part{Kind: "data", Data: json.RawMessage(`{"city": "Oslo"}`)})))
+	assert.Equal(t, TaskStateCompleted, got.Status.State)
+	assert.Equal(t, "task", got.Kind)
+	assert.NotEmpty(t, got.ContextID)
+	require.Len(t, got.Artifacts, 1)
+	require.Len(t, got.Artifacts[0].Parts, 2)
+	assert.Equal(t, textPart("Sunny in Oslo"), got.Artifacts[0].Parts[0])
+	assert.JSONEq(t, `{"city": "Oslo", "sky": "sunny"}`, string(got.Artifacts[0].Parts[1].Data))
+	require.Len(t, got.History, 1)
+	assert.Equal(t, got.ID, got.History[0].TaskID)
+
+	fetched := task(t, call(t, s, ctx, "tasks/get", map[string]any{"id": got.ID, "historyLength": 0}))
+	assert.Equal(t, TaskStateCompleted, fetched.Status.State)
+	assert.Empty(t, fetched.History)
+	res := call(t, s, auth.ContextWithUser(ctx, "mallory"), "tasks/get", map[string]any{"id": got.ID})
+	require.NotNil(t, res.Error)
+	assert.Equal(t, codeTaskNotFound, res.Error.Code, "the tasks of other users are not found")
+
+	// A text part holding a JSON object carries the arguments too.
+	got = task(t, call(t, s, ctx, "message/send", message("weather.get_forecast", textPart(`{"city": "Lima"}`))))
+	assert.Equal(t, TaskStateCompleted, got.Status.State)
+
+	got = task(t, call(t, s, ctx, "message/send", message("weather.get_forecast")))
+	assert.Equal(t, TaskStateFailed, got.Status.State)
+	require.NotNil(t, got.Status.Message)
+	assert.Equal(t, "agent", got.Status.Message.Role)
+	assert.Equal(t, []Part{textPart("city is required")}, got.Status.Message.Parts)
+
+	got = task(t, call(t, s, auth.ContextWithProfileID(ctx, "finance"), "message/send", message("billing.list_invoices")))
+	assert.Equal(t, TaskStateFailed, got.Status.State)
+	assert.Equal(t, []Part{textPart("Tool execution failed: upstream unavailable")}, got.Status.Message.Parts)
+
+	for _, tc := range []struct {
+		params any
+		code   int
+	}{
+		{message("billing.list_invoices"), codeInvalidParams},
+		{message(""), codeInvalidParams},
+		{message("weather.get_forecast", textPart("What is the weather in Oslo?")), codeInvalidParams},
+		{message("weather.get_forecast", Part{Kind: "file", File: &FileContent{URI: "https://example.com/a.pdf"}}), codeContentTypeNotAllowed},
+		{map[string]any{"message": Message{Role: "user", TaskID: "missing"}}, codeTaskNotFound},
+	} {
+		res := call(t, s, auth.ContextWithProfileID(ctx, "support"), "message/send", tc.params)
+		require.NotNil(t, res.Error, "%v", tc.params)
+		assert.Equal(t, tc.code, res.Error.Code, res.Error.Message)
+	}
+}
+
+func TestServer_TaskLifecycle(t *testing.T) {
+	s := NewServer(fakeToolSet{}, Options{Path: "/a2a"})
+	ctx := auth.ContextWithUser(context.Background(), "alice")
+
+	params := message("reports.build")
+	params["configuration"] = map[string]any{"blocking": false}
+	started := task(t, call(t, s, ctx, "message/send", params))
+	assert.Contains(t, []TaskState{TaskStateSubmitted, TaskStateWorking}, started.Status.State)
+
+	canceled := task(t, call(t, s, ctx, "tasks/cancel", map[string]any{"id": started.ID}))
+	assert.Equal(t, TaskStateCanceled, canceled.Status.State)
+	require.Eventually(t, func() bool {
+		entry, _ := s.lookup(ctx, started.ID)
+		select {
+		case <-entry.done:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, TaskStateCanceled, task(t, call(t, s, ctx, "tasks/get", map[string]any{"id": started.ID})).Status.State, "the canceled run does not fail the task")
+
+	res := call(t, s, ctx, "tasks/cancel", map[string]any{"id": started.ID})
+	require.NotNil(t, res.Error)
+	assert.Equal(t, codeTaskNotCancelable, res.Error.Code)
+
+	res = call(t, s, ctx, "message/stream", params)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, codeUnsupportedOperation, res.Error.Code)
+	res = call(t, s, ctx, "tasks/pushNotificationConfig/set", map[string]any{})
+	require.NotNil(t, res.Error)
+	assert.Equal(t, codePushNotSupported, res.Error.Code)
+	res = call(t, s, ctx, "agent/unknown", nil)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, codeMethodNotFound, res.Error.Code)
+}
+
+func TestServer_EvictsFinishedTasks(t *testing.T) {
+	s := NewServer(fakeToolSet{}, Options{TaskTTL: time.Millisecond})
+	ctx := context.Background()
+	first := task(t, call(t, s, ctx, "message/send", message("weather.get_forecast", textPart(`{"city": "Oslo"}`))))
+	time.Sleep(5 * time.Millisecond)
+	task(t, call(t, s, ctx, "message/send", message("weather.get_forecast", textPart(`{"city": "Rome"}`))))
+	res := call(t, s, ctx, "tasks/get", map[string]any{"id": first.ID})
+	require.NotNil(t, res.Error)
+	assert.Equal(t, codeTaskNotFound, res.Error.Code)
+}
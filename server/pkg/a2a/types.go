@@ -0,0 +1,119 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package a2a
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ProtocolVersion is the version of the A2A protocol the server implements.
+const ProtocolVersion = "0.3.0"
+
+// AgentCard describes the agent, its endpoint and its skills.
+type AgentCard struct {
+	ProtocolVersion    string            `json:"protocolVersion"`
+	Name               string            `json:"name"`
+	Description        string            `json:"description"`
+	URL                string            `json:"url"`
+	PreferredTransport string            `json:"preferredTransport"`
+	Version            string            `json:"version"`
+	Capabilities       AgentCapabilities `json:"capabilities"`
+	DefaultInputModes  []string          `json:"defaultInputModes"`
+	DefaultOutputModes []string          `json:"defaultOutputModes"`
+	Skills             []AgentSkill      `json:"skills"`
+}
+
+// AgentCapabilities lists the optional protocol features of the agent.
+type AgentCapabilities struct {
+	Streaming              bool `json:"streaming"`
+	PushNotifications      bool `json:"pushNotifications"`
+	StateTransitionHistory bool `json:"stateTransitionHistory"`
+}
+
+// AgentSkill is a capability of the agent: an MCP tool.
+type AgentSkill struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	InputModes  []string `json:"inputModes,omitempty"`
+	OutputModes []string `json:"outputModes,omitempty"`
+}
+
+// TaskState is the state of a task.
+type TaskState string
+
+const (
+	// TaskStateSubmitted is the state of a task waiting to run.
+	TaskStateSubmitted TaskState = "submitted"
+	// TaskStateWorking is the state of a running task.
+	TaskStateWorking TaskState = "working"
+	// TaskStateCompleted is the state of a task whose tool returned.
+	TaskStateCompleted TaskState = "completed"
+	// TaskStateFailed is the state of a task whose tool failed.
+	TaskStateFailed TaskState = "failed"
+	// TaskStateCanceled is the state of a task canceled by the client.
+	TaskStateCanceled TaskState = "canceled"
+)
+
+// Terminal reports whether a task in the state is done.
+//
+// Returns:
+//   - bool: True for completed, failed and canceled tasks.
+func (s TaskState) Terminal() bool {
+	return s == TaskStateCompleted || s == TaskStateFailed || s == TaskStateCanceled
+}
+
+// Task is the unit of work delegated to the agent: one tool call.
+type Task struct {
+	ID        string         `json:"id"`
+	ContextID string         `json:"contextId"`
+	Status    TaskStatus     `json:"status"`
+	Artifacts []Artifact     `json:"artifacts,omitempty"`
+	History   []Message      `json:"history,omitempty"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+	Kind      string         `json:"kind"`
+}
+
+// TaskStatus is the state of a task, with the message of the agent about it.
+type TaskStatus struct {
+	State     TaskState `json:"state"`
+	Message   *Message  `json:"message,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Message is a turn of the conversation between the client and the agent.
+type Message struct {
+	Role      string         `json:"role"`
+	Parts     []Part         `json:"parts"`
+	MessageID string         `json:"messageId"`
+	TaskID    string         `json:"taskId,omitempty"`
+	ContextID string         `json:"contextId,omitempty"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+	Kind      string         `json:"kind"`
+}
+
+// Part is a piece of content: text, structured data or a file.
+type Part struct {
+	Kind string          `json:"kind"`
+	Text string          `json:"text,omitempty"`
+	Data json.RawMessage `json:"data,omitempty"`
+	File *FileContent    `json:"file,omitempty"`
+}
+
+// FileContent is the content of a file part, inline or by URI.
+type FileContent struct {
+	Name     string `json:"name,omitempty"`
+	MIMEType string `json:"mimeType,omitempty"`
+	Bytes    []byte `json:"bytes,omitempty"`
+	URI      string `json:"uri,omitempty"`
+}
+
+// Artifact is an output of a task.
+type Artifact struct {
+	ArtifactID string `json:"artifactId"`
+	Name       string `json:"name,omitempty"`
+	Parts      []Part `json:"parts"`
+}
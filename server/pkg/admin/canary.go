@@ -0,0 +1,137 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pb "github.com/mcpany/core/proto/admin/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/mcpany/core/server/pkg/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// ListCanaries lists the canary rollouts with the error rates of their
+// canaries.
+//
+// Parameters:
+//   - _ (context.Context): The context for the request.
+//   - _ (*pb.ListCanariesRequest): The request object.
+//
+// Returns:
+//   - *pb.ListCanariesResponse: The rollouts, sorted by stable service.
+//   - error: Unimplemented if the tool manager has no canary rollouts.
+func (s *Server) ListCanaries(_ context.Context, _ *pb.ListCanariesRequest) (*pb.ListCanariesResponse, error) {
+	controller, err := s.canaryController()
+	if err != nil {
+		return nil, err
+	}
+	list := controller.ListCanaries()
+	canaries := make([]*pb.CanaryStatus, 0, len(list))
+	for _, st := range list {
+		canaries = append(canaries, canaryStatus(st))
+	}
+	return pb.ListCanariesResponse_builder{Canaries: canaries}.Build(), nil
+}
+
+// SetCanaryWeight sets the percentage of the calls of a stable service routed
+// to its canary. It clears a rollback and starts a new window.
+//
+// Parameters:
+//   - _ (context.Context): The context for the request.
+//   - req (*pb.SetCanaryWeightRequest): The request object.
+//
+// Returns:
+//   - *pb.SetCanaryWeightResponse: The rollout after the change.
+//   - error: NotFound if the service has no canary, and InvalidArgument if
+//     the weight is missing or out of range.
+//
+// Side Effects:
+//   - Changes the routing of the calls until the next change of the
+//     configuration of the canary.
+func (s *Server) SetCanaryWeight(_ context.Context, req *pb.SetCanaryWeightRequest) (*pb.SetCanaryWeightResponse, error) {
+	if !req.HasWeight() {
+		return nil, status.Error(codes.InvalidArgument, "weight is required")
+	}
+	st, err := s.steerCanary(req.GetStableService(), req.GetWeight(), "weight")
+	if err != nil {
+		return nil, err
+	}
+	return pb.SetCanaryWeightResponse_builder{Canary: st}.Build(), nil
+}
+
+// RollbackCanary routes all the calls of a stable service back to it.
+//
+// Parameters:
+//   - _ (context.Context): The context for the request.
+//   - req (*pb.RollbackCanaryRequest): The request object.
+//
+// Returns:
+//   - *pb.RollbackCanaryResponse: The rollout after the rollback.
+//   - error: NotFound if the service has no canary.
+//
+// Side Effects:
+//   - Changes the routing of the calls until the next change of the
+//     configuration of the canary.
+func (s *Server) RollbackCanary(_ context.Context, req *pb.RollbackCanaryRequest) (*pb.RollbackCanaryResponse, error) {
+	st, err := s.steerCanary(req.GetStableService(), 0, "rollback")
+	if err != nil {
+		return nil, err
+	}
+	return pb.RollbackCanaryResponse_builder{Canary: st}.Build(), nil
+}
+
+// steerCanary sets the weight of the canary of a stable service.
+func (s *Server) steerCanary(service string, weight int32, action string) (*pb.CanaryStatus, error) {
+	controller, err := s.canaryController()
+	if err != nil {
+		return nil, err
+	}
+	serviceID, err := util.SanitizeServiceName(service)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid service name")
+	}
+	st, err := controller.SetCanaryWeight(serviceID, weight)
+	switch {
+	case errors.Is(err, tool.ErrCanaryNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	logging.GetLogger().Info("Canary changed through the admin API", "service", serviceID, "action", action, "weight", weight)
+	return canaryStatus(st), nil
+}
+
+// canaryController returns the canary controller of the tool manager.
+func (s *Server) canaryController() (tool.CanaryController, error) {
+	controller, ok := s.toolManager.(tool.CanaryController)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "canary rollouts are not supported by the tool manager")
+	}
+	return controller, nil
+}
+
+// canaryStatus converts the state of a canary rollout.
+func canaryStatus(st tool.CanaryStatus) *pb.CanaryStatus {
+	b := pb.CanaryStatus_builder{
+		StableService:    proto.String(st.StableService),
+		CanaryService:    proto.String(st.CanaryService),
+		Weight:           proto.Int32(st.Weight),
+		ConfiguredWeight: proto.Int32(st.ConfiguredWeight),
+		MaxErrorRate:     proto.Float64(st.MaxErrorRate),
+		Calls:            proto.Int64(int64(st.Calls)),
+		Errors:           proto.Int64(int64(st.Errors)),
+		ErrorRate:        proto.Float64(st.ErrorRate),
+		RolledBack:       proto.Bool(st.RolledBack),
+	}
+	if st.RolledBackAt != nil {
+		b.RolledBackAt = proto.String(st.RolledBackAt.UTC().Format(time.RFC3339))
+	}
+	return b.Build()
+}
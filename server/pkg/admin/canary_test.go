@@ -0,0 +1,59 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/mcpany/core/proto/admin/v1"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestServer_Canaries(t *testing.T) {
+	ctx := context.Background()
+	tm := tool.NewManager(nil)
+	tm.AddServiceInfo("weather-v2", &tool.ServiceInfo{
+		Name: "weather-v2",
+		Config: configv1.UpstreamServiceConfig_builder{
+			Name: proto.String("weather-v2"),
+			Canary: configv1.CanaryConfig_builder{
+				StableService: proto.String("weather"),
+				Weight:        proto.Int32(5),
+			}.Build(),
+		}.Build(),
+	})
+	s := NewServer(nil, tm, nil, nil, nil, nil)
+
+	list, err := s.ListCanaries(ctx, &pb.ListCanariesRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.GetCanaries(), 1)
+	assert.Equal(t, "weather-v2", list.GetCanaries()[0].GetCanaryService())
+	assert.Equal(t, int32(5), list.GetCanaries()[0].GetWeight())
+
+	set, err := s.SetCanaryWeight(ctx, pb.SetCanaryWeightRequest_builder{StableService: proto.String("weather"), Weight: proto.Int32(50)}.Build())
+	require.NoError(t, err)
+	assert.Equal(t, int32(50), set.GetCanary().GetWeight())
+	assert.Equal(t, int32(5), set.GetCanary().GetConfiguredWeight())
+
+	rollback, err := s.RollbackCanary(ctx, pb.RollbackCanaryRequest_builder{StableService: proto.String("weather")}.Build())
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), rollback.GetCanary().GetWeight())
+
+	_, err = s.SetCanaryWeight(ctx, pb.SetCanaryWeightRequest_builder{StableService: proto.String("weather"), Weight: proto.Int32(150)}.Build())
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.SetCanaryWeight(ctx, pb.SetCanaryWeightRequest_builder{StableService: proto.String("weather")}.Build())
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "the weight is required")
+	_, err = s.RollbackCanary(ctx, pb.RollbackCanaryRequest_builder{StableService: proto.String("github")}.Build())
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = NewServer(nil, nil, nil, nil, nil, nil).ListCanaries(ctx, &pb.ListCanariesRequest{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
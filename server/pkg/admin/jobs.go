@@ -0,0 +1,124 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package admin
+
+import (
+	"context"
+
+	pb "github.com/mcpany/core/proto/admin/v1"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/tool"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListWorkerJobs lists the jobs of the asynchronous tools of all users,
+// newest first. They carry the arguments and results of the calls.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - req (*pb.ListWorkerJobsRequest): The request object.
+//
+// Returns:
+//   - *pb.ListWorkerJobsResponse: The jobs.
+//   - error: InvalidArgument if the status or limit is invalid, and
+//     FailedPrecondition without a storage backend.
+func (s *Server) ListWorkerJobs(ctx context.Context, req *pb.ListWorkerJobsRequest) (*pb.ListWorkerJobsResponse, error) {
+	if err := s.requireJobStorage(); err != nil {
+		return nil, err
+	}
+	switch tool.JobStatus(req.GetStatus()) {
+	case "", tool.JobPending, tool.JobRunning, tool.JobSucceeded, tool.JobFailed:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid status %q", req.GetStatus())
+	}
+	limit := int(req.GetLimit())
+	if limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+
+	// The filter applies before the limit
+	storeLimit := limit
+	if req.GetStatus() != "" {
+		storeLimit = 0
+	}
+	jobs, err := s.storage.ListWorkerJobs(ctx, storeLimit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list worker jobs: %v", err)
+	}
+	kept := make([]*configv1.WorkerJob, 0, len(jobs))
+	for _, job := range jobs {
+		if req.GetStatus() != "" && job.GetStatus() != req.GetStatus() {
+			continue
+		}
+		if limit > 0 && len(kept) == limit {
+			break
+		}
+		kept = append(kept, job)
+	}
+	return pb.ListWorkerJobsResponse_builder{Jobs: kept}.Build(), nil
+}
+
+// GetWorkerJob returns a job of an asynchronous tool by ID.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - req (*pb.GetWorkerJobRequest): The request object.
+//
+// Returns:
+//   - *pb.GetWorkerJobResponse: The job.
+//   - error: NotFound if there is no such job, and FailedPrecondition without
+//     a storage backend.
+func (s *Server) GetWorkerJob(ctx context.Context, req *pb.GetWorkerJobRequest) (*pb.GetWorkerJobResponse, error) {
+	if err := s.requireJobStorage(); err != nil {
+		return nil, err
+	}
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	job, err := s.storage.GetWorkerJob(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get worker job: %v", err)
+	}
+	if job == nil {
+		return nil, status.Error(codes.NotFound, tool.ErrJobNotFound.Error())
+	}
+	return pb.GetWorkerJobResponse_builder{Job: job}.Build(), nil
+}
+
+// DeleteWorkerJob discards a job of an asynchronous tool. A job that is not
+// done runs on, but its result is no longer kept.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - req (*pb.DeleteWorkerJobRequest): The request object.
+//
+// Returns:
+//   - *pb.DeleteWorkerJobResponse: The empty response.
+//   - error: An error if the storage fails, and FailedPrecondition without a
+//     storage backend.
+//
+// Side Effects:
+//   - Deletes the job from the storage.
+func (s *Server) DeleteWorkerJob(ctx context.Context, req *pb.DeleteWorkerJobRequest) (*pb.DeleteWorkerJobResponse, error) {
+	if err := s.requireJobStorage(); err != nil {
+		return nil, err
+	}
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	if err := s.storage.DeleteWorkerJob(ctx, req.GetId()); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete worker job: %v", err)
+	}
+	return &pb.DeleteWorkerJobResponse{}, nil
+}
+
+// requireJobStorage rejects the job requests of a server without storage,
+// whose jobs are only held by its tool manager.
+func (s *Server) requireJobStorage() error {
+	if s.storage == nil {
+		return status.Error(codes.FailedPrecondition, "the worker jobs require a storage backend")
+	}
+	return nil
+}
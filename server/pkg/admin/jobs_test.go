@@ -0,0 +1,81 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/mcpany/core/proto/admin/v1"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestServer_WorkerJobs(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	for _, j := range []struct{ id, status, createdAt string }{
+		{"job-1", "succeeded", "2026-01-01T00:00:00.000Z"},
+		{"job-2", "running", "2026-01-02T00:00:00.000Z"},
+		{"job-3", "pending", "2026-01-03T00:00:00.000Z"},
+	} {
+		require.NoError(t, store.SaveWorkerJob(ctx, configv1.WorkerJob_builder{
+			Id:        proto.String(j.id),
+			ServiceId: proto.String("reports"),
+			ToolName:  proto.String("reports.build_report"),
+			Status:    proto.String(j.status),
+			Owner:     proto.String("alice"),
+			CreatedAt: proto.String(j.createdAt),
+		}.Build()))
+	}
+	s := NewServer(nil, nil, nil, store, nil, nil)
+
+	t.Run("list", func(t *testing.T) {
+		resp, err := s.ListWorkerJobs(ctx, pb.ListWorkerJobsRequest_builder{Limit: proto.Int32(2)}.Build())
+		require.NoError(t, err)
+		require.Len(t, resp.GetJobs(), 2)
+		assert.Equal(t, "job-3", resp.GetJobs()[0].GetId(), "the newest job is listed first")
+
+		resp, err = s.ListWorkerJobs(ctx, pb.ListWorkerJobsRequest_builder{Status: proto.String("succeeded"), Limit: proto.Int32(1)}.Build())
+		require.NoError(t, err)
+		require.Len(t, resp.GetJobs(), 1)
+		assert.Equal(t, "job-1", resp.GetJobs()[0].GetId(), "the status filters before the limit")
+
+		_, err = s.ListWorkerJobs(ctx, pb.ListWorkerJobsRequest_builder{Limit: proto.Int32(-1)}.Build())
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		_, err = s.ListWorkerJobs(ctx, pb.ListWorkerJobsRequest_builder{Status: proto.String("lost")}.Build())
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("get", func(t *testing.T) {
+		resp, err := s.GetWorkerJob(ctx, pb.GetWorkerJobRequest_builder{Id: proto.String("job-2")}.Build())
+		require.NoError(t, err)
+		assert.Equal(t, "running", resp.GetJob().GetStatus())
+		assert.Equal(t, "reports.build_report", resp.GetJob().GetToolName())
+
+		_, err = s.GetWorkerJob(ctx, pb.GetWorkerJobRequest_builder{Id: proto.String("unknown")}.Build())
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, err := s.DeleteWorkerJob(ctx, pb.DeleteWorkerJobRequest_builder{Id: proto.String("job-1")}.Build())
+		require.NoError(t, err)
+		job, err := store.GetWorkerJob(ctx, "job-1")
+		require.NoError(t, err)
+		assert.Nil(t, job)
+
+		_, err = s.DeleteWorkerJob(ctx, &pb.DeleteWorkerJobRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("without storage", func(t *testing.T) {
+		_, err := NewServer(nil, nil, nil, nil, nil, nil).ListWorkerJobs(ctx, &pb.ListWorkerJobsRequest{})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
@@ -0,0 +1,364 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package admin
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/mcpany/core/proto/admin/v1"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/config"
+	"github.com/mcpany/core/server/pkg/expiry"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/secretusage"
+	"github.com/mcpany/core/server/pkg/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// redactedSecretValue replaces the value of the secrets the API returns.
+const redactedSecretValue = "[REDACTED]"
+
+// reload reloads the configuration after a change to the stored profiles or
+// secrets. A failed reload is logged: the change is stored either way.
+func (s *Server) reload(ctx context.Context, what string) {
+	if s.reloadConfig == nil {
+		return
+	}
+	if err := s.reloadConfig(ctx); err != nil {
+		logging.GetLogger().Error("failed to reload config after "+what, "error", err)
+	}
+}
+
+// safeProfile returns a copy of a profile without its secrets.
+func safeProfile(profile *configv1.ProfileDefinition) *configv1.ProfileDefinition {
+	safe := proto.Clone(profile).(*configv1.ProfileDefinition)
+	config.StripSecretsFromProfile(safe)
+	return safe
+}
+
+// redactSecret returns a copy of a secret without its value.
+func redactSecret(secret *configv1.Secret) *configv1.Secret {
+	safe := proto.Clone(secret).(*configv1.Secret)
+	safe.SetValue(redactedSecretValue)
+	return safe
+}
+
+// ListProfiles lists the stored profiles, without their secrets.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - _ (*pb.ListProfilesRequest): The request object.
+//
+// Returns:
+//   - *pb.ListProfilesResponse: The profiles.
+//   - error: An error if the storage fails.
+func (s *Server) ListProfiles(ctx context.Context, _ *pb.ListProfilesRequest) (*pb.ListProfilesResponse, error) {
+	profiles, err := s.storage.ListProfiles(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list profiles: %v", err)
+	}
+	safe := make([]*configv1.ProfileDefinition, 0, len(profiles))
+	for _, p := range profiles {
+		safe = append(safe, safeProfile(p))
+	}
+	return pb.ListProfilesResponse_builder{Profiles: safe}.Build(), nil
+}
+
+// GetProfile returns a stored profile by name, without its secrets.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - req (*pb.GetProfileRequest): The request object.
+//
+// Returns:
+//   - *pb.GetProfileResponse: The profile.
+//   - error: NotFound if there is no such profile.
+func (s *Server) GetProfile(ctx context.Context, req *pb.GetProfileRequest) (*pb.GetProfileResponse, error) {
+	profile, err := s.storage.GetProfile(ctx, req.GetName())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get profile: %v", err)
+	}
+	if profile == nil {
+		return nil, status.Error(codes.NotFound, "profile not found")
+	}
+	return pb.GetProfileResponse_builder{Profile: safeProfile(profile)}.Build(), nil
+}
+
+// SaveProfile creates or replaces a profile, and reloads the configuration.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - req (*pb.SaveProfileRequest): The request object.
+//
+// Returns:
+//   - *pb.SaveProfileResponse: The saved profile, without its secrets.
+//   - error: InvalidArgument if the profile has no name.
+//
+// Side Effects:
+//   - Stores the profile and reloads the configuration.
+func (s *Server) SaveProfile(ctx context.Context, req *pb.SaveProfileRequest) (*pb.SaveProfileResponse, error) {
+	profile := req.GetProfile()
+	if profile.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "profile name is required")
+	}
+	if err := s.storage.SaveProfile(ctx, profile); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save profile: %v", err)
+	}
+	s.reload(ctx, "profile save")
+	return pb.SaveProfileResponse_builder{Profile: safeProfile(profile)}.Build(), nil
+}
+
+// DeleteProfile deletes a profile by name, and reloads the configuration.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - req (*pb.DeleteProfileRequest): The request object.
+//
+// Returns:
+//   - *pb.DeleteProfileResponse: The empty response.
+//   - error: An error if the storage fails.
+//
+// Side Effects:
+//   - Deletes the profile and reloads the configuration.
+func (s *Server) DeleteProfile(ctx context.Context, req *pb.DeleteProfileRequest) (*pb.DeleteProfileResponse, error) {
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	if err := s.storage.DeleteProfile(ctx, req.GetName()); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete profile: %v", err)
+	}
+	s.reload(ctx, "profile delete")
+	return &pb.DeleteProfileResponse{}, nil
+}
+
+// ListCredentials lists the stored credentials, without their secrets.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - _ (*pb.ListCredentialsRequest): The request object.
+//
+// Returns:
+//   - *pb.ListCredentialsResponse: The credentials.
+//   - error: An error if the storage fails.
+func (s *Server) ListCredentials(ctx context.Context, _ *pb.ListCredentialsRequest) (*pb.ListCredentialsResponse, error) {
+	creds, err := s.storage.ListCredentials(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list credentials: %v", err)
+	}
+	safe := make([]*configv1.Credential, 0, len(creds))
+	for _, c := range creds {
+		safe = append(safe, util.SanitizeCredential(c))
+	}
+	return pb.ListCredentialsResponse_builder{Credentials: safe}.Build(), nil
+}
+
+// GetCredential returns a stored credential by ID, without its secrets.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - req (*pb.GetCredentialRequest): The request object.
+//
+// Returns:
+//   - *pb.GetCredentialResponse: The credential.
+//   - error: NotFound if there is no such credential.
+func (s *Server) GetCredential(ctx context.Context, req *pb.GetCredentialRequest) (*pb.GetCredentialResponse, error) {
+	cred, err := s.storage.GetCredential(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get credential: %v", err)
+	}
+	if cred == nil {
+		return nil, status.Error(codes.NotFound, "credential not found")
+	}
+	return pb.GetCredentialResponse_builder{Credential: util.SanitizeCredential(cred)}.Build(), nil
+}
+
+// SaveCredential creates or replaces a credential. A new credential, or one
+// whose authentication changed, is stamped as rotated now.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - req (*pb.SaveCredentialRequest): The request object.
+//
+// Returns:
+//   - *pb.SaveCredentialResponse: The saved credential, without its secrets.
+//   - error: InvalidArgument if the credential has no ID or invalid timestamps.
+//
+// Side Effects:
+//   - Stores the credential.
+func (s *Server) SaveCredential(ctx context.Context, req *pb.SaveCredentialRequest) (*pb.SaveCredentialResponse, error) {
+	cred := req.GetCredential()
+	if cred.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "credential id is required")
+	}
+	existing, err := s.storage.GetCredential(ctx, cred.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get credential: %v", err)
+	}
+	if err := expiry.StampRotation(cred, existing, time.Now()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.storage.SaveCredential(ctx, cred); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save credential: %v", err)
+	}
+	return pb.SaveCredentialResponse_builder{Credential: util.SanitizeCredential(cred)}.Build(), nil
+}
+
+// DeleteCredential deletes a credential by ID.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - req (*pb.DeleteCredentialRequest): The request object.
+//
+// Returns:
+//   - *pb.DeleteCredentialResponse: The empty response.
+//   - error: An error if the storage fails.
+//
+// Side Effects:
+//   - Deletes the credential.
+func (s *Server) DeleteCredential(ctx context.Context, req *pb.DeleteCredentialRequest) (*pb.DeleteCredentialResponse, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	if err := s.storage.DeleteCredential(ctx, req.GetId()); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete credential: %v", err)
+	}
+	return &pb.DeleteCredentialResponse{}, nil
+}
+
+// ListSecrets lists the stored secrets, with their values redacted.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - _ (*pb.ListSecretsRequest): The request object.
+//
+// Returns:
+//   - *pb.ListSecretsResponse: The secrets.
+//   - error: An error if the storage fails.
+func (s *Server) ListSecrets(ctx context.Context, _ *pb.ListSecretsRequest) (*pb.ListSecretsResponse, error) {
+	secrets, err := s.storage.ListSecrets(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list secrets: %v", err)
+	}
+	safe := make([]*configv1.Secret, 0, len(secrets))
+	for _, secret := range secrets {
+		safe = append(safe, redactSecret(secret))
+	}
+	return pb.ListSecretsResponse_builder{Secrets: safe}.Build(), nil
+}
+
+// GetSecret returns a stored secret by ID, with its value redacted.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - req (*pb.GetSecretRequest): The request object.
+//
+// Returns:
+//   - *pb.GetSecretResponse: The secret.
+//   - error: NotFound if there is no such secret.
+func (s *Server) GetSecret(ctx context.Context, req *pb.GetSecretRequest) (*pb.GetSecretResponse, error) {
+	secret, err := s.getSecret(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return pb.GetSecretResponse_builder{Secret: redactSecret(secret)}.Build(), nil
+}
+
+// SaveSecret creates or replaces a secret, and reloads the configuration.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - req (*pb.SaveSecretRequest): The request object.
+//
+// Returns:
+//   - *pb.SaveSecretResponse: The saved secret, with its value redacted.
+//   - error: InvalidArgument if the secret has no ID.
+//
+// Side Effects:
+//   - Stores the secret and reloads the configuration.
+func (s *Server) SaveSecret(ctx context.Context, req *pb.SaveSecretRequest) (*pb.SaveSecretResponse, error) {
+	secret := req.GetSecret()
+	if secret.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "secret id is required")
+	}
+	if secret.GetName() == "" {
+		secret.SetName(secret.GetId())
+	}
+	if err := s.storage.SaveSecret(ctx, secret); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save secret: %v", err)
+	}
+	s.reload(ctx, "secret save")
+	return pb.SaveSecretResponse_builder{Secret: redactSecret(secret)}.Build(), nil
+}
+
+// DeleteSecret deletes a secret by ID, and reloads the configuration.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - req (*pb.DeleteSecretRequest): The request object.
+//
+// Returns:
+//   - *pb.DeleteSecretResponse: The empty response.
+//   - error: An error if the storage fails.
+//
+// Side Effects:
+//   - Deletes the secret and reloads the configuration.
+func (s *Server) DeleteSecret(ctx context.Context, req *pb.DeleteSecretRequest) (*pb.DeleteSecretResponse, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	if err := s.storage.DeleteSecret(ctx, req.GetId()); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete secret: %v", err)
+	}
+	s.reload(ctx, "secret delete")
+	return &pb.DeleteSecretResponse{}, nil
+}
+
+// RevealSecret returns the value of a secret to a caller with one of its
+// allowed roles, and records the read in the usage of the secret.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - req (*pb.RevealSecretRequest): The request object.
+//
+// Returns:
+//   - *pb.RevealSecretResponse: The value of the secret.
+//   - error: NotFound if there is no such secret, PermissionDenied if the
+//     caller may not reveal it.
+//
+// Side Effects:
+//   - Records the read in the stored secret.
+func (s *Server) RevealSecret(ctx context.Context, req *pb.RevealSecretRequest) (*pb.RevealSecretResponse, error) {
+	secret, err := s.getSecret(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	user, _ := auth.UserFromContext(ctx)
+	if !secretusage.CanReveal(ctx, secret) {
+		logging.GetLogger().Warn("Secret reveal denied", "id", req.GetId(), "user", user)
+		return nil, status.Error(codes.PermissionDenied, "not allowed to reveal this secret")
+	}
+
+	logging.GetLogger().Info("Secret revealed", "id", req.GetId(), "user", user)
+	secretusage.RecordRead(secret, secretusage.AdminConsumer(user), time.Now())
+	if err := s.storage.SaveSecret(ctx, secret); err != nil {
+		logging.GetLogger().Warn("failed to record secret usage", "id", req.GetId(), "error", err)
+	}
+	return pb.RevealSecretResponse_builder{Value: proto.String(secret.GetValue())}.Build(), nil
+}
+
+// getSecret returns a stored secret, or a NotFound status.
+func (s *Server) getSecret(ctx context.Context, id string) (*configv1.Secret, error) {
+	secret, err := s.storage.GetSecret(ctx, id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get secret: %v", err)
+	}
+	if secret == nil {
+		return nil, status.Error(codes.NotFound, "secret not found")
+	}
+	return secret, nil
+}
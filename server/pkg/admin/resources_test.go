@@ -0,0 +1,155 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/mcpany/core/proto/admin/v1"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func plainSecret(value string) *configv1.SecretValue {
+	return configv1.SecretValue_builder{PlainText: proto.String(value)}.Build()
+}
+
+func TestServer_ProfileManagement(t *testing.T) {
+	ctx := context.Background()
+	s := NewServer(nil, nil, nil, memory.NewStore(), nil, nil)
+	reloads := 0
+	s.SetConfigReloader(func(context.Context) error {
+		reloads++
+		return nil
+	})
+
+	profile := configv1.ProfileDefinition_builder{
+		Name:          proto.String("dev"),
+		RequiredRoles: []string{"developer"},
+		Secrets:       map[string]*configv1.SecretValue{"GITHUB_TOKEN": plainSecret("ghp_secret")},
+	}.Build()
+	saved, err := s.SaveProfile(ctx, pb.SaveProfileRequest_builder{Profile: profile}.Build())
+	require.NoError(t, err)
+	assert.Equal(t, 1, reloads)
+	assert.NotEqual(t, "ghp_secret", saved.GetProfile().GetSecrets()["GITHUB_TOKEN"].GetPlainText())
+
+	got, err := s.GetProfile(ctx, pb.GetProfileRequest_builder{Name: proto.String("dev")}.Build())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"developer"}, got.GetProfile().GetRequiredRoles())
+	assert.NotEqual(t, "ghp_secret", got.GetProfile().GetSecrets()["GITHUB_TOKEN"].GetPlainText(), "profile secrets are not returned")
+
+	list, err := s.ListProfiles(ctx, &pb.ListProfilesRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.GetProfiles(), 1)
+
+	_, err = s.DeleteProfile(ctx, pb.DeleteProfileRequest_builder{Name: proto.String("dev")}.Build())
+	require.NoError(t, err)
+	assert.Equal(t, 2, reloads)
+	_, err = s.GetProfile(ctx, pb.GetProfileRequest_builder{Name: proto.String("dev")}.Build())
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = s.SaveProfile(ctx, &pb.SaveProfileRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_CredentialManagement(t *testing.T) {
+	ctx := context.Background()
+	s := NewServer(nil, nil, nil, memory.NewStore(), nil, nil)
+
+	cred := configv1.Credential_builder{
+		Id:   proto.String("github"),
+		Name: proto.String("GitHub"),
+		Authentication: configv1.Authentication_builder{
+			BearerToken: configv1.BearerTokenAuth_builder{Token: plainSecret("ghp_secret")}.Build(),
+		}.Build(),
+	}.Build()
+	saved, err := s.SaveCredential(ctx, pb.SaveCredentialRequest_builder{Credential: cred}.Build())
+	require.NoError(t, err)
+	assert.NotEmpty(t, saved.GetCredential().GetRotatedAt(), "a new credential is stamped as rotated")
+	assert.NotEqual(t, "ghp_secret", saved.GetCredential().GetAuthentication().GetBearerToken().GetToken().GetPlainText())
+
+	got, err := s.GetCredential(ctx, pb.GetCredentialRequest_builder{Id: proto.String("github")}.Build())
+	require.NoError(t, err)
+	assert.Equal(t, "GitHub", got.GetCredential().GetName())
+	assert.NotEqual(t, "ghp_secret", got.GetCredential().GetAuthentication().GetBearerToken().GetToken().GetPlainText())
+
+	list, err := s.ListCredentials(ctx, &pb.ListCredentialsRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.GetCredentials(), 1)
+
+	invalid := configv1.Credential_builder{Id: proto.String("github"), ExpiresAt: proto.String("next week")}.Build()
+	_, err = s.SaveCredential(ctx, pb.SaveCredentialRequest_builder{Credential: invalid}.Build())
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.DeleteCredential(ctx, pb.DeleteCredentialRequest_builder{Id: proto.String("github")}.Build())
+	require.NoError(t, err)
+	_, err = s.GetCredential(ctx, pb.GetCredentialRequest_builder{Id: proto.String("github")}.Build())
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestServer_SecretManagement(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	s := NewServer(nil, nil, nil, store, nil, nil)
+	reloads := 0
+	s.SetConfigReloader(func(context.Context) error {
+		reloads++
+		return nil
+	})
+
+	secret := configv1.Secret_builder{
+		Id:           proto.String("openai"),
+		Key:          proto.String("OPENAI_API_KEY"),
+		Value:        proto.String("sk-secret"),
+		AllowedRoles: []string{"ml"},
+	}.Build()
+	saved, err := s.SaveSecret(ctx, pb.SaveSecretRequest_builder{Secret: secret}.Build())
+	require.NoError(t, err)
+	assert.Equal(t, 1, reloads)
+	assert.Equal(t, "openai", saved.GetSecret().GetName(), "the name defaults to the ID")
+	assert.Equal(t, redactedSecretValue, saved.GetSecret().GetValue())
+
+	list, err := s.ListSecrets(ctx, &pb.ListSecretsRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.GetSecrets(), 1)
+	assert.Equal(t, redactedSecretValue, list.GetSecrets()[0].GetValue())
+
+	got, err := s.GetSecret(ctx, pb.GetSecretRequest_builder{Id: proto.String("openai")}.Build())
+	require.NoError(t, err)
+	assert.Equal(t, redactedSecretValue, got.GetSecret().GetValue())
+
+	t.Run("reveal", func(t *testing.T) {
+		req := pb.RevealSecretRequest_builder{Id: proto.String("openai")}.Build()
+
+		viewer := auth.ContextWithRoles(auth.ContextWithUser(ctx, "bob"), []string{"viewer"})
+		_, err := s.RevealSecret(viewer, req)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		ml := auth.ContextWithRoles(auth.ContextWithUser(ctx, "alice"), []string{"ml"})
+		revealed, err := s.RevealSecret(ml, req)
+		require.NoError(t, err)
+		assert.Equal(t, "sk-secret", revealed.GetValue())
+
+		stored, err := store.GetSecret(ctx, "openai")
+		require.NoError(t, err)
+		require.Len(t, stored.GetUsage(), 1)
+		assert.Equal(t, "admin-api:alice", stored.GetUsage()[0].GetConsumer())
+	})
+
+	_, err = s.DeleteSecret(ctx, pb.DeleteSecretRequest_builder{Id: proto.String("openai")}.Build())
+	require.NoError(t, err)
+	assert.Equal(t, 2, reloads)
+	_, err = s.RevealSecret(ctx, pb.RevealSecretRequest_builder{Id: proto.String("openai")}.Build())
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = s.SaveSecret(ctx, &pb.SaveSecretRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
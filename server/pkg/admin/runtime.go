@@ -0,0 +1,162 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package admin
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	pb "github.com/mcpany/core/proto/admin/v1"
+	"github.com/mcpany/core/server/pkg/resilience"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// CircuitBreakerSource lists the circuit breakers of the services, by the ID
+// of their service.
+type CircuitBreakerSource interface {
+	CircuitBreakers() map[string]*resilience.CircuitBreaker
+}
+
+// ListSessions lists the MCP sessions of the connected clients, with their
+// transport, identity and activity if a session tracker is set.
+//
+// Parameters:
+//   - _ (context.Context): The context for the request.
+//   - _ (*pb.ListSessionsRequest): The request object.
+//
+// Returns:
+//   - *pb.ListSessionsResponse: The sessions; none if no MCP server is set.
+//   - error: Always nil.
+func (s *Server) ListSessions(_ context.Context, _ *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	if s.mcpServer == nil {
+		return &pb.ListSessionsResponse{}, nil
+	}
+	var sessions []*pb.Session
+	for ss := range s.mcpServer.Sessions() {
+		session := pb.Session_builder{Id: proto.String(ss.ID())}
+		if params := ss.InitializeParams(); params != nil {
+			session.ProtocolVersion = proto.String(params.ProtocolVersion)
+			if params.ClientInfo != nil {
+				session.ClientName = proto.String(params.ClientInfo.Name)
+				session.ClientVersion = proto.String(params.ClientInfo.Version)
+			}
+		}
+		if s.sessionTracker != nil {
+			if info, ok := s.sessionTracker.Info(ss); ok {
+				session.Transport = proto.String(info.Transport)
+				session.UserId = proto.String(info.UserID)
+				session.ProfileId = proto.String(info.ProfileID)
+				session.RemoteAddr = proto.String(info.RemoteAddr)
+				session.StartedAt = proto.String(info.StartedAt.UTC().Format(time.RFC3339))
+				session.LastActiveAt = proto.String(info.LastActiveAt.UTC().Format(time.RFC3339))
+				session.RequestCount = proto.Int64(info.Requests)
+				session.ToolCallCount = proto.Int64(info.ToolCalls)
+				session.ErrorCount = proto.Int64(info.Errors)
+			}
+		}
+		sessions = append(sessions, session.Build())
+	}
+	sort.SliceStable(sessions, func(i, j int) bool { return sessions[i].GetStartedAt() < sessions[j].GetStartedAt() })
+	return pb.ListSessionsResponse_builder{Sessions: sessions}.Build(), nil
+}
+
+// CloseSession disconnects an MCP session. The client may start a new one.
+//
+// Parameters:
+//   - _ (context.Context): The context for the request.
+//   - req (*pb.CloseSessionRequest): The request object.
+//
+// Returns:
+//   - *pb.CloseSessionResponse: The empty response.
+//   - error: NotFound if there is no such session.
+//
+// Side Effects:
+//   - Closes the session.
+func (s *Server) CloseSession(_ context.Context, req *pb.CloseSessionRequest) (*pb.CloseSessionResponse, error) {
+	if req.GetSessionId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "session_id is required")
+	}
+	if s.mcpServer != nil {
+		for ss := range s.mcpServer.Sessions() {
+			if ss.ID() != req.GetSessionId() {
+				continue
+			}
+			if err := ss.Close(); err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to close session: %v", err)
+			}
+			return &pb.CloseSessionResponse{}, nil
+		}
+	}
+	return nil, status.Error(codes.NotFound, "session not found")
+}
+
+// ListCircuitBreakers lists the state of the circuit breakers, sorted by
+// service ID.
+//
+// Parameters:
+//   - _ (context.Context): The context for the request.
+//   - _ (*pb.ListCircuitBreakersRequest): The request object.
+//
+// Returns:
+//   - *pb.ListCircuitBreakersResponse: The circuit breakers.
+//   - error: Always nil.
+func (s *Server) ListCircuitBreakers(_ context.Context, _ *pb.ListCircuitBreakersRequest) (*pb.ListCircuitBreakersResponse, error) {
+	if s.circuitBreakers == nil {
+		return &pb.ListCircuitBreakersResponse{}, nil
+	}
+	breakers := s.circuitBreakers.CircuitBreakers()
+	ids := make([]string, 0, len(breakers))
+	for id := range breakers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	states := make([]*pb.CircuitBreakerState, 0, len(ids))
+	for _, id := range ids {
+		states = append(states, circuitBreakerState(id, breakers[id].Status()))
+	}
+	return pb.ListCircuitBreakersResponse_builder{CircuitBreakers: states}.Build(), nil
+}
+
+// ResetCircuitBreaker closes the circuit breaker of a service, so that its
+// calls are served again at once.
+//
+// Parameters:
+//   - _ (context.Context): The context for the request.
+//   - req (*pb.ResetCircuitBreakerRequest): The request object.
+//
+// Returns:
+//   - *pb.ResetCircuitBreakerResponse: The state of the breaker.
+//   - error: NotFound if the service has no circuit breaker.
+//
+// Side Effects:
+//   - Closes the breaker.
+func (s *Server) ResetCircuitBreaker(_ context.Context, req *pb.ResetCircuitBreakerRequest) (*pb.ResetCircuitBreakerResponse, error) {
+	var cb *resilience.CircuitBreaker
+	if s.circuitBreakers != nil {
+		cb = s.circuitBreakers.CircuitBreakers()[req.GetServiceId()]
+	}
+	if cb == nil {
+		return nil, status.Errorf(codes.NotFound, "no circuit breaker for service %q", req.GetServiceId())
+	}
+	cb.Reset()
+	return pb.ResetCircuitBreakerResponse_builder{
+		CircuitBreaker: circuitBreakerState(req.GetServiceId(), cb.Status()),
+	}.Build(), nil
+}
+
+// circuitBreakerState converts the status of a breaker.
+func circuitBreakerState(serviceID string, st resilience.Status) *pb.CircuitBreakerState {
+	state := pb.CircuitBreakerState_builder{
+		ServiceId:           proto.String(serviceID),
+		State:               proto.String(st.State.String()),
+		ConsecutiveFailures: proto.Int32(st.Failures),
+	}
+	if !st.OpenedAt.IsZero() {
+		state.OpenedAt = proto.String(st.OpenedAt.UTC().Format(time.RFC3339))
+	}
+	return state.Build()
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/mcpany/core/proto/admin/v1"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/mcpserver"
+	"github.com/mcpany/core/server/pkg/resilience"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type circuitBreakers map[string]*resilience.CircuitBreaker
+
+func (c circuitBreakers) CircuitBreakers() map[string]*resilience.CircuitBreaker {
+	return c
+}
+
+func TestServer_Sessions(t *testing.T) {
+	ctx := context.Background()
+	server := mcp.NewServer(&mcp.Implementation{Name: "mcpany"}, nil)
+	s := NewServer(nil, nil, nil, nil, nil, nil)
+
+	resp, err := s.ListSessions(ctx, &pb.ListSessionsRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, resp.GetSessions(), "no MCP server, no sessions")
+
+	s.SetMCPServer(server)
+	tracker := mcpserver.NewSessionTracker()
+	server.AddReceivingMiddleware(tracker.Middleware)
+	s.SetSessionTracker(tracker)
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := server.Connect(mcpserver.ContextWithTransport(auth.ContextWithUser(ctx, "alice"), mcpserver.TransportStdio), serverTransport, nil)
+	require.NoError(t, err)
+	client := mcp.NewClient(&mcp.Implementation{Name: "claude", Version: "1.2.3"}, nil)
+	cs, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer func() { _ = cs.Close() }()
+
+	resp, err = s.ListSessions(ctx, &pb.ListSessionsRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.GetSessions(), 1)
+	session := resp.GetSessions()[0]
+	assert.Equal(t, ss.ID(), session.GetId())
+	assert.Equal(t, "claude", session.GetClientName())
+	assert.Equal(t, "1.2.3", session.GetClientVersion())
+	assert.NotEmpty(t, session.GetProtocolVersion())
+	assert.Equal(t, mcpserver.TransportStdio, session.GetTransport())
+	assert.Equal(t, "alice", session.GetUserId())
+	assert.NotEmpty(t, session.GetStartedAt())
+	assert.Positive(t, session.GetRequestCount())
+
+	_, err = s.CloseSession(ctx, pb.CloseSessionRequest_builder{SessionId: proto.String("missing")}.Build())
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestServer_CircuitBreakers(t *testing.T) {
+	ctx := context.Background()
+	cb := resilience.NewCircuitBreaker(configv1.CircuitBreakerConfig_builder{
+		ConsecutiveFailures: proto.Int32(2),
+		OpenDuration:        durationpb.New(time.Hour),
+	}.Build())
+	s := NewServer(nil, nil, nil, nil, nil, nil)
+	s.SetCircuitBreakers(circuitBreakers{"github": cb})
+
+	fail := func(context.Context) error { return errors.New("upstream down") }
+	for range 2 {
+		_ = cb.Execute(ctx, fail)
+	}
+
+	resp, err := s.ListCircuitBreakers(ctx, &pb.ListCircuitBreakersRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.GetCircuitBreakers(), 1)
+	state := resp.GetCircuitBreakers()[0]
+	assert.Equal(t, "github", state.GetServiceId())
+	assert.Equal(t, "open", state.GetState())
+	assert.NotEmpty(t, state.GetOpenedAt())
+
+	reset, err := s.ResetCircuitBreaker(ctx, pb.ResetCircuitBreakerRequest_builder{ServiceId: proto.String("github")}.Build())
+	require.NoError(t, err)
+	assert.Equal(t, "closed", reset.GetCircuitBreaker().GetState())
+	assert.Zero(t, reset.GetCircuitBreaker().GetConsecutiveFailures())
+	assert.Empty(t, reset.GetCircuitBreaker().GetOpenedAt())
+	assert.NoError(t, cb.Execute(ctx, func(context.Context) error { return nil }), "calls are served again")
+
+	_, err = s.ResetCircuitBreaker(ctx, pb.ResetCircuitBreakerRequest_builder{ServiceId: proto.String("slack")}.Build())
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
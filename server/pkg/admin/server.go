@@ -7,21 +7,28 @@ package admin
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	pb "github.com/mcpany/core/proto/admin/v1"
 	configv1 "github.com/mcpany/core/proto/config/v1"
 	mcprouterv1 "github.com/mcpany/core/proto/mcp_router/v1"
 	"github.com/mcpany/core/server/pkg/audit"
+	"github.com/mcpany/core/server/pkg/auth"
 	"github.com/mcpany/core/server/pkg/config"
 	"github.com/mcpany/core/server/pkg/discovery"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/mcpserver"
 	"github.com/mcpany/core/server/pkg/middleware"
 	"github.com/mcpany/core/server/pkg/serviceregistry"
+	"github.com/mcpany/core/server/pkg/slo"
 	"github.com/mcpany/core/server/pkg/storage"
 	"github.com/mcpany/core/server/pkg/tool"
 	"github.com/mcpany/core/server/pkg/util/passhash"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
@@ -36,6 +43,16 @@ type Server struct {
 	storage          storage.Storage
 	discoveryManager *discovery.Manager
 	auditMiddleware  *middleware.AuditMiddleware
+	sloTracker       *slo.Tracker
+	apiKeyNotifier   auth.APIKeyNotifier
+	reloadConfig     func(context.Context) error
+	mcpServer        *mcp.Server
+	sessionTracker   *mcpserver.SessionTracker
+	circuitBreakers  CircuitBreakerSource
+
+	// disabledServicesMu serializes the updates of
+	// global_settings.disabled_services.
+	disabledServicesMu sync.Mutex
 }
 
 // NewServer creates a new Admin Server. cache manages the caching layer. toolManager is the toolManager. serviceRegistry is the registry of upstream services. storage provides the persistence layer. discoveryManager manages auto-discovery. auditMiddleware provides access to audit logs. Returns the result.
@@ -74,6 +91,75 @@ func NewServer(
 	}
 }
 
+// SetSLOTracker sets the tracker of the service level objectives.
+//
+// Parameters:
+//   - tracker (*slo.Tracker): The tracker; nil disables SLO status.
+//
+// Side Effects:
+//   - None
+func (s *Server) SetSLOTracker(tracker *slo.Tracker) {
+	s.sloTracker = tracker
+}
+
+// SetAPIKeyNotifier sets the notifier told about rotated API keys.
+//
+// Parameters:
+//   - notify (auth.APIKeyNotifier): The notifier; nil disables notifications.
+//
+// Side Effects:
+//   - None
+func (s *Server) SetAPIKeyNotifier(notify auth.APIKeyNotifier) {
+	s.apiKeyNotifier = notify
+}
+
+// SetConfigReloader sets the function that reloads the configuration after
+// the stored profiles or secrets change.
+//
+// Parameters:
+//   - reload (func(context.Context) error): The reloader; nil disables reloads.
+//
+// Side Effects:
+//   - None
+func (s *Server) SetConfigReloader(reload func(context.Context) error) {
+	s.reloadConfig = reload
+}
+
+// SetMCPServer sets the MCP server whose sessions are listed and closed.
+//
+// Parameters:
+//   - server (*mcp.Server): The server; nil lists no sessions.
+//
+// Side Effects:
+//   - None
+func (s *Server) SetMCPServer(server *mcp.Server) {
+	s.mcpServer = server
+}
+
+// SetSessionTracker sets the tracker of the identity and activity of the MCP
+// sessions.
+//
+// Parameters:
+//   - tracker (*mcpserver.SessionTracker): The tracker; nil lists the
+//     sessions without their identity and activity.
+//
+// Side Effects:
+//   - None
+func (s *Server) SetSessionTracker(tracker *mcpserver.SessionTracker) {
+	s.sessionTracker = tracker
+}
+
+// SetCircuitBreakers sets the source of the circuit breakers of the services.
+//
+// Parameters:
+//   - source (CircuitBreakerSource): The source; nil lists no breakers.
+//
+// Side Effects:
+//   - None
+func (s *Server) SetCircuitBreakers(source CircuitBreakerSource) {
+	s.circuitBreakers = source
+}
+
 // ClearCache clears the cache. ctx is the context for the request. _ is an unused parameter. Returns the response. Returns an error if the operation fails.
 //
 // Parameters:
@@ -426,6 +512,140 @@ func (s *Server) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb
 	return &pb.DeleteUserResponse{}, nil
 }
 
+// CreateApiKey issues a new per-client API key. The full key is only returned in this response.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - req (*pb.CreateApiKeyRequest): The request object.
+//
+// Returns:
+//   - *pb.CreateApiKeyResponse: The stored key record and the full key.
+//   - error: An error if the operation fails.
+//
+// Errors:
+//   - Returns InvalidArgument if the key attributes are missing or invalid.
+//
+// Side Effects:
+//   - Persists the key record to storage.
+func (s *Server) CreateApiKey(ctx context.Context, req *pb.CreateApiKeyRequest) (*pb.CreateApiKeyResponse, error) { //nolint:revive // Name is generated from the proto.
+	if !req.HasApiKey() {
+		return nil, status.Error(codes.InvalidArgument, "api_key is required")
+	}
+	record, key, err := auth.CreateClientAPIKey(ctx, s.storage, req.GetApiKey())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to create api key: %v", err)
+	}
+	return pb.CreateApiKeyResponse_builder{
+		ApiKey: safeAPIKey(record),
+		Key:    proto.String(key),
+	}.Build(), nil
+}
+
+// ListApiKeys lists all per-client API keys, including revoked ones.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - _ (*pb.ListApiKeysRequest): The _ parameter.
+//
+// Returns:
+//   - *pb.ListApiKeysResponse: The key records, without secret hashes.
+//   - error: An error if the operation fails.
+//
+// Errors:
+//   - Returns an error if storage read fails.
+//
+// Side Effects:
+//   - None
+func (s *Server) ListApiKeys(ctx context.Context, _ *pb.ListApiKeysRequest) (*pb.ListApiKeysResponse, error) { //nolint:revive // Name is generated from the proto.
+	keys, err := s.storage.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list api keys: %v", err)
+	}
+	safeKeys := make([]*configv1.ClientApiKey, 0, len(keys))
+	for _, k := range keys {
+		safeKeys = append(safeKeys, safeAPIKey(k))
+	}
+	return pb.ListApiKeysResponse_builder{ApiKeys: safeKeys}.Build(), nil
+}
+
+// RevokeApiKey revokes a per-client API key. Requests made with it are rejected from then on.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - req (*pb.RevokeApiKeyRequest): The request object.
+//
+// Returns:
+//   - *pb.RevokeApiKeyResponse: The revoked key record.
+//   - error: An error if the operation fails.
+//
+// Errors:
+//   - Returns NotFound if no key has the given ID.
+//
+// Side Effects:
+//   - Marks the key as revoked in storage.
+func (s *Server) RevokeApiKey(ctx context.Context, req *pb.RevokeApiKeyRequest) (*pb.RevokeApiKeyResponse, error) { //nolint:revive // Name is generated from the proto.
+	record, err := auth.RevokeClientAPIKey(ctx, s.storage, req.GetId())
+	if errors.Is(err, auth.ErrAPIKeyNotFound) {
+		return nil, status.Error(codes.NotFound, "api key not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke api key: %v", err)
+	}
+	return pb.RevokeApiKeyResponse_builder{ApiKey: safeAPIKey(record)}.Build(), nil
+}
+
+// RotateApiKey issues a replacement for a per-client API key. The replaced key stays valid
+// for the grace period. The full new key is only returned in this response.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - req (*pb.RotateApiKeyRequest): The request object.
+//
+// Returns:
+//   - *pb.RotateApiKeyResponse: The new key record, the full new key and the replaced key record.
+//   - error: An error if the operation fails.
+//
+// Errors:
+//   - Returns NotFound if no key has the given ID.
+//   - Returns FailedPrecondition if the key is revoked, expired or already rotated.
+//   - Returns InvalidArgument if the grace period is negative.
+//
+// Side Effects:
+//   - Persists both key records to storage and notifies the owner of the key.
+func (s *Server) RotateApiKey(ctx context.Context, req *pb.RotateApiKeyRequest) (*pb.RotateApiKeyResponse, error) { //nolint:revive // Name is generated from the proto.
+	grace := req.GetGracePeriod().AsDuration()
+	if grace < 0 {
+		return nil, status.Error(codes.InvalidArgument, "grace_period must not be negative")
+	}
+	rotation, err := auth.RotateClientAPIKey(ctx, s.storage, req.GetId(), grace)
+	switch {
+	case errors.Is(err, auth.ErrAPIKeyNotFound):
+		return nil, status.Error(codes.NotFound, "api key not found")
+	case errors.Is(err, auth.ErrAPIKeyRevoked), errors.Is(err, auth.ErrAPIKeyAlreadyRotated), errors.Is(err, auth.ErrReservedAPIKeyScope):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "failed to rotate api key: %v", err)
+	}
+	if s.apiKeyNotifier != nil {
+		// The caller receives the new key, so the owner is only told.
+		if err := s.apiKeyNotifier(ctx, auth.NewAPIKeyEvent(auth.APIKeyEventRotated, rotation.Previous)); err != nil {
+			logging.GetLogger().Warn("Failed to notify api key owner", "api_key_id", rotation.Previous.GetId(), "error", err)
+		}
+	}
+	return pb.RotateApiKeyResponse_builder{
+		ApiKey:   safeAPIKey(rotation.New),
+		Key:      proto.String(rotation.Key),
+		Previous: safeAPIKey(rotation.Previous),
+	}.Build(), nil
+}
+
+// safeAPIKey returns a copy of the key record without its secret hash.
+func safeAPIKey(key *configv1.ClientApiKey) *configv1.ClientApiKey {
+	safe := proto.Clone(key).(*configv1.ClientApiKey)
+	safe.ClearKeyHash()
+	return safe
+}
+
 // GetDiscoveryStatus returns the status of auto-discovery providers.
 //
 // Parameters:
@@ -463,6 +683,48 @@ func (s *Server) GetDiscoveryStatus(_ context.Context, _ *pb.GetDiscoveryStatusR
 	return pb.GetDiscoveryStatusResponse_builder{Providers: pbStatuses}.Build(), nil
 }
 
+// ListSLOStatus returns the compliance and error budget of the service level objectives.
+//
+// Parameters:
+//   - _ (context.Context): The _ parameter.
+//   - req (*pb.ListSLOStatusRequest): The request object.
+//
+// Returns:
+//   - *pb.ListSLOStatusResponse: The resulting *pb.ListSLOStatusResponse.
+//   - error: An error if the operation fails.
+//
+// Errors:
+//   - None
+//
+// Side Effects:
+//   - None
+func (s *Server) ListSLOStatus(_ context.Context, req *pb.ListSLOStatusRequest) (*pb.ListSLOStatusResponse, error) {
+	if s.sloTracker == nil {
+		return &pb.ListSLOStatusResponse{}, nil
+	}
+
+	var pbStatuses []*pb.SLOStatus
+	for _, st := range s.sloTracker.Status() {
+		if req.GetServiceName() != "" && st.Service != req.GetServiceName() {
+			continue
+		}
+		pbStatuses = append(pbStatuses, pb.SLOStatus_builder{
+			ServiceName:          proto.String(st.Service),
+			Name:                 proto.String(st.Name),
+			Target:               proto.Float64(st.Target),
+			Window:               proto.String(st.Window),
+			Compliance:           proto.Float64(st.Compliance),
+			ErrorBudgetRemaining: proto.Float64(st.ErrorBudgetRemaining),
+			BurnRate_1H:          proto.Float64(st.BurnRate1h),
+			BurnRate_6H:          proto.Float64(st.BurnRate6h),
+			TotalCalls:           proto.Int64(st.TotalCalls),
+			BadCalls:             proto.Int64(st.BadCalls),
+			Alert:                proto.String(string(st.Alert)),
+		}.Build())
+	}
+	return pb.ListSLOStatusResponse_builder{Slos: pbStatuses}.Build(), nil
+}
+
 // ListAuditLogs returns audit logs matching the filter.
 //
 // Parameters:
@@ -505,6 +767,7 @@ func (s *Server) ListAuditLogs(ctx context.Context, req *pb.ListAuditLogsRequest
 		ToolName:  req.GetToolName(),
 		UserID:    req.GetUserId(),
 		ProfileID: req.GetProfileId(),
+		APIKeyID:  req.GetApiKeyId(),
 		Limit:     int(req.GetLimit()),
 		Offset:    int(req.GetOffset()),
 	}
@@ -536,6 +799,7 @@ func (s *Server) ListAuditLogs(ctx context.Context, req *pb.ListAuditLogsRequest
 			TraceId:    proto.String(e.TraceID),
 			SpanId:     proto.String(e.SpanID),
 			ParentId:   proto.String(e.ParentID),
+			ApiKeyId:   proto.String(e.APIKeyID),
 			Arguments:  proto.String(argsStr),
 			Result:     proto.String(resultStr),
 			Error:      proto.String(e.Error),
@@ -12,9 +12,11 @@ import (
 	configv1 "github.com/mcpany/core/proto/config/v1"
 	mcprouterv1 "github.com/mcpany/core/proto/mcp_router/v1"
 	"github.com/mcpany/core/server/pkg/audit"
+	"github.com/mcpany/core/server/pkg/auth"
 	"github.com/mcpany/core/server/pkg/discovery"
 	"github.com/mcpany/core/server/pkg/middleware"
 	"github.com/mcpany/core/server/pkg/serviceregistry"
+	"github.com/mcpany/core/server/pkg/slo"
 	"github.com/mcpany/core/server/pkg/storage/memory"
 	"github.com/mcpany/core/server/pkg/tool"
 	"github.com/stretchr/testify/assert"
@@ -23,6 +25,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 // MockServiceRegistry is a manual mock for ServiceRegistryInterface
@@ -140,6 +143,70 @@ func TestServer_UserManagement(t *testing.T) {
 	assert.Equal(t, codes.NotFound, status.Code(err))
 }
 
+func TestServer_APIKeyManagement(t *testing.T) {
+	store := memory.NewStore()
+	s := NewServer(nil, nil, nil, store, nil, nil)
+	ctx := context.Background()
+
+	createResp, err := s.CreateApiKey(ctx, pb.CreateApiKeyRequest_builder{
+		ApiKey: configv1.ClientApiKey_builder{
+			Name:   proto.String("ci"),
+			Scopes: []string{"viewer"},
+		}.Build(),
+	}.Build())
+	require.NoError(t, err)
+	id := createResp.GetApiKey().GetId()
+	assert.NotEmpty(t, id)
+	assert.Contains(t, createResp.GetKey(), id)
+	assert.Empty(t, createResp.GetApiKey().GetKeyHash())
+
+	_, err = s.CreateApiKey(ctx, &pb.CreateApiKeyRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	listResp, err := s.ListApiKeys(ctx, &pb.ListApiKeysRequest{})
+	require.NoError(t, err)
+	require.Len(t, listResp.GetApiKeys(), 1)
+	assert.Equal(t, "ci", listResp.GetApiKeys()[0].GetName())
+	assert.Empty(t, listResp.GetApiKeys()[0].GetKeyHash())
+
+	var notified []auth.APIKeyEvent
+	s.SetAPIKeyNotifier(func(_ context.Context, event auth.APIKeyEvent) error {
+		notified = append(notified, event)
+		return nil
+	})
+	rotateResp, err := s.RotateApiKey(ctx, pb.RotateApiKeyRequest_builder{
+		Id:          proto.String(id),
+		GracePeriod: durationpb.New(time.Hour),
+	}.Build())
+	require.NoError(t, err)
+	newID := rotateResp.GetApiKey().GetId()
+	assert.Contains(t, rotateResp.GetKey(), newID)
+	assert.Equal(t, id, rotateResp.GetApiKey().GetRotatedFrom())
+	assert.Empty(t, rotateResp.GetApiKey().GetKeyHash())
+	assert.Equal(t, newID, rotateResp.GetPrevious().GetReplacedBy())
+	assert.NotEmpty(t, rotateResp.GetPrevious().GetExpiresAt())
+	require.Len(t, notified, 1)
+	assert.Equal(t, auth.APIKeyEventRotated, notified[0].Type)
+	assert.Empty(t, notified[0].Key)
+
+	_, err = s.RotateApiKey(ctx, pb.RotateApiKeyRequest_builder{Id: proto.String(id)}.Build())
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = s.RotateApiKey(ctx, pb.RotateApiKeyRequest_builder{Id: proto.String("missing")}.Build())
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = s.RotateApiKey(ctx, pb.RotateApiKeyRequest_builder{
+		Id:          proto.String(newID),
+		GracePeriod: durationpb.New(-time.Hour),
+	}.Build())
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	revokeResp, err := s.RevokeApiKey(ctx, pb.RevokeApiKeyRequest_builder{Id: proto.String(id)}.Build())
+	require.NoError(t, err)
+	assert.NotEmpty(t, revokeResp.GetApiKey().GetRevokedAt())
+
+	_, err = s.RevokeApiKey(ctx, pb.RevokeApiKeyRequest_builder{Id: proto.String("missing")}.Build())
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
 func TestServer_ServiceManagement(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -447,6 +514,56 @@ func TestServer_GetDiscoveryStatus(t *testing.T) {
 	assert.Empty(t, respNil.GetProviders())
 }
 
+func TestServer_ListSLOStatus(t *testing.T) {
+	ctx := context.Background()
+	s := NewServer(nil, nil, nil, nil, nil, nil)
+
+	// Without a tracker
+	resp, err := s.ListSLOStatus(ctx, pb.ListSLOStatusRequest_builder{}.Build())
+	require.NoError(t, err)
+	assert.Empty(t, resp.GetSlos())
+
+	calls := 0.0
+	now := time.Now()
+	tracker := slo.NewTracker(nil,
+		slo.WithSource(func() (map[string]slo.Counts, error) {
+			return map[string]slo.Counts{"billing": {Total: calls, Errors: calls / 100}}, nil
+		}),
+		slo.WithClock(func() time.Time { return now }),
+	)
+	objective := func(name string) *configv1.ServiceLevelObjective {
+		return configv1.ServiceLevelObjective_builder{Name: proto.String(name), Target: proto.Float64(99.5)}.Build()
+	}
+	tracker.SetObjectives([]*configv1.UpstreamServiceConfig{
+		configv1.UpstreamServiceConfig_builder{Name: proto.String("billing"), Slos: []*configv1.ServiceLevelObjective{objective("availability")}}.Build(),
+		configv1.UpstreamServiceConfig_builder{Name: proto.String("search"), Slos: []*configv1.ServiceLevelObjective{objective("availability")}}.Build(),
+	})
+	tracker.Sample()
+	calls = 1000
+	now = now.Add(time.Minute)
+	tracker.Sample()
+	s.SetSLOTracker(tracker)
+
+	resp, err = s.ListSLOStatus(ctx, pb.ListSLOStatusRequest_builder{}.Build())
+	require.NoError(t, err)
+	require.Len(t, resp.GetSlos(), 2)
+
+	resp, err = s.ListSLOStatus(ctx, pb.ListSLOStatusRequest_builder{ServiceName: proto.String("billing")}.Build())
+	require.NoError(t, err)
+	require.Len(t, resp.GetSlos(), 1)
+	st := resp.GetSlos()[0]
+	assert.Equal(t, "billing", st.GetServiceName())
+	assert.Equal(t, "availability", st.GetName())
+	assert.Equal(t, 99.5, st.GetTarget())
+	assert.Equal(t, "720h0m0s", st.GetWindow())
+	assert.Equal(t, int64(1000), st.GetTotalCalls())
+	assert.Equal(t, int64(10), st.GetBadCalls())
+	assert.InDelta(t, 99, st.GetCompliance(), 1e-9)
+	assert.InDelta(t, -1, st.GetErrorBudgetRemaining(), 1e-9)
+	assert.InDelta(t, 2, st.GetBurnRate_1H(), 1e-9)
+	assert.Empty(t, st.GetAlert())
+}
+
 // MockAuditStore is a manual mock for audit.Store
 type MockAuditStore struct {
 	entries []audit.Entry
@@ -0,0 +1,145 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package admin
+
+import (
+	"context"
+	"slices"
+
+	pb "github.com/mcpany/core/proto/admin/v1"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// DisableService takes a service out of the tool catalog at runtime by
+// adding it to global_settings.disabled_services in the storage, then
+// reloads the configuration, without touching the configuration of the
+// service itself.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - req (*pb.DisableServiceRequest): The request object.
+//
+// Returns:
+//   - *pb.DisableServiceResponse: The service, now disabled.
+//   - error: NotFound if there is no such service, and FailedPrecondition
+//     without a storage backend.
+//
+// Side Effects:
+//   - Saves the global settings and reloads the configuration.
+func (s *Server) DisableService(ctx context.Context, req *pb.DisableServiceRequest) (*pb.DisableServiceResponse, error) {
+	service, err := s.setServiceEnabled(ctx, req.GetServiceId(), false)
+	if err != nil {
+		return nil, err
+	}
+	return pb.DisableServiceResponse_builder{Service: service}.Build(), nil
+}
+
+// EnableService puts a service disabled with DisableService back in the tool
+// catalog, then reloads the configuration. A service turned off by its
+// configuration stays inactive.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - req (*pb.EnableServiceRequest): The request object.
+//
+// Returns:
+//   - *pb.EnableServiceResponse: The service, now enabled.
+//   - error: NotFound if there is no such service, and FailedPrecondition
+//     without a storage backend.
+//
+// Side Effects:
+//   - Saves the global settings and reloads the configuration.
+func (s *Server) EnableService(ctx context.Context, req *pb.EnableServiceRequest) (*pb.EnableServiceResponse, error) {
+	service, err := s.setServiceEnabled(ctx, req.GetServiceId(), true)
+	if err != nil {
+		return nil, err
+	}
+	return pb.EnableServiceResponse_builder{Service: service}.Build(), nil
+}
+
+// setServiceEnabled adds a service to global_settings.disabled_services, or
+// removes it, and reloads the configuration if the list changed.
+func (s *Server) setServiceEnabled(ctx context.Context, name string, enabled bool) (*pb.ServiceEnablement, error) {
+	if s.storage == nil {
+		return nil, status.Error(codes.FailedPrecondition, "disabling services requires a storage backend")
+	}
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "service_id is required")
+	}
+
+	s.disabledServicesMu.Lock()
+	defer s.disabledServicesMu.Unlock()
+
+	settings, err := s.storage.GetGlobalSettings(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get global settings: %v", err)
+	}
+	if settings == nil {
+		settings = configv1.GlobalSettings_builder{}.Build()
+	}
+	disabled := settings.GetDisabledServices()
+	listed := slices.Contains(disabled, name)
+	if !listed {
+		known, err := s.isKnownService(ctx, name)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get service: %v", err)
+		}
+		if !known {
+			return nil, status.Error(codes.NotFound, "service not found")
+		}
+	}
+
+	if listed == enabled {
+		if enabled {
+			settings.SetDisabledServices(slices.DeleteFunc(slices.Clone(disabled), func(s string) bool { return s == name }))
+		} else {
+			settings.SetDisabledServices(append(slices.Clone(disabled), name))
+		}
+		if err := s.storage.SaveGlobalSettings(ctx, settings); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to save global settings: %v", err)
+		}
+		logging.GetLogger().Info("Service enabled state changed", "service", name, "enabled", enabled)
+		// Unlike the profiles and secrets, the change is only useful once
+		// the catalog is rebuilt, so a failed reload fails the request.
+		if s.reloadConfig != nil {
+			if err := s.reloadConfig(ctx); err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to reload the configuration: %v", err)
+			}
+		}
+	}
+
+	return pb.ServiceEnablement_builder{
+		ServiceId: proto.String(name),
+		Disabled:  proto.Bool(!enabled),
+		Active:    proto.Bool(s.isActiveService(name)),
+	}.Build(), nil
+}
+
+// isKnownService reports whether a service with the ID or name is in the
+// tool catalog or in the storage.
+func (s *Server) isKnownService(ctx context.Context, name string) (bool, error) {
+	if s.isActiveService(name) {
+		return true, nil
+	}
+	svc, err := s.storage.GetService(ctx, name)
+	return svc != nil, err
+}
+
+// isActiveService reports whether a service with the ID or name is in the
+// tool catalog.
+func (s *Server) isActiveService(name string) bool {
+	if s.toolManager == nil {
+		return false
+	}
+	for _, info := range s.toolManager.ListServices() {
+		if info.Name == name || (info.Config != nil && info.Config.GetId() == name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pb "github.com/mcpany/core/proto/admin/v1"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestServer_DisableEnableService(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	require.NoError(t, store.SaveService(ctx, configv1.UpstreamServiceConfig_builder{
+		Name: proto.String("weather"),
+		HttpService: configv1.HttpUpstreamService_builder{
+			Address: proto.String("http://127.0.0.1:1"),
+		}.Build(),
+	}.Build()))
+	require.NoError(t, store.SaveGlobalSettings(ctx, configv1.GlobalSettings_builder{
+		DisabledServices: []string{"github"},
+	}.Build()))
+	s := NewServer(nil, nil, nil, store, nil, nil)
+	reloads := 0
+	var reloadErr error
+	s.SetConfigReloader(func(context.Context) error {
+		reloads++
+		return reloadErr
+	})
+
+	disabled := func() []string {
+		settings, err := store.GetGlobalSettings(ctx)
+		require.NoError(t, err)
+		return settings.GetDisabledServices()
+	}
+	disable := func(id string) (*pb.DisableServiceResponse, error) {
+		return s.DisableService(ctx, pb.DisableServiceRequest_builder{ServiceId: proto.String(id)}.Build())
+	}
+	enable := func(id string) (*pb.EnableServiceResponse, error) {
+		return s.EnableService(ctx, pb.EnableServiceRequest_builder{ServiceId: proto.String(id)}.Build())
+	}
+
+	resp, err := disable("weather")
+	require.NoError(t, err)
+	assert.Equal(t, "weather", resp.GetService().GetServiceId())
+	assert.True(t, resp.GetService().GetDisabled())
+	assert.False(t, resp.GetService().GetActive())
+	assert.Equal(t, []string{"github", "weather"}, disabled())
+	assert.Equal(t, 1, reloads)
+
+	_, err = disable("weather")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github", "weather"}, disabled(), "disabling twice is a no-op")
+	assert.Equal(t, 1, reloads, "a no-op does not reload")
+
+	_, err = enable("github")
+	require.NoError(t, err, "a disabled service may be gone from the storage")
+	assert.Equal(t, []string{"weather"}, disabled())
+
+	reloadErr = errors.New("invalid config")
+	_, err = enable("weather")
+	assert.Equal(t, codes.Internal, status.Code(err), "a failed reload fails the request")
+	assert.Empty(t, disabled(), "the change is stored either way")
+
+	_, err = disable("missing")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = disable("")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = NewServer(nil, nil, nil, nil, nil, nil).DisableService(ctx, pb.DisableServiceRequest_builder{ServiceId: proto.String("weather")}.Build())
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/webhooks"
 )
 
 // ManagerInterface defines the interface for managing alerts.
@@ -57,6 +58,8 @@ type Manager struct {
 	alerts     map[string]*Alert
 	rules      map[string]*AlertRule
 	webhookURL string
+	// client signs the webhook requests with the outbound webhook settings.
+	client *http.Client
 }
 
 // NewManager creates a new Manager and seeds it with initial data.
@@ -76,6 +79,7 @@ func NewManager() *Manager {
 	m := &Manager{
 		alerts: make(map[string]*Alert),
 		rules:  make(map[string]*AlertRule),
+		client: webhooks.NewOutboundClient(10 * time.Second),
 	}
 	m.seedData()
 	return m
@@ -183,7 +187,7 @@ func (m *Manager) CreateAlert(alert *Alert) *Alert {
 			}
 			req.Header.Set("Content-Type", "application/json")
 
-			resp, err := http.DefaultClient.Do(req)
+			resp, err := m.client.Do(req)
 			if err != nil {
 				logging.GetLogger().Error("failed to call webhook", "url", url, "error", err)
 				return
@@ -283,7 +287,7 @@ func (m *Manager) UpdateAlert(id string, alert *Alert) *Alert {
 			}
 			req.Header.Set("Content-Type", "application/json")
 
-			resp, err := http.DefaultClient.Do(req)
+			resp, err := m.client.Do(req)
 			if err != nil {
 				logging.GetLogger().Error("failed to call webhook", "url", url, "error", err)
 				return
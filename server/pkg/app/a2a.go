@@ -0,0 +1,33 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"net/http"
+
+	"github.com/mcpany/core/server/pkg/a2a"
+	"github.com/mcpany/core/server/pkg/appconsts"
+	"github.com/mcpany/core/server/pkg/mcpserver"
+)
+
+// a2aPath is the path of the A2A JSON-RPC endpoint.
+const a2aPath = "/a2a"
+
+// newA2AServer creates the A2A agent serving the tools of the MCP server as
+// skills, at /a2a with its agent card at /.well-known/agent-card.json. The
+// tasks run through the same middleware as the MCP calls.
+//
+// Parameters:
+//   - mcpSrv (*mcpserver.Server): The MCP server executing the tasks.
+//
+// Returns:
+//   - http.Handler: The handler of the agent card and the JSON-RPC endpoint.
+func newA2AServer(mcpSrv *mcpserver.Server) http.Handler {
+	return a2a.NewServer(chainedToolSet{mcpToolSet{mcpSrv}}, a2a.Options{
+		Name:        "MCP Any",
+		Description: "Delegates tasks to the tools of the upstream services aggregated by MCP Any.",
+		Version:     appconsts.Version,
+		Path:        a2aPath,
+	})
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mcpany/core/server/pkg/audit"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestA2AServer_TasksGoThroughMCPMiddleware(t *testing.T) {
+	mcpSrv, auditMiddleware := newChainedTestServer(t)
+	agent := newA2AServer(mcpSrv)
+
+	body := `{"jsonrpc": "2.0", "id": 1, "method": "message/send", "params": {"message": {
+		"role": "user", "messageId": "m1", "kind": "message",
+		"parts": [{"kind": "data", "data": {}}], "metadata": {"skillId": "crm.contact"}}}}`
+	req := httptest.NewRequest(http.MethodPost, a2aPath, strings.NewReader(body))
+	req = req.WithContext(auth.ContextWithUser(req.Context(), "a2a-user"))
+	rec := httptest.NewRecorder()
+	agent.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"state":"completed"`)
+	assert.NotContains(t, rec.Body.String(), "alice@example.com", "the result is masked by DLP before it reaches the agent")
+
+	require.NoError(t, auditMiddleware.Flush(context.Background()))
+	entries, err := auditMiddleware.Read(context.Background(), audit.Filter{ToolName: "crm.contact"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "the task is audited")
+	assert.Equal(t, "a2a-user", entries[0].UserID)
+}
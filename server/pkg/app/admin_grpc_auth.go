@@ -0,0 +1,95 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	pb_admin "github.com/mcpany/core/proto/admin/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// adminServiceMethodPrefix prefixes the full method names of the admin
+// service.
+var adminServiceMethodPrefix = "/" + pb_admin.AdminService_ServiceDesc.ServiceName + "/"
+
+// authorizeAdminRPC authenticates a call of the admin service over gRPC and
+// requires the admin role. The call carries the credentials of the HTTP API
+// in its metadata (x-api-key, authorization), which go through the same
+// authentication middleware, with the full method name as the request path.
+// Calls over gRPC-Web were authenticated by the middleware already.
+func authorizeAdminRPC(ctx context.Context, fullMethod string, authMiddleware func(http.Handler) http.Handler) (context.Context, error) {
+	if _, ok := auth.UserFromContext(ctx); !ok {
+		var err error
+		if ctx, err = authenticateRPC(ctx, fullMethod, authMiddleware); err != nil {
+			return nil, err
+		}
+	}
+	if !auth.NewRBACEnforcer().HasRoleInContext(ctx, "admin") {
+		return nil, status.Error(codes.PermissionDenied, "the admin API requires the admin role")
+	}
+	return ctx, nil
+}
+
+// authenticateRPC runs the authentication middleware on a request for the
+// full method name, with the metadata of a gRPC call as its headers, and
+// returns the authenticated context.
+func authenticateRPC(ctx context.Context, fullMethod string, authMiddleware func(http.Handler) http.Handler) (context.Context, error) {
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, fullMethod, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to authenticate: %v", err)
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for key, values := range md {
+		if strings.HasPrefix(key, ":") {
+			continue
+		}
+		for _, v := range values {
+			r.Header.Add(key, v)
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		r.RemoteAddr = p.Addr.String()
+	}
+
+	var authenticated context.Context
+	w := &rpcAuthResponse{header: http.Header{}}
+	authMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		authenticated = r.Context()
+	})).ServeHTTP(w, r)
+	if authenticated != nil {
+		return authenticated, nil
+	}
+	switch w.status {
+	case http.StatusTooManyRequests:
+		return nil, status.Error(codes.ResourceExhausted, "too many requests")
+	case http.StatusForbidden:
+		return nil, status.Error(codes.PermissionDenied, "forbidden")
+	default:
+		return nil, status.Error(codes.Unauthenticated, "unauthenticated")
+	}
+}
+
+// rpcAuthResponse records the status the authentication middleware rejects
+// a call with.
+type rpcAuthResponse struct {
+	header http.Header
+	status int
+}
+
+func (w *rpcAuthResponse) Header() http.Header { return w.header }
+
+func (w *rpcAuthResponse) Write(b []byte) (int, error) { return len(b), nil }
+
+func (w *rpcAuthResponse) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	pb_admin "github.com/mcpany/core/proto/admin/v1"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/storage/memory"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAuthorizeAdminRPC(t *testing.T) {
+	// A stand-in for the auth middleware: one key per role.
+	roles := map[string]string{"admin-key": "admin", "viewer-key": "viewer"}
+	method := pb_admin.AdminService_ListCircuitBreakers_FullMethodName
+	authMiddleware := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, method, r.URL.Path)
+			role, ok := roles[r.Header.Get("X-API-Key")]
+			if !ok {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			ctx := auth.ContextWithRoles(auth.ContextWithUser(r.Context(), role+"-user"), []string{role})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+	call := func(ctx context.Context, kv ...string) (context.Context, error) {
+		return authorizeAdminRPC(metadata.NewIncomingContext(ctx, metadata.Pairs(kv...)), method, authMiddleware)
+	}
+
+	ctx, err := call(context.Background(), "x-api-key", "admin-key")
+	require.NoError(t, err)
+	user, _ := auth.UserFromContext(ctx)
+	assert.Equal(t, "admin-user", user)
+
+	_, err = call(context.Background(), "x-api-key", "viewer-key")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = call(context.Background(), "x-api-key", "wrong")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = call(context.Background())
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Calls over gRPC-Web carry the identity of the HTTP request.
+	webCtx := auth.ContextWithRoles(auth.ContextWithUser(context.Background(), "alice"), []string{"admin"})
+	_, err = call(webCtx)
+	assert.NoError(t, err)
+}
+
+func TestAdminAPI_RequiresAuthentication(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/config.yaml", []byte("upstream_services: []"), 0o644))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Keep the logs of this server out of the history other tests read.
+	t.Cleanup(logging.GlobalBroadcaster.Reset)
+
+	// Client API keys whose scopes are their roles. The admin scope is
+	// reserved: a key stored with it before it was rejected is not admin.
+	store := memory.NewStore()
+	keys := map[string]string{}
+	for _, role := range []string{"admin", "viewer"} {
+		record, key, err := auth.GenerateClientAPIKey(configv1.ClientApiKey_builder{
+			Name:   proto.String(role),
+			Scopes: []string{role},
+		}.Build())
+		require.NoError(t, err)
+		require.NoError(t, store.SaveAPIKey(ctx, record))
+		keys[role] = key
+	}
+
+	app := NewApplication()
+	app.Storage = store
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- app.Run(RunOptions{Ctx: ctx, Fs: fs, JSONRPCPort: "127.0.0.1:0", GRPCPort: "127.0.0.1:0", ConfigPaths: []string{"/config.yaml"}, APIKey: "secret", ShutdownTimeout: 5 * time.Second})
+	}()
+	defer func() {
+		cancel()
+		<-errChan
+	}()
+	require.NoError(t, app.WaitForStartup(ctx))
+
+	t.Run("rest", func(t *testing.T) {
+		url := fmt.Sprintf("http://127.0.0.1:%d/v1/admin/circuit-breakers", app.BoundHTTPPort.Load())
+		get := func(key string) int {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			require.NoError(t, err)
+			if key != "" {
+				req.Header.Set("X-API-Key", key)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+			return resp.StatusCode
+		}
+		assert.Equal(t, http.StatusUnauthorized, get(""))
+		assert.Equal(t, http.StatusUnauthorized, get("wrong"))
+		assert.Equal(t, http.StatusForbidden, get(keys["viewer"]))
+		assert.Equal(t, http.StatusForbidden, get(keys["admin"]))
+		assert.Equal(t, http.StatusOK, get("secret"))
+	})
+
+	t.Run("grpc", func(t *testing.T) {
+		conn, err := gogrpc.NewClient(fmt.Sprintf("127.0.0.1:%d", app.BoundGRPCPort.Load()), gogrpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+		client := pb_admin.NewAdminServiceClient(conn)
+
+		list := func(key string) codes.Code {
+			callCtx := ctx
+			if key != "" {
+				callCtx = metadata.AppendToOutgoingContext(ctx, "x-api-key", key)
+			}
+			_, err := client.ListCircuitBreakers(callCtx, &pb_admin.ListCircuitBreakersRequest{})
+			return status.Code(err)
+		}
+		assert.Equal(t, codes.Unauthenticated, list(""))
+		assert.Equal(t, codes.Unauthenticated, list("wrong"))
+		assert.Equal(t, codes.PermissionDenied, list(keys["viewer"]))
+		assert.Equal(t, codes.PermissionDenied, list(keys["admin"]))
+		assert.Equal(t, codes.OK, list("secret"))
+	})
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/mcpany/core/server/pkg/health"
 	"github.com/mcpany/core/server/pkg/logging"
 	"github.com/mcpany/core/server/pkg/middleware"
+	"github.com/mcpany/core/server/pkg/secretusage"
 	"github.com/mcpany/core/server/pkg/storage"
 	"github.com/mcpany/core/server/pkg/tool"
 	"github.com/mcpany/core/server/pkg/util"
@@ -74,11 +75,24 @@ func (a *Application) createAPIHandler(store storage.Storage) http.Handler {
 	doctor := health.NewDoctor()
 	doctor.AddCheck("configuration", a.configHealthCheck)
 	doctor.AddCheck("filesystem", a.filesystemHealthCheck)
+	if a.ExpiryChecker != nil {
+		doctor.AddCheck("expiry", a.ExpiryChecker.HealthCheck)
+	}
+	doctor.SetRegressionObserver(a.notifyDoctorRegression)
 	mux.Handle("/doctor", doctor.Handler())
 	mux.HandleFunc("/system/status", a.handleSystemStatus)
+	mux.HandleFunc("/version", a.handleVersion)
 	mux.HandleFunc("/discovery/status", a.handleDiscoveryStatus)
 	mux.HandleFunc("/discovery/trigger", a.handleDiscoveryTrigger)
+	mux.HandleFunc("/slos", a.handleSLOs)
+	mux.HandleFunc("/stats/slow-calls", a.handleSlowCalls)
+	mux.HandleFunc("/logs/stream", a.handleLogsStream)
+	mux.HandleFunc("/config/dry-run", a.handleConfigDryRun)
+	mux.HandleFunc("/config/versions", a.handleConfigVersions)
+	mux.HandleFunc("/config/versions/", a.handleConfigVersionDetail)
+	mux.HandleFunc("/cache/invalidate", a.handleCacheInvalidate)
 	mux.HandleFunc("/audit/logs", a.handleAuditLogs)
+	mux.HandleFunc("/audit/logs/", a.handleAuditLog)
 	mux.HandleFunc("/audit/export", a.handleAuditExport)
 	mux.HandleFunc("/validate", a.handleValidate())
 
@@ -107,6 +121,7 @@ func (a *Application) createAPIHandler(store storage.Storage) http.Handler {
 
 	mux.HandleFunc("/skills", a.handleSkills())
 	mux.HandleFunc("/skills/", a.handleSkillDetail())
+	mux.HandleFunc("/skill-packages", a.handleInstallSkillPackage())
 	// Asset upload is handled via query param path, but we can mount it if needed.
 	// Actually handleUploadSkillAsset parses path manually, so checking if we need explicit mount.
 	// No, handleUploadSkillAsset is NOT registered!
@@ -187,6 +202,11 @@ func (a *Application) createAPIHandler(store storage.Storage) http.Handler {
 
 	mux.HandleFunc("/webhooks", a.handleWebhooks())
 	mux.HandleFunc("/webhooks/", a.handleWebhookDetail())
+	mux.HandleFunc("/webhooks/dlq", a.handleWebhookDeadLetters)
+	mux.HandleFunc("/webhooks/dlq/", a.handleWebhookDeadLetterDetail)
+
+	mux.HandleFunc("/cluster/instances", a.handleClusterInstances)
+	mux.HandleFunc("/drain", a.handleDrain)
 
 	mux.HandleFunc("/alerts", a.handleAlerts())
 	mux.HandleFunc("/alerts/stats", a.handleAlertStats())
@@ -199,7 +219,7 @@ func (a *Application) createAPIHandler(store storage.Storage) http.Handler {
 	mux.HandleFunc("/ws/logs", a.handleLogsWS())
 	mux.HandleFunc("/ws/traces", a.handleTracesWS())
 
-	return mux
+	return withAdminAPIReloadTrigger(mux)
 }
 
 func (a *Application) handleServices(store storage.Storage) http.HandlerFunc {
@@ -1002,13 +1022,24 @@ func (a *Application) handleSecretReveal(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
-	// Log the access (Audit)
 	user, _ := auth.UserFromContext(r.Context())
+	if !secretusage.CanReveal(r.Context(), secret) {
+		logging.GetLogger().Warn("Secret reveal denied", "id", id, "user", user)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	// Log the access (Audit)
 	logging.GetLogger().Info("Secret revealed", "id", id, "user", user)
+	secretusage.RecordRead(secret, secretusage.AdminConsumer(user), time.Now())
+	if err := store.SaveSecret(r.Context(), secret); err != nil {
+		logging.GetLogger().Warn("failed to record secret usage", "id", id, "error", err)
+	}
+	value := secret.GetValue()
 
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(map[string]string{
-		"value": secret.GetValue(),
+		"value": value,
 	})
 }
 
@@ -9,17 +9,24 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/mcpany/core/server/pkg/audit"
+	"github.com/mcpany/core/server/pkg/auth"
 )
 
-// handleAuditLogs handles requests to list audit logs.
+// handleAuditLogs handles requests to list audit logs. The entries hold the
+// arguments and results of the calls, so only admins may read them.
 func (a *Application) handleAuditLogs(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
+	if !auth.NewRBACEnforcer().HasRoleInContext(r.Context(), "admin") {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
 
 	filter := audit.Filter{}
 	if start := r.URL.Query().Get("start_time"); start != "" {
@@ -41,6 +48,16 @@ func (a *Application) handleAuditLogs(w http.ResponseWriter, r *http.Request) {
 	filter.ToolName = r.URL.Query().Get("tool_name")
 	filter.UserID = r.URL.Query().Get("user_id")
 	filter.ProfileID = r.URL.Query().Get("profile_id")
+	filter.APIKeyID = r.URL.Query().Get("api_key_id")
+	filter.Query = r.URL.Query().Get("q")
+	if errorsOnly := r.URL.Query().Get("errors_only"); errorsOnly != "" {
+		v, err := strconv.ParseBool(errorsOnly)
+		if err != nil {
+			http.Error(w, "invalid errors_only format", http.StatusBadRequest)
+			return
+		}
+		filter.ErrorsOnly = v
+	}
 
 	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
 		if limit, err := strconv.Atoi(limitStr); err == nil {
@@ -77,11 +94,52 @@ func (a *Application) handleAuditLogs(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleAuditLog returns a single audit entry, with its full arguments and
+// result, at /audit/logs/{id}. Only admins may read it.
+func (a *Application) handleAuditLog(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !auth.NewRBACEnforcer().HasRoleInContext(r.Context(), "admin") {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, "/audit/logs/")
+	if id == "" || strings.Contains(id, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	if a.standardMiddlewares == nil || a.standardMiddlewares.Audit == nil {
+		http.Error(w, "Audit store not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	entries, err := a.standardMiddlewares.Audit.Read(r.Context(), audit.Filter{ID: id, Limit: 1})
+	if err != nil {
+		http.Error(w, "Failed to read audit logs: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(entries) == 0 {
+		http.Error(w, "audit entry not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(entries[0]); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
 func (a *Application) handleAuditExport(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
+	if !auth.NewRBACEnforcer().HasRoleInContext(r.Context(), "admin") {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
 
 	// Basic filtering from query params
 	filter := audit.Filter{}
@@ -119,7 +177,7 @@ func (a *Application) handleAuditExport(w http.ResponseWriter, r *http.Request)
 	defer writer.Flush()
 
 	// Header
-	_ = writer.Write([]string{"Timestamp", "ToolName", "UserID", "ProfileID", "Arguments", "Result", "Error", "DurationMs"})
+	_ = writer.Write([]string{"Timestamp", "ToolName", "UserID", "ProfileID", "APIKeyID", "Arguments", "Result", "Error", "DurationMs"})
 
 	for _, entry := range entries {
 		_ = writer.Write([]string{
@@ -127,6 +185,7 @@ func (a *Application) handleAuditExport(w http.ResponseWriter, r *http.Request)
 			entry.ToolName,
 			entry.UserID,
 			entry.ProfileID,
+			entry.APIKeyID,
 			string(entry.Arguments),
 			fmt.Sprintf("%v", entry.Result),
 			entry.Error,
@@ -16,6 +16,7 @@ import (
 
 	configv1 "github.com/mcpany/core/proto/config/v1"
 	"github.com/mcpany/core/server/pkg/audit"
+	"github.com/mcpany/core/server/pkg/auth"
 	"github.com/mcpany/core/server/pkg/middleware"
 	"github.com/mcpany/core/server/pkg/validation"
 	"github.com/stretchr/testify/assert"
@@ -42,6 +43,12 @@ func (m *MockAuditStore) Close() error {
 	return args.Error(0)
 }
 
+// newAuditRequest returns a request from an admin, who may read the audit log.
+func newAuditRequest(method, target string) *http.Request {
+	r := httptest.NewRequest(method, target, nil)
+	return r.WithContext(auth.ContextWithRoles(r.Context(), []string{"admin"}))
+}
+
 func TestHandleAuditExport_Mock(t *testing.T) {
 	app := NewApplication()
 	mockStore := new(MockAuditStore)
@@ -71,7 +78,7 @@ func TestHandleAuditExport_Mock(t *testing.T) {
 		}
 		mockStore.On("Read", mock.Anything, mock.Anything).Return(entries, nil).Once()
 
-		req := httptest.NewRequest(http.MethodGet, "/audit/export", nil)
+		req := newAuditRequest(http.MethodGet, "/audit/export")
 		w := httptest.NewRecorder()
 
 		app.handleAuditExport(w, req)
@@ -89,7 +96,7 @@ func TestHandleAuditExport_Mock(t *testing.T) {
 	t.Run("StoreError", func(t *testing.T) {
 		mockStore.On("Read", mock.Anything, mock.Anything).Return([]audit.Entry{}, assert.AnError).Once()
 
-		req := httptest.NewRequest(http.MethodGet, "/audit/export", nil)
+		req := newAuditRequest(http.MethodGet, "/audit/export")
 		w := httptest.NewRecorder()
 
 		app.handleAuditExport(w, req)
@@ -99,7 +106,7 @@ func TestHandleAuditExport_Mock(t *testing.T) {
 
 	t.Run("NotConfigured", func(t *testing.T) {
 		app.standardMiddlewares.Audit = nil
-		req := httptest.NewRequest(http.MethodGet, "/audit/export", nil)
+		req := newAuditRequest(http.MethodGet, "/audit/export")
 		w := httptest.NewRecorder()
 
 		app.handleAuditExport(w, req)
@@ -110,7 +117,7 @@ func TestHandleAuditExport_Mock(t *testing.T) {
 	})
 
 	t.Run("MethodNotAllowed", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodPost, "/audit/export", nil)
+		req := newAuditRequest(http.MethodPost, "/audit/export")
 		w := httptest.NewRecorder()
 		app.handleAuditExport(w, req)
 		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
@@ -157,7 +164,7 @@ func TestHandleAuditLogs(t *testing.T) {
 	app.standardMiddlewares.Audit = auditMiddleware
 	defer auditMiddleware.Close()
 
-	req, _ := http.NewRequest("GET", "/audit/logs?tool_name=tool-1", nil)
+	req := newAuditRequest(http.MethodGet, "/audit/logs?tool_name=tool-1")
 	rr := httptest.NewRecorder()
 	// Use createAPIHandler to verify routing
 	mux := app.createAPIHandler(app.Storage)
@@ -175,3 +182,65 @@ func TestHandleAuditLogs(t *testing.T) {
 	assert.Equal(t, "tool-1", entries[0].ToolName)
 	assert.Equal(t, "user-1", entries[0].UserID)
 }
+
+func TestHandleAuditLog(t *testing.T) {
+	app := NewApplication()
+	mockStore := new(MockAuditStore)
+	auditConfig := &configv1.AuditConfig{}
+	auditConfig.SetEnabled(true)
+	am, err := middleware.NewAuditMiddleware(auditConfig)
+	require.NoError(t, err)
+	am.SetStore(mockStore)
+	app.standardMiddlewares = &middleware.StandardMiddlewares{Audit: am}
+	mux := app.createAPIHandler(app.Storage)
+
+	entry := audit.Entry{ID: "0192.abc", ToolName: "weather.get_forecast", Arguments: []byte(`{"city":"Paris"}`), Result: "sunny"}
+	mockStore.On("Read", mock.Anything, audit.Filter{ID: "0192.abc", Limit: 1}).Return([]audit.Entry{entry}, nil).Once()
+	mockStore.On("Read", mock.Anything, audit.Filter{ID: "missing", Limit: 1}).Return([]audit.Entry{}, nil).Once()
+	mockStore.On("Read", mock.Anything, audit.Filter{ToolName: "weather.get_forecast", ErrorsOnly: true, Query: "timeout", Limit: 2}).
+		Return([]audit.Entry{}, nil).Once()
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, newAuditRequest(http.MethodGet, "/audit/logs/0192.abc"))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var got audit.Entry
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+	assert.Equal(t, "0192.abc", got.ID)
+	assert.JSONEq(t, `{"city":"Paris"}`, string(got.Arguments))
+	assert.Equal(t, "sunny", got.Result)
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, newAuditRequest(http.MethodGet, "/audit/logs/missing"))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, newAuditRequest(http.MethodGet, "/audit/logs?tool_name=weather.get_forecast&errors_only=true&q=timeout&limit=2"))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, newAuditRequest(http.MethodGet, "/audit/logs?errors_only=maybe"))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	mockStore.AssertExpectations(t)
+}
+
+func TestHandleAuditLog_RequiresAdmin(t *testing.T) {
+	app := NewApplication()
+	mockStore := new(MockAuditStore)
+	auditConfig := &configv1.AuditConfig{}
+	auditConfig.SetEnabled(true)
+	am, err := middleware.NewAuditMiddleware(auditConfig)
+	require.NoError(t, err)
+	am.SetStore(mockStore)
+	app.standardMiddlewares = &middleware.StandardMiddlewares{Audit: am}
+	mux := app.createAPIHandler(app.Storage)
+
+	for _, target := range []string{"/audit/logs", "/audit/logs?q=password", "/audit/logs/0192.abc", "/audit/export"} {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		r = r.WithContext(auth.ContextWithRoles(r.Context(), []string{"viewer"}))
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, r)
+		assert.Equal(t, http.StatusForbidden, rr.Code, target)
+	}
+	mockStore.AssertNotCalled(t, "Read", mock.Anything, mock.Anything)
+}
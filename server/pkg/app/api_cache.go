@@ -0,0 +1,84 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/logging"
+)
+
+// cacheInvalidateRequest selects the cached tool results to remove.
+type cacheInvalidateRequest struct {
+	// All removes every entry.
+	All bool `json:"all"`
+	// Tool removes the entries of the tool.
+	Tool string `json:"tool"`
+	// Arguments narrows Tool down to the entry of a call.
+	Arguments map[string]any `json:"arguments"`
+}
+
+// handleCacheInvalidate removes cached tool results: all of them, those of a
+// tool, or the one of a tool call.
+//
+// Summary: Invalidates response cache entries. Admin only.
+//
+// Parameters:
+//   - w: http.ResponseWriter. The response writer.
+//   - r: *http.Request. The HTTP request.
+//
+// Side Effects:
+//   - Deletes entries from the response cache, which may be shared through Redis.
+func (a *Application) handleCacheInvalidate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !auth.NewRBACEnforcer().HasRoleInContext(r.Context(), "admin") {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	if a.ResponseCache == nil {
+		http.Error(w, "response cache not initialized", http.StatusServiceUnavailable)
+		return
+	}
+
+	body, err := readBodyWithLimit(w, r, 1048576)
+	if err != nil {
+		return
+	}
+	var req cacheInvalidateRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var scope string
+	switch {
+	case req.All && req.Tool == "":
+		scope, err = "all", a.ResponseCache.Clear(r.Context())
+	case !req.All && req.Tool != "" && req.Arguments == nil:
+		scope, err = "tool", a.ResponseCache.InvalidateTool(r.Context(), req.Tool)
+	case !req.All && req.Tool != "":
+		if _, ok := a.ToolManager.GetTool(req.Tool); !ok {
+			http.Error(w, "tool not found", http.StatusNotFound)
+			return
+		}
+		scope, err = "call", a.ResponseCache.InvalidateCall(r.Context(), req.Tool, req.Arguments)
+	default:
+		http.Error(w, "set either all or tool", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		logging.GetLogger().Error("Failed to invalidate response cache", "scope", scope, "tool", req.Tool, "error", err)
+		http.Error(w, "failed to invalidate cache", http.StatusInternalServerError)
+		return
+	}
+	logging.GetLogger().Info("Invalidated response cache", "scope", scope, "tool", req.Tool)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"invalidated": scope})
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	mcp_router_v1 "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/middleware"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestHandleCacheInvalidate(t *testing.T) {
+	app := NewApplication()
+	admin := func(body string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/cache/invalidate", strings.NewReader(body))
+		return r.WithContext(auth.ContextWithRoles(r.Context(), []string{"admin"}))
+	}
+	invalidate := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		app.handleCacheInvalidate(w, admin(body))
+		return w
+	}
+
+	t.Run("forbidden", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		app.handleCacheInvalidate(w, httptest.NewRequest(http.MethodPost, "/api/v1/cache/invalidate", strings.NewReader(`{"all": true}`)))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("no cache", func(t *testing.T) {
+		assert.Equal(t, http.StatusServiceUnavailable, invalidate(`{"all": true}`).Code)
+	})
+
+	var executions int
+	weather := &tool.MockTool{
+		ToolFunc: func() *mcp_router_v1.Tool {
+			return mcp_router_v1.Tool_builder{Name: proto.String("get_forecast"), ServiceId: proto.String("weather")}.Build()
+		},
+		ExecuteFunc: func(context.Context, *tool.ExecutionRequest) (any, error) {
+			executions++
+			return "sunny", nil
+		},
+		GetCacheConfigFunc: func() *configv1.CacheConfig {
+			return configv1.CacheConfig_builder{IsEnabled: proto.Bool(true), Ttl: durationpb.New(time.Minute)}.Build()
+		},
+	}
+	require.NoError(t, app.ToolManager.AddTool(weather))
+	app.ResponseCache = middleware.NewCachingMiddleware(app.ToolManager)
+
+	ctx := tool.NewContextWithTool(context.Background(), weather)
+	call := func(city string) {
+		req := &tool.ExecutionRequest{ToolName: "weather.get_forecast", Arguments: map[string]any{"city": city}}
+		_, err := app.ResponseCache.Execute(ctx, req, weather.Execute)
+		require.NoError(t, err)
+	}
+	call("Paris")
+	call("Berlin")
+	require.Equal(t, 2, executions)
+
+	t.Run("call", func(t *testing.T) {
+		w := invalidate(`{"tool": "weather.get_forecast", "arguments": {"city": "Paris"}}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"invalidated": "call"}`, w.Body.String())
+		call("Paris")
+		call("Berlin")
+		assert.Equal(t, 3, executions, "only the Paris entry is invalidated")
+	})
+
+	t.Run("tool", func(t *testing.T) {
+		w := invalidate(`{"tool": "weather.get_forecast"}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"invalidated": "tool"}`, w.Body.String())
+		call("Paris")
+		call("Berlin")
+		assert.Equal(t, 5, executions)
+	})
+
+	t.Run("all", func(t *testing.T) {
+		w := invalidate(`{"all": true}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		call("Paris")
+		assert.Equal(t, 6, executions)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, invalidate(`{}`).Code)
+		assert.Equal(t, http.StatusBadRequest, invalidate(`{"all": true, "tool": "weather.get_forecast"}`).Code)
+		assert.Equal(t, http.StatusNotFound, invalidate(`{"tool": "unknown", "arguments": {}}`).Code)
+	})
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/logging"
+)
+
+// handleClusterInstances lists the replicas registered in the shared state,
+// oldest first, with the one leading the cluster marked.
+//
+// Summary: Returns the instance registry. Admin only.
+//
+// Parameters:
+//   - w: http.ResponseWriter. The response writer.
+//   - r: *http.Request. The HTTP request.
+//
+// Side Effects:
+//   - Writes the instances as a JSON array.
+func (a *Application) handleClusterInstances(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !auth.NewRBACEnforcer().HasRoleInContext(r.Context(), "admin") {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	if a.Membership == nil {
+		http.Error(w, "shared_state is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	instances, err := a.Membership.Instances(r.Context())
+	if err != nil {
+		logging.GetLogger().Error("Failed to list cluster instances", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(instances); err != nil {
+		logging.GetLogger().Error("Failed to encode cluster instances", "error", err)
+	}
+}
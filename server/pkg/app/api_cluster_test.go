@@ -0,0 +1,59 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/mcpany/core/proto/bus"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/sharedstate"
+	"github.com/mcpany/core/server/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleClusterInstances(t *testing.T) {
+	store := memory.NewStore()
+	app := &Application{Storage: store}
+	handler := app.createAPIHandler(store)
+	serve := func(admin bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/cluster/instances", nil)
+		if admin {
+			r = r.WithContext(auth.ContextWithRoles(r.Context(), []string{"admin"}))
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	assert.Equal(t, http.StatusForbidden, serve(false).Code)
+	assert.Equal(t, http.StatusServiceUnavailable, serve(true).Code, "shared_state is not configured")
+
+	mr := miniredis.RunT(t)
+	redis := &bus.RedisBus{}
+	redis.SetAddress(mr.Addr())
+	shared, err := sharedstate.New(context.Background(), configv1.SharedStateConfig_builder{Redis: redis}.Build())
+	require.NoError(t, err)
+	defer func() { _ = shared.Close() }()
+	app.Membership = shared.NewMembership("replica-a", "1.2.3", time.Minute)
+	require.NoError(t, app.Membership.Start(context.Background()))
+	defer func() { _ = app.Membership.Stop(context.Background()) }()
+
+	w := serve(true)
+	require.Equal(t, http.StatusOK, w.Code)
+	var instances []sharedstate.Instance
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &instances))
+	require.Len(t, instances, 1)
+	assert.Equal(t, "replica-a", instances[0].ID)
+	assert.Equal(t, "1.2.3", instances[0].Version)
+	assert.True(t, instances[0].Leader)
+}
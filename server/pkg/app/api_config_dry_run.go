@@ -0,0 +1,68 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mcpany/core/server/pkg/auth"
+)
+
+// dryRunRequest is the request body of the dry run endpoint.
+type dryRunRequest struct {
+	Content string `json:"content"`
+	// Format is yaml, json, toml or textproto. It is detected if empty.
+	Format           string `json:"format"`
+	SkipConnectivity bool   `json:"skip_connectivity"`
+}
+
+// handleConfigDryRun checks a candidate configuration without applying it.
+//
+// Summary: Returns the verdict of a dry run of a candidate configuration. Admin only.
+//
+// Parameters:
+//   - w: http.ResponseWriter. The response writer.
+//   - r: *http.Request. The HTTP request.
+//
+// Side Effects:
+//   - Connects to the upstreams of the candidate, unless skip_connectivity is set.
+//   - Writes the verdict as JSON, with 200 OK whatever the verdict.
+func (a *Application) handleConfigDryRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// The candidate is merged with the stored configuration, and its
+	// upstreams are contacted.
+	if !auth.NewRBACEnforcer().HasRoleInContext(r.Context(), "admin") {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	body, err := readBodyWithLimit(w, r, 1048576) // 1MB limit
+	if err != nil {
+		return
+	}
+	var req dryRunRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Content == "" {
+		http.Error(w, "content is required", http.StatusBadRequest)
+		return
+	}
+
+	verdict, err := a.DryRunConfig(r.Context(), []byte(req.Content), DryRunOptions{
+		Format:           req.Format,
+		SkipConnectivity: req.SkipConnectivity,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(verdict)
+}
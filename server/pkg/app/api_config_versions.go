@@ -0,0 +1,159 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	config_v1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/util"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// handleConfigVersions lists the recorded reloads of the configuration,
+// newest first, without their configurations.
+//
+// Summary: Returns the configuration version history. Admin only.
+//
+// Parameters:
+//   - w: http.ResponseWriter. The response writer.
+//   - r: *http.Request. The HTTP request.
+//
+// Side Effects:
+//   - Writes the versions as a JSON array.
+func (a *Application) handleConfigVersions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !a.authorizeConfigVersions(w, r) {
+		return
+	}
+
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	versions, err := a.Storage.ListConfigVersions(r.Context(), limit)
+	if err != nil {
+		logging.GetLogger().Error("Failed to list config versions", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	parts := make([]string, 0, len(versions))
+	for _, v := range versions {
+		v.ClearConfig()
+		b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(v)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		parts = append(parts, string(b))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte("[" + strings.Join(parts, ",") + "]"))
+}
+
+// handleConfigVersionDetail serves a configuration version and rolls the
+// configuration back to it.
+//
+// GET /config/versions/{version} returns the version with its configuration,
+// its secrets redacted. POST /config/versions/{version}/rollback applies its
+// configuration again.
+//
+// Summary: Returns or restores a configuration version. Admin only.
+//
+// Parameters:
+//   - w: http.ResponseWriter. The response writer.
+//   - r: *http.Request. The HTTP request.
+//
+// Side Effects:
+//   - Reconfigures the server on rollback.
+func (a *Application) handleConfigVersionDetail(w http.ResponseWriter, r *http.Request) {
+	if !a.authorizeConfigVersions(w, r) {
+		return
+	}
+
+	rest := strings.TrimPrefix(r.URL.Path, "/config/versions/")
+	rest, rollback := strings.CutSuffix(rest, "/rollback")
+	number, err := strconv.ParseInt(rest, 10, 64)
+	if err != nil || number <= 0 {
+		http.Error(w, "invalid version", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case rollback && r.Method == http.MethodPost:
+		v, err := a.RollbackConfig(r.Context(), number)
+		switch {
+		case errors.Is(err, ErrConfigVersionNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		v.ClearConfig()
+		writeConfigVersion(w, v)
+	case !rollback && r.Method == http.MethodGet:
+		v, err := a.Storage.GetConfigVersion(r.Context(), number)
+		if err != nil {
+			logging.GetLogger().Error("Failed to get config version", "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		if v == nil {
+			http.Error(w, "config version not found", http.StatusNotFound)
+			return
+		}
+		if v.HasConfig() {
+			cfg := proto.Clone(v.GetConfig()).(*config_v1.McpAnyServerConfig)
+			util.StripSecretsFromConfig(cfg)
+			v.SetConfig(cfg)
+		}
+		writeConfigVersion(w, v)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// authorizeConfigVersions rejects the requests of non-admins, since the
+// snapshots carry the whole configuration, and the requests to a server
+// without storage.
+func (a *Application) authorizeConfigVersions(w http.ResponseWriter, r *http.Request) bool {
+	if !auth.NewRBACEnforcer().HasRoleInContext(r.Context(), "admin") {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return false
+	}
+	if a.Storage == nil {
+		http.Error(w, "config history requires a storage backend", http.StatusServiceUnavailable)
+		return false
+	}
+	return true
+}
+
+// writeConfigVersion writes a configuration version as JSON, with the values
+// of sensitive keys redacted.
+func writeConfigVersion(w http.ResponseWriter, v *config_v1.ConfigVersion) {
+	b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(util.RedactJSON(b))
+}
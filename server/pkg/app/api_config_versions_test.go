@@ -0,0 +1,111 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestHandleConfigVersions(t *testing.T) {
+	app := newVersionedApp()
+	fs := afero.NewMemMapFs()
+	ctx := context.Background()
+	require.NoError(t, reloadVersionedConfig(ctx, t, app, fs, "get_forecast"))
+	require.Error(t, reloadVersionedConfig(ctx, t, app, fs, ""))
+
+	// A version whose configuration holds a secret.
+	secret := configv1.ConfigVersion_builder{
+		Status: configv1.ConfigVersion_STATUS_APPLIED.Enum(),
+		Config: configv1.McpAnyServerConfig_builder{
+			GlobalSettings: configv1.GlobalSettings_builder{ApiKey: proto.String("super-secret-key")}.Build(),
+		}.Build(),
+	}.Build()
+	require.NoError(t, app.Storage.SaveConfigVersion(ctx, secret))
+
+	handler := app.createAPIHandler(app.Storage)
+	serve := func(method, target string, admin bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, target, nil)
+		if admin {
+			r = r.WithContext(auth.ContextWithRoles(r.Context(), []string{"admin"}))
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("forbidden", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, serve(http.MethodGet, "/config/versions", false).Code)
+		assert.Equal(t, http.StatusForbidden, serve(http.MethodPost, "/config/versions/1/rollback", false).Code)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		w := serve(http.MethodGet, "/config/versions?limit=2", true)
+		require.Equal(t, http.StatusOK, w.Code)
+		var versions []map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &versions))
+		require.Len(t, versions, 2)
+		assert.Equal(t, "3", versions[0]["version"])
+		assert.Equal(t, "STATUS_FAILED", versions[1]["status"])
+		assert.NotContains(t, versions[0], "config")
+		assert.NotContains(t, w.Body.String(), "super-secret-key")
+
+		assert.Equal(t, http.StatusBadRequest, serve(http.MethodGet, "/config/versions?limit=x", true).Code)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		w := serve(http.MethodGet, "/config/versions/1", true)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"upstream_services"`)
+
+		w = serve(http.MethodGet, "/config/versions/3", true)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "super-secret-key")
+
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/config/versions/42", true).Code)
+		assert.Equal(t, http.StatusBadRequest, serve(http.MethodGet, "/config/versions/latest", true).Code)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		assert.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodGet, "/config/versions/1/rollback", true).Code)
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, "/config/versions/42/rollback", true).Code)
+		assert.Equal(t, http.StatusConflict, serve(http.MethodPost, "/config/versions/2/rollback", true).Code)
+
+		w := serve(http.MethodPost, "/config/versions/1/rollback", true)
+		require.Equal(t, http.StatusOK, w.Code)
+		var v map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &v))
+		assert.Equal(t, "4", v["version"])
+		assert.Equal(t, "1", v["rollback_of"])
+		assert.Equal(t, "rollback", v["trigger"])
+		assert.NotContains(t, v, "config")
+	})
+
+	t.Run("no storage", func(t *testing.T) {
+		noStore := NewApplication()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/config/versions", nil)
+		noStore.handleConfigVersions(w, r.WithContext(auth.ContextWithRoles(r.Context(), []string{"admin"})))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+}
+
+func TestAdminAPIReloadTrigger(t *testing.T) {
+	var trigger string
+	handler := withAdminAPIReloadTrigger(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		trigger = reloadTrigger(r.Context())
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/services", nil))
+	assert.Equal(t, "admin api: POST /services", trigger)
+}
@@ -16,6 +16,7 @@ import (
 
 	configv1 "github.com/mcpany/core/proto/config/v1"
 	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/expiry"
 	"github.com/mcpany/core/server/pkg/util"
 	"github.com/mcpany/core/server/pkg/validation"
 	"google.golang.org/protobuf/encoding/protojson"
@@ -199,6 +200,10 @@ func (a *Application) createCredentialHandler(w http.ResponseWriter, r *http.Req
 			return
 		}
 	}
+	if err := expiry.StampRotation(&cred, nil, time.Now()); err != nil {
+		writeError(w, err)
+		return
+	}
 
 	if err := a.Storage.SaveCredential(ctx, &cred); err != nil {
 		writeError(w, err)
@@ -246,6 +251,16 @@ func (a *Application) updateCredentialHandler(w http.ResponseWriter, r *http.Req
 	}
 	cred.SetId(id)
 
+	existing, err := a.Storage.GetCredential(ctx, id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := expiry.StampRotation(&cred, existing, time.Now()); err != nil {
+		writeError(w, err)
+		return
+	}
+
 	if err := a.Storage.SaveCredential(ctx, &cred); err != nil {
 		writeError(w, err)
 		return
@@ -0,0 +1,62 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/logging"
+)
+
+// handleDrain drains the server ahead of its termination.
+//
+// POST /drain starts the drain and waits for it to end, so that a Kubernetes
+// preStop hook can call it; with ?wait=false it returns at once with 202
+// Accepted. GET /drain reports the progress of the drain.
+//
+// Summary: Drains the server. Admin only.
+//
+// Parameters:
+//   - w: http.ResponseWriter. The response writer.
+//   - r: *http.Request. The HTTP request.
+//
+// Side Effects:
+//   - Starts the drain on POST. It cannot be undone.
+//   - Writes the drain status as JSON.
+func (a *Application) handleDrain(w http.ResponseWriter, r *http.Request) {
+	if !auth.NewRBACEnforcer().HasRoleInContext(r.Context(), "admin") {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	code := http.StatusOK
+	var status DrainStatus
+	switch r.Method {
+	case http.MethodGet:
+		status = a.DrainStatus()
+	case http.MethodPost:
+		if r.URL.Query().Get("wait") == "false" {
+			ctx, cancel := context.WithCancel(r.Context())
+			cancel()
+			status = a.Drain(ctx)
+		} else {
+			status = a.Drain(r.Context())
+		}
+		if !status.ReadyToTerminate {
+			code = http.StatusAccepted
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		logging.GetLogger().Error("Failed to encode drain status", "error", err)
+	}
+}
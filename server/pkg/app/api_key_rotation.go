@@ -0,0 +1,61 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/alerts"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/webhooks"
+)
+
+const apiKeyAlertSource = "api-key-rotation"
+
+// apiKeyNotifier returns the notifier of rotated and expired client API keys.
+// The owner of the key is notified at its webhook with a signed request,
+// which only reaches the addresses the SSRF protection allows; operators get
+// an alert, which never contains the new key.
+//
+// Returns:
+//   - auth.APIKeyNotifier: The notifier.
+func (a *Application) apiKeyNotifier() auth.APIKeyNotifier {
+	webhook := auth.WebhookAPIKeyNotifier(webhooks.NewSafeOutboundClient(10 * time.Second))
+	return func(ctx context.Context, event auth.APIKeyEvent) error {
+		// The notification is retried if the webhook fails, so the alert
+		// waits for the delivery.
+		if err := webhook(ctx, event); err != nil {
+			return err
+		}
+		if a.AlertsManager == nil {
+			return nil
+		}
+		alert := &alerts.Alert{
+			Severity: alerts.SeverityInfo,
+			Status:   alerts.StatusActive,
+			Source:   apiKeyAlertSource,
+		}
+		switch event.Type {
+		case auth.APIKeyEventRotated:
+			alert.Title = fmt.Sprintf("API key %q was rotated", event.Name)
+			alert.Message = fmt.Sprintf("Key %s was replaced by %s and stays valid until %s.", event.KeyID, event.ReplacedBy, event.ExpiresAt)
+		case auth.APIKeyEventExpired:
+			alert.Title = fmt.Sprintf("Rotated API key %q expired", event.Name)
+			alert.Message = fmt.Sprintf("Key %s was used %d times after it was replaced by %s.", event.KeyID, event.DeprecatedUseCount, event.ReplacedBy)
+			if event.DeprecatedUseCount > 0 {
+				alert.Severity = alerts.SeverityWarning
+				alert.Message += fmt.Sprintf(" The last use was at %s; clients still using it are now rejected.", event.DeprecatedLastUsedAt)
+			}
+		default:
+			return nil
+		}
+		if event.Owner != "" {
+			alert.Message += fmt.Sprintf(" Owner: %s.", event.Owner)
+		}
+		a.AlertsManager.CreateAlert(alert)
+		return nil
+	}
+}
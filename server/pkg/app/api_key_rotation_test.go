@@ -0,0 +1,59 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mcpany/core/server/pkg/alerts"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyNotifier_Alerts(t *testing.T) {
+	app := NewApplication()
+	app.AlertsManager = alerts.NewManager()
+	notify := app.apiKeyNotifier()
+
+	findAlert := func() *alerts.Alert {
+		for _, a := range app.AlertsManager.ListAlerts() {
+			if a.Source == apiKeyAlertSource {
+				return a
+			}
+		}
+		return nil
+	}
+
+	require.NoError(t, notify(context.Background(), auth.APIKeyEvent{
+		Type:       auth.APIKeyEventRotated,
+		KeyID:      "old",
+		Name:       "ci",
+		Owner:      "platform-team",
+		ReplacedBy: "new",
+		Key:        "mcpany_new_secret",
+		ExpiresAt:  "2026-01-02T00:00:00Z",
+	}))
+	rotated := findAlert()
+	require.NotNil(t, rotated)
+	assert.Equal(t, alerts.SeverityInfo, rotated.Severity)
+	assert.Contains(t, rotated.Title, `"ci"`)
+	assert.Contains(t, rotated.Message, "platform-team")
+	assert.NotContains(t, rotated.Message, "mcpany_new_secret")
+
+	app.AlertsManager = alerts.NewManager()
+	require.NoError(t, notify(context.Background(), auth.APIKeyEvent{
+		Type:                 auth.APIKeyEventExpired,
+		KeyID:                "old",
+		Name:                 "ci",
+		ReplacedBy:           "new",
+		DeprecatedUseCount:   4,
+		DeprecatedLastUsedAt: "2026-01-01T23:00:00Z",
+	}))
+	expired := findAlert()
+	require.NotNil(t, expired)
+	assert.Equal(t, alerts.SeverityWarning, expired.Severity)
+	assert.Contains(t, expired.Message, "used 4 times")
+}
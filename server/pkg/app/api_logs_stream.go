@@ -0,0 +1,120 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/logging"
+)
+
+// logStreamKeepAlive is the interval of the comments keeping an idle log
+// stream open through proxies.
+var logStreamKeepAlive = 15 * time.Second
+
+// handleLogsStream streams the server logs as server-sent events, starting
+// with the recent history, filtered by service, level and age.
+//
+// Summary: Streams the filtered server logs over SSE. Admin only.
+//
+// Parameters:
+//   - w: http.ResponseWriter. The response writer.
+//   - r: *http.Request. The HTTP request, with the optional service, level,
+//     since (a duration) and follow (default true) query parameters.
+//
+// Side Effects:
+//   - Writes one "data:" event per log entry until the client disconnects,
+//     or after the history if follow is false.
+func (a *Application) handleLogsStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// The logs carry the details of every user's calls.
+	if !auth.NewRBACEnforcer().HasRoleInContext(r.Context(), "admin") {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	q := r.URL.Query()
+	filter := logging.Filter{Service: q.Get("service")}
+	if v := q.Get("level"); v != "" {
+		level, err := logging.ParseLevel(v)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		filter.MinLevel = &level
+	}
+	if v := q.Get("since"); v != "" {
+		since, err := time.ParseDuration(v)
+		if err != nil || since < 0 {
+			http.Error(w, "invalid since: must be a duration such as 10m", http.StatusBadRequest)
+			return
+		}
+		filter.Since = time.Now().Add(-since)
+	}
+	follow := q.Get("follow") != "false"
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	ch, history := logging.GlobalBroadcaster.SubscribeWithHistory()
+	defer logging.GlobalBroadcaster.Unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	for _, msg := range history {
+		if err := writeLogEvent(w, filter, msg); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+	if !follow {
+		return
+	}
+
+	keepAlive := time.NewTicker(logStreamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := writeLogEvent(w, filter, msg); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// writeLogEvent writes a log entry passing the filter as a server-sent event.
+func writeLogEvent(w http.ResponseWriter, filter logging.Filter, msg any) error {
+	entry, ok := logging.AsLogEntry(msg)
+	if !ok || !filter.Match(entry) {
+		return nil
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil // Skip entries that cannot be encoded.
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+	return err
+}
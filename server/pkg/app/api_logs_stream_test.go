@@ -0,0 +1,108 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleLogsStream(t *testing.T) {
+	logging.GlobalBroadcaster.Reset()
+	t.Cleanup(logging.GlobalBroadcaster.Reset)
+	now := time.Now().UTC()
+	logging.GlobalBroadcaster.Broadcast(logging.LogEntry{ID: "old", Timestamp: now.Add(-time.Hour).Format(time.RFC3339),
+		Level: "ERROR", Message: "old failure", Metadata: map[string]any{"service": "weather"}})
+	logging.GlobalBroadcaster.Broadcast(logging.LogEntry{ID: "info", Timestamp: now.Format(time.RFC3339),
+		Level: "INFO", Message: "registered", Metadata: map[string]any{"service": "weather"}})
+	logging.GlobalBroadcaster.Broadcast(logging.LogEntry{ID: "other", Timestamp: now.Format(time.RFC3339),
+		Level: "ERROR", Message: "github down", Metadata: map[string]any{"service": "github"}})
+	logging.GlobalBroadcaster.Broadcast(logging.LogEntry{ID: "match", Timestamp: now.Format(time.RFC3339),
+		Level: "ERROR", Message: "weather down", Metadata: map[string]any{"toolName": "weather.get_forecast"}})
+
+	app := NewApplication()
+	admin := func(target string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		return r.WithContext(auth.ContextWithRoles(r.Context(), []string{"admin"}))
+	}
+	ids := func(body string) []string {
+		var got []string
+		for _, line := range strings.Split(body, "\n") {
+			if data, ok := strings.CutPrefix(line, "data: "); ok {
+				var entry logging.LogEntry
+				require.NoError(t, json.Unmarshal([]byte(data), &entry))
+				got = append(got, entry.ID)
+			}
+		}
+		return got
+	}
+
+	t.Run("forbidden", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		app.handleLogsStream(w, httptest.NewRequest(http.MethodGet, "/api/v1/logs/stream", nil))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("history", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		app.handleLogsStream(w, admin("/api/v1/logs/stream?follow=false"))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+		assert.Equal(t, []string{"old", "info", "other", "match"}, ids(w.Body.String()))
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		app.handleLogsStream(w, admin("/api/v1/logs/stream?follow=false&service=weather&level=error&since=10m"))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"match"}, ids(w.Body.String()))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, q := range []string{"level=loud", "since=yesterday", "since=-1m"} {
+			w := httptest.NewRecorder()
+			app.handleLogsStream(w, admin("/api/v1/logs/stream?"+q))
+			assert.Equal(t, http.StatusBadRequest, w.Code, q)
+		}
+	})
+
+	t.Run("follow", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			app.handleLogsStream(w, r.WithContext(auth.ContextWithRoles(r.Context(), []string{"admin"})))
+		}))
+		defer srv.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"?service=github", nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+
+		scanner := bufio.NewScanner(resp.Body)
+		next := func() string {
+			for scanner.Scan() {
+				if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+					return data
+				}
+			}
+			return ""
+		}
+		assert.Contains(t, next(), "github down")
+		logging.GlobalBroadcaster.Broadcast(logging.LogEntry{ID: "skipped", Level: "ERROR", Metadata: map[string]any{"service": "weather"}})
+		logging.GlobalBroadcaster.Broadcast(logging.LogEntry{ID: "live", Level: "WARN", Message: "github slow", Metadata: map[string]any{"service": "github"}})
+		assert.Contains(t, next(), "github slow")
+	})
+}
@@ -11,6 +11,9 @@ import (
 	"time"
 
 	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/secretusage"
 	"github.com/mcpany/core/server/pkg/util"
 	"google.golang.org/protobuf/encoding/protojson"
 )
@@ -157,16 +160,80 @@ func (a *Application) revealSecretHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	user, _ := auth.UserFromContext(ctx)
+	if !secretusage.CanReveal(ctx, secret) {
+		logging.GetLogger().Warn("Secret reveal denied", "id", id, "user", user)
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "forbidden: secret is restricted to roles " + strings.Join(secret.GetAllowedRoles(), ", ")})
+		return
+	}
+
 	// Update last used
-	secret.SetLastUsed(time.Now().Format(time.RFC3339))
+	secretusage.RecordRead(secret, secretusage.AdminConsumer(user), time.Now())
 	if err := a.Storage.SaveSecret(ctx, secret); err != nil {
 		// Log error but proceed?
-		fmt.Printf("Failed to update last used for secret %s: %v\n", id, err)
+		logging.GetLogger().Warn("Failed to record secret usage", "id", id, "error", err)
 	}
 
 	writeJSON(w, http.StatusOK, map[string]string{"value": secret.GetValue()})
 }
 
+// secretUsageHandler reports where a secret is referenced and who has read it.
+func (a *Application) secretUsageHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, fmt.Errorf("method not allowed"))
+		return
+	}
+	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	// /api/v1/secrets/:id/usage -> ["api", "v1", "secrets", "id", "usage"]
+	if len(pathParts) < 5 || pathParts[4] != "usage" {
+		writeError(w, fmt.Errorf("invalid path"))
+		return
+	}
+	id := pathParts[3]
+
+	ctx := r.Context()
+	var services []*configv1.UpstreamServiceConfig
+	if a.ServiceRegistry != nil {
+		registered, err := a.ServiceRegistry.GetAllServices()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		services = registered
+	}
+	report, err := secretusage.Lookup(ctx, a.Storage, id, services)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if report == nil {
+		writeError(w, fmt.Errorf("secret not found: %s", id))
+		return
+	}
+
+	usage := make([]map[string]any, 0, len(report.Secret.GetUsage()))
+	for _, u := range report.Secret.GetUsage() {
+		usage = append(usage, map[string]any{
+			"consumer":  u.GetConsumer(),
+			"count":     u.GetCount(),
+			"last_used": u.GetLastUsed(),
+		})
+	}
+	references := report.References
+	if references == nil {
+		references = []secretusage.Reference{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":            report.Secret.GetId(),
+		"name":          report.Secret.GetName(),
+		"key":           report.Secret.GetKey(),
+		"allowed_roles": report.Secret.GetAllowedRoles(),
+		"last_used":     report.Secret.GetLastUsed(),
+		"references":    references,
+		"usage":         usage,
+	})
+}
+
 func sanitizeSecret(s *configv1.Secret) {
 	if s == nil {
 		return
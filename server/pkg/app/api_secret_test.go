@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
 	"github.com/mcpany/core/server/pkg/storage/memory"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -111,3 +112,69 @@ func TestSecretCRUD(t *testing.T) {
 		assert.Nil(t, s)
 	})
 }
+
+func TestSecretRevealRolesAndUsage(t *testing.T) {
+	app := NewApplication()
+	app.Storage = memory.NewStore()
+	ctx := context.Background()
+	require.NoError(t, app.Storage.SaveSecret(ctx, configv1.Secret_builder{
+		Id:           proto.String("prod-db"),
+		Name:         proto.String("Prod DB"),
+		Key:          proto.String("PROD_DB_PASSWORD"),
+		Value:        proto.String("hunter2"),
+		AllowedRoles: []string{"dba"},
+	}.Build()))
+
+	reveal := func(roles ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/secrets/prod-db/reveal", nil)
+		reqCtx := auth.ContextWithUser(req.Context(), "alice")
+		reqCtx = auth.ContextWithRoles(reqCtx, roles)
+		w := httptest.NewRecorder()
+		app.revealSecretHandler(w, req.WithContext(reqCtx))
+		return w
+	}
+
+	t.Run("Forbidden Without Role", func(t *testing.T) {
+		w := reveal("viewer")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.NotContains(t, w.Body.String(), "hunter2")
+
+		s, err := app.Storage.GetSecret(ctx, "prod-db")
+		require.NoError(t, err)
+		assert.Empty(t, s.GetUsage())
+	})
+
+	t.Run("Allowed Role", func(t *testing.T) {
+		w := reveal("dba")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "hunter2")
+	})
+
+	t.Run("Usage", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/secrets/prod-db/usage", nil)
+		w := httptest.NewRecorder()
+		app.secretUsageHandler(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "hunter2")
+		var resp struct {
+			AllowedRoles []string `json:"allowed_roles"`
+			LastUsed     string   `json:"last_used"`
+			Usage        []struct {
+				Consumer string `json:"consumer"`
+				Count    int64  `json:"count"`
+			} `json:"usage"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, []string{"dba"}, resp.AllowedRoles)
+		assert.NotEmpty(t, resp.LastUsed)
+		require.Len(t, resp.Usage, 1)
+		assert.Equal(t, "admin-api:alice", resp.Usage[0].Consumer)
+		assert.Equal(t, int64(1), resp.Usage[0].Count)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/secrets/missing/usage", nil)
+		w = httptest.NewRecorder()
+		app.secretUsageHandler(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -181,6 +181,8 @@ func toProtoSkill(sk *skill.Skill) *config_v1.Skill {
 		AllowedTools: sk.AllowedTools,
 		Assets:       sk.Assets,
 		Metadata:     sk.Metadata,
+		Version:      proto.String(sk.Version),
+		Profiles:     sk.Profiles,
 	}.Build()
 }
 
@@ -192,6 +194,8 @@ func fromProtoSkill(pbSkill *config_v1.Skill) *skill.Skill {
 			License:      pbSkill.GetLicense(),
 			AllowedTools: pbSkill.GetAllowedTools(),
 			Metadata:     pbSkill.GetMetadata(),
+			Version:      pbSkill.GetVersion(),
+			Profiles:     pbSkill.GetProfiles(),
 		},
 		Instructions: pbSkill.GetInstructions(),
 		Assets:       pbSkill.GetAssets(),
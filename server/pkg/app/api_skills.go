@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/mcpany/core/server/pkg/logging"
@@ -188,3 +189,33 @@ func (a *Application) handleUploadSkillAsset() http.HandlerFunc {
 		w.WriteHeader(http.StatusOK)
 	}
 }
+
+// handleInstallSkillPackage returns a handler that installs a skill package
+// written by `mcpctl skill package`.
+// POST /api/v1/skill-packages
+// Query Params: sha256 (expected package checksum), force (allow downgrades).
+func (a *Application) handleInstallSkillPackage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+		sk, err := a.SkillManager.InstallPackage(r.Body, skill.InstallOptions{
+			SHA256: r.URL.Query().Get("sha256"),
+			Force:  force,
+		})
+		if err != nil {
+			logging.GetLogger().Warn("Failed to install skill package", "error", err)
+			status := http.StatusBadRequest
+			if errors.Is(err, skill.ErrNotNewer) {
+				status = http.StatusConflict
+			}
+			http.Error(w, fmt.Sprintf("Failed to install skill package: %v", err), status)
+			return
+		}
+		logging.GetLogger().Info("Installed skill package", "name", sk.Name, "version", sk.Version)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(sk)
+	}
+}
@@ -119,3 +119,49 @@ type skillErrorReader struct{}
 func (e *skillErrorReader) Read(p []byte) (n int, err error) {
 	return 0, io.ErrUnexpectedEOF
 }
+
+func TestHandleInstallSkillPackage(t *testing.T) {
+	manager, _ := setupSkillManagerForHTTPTest(t)
+	app := &Application{
+		SkillManager: manager,
+	}
+	handler := app.handleInstallSkillPackage()
+
+	packageSkill := func(version string) []byte {
+		dir := t.TempDir()
+		content := "---\nname: report-writer\ndescription: Writes reports\nversion: " + version + "\n---\n\nWrite the report."
+		require.NoError(t, os.WriteFile(filepath.Join(dir, skill.SkillFileName), []byte(content), 0644))
+		var buf bytes.Buffer
+		_, err := skill.Package(dir, &buf)
+		require.NoError(t, err)
+		return buf.Bytes()
+	}
+	install := func(query string, pkg []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/skill-packages"+query, bytes.NewReader(pkg))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := install("", packageSkill("1.0.0"))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"version":"1.0.0"`)
+
+	w = install("", packageSkill("0.9.0"))
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = install("?force=true", packageSkill("0.9.0"))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = install("?sha256=00", packageSkill("2.0.0"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "package checksum mismatch")
+
+	sk, err := manager.GetSkill("report-writer")
+	require.NoError(t, err)
+	assert.Equal(t, "0.9.0", sk.Version)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/skill-packages", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
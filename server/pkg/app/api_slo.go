@@ -0,0 +1,45 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/slo"
+)
+
+// handleSLOs serves the compliance and error budget of the service level
+// objectives.
+//
+// Summary: Lists SLO status, optionally filtered by the "service" query parameter.
+//
+// Parameters:
+//   - w: http.ResponseWriter. The response writer.
+//   - r: *http.Request. The HTTP request.
+//
+// Side Effects:
+//   - Writes the SLO statuses as JSON.
+func (a *Application) handleSLOs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	statuses := []slo.Status{}
+	if a.SLOTracker != nil {
+		service := r.URL.Query().Get("service")
+		for _, st := range a.SLOTracker.Status() {
+			if service == "" || st.Service == service {
+				statuses = append(statuses, st)
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(statuses); err != nil {
+		logging.GetLogger().Error("Failed to encode SLO status", "error", err)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/slo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestHandleSLOs(t *testing.T) {
+	app := NewApplication()
+
+	t.Run("no tracker", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		app.handleSLOs(w, httptest.NewRequest(http.MethodGet, "/api/v1/slos", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[]`, w.Body.String())
+	})
+
+	app.SLOTracker = slo.NewTracker(nil, slo.WithSource(func() (map[string]slo.Counts, error) {
+		return map[string]slo.Counts{}, nil
+	}))
+	objective := configv1.ServiceLevelObjective_builder{Name: proto.String("availability"), Target: proto.Float64(99.9)}.Build()
+	app.SLOTracker.SetObjectives([]*configv1.UpstreamServiceConfig{
+		configv1.UpstreamServiceConfig_builder{Name: proto.String("billing"), Slos: []*configv1.ServiceLevelObjective{objective}}.Build(),
+		configv1.UpstreamServiceConfig_builder{Name: proto.String("search"), Slos: []*configv1.ServiceLevelObjective{objective}}.Build(),
+	})
+
+	t.Run("list", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		app.handleSLOs(w, httptest.NewRequest(http.MethodGet, "/api/v1/slos", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var statuses []slo.Status
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &statuses))
+		require.Len(t, statuses, 2)
+		assert.Equal(t, "billing", statuses[0].Service)
+		assert.Equal(t, 99.9, statuses[0].Target)
+		assert.Equal(t, float64(1), statuses[0].ErrorBudgetRemaining)
+	})
+
+	t.Run("filter by service", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		app.handleSLOs(w, httptest.NewRequest(http.MethodGet, "/api/v1/slos?service=search", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var statuses []slo.Status
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &statuses))
+		require.Len(t, statuses, 1)
+		assert.Equal(t, "search", statuses[0].Service)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		app.handleSLOs(w, httptest.NewRequest(http.MethodPost, "/api/v1/slos", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/slowcall"
+)
+
+// handleSlowCalls serves the rolling report of the tool calls slower than
+// their threshold.
+//
+// Summary: Returns the slowest recent tool calls with their timing breakdown. Admin only.
+//
+// Parameters:
+//   - w: http.ResponseWriter. The response writer.
+//   - r: *http.Request. The HTTP request.
+//
+// Side Effects:
+//   - Writes the report as JSON.
+func (a *Application) handleSlowCalls(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// The report carries the trace IDs and errors of every user's calls.
+	if !auth.NewRBACEnforcer().HasRoleInContext(r.Context(), "admin") {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	report := slowcall.Report{Calls: []slowcall.Call{}, Tools: []slowcall.ToolSummary{}}
+	if a.SlowCalls != nil {
+		report = a.SlowCalls.Report(limit)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		logging.GetLogger().Error("Failed to encode slow call report", "error", err)
+	}
+}
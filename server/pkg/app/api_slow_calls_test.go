@@ -0,0 +1,62 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/slowcall"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleSlowCalls(t *testing.T) {
+	app := NewApplication()
+	admin := func(target string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		return r.WithContext(auth.ContextWithRoles(r.Context(), []string{"admin"}))
+	}
+
+	t.Run("forbidden", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		app.handleSlowCalls(w, httptest.NewRequest(http.MethodGet, "/api/v1/stats/slow-calls", nil))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("no recorder", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		app.handleSlowCalls(w, admin("/api/v1/stats/slow-calls"))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"window":"","calls":[],"tools":[]}`, w.Body.String())
+	})
+
+	app.SlowCalls = slowcall.NewRecorder(nil)
+	now := time.Now()
+	app.SlowCalls.Record(slowcall.Call{Timestamp: now, Tool: "weather.get_forecast", DurationMs: 3000})
+	app.SlowCalls.Record(slowcall.Call{Timestamp: now, Tool: "weather.get_forecast", DurationMs: 7000, UpstreamMs: 6500})
+
+	t.Run("report", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		app.handleSlowCalls(w, admin("/api/v1/stats/slow-calls?limit=1"))
+		require.Equal(t, http.StatusOK, w.Code)
+		var report slowcall.Report
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		require.Len(t, report.Calls, 1)
+		assert.Equal(t, int64(7000), report.Calls[0].DurationMs)
+		assert.Equal(t, int64(6500), report.Calls[0].UpstreamMs)
+		require.Len(t, report.Tools, 1)
+		assert.Equal(t, 2, report.Tools[0].Count)
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		app.handleSlowCalls(w, admin("/api/v1/stats/slow-calls?limit=ten"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -21,6 +21,9 @@ type SystemStatusResponse struct {
 	BoundGRPCPort     int      `json:"bound_grpc_port"`
 	Version           string   `json:"version"`
 	SecurityWarnings  []string `json:"security_warnings"`
+	// StartupDiscovery is the summary of the discovery of the upstream
+	// services at startup, once it is done.
+	StartupDiscovery *StartupDiscoverySummary `json:"startup_discovery,omitempty"`
 }
 
 func (a *Application) handleSystemStatus(w http.ResponseWriter, _ *http.Request) {
@@ -42,6 +45,7 @@ func (a *Application) handleSystemStatus(w http.ResponseWriter, _ *http.Request)
 		BoundGRPCPort:     int(a.BoundGRPCPort.Load()),
 		Version:           appconsts.Version,
 		SecurityWarnings:  warnings,
+		StartupDiscovery:  a.StartupDiscovery(),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -1171,7 +1171,7 @@ func TestHandleAuditExport(t *testing.T) {
 	app.standardMiddlewares.Audit = audit
 	defer audit.Close()
 
-	req, _ := http.NewRequest("GET", "/audit/export?tool_name=tool-1", nil)
+	req := newAuditRequest(http.MethodGet, "/audit/export?tool_name=tool-1")
 	rr := httptest.NewRecorder()
 	mux := app.createAPIHandler(app.Storage)
 	mux.ServeHTTP(rr, req)
@@ -1475,6 +1475,69 @@ func (s *MockServiceStore) SaveCredential(ctx context.Context, cred *configv1.Cr
 	return nil
 }
 func (s *MockServiceStore) DeleteCredential(ctx context.Context, id string) error { return nil }
+func (s *MockServiceStore) ListAPIKeys(ctx context.Context) ([]*configv1.ClientApiKey, error) {
+	return nil, nil
+}
+func (s *MockServiceStore) GetAPIKey(ctx context.Context, id string) (*configv1.ClientApiKey, error) {
+	return nil, nil
+}
+func (s *MockServiceStore) SaveAPIKey(ctx context.Context, key *configv1.ClientApiKey) error {
+	return nil
+}
+func (s *MockServiceStore) DeleteAPIKey(ctx context.Context, id string) error {
+	return nil
+}
+func (s *MockServiceStore) SaveConfigVersion(ctx context.Context, version *configv1.ConfigVersion) error {
+	return nil
+}
+func (s *MockServiceStore) ListConfigVersions(ctx context.Context, limit int) ([]*configv1.ConfigVersion, error) {
+	return nil, nil
+}
+func (s *MockServiceStore) GetConfigVersion(ctx context.Context, version int64) (*configv1.ConfigVersion, error) {
+	return nil, nil
+}
+func (s *MockServiceStore) PruneConfigVersions(ctx context.Context, keep int) error {
+	return nil
+}
+func (s *MockServiceStore) SaveToolCatalogSnapshot(ctx context.Context, snapshot *configv1.ToolCatalogSnapshot) error {
+	return nil
+}
+func (s *MockServiceStore) ListToolCatalogSnapshots(ctx context.Context) ([]*configv1.ToolCatalogSnapshot, error) {
+	return nil, nil
+}
+func (s *MockServiceStore) DeleteToolCatalogSnapshot(ctx context.Context, serviceID string) error {
+	return nil
+}
+func (s *MockServiceStore) SaveWebhookDeadLetter(ctx context.Context, letter *configv1.WebhookDeadLetter) error {
+	return nil
+}
+func (s *MockServiceStore) ListWebhookDeadLetters(ctx context.Context, limit int) ([]*configv1.WebhookDeadLetter, error) {
+	return nil, nil
+}
+func (s *MockServiceStore) GetWebhookDeadLetter(ctx context.Context, id string) (*configv1.WebhookDeadLetter, error) {
+	return nil, nil
+}
+func (s *MockServiceStore) DeleteWebhookDeadLetter(ctx context.Context, id string) error {
+	return nil
+}
+func (s *MockServiceStore) SaveWorkerJob(ctx context.Context, job *configv1.WorkerJob) error {
+	return nil
+}
+func (s *MockServiceStore) ListWorkerJobs(ctx context.Context, limit int) ([]*configv1.WorkerJob, error) {
+	return nil, nil
+}
+func (s *MockServiceStore) GetWorkerJob(ctx context.Context, id string) (*configv1.WorkerJob, error) {
+	return nil, nil
+}
+func (s *MockServiceStore) ClaimWorkerJob(ctx context.Context, id, owner, now, leaseUntil string) (bool, error) {
+	return false, nil
+}
+func (s *MockServiceStore) DeleteWorkerJob(ctx context.Context, id string) error {
+	return nil
+}
+func (s *MockServiceStore) DeleteWorkerJobsCompletedBefore(ctx context.Context, before string) error {
+	return nil
+}
 func (s *MockServiceStore) ListServiceTemplates(ctx context.Context) ([]*configv1.ServiceTemplate, error) {
 	return nil, nil
 }
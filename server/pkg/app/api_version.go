@@ -0,0 +1,42 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mcpany/core/server/pkg/appconsts"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/buildinfo"
+	"github.com/mcpany/core/server/pkg/logging"
+)
+
+// handleVersion serves the build information of the running server, including
+// Go module versions and the embedded SBOM and provenance.
+//
+// Summary: Returns what the running deployment was built from. Admin only.
+//
+// Parameters:
+//   - w: http.ResponseWriter. The response writer.
+//   - r: *http.Request. The HTTP request.
+//
+// Side Effects:
+//   - Writes the build information as JSON.
+func (a *Application) handleVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// The dependency list helps attackers as much as auditors.
+	if !auth.NewRBACEnforcer().HasRoleInContext(r.Context(), "admin") {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(buildinfo.Get(appconsts.Version)); err != nil {
+		logging.GetLogger().Error("Failed to encode version response", "error", err)
+	}
+}
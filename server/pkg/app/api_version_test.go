@@ -0,0 +1,53 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mcpany/core/server/pkg/appconsts"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/buildinfo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleVersion(t *testing.T) {
+	app := NewApplication()
+
+	t.Run("admin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/version", nil)
+		req = req.WithContext(auth.ContextWithRoles(context.Background(), []string{"admin"}))
+		w := httptest.NewRecorder()
+		app.handleVersion(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var info buildinfo.Info
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&info))
+		assert.Equal(t, appconsts.Version, info.Version)
+		assert.NotEmpty(t, info.GoVersion)
+		assert.NotEmpty(t, info.Modules)
+	})
+
+	t.Run("non-admin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/version", nil)
+		req = req.WithContext(auth.ContextWithRoles(context.Background(), []string{"viewer"}))
+		w := httptest.NewRecorder()
+		app.handleVersion(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("wrong method", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/version", nil)
+		w := httptest.NewRecorder()
+		app.handleVersion(w, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	config_v1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/logging"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// handleWebhookDeadLetters lists the post-call webhook events that failed
+// after their retries, newest first.
+//
+// Summary: Returns the webhook dead-letter queue. Admin only.
+//
+// Parameters:
+//   - w: http.ResponseWriter. The response writer.
+//   - r: *http.Request. The HTTP request.
+//
+// Side Effects:
+//   - Writes the dead letters as a JSON array.
+func (a *Application) handleWebhookDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !a.authorizeWebhookDeadLetters(w, r) {
+		return
+	}
+
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	letters, err := a.Storage.ListWebhookDeadLetters(r.Context(), limit)
+	if err != nil {
+		logging.GetLogger().Error("Failed to list webhook dead letters", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	parts := make([]string, 0, len(letters))
+	for _, letter := range letters {
+		b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(letter)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		parts = append(parts, string(b))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte("[" + strings.Join(parts, ",") + "]"))
+}
+
+// handleWebhookDeadLetterDetail serves, replays and deletes a webhook dead
+// letter.
+//
+// GET /webhooks/dlq/{id} returns the dead letter, POST
+// /webhooks/dlq/{id}/replay sends its event to the webhook again and DELETE
+// /webhooks/dlq/{id} discards it.
+//
+// Summary: Manages a webhook dead letter. Admin only.
+//
+// Parameters:
+//   - w: http.ResponseWriter. The response writer.
+//   - r: *http.Request. The HTTP request.
+//
+// Side Effects:
+//   - Invokes the webhook on replay and updates the store.
+func (a *Application) handleWebhookDeadLetterDetail(w http.ResponseWriter, r *http.Request) {
+	if !a.authorizeWebhookDeadLetters(w, r) {
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/webhooks/dlq/")
+	id, replay := strings.CutSuffix(id, "/replay")
+	if id == "" || strings.Contains(id, "/") {
+		http.Error(w, "invalid dead letter id", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case replay && r.Method == http.MethodPost:
+		letter, err := a.ReplayWebhookDeadLetter(r.Context(), id)
+		switch {
+		case errors.Is(err, ErrDeadLetterNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, ErrDeadLetterHookNotFound):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusBadGateway)
+		default:
+			writeWebhookDeadLetter(w, letter)
+		}
+	case !replay && r.Method == http.MethodGet:
+		letter, err := a.Storage.GetWebhookDeadLetter(r.Context(), id)
+		if err != nil {
+			logging.GetLogger().Error("Failed to get webhook dead letter", "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		if letter == nil {
+			http.Error(w, ErrDeadLetterNotFound.Error(), http.StatusNotFound)
+			return
+		}
+		writeWebhookDeadLetter(w, letter)
+	case !replay && r.Method == http.MethodDelete:
+		if err := a.Storage.DeleteWebhookDeadLetter(r.Context(), id); err != nil {
+			logging.GetLogger().Error("Failed to delete webhook dead letter", "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// authorizeWebhookDeadLetters rejects the requests of non-admins, since the
+// dead letters carry the results of the tools, and the requests to a server
+// without storage.
+func (a *Application) authorizeWebhookDeadLetters(w http.ResponseWriter, r *http.Request) bool {
+	if !auth.NewRBACEnforcer().HasRoleInContext(r.Context(), "admin") {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return false
+	}
+	if a.Storage == nil {
+		http.Error(w, "the webhook dead-letter queue requires a storage backend", http.StatusServiceUnavailable)
+		return false
+	}
+	return true
+}
+
+// writeWebhookDeadLetter writes a webhook dead letter as JSON.
+func writeWebhookDeadLetter(w http.ResponseWriter, letter *config_v1.WebhookDeadLetter) {
+	b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(letter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
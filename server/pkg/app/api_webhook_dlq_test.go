@@ -0,0 +1,138 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/serviceregistry"
+	"github.com/mcpany/core/server/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// deadLetterTestRegistry serves the configuration of one service.
+type deadLetterTestRegistry struct {
+	serviceregistry.ServiceRegistryInterface
+	service *configv1.UpstreamServiceConfig
+}
+
+func (r *deadLetterTestRegistry) GetServiceConfig(serviceID string) (*configv1.UpstreamServiceConfig, bool) {
+	if serviceID != r.service.GetName() {
+		return nil, false
+	}
+	return r.service, true
+}
+
+func TestHandleWebhookDeadLetters(t *testing.T) {
+	t.Setenv("MCPANY_ALLOW_LOOPBACK_RESOURCES", "true")
+	t.Setenv("MCPANY_DANGEROUS_ALLOW_LOCAL_IPS", "true")
+	var healthy atomic.Bool
+	var calls atomic.Int32
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Ce-Id", "resp-id")
+		w.Header().Set("Ce-Specversion", "1.0")
+		w.Header().Set("Ce-Type", "resp-type")
+		w.Header().Set("Ce-Source", "server")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"allowed":true}`))
+	}))
+	defer webhook.Close()
+
+	store := memory.NewStore()
+	app := &Application{
+		Storage: store,
+		ServiceRegistry: &deadLetterTestRegistry{service: configv1.UpstreamServiceConfig_builder{
+			Name: proto.String("weather"),
+			PostCallHooks: []*configv1.CallHook{configv1.CallHook_builder{
+				Name:    proto.String("audit"),
+				Webhook: configv1.WebhookConfig_builder{Url: webhook.URL}.Build(),
+			}.Build()},
+		}.Build()},
+	}
+	ctx := context.Background()
+	for _, l := range []struct{ id, hook string }{{"dl-1", "audit"}, {"dl-2", "audit"}, {"dl-3", "removed"}} {
+		require.NoError(t, store.SaveWebhookDeadLetter(ctx, configv1.WebhookDeadLetter_builder{
+			Id:        l.id,
+			ServiceId: "weather",
+			HookName:  l.hook,
+			EventType: "com.mcpany.tool.post_call",
+			Payload:   `{"tool_name":"weather.forecast","result":{"temp":21}}`,
+			Attempts:  1,
+			FailedAt:  "2026-01-01T00:00:00.000Z",
+		}.Build()))
+	}
+
+	handler := app.createAPIHandler(store)
+	serve := func(method, target string, admin bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, target, nil)
+		if admin {
+			r = r.WithContext(auth.ContextWithRoles(r.Context(), []string{"admin"}))
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("forbidden", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, serve(http.MethodGet, "/webhooks/dlq", false).Code)
+		assert.Equal(t, http.StatusForbidden, serve(http.MethodPost, "/webhooks/dlq/dl-1/replay", false).Code)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		w := serve(http.MethodGet, "/webhooks/dlq?limit=2", true)
+		require.Equal(t, http.StatusOK, w.Code)
+		var letters []map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &letters))
+		assert.Len(t, letters, 2)
+		assert.Equal(t, http.StatusBadRequest, serve(http.MethodGet, "/webhooks/dlq?limit=x", true).Code)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		w := serve(http.MethodGet, "/webhooks/dlq/dl-1", true)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"hook_name":"audit"`)
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/webhooks/dlq/unknown", true).Code)
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		w := serve(http.MethodPost, "/webhooks/dlq/dl-1/replay", true)
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+		letter, err := store.GetWebhookDeadLetter(ctx, "dl-1")
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), letter.GetAttempts(), "a failed replay is kept with its error")
+		assert.NotEmpty(t, letter.GetLastReplayedAt())
+
+		healthy.Store(true)
+		w = serve(http.MethodPost, "/webhooks/dlq/dl-1/replay", true)
+		require.Equal(t, http.StatusOK, w.Code)
+		letter, err = store.GetWebhookDeadLetter(ctx, "dl-1")
+		require.NoError(t, err)
+		assert.Nil(t, letter, "a replayed dead letter is removed")
+		assert.Equal(t, int32(2), calls.Load())
+
+		assert.Equal(t, http.StatusConflict, serve(http.MethodPost, "/webhooks/dlq/dl-3/replay", true).Code)
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, "/webhooks/dlq/dl-1/replay", true).Code)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, serve(http.MethodDelete, "/webhooks/dlq/dl-2", true).Code)
+		letter, err := store.GetWebhookDeadLetter(ctx, "dl-2")
+		require.NoError(t, err)
+		assert.Nil(t, letter)
+	})
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/dashboard"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinDashboard(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/config.yaml", []byte("upstream_services: []"), 0o644))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Keep the logs of this server out of the history other tests read.
+	t.Cleanup(logging.GlobalBroadcaster.Reset)
+
+	app := NewApplication()
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- app.Run(RunOptions{Ctx: ctx, Fs: fs, JSONRPCPort: "127.0.0.1:0", GRPCPort: "127.0.0.1:0", ConfigPaths: []string{"/config.yaml"}, APIKey: "secret", ShutdownTimeout: 5 * time.Second})
+	}()
+	defer func() {
+		cancel()
+		<-errChan
+	}()
+	require.NoError(t, app.WaitForStartup(ctx))
+
+	get := func(path, key string) (int, []byte) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://127.0.0.1:%d%s", app.BoundHTTPPort.Load(), path), nil)
+		require.NoError(t, err)
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, body
+	}
+
+	code, body := get("/dashboard/", "")
+	require.Equal(t, http.StatusOK, code, "the page is served without credentials")
+	assert.Contains(t, string(body), "MCP Any Dashboard")
+
+	code, _ = get("/dashboard/api/me", "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	code, body = get("/dashboard/api/me", "secret")
+	require.Equal(t, http.StatusOK, code)
+	var me dashboard.Me
+	require.NoError(t, json.Unmarshal(body, &me))
+	assert.True(t, me.Admin)
+
+	code, _ = get("/dashboard/api/upstreams", "secret")
+	assert.Equal(t, http.StatusOK, code)
+}
@@ -0,0 +1,250 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	config_v1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/config"
+	"github.com/mcpany/core/server/pkg/configdiff"
+	"github.com/mcpany/core/server/pkg/doctor"
+	"github.com/mcpany/core/server/pkg/lint"
+	"github.com/spf13/afero"
+)
+
+// Verdicts of a dry run.
+const (
+	// DryRunPass means a reload would apply the configuration, and no check
+	// found a problem.
+	DryRunPass = "pass"
+	// DryRunWarn means a reload would apply the configuration, but an upstream
+	// is unreachable or the linter found a problem.
+	DryRunWarn = "warn"
+	// DryRunFail means a reload would reject the configuration.
+	DryRunFail = "fail"
+)
+
+// Statuses of the stages of a dry run.
+const (
+	stagePassed  = "passed"
+	stageWarning = "warning"
+	stageFailed  = "failed"
+	stageSkipped = "skipped"
+)
+
+// DryRunFinding is a problem a dry run found in a configuration.
+type DryRunFinding struct {
+	// Severity is error, warning or info.
+	Severity string `json:"severity"`
+	// Service is the name of the service the finding is about, if any.
+	Service string `json:"service,omitempty"`
+	// Path locates the finding in the configuration, if known.
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+// DryRunStage is the outcome of a step of a dry run.
+type DryRunStage struct {
+	// Name is parse, schema, validation, connectivity or lint.
+	Name string `json:"name"`
+	// Status is passed, warning, failed or skipped.
+	Status   string          `json:"status"`
+	Findings []DryRunFinding `json:"findings,omitempty"`
+}
+
+// DryRunChange is a difference between the running configuration and the
+// candidate.
+type DryRunChange struct {
+	// Path locates the field, or the tool, e.g. tools[weather.get_forecast].
+	Path string `json:"path"`
+	// Kind is added, removed or changed.
+	Kind string `json:"kind"`
+	// Risk explains why the change weakens the security of the server.
+	Risk string `json:"risk,omitempty"`
+}
+
+// DryRunVerdict is the outcome of a dry run of a candidate configuration.
+type DryRunVerdict struct {
+	// Verdict is pass, warn or fail.
+	Verdict string        `json:"verdict"`
+	Stages  []DryRunStage `json:"stages"`
+	// Changes lists the tools and fields that differ from the running
+	// configuration, when the candidate loads.
+	Changes []DryRunChange `json:"changes,omitempty"`
+}
+
+// DryRunOptions configures a dry run.
+type DryRunOptions struct {
+	// Format is the format of the candidate: yaml, json, toml or textproto.
+	Format string
+	// SkipConnectivity skips the connectivity checks of the upstreams.
+	SkipConnectivity bool
+}
+
+// DryRunConfig checks a candidate configuration the way a reload would load
+// it, without applying it: it parses the candidate, validates it against the
+// schema, loads and validates it merged with the configuration in storage,
+// checks that its upstreams are reachable and lints it. The running server is
+// not changed.
+//
+// Summary: Validates a candidate configuration without applying it.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the dry run.
+//   - content: []byte. The candidate configuration. Its includes are not resolved.
+//   - opts: DryRunOptions. The format of the candidate and the checks to skip.
+//
+// Returns:
+//   - *DryRunVerdict: The outcome of each stage, and the changes the candidate makes.
+//   - error: An error if the format is not supported.
+//
+// Side Effects:
+//   - Connects to the upstreams of the candidate, unless SkipConnectivity is set.
+func (a *Application) DryRunConfig(ctx context.Context, content []byte, opts DryRunOptions) (*DryRunVerdict, error) {
+	format := strings.ToLower(opts.Format)
+	if format == "" {
+		format = "yaml"
+		if json.Valid(content) {
+			format = "json"
+		}
+	}
+	path := "/candidate." + format
+	if _, err := config.NewEngine(path); err != nil {
+		return nil, fmt.Errorf("unsupported format %q", opts.Format)
+	}
+
+	v := &DryRunVerdict{Verdict: DryRunPass}
+	cfg := a.dryRunLoad(ctx, v, content, format, path)
+	if cfg == nil {
+		for _, name := range []string{"connectivity", "lint"} {
+			v.add(DryRunStage{Name: name, Status: stageSkipped})
+		}
+		return v, nil
+	}
+
+	if opts.SkipConnectivity {
+		v.add(DryRunStage{Name: "connectivity", Status: stageSkipped})
+	} else {
+		stage := DryRunStage{Name: "connectivity"}
+		for _, res := range doctor.RunChecks(ctx, cfg) {
+			switch res.Status {
+			case doctor.StatusError:
+				stage.Findings = append(stage.Findings, DryRunFinding{Severity: "error", Service: res.ServiceName, Message: res.Message})
+			case doctor.StatusWarning:
+				stage.Findings = append(stage.Findings, DryRunFinding{Severity: "warning", Service: res.ServiceName, Message: res.Message})
+			}
+		}
+		// An unreachable upstream does not stop a reload: it is registered
+		// and retried.
+		v.add(stage.withStatus(stageWarning))
+	}
+
+	stage := DryRunStage{Name: "lint"}
+	results, _ := lint.NewLinter(cfg).Run(ctx)
+	for _, res := range results {
+		if res.Severity == lint.Error {
+			// The errors of the linter are the validation errors.
+			continue
+		}
+		stage.Findings = append(stage.Findings, DryRunFinding{
+			Severity: strings.ToLower(res.Severity.String()),
+			Service:  res.ServiceName,
+			Path:     res.Path,
+			Message:  res.Message,
+		})
+	}
+	v.add(stage.withStatus(stageWarning))
+
+	if report, err := configdiff.Diff(a.activeConfig.Load(), cfg); err == nil {
+		for _, t := range report.Tools {
+			v.Changes = append(v.Changes, DryRunChange{Path: "tools[" + t.Name + "]", Kind: string(t.Kind)})
+		}
+		for _, c := range report.Changes {
+			v.Changes = append(v.Changes, DryRunChange{Path: c.Path, Kind: string(c.Kind), Risk: c.Risk})
+		}
+	}
+	return v, nil
+}
+
+// dryRunLoad runs the parse, schema and validation stages of a dry run, and
+// returns the loaded candidate, or nil if a reload would reject it.
+func (a *Application) dryRunLoad(ctx context.Context, v *DryRunVerdict, content []byte, format, path string) *config_v1.McpAnyServerConfig {
+	fail := func(name string, err error, rest ...string) *config_v1.McpAnyServerConfig {
+		v.add(DryRunStage{Name: name, Status: stageFailed, Findings: []DryRunFinding{{Severity: "error", Message: err.Error()}}})
+		for _, r := range rest {
+			v.add(DryRunStage{Name: r, Status: stageSkipped})
+		}
+		return nil
+	}
+
+	// The schema applies to the YAML, JSON and TOML formats.
+	if format == "textproto" {
+		v.add(DryRunStage{Name: "parse", Status: stagePassed})
+		v.add(DryRunStage{Name: "schema", Status: stageSkipped})
+	} else {
+		raw, err := config.DecodeDocument(path, content)
+		if err != nil {
+			return fail("parse", err, "schema", "validation")
+		}
+		v.add(DryRunStage{Name: "parse", Status: stagePassed})
+		if err := config.ValidateConfigAgainstSchema(raw); err != nil {
+			return fail("schema", err, "validation")
+		}
+		v.add(DryRunStage{Name: "schema", Status: stagePassed})
+	}
+
+	// Load the candidate as a reload would, merged with the configuration in
+	// storage, from an in-memory file so that nothing is written.
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, path, content, 0o600); err != nil {
+		return fail("validation", err)
+	}
+	stores := []config.Store{}
+	if a.Storage != nil {
+		stores = append(stores, a.Storage)
+	}
+	stores = append(stores, config.NewFileStore(fs, []string{path}))
+	cfg, err := config.LoadResolvedConfig(ctx, config.NewMultiStore(stores...))
+	if err != nil {
+		return fail("validation", err)
+	}
+	stage := DryRunStage{Name: "validation"}
+	for _, e := range config.Validate(ctx, cfg, config.Server) {
+		stage.Findings = append(stage.Findings, DryRunFinding{Severity: "error", Service: e.ServiceName, Message: e.Err.Error()})
+	}
+	v.add(stage.withStatus(stageFailed))
+	if len(stage.Findings) > 0 {
+		return nil
+	}
+	return cfg
+}
+
+// withStatus sets the status of a stage: passed without findings, else
+// failed if a finding is an error, else the given status.
+func (s DryRunStage) withStatus(status string) DryRunStage {
+	s.Status = stagePassed
+	for _, f := range s.Findings {
+		if f.Severity == "error" {
+			s.Status = status
+			break
+		}
+		s.Status = stageWarning
+	}
+	return s
+}
+
+// add records a stage and updates the verdict with its status.
+func (v *DryRunVerdict) add(s DryRunStage) {
+	v.Stages = append(v.Stages, s)
+	switch {
+	case s.Status == stageFailed:
+		v.Verdict = DryRunFail
+	case s.Status == stageWarning && v.Verdict == DryRunPass:
+		v.Verdict = DryRunWarn
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stageStatuses returns the status of each stage of a verdict, by name.
+func stageStatuses(v *DryRunVerdict) map[string]string {
+	statuses := make(map[string]string)
+	for _, s := range v.Stages {
+		statuses[s.Name] = s.Status
+	}
+	return statuses
+}
+
+func TestDryRunConfig(t *testing.T) {
+	app := newVersionedApp()
+	ctx := context.Background()
+	require.NoError(t, reloadVersionedConfig(ctx, t, app, afero.NewMemMapFs(), "get_forecast"))
+	running := app.activeConfig.Load()
+
+	t.Run("valid", func(t *testing.T) {
+		v, err := app.DryRunConfig(ctx, []byte(fmt.Sprintf(versionedConfig, "get_alerts")), DryRunOptions{SkipConnectivity: true})
+		require.NoError(t, err)
+		assert.Equal(t, DryRunPass, v.Verdict)
+		assert.Equal(t, map[string]string{
+			"parse":        stagePassed,
+			"schema":       stagePassed,
+			"validation":   stagePassed,
+			"connectivity": stageSkipped,
+			"lint":         stagePassed,
+		}, stageStatuses(v))
+		assert.Contains(t, v.Changes, DryRunChange{Path: "tools[weather.get_alerts]", Kind: "added"})
+		assert.Contains(t, v.Changes, DryRunChange{Path: "tools[weather.get_forecast]", Kind: "removed"})
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		v, err := app.DryRunConfig(ctx, []byte("upstream_services: [\n"), DryRunOptions{Format: "yaml"})
+		require.NoError(t, err)
+		assert.Equal(t, DryRunFail, v.Verdict)
+		assert.Equal(t, map[string]string{
+			"parse":        stageFailed,
+			"schema":       stageSkipped,
+			"validation":   stageSkipped,
+			"connectivity": stageSkipped,
+			"lint":         stageSkipped,
+		}, stageStatuses(v))
+		assert.Empty(t, v.Changes)
+	})
+
+	t.Run("schema", func(t *testing.T) {
+		v, err := app.DryRunConfig(ctx, []byte(`{"upstream_services": "weather"}`), DryRunOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, DryRunFail, v.Verdict)
+		assert.Equal(t, stageFailed, stageStatuses(v)["schema"])
+		assert.Equal(t, stageSkipped, stageStatuses(v)["validation"])
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		candidate := strings.Replace(fmt.Sprintf(versionedConfig, "get_alerts"), `"http://127.0.0.1:8080"`, `""`, 1)
+		v, err := app.DryRunConfig(ctx, []byte(candidate), DryRunOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, DryRunFail, v.Verdict)
+		assert.Equal(t, stageFailed, stageStatuses(v)["validation"])
+		assert.Equal(t, stageSkipped, stageStatuses(v)["connectivity"])
+		require.NotEmpty(t, v.Stages[2].Findings)
+		assert.Equal(t, "weather", v.Stages[2].Findings[0].Service)
+	})
+
+	t.Run("unreachable upstream", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := l.Addr().String()
+		require.NoError(t, l.Close())
+
+		candidate := strings.Replace(fmt.Sprintf(versionedConfig, "get_alerts"), "127.0.0.1:8080", addr, 1)
+		v, err := app.DryRunConfig(ctx, []byte(candidate), DryRunOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, DryRunWarn, v.Verdict, "an unreachable upstream does not stop a reload")
+		assert.Equal(t, stageWarning, stageStatuses(v)["connectivity"])
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := app.DryRunConfig(ctx, []byte("a = 1"), DryRunOptions{Format: "ini"})
+		assert.ErrorContains(t, err, "unsupported format")
+	})
+
+	// The dry runs leave the running server alone.
+	assert.Same(t, running, app.activeConfig.Load())
+	_, ok := app.ToolManager.GetTool("weather.get_forecast")
+	assert.True(t, ok)
+	_, ok = app.ToolManager.GetTool("weather.get_alerts")
+	assert.False(t, ok)
+	versions, err := app.Storage.ListConfigVersions(ctx, 0)
+	require.NoError(t, err)
+	assert.Len(t, versions, 1)
+}
+
+func TestHandleConfigDryRun(t *testing.T) {
+	app := newVersionedApp()
+	handler := app.createAPIHandler(app.Storage)
+	serve := func(body string, admin bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/config/dry-run", strings.NewReader(body))
+		if admin {
+			r = r.WithContext(auth.ContextWithRoles(r.Context(), []string{"admin"}))
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	body, err := json.Marshal(dryRunRequest{Content: fmt.Sprintf(versionedConfig, "get_alerts"), SkipConnectivity: true})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, serve(string(body), false).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(`{}`, true).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(`{"content": "a", "format": "ini"}`, true).Code)
+
+	w := serve(string(body), true)
+	require.Equal(t, http.StatusOK, w.Code)
+	var v DryRunVerdict
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &v))
+	assert.NotEqual(t, DryRunFail, v.Verdict)
+	assert.Len(t, v.Stages, 5)
+
+	// A rejected candidate is a verdict, not an error.
+	w = serve(`{"content": "upstream_services: [", "format": "yaml"}`, true)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &v))
+	assert.Equal(t, DryRunFail, v.Verdict)
+}
@@ -0,0 +1,464 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	config_v1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/storage"
+	"github.com/mcpany/core/server/pkg/util"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// maxConfigVersions is the number of configuration versions kept in the
+// store. Older ones are pruned after each reload.
+const maxConfigVersions = 100
+
+// systemActor is the actor of the reloads no user or API key triggered.
+const systemActor = "system"
+
+// redactedSecret replaces the secrets of the stored configurations.
+const redactedSecret = "[REDACTED]"
+
+// ErrConfigVersionNotFound is returned when rolling back to a configuration
+// version that is not in the store.
+var ErrConfigVersionNotFound = errors.New("config version not found")
+
+type reloadTriggerKey struct{}
+
+// WithReloadTrigger returns a context that records what triggered a
+// configuration reload, e.g. "config change", in the version history.
+//
+// Summary: Embeds the trigger of a reload into the context.
+//
+// Parameters:
+//   - ctx: context.Context. The context to extend.
+//   - trigger: string. What triggered the reload.
+//
+// Returns:
+//   - context.Context: A new context containing the trigger.
+func WithReloadTrigger(ctx context.Context, trigger string) context.Context {
+	return context.WithValue(ctx, reloadTriggerKey{}, trigger)
+}
+
+// reloadTrigger returns the trigger recorded in the context, or "reload".
+func reloadTrigger(ctx context.Context) string {
+	if trigger, ok := ctx.Value(reloadTriggerKey{}).(string); ok && trigger != "" {
+		return trigger
+	}
+	return "reload"
+}
+
+// reloadActor returns the user or the API key of the request that triggered
+// a reload, or "system".
+func reloadActor(ctx context.Context) string {
+	if user, ok := auth.UserFromContext(ctx); ok && user != "" {
+		return user
+	}
+	if id, ok := auth.APIKeyIDFromContext(ctx); ok && id != "" {
+		return id
+	}
+	return systemActor
+}
+
+// withAdminAPIReloadTrigger records the admin API requests as the trigger of
+// the reloads they cause.
+func withAdminAPIReloadTrigger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trigger := fmt.Sprintf("admin api: %s %s", r.Method, r.URL.Path)
+		next.ServeHTTP(w, r.WithContext(WithReloadTrigger(r.Context(), trigger)))
+	})
+}
+
+// configHash returns the hex-encoded SHA-256 hash of a configuration.
+func configHash(cfg *config_v1.McpAnyServerConfig) string {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(cfg)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// recordConfigVersion saves the outcome of a reload in the version history
+// and prunes the oldest versions. Failures are logged: the history must not
+// fail a reload.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the reload, which carries its actor and trigger.
+//   - cfg: *config_v1.McpAnyServerConfig. The applied configuration, or nil if the reload failed.
+//   - reloadErr: error. The error of a failed reload.
+//   - rollbackOf: int64. The version restored by a rollback, or 0.
+//
+// Returns:
+//   - *config_v1.ConfigVersion: The recorded version, or nil if it was not saved.
+func (a *Application) recordConfigVersion(ctx context.Context, cfg *config_v1.McpAnyServerConfig, reloadErr error, rollbackOf int64) *config_v1.ConfigVersion {
+	if a.Storage == nil {
+		return nil
+	}
+	v := config_v1.ConfigVersion_builder{
+		CreatedAt: proto.String(time.Now().UTC().Format(time.RFC3339)),
+		Actor:     proto.String(reloadActor(ctx)),
+		Trigger:   proto.String(reloadTrigger(ctx)),
+		Status:    config_v1.ConfigVersion_STATUS_APPLIED.Enum(),
+	}.Build()
+	if rollbackOf != 0 {
+		v.SetRollbackOf(rollbackOf)
+		v.SetSourceHash(a.sourceHash)
+	}
+	if reloadErr != nil {
+		v.SetStatus(config_v1.ConfigVersion_STATUS_FAILED)
+		v.SetError(reloadErr.Error())
+	} else {
+		v.SetConfig(snapshotConfig(cfg))
+		v.SetConfigHash(configHash(cfg))
+	}
+
+	log := logging.GetLogger()
+	if err := a.Storage.SaveConfigVersion(ctx, v); err != nil {
+		log.Error("Failed to record config version", "error", err)
+		return nil
+	}
+	if err := a.Storage.PruneConfigVersions(ctx, maxConfigVersions); err != nil {
+		log.Error("Failed to prune config versions", "error", err)
+	}
+	return v
+}
+
+// RollbackConfig applies the configuration of an earlier version again. The
+// restored configuration stays in effect, across reloads and restarts, until
+// the configuration sources load a different configuration. Its redacted
+// secrets are taken from the running configuration.
+//
+// Summary: Rolls the configuration back to an applied version.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the rollback, which carries its actor.
+//   - version: int64. The version to restore.
+//
+// Returns:
+//   - *config_v1.ConfigVersion: The version recording the rollback.
+//   - error: ErrConfigVersionNotFound if the version is not in the store, or an error if it cannot be restored,
+//     e.g. because one of its secrets is not in the running configuration.
+//
+// Side Effects:
+//   - Reconfigures the services, users, profiles and settings, and records a new version.
+func (a *Application) RollbackConfig(ctx context.Context, version int64) (*config_v1.ConfigVersion, error) {
+	if a.Storage == nil {
+		return nil, errors.New("config history requires a storage backend")
+	}
+	a.configMu.Lock()
+	defer a.configMu.Unlock()
+
+	target, err := a.Storage.GetConfigVersion(ctx, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config version %d: %w", version, err)
+	}
+	if target == nil {
+		return nil, fmt.Errorf("%w: %d", ErrConfigVersionNotFound, version)
+	}
+	if target.GetStatus() != config_v1.ConfigVersion_STATUS_APPLIED || !target.HasConfig() {
+		return nil, fmt.Errorf("config version %d was not applied and cannot be restored", version)
+	}
+
+	cfg, err := restoreConfig(target.GetConfig(), a.activeConfig.Load())
+	if err != nil {
+		return nil, fmt.Errorf("config version %d cannot be restored: %w", version, err)
+	}
+
+	logging.GetLogger().Info("Rolling back configuration", "version", version)
+	ctx = WithReloadTrigger(ctx, "rollback")
+	a.lastReloadTime = time.Now()
+	a.lastReloadErr = nil
+	a.applyConfig(ctx, cfg)
+
+	recorded := a.recordConfigVersion(ctx, cfg, nil, version)
+	if recorded == nil {
+		// The rollback is applied even if it could not be recorded.
+		recorded = config_v1.ConfigVersion_builder{RollbackOf: proto.Int64(version)}.Build()
+	}
+	return recorded, nil
+}
+
+// pinnedRollback returns the rollback in effect: the newest applied version,
+// if it is a rollback made while the sources loaded the configuration they
+// load now. It must be called with configMu held, or before the server runs.
+func (a *Application) pinnedRollback(ctx context.Context, store storage.Storage) *config_v1.ConfigVersion {
+	versions, err := store.ListConfigVersions(ctx, 0)
+	if err != nil {
+		logging.GetLogger().Error("Failed to list config versions", "error", err)
+		return nil
+	}
+	for _, v := range versions {
+		if v.GetStatus() != config_v1.ConfigVersion_STATUS_APPLIED {
+			continue
+		}
+		if v.GetRollbackOf() != 0 && v.GetSourceHash() == a.sourceHash {
+			return v
+		}
+		return nil
+	}
+	return nil
+}
+
+// restorePinnedRollback returns the configuration to start with: the one of
+// the rollback in effect, or the one loaded from the sources. It also returns
+// the version the rollback restored, or 0.
+func (a *Application) restorePinnedRollback(ctx context.Context, store storage.Storage, sources *config_v1.McpAnyServerConfig) (*config_v1.McpAnyServerConfig, int64) {
+	pinned := a.pinnedRollback(ctx, store)
+	if pinned == nil {
+		return sources, 0
+	}
+	log := logging.GetLogger()
+	cfg, err := restoreConfig(pinned.GetConfig(), sources)
+	if err != nil {
+		log.Error("Failed to restore the rolled back configuration, using the configuration sources", "version", pinned.GetRollbackOf(), "error", err)
+		return sources, 0
+	}
+	log.Info("Configuration sources unchanged since the rollback, restoring it", "version", pinned.GetRollbackOf())
+	return cfg, pinned.GetRollbackOf()
+}
+
+var (
+	secretValueName = (&config_v1.SecretValue{}).ProtoReflect().Descriptor().FullName()
+	plainTextField  = (&config_v1.SecretValue{}).ProtoReflect().Descriptor().Fields().ByName("plain_text")
+)
+
+// nonSecretSuffixes are the suffixes of the fields that are named like
+// secrets but only refer to them, e.g. token_url or secret_id.
+var nonSecretSuffixes = []string{"_url", "_path", "_file", "_type", "_ref", "_id", "_prefix"}
+
+// isSecretName reports whether a field or map key holds a secret.
+func isSecretName(name string) bool {
+	if !util.IsSensitiveKey(name) {
+		return false
+	}
+	for _, suffix := range nonSecretSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// snapshotConfig returns a copy of a configuration to store in a version,
+// with its secrets replaced by redactedSecret: the plain-text secret values,
+// the string fields named like secrets, e.g. api_key, and the string map
+// entries keyed like them, e.g. an Authorization header.
+func snapshotConfig(cfg *config_v1.McpAnyServerConfig) *config_v1.McpAnyServerConfig {
+	snapshot := proto.Clone(cfg).(*config_v1.McpAnyServerConfig)
+	redactSecrets(snapshot.ProtoReflect())
+	return snapshot
+}
+
+// populatedFields returns the populated fields of a message, so that they
+// can be set without ranging over the message.
+func populatedFields(m protoreflect.Message) []protoreflect.FieldDescriptor {
+	var fields []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		fields = append(fields, fd)
+		return true
+	})
+	return fields
+}
+
+func redactSecrets(m protoreflect.Message) {
+	if m.Descriptor().FullName() == secretValueName {
+		if m.Has(plainTextField) {
+			m.Set(plainTextField, protoreflect.ValueOfString(redactedSecret))
+		}
+		return
+	}
+	for _, fd := range populatedFields(m) {
+		v := m.Get(fd)
+		switch {
+		case fd.IsMap():
+			isMessage := fd.MapValue().Message() != nil
+			isString := fd.MapValue().Kind() == protoreflect.StringKind
+			var secretKeys []protoreflect.MapKey
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				switch {
+				case isMessage:
+					redactSecrets(mv.Message())
+				case isString && isSecretName(strings.ToLower(k.String())):
+					secretKeys = append(secretKeys, k)
+				}
+				return true
+			})
+			for _, k := range secretKeys {
+				v.Map().Set(k, protoreflect.ValueOfString(redactedSecret))
+			}
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				if fd.Message() != nil {
+					redactSecrets(list.Get(i).Message())
+				} else if fd.Kind() == protoreflect.StringKind && isSecretName(string(fd.Name())) {
+					list.Set(i, protoreflect.ValueOfString(redactedSecret))
+				}
+			}
+		case fd.Message() != nil:
+			redactSecrets(v.Message())
+		case fd.Kind() == protoreflect.StringKind && isSecretName(string(fd.Name())):
+			m.Set(fd, protoreflect.ValueOfString(redactedSecret))
+		}
+	}
+}
+
+// restoreConfig returns a copy of a stored configuration, with its redacted
+// secrets replaced by the values at the same place in the running
+// configuration.
+func restoreConfig(stored, running *config_v1.McpAnyServerConfig) (*config_v1.McpAnyServerConfig, error) {
+	cfg := proto.Clone(stored).(*config_v1.McpAnyServerConfig)
+	var src protoreflect.Message
+	if running != nil {
+		src = running.ProtoReflect()
+	}
+	if err := restoreSecrets(cfg.ProtoReflect(), src, ""); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// restoreSecrets replaces the redacted secrets of dst with the values in src,
+// which may be nil. It fails if src does not have a value for one of them.
+func restoreSecrets(dst, src protoreflect.Message, path string) error {
+	if src != nil && !src.IsValid() {
+		src = nil
+	}
+	for _, fd := range populatedFields(dst) {
+		at := joinField(path, string(fd.Name()))
+		v := dst.Get(fd)
+		var sv protoreflect.Value
+		if src != nil {
+			sv = src.Get(fd)
+		}
+		switch {
+		case fd.IsMap():
+			if err := restoreMapSecrets(fd, v.Map(), sv, src != nil, at); err != nil {
+				return err
+			}
+		case fd.IsList():
+			if err := restoreListSecrets(fd, v.List(), sv, src != nil, at); err != nil {
+				return err
+			}
+		case fd.Message() != nil:
+			var child protoreflect.Message
+			if src != nil && src.Has(fd) {
+				child = sv.Message()
+			}
+			if err := restoreSecrets(v.Message(), child, at); err != nil {
+				return err
+			}
+		case fd.Kind() == protoreflect.StringKind && v.String() == redactedSecret:
+			if src == nil || !src.Has(fd) || sv.String() == redactedSecret {
+				return errSecretNotRunning(at)
+			}
+			dst.Set(fd, sv)
+		}
+	}
+	return nil
+}
+
+func restoreMapSecrets(fd protoreflect.FieldDescriptor, dst protoreflect.Map, sv protoreflect.Value, hasSrc bool, path string) error {
+	var keys []protoreflect.MapKey
+	dst.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+		keys = append(keys, k)
+		return true
+	})
+	for _, k := range keys {
+		at := fmt.Sprintf("%s[%s]", path, k.String())
+		v := dst.Get(k)
+		var src protoreflect.Value
+		found := hasSrc && sv.Map().Has(k)
+		if found {
+			src = sv.Map().Get(k)
+		}
+		if fd.MapValue().Message() != nil {
+			var child protoreflect.Message
+			if found {
+				child = src.Message()
+			}
+			if err := restoreSecrets(v.Message(), child, at); err != nil {
+				return err
+			}
+		} else if fd.MapValue().Kind() == protoreflect.StringKind && v.String() == redactedSecret {
+			if !found || src.String() == redactedSecret {
+				return errSecretNotRunning(at)
+			}
+			dst.Set(k, src)
+		}
+	}
+	return nil
+}
+
+func restoreListSecrets(fd protoreflect.FieldDescriptor, dst protoreflect.List, sv protoreflect.Value, hasSrc bool, path string) error {
+	for i := 0; i < dst.Len(); i++ {
+		if fd.Message() != nil {
+			elem := dst.Get(i).Message()
+			at := fmt.Sprintf("%s[%d]", path, i)
+			var child protoreflect.Message
+			if hasSrc {
+				child = matchingElement(elem, sv.List(), i)
+			}
+			if err := restoreSecrets(elem, child, at); err != nil {
+				return err
+			}
+			continue
+		}
+		if fd.Kind() != protoreflect.StringKind || dst.Get(i).String() != redactedSecret {
+			continue
+		}
+		at := fmt.Sprintf("%s[%d]", path, i)
+		if !hasSrc || i >= sv.List().Len() || sv.List().Get(i).String() == redactedSecret {
+			return errSecretNotRunning(at)
+		}
+		dst.Set(i, sv.List().Get(i))
+	}
+	return nil
+}
+
+// matchingElement returns the element of a list that matches elem: the one
+// with the same name or ID, or the one at the same index if elem has
+// neither.
+func matchingElement(elem protoreflect.Message, list protoreflect.List, index int) protoreflect.Message {
+	for _, name := range []protoreflect.Name{"name", "id"} {
+		fd := elem.Descriptor().Fields().ByName(name)
+		if fd == nil || fd.Kind() != protoreflect.StringKind || elem.Get(fd).String() == "" {
+			continue
+		}
+		for i := 0; i < list.Len(); i++ {
+			if candidate := list.Get(i).Message(); candidate.Get(fd).String() == elem.Get(fd).String() {
+				return candidate
+			}
+		}
+		return nil
+	}
+	if index < list.Len() {
+		return list.Get(index).Message()
+	}
+	return nil
+}
+
+func errSecretNotRunning(path string) error {
+	return fmt.Errorf("the secret at %s is not in the running configuration", path)
+}
+
+func joinField(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,213 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/pool"
+	"github.com/mcpany/core/server/pkg/serviceregistry"
+	"github.com/mcpany/core/server/pkg/storage/memory"
+	"github.com/mcpany/core/server/pkg/upstream/factory"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+const versionedConfig = `
+upstream_services:
+ - name: "weather"
+   http_service:
+     address: "http://127.0.0.1:8080"
+     tools:
+       - name: "%s"
+         call_id: "forecast"
+     calls:
+       forecast:
+         id: "forecast"
+         endpoint_path: "/forecast"
+         method: "HTTP_METHOD_GET"
+`
+
+// newVersionedApp returns an application with a service registry and an
+// in-memory store, which records the configuration versions.
+func newVersionedApp() *Application {
+	app := NewApplication()
+	app.ServiceRegistry = serviceregistry.New(
+		factory.NewUpstreamServiceFactory(pool.NewManager(), nil),
+		app.ToolManager,
+		app.PromptManager,
+		app.ResourceManager,
+		auth.NewManager(),
+	)
+	app.Storage = memory.NewStore()
+	return app
+}
+
+// reloadVersionedConfig writes a configuration with the given tool, or a
+// malformed one if tool is empty, and reloads it.
+func reloadVersionedConfig(ctx context.Context, t *testing.T, app *Application, fs afero.Fs, tool string) error {
+	t.Helper()
+	content := "malformed yaml:"
+	if tool != "" {
+		content = fmt.Sprintf(versionedConfig, tool)
+	}
+	require.NoError(t, afero.WriteFile(fs, "/config.yaml", []byte(content), 0o644))
+	return app.ReloadConfig(ctx, fs, []string{"/config.yaml"})
+}
+
+func TestConfigVersions_Reload(t *testing.T) {
+	app := newVersionedApp()
+	fs := afero.NewMemMapFs()
+
+	alice := auth.ContextWithUser(context.Background(), "alice")
+	require.NoError(t, reloadVersionedConfig(alice, t, app, fs, "get_forecast"))
+	watcher := WithReloadTrigger(context.Background(), "config change")
+	require.Error(t, reloadVersionedConfig(watcher, t, app, fs, ""))
+	require.NoError(t, reloadVersionedConfig(watcher, t, app, fs, "get_alerts"))
+
+	versions, err := app.Storage.ListConfigVersions(context.Background(), 0)
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+
+	applied, failed, latest := versions[2], versions[1], versions[0]
+	assert.Equal(t, int64(1), applied.GetVersion())
+	assert.Equal(t, "alice", applied.GetActor())
+	assert.Equal(t, "reload", applied.GetTrigger())
+	assert.Equal(t, configv1.ConfigVersion_STATUS_APPLIED, applied.GetStatus())
+	assert.Len(t, applied.GetConfigHash(), 64)
+	assert.Equal(t, "weather", applied.GetConfig().GetUpstreamServices()[0].GetName())
+
+	assert.Equal(t, "system", failed.GetActor())
+	assert.Equal(t, "config change", failed.GetTrigger())
+	assert.Equal(t, configv1.ConfigVersion_STATUS_FAILED, failed.GetStatus())
+	assert.NotEmpty(t, failed.GetError())
+	assert.False(t, failed.HasConfig())
+
+	assert.NotEqual(t, applied.GetConfigHash(), latest.GetConfigHash())
+}
+
+func TestRollbackConfig(t *testing.T) {
+	app := newVersionedApp()
+	fs := afero.NewMemMapFs()
+	ctx := context.Background()
+
+	require.NoError(t, reloadVersionedConfig(ctx, t, app, fs, "get_forecast"))
+	require.Error(t, reloadVersionedConfig(ctx, t, app, fs, ""))
+	require.NoError(t, reloadVersionedConfig(ctx, t, app, fs, "get_alerts"))
+	_, ok := app.ToolManager.GetTool("weather.get_alerts")
+	require.True(t, ok)
+
+	v, err := app.RollbackConfig(auth.ContextWithUser(ctx, "bob"), 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), v.GetVersion())
+	assert.Equal(t, int64(1), v.GetRollbackOf())
+	assert.Equal(t, "bob", v.GetActor())
+	assert.Equal(t, "rollback", v.GetTrigger())
+
+	_, ok = app.ToolManager.GetTool("weather.get_forecast")
+	assert.True(t, ok, "the tool of the restored version should be registered")
+	_, ok = app.ToolManager.GetTool("weather.get_alerts")
+	assert.False(t, ok, "the tool of the rolled back version should be removed")
+	assert.Equal(t, "get_forecast", app.activeConfig.Load().GetUpstreamServices()[0].GetHttpService().GetTools()[0].GetName())
+
+	_, err = app.RollbackConfig(ctx, 2)
+	assert.ErrorContains(t, err, "was not applied")
+
+	_, err = app.RollbackConfig(ctx, 42)
+	assert.ErrorIs(t, err, ErrConfigVersionNotFound)
+}
+
+func TestRollbackConfig_KeptWhileSourcesUnchanged(t *testing.T) {
+	app := newVersionedApp()
+	fs := afero.NewMemMapFs()
+	ctx := context.Background()
+
+	require.NoError(t, reloadVersionedConfig(ctx, t, app, fs, "get_forecast"))
+	require.NoError(t, reloadVersionedConfig(ctx, t, app, fs, "get_alerts"))
+	_, err := app.RollbackConfig(ctx, 1)
+	require.NoError(t, err)
+
+	// A reload of the unchanged sources keeps the rollback.
+	require.NoError(t, reloadVersionedConfig(ctx, t, app, fs, "get_alerts"))
+	_, ok := app.ToolManager.GetTool("weather.get_forecast")
+	assert.True(t, ok, "the rollback should stay in effect")
+
+	// So does a restart.
+	sources, err := app.loadConfig(ctx, fs, []string{"/config.yaml"})
+	require.NoError(t, err)
+	restarted := newVersionedApp()
+	restarted.Storage = app.Storage
+	restarted.sourceHash = configHash(sources)
+	cfg, rollbackOf := restarted.restorePinnedRollback(ctx, restarted.Storage, sources)
+	assert.Equal(t, int64(1), rollbackOf)
+	assert.Equal(t, "get_forecast", cfg.GetUpstreamServices()[0].GetHttpService().GetTools()[0].GetName())
+
+	// A change of the sources replaces it.
+	require.NoError(t, reloadVersionedConfig(ctx, t, app, fs, "get_warnings"))
+	_, ok = app.ToolManager.GetTool("weather.get_warnings")
+	assert.True(t, ok)
+	_, ok = app.ToolManager.GetTool("weather.get_forecast")
+	assert.False(t, ok)
+	cfg, rollbackOf = restarted.restorePinnedRollback(ctx, restarted.Storage, sources)
+	assert.Zero(t, rollbackOf)
+	assert.Same(t, sources, cfg)
+}
+
+func TestRecordConfigVersion_RedactsSecrets(t *testing.T) {
+	app := newVersionedApp()
+	ctx := context.Background()
+	cfg := configv1.McpAnyServerConfig_builder{
+		GlobalSettings: configv1.GlobalSettings_builder{
+			ApiKey: proto.String("server-key"),
+		}.Build(),
+		UpstreamServices: []*configv1.UpstreamServiceConfig{
+			configv1.UpstreamServiceConfig_builder{
+				Name: proto.String("github"),
+				UpstreamAuth: configv1.Authentication_builder{
+					BearerToken: configv1.BearerTokenAuth_builder{
+						Token: configv1.SecretValue_builder{PlainText: proto.String("ghp_secret")}.Build(),
+					}.Build(),
+				}.Build(),
+			}.Build(),
+		},
+	}.Build()
+
+	v := app.recordConfigVersion(ctx, cfg, nil, 0)
+	require.NotNil(t, v)
+	stored, err := app.Storage.GetConfigVersion(ctx, v.GetVersion())
+	require.NoError(t, err)
+	snapshot := stored.GetConfig()
+	assert.Equal(t, redactedSecret, snapshot.GetGlobalSettings().GetApiKey())
+	assert.Equal(t, redactedSecret, snapshot.GetUpstreamServices()[0].GetUpstreamAuth().GetBearerToken().GetToken().GetPlainText())
+	assert.Equal(t, configHash(cfg), stored.GetConfigHash())
+
+	restored, err := restoreConfig(snapshot, cfg)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(cfg, restored))
+
+	renamed := proto.Clone(cfg).(*configv1.McpAnyServerConfig)
+	renamed.GetUpstreamServices()[0].SetName("gitlab")
+	_, err = restoreConfig(snapshot, renamed)
+	assert.ErrorContains(t, err, "upstream_services[0].upstream_auth.bearer_token.token.plain_text is not in the running configuration")
+}
+
+func TestRecordConfigVersion_Prunes(t *testing.T) {
+	app := newVersionedApp()
+	ctx := context.Background()
+	cfg := configv1.McpAnyServerConfig_builder{}.Build()
+	for i := 0; i < maxConfigVersions+5; i++ {
+		app.recordConfigVersion(ctx, cfg, nil, 0)
+	}
+
+	versions, err := app.Storage.ListConfigVersions(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, versions, maxConfigVersions)
+	assert.Equal(t, int64(maxConfigVersions+5), versions[0].GetVersion())
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	runtimepprof "runtime/pprof"
+	"time"
+
+	config_v1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/middleware"
+	"github.com/mcpany/core/server/pkg/util"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// defaultDebugListenAddress is the address of the debug listener when none
+// is configured. It is only reachable from the host by default.
+const defaultDebugListenAddress = "127.0.0.1:6060"
+
+var processStart = time.Now()
+
+func init() {
+	expvar.Publish("goroutines", expvar.Func(func() any {
+		return runtime.NumGoroutine()
+	}))
+	expvar.Publish("uptime_seconds", expvar.Func(func() any {
+		return int64(time.Since(processStart).Seconds())
+	}))
+}
+
+// debugListenAddress returns the address of the debug listener, or "" when
+// it is disabled.
+func debugListenAddress(cfg *config_v1.DebugListenerConfig) string {
+	if !cfg.GetEnabled() {
+		return ""
+	}
+	if cfg.GetAddress() == "" {
+		return defaultDebugListenAddress
+	}
+	return cfg.GetAddress()
+}
+
+// newDebugHandler returns the handler of the debug listener. Every endpoint
+// goes through the IP allowlist, the network access rules of the admin API
+// and authMiddleware, and requires the admin role, since profiles, goroutine
+// dumps and the configuration reveal the internals of the server.
+func (a *Application) newDebugHandler(
+	authMiddleware func(http.Handler) http.Handler,
+	ipMiddleware *middleware.IPAllowlistMiddleware,
+	networkAccess *middleware.NetworkAccess,
+) http.Handler {
+	mux := http.NewServeMux()
+	// pprof.Index also serves the named profiles, e.g. /debug/pprof/heap.
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/goroutines", handleGoroutineDump)
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/debug/config", a.handleDebugConfig)
+
+	requireAdmin := middleware.NewRBACMiddleware().RequireRole("admin")
+	// Every path starts with /debug/, so the admin rules apply.
+	return ipMiddleware.Handler(networkAccess.Handler(authMiddleware(requireAdmin(mux))))
+}
+
+// handleGoroutineDump writes the stack traces of all goroutines, in the
+// format of an unrecovered panic.
+func handleGoroutineDump(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = runtimepprof.Lookup("goroutine").WriteTo(w, 2)
+}
+
+// handleDebugConfig writes the configuration the server is running with as
+// JSON. Plain-text secrets are removed and the values of sensitive keys are
+// redacted.
+func (a *Application) handleDebugConfig(w http.ResponseWriter, _ *http.Request) {
+	cfg := a.activeConfig.Load()
+	if cfg == nil {
+		http.Error(w, "configuration not loaded", http.StatusServiceUnavailable)
+		return
+	}
+	cfg = proto.Clone(cfg).(*config_v1.McpAnyServerConfig)
+	util.StripSecretsFromConfig(cfg)
+
+	b, err := protojson.MarshalOptions{UseProtoNames: true, Multiline: true, Indent: "  "}.Marshal(cfg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(util.RedactJSON(b))
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func withRoles(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(auth.ContextWithRoles(r.Context(), roles)))
+		})
+	}
+}
+
+// newTestDebugHandler returns the debug handler of app for callers with the
+// given roles, behind the given network access rules and IP allowlist.
+func newTestDebugHandler(t *testing.T, app *Application, rules *configv1.NetworkAccessConfig, allowedIPs []string, roles ...string) http.Handler {
+	t.Helper()
+	ipMiddleware, err := middleware.NewIPAllowlistMiddleware(allowedIPs)
+	require.NoError(t, err)
+	networkAccess, err := middleware.NewNetworkAccess(rules)
+	require.NoError(t, err)
+	return app.newDebugHandler(withRoles(roles...), ipMiddleware, networkAccess)
+}
+
+func debugRequest(handler http.Handler, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestDebugHandler_RequiresAdmin(t *testing.T) {
+	app := NewApplication()
+	handler := newTestDebugHandler(t, app, nil, nil, "viewer")
+	for _, path := range []string{"/debug/pprof/", "/debug/goroutines", "/debug/vars", "/debug/config"} {
+		assert.Equal(t, http.StatusForbidden, debugRequest(handler, path).Code, path)
+	}
+}
+
+func TestDebugHandler_NetworkAccess(t *testing.T) {
+	app := NewApplication()
+	// httptest requests come from 192.0.2.1.
+	handler := newTestDebugHandler(t, app, configv1.NetworkAccessConfig_builder{
+		Admin: configv1.NetworkAccessRules_builder{Deny: []string{"192.0.2.0/24"}}.Build(),
+	}.Build(), nil, "admin")
+	assert.Equal(t, http.StatusForbidden, debugRequest(handler, "/debug/vars").Code, "the admin rules apply")
+
+	handler = newTestDebugHandler(t, app, configv1.NetworkAccessConfig_builder{
+		Mcp: configv1.NetworkAccessRules_builder{Deny: []string{"192.0.2.0/24"}}.Build(),
+	}.Build(), nil, "admin")
+	assert.Equal(t, http.StatusOK, debugRequest(handler, "/debug/vars").Code, "the MCP rules do not apply")
+
+	handler = newTestDebugHandler(t, app, nil, []string{"10.0.0.0/8"}, "admin")
+	assert.Equal(t, http.StatusForbidden, debugRequest(handler, "/debug/vars").Code, "the IP allowlist applies")
+}
+
+func TestDebugHandler_Endpoints(t *testing.T) {
+	app := NewApplication()
+	handler := newTestDebugHandler(t, app, nil, nil, "admin")
+
+	w := debugRequest(handler, "/debug/pprof/")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "goroutine")
+
+	w = debugRequest(handler, "/debug/goroutines")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "goroutine ")
+
+	w = debugRequest(handler, "/debug/vars")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"memstats"`)
+	assert.Contains(t, w.Body.String(), `"goroutines"`)
+
+	// The configuration is not loaded yet.
+	assert.Equal(t, http.StatusServiceUnavailable, debugRequest(handler, "/debug/config").Code)
+}
+
+func TestDebugHandler_ConfigIsRedacted(t *testing.T) {
+	app := NewApplication()
+	cfg := configv1.McpAnyServerConfig_builder{
+		GlobalSettings: configv1.GlobalSettings_builder{
+			ApiKey: proto.String("global-key"),
+		}.Build(),
+		UpstreamServices: []*configv1.UpstreamServiceConfig{
+			configv1.UpstreamServiceConfig_builder{
+				Name: proto.String("weather"),
+				UpstreamAuth: configv1.Authentication_builder{
+					ApiKey: configv1.APIKeyAuth_builder{
+						ParamName: proto.String("X-Weather-Key"),
+						Value:     configv1.SecretValue_builder{PlainText: proto.String("upstream-key")}.Build(),
+					}.Build(),
+				}.Build(),
+			}.Build(),
+		},
+	}.Build()
+	app.activeConfig.Store(cfg)
+
+	w := debugRequest(newTestDebugHandler(t, app, nil, nil, "admin"), "/debug/config")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	body := w.Body.String()
+	assert.Contains(t, body, `"weather"`)
+	assert.Contains(t, body, "[REDACTED]")
+	assert.NotContains(t, body, "global-key")
+	assert.NotContains(t, body, "upstream-key")
+
+	// The configuration in use is left untouched.
+	assert.Equal(t, "upstream-key", cfg.GetUpstreamServices()[0].GetUpstreamAuth().GetApiKey().GetValue().GetPlainText())
+}
+
+func TestDebugListenAddress(t *testing.T) {
+	assert.Empty(t, debugListenAddress(nil))
+	assert.Empty(t, debugListenAddress(configv1.DebugListenerConfig_builder{Address: proto.String(":6060")}.Build()))
+	assert.Equal(t, "127.0.0.1:6060", debugListenAddress(configv1.DebugListenerConfig_builder{Enabled: proto.Bool(true)}.Build()))
+	assert.Equal(t, ":7070", debugListenAddress(configv1.DebugListenerConfig_builder{
+		Enabled: proto.Bool(true),
+		Address: proto.String(":7070"),
+	}.Build()))
+}
@@ -0,0 +1,229 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/consts"
+	"github.com/mcpany/core/server/pkg/health"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/tool"
+)
+
+// drainFlushTimeout bounds the flush of the audit and log buffers, which
+// starts once the calls are done or the shutdown timeout expired.
+const drainFlushTimeout = 10 * time.Second
+
+// DrainState is the state of the drain of the server ahead of its
+// termination.
+type DrainState string
+
+const (
+	// DrainServing is the state of a server that accepts new sessions.
+	DrainServing DrainState = "serving"
+	// DrainDraining is the state of a server that refuses new sessions and
+	// waits for its calls in flight.
+	DrainDraining DrainState = "draining"
+	// DrainDrained is the state of a server that can be terminated.
+	DrainDrained DrainState = "drained"
+)
+
+// DrainStatus reports the progress of the drain of the server.
+type DrainStatus struct {
+	State DrainState `json:"state"`
+	// StartedAt is when the drain started, in RFC 3339 format.
+	StartedAt     string `json:"started_at,omitempty"`
+	InFlightCalls int    `json:"in_flight_calls"`
+	// AbandonedCalls is the number of calls still in flight when the
+	// shutdown timeout expired.
+	AbandonedCalls int      `json:"abandoned_calls,omitempty"`
+	FlushErrors    []string `json:"flush_errors,omitempty"`
+	// ReadyToTerminate is set once the calls are done and the buffers
+	// flushed.
+	ReadyToTerminate bool `json:"ready_to_terminate"`
+}
+
+// Drainer is implemented by the runners that can be drained before they are
+// terminated.
+//
+// Summary: Interface for draining a server.
+type Drainer interface {
+	// Drain stops accepting new sessions, waits for the calls in flight and
+	// flushes the buffers.
+	//
+	// Summary: Drains the server.
+	//
+	// Parameters:
+	//   - ctx: context.Context. Bounds the wait; the drain goes on after it.
+	//
+	// Returns:
+	//   - DrainStatus: The status of the drain when it ends or ctx is done.
+	Drain(ctx context.Context) DrainStatus
+}
+
+// drainFlush is a buffer flushed at the end of a drain.
+type drainFlush struct {
+	name  string
+	flush func(context.Context) error
+}
+
+// drainState tracks the drain of the server. The zero value is a server that
+// is not draining.
+type drainState struct {
+	started atomic.Bool
+
+	mu          sync.Mutex
+	timeout     time.Duration
+	flushes     []drainFlush
+	startedAt   time.Time
+	done        chan struct{}
+	abandoned   int
+	flushErrors []string
+}
+
+// onDrainFlush registers a buffer to flush at the end of a drain.
+func (a *Application) onDrainFlush(name string, flush func(context.Context) error) {
+	a.drain.mu.Lock()
+	defer a.drain.mu.Unlock()
+	a.drain.flushes = append(a.drain.flushes, drainFlush{name: name, flush: flush})
+}
+
+// setDrainTimeout sets how long a drain waits for the calls in flight.
+func (a *Application) setDrainTimeout(timeout time.Duration) {
+	a.drain.mu.Lock()
+	defer a.drain.mu.Unlock()
+	a.drain.timeout = timeout
+}
+
+// Drain stops accepting new MCP sessions, waits for the calls in flight for
+// up to the shutdown timeout, and flushes the audit and log buffers. The
+// sessions in progress are still served. Draining again waits for the first
+// drain.
+//
+// Summary: Drains the server ahead of its termination.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the wait; the drain goes on after it.
+//
+// Returns:
+//   - DrainStatus: The status of the drain when it ends or ctx is done.
+//
+// Side Effects:
+//   - Fails the readiness probe, and answers the requests that would start a
+//     session with 503 Service Unavailable, for the life of the process.
+func (a *Application) Drain(ctx context.Context) DrainStatus {
+	a.drain.mu.Lock()
+	if a.drain.done == nil {
+		a.drain.startedAt = time.Now().UTC()
+		a.drain.done = make(chan struct{})
+		a.drain.started.Store(true)
+		logging.GetLogger().Info("Draining the server", "timeout", a.drain.timeout)
+		go a.runDrain(a.drain.done, a.drain.timeout, a.drain.flushes)
+	}
+	done := a.drain.done
+	a.drain.mu.Unlock()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	return a.DrainStatus()
+}
+
+// runDrain waits for the calls in flight, flushes the buffers and closes
+// done.
+func (a *Application) runDrain(done chan struct{}, timeout time.Duration, flushes []drainFlush) {
+	defer close(done)
+	log := logging.GetLogger()
+
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	abandoned := 0
+	if drainer, ok := a.ToolManager.(tool.Drainer); ok {
+		abandoned = drainer.Drain(ctx)
+	}
+	if abandoned > 0 {
+		log.Warn("Shutdown timeout expired with calls in flight", "calls", abandoned)
+	}
+
+	flushCtx, cancel := context.WithTimeout(context.Background(), drainFlushTimeout)
+	defer cancel()
+	var flushErrors []string
+	for _, f := range flushes {
+		if err := f.flush(flushCtx); err != nil {
+			log.Error("Failed to flush on drain", "buffer", f.name, "error", err)
+			flushErrors = append(flushErrors, f.name+": "+err.Error())
+		}
+	}
+
+	a.drain.mu.Lock()
+	a.drain.abandoned = abandoned
+	a.drain.flushErrors = flushErrors
+	a.drain.mu.Unlock()
+	log.Info("Server drained, ready to terminate")
+}
+
+// DrainStatus reports the progress of the drain of the server.
+//
+// Summary: Returns the drain status.
+//
+// Returns:
+//   - DrainStatus: The status, with the state DrainServing if the server is
+//     not draining.
+func (a *Application) DrainStatus() DrainStatus {
+	status := DrainStatus{State: DrainServing}
+	if drainer, ok := a.ToolManager.(tool.Drainer); ok {
+		status.InFlightCalls = drainer.TotalInFlightCalls()
+	}
+
+	a.drain.mu.Lock()
+	defer a.drain.mu.Unlock()
+	if a.drain.done == nil {
+		return status
+	}
+	status.StartedAt = a.drain.startedAt.Format(time.RFC3339)
+	select {
+	case <-a.drain.done:
+		status.State = DrainDrained
+		status.AbandonedCalls = a.drain.abandoned
+		status.FlushErrors = a.drain.flushErrors
+		status.ReadyToTerminate = true
+	default:
+		status.State = DrainDraining
+	}
+	return status
+}
+
+// rejectNewSessionsWhileDraining answers the requests that would start an MCP
+// session with 503 once the server drains, so that the clients retry on
+// another replica. The requests of the sessions in progress are served.
+func (a *Application) rejectNewSessionsWhileDraining(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.drain.started.Load() && r.Header.Get(consts.HeaderMcpSessionID) == "" {
+			w.Header().Set("Connection", "close")
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "server is draining", http.StatusServiceUnavailable)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// drainReadinessCheck fails once the server drains, so that the load
+// balancer stops sending it new sessions.
+func (a *Application) drainReadinessCheck(_ context.Context) health.CheckResult {
+	if a.drain.started.Load() {
+		return health.CheckResult{Status: health.StatusFail, Message: "server is draining"}
+	}
+	return health.CheckResult{Status: health.StatusOK}
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/consts"
+	"github.com/mcpany/core/server/pkg/health"
+	"github.com/mcpany/core/server/pkg/storage/memory"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// blockingToolManager returns a tool manager whose only tool blocks until
+// release is closed, and a channel receiving the start of each call.
+func blockingToolManager(t *testing.T, release chan struct{}) (*tool.Manager, chan struct{}) {
+	t.Helper()
+	tm := tool.NewManager(nil)
+	started := make(chan struct{}, 1)
+	require.NoError(t, tm.AddTool(&tool.MockTool{
+		ToolFunc: func() *v1.Tool {
+			return v1.Tool_builder{ServiceId: proto.String("reports"), Name: proto.String("build")}.Build()
+		},
+		ExecuteFunc: func(_ context.Context, _ *tool.ExecutionRequest) (any, error) {
+			started <- struct{}{}
+			<-release
+			return "ok", nil
+		},
+	}))
+	return tm, started
+}
+
+func TestApplication_Drain(t *testing.T) {
+	release := make(chan struct{})
+	tm, started := blockingToolManager(t, release)
+	app := &Application{ToolManager: tm}
+	app.setDrainTimeout(time.Minute)
+	var flushed []string
+	app.onDrainFlush("audit", func(context.Context) error {
+		flushed = append(flushed, "audit")
+		return nil
+	})
+
+	sessions := app.rejectNewSessionsWhileDraining(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(sessionID string) int {
+		r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		if sessionID != "" {
+			r.Header.Set(consts.HeaderMcpSessionID, sessionID)
+		}
+		w := httptest.NewRecorder()
+		sessions.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	assert.Equal(t, DrainStatus{State: DrainServing}, app.DrainStatus())
+	assert.Equal(t, http.StatusOK, serve(""))
+	assert.Equal(t, health.StatusOK, app.drainReadinessCheck(context.Background()).Status)
+
+	called := make(chan struct{})
+	go func() {
+		defer close(called)
+		_, _ = tm.ExecuteTool(context.Background(), &tool.ExecutionRequest{ToolName: "reports.build"})
+	}()
+	<-started
+
+	// The drain waits for the call in flight.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	status := app.Drain(ctx)
+	assert.Equal(t, DrainDraining, status.State)
+	assert.Equal(t, 1, status.InFlightCalls)
+	assert.False(t, status.ReadyToTerminate)
+	assert.NotEmpty(t, status.StartedAt)
+	assert.Empty(t, flushed)
+
+	assert.Equal(t, http.StatusServiceUnavailable, serve(""), "new sessions are refused")
+	assert.Equal(t, http.StatusOK, serve("session-1"), "sessions in progress are served")
+	assert.Equal(t, health.StatusFail, app.drainReadinessCheck(context.Background()).Status)
+
+	close(release)
+	<-called
+	status = app.Drain(context.Background())
+	assert.Equal(t, DrainDrained, status.State)
+	assert.True(t, status.ReadyToTerminate)
+	assert.Zero(t, status.InFlightCalls)
+	assert.Zero(t, status.AbandonedCalls)
+	assert.Equal(t, []string{"audit"}, flushed)
+}
+
+func TestApplication_Drain_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	tm, started := blockingToolManager(t, release)
+	app := &Application{ToolManager: tm}
+	app.setDrainTimeout(20 * time.Millisecond)
+	app.onDrainFlush("log sink loki", func(context.Context) error {
+		return errors.New("sink unavailable")
+	})
+
+	go func() { _, _ = tm.ExecuteTool(context.Background(), &tool.ExecutionRequest{ToolName: "reports.build"}) }()
+	<-started
+
+	status := app.Drain(context.Background())
+	assert.Equal(t, DrainDrained, status.State)
+	assert.True(t, status.ReadyToTerminate)
+	assert.Equal(t, 1, status.AbandonedCalls)
+	assert.Equal(t, []string{"log sink loki: sink unavailable"}, status.FlushErrors)
+}
+
+func TestHandleDrain(t *testing.T) {
+	store := memory.NewStore()
+	app := &Application{Storage: store, ToolManager: tool.NewManager(nil)}
+	handler := app.createAPIHandler(store)
+	serve := func(method, target string, admin bool) (*httptest.ResponseRecorder, DrainStatus) {
+		r := httptest.NewRequest(method, target, nil)
+		if admin {
+			r = r.WithContext(auth.ContextWithRoles(r.Context(), []string{"admin"}))
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		var status DrainStatus
+		if w.Code == http.StatusOK || w.Code == http.StatusAccepted {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+		}
+		return w, status
+	}
+
+	w, _ := serve(http.MethodPost, "/drain", false)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w, _ = serve(http.MethodDelete, "/drain", true)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	w, status := serve(http.MethodGet, "/drain", true)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, DrainServing, status.State)
+
+	w, status = serve(http.MethodPost, "/drain", true)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, DrainDrained, status.State)
+	assert.True(t, status.ReadyToTerminate)
+
+	w, status = serve(http.MethodGet, "/drain", true)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, status.ReadyToTerminate)
+}
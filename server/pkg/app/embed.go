@@ -0,0 +1,305 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/storage"
+	"github.com/mcpany/core/server/pkg/storage/memory"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/spf13/afero"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// embeddedConfigPath is the path of the configuration of an embedded server,
+// in its in-memory filesystem.
+const embeddedConfigPath = "/mcpany/config.json"
+
+// Transport selects how an embedded server serves MCP.
+type Transport int
+
+const (
+	// TransportHTTP serves MCP over streamable HTTP at /mcp, with the HTTP
+	// APIs and the gRPC registration API.
+	TransportHTTP Transport = iota
+	// TransportStdio serves MCP over the standard input and output of the
+	// process.
+	TransportStdio
+)
+
+// ErrServerStarted is returned when a server is changed or run again after it
+// has started.
+var ErrServerStarted = errors.New("the server has already started")
+
+// ServerOptions configures an embedded server.
+//
+// Fields:
+//   - Transport: Transport. How MCP is served, over HTTP by default.
+//   - HTTPAddress: string. The listen address of the HTTP server, "localhost:50050" by default.
+//   - GRPCAddress: string. The listen address of the gRPC registration server; empty disables it.
+//   - APIKey: string. The API key the clients must send; empty disables the check.
+//   - Storage: storage.Storage. The store of the services and settings; in memory by default.
+//   - ShutdownTimeout: time.Duration. How long the server drains on shutdown, 5 seconds by default.
+type ServerOptions struct {
+	Transport       Transport
+	HTTPAddress     string
+	GRPCAddress     string
+	APIKey          string
+	Storage         storage.Storage
+	ShutdownTimeout time.Duration
+}
+
+// Server is an MCP Any proxy embedded in a Go program. It serves the upstream
+// services of its configuration alongside the tools the program implements.
+//
+// Summary: An embeddable MCP Any server.
+type Server struct {
+	app  *Application
+	fs   afero.Fs
+	opts ServerOptions
+
+	mu      sync.Mutex
+	started bool
+}
+
+// NewServer creates an embedded server from a configuration.
+//
+// Summary: Creates an embedded MCP Any server.
+//
+// Parameters:
+//   - cfg (*configv1.McpAnyServerConfig): The configuration, as loaded from a config file by cmd/server. May be nil.
+//   - opts (ServerOptions): The options of the server.
+//
+// Returns:
+//   - *Server: The server, ready to run.
+//   - error: An error if the configuration cannot be encoded.
+func NewServer(cfg *configv1.McpAnyServerConfig, opts ServerOptions) (*Server, error) {
+	if cfg == nil {
+		cfg = &configv1.McpAnyServerConfig{}
+	}
+	content, err := protojson.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the configuration: %w", err)
+	}
+	// The configuration is loaded like a config file, from memory.
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, embeddedConfigPath, content, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write the configuration: %w", err)
+	}
+
+	if opts.HTTPAddress == "" {
+		opts.HTTPAddress = "localhost:50050"
+	}
+	if opts.ShutdownTimeout <= 0 {
+		opts.ShutdownTimeout = 5 * time.Second
+	}
+	application := NewApplication()
+	application.Storage = opts.Storage
+	if application.Storage == nil {
+		application.Storage = memory.NewStore()
+	}
+	return &Server{app: application, fs: fs, opts: opts}, nil
+}
+
+// AddTool registers a tool the embedding program implements, served with the
+// tools of the upstream services. Its service ID namespaces it, e.g. the tool
+// "lookup" of the service "crm" is called as "crm.lookup", and must not be the
+// name of a configured service.
+//
+// Summary: Registers an in-process tool.
+//
+// Parameters:
+//   - t (tool.Tool): The tool.
+//
+// Returns:
+//   - error: ErrServerStarted once the server runs, or an error if the tool has no name or service ID.
+func (s *Server) AddTool(t tool.Tool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return ErrServerStarted
+	}
+	if t.Tool().GetName() == "" || t.Tool().GetServiceId() == "" {
+		return fmt.Errorf("the tool must have a name and a service ID")
+	}
+	s.app.inProcessTools = append(s.app.inProcessTools, t)
+	return nil
+}
+
+// RegisterTool serves a native Go function as a tool. A name "crm.lookup"
+// registers the tool "lookup" of the service "crm"; a name without a service
+// registers the tool in InProcessService. The calls of the tool are
+// authenticated, audited, rate limited and guarded by the resilience policy
+// of its service, like the calls of the tools of the upstream services.
+//
+// Summary: Registers a Go function as a tool.
+//
+// Parameters:
+//   - name (string): The name of the tool.
+//   - schema (map[string]any): The JSON schema of the arguments; nil accepts any object.
+//   - handler (ToolHandler): The function executing the calls.
+//   - opts (...ToolOption): Options such as WithDescription.
+//
+// Returns:
+//   - error: ErrServerStarted once the server runs, or an error if the name or schema is invalid.
+func (s *Server) RegisterTool(name string, schema map[string]any, handler ToolHandler, opts ...ToolOption) error {
+	t, err := newFuncTool(name, schema, handler, opts...)
+	if err != nil {
+		return err
+	}
+	return s.AddTool(t)
+}
+
+// ConfigureService sets the policies of a service of in-process tools: its
+// authentication, rate limit and resilience. The service config must not
+// define an upstream, since the tools of the service run in the process.
+//
+// Summary: Configures a service of in-process tools.
+//
+// Parameters:
+//   - cfg (*configv1.UpstreamServiceConfig): The config; its name is the service of the tools.
+//
+// Returns:
+//   - error: ErrServerStarted once the server runs, or an error if the config is invalid.
+func (s *Server) ConfigureService(cfg *configv1.UpstreamServiceConfig) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return ErrServerStarted
+	}
+	if cfg.GetName() == "" {
+		return fmt.Errorf("the service must have a name")
+	}
+	if cfg.WhichServiceConfig() != configv1.UpstreamServiceConfig_ServiceConfig_not_set_case {
+		return fmt.Errorf("service %q of in-process tools must not define an upstream", cfg.GetName())
+	}
+	if s.app.inProcessServices == nil {
+		s.app.inProcessServices = make(map[string]*configv1.UpstreamServiceConfig)
+	}
+	s.app.inProcessServices[cfg.GetName()] = proto.Clone(cfg).(*configv1.UpstreamServiceConfig)
+	return nil
+}
+
+// Run serves the server until the context is canceled.
+//
+// Summary: Runs the embedded server.
+//
+// Parameters:
+//   - ctx (context.Context): The context; canceling it shuts the server down.
+//
+// Returns:
+//   - error: ErrServerStarted if the server already ran, or an error if the server fails.
+//
+// Side Effects:
+//   - Listens on the addresses of the options, or serves stdio.
+//   - Connects to the upstream services of the configuration.
+func (s *Server) Run(ctx context.Context) error {
+	s.mu.Lock()
+	if s.started {
+		s.mu.Unlock()
+		return ErrServerStarted
+	}
+	s.started = true
+	s.mu.Unlock()
+
+	return s.app.Run(RunOptions{
+		Ctx:             ctx,
+		Fs:              s.fs,
+		Stdio:           s.opts.Transport == TransportStdio,
+		JSONRPCPort:     s.opts.HTTPAddress,
+		GRPCPort:        s.opts.GRPCAddress,
+		ConfigPaths:     []string{embeddedConfigPath},
+		APIKey:          s.opts.APIKey,
+		ShutdownTimeout: s.opts.ShutdownTimeout,
+	})
+}
+
+// WaitForStartup blocks until the server serves HTTP.
+//
+// Summary: Waits for the server to start.
+//
+// Parameters:
+//   - ctx (context.Context): The context bounding the wait.
+//
+// Returns:
+//   - error: The error of the context if it ends first.
+func (s *Server) WaitForStartup(ctx context.Context) error {
+	return s.app.WaitForStartup(ctx)
+}
+
+// HTTPPort returns the port the HTTP server listens on, which is useful with
+// a ":0" address. It is 0 until the server has started.
+//
+// Summary: Returns the bound HTTP port.
+//
+// Returns:
+//   - int: The port.
+func (s *Server) HTTPPort() int {
+	return int(s.app.BoundHTTPPort.Load())
+}
+
+// Application returns the application the server runs, for advanced use.
+//
+// Summary: Returns the underlying application.
+//
+// Returns:
+//   - *Application: The application.
+func (s *Server) Application() *Application {
+	return s.app
+}
+
+// registerInProcessTools registers the tools of the embedding program with
+// the tool manager, each service of them with its config, or a minimal one, so
+// the profiles and policies keyed by service apply to them.
+func (a *Application) registerInProcessTools(ctx context.Context) error {
+	for _, t := range a.inProcessTools {
+		serviceID := t.Tool().GetServiceId()
+		if _, ok := a.ToolManager.GetServiceInfo(serviceID); !ok {
+			cfg, ok := a.inProcessServices[serviceID]
+			if !ok {
+				cfg = configv1.UpstreamServiceConfig_builder{Name: proto.String(serviceID)}.Build()
+			}
+			cfg.SetId(serviceID)
+			if err := a.addInProcessAuthenticators(ctx, serviceID, cfg.GetAuthentication()); err != nil {
+				return err
+			}
+			a.ToolManager.AddServiceInfo(serviceID, &tool.ServiceInfo{
+				Name:         serviceID,
+				Config:       cfg,
+				HealthStatus: tool.HealthStatusHealthy,
+			})
+		}
+		if err := a.ToolManager.AddTool(t); err != nil {
+			return fmt.Errorf("failed to register in-process tool %s.%s: %w", serviceID, t.Tool().GetName(), err)
+		}
+	}
+	return nil
+}
+
+// addInProcessAuthenticators registers the authenticators of a service of
+// in-process tools, like the service registry does for upstream services.
+func (a *Application) addInProcessAuthenticators(ctx context.Context, serviceID string, authConfig *configv1.Authentication) error {
+	if apiKeyConfig := authConfig.GetApiKey(); apiKeyConfig != nil {
+		if err := a.AuthManager.AddAuthenticator(serviceID, auth.NewAPIKeyAuthenticator(apiKeyConfig)); err != nil {
+			return fmt.Errorf("failed to add api key authenticator of service %s: %w", serviceID, err)
+		}
+	}
+	if oauth2Config := authConfig.GetOauth2(); oauth2Config != nil {
+		if err := a.AuthManager.AddOAuth2Authenticator(ctx, serviceID, &auth.OAuth2Config{
+			IssuerURL: oauth2Config.GetIssuerUrl(),
+			Audience:  oauth2Config.GetAudience(),
+		}); err != nil {
+			return fmt.Errorf("failed to add oauth2 authenticator of service %s: %w", serviceID, err)
+		}
+	}
+	return nil
+}
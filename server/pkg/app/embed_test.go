@@ -0,0 +1,167 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	v1 "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func newEmbeddedServer(t *testing.T) *Server {
+	t.Helper()
+	srv, err := NewServer(configv1.McpAnyServerConfig_builder{
+		GlobalSettings: configv1.GlobalSettings_builder{LogLevel: configv1.GlobalSettings_LOG_LEVEL_ERROR.Enum()}.Build(),
+	}.Build(), ServerOptions{HTTPAddress: "127.0.0.1:0", APIKey: "embed-key"})
+	require.NoError(t, err)
+	return srv
+}
+
+// runEmbedded runs a server until the end of the test, and connects an MCP
+// client to it.
+func runEmbedded(t *testing.T, srv *Server) *mcp.ClientSession {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- srv.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		select {
+		case <-runErr:
+		case <-time.After(10 * time.Second):
+			t.Log("the embedded server did not shut down in time")
+		}
+	})
+	startupCtx, startupCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer startupCancel()
+	require.NoError(t, srv.WaitForStartup(startupCtx))
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "embed-test", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, &mcp.StreamableClientTransport{
+		Endpoint:   fmt.Sprintf("http://127.0.0.1:%d/mcp", srv.HTTPPort()),
+		HTTPClient: &http.Client{Transport: apiKeyTransport("embed-key")},
+		MaxRetries: -1,
+	}, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+	return session
+}
+
+func TestServer_Embedded(t *testing.T) {
+	srv := newEmbeddedServer(t)
+	inputSchema, err := structpb.NewStruct(map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"name": map[string]any{"type": "string"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, srv.AddTool(&tool.MockTool{
+		ToolFunc: func() *v1.Tool {
+			return v1.Tool_builder{ServiceId: proto.String("greeter"), Name: proto.String("greet"), InputSchema: inputSchema}.Build()
+		},
+		MCPToolFunc: func() *mcp.Tool {
+			return &mcp.Tool{Name: "greeter.greet", InputSchema: inputSchema.AsMap()}
+		},
+		ExecuteFunc: func(_ context.Context, req *tool.ExecutionRequest) (any, error) {
+			var args struct{ Name string }
+			if err := json.Unmarshal(req.ToolInputs, &args); err != nil {
+				return nil, err
+			}
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "Hello, " + args.Name}}}, nil
+		},
+	}))
+	require.Error(t, srv.AddTool(&tool.MockTool{ToolFunc: func() *v1.Tool { return v1.Tool_builder{Name: proto.String("anonymous")}.Build() }}))
+
+	session := runEmbedded(t, srv)
+	assert.ErrorIs(t, srv.Run(context.Background()), ErrServerStarted)
+	assert.ErrorIs(t, srv.AddTool(&tool.MockTool{}), ErrServerStarted)
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "greeter.greet", Arguments: map[string]any{"name": "Ada"}})
+	require.NoError(t, err)
+	require.False(t, res.IsError, "%+v", res.Content)
+	require.Len(t, res.Content, 1)
+	assert.Equal(t, "Hello, Ada", res.Content[0].(*mcp.TextContent).Text)
+}
+
+func TestServer_RegisterTool(t *testing.T) {
+	srv := newEmbeddedServer(t)
+	require.NoError(t, srv.RegisterTool("math.add", map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"a": map[string]any{"type": "number"},
+			"b": map[string]any{"type": "number"},
+		},
+	}, func(_ context.Context, args json.RawMessage) (any, error) {
+		var in struct{ A, B float64 }
+		if err := json.Unmarshal(args, &in); err != nil {
+			return nil, err
+		}
+		return map[string]float64{"sum": in.A + in.B}, nil
+	}, WithDescription("Adds two numbers."), WithReadOnly()))
+	require.NoError(t, srv.RegisterTool("ping", nil, func(context.Context, json.RawMessage) (any, error) {
+		return "pong", nil
+	}))
+	require.NoError(t, srv.ConfigureService(configv1.UpstreamServiceConfig_builder{
+		Name:       proto.String("reports"),
+		Resilience: configv1.ResilienceConfig_builder{Timeout: durationpb.New(50 * time.Millisecond)}.Build(),
+	}.Build()))
+	require.NoError(t, srv.RegisterTool("reports.build", nil, func(ctx context.Context, _ json.RawMessage) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}))
+
+	require.Error(t, srv.RegisterTool("math.", nil, func(context.Context, json.RawMessage) (any, error) { return nil, nil }))
+	require.Error(t, srv.RegisterTool("math.nil", nil, nil))
+	require.Error(t, srv.ConfigureService(configv1.UpstreamServiceConfig_builder{
+		Name:        proto.String("proxied"),
+		HttpService: configv1.HttpUpstreamService_builder{Address: proto.String("http://localhost:8080")}.Build(),
+	}.Build()), "in-process services have no upstream")
+
+	session := runEmbedded(t, srv)
+	ctx := context.Background()
+
+	tools, err := session.ListTools(ctx, nil)
+	require.NoError(t, err)
+	byName := map[string]*mcp.Tool{}
+	for _, tl := range tools.Tools {
+		byName[tl.Name] = tl
+	}
+	require.Contains(t, byName, "math.add")
+	assert.Equal(t, "Adds two numbers.", byName["math.add"].Description)
+	require.Contains(t, byName, InProcessService+".ping", "tools without a service go to the in-process service")
+
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "math.add", Arguments: map[string]any{"a": 2, "b": 3}})
+	require.NoError(t, err)
+	require.False(t, res.IsError, "%+v", res.Content)
+	assert.JSONEq(t, `{"sum": 5}`, res.Content[0].(*mcp.TextContent).Text)
+
+	// The resilience policy of the service bounds its calls.
+	start := time.Now()
+	res, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "reports.build"})
+	if err == nil {
+		assert.True(t, res.IsError)
+	}
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+// apiKeyTransport sends its API key with every request.
+type apiKeyTransport string
+
+func (k apiKeyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("X-API-Key", string(k))
+	return http.DefaultTransport.RoundTrip(req)
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	v1 "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// InProcessService is the service of the in-process tools registered with a
+// name that does not name their service.
+const InProcessService = "local"
+
+// ToolHandler is a native Go function served as a tool.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the call, carrying the authenticated user and profile.
+//   - args (json.RawMessage): The arguments of the call, a JSON object.
+//
+// Returns:
+//   - any: The result, an *mcp.CallToolResult or any value encoded as JSON.
+//   - error: An error if the call fails.
+type ToolHandler func(ctx context.Context, args json.RawMessage) (any, error)
+
+// ToolOption configures a tool registered with Server.RegisterTool.
+type ToolOption func(*v1.Tool_builder)
+
+// WithDescription sets the description of the tool, shown to the clients.
+//
+// Parameters:
+//   - description (string): The description.
+//
+// Returns:
+//   - ToolOption: The option.
+func WithDescription(description string) ToolOption {
+	return func(b *v1.Tool_builder) { b.Description = proto.String(description) }
+}
+
+// WithReadOnly marks the tool as not changing its environment, so that
+// clients may call it without confirmation.
+//
+// Returns:
+//   - ToolOption: The option.
+func WithReadOnly() ToolOption {
+	return func(b *v1.Tool_builder) {
+		b.Annotations = v1.ToolAnnotations_builder{ReadOnlyHint: proto.Bool(true)}.Build()
+	}
+}
+
+// funcTool is a tool executed by a ToolHandler.
+type funcTool struct {
+	tool    *v1.Tool
+	mcpTool *mcp.Tool
+	handler ToolHandler
+}
+
+// newFuncTool creates the tool of a handler. A name "crm.lookup" registers
+// the tool "lookup" of the service "crm"; a name without a service registers
+// the tool in InProcessService.
+func newFuncTool(name string, schema map[string]any, handler ToolHandler, opts ...ToolOption) (*funcTool, error) {
+	if handler == nil {
+		return nil, fmt.Errorf("the handler of tool %q is nil", name)
+	}
+	serviceID, toolName, found := strings.Cut(name, ".")
+	if !found {
+		serviceID, toolName = InProcessService, name
+	}
+	if serviceID == "" || toolName == "" {
+		return nil, fmt.Errorf("invalid tool name %q", name)
+	}
+	if schema == nil {
+		schema = map[string]any{"type": "object"}
+	}
+	inputSchema, err := structpb.NewStruct(schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid input schema of tool %q: %w", name, err)
+	}
+
+	b := v1.Tool_builder{
+		Name:        proto.String(toolName),
+		ServiceId:   proto.String(serviceID),
+		InputSchema: inputSchema,
+	}
+	for _, opt := range opts {
+		opt(&b)
+	}
+	t := b.Build()
+	mcpTool, err := tool.ConvertProtoToMCPTool(t)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tool %q: %w", name, err)
+	}
+	mcpTool.InputSchema = schema
+	return &funcTool{tool: t, mcpTool: mcpTool, handler: handler}, nil
+}
+
+// Tool returns the protobuf definition of the tool.
+//
+// Returns:
+//   - *v1.Tool: The definition.
+func (t *funcTool) Tool() *v1.Tool {
+	return t.tool
+}
+
+// MCPTool returns the MCP definition of the tool.
+//
+// Returns:
+//   - *mcp.Tool: The definition.
+func (t *funcTool) MCPTool() *mcp.Tool {
+	return t.mcpTool
+}
+
+// Execute calls the handler with the arguments of the request.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*tool.ExecutionRequest): The request.
+//
+// Returns:
+//   - any: The result of the handler.
+//   - error: An error if the arguments are invalid or the handler fails.
+func (t *funcTool) Execute(ctx context.Context, req *tool.ExecutionRequest) (any, error) {
+	args := req.ToolInputs
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+		if req.Arguments != nil {
+			b, err := json.Marshal(req.Arguments)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", tool.ErrInvalidArguments, err)
+			}
+			args = b
+		}
+	}
+	return t.handler(ctx, args)
+}
+
+// GetCacheConfig returns nil: the results of in-process tools are not cached.
+//
+// Returns:
+//   - *configv1.CacheConfig: nil.
+func (t *funcTool) GetCacheConfig() *configv1.CacheConfig {
+	return nil
+}
+
+var _ tool.Tool = (*funcTool)(nil)
@@ -0,0 +1,62 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"time"
+
+	config_v1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/serviceregistry"
+	"github.com/mcpany/core/server/pkg/tool"
+)
+
+// jobPersister is a tool manager that can keep the jobs of the asynchronous
+// tools in the storage, and resume them after a restart.
+type jobPersister interface {
+	SetJobStore(store tool.JobStore)
+	ResumeJobs(ctx context.Context, serviceID string) (int, error)
+	RenewJobLeases(ctx context.Context)
+}
+
+// jobResumer returns the hook resuming the unfinished jobs of a service once
+// it is registered.
+func jobResumer(jobs jobPersister) serviceregistry.RegisteredHook {
+	return func(ctx context.Context, serviceID string, _ *config_v1.UpstreamServiceConfig) {
+		resumeJobs(ctx, jobs, serviceID)
+	}
+}
+
+// resumeJobs resumes the unfinished jobs of a service, or of all the
+// registered services if serviceID is empty, whose replica is gone.
+func resumeJobs(ctx context.Context, jobs jobPersister, serviceID string) {
+	n, err := jobs.ResumeJobs(ctx, serviceID)
+	if err != nil {
+		logging.GetLogger().Error("Failed to resume jobs", "service", serviceID, "error", err)
+		return
+	}
+	if n > 0 {
+		logging.GetLogger().Info("Resumed jobs", "service", serviceID, "count", n)
+	}
+}
+
+// startJobLeases starts a background worker that renews the leases of the
+// jobs this replica waits for, and resumes the jobs of the replicas that
+// stopped, every interval.
+func startJobLeases(ctx context.Context, jobs jobPersister, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			jobs.RenewJobLeases(ctx)
+			resumeJobs(ctx, jobs, "")
+		}
+	}()
+}
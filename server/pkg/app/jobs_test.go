@@ -0,0 +1,47 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingJobs counts the lease renewals and the resumed services.
+type recordingJobs struct {
+	renewed atomic.Int32
+	resumed atomic.Value
+}
+
+func (r *recordingJobs) SetJobStore(tool.JobStore) {}
+
+func (r *recordingJobs) ResumeJobs(_ context.Context, serviceID string) (int, error) {
+	r.resumed.Store(serviceID)
+	return 0, nil
+}
+
+func (r *recordingJobs) RenewJobLeases(context.Context) {
+	r.renewed.Add(1)
+}
+
+func TestStartJobLeases(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	jobs := &recordingJobs{}
+	startJobLeases(ctx, jobs, 5*time.Millisecond)
+
+	require.Eventually(t, func() bool { return jobs.renewed.Load() >= 2 }, 5*time.Second, 5*time.Millisecond)
+	assert.Equal(t, "", jobs.resumed.Load(), "the jobs of all the services are resumed")
+
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+	renewed := jobs.renewed.Load()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, renewed, jobs.renewed.Load(), "the worker stops with the server")
+}
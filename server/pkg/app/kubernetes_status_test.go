@@ -0,0 +1,41 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/serviceregistry"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestKubernetesServiceStatus(t *testing.T) {
+	registry := &serviceregistry.MockServiceRegistry{}
+	registry.On("GetAllServices").Return([]*configv1.UpstreamServiceConfig{
+		configv1.UpstreamServiceConfig_builder{Name: proto.String("weather"), Id: proto.String("weather-id")}.Build(),
+		configv1.UpstreamServiceConfig_builder{Name: proto.String("github"), Id: proto.String("github-id")}.Build(),
+		configv1.UpstreamServiceConfig_builder{Name: proto.String("legacy"), Disable: proto.Bool(true)}.Build(),
+	}, nil)
+	registry.On("GetServiceError", "weather-id").Return("", false)
+	registry.On("GetServiceError", "github-id").Return("health check failed", true)
+
+	a := NewApplication()
+	a.ServiceRegistry = registry
+
+	for name, want := range map[string]struct {
+		ready   bool
+		message string
+	}{
+		"weather": {true, ""},
+		"github":  {false, "health check failed"},
+		"legacy":  {false, "disabled"},
+		"unknown": {false, "not registered"},
+	} {
+		ready, message := a.kubernetesServiceStatus(name)
+		assert.Equal(t, want.ready, ready, name)
+		assert.Equal(t, want.message, message, name)
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/mcpany/core/server/pkg/util"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// Client certificate policies of the MCP listener.
+const (
+	// ClientAuthRequire rejects TLS handshakes without a valid client certificate.
+	ClientAuthRequire = "require"
+	// ClientAuthOptional verifies client certificates when presented; clients
+	// without one authenticate by other means.
+	ClientAuthOptional = "optional"
+)
+
+const defaultACMECacheDir = "data/acme"
+
+// ListenerTLSOptions configures TLS termination on the MCP listen address.
+//
+// The certificate either comes from CertFile and KeyFile or is provisioned
+// from an ACME CA such as Let's Encrypt for ACMEDomains. With ClientCAFile
+// set, clients can authenticate with a certificate issued by that CA.
+type ListenerTLSOptions struct {
+	// CertFile is the PEM certificate (chain) served to clients.
+	CertFile string
+	// KeyFile is the PEM private key of CertFile.
+	KeyFile string
+	// ClientCAFile is the CA bundle verifying client certificates.
+	ClientCAFile string
+	// ClientAuth is ClientAuthRequire (the default) or ClientAuthOptional.
+	ClientAuth string
+	// ACMEDomains are the host names to obtain certificates for.
+	ACMEDomains []string
+	// ACMEEmail is the contact address of the ACME account.
+	ACMEEmail string
+	// ACMECacheDir stores the account key and certificates; "data/acme" by default.
+	ACMECacheDir string
+	// ACMEDirectoryURL is the ACME directory; Let's Encrypt production by default.
+	ACMEDirectoryURL string
+}
+
+// Enabled reports whether the listener serves TLS.
+//
+// Returns:
+//   - bool: True if a certificate file or ACME domains are configured.
+func (o ListenerTLSOptions) Enabled() bool {
+	return o.CertFile != "" || o.KeyFile != "" || len(o.ACMEDomains) > 0
+}
+
+// TLSConfig builds the TLS configuration of the listener.
+//
+// Summary: Builds the listener TLS configuration.
+//
+// Returns:
+//   - *tls.Config: The configuration, nil if TLS is not enabled.
+//   - error: An error if the options are inconsistent or a file cannot be read.
+func (o ListenerTLSOptions) TLSConfig() (*tls.Config, error) {
+	if !o.Enabled() {
+		if o.ClientCAFile != "" {
+			return nil, fmt.Errorf("--tls-client-ca requires --tls-cert and --tls-key or --acme-domains")
+		}
+		return nil, nil
+	}
+	clientAuth, err := o.clientAuthType()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(o.ACMEDomains) == 0 {
+		if o.CertFile == "" || o.KeyFile == "" {
+			return nil, fmt.Errorf("--tls-cert and --tls-key must be set together")
+		}
+		return util.NewTLSServerConfig(o.CertFile, o.KeyFile, o.ClientCAFile, clientAuth)
+	}
+	if o.CertFile != "" || o.KeyFile != "" {
+		return nil, fmt.Errorf("--acme-domains cannot be combined with --tls-cert and --tls-key")
+	}
+	return o.acmeTLSConfig(clientAuth)
+}
+
+func (o ListenerTLSOptions) clientAuthType() (tls.ClientAuthType, error) {
+	mode := strings.ToLower(o.ClientAuth)
+	if o.ClientCAFile == "" {
+		if mode != "" {
+			return tls.NoClientCert, fmt.Errorf("--tls-client-auth requires --tls-client-ca")
+		}
+		return tls.NoClientCert, nil
+	}
+	switch mode {
+	case "", ClientAuthRequire:
+		return tls.RequireAndVerifyClientCert, nil
+	case ClientAuthOptional:
+		return tls.VerifyClientCertIfGiven, nil
+	default:
+		return tls.NoClientCert, fmt.Errorf("invalid --tls-client-auth %q: must be %q or %q", o.ClientAuth, ClientAuthRequire, ClientAuthOptional)
+	}
+}
+
+// acmeTLSConfig obtains and renews certificates with the TLS-ALPN-01
+// challenge, which the ACME CA completes over the listener itself; the
+// listener must therefore be reachable on port 443 of the domains.
+func (o ListenerTLSOptions) acmeTLSConfig(clientAuth tls.ClientAuthType) (*tls.Config, error) {
+	cacheDir := o.ACMECacheDir
+	if cacheDir == "" {
+		cacheDir = defaultACMECacheDir
+	}
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      autocert.DirCache(cacheDir),
+		HostPolicy: autocert.HostWhitelist(o.ACMEDomains...),
+		Email:      o.ACMEEmail,
+	}
+	if o.ACMEDirectoryURL != "" {
+		manager.Client = &acme.Client{DirectoryURL: o.ACMEDirectoryURL}
+	}
+
+	tlsConfig := manager.TLSConfig()
+	if o.ClientCAFile == "" {
+		return tlsConfig, nil
+	}
+	pool, err := util.LoadCertPool(o.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TLS client CA: %w", err)
+	}
+	return withACMEChallengeConfig(tlsConfig, pool, clientAuth), nil
+}
+
+// withACMEChallengeConfig requires client certificates on tlsConfig except
+// for TLS-ALPN-01 challenge handshakes: the CA validating a challenge has no
+// client certificate. Challenge handshakes can only negotiate
+// acme.ALPNProto, so a client offering it alongside h2 or http/1.1 cannot
+// reach the MCP endpoints without a certificate; see
+// closeACMEChallengeConns for the connections themselves.
+func withACMEChallengeConfig(tlsConfig *tls.Config, clientCAs *x509.CertPool, clientAuth tls.ClientAuthType) *tls.Config {
+	challengeConfig := tlsConfig.Clone()
+	challengeConfig.NextProtos = []string{acme.ALPNProto}
+	tlsConfig.ClientCAs = clientCAs
+	tlsConfig.ClientAuth = clientAuth
+	tlsConfig.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		if slices.Contains(hello.SupportedProtos, acme.ALPNProto) {
+			return challengeConfig, nil
+		}
+		return nil, nil
+	}
+	return tlsConfig
+}
+
+// closeACMEChallengeConns makes srv close connections that negotiated the
+// TLS-ALPN-01 protocol once the handshake, which is all the CA needs, is
+// done, instead of serving HTTP on them. HTTP/1.1 and HTTP/2 stay enabled.
+func closeACMEChallengeConns(srv *http.Server) {
+	if srv.Protocols == nil {
+		srv.Protocols = new(http.Protocols)
+		srv.Protocols.SetHTTP1(true)
+		srv.Protocols.SetHTTP2(true)
+	}
+	if srv.TLSNextProto == nil {
+		srv.TLSNextProto = make(map[string]func(*http.Server, *tls.Conn, http.Handler))
+	}
+	srv.TLSNextProto[acme.ALPNProto] = func(_ *http.Server, conn *tls.Conn, _ http.Handler) {
+		_ = conn.Close()
+	}
+}
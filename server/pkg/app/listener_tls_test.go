@@ -0,0 +1,432 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/mcpany/core/proto/mcp_router/v1"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/acme"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// testCert is a certificate signed by the test CA.
+type testCert struct {
+	cert     *x509.Certificate
+	key      *ecdsa.PrivateKey
+	certPath string
+	keyPath  string
+}
+
+func (c testCert) tlsCertificate() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.cert.Raw}, PrivateKey: c.key}
+}
+
+// issueTestCert writes a certificate signed by ca (self-signed if nil).
+func issueTestCert(t *testing.T, dir, name string, ca *testCert, tmpl *x509.Certificate) testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	tmpl.SerialNumber = serial
+	tmpl.NotBefore = time.Now().Add(-time.Minute)
+	tmpl.NotAfter = time.Now().Add(time.Hour)
+
+	parent, signer := tmpl, key
+	if ca != nil {
+		parent, signer = ca.cert, ca.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, signer)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	c := testCert{
+		cert:     cert,
+		key:      key,
+		certPath: filepath.Join(dir, name+".pem"),
+		keyPath:  filepath.Join(dir, name+".key"),
+	}
+	require.NoError(t, os.WriteFile(c.certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(c.keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return c
+}
+
+func TestListenerTLSOptions_TLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	ca := issueTestCert(t, dir, "ca", nil, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	})
+	server := issueTestCert(t, dir, "server", &ca, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "localhost"},
+		IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1)},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+
+	tests := []struct {
+		name       string
+		opts       ListenerTLSOptions
+		wantNil    bool
+		wantAuth   tls.ClientAuthType
+		wantErrMsg string
+	}{
+		{name: "disabled", wantNil: true},
+		{name: "certificate", opts: ListenerTLSOptions{CertFile: server.certPath, KeyFile: server.keyPath}, wantAuth: tls.NoClientCert},
+		{name: "client CA requires certificates by default", opts: ListenerTLSOptions{CertFile: server.certPath, KeyFile: server.keyPath, ClientCAFile: ca.certPath}, wantAuth: tls.RequireAndVerifyClientCert},
+		{name: "optional client certificates", opts: ListenerTLSOptions{CertFile: server.certPath, KeyFile: server.keyPath, ClientCAFile: ca.certPath, ClientAuth: "optional"}, wantAuth: tls.VerifyClientCertIfGiven},
+		{name: "cert without key", opts: ListenerTLSOptions{CertFile: server.certPath}, wantErrMsg: "must be set together"},
+		{name: "client CA without TLS", opts: ListenerTLSOptions{ClientCAFile: ca.certPath}, wantErrMsg: "--tls-client-ca requires"},
+		{name: "client auth without CA", opts: ListenerTLSOptions{CertFile: server.certPath, KeyFile: server.keyPath, ClientAuth: "optional"}, wantErrMsg: "requires --tls-client-ca"},
+		{name: "unknown client auth", opts: ListenerTLSOptions{CertFile: server.certPath, KeyFile: server.keyPath, ClientCAFile: ca.certPath, ClientAuth: "sometimes"}, wantErrMsg: "invalid --tls-client-auth"},
+		{name: "ACME and certificate", opts: ListenerTLSOptions{CertFile: server.certPath, KeyFile: server.keyPath, ACMEDomains: []string{"mcp.example.com"}}, wantErrMsg: "cannot be combined"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tlsConfig, err := tt.opts.TLSConfig()
+			if tt.wantErrMsg != "" {
+				assert.ErrorContains(t, err, tt.wantErrMsg)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantNil {
+				assert.Nil(t, tlsConfig)
+				return
+			}
+			require.NotNil(t, tlsConfig)
+			assert.Equal(t, tt.wantAuth, tlsConfig.ClientAuth)
+		})
+	}
+}
+
+func TestListenerTLSOptions_ACME(t *testing.T) {
+	dir := t.TempDir()
+	ca := issueTestCert(t, dir, "ca", nil, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	})
+
+	tlsConfig, err := ListenerTLSOptions{
+		ACMEDomains:  []string{"mcp.example.com"},
+		ACMECacheDir: filepath.Join(dir, "acme"),
+		ClientCAFile: ca.certPath,
+	}.TLSConfig()
+	require.NoError(t, err)
+	assert.Contains(t, tlsConfig.NextProtos, acme.ALPNProto, "TLS-ALPN-01 challenges are answered on the listener")
+	assert.Equal(t, tls.RequireAndVerifyClientCert, tlsConfig.ClientAuth)
+	require.NotNil(t, tlsConfig.GetConfigForClient)
+
+	challenge, err := tlsConfig.GetConfigForClient(&tls.ClientHelloInfo{SupportedProtos: []string{acme.ALPNProto}})
+	require.NoError(t, err)
+	require.NotNil(t, challenge)
+	assert.Equal(t, tls.NoClientCert, challenge.ClientAuth, "the ACME CA has no client certificate")
+	assert.Equal(t, []string{acme.ALPNProto}, challenge.NextProtos, "challenge handshakes cannot negotiate HTTP")
+
+	regular, err := tlsConfig.GetConfigForClient(&tls.ClientHelloInfo{SupportedProtos: []string{"h2"}})
+	require.NoError(t, err)
+	assert.Nil(t, regular)
+
+	// Certificates are only requested for the configured domains.
+	_, err = tlsConfig.GetCertificate(&tls.ClientHelloInfo{ServerName: "other.example.com"})
+	assert.Error(t, err)
+}
+
+func TestListenerTLS_ClientCertificateAuthentication(t *testing.T) {
+	dir := t.TempDir()
+	ca := issueTestCert(t, dir, "ca", nil, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	})
+	server := issueTestCert(t, dir, "server", &ca, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "localhost"},
+		IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1)},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	client := issueTestCert(t, dir, "client", &ca, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "ci-bot"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	rogueCA := issueTestCert(t, dir, "rogue-ca", nil, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Rogue CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	})
+	rogue := issueTestCert(t, dir, "rogue", &rogueCA, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "ci-bot"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	app := NewApplication()
+	app.AuthManager = auth.NewManager()
+	app.AuthManager.SetUsers([]*configv1.User{configv1.User_builder{
+		Id:    proto.String("ci-bot"),
+		Roles: []string{"viewer"},
+	}.Build()})
+	app.SettingsManager = NewGlobalSettingsManager("server-key", nil, nil)
+
+	handler := app.createAuthMiddleware(false, false)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _ := auth.UserFromContext(r.Context())
+		roles, _ := auth.RolesFromContext(r.Context())
+		_, _ = w.Write([]byte(user + ":" + strings.Join(roles, ",")))
+	}))
+
+	start := func(t *testing.T, clientAuth string) *httptest.Server {
+		t.Helper()
+		tlsConfig, err := ListenerTLSOptions{
+			CertFile:     server.certPath,
+			KeyFile:      server.keyPath,
+			ClientCAFile: ca.certPath,
+			ClientAuth:   clientAuth,
+		}.TLSConfig()
+		require.NoError(t, err)
+		// Wrap the listener like runServerMode does; StartTLS would add its own certificate.
+		srv := httptest.NewUnstartedServer(handler)
+		srv.Listener = tls.NewListener(srv.Listener, tlsConfig)
+		srv.Start()
+		t.Cleanup(srv.Close)
+		return srv
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	get := func(srv *httptest.Server, certs []tls.Certificate, apiKey string) (string, int, error) {
+		httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs:      roots,
+			Certificates: certs,
+			MinVersion:   tls.VersionTLS12,
+		}}}
+		defer httpClient.CloseIdleConnections()
+		req, err := http.NewRequest(http.MethodGet, strings.Replace(srv.URL, "http://", "https://", 1), nil)
+		require.NoError(t, err)
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return "", 0, err
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body), resp.StatusCode, nil
+	}
+
+	t.Run("required", func(t *testing.T) {
+		srv := start(t, "")
+
+		body, code, err := get(srv, []tls.Certificate{client.tlsCertificate()}, "")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "ci-bot:viewer", body)
+
+		_, _, err = get(srv, nil, "server-key")
+		assert.Error(t, err, "the handshake fails without a client certificate")
+
+		_, _, err = get(srv, []tls.Certificate{rogue.tlsCertificate()}, "")
+		assert.Error(t, err, "certificates from other CAs are rejected")
+	})
+
+	t.Run("optional", func(t *testing.T) {
+		srv := start(t, "optional")
+
+		body, code, err := get(srv, []tls.Certificate{client.tlsCertificate()}, "")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "ci-bot:viewer", body)
+
+		body, code, err = get(srv, nil, "server-key")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "system-admin:admin", body)
+
+		_, code, err = get(srv, nil, "")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+}
+
+func TestListenerTLS_ACMEChallengeConnectionsServeNoHTTP(t *testing.T) {
+	dir := t.TempDir()
+	ca := issueTestCert(t, dir, "ca", nil, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	})
+	server := issueTestCert(t, dir, "server", &ca, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "localhost"},
+		IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1)},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	// The certificate stands in for the challenge certificate autocert
+	// serves to the CA.
+	tlsConfig := withACMEChallengeConfig(&tls.Config{
+		Certificates: []tls.Certificate{server.tlsCertificate()},
+		NextProtos:   []string{"h2", "http/1.1", acme.ALPNProto},
+		MinVersion:   tls.VersionTLS12,
+	}, clientCAs, tls.RequireAndVerifyClientCert)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("served"))
+	}))
+	closeACMEChallengeConns(srv.Config)
+	srv.Listener = tls.NewListener(srv.Listener, tlsConfig)
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	for _, protos := range [][]string{
+		{acme.ALPNProto},
+		{acme.ALPNProto, "h2", "http/1.1"},
+	} {
+		t.Run(strings.Join(protos, ","), func(t *testing.T) {
+			conn, err := tls.Dial("tcp", srv.Listener.Addr().String(), &tls.Config{
+				RootCAs:    roots,
+				NextProtos: protos,
+				MinVersion: tls.VersionTLS12,
+			})
+			require.NoError(t, err, "the challenge handshake succeeds without a client certificate")
+			defer func() { _ = conn.Close() }()
+			assert.Equal(t, acme.ALPNProto, conn.ConnectionState().NegotiatedProtocol)
+
+			_, _ = conn.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\n\r\n"))
+			_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			response, err := io.ReadAll(conn)
+			assert.NotContains(t, string(response), "served")
+			if err != nil {
+				assert.NotErrorIs(t, err, os.ErrDeadlineExceeded, "the connection is closed")
+			}
+		})
+	}
+
+	httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:    roots,
+		NextProtos: []string{acme.ALPNProto},
+		MinVersion: tls.VersionTLS12,
+	}}}
+	defer httpClient.CloseIdleConnections()
+	resp, err := httpClient.Get(strings.Replace(srv.URL, "http://", "https://", 1))
+	if err == nil {
+		_ = resp.Body.Close()
+	}
+	assert.Error(t, err, "HTTP requests over acme-tls/1 connections are refused")
+}
+
+func TestListenerTLS_ClientCertificateMCPCall(t *testing.T) {
+	dir := t.TempDir()
+	ca := issueTestCert(t, dir, "ca", nil, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	})
+	server := issueTestCert(t, dir, "server", &ca, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "localhost"},
+		IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1)},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	client := issueTestCert(t, dir, "client", &ca, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "ci-bot"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/config.yaml", []byte("upstream_services: []"), 0o644))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	t.Cleanup(logging.GlobalBroadcaster.Reset)
+
+	// The tool answers with the user the call was authenticated as.
+	inputSchema, err := structpb.NewStruct(map[string]any{"type": "object"})
+	require.NoError(t, err)
+	app := NewApplication()
+	app.inProcessTools = []tool.Tool{&tool.MockTool{
+		ToolFunc: func() *v1.Tool {
+			return v1.Tool_builder{ServiceId: proto.String("whoami"), Name: proto.String("user"), InputSchema: inputSchema}.Build()
+		},
+		MCPToolFunc: func() *mcp.Tool {
+			return &mcp.Tool{Name: "whoami.user", InputSchema: inputSchema.AsMap()}
+		},
+		ExecuteFunc: func(ctx context.Context, _ *tool.ExecutionRequest) (any, error) {
+			user, _ := auth.UserFromContext(ctx)
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: user}}}, nil
+		},
+	}}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- app.Run(RunOptions{
+			Ctx: ctx, Fs: fs, JSONRPCPort: "127.0.0.1:0", GRPCPort: "127.0.0.1:0", ConfigPaths: []string{"/config.yaml"},
+			APIKey: "server-key", ShutdownTimeout: 5 * time.Second,
+			TLSCert: server.certPath, TLSKey: server.keyPath, TLSClientCA: ca.certPath,
+		})
+	}()
+	defer func() {
+		cancel()
+		<-errChan
+	}()
+	require.NoError(t, app.WaitForStartup(ctx))
+
+	// The client sends its certificate, but not the API key.
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	transport := &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      roots,
+		Certificates: []tls.Certificate{client.tlsCertificate()},
+		MinVersion:   tls.VersionTLS12,
+	}}
+	defer transport.CloseIdleConnections()
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "mtls-test", Version: "1.0.0"}, nil).Connect(ctx, &mcp.StreamableClientTransport{
+		Endpoint:   fmt.Sprintf("https://127.0.0.1:%d/mcp", app.BoundHTTPPort.Load()),
+		HTTPClient: &http.Client{Transport: transport},
+		MaxRetries: -1,
+	}, nil)
+	require.NoError(t, err)
+	defer func() { _ = session.Close() }()
+
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "whoami.user", Arguments: map[string]any{}})
+	require.NoError(t, err)
+	require.False(t, res.IsError, "%v", res.Content)
+	require.Len(t, res.Content, 1)
+	assert.Equal(t, "ci-bot", res.Content[0].(*mcp.TextContent).Text)
+}
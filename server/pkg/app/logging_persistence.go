@@ -7,6 +7,8 @@ import (
 	"context"
 	"time"
 
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/cluster"
 	"github.com/mcpany/core/server/pkg/logging"
 	"github.com/mcpany/core/server/pkg/storage"
 )
@@ -99,3 +101,45 @@ func (a *Application) startLogPersistence(ctx context.Context, store storage.Sto
 	}()
 	log.Info("Started log persistence worker")
 }
+
+// logPruner is implemented by the storage backends that can prune persisted logs.
+type logPruner interface {
+	PruneLogs(ctx context.Context, config *configv1.RetentionConfig) (int64, error)
+}
+
+// startLogRetention starts a background worker that prunes persisted logs
+// once at start and then every retention interval, while this replica leads
+// the cluster.
+func (a *Application) startLogRetention(ctx context.Context, store logPruner, config *configv1.RetentionConfig) {
+	log := logging.GetLogger()
+	interval := config.GetInterval().AsDuration()
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			// The replicas share the logs of the database, so only the
+			// leader prunes them.
+			if cluster.IsLeader() {
+				pruneCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+				pruned, err := store.PruneLogs(pruneCtx, config)
+				cancel()
+				if err != nil {
+					log.Error("Failed to prune persisted logs", "error", err)
+				} else if pruned > 0 {
+					log.Info("Pruned persisted logs", "count", pruned)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	log.Info("Started log retention worker", "interval", interval)
+}
@@ -7,11 +7,17 @@ import (
 	"context"
 	"log/slog"
 	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/cluster"
 	"github.com/mcpany/core/server/pkg/logging"
 	"github.com/mcpany/core/server/pkg/storage/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 func TestLogPersistence_CatchUp(t *testing.T) {
@@ -144,3 +150,35 @@ func TestLogPersistence_Idempotency(t *testing.T) {
 		t.Errorf("Expected 1 log with duplicate-id, got %d", count)
 	}
 }
+
+// countingLogPruner counts the prunes of the log retention worker.
+type countingLogPruner struct {
+	prunes atomic.Int32
+}
+
+func (p *countingLogPruner) PruneLogs(context.Context, *configv1.RetentionConfig) (int64, error) {
+	p.prunes.Add(1)
+	return 0, nil
+}
+
+// followerReplica is a replica that does not lead the cluster.
+type followerReplica struct{}
+
+func (followerReplica) IsLeader() bool { return false }
+
+func TestLogRetention_OnlyLeaderPrunes(t *testing.T) {
+	cluster.SetLeader(followerReplica{})
+	t.Cleanup(func() { cluster.SetLeader(nil) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pruner := &countingLogPruner{}
+	config := configv1.RetentionConfig_builder{Interval: durationpb.New(5 * time.Millisecond)}.Build()
+	NewApplication().startLogRetention(ctx, pruner, config)
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(0), pruner.prunes.Load(), "a follower does not prune the shared logs")
+
+	cluster.SetLeader(cluster.Standalone)
+	require.Eventually(t, func() bool { return pruner.prunes.Load() > 0 }, 5*time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/mcpany/core/server/pkg/health"
+	"github.com/mcpany/core/server/pkg/notify"
+	"github.com/mcpany/core/server/pkg/resilience"
+	"github.com/mcpany/core/server/pkg/tool"
+)
+
+// installNotificationObservers sends the circuit breaker state changes, the
+// upstream health changes and the canary rollbacks to the notifier.
+//
+// Side Effects:
+//   - Replaces the process-wide circuit breaker, health status and canary
+//     rollback observers.
+func (a *Application) installNotificationObservers() {
+	resilience.SetStateChangeObserver(func(name string, from, to resilience.State) {
+		switch to {
+		case resilience.StateOpen:
+			a.notify(notify.Event{
+				Type:     notify.EventCircuitBreakerOpened,
+				Severity: notify.SeverityWarning,
+				Subject:  name,
+				Message:  fmt.Sprintf("The circuit breaker of service %s opened; its calls are rejected until a probe succeeds.", name),
+				Data:     map[string]any{"service": name, "from": from.String()},
+			})
+		case resilience.StateClosed:
+			a.notify(notify.Event{
+				Type:     notify.EventCircuitBreakerClosed,
+				Severity: notify.SeverityInfo,
+				Subject:  name,
+				Message:  fmt.Sprintf("The circuit breaker of service %s closed; its calls are served again.", name),
+				Data:     map[string]any{"service": name},
+			})
+		}
+	})
+	health.SetStatusObserver(func(service, from, to string) {
+		switch {
+		case to == "down":
+			a.notify(notify.Event{
+				Type:     notify.EventUpstreamDown,
+				Severity: notify.SeverityCritical,
+				Subject:  service,
+				Message:  fmt.Sprintf("The health check of service %s is failing.", service),
+				Data:     map[string]any{"service": service, "from": from},
+			})
+		case to == "up" && from == "down":
+			a.notify(notify.Event{
+				Type:     notify.EventUpstreamUp,
+				Severity: notify.SeverityInfo,
+				Subject:  service,
+				Message:  fmt.Sprintf("Service %s is healthy again.", service),
+				Data:     map[string]any{"service": service},
+			})
+		}
+	})
+	tool.SetCanaryRollbackObserver(func(s tool.CanaryStatus) {
+		a.notify(notify.Event{
+			Type:     notify.EventCanaryRolledBack,
+			Severity: notify.SeverityWarning,
+			Subject:  s.StableService,
+			Message: fmt.Sprintf("The canary %s of service %s was rolled back: %d of its last %d calls failed (max error rate %g).",
+				s.CanaryService, s.StableService, s.Errors, s.Calls, s.MaxErrorRate),
+			Data: map[string]any{"service": s.StableService, "canary": s.CanaryService, "error_rate": s.ErrorRate},
+		})
+	})
+}
+
+// removeNotificationObservers removes the observers installed by
+// installNotificationObservers.
+func removeNotificationObservers() {
+	resilience.SetStateChangeObserver(nil)
+	health.SetStatusObserver(nil)
+	tool.SetCanaryRollbackObserver(nil)
+}
+
+// notify sends an event if the notifier is initialized.
+func (a *Application) notify(event notify.Event) {
+	if a.Notifier != nil {
+		a.Notifier.Notify(event)
+	}
+}
+
+// notifyReloadFailure sends a config.reload_failed event.
+func (a *Application) notifyReloadFailure(configPaths []string, err error) {
+	a.notify(notify.Event{
+		Type:     notify.EventConfigReloadFailed,
+		Severity: notify.SeverityCritical,
+		Subject:  "config",
+		Message:  fmt.Sprintf("The configuration could not be reloaded; the server keeps running with the previous one: %v", err),
+		Data:     map[string]any{"paths": configPaths, "error": err.Error()},
+	})
+}
+
+// notifyDoctorRegression sends a doctor.check_regressed event.
+func (a *Application) notifyDoctorRegression(name string, result health.CheckResult) {
+	a.notify(notify.Event{
+		Type:     notify.EventDoctorCheckRegressed,
+		Severity: notify.SeverityWarning,
+		Subject:  name,
+		Message:  fmt.Sprintf("Doctor check %s is %s: %s", name, result.Status, result.Message),
+		Data:     map[string]any{"check": name, "status": result.Status, "detail": result.Message},
+	})
+}
+
+// recordAuthFailure counts a failed authentication of a client.
+func (a *Application) recordAuthFailure(ip string) {
+	if a.Notifier != nil {
+		a.Notifier.RecordAuthFailure(ip)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+
+	config_v1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/mcpserver"
+	"github.com/mcpany/core/server/pkg/openai"
+	"github.com/mcpany/core/server/pkg/util"
+)
+
+// mcpToolSet serves the tools of the MCP server to the bridges to other agent
+// protocols.
+type mcpToolSet struct {
+	*mcpserver.Server
+}
+
+// IsServiceAllowed reports whether a profile may use the tools of a service.
+func (t mcpToolSet) IsServiceAllowed(serviceID, profileID string) bool {
+	return t.ToolManager().IsServiceAllowed(serviceID, profileID)
+}
+
+// newOpenAIBridge creates the handler of the OpenAI-compatible function
+// calling bridge, served under /openai/v1. The tool calls go through the
+// same middleware as the MCP calls.
+//
+// The chat completions endpoint is served once configureOpenAIBridge sets
+// the model server.
+//
+// Parameters:
+//   - mcpSrv (*mcpserver.Server): The MCP server executing the tool calls.
+//
+// Returns:
+//   - *openai.Handler: The handler, relative to /openai/v1.
+func newOpenAIBridge(mcpSrv *mcpserver.Server) *openai.Handler {
+	return openai.NewHandler(chainedToolSet{mcpToolSet{mcpSrv}}, nil)
+}
+
+// configureOpenAIBridge sets the model server chat completions are forwarded
+// to from the openai_bridge settings, on start and on reload. On error, the
+// bridge keeps its current model server. It must be called with configMu
+// held.
+//
+// Parameters:
+//   - ctx (context.Context): The context resolving the API key.
+//   - cfg (*config_v1.OpenAIBridgeConfig): The settings of the bridge.
+//
+// Returns:
+//   - error: An error if the API key cannot be resolved.
+func (a *Application) configureOpenAIBridge(ctx context.Context, cfg *config_v1.OpenAIBridgeConfig) error {
+	bridge := a.openAIBridge
+	if bridge == nil {
+		return nil
+	}
+	if cfg.GetUpstreamUrl() == "" {
+		bridge.SetUpstream(nil)
+		return nil
+	}
+	var apiKey string
+	if cfg.HasUpstreamApiKey() {
+		var err error
+		if apiKey, err = util.ResolveSecret(ctx, cfg.GetUpstreamApiKey()); err != nil {
+			return fmt.Errorf("failed to resolve the openai_bridge upstream_api_key: %w", err)
+		}
+	}
+	bridge.SetUpstream(&openai.Upstream{BaseURL: cfg.GetUpstreamUrl(), APIKey: apiKey})
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestOpenAIBridge_CallsGoThroughMCPMiddleware(t *testing.T) {
+	mcpSrv, auditMiddleware := newChainedTestServer(t)
+	bridge := newOpenAIBridge(mcpSrv)
+
+	body := `{"tool_calls": [{"id": "call_1", "type": "function", "function": {"name": "crm__contact", "arguments": "{}"}}]}`
+	rec := httptest.NewRecorder()
+	bridge.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tools/call", strings.NewReader(body)))
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"tool_call_id":"call_1"`)
+	assert.NotContains(t, rec.Body.String(), "alice@example.com", "the result is masked by DLP before it reaches the model")
+
+	require.NoError(t, auditMiddleware.Flush(context.Background()))
+	entries, err := auditMiddleware.Read(context.Background(), audit.Filter{ToolName: "crm.contact"})
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "the call is audited")
+}
+
+func TestConfigureOpenAIBridge(t *testing.T) {
+	model := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "` + r.Header.Get("Authorization") + `"}}]}`))
+	}))
+	defer model.Close()
+
+	mcpSrv, _ := newChainedTestServer(t)
+	app := NewApplication()
+	app.openAIBridge = newOpenAIBridge(mcpSrv)
+	complete := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		body := `{"model": "m", "messages": [], "tools": []}`
+		app.openAIBridge.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/chat/completions", strings.NewReader(body)))
+		return rec
+	}
+	assert.Equal(t, http.StatusNotImplemented, complete().Code)
+
+	require.NoError(t, app.configureOpenAIBridge(context.Background(), configv1.OpenAIBridgeConfig_builder{
+		UpstreamUrl:    proto.String(model.URL),
+		UpstreamApiKey: configv1.SecretValue_builder{PlainText: proto.String("sk-test")}.Build(),
+	}.Build()))
+	rec := complete()
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Bearer sk-test")
+
+	// A reload without the settings disables the chat completions.
+	require.NoError(t, app.configureOpenAIBridge(context.Background(), nil))
+	assert.Equal(t, http.StatusNotImplemented, complete().Code)
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mcpany/core/server/pkg/health"
+)
+
+// storagePinger is implemented by the storage backends whose connection can
+// be checked.
+type storagePinger interface {
+	Ping(ctx context.Context) error
+}
+
+// newLivenessProbe returns the probe of /livez. It has no checks: the server
+// is live as long as it answers, and a dependency being down must not get it
+// restarted.
+func (a *Application) newLivenessProbe() *health.Probe {
+	return health.NewProbe()
+}
+
+// newReadinessProbe returns the probe of /readyz, which fails until the
+// server has started, while its configuration, its storage or one of its
+// critical upstream services is unavailable, and once it drains.
+func (a *Application) newReadinessProbe() *health.Probe {
+	probe := health.NewProbe()
+	probe.AddCheck("startup", a.startupReadinessCheck)
+	probe.AddCheck("config", a.configReadinessCheck)
+	probe.AddCheck("storage", a.storageReadinessCheck)
+	probe.AddCheck("upstreams", a.upstreamsReadinessCheck)
+	probe.AddCheck("drain", a.drainReadinessCheck)
+	return probe
+}
+
+func (a *Application) startupReadinessCheck(_ context.Context) health.CheckResult {
+	select {
+	case <-a.startupCh:
+		return health.CheckResult{Status: health.StatusOK}
+	default:
+		return health.CheckResult{Status: health.StatusFail, Message: "server is starting"}
+	}
+}
+
+func (a *Application) configReadinessCheck(_ context.Context) health.CheckResult {
+	// A reload holds the lock while it reconciles the services, which can
+	// take longer than the probe timeout. The previous configuration is
+	// served meanwhile.
+	if !a.configMu.TryLock() {
+		return health.CheckResult{Status: health.StatusOK, Message: "configuration reload in progress"}
+	}
+	defer a.configMu.Unlock()
+
+	switch {
+	case a.lastReloadTime.IsZero():
+		return health.CheckResult{Status: health.StatusFail, Message: "configuration not loaded"}
+	case a.lastReloadErr != nil:
+		return health.CheckResult{
+			Status:  health.StatusDegraded,
+			Message: fmt.Sprintf("last reload failed, serving the previous configuration: %v", a.lastReloadErr),
+		}
+	default:
+		return health.CheckResult{Status: health.StatusOK}
+	}
+}
+
+func (a *Application) storageReadinessCheck(ctx context.Context) health.CheckResult {
+	if a.Storage == nil {
+		return health.CheckResult{Status: health.StatusFail, Message: "storage not initialized"}
+	}
+	pinger, ok := a.Storage.(storagePinger)
+	if !ok {
+		return health.CheckResult{Status: health.StatusOK}
+	}
+	if err := pinger.Ping(ctx); err != nil {
+		return health.CheckResult{Status: health.StatusFail, Message: err.Error()}
+	}
+	return health.CheckResult{Status: health.StatusOK}
+}
+
+// upstreamsReadinessCheck checks that the critical services are registered
+// and pass their latest health check.
+func (a *Application) upstreamsReadinessCheck(_ context.Context) health.CheckResult {
+	critical := a.readiness.Load().GetCriticalServices()
+	if len(critical) == 0 {
+		return health.CheckResult{Status: health.StatusOK, Message: "no critical services configured"}
+	}
+	if a.ServiceRegistry == nil {
+		return health.CheckResult{Status: health.StatusFail, Message: "service registry not initialized"}
+	}
+
+	services, err := a.ServiceRegistry.GetAllServices()
+	if err != nil {
+		return health.CheckResult{Status: health.StatusFail, Message: fmt.Sprintf("failed to list services: %v", err)}
+	}
+	ids := make(map[string]string, len(services))
+	for _, svc := range services {
+		ids[svc.GetName()] = svc.GetId()
+	}
+
+	var issues []string
+	for _, name := range critical {
+		id, ok := ids[name]
+		if !ok {
+			issues = append(issues, fmt.Sprintf("service %q is not registered", name))
+			continue
+		}
+		if errMsg, failed := a.ServiceRegistry.GetServiceError(id); failed {
+			issues = append(issues, fmt.Sprintf("service %q is unhealthy: %s", name, errMsg))
+		}
+	}
+	if len(issues) > 0 {
+		return health.CheckResult{Status: health.StatusFail, Message: strings.Join(issues, "; ")}
+	}
+	return health.CheckResult{Status: health.StatusOK}
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/health"
+	"github.com/mcpany/core/server/pkg/serviceregistry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+type pingingStore struct {
+	*MockStore
+	err error
+}
+
+func (s *pingingStore) Ping(context.Context) error {
+	return s.err
+}
+
+func readyz(t *testing.T, app *Application) (int, health.ProbeReport) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	app.newReadinessProbe().DetailsHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz/details", nil))
+	var report health.ProbeReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	return w.Code, report
+}
+
+func TestReadinessProbe(t *testing.T) {
+	app := NewApplication()
+	store := &pingingStore{MockStore: new(MockStore)}
+	app.Storage = store
+
+	// Not started, configuration not loaded.
+	code, report := readyz(t, app)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "server is starting", report.Checks["startup"].Message)
+	assert.Equal(t, "configuration not loaded", report.Checks["config"].Message)
+	assert.Equal(t, health.StatusOK, report.Checks["storage"].Status)
+	assert.Equal(t, health.StatusOK, report.Checks["upstreams"].Status)
+
+	close(app.startupCh)
+	app.lastReloadTime = time.Now()
+	code, report = readyz(t, app)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusOK, report.Status)
+
+	// A failed reload degrades the configuration check without failing the probe.
+	app.lastReloadErr = errors.New("invalid yaml")
+	code, report = readyz(t, app)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusDegraded, report.Checks["config"].Status)
+	assert.Contains(t, report.Checks["config"].Message, "invalid yaml")
+
+	store.err = errors.New("database is closed")
+	code, report = readyz(t, app)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "database is closed", report.Checks["storage"].Message)
+
+	// The unauthenticated probe only reports the status names.
+	w := httptest.NewRecorder()
+	app.newReadinessProbe().Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.NotContains(t, w.Body.String(), "database is closed")
+	assert.NotContains(t, w.Body.String(), "invalid yaml")
+}
+
+func TestReadinessProbe_CriticalServices(t *testing.T) {
+	app := NewApplication()
+	app.readiness.Store(configv1.ReadinessConfig_builder{CriticalServices: []string{"weather"}}.Build())
+
+	res := app.upstreamsReadinessCheck(context.Background())
+	assert.Equal(t, health.StatusFail, res.Status)
+	assert.Equal(t, "service registry not initialized", res.Message)
+
+	app.ServiceRegistry = serviceregistry.New(nil, nil, nil, nil, nil)
+	res = app.upstreamsReadinessCheck(context.Background())
+	assert.Equal(t, health.StatusFail, res.Status)
+	assert.Equal(t, `service "weather" is not registered`, res.Message)
+}
+
+func TestLivenessProbe(t *testing.T) {
+	app := NewApplication()
+	w := httptest.NewRecorder()
+	app.newLivenessProbe().Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusOK, w.Code, "the server is live even before it is ready")
+}
+
+func TestUpstreamHealthEndpoint(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	app := newVersionedApp()
+	registry := app.ServiceRegistry.(*serviceregistry.ServiceRegistry)
+	registry.SetHealthConfig(configv1.UpstreamHealthConfig_builder{UnhealthyThreshold: proto.Int32(1)}.Build())
+	_, _, _, err := registry.RegisterService(context.Background(), configv1.UpstreamServiceConfig_builder{
+		Name: proto.String("weather"),
+		HttpService: configv1.HttpUpstreamService_builder{
+			Address: proto.String(upstream.URL),
+			HealthCheck: configv1.HttpHealthCheck_builder{
+				Url:          proto.String(upstream.URL + "/health"),
+				ExpectedCode: proto.Int32(http.StatusOK),
+			}.Build(),
+		}.Build(),
+	}.Build())
+	require.NoError(t, err)
+
+	get := func() upstreamHealthReport {
+		w := httptest.NewRecorder()
+		app.handleUpstreamHealth(w, httptest.NewRequest(http.MethodGet, "/healthz/upstreams", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var report upstreamHealthReport
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		return report
+	}
+	report := get()
+	assert.Equal(t, health.StatusOK, report.Status)
+	require.Len(t, report.Upstreams, 1)
+	assert.Equal(t, "weather", report.Upstreams[0].Service)
+	assert.Equal(t, "http", report.Upstreams[0].Probe)
+	assert.Equal(t, "healthy", report.Upstreams[0].Status)
+
+	upstream.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	registry.StartHealthChecks(ctx, 10*time.Millisecond)
+	require.Eventually(t, func() bool { return get().Status == health.StatusDegraded }, 5*time.Second, 20*time.Millisecond)
+	report = get()
+	assert.Equal(t, "unhealthy", report.Upstreams[0].Status)
+	assert.NotEmpty(t, report.Upstreams[0].LastError)
+	assert.NotNil(t, report.Upstreams[0].LastSuccess)
+}
@@ -0,0 +1,152 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	armonmetrics "github.com/armon/go-metrics"
+	config_v1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/metrics"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/mcpany/core/server/pkg/util"
+)
+
+// defaultReloadDrainTimeout is how long a reload waits for the in-flight
+// calls of the services it removes or changes, unless
+// global_settings.reload_drain_timeout is set.
+const defaultReloadDrainTimeout = 30 * time.Second
+
+// serviceReloadAction is what a reload does to a service.
+type serviceReloadAction string
+
+const (
+	serviceAdded     serviceReloadAction = "added"
+	serviceUpdated   serviceReloadAction = "updated"
+	serviceRemoved   serviceReloadAction = "removed"
+	serviceUnchanged serviceReloadAction = "unchanged"
+)
+
+// serviceDrain is the outcome of draining the in-flight calls of a service.
+type serviceDrain struct {
+	// inFlight is the number of calls in flight when the drain started.
+	inFlight int
+	// abandoned is the number of calls still in flight at the deadline.
+	abandoned int
+	duration  time.Duration
+}
+
+// drainTimeout returns the drain deadline of a configuration.
+func drainTimeout(cfg *config_v1.McpAnyServerConfig) time.Duration {
+	settings := cfg.GetGlobalSettings()
+	if !settings.HasReloadDrainTimeout() {
+		return defaultReloadDrainTimeout
+	}
+	return settings.GetReloadDrainTimeout().AsDuration()
+}
+
+// drainServices waits, up to timeout, for the in-flight calls of the services
+// a reload removes or changes, so that they finish on the old upstream before
+// it is torn down. The services are drained concurrently; the others are not
+// affected.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the reload.
+//   - actions: map[string]serviceReloadAction. What the reload does to each service, by name.
+//   - timeout: time.Duration. The deadline of the drain. With 0, the calls are not waited for.
+//
+// Returns:
+//   - map[string]serviceDrain: The outcome of the drain of the services that had calls in flight.
+func (a *Application) drainServices(ctx context.Context, actions map[string]serviceReloadAction, timeout time.Duration) map[string]serviceDrain {
+	drainer, ok := a.ToolManager.(tool.ServiceDrainer)
+	if !ok {
+		return nil
+	}
+	log := logging.GetLogger()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		drains = make(map[string]serviceDrain)
+	)
+	for name, action := range actions {
+		if action != serviceRemoved && action != serviceUpdated {
+			continue
+		}
+		serviceID, err := util.SanitizeServiceName(name)
+		if err != nil {
+			continue
+		}
+		n := drainer.InFlightCalls(serviceID)
+		if n == 0 {
+			continue
+		}
+		if timeout <= 0 {
+			drains[name] = serviceDrain{inFlight: n, abandoned: n}
+			continue
+		}
+		log.Info("Draining in-flight calls", "service", name, "calls", n, "timeout", timeout)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			abandoned := drainer.DrainService(ctx, serviceID)
+			mu.Lock()
+			drains[name] = serviceDrain{inFlight: n, abandoned: abandoned, duration: time.Since(start)}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return drains
+}
+
+// reportServiceReload logs and counts what a reload did to each service.
+//
+// Parameters:
+//   - actions: map[string]serviceReloadAction. What the reload did to each service, by name.
+//   - drains: map[string]serviceDrain. The drains of the services that had calls in flight.
+func reportServiceReload(actions map[string]serviceReloadAction, drains map[string]serviceDrain) {
+	log := logging.GetLogger()
+	names := make([]string, 0, len(actions))
+	for name := range actions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	counts := make(map[serviceReloadAction]int)
+	for _, name := range names {
+		action := actions[name]
+		counts[action]++
+		metrics.IncrCounterWithLabels([]string{"config", "reload", "services"}, 1, []armonmetrics.Label{
+			{Name: "service", Value: name},
+			{Name: "action", Value: string(action)},
+		})
+		if action == serviceUnchanged {
+			continue
+		}
+
+		attrs := []any{"service", name, "action", string(action)}
+		if d, ok := drains[name]; ok {
+			attrs = append(attrs, "drained_calls", d.inFlight-d.abandoned, "abandoned_calls", d.abandoned, "drain_duration", d.duration)
+			serviceLabel := []armonmetrics.Label{{Name: "service", Value: name}}
+			metrics.AddSampleWithLabels([]string{"config", "reload", "drain_seconds"}, float32(d.duration.Seconds()), serviceLabel)
+			if d.abandoned > 0 {
+				metrics.IncrCounterWithLabels([]string{"config", "reload", "abandoned_calls"}, float32(d.abandoned), serviceLabel)
+				log.Warn("Tore down service with calls in flight", "service", name, "calls", d.abandoned)
+			}
+		}
+		log.Info("Reloaded service", attrs...)
+	}
+	log.Info("Reload summary",
+		"added", counts[serviceAdded],
+		"updated", counts[serviceUpdated],
+		"removed", counts[serviceRemoved],
+		"unchanged", counts[serviceUnchanged])
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// drainingToolManager reports fixed in-flight calls per service, and waits
+// for the deadline when draining the services listed in stuck.
+type drainingToolManager struct {
+	tool.ManagerInterface
+	inFlight map[string]int
+	stuck    map[string]bool
+
+	mu      sync.Mutex
+	drained []string
+}
+
+func (m *drainingToolManager) InFlightCalls(serviceID string) int {
+	return m.inFlight[serviceID]
+}
+
+func (m *drainingToolManager) DrainService(ctx context.Context, serviceID string) int {
+	m.mu.Lock()
+	m.drained = append(m.drained, serviceID)
+	m.mu.Unlock()
+	if m.stuck[serviceID] {
+		<-ctx.Done()
+		return m.inFlight[serviceID]
+	}
+	return 0
+}
+
+func TestDrainServices(t *testing.T) {
+	actions := map[string]serviceReloadAction{
+		"weather":  serviceUpdated,
+		"alerts":   serviceRemoved,
+		"news":     serviceUnchanged,
+		"maps":     serviceAdded,
+		"calendar": serviceRemoved,
+	}
+	newManager := func() *drainingToolManager {
+		return &drainingToolManager{
+			ManagerInterface: tool.NewManager(nil),
+			inFlight:         map[string]int{"weather": 2, "alerts": 1, "news": 3, "maps": 1},
+			stuck:            map[string]bool{"alerts": true},
+		}
+	}
+
+	t.Run("waits up to the deadline", func(t *testing.T) {
+		tm := newManager()
+		app := NewApplication()
+		app.ToolManager = tm
+
+		start := time.Now()
+		drains := app.drainServices(context.Background(), actions, 50*time.Millisecond)
+		assert.Less(t, time.Since(start), 5*time.Second)
+
+		assert.ElementsMatch(t, []string{"weather", "alerts"}, tm.drained, "only changed services with calls in flight are drained")
+		assert.Len(t, drains, 2)
+		assert.Equal(t, 2, drains["weather"].inFlight)
+		assert.Equal(t, 0, drains["weather"].abandoned)
+		assert.Equal(t, 1, drains["alerts"].abandoned)
+		assert.Greater(t, drains["alerts"].duration, 40*time.Millisecond)
+	})
+
+	t.Run("zero timeout", func(t *testing.T) {
+		tm := newManager()
+		app := NewApplication()
+		app.ToolManager = tm
+
+		drains := app.drainServices(context.Background(), actions, 0)
+		assert.Empty(t, tm.drained)
+		assert.Equal(t, 2, drains["weather"].abandoned)
+		assert.Equal(t, 1, drains["alerts"].abandoned)
+	})
+
+	t.Run("manager without drain support", func(t *testing.T) {
+		app := NewApplication()
+		app.ToolManager = struct{ tool.ManagerInterface }{tool.NewManager(nil)}
+		assert.Nil(t, app.drainServices(context.Background(), actions, time.Second))
+	})
+}
+
+func TestDrainTimeout(t *testing.T) {
+	assert.Equal(t, defaultReloadDrainTimeout, drainTimeout(configv1.McpAnyServerConfig_builder{}.Build()))
+
+	cfg := configv1.McpAnyServerConfig_builder{
+		GlobalSettings: configv1.GlobalSettings_builder{
+			ReloadDrainTimeout: durationpb.New(5 * time.Second),
+		}.Build(),
+	}.Build()
+	assert.Equal(t, 5*time.Second, drainTimeout(cfg))
+
+	cfg.GetGlobalSettings().SetReloadDrainTimeout(durationpb.New(0))
+	assert.Equal(t, time.Duration(0), drainTimeout(cfg))
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/mcpany/core/server/pkg/appconsts"
+	"github.com/mcpany/core/server/pkg/mcpserver"
+	"github.com/mcpany/core/server/pkg/restgateway"
+	"github.com/mcpany/core/server/pkg/tool"
+)
+
+// restGatewayPath is the base path of the REST gateway.
+const restGatewayPath = "/rest/v1"
+
+// chainedToolSet serves the tools of the MCP server to the other transports,
+// executing their calls through the receiving middleware of the MCP server,
+// so that they are audited, rate limited, counted against the quotas, masked
+// and guarded like MCP calls.
+type chainedToolSet struct {
+	mcpToolSet
+}
+
+// CallTool executes a tool call through the middleware of the MCP server.
+func (t chainedToolSet) CallTool(ctx context.Context, req *tool.ExecutionRequest) (any, error) {
+	return t.CallToolThroughMiddleware(ctx, req)
+}
+
+// newRESTGateway creates the REST gateway serving every tool at
+// POST /rest/v1/tools/{name}, with its OpenAPI document at
+// /rest/v1/openapi.json. The calls go through the same middleware as the MCP
+// calls.
+//
+// Parameters:
+//   - mcpSrv (*mcpserver.Server): The MCP server executing the calls.
+//
+// Returns:
+//   - http.Handler: The handler, relative to /rest/v1.
+func newRESTGateway(mcpSrv *mcpserver.Server) http.Handler {
+	return restgateway.New(chainedToolSet{mcpToolSet{mcpSrv}}, restgateway.Options{
+		Path:    restGatewayPath,
+		Version: appconsts.Version,
+	})
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	bus_pb "github.com/mcpany/core/proto/bus"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	v1 "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/mcpany/core/server/pkg/audit"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/bus"
+	"github.com/mcpany/core/server/pkg/mcpserver"
+	"github.com/mcpany/core/server/pkg/middleware"
+	"github.com/mcpany/core/server/pkg/prompt"
+	"github.com/mcpany/core/server/pkg/resource"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/mcpany/core/server/pkg/validation"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// contactTool returns a result holding an email address.
+type contactTool struct {
+	tool *v1.Tool
+}
+
+func (c *contactTool) Tool() *v1.Tool { return c.tool }
+
+func (c *contactTool) MCPTool() *mcp.Tool {
+	t, _ := tool.ConvertProtoToMCPTool(c.tool)
+	return t
+}
+
+func (c *contactTool) Execute(_ context.Context, _ *tool.ExecutionRequest) (any, error) {
+	return "write to alice@example.com", nil
+}
+
+func (c *contactTool) GetCacheConfig() *configv1.CacheConfig { return nil }
+
+// newChainedTestServer creates an MCP server with a contact tool behind the
+// audit and DLP middleware of the MCP calls.
+func newChainedTestServer(t *testing.T) (*mcpserver.Server, *middleware.AuditMiddleware) {
+	t.Helper()
+	messageBus := bus_pb.MessageBus_builder{}.Build()
+	messageBus.SetInMemory(bus_pb.InMemoryBus_builder{}.Build())
+	busProvider, err := bus.NewProvider(messageBus)
+	require.NoError(t, err)
+	toolManager := tool.NewManager(busProvider)
+	authManager := auth.NewManager()
+	mcpSrv, err := mcpserver.NewServer(context.Background(), toolManager, prompt.NewManager(), resource.NewManager(), authManager, nil, nil, busProvider, false)
+	require.NoError(t, err)
+
+	auditDir := t.TempDir()
+	validation.SetAllowedPaths([]string{auditDir})
+	t.Cleanup(func() { validation.SetAllowedPaths(nil) })
+
+	toolManager.AddServiceInfo("crm", &tool.ServiceInfo{Name: "crm", Config: configv1.UpstreamServiceConfig_builder{}.Build()})
+	require.NoError(t, toolManager.AddTool(&contactTool{tool: v1.Tool_builder{
+		Name:      proto.String("contact"),
+		ServiceId: proto.String("crm"),
+		Annotations: v1.ToolAnnotations_builder{
+			InputSchema: &structpb.Struct{Fields: map[string]*structpb.Value{
+				"type": structpb.NewStringValue("object"),
+			}},
+		}.Build(),
+	}.Build()}))
+
+	standard, err := middleware.InitStandardMiddlewares(
+		authManager,
+		toolManager,
+		configv1.AuditConfig_builder{
+			Enabled:     proto.Bool(true),
+			StorageType: configv1.AuditConfig_STORAGE_TYPE_SQLITE.Enum(),
+			OutputPath:  proto.String(filepath.Join(auditDir, "audit.db")),
+		}.Build(),
+		nil,
+		nil,
+		configv1.DLPConfig_builder{Enabled: proto.Bool(true)}.Build(),
+		nil,
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = standard.Cleanup() })
+	for _, m := range middleware.GetMCPMiddlewares([]*configv1.Middleware{
+		configv1.Middleware_builder{Name: proto.String("audit"), Priority: proto.Int32(40)}.Build(),
+		configv1.Middleware_builder{Name: proto.String("dlp"), Priority: proto.Int32(42)}.Build(),
+	}) {
+		mcpSrv.AddReceivingMiddleware(m)
+	}
+	return mcpSrv, standard.Audit
+}
+
+func TestRESTGateway_CallsGoThroughMCPMiddleware(t *testing.T) {
+	mcpSrv, auditMiddleware := newChainedTestServer(t)
+	gateway := newRESTGateway(mcpSrv)
+
+	req := httptest.NewRequest(http.MethodPost, "/tools/crm.contact", strings.NewReader(`{}`))
+	req = req.WithContext(auth.ContextWithUser(req.Context(), "rest-user"))
+	rec := httptest.NewRecorder()
+	gateway.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.NotContains(t, rec.Body.String(), "alice@example.com", "the result is masked by DLP")
+
+	require.NoError(t, auditMiddleware.Flush(context.Background()))
+	entries, err := auditMiddleware.Read(context.Background(), audit.Filter{ToolName: "crm.contact"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "the call is audited")
+	assert.Equal(t, "rest-user", entries[0].UserID)
+}
+
+func TestRESTGateway_RateLimited(t *testing.T) {
+	mcpSrv, _ := newChainedTestServer(t)
+	mcpSrv.ToolManager().AddServiceInfo("crm", &tool.ServiceInfo{Name: "crm", Config: configv1.UpstreamServiceConfig_builder{
+		RateLimit: configv1.RateLimitConfig_builder{
+			IsEnabled:         true,
+			RequestsPerSecond: 0.001,
+			Burst:             1,
+		}.Build(),
+	}.Build()})
+	for _, m := range middleware.GetMCPMiddlewares([]*configv1.Middleware{
+		configv1.Middleware_builder{Name: proto.String("ratelimit"), Priority: proto.Int32(30)}.Build(),
+	}) {
+		mcpSrv.AddReceivingMiddleware(m)
+	}
+	gateway := newRESTGateway(mcpSrv)
+
+	call := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		gateway.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tools/crm.contact", strings.NewReader(`{}`)))
+		return rec
+	}
+	rec := call()
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = call()
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code, "the rate limits of the MCP calls apply")
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+}
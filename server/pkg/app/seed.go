@@ -7,19 +7,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 
 	"strings"
 	"time"
 
-	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/fixtures"
 	"github.com/mcpany/core/server/pkg/logging"
-	"google.golang.org/protobuf/encoding/protojson"
 )
 
-// SeedRequest defines the payload for seeding the database.
-// We use json.RawMessage to manually unmarshal using protojson, ensuring correct Protobuf handling.
+// SeedRequest is the JSON form of a seed payload.
+//
+// Deprecated: The payload is a fixture document (see package fixtures), which
+// also accepts audit entries and skills.
 type SeedRequest struct {
 	ServicesRaw    []json.RawMessage `json:"upstream_services"`
 	CredentialsRaw []json.RawMessage `json:"credentials"`
@@ -29,10 +31,11 @@ type SeedRequest struct {
 	TemplatesRaw   []json.RawMessage `json:"service_templates"`
 }
 
-// handleDebugSeed creates a handler to seed the database with data.
-// It clears existing data before inserting new data.
-// handleDebugSeed creates a handler to seed the database with data.
-// It clears existing data before inserting new data.
+// maxSeedBodySize bounds the fixture document accepted by the seed endpoint.
+const maxSeedBodySize = 10 << 20
+
+// handleDebugSeed creates a handler to seed the database with a fixture
+// document in YAML or JSON. It clears existing data before applying the fixture.
 func (a *Application) handleDebugSeed() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
@@ -40,9 +43,14 @@ func (a *Application) handleDebugSeed() http.HandlerFunc {
 			return
 		}
 
-		var req SeedRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxSeedBodySize))
+		if err != nil {
+			http.Error(w, "Failed to read body", http.StatusBadRequest)
+			return
+		}
+		fixture, err := fixtures.Parse(body)
+		if err != nil {
+			http.Error(w, "Invalid seed data: "+err.Error(), http.StatusBadRequest)
 			return
 		}
 
@@ -55,13 +63,18 @@ func (a *Application) handleDebugSeed() http.HandlerFunc {
 			return
 		}
 
-		if err := a.seedData(ctx, req); err != nil {
+		target := fixtures.Target{Storage: a.Storage, Skills: a.SkillManager}
+		if a.standardMiddlewares != nil && a.standardMiddlewares.Audit != nil {
+			target.Audit = a.standardMiddlewares.Audit
+		}
+		// Applying a fixture is idempotent, so it is safe to retry as a whole.
+		err = withRetry(ctx, log, func() error {
+			_, err := fixtures.Apply(ctx, target, []*fixtures.Fixture{fixture})
+			return err
+		})
+		if err != nil {
 			log.Error("Failed to seed data", "error", err)
-			if err.Error() == "invalid json" {
-				http.Error(w, "Invalid JSON in seed data", http.StatusBadRequest)
-			} else {
-				http.Error(w, "Failed to seed data: "+err.Error(), http.StatusInternalServerError)
-			}
+			http.Error(w, "Failed to seed data: "+err.Error(), http.StatusInternalServerError)
 			return
 		}
 
@@ -191,79 +204,3 @@ func (a *Application) clearData(ctx context.Context, log *slog.Logger) error {
 
 	return nil
 }
-
-func (a *Application) seedData(ctx context.Context, req SeedRequest) error {
-	for _, raw := range req.ServicesRaw {
-		s := configv1.UpstreamServiceConfig_builder{}.Build()
-		if err := protojson.Unmarshal(raw, s); err != nil {
-			return fmt.Errorf("invalid json")
-		}
-		err := withRetry(ctx, logging.GetLogger(), func() error {
-			return a.Storage.SaveService(ctx, s)
-		})
-		if err != nil {
-			return fmt.Errorf("failed to save service %s: %w", s.GetName(), err)
-		}
-	}
-	for _, raw := range req.CredentialsRaw {
-		c := configv1.Credential_builder{}.Build()
-		if err := protojson.Unmarshal(raw, c); err != nil {
-			return fmt.Errorf("invalid json")
-		}
-		err := withRetry(ctx, logging.GetLogger(), func() error {
-			return a.Storage.SaveCredential(ctx, c)
-		})
-		if err != nil {
-			return fmt.Errorf("failed to save credential %s: %w", c.GetId(), err)
-		}
-	}
-	for _, raw := range req.SecretsRaw {
-		s := configv1.Secret_builder{}.Build()
-		if err := protojson.Unmarshal(raw, s); err != nil {
-			return fmt.Errorf("invalid json")
-		}
-		err := withRetry(ctx, logging.GetLogger(), func() error {
-			return a.Storage.SaveSecret(ctx, s)
-		})
-		if err != nil {
-			return fmt.Errorf("failed to save secret %s: %w", s.GetId(), err)
-		}
-	}
-	for _, raw := range req.ProfilesRaw {
-		p := configv1.ProfileDefinition_builder{}.Build()
-		if err := protojson.Unmarshal(raw, p); err != nil {
-			return fmt.Errorf("invalid json")
-		}
-		err := withRetry(ctx, logging.GetLogger(), func() error {
-			return a.Storage.SaveProfile(ctx, p)
-		})
-		if err != nil {
-			return fmt.Errorf("failed to save profile %s: %w", p.GetName(), err)
-		}
-	}
-	for _, raw := range req.UsersRaw {
-		u := configv1.User_builder{}.Build()
-		if err := protojson.Unmarshal(raw, u); err != nil {
-			return fmt.Errorf("invalid json")
-		}
-		err := withRetry(ctx, logging.GetLogger(), func() error {
-			return a.Storage.CreateUser(ctx, u)
-		})
-		if err != nil {
-			return fmt.Errorf("failed to create user %s: %w", u.GetId(), err)
-		}
-	}
-	for _, raw := range req.TemplatesRaw {
-		t := configv1.ServiceTemplate_builder{}.Build()
-		if err := protojson.Unmarshal(raw, t); err != nil {
-			return fmt.Errorf("invalid json")
-		}
-		err := withRetry(ctx, logging.GetLogger(), func() error {
-			return a.Storage.SaveServiceTemplate(ctx, t)
-		})
-		if err != nil {
-			return fmt.Errorf("failed to save service template %s: %w", t.GetId(), err)
-		}
-	}
-	return nil
-}
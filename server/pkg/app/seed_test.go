@@ -68,3 +68,36 @@ func TestHandleDebugSeed(t *testing.T) {
 	require.NotNil(t, svc)
 	require.Equal(t, "new-service", svc.GetName())
 }
+
+func TestHandleDebugSeed_Fixture(t *testing.T) {
+	store := memory.NewStore()
+	app := &Application{Storage: store, configPaths: []string{}}
+
+	body := `
+upstream_services:
+  - name: "weather"
+    http_service:
+      address: "https://wttr.in"
+users:
+  - id: "alice"
+    roles: ["admin"]
+`
+	w := httptest.NewRecorder()
+	app.handleDebugSeed().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/debug/seed", bytes.NewBufferString(body)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	svc, err := store.GetService(context.Background(), "weather")
+	require.NoError(t, err)
+	require.NotNil(t, svc)
+	user, err := store.GetUser(context.Background(), "alice")
+	require.NoError(t, err)
+	require.NotNil(t, user)
+
+	// An invalid fixture is rejected before anything is cleared.
+	w = httptest.NewRecorder()
+	app.handleDebugSeed().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/debug/seed", bytes.NewBufferString(`users: [{roles: [admin]}]`)))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	svc, err = store.GetService(context.Background(), "weather")
+	require.NoError(t, err)
+	require.NotNil(t, svc)
+}
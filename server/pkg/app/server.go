@@ -9,8 +9,8 @@ import (
 	"context"
 	"crypto/subtle"
 	"crypto/tls"
-	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html"
 	"io"
@@ -33,20 +33,35 @@ import (
 	"github.com/mcpany/core/server/pkg/auth"
 	"github.com/mcpany/core/server/pkg/bus"
 	"github.com/mcpany/core/server/pkg/catalog"
+	"github.com/mcpany/core/server/pkg/cloudsecrets"
+	"github.com/mcpany/core/server/pkg/cluster"
 	"github.com/mcpany/core/server/pkg/config"
+	"github.com/mcpany/core/server/pkg/dashboard"
 	"github.com/mcpany/core/server/pkg/discovery"
+	"github.com/mcpany/core/server/pkg/enrichment"
+	"github.com/mcpany/core/server/pkg/expiry"
+	"github.com/mcpany/core/server/pkg/federation"
 	"github.com/mcpany/core/server/pkg/gc"
 	"github.com/mcpany/core/server/pkg/health"
+	"github.com/mcpany/core/server/pkg/lifecycle"
 	"github.com/mcpany/core/server/pkg/logging"
 	"github.com/mcpany/core/server/pkg/mcpserver"
+	"github.com/mcpany/core/server/pkg/openai"
 	"github.com/mcpany/core/server/pkg/metrics"
 	"github.com/mcpany/core/server/pkg/middleware"
+	"github.com/mcpany/core/server/pkg/notify"
+	"github.com/mcpany/core/server/pkg/plugin"
 	"github.com/mcpany/core/server/pkg/pool"
 	"github.com/mcpany/core/server/pkg/profile"
 	"github.com/mcpany/core/server/pkg/prompt"
+	"github.com/mcpany/core/server/pkg/resilience"
 	"github.com/mcpany/core/server/pkg/resource"
+	"github.com/mcpany/core/server/pkg/secretusage"
 	"github.com/mcpany/core/server/pkg/serviceregistry"
+	"github.com/mcpany/core/server/pkg/sharedstate"
 	"github.com/mcpany/core/server/pkg/skill"
+	"github.com/mcpany/core/server/pkg/slo"
+	"github.com/mcpany/core/server/pkg/slowcall"
 	"github.com/mcpany/core/server/pkg/storage"
 	"github.com/mcpany/core/server/pkg/storage/postgres"
 	"github.com/mcpany/core/server/pkg/storage/sqlite"
@@ -56,6 +71,7 @@ import (
 	"github.com/mcpany/core/server/pkg/upstream/factory"
 	"github.com/mcpany/core/server/pkg/util"
 	"github.com/mcpany/core/server/pkg/validation"
+	"github.com/mcpany/core/server/pkg/vault"
 	"github.com/mcpany/core/server/pkg/webhooks"
 	"github.com/mcpany/core/server/pkg/worker"
 	"github.com/pmezard/go-difflib/difflib"
@@ -135,6 +151,7 @@ func (a *Application) uploadFile(w http.ResponseWriter, r *http.Request) {
 //   - Ctx: context.Context. The context for the application.
 //   - Fs: afero.Fs. The filesystem interface.
 //   - Stdio: bool. Whether to run in stdio mode (for CLI/one-off usage).
+//   - StdioWithNetwork: bool. Whether to also start the HTTP and gRPC listeners in stdio mode.
 //   - JSONRPCPort: string. The port for the JSON-RPC/HTTP server.
 //   - GRPCPort: string. The port for the gRPC registration server.
 //   - ConfigPaths: []string. Paths to configuration files.
@@ -143,20 +160,31 @@ func (a *Application) uploadFile(w http.ResponseWriter, r *http.Request) {
 //   - TLSCert: string. Path to the TLS certificate file.
 //   - TLSKey: string. Path to the TLS private key file.
 //   - TLSClientCA: string. Path to the TLS client CA certificate file (for mTLS).
+//   - TLSClientAuth: string. Client certificate policy with TLSClientCA, "require" (default) or "optional".
+//   - ACMEDomains: []string. Domains to obtain certificates for from an ACME CA instead of TLSCert/TLSKey.
+//   - ACMEEmail: string. Contact email of the ACME account.
+//   - ACMECacheDir: string. Directory storing ACME account keys and certificates.
+//   - ACMEDirectoryURL: string. ACME directory URL; Let's Encrypt by default.
 //   - DBPath: string. Path to the SQLite database file.
 type RunOptions struct {
-	Ctx             context.Context
-	Fs              afero.Fs
-	Stdio           bool
-	JSONRPCPort     string
-	GRPCPort        string
-	ConfigPaths     []string
-	APIKey          string
-	ShutdownTimeout time.Duration
-	TLSCert         string
-	TLSKey          string
-	TLSClientCA     string
-	DBPath          string
+	Ctx              context.Context
+	Fs               afero.Fs
+	Stdio            bool
+	StdioWithNetwork bool
+	JSONRPCPort      string
+	GRPCPort         string
+	ConfigPaths      []string
+	APIKey           string
+	ShutdownTimeout  time.Duration
+	TLSCert          string
+	TLSKey           string
+	TLSClientCA      string
+	TLSClientAuth    string
+	ACMEDomains      []string
+	ACMEEmail        string
+	ACMECacheDir     string
+	ACMEDirectoryURL string
+	DBPath           string
 }
 
 // Runner defines the interface for running the application.
@@ -203,10 +231,17 @@ type Runner interface {
 //   - TemplateManager: *TemplateManager. Manages templates.
 //   - SkillManager: *skill.Manager. Manages agent skills.
 //   - AlertsManager: *alerts.Manager. Manages system alerts.
+//   - SLOTracker: *slo.Tracker. Tracks the service level objectives of upstream services.
+//   - SecretUsage: *secretusage.Recorder. Records which services read the stored secrets.
+//   - SlowCalls: *slowcall.Recorder. Keeps the report of the slow tool calls.
+//   - ResponseCache: *middleware.CachingMiddleware. Caches the results of the tools with caching enabled.
+//   - Notifier: *notify.Notifier. Sends notifications of operational events.
+//   - ExpiryChecker: *expiry.Checker. Warns about expiring credentials and upstream TLS certificates.
 //   - DiscoveryManager: *discovery.Manager. Manages auto-discovery of services.
 //   - SettingsManager: *GlobalSettingsManager. Manages dynamic global settings.
 //   - ProfileManager: *profile.Manager. Manages user profiles.
 //   - AuthManager: *auth.Manager. Manages authentication and authorization.
+//   - Lifecycle: *lifecycle.Registry. Start, reload and shutdown hooks of extensions.
 //   - RegistrationRetryDelay: time.Duration. Delay between service registration retries.
 //   - MetricsGatherer: prometheus.Gatherer. Interface for gathering metrics.
 //   - BoundHTTPPort: atomic.Int32. The actual bound HTTP port.
@@ -233,6 +268,48 @@ type Application struct {
 	// AlertsManager manages system alerts
 	AlertsManager *alerts.Manager
 
+	// SLOTracker tracks the service level objectives of upstream services.
+	// It is created in Run from AlertsManager and MetricsGatherer if nil.
+	SLOTracker *slo.Tracker
+
+	// SecretUsage records which services read the stored secrets.
+	// It is created in Run from the storage if nil.
+	SecretUsage *secretusage.Recorder
+
+	// SlowCalls keeps the report of the tool calls slower than their
+	// threshold. It is created in Run.
+	SlowCalls *slowcall.Recorder
+
+	// ResponseCache caches the results of the tools with caching enabled.
+	// It is created in Run.
+	ResponseCache *middleware.CachingMiddleware
+
+	// SharedState is the state shared with the other replicas through
+	// Redis, or nil if shared_state is not configured. It is created in Run.
+	SharedState *sharedstate.Store
+
+	// Membership registers this replica with the others and elects the one
+	// that runs the singleton duties, or is nil if shared_state is not
+	// configured. It is created in Run.
+	Membership *sharedstate.Membership
+
+	// Notifier sends notifications of operational events, such as opened
+	// circuit breakers and failed reloads. It is created in Run.
+	Notifier *notify.Notifier
+
+	// Resilience applies the circuit breakers, retries and timeouts of the
+	// services to their tool calls. It is created in Run.
+	Resilience *middleware.ResilienceMiddleware
+
+	// kubernetesStatus writes the state of the services defined by
+	// UpstreamService resources to their status, or is nil without a
+	// k8s:// UpstreamService config path. It is created in Run.
+	kubernetesStatus *config.KubernetesStatusWriter
+
+	// ExpiryChecker warns about credentials and upstream TLS certificates
+	// that expire or are due for rotation soon. It is created in Run if nil.
+	ExpiryChecker *expiry.Checker
+
 	// WebhooksManager manages outbound webhooks
 	WebhooksManager *webhooks.Manager
 
@@ -251,10 +328,20 @@ type Application struct {
 	// Auth Manager (stored here for access in runServerMode, though it is also passed to serviceregistry)
 	// We need to keep a reference to update it on reload.
 	AuthManager *auth.Manager
+	// Lifecycle holds the start, reload and shutdown hooks of extensions.
+	// The hooks of lifecycle.Default() are run as well.
+	Lifecycle *lifecycle.Registry
+	// hooks is the registry of the current run, including the hooks of the
+	// built-in subsystems. It is protected by configMu.
+	hooks *lifecycle.Registry
 	// Middlewares that need manual updates
 	ipMiddleware   *middleware.IPAllowlistMiddleware
+	networkAccess  *middleware.NetworkAccess
 	corsMiddleware *middleware.HTTPCORSMiddleware
 	csrfMiddleware *middleware.CSRFMiddleware
+	// openAIBridge is the OpenAI-compatible bridge, whose model server is
+	// updated on reload.
+	openAIBridge *openai.Handler
 
 	busProvider *bus.Provider
 
@@ -280,6 +367,18 @@ type Application struct {
 	// It is protected by configMu.
 	configDiff string
 
+	// sourceHash is the hash of the configuration last loaded from the
+	// sources, which keeps a rollback in effect while it is unchanged.
+	// It is protected by configMu.
+	sourceHash string
+
+	// readiness is the configuration of the /readyz checks.
+	readiness atomic.Pointer[config_v1.ReadinessConfig]
+
+	// activeConfig is the last configuration loaded successfully, served
+	// by /debug/config.
+	activeConfig atomic.Pointer[config_v1.McpAnyServerConfig]
+
 	// BoundHTTPPort stores the actual port the HTTP server is listening on.
 	BoundHTTPPort atomic.Int32
 	// BoundGRPCPort stores the actual port the gRPC server is listening on.
@@ -287,8 +386,13 @@ type Application struct {
 
 	// startTime is the time the application started.
 	startTime time.Time
+	// startupDiscovery is the summary of the discovery of the upstream
+	// services at startup, once it is done.
+	startupDiscovery atomic.Pointer[StartupDiscoverySummary]
 	// activeConnections tracks the number of active HTTP connections.
 	activeConnections int32
+	// drain tracks the drain of the server ahead of its termination.
+	drain drainState
 
 	// RegistrationRetryDelay allows configuring the retry delay for service registration.
 	// If 0, it defaults to 5 seconds (in the worker).
@@ -309,6 +413,13 @@ type Application struct {
 	// seededTraceSubs for broadcasting seeded traces to active websockets
 	seededTraceSubsMu sync.RWMutex
 	seededTraceSubs   map[chan *Trace]struct{}
+
+	// inProcessTools are the tools implemented by the program embedding the
+	// application, registered once the MCP server is created.
+	inProcessTools []tool.Tool
+	// inProcessServices are the configs of the services of the in-process
+	// tools, by service name.
+	inProcessServices map[string]*config_v1.UpstreamServiceConfig
 }
 
 type statsCacheEntry struct {
@@ -331,6 +442,7 @@ func NewApplication() *Application {
 		AlertsManager:    alerts.NewManager(),
 		WebhooksManager:  webhooks.NewManager(),
 		CatalogManager:   catalog.NewManager(afero.NewOsFs(), "marketplace/catalog"), // Default path, can be overridden
+		Lifecycle:        lifecycle.NewRegistry(),
 
 		ResourceManager: resource.NewManager(),
 		UpstreamFactory: factory.NewUpstreamServiceFactory(pool.NewManager(), nil),
@@ -374,9 +486,28 @@ func (a *Application) Run(opts RunOptions) error {
 
 	log.Info("Starting MCP Any Service...")
 
+	// Subsystems register their shutdown with the run's hooks as they start.
+	// The hooks run when Run returns, whatever the exit path.
+	hooks := lifecycle.Merge(lifecycle.Default(), a.Lifecycle)
+	a.configMu.Lock()
+	a.hooks = hooks
+	a.configMu.Unlock()
+	a.setDrainTimeout(opts.ShutdownTimeout)
+	defer func() {
+		shutdownCtx := context.Background()
+		if opts.ShutdownTimeout > 0 {
+			var cancel context.CancelFunc
+			shutdownCtx, cancel = context.WithTimeout(shutdownCtx, opts.ShutdownTimeout)
+			defer cancel()
+		}
+		_ = hooks.Shutdown(shutdownCtx)
+	}()
+
 	// Load initial services from config files and Storage
 	var storageStore config.Store
 	var storageCloser func() error
+	// sharedDB is the database the replicas share, if any.
+	var sharedDB *postgres.DB
 
 	if a.Storage != nil {
 		storageStore = a.Storage
@@ -409,15 +540,16 @@ func (a *Application) Run(opts RunOptions) error {
 			}
 			storageCloser = func() error { return pgDB.Close() }
 			storageStore = postgres.NewStore(pgDB)
+			sharedDB = pgDB
 		default:
 			return fmt.Errorf("unsupported db driver: %s", dbDriver)
 		}
 	}
-	defer func() {
-		if storageCloser != nil {
-			_ = storageCloser()
-		}
-	}()
+	if storageCloser != nil {
+		hooks.OnShutdown("storage", func(context.Context) error {
+			return storageCloser()
+		}, lifecycle.WithOrder(lifecycle.OrderStorage))
+	}
 
 	var stores []config.Store
 
@@ -459,13 +591,30 @@ func (a *Application) Run(opts RunOptions) error {
 	if cfg == nil {
 		cfg = config_v1.McpAnyServerConfig_builder{}.Build()
 	}
+	a.sourceHash = configHash(cfg)
+	var rollbackOf int64
+	if s, ok := storageStore.(storage.Storage); ok {
+		cfg, rollbackOf = a.restorePinnedRollback(opts.Ctx, s, cfg)
+	}
 	a.lastReloadTime = time.Now()
+	a.readiness.Store(cfg.GetGlobalSettings().GetReadiness())
+	a.activeConfig.Store(cfg)
 
 	// Populate initial good config for diffing
 	if len(opts.ConfigPaths) > 0 {
 		a.lastGoodConfig, _ = a.readConfigFiles(fs, opts.ConfigPaths)
 	}
 
+	// Report the state of the services defined by UpstreamService resources
+	a.kubernetesStatus, err = config.NewKubernetesStatusWriter(opts.ConfigPaths, a.kubernetesServiceStatus)
+	if err != nil {
+		return fmt.Errorf("failed to configure Kubernetes status: %w", err)
+	}
+	if a.kubernetesStatus != nil {
+		hooks.OnStart("kubernetes status", a.kubernetesStatus.Start, lifecycle.WithOrder(lifecycle.OrderWorkers))
+		hooks.OnShutdown("kubernetes status", a.kubernetesStatus.Stop, lifecycle.WithOrder(lifecycle.OrderWorkers))
+	}
+
 	// Initialize Telemetry with loaded config
 	shutdownTelemetry, err := telemetry.InitTelemetry(opts.Ctx, appconsts.Name, appconsts.Version, cfg.GetGlobalSettings().GetTelemetry(), os.Stderr)
 	if err != nil {
@@ -473,12 +622,40 @@ func (a *Application) Run(opts RunOptions) error {
 		// but typically we might want to know.
 		log.Error("Failed to initialize telemetry", "error", err)
 	} else {
-		defer func() {
-			if err := shutdownTelemetry(context.Background()); err != nil {
-				log.Error("Failed to shutdown telemetry", "error", err)
-			}
-		}()
+		hooks.OnShutdown("telemetry", shutdownTelemetry, lifecycle.WithOrder(lifecycle.OrderTelemetry))
+	}
+
+	// Ship the logs to the configured sinks
+	for _, sinkConfig := range cfg.GetGlobalSettings().GetLogSinks() {
+		shipper, err := logging.NewShipper(logging.GlobalBroadcaster, sinkConfig)
+		if err != nil {
+			return fmt.Errorf("failed to initialize log sink: %w", err)
+		}
+		hooks.OnShutdown("log sink "+shipper.Name(), shipper.Close, lifecycle.WithOrder(lifecycle.OrderTelemetry))
+		a.onDrainFlush("log sink "+shipper.Name(), shipper.Flush)
+		log.Info("Shipping logs to sink", "sink", shipper.Name())
+	}
+
+	// Sign the webhooks the server calls on its own
+	if err := webhooks.ConfigureOutbound(cfg.GetGlobalSettings().GetOutboundWebhooks()); err != nil {
+		return fmt.Errorf("failed to configure outbound webhooks: %w", err)
 	}
+	hooks.OnConfigReload("outbound webhooks", func(_ context.Context, cfg *config_v1.McpAnyServerConfig) error {
+		return webhooks.ConfigureOutbound(cfg.GetGlobalSettings().GetOutboundWebhooks())
+	})
+
+	// Notify the configured sinks of operational events
+	a.Notifier = notify.NewNotifier(cfg.GetGlobalSettings().GetNotifications())
+	a.installNotificationObservers()
+	hooks.OnStart("notifications", a.Notifier.Start, lifecycle.WithOrder(lifecycle.OrderTelemetry))
+	hooks.OnConfigReload("notifications", func(_ context.Context, cfg *config_v1.McpAnyServerConfig) error {
+		a.Notifier.SetConfig(cfg.GetGlobalSettings().GetNotifications())
+		return nil
+	})
+	hooks.OnShutdown("notifications", func(ctx context.Context) error {
+		removeNotificationObservers()
+		return a.Notifier.Stop(ctx)
+	}, lifecycle.WithOrder(lifecycle.OrderTelemetry))
 
 	// Initialize Settings Manager
 	a.SettingsManager = NewGlobalSettingsManager(
@@ -489,6 +666,49 @@ func (a *Application) Run(opts RunOptions) error {
 	)
 	a.SettingsManager.Update(cfg.GetGlobalSettings(), opts.APIKey)
 
+	// Resolve the vault: secret references before any service reads them
+	if vaultCfg := cfg.GetGlobalSettings().GetVault(); vaultCfg != nil {
+		vaultResolver, err := vault.NewResolver(vaultCfg)
+		if err != nil {
+			return fmt.Errorf("failed to configure vault: %w", err)
+		}
+		util.SetSecretRefResolver(vault.Scheme, vaultResolver.Resolve)
+		hooks.OnStart("vault", vaultResolver.Start, lifecycle.WithOrder(lifecycle.OrderWorkers))
+		hooks.OnConfigReload("vault", func(_ context.Context, cfg *config_v1.McpAnyServerConfig) error {
+			if !proto.Equal(cfg.GetGlobalSettings().GetVault(), vaultCfg) {
+				log.Warn("Vault settings changed; restart the server to apply them")
+			}
+			return nil
+		})
+		hooks.OnShutdown("vault", func(ctx context.Context) error {
+			util.SetSecretRefResolver(vault.Scheme, nil)
+			return vaultResolver.Stop(ctx)
+		}, lifecycle.WithOrder(lifecycle.OrderWorkers))
+	}
+
+	// Resolve the cloud secret manager references
+	if backendsCfg := cfg.GetGlobalSettings().GetSecretBackends(); backendsCfg != nil {
+		backends, err := cloudsecrets.New(opts.Ctx, backendsCfg)
+		if err != nil {
+			return fmt.Errorf("failed to configure secret backends: %w", err)
+		}
+		for _, backend := range backends {
+			name := "secret backend " + backend.Scheme()
+			util.SetSecretRefResolver(backend.Scheme(), backend.Resolve)
+			hooks.OnStart(name, backend.Start, lifecycle.WithOrder(lifecycle.OrderWorkers))
+			hooks.OnShutdown(name, func(ctx context.Context) error {
+				util.SetSecretRefResolver(backend.Scheme(), nil)
+				return backend.Stop(ctx)
+			}, lifecycle.WithOrder(lifecycle.OrderWorkers))
+		}
+		hooks.OnConfigReload("secret backends", func(_ context.Context, cfg *config_v1.McpAnyServerConfig) error {
+			if !proto.Equal(cfg.GetGlobalSettings().GetSecretBackends(), backendsCfg) {
+				log.Warn("Secret backend settings changed; restart the server to apply them")
+			}
+			return nil
+		})
+	}
+
 	busConfig := cfg.GetGlobalSettings().GetMessageBus()
 	busProvider, err := bus.NewProvider(busConfig)
 	if err != nil {
@@ -502,10 +722,49 @@ func (a *Application) Run(opts RunOptions) error {
 	}
 	upstreamFactory := factory.NewUpstreamServiceFactory(poolManager, cfg.GetGlobalSettings())
 	a.ToolManager = tool.NewManager(busProvider)
+	// Label enrichers and latency buckets must be configured before the tool
+	// metrics register their label names and histograms.
+	if err := enrichment.Configure(cfg.GetGlobalSettings().GetLabelEnrichers()); err != nil {
+		return fmt.Errorf("failed to configure label enrichers: %w", err)
+	}
+	hooks.OnShutdown("label enrichers", func(context.Context) error {
+		return enrichment.Close()
+	}, lifecycle.WithOrder(lifecycle.OrderMiddlewares))
+	if err := metrics.SetLatencyBuckets(cfg.GetGlobalSettings().GetTelemetry().GetLatencyBuckets()); err != nil {
+		return fmt.Errorf("failed to configure latency buckets: %w", err)
+	}
+	configureLogRedaction(cfg.GetGlobalSettings().GetDlp())
+	// Add Slow Call Middleware first, so that the time spent in the other middlewares counts.
+	a.SlowCalls = slowcall.NewRecorder(cfg.GetGlobalSettings().GetSlowCalls())
+	a.ToolManager.AddMiddleware(middleware.NewSlowCallMiddleware(a.SlowCalls))
 	// Add Tool Metrics Middleware
 	a.ToolManager.AddMiddleware(middleware.NewToolMetricsMiddleware(tokenizer.NewSimpleTokenizer()))
+	// Add Capture Middleware before resilience, so that a retried call is captured once.
+	if captureCfg := cfg.GetGlobalSettings().GetCapture(); captureCfg.GetEnabled() {
+		captureMiddleware, err := middleware.NewCaptureMiddleware(captureCfg, cfg.GetGlobalSettings().GetDlp())
+		if err != nil {
+			return fmt.Errorf("failed to initialize tool call capture: %w", err)
+		}
+		a.ToolManager.AddMiddleware(captureMiddleware)
+	}
+	// Add the tool middlewares compiled in or loaded from plugins, outside
+	// resilience so that a retried call runs them once.
+	plugins, err := plugin.NewChain(opts.Ctx, cfg.GetGlobalSettings().GetMiddlewares())
+	if err != nil {
+		return fmt.Errorf("failed to load tool middlewares: %w", err)
+	}
+	hooks.OnShutdown("middleware plugins", func(context.Context) error {
+		return plugins.Close()
+	}, lifecycle.WithOrder(lifecycle.OrderMiddlewares))
+	if plugins.Len() > 0 {
+		a.ToolManager.AddMiddleware(plugins)
+	}
+	hooks.OnShutdown("webhook connections", func(context.Context) error {
+		return tool.CloseReviewConns()
+	}, lifecycle.WithOrder(lifecycle.OrderMiddlewares))
 	// Add Resilience Middleware
-	a.ToolManager.AddMiddleware(middleware.NewResilienceMiddleware(a.ToolManager))
+	a.Resilience = middleware.NewResilienceMiddleware(a.ToolManager)
+	a.ToolManager.AddMiddleware(a.Resilience)
 
 	a.PromptManager = prompt.NewManager()
 	a.TemplateManager = NewTemplateManager("data") // Use "data" directory for now
@@ -541,10 +800,36 @@ func (a *Application) Run(opts RunOptions) error {
 		log.Warn("storageStore does not implement storage.Storage, interactive OAuth will be disabled")
 	}
 
+	if retention := cfg.GetGlobalSettings().GetLogRetention(); retention != nil {
+		if p, ok := storageStore.(logPruner); ok {
+			a.startLogRetention(opts.Ctx, p, retention)
+		} else {
+			log.Warn("log_retention is only supported by the sqlite storage backend")
+		}
+	}
+
 	// Use SetAPIKey from config if available
 	if a.SettingsManager.GetAPIKey() != "" {
 		authManager.SetAPIKey(a.SettingsManager.GetAPIKey())
 	}
+	// OAuth 2.1 access tokens for downstream MCP clients
+	if rsConfig := cfg.GetGlobalSettings().GetOauthResourceServer(); rsConfig != nil {
+		resourceServer, err := auth.NewResourceServer(opts.Ctx, rsConfig)
+		if err != nil {
+			return fmt.Errorf("failed to initialize oauth resource server: %w", err)
+		}
+		authManager.SetResourceServer(resourceServer)
+		log.Info("OAuth authorization enabled for downstream clients", "issuer", rsConfig.GetIssuer())
+	}
+	// JWTs from trusted OIDC issuers
+	if jwtConfig := cfg.GetGlobalSettings().GetJwtAuth(); jwtConfig != nil {
+		jwtValidator, err := auth.NewJWTValidator(jwtConfig)
+		if err != nil {
+			return fmt.Errorf("failed to initialize jwt validation: %w", err)
+		}
+		authManager.SetJWTValidator(jwtValidator)
+		log.Info("JWT validation enabled", "issuers", len(jwtConfig.GetIssuers()), "on_failure", jwtConfig.GetOnFailure().String())
+	}
 	// Note: previous code checked cfg.GetGlobalSettings().GetApiKeyParamName() but that might be inside Authentication config?
 	// GlobalSettings usually has Authentication field.
 	// Let's rely on SettingsManager or check cfg.GetGlobalSettings().GetAuthentication() if needed
@@ -565,6 +850,7 @@ func (a *Application) Run(opts RunOptions) error {
 		profileDefinitions = config.GlobalSettings().GetProfileDefinitions()
 	}
 	a.ProfileManager = profile.NewManager(profileDefinitions)
+	authManager.SetProfileDefinitions(a.ProfileManager)
 
 	// Set profiles for tool filtering
 	a.ToolManager.SetProfiles(
@@ -580,9 +866,12 @@ func (a *Application) Run(opts RunOptions) error {
 		authManager,
 	)
 	a.ServiceRegistry = serviceRegistry
+	serviceRegistry.SetHealthConfig(cfg.GetGlobalSettings().GetUpstreamHealth())
+	serviceRegistry.OnHealthChange(a.resetCircuitOnRecovery)
 
 	// New message bus and workers
 	upstreamWorker := worker.NewUpstreamWorker(busProvider, a.ToolManager)
+	upstreamWorker.SetConfig(cfg.GetGlobalSettings().GetWorker())
 	registrationWorker := worker.NewServiceRegistrationWorker(busProvider, serviceRegistry)
 	if a.RegistrationRetryDelay > 0 {
 		registrationWorker.SetRetryDelay(a.RegistrationRetryDelay)
@@ -590,12 +879,18 @@ func (a *Application) Run(opts RunOptions) error {
 
 	// Create a context for workers that we can cancel on shutdown
 	workerCtx, workerCancel := context.WithCancel(opts.Ctx)
-	defer workerCancel()
+	hooks.OnShutdown("workers", func(context.Context) error {
+		workerCancel()
+		upstreamWorker.Stop()
+		registrationWorker.Stop()
+		return nil
+	}, lifecycle.WithOrder(lifecycle.OrderWorkers))
 
 	// Start background workers
 	upstreamWorker.Start(workerCtx)
 	registrationWorker.Start(workerCtx)
-	// Start periodic health checks (every 30 seconds)
+	// Start the active probes of the upstream services, every 30 seconds
+	// unless configured otherwise
 	serviceRegistry.StartHealthChecks(workerCtx, 30*time.Second)
 
 	// If we're using an in-memory bus, start the in-process worker
@@ -606,8 +901,78 @@ func (a *Application) Run(opts RunOptions) error {
 		}
 		inProcessWorker := worker.New(busProvider, workerCfg)
 		inProcessWorker.Start(workerCtx)
-		defer inProcessWorker.Stop()
+		hooks.OnShutdown("in-process worker", func(context.Context) error {
+			inProcessWorker.Stop()
+			return nil
+		}, lifecycle.WithOrder(lifecycle.OrderWorkers))
+	}
+
+	// Track the service level objectives from the tool call metrics
+	if a.SLOTracker == nil {
+		var alertsManager alerts.ManagerInterface
+		if a.AlertsManager != nil {
+			alertsManager = a.AlertsManager
+		}
+		gatherer := a.MetricsGatherer
+		if gatherer == nil {
+			gatherer = prometheus.DefaultGatherer
+		}
+		a.SLOTracker = slo.NewTracker(alertsManager, slo.WithSource(slo.PrometheusSource(gatherer)))
 	}
+	a.SLOTracker.SetObjectives(cfg.GetUpstreamServices())
+	hooks.OnStart("slo", a.SLOTracker.Start, lifecycle.WithOrder(lifecycle.OrderWorkers))
+	hooks.OnConfigReload("slo", func(_ context.Context, cfg *config_v1.McpAnyServerConfig) error {
+		a.SLOTracker.SetObjectives(cfg.GetUpstreamServices())
+		return nil
+	})
+	hooks.OnShutdown("slo", a.SLOTracker.Stop, lifecycle.WithOrder(lifecycle.OrderWorkers))
+
+	// Record which consumers read the stored secrets
+	if s, ok := storageStore.(storage.Storage); ok {
+		if a.SecretUsage == nil {
+			a.SecretUsage = secretusage.NewRecorder(s)
+		}
+		util.SetSecretUsageObserver(a.SecretUsage.Observe)
+		hooks.OnStart("secret usage", a.SecretUsage.Start, lifecycle.WithOrder(lifecycle.OrderWorkers))
+		hooks.OnShutdown("secret usage", func(ctx context.Context) error {
+			util.SetSecretUsageObserver(nil)
+			return a.SecretUsage.Stop(ctx)
+		}, lifecycle.WithOrder(lifecycle.OrderWorkers))
+	}
+
+	// Keep the post-call webhook events that failed, to replay them
+	if s, ok := storageStore.(storage.Storage); ok {
+		tool.SetWebhookDeadLetterHandler(deadLetterSaver(s))
+		hooks.OnShutdown("webhook dead letters", func(context.Context) error {
+			tool.SetWebhookDeadLetterHandler(nil)
+			return nil
+		}, lifecycle.WithOrder(lifecycle.OrderWorkers))
+	}
+
+	// Rotate client API keys on their schedules and expire replaced keys
+	if s, ok := storageStore.(storage.Storage); ok {
+		rotator := auth.NewAPIKeyRotator(s, authManager, auth.WithAPIKeyNotifier(a.apiKeyNotifier()))
+		hooks.OnStart("api key rotation", rotator.Start, lifecycle.WithOrder(lifecycle.OrderWorkers))
+		hooks.OnShutdown("api key rotation", rotator.Stop, lifecycle.WithOrder(lifecycle.OrderWorkers))
+	}
+
+	// Warn about expiring credentials and upstream TLS certificates. The
+	// checker only lists the credentials and dials the upstreams once
+	// expiry_alerts is enabled.
+	if a.ExpiryChecker == nil {
+		var credentials expiry.CredentialLister
+		if s, ok := storageStore.(storage.Storage); ok {
+			credentials = s
+		}
+		a.ExpiryChecker = expiry.NewChecker(credentials)
+	}
+	a.ExpiryChecker.Configure(cfg)
+	hooks.OnStart("expiry alerts", a.ExpiryChecker.Start, lifecycle.WithOrder(lifecycle.OrderWorkers))
+	hooks.OnConfigReload("expiry alerts", func(_ context.Context, cfg *config_v1.McpAnyServerConfig) error {
+		a.ExpiryChecker.Configure(cfg)
+		return nil
+	})
+	hooks.OnShutdown("expiry alerts", a.ExpiryChecker.Stop, lifecycle.WithOrder(lifecycle.OrderWorkers))
 
 	// Initialize and start Global GC Worker
 	gcSettings := cfg.GetGlobalSettings().GetGcSettings()
@@ -645,9 +1010,6 @@ func (a *Application) Run(opts RunOptions) error {
 		config.GlobalSettings().IsDebug(),
 	)
 	if err != nil {
-		workerCancel()
-		upstreamWorker.Stop()
-		registrationWorker.Stop()
 		return fmt.Errorf("failed to create mcp server: %w", err)
 	}
 
@@ -660,43 +1022,126 @@ func (a *Application) Run(opts RunOptions) error {
 		log.Error("Failed to register skill resources", "error", err)
 		// Don't fail startup for this?
 	}
+	// Serve the skills created, installed or removed from now on.
+	a.SkillManager.OnChange(func() {
+		if err := mcpserver.SyncSkillResources(a.ResourceManager, a.SkillManager); err != nil {
+			log.Error("Failed to refresh skill resources", "error", err)
+		}
+	})
 
 	a.ToolManager.SetMCPServer(mcpSrv)
+	if err := a.registerInProcessTools(opts.Ctx); err != nil {
+		return err
+	}
+
+	// Keep the tool catalog of each discovered service, to list it right
+	// after a restart.
+	var snapshots *toolCatalogSnapshots
+	if snapshotConfig := cfg.GetGlobalSettings().GetToolCatalogSnapshot(); snapshotConfig.GetEnabled() {
+		if s, ok := storageStore.(storage.Storage); ok {
+			snapshots = newToolCatalogSnapshots(s, a.ToolManager, snapshotConfig)
+			serviceRegistry.OnServiceRegistered(snapshots.save)
+		}
+	}
+
+	// Keep the jobs of the asynchronous tools, and resume the unfinished ones
+	// of each service once it is registered again. The leader also resumes
+	// the jobs of the replicas that stop later, once their leases expire.
+	if s, ok := storageStore.(storage.Storage); ok {
+		if jobs, ok := a.ToolManager.(jobPersister); ok {
+			jobs.SetJobStore(s)
+			serviceRegistry.OnServiceRegistered(jobResumer(jobs))
+			startJobLeases(opts.Ctx, jobs, tool.JobLeaseRenewInterval)
+		}
+	}
 
 	if cfg.GetUpstreamServices() != nil {
-		// Publish registration requests to the bus for each service
+		// The services that fail to be discovered at startup are queued for
+		// the registration worker, which retries them.
 		registrationBus, err := bus.GetBus[*bus.ServiceRegistrationRequest](
 			busProvider,
 			"service_registration_requests",
 		)
 		if err != nil {
-			workerCancel()
-			upstreamWorker.Stop()
-			registrationWorker.Stop()
 			return fmt.Errorf("failed to get registration bus: %w", err)
 		}
+		services := make([]*config_v1.UpstreamServiceConfig, 0, len(cfg.GetUpstreamServices()))
 		for _, serviceConfig := range cfg.GetUpstreamServices() {
 			if serviceConfig.GetDisable() {
 				log.Info("Skipping disabled service", "service", serviceConfig.GetName())
 				continue
 			}
-			log.Info(
-				"Queueing service for registration from config",
-				"service",
-				serviceConfig.GetName(),
-			)
+			services = append(services, serviceConfig)
+		}
+		retry := func(serviceConfig *config_v1.UpstreamServiceConfig) {
 			regReq := &bus.ServiceRegistrationRequest{Config: serviceConfig}
 			// We don't need a correlation ID since we are not waiting for a response here
 			if err := registrationBus.Publish(opts.Ctx, "request", regReq); err != nil {
 				log.Error("Failed to publish registration request", "error", err)
 			}
 		}
+		if snapshots != nil {
+			snapshots.restore(opts.Ctx, services)
+		}
+		if err := a.discoverServices(opts.Ctx, services, cfg.GetGlobalSettings().GetStartupDiscovery(), retry); err != nil {
+			return err
+		}
 	} else {
 		log.Info("No services found in config, skipping service registration.")
 	}
 
+	if sharedConfig := cfg.GetGlobalSettings().GetSharedState(); sharedConfig != nil {
+		shared, err := sharedstate.New(opts.Ctx, sharedConfig)
+		if err != nil {
+			return fmt.Errorf("failed to connect to the shared state: %w", err)
+		}
+		a.SharedState = shared
+		resilience.SetSharedState(shared)
+		hooks.OnShutdown("shared state", func(context.Context) error {
+			resilience.SetSharedState(nil)
+			return shared.Close()
+		}, lifecycle.WithOrder(lifecycle.OrderStorage))
+
+		// The replica does not lead until its first heartbeat, so that the
+		// singleton duties do not run before the election.
+		a.Membership = shared.NewMembership(sharedConfig.GetInstanceId(), appconsts.Version, sharedConfig.GetHeartbeatInterval().AsDuration())
+		cluster.SetLeader(a.Membership)
+		hooks.OnStart("cluster membership", a.Membership.Start, lifecycle.WithOrder(lifecycle.OrderStorage))
+		hooks.OnShutdown("cluster membership", func(ctx context.Context) error {
+			cluster.Resign()
+			return a.Membership.Stop(ctx)
+		}, lifecycle.WithOrder(lifecycle.OrderWorkers))
+	} else if sharedDB != nil {
+		// Without shared state, the replicas sharing the database elect
+		// their leader with an advisory lock.
+		election := sharedDB.NewLeaderElection(0)
+		cluster.SetLeader(election)
+		hooks.OnStart("leader election", election.Start, lifecycle.WithOrder(lifecycle.OrderStorage))
+		hooks.OnShutdown("leader election", func(ctx context.Context) error {
+			cluster.Resign()
+			return election.Stop(ctx)
+		}, lifecycle.WithOrder(lifecycle.OrderWorkers))
+	} else {
+		// The storage is this server's own.
+		cluster.SetLeader(cluster.Standalone)
+		hooks.OnShutdown("leader election", func(context.Context) error {
+			cluster.Resign()
+			return nil
+		}, lifecycle.WithOrder(lifecycle.OrderWorkers))
+	}
+
 	// Initialize standard middlewares in registry
 	cachingMiddleware := middleware.NewCachingMiddleware(a.ToolManager)
+	if a.SharedState != nil {
+		cachingMiddleware.SetSharedRedis(a.SharedState.Client())
+	}
+	if err := cachingMiddleware.Configure(cfg.GetGlobalSettings().GetResponseCache()); err != nil {
+		return fmt.Errorf("failed to configure response cache: %w", err)
+	}
+	a.ResponseCache = cachingMiddleware
+	hooks.OnShutdown("response cache", func(context.Context) error {
+		return cachingMiddleware.Close()
+	}, lifecycle.WithOrder(lifecycle.OrderMiddlewares))
 	standardMiddlewares, err := middleware.InitStandardMiddlewares(
 		mcpSrv.AuthManager(),
 		a.ToolManager,
@@ -709,11 +1154,13 @@ func (a *Application) Run(opts RunOptions) error {
 		cfg.GetGlobalSettings().GetSmartRecovery(),
 	)
 	if err != nil {
-		workerCancel()
-		upstreamWorker.Stop()
-		registrationWorker.Stop()
 		return fmt.Errorf("failed to init standard middlewares: %w", err)
 	}
+	if a.SharedState != nil {
+		standardMiddlewares.RateLimit.SetSharedRedis(a.SharedState.Client())
+		standardMiddlewares.GlobalRateLimit.SetSharedRedis(a.SharedState.Client())
+	}
+	standardMiddlewares.Quota.UpdateConfig(cfg.GetGlobalSettings().GetQuotas())
 
 	// Auto-discovery of local services
 	if cfg.GetGlobalSettings().GetAutoDiscoverLocal() {
@@ -728,12 +1175,13 @@ func (a *Application) Run(opts RunOptions) error {
 		}
 	}
 	a.standardMiddlewares = standardMiddlewares
+	if standardMiddlewares.Audit != nil {
+		a.onDrainFlush("audit", standardMiddlewares.Audit.Flush)
+	}
 	if standardMiddlewares.Cleanup != nil {
-		defer func() {
-			if err := standardMiddlewares.Cleanup(); err != nil {
-				log.Error("Failed to close audit middleware", "error", err)
-			}
-		}()
+		hooks.OnShutdown("middlewares", func(context.Context) error {
+			return standardMiddlewares.Cleanup()
+		}, lifecycle.WithOrder(lifecycle.OrderMiddlewares))
 	}
 	// Get configured middlewares
 	// We clone them to avoid modifying the singleton's underlying slice if we append/modify.
@@ -765,6 +1213,10 @@ func (a *Application) Run(opts RunOptions) error {
 				Name:     proto.String("global_ratelimit"),
 				Priority: proto.Int32(45),
 			}.Build(),
+			config_v1.Middleware_builder{
+				Name:     proto.String("quota"),
+				Priority: proto.Int32(46),
+			}.Build(),
 			config_v1.Middleware_builder{
 				Name:     proto.String("call_policy"),
 				Priority: proto.Int32(50),
@@ -795,7 +1247,10 @@ func (a *Application) Run(opts RunOptions) error {
 	// an HTTP request availability in the context, which is not present in stdio.
 	// Stdio mode implies local access (shell), so we trust the user.
 	// Stdio mode implies local access (shell), so we trust the user.
-	if opts.Stdio {
+	// When the network listeners are also running, the auth middleware stays:
+	// it only authenticates requests that carry an HTTP request, so stdio
+	// sessions still pass through while remote clients are checked.
+	if opts.Stdio && !opts.StdioWithNetwork {
 		var filtered []*config_v1.Middleware
 		for _, m := range middlewares {
 			if m.GetName() != authMiddlewareName {
@@ -824,22 +1279,22 @@ func (a *Application) Run(opts RunOptions) error {
 	chain := middleware.GetMCPMiddlewares(middlewares)
 	for _, m := range chain {
 		logging.GetLogger().Info("Adding middleware", "count", len(chain))
-		mcpSrv.Server().AddReceivingMiddleware(m)
+		mcpSrv.AddReceivingMiddleware(m)
 	}
 
 	// Add Topology Middleware (Always Active)
-	mcpSrv.Server().AddReceivingMiddleware(a.TopologyManager.Middleware)
+	mcpSrv.AddReceivingMiddleware(a.TopologyManager.Middleware)
 
 	// Add Prometheus Metrics Middleware (Always Active)
 	// We use SimpleTokenizer for low-overhead token counting
-	mcpSrv.Server().AddReceivingMiddleware(middleware.PrometheusMetricsMiddleware(tokenizer.NewSimpleTokenizer()))
+	mcpSrv.AddReceivingMiddleware(middleware.PrometheusMetricsMiddleware(tokenizer.NewSimpleTokenizer()))
 
-	if opts.Stdio {
-		err := a.runStdioModeFunc(opts.Ctx, mcpSrv)
-		workerCancel()
-		upstreamWorker.Stop()
-		registrationWorker.Stop()
-		return err
+	if err := hooks.Start(opts.Ctx); err != nil {
+		return fmt.Errorf("failed to start: %w", err)
+	}
+
+	if opts.Stdio && !opts.StdioWithNetwork {
+		return a.runStdioModeFunc(opts.Ctx, mcpSrv)
 	}
 
 	bindAddress := opts.JSONRPCPort
@@ -862,6 +1317,7 @@ func (a *Application) Run(opts RunOptions) error {
 		return fmt.Errorf("storage store does not implement storage.Storage")
 	}
 	a.Storage = s
+	a.recordConfigVersion(WithReloadTrigger(opts.Ctx, "startup"), cfg, nil, rollbackOf)
 
 	// Signal startup complete
 	startupCallback := func() {
@@ -870,9 +1326,26 @@ func (a *Application) Run(opts RunOptions) error {
 		})
 	}
 
+	serverCtx := opts.Ctx
+	if opts.Stdio {
+		// Serve the local agent over stdio alongside the network listeners.
+		// The process belongs to the agent that spawned it, so the whole
+		// server shuts down once the stdio session ends.
+		var stdioCancel context.CancelFunc
+		serverCtx, stdioCancel = context.WithCancel(opts.Ctx)
+		defer stdioCancel()
+		go func() {
+			defer stdioCancel()
+			if err := a.runStdioModeFunc(serverCtx, mcpSrv); err != nil && serverCtx.Err() == nil {
+				logging.GetLogger().Error("Stdio session failed", "error", err)
+			}
+			logging.GetLogger().Info("Stdio session ended, shutting down network listeners")
+		}()
+	}
+
 	// Start servers
 	if err := a.runServerMode(
-		opts.Ctx,
+		serverCtx,
 		mcpSrv,
 		busProvider,
 		bindAddress,
@@ -884,21 +1357,19 @@ func (a *Application) Run(opts RunOptions) error {
 		s,
 		serviceRegistry,
 		startupCallback,
-		opts.TLSCert,
-		opts.TLSKey,
-		opts.TLSClientCA,
+		ListenerTLSOptions{
+			CertFile:         opts.TLSCert,
+			KeyFile:          opts.TLSKey,
+			ClientCAFile:     opts.TLSClientCA,
+			ClientAuth:       opts.TLSClientAuth,
+			ACMEDomains:      opts.ACMEDomains,
+			ACMEEmail:        opts.ACMEEmail,
+			ACMECacheDir:     opts.ACMECacheDir,
+			ACMEDirectoryURL: opts.ACMEDirectoryURL,
+		},
 	); err != nil {
-		workerCancel()
-		upstreamWorker.Stop()
-		registrationWorker.Stop()
 		return err
 	}
-
-	// Stop workers
-	workerCancel()
-	upstreamWorker.Stop()
-	registrationWorker.Stop()
-
 	return nil
 }
 
@@ -944,7 +1415,12 @@ func (a *Application) ReloadConfig(ctx context.Context, fs afero.Fs, configPaths
 	a.lastReloadTime = time.Now()
 	a.lastReloadErr = err
 	if err != nil {
+		if a.kubernetesStatus != nil {
+			a.kubernetesStatus.Reloaded(err)
+		}
 		metrics.IncrCounter([]string{"config", "reload", "errors"}, 1)
+		a.notifyReloadFailure(configPaths, err)
+		a.recordConfigVersion(ctx, nil, err, 0)
 		// Generate Diff if we have previous good config and new config
 		if newConfigRaw != nil && a.lastGoodConfig != nil {
 			a.configDiff = a.generateConfigDiff(a.lastGoodConfig, newConfigRaw)
@@ -958,8 +1434,29 @@ func (a *Application) ReloadConfig(ctx context.Context, fs afero.Fs, configPaths
 		a.configDiff = ""
 	}
 
+	a.sourceHash = configHash(cfg)
+	if a.Storage != nil {
+		if pinned := a.pinnedRollback(ctx, a.Storage); pinned != nil {
+			log.Info("Configuration sources unchanged since the rollback, keeping it", "version", pinned.GetRollbackOf())
+			return nil
+		}
+	}
+
+	a.applyConfig(ctx, cfg)
+	a.recordConfigVersion(ctx, cfg, nil, 0)
+	return nil
+}
+
+// applyConfig reconfigures the running server with a loaded configuration.
+// It must be called with configMu held.
+func (a *Application) applyConfig(ctx context.Context, cfg *config_v1.McpAnyServerConfig) {
+	log := logging.GetLogger()
+
 	// Update global settings
 	a.updateGlobalSettings(cfg)
+	if err := a.configureOpenAIBridge(ctx, cfg.GetGlobalSettings().GetOpenaiBridge()); err != nil {
+		log.Error("Failed to update the OpenAI bridge", "error", err)
+	}
 
 	// Update Users (Dynamic!)
 	if a.AuthManager != nil {
@@ -981,7 +1478,40 @@ func (a *Application) ReloadConfig(ctx context.Context, fs afero.Fs, configPaths
 
 	// Reconcile services (add/remove/update)
 	a.reconcileServices(ctx, cfg)
-	return nil
+	if a.kubernetesStatus != nil {
+		a.kubernetesStatus.Reloaded(nil)
+	}
+
+	if a.hooks != nil {
+		if err := a.hooks.Reload(ctx, cfg); err != nil {
+			log.Error("Config reload hooks failed", "error", err)
+		}
+	}
+}
+
+// kubernetesServiceStatus reports whether the service is registered and
+// healthy, for the status of its UpstreamService resource.
+func (a *Application) kubernetesServiceStatus(name string) (bool, string) {
+	if a.ServiceRegistry == nil {
+		return false, "service registry not initialized"
+	}
+	services, err := a.ServiceRegistry.GetAllServices()
+	if err != nil {
+		return false, err.Error()
+	}
+	for _, svc := range services {
+		if svc.GetName() != name {
+			continue
+		}
+		if svc.GetDisable() {
+			return false, "disabled"
+		}
+		if errMsg, ok := a.ServiceRegistry.GetServiceError(svc.GetId()); ok {
+			return false, errMsg
+		}
+		return true, ""
+	}
+	return false, "not registered"
 }
 
 func (a *Application) loadConfig(ctx context.Context, fs afero.Fs, configPaths []string) (*config_v1.McpAnyServerConfig, error) {
@@ -1013,10 +1543,31 @@ func (a *Application) updateGlobalSettings(cfg *config_v1.McpAnyServerConfig) {
 		log.Info("Updated log level", "level", newLevel)
 	}
 
+	// Update label enrichers. New label names only reach the metrics after a
+	// restart.
+	if err := enrichment.Configure(cfg.GetGlobalSettings().GetLabelEnrichers()); err != nil {
+		log.Error("Failed to update label enrichers", "error", err)
+	}
+
+	configureLogRedaction(cfg.GetGlobalSettings().GetDlp())
+	a.readiness.Store(cfg.GetGlobalSettings().GetReadiness())
+	a.activeConfig.Store(cfg)
+	if a.SlowCalls != nil {
+		a.SlowCalls.SetConfig(cfg.GetGlobalSettings().GetSlowCalls())
+	}
+	if a.ResponseCache != nil {
+		if err := a.ResponseCache.Configure(cfg.GetGlobalSettings().GetResponseCache()); err != nil {
+			log.Error("Failed to update response cache", "error", err)
+		}
+	}
+
 	// Update Health Alerts
 	if cfg.GetGlobalSettings().GetAlerts() != nil {
 		health.SetGlobalAlertConfig(cfg.GetGlobalSettings().GetAlerts())
 	}
+	if reporter, ok := a.ServiceRegistry.(upstreamHealthReporter); ok {
+		reporter.SetHealthConfig(cfg.GetGlobalSettings().GetUpstreamHealth())
+	}
 
 	// Update dynamic middlewares
 	if a.ipMiddleware != nil {
@@ -1024,6 +1575,11 @@ func (a *Application) updateGlobalSettings(cfg *config_v1.McpAnyServerConfig) {
 			log.Error("Failed to update IP allowlist", "error", err)
 		}
 	}
+	if a.networkAccess != nil {
+		if err := a.networkAccess.Update(cfg.GetGlobalSettings().GetNetworkAccess()); err != nil {
+			log.Error("Failed to update network access rules", "error", err)
+		}
+	}
 	if a.corsMiddleware != nil {
 		a.corsMiddleware.Update(a.SettingsManager.GetAllowedOrigins())
 	}
@@ -1040,7 +1596,20 @@ func (a *Application) updateGlobalSettings(cfg *config_v1.McpAnyServerConfig) {
 		if a.standardMiddlewares.GlobalRateLimit != nil {
 			a.standardMiddlewares.GlobalRateLimit.UpdateConfig(cfg.GetGlobalSettings().GetRateLimit())
 		}
+		if a.standardMiddlewares.Quota != nil {
+			a.standardMiddlewares.Quota.UpdateConfig(cfg.GetGlobalSettings().GetQuotas())
+		}
+	}
+}
+
+// configureLogRedaction masks the values the DLP detectors find in log
+// messages and attributes when redact_logs is set.
+func configureLogRedaction(dlp *config_v1.DLPConfig) {
+	if r := middleware.NewRedactor(dlp, logging.GetLogger()); r != nil && dlp.GetRedactLogs() {
+		logging.SetValueRedactor(r.RedactString)
+		return
 	}
+	logging.SetValueRedactor(nil)
 }
 
 //nolint:gocyclo // complexity is fine here
@@ -1141,79 +1710,101 @@ func (a *Application) reconcileServices(ctx context.Context, cfg *config_v1.McpA
 		}
 	}
 
-	// Identify removed services
+	// Classify the services, so that the in-flight calls of all the removed
+	// and changed ones are drained together before any is torn down.
+	actions := make(map[string]serviceReloadAction, len(currentServicesMap)+len(newServices))
 	for name := range currentServicesMap {
 		if _, exists := newServices[name]; !exists {
-			log.Info("Removing service", "service", name)
-			if a.ServiceRegistry != nil {
-				if err := a.ServiceRegistry.UnregisterService(ctx, name); err != nil {
-					log.Error("Failed to unregister service", "service", name, "error", err)
-				}
-			}
+			actions[name] = serviceRemoved
 		}
 	}
-
-	// Identify added or updated services
 	for name, newSvc := range newServices {
 		oldConfig, exists := currentServicesMap[name]
-		needsUpdate := false
-
 		if !exists {
-			log.Info("Adding new service", "service", name)
-			needsUpdate = true
+			actions[name] = serviceAdded
+			continue
+		}
+		// Compare configs
+		newSvcCopy := proto.Clone(newSvc).(*config_v1.UpstreamServiceConfig)
+		if newSvcCopy.GetId() == "" {
+			newSvcCopy.SetId(oldConfig.GetId())
+		}
+		if newSvcCopy.GetSanitizedName() == "" {
+			newSvcCopy.SetSanitizedName(oldConfig.GetSanitizedName())
+		}
+		if proto.Equal(oldConfig, newSvcCopy) {
+			actions[name] = serviceUnchanged
 		} else {
-			// Compare configs
-			newSvcCopy := proto.Clone(newSvc).(*config_v1.UpstreamServiceConfig)
-			if newSvcCopy.GetId() == "" {
-				newSvcCopy.SetId(oldConfig.GetId())
-			}
-			if newSvcCopy.GetSanitizedName() == "" {
-				newSvcCopy.SetSanitizedName(oldConfig.GetSanitizedName())
-			}
+			actions[name] = serviceUpdated
+		}
+	}
+	drains := a.drainServices(ctx, actions, drainTimeout(cfg))
 
-			if !proto.Equal(oldConfig, newSvcCopy) {
-				log.Info("Updating service", "service", name)
-				needsUpdate = true
-				if a.ServiceRegistry != nil {
-					if err := a.ServiceRegistry.UnregisterService(ctx, name); err != nil {
-						log.Error("Failed to unregister service for update", "service", name, "error", err)
-					}
-				}
+	// Identify removed services
+	for name, action := range actions {
+		if action != serviceRemoved {
+			continue
+		}
+		log.Info("Removing service", "service", name)
+		if a.ServiceRegistry != nil {
+			if err := a.ServiceRegistry.UnregisterService(ctx, name); err != nil {
+				log.Error("Failed to unregister service", "service", name, "error", err)
 			}
 		}
+	}
 
-		if needsUpdate {
-			switch {
-			case a.busProvider != nil:
-				// Async registration via bus to support retries
-				registrationBus, err := bus.GetBus[*bus.ServiceRegistrationRequest](
-					a.busProvider,
-					bus.ServiceRegistrationRequestTopic,
-				)
-				if err != nil {
-					log.Error("Failed to get registration bus during reload", "error", err)
-					continue
-				}
-				regReq := &bus.ServiceRegistrationRequest{Config: newSvc}
-				if err := registrationBus.Publish(context.Background(), "request", regReq); err != nil {
-					log.Error("Failed to publish registration request during reload", "error", err)
-				} else {
-					log.Info("Queued service for registration update", "service", name)
-				}
-			case a.ServiceRegistry != nil:
-				// Fallback to sync registration if bus is not available (e.g. tests without full init)
-				_, _, _, err := a.ServiceRegistry.RegisterService(context.Background(), newSvc)
-				if err != nil {
-					log.Error("Failed to register upstream service", "service", name, "error", err)
-					continue
+	// Identify added or updated services
+	for name, newSvc := range newServices {
+		switch actions[name] {
+		case serviceAdded:
+			log.Info("Adding new service", "service", name)
+		case serviceUpdated:
+			log.Info("Updating service", "service", name)
+			if a.ServiceRegistry != nil {
+				if err := a.ServiceRegistry.UnregisterService(ctx, name); err != nil {
+					log.Error("Failed to unregister service for update", "service", name, "error", err)
 				}
-			default:
-				log.Warn("ServiceRegistry is nil, cannot register service", "service", name)
 			}
-		} else {
+		default:
 			log.Debug("Service unchanged", "service", name)
+			continue
+		}
+
+		switch {
+		case newSvc.GetInitialization() == config_v1.UpstreamServiceConfig_EAGER && a.ServiceRegistry != nil:
+			timeout := discoveryServiceTimeout(cfg.GetGlobalSettings().GetStartupDiscovery(), newSvc)
+			if _, err := registerService(ctx, a.ServiceRegistry, newSvc, timeout); err != nil {
+				log.Error("Failed to register eager service", "service", name, "error", err)
+				continue
+			}
+		case a.busProvider != nil:
+			// Async registration via bus to support retries
+			registrationBus, err := bus.GetBus[*bus.ServiceRegistrationRequest](
+				a.busProvider,
+				bus.ServiceRegistrationRequestTopic,
+			)
+			if err != nil {
+				log.Error("Failed to get registration bus during reload", "error", err)
+				continue
+			}
+			regReq := &bus.ServiceRegistrationRequest{Config: newSvc}
+			if err := registrationBus.Publish(context.Background(), "request", regReq); err != nil {
+				log.Error("Failed to publish registration request during reload", "error", err)
+			} else {
+				log.Info("Queued service for registration update", "service", name)
+			}
+		case a.ServiceRegistry != nil:
+			// Fallback to sync registration if bus is not available (e.g. tests without full init)
+			_, _, _, err := a.ServiceRegistry.RegisterService(context.Background(), newSvc)
+			if err != nil {
+				log.Error("Failed to register upstream service", "service", name, "error", err)
+				continue
+			}
+		default:
+			log.Warn("ServiceRegistry is nil, cannot register service", "service", name)
 		}
 	}
+	reportServiceReload(actions, drains)
 
 	log.Info("Reload complete", "tools_count", len(a.ToolManager.ListTools()))
 
@@ -1251,8 +1842,8 @@ func (a *Application) reconcileServices(ctx context.Context, cfg *config_v1.McpA
 func (a *Application) readConfigFiles(fs afero.Fs, paths []string) (map[string]string, error) {
 	result := make(map[string]string)
 	for _, path := range paths {
-		// Handle URL - skip for diffing for now as it requires network call and we only care about file changes mostly
-		if strings.HasPrefix(strings.ToLower(path), "http://") || strings.HasPrefix(strings.ToLower(path), "https://") {
+		// Handle URL and key-value sources - skip for diffing for now as it requires network call and we only care about file changes mostly
+		if config.IsRemotePath(path) {
 			continue
 		}
 
@@ -1267,9 +1858,8 @@ func (a *Application) readConfigFiles(fs afero.Fs, paths []string) (map[string]s
 					return err
 				}
 				if !fi.IsDir() {
-					// Simple check for extension to match Config behavior roughly
-					ext := strings.ToLower(filepath.Ext(p))
-					if ext == ".yaml" || ext == ".yml" || ext == ".json" || ext == ".textproto" || ext == ".prototxt" {
+					// Read the files the config store loads.
+					if _, err := config.NewEngine(p); err == nil {
 						b, err := afero.ReadFile(fs, p)
 						if err != nil {
 							return err
@@ -1391,7 +1981,7 @@ func setup(fs afero.Fs) (afero.Fs, error) {
 func runStdioMode(ctx context.Context, mcpSrv *mcpserver.Server) error {
 	log := logging.GetLogger()
 	log.Info("Starting in stdio mode")
-	return mcpSrv.Server().Run(ctx, &mcp.StdioTransport{})
+	return mcpSrv.Server().Run(mcpserver.ContextWithTransport(ctx, mcpserver.TransportStdio), &mcp.StdioTransport{})
 }
 
 // configHealthCheck checks the status of the configuration.
@@ -1552,9 +2142,7 @@ func HealthCheckWithContext(
 //   - store (storage.Storage): The storage interface.
 //   - serviceRegistry (*serviceregistry.ServiceRegistry): The service registry.
 //   - startupCallback (func()): Callback function executed when servers are ready.
-//   - tlsCert (string): Path to TLS certificate.
-//   - tlsKey (string): Path to TLS key.
-//   - tlsClientCA (string): Path to TLS Client CA.
+//   - listenerTLS (ListenerTLSOptions): TLS termination on the HTTP listener.
 //
 // Returns:
 //   - (error): An error if any of the servers fail to start or run.
@@ -1572,15 +2160,29 @@ func (a *Application) runServerMode(
 	store storage.Storage,
 	serviceRegistry *serviceregistry.ServiceRegistry,
 	startupCallback func(),
-	tlsCert, tlsKey, tlsClientCA string,
+	listenerTLS ListenerTLSOptions,
 ) error {
 	ipMiddleware, err := middleware.NewIPAllowlistMiddleware(a.SettingsManager.GetAllowedIPs())
 	if err != nil {
 		return fmt.Errorf("failed to create IP allowlist middleware: %w", err)
 	}
+	networkAccess, err := middleware.NewNetworkAccess(globalSettings.GetNetworkAccess())
+	if err != nil {
+		return fmt.Errorf("failed to create network access rules: %w", err)
+	}
+	if standardMiddlewares != nil && standardMiddlewares.Audit != nil {
+		networkAccess.SetAuditor(standardMiddlewares.Audit)
+	}
+
+	// Fail before any listener starts if the TLS settings are unusable.
+	tlsConfig, err := listenerTLS.TLSConfig()
+	if err != nil {
+		return fmt.Errorf("failed to configure TLS: %w", err)
+	}
 
 	a.configMu.Lock()
 	a.ipMiddleware = ipMiddleware
+	a.networkAccess = networkAccess
 	a.configMu.Unlock()
 
 	// localCtx is used to manage the lifecycle of the servers started in this function.
@@ -1588,8 +2190,8 @@ func (a *Application) runServerMode(
 	localCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	errChan := make(chan error, 2)
-	readyChan := make(chan struct{}, 2)
+	errChan := make(chan error, 3)
+	readyChan := make(chan struct{}, 3)
 	expectedReady := 0
 	var wg sync.WaitGroup
 
@@ -1597,10 +2199,17 @@ func (a *Application) runServerMode(
 		return mcpSrv.Server()
 	}, nil)
 
+	// Route the requests of the sessions created by other replicas to them.
+	var sessionHandler http.Handler = rawHTTPHandler
+	if a.SharedState != nil {
+		sessionHandler = a.SharedState.SessionAffinity(rawHTTPHandler)
+	}
+	sessionHandler = a.rejectNewSessionsWhileDraining(sessionHandler)
+
 	// Wrap the HTTP handler with OpenTelemetry instrumentation
 	// Note: We don't inject HTTPRequestContextKey here anymore because we do it globally
 	// in the HTTPRequestContextMiddleware.
-	httpHandler := otelhttp.NewHandler(rawHTTPHandler, "server-request")
+	httpHandler := otelhttp.NewHandler(sessionHandler, "server-request")
 
 	// Check if auth middleware is disabled in config
 	var authDisabled bool
@@ -1628,13 +2237,27 @@ func (a *Application) runServerMode(
 	// Trust Proxy Config
 	trustProxy := os.Getenv("MCPANY_TRUST_PROXY") == util.TrueStr
 
-	var authMiddleware func(http.Handler) http.Handler
+	var authMiddleware, mcpAuthMiddleware func(http.Handler) http.Handler
 	if authDisabled {
 		logging.GetLogger().Warn("Auth middleware is disabled by config! Enforcing private-IP-only access for safety.")
 		// Even if auth is disabled, we enforce private-IP-only access to prevent public exposure.
 		authMiddleware = a.createAuthMiddleware(true, trustProxy)
+		mcpAuthMiddleware = a.createMCPAuthMiddleware(true, trustProxy)
 	} else {
 		authMiddleware = a.createAuthMiddleware(false, trustProxy)
+		mcpAuthMiddleware = a.createMCPAuthMiddleware(false, trustProxy)
+	}
+
+	if debugAddress := debugListenAddress(globalSettings.GetDebugListener()); debugAddress != "" {
+		// The debug endpoints are served on their own listener, so that they
+		// can stay unreachable from the network the MCP clients are on.
+		if debugLis, err := util.ListenWithRetry(ctx, "tcp", debugAddress); err != nil {
+			errChan <- fmt.Errorf("debug server failed to listen on %s: %w", debugAddress, err)
+		} else {
+			logging.GetLogger().Info("Starting debug listener", "address", debugLis.Addr().String())
+			expectedReady++
+			startHTTPServer(localCtx, &wg, errChan, readyChan, "Debug", debugLis, a.newDebugHandler(authMiddleware, ipMiddleware, networkAccess), shutdownTimeout, nil)
+		}
 	}
 
 	mux := http.NewServeMux()
@@ -1700,6 +2323,28 @@ func (a *Application) runServerMode(
 	apiHandler := http.StripPrefix("/api/v1", a.createAPIHandler(store))
 	mux.Handle("/api/v1/", authMiddleware(apiHandler))
 
+	// Bridges of the MCP toolset to other agent protocols.
+	if mcpSrv != nil {
+		openAIBridge := newOpenAIBridge(mcpSrv)
+		a.configMu.Lock()
+		a.openAIBridge = openAIBridge
+		err := a.configureOpenAIBridge(ctx, globalSettings.GetOpenaiBridge())
+		a.configMu.Unlock()
+		if err != nil {
+			return err
+		}
+		mux.Handle("/openai/v1/", authMiddleware(http.StripPrefix("/openai/v1", openAIBridge)))
+
+		// A2A agent delegating its tasks to the tools.
+		a2aHandler := authMiddleware(newA2AServer(mcpSrv))
+		mux.Handle(a2aPath, a2aHandler)
+		mux.Handle("/.well-known/agent-card.json", a2aHandler)
+		mux.Handle("/.well-known/agent.json", a2aHandler)
+
+		// REST facade calling every tool over plain HTTP.
+		mux.Handle(restGatewayPath+"/", authMiddleware(http.StripPrefix(restGatewayPath, newRESTGateway(mcpSrv))))
+	}
+
 	// Topology API is now handled by apiHandler via api.go
 
 	// Catalog API
@@ -1971,6 +2616,11 @@ func (a *Application) runServerMode(
 	})
 	mux.Handle("/healthz", healthHandler)
 	mux.Handle("/health", healthHandler)
+	mux.Handle("/livez", a.newLivenessProbe().Handler())
+	readiness := a.newReadinessProbe()
+	mux.Handle("/readyz", readiness.Handler())
+	mux.Handle("/readyz/details", authMiddleware(readiness.DetailsHandler()))
+	mux.Handle("/healthz/upstreams", authMiddleware(http.HandlerFunc(a.handleUpstreamHealth)))
 	mux.Handle("/metrics", authMiddleware(metrics.Handler()))
 	mux.Handle("/upload", authMiddleware(http.HandlerFunc(a.uploadFile)))
 
@@ -1997,6 +2647,15 @@ func (a *Application) runServerMode(
 		}
 	}
 
+	// OAuth 2.0 Protected Resource Metadata (RFC 9728), served unauthenticated
+	// so clients can discover the authorization server.
+	if a.AuthManager != nil {
+		if resourceServer := a.AuthManager.ResourceServer(); resourceServer != nil {
+			mux.Handle(auth.ProtectedResourceMetadataPath, resourceServer.MetadataHandler())
+			mux.Handle(auth.ProtectedResourceMetadataPath+"/", resourceServer.MetadataHandler())
+		}
+	}
+
 	// OAuth API Routes
 	mux.Handle("/auth/oauth/initiate", authMiddleware(http.HandlerFunc(a.handleInitiateOAuth)))
 	mux.Handle("/auth/oauth/callback", authMiddleware(http.HandlerFunc(a.handleOAuthCallback)))
@@ -2019,6 +2678,10 @@ func (a *Application) runServerMode(
 			a.revealSecretHandler(w, r)
 			return
 		}
+		if strings.HasSuffix(r.URL.Path, "/usage") {
+			a.secretUsageHandler(w, r)
+			return
+		}
 		switch r.Method {
 		case http.MethodGet:
 			a.getSecretHandler(w, r)
@@ -2157,16 +2820,22 @@ func (a *Application) runServerMode(
 			}
 	}
 
-	// Middleware order: SecurityHeaders -> CORS -> CSRF -> JSONRPCCompliance -> Recovery -> IPAllowList -> RateLimit -> (Debugger -> Optimizer -> Mux)
+	// Middleware order: SecurityHeaders -> CORS -> CSRF -> JSONRPCCompliance -> Recovery -> Federation -> IPAllowList -> NetworkAccess -> RateLimit -> AuthFailures -> (Debugger -> Optimizer -> Mux)
 	// We wrap everything with a debug logger to see what's coming in
 	handler := middleware.HTTPSecurityHeadersMiddleware(
 		corsMiddleware.Handler(
 			csrfMiddleware.Handler(
 				middleware.JSONRPCComplianceMiddleware(
 					middleware.RecoveryMiddleware(
-						a.HTTPRequestContextMiddleware(
-							ipMiddleware.Handler(
-								rateLimiter.Handler(finalHandler),
+						federation.Middleware(
+							a.HTTPRequestContextMiddleware(
+								ipMiddleware.Handler(
+									networkAccess.Handler(
+										rateLimiter.Handler(
+											middleware.AuthFailureMiddleware(a.recordAuthFailure)(finalHandler),
+										),
+									),
+								),
 							),
 						),
 					),
@@ -2182,7 +2851,7 @@ func (a *Application) runServerMode(
 	grpcBindAddress := grpcPort
 
 	// Initialize gRPC Interceptors
-	grpcUnaryInterceptor := func(ctx context.Context, req interface{}, _ *gogrpc.UnaryServerInfo, handler gogrpc.UnaryHandler) (interface{}, error) {
+	grpcUnaryInterceptor := func(ctx context.Context, req interface{}, info *gogrpc.UnaryServerInfo, handler gogrpc.UnaryHandler) (interface{}, error) {
 		if p, ok := peer.FromContext(ctx); ok {
 			ip := util.ExtractIP(p.Addr.String())
 			ctx = util.ContextWithRemoteIP(ctx, ip)
@@ -2190,10 +2859,20 @@ func (a *Application) runServerMode(
 			if !ipMiddleware.Allow(p.Addr.String()) {
 				return nil, status.Error(codes.PermissionDenied, "IP not allowed")
 			}
+			if !networkAccess.Allow(ctx, middleware.ListenerAdmin, net.ParseIP(ip), map[string]string{"method": info.FullMethod}) {
+				return nil, status.Error(codes.PermissionDenied, "IP not allowed")
+			}
+		}
+		// The admin service requires the admin role, like its REST gateway.
+		if strings.HasPrefix(info.FullMethod, adminServiceMethodPrefix) {
+			var err error
+			if ctx, err = authorizeAdminRPC(ctx, info.FullMethod, authMiddleware); err != nil {
+				return nil, err
+			}
 		}
 		return handler(ctx, req)
 	}
-	grpcStreamInterceptor := func(srv interface{}, ss gogrpc.ServerStream, _ *gogrpc.StreamServerInfo, handler gogrpc.StreamHandler) error {
+	grpcStreamInterceptor := func(srv interface{}, ss gogrpc.ServerStream, info *gogrpc.StreamServerInfo, handler gogrpc.StreamHandler) error {
 		if p, ok := peer.FromContext(ss.Context()); ok {
 			ip := util.ExtractIP(p.Addr.String())
 			// Wrapper to modify context for stream
@@ -2204,6 +2883,9 @@ func (a *Application) runServerMode(
 			if !ipMiddleware.Allow(p.Addr.String()) {
 				return status.Error(codes.PermissionDenied, "IP not allowed")
 			}
+			if !networkAccess.Allow(wrappedStream.Ctx, middleware.ListenerAdmin, net.ParseIP(ip), map[string]string{"method": info.FullMethod}) {
+				return status.Error(codes.PermissionDenied, "IP not allowed")
+			}
 			return handler(srv, wrappedStream)
 		}
 		return handler(srv, ss)
@@ -2229,8 +2911,32 @@ func (a *Application) runServerMode(
 		auditMiddleware = standardMiddlewares.Audit
 	}
 	adminServer := admin.NewServer(cachingMiddleware, a.ToolManager, serviceRegistry, store, a.DiscoveryManager, auditMiddleware)
+	adminServer.SetSLOTracker(a.SLOTracker)
+	adminServer.SetAPIKeyNotifier(a.apiKeyNotifier())
+	adminServer.SetConfigReloader(func(ctx context.Context) error {
+		return a.ReloadConfig(ctx, a.fs, a.configPaths)
+	})
+	if mcpSrv != nil {
+		adminServer.SetMCPServer(mcpSrv.Server())
+		adminServer.SetSessionTracker(mcpSrv.SessionTracker())
+	}
+	adminServer.SetCircuitBreakers(a.Resilience)
 	pb_admin.RegisterAdminServiceServer(grpcServer, adminServer)
 
+	// The REST gateway of the admin service calls it in process, with the
+	// identity the auth middleware put in the request context.
+	adminGwmux := runtime.NewServeMux()
+	if err := pb_admin.RegisterAdminServiceHandlerServer(ctx, adminGwmux, adminServer); err != nil {
+		return fmt.Errorf("failed to register admin gateway: %w", err)
+	}
+	mux.Handle("/v1/admin/", authMiddleware(middleware.NewRBACMiddleware().RequireRole("admin")(adminGwmux)))
+
+	// The built-in dashboard reads the admin service too, read-only, and is
+	// open to viewers. Its page holds no data and is served to anyone.
+	mux.Handle("/dashboard/", http.StripPrefix("/dashboard", dashboard.Assets()))
+	mux.Handle("/dashboard/api/", authMiddleware(middleware.NewRBACMiddleware().RequireAnyRole(dashboard.Roles...)(
+		http.StripPrefix("/dashboard", dashboard.API(adminServer)))))
+
 	// Register Skill Service
 	v1.RegisterSkillServiceServer(grpcServer, NewSkillServiceServer(a.SkillManager))
 
@@ -2260,8 +2966,6 @@ func (a *Application) runServerMode(
 					errChan <- fmt.Errorf("failed to register gateway: %w", err)
 				} else if err := v1.RegisterSkillServiceHandlerFromEndpoint(ctx, gwmux, endpoint, opts); err != nil {
 					errChan <- fmt.Errorf("failed to register skill gateway: %w", err)
-				} else if err := pb_admin.RegisterAdminServiceHandlerFromEndpoint(ctx, gwmux, endpoint, opts); err != nil {
-					errChan <- fmt.Errorf("failed to register admin gateway: %w", err)
 				} else {
 					// Consolidated handler for /v1/ to support both gRPC Gateway and Asset Uploads
 					mux.Handle("/v1/", authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -2287,13 +2991,20 @@ func (a *Application) runServerMode(
 		}
 	}
 
-	// Register Root Handler with gRPC-Web support
-	mux.Handle("/", authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if wrappedGrpc != nil && wrappedGrpc.IsGrpcWebRequest(r) {
-			wrappedGrpc.ServeHTTP(w, r)
-			return
-		}
+	// WebSocket transport for MCP clients that can't use SSE/streamable HTTP.
+	// Authentication happens once on the upgrade request (headers or the
+	// api_key/auth_token query parameters) and applies to the whole session.
+	mux.Handle("/mcp/ws", mcpAuthMiddleware(a.rejectNewSessionsWhileDraining(mcpserver.NewWebSocketHandler(func(_ *http.Request) *mcp.Server {
+		return mcpSrv.Server()
+	}, nil))))
 
+	// Register Root Handler with gRPC-Web support. Only the MCP transport
+	// may serve downgraded JWT requests; gRPC-Web calls never are.
+	var grpcWebHandler http.Handler
+	if wrappedGrpc != nil {
+		grpcWebHandler = authMiddleware(wrappedGrpc)
+	}
+	rootHandler := mcpAuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// UI Routing for root path
 		if r.URL.Path == "/" && uiPath != "" {
 			http.ServeFile(w, r, filepath.Join(uiPath, "index.html"))
@@ -2302,32 +3013,22 @@ func (a *Application) runServerMode(
 
 		// Fallback to JSON-RPC handler (for API calls at root or SSE)
 		httpHandler.ServeHTTP(w, r)
-	})))
-
-	var httpLis net.Listener
-
-	if tlsCert != "" && tlsKey != "" {
-		cert, err := tls.LoadX509KeyPair(tlsCert, tlsKey)
-		if err != nil {
-			return fmt.Errorf("failed to load TLS key pair: %w", err)
+	}))
+	mux.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if grpcWebHandler != nil && wrappedGrpc.IsGrpcWebRequest(r) {
+			grpcWebHandler.ServeHTTP(w, r)
+			return
 		}
+		rootHandler.ServeHTTP(w, r)
+	}))
 
-		tlsConfig := &tls.Config{
-			Certificates: []tls.Certificate{cert},
-			MinVersion:   tls.VersionTLS12,
-		}
-		if tlsClientCA != "" {
-			caCert, err := os.ReadFile(tlsClientCA)
-			if err != nil {
-				return fmt.Errorf("failed to read TLS client CA: %w", err)
-			}
-			caCertPool := x509.NewCertPool()
-			caCertPool.AppendCertsFromPEM(caCert)
-			tlsConfig.ClientCAs = caCertPool
-			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
-		}
+	var httpLis net.Listener
 
-		logging.GetLogger().Info("Enabling TLS for HTTP server", "mtls_enabled", tlsConfig.ClientAuth == tls.RequireAndVerifyClientCert)
+	if tlsConfig != nil {
+		logging.GetLogger().Info("Enabling TLS for HTTP server",
+			"acme_domains", listenerTLS.ACMEDomains,
+			"mtls_enabled", tlsConfig.ClientAuth != tls.NoClientCert,
+			"client_cert_required", tlsConfig.ClientAuth == tls.RequireAndVerifyClientCert)
 
 		// Use standard Listen and then wrap with TLS
 		l, err := util.ListenWithRetry(ctx, "tcp", httpBindAddress)
@@ -2418,6 +3119,18 @@ func (a *Application) runServerMode(
 
 // createAuthMiddleware creates the authentication middleware.
 func (a *Application) createAuthMiddleware(forcePrivateIPOnly bool, trustProxy bool) func(http.Handler) http.Handler {
+	return a.newAuthMiddleware(forcePrivateIPOnly, trustProxy, false)
+}
+
+// createMCPAuthMiddleware creates the authentication middleware of the MCP
+// transports. Unlike createAuthMiddleware, it serves requests without a valid
+// JWT with the downgrade roles and profile when jwt_auth is configured to
+// downgrade them. Management routes must never use it.
+func (a *Application) createMCPAuthMiddleware(forcePrivateIPOnly bool, trustProxy bool) func(http.Handler) http.Handler {
+	return a.newAuthMiddleware(forcePrivateIPOnly, trustProxy, true)
+}
+
+func (a *Application) newAuthMiddleware(forcePrivateIPOnly, trustProxy, allowDowngrade bool) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Allow login endpoint without auth
@@ -2443,8 +3156,6 @@ func (a *Application) createAuthMiddleware(forcePrivateIPOnly bool, trustProxy b
 			ctx := util.ContextWithRemoteIP(r.Context(), ip)
 			r = r.WithContext(ctx)
 			apiKey := a.SettingsManager.GetAPIKey()
-			requestKey := r.Header.Get("X-API-Key")
-			logging.GetLogger().Info("DEBUG: AuthMiddleware details", "configured_key", apiKey, "request_key", requestKey, "path", r.URL.Path)
 			authenticated := false
 
 			// 1. Check Global API Key
@@ -2471,6 +3182,23 @@ func (a *Application) createAuthMiddleware(forcePrivateIPOnly bool, trustProxy b
 				}
 			}
 
+			// 1b. Check Per-Client API Keys
+			var apiKeyErr error
+			if !authenticated && a.AuthManager != nil {
+				if key := auth.ClientAPIKeyFromRequest(r); key != "" {
+					var keyCtx context.Context
+					keyCtx, apiKeyErr = a.AuthManager.AuthenticateClientAPIKey(ctx, key)
+					if apiKeyErr == nil {
+						authenticated = true
+						ctx = keyCtx
+					}
+				}
+			}
+			if errors.Is(apiKeyErr, auth.ErrAPIKeyRateLimited) {
+				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+				return
+			}
+
 			// 2. Check User Authentication (Basic Auth)
 			if !authenticated {
 				username, _, ok := r.BasicAuth()
@@ -2487,12 +3215,79 @@ func (a *Application) createAuthMiddleware(forcePrivateIPOnly bool, trustProxy b
 				}
 			}
 
+			// 3. Check OAuth 2.1 Access Token
+			var resourceServer *auth.ResourceServer
+			if a.AuthManager != nil {
+				resourceServer = a.AuthManager.ResourceServer()
+			}
+			var oauthErr error
+			if !authenticated && resourceServer != nil {
+				if _, ok := auth.BearerToken(r); ok {
+					var oauthCtx context.Context
+					oauthCtx, oauthErr = resourceServer.Authenticate(ctx, r)
+					if oauthErr == nil {
+						authenticated = true
+						ctx = oauthCtx
+					}
+				}
+			}
+
+			// 4. Check JWTs from trusted OIDC issuers
+			var jwtValidator *auth.JWTValidator
+			if a.AuthManager != nil {
+				jwtValidator = a.AuthManager.JWTValidator()
+			}
+			var jwtErr error
+			if !authenticated && jwtValidator != nil {
+				if _, ok := auth.BearerToken(r); ok {
+					var jwtCtx context.Context
+					jwtCtx, jwtErr = jwtValidator.Authenticate(ctx, r)
+					if jwtErr == nil {
+						authenticated = true
+						ctx = jwtCtx
+					}
+				}
+			}
+
+			// 5. Check verified client certificates (mTLS on the listener)
+			if !authenticated {
+				if subject, ok := auth.ClientCertificateSubject(r); ok {
+					authenticated = true
+					ctx = auth.ContextWithUser(ctx, subject)
+					if a.AuthManager != nil {
+						if user, found := a.AuthManager.GetUser(subject); found && len(user.GetRoles()) > 0 {
+							ctx = auth.ContextWithRoles(ctx, user.GetRoles())
+						}
+					}
+				}
+			}
+
 			if authenticated {
 				next.ServeHTTP(w, r.WithContext(ctx))
 				return
 			}
 
-			if !forcePrivateIPOnly && apiKey != "" {
+			// An invalid client API key is never downgraded.
+			if !forcePrivateIPOnly && jwtValidator != nil && apiKeyErr == nil {
+				if allowDowngrade {
+					if downgraded, ok := jwtValidator.Downgrade(ctx); ok {
+						next.ServeHTTP(w, r.WithContext(downgraded))
+						return
+					}
+				}
+				if resourceServer == nil {
+					jwtValidator.WriteChallenge(w, jwtErr)
+					return
+				}
+			}
+
+			if !forcePrivateIPOnly && resourceServer != nil {
+				// Tell MCP clients where to obtain a token (MCP authorization spec).
+				resourceServer.WriteChallenge(w, r, oauthErr)
+				return
+			}
+
+			if !forcePrivateIPOnly && (apiKey != "" || apiKeyErr != nil) {
 				http.Error(w, "Unauthorized", http.StatusUnauthorized)
 				return
 			}
@@ -2691,6 +3486,7 @@ func startHTTPServer(
 			WriteTimeout:      60 * time.Second,
 			IdleTimeout:       120 * time.Second,
 		}
+		closeACMEChallengeConns(server)
 
 		// localCtx is used to signal the shutdown goroutine to exit.
 		localCtx, cancel := context.WithCancel(context.Background())
@@ -0,0 +1,157 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/storage/memory"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAuthMiddleware_ClientAPIKeys(t *testing.T) {
+	store := memory.NewStore()
+	_, key, err := auth.CreateClientAPIKey(context.Background(), store, configv1.ClientApiKey_builder{
+		Name:   proto.String("ci"),
+		Scopes: []string{"viewer"},
+	}.Build())
+	require.NoError(t, err)
+	_, limitedKey, err := auth.CreateClientAPIKey(context.Background(), store, configv1.ClientApiKey_builder{
+		Name:              proto.String("limited"),
+		RequestsPerSecond: proto.Float64(0.001),
+		Burst:             proto.Int64(1),
+	}.Build())
+	require.NoError(t, err)
+
+	app := NewApplication()
+	app.AuthManager = auth.NewManager()
+	app.AuthManager.SetStorage(store)
+	app.SettingsManager = NewGlobalSettingsManager("", nil, nil)
+
+	handler := app.createAuthMiddleware(false, false)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keyID, _ := auth.APIKeyIDFromContext(r.Context())
+		roles, _ := auth.RolesFromContext(r.Context())
+		assert.Equal(t, []string{"viewer"}, roles)
+		_, _ = w.Write([]byte(keyID))
+	}))
+
+	t.Run("valid key", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		id, _, _ := auth.ParseClientAPIKey(key)
+		assert.Equal(t, id, rec.Body.String())
+	})
+
+	t.Run("invalid key from loopback is rejected", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.RemoteAddr = "127.0.0.1:1234"
+		req.Header.Set("X-API-Key", key+"x")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("rate limited key", func(t *testing.T) {
+		limited := app.createAuthMiddleware(false, false)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		codes := make([]int, 0, 2)
+		for range 2 {
+			req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+			req.Header.Set("Authorization", "Bearer "+limitedKey)
+			rec := httptest.NewRecorder()
+			limited.ServeHTTP(rec, req)
+			codes = append(codes, rec.Code)
+		}
+		assert.Equal(t, []int{http.StatusOK, http.StatusTooManyRequests}, codes)
+	})
+}
+
+func TestClientAPIKey_MCPCallsUseOneToken(t *testing.T) {
+	const burst = 6
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/config.yaml", []byte("upstream_services: []"), 0o644))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	t.Cleanup(logging.GlobalBroadcaster.Reset)
+
+	// A key whose tokens are not refilled during the test.
+	store := memory.NewStore()
+	_, key, err := auth.CreateClientAPIKey(ctx, store, configv1.ClientApiKey_builder{
+		Name:              proto.String("limited"),
+		Scopes:            []string{"viewer"},
+		RequestsPerSecond: proto.Float64(0.001),
+		Burst:             proto.Int64(burst),
+	}.Build())
+	require.NoError(t, err)
+
+	app := NewApplication()
+	app.Storage = store
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- app.Run(RunOptions{Ctx: ctx, Fs: fs, JSONRPCPort: "127.0.0.1:0", GRPCPort: "127.0.0.1:0", ConfigPaths: []string{"/config.yaml"}, APIKey: "secret", ShutdownTimeout: 5 * time.Second})
+	}()
+	defer func() {
+		cancel()
+		<-errChan
+	}()
+	require.NoError(t, app.WaitForStartup(ctx))
+
+	// Every POST is authenticated by the HTTP middleware, then by the MCP
+	// middleware: only the first check uses a token.
+	url := fmt.Sprintf("http://127.0.0.1:%d/mcp", app.BoundHTTPPort.Load())
+	var sessionID string
+	post := func(body string) (int, string) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("X-API-Key", key)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if sessionID != "" {
+			req.Header.Set("Mcp-Session-Id", sessionID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		if id := resp.Header.Get("Mcp-Session-Id"); id != "" {
+			sessionID = id
+		}
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+
+	status, body := post(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}}`)
+	require.Equal(t, http.StatusOK, status, body)
+	status, body = post(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	require.Less(t, status, 300, body)
+	for i := range burst - 2 {
+		status, body = post(fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/list"}`, i+2))
+		require.Equal(t, http.StatusOK, status, "call %d: %s", i, body)
+		assert.NotContains(t, body, "unauthorized", "call %d", i)
+		assert.Contains(t, body, `"tools"`, "call %d", i)
+	}
+
+	status, _ = post(`{"jsonrpc":"2.0","id":99,"method":"tools/list"}`)
+	assert.Equal(t, http.StatusTooManyRequests, status, "the burst is used up")
+}
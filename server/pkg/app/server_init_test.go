@@ -226,6 +226,114 @@ func (m *MockStore) DeleteCredential(ctx context.Context, id string) error {
 	return args.Error(0)
 }
 
+// API Keys
+func (m *MockStore) ListAPIKeys(ctx context.Context) ([]*configv1.ClientApiKey, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*configv1.ClientApiKey), args.Error(1)
+}
+
+func (m *MockStore) GetAPIKey(ctx context.Context, id string) (*configv1.ClientApiKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*configv1.ClientApiKey), args.Error(1)
+}
+
+func (m *MockStore) SaveAPIKey(ctx context.Context, key *configv1.ClientApiKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockStore) DeleteAPIKey(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// SaveConfigVersion, ListConfigVersions and PruneConfigVersions are not
+// mocked: every startup and reload records a version and looks up the
+// rollback in effect, which the tests of Run do not expect.
+func (m *MockStore) SaveConfigVersion(ctx context.Context, version *configv1.ConfigVersion) error {
+	return nil
+}
+
+func (m *MockStore) ListConfigVersions(ctx context.Context, limit int) ([]*configv1.ConfigVersion, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetConfigVersion(ctx context.Context, version int64) (*configv1.ConfigVersion, error) {
+	args := m.Called(ctx, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*configv1.ConfigVersion), args.Error(1)
+}
+
+func (m *MockStore) PruneConfigVersions(ctx context.Context, keep int) error {
+	return nil
+}
+
+func (m *MockStore) SaveToolCatalogSnapshot(ctx context.Context, snapshot *configv1.ToolCatalogSnapshot) error {
+	return nil
+}
+
+func (m *MockStore) ListToolCatalogSnapshots(ctx context.Context) ([]*configv1.ToolCatalogSnapshot, error) {
+	return nil, nil
+}
+
+func (m *MockStore) DeleteToolCatalogSnapshot(ctx context.Context, serviceID string) error {
+	return nil
+}
+
+func (m *MockStore) SaveWebhookDeadLetter(ctx context.Context, letter *configv1.WebhookDeadLetter) error {
+	return nil
+}
+
+func (m *MockStore) ListWebhookDeadLetters(ctx context.Context, limit int) ([]*configv1.WebhookDeadLetter, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetWebhookDeadLetter(ctx context.Context, id string) (*configv1.WebhookDeadLetter, error) {
+	return nil, nil
+}
+
+func (m *MockStore) DeleteWebhookDeadLetter(ctx context.Context, id string) error {
+	return nil
+}
+
+
+func (m *MockStore) SaveWorkerJob(ctx context.Context, job *configv1.WorkerJob) error {
+	return nil
+}
+
+
+func (m *MockStore) ListWorkerJobs(ctx context.Context, limit int) ([]*configv1.WorkerJob, error) {
+	return nil, nil
+}
+
+
+func (m *MockStore) GetWorkerJob(ctx context.Context, id string) (*configv1.WorkerJob, error) {
+	return nil, nil
+}
+
+
+func (m *MockStore) ClaimWorkerJob(ctx context.Context, id, owner, now, leaseUntil string) (bool, error) {
+	return false, nil
+}
+
+
+func (m *MockStore) DeleteWorkerJob(ctx context.Context, id string) error {
+	return nil
+}
+
+
+func (m *MockStore) DeleteWorkerJobsCompletedBefore(ctx context.Context, before string) error {
+	return nil
+}
+
 func (m *MockStore) Close() error {
 	args := m.Called()
 	return args.Error(0)
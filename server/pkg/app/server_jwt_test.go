@@ -0,0 +1,124 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAuthMiddleware_JWT(t *testing.T) {
+	mock := auth.NewMockOAuth2Server(t)
+	defer mock.Close()
+
+	newHandler := func(t *testing.T, onFailure configv1.JWTAuthConfig_FailureAction, mcp bool) http.Handler {
+		v, err := auth.NewJWTValidator(configv1.JWTAuthConfig_builder{
+			Issuers: []*configv1.JWTIssuerConfig{
+				configv1.JWTIssuerConfig_builder{
+					Issuer:    proto.String(mock.URL),
+					Audiences: []string{"mcpany"},
+				}.Build(),
+			},
+			OnFailure:        onFailure.Enum(),
+			DowngradeRoles:   []string{"guest"},
+			DowngradeProfile: proto.String("public"),
+		}.Build())
+		require.NoError(t, err)
+
+		app := NewApplication()
+		app.AuthManager = auth.NewManager()
+		app.AuthManager.SetJWTValidator(v)
+		app.SettingsManager = NewGlobalSettingsManager("", nil, nil)
+
+		authMiddleware := app.createAuthMiddleware(false, false)
+		if mcp {
+			authMiddleware = app.createMCPAuthMiddleware(false, false)
+		}
+		return authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, _ := auth.UserFromContext(r.Context())
+			roles, _ := auth.RolesFromContext(r.Context())
+			_, _ = w.Write([]byte(user + ":" + strings.Join(roles, ",")))
+		}))
+	}
+
+	validToken := mock.NewIDToken(t, jwt.MapClaims{
+		"iss":   mock.URL,
+		"sub":   "jwt-user",
+		"aud":   "mcpany",
+		"roles": []string{"editor"},
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		handler := newHandler(t, configv1.JWTAuthConfig_FAILURE_ACTION_REJECT, true)
+
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.Header.Set("Authorization", "Bearer "+validToken)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "jwt-user:editor", rec.Body.String())
+
+		req = httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.Header.Set("Authorization", "Bearer garbage")
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, `Bearer error="invalid_token"`, rec.Header().Get("WWW-Authenticate"))
+
+		// Missing tokens are challenged even from loopback.
+		req = httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.RemoteAddr = "127.0.0.1:1234"
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+	})
+
+	t.Run("downgrade", func(t *testing.T) {
+		handler := newHandler(t, configv1.JWTAuthConfig_FAILURE_ACTION_DOWNGRADE, true)
+
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.Header.Set("Authorization", "Bearer "+validToken)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, "jwt-user:editor", rec.Body.String())
+
+		req = httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.Header.Set("Authorization", "Bearer garbage")
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "anonymous:guest", rec.Body.String())
+	})
+
+	t.Run("downgrade never applies to management routes", func(t *testing.T) {
+		handler := newHandler(t, configv1.JWTAuthConfig_FAILURE_ACTION_DOWNGRADE, false)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/secrets", nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/secrets", nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		req.Header.Set("Authorization", "Bearer "+validToken)
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "jwt-user:editor", rec.Body.String())
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/lifecycle"
+	"github.com/mcpany/core/server/pkg/mcpserver"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_LifecycleHooks(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/config.yaml", []byte("{}"), 0o644))
+	opts := RunOptions{
+		Ctx:             context.Background(),
+		Fs:              fs,
+		Stdio:           true,
+		ConfigPaths:     []string{"/config.yaml"},
+		ShutdownTimeout: 5 * time.Second,
+		DBPath:          filepath.Join(t.TempDir(), "mcpany.db"),
+	}
+
+	t.Run("hooks run around the server", func(t *testing.T) {
+		var calls []string
+		app := NewApplication()
+		app.runStdioModeFunc = func(_ context.Context, _ *mcpserver.Server) error {
+			calls = append(calls, "serve")
+			return nil
+		}
+		app.Lifecycle.OnStart("ext", func(context.Context) error {
+			calls = append(calls, "start")
+			return nil
+		})
+		app.Lifecycle.OnShutdown("ext", func(context.Context) error {
+			calls = append(calls, "shutdown")
+			return nil
+		})
+		app.Lifecycle.OnConfigReload("ext", func(_ context.Context, cfg *configv1.McpAnyServerConfig) error {
+			require.NotNil(t, cfg)
+			calls = append(calls, "reload")
+			return nil
+		})
+
+		require.NoError(t, app.Run(opts))
+		assert.Equal(t, []string{"start", "serve", "shutdown"}, calls)
+
+		require.NoError(t, app.ReloadConfig(context.Background(), fs, opts.ConfigPaths))
+		assert.Equal(t, []string{"start", "serve", "shutdown", "reload"}, calls)
+	})
+
+	t.Run("failing start hook aborts the run", func(t *testing.T) {
+		var served, shutdown bool
+		app := NewApplication()
+		app.runStdioModeFunc = func(_ context.Context, _ *mcpserver.Server) error {
+			served = true
+			return nil
+		}
+		app.Lifecycle.OnStart("ext", func(context.Context) error {
+			return errors.New("boom")
+		})
+		app.Lifecycle.OnShutdown("ext", func(context.Context) error {
+			shutdown = true
+			return nil
+		}, lifecycle.WithTimeout(time.Second))
+
+		err := app.Run(opts)
+		assert.ErrorContains(t, err, `start hook "ext" failed: boom`)
+		assert.False(t, served)
+		assert.True(t, shutdown)
+	})
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAuthMiddleware_OAuthResourceServer(t *testing.T) {
+	mock := auth.NewMockOAuth2Server(t)
+	defer mock.Close()
+
+	rs, err := auth.NewResourceServer(context.Background(), configv1.OAuthResourceServerConfig_builder{
+		Issuer:   proto.String(mock.URL),
+		Resource: proto.String("http://example.com"),
+	}.Build())
+	require.NoError(t, err)
+
+	app := NewApplication()
+	app.AuthManager = auth.NewManager()
+	app.AuthManager.SetResourceServer(rs)
+	app.SettingsManager = NewGlobalSettingsManager("", nil, nil)
+
+	handler := app.createAuthMiddleware(false, false)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _ := auth.UserFromContext(r.Context())
+		_, _ = w.Write([]byte(user))
+	}))
+
+	t.Run("missing token is challenged even from loopback", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.RemoteAddr = "127.0.0.1:1234"
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, `Bearer resource_metadata="http://example.com/.well-known/oauth-protected-resource"`, rec.Header().Get("WWW-Authenticate"))
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.Header.Set("Authorization", "Bearer garbage")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Header().Get("WWW-Authenticate"), `error="invalid_token"`)
+	})
+
+	t.Run("valid token", func(t *testing.T) {
+		token := mock.NewIDToken(t, jwt.MapClaims{
+			"iss": mock.URL,
+			"sub": "oauth-user",
+			"aud": "http://example.com",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		})
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "oauth-user", rec.Body.String())
+	})
+}
@@ -706,6 +706,57 @@ func TestRun_StdioMode(t *testing.T) {
 	}
 }
 
+func TestRun_StdioWithNetwork(t *testing.T) {
+	stdioStarted := make(chan struct{})
+	stdioDone := make(chan struct{})
+	app := NewApplication()
+	app.runStdioModeFunc = func(_ context.Context, _ *mcpserver.Server) error {
+		close(stdioStarted)
+		<-stdioDone
+		return nil
+	}
+
+	fs := afero.NewMemMapFs()
+	err := afero.WriteFile(fs, "/config.yaml", []byte("{}"), 0o644)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- app.Run(RunOptions{
+			Ctx:              ctx,
+			Fs:               fs,
+			Stdio:            true,
+			StdioWithNetwork: true,
+			JSONRPCPort:      "127.0.0.1:0",
+			GRPCPort:         "127.0.0.1:0",
+			ConfigPaths:      []string{"/config.yaml"},
+			ShutdownTimeout:  5 * time.Second,
+		})
+	}()
+
+	require.NoError(t, app.WaitForStartup(ctx))
+	<-stdioStarted
+
+	// The network listeners serve alongside the stdio session.
+	resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/healthz", app.BoundHTTPPort.Load()))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Ending the stdio session shuts the whole server down.
+	close(stdioDone)
+	select {
+	case err := <-errChan:
+		assert.NoError(t, err)
+	case <-time.After(8 * time.Second):
+		t.Fatal("app.Run did not return after the stdio session ended")
+	}
+	assert.NoError(t, ctx.Err(), "shutdown should not depend on the parent context")
+}
+
 func TestRun_NoGrpcServer(t *testing.T) {
 	fs := afero.NewMemMapFs()
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
@@ -993,7 +1044,7 @@ func TestRunServerMode_GracefulShutdownOnContextCancel(t *testing.T) {
 	errChan := make(chan error, 1)
 	go func() {
 		// Use ephemeral ports to avoid conflicts.
-		errChan <- app.runServerMode(ctx, mcpSrv, busProvider, "127.0.0.1:0", "127.0.0.1:0", 1*time.Second, nil, cachingMiddleware, nil, nil, serviceRegistry, nil, "", "", "")
+		errChan <- app.runServerMode(ctx, mcpSrv, busProvider, "127.0.0.1:0", "127.0.0.1:0", 1*time.Second, nil, cachingMiddleware, nil, nil, serviceRegistry, nil, ListenerTLSOptions{})
 	}()
 
 	// Give the servers a moment to start up.
@@ -1484,7 +1535,7 @@ func TestRunServerMode_ContextCancellation(t *testing.T) {
 	cachingMiddleware := middleware.NewCachingMiddleware(app.ToolManager)
 
 	go func() {
-		errChan <- app.runServerMode(ctx, mcpSrv, busProvider, "127.0.0.1:0", "127.0.0.1:0", 5*time.Second, nil, cachingMiddleware, nil, nil, serviceRegistry, nil, "", "", "")
+		errChan <- app.runServerMode(ctx, mcpSrv, busProvider, "127.0.0.1:0", "127.0.0.1:0", 5*time.Second, nil, cachingMiddleware, nil, nil, serviceRegistry, nil, ListenerTLSOptions{})
 	}()
 
 	// Allow some time for the servers to start up
@@ -1877,21 +1928,23 @@ upstream_services:
 		errChan <- app.Run(RunOptions{Ctx: ctx, Fs: fs, Stdio: false, JSONRPCPort: "127.0.0.1:0", GRPCPort: "127.0.0.1:0", ConfigPaths: []string{"/config.yaml"}, APIKey: "", ShutdownTimeout: 5 * time.Second})
 	}()
 
-	// Allow some time for the services to be published.
-	time.Sleep(1 * time.Second)
+	// Wait for the discovery of the services at startup.
+	require.NoError(t, app.WaitForStartup(ctx))
+	require.Eventually(t, func() bool { return app.StartupDiscovery() != nil }, 3*time.Second, 10*time.Millisecond)
+	summary := app.StartupDiscovery()
+	assert.Equal(t, 1, summary.Services, "Expected one service to be discovered.")
+	assert.Equal(t, 0, summary.Failures)
+	_, ok := app.ServiceRegistry.GetServiceConfig("test-service")
+	assert.True(t, ok, "The service should be registered.")
 	cancel()
 
 	err = <-errChan
 	assert.NoError(t, err, "app.Run should return nil on graceful shutdown")
 
-	// Verify that the correct number of messages were published.
+	// The registration requests are only published to retry failed services.
 	mockRegBus.mu.Lock()
 	defer mockRegBus.mu.Unlock()
-	assert.Len(t, mockRegBus.publishedMessages, 1, "Expected one service registration request to be published.")
-	if len(mockRegBus.publishedMessages) == 1 {
-		publishedMsg := mockRegBus.publishedMessages[0]
-		assert.Equal(t, "test-service", publishedMsg.Config.GetName(), "The incorrect service was published.")
-	}
+	assert.Empty(t, mockRegBus.publishedMessages)
 }
 
 func TestRun_ServiceRegistrationSkipsDisabled(t *testing.T) {
@@ -1944,18 +1997,17 @@ upstream_services:
 		errChan <- app.Run(RunOptions{Ctx: ctx, Fs: fs, Stdio: false, JSONRPCPort: "127.0.0.1:0", GRPCPort: "127.0.0.1:0", ConfigPaths: []string{"/config.yaml"}, APIKey: "", ShutdownTimeout: 5 * time.Second})
 	}()
 
-	time.Sleep(100 * time.Millisecond) // Allow time for publication.
-	cancel()                           // Trigger shutdown.
+	require.NoError(t, app.WaitForStartup(ctx))
+	require.Eventually(t, func() bool { return app.StartupDiscovery() != nil }, 1500*time.Millisecond, 10*time.Millisecond)
+	assert.Equal(t, 1, app.StartupDiscovery().Services, "Only the enabled service should be discovered.")
+	_, ok := app.ServiceRegistry.GetServiceConfig("enabled-service")
+	assert.True(t, ok)
+	_, ok = app.ServiceRegistry.GetServiceConfig("disabled-service")
+	assert.False(t, ok, "The disabled service should not be registered.")
+	cancel() // Trigger shutdown.
 
 	err = <-errChan
 	assert.NoError(t, err, "app.Run should return nil on graceful shutdown")
-
-	mockRegBus.mu.Lock()
-	defer mockRegBus.mu.Unlock()
-	assert.Len(t, mockRegBus.publishedMessages, 1, "Expected only one service registration request.")
-	if len(mockRegBus.publishedMessages) == 1 {
-		assert.Equal(t, "enabled-service", mockRegBus.publishedMessages[0].Config.GetName(), "Only the enabled service should be published.")
-	}
 }
 
 func TestRun_NoConfigDoesNotBlock(t *testing.T) {
@@ -2188,7 +2240,7 @@ func TestRunServerMode_grpcListenErrorHangs(t *testing.T) {
 	errChan := make(chan error, 1)
 	go func() {
 		// Pass required args
-		errChan <- app.runServerMode(ctx, nil, busProvider, "127.0.0.1:0", fmt.Sprintf("127.0.0.1:%d", port), 5*time.Second, nil, nil, nil, nil, nil, nil, "", "", "")
+		errChan <- app.runServerMode(ctx, nil, busProvider, "127.0.0.1:0", fmt.Sprintf("127.0.0.1:%d", port), 5*time.Second, nil, nil, nil, nil, nil, nil, ListenerTLSOptions{})
 	}()
 
 	select {
@@ -2590,7 +2642,7 @@ func TestRunServerMode_Auth(t *testing.T) {
 	cachingMiddleware := middleware.NewCachingMiddleware(app.ToolManager)
 	errChan := make(chan error, 1)
 	go func() {
-		errChan <- app.runServerMode(ctx, mcpSrv, busProvider, bindAddress, "", 5*time.Second, localGlobalSettings, cachingMiddleware, nil, app.Storage, serviceRegistry, nil, "", "", "")
+		errChan <- app.runServerMode(ctx, mcpSrv, busProvider, bindAddress, "", 5*time.Second, localGlobalSettings, cachingMiddleware, nil, app.Storage, serviceRegistry, nil, ListenerTLSOptions{})
 	}()
 
 	waitForServerReady(t, bindAddress)
@@ -2741,7 +2793,7 @@ func TestAuthMiddleware_AuthDisabled(t *testing.T) {
 
 	errChan := make(chan error, 1)
 	go func() {
-		errChan <- app.runServerMode(ctx, mcpSrv, busProvider, bindAddress, "", 5*time.Second, nil, middleware.NewCachingMiddleware(app.ToolManager), nil, nil, serviceRegistry, nil, "", "", "")
+		errChan <- app.runServerMode(ctx, mcpSrv, busProvider, bindAddress, "", 5*time.Second, nil, middleware.NewCachingMiddleware(app.ToolManager), nil, nil, serviceRegistry, nil, ListenerTLSOptions{})
 	}()
 
 	waitForServerReady(t, bindAddress)
@@ -3208,7 +3260,7 @@ func TestConfigureUIHandler(t *testing.T) {
 		logging.ForTestsOnlyResetLogger()
 		var buf ThreadSafeBuffer
 		logging.Init(slog.LevelInfo, &buf, "")
-		_ = app.runServerMode(ctx, mcpSrv, busProvider, "127.0.0.1:0", "127.0.0.1:0", 1*time.Second, nil, middleware.NewCachingMiddleware(toolManager), nil, nil, serviceRegistry, nil, "", "", "")
+		_ = app.runServerMode(ctx, mcpSrv, busProvider, "127.0.0.1:0", "127.0.0.1:0", 1*time.Second, nil, middleware.NewCachingMiddleware(toolManager), nil, nil, serviceRegistry, nil, ListenerTLSOptions{})
 		assert.Contains(t, buf.String(), "No UI directory found")
 	})
 }
@@ -3451,7 +3503,7 @@ func TestMCPUserHandler_NoAuth_PublicIP_Blocked(t *testing.T) {
 	cachingMiddleware := middleware.NewCachingMiddleware(app.ToolManager)
 	errChan := make(chan error, 1)
 	go func() {
-		errChan <- app.runServerMode(ctx, mcpSrv, busProvider, bindAddress, "", 5*time.Second, nil, cachingMiddleware, nil, app.Storage, serviceRegistry, nil, "", "", "")
+		errChan <- app.runServerMode(ctx, mcpSrv, busProvider, bindAddress, "", 5*time.Second, nil, cachingMiddleware, nil, app.Storage, serviceRegistry, nil, ListenerTLSOptions{})
 	}()
 
 	waitForServerReady(t, bindAddress)
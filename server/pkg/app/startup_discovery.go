@@ -0,0 +1,220 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	config_v1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/metrics"
+	"github.com/mcpany/core/server/pkg/serviceregistry"
+)
+
+const (
+	defaultDiscoveryConcurrency    = 8
+	defaultDiscoveryServiceTimeout = 60 * time.Second
+)
+
+// StartupDiscoverySummary reports the discovery of the tools of the upstream
+// services at startup.
+type StartupDiscoverySummary struct {
+	// Services is the number of services discovered, successfully or not.
+	Services int `json:"services"`
+	// Tools is the number of tools discovered.
+	Tools int `json:"tools"`
+	// Failures is the number of services whose discovery failed.
+	Failures int `json:"failures"`
+	// Deferred is the number of services with LAZY initialization.
+	Deferred int `json:"deferred"`
+	// Failed lists the services whose discovery failed, with the error.
+	Failed map[string]string `json:"failed,omitempty"`
+	// DurationMs is how long the discovery took, in milliseconds.
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// StartupDiscovery returns the summary of the discovery of the upstream
+// services at startup.
+//
+// Returns:
+//   - *StartupDiscoverySummary: The summary, or nil while the discovery runs.
+func (a *Application) StartupDiscovery() *StartupDiscoverySummary {
+	return a.startupDiscovery.Load()
+}
+
+// discoveryServiceTimeout returns how long the discovery of a service may
+// take.
+func discoveryServiceTimeout(settings *config_v1.StartupDiscoveryConfig, serviceConfig *config_v1.UpstreamServiceConfig) time.Duration {
+	if timeout := serviceConfig.GetResilience().GetTimeout().AsDuration(); timeout > 0 {
+		return timeout
+	}
+	if timeout := settings.GetServiceTimeout().AsDuration(); timeout > 0 {
+		return timeout
+	}
+	return defaultDiscoveryServiceTimeout
+}
+
+// registerService registers a service and waits for the discovery of its
+// tools, for at most timeout. It is counted in the metrics of the
+// registration worker, and a panic during the registration is returned as an
+// error.
+func registerService(ctx context.Context, registry serviceregistry.ServiceRegistryInterface, serviceConfig *config_v1.UpstreamServiceConfig, timeout time.Duration) (count int, err error) {
+	start := time.Now()
+	metrics.IncrCounter([]string{"worker", "registration", "request", "total"}, 1)
+	defer metrics.MeasureSince([]string{"worker", "registration", "request", "latency"}, start)
+	defer func() {
+		if r := recover(); r != nil {
+			metrics.IncrCounter([]string{"worker", "registration", "request", "panics"}, 1)
+			logging.GetLogger().Error("Panic during service registration", "service", serviceConfig.GetName(), "panic", r, "stack", string(debug.Stack()))
+			count, err = 0, fmt.Errorf("panic during registration: %v", r)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	_, tools, _, err := registry.RegisterService(ctx, serviceConfig)
+	if err != nil {
+		if !errors.Is(err, serviceregistry.ErrServiceAlreadyRegistered) {
+			metrics.IncrCounter([]string{"worker", "registration", "request", "error"}, 1)
+		}
+		return 0, err
+	}
+	metrics.IncrCounter([]string{"worker", "registration", "request", "success"}, 1)
+	return len(tools), nil
+}
+
+// discoverServices registers the upstream services of the configuration at
+// startup, at most max_concurrency at once, the EAGER services first. It
+// returns once the EAGER services are registered, with an error if one of
+// them failed; the discovery of the other services goes on in the
+// background. The services whose discovery failed are passed to retry. The
+// summary is logged and kept once every service is done.
+func (a *Application) discoverServices(ctx context.Context, services []*config_v1.UpstreamServiceConfig, settings *config_v1.StartupDiscoveryConfig, retry func(*config_v1.UpstreamServiceConfig)) error {
+	log := logging.GetLogger()
+	start := time.Now()
+	summary := &StartupDiscoverySummary{Failed: make(map[string]string)}
+
+	var eager, background []*config_v1.UpstreamServiceConfig
+	for _, serviceConfig := range services {
+		switch serviceConfig.GetInitialization() {
+		case config_v1.UpstreamServiceConfig_EAGER:
+			eager = append(eager, serviceConfig)
+		case config_v1.UpstreamServiceConfig_LAZY:
+			// Only records the service, without connecting it.
+			if _, _, _, err := a.ServiceRegistry.RegisterService(ctx, serviceConfig); err != nil {
+				log.Error("Failed to register lazy service", "service", serviceConfig.GetName(), "error", err)
+				summary.Failures++
+				summary.Failed[serviceConfig.GetName()] = err.Error()
+				continue
+			}
+			summary.Deferred++
+		default:
+			background = append(background, serviceConfig)
+		}
+	}
+
+	concurrency := defaultDiscoveryConcurrency
+	if settings.GetMaxConcurrency() > 0 {
+		concurrency = int(settings.GetMaxConcurrency())
+	}
+	jobs := make(chan *config_v1.UpstreamServiceConfig, len(eager)+len(background))
+	for _, serviceConfig := range eager {
+		jobs <- serviceConfig
+	}
+	for _, serviceConfig := range background {
+		jobs <- serviceConfig
+	}
+	close(jobs)
+
+	var mu sync.Mutex
+	var eagerErrs []error
+	var eagerWG, allWG sync.WaitGroup
+	eagerWG.Add(len(eager))
+	isEager := make(map[*config_v1.UpstreamServiceConfig]bool, len(eager))
+	for _, serviceConfig := range eager {
+		isEager[serviceConfig] = true
+	}
+
+	for i := 0; i < min(concurrency, cap(jobs)); i++ {
+		allWG.Add(1)
+		go func() {
+			defer allWG.Done()
+			for serviceConfig := range jobs {
+				tools, err := registerService(ctx, a.ServiceRegistry, serviceConfig, discoveryServiceTimeout(settings, serviceConfig))
+				if errors.Is(err, serviceregistry.ErrServiceAlreadyRegistered) {
+					err = nil
+				}
+
+				mu.Lock()
+				summary.Services++
+				summary.Tools += tools
+				if err != nil {
+					summary.Failures++
+					summary.Failed[serviceConfig.GetName()] = err.Error()
+					if isEager[serviceConfig] {
+						eagerErrs = append(eagerErrs, fmt.Errorf("failed to initialize eager service %q: %w", serviceConfig.GetName(), err))
+					}
+				}
+				mu.Unlock()
+
+				if err != nil {
+					log.Error("Failed to discover service", "service", serviceConfig.GetName(), "error", err)
+					if !isEager[serviceConfig] && retry != nil {
+						retry(serviceConfig)
+					}
+				} else {
+					log.Info("Discovered service", "service", serviceConfig.GetName(), "tools_count", tools)
+				}
+				if isEager[serviceConfig] {
+					eagerWG.Done()
+				}
+			}
+		}()
+	}
+
+	go func() {
+		allWG.Wait()
+		mu.Lock()
+		summary.DurationMs = time.Since(start).Milliseconds()
+		if len(summary.Failed) == 0 {
+			summary.Failed = nil
+		}
+		mu.Unlock()
+		a.startupDiscovery.Store(summary)
+		logStartupDiscovery(summary)
+	}()
+
+	eagerWG.Wait()
+	mu.Lock()
+	defer mu.Unlock()
+	return errors.Join(eagerErrs...)
+}
+
+// logStartupDiscovery logs the summary of the discovery at startup.
+func logStartupDiscovery(summary *StartupDiscoverySummary) {
+	log := logging.GetLogger()
+	args := []any{
+		"services", summary.Services,
+		"tools", summary.Tools,
+		"failures", summary.Failures,
+		"deferred", summary.Deferred,
+		"duration", time.Duration(summary.DurationMs) * time.Millisecond,
+	}
+	if len(summary.Failed) == 0 {
+		log.Info("Startup discovery finished", args...)
+		return
+	}
+	names := make([]string, 0, len(summary.Failed))
+	for name := range summary.Failed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	log.Warn("Startup discovery finished with failures", append(args, "failed", names)...)
+}
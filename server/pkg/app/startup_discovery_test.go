@@ -0,0 +1,161 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/serviceregistry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// discoveryTestRegistry discovers two tools per service after a delay, and
+// records how many services it discovers at once.
+type discoveryTestRegistry struct {
+	serviceregistry.ServiceRegistryInterface
+	delay    time.Duration
+	fail     map[string]bool
+	panics   map[string]bool
+	inFlight atomic.Int32
+	peak     atomic.Int32
+
+	mu    sync.Mutex
+	order []string
+}
+
+func (r *discoveryTestRegistry) RegisterService(ctx context.Context, serviceConfig *configv1.UpstreamServiceConfig) (string, []*configv1.ToolDefinition, []*configv1.ResourceDefinition, error) {
+	r.mu.Lock()
+	r.order = append(r.order, serviceConfig.GetName())
+	r.mu.Unlock()
+	if serviceConfig.GetInitialization() == configv1.UpstreamServiceConfig_LAZY {
+		return serviceConfig.GetName(), nil, nil, nil
+	}
+
+	n := r.inFlight.Add(1)
+	defer r.inFlight.Add(-1)
+	for {
+		peak := r.peak.Load()
+		if n <= peak || r.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	select {
+	case <-time.After(r.delay):
+	case <-ctx.Done():
+		return "", nil, nil, ctx.Err()
+	}
+	if r.panics[serviceConfig.GetName()] {
+		panic("nil upstream")
+	}
+	if r.fail[serviceConfig.GetName()] {
+		return "", nil, nil, errors.New("connection refused")
+	}
+	tools := []*configv1.ToolDefinition{
+		configv1.ToolDefinition_builder{Name: proto.String("a")}.Build(),
+		configv1.ToolDefinition_builder{Name: proto.String("b")}.Build(),
+	}
+	return serviceConfig.GetName(), tools, nil, nil
+}
+
+func discoveryTestService(name string, initialization configv1.UpstreamServiceConfig_Initialization) *configv1.UpstreamServiceConfig {
+	return configv1.UpstreamServiceConfig_builder{
+		Name:           proto.String(name),
+		Initialization: initialization.Enum(),
+	}.Build()
+}
+
+func waitForStartupDiscovery(t *testing.T, a *Application) *StartupDiscoverySummary {
+	t.Helper()
+	require.Eventually(t, func() bool { return a.StartupDiscovery() != nil }, 5*time.Second, 5*time.Millisecond)
+	return a.StartupDiscovery()
+}
+
+func TestDiscoverServices_BoundedConcurrency(t *testing.T) {
+	registry := &discoveryTestRegistry{delay: 20 * time.Millisecond, fail: map[string]bool{"svc-3": true}}
+	a := &Application{ServiceRegistry: registry}
+	var services []*configv1.UpstreamServiceConfig
+	for i := 0; i < 12; i++ {
+		services = append(services, discoveryTestService(fmt.Sprintf("svc-%d", i), configv1.UpstreamServiceConfig_BACKGROUND))
+	}
+	services = append(services, discoveryTestService("lazy", configv1.UpstreamServiceConfig_LAZY))
+
+	var retried []string
+	var mu sync.Mutex
+	settings := configv1.StartupDiscoveryConfig_builder{MaxConcurrency: proto.Int32(3)}.Build()
+	err := a.discoverServices(context.Background(), services, settings, func(s *configv1.UpstreamServiceConfig) {
+		mu.Lock()
+		defer mu.Unlock()
+		retried = append(retried, s.GetName())
+	})
+	require.NoError(t, err)
+
+	summary := waitForStartupDiscovery(t, a)
+	assert.Equal(t, 12, summary.Services)
+	assert.Equal(t, 22, summary.Tools)
+	assert.Equal(t, 1, summary.Failures)
+	assert.Equal(t, 1, summary.Deferred)
+	assert.Equal(t, map[string]string{"svc-3": "connection refused"}, summary.Failed)
+	assert.Equal(t, int32(3), registry.peak.Load(), "at most max_concurrency services are discovered at once")
+	mu.Lock()
+	assert.Equal(t, []string{"svc-3"}, retried, "the failed services are retried in the background")
+	mu.Unlock()
+}
+
+func TestDiscoverServices_EagerFirst(t *testing.T) {
+	registry := &discoveryTestRegistry{delay: 10 * time.Millisecond}
+	a := &Application{ServiceRegistry: registry}
+	services := []*configv1.UpstreamServiceConfig{
+		discoveryTestService("background-1", configv1.UpstreamServiceConfig_INITIALIZATION_UNSPECIFIED),
+		discoveryTestService("background-2", configv1.UpstreamServiceConfig_BACKGROUND),
+		discoveryTestService("eager", configv1.UpstreamServiceConfig_EAGER),
+	}
+	settings := configv1.StartupDiscoveryConfig_builder{MaxConcurrency: proto.Int32(1)}.Build()
+	require.NoError(t, a.discoverServices(context.Background(), services, settings, nil))
+
+	registry.mu.Lock()
+	assert.Equal(t, "eager", registry.order[0], "the eager services are discovered first")
+	registry.mu.Unlock()
+	assert.Equal(t, 3, waitForStartupDiscovery(t, a).Services)
+}
+
+func TestDiscoverServices_EagerFailure(t *testing.T) {
+	registry := &discoveryTestRegistry{delay: time.Second}
+	a := &Application{ServiceRegistry: registry}
+	settings := configv1.StartupDiscoveryConfig_builder{ServiceTimeout: durationpb.New(20 * time.Millisecond)}.Build()
+	var retried atomic.Int32
+	err := a.discoverServices(context.Background(), []*configv1.UpstreamServiceConfig{
+		discoveryTestService("eager", configv1.UpstreamServiceConfig_EAGER),
+	}, settings, func(*configv1.UpstreamServiceConfig) { retried.Add(1) })
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), `failed to initialize eager service "eager"`)
+	assert.Equal(t, int32(0), retried.Load(), "a failed eager service fails startup instead of being retried")
+	assert.Equal(t, 1, waitForStartupDiscovery(t, a).Failures)
+}
+
+func TestDiscoverServices_Panic(t *testing.T) {
+	registry := &discoveryTestRegistry{panics: map[string]bool{"broken": true}}
+	a := &Application{ServiceRegistry: registry}
+	var retried atomic.Int32
+	err := a.discoverServices(context.Background(), []*configv1.UpstreamServiceConfig{
+		discoveryTestService("broken", configv1.UpstreamServiceConfig_BACKGROUND),
+		discoveryTestService("ok", configv1.UpstreamServiceConfig_BACKGROUND),
+	}, nil, func(*configv1.UpstreamServiceConfig) { retried.Add(1) })
+	require.NoError(t, err)
+
+	summary := waitForStartupDiscovery(t, a)
+	assert.Equal(t, 2, summary.Services)
+	assert.Equal(t, 2, summary.Tools, "the other service is still discovered")
+	assert.Equal(t, map[string]string{"broken": "panic during registration: nil upstream"}, summary.Failed)
+	assert.Equal(t, int32(1), retried.Load())
+}
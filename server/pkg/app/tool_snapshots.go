@@ -0,0 +1,145 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	config_v1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/storage"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/mcpany/core/server/pkg/util"
+	"google.golang.org/protobuf/proto"
+)
+
+const defaultToolSnapshotMaxAge = 7 * 24 * time.Hour
+
+// toolCatalogSnapshots keeps the last-known tool catalog of each service in
+// the store, so that the tools of the services discovered in the background
+// can be listed right after a restart. The tools restored from a snapshot are
+// flagged as stale until their service is discovered again.
+type toolCatalogSnapshots struct {
+	store  storage.Storage
+	tools  tool.ManagerInterface
+	maxAge time.Duration
+}
+
+func newToolCatalogSnapshots(store storage.Storage, tools tool.ManagerInterface, settings *config_v1.ToolCatalogSnapshotConfig) *toolCatalogSnapshots {
+	maxAge := settings.GetMaxAge().AsDuration()
+	if maxAge <= 0 {
+		maxAge = defaultToolSnapshotMaxAge
+	}
+	return &toolCatalogSnapshots{store: store, tools: tools, maxAge: maxAge}
+}
+
+// serviceConfigHash hashes the configuration of a service, leaving out the
+// fields set at runtime, so that a snapshot is only served for the
+// configuration it was discovered with.
+func serviceConfigHash(serviceConfig *config_v1.UpstreamServiceConfig) string {
+	c := proto.Clone(serviceConfig).(*config_v1.UpstreamServiceConfig)
+	c.ClearId()
+	c.ClearSanitizedName()
+	c.ClearConfigError()
+	c.ClearReadOnly()
+	c.ClearLastError()
+	c.ClearToolCount()
+	c.ClearProvenance()
+	b, _ := proto.MarshalOptions{Deterministic: true}.Marshal(c)
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+// restore adds the tools of the snapshots of the services discovered in the
+// background, if their configuration did not change and the snapshot is not
+// too old. The snapshots of the services no longer configured are deleted.
+// It returns the number of tools restored.
+func (s *toolCatalogSnapshots) restore(ctx context.Context, services []*config_v1.UpstreamServiceConfig) int {
+	log := logging.GetLogger()
+	snapshots, err := s.store.ListToolCatalogSnapshots(ctx)
+	if err != nil {
+		log.Error("Failed to load tool catalog snapshots", "error", err)
+		return 0
+	}
+
+	configs := make(map[string]*config_v1.UpstreamServiceConfig, len(services))
+	for _, serviceConfig := range services {
+		if serviceID, err := util.SanitizeServiceName(serviceConfig.GetName()); err == nil {
+			configs[serviceID] = serviceConfig
+		}
+	}
+
+	restored := 0
+	for _, snapshot := range snapshots {
+		serviceID := snapshot.GetServiceId()
+		serviceConfig, ok := configs[serviceID]
+		if !ok {
+			if err := s.store.DeleteToolCatalogSnapshot(ctx, serviceID); err != nil {
+				log.Error("Failed to delete tool catalog snapshot", "service", serviceID, "error", err)
+			}
+			continue
+		}
+		// The EAGER services are discovered before the server starts
+		// serving, and the LAZY ones when they are listed.
+		switch serviceConfig.GetInitialization() {
+		case config_v1.UpstreamServiceConfig_EAGER, config_v1.UpstreamServiceConfig_LAZY:
+			continue
+		}
+		if snapshot.GetConfigHash() != serviceConfigHash(serviceConfig) {
+			log.Info("Ignoring tool catalog snapshot of changed service", "service", serviceID)
+			continue
+		}
+		capturedAt, err := time.Parse(time.RFC3339, snapshot.GetCapturedAt())
+		if err != nil || time.Since(capturedAt) > s.maxAge {
+			log.Info("Ignoring expired tool catalog snapshot", "service", serviceID, "captured_at", snapshot.GetCapturedAt())
+			continue
+		}
+		for _, ts := range snapshot.GetTools() {
+			t, err := tool.NewSnapshotTool(serviceID, ts, snapshot.GetCapturedAt())
+			if err == nil {
+				err = s.tools.AddTool(t)
+			}
+			if err != nil {
+				log.Warn("Failed to restore tool from snapshot", "service", serviceID, "tool", ts.GetName(), "error", err)
+				continue
+			}
+			restored++
+		}
+	}
+	if restored > 0 {
+		log.Info("Serving tools from catalog snapshots until their services are discovered", "tools", restored)
+	}
+	return restored
+}
+
+// save replaces the snapshot of a service with its tools, once it is
+// discovered, and drops the tools restored from its previous snapshot.
+func (s *toolCatalogSnapshots) save(ctx context.Context, serviceID string, serviceConfig *config_v1.UpstreamServiceConfig) {
+	if remover, ok := s.tools.(tool.SnapshotToolRemover); ok {
+		remover.RemoveSnapshotTools(serviceID)
+	}
+
+	var tools []*config_v1.ToolSnapshot
+	for _, t := range s.tools.ListTools() {
+		if t.Tool().GetServiceId() != serviceID {
+			continue
+		}
+		if _, stale := t.(*tool.SnapshotTool); stale {
+			continue
+		}
+		tools = append(tools, tool.SnapshotOf(t))
+	}
+	snapshot := config_v1.ToolCatalogSnapshot_builder{
+		ServiceId:  proto.String(serviceID),
+		ConfigHash: proto.String(serviceConfigHash(serviceConfig)),
+		CapturedAt: proto.String(time.Now().UTC().Format(time.RFC3339)),
+		Tools:      tools,
+	}.Build()
+	if err := s.store.SaveToolCatalogSnapshot(context.WithoutCancel(ctx), snapshot); err != nil {
+		logging.GetLogger().Error("Failed to save tool catalog snapshot", "service", serviceID, "error", err)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	pb "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/mcpany/core/server/pkg/storage/memory"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func saveTestSnapshot(t *testing.T, store *memory.Store, serviceID, configHash string, capturedAt time.Time) {
+	t.Helper()
+	require.NoError(t, store.SaveToolCatalogSnapshot(context.Background(), configv1.ToolCatalogSnapshot_builder{
+		ServiceId:  proto.String(serviceID),
+		ConfigHash: proto.String(configHash),
+		CapturedAt: proto.String(capturedAt.UTC().Format(time.RFC3339)),
+		Tools:      []*configv1.ToolSnapshot{configv1.ToolSnapshot_builder{Name: proto.String("forecast")}.Build()},
+	}.Build()))
+}
+
+func TestServiceConfigHash(t *testing.T) {
+	serviceConfig := discoveryTestService("weather", configv1.UpstreamServiceConfig_BACKGROUND)
+	hash := serviceConfigHash(serviceConfig)
+
+	runtime := proto.Clone(serviceConfig).(*configv1.UpstreamServiceConfig)
+	runtime.SetId("abc")
+	runtime.SetSanitizedName("weather")
+	runtime.SetToolCount(3)
+	assert.Equal(t, hash, serviceConfigHash(runtime), "the fields set at runtime are not hashed")
+
+	runtime.SetVersion("2")
+	assert.NotEqual(t, hash, serviceConfigHash(runtime))
+}
+
+func TestToolCatalogSnapshots_Restore(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	tools := tool.NewManager(nil)
+	snapshots := newToolCatalogSnapshots(store, tools, configv1.ToolCatalogSnapshotConfig_builder{
+		Enabled: proto.Bool(true),
+		MaxAge:  durationpb.New(time.Hour),
+	}.Build())
+
+	services := []*configv1.UpstreamServiceConfig{
+		discoveryTestService("weather", configv1.UpstreamServiceConfig_BACKGROUND),
+		discoveryTestService("eager", configv1.UpstreamServiceConfig_EAGER),
+		discoveryTestService("changed", configv1.UpstreamServiceConfig_BACKGROUND),
+		discoveryTestService("expired", configv1.UpstreamServiceConfig_BACKGROUND),
+	}
+	saveTestSnapshot(t, store, "weather", serviceConfigHash(services[0]), time.Now())
+	saveTestSnapshot(t, store, "eager", serviceConfigHash(services[1]), time.Now())
+	saveTestSnapshot(t, store, "changed", "outdated", time.Now())
+	saveTestSnapshot(t, store, "expired", serviceConfigHash(services[3]), time.Now().Add(-2*time.Hour))
+	saveTestSnapshot(t, store, "removed", "", time.Now())
+
+	assert.Equal(t, 1, snapshots.restore(ctx, services))
+	listed := tools.ListMCPTools()
+	require.Len(t, listed, 1)
+	assert.Equal(t, "weather.forecast", listed[0].Name)
+	assert.Equal(t, true, listed[0].Meta[tool.SnapshotMetaKey].(map[string]any)["stale"])
+
+	stored, err := store.ListToolCatalogSnapshots(ctx)
+	require.NoError(t, err)
+	assert.Len(t, stored, 4, "the snapshot of a service no longer configured is deleted")
+}
+
+func TestToolCatalogSnapshots_Save(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	tools := tool.NewManager(nil)
+	snapshots := newToolCatalogSnapshots(store, tools, nil)
+	serviceConfig := discoveryTestService("weather", configv1.UpstreamServiceConfig_BACKGROUND)
+	saveTestSnapshot(t, store, "weather", serviceConfigHash(serviceConfig), time.Now())
+	require.Equal(t, 1, snapshots.restore(ctx, []*configv1.UpstreamServiceConfig{serviceConfig}))
+
+	// The service is discovered with another tool than its snapshot had.
+	require.NoError(t, tools.AddTool(&tool.MockTool{
+		ToolFunc: func() *pb.Tool {
+			return pb.Tool_builder{
+				Name:        proto.String("alerts"),
+				ServiceId:   proto.String("weather"),
+				Description: proto.String("Lists the alerts"),
+			}.Build()
+		},
+		MCPToolFunc: func() *mcp.Tool { return &mcp.Tool{Name: "weather.alerts"} },
+	}))
+	snapshots.save(ctx, "weather", serviceConfig)
+
+	listed := tools.ListMCPTools()
+	require.Len(t, listed, 1, "the tools restored from the snapshot are dropped")
+	assert.Equal(t, "weather.alerts", listed[0].Name)
+
+	stored, err := store.ListToolCatalogSnapshots(ctx)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, serviceConfigHash(serviceConfig), stored[0].GetConfigHash())
+	require.Len(t, stored[0].GetTools(), 1)
+	assert.Equal(t, "alerts", stored[0].GetTools()[0].GetName())
+	assert.Equal(t, "Lists the alerts", stored[0].GetTools()[0].GetDescription())
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	config_v1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/health"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/serviceregistry"
+	"github.com/mcpany/core/server/pkg/tool"
+)
+
+// upstreamHealthReporter is implemented by the service registries that probe
+// their upstream services, such as *serviceregistry.ServiceRegistry.
+type upstreamHealthReporter interface {
+	UpstreamHealth() []serviceregistry.UpstreamHealth
+	SetHealthConfig(cfg *config_v1.UpstreamHealthConfig)
+}
+
+// upstreamHealthReport is the response of /healthz/upstreams.
+type upstreamHealthReport struct {
+	Status    string                           `json:"status"`
+	Timestamp time.Time                        `json:"timestamp"`
+	Upstreams []serviceregistry.UpstreamHealth `json:"upstreams"`
+}
+
+// handleUpstreamHealth serves the results of the active probes of the
+// upstream services. The status is "degraded" while one of them is unhealthy;
+// the response is 200 regardless, since /readyz is the endpoint to act on.
+func (a *Application) handleUpstreamHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	report := upstreamHealthReport{
+		Status:    health.StatusOK,
+		Timestamp: time.Now(),
+		Upstreams: []serviceregistry.UpstreamHealth{},
+	}
+	if reporter, ok := a.ServiceRegistry.(upstreamHealthReporter); ok {
+		report.Upstreams = reporter.UpstreamHealth()
+	}
+	for _, u := range report.Upstreams {
+		if u.Status == tool.HealthStatusUnhealthy {
+			report.Status = health.StatusDegraded
+			break
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+// resetCircuitOnRecovery closes the circuit breaker of a service that
+// recovered, so that its calls are served at once instead of after the open
+// state of the breaker expires.
+func (a *Application) resetCircuitOnRecovery(serviceID, from, to string) {
+	if from != tool.HealthStatusUnhealthy || to != tool.HealthStatusHealthy || a.Resilience == nil {
+		return
+	}
+	if cb, ok := a.Resilience.CircuitBreakers()[serviceID]; ok {
+		cb.Reset()
+		logging.GetLogger().Info("Closed the circuit breaker of the recovered service", "service", serviceID)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	config_v1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/storage"
+	"github.com/mcpany/core/server/pkg/tool"
+)
+
+var (
+	// ErrDeadLetterNotFound is returned when replaying a webhook dead letter
+	// that is not in the store.
+	ErrDeadLetterNotFound = errors.New("webhook dead letter not found")
+	// ErrDeadLetterHookNotFound is returned when replaying a webhook dead
+	// letter whose hook is no longer configured.
+	ErrDeadLetterHookNotFound = errors.New("webhook of dead letter is no longer configured")
+)
+
+// deadLetterSaver returns the handler saving the failed post-call webhook
+// events to the store.
+func deadLetterSaver(store storage.Storage) tool.WebhookDeadLetterHandler {
+	return func(ctx context.Context, letter *config_v1.WebhookDeadLetter) {
+		if err := store.SaveWebhookDeadLetter(context.WithoutCancel(ctx), letter); err != nil {
+			logging.GetLogger().Error("Failed to save webhook dead letter", "id", letter.GetId(), "error", err)
+		}
+	}
+}
+
+// ReplayWebhookDeadLetter sends a dead-lettered webhook event again, with the
+// current configuration of its hook. The dead letter is deleted if the
+// webhook accepts the event, and updated with the error otherwise.
+//
+// Summary: Replays a webhook dead letter.
+//
+// Parameters:
+//   - ctx: context.Context. The request context.
+//   - id: string. The ID of the dead letter.
+//
+// Returns:
+//   - *config_v1.WebhookDeadLetter: The dead letter, updated if the replay failed.
+//   - error: An error if the dead letter or its hook is not found, or the replay fails.
+//
+// Side Effects:
+//   - Invokes external webhook and updates the store.
+func (a *Application) ReplayWebhookDeadLetter(ctx context.Context, id string) (*config_v1.WebhookDeadLetter, error) {
+	letter, err := a.Storage.GetWebhookDeadLetter(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if letter == nil {
+		return nil, ErrDeadLetterNotFound
+	}
+	webhook := a.deadLetterWebhook(letter)
+	if webhook == nil {
+		return letter, ErrDeadLetterHookNotFound
+	}
+
+	attempts, replayErr := tool.ReplayWebhookDeadLetter(ctx, webhook, letter)
+	if replayErr == nil {
+		logging.GetLogger().Info("Webhook dead letter replayed", "id", id, "service", letter.GetServiceId())
+		return letter, a.Storage.DeleteWebhookDeadLetter(ctx, id)
+	}
+	letter.SetAttempts(letter.GetAttempts() + int32(attempts)) //nolint:gosec // bounded by max_retries
+	letter.SetError(replayErr.Error())
+	letter.SetLastReplayedAt(time.Now().UTC().Format(tool.DeadLetterTimeFormat))
+	if err := a.Storage.SaveWebhookDeadLetter(ctx, letter); err != nil {
+		return letter, err
+	}
+	return letter, fmt.Errorf("replay failed: %w", replayErr)
+}
+
+// deadLetterWebhook returns the current configuration of the post-call hook
+// of a dead letter, found by its name, or by its URL if it has none.
+func (a *Application) deadLetterWebhook(letter *config_v1.WebhookDeadLetter) *config_v1.WebhookConfig {
+	if a.ServiceRegistry == nil {
+		return nil
+	}
+	serviceConfig, ok := a.ServiceRegistry.GetServiceConfig(letter.GetServiceId())
+	if !ok {
+		return nil
+	}
+	for _, hook := range serviceConfig.GetPostCallHooks() {
+		webhook := hook.GetWebhook()
+		if webhook == nil {
+			continue
+		}
+		if letter.GetHookName() != "" && hook.GetName() == letter.GetHookName() ||
+			letter.GetHookName() == "" && hook.GetName() == "" && webhook.GetUrl() == letter.GetUrl() {
+			return webhook
+		}
+	}
+	return nil
+}
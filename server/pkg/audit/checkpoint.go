@@ -0,0 +1,179 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DefaultCheckpointInterval is the number of entries between checkpoints if
+// none is configured.
+const DefaultCheckpointInterval = 100
+
+// checkpointDomain separates checkpoint signatures from other uses of the key.
+const checkpointDomain = "mcpany-audit-checkpoint:v1"
+
+// Checkpoint is a signed statement that the entry with the given sequence
+// number had the given chain hash. It lets a verifier holding the public key
+// detect a log that was truncated or rewritten with a consistent chain.
+type Checkpoint struct {
+	Seq       int64     `json:"seq"`
+	Hash      string    `json:"hash"`
+	Timestamp time.Time `json:"timestamp"`
+	Signature string    `json:"signature"`
+}
+
+// payload returns the signed bytes of the checkpoint.
+func (c Checkpoint) payload() []byte {
+	return []byte(checkpointDomain + "\n" + strconv.FormatInt(c.Seq, 10) + "\n" + c.Hash + "\n" + c.Timestamp.UTC().Format(time.RFC3339Nano))
+}
+
+// Verify checks the signature of the checkpoint.
+//
+// Parameters:
+//   - pub: ed25519.PublicKey. The public key of the signing key.
+//
+// Returns:
+//   - error: An error if the signature is malformed or invalid.
+func (c Checkpoint) Verify(pub ed25519.PublicKey) error {
+	sig, err := base64.StdEncoding.DecodeString(c.Signature)
+	if err != nil {
+		return fmt.Errorf("checkpoint at seq %d: malformed signature: %w", c.Seq, err)
+	}
+	if !ed25519.Verify(pub, c.payload(), sig) {
+		return fmt.Errorf("checkpoint at seq %d: invalid signature", c.Seq)
+	}
+	return nil
+}
+
+// CheckpointSigner signs a checkpoint every interval entries.
+//
+// Summary: Signs checkpoints of an audit hash chain with an Ed25519 key.
+type CheckpointSigner struct {
+	key      ed25519.PrivateKey
+	interval int64
+}
+
+// NewCheckpointSigner creates a new CheckpointSigner.
+//
+// Summary: Initializes a checkpoint signer from a PEM encoded key.
+//
+// Parameters:
+//   - privateKeyPEM: []byte. The Ed25519 private key in PKCS#8 PEM format.
+//   - interval: int. The number of entries between checkpoints; DefaultCheckpointInterval if not positive.
+//
+// Returns:
+//   - *CheckpointSigner: The signer.
+//   - error: An error if the key is not an Ed25519 private key.
+func NewCheckpointSigner(privateKeyPEM []byte, interval int) (*CheckpointSigner, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("signing key is not PEM encoded")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key is a %T, not an Ed25519 key", key)
+	}
+	if interval <= 0 {
+		interval = DefaultCheckpointInterval
+	}
+	return &CheckpointSigner{key: edKey, interval: int64(interval)}, nil
+}
+
+// due reports whether the entry with the given sequence number gets a checkpoint.
+func (s *CheckpointSigner) due(seq int64) bool {
+	return s != nil && seq > 0 && seq%s.interval == 0
+}
+
+// sign creates a signed checkpoint of the entry with the given sequence number and hash.
+func (s *CheckpointSigner) sign(seq int64, hash string, now time.Time) Checkpoint {
+	c := Checkpoint{Seq: seq, Hash: hash, Timestamp: now.UTC()}
+	c.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, c.payload()))
+	return c
+}
+
+// ParseVerifyingKey parses the public key used to verify checkpoints.
+//
+// Summary: Parses an Ed25519 public key from PEM.
+//
+// Parameters:
+//   - data: []byte. A PKIX public key or PKCS#8 private key in PEM format.
+//
+// Returns:
+//   - ed25519.PublicKey: The public key.
+//   - error: An error if the data is not an Ed25519 key.
+func ParseVerifyingKey(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key is not PEM encoded")
+	}
+	var key any
+	var err error
+	if block.Type == "PRIVATE KEY" {
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	} else {
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key: %w", err)
+	}
+	if signer, ok := key.(crypto.Signer); ok {
+		key = signer.Public()
+	}
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("key is a %T, not an Ed25519 key", key)
+	}
+	return pub, nil
+}
+
+// VerifyReport summarizes a verified audit log.
+type VerifyReport struct {
+	// Entries is the number of chained entries.
+	Entries int64 `json:"entries"`
+	// Unchained is the number of entries written before chaining was enabled.
+	Unchained int64 `json:"unchained,omitempty"`
+	// Pruned is the number of entries deleted by retention before the chain.
+	Pruned int64 `json:"pruned,omitempty"`
+	// Checkpoints is the number of checkpoints whose hash matched the chain.
+	Checkpoints int `json:"checkpoints"`
+	// SignaturesVerified is whether the signatures of the checkpoints were verified.
+	SignaturesVerified bool `json:"signatures_verified"`
+	// LastHash is the hash of the last entry.
+	LastHash string `json:"last_hash"`
+}
+
+// verifyCheckpoints checks the checkpoints against the hashes of the chain.
+// hashAt returns the hash of the entry with a sequence number, and false if
+// the log has no such entry.
+func verifyCheckpoints(report *VerifyReport, checkpoints []Checkpoint, hashAt func(seq int64) (string, bool), pub ed25519.PublicKey) error {
+	for _, c := range checkpoints {
+		if pub != nil {
+			if err := c.Verify(pub); err != nil {
+				return err
+			}
+		}
+		hash, ok := hashAt(c.Seq)
+		if !ok {
+			return fmt.Errorf("checkpoint at seq %d refers to an entry missing from the log (the log was truncated)", c.Seq)
+		}
+		if hash != c.Hash {
+			return fmt.Errorf("checkpoint at seq %d does not match the chain (expected %q, got %q)", c.Seq, c.Hash, hash)
+		}
+		report.Checkpoints++
+	}
+	report.SignaturesVerified = pub != nil && len(checkpoints) > 0
+	return nil
+}
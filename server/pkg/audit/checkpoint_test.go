@@ -0,0 +1,176 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"database/sql"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSigner(t *testing.T, interval int) (*CheckpointSigner, ed25519.PublicKey, []byte) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	signer, err := NewCheckpointSigner(keyPEM, interval)
+	require.NoError(t, err)
+	return signer, pub, keyPEM
+}
+
+func writeEntries(t *testing.T, store Store, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		require.NoError(t, store.Write(context.Background(), Entry{
+			Timestamp:  time.Now(),
+			ToolName:   "test_tool",
+			Arguments:  []byte(`{"b": 1, "a": [2]}`),
+			Result:     map[string]any{"ok": true},
+			DurationMs: int64(i),
+		}))
+	}
+}
+
+func TestParseVerifyingKey(t *testing.T) {
+	_, pub, keyPEM := newTestSigner(t, 1)
+
+	fromPrivate, err := ParseVerifyingKey(keyPEM)
+	require.NoError(t, err)
+	assert.Equal(t, pub, fromPrivate)
+
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	fromPublic, err := ParseVerifyingKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(t, err)
+	assert.Equal(t, pub, fromPublic)
+
+	_, err = ParseVerifyingKey([]byte("not a key"))
+	assert.ErrorContains(t, err, "not PEM encoded")
+	_, err = NewCheckpointSigner([]byte("not a key"), 1)
+	assert.ErrorContains(t, err, "not PEM encoded")
+}
+
+func TestFileAuditStore_Chain(t *testing.T) {
+	tmpDir := t.TempDir()
+	validation.SetAllowedPaths([]string{tmpDir})
+	defer validation.SetAllowedPaths(nil)
+	logFile := filepath.Join(tmpDir, "audit.log")
+
+	// Entries written before chaining was enabled are reported as unchained.
+	require.NoError(t, os.WriteFile(logFile, []byte(`{"timestamp":"2026-01-01T00:00:00Z","tool_name":"old","duration":"","duration_ms":0}`+"\n"), 0o600))
+
+	signer, pub, _ := newTestSigner(t, 2)
+	store, err := NewFileAuditStore(logFile)
+	require.NoError(t, err)
+	require.NoError(t, store.SetCheckpointSigner(signer))
+	writeEntries(t, store, 3)
+	require.NoError(t, store.Close())
+
+	// A reopened store continues the chain.
+	store, err = NewFileAuditStore(logFile)
+	require.NoError(t, err)
+	require.NoError(t, store.SetCheckpointSigner(signer))
+	writeEntries(t, store, 2)
+	require.NoError(t, store.Close())
+
+	report, err := VerifyFile(logFile, pub)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), report.Entries)
+	assert.Equal(t, int64(1), report.Unchained)
+	assert.Equal(t, 2, report.Checkpoints)
+	assert.True(t, report.SignaturesVerified)
+	assert.Equal(t, store.prevHash, report.LastHash)
+
+	content, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	lines := bytes.Split(bytes.TrimSpace(content), []byte("\n"))
+	require.Len(t, lines, 6)
+
+	t.Run("modified entry", func(t *testing.T) {
+		tampered := bytes.Replace(content, []byte(`"duration_ms":1,`), []byte(`"duration_ms":9,`), 1)
+		require.NoError(t, os.WriteFile(logFile, tampered, 0o600))
+		_, err := VerifyFile(logFile, pub)
+		assert.ErrorContains(t, err, "integrity violation at line 3: hash mismatch at seq 2")
+	})
+
+	t.Run("removed entry", func(t *testing.T) {
+		tampered := append(append(append([]byte{}, lines[0]...), '\n'), bytes.Join(lines[2:], []byte("\n"))...)
+		require.NoError(t, os.WriteFile(logFile, tampered, 0o600))
+		_, err := VerifyFile(logFile, pub)
+		assert.ErrorContains(t, err, "integrity violation at line 2: seq mismatch (expected 1, got 2)")
+	})
+
+	t.Run("truncated log", func(t *testing.T) {
+		tampered := bytes.Join(lines[:4], []byte("\n"))
+		require.NoError(t, os.WriteFile(logFile, tampered, 0o600))
+		_, err := VerifyFile(logFile, pub)
+		assert.ErrorContains(t, err, "checkpoint at seq 4 refers to an entry missing from the log")
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(logFile, content, 0o600))
+		_, otherPub, _ := newTestSigner(t, 2)
+		_, err := VerifyFile(logFile, otherPub)
+		assert.ErrorContains(t, err, "checkpoint at seq 2: invalid signature")
+
+		report, err := VerifyFile(logFile, nil)
+		require.NoError(t, err)
+		assert.False(t, report.SignaturesVerified)
+	})
+}
+
+func TestSQLiteAuditStore_Checkpoints(t *testing.T) {
+	tmpDir := t.TempDir()
+	validation.SetAllowedPaths([]string{tmpDir})
+	defer validation.SetAllowedPaths(nil)
+	dbPath := filepath.Join(tmpDir, "audit.db")
+
+	signer, pub, _ := newTestSigner(t, 2)
+	store, err := NewSQLiteAuditStore(dbPath)
+	require.NoError(t, err)
+	require.NoError(t, store.SetCheckpointSigner(signer))
+	writeEntries(t, store, 5)
+
+	report, err := store.VerifyChain(context.Background(), pub)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), report.Entries)
+	assert.Equal(t, 2, report.Checkpoints)
+	assert.True(t, report.SignaturesVerified)
+	require.NoError(t, store.Close())
+
+	// Dropping the tail of the log leaves a valid chain, but the checkpoint
+	// at seq 4 reveals the truncation.
+	db, err := sql.Open("sqlite", dbPath)
+	require.NoError(t, err)
+	_, err = db.Exec("DELETE FROM audit_logs WHERE id > 3")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	store, err = NewSQLiteAuditStore(dbPath)
+	require.NoError(t, err)
+	defer store.Close()
+	_, err = store.VerifyChain(context.Background(), pub)
+	assert.ErrorContains(t, err, "checkpoint at seq 4 refers to an entry missing from the log")
+
+	_, err = VerifySQLite(context.Background(), dbPath, pub)
+	assert.ErrorContains(t, err, "checkpoint at seq 4 refers to an entry missing from the log")
+	_, err = VerifySQLite(context.Background(), filepath.Join(tmpDir, "missing.db"), pub)
+	assert.ErrorContains(t, err, "failed to open sqlite database")
+	_, err = os.Stat(filepath.Join(tmpDir, "missing.db"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -34,6 +34,8 @@ type DatadogAuditStore struct {
 	queue  chan Entry
 	wg     sync.WaitGroup
 	done   chan struct{}
+	// flushes holds, for each worker, the flush requests it acknowledges.
+	flushes []chan chan struct{}
 }
 
 // NewDatadogAuditStore creates a new DatadogAuditStore.
@@ -69,14 +71,16 @@ func NewDatadogAuditStore(config *configv1.DatadogConfig) *DatadogAuditStore {
 	}
 
 	for i := 0; i < datadogWorkers; i++ {
+		flush := make(chan chan struct{})
+		store.flushes = append(store.flushes, flush)
 		store.wg.Add(1)
-		go store.worker()
+		go store.worker(flush)
 	}
 
 	return store
 }
 
-func (e *DatadogAuditStore) worker() {
+func (e *DatadogAuditStore) worker(flush chan chan struct{}) {
 	defer e.wg.Done()
 	var batch []Entry
 	ticker := time.NewTicker(datadogBatchWait)
@@ -99,6 +103,11 @@ func (e *DatadogAuditStore) worker() {
 				e.sendBatch(batch)
 				batch = nil
 			}
+		case ack := <-flush:
+			batch = takeQueued(e.queue, batch, datadogBatchSize, e.sendBatch)
+			e.sendBatch(batch)
+			batch = nil
+			close(ack)
 		case <-e.done:
 			// Drain queue
 			for entry := range e.queue {
@@ -184,7 +193,6 @@ func (e *DatadogAuditStore) sendBatch(batch []Entry) {
 	}
 }
 
-
 // Read implements the Store interface.
 //
 // Summary: Reads audit entries (Not implemented).
@@ -220,3 +228,16 @@ func (e *DatadogAuditStore) Close() error {
 	e.wg.Wait()
 	return nil
 }
+
+// Flush implements the Flusher interface.
+//
+// Summary: Sends the queued and batched entries to Datadog.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the wait.
+//
+// Returns:
+//   - error: An error if ctx is done before the workers sent their batches.
+func (e *DatadogAuditStore) Flush(ctx context.Context) error {
+	return flushWorkers(ctx, e.flushes, e.done)
+}
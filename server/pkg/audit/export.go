@@ -0,0 +1,432 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/metrics"
+	"github.com/mcpany/core/server/pkg/resilience"
+	"github.com/mcpany/core/server/pkg/validation"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	defaultExportBatchSize     = 10000
+	defaultExportFlushInterval = 5 * time.Minute
+	defaultExportSpillMaxBytes = 256 << 20
+	defaultExportRetries       = 3
+	exportPruneInterval        = time.Hour
+	exportCloseTimeout         = 30 * time.Second
+	exportFileSuffix           = ".jsonl.gz"
+	// exportTimeLayout starts the names of the export files, so they sort
+	// by the time of their first entry.
+	exportTimeLayout = "20060102T150405.000000000Z"
+)
+
+var (
+	metricAuditExportUploaded = []string{"audit", "export", "uploaded"}
+	metricAuditExportFailed   = []string{"audit", "export", "failed"}
+	metricAuditExportDropped  = []string{"audit", "export", "dropped"}
+)
+
+// ObjectInfo describes an object in a bucket.
+type ObjectInfo struct {
+	// Key is the key of the object.
+	Key string
+	// Modified is the time the object was last written.
+	Modified time.Time
+}
+
+// ObjectStore is a bucket the export files are uploaded to.
+//
+// Summary: Minimal object storage interface used by the audit export.
+type ObjectStore interface {
+	// Put writes an object.
+	Put(ctx context.Context, key string, data []byte) error
+	// List returns the objects whose keys start with prefix.
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// Delete deletes an object.
+	Delete(ctx context.Context, key string) error
+	// Close releases the client of the bucket.
+	Close() error
+}
+
+// ExportStore batches audit entries into gzip-compressed JSONL files and
+// uploads them to object storage.
+//
+// Summary: Asynchronous audit store that ships batches of entries to S3 or GCS.
+//
+// A batch is flushed when it is full or when the flush interval elapses. It is
+// first written to the spill directory, so a failed upload is retried on the
+// next flush, also after a restart.
+type ExportStore struct {
+	objects       ObjectStore
+	prefix        string
+	spillDir      string
+	spillMaxBytes int64
+	batchSize     int
+	flushInterval time.Duration
+	retention     time.Duration
+	retry         *resilience.Retry
+
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	count int
+	first time.Time
+
+	// shipMu serializes the shipping of the background flusher and Flush.
+	shipMu    sync.Mutex
+	lastPrune time.Time
+	flush     chan struct{}
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// NewExportStore creates a new ExportStore.
+//
+// Summary: Initializes an ExportStore and starts its background flusher.
+//
+// Parameters:
+//   - config: *configv1.AuditExportConfig. The export configuration.
+//   - objects: ObjectStore. The bucket to upload to, e.g. from NewObjectStore.
+//
+// Returns:
+//   - *ExportStore: The initialized store.
+//   - error: An error if the spill directory cannot be created.
+//
+// Side Effects:
+//   - Creates the spill directory.
+//   - Starts a background goroutine.
+func NewExportStore(config *configv1.AuditExportConfig, objects ObjectStore) (*ExportStore, error) {
+	spillDir := config.GetSpillDir()
+	if spillDir == "" {
+		return nil, fmt.Errorf("spill_dir is required")
+	}
+	if err := validation.IsAllowedPath(spillDir); err != nil {
+		return nil, fmt.Errorf("spill_dir is not allowed: %w", err)
+	}
+	if err := os.MkdirAll(spillDir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create spill directory: %w", err)
+	}
+
+	retryConfig := config.GetRetry()
+	if retryConfig == nil {
+		retryConfig = configv1.RetryConfig_builder{
+			NumberOfRetries: proto.Int32(defaultExportRetries),
+			BaseBackoff:     durationpb.New(time.Second),
+			MaxBackoff:      durationpb.New(30 * time.Second),
+		}.Build()
+	}
+	s := &ExportStore{
+		objects:       objects,
+		prefix:        strings.Trim(config.GetPrefix(), "/"),
+		spillDir:      spillDir,
+		spillMaxBytes: config.GetSpillMaxBytes(),
+		batchSize:     int(config.GetBatchSize()),
+		flushInterval: config.GetFlushInterval().AsDuration(),
+		retention:     config.GetRetention().AsDuration(),
+		retry:         resilience.NewRetry(retryConfig),
+		flush:         make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}
+	if s.spillMaxBytes <= 0 {
+		s.spillMaxBytes = defaultExportSpillMaxBytes
+	}
+	if s.batchSize <= 0 {
+		s.batchSize = defaultExportBatchSize
+	}
+	if s.flushInterval <= 0 {
+		s.flushInterval = defaultExportFlushInterval
+	}
+
+	s.wg.Add(1)
+	go s.run()
+	return s, nil
+}
+
+func (s *ExportStore) run() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	// Files left behind by a previous run are uploaded right away.
+	s.ship(context.Background())
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.flush:
+		case <-s.done:
+			ctx, cancel := context.WithTimeout(context.Background(), exportCloseTimeout)
+			s.ship(ctx)
+			cancel()
+			return
+		}
+		s.ship(context.Background())
+	}
+}
+
+// ship seals the current batch, uploads the spilled files and deletes the
+// expired objects.
+func (s *ExportStore) ship(ctx context.Context) {
+	s.shipMu.Lock()
+	defer s.shipMu.Unlock()
+	if err := s.seal(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to spill audit export batch: %v\n", err)
+	}
+	s.uploadSpilled(ctx)
+	s.prune(ctx)
+}
+
+// Flush implements the Flusher interface.
+//
+// Summary: Uploads the current batch and the spilled files.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the uploads.
+//
+// Returns:
+//   - error: An error if ctx is done. Files that cannot be uploaded stay in
+//     the spill directory, and are retried at the next flush.
+func (s *ExportStore) Flush(ctx context.Context) error {
+	s.ship(ctx)
+	return ctx.Err()
+}
+
+// Write implements the Store interface.
+//
+// Summary: Adds an audit entry to the current batch.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//   - entry: Entry. The audit entry.
+//
+// Returns:
+//   - error: An error if the entry cannot be encoded.
+//
+// Side Effects:
+//   - Triggers a flush if the batch is full.
+func (s *ExportStore) Write(_ context.Context, entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	s.mu.Lock()
+	if s.count == 0 {
+		s.first = entry.Timestamp
+		if s.first.IsZero() {
+			s.first = time.Now()
+		}
+	}
+	s.buf.Write(line)
+	s.buf.WriteByte('\n')
+	s.count++
+	full := s.count >= s.batchSize
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.flush <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// seal compresses the current batch into a file in the spill directory.
+func (s *ExportStore) seal() error {
+	s.mu.Lock()
+	if s.count == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	data := append([]byte(nil), s.buf.Bytes()...)
+	first := s.first
+	s.buf.Reset()
+	s.count = 0
+	s.mu.Unlock()
+
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	name := first.UTC().Format(exportTimeLayout) + "-" + hex.EncodeToString(suffix) + exportFileSuffix
+	tmp := filepath.Join(s.spillDir, "."+name+".tmp")
+	if err := os.WriteFile(tmp, compressed.Bytes(), 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(s.spillDir, name)); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	s.enforceSpillLimit()
+	return nil
+}
+
+// spilledFiles returns the files waiting in the spill directory, oldest first.
+func (s *ExportStore) spilledFiles() ([]os.DirEntry, error) {
+	entries, err := os.ReadDir(s.spillDir)
+	if err != nil {
+		return nil, err
+	}
+	files := entries[:0]
+	for _, e := range entries {
+		if e.Type().IsRegular() && strings.HasSuffix(e.Name(), exportFileSuffix) && !strings.HasPrefix(e.Name(), ".") {
+			files = append(files, e)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name() < files[j].Name() })
+	return files, nil
+}
+
+// enforceSpillLimit drops the oldest spilled files while the spill directory
+// exceeds its size limit.
+func (s *ExportStore) enforceSpillLimit() {
+	files, err := s.spilledFiles()
+	if err != nil {
+		return
+	}
+	sizes := make([]int64, len(files))
+	var total int64
+	for i, f := range files {
+		if info, err := f.Info(); err == nil {
+			sizes[i] = info.Size()
+			total += sizes[i]
+		}
+	}
+	// The newest file is always kept.
+	for i := 0; i < len(files)-1 && total > s.spillMaxBytes; i++ {
+		if err := os.Remove(filepath.Join(s.spillDir, files[i].Name())); err != nil {
+			continue
+		}
+		total -= sizes[i]
+		metrics.IncrCounter(metricAuditExportDropped, 1)
+		fmt.Fprintf(os.Stderr, "Audit export spill directory full, dropped %s\n", files[i].Name())
+	}
+}
+
+// uploadSpilled uploads the spilled files, oldest first, and removes the
+// uploaded ones. It stops at the first file that cannot be uploaded.
+func (s *ExportStore) uploadSpilled(ctx context.Context) {
+	files, err := s.spilledFiles()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list audit export spill directory: %v\n", err)
+		return
+	}
+	for _, f := range files {
+		file := filepath.Join(s.spillDir, f.Name())
+		data, err := os.ReadFile(file) //nolint:gosec // The file is in the spill directory.
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read audit export file %s: %v\n", f.Name(), err)
+			continue
+		}
+		key := s.objectKey(f.Name())
+		if err := s.retry.Execute(ctx, func(ctx context.Context) error {
+			return s.objects.Put(ctx, key, data)
+		}); err != nil {
+			metrics.IncrCounter(metricAuditExportFailed, 1)
+			fmt.Fprintf(os.Stderr, "Failed to upload audit export file %s, keeping it for the next flush: %v\n", key, err)
+			return
+		}
+		metrics.IncrCounter(metricAuditExportUploaded, 1)
+		_ = os.Remove(file)
+	}
+}
+
+// objectKey returns the key of a spilled file: <prefix>/YYYY/MM/DD/<name>.
+func (s *ExportStore) objectKey(name string) string {
+	day := "unknown"
+	if t, err := time.Parse("20060102", name[:min(len(name), 8)]); err == nil {
+		day = t.Format("2006/01/02")
+	}
+	return path.Join(s.prefix, day, name)
+}
+
+// prune deletes the uploaded files older than the retention, at most once
+// per prune interval.
+func (s *ExportStore) prune(ctx context.Context) {
+	if s.retention <= 0 || time.Since(s.lastPrune) < exportPruneInterval {
+		return
+	}
+	s.lastPrune = time.Now()
+
+	prefix := s.prefix
+	if prefix != "" {
+		prefix += "/"
+	}
+	objects, err := s.objects.List(ctx, prefix)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list audit export files: %v\n", err)
+		return
+	}
+	cutoff := time.Now().Add(-s.retention)
+	for _, o := range objects {
+		if !strings.HasSuffix(o.Key, exportFileSuffix) || !o.Modified.Before(cutoff) {
+			continue
+		}
+		if err := s.objects.Delete(ctx, o.Key); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to delete expired audit export file %s: %v\n", o.Key, err)
+		}
+	}
+}
+
+// Read implements the Store interface.
+//
+// Summary: Reads audit entries (Not implemented).
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//   - _: Filter. Unused.
+//
+// Returns:
+//   - []Entry: Nil.
+//   - error: Always returns "not implemented".
+func (s *ExportStore) Read(_ context.Context, _ Filter) ([]Entry, error) {
+	return nil, fmt.Errorf("read not implemented for audit export store")
+}
+
+// Close flushes the current batch and stops the background flusher.
+//
+// Summary: Shuts down the export store.
+//
+// Returns:
+//   - error: An error if the bucket client cannot be closed.
+//
+// Side Effects:
+//   - Uploads the pending files, waiting up to 30 seconds. Files that cannot
+//     be uploaded stay in the spill directory.
+func (s *ExportStore) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.wg.Wait()
+		err = s.objects.Close()
+	})
+	return err
+}
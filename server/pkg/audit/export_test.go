@@ -0,0 +1,222 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type fakeObjectStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	old     []ObjectInfo
+	deleted []string
+	failPut bool
+}
+
+func newFakeObjectStore() *fakeObjectStore {
+	return &fakeObjectStore{objects: map[string][]byte{}}
+}
+
+func (f *fakeObjectStore) Put(_ context.Context, key string, data []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failPut {
+		return errors.New("bucket unavailable")
+	}
+	f.objects[key] = data
+	return nil
+}
+
+func (f *fakeObjectStore) List(_ context.Context, prefix string) ([]ObjectInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	objects := append([]ObjectInfo(nil), f.old...)
+	for key := range f.objects {
+		if strings.HasPrefix(key, prefix) {
+			objects = append(objects, ObjectInfo{Key: key, Modified: time.Now()})
+		}
+	}
+	return objects, nil
+}
+
+func (f *fakeObjectStore) Delete(_ context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deleted = append(f.deleted, key)
+	return nil
+}
+
+func (f *fakeObjectStore) Close() error {
+	return nil
+}
+
+func (f *fakeObjectStore) keys() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var keys []string
+	for key := range f.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (f *fakeObjectStore) entries(t *testing.T, key string) []Entry {
+	t.Helper()
+	f.mu.Lock()
+	data := f.objects[key]
+	f.mu.Unlock()
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	var entries []Entry
+	scanner := bufio.NewScanner(zr)
+	for scanner.Scan() {
+		var e Entry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		entries = append(entries, e)
+	}
+	require.NoError(t, scanner.Err())
+	return entries
+}
+
+func newTestExportConfig(t *testing.T) *configv1.AuditExportConfig {
+	t.Helper()
+	dir := t.TempDir()
+	validation.SetAllowedPaths([]string{dir})
+	t.Cleanup(func() { validation.SetAllowedPaths(nil) })
+	return configv1.AuditExportConfig_builder{
+		Prefix:        proto.String("/audit/"),
+		SpillDir:      proto.String(filepath.Join(dir, "spill")),
+		BatchSize:     proto.Int32(2),
+		FlushInterval: durationpb.New(time.Hour),
+		Retry:         configv1.RetryConfig_builder{NumberOfRetries: proto.Int32(0)}.Build(),
+	}.Build()
+}
+
+func TestExportStore_Batches(t *testing.T) {
+	objects := newFakeObjectStore()
+	store, err := NewExportStore(newTestExportConfig(t), objects)
+	require.NoError(t, err)
+
+	ts := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, store.Write(context.Background(), Entry{Timestamp: ts, ToolName: "weather.get", DurationMs: int64(i)}))
+	}
+	// The full batch is shipped without waiting for the flush interval.
+	require.Eventually(t, func() bool { return len(objects.keys()) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// Closing ships the partial batch.
+	require.NoError(t, store.Write(context.Background(), Entry{Timestamp: ts, ToolName: "weather.get", DurationMs: 2}))
+	require.NoError(t, store.Close())
+	keys := objects.keys()
+	require.Len(t, keys, 2)
+	for _, key := range keys {
+		assert.True(t, strings.HasPrefix(key, "audit/2026/03/04/20260304T050607.000000000Z-"), key)
+		assert.True(t, strings.HasSuffix(key, ".jsonl.gz"), key)
+	}
+
+	var durations []int64
+	for _, key := range keys {
+		for _, e := range objects.entries(t, key) {
+			assert.Equal(t, "weather.get", e.ToolName)
+			durations = append(durations, e.DurationMs)
+		}
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	assert.Equal(t, []int64{0, 1, 2}, durations)
+
+	_, err = store.Read(context.Background(), Filter{})
+	assert.Error(t, err)
+}
+
+func TestExportStore_SpillAndRetry(t *testing.T) {
+	config := newTestExportConfig(t)
+	objects := newFakeObjectStore()
+	objects.failPut = true
+
+	store, err := NewExportStore(config, objects)
+	require.NoError(t, err)
+	require.NoError(t, store.Write(context.Background(), Entry{Timestamp: time.Now(), ToolName: "a"}))
+	require.NoError(t, store.Close())
+	assert.Empty(t, objects.keys())
+
+	spilled, err := os.ReadDir(config.GetSpillDir())
+	require.NoError(t, err)
+	require.Len(t, spilled, 1)
+
+	// A restarted store uploads the spilled file once the bucket is back.
+	objects.failPut = false
+	store, err = NewExportStore(config, objects)
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+	require.Len(t, objects.keys(), 1)
+	assert.Equal(t, "a", objects.entries(t, objects.keys()[0])[0].ToolName)
+
+	spilled, err = os.ReadDir(config.GetSpillDir())
+	require.NoError(t, err)
+	assert.Empty(t, spilled)
+}
+
+func TestExportStore_SpillLimit(t *testing.T) {
+	config := newTestExportConfig(t)
+	config.SetSpillMaxBytes(1)
+	objects := newFakeObjectStore()
+	objects.failPut = true
+
+	store, err := NewExportStore(config, objects)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, store.seal())
+		require.NoError(t, store.Write(context.Background(), Entry{Timestamp: time.Now().Add(time.Duration(i) * time.Second), ToolName: "a"}))
+	}
+	require.NoError(t, store.Close())
+
+	// Only the newest file is kept.
+	spilled, err := os.ReadDir(config.GetSpillDir())
+	require.NoError(t, err)
+	assert.Len(t, spilled, 1)
+}
+
+func TestExportStore_Retention(t *testing.T) {
+	config := newTestExportConfig(t)
+	config.SetRetention(durationpb.New(24 * time.Hour))
+	objects := newFakeObjectStore()
+	objects.old = []ObjectInfo{
+		{Key: "audit/2025/01/01/20250101T000000.000000000Z-00000000.jsonl.gz", Modified: time.Now().Add(-48 * time.Hour)},
+		{Key: "audit/2025/01/01/notes.txt", Modified: time.Now().Add(-48 * time.Hour)},
+	}
+
+	store, err := NewExportStore(config, objects)
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+	assert.Equal(t, []string{"audit/2025/01/01/20250101T000000.000000000Z-00000000.jsonl.gz"}, objects.deleted)
+}
+
+func TestNewExportStore_Errors(t *testing.T) {
+	_, err := NewExportStore(configv1.AuditExportConfig_builder{}.Build(), newFakeObjectStore())
+	assert.ErrorContains(t, err, "spill_dir is required")
+
+	_, err = NewObjectStore(context.Background(), configv1.AuditExportConfig_builder{}.Build())
+	assert.ErrorContains(t, err, "either s3 or gcs is required")
+}
@@ -4,23 +4,48 @@
 package audit
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/ed25519"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"regexp"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/mcpany/core/server/pkg/validation"
 )
 
+// checkpointsSuffix is appended to the path of an audit log file to get the
+// path of its checkpoints.
+const checkpointsSuffix = ".checkpoints"
+
+// chainSuffix matches the chain fields appended to each line of an audit log
+// file. Removing them and restoring the closing brace yields the exact bytes
+// that were hashed.
+var chainSuffix = regexp.MustCompile(`,"seq":(\d+),"prev_hash":"([^"]*)","hash":"([^"]*)"}$`)
+
 // FileAuditStore writes audit logs to a file or stdout.
 //
 // Summary: Audit store implementation that appends newline-delimited JSON (NDJSON) to a file or standard output.
+//
+// Entries written to a file are chained: each line carries its sequence
+// number, the hash of the previous line and its own hash.
 type FileAuditStore struct {
 	mu   sync.Mutex
 	file *os.File
 	out  io.Writer
+
+	seq      int64
+	prevHash string
+
+	signer      *CheckpointSigner
+	checkpoints *os.File
 }
 
 // NewFileAuditStore creates a new FileAuditStore.
@@ -52,10 +77,52 @@ func NewFileAuditStore(path string) (*FileAuditStore, error) {
 			return nil, fmt.Errorf("failed to open audit log file: %w", err)
 		}
 	}
-	return &FileAuditStore{
+	s := &FileAuditStore{
 		file: f,
 		out:  os.Stdout,
-	}, nil
+	}
+	if f != nil {
+		// Continue the chain of the existing file.
+		last, err := readLastLine(path)
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("failed to read audit log file: %w", err)
+		}
+		if m := chainSuffix.FindSubmatch(last); m != nil {
+			s.seq, _ = strconv.ParseInt(string(m[1]), 10, 64)
+			s.prevHash = string(m[3])
+		}
+	}
+	return s, nil
+}
+
+// SetCheckpointSigner enables signed checkpoints. They are appended to the
+// file at the path of the audit log with a ".checkpoints" suffix. Entries
+// written to stdout are not checkpointed.
+//
+// Summary: Enables signed checkpoints of the hash chain.
+//
+// Parameters:
+//   - signer: *CheckpointSigner. The signer.
+//
+// Returns:
+//   - error: An error if the checkpoints file cannot be opened.
+func (s *FileAuditStore) SetCheckpointSigner(signer *CheckpointSigner) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	f, err := os.OpenFile(s.file.Name()+checkpointsSuffix, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit checkpoints file: %w", err)
+	}
+	if s.checkpoints != nil {
+		_ = s.checkpoints.Close()
+	}
+	s.signer = signer
+	s.checkpoints = f
+	return nil
 }
 
 // Write writes an audit entry to the file.
@@ -78,21 +145,38 @@ func (s *FileAuditStore) Write(_ context.Context, entry Entry) error {
 	if err != nil {
 		return err
 	}
-	// json.NewEncoder.Encode appends a newline, so we must add it here too.
-	b = append(b, '\n')
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	var w io.Writer
-	if s.file != nil {
-		w = s.file
-	} else {
-		w = s.out
+	if s.file == nil {
+		// json.NewEncoder.Encode appends a newline, so we must add it here too.
+		_, err = s.out.Write(append(b, '\n'))
+		return err
 	}
 
-	_, err = w.Write(b)
-	return err
+	seq := s.seq + 1
+	hash := computeLineHash(seq, b, s.prevHash)
+	line := make([]byte, 0, len(b)+len(hash)+len(s.prevHash)+64)
+	line = append(line, b[:len(b)-1]...)
+	line = fmt.Appendf(line, `,"seq":%d,"prev_hash":%q,"hash":%q}`, seq, s.prevHash, hash)
+	line = append(line, '\n')
+	if _, err := s.file.Write(line); err != nil {
+		return err
+	}
+	s.seq = seq
+	s.prevHash = hash
+
+	if s.signer.due(seq) {
+		cb, err := json.Marshal(s.signer.sign(seq, hash, time.Now()))
+		if err != nil {
+			return err
+		}
+		if _, err := s.checkpoints.Write(append(cb, '\n')); err != nil {
+			return fmt.Errorf("failed to write audit checkpoint: %w", err)
+		}
+	}
+	return nil
 }
 
 // Read implements the Store interface.
@@ -122,8 +206,153 @@ func (s *FileAuditStore) Read(_ context.Context, _ Filter) ([]Entry, error) {
 func (s *FileAuditStore) Close() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if s.checkpoints != nil {
+		_ = s.checkpoints.Close()
+	}
 	if s.file != nil {
 		return s.file.Close()
 	}
 	return nil
 }
+
+// VerifyFile checks the hash chain of an audit log file and its checkpoints.
+//
+// Summary: Validates the tamper evidence of an audit log file.
+//
+// Parameters:
+//   - path: string. The path of the audit log file.
+//   - pub: ed25519.PublicKey. The key verifying the checkpoint signatures, or nil to only match their hashes.
+//
+// Returns:
+//   - *VerifyReport: A summary of the verified log.
+//   - error: An error describing the first integrity violation, or if the file cannot be read.
+func VerifyFile(path string, pub ed25519.PublicKey) (*VerifyReport, error) {
+	checkpoints, err := readCheckpoints(path + checkpointsSuffix)
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[int64]string, len(checkpoints))
+	for _, c := range checkpoints {
+		wanted[c.Seq] = ""
+	}
+
+	f, err := os.Open(path) //nolint:gosec // The path is given by the operator.
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	report := &VerifyReport{}
+	r := bufio.NewReader(f)
+	for lineNo := 1; ; lineNo++ {
+		line, readErr := r.ReadBytes('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return nil, fmt.Errorf("failed to read audit log file: %w", readErr)
+		}
+		line = bytes.TrimRight(line, "\r\n")
+		if len(line) > 0 {
+			if err := verifyLine(report, line, wanted); err != nil {
+				return report, fmt.Errorf("integrity violation at line %d: %w", lineNo, err)
+			}
+		}
+		if readErr != nil {
+			break
+		}
+	}
+
+	err = verifyCheckpoints(report, checkpoints, func(seq int64) (string, bool) {
+		if seq > report.Entries {
+			return "", false
+		}
+		return wanted[seq], true
+	}, pub)
+	return report, err
+}
+
+// verifyLine checks one line of an audit log file against the chain so far.
+func verifyLine(report *VerifyReport, line []byte, wanted map[int64]string) error {
+	loc := chainSuffix.FindSubmatchIndex(line)
+	if loc == nil {
+		if report.Entries > 0 {
+			return fmt.Errorf("entry after seq %d is not chained", report.Entries)
+		}
+		report.Unchained++
+		return nil
+	}
+	seq, _ := strconv.ParseInt(string(line[loc[2]:loc[3]]), 10, 64)
+	prevHash := string(line[loc[4]:loc[5]])
+	hash := string(line[loc[6]:loc[7]])
+
+	if seq != report.Entries+1 {
+		return fmt.Errorf("seq mismatch (expected %d, got %d)", report.Entries+1, seq)
+	}
+	if prevHash != report.LastHash {
+		return fmt.Errorf("prev_hash mismatch at seq %d (expected %q, got %q)", seq, report.LastHash, prevHash)
+	}
+	content := append(line[:loc[0]:loc[0]], '}')
+	if calculated := computeLineHash(seq, content, prevHash); calculated != hash {
+		return fmt.Errorf("hash mismatch at seq %d (calculated %q, got %q)", seq, calculated, hash)
+	}
+	if _, ok := wanted[seq]; ok {
+		wanted[seq] = hash
+	}
+	report.Entries = seq
+	report.LastHash = hash
+	return nil
+}
+
+// readCheckpoints reads a checkpoints file. A missing file has no checkpoints.
+func readCheckpoints(path string) ([]Checkpoint, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // The path is given by the operator.
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit checkpoints file: %w", err)
+	}
+	var checkpoints []Checkpoint
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var c Checkpoint
+		if err := json.Unmarshal(line, &c); err != nil {
+			return nil, fmt.Errorf("malformed checkpoint at line %d: %w", i+1, err)
+		}
+		checkpoints = append(checkpoints, c)
+	}
+	return checkpoints, nil
+}
+
+// readLastLine returns the last non-empty line of a file without reading all of it.
+func readLastLine(path string) ([]byte, error) {
+	f, err := os.Open(path) //nolint:gosec // The path was validated by the caller.
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	const chunkSize = 4096
+	var tail []byte
+	for offset := info.Size(); offset > 0; {
+		n := int64(chunkSize)
+		if offset < n {
+			n = offset
+		}
+		offset -= n
+		chunk := make([]byte, n)
+		if _, err := f.ReadAt(chunk, offset); err != nil {
+			return nil, err
+		}
+		tail = append(chunk, tail...)
+		trimmed := bytes.TrimRight(tail, "\r\n")
+		if i := bytes.LastIndexByte(trimmed, '\n'); i >= 0 {
+			return trimmed[i+1:], nil
+		}
+	}
+	return bytes.TrimRight(tail, "\r\n"), nil
+}
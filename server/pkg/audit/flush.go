@@ -0,0 +1,106 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+)
+
+// Flusher is implemented by the audit stores that buffer entries before
+// writing them.
+//
+// Summary: Interface for sending the buffered audit entries.
+type Flusher interface {
+	// Flush sends the buffered entries.
+	//
+	// Summary: Sends the buffered entries.
+	//
+	// Parameters:
+	//   - ctx: context.Context. Bounds the wait.
+	//
+	// Returns:
+	//   - error: An error if ctx is done before the entries are sent.
+	Flush(ctx context.Context) error
+}
+
+// Flush sends the buffered entries of a store, if it buffers any.
+//
+// Summary: Flushes an audit store.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the wait.
+//   - store: Store. The store.
+//
+// Returns:
+//   - error: An error if the store cannot be flushed in time.
+func Flush(ctx context.Context, store Store) error {
+	if f, ok := store.(Flusher); ok {
+		return f.Flush(ctx)
+	}
+	return nil
+}
+
+// flushWorkers asks each batching worker to send its batch along with the
+// queued entries, and waits for them. A store that is closed has nothing left
+// to flush.
+func flushWorkers(ctx context.Context, flushes []chan chan struct{}, done <-chan struct{}) error {
+	acks := make([]chan struct{}, 0, len(flushes))
+	for _, flush := range flushes {
+		ack := make(chan struct{})
+		select {
+		case flush <- ack:
+			acks = append(acks, ack)
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	for _, ack := range acks {
+		select {
+		case <-ack:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// takeQueued appends the entries waiting in the queue to the batch, sending
+// the full batches on the way.
+func takeQueued(queue chan Entry, batch []Entry, batchSize int, send func([]Entry)) []Entry {
+	for {
+		select {
+		case entry, ok := <-queue:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, entry)
+			if len(batch) >= batchSize {
+				send(batch)
+				batch = nil
+			}
+		default:
+			return batch
+		}
+	}
+}
+
+// Flush implements the Flusher interface.
+//
+// Summary: Flushes the primary store and all sinks.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the wait.
+//
+// Returns:
+//   - error: The joined errors of the stores that failed to flush.
+func (t *TeeStore) Flush(ctx context.Context) error {
+	errs := []error{Flush(ctx, t.primary)}
+	for _, s := range t.sinks {
+		errs = append(errs, Flush(ctx, s))
+	}
+	return errors.Join(errs...)
+}
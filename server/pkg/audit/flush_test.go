@@ -0,0 +1,66 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlush(t *testing.T) {
+	var mu sync.Mutex
+	var received []Entry
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []Entry
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		received = append(received, batch...)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	webhook := NewWebhookAuditStore(server.URL, nil)
+	tee := NewTeeStore(&recordingStore{}, webhook)
+	ctx := context.Background()
+	for _, name := range []string{"a", "b", "c"} {
+		require.NoError(t, tee.Write(ctx, Entry{ToolName: name, Timestamp: time.Now()}))
+	}
+
+	// The entries are sent by Flush rather than after the batch wait.
+	require.NoError(t, Flush(ctx, tee))
+	mu.Lock()
+	assert.Len(t, received, 3)
+	mu.Unlock()
+
+	require.NoError(t, tee.Close())
+	assert.NoError(t, Flush(ctx, webhook), "a closed store has nothing to flush")
+	assert.NoError(t, Flush(ctx, &recordingStore{}), "a store without buffer has nothing to flush")
+}
+
+func TestFlush_ContextDone(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	store := NewWebhookAuditStore(server.URL, nil)
+	defer func() { _ = store.Close() }()
+	defer close(release)
+	require.NoError(t, store.Write(context.Background(), Entry{ToolName: "a"}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, store.Flush(ctx), context.DeadlineExceeded)
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"cloud.google.com/go/storage"
+	"github.com/aws/aws-sdk-go/aws"             //nolint:staticcheck
+	"github.com/aws/aws-sdk-go/aws/credentials" //nolint:staticcheck
+	"github.com/aws/aws-sdk-go/aws/session"     //nolint:staticcheck
+	"github.com/aws/aws-sdk-go/service/s3"      //nolint:staticcheck
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"google.golang.org/api/iterator"
+)
+
+// NewObjectStore creates the client of the bucket of an export configuration.
+//
+// Summary: Creates an S3 or GCS object store.
+//
+// Parameters:
+//   - ctx: context.Context. The context for creating the client.
+//   - config: *configv1.AuditExportConfig. The export configuration.
+//
+// Returns:
+//   - ObjectStore: The bucket.
+//   - error: An error if no bucket is configured or the client cannot be created.
+func NewObjectStore(ctx context.Context, config *configv1.AuditExportConfig) (ObjectStore, error) {
+	switch {
+	case config.HasS3():
+		return newS3ObjectStore(config.GetS3())
+	case config.HasGcs():
+		return newGCSObjectStore(ctx, config.GetGcs())
+	default:
+		return nil, errors.New("either s3 or gcs is required")
+	}
+}
+
+// s3ObjectStore is an ObjectStore backed by an S3 bucket.
+type s3ObjectStore struct {
+	client *s3.S3
+	bucket string
+}
+
+func newS3ObjectStore(config *configv1.S3Fs) (*s3ObjectStore, error) {
+	awsConfig := aws.NewConfig()
+	if config.GetRegion() != "" {
+		awsConfig.WithRegion(config.GetRegion())
+	}
+	if config.GetAccessKeyId() != "" && config.GetSecretAccessKey() != "" {
+		awsConfig.WithCredentials(credentials.NewStaticCredentials(
+			config.GetAccessKeyId(),
+			config.GetSecretAccessKey(),
+			config.GetSessionToken(),
+		))
+	}
+	if config.GetEndpoint() != "" {
+		awsConfig.WithEndpoint(config.GetEndpoint())
+		// Needed for MinIO and some S3 compatible services
+		awsConfig.WithS3ForcePathStyle(true)
+	}
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS session: %w", err)
+	}
+	return &s3ObjectStore{client: s3.New(sess), bucket: config.GetBucket()}, nil
+}
+
+func (o *s3ObjectStore) Put(ctx context.Context, key string, data []byte) error {
+	_, err := o.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(o.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/gzip"),
+	})
+	return err
+}
+
+func (o *s3ObjectStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	err := o.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(o.bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, obj := range page.Contents {
+			objects = append(objects, ObjectInfo{Key: aws.StringValue(obj.Key), Modified: aws.TimeValue(obj.LastModified)})
+		}
+		return true
+	})
+	return objects, err
+}
+
+func (o *s3ObjectStore) Delete(ctx context.Context, key string) error {
+	_, err := o.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(o.bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+func (o *s3ObjectStore) Close() error {
+	return nil
+}
+
+// gcsObjectStore is an ObjectStore backed by a Google Cloud Storage bucket.
+type gcsObjectStore struct {
+	client *storage.Client
+	bucket *storage.BucketHandle
+}
+
+func newGCSObjectStore(ctx context.Context, config *configv1.GcsFs) (*gcsObjectStore, error) {
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gcs client: %w", err)
+	}
+	return &gcsObjectStore{client: client, bucket: client.Bucket(config.GetBucket())}, nil
+}
+
+func (o *gcsObjectStore) Put(ctx context.Context, key string, data []byte) error {
+	w := o.bucket.Object(key).NewWriter(ctx)
+	w.ContentType = "application/gzip"
+	if _, err := w.Write(data); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func (o *gcsObjectStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	it := o.bucket.Objects(ctx, &storage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			return objects, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, ObjectInfo{Key: attrs.Name, Modified: attrs.Updated})
+	}
+}
+
+func (o *gcsObjectStore) Delete(ctx context.Context, key string) error {
+	return o.bucket.Object(key).Delete(ctx)
+}
+
+func (o *gcsObjectStore) Close() error {
+	return o.client.Close()
+}
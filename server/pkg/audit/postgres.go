@@ -8,12 +8,83 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/mcpany/core/server/pkg/storage/migrate"
+
 	_ "github.com/lib/pq" // Register postgres driver
 )
 
+// postgresAuditMigrationsTable records the applied migrations of the audit
+// schema, apart from those of the storage, since the audit logs may share
+// its database.
+const postgresAuditMigrationsTable = "audit_schema_migrations"
+
+// postgresAuditMigrations are the schema versions of the audit logs. Append
+// new migrations; never edit or renumber the released ones.
+var postgresAuditMigrations = []migrate.Migration{
+	{
+		// The table created before migrations were versioned. IF NOT EXISTS
+		// adopts the databases created by those releases.
+		// The arguments and results are TEXT rather than JSONB to be hashed
+		// exactly as written.
+		Version: 1,
+		Name:    "create_audit_logs",
+		Up: `
+		CREATE TABLE IF NOT EXISTS audit_logs (
+			id BIGSERIAL PRIMARY KEY,
+			timestamp TIMESTAMPTZ NOT NULL,
+			tool_name TEXT NOT NULL,
+			user_id TEXT NOT NULL DEFAULT '',
+			profile_id TEXT NOT NULL DEFAULT '',
+			arguments TEXT,
+			result TEXT,
+			error TEXT,
+			duration_ms BIGINT,
+			prev_hash TEXT,
+			hash TEXT
+		);
+		CREATE INDEX IF NOT EXISTS idx_audit_logs_timestamp ON audit_logs (timestamp);
+		CREATE INDEX IF NOT EXISTS idx_audit_logs_tool_name ON audit_logs (tool_name, timestamp);
+		CREATE INDEX IF NOT EXISTS idx_audit_logs_user_id ON audit_logs (user_id, timestamp);
+		CREATE INDEX IF NOT EXISTS idx_audit_logs_profile_id ON audit_logs (profile_id, timestamp);
+		`,
+		Down: `
+		DROP TABLE IF EXISTS audit_logs;
+		`,
+	},
+	{
+		// The fields of the entries the SQLite store already kept: the ID
+		// of the entry, the per-client API key, the trace and the labels.
+		Version: 2,
+		Name:    "add_audit_logs_entry_fields",
+		Up: `
+		ALTER TABLE audit_logs
+			ADD COLUMN IF NOT EXISTS entry_id TEXT NOT NULL DEFAULT '',
+			ADD COLUMN IF NOT EXISTS api_key_id TEXT NOT NULL DEFAULT '',
+			ADD COLUMN IF NOT EXISTS trace_id TEXT NOT NULL DEFAULT '',
+			ADD COLUMN IF NOT EXISTS span_id TEXT NOT NULL DEFAULT '',
+			ADD COLUMN IF NOT EXISTS parent_id TEXT NOT NULL DEFAULT '',
+			ADD COLUMN IF NOT EXISTS labels TEXT NOT NULL DEFAULT '';
+		CREATE INDEX IF NOT EXISTS idx_audit_logs_entry_id ON audit_logs (entry_id);
+		CREATE INDEX IF NOT EXISTS idx_audit_logs_api_key_id ON audit_logs (api_key_id, timestamp);
+		`,
+		Down: `
+		DROP INDEX IF EXISTS idx_audit_logs_api_key_id;
+		DROP INDEX IF EXISTS idx_audit_logs_entry_id;
+		ALTER TABLE audit_logs
+			DROP COLUMN IF EXISTS labels,
+			DROP COLUMN IF EXISTS parent_id,
+			DROP COLUMN IF EXISTS span_id,
+			DROP COLUMN IF EXISTS trace_id,
+			DROP COLUMN IF EXISTS api_key_id,
+			DROP COLUMN IF EXISTS entry_id;
+		`,
+	},
+}
+
 // PostgresAuditStore writes audit logs to a PostgreSQL database.
 //
 // Summary: Stores audit log entries in a PostgreSQL database with tamper-evident hashing.
@@ -36,11 +107,12 @@ type PostgresAuditStore struct {
 // Errors:
 //   - Returns "postgres dsn is required" if the dsn is empty.
 //   - Returns error if database connection or ping fails.
-//   - Returns error if table creation fails.
+//   - Returns error if a schema migration fails.
 //
 // Side Effects:
 //   - Connects to the database.
-//   - Creates the 'audit_logs' table if it doesn't exist.
+//   - Applies the pending migrations of the 'audit_logs' table, recorded in
+//     the 'audit_schema_migrations' table.
 func NewPostgresAuditStore(dsn string) (*PostgresAuditStore, error) {
 	if dsn == "" {
 		return nil, fmt.Errorf("postgres dsn is required")
@@ -58,38 +130,27 @@ func NewPostgresAuditStore(dsn string) (*PostgresAuditStore, error) {
 		return nil, fmt.Errorf("failed to ping postgres database: %w", err)
 	}
 
-	// Create table if not exists
-	// Postgres uses standard SQL types.
-	// BIGSERIAL for auto-incrementing 8-byte integer.
-	// TIMESTAMPTZ for timestamps.
-	// JSONB for arguments and result could be used, but we stick to TEXT/string to match the hashing logic exactly
-	// and avoid issues with JSON normalization differences in DB.
-	// We can cast to JSONB in queries if needed.
-	schema := `
-	CREATE TABLE IF NOT EXISTS audit_logs (
-		id BIGSERIAL PRIMARY KEY,
-		timestamp TIMESTAMPTZ NOT NULL,
-		tool_name TEXT NOT NULL,
-		user_id TEXT NOT NULL DEFAULT '',
-		profile_id TEXT NOT NULL DEFAULT '',
-		arguments TEXT,
-		result TEXT,
-		error TEXT,
-		duration_ms BIGINT,
-		prev_hash TEXT,
-		hash TEXT
-	);
-	`
-	ctxSchema, cancelSchema := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancelSchema()
-	if _, err := db.ExecContext(ctxSchema, schema); err != nil {
+	store, err := newPostgresAuditStore(db)
+	if err != nil {
 		_ = db.Close()
-		return nil, fmt.Errorf("failed to create audit_logs table: %w", err)
+		return nil, err
 	}
+	return store, nil
+}
 
-	return &PostgresAuditStore{
-		db: db,
-	}, nil
+// newPostgresAuditStore creates the store of a database, applying the
+// pending migrations of the audit schema.
+func newPostgresAuditStore(db *sql.DB) (*PostgresAuditStore, error) {
+	m, err := migrate.New(db, migrate.Postgres, postgresAuditMigrations, migrate.WithTable(postgresAuditMigrationsTable))
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if _, err := m.Up(ctx); err != nil {
+		return nil, fmt.Errorf("failed to migrate the audit_logs table: %w", err)
+	}
+	return &PostgresAuditStore{db: db}, nil
 }
 
 // Write writes an audit entry to the database.
@@ -119,6 +180,13 @@ func (s *PostgresAuditStore) Write(ctx context.Context, entry Entry) error {
 		argsJSON = string(entry.Arguments)
 	}
 
+	var labelsJSON string
+	if len(entry.Labels) > 0 {
+		if b, err := json.Marshal(entry.Labels); err == nil {
+			labelsJSON = string(b)
+		}
+	}
+
 	resultJSON := "{}"
 	if entry.Result != nil {
 		if b, err := json.Marshal(entry.Result); err == nil {
@@ -166,15 +234,21 @@ func (s *PostgresAuditStore) Write(ctx context.Context, entry Entry) error {
 
 	query := `
 	INSERT INTO audit_logs (
-		timestamp, tool_name, user_id, profile_id, arguments, result, error, duration_ms, prev_hash, hash
-	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		entry_id, timestamp, tool_name, user_id, profile_id, api_key_id, trace_id, span_id, parent_id, labels, arguments, result, error, duration_ms, prev_hash, hash
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 	`
 
 	_, err = tx.ExecContext(ctx, query,
+		entry.ID,
 		entry.Timestamp, // Postgres driver handles time.Time
 		entry.ToolName,
 		entry.UserID,
 		entry.ProfileID,
+		entry.APIKeyID,
+		entry.TraceID,
+		entry.SpanID,
+		entry.ParentID,
+		labelsJSON,
 		argsJSON,
 		resultJSON,
 		entry.Error,
@@ -191,17 +265,95 @@ func (s *PostgresAuditStore) Write(ctx context.Context, entry Entry) error {
 
 // Read implements the Store interface.
 //
-// Summary: Reads audit entries (Not implemented).
+// Summary: Reads audit entries matching the filter, newest first.
 //
 // Parameters:
-//   - _: context.Context. Unused.
-//   - _: Filter. Unused.
+//   - ctx: context.Context. The request context.
+//   - filter: Filter. The criteria of the entries. Entries written before
+//     the entry_id column was added are identified by their row number.
 //
 // Returns:
-//   - []Entry: Nil.
-//   - error: Always returns "not implemented".
-func (s *PostgresAuditStore) Read(_ context.Context, _ Filter) ([]Entry, error) {
-	return nil, fmt.Errorf("read not implemented for postgres audit store")
+//   - []Entry: The matching entries.
+//   - error: An error if the query fails.
+func (s *PostgresAuditStore) Read(ctx context.Context, filter Filter) ([]Entry, error) {
+	query := "SELECT id, entry_id, timestamp, tool_name, user_id, profile_id, api_key_id, trace_id, span_id, parent_id, labels, COALESCE(arguments, ''), COALESCE(result, ''), COALESCE(error, ''), COALESCE(duration_ms, 0) FROM audit_logs WHERE 1=1"
+	var args []any
+	addFilter := func(clause string, arg any) {
+		args = append(args, arg)
+		query += fmt.Sprintf(" AND %s $%d", clause, len(args))
+	}
+	if filter.StartTime != nil {
+		addFilter("timestamp >=", *filter.StartTime)
+	}
+	if filter.EndTime != nil {
+		addFilter("timestamp <=", *filter.EndTime)
+	}
+	if filter.ToolName != "" {
+		addFilter("tool_name =", filter.ToolName)
+	}
+	if filter.UserID != "" {
+		addFilter("user_id =", filter.UserID)
+	}
+	if filter.ProfileID != "" {
+		addFilter("profile_id =", filter.ProfileID)
+	}
+	if filter.APIKeyID != "" {
+		addFilter("api_key_id =", filter.APIKeyID)
+	}
+	if filter.ID != "" {
+		args = append(args, filter.ID)
+		query += fmt.Sprintf(" AND (entry_id = $%[1]d OR (entry_id = '' AND id::text = $%[1]d))", len(args))
+	}
+	if filter.ErrorsOnly {
+		query += " AND COALESCE(error, '') != ''"
+	}
+	if filter.Query != "" {
+		args = append(args, likePattern(filter.Query))
+		n := len(args)
+		query += fmt.Sprintf(` AND (tool_name ILIKE $%[1]d OR arguments ILIKE $%[1]d OR result ILIKE $%[1]d OR error ILIKE $%[1]d)`, n)
+	}
+
+	query += " ORDER BY timestamp DESC, id DESC"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []Entry
+	for rows.Next() {
+		var entry Entry
+		var id int64
+		var labelsStr, argsStr, resultStr string
+		if err := rows.Scan(&id, &entry.ID, &entry.Timestamp, &entry.ToolName, &entry.UserID, &entry.ProfileID, &entry.APIKeyID, &entry.TraceID, &entry.SpanID, &entry.ParentID, &labelsStr, &argsStr, &resultStr, &entry.Error, &entry.DurationMs); err != nil {
+			return nil, err
+		}
+		if entry.ID == "" {
+			entry.ID = strconv.FormatInt(id, 10)
+		}
+		if labelsStr != "" {
+			_ = json.Unmarshal([]byte(labelsStr), &entry.Labels)
+		}
+		entry.Arguments = json.RawMessage(argsStr)
+		if resultStr != "" && resultStr != "{}" {
+			_ = json.Unmarshal([]byte(resultStr), &entry.Result)
+		}
+		entry.Duration = fmt.Sprintf("%dms", entry.DurationMs)
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
 }
 
 // Verify checks the integrity of the audit logs.
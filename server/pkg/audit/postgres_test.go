@@ -10,6 +10,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPostgresAuditStore_New(t *testing.T) {
@@ -39,10 +40,16 @@ func TestPostgresAuditStore_Write(t *testing.T) {
 	}
 
 	entry := Entry{
+		ID:         "entry-1",
 		Timestamp:  time.Now(),
 		ToolName:   "test_tool",
 		UserID:     "user1",
 		ProfileID:  "p1",
+		APIKeyID:   "key-1",
+		TraceID:    "trace-1",
+		SpanID:     "span-1",
+		ParentID:   "parent-1",
+		Labels:     map[string]string{"team": "payments"},
 		Arguments:  []byte(`{"arg":"val"}`),
 		Result:     map[string]any{"res": "val"},
 		Error:      "",
@@ -59,10 +66,16 @@ func TestPostgresAuditStore_Write(t *testing.T) {
 	// Mock Insert
 	mock.ExpectExec("INSERT INTO audit_logs").
 		WithArgs(
+			entry.ID,
 			entry.Timestamp,
 			entry.ToolName,
 			entry.UserID,
 			entry.ProfileID,
+			entry.APIKeyID,
+			entry.TraceID,
+			entry.SpanID,
+			entry.ParentID,
+			`{"team":"payments"}`,
 			string(entry.Arguments),
 			`{"res":"val"}`,
 			entry.Error,
@@ -161,6 +174,93 @@ func TestPostgresAuditStore_Verify_ChainBroken(t *testing.T) {
 	assert.Contains(t, err.Error(), "prev_hash mismatch")
 }
 
+func TestPostgresAuditStore_Read(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	store := &PostgresAuditStore{
+		db: db,
+	}
+
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	rows := sqlmock.NewRows(postgresReadColumns).
+		AddRow(int64(7), "", ts, "tool1", "u1", "p1", "key-1", "trace-1", "span-1", "", `{"team":"payments"}`, `{"a":1}`, `{"ok":true}`, "", int64(12))
+	mock.ExpectQuery(`SELECT .* FROM audit_logs WHERE 1=1 AND timestamp >= \$1 AND tool_name = \$2 AND user_id = \$3 ORDER BY timestamp DESC, id DESC LIMIT \$4 OFFSET \$5`).
+		WithArgs(ts.Add(-time.Hour), "tool1", "u1", 10, 5).
+		WillReturnRows(rows)
+
+	start := ts.Add(-time.Hour)
+	entries, err := store.Read(context.Background(), Filter{StartTime: &start, ToolName: "tool1", UserID: "u1", Limit: 10, Offset: 5})
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "7", entries[0].ID, "entries without an entry_id are identified by their row")
+		assert.Equal(t, ts, entries[0].Timestamp)
+		assert.Equal(t, "key-1", entries[0].APIKeyID)
+		assert.Equal(t, "trace-1", entries[0].TraceID)
+		assert.Equal(t, map[string]string{"team": "payments"}, entries[0].Labels)
+		assert.Equal(t, "tool1", entries[0].ToolName)
+		assert.JSONEq(t, `{"a":1}`, string(entries[0].Arguments))
+		assert.Equal(t, map[string]any{"ok": true}, entries[0].Result)
+		assert.Equal(t, "12ms", entries[0].Duration)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	mock.ExpectQuery(`SELECT .* FROM audit_logs WHERE 1=1 AND api_key_id = \$1 ORDER BY`).
+		WithArgs("key-1").
+		WillReturnRows(sqlmock.NewRows(postgresReadColumns).
+			AddRow(int64(8), "entry-8", ts, "tool1", "u1", "p1", "key-1", "", "", "", "", `{}`, `{}`, "", int64(3)))
+	entries, err = store.Read(context.Background(), Filter{APIKeyID: "key-1"})
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "entry-8", entries[0].ID)
+		assert.Equal(t, "key-1", entries[0].APIKeyID)
+	}
+
+	mock.ExpectQuery(`SELECT .* FROM audit_logs WHERE 1=1 AND \(entry_id = \$1 OR \(entry_id = '' AND id::text = \$1\)\) AND COALESCE\(error, ''\) != '' AND \(tool_name ILIKE \$2 OR arguments ILIKE \$2 OR result ILIKE \$2 OR error ILIKE \$2\) ORDER BY`).
+		WithArgs("7", `%50\%\_off%`).
+		WillReturnRows(sqlmock.NewRows(postgresReadColumns))
+	entries, err = store.Read(context.Background(), Filter{ID: "7", ErrorsOnly: true, Query: "50%_off"})
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// postgresReadColumns are the columns selected by Read.
+var postgresReadColumns = []string{"id", "entry_id", "timestamp", "tool_name", "user_id", "profile_id", "api_key_id", "trace_id", "span_id", "parent_id", "labels", "arguments", "result", "error", "duration_ms"}
+
+func TestPostgresAuditStore_Migrations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	expectTx := func() {
+		mock.ExpectBegin()
+		mock.ExpectExec("SELECT pg_advisory_xact_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS audit_schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	// The table of a release before the migrations only gets the new
+	// columns.
+	expectTx()
+	mock.ExpectQuery("SELECT version, name, applied_at FROM audit_schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "name", "applied_at"}).AddRow(1, "create_audit_logs", "2026-01-02T03:04:05Z"))
+	mock.ExpectCommit()
+	expectTx()
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM audit_schema_migrations WHERE version = \$1`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(`ALTER TABLE audit_logs\s+ADD COLUMN IF NOT EXISTS entry_id .*api_key_id .*trace_id .*labels`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO audit_schema_migrations").
+		WithArgs(2, "add_audit_logs_entry_fields", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	_, err = newPostgresAuditStore(db)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestPostgresAuditStore_New_Error(t *testing.T) {
 	// Simple check for DSN validation
 	_, err := NewPostgresAuditStore("")
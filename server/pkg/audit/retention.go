@@ -0,0 +1,255 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/metrics"
+)
+
+const (
+	defaultRetentionInterval = time.Hour
+	retentionTimeout         = 5 * time.Minute
+	// retentionSizeStep is the share of the remaining rows deleted per round
+	// while the database exceeds its size limit.
+	retentionSizeStep = 10
+)
+
+var metricAuditPrunedRows = []string{"audit", "pruned_rows"}
+
+// SetRetention starts a background job that prunes the store according to
+// the retention configuration, once at start and then every interval. It
+// replaces a job started before.
+//
+// Summary: Enables retention of the audit database.
+//
+// Parameters:
+//   - config: *configv1.RetentionConfig. The retention limits.
+//
+// Returns:
+//   - error: Always nil.
+//
+// Side Effects:
+//   - Starts a background goroutine, stopped by Close.
+func (s *SQLiteAuditStore) SetRetention(config *configv1.RetentionConfig) error {
+	s.stopRetention()
+	interval := config.GetInterval().AsDuration()
+	if interval <= 0 {
+		interval = defaultRetentionInterval
+	}
+	stop := make(chan struct{})
+	s.retentionStop = stop
+	s.retentionWG.Add(1)
+	go func() {
+		defer s.retentionWG.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), retentionTimeout)
+			if _, err := s.Prune(ctx, config); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to prune audit database: %v\n", err)
+			}
+			cancel()
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (s *SQLiteAuditStore) stopRetention() {
+	if s.retentionStop != nil {
+		close(s.retentionStop)
+		s.retentionWG.Wait()
+		s.retentionStop = nil
+	}
+}
+
+// Prune deletes the oldest entries that exceed the retention limits and
+// vacuums the database if any were deleted.
+//
+// Summary: Applies the retention limits to the audit database.
+//
+// Only a prefix of the hash chain is deleted. The hash of the last deleted
+// entry is kept in the audit_pruned table, so the remaining chain still
+// verifies; checkpoints of deleted entries are deleted with them.
+//
+// Parameters:
+//   - ctx: context.Context. The context for the queries.
+//   - config: *configv1.RetentionConfig. The retention limits.
+//
+// Returns:
+//   - int64: The number of deleted entries.
+//   - error: An error if the database cannot be pruned.
+//
+// Side Effects:
+//   - Deletes rows and runs VACUUM.
+//   - Increments the audit_pruned_rows metric.
+func (s *SQLiteAuditStore) Prune(ctx context.Context, config *configv1.RetentionConfig) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var maxID sql.NullInt64
+	if err := s.db.QueryRowContext(ctx, "SELECT MAX(id) FROM audit_logs").Scan(&maxID); err != nil {
+		return 0, fmt.Errorf("failed to read audit log size: %w", err)
+	}
+	if !maxID.Valid {
+		return 0, nil
+	}
+
+	var through int64
+	if maxAge := config.GetMaxAge().AsDuration(); maxAge > 0 {
+		cutoff := time.Now().Add(-maxAge).Format(time.RFC3339Nano)
+		if err := s.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(id), 0) FROM audit_logs WHERE julianday(timestamp) < julianday(?)", cutoff).Scan(&through); err != nil {
+			return 0, fmt.Errorf("failed to find expired audit entries: %w", err)
+		}
+	}
+	if maxRows := config.GetMaxRows(); maxRows > 0 && maxID.Int64-maxRows > through {
+		through = maxID.Int64 - maxRows
+	}
+
+	var pruned int64
+	if through > 0 {
+		n, err := s.pruneThrough(ctx, through)
+		if err != nil {
+			return 0, err
+		}
+		pruned += n
+	}
+	if maxSize := config.GetMaxSizeBytes(); maxSize > 0 {
+		n, err := s.pruneToSize(ctx, maxSize)
+		pruned += n
+		if err != nil {
+			return pruned, err
+		}
+	}
+
+	if pruned > 0 {
+		metrics.IncrCounter(metricAuditPrunedRows, float32(pruned))
+		if _, err := s.db.ExecContext(ctx, "VACUUM"); err != nil {
+			return pruned, fmt.Errorf("failed to vacuum audit database: %w", err)
+		}
+	}
+	return pruned, nil
+}
+
+// pruneToSize deletes the oldest entries while the used size of the database
+// exceeds maxSize.
+func (s *SQLiteAuditStore) pruneToSize(ctx context.Context, maxSize int64) (int64, error) {
+	var pruned int64
+	for {
+		used, err := usedBytes(ctx, s.db)
+		if err != nil {
+			return pruned, err
+		}
+		if used <= maxSize {
+			return pruned, nil
+		}
+		var count int64
+		if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_logs").Scan(&count); err != nil {
+			return pruned, fmt.Errorf("failed to count audit entries: %w", err)
+		}
+		if count == 0 {
+			return pruned, nil
+		}
+		var through int64
+		offset := max(count/retentionSizeStep, 1) - 1
+		if err := s.db.QueryRowContext(ctx, "SELECT id FROM audit_logs ORDER BY id ASC LIMIT 1 OFFSET ?", offset).Scan(&through); err != nil {
+			return pruned, fmt.Errorf("failed to find oldest audit entries: %w", err)
+		}
+		n, err := s.pruneThrough(ctx, through)
+		pruned += n
+		if err != nil {
+			return pruned, err
+		}
+	}
+}
+
+// pruneThrough deletes the entries up to and including the sequence number
+// through, and records the hash of the last deleted entry.
+func (s *SQLiteAuditStore) pruneThrough(ctx context.Context, through int64) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin prune transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var lastSeq int64
+	var lastHash string
+	err = tx.QueryRowContext(ctx, "SELECT id, COALESCE(hash, '') FROM audit_logs WHERE id <= ? ORDER BY id DESC LIMIT 1", through).Scan(&lastSeq, &lastHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read last pruned audit entry: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM audit_logs WHERE id <= ?", lastSeq)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete audit entries: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM audit_checkpoints WHERE seq <= ?", lastSeq); err != nil {
+		return 0, fmt.Errorf("failed to delete audit checkpoints: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+	INSERT INTO audit_pruned (id, through_seq, last_hash, pruned) VALUES (1, ?, ?, ?)
+	ON CONFLICT(id) DO UPDATE SET through_seq = excluded.through_seq, last_hash = excluded.last_hash, pruned = audit_pruned.pruned + excluded.pruned
+	`, lastSeq, lastHash, n); err != nil {
+		return 0, fmt.Errorf("failed to record pruned audit entries: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit prune transaction: %w", err)
+	}
+	return n, nil
+}
+
+// readPruned returns the hash of the last pruned entry and the number of
+// pruned entries.
+func (s *SQLiteAuditStore) readPruned(ctx context.Context) (string, int64, error) {
+	// Databases written by older versions have no audit_pruned table.
+	var tables int
+	if err := s.db.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'audit_pruned'").Scan(&tables); err != nil {
+		return "", 0, fmt.Errorf("failed to read pruned audit entries: %w", err)
+	}
+	if tables == 0 {
+		return "", 0, nil
+	}
+	var lastHash string
+	var pruned int64
+	err := s.db.QueryRowContext(ctx, "SELECT last_hash, pruned FROM audit_pruned WHERE id = 1").Scan(&lastHash, &pruned)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", 0, fmt.Errorf("failed to read pruned audit entries: %w", err)
+	}
+	return lastHash, pruned, nil
+}
+
+// usedBytes returns the size of the pages of a SQLite database that hold
+// data, which is the size of the file after a VACUUM.
+func usedBytes(ctx context.Context, db *sql.DB) (int64, error) {
+	var pageCount, freePages, pageSize int64
+	if err := db.QueryRowContext(ctx, "PRAGMA page_count").Scan(&pageCount); err != nil {
+		return 0, fmt.Errorf("failed to read database size: %w", err)
+	}
+	if err := db.QueryRowContext(ctx, "PRAGMA freelist_count").Scan(&freePages); err != nil {
+		return 0, fmt.Errorf("failed to read database size: %w", err)
+	}
+	if err := db.QueryRowContext(ctx, "PRAGMA page_size").Scan(&pageSize); err != nil {
+		return 0, fmt.Errorf("failed to read database size: %w", err)
+	}
+	return (pageCount - freePages) * pageSize, nil
+}
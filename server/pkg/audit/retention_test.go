@@ -0,0 +1,117 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func newTestSQLiteStore(t *testing.T) *SQLiteAuditStore {
+	t.Helper()
+	dir := t.TempDir()
+	validation.SetAllowedPaths([]string{dir})
+	t.Cleanup(func() { validation.SetAllowedPaths(nil) })
+	store, err := NewSQLiteAuditStore(filepath.Join(dir, "audit.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func writeTestEntries(t *testing.T, store *SQLiteAuditStore, ts time.Time, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		require.NoError(t, store.Write(context.Background(), Entry{Timestamp: ts, ToolName: "weather.get", DurationMs: int64(i)}))
+	}
+}
+
+func TestSQLiteAuditStore_PruneMaxRows(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+	writeTestEntries(t, store, time.Now(), 5)
+
+	pruned, err := store.Prune(ctx, configv1.RetentionConfig_builder{MaxRows: proto.Int64(2)}.Build())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), pruned)
+
+	entries, err := store.Read(ctx, Filter{})
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	// The remaining chain, and entries written after pruning, still verify.
+	writeTestEntries(t, store, time.Now(), 1)
+	report, err := store.VerifyChain(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), report.Entries)
+	assert.Equal(t, int64(3), report.Pruned)
+}
+
+func TestSQLiteAuditStore_PruneMaxAge(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+	writeTestEntries(t, store, time.Now().Add(-48*time.Hour), 3)
+	writeTestEntries(t, store, time.Now(), 2)
+
+	pruned, err := store.Prune(ctx, configv1.RetentionConfig_builder{MaxAge: durationpb.New(24 * time.Hour)}.Build())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), pruned)
+
+	report, err := store.VerifyChain(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), report.Entries)
+}
+
+func TestSQLiteAuditStore_PruneAll(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+	writeTestEntries(t, store, time.Now().Add(-48*time.Hour), 3)
+
+	pruned, err := store.Prune(ctx, configv1.RetentionConfig_builder{MaxAge: durationpb.New(time.Hour)}.Build())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), pruned)
+
+	// The first entry after an empty table chains to the last pruned entry.
+	writeTestEntries(t, store, time.Now(), 1)
+	report, err := store.VerifyChain(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), report.Entries)
+}
+
+func TestSQLiteAuditStore_PruneMaxSize(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+	writeTestEntries(t, store, time.Now(), 2000)
+
+	pruned, err := store.Prune(ctx, configv1.RetentionConfig_builder{MaxSizeBytes: proto.Int64(64 * 1024)}.Build())
+	require.NoError(t, err)
+	assert.Positive(t, pruned)
+
+	used, err := usedBytes(ctx, store.db)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, used, int64(64*1024))
+
+	_, err = store.VerifyChain(ctx, nil)
+	require.NoError(t, err)
+}
+
+func TestSQLiteAuditStore_SetRetention(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	writeTestEntries(t, store, time.Now(), 3)
+
+	require.NoError(t, store.SetRetention(configv1.RetentionConfig_builder{MaxRows: proto.Int64(1)}.Build()))
+	require.Eventually(t, func() bool {
+		entries, err := store.Read(context.Background(), Filter{})
+		return err == nil && len(entries) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, store.Close())
+}
@@ -32,6 +32,8 @@ type SplunkAuditStore struct {
 	queue  chan Entry
 	wg     sync.WaitGroup
 	done   chan struct{}
+	// flushes holds, for each worker, the flush requests it acknowledges.
+	flushes []chan chan struct{}
 }
 
 // NewSplunkAuditStore creates a new SplunkAuditStore.
@@ -60,14 +62,16 @@ func NewSplunkAuditStore(config *configv1.SplunkConfig) *SplunkAuditStore {
 	}
 
 	for i := 0; i < splunkWorkers; i++ {
+		flush := make(chan chan struct{})
+		store.flushes = append(store.flushes, flush)
 		store.wg.Add(1)
-		go store.worker()
+		go store.worker(flush)
 	}
 
 	return store
 }
 
-func (e *SplunkAuditStore) worker() {
+func (e *SplunkAuditStore) worker(flush chan chan struct{}) {
 	defer e.wg.Done()
 	var batch []Entry
 	ticker := time.NewTicker(splunkBatchWait)
@@ -90,6 +94,11 @@ func (e *SplunkAuditStore) worker() {
 				e.sendBatch(batch)
 				batch = nil
 			}
+		case ack := <-flush:
+			batch = takeQueued(e.queue, batch, splunkBatchSize, e.sendBatch)
+			e.sendBatch(batch)
+			batch = nil
+			close(ack)
 		case <-e.done:
 			// Drain queue
 			for entry := range e.queue {
@@ -176,7 +185,6 @@ func (e *SplunkAuditStore) sendBatch(batch []Entry) {
 	}
 }
 
-
 // Read implements the Store interface.
 //
 // Summary: Reads audit entries (Not implemented).
@@ -212,3 +220,16 @@ func (e *SplunkAuditStore) Close() error {
 	e.wg.Wait()
 	return nil
 }
+
+// Flush implements the Flusher interface.
+//
+// Summary: Sends the queued and batched entries to Splunk.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the wait.
+//
+// Returns:
+//   - error: An error if ctx is done before the workers sent their batches.
+func (e *SplunkAuditStore) Flush(ctx context.Context) error {
+	return flushWorkers(ctx, e.flushes, e.done)
+}
@@ -5,12 +5,15 @@ package audit
 
 import (
 	"context"
+	"crypto/ed25519"
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"os"
 	"sync"
 	"time"
 
+	"github.com/mcpany/core/server/pkg/storage/migrate"
 	"github.com/mcpany/core/server/pkg/validation"
 
 	// modernc.org/sqlite is a pure Go SQLite driver.
@@ -21,8 +24,12 @@ import (
 //
 // Summary: Stores audit logs in a local SQLite database with tamper-evident hashing.
 type SQLiteAuditStore struct {
-	db *sql.DB
-	mu sync.Mutex
+	db     *sql.DB
+	mu     sync.Mutex
+	signer *CheckpointSigner
+
+	retentionStop chan struct{}
+	retentionWG   sync.WaitGroup
 }
 
 // NewSQLiteAuditStore creates a new SQLiteAuditStore.
@@ -43,9 +50,9 @@ type SQLiteAuditStore struct {
 //
 // Side Effects:
 //   - Opens (or creates) the SQLite database file.
-//   - Creates the 'audit_logs' table.
+//   - Applies the pending migrations of the audit schema, creating the
+//     'audit_logs' table and its indexes.
 //   - Optimizes database with PRAGMA settings.
-//   - Adds missing columns if schema migration is needed.
 func NewSQLiteAuditStore(path string) (*SQLiteAuditStore, error) {
 	if path == "" {
 		return nil, fmt.Errorf("sqlite path is required")
@@ -60,28 +67,7 @@ func NewSQLiteAuditStore(path string) (*SQLiteAuditStore, error) {
 		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
 	}
 
-	// Create table if not exists
-	schema := `
-	CREATE TABLE IF NOT EXISTS audit_logs (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		timestamp TEXT,
-		tool_name TEXT,
-		user_id TEXT,
-		profile_id TEXT,
-		trace_id TEXT,
-		span_id TEXT,
-		parent_id TEXT,
-		arguments TEXT,
-		result TEXT,
-		error TEXT,
-		duration_ms INTEGER,
-		prev_hash TEXT,
-		hash TEXT
-	);
-	`
-	ctxSchema, cancelSchema := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancelSchema()
-	if _, err := db.ExecContext(ctxSchema, schema); err != nil {
+	if err := migrateSQLiteAuditStore(db); err != nil {
 		_ = db.Close() // Best effort close
 		return nil, fmt.Errorf("failed to create audit_logs table: %w", err)
 	}
@@ -102,42 +88,161 @@ func NewSQLiteAuditStore(path string) (*SQLiteAuditStore, error) {
 		return nil, fmt.Errorf("failed to set busy_timeout: %w", err)
 	}
 
-	// Ensure columns exist (for migration)
-	if err := ensureColumns(db); err != nil {
-		_ = db.Close()
-		return nil, fmt.Errorf("failed to ensure columns: %w", err)
-	}
-
 	return &SQLiteAuditStore{
 		db: db,
 	}, nil
 }
 
-func ensureColumns(db *sql.DB) error {
-	if err := ensureColumn(db, "prev_hash"); err != nil {
-		return err
+// sqliteAuditMigrationsTable records the applied migrations of the audit
+// schema, apart from those of the storage, since the audit logs may share
+// its database.
+const sqliteAuditMigrationsTable = "audit_schema_migrations"
+
+// sqliteAuditMigrations are the schema versions of the audit logs. Append
+// new migrations; never edit or renumber the released ones.
+var sqliteAuditMigrations = []migrate.Migration{
+	{
+		// The table created before migrations were versioned. IF NOT EXISTS
+		// adopts the databases created by those releases, once
+		// adoptSQLiteAuditSchema added the columns they may lack.
+		// The indexes back the filters of Read, each with the ordering by
+		// timestamp.
+		Version: 1,
+		Name:    "create_audit_logs",
+		Up: `
+		CREATE TABLE IF NOT EXISTS audit_logs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			entry_id TEXT,
+			timestamp TEXT,
+			tool_name TEXT,
+			user_id TEXT,
+			profile_id TEXT,
+			api_key_id TEXT,
+			trace_id TEXT,
+			span_id TEXT,
+			parent_id TEXT,
+			labels TEXT,
+			arguments TEXT,
+			result TEXT,
+			error TEXT,
+			duration_ms INTEGER,
+			prev_hash TEXT,
+			hash TEXT
+		);
+		CREATE INDEX IF NOT EXISTS idx_audit_logs_timestamp ON audit_logs (timestamp);
+		CREATE INDEX IF NOT EXISTS idx_audit_logs_tool_name ON audit_logs (tool_name, timestamp);
+		CREATE INDEX IF NOT EXISTS idx_audit_logs_user_id ON audit_logs (user_id, timestamp);
+		CREATE INDEX IF NOT EXISTS idx_audit_logs_profile_id ON audit_logs (profile_id, timestamp);
+		CREATE INDEX IF NOT EXISTS idx_audit_logs_entry_id ON audit_logs (entry_id);
+		`,
+		Down: `
+		DROP TABLE IF EXISTS audit_logs;
+		`,
+	},
+	{
+		// The signed checkpoints of the hash chain, and the end of the chain
+		// deleted by the retention policy.
+		Version: 2,
+		Name:    "create_audit_checkpoints",
+		Up: `
+		CREATE TABLE IF NOT EXISTS audit_checkpoints (
+			seq INTEGER PRIMARY KEY,
+			hash TEXT NOT NULL,
+			timestamp TEXT NOT NULL,
+			signature TEXT NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS audit_pruned (
+			id INTEGER PRIMARY KEY CHECK (id = 1),
+			through_seq INTEGER NOT NULL,
+			last_hash TEXT NOT NULL,
+			pruned INTEGER NOT NULL
+		);
+		`,
+		Down: `
+		DROP TABLE IF EXISTS audit_pruned;
+		DROP TABLE IF EXISTS audit_checkpoints;
+		`,
+	},
+}
+
+// migrateSQLiteAuditStore applies the pending migrations of the audit
+// schema.
+func migrateSQLiteAuditStore(db *sql.DB) error {
+	if err := adoptSQLiteAuditSchema(db); err != nil {
+		return fmt.Errorf("failed to ensure columns: %w", err)
 	}
-	if err := ensureColumn(db, "hash"); err != nil {
+	m, err := migrate.New(db, migrate.SQLite, sqliteAuditMigrations, migrate.WithTable(sqliteAuditMigrationsTable))
+	if err != nil {
 		return err
 	}
-	if err := ensureColumn(db, "trace_id"); err != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	_, err = m.Up(ctx)
+	return err
+}
+
+// adoptSQLiteAuditSchema adds the columns missing from an audit_logs table
+// created before migrations were versioned, so that the first migration
+// finds the table it creates. Databases that record their migrations are
+// left to them.
+func adoptSQLiteAuditSchema(db *sql.DB) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var logs, versioned int
+	err := db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE name = 'audit_logs'),
+			COUNT(*) FILTER (WHERE name = ?)
+		FROM sqlite_master WHERE type = 'table'`,
+		sqliteAuditMigrationsTable).Scan(&logs, &versioned)
+	if err != nil {
 		return err
 	}
-	if err := ensureColumn(db, "span_id"); err != nil {
-		return err
+	if logs == 0 || versioned > 0 {
+		return nil
 	}
-	if err := ensureColumn(db, "parent_id"); err != nil {
-		return err
+	return ensureColumns(db)
+}
+
+// sqliteAuditColumns are the columns of audit_logs that ensureColumns adds
+// to a table created before migrations were versioned, with their types.
+var sqliteAuditColumns = []struct{ name, typ string }{
+	{"entry_id", "TEXT DEFAULT ''"},
+	{"timestamp", "TEXT"},
+	{"tool_name", "TEXT"},
+	{"user_id", "TEXT"},
+	{"profile_id", "TEXT"},
+	{"api_key_id", "TEXT DEFAULT ''"},
+	{"trace_id", "TEXT DEFAULT ''"},
+	{"span_id", "TEXT DEFAULT ''"},
+	{"parent_id", "TEXT DEFAULT ''"},
+	{"labels", "TEXT DEFAULT ''"},
+	{"arguments", "TEXT"},
+	{"result", "TEXT"},
+	{"error", "TEXT"},
+	{"duration_ms", "INTEGER"},
+	{"prev_hash", "TEXT DEFAULT ''"},
+	{"hash", "TEXT DEFAULT ''"},
+}
+
+func ensureColumns(db *sql.DB) error {
+	for _, col := range sqliteAuditColumns {
+		if err := ensureColumn(db, col.name); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
 func ensureColumn(db *sql.DB, colName string) error {
 	// Whitelist valid column names to prevent SQL injection even from internal calls
-	switch colName {
-	case "prev_hash", "hash", "trace_id", "span_id", "parent_id":
-		// Allowed
-	default:
+	colType := ""
+	for _, col := range sqliteAuditColumns {
+		if col.name == colName {
+			colType = col.typ
+		}
+	}
+	if colType == "" {
 		return fmt.Errorf("invalid column name: %s", colName)
 	}
 
@@ -151,7 +256,7 @@ func ensureColumn(db *sql.DB, colName string) error {
 	}
 	// Add column
 
-	query = fmt.Sprintf("ALTER TABLE audit_logs ADD COLUMN %s TEXT DEFAULT ''", colName)
+	query = fmt.Sprintf("ALTER TABLE audit_logs ADD COLUMN %s %s", colName, colType)
 	ctxAlter, cancelAlter := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancelAlter()
 	_, err := db.ExecContext(ctxAlter, query)
@@ -181,6 +286,13 @@ func (s *SQLiteAuditStore) Write(ctx context.Context, entry Entry) error {
 		argsJSON = string(entry.Arguments)
 	}
 
+	var labelsJSON string
+	if len(entry.Labels) > 0 {
+		if b, err := json.Marshal(entry.Labels); err == nil {
+			labelsJSON = string(b)
+		}
+	}
+
 	resultJSON := "{}"
 	if entry.Result != nil {
 		if b, err := json.Marshal(entry.Result); err == nil {
@@ -198,7 +310,10 @@ func (s *SQLiteAuditStore) Write(ctx context.Context, entry Entry) error {
 		return fmt.Errorf("failed to get previous hash: %w", err)
 	}
 	if err == sql.ErrNoRows {
-		prevHash = "" // First entry
+		// First entry, or the first after retention pruned all entries.
+		if prevHash, _, err = s.readPruned(ctx); err != nil {
+			return err
+		}
 	}
 
 	// Compute hash
@@ -206,18 +321,21 @@ func (s *SQLiteAuditStore) Write(ctx context.Context, entry Entry) error {
 
 	query := `
 	INSERT INTO audit_logs (
-		timestamp, tool_name, user_id, profile_id, trace_id, span_id, parent_id, arguments, result, error, duration_ms, prev_hash, hash
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		entry_id, timestamp, tool_name, user_id, profile_id, api_key_id, trace_id, span_id, parent_id, labels, arguments, result, error, duration_ms, prev_hash, hash
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err = s.db.ExecContext(ctx, query,
+	res, err := s.db.ExecContext(ctx, query,
+		entry.ID,
 		ts,
 		entry.ToolName,
 		entry.UserID,
 		entry.ProfileID,
+		entry.APIKeyID,
 		entry.TraceID,
 		entry.SpanID,
 		entry.ParentID,
+		labelsJSON,
 		argsJSON,
 		resultJSON,
 		entry.Error,
@@ -225,7 +343,39 @@ func (s *SQLiteAuditStore) Write(ctx context.Context, entry Entry) error {
 		prevHash,
 		hash,
 	)
-	return err
+	if err != nil || s.signer == nil {
+		return err
+	}
+	seq, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get audit entry id: %w", err)
+	}
+	if !s.signer.due(seq) {
+		return nil
+	}
+	c := s.signer.sign(seq, hash, time.Now())
+	if _, err := s.db.ExecContext(ctx, "INSERT INTO audit_checkpoints (seq, hash, timestamp, signature) VALUES (?, ?, ?, ?)",
+		c.Seq, c.Hash, c.Timestamp.Format(time.RFC3339Nano), c.Signature); err != nil {
+		return fmt.Errorf("failed to write audit checkpoint: %w", err)
+	}
+	return nil
+}
+
+// SetCheckpointSigner enables signed checkpoints. The sequence number of an
+// entry is its row id; checkpoints are stored in the audit_checkpoints table.
+//
+// Summary: Enables signed checkpoints of the hash chain.
+//
+// Parameters:
+//   - signer: *CheckpointSigner. The signer.
+//
+// Returns:
+//   - error: Always nil.
+func (s *SQLiteAuditStore) SetCheckpointSigner(signer *CheckpointSigner) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.signer = signer
+	return nil
 }
 
 // Read reads audit entries from the database based on the filter.
@@ -246,7 +396,7 @@ func (s *SQLiteAuditStore) Read(ctx context.Context, filter Filter) ([]Entry, er
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	query := "SELECT timestamp, tool_name, user_id, profile_id, trace_id, span_id, parent_id, arguments, result, error, duration_ms FROM audit_logs WHERE 1=1"
+	query := "SELECT COALESCE(entry_id, ''), timestamp, tool_name, user_id, profile_id, COALESCE(api_key_id, ''), trace_id, span_id, parent_id, COALESCE(labels, ''), arguments, result, error, duration_ms FROM audit_logs WHERE 1=1"
 	var args []any
 
 	if filter.StartTime != nil {
@@ -269,6 +419,23 @@ func (s *SQLiteAuditStore) Read(ctx context.Context, filter Filter) ([]Entry, er
 		query += " AND profile_id = ?"
 		args = append(args, filter.ProfileID)
 	}
+	if filter.APIKeyID != "" {
+		query += " AND api_key_id = ?"
+		args = append(args, filter.APIKeyID)
+	}
+	if filter.ID != "" {
+		query += " AND entry_id = ?"
+		args = append(args, filter.ID)
+	}
+	if filter.ErrorsOnly {
+		query += " AND COALESCE(error, '') != ''"
+	}
+	if filter.Query != "" {
+		// LIKE ignores the case of ASCII letters in SQLite.
+		query += ` AND (tool_name LIKE ? ESCAPE '\' OR arguments LIKE ? ESCAPE '\' OR result LIKE ? ESCAPE '\' OR error LIKE ? ESCAPE '\')`
+		pattern := likePattern(filter.Query)
+		args = append(args, pattern, pattern, pattern, pattern)
+	}
 
 	query += " ORDER BY timestamp DESC"
 
@@ -290,12 +457,15 @@ func (s *SQLiteAuditStore) Read(ctx context.Context, filter Filter) ([]Entry, er
 	var entries []Entry
 	for rows.Next() {
 		var entry Entry
-		var tsStr, argsStr, resultStr string
-		if err := rows.Scan(&tsStr, &entry.ToolName, &entry.UserID, &entry.ProfileID, &entry.TraceID, &entry.SpanID, &entry.ParentID, &argsStr, &resultStr, &entry.Error, &entry.DurationMs); err != nil {
+		var tsStr, labelsStr, argsStr, resultStr string
+		if err := rows.Scan(&entry.ID, &tsStr, &entry.ToolName, &entry.UserID, &entry.ProfileID, &entry.APIKeyID, &entry.TraceID, &entry.SpanID, &entry.ParentID, &labelsStr, &argsStr, &resultStr, &entry.Error, &entry.DurationMs); err != nil {
 			return nil, err
 		}
 
 		entry.Timestamp, _ = time.Parse(time.RFC3339Nano, tsStr)
+		if labelsStr != "" {
+			_ = json.Unmarshal([]byte(labelsStr), &entry.Labels)
+		}
 		entry.Arguments = json.RawMessage(argsStr)
 		if resultStr != "" && resultStr != "{}" {
 			_ = json.Unmarshal([]byte(resultStr), &entry.Result)
@@ -325,29 +495,63 @@ func (s *SQLiteAuditStore) Read(ctx context.Context, filter Filter) ([]Entry, er
 // Side Effects:
 //   - Scans the entire audit_logs table.
 func (s *SQLiteAuditStore) Verify() (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if _, err := s.VerifyChain(ctx, nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// VerifyChain checks the hash chain of all audit entries and the checkpoints.
+//
+// Summary: Validates the tamper evidence of the audit database.
+//
+// Parameters:
+//   - ctx: context.Context. The context for the scan.
+//   - pub: ed25519.PublicKey. The key verifying the checkpoint signatures, or nil to only match their hashes.
+//
+// Returns:
+//   - *VerifyReport: A summary of the verified log.
+//   - error: An error describing the first integrity violation, or if the database cannot be read.
+func (s *SQLiteAuditStore) VerifyChain(ctx context.Context, pub ed25519.PublicKey) (*VerifyReport, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	checkpoints, err := s.readCheckpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make(map[int64]string, len(checkpoints))
+	for _, c := range checkpoints {
+		hashes[c.Seq] = ""
+	}
+
+	// The chain continues from the last entry deleted by retention.
+	lastHash, pruned, err := s.readPruned(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := &VerifyReport{Pruned: pruned, LastHash: lastHash}
+
 	rows, err := s.db.QueryContext(ctx, "SELECT id, timestamp, tool_name, user_id, profile_id, arguments, result, error, duration_ms, prev_hash, hash FROM audit_logs ORDER BY id ASC")
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	defer func() { _ = rows.Close() }()
 
-	var expectedPrevHash string
+	var lastID int64
 	for rows.Next() {
 		var id int64
 		var ts, toolName, userID, profileID, args, result, errorMsg, prevHash, hash string
 		var durationMs int64
 
 		if err := rows.Scan(&id, &ts, &toolName, &userID, &profileID, &args, &result, &errorMsg, &durationMs, &prevHash, &hash); err != nil {
-			return false, fmt.Errorf("scan error at id %d: %w", id, err)
+			return report, fmt.Errorf("scan error at id %d: %w", id, err)
 		}
 
-		if prevHash != expectedPrevHash {
-			return false, fmt.Errorf("integrity violation at id %d: prev_hash mismatch (expected %q, got %q)", id, expectedPrevHash, prevHash)
+		if prevHash != report.LastHash {
+			return report, fmt.Errorf("integrity violation at id %d: prev_hash mismatch (expected %q, got %q)", id, report.LastHash, prevHash)
 		}
 
 		// Check hash version
@@ -360,15 +564,87 @@ func (s *SQLiteAuditStore) Verify() (bool, error) {
 		}
 
 		if calculatedHash != hash {
-			return false, fmt.Errorf("integrity violation at id %d: hash mismatch (calculated %q, got %q)", id, calculatedHash, hash)
+			return report, fmt.Errorf("integrity violation at id %d: hash mismatch (calculated %q, got %q)", id, calculatedHash, hash)
 		}
 
-		expectedPrevHash = hash
+		if _, ok := hashes[id]; ok {
+			hashes[id] = hash
+		}
+		report.Entries++
+		report.LastHash = hash
+		lastID = id
 	}
 	if err := rows.Err(); err != nil {
-		return false, err
+		return report, err
 	}
-	return true, nil
+
+	err = verifyCheckpoints(report, checkpoints, func(seq int64) (string, bool) {
+		if seq > lastID {
+			return "", false
+		}
+		// A checkpointed row that was deleted breaks the chain, so every
+		// checkpoint up to the last row refers to a verified row.
+		return hashes[seq], true
+	}, pub)
+	return report, err
+}
+
+// VerifySQLite checks the hash chain and checkpoints of an existing audit
+// database without modifying it.
+//
+// Summary: Validates the tamper evidence of an audit database file.
+//
+// Parameters:
+//   - ctx: context.Context. The context for the scan.
+//   - path: string. The path of the SQLite database.
+//   - pub: ed25519.PublicKey. The key verifying the checkpoint signatures, or nil to only match their hashes.
+//
+// Returns:
+//   - *VerifyReport: A summary of the verified log.
+//   - error: An error describing the first integrity violation, or if the database cannot be read.
+func VerifySQLite(ctx context.Context, path string, pub ed25519.PublicKey) (*VerifyReport, error) {
+	// Opening a missing file would create an empty database.
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+	s := &SQLiteAuditStore{db: db}
+	return s.VerifyChain(ctx, pub)
+}
+
+// readCheckpoints reads all checkpoints in order.
+func (s *SQLiteAuditStore) readCheckpoints(ctx context.Context) ([]Checkpoint, error) {
+	// Databases written by older versions have no checkpoints table.
+	var tables int
+	if err := s.db.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'audit_checkpoints'").Scan(&tables); err != nil {
+		return nil, fmt.Errorf("failed to read audit checkpoints: %w", err)
+	}
+	if tables == 0 {
+		return nil, nil
+	}
+	rows, err := s.db.QueryContext(ctx, "SELECT seq, hash, timestamp, signature FROM audit_checkpoints ORDER BY seq ASC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit checkpoints: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var checkpoints []Checkpoint
+	for rows.Next() {
+		var c Checkpoint
+		var ts string
+		if err := rows.Scan(&c.Seq, &c.Hash, &ts, &c.Signature); err != nil {
+			return nil, fmt.Errorf("failed to scan audit checkpoint: %w", err)
+		}
+		if c.Timestamp, err = time.Parse(time.RFC3339Nano, ts); err != nil {
+			return nil, fmt.Errorf("malformed timestamp of checkpoint at seq %d: %w", c.Seq, err)
+		}
+		checkpoints = append(checkpoints, c)
+	}
+	return checkpoints, rows.Err()
 }
 
 // Close closes the database connection.
@@ -381,6 +657,7 @@ func (s *SQLiteAuditStore) Verify() (bool, error) {
 // Side Effects:
 //   - Closes the DB connection.
 func (s *SQLiteAuditStore) Close() error {
+	s.stopRetention()
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	return s.db.Close()
@@ -114,6 +114,57 @@ func TestSQLiteAuditStore(t *testing.T) {
 	assert.True(t, valid)
 }
 
+func TestSQLiteAuditStore_MigratesLegacySchema(t *testing.T) {
+	f, err := os.CreateTemp("", "audit_legacy_*.db")
+	require.NoError(t, err)
+	dbPath := f.Name()
+	f.Close()
+	defer os.Remove(dbPath)
+
+	validation.SetAllowedPaths([]string{os.TempDir()})
+	defer validation.SetAllowedPaths(nil)
+
+	// A table created before the hash chain and the entry fields.
+	db, err := sql.Open("sqlite", dbPath)
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec(`CREATE TABLE audit_logs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		timestamp TEXT,
+		tool_name TEXT,
+		user_id TEXT,
+		profile_id TEXT,
+		arguments TEXT,
+		result TEXT,
+		error TEXT,
+		duration_ms INTEGER
+	)`)
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO audit_logs (timestamp, tool_name) VALUES ('2023-10-27T12:00:00Z', 'old_tool')")
+	require.NoError(t, err)
+
+	// Reopening must not apply the migrations again.
+	for i := 0; i < 2; i++ {
+		store, err := NewSQLiteAuditStore(dbPath)
+		require.NoError(t, err)
+		require.NoError(t, store.Write(context.Background(), Entry{
+			ID:        fmt.Sprintf("entry-%d", i),
+			Timestamp: time.Date(2023, 10, 28, 12, 0, 0, 0, time.UTC),
+			ToolName:  "new_tool",
+			APIKeyID:  "key-1",
+		}))
+		require.NoError(t, store.Close())
+	}
+
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM audit_logs WHERE api_key_id = 'key-1'").Scan(&count))
+	assert.Equal(t, 2, count)
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM audit_schema_migrations").Scan(&count))
+	assert.Equal(t, len(sqliteAuditMigrations), count)
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = 'idx_audit_logs_entry_id'").Scan(&count))
+	assert.Equal(t, 1, count)
+}
+
 func TestSQLiteAuditStore_TamperEvident(t *testing.T) {
 	// Create a temporary database file
 	f, err := os.CreateTemp("", "audit_tamper_*.db")
@@ -676,3 +727,132 @@ func TestSQLiteAuditStore_Read(t *testing.T) {
 	// Offset 1 -> 1h(tool2)
 	assert.Equal(t, "tool2", results[0].ToolName)
 }
+
+func TestSQLiteAuditStore_EntryID(t *testing.T) {
+	f, err := os.CreateTemp("", "audit_entry_id_*.db")
+	require.NoError(t, err)
+	dbPath := f.Name()
+	f.Close()
+	defer os.Remove(dbPath)
+
+	validation.SetAllowedPaths([]string{os.TempDir()})
+	defer validation.SetAllowedPaths(nil)
+
+	store, err := NewSQLiteAuditStore(dbPath)
+	require.NoError(t, err)
+	defer store.Close()
+
+	entry := Entry{
+		ID:         "01920000-0000-7000-8000-000000000000.4bf92f3577b34da6a3ce929d0e0e4736",
+		Timestamp:  time.Now(),
+		ToolName:   "tool",
+		TraceID:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		DurationMs: 1,
+	}
+	require.NoError(t, store.Write(context.Background(), entry))
+
+	results, err := store.Read(context.Background(), Filter{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, entry.ID, results[0].ID)
+
+	valid, err := store.Verify()
+	assert.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestSQLiteAuditStore_ReadQuery(t *testing.T) {
+	f, err := os.CreateTemp("", "audit_query_*.db")
+	require.NoError(t, err)
+	dbPath := f.Name()
+	f.Close()
+	defer os.Remove(dbPath)
+
+	validation.SetAllowedPaths([]string{os.TempDir()})
+	defer validation.SetAllowedPaths(nil)
+
+	store, err := NewSQLiteAuditStore(dbPath)
+	require.NoError(t, err)
+	defer store.Close()
+
+	now := time.Now()
+	for _, e := range []Entry{
+		{ID: "a", Timestamp: now, ToolName: "weather.get_forecast", Arguments: json.RawMessage(`{"city":"Paris"}`)},
+		{ID: "b", Timestamp: now.Add(time.Second), ToolName: "weather.get_forecast", Error: "upstream timed out"},
+		{ID: "c", Timestamp: now.Add(2 * time.Second), ToolName: "shop.discount", Arguments: json.RawMessage(`{"code":"50%_off"}`)},
+		{ID: "d", Timestamp: now.Add(3 * time.Second), ToolName: "shop.discount", Arguments: json.RawMessage(`{"code":"50X-off"}`)},
+	} {
+		require.NoError(t, store.Write(context.Background(), e))
+	}
+	ids := func(filter Filter) []string {
+		results, err := store.Read(context.Background(), filter)
+		require.NoError(t, err)
+		var got []string
+		for _, r := range results {
+			got = append(got, r.ID)
+		}
+		return got
+	}
+
+	assert.Equal(t, []string{"b"}, ids(Filter{ID: "b"}))
+	assert.Empty(t, ids(Filter{ID: "missing"}))
+	assert.Equal(t, []string{"b"}, ids(Filter{ErrorsOnly: true}))
+	assert.Equal(t, []string{"a"}, ids(Filter{Query: "paris"}), "case-insensitive match on the arguments")
+	assert.Equal(t, []string{"b"}, ids(Filter{Query: "timed out"}), "match on the error")
+	assert.Equal(t, []string{"c"}, ids(Filter{Query: "50%_off"}), "wildcards are literal")
+	assert.Equal(t, []string{"d", "c"}, ids(Filter{Query: "shop."}))
+}
+
+func TestSQLiteAuditStore_APIKeyID(t *testing.T) {
+	f, err := os.CreateTemp("", "audit_api_key_id_*.db")
+	require.NoError(t, err)
+	dbPath := f.Name()
+	f.Close()
+	defer os.Remove(dbPath)
+
+	validation.SetAllowedPaths([]string{os.TempDir()})
+	defer validation.SetAllowedPaths(nil)
+
+	store, err := NewSQLiteAuditStore(dbPath)
+	require.NoError(t, err)
+	defer store.Close()
+
+	now := time.Now()
+	require.NoError(t, store.Write(context.Background(), Entry{Timestamp: now, ToolName: "tool", UserID: "apikey:k1", APIKeyID: "k1"}))
+	require.NoError(t, store.Write(context.Background(), Entry{Timestamp: now, ToolName: "tool", UserID: "alice"}))
+
+	results, err := store.Read(context.Background(), Filter{APIKeyID: "k1"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "k1", results[0].APIKeyID)
+	assert.Equal(t, "apikey:k1", results[0].UserID)
+
+	valid, err := store.Verify()
+	assert.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestSQLiteAuditStore_Labels(t *testing.T) {
+	f, err := os.CreateTemp("", "audit_labels_*.db")
+	require.NoError(t, err)
+	dbPath := f.Name()
+	f.Close()
+	defer os.Remove(dbPath)
+
+	validation.SetAllowedPaths([]string{os.TempDir()})
+	defer validation.SetAllowedPaths(nil)
+
+	store, err := NewSQLiteAuditStore(dbPath)
+	require.NoError(t, err)
+	defer store.Close()
+
+	now := time.Now()
+	require.NoError(t, store.Write(context.Background(), Entry{Timestamp: now, ToolName: "labelled", Labels: map[string]string{"team": "payments"}}))
+	require.NoError(t, store.Write(context.Background(), Entry{Timestamp: now.Add(-time.Second), ToolName: "plain"}))
+
+	results, err := store.Read(context.Background(), Filter{})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, map[string]string{"team": "payments"}, results[0].Labels)
+	assert.Nil(t, results[1].Labels)
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+)
+
+// TeeStore writes audit entries to a primary store and to additional sinks,
+// such as an export to object storage.
+//
+// Summary: Audit store that fans out writes to several stores.
+type TeeStore struct {
+	primary Store
+	sinks   []Store
+}
+
+// NewTeeStore creates a new TeeStore.
+//
+// Summary: Initializes a TeeStore.
+//
+// Parameters:
+//   - primary: Store. The store that serves reads.
+//   - sinks: ...Store. The stores that only receive writes.
+//
+// Returns:
+//   - *TeeStore: The initialized store.
+func NewTeeStore(primary Store, sinks ...Store) *TeeStore {
+	return &TeeStore{primary: primary, sinks: sinks}
+}
+
+// Primary returns the store that serves reads.
+//
+// Returns:
+//   - Store: The primary store.
+func (t *TeeStore) Primary() Store {
+	return t.primary
+}
+
+// Write implements the Store interface.
+//
+// Summary: Writes an entry to the primary store and all sinks.
+//
+// Parameters:
+//   - ctx: context.Context. The context for the request.
+//   - entry: Entry. The audit entry.
+//
+// Returns:
+//   - error: The joined errors of the stores that failed.
+func (t *TeeStore) Write(ctx context.Context, entry Entry) error {
+	errs := []error{t.primary.Write(ctx, entry)}
+	for _, s := range t.sinks {
+		errs = append(errs, s.Write(ctx, entry))
+	}
+	return errors.Join(errs...)
+}
+
+// Read implements the Store interface.
+//
+// Summary: Reads entries from the primary store.
+//
+// Parameters:
+//   - ctx: context.Context. The context for the request.
+//   - filter: Filter. The filter to apply.
+//
+// Returns:
+//   - []Entry: The matching entries.
+//   - error: An error if the primary store fails.
+func (t *TeeStore) Read(ctx context.Context, filter Filter) ([]Entry, error) {
+	return t.primary.Read(ctx, filter)
+}
+
+// Close implements the Store interface.
+//
+// Summary: Closes the primary store and all sinks.
+//
+// Returns:
+//   - error: The joined errors of the stores that failed to close.
+func (t *TeeStore) Close() error {
+	errs := []error{t.primary.Close()}
+	for _, s := range t.sinks {
+		errs = append(errs, s.Close())
+	}
+	return errors.Join(errs...)
+}
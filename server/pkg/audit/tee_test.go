@@ -0,0 +1,53 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingStore struct {
+	entries  []Entry
+	writeErr error
+	closed   bool
+}
+
+func (r *recordingStore) Write(_ context.Context, entry Entry) error {
+	r.entries = append(r.entries, entry)
+	return r.writeErr
+}
+
+func (r *recordingStore) Read(_ context.Context, _ Filter) ([]Entry, error) {
+	return r.entries, nil
+}
+
+func (r *recordingStore) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestTeeStore(t *testing.T) {
+	primary := &recordingStore{}
+	sink := &recordingStore{writeErr: errors.New("queue full")}
+	tee := NewTeeStore(primary, sink)
+	assert.Same(t, primary, tee.Primary())
+
+	err := tee.Write(context.Background(), Entry{ToolName: "a"})
+	assert.EqualError(t, err, "queue full")
+	assert.Len(t, primary.entries, 1)
+	assert.Len(t, sink.entries, 1)
+
+	entries, err := tee.Read(context.Background(), Filter{})
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	require.NoError(t, tee.Close())
+	assert.True(t, primary.closed)
+	assert.True(t, sink.closed)
+}
@@ -11,29 +11,38 @@ import (
 
 // Entry represents a single audit log entry.
 type Entry struct {
-	Timestamp  time.Time       `json:"timestamp"`
-	ToolName   string          `json:"tool_name"`
-	UserID     string          `json:"user_id,omitempty"`
-	ProfileID  string          `json:"profile_id,omitempty"`
-	TraceID    string          `json:"trace_id,omitempty"`
-	SpanID     string          `json:"span_id,omitempty"`
-	ParentID   string          `json:"parent_id,omitempty"`
-	Arguments  json.RawMessage `json:"arguments,omitempty"`
-	Result     any             `json:"result,omitempty"`
-	Error      string          `json:"error,omitempty"`
-	Duration   string          `json:"duration"`
-	DurationMs int64           `json:"duration_ms"`
+	ID         string            `json:"id,omitempty"`
+	Timestamp  time.Time         `json:"timestamp"`
+	ToolName   string            `json:"tool_name"`
+	UserID     string            `json:"user_id,omitempty"`
+	ProfileID  string            `json:"profile_id,omitempty"`
+	APIKeyID   string            `json:"api_key_id,omitempty"`
+	TraceID    string            `json:"trace_id,omitempty"`
+	SpanID     string            `json:"span_id,omitempty"`
+	ParentID   string            `json:"parent_id,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Arguments  json.RawMessage   `json:"arguments,omitempty"`
+	Result     any               `json:"result,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	Duration   string            `json:"duration"`
+	DurationMs int64             `json:"duration_ms"`
 }
 
-// Filter defines the filters for reading audit logs.
+// Filter defines the filters for reading audit logs. ID selects a single
+// entry, ErrorsOnly the entries of failed calls, and Query the entries whose
+// tool name, arguments, result or error contain the text, ignoring case.
 type Filter struct {
-	StartTime *time.Time `json:"start_time,omitempty"`
-	EndTime   *time.Time `json:"end_time,omitempty"`
-	ToolName  string     `json:"tool_name,omitempty"`
-	UserID    string     `json:"user_id,omitempty"`
-	ProfileID string     `json:"profile_id,omitempty"`
-	Limit     int        `json:"limit,omitempty"`
-	Offset    int        `json:"offset,omitempty"`
+	StartTime  *time.Time `json:"start_time,omitempty"`
+	EndTime    *time.Time `json:"end_time,omitempty"`
+	ToolName   string     `json:"tool_name,omitempty"`
+	UserID     string     `json:"user_id,omitempty"`
+	ProfileID  string     `json:"profile_id,omitempty"`
+	APIKeyID   string     `json:"api_key_id,omitempty"`
+	ID         string     `json:"id,omitempty"`
+	ErrorsOnly bool       `json:"errors_only,omitempty"`
+	Query      string     `json:"query,omitempty"`
+	Limit      int        `json:"limit,omitempty"`
+	Offset     int        `json:"offset,omitempty"`
 }
 
 // Store defines the interface for audit log storage.
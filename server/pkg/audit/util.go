@@ -8,6 +8,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 // computeHash computes the hash for the audit entry using SHA-256.
@@ -28,3 +29,18 @@ func computeHashV0(timestamp, toolName, userID, profileID, args, result, errorMs
 	h := sha256.Sum256([]byte(data))
 	return hex.EncodeToString(h[:])
 }
+
+// computeLineHash computes the chain hash of a line of an audit log file.
+// content is the JSON of the entry exactly as written, without the chain fields.
+func computeLineHash(seq int64, content []byte, prevHash string) string {
+	fields := []any{seq, string(content), prevHash}
+	data, _ := json.Marshal(fields)
+	h := sha256.Sum256(data)
+	return "v1:" + hex.EncodeToString(h[:])
+}
+
+// likePattern returns the LIKE pattern matching the values containing text,
+// with the wildcards of text escaped by a backslash.
+func likePattern(text string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(text) + "%"
+}
@@ -58,3 +58,8 @@ func TestJsonSerializationConsistency(t *testing.T) {
 	data, _ := json.Marshal(fields)
 	assert.Equal(t, `["a","b",123]`, string(data))
 }
+
+func TestLikePattern(t *testing.T) {
+	assert.Equal(t, "%tool%", likePattern("tool"))
+	assert.Equal(t, `%50\%\_off\\%`, likePattern(`50%_off\`))
+}
@@ -12,6 +12,8 @@ import (
 	"os"
 	"sync"
 	"time"
+
+	"github.com/mcpany/core/server/pkg/webhooks"
 )
 
 const (
@@ -29,6 +31,8 @@ type WebhookAuditStore struct {
 	queue      chan Entry
 	wg         sync.WaitGroup
 	done       chan struct{}
+	// flushes holds, for each worker, the flush requests it acknowledges.
+	flushes []chan chan struct{}
 }
 
 // NewWebhookAuditStore creates a new WebhookAuditStore.
@@ -48,20 +52,22 @@ func NewWebhookAuditStore(webhookURL string, headers map[string]string) *Webhook
 	store := &WebhookAuditStore{
 		webhookURL: webhookURL,
 		headers:    headers,
-		client:     &http.Client{Timeout: 10 * time.Second},
+		client:     webhooks.NewOutboundClient(10 * time.Second),
 		queue:      make(chan Entry, webhookBufferSize),
 		done:       make(chan struct{}),
 	}
 
 	for i := 0; i < webhookWorkers; i++ {
+		flush := make(chan chan struct{})
+		store.flushes = append(store.flushes, flush)
 		store.wg.Add(1)
-		go store.worker()
+		go store.worker(flush)
 	}
 
 	return store
 }
 
-func (s *WebhookAuditStore) worker() {
+func (s *WebhookAuditStore) worker(flush chan chan struct{}) {
 	defer s.wg.Done()
 	var batch []Entry
 	ticker := time.NewTicker(webhookBatchWait)
@@ -84,6 +90,11 @@ func (s *WebhookAuditStore) worker() {
 				s.sendBatch(batch)
 				batch = nil
 			}
+		case ack := <-flush:
+			batch = takeQueued(s.queue, batch, webhookBatchSize, s.sendBatch)
+			s.sendBatch(batch)
+			batch = nil
+			close(ack)
 		case <-s.done:
 			// Drain queue
 			for entry := range s.queue {
@@ -196,3 +207,16 @@ func (s *WebhookAuditStore) Close() error {
 	s.wg.Wait()
 	return nil
 }
+
+// Flush implements the Flusher interface.
+//
+// Summary: Sends the queued and batched entries to the webhook.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the wait.
+//
+// Returns:
+//   - error: An error if ctx is done before the workers sent their batches.
+func (s *WebhookAuditStore) Flush(ctx context.Context) error {
+	return flushWorkers(ctx, s.flushes, s.done)
+}
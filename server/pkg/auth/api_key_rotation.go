@@ -0,0 +1,612 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/cluster"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/storage"
+	"github.com/mcpany/core/server/pkg/webhooks"
+	xsync "github.com/puzpuzpuz/xsync/v4"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultAPIKeyGracePeriod is how long a rotated key stays valid when
+// neither the caller nor the key's rotation policy sets a grace period.
+const DefaultAPIKeyGracePeriod = 24 * time.Hour
+
+const defaultRotationCheckInterval = time.Minute
+
+// expiryNotificationRetry is how long the expiry notification of a replaced
+// key is retried before the key is revoked without it.
+const expiryNotificationRetry = 24 * time.Hour
+
+// Types of APIKeyEvent.
+const (
+	// APIKeyEventRotated is sent when a key is replaced by a new key.
+	APIKeyEventRotated = "rotated"
+	// APIKeyEventExpired is sent when the grace period of a replaced key ends.
+	APIKeyEventExpired = "expired"
+)
+
+var (
+	// ErrAPIKeyRevoked is returned when rotating a revoked or expired key.
+	ErrAPIKeyRevoked = errors.New("api key is revoked")
+	// ErrAPIKeyAlreadyRotated is returned when rotating a key that already has a replacement.
+	ErrAPIKeyAlreadyRotated = errors.New("api key has already been rotated")
+)
+
+// APIKeyRotation is the result of rotating a client API key.
+type APIKeyRotation struct {
+	// New is the stored record of the new key.
+	New *configv1.ClientApiKey
+	// Key is the full new key. It is only available now.
+	Key string
+	// Previous is the replaced key, valid until its expires_at.
+	Previous *configv1.ClientApiKey
+}
+
+// RotateClientAPIKey issues a key with the attributes of an existing key and
+// deprecates the existing key: it stays valid for the grace period so
+// clients can switch over, and is rejected afterwards.
+//
+// Summary: Rotates a stored client API key.
+//
+// Parameters:
+//   - ctx: context.Context. The request context.
+//   - store: storage.Storage. The storage holding the key.
+//   - id: string. The ID of the key to rotate.
+//   - grace: time.Duration. How long the old key stays valid; zero uses the
+//     key's rotation policy or DefaultAPIKeyGracePeriod.
+//
+// Returns:
+//   - *APIKeyRotation: The new key and the deprecated record.
+//   - error: ErrAPIKeyNotFound, ErrAPIKeyRevoked, ErrAPIKeyAlreadyRotated,
+//     ErrReservedAPIKeyScope, or an error if saving fails.
+//
+// Side Effects:
+//   - Writes both key records to storage.
+func RotateClientAPIKey(ctx context.Context, store storage.Storage, id string, grace time.Duration) (*APIKeyRotation, error) {
+	return rotateClientAPIKey(ctx, store, id, grace, time.Now().UTC())
+}
+
+func rotateClientAPIKey(ctx context.Context, store storage.Storage, id string, grace time.Duration, now time.Time) (*APIKeyRotation, error) {
+	rotation, err := prepareRotation(ctx, store, id, grace, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := saveRotation(ctx, store, rotation); err != nil {
+		return nil, err
+	}
+	return rotation, nil
+}
+
+// prepareRotation issues the replacement of a key without saving it or the
+// deprecation of the key.
+func prepareRotation(ctx context.Context, store storage.Storage, id string, grace time.Duration, now time.Time) (*APIKeyRotation, error) {
+	previous, err := store.GetAPIKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if previous == nil {
+		return nil, ErrAPIKeyNotFound
+	}
+	if previous.GetRevokedAt() != "" || APIKeyExpired(previous, now) {
+		return nil, ErrAPIKeyRevoked
+	}
+	if previous.GetReplacedBy() != "" {
+		return nil, ErrAPIKeyAlreadyRotated
+	}
+	if err := ValidateAPIKeyScopes(previous.GetScopes()); err != nil {
+		return nil, err
+	}
+	if grace < 0 {
+		return nil, fmt.Errorf("grace period must not be negative")
+	}
+	if grace == 0 {
+		grace = rotationGracePeriod(previous)
+	}
+
+	template := proto.Clone(previous).(*configv1.ClientApiKey)
+	template.ClearExpiresAt()
+	template.ClearReplacedBy()
+	template.ClearDeprecatedUseCount()
+	template.ClearDeprecatedLastUsedAt()
+	template.SetRotatedFrom(previous.GetId())
+	record, key, err := GenerateClientAPIKey(template)
+	if err != nil {
+		return nil, err
+	}
+	// The rotation schedule of the new key starts now.
+	record.SetCreatedAt(now.Format(time.RFC3339))
+	previous.SetReplacedBy(record.GetId())
+	previous.SetExpiresAt(now.Add(grace).Format(time.RFC3339))
+	return &APIKeyRotation{New: record, Key: key, Previous: previous}, nil
+}
+
+// saveRotation saves the new key, then the deprecation of the replaced key.
+func saveRotation(ctx context.Context, store storage.Storage, rotation *APIKeyRotation) error {
+	if err := store.SaveAPIKey(ctx, rotation.New); err != nil {
+		return err
+	}
+	return store.SaveAPIKey(ctx, rotation.Previous)
+}
+
+// ValidateAPIKeyNotification checks the notify_url and the rotation policy
+// of a key: the webhook must use https, since scheduled rotations send the
+// new key to it, and a scheduled rotation needs a webhook to send the key to.
+//
+// Parameters:
+//   - record: *configv1.ClientApiKey. The key.
+//
+// Returns:
+//   - error: An error if the webhook or the policy is invalid.
+func ValidateAPIKeyNotification(record *configv1.ClientApiKey) error {
+	if record.GetNotifyUrl() != "" {
+		if err := validateNotifyURL(record.GetNotifyUrl()); err != nil {
+			return err
+		}
+	}
+	policy := record.GetRotationPolicy()
+	if policy.GetInterval().AsDuration() < 0 || policy.GetGracePeriod().AsDuration() < 0 {
+		return fmt.Errorf("api key rotation interval and grace period must not be negative")
+	}
+	if policy.GetInterval().AsDuration() > 0 && record.GetNotifyUrl() == "" {
+		return fmt.Errorf("a scheduled api key rotation requires a notify_url to send the new key to")
+	}
+	return nil
+}
+
+func validateNotifyURL(notifyURL string) error {
+	u, err := url.Parse(notifyURL)
+	if err != nil {
+		return fmt.Errorf("invalid notify_url: %w", err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("notify_url must be an https URL")
+	}
+	return nil
+}
+
+// APIKeyExpired reports whether the key is past its expires_at.
+//
+// Parameters:
+//   - record: *configv1.ClientApiKey. The key.
+//   - now: time.Time. The current time.
+//
+// Returns:
+//   - bool: True if the key has an expiry that has passed.
+func APIKeyExpired(record *configv1.ClientApiKey, now time.Time) bool {
+	if record.GetExpiresAt() == "" {
+		return false
+	}
+	expiresAt, err := time.Parse(time.RFC3339, record.GetExpiresAt())
+	// A malformed expiry fails closed.
+	return err != nil || !now.Before(expiresAt)
+}
+
+func rotationGracePeriod(record *configv1.ClientApiKey) time.Duration {
+	if d := record.GetRotationPolicy().GetGracePeriod().AsDuration(); d > 0 {
+		return d
+	}
+	return DefaultAPIKeyGracePeriod
+}
+
+// rotationDue reports whether the rotation policy of an active key asks for
+// a new key.
+func rotationDue(record *configv1.ClientApiKey, now time.Time) bool {
+	interval := record.GetRotationPolicy().GetInterval().AsDuration()
+	if interval <= 0 || record.GetRevokedAt() != "" || record.GetReplacedBy() != "" {
+		return false
+	}
+	createdAt, err := time.Parse(time.RFC3339, record.GetCreatedAt())
+	return err == nil && !now.Before(createdAt.Add(interval))
+}
+
+// deprecatedUse counts the requests made with a replaced key since the
+// counts were last written to storage.
+type deprecatedUse struct {
+	count int64
+	last  time.Time
+}
+
+// noteDeprecatedUse records a request made with a replaced key.
+func (am *Manager) noteDeprecatedUse(ctx context.Context, record *configv1.ClientApiKey) {
+	now := time.Now().UTC()
+	am.deprecatedUses.Compute(record.GetId(), func(old *deprecatedUse, _ bool) (*deprecatedUse, xsync.ComputeOp) {
+		if old == nil {
+			old = &deprecatedUse{}
+		}
+		return &deprecatedUse{count: old.count + 1, last: now}, xsync.UpdateOp
+	})
+	logging.GetLogger().WarnContext(ctx, "Deprecated API key used",
+		"api_key_id", record.GetId(),
+		"name", record.GetName(),
+		"replaced_by", record.GetReplacedBy(),
+		"expires_at", record.GetExpiresAt())
+}
+
+// APIKeyEvent is the notification sent to the owner of a key.
+type APIKeyEvent struct {
+	// Type is APIKeyEventRotated or APIKeyEventExpired.
+	Type string `json:"type"`
+	// KeyID is the ID of the replaced key.
+	KeyID string `json:"key_id"`
+	// Name is the name of the key.
+	Name string `json:"name"`
+	// Owner is the owner of the key.
+	Owner string `json:"owner,omitempty"`
+	// ReplacedBy is the ID of the new key.
+	ReplacedBy string `json:"replaced_by,omitempty"`
+	// Key is the full new key, set for scheduled rotations only.
+	Key string `json:"key,omitempty"`
+	// ExpiresAt is the end of the grace period of the replaced key.
+	ExpiresAt string `json:"expires_at,omitempty"`
+	// DeprecatedUseCount is the number of requests made with the replaced key since the rotation.
+	DeprecatedUseCount int64 `json:"deprecated_use_count"`
+	// DeprecatedLastUsedAt is the time of the last of those requests.
+	DeprecatedLastUsedAt string `json:"deprecated_last_used_at,omitempty"`
+	// Timestamp is when the event happened.
+	Timestamp time.Time `json:"timestamp"`
+	// NotifyURL is the webhook of the owner.
+	NotifyURL string `json:"-"`
+}
+
+// NewAPIKeyEvent describes a replaced key.
+//
+// Parameters:
+//   - eventType: string. APIKeyEventRotated or APIKeyEventExpired.
+//   - previous: *configv1.ClientApiKey. The replaced key.
+//
+// Returns:
+//   - APIKeyEvent: The event, without the new key.
+func NewAPIKeyEvent(eventType string, previous *configv1.ClientApiKey) APIKeyEvent {
+	return APIKeyEvent{
+		Type:                 eventType,
+		KeyID:                previous.GetId(),
+		Name:                 previous.GetName(),
+		Owner:                previous.GetOwner(),
+		ReplacedBy:           previous.GetReplacedBy(),
+		ExpiresAt:            previous.GetExpiresAt(),
+		DeprecatedUseCount:   previous.GetDeprecatedUseCount(),
+		DeprecatedLastUsedAt: previous.GetDeprecatedLastUsedAt(),
+		Timestamp:            time.Now().UTC(),
+		NotifyURL:            previous.GetNotifyUrl(),
+	}
+}
+
+// APIKeyNotifier is told about rotated and expired keys. It returns an error
+// if the owner could not be notified.
+type APIKeyNotifier func(ctx context.Context, event APIKeyEvent) error
+
+// WebhookAPIKeyNotifier returns a notifier posting events as JSON to the
+// notify_url of the key. Keys without a notify_url are skipped. Events
+// carrying a new key are only sent to an https URL, and only while the
+// outbound webhooks are signed.
+//
+// Parameters:
+//   - client: *http.Client. The client for the webhook calls; an outbound
+//     webhook client with a 10s timeout if nil, which signs the requests.
+//
+// Returns:
+//   - APIKeyNotifier: The notifier.
+func WebhookAPIKeyNotifier(client *http.Client) APIKeyNotifier {
+	if client == nil {
+		client = webhooks.NewOutboundClient(10 * time.Second)
+	}
+	return func(ctx context.Context, event APIKeyEvent) error {
+		if event.NotifyURL == "" {
+			return nil
+		}
+		if err := validateNotifyURL(event.NotifyURL); err != nil {
+			return err
+		}
+		if event.Key != "" && !webhooks.OutboundSigned() {
+			return fmt.Errorf("refusing to send a new api key unsigned: set global_settings.outbound_webhooks.webhook_secret")
+		}
+		return postAPIKeyEvent(ctx, client, event)
+	}
+}
+
+func postAPIKeyEvent(ctx context.Context, client *http.Client, event APIKeyEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, event.NotifyURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// The ID is the same when a notification is sent again, and differs for
+	// each new key issued by a retried rotation.
+	req.Header.Set("Webhook-Id", event.Type+"_"+event.KeyID+"_"+event.ReplacedBy)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// APIKeyRotator rotates client API keys according to their rotation
+// policies, expires replaced keys at the end of their grace period and
+// writes the usage of replaced keys to storage.
+type APIKeyRotator struct {
+	store    storage.Storage
+	manager  *Manager
+	notify   APIKeyNotifier
+	interval time.Duration
+	now      func() time.Time
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// RotatorOption configures an APIKeyRotator.
+type RotatorOption func(*APIKeyRotator)
+
+// WithAPIKeyNotifier sets the notifier of rotated and expired keys.
+//
+// Parameters:
+//   - notify: APIKeyNotifier. The notifier.
+//
+// Returns:
+//   - RotatorOption: The option.
+func WithAPIKeyNotifier(notify APIKeyNotifier) RotatorOption {
+	return func(r *APIKeyRotator) { r.notify = notify }
+}
+
+// WithRotationCheckInterval sets how often the keys are checked.
+//
+// Parameters:
+//   - d: time.Duration. The interval; one minute by default.
+//
+// Returns:
+//   - RotatorOption: The option.
+func WithRotationCheckInterval(d time.Duration) RotatorOption {
+	return func(r *APIKeyRotator) { r.interval = d }
+}
+
+// WithRotationClock sets the clock of the rotator.
+//
+// Parameters:
+//   - now: func() time.Time. The clock; time.Now by default.
+//
+// Returns:
+//   - RotatorOption: The option.
+func WithRotationClock(now func() time.Time) RotatorOption {
+	return func(r *APIKeyRotator) { r.now = now }
+}
+
+// NewAPIKeyRotator creates an APIKeyRotator.
+//
+// Summary: Initializes scheduled API key rotation.
+//
+// Parameters:
+//   - store: storage.Storage. The storage holding the keys.
+//   - manager: *Manager. Counts the requests made with replaced keys; may be nil.
+//   - opts: ...RotatorOption. The options.
+//
+// Returns:
+//   - *APIKeyRotator: The rotator.
+func NewAPIKeyRotator(store storage.Storage, manager *Manager, opts ...RotatorOption) *APIKeyRotator {
+	r := &APIKeyRotator{
+		store:    store,
+		manager:  manager,
+		notify:   func(context.Context, APIKeyEvent) error { return nil },
+		interval: defaultRotationCheckInterval,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Check writes the usage of replaced keys, expires replaced keys past their
+// grace period and rotates the keys whose rotation is due. Only the replica
+// leading the cluster expires and rotates the keys.
+//
+// A scheduled rotation is only saved once its notification, which carries
+// the new key, is delivered; until then the key stays active and the
+// rotation is tried again on the next check. The revocation of an expired
+// key also waits for its notification, for up to a day.
+//
+// Summary: Runs one rotation pass.
+//
+// Parameters:
+//   - ctx: context.Context. The context for the storage calls.
+//
+// Returns:
+//   - error: An error if the keys cannot be read or saved.
+//
+// Side Effects:
+//   - Updates key records and notifies owners.
+func (r *APIKeyRotator) Check(ctx context.Context) error {
+	keys, err := r.store.ListAPIKeys(ctx)
+	if err != nil {
+		return err
+	}
+	now := r.now().UTC()
+	// The uses are counted by each replica, but only the leader revokes and
+	// rotates the keys, so that a key is rotated once.
+	leader := cluster.IsLeader()
+	var errs []error
+	for _, key := range keys {
+		changed := r.applyDeprecatedUse(key)
+		if leader && key.GetReplacedBy() != "" && key.GetRevokedAt() == "" && APIKeyExpired(key, now) {
+			err := r.notify(ctx, NewAPIKeyEvent(APIKeyEventExpired, key))
+			switch {
+			case err == nil:
+			case expiryNotificationExhausted(key, now):
+				logging.GetLogger().Warn("Revoking expired API key without notifying its owner", "api_key_id", key.GetId(), "error", err)
+			default:
+				errs = append(errs, fmt.Errorf("failed to notify the expiry of api key %s: %w", key.GetId(), err))
+			}
+			if err == nil || expiryNotificationExhausted(key, now) {
+				key.SetRevokedAt(now.Format(time.RFC3339))
+				changed = true
+			}
+			if changed {
+				if err := r.store.SaveAPIKey(ctx, key); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			continue
+		}
+		if changed {
+			if err := r.store.SaveAPIKey(ctx, key); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		if leader && rotationDue(key, now) {
+			if err := r.rotate(ctx, key, now); err != nil {
+				errs = append(errs, fmt.Errorf("failed to rotate api key %s: %w", key.GetId(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// rotate rotates a key on its schedule, once the new key is delivered.
+func (r *APIKeyRotator) rotate(ctx context.Context, key *configv1.ClientApiKey, now time.Time) error {
+	if key.GetNotifyUrl() == "" {
+		return fmt.Errorf("the key has no notify_url to send the new key to")
+	}
+	rotation, err := prepareRotation(ctx, r.store, key.GetId(), 0, now)
+	if err != nil {
+		return err
+	}
+	event := NewAPIKeyEvent(APIKeyEventRotated, rotation.Previous)
+	// The owner has no other way to obtain a key issued by the schedule.
+	event.Key = rotation.Key
+	if err := r.notify(ctx, event); err != nil {
+		return fmt.Errorf("the new key was not delivered, the rotation is retried: %w", err)
+	}
+	if err := saveRotation(ctx, r.store, rotation); err != nil {
+		return err
+	}
+	logging.GetLogger().Info("Rotated API key", "api_key_id", key.GetId(), "new_api_key_id", rotation.New.GetId(), "expires_at", rotation.Previous.GetExpiresAt())
+	return nil
+}
+
+// expiryNotificationExhausted reports whether the expiry notification of a
+// key is no longer retried.
+func expiryNotificationExhausted(key *configv1.ClientApiKey, now time.Time) bool {
+	expiresAt, err := time.Parse(time.RFC3339, key.GetExpiresAt())
+	return err != nil || now.Sub(expiresAt) >= expiryNotificationRetry
+}
+
+// applyDeprecatedUse adds the requests counted by the manager to the key.
+func (r *APIKeyRotator) applyDeprecatedUse(key *configv1.ClientApiKey) bool {
+	if r.manager == nil {
+		return false
+	}
+	use, ok := r.manager.deprecatedUses.LoadAndDelete(key.GetId())
+	if !ok {
+		return false
+	}
+	key.SetDeprecatedUseCount(key.GetDeprecatedUseCount() + use.count)
+	key.SetDeprecatedLastUsedAt(use.last.Format(time.RFC3339))
+	return true
+}
+
+// Start checks the keys periodically until Stop is called.
+//
+// Parameters:
+//   - ctx: context.Context. Checking also stops when it is cancelled.
+//
+// Returns:
+//   - error: Always nil.
+//
+// Side Effects:
+//   - Starts a goroutine.
+func (r *APIKeyRotator) Start(ctx context.Context) error {
+	r.mu.Lock()
+	if r.cancel != nil {
+		r.mu.Unlock()
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	r.cancel = cancel
+	r.done = make(chan struct{})
+	done := r.done
+	r.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.Check(ctx); err != nil {
+					logging.GetLogger().Warn("API key rotation check failed", "error", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the periodic checks and writes the remaining usage of
+// replaced keys.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the wait.
+//
+// Returns:
+//   - error: An error if the context expires or the usage cannot be saved.
+func (r *APIKeyRotator) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.cancel, r.done = nil, nil
+	r.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return r.flushDeprecatedUse(ctx)
+}
+
+func (r *APIKeyRotator) flushDeprecatedUse(ctx context.Context) error {
+	if r.manager == nil || r.manager.deprecatedUses.Size() == 0 {
+		return nil
+	}
+	keys, err := r.store.ListAPIKeys(ctx)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if r.applyDeprecatedUse(key) {
+			if err := r.store.SaveAPIKey(ctx, key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,377 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/cluster"
+	"github.com/mcpany/core/server/pkg/storage/memory"
+	"github.com/mcpany/core/server/pkg/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestRotateClientAPIKey(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	am := NewManager()
+	am.SetStorage(store)
+
+	old, oldKey, err := CreateClientAPIKey(ctx, store, configv1.ClientApiKey_builder{
+		Name:      proto.String("ci"),
+		Scopes:    []string{"viewer"},
+		ProfileId: proto.String("dev"),
+		Owner:     proto.String("platform-team"),
+	}.Build())
+	require.NoError(t, err)
+
+	rotation, err := RotateClientAPIKey(ctx, store, old.GetId(), time.Hour)
+	require.NoError(t, err)
+	assert.NotEqual(t, old.GetId(), rotation.New.GetId())
+	assert.Equal(t, old.GetId(), rotation.New.GetRotatedFrom())
+	assert.Equal(t, "ci", rotation.New.GetName())
+	assert.Equal(t, []string{"viewer"}, rotation.New.GetScopes())
+	assert.Equal(t, "platform-team", rotation.New.GetOwner())
+	assert.Empty(t, rotation.New.GetExpiresAt())
+	assert.Equal(t, rotation.New.GetId(), rotation.Previous.GetReplacedBy())
+
+	expiresAt, err := time.Parse(time.RFC3339, rotation.Previous.GetExpiresAt())
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+
+	// Both keys are valid during the grace period.
+	authCtx, err := am.AuthenticateClientAPIKey(ctx, rotation.Key)
+	require.NoError(t, err)
+	id, _ := APIKeyIDFromContext(authCtx)
+	assert.Equal(t, rotation.New.GetId(), id)
+	authCtx, err = am.AuthenticateClientAPIKey(ctx, oldKey)
+	require.NoError(t, err)
+	id, _ = APIKeyIDFromContext(authCtx)
+	assert.Equal(t, old.GetId(), id)
+
+	_, err = RotateClientAPIKey(ctx, store, old.GetId(), time.Hour)
+	assert.ErrorIs(t, err, ErrAPIKeyAlreadyRotated)
+	_, err = RotateClientAPIKey(ctx, store, "missing", time.Hour)
+	assert.ErrorIs(t, err, ErrAPIKeyNotFound)
+	_, err = RotateClientAPIKey(ctx, store, rotation.New.GetId(), -time.Hour)
+	assert.Error(t, err)
+
+	_, err = RevokeClientAPIKey(ctx, store, rotation.New.GetId())
+	require.NoError(t, err)
+	_, err = RotateClientAPIKey(ctx, store, rotation.New.GetId(), time.Hour)
+	assert.ErrorIs(t, err, ErrAPIKeyRevoked)
+}
+
+func TestRotateClientAPIKey_PolicyGracePeriod(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	old, _, err := CreateClientAPIKey(ctx, store, configv1.ClientApiKey_builder{
+		Name: proto.String("ci"),
+		RotationPolicy: configv1.ApiKeyRotationPolicy_builder{
+			GracePeriod: durationpb.New(2 * time.Hour),
+		}.Build(),
+	}.Build())
+	require.NoError(t, err)
+
+	rotation, err := RotateClientAPIKey(ctx, store, old.GetId(), 0)
+	require.NoError(t, err)
+	expiresAt, err := time.Parse(time.RFC3339, rotation.Previous.GetExpiresAt())
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(2*time.Hour), expiresAt, time.Minute)
+	assert.True(t, proto.Equal(old.GetRotationPolicy(), rotation.New.GetRotationPolicy()))
+}
+
+func TestManager_AuthenticateClientAPIKey_Expired(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	am := NewManager()
+	am.SetStorage(store)
+
+	record, key, err := CreateClientAPIKey(ctx, store, configv1.ClientApiKey_builder{Name: proto.String("ci")}.Build())
+	require.NoError(t, err)
+	record.SetExpiresAt(time.Now().Add(-time.Second).UTC().Format(time.RFC3339))
+	require.NoError(t, store.SaveAPIKey(ctx, record))
+
+	_, err = am.AuthenticateClientAPIKey(ctx, key)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+}
+
+type recordedEvents struct {
+	mu     sync.Mutex
+	events []APIKeyEvent
+	// err fails the deliveries while set.
+	err error
+}
+
+func (r *recordedEvents) notify(_ context.Context, event APIKeyEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.events = append(r.events, event)
+	return nil
+}
+
+func TestAPIKeyRotator_Check(t *testing.T) {
+	cluster.SetLeader(cluster.Standalone)
+	t.Cleanup(func() { cluster.SetLeader(nil) })
+	ctx := context.Background()
+	store := memory.NewStore()
+	am := NewManager()
+	am.SetStorage(store)
+
+	scheduled, scheduledKey, err := CreateClientAPIKey(ctx, store, configv1.ClientApiKey_builder{
+		Name:      proto.String("scheduled"),
+		Owner:     proto.String("platform-team"),
+		NotifyUrl: proto.String("https://hooks.example.com/keys"),
+		RotationPolicy: configv1.ApiKeyRotationPolicy_builder{
+			Interval:    durationpb.New(24 * time.Hour),
+			GracePeriod: durationpb.New(time.Hour),
+		}.Build(),
+	}.Build())
+	require.NoError(t, err)
+	unscheduled, _, err := CreateClientAPIKey(ctx, store, configv1.ClientApiKey_builder{Name: proto.String("manual")}.Build())
+	require.NoError(t, err)
+
+	now := time.Now()
+	events := &recordedEvents{}
+	rotator := NewAPIKeyRotator(store, am,
+		WithAPIKeyNotifier(events.notify),
+		WithRotationClock(func() time.Time { return now }))
+
+	// Nothing is due yet.
+	require.NoError(t, rotator.Check(ctx))
+	assert.Empty(t, events.events)
+
+	now = now.Add(25 * time.Hour)
+	require.NoError(t, rotator.Check(ctx))
+	require.Len(t, events.events, 1)
+	rotated := events.events[0]
+	assert.Equal(t, APIKeyEventRotated, rotated.Type)
+	assert.Equal(t, scheduled.GetId(), rotated.KeyID)
+	assert.Equal(t, "platform-team", rotated.Owner)
+	assert.Equal(t, "https://hooks.example.com/keys", rotated.NotifyURL)
+	assert.NotEmpty(t, rotated.Key)
+	_, err = am.AuthenticateClientAPIKey(ctx, rotated.Key)
+	require.NoError(t, err)
+
+	stored, err := store.GetAPIKey(ctx, unscheduled.GetId())
+	require.NoError(t, err)
+	assert.Empty(t, stored.GetReplacedBy())
+
+	// Requests with the deprecated key are counted.
+	for i := 0; i < 3; i++ {
+		_, err = am.AuthenticateClientAPIKey(ctx, scheduledKey)
+		require.NoError(t, err)
+	}
+	require.NoError(t, rotator.Check(ctx))
+	stored, err = store.GetAPIKey(ctx, scheduled.GetId())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stored.GetDeprecatedUseCount())
+	assert.NotEmpty(t, stored.GetDeprecatedLastUsedAt())
+	assert.Empty(t, stored.GetRevokedAt())
+
+	// The deprecated key expires at the end of its grace period.
+	expiresAt, err := time.Parse(time.RFC3339, stored.GetExpiresAt())
+	require.NoError(t, err)
+	now = expiresAt.Add(time.Second)
+	require.NoError(t, rotator.Check(ctx))
+	require.Len(t, events.events, 2)
+	expired := events.events[1]
+	assert.Equal(t, APIKeyEventExpired, expired.Type)
+	assert.Equal(t, scheduled.GetId(), expired.KeyID)
+	assert.Equal(t, int64(3), expired.DeprecatedUseCount)
+	assert.Empty(t, expired.Key)
+
+	stored, err = store.GetAPIKey(ctx, scheduled.GetId())
+	require.NoError(t, err)
+	assert.NotEmpty(t, stored.GetRevokedAt())
+	_, err = am.AuthenticateClientAPIKey(ctx, scheduledKey)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+}
+
+// follower is a replica that does not lead the cluster.
+type follower struct{}
+
+func (follower) IsLeader() bool { return false }
+
+func TestAPIKeyRotator_Check_NotLeader(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	_, _, err := CreateClientAPIKey(ctx, store, configv1.ClientApiKey_builder{
+		Name:           proto.String("scheduled"),
+		NotifyUrl:      proto.String("https://hooks.example.com/keys"),
+		RotationPolicy: configv1.ApiKeyRotationPolicy_builder{Interval: durationpb.New(time.Hour)}.Build(),
+	}.Build())
+	require.NoError(t, err)
+
+	cluster.SetLeader(follower{})
+	defer cluster.SetLeader(nil)
+	events := &recordedEvents{}
+	rotator := NewAPIKeyRotator(store, nil,
+		WithAPIKeyNotifier(events.notify),
+		WithRotationClock(func() time.Time { return time.Now().Add(2 * time.Hour) }))
+	require.NoError(t, rotator.Check(ctx))
+	assert.Empty(t, events.events, "only the leader rotates the keys")
+	keys, err := store.ListAPIKeys(ctx)
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+}
+
+func TestAPIKeyRotator_Check_RetriesUndeliveredNotifications(t *testing.T) {
+	cluster.SetLeader(cluster.Standalone)
+	t.Cleanup(func() { cluster.SetLeader(nil) })
+	ctx := context.Background()
+	store := memory.NewStore()
+	am := NewManager()
+	am.SetStorage(store)
+	scheduled, scheduledKey, err := CreateClientAPIKey(ctx, store, configv1.ClientApiKey_builder{
+		Name:      proto.String("scheduled"),
+		NotifyUrl: proto.String("https://hooks.example.com/keys"),
+		RotationPolicy: configv1.ApiKeyRotationPolicy_builder{
+			Interval:    durationpb.New(72 * time.Hour),
+			GracePeriod: durationpb.New(time.Hour),
+		}.Build(),
+	}.Build())
+	require.NoError(t, err)
+
+	now := time.Now().Add(73 * time.Hour)
+	events := &recordedEvents{err: errors.New("webhook unavailable")}
+	rotator := NewAPIKeyRotator(store, am,
+		WithAPIKeyNotifier(events.notify),
+		WithRotationClock(func() time.Time { return now }))
+
+	// The key is not rotated while the new key cannot be delivered.
+	assert.ErrorContains(t, rotator.Check(ctx), "webhook unavailable")
+	keys, err := store.ListAPIKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Empty(t, keys[0].GetReplacedBy())
+	_, err = am.AuthenticateClientAPIKey(ctx, scheduledKey)
+	require.NoError(t, err)
+
+	events.err = nil
+	require.NoError(t, rotator.Check(ctx))
+	require.Len(t, events.events, 1)
+	_, err = am.AuthenticateClientAPIKey(ctx, events.events[0].Key)
+	require.NoError(t, err)
+
+	// The replaced key is only revoked once its expiry is delivered, or a
+	// day after it expired.
+	now = now.Add(2 * time.Hour)
+	events.err = errors.New("webhook unavailable")
+	assert.Error(t, rotator.Check(ctx))
+	stored, err := store.GetAPIKey(ctx, scheduled.GetId())
+	require.NoError(t, err)
+	assert.Empty(t, stored.GetRevokedAt())
+
+	now = now.Add(expiryNotificationRetry)
+	require.NoError(t, rotator.Check(ctx))
+	stored, err = store.GetAPIKey(ctx, scheduled.GetId())
+	require.NoError(t, err)
+	assert.NotEmpty(t, stored.GetRevokedAt())
+}
+
+func TestCreateClientAPIKey_Notification(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	policy := configv1.ApiKeyRotationPolicy_builder{Interval: durationpb.New(time.Hour)}.Build()
+
+	_, _, err := CreateClientAPIKey(ctx, store, configv1.ClientApiKey_builder{
+		Name:           proto.String("ci"),
+		RotationPolicy: policy,
+	}.Build())
+	assert.ErrorContains(t, err, "requires a notify_url")
+
+	_, _, err = CreateClientAPIKey(ctx, store, configv1.ClientApiKey_builder{
+		Name:      proto.String("ci"),
+		NotifyUrl: proto.String("http://hooks.example.com/keys"),
+	}.Build())
+	assert.ErrorContains(t, err, "must be an https URL")
+
+	_, _, err = CreateClientAPIKey(ctx, store, configv1.ClientApiKey_builder{
+		Name:           proto.String("ci"),
+		NotifyUrl:      proto.String("https://hooks.example.com/keys"),
+		RotationPolicy: policy,
+	}.Build())
+	assert.NoError(t, err)
+}
+
+func TestAPIKeyRotator_StopFlushesDeprecatedUse(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	am := NewManager()
+	am.SetStorage(store)
+
+	old, oldKey, err := CreateClientAPIKey(ctx, store, configv1.ClientApiKey_builder{Name: proto.String("ci")}.Build())
+	require.NoError(t, err)
+	_, err = RotateClientAPIKey(ctx, store, old.GetId(), time.Hour)
+	require.NoError(t, err)
+
+	rotator := NewAPIKeyRotator(store, am, WithRotationCheckInterval(time.Hour))
+	require.NoError(t, rotator.Start(ctx))
+	_, err = am.AuthenticateClientAPIKey(ctx, oldKey)
+	require.NoError(t, err)
+	require.NoError(t, rotator.Stop(ctx))
+
+	stored, err := store.GetAPIKey(ctx, old.GetId())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stored.GetDeprecatedUseCount())
+}
+
+func TestWebhookAPIKeyNotifier(t *testing.T) {
+	received := make(chan map[string]any, 1)
+	ids := make(chan string, 1)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		received <- body
+		ids <- r.Header.Get("Webhook-Id")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	notify := WebhookAPIKeyNotifier(srv.Client())
+	require.NoError(t, notify(ctx, APIKeyEvent{Type: APIKeyEventExpired, KeyID: "abc", Name: "ci", ReplacedBy: "def", NotifyURL: srv.URL}))
+
+	body := <-received
+	assert.Equal(t, "expired", body["type"])
+	assert.Equal(t, "abc", body["key_id"])
+	assert.NotContains(t, body, "key")
+	assert.NotContains(t, body, "NotifyURL")
+	assert.Equal(t, "expired_abc_def", <-ids)
+
+	// Keys without a webhook are skipped.
+	require.NoError(t, notify(ctx, APIKeyEvent{Type: APIKeyEventExpired, KeyID: "abc"}))
+	assert.Empty(t, received)
+
+	// Plain http is refused.
+	assert.ErrorContains(t, notify(ctx, APIKeyEvent{Type: APIKeyEventExpired, KeyID: "abc", NotifyURL: "http://hooks.example.com"}), "https")
+
+	// A new key is only sent signed.
+	rotated := APIKeyEvent{Type: APIKeyEventRotated, KeyID: "abc", ReplacedBy: "def", Key: "mcpany_def_secret", NotifyURL: srv.URL}
+	assert.ErrorContains(t, notify(ctx, rotated), "unsigned")
+	assert.Empty(t, received)
+	t.Cleanup(func() { require.NoError(t, webhooks.ConfigureOutbound(nil)) })
+	require.NoError(t, webhooks.ConfigureOutbound(configv1.OutboundWebhookConfig_builder{
+		WebhookSecret: proto.String("whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"),
+	}.Build()))
+	require.NoError(t, notify(ctx, rotated))
+	body = <-received
+	assert.Equal(t, "mcpany_def_secret", body["key"])
+	assert.Equal(t, "rotated_abc_def", <-ids)
+}
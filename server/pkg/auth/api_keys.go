@@ -0,0 +1,340 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/storage"
+	xsync "github.com/puzpuzpuz/xsync/v4"
+	"golang.org/x/time/rate"
+	"google.golang.org/protobuf/proto"
+)
+
+// ClientAPIKeyPrefix is the prefix of every issued client API key.
+// Keys have the form "mcpany_<id>_<secret>".
+const ClientAPIKeyPrefix = "mcpany_"
+
+// APIKeyIDContextKey is the context key for the ID of the client API key used.
+const APIKeyIDContextKey authContextKey = "api_key_id"
+
+var (
+	// ErrInvalidAPIKey is returned when a client API key is unknown, revoked or does not match.
+	ErrInvalidAPIKey = errors.New("invalid api key")
+	// ErrAPIKeyRateLimited is returned when a client API key exceeds its rate limit.
+	ErrAPIKeyRateLimited = errors.New("api key rate limit exceeded")
+	// ErrAPIKeyNotFound is returned when revoking a key that does not exist.
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	// ErrReservedAPIKeyScope is returned when a client API key would grant a
+	// reserved role.
+	ErrReservedAPIKeyScope = errors.New("api key scope is reserved")
+)
+
+// reservedAPIKeyScopes are the roles a client API key may not grant. Admin
+// access is only given by the global api_key and by users.
+var reservedAPIKeyScopes = []string{"admin"}
+
+// ValidateAPIKeyScopes checks that the scopes of a client API key grant no
+// reserved role.
+//
+// Summary: Rejects reserved client API key scopes.
+//
+// Parameters:
+//   - scopes: []string. The scopes of the key.
+//
+// Returns:
+//   - error: ErrReservedAPIKeyScope naming the first reserved scope, or nil.
+func ValidateAPIKeyScopes(scopes []string) error {
+	for _, scope := range scopes {
+		if isReservedAPIKeyScope(scope) {
+			return fmt.Errorf("%w: %q", ErrReservedAPIKeyScope, scope)
+		}
+	}
+	return nil
+}
+
+func isReservedAPIKeyScope(scope string) bool {
+	return slices.ContainsFunc(reservedAPIKeyScopes, func(reserved string) bool {
+		return strings.EqualFold(strings.TrimSpace(scope), reserved)
+	})
+}
+
+// ContextWithAPIKeyID returns a new context with the client API key ID embedded.
+//
+// Summary: Embeds a client API key ID into the context.
+//
+// Parameters:
+//   - ctx: context.Context. The context to extend.
+//   - id: string. The key ID to store.
+//
+// Returns:
+//   - context.Context: A new context containing the key ID.
+func ContextWithAPIKeyID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, APIKeyIDContextKey, id)
+}
+
+// APIKeyIDFromContext returns the client API key ID from the context if present.
+//
+// Summary: Retrieves the client API key ID from the context.
+//
+// Parameters:
+//   - ctx: context.Context. The context to search.
+//
+// Returns:
+//   - string: The key ID.
+//   - bool: True if found.
+func APIKeyIDFromContext(ctx context.Context) (string, bool) {
+	val, ok := ctx.Value(APIKeyIDContextKey).(string)
+	return val, ok
+}
+
+// GenerateClientAPIKey issues a new client API key.
+//
+// Summary: Creates a key record and the matching secret key.
+//
+// Parameters:
+//   - template: *configv1.ClientApiKey. The attributes of the key (name, scopes, profile, rate limit).
+//
+// Returns:
+//   - *configv1.ClientApiKey: The record to store, with ID, hash and creation time set.
+//   - string: The full key to hand to the client. It cannot be recovered later.
+//   - error: An error if random generation fails.
+func GenerateClientAPIKey(template *configv1.ClientApiKey) (*configv1.ClientApiKey, string, error) {
+	idBytes := make([]byte, 8)
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, "", fmt.Errorf("failed to generate api key id: %w", err)
+	}
+	if _, err := rand.Read(secretBytes); err != nil {
+		return nil, "", fmt.Errorf("failed to generate api key secret: %w", err)
+	}
+	id := hex.EncodeToString(idBytes)
+	secret := hex.EncodeToString(secretBytes)
+
+	key := proto.Clone(template).(*configv1.ClientApiKey)
+	key.SetId(id)
+	key.SetKeyHash(hashAPIKeySecret(secret))
+	key.SetCreatedAt(time.Now().UTC().Format(time.RFC3339))
+	key.ClearRevokedAt()
+	return key, ClientAPIKeyPrefix + id + "_" + secret, nil
+}
+
+// ParseClientAPIKey splits a client API key into its ID and secret.
+//
+// Summary: Parses a client API key.
+//
+// Parameters:
+//   - key: string. The full key.
+//
+// Returns:
+//   - string: The key ID.
+//   - string: The key secret.
+//   - bool: True if the key has the client API key format.
+func ParseClientAPIKey(key string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(key, ClientAPIKeyPrefix)
+	if !ok {
+		return "", "", false
+	}
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || id == "" || secret == "" {
+		return "", "", false
+	}
+	return id, secret, true
+}
+
+// ClientAPIKeyFromRequest returns the client API key presented in the
+// X-API-Key header, the api_key query parameter or a bearer token, if any.
+//
+// Summary: Extracts a client API key from a request.
+//
+// Parameters:
+//   - r: *http.Request. The HTTP request.
+//
+// Returns:
+//   - string: The client API key, or empty if none was presented.
+func ClientAPIKeyFromRequest(r *http.Request) string {
+	candidates := []string{r.Header.Get("X-API-Key"), r.URL.Query().Get("api_key")}
+	if token, ok := BearerToken(r); ok {
+		candidates = append(candidates, token)
+	}
+	for _, key := range candidates {
+		if _, _, ok := ParseClientAPIKey(key); ok {
+			return key
+		}
+	}
+	return ""
+}
+
+func hashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// keyLimiter is the rate limiter of a client API key, along with the limits
+// it was built from so changes to the key are picked up.
+type keyLimiter struct {
+	limiter *rate.Limiter
+	rps     float64
+	burst   int64
+}
+
+// AuthenticateClientAPIKey validates a client API key against storage.
+//
+// Summary: Authenticates a request using a per-client API key.
+//
+// Parameters:
+//   - ctx: context.Context. The request context.
+//   - key: string. The presented key.
+//
+// Returns:
+//   - context.Context: The context with the key ID, user, roles and profile of the key.
+//   - error: ErrInvalidAPIKey if the key is unknown, revoked, expired or wrong; ErrAPIKeyRateLimited if it is over its limit.
+//
+// Side Effects:
+//   - Consumes a token from the key's rate limiter.
+//   - Counts and logs the use of a key that has been replaced by rotation.
+func (am *Manager) AuthenticateClientAPIKey(ctx context.Context, key string) (context.Context, error) {
+	id, secret, ok := ParseClientAPIKey(key)
+	if !ok {
+		return ctx, ErrInvalidAPIKey
+	}
+	am.mu.RLock()
+	store := am.storage
+	am.mu.RUnlock()
+	if store == nil {
+		return ctx, ErrInvalidAPIKey
+	}
+
+	record, err := store.GetAPIKey(ctx, id)
+	if err != nil {
+		return ctx, fmt.Errorf("failed to look up api key: %w", err)
+	}
+	if record == nil || record.GetRevokedAt() != "" || APIKeyExpired(record, time.Now()) {
+		return ctx, ErrInvalidAPIKey
+	}
+	if subtle.ConstantTimeCompare([]byte(hashAPIKeySecret(secret)), []byte(record.GetKeyHash())) != 1 {
+		return ctx, ErrInvalidAPIKey
+	}
+	if !am.allowAPIKey(record) {
+		return ctx, ErrAPIKeyRateLimited
+	}
+	if record.GetReplacedBy() != "" {
+		am.noteDeprecatedUse(ctx, record)
+	}
+
+	ctx = ContextWithAPIKey(ctx, key)
+	ctx = ContextWithAPIKeyID(ctx, id)
+	ctx = ContextWithUser(ctx, "apikey:"+id)
+	// Keys stored before the reserved scopes were rejected never grant them.
+	roles := slices.DeleteFunc(slices.Clone(record.GetScopes()), isReservedAPIKeyScope)
+	if len(roles) > 0 {
+		ctx = ContextWithRoles(ctx, roles)
+	}
+	if record.GetProfileId() != "" {
+		ctx = ContextWithProfileID(ctx, record.GetProfileId())
+	}
+	return ctx, nil
+}
+
+func (am *Manager) allowAPIKey(record *configv1.ClientApiKey) bool {
+	rps := record.GetRequestsPerSecond()
+	if rps <= 0 {
+		am.keyLimiters.Delete(record.GetId())
+		return true
+	}
+	burst := record.GetBurst()
+	if burst <= 0 {
+		burst = max(int64(rps), 1)
+	}
+	l, _ := am.keyLimiters.Compute(record.GetId(), func(old *keyLimiter, loaded bool) (*keyLimiter, xsync.ComputeOp) {
+		if loaded && old.rps == rps && old.burst == burst {
+			return old, xsync.CancelOp
+		}
+		return &keyLimiter{limiter: rate.NewLimiter(rate.Limit(rps), int(burst)), rps: rps, burst: burst}, xsync.UpdateOp
+	})
+	return l.limiter.Allow()
+}
+
+// CreateClientAPIKey issues a new client API key and persists it.
+//
+// Summary: Generates and stores a client API key.
+//
+// Parameters:
+//   - ctx: context.Context. The request context.
+//   - store: storage.Storage. The storage to persist the key in.
+//   - template: *configv1.ClientApiKey. The attributes of the key. A name is required.
+//
+// Returns:
+//   - *configv1.ClientApiKey: The stored record.
+//   - string: The full key. It is only available now.
+//   - error: An error if the template is invalid or saving fails.
+//
+// Side Effects:
+//   - Writes the key record to storage.
+func CreateClientAPIKey(ctx context.Context, store storage.Storage, template *configv1.ClientApiKey) (*configv1.ClientApiKey, string, error) {
+	if template.GetName() == "" {
+		return nil, "", fmt.Errorf("api key name is required")
+	}
+	if template.GetRequestsPerSecond() < 0 || template.GetBurst() < 0 {
+		return nil, "", fmt.Errorf("api key rate limit must not be negative")
+	}
+	if err := ValidateAPIKeyScopes(template.GetScopes()); err != nil {
+		return nil, "", err
+	}
+	if err := ValidateAPIKeyNotification(template); err != nil {
+		return nil, "", err
+	}
+	record, key, err := GenerateClientAPIKey(template)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := store.SaveAPIKey(ctx, record); err != nil {
+		return nil, "", err
+	}
+	return record, key, nil
+}
+
+// RevokeClientAPIKey marks a client API key as revoked. The record is kept
+// so audit entries can still be attributed to it.
+//
+// Summary: Revokes a stored client API key.
+//
+// Parameters:
+//   - ctx: context.Context. The request context.
+//   - store: storage.Storage. The storage holding the key.
+//   - id: string. The key ID.
+//
+// Returns:
+//   - *configv1.ClientApiKey: The revoked record.
+//   - error: ErrAPIKeyNotFound if no key has the ID, or an error if saving fails.
+//
+// Side Effects:
+//   - Updates the key record in storage.
+func RevokeClientAPIKey(ctx context.Context, store storage.Storage, id string) (*configv1.ClientApiKey, error) {
+	record, err := store.GetAPIKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrAPIKeyNotFound
+	}
+	if record.GetRevokedAt() == "" {
+		record.SetRevokedAt(time.Now().UTC().Format(time.RFC3339))
+		if err := store.SaveAPIKey(ctx, record); err != nil {
+			return nil, err
+		}
+	}
+	return record, nil
+}
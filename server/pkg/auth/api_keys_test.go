@@ -0,0 +1,198 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParseClientAPIKey(t *testing.T) {
+	id, secret, ok := ParseClientAPIKey("mcpany_0123abcd_s3cret")
+	assert.True(t, ok)
+	assert.Equal(t, "0123abcd", id)
+	assert.Equal(t, "s3cret", secret)
+
+	for _, key := range []string{"", "global-key", "mcpany_", "mcpany_id", "mcpany__secret", "mcpany_id_"} {
+		_, _, ok := ParseClientAPIKey(key)
+		assert.False(t, ok, key)
+	}
+}
+
+func TestCreateClientAPIKey(t *testing.T) {
+	store := memory.NewStore()
+	record, key, err := CreateClientAPIKey(context.Background(), store, configv1.ClientApiKey_builder{
+		Name:   proto.String("ci"),
+		Scopes: []string{"viewer"},
+	}.Build())
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(key, ClientAPIKeyPrefix+record.GetId()+"_"))
+	assert.NotEmpty(t, record.GetCreatedAt())
+	assert.NotContains(t, record.GetKeyHash(), strings.TrimPrefix(key, ClientAPIKeyPrefix+record.GetId()+"_"))
+
+	stored, err := store.GetAPIKey(context.Background(), record.GetId())
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(record, stored))
+
+	_, _, err = CreateClientAPIKey(context.Background(), store, configv1.ClientApiKey_builder{}.Build())
+	assert.Error(t, err)
+
+	for _, scope := range []string{"admin", " Admin"} {
+		_, _, err = CreateClientAPIKey(context.Background(), store, configv1.ClientApiKey_builder{
+			Name:   proto.String("root"),
+			Scopes: []string{"viewer", scope},
+		}.Build())
+		assert.ErrorIs(t, err, ErrReservedAPIKeyScope, scope)
+	}
+}
+
+func TestManager_AuthenticateClientAPIKey(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	am := NewManager()
+	am.SetStorage(store)
+
+	record, key, err := CreateClientAPIKey(ctx, store, configv1.ClientApiKey_builder{
+		Name:      proto.String("ci"),
+		Scopes:    []string{"viewer"},
+		ProfileId: proto.String("dev"),
+	}.Build())
+	require.NoError(t, err)
+
+	t.Run("valid key", func(t *testing.T) {
+		authCtx, err := am.AuthenticateClientAPIKey(ctx, key)
+		require.NoError(t, err)
+		id, _ := APIKeyIDFromContext(authCtx)
+		assert.Equal(t, record.GetId(), id)
+		user, _ := UserFromContext(authCtx)
+		assert.Equal(t, "apikey:"+record.GetId(), user)
+		roles, _ := RolesFromContext(authCtx)
+		assert.Equal(t, []string{"viewer"}, roles)
+		profile, _ := ProfileIDFromContext(authCtx)
+		assert.Equal(t, "dev", profile)
+	})
+
+	t.Run("reserved scopes are not granted", func(t *testing.T) {
+		// A key stored before the reserved scopes were rejected.
+		legacy, legacyKey, err := GenerateClientAPIKey(configv1.ClientApiKey_builder{
+			Name:   proto.String("legacy"),
+			Scopes: []string{"admin", "viewer"},
+		}.Build())
+		require.NoError(t, err)
+		require.NoError(t, store.SaveAPIKey(ctx, legacy))
+
+		authCtx, err := am.AuthenticateClientAPIKey(ctx, legacyKey)
+		require.NoError(t, err)
+		roles, _ := RolesFromContext(authCtx)
+		assert.Equal(t, []string{"viewer"}, roles)
+
+		_, err = RotateClientAPIKey(ctx, store, legacy.GetId(), 0)
+		assert.ErrorIs(t, err, ErrReservedAPIKeyScope)
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		_, err := am.AuthenticateClientAPIKey(ctx, ClientAPIKeyPrefix+record.GetId()+"_wrong")
+		assert.ErrorIs(t, err, ErrInvalidAPIKey)
+	})
+
+	t.Run("unknown id", func(t *testing.T) {
+		_, err := am.AuthenticateClientAPIKey(ctx, "mcpany_unknown_secret")
+		assert.ErrorIs(t, err, ErrInvalidAPIKey)
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		_, revokedKey, err := CreateClientAPIKey(ctx, store, configv1.ClientApiKey_builder{Name: proto.String("old")}.Build())
+		require.NoError(t, err)
+		id, _, _ := ParseClientAPIKey(revokedKey)
+		_, err = RevokeClientAPIKey(ctx, store, id)
+		require.NoError(t, err)
+
+		_, err = am.AuthenticateClientAPIKey(ctx, revokedKey)
+		assert.ErrorIs(t, err, ErrInvalidAPIKey)
+	})
+
+	t.Run("revoke unknown", func(t *testing.T) {
+		_, err := RevokeClientAPIKey(ctx, store, "missing")
+		assert.ErrorIs(t, err, ErrAPIKeyNotFound)
+	})
+}
+
+func TestManager_AuthenticateClientAPIKey_RateLimit(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	am := NewManager()
+	am.SetStorage(store)
+
+	record, key, err := CreateClientAPIKey(ctx, store, configv1.ClientApiKey_builder{
+		Name:              proto.String("limited"),
+		RequestsPerSecond: proto.Float64(0.001),
+		Burst:             proto.Int64(2),
+	}.Build())
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err := am.AuthenticateClientAPIKey(ctx, key)
+		require.NoError(t, err)
+	}
+	_, err = am.AuthenticateClientAPIKey(ctx, key)
+	assert.ErrorIs(t, err, ErrAPIKeyRateLimited)
+
+	// Raising the limit takes effect without a restart.
+	record.SetBurst(10)
+	require.NoError(t, store.SaveAPIKey(ctx, record))
+	_, err = am.AuthenticateClientAPIKey(ctx, key)
+	assert.NoError(t, err)
+}
+
+func TestManager_Authenticate_ClientAPIKey(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	am := NewManager()
+	am.SetAPIKey("global-api-key")
+	am.SetStorage(store)
+
+	record, key, err := CreateClientAPIKey(ctx, store, configv1.ClientApiKey_builder{Name: proto.String("ci")}.Build())
+	require.NoError(t, err)
+
+	for name, set := range map[string]func(*http.Request){
+		"header": func(r *http.Request) { r.Header.Set("X-API-Key", key) },
+		"bearer": func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+key) },
+		"query":  func(r *http.Request) { r.URL.RawQuery = "api_key=" + key },
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			set(req)
+			authCtx, err := am.Authenticate(ctx, "", req)
+			require.NoError(t, err)
+			id, _ := APIKeyIDFromContext(authCtx)
+			assert.Equal(t, record.GetId(), id)
+		})
+	}
+
+	t.Run("invalid client key is not accepted as global key", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.Header.Set("X-API-Key", "mcpany_"+record.GetId()+"_nope")
+		_, err := am.Authenticate(ctx, "", req)
+		assert.ErrorIs(t, err, ErrInvalidAPIKey)
+	})
+
+	t.Run("global key still works", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.Header.Set("X-API-Key", "global-api-key")
+		authCtx, err := am.Authenticate(ctx, "", req)
+		require.NoError(t, err)
+		_, ok := APIKeyIDFromContext(authCtx)
+		assert.False(t, ok)
+	})
+}
@@ -315,14 +315,27 @@ func (a *TrustedHeaderAuthenticator) Authenticate(ctx context.Context, r *http.R
 type Manager struct {
 	authenticators *xsync.Map[string, Authenticator]
 	apiKey         string
+	keyLimiters    *xsync.Map[string, *keyLimiter]
+	// deprecatedUses counts requests made with rotated keys until the
+	// APIKeyRotator writes them to storage.
+	deprecatedUses *xsync.Map[string, *deprecatedUse]
 
 	// usersMu protects users map to allow atomic updates (hot-swap).
 	usersMu sync.RWMutex
 	users   map[string]*configv1.User
 
-	// mu protects storage
-	mu      sync.RWMutex
-	storage storage.Storage
+	// mu protects storage, resourceServer, jwtValidator and profiles
+	mu             sync.RWMutex
+	storage        storage.Storage
+	resourceServer *ResourceServer
+	jwtValidator   *JWTValidator
+	profiles       ProfileDefinitions
+}
+
+// ProfileDefinitions looks up the configured profile definitions.
+type ProfileDefinitions interface {
+	// GetProfileDefinition returns the profile definition by name.
+	GetProfileDefinition(name string) (*configv1.ProfileDefinition, bool)
 }
 
 // NewManager creates and initializes a new Manager with an empty authenticator registry.
@@ -334,6 +347,8 @@ type Manager struct {
 func NewManager() *Manager {
 	return &Manager{
 		authenticators: xsync.NewMap[string, Authenticator](),
+		keyLimiters:    xsync.NewMap[string, *keyLimiter](),
+		deprecatedUses: xsync.NewMap[string, *deprecatedUse](),
 		users:          make(map[string]*configv1.User),
 	}
 }
@@ -370,6 +385,96 @@ func (am *Manager) SetStorage(s storage.Storage) {
 	am.storage = s
 }
 
+// SetResourceServer enables OAuth 2.1 access tokens for downstream clients.
+//
+// Summary: Configures the OAuth resource server.
+//
+// Parameters:
+//   - rs: *ResourceServer. The resource server, or nil to disable OAuth.
+//
+// Side Effects:
+//   - Bearer tokens are validated against the resource server in Authenticate.
+func (am *Manager) SetResourceServer(rs *ResourceServer) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	am.resourceServer = rs
+}
+
+// ResourceServer returns the configured OAuth resource server.
+//
+// Summary: Retrieves the OAuth resource server.
+//
+// Returns:
+//   - *ResourceServer: The resource server, or nil if OAuth is not configured.
+func (am *Manager) ResourceServer() *ResourceServer {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+	return am.resourceServer
+}
+
+// SetJWTValidator enables validation of Bearer JWTs from trusted OIDC issuers.
+//
+// Summary: Configures the JWT validator.
+//
+// Parameters:
+//   - v: *JWTValidator. The validator, or nil to disable JWT validation.
+//
+// Side Effects:
+//   - Bearer tokens are validated against the issuers in Authenticate.
+func (am *Manager) SetJWTValidator(v *JWTValidator) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	am.jwtValidator = v
+}
+
+// JWTValidator returns the configured JWT validator.
+//
+// Summary: Retrieves the JWT validator.
+//
+// Returns:
+//   - *JWTValidator: The validator, or nil if JWT validation is not configured.
+func (am *Manager) JWTValidator() *JWTValidator {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+	return am.jwtValidator
+}
+
+// SetProfileDefinitions sets the profile definitions whose required roles
+// are checked when a caller selects a profile.
+//
+// Summary: Configures the profile definitions.
+//
+// Parameters:
+//   - profiles: ProfileDefinitions. The profile definitions, or nil for none.
+//
+// Side Effects:
+//   - Updates the internal profile definitions reference.
+func (am *Manager) SetProfileDefinitions(profiles ProfileDefinitions) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	am.profiles = profiles
+}
+
+// GetProfileDefinition returns a profile definition by name.
+//
+// Summary: Looks up a profile definition.
+//
+// Parameters:
+//   - name: string. The profile name.
+//
+// Returns:
+//   - *configv1.ProfileDefinition: The profile definition.
+//   - bool: True if found.
+func (am *Manager) GetProfileDefinition(name string) (*configv1.ProfileDefinition, bool) {
+	am.mu.RLock()
+	profiles := am.profiles
+	am.mu.RUnlock()
+	if profiles == nil {
+		return nil, false
+	}
+	return profiles.GetProfileDefinition(name)
+}
+
 // GetUser retrieves a user configuration by their ID.
 //
 // Summary: Looks up a user by ID.
@@ -434,6 +539,68 @@ func (am *Manager) AddAuthenticator(serviceID string, authenticator Authenticato
 //   - context.Context: The authenticated context.
 //   - error: Error if unauthorized.
 func (am *Manager) Authenticate(ctx context.Context, serviceID string, r *http.Request) (context.Context, error) {
+	// OAuth access tokens stand in for the global API key.
+	if rs := am.ResourceServer(); rs != nil && am.isOAuthRequest(r) {
+		rsCtx, err := rs.Authenticate(ctx, r)
+		if err == nil {
+			if authenticator, ok := am.authenticators.Load(serviceID); ok {
+				return authenticator.Authenticate(rsCtx, r)
+			}
+			return rsCtx, nil
+		}
+		// The token may still come from a trusted OIDC issuer.
+		if am.JWTValidator() == nil {
+			return rsCtx, fmt.Errorf("unauthorized: %w", err)
+		}
+	}
+
+	// JWTs from trusted OIDC issuers also stand in for the global API key.
+	// In downgrade mode, requests with an invalid token or no credentials at
+	// all are served with the downgrade roles instead of being rejected.
+	if v := am.JWTValidator(); v != nil && (am.isOAuthRequest(r) || (v.downgrade && !hasCredentials(r))) {
+		ctx, err := v.Authenticate(ctx, r)
+		if err != nil {
+			downgraded, ok := v.Downgrade(ctx)
+			if !ok {
+				return ctx, fmt.Errorf("unauthorized: %w", err)
+			}
+			ctx = downgraded
+		}
+		if authenticator, ok := am.authenticators.Load(serviceID); ok {
+			return authenticator.Authenticate(ctx, r)
+		}
+		return ctx, nil
+	}
+
+	// Per-client API keys are checked before the global key. A key the HTTP
+	// middleware has already checked is not checked again, so that a call
+	// uses one token of its rate limit.
+	if key := ClientAPIKeyFromRequest(r); key != "" {
+		if !clientAPIKeyAuthenticated(ctx, key) {
+			var err error
+			if ctx, err = am.AuthenticateClientAPIKey(ctx, key); err != nil {
+				return ctx, fmt.Errorf("unauthorized: %w", err)
+			}
+		}
+		if authenticator, ok := am.authenticators.Load(serviceID); ok {
+			return authenticator.Authenticate(ctx, r)
+		}
+		return ctx, nil
+	}
+
+	// Verified client certificates (mTLS on the listener) also stand in for
+	// the global API key, as in the HTTP middleware.
+	if subject, ok := ClientCertificateSubject(r); ok && !hasCredentials(r) {
+		ctx = ContextWithUser(ctx, subject)
+		if user, found := am.GetUser(subject); found && len(user.GetRoles()) > 0 {
+			ctx = ContextWithRoles(ctx, user.GetRoles())
+		}
+		if authenticator, ok := am.authenticators.Load(serviceID); ok {
+			return authenticator.Authenticate(ctx, r)
+		}
+		return ctx, nil
+	}
+
 	if am.apiKey != "" {
 		receivedKey := r.Header.Get("X-API-Key")
 		if receivedKey == "" {
@@ -482,6 +649,38 @@ func (am *Manager) Authenticate(ctx context.Context, serviceID string, r *http.R
 	return ctx, fmt.Errorf("unauthorized: no authentication configured")
 }
 
+// clientAPIKeyAuthenticated reports whether the context already holds the
+// identity of the client API key, set by the HTTP middleware.
+func clientAPIKeyAuthenticated(ctx context.Context, key string) bool {
+	if _, ok := APIKeyIDFromContext(ctx); !ok {
+		return false
+	}
+	authKey, ok := APIKeyFromContext(ctx)
+	return ok && subtle.ConstantTimeCompare([]byte(authKey), []byte(key)) == 1
+}
+
+// isOAuthRequest reports whether the request carries a bearer token that is
+// not an API key.
+func (am *Manager) isOAuthRequest(r *http.Request) bool {
+	if r.Header.Get("X-API-Key") != "" || r.URL.Query().Get("api_key") != "" {
+		return false
+	}
+	token, ok := BearerToken(r)
+	if !ok {
+		return false
+	}
+	if _, _, isClientKey := ParseClientAPIKey(token); isClientKey {
+		return false
+	}
+	return am.apiKey == "" || subtle.ConstantTimeCompare([]byte(token), []byte(am.apiKey)) != 1
+}
+
+// hasCredentials reports whether the request carries an Authorization header
+// or an API key.
+func hasCredentials(r *http.Request) bool {
+	return r.Header.Get("Authorization") != "" || r.Header.Get("X-API-Key") != "" || r.URL.Query().Get("api_key") != ""
+}
+
 // GetAuthenticator retrieves the authenticator registered for a specific service.
 //
 // Summary: Looks up an authenticator by service ID.
@@ -0,0 +1,40 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"net/http"
+)
+
+// ClientCertificateSubject returns the identity of the verified client
+// certificate of a request received over mTLS: the subject common name, or
+// the first DNS, email or URI subject alternative name if it has none.
+//
+// Only certificates verified against the listener's client CA count, so this
+// returns false for plain HTTP, for TLS without client certificates and for
+// certificates that were presented but not verified.
+//
+// Parameters:
+//   - r: *http.Request. The request.
+//
+// Returns:
+//   - string: The identity.
+//   - bool: True if the request carries a verified client certificate with an identity.
+func ClientCertificateSubject(r *http.Request) (string, bool) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+	cert := r.TLS.VerifiedChains[0][0]
+	switch {
+	case cert.Subject.CommonName != "":
+		return cert.Subject.CommonName, true
+	case len(cert.DNSNames) > 0:
+		return cert.DNSNames[0], true
+	case len(cert.EmailAddresses) > 0:
+		return cert.EmailAddresses[0], true
+	case len(cert.URIs) > 0:
+		return cert.URIs[0].String(), true
+	}
+	return "", false
+}
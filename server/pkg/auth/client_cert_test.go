@@ -0,0 +1,53 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientCertificateSubject(t *testing.T) {
+	withChain := func(cert *x509.Certificate) *tls.ConnectionState {
+		return &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert}},
+		}
+	}
+	spiffe, _ := url.Parse("spiffe://example.org/agent")
+
+	tests := []struct {
+		name   string
+		state  *tls.ConnectionState
+		want   string
+		wantOK bool
+	}{
+		{name: "plain HTTP"},
+		{name: "no client certificate", state: &tls.ConnectionState{}},
+		{
+			name:  "unverified certificate",
+			state: &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "mallory"}}}},
+		},
+		{name: "common name", state: withChain(&x509.Certificate{Subject: pkix.Name{CommonName: "ci-bot"}, DNSNames: []string{"ci.internal"}}), want: "ci-bot", wantOK: true},
+		{name: "DNS name", state: withChain(&x509.Certificate{DNSNames: []string{"ci.internal"}}), want: "ci.internal", wantOK: true},
+		{name: "email", state: withChain(&x509.Certificate{EmailAddresses: []string{"bot@example.com"}}), want: "bot@example.com", wantOK: true},
+		{name: "URI", state: withChain(&x509.Certificate{URIs: []*url.URL{spiffe}}), want: "spiffe://example.org/agent", wantOK: true},
+		{name: "no identity", state: withChain(&x509.Certificate{})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.TLS = tt.state
+			got, ok := ClientCertificateSubject(r)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
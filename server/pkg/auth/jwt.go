@@ -0,0 +1,343 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+)
+
+// issuerRetryInterval is how long a failed issuer discovery is remembered
+// before it is attempted again.
+const issuerRetryInterval = 30 * time.Second
+
+// defaultJWTRolesClaim is the claim read for roles when none is configured.
+const defaultJWTRolesClaim = "roles"
+
+// ClaimsContextKey is the context key for the claims of a validated JWT.
+const ClaimsContextKey authContextKey = "jwt_claims"
+
+// ContextWithClaims returns a new context with the claims of a validated JWT.
+//
+// Summary: Embeds JWT claims into the context.
+//
+// Parameters:
+//   - ctx: context.Context. The context to extend.
+//   - claims: map[string]any. The decoded claims.
+//
+// Returns:
+//   - context.Context: A new context containing the claims.
+func ContextWithClaims(ctx context.Context, claims map[string]any) context.Context {
+	return context.WithValue(ctx, ClaimsContextKey, claims)
+}
+
+// ClaimFromContext returns a claim of the JWT that authenticated the request.
+//
+// Summary: Looks up a JWT claim of the request.
+//
+// Parameters:
+//   - ctx: context.Context. The request context.
+//   - path: string. The claim name, or a dotted path into nested claims, e.g. "org.team".
+//
+// Returns:
+//   - any: The claim value.
+//   - bool: True if the request carries a JWT with the claim.
+func ClaimFromContext(ctx context.Context, path string) (any, bool) {
+	claims, ok := ctx.Value(ClaimsContextKey).(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	v := lookupClaim(claims, path)
+	return v, v != nil
+}
+
+// JWTValidator validates Bearer JWTs issued by one or more trusted OIDC
+// issuers and maps their claims to the user, roles and profile of the request.
+type JWTValidator struct {
+	issuers          map[string]*jwtIssuer
+	downgrade        bool
+	downgradeRoles   []string
+	downgradeProfile string
+}
+
+// jwtIssuer is a trusted issuer. Its verifier is created on first use so an
+// unreachable issuer does not prevent the server from starting.
+type jwtIssuer struct {
+	cfg *configv1.JWTIssuerConfig
+
+	mu        sync.Mutex
+	verifier  *oidc.IDTokenVerifier
+	lastErr   error
+	lastTried time.Time
+}
+
+// NewJWTValidator creates a JWTValidator from the configuration.
+//
+// Summary: Initializes Bearer JWT validation for incoming requests.
+//
+// Parameters:
+//   - cfg: *configv1.JWTAuthConfig. The JWT validation configuration.
+//
+// Returns:
+//   - *JWTValidator: The validator.
+//   - error: An error if no issuer is configured or an issuer is incomplete.
+func NewJWTValidator(cfg *configv1.JWTAuthConfig) (*JWTValidator, error) {
+	if len(cfg.GetIssuers()) == 0 {
+		return nil, fmt.Errorf("jwt auth requires at least one issuer")
+	}
+	issuers := make(map[string]*jwtIssuer, len(cfg.GetIssuers()))
+	for _, ic := range cfg.GetIssuers() {
+		if ic.GetIssuer() == "" {
+			return nil, fmt.Errorf("jwt auth issuer url is required")
+		}
+		if len(ic.GetAudiences()) == 0 {
+			return nil, fmt.Errorf("jwt auth issuer %q requires at least one audience", ic.GetIssuer())
+		}
+		if _, dup := issuers[ic.GetIssuer()]; dup {
+			return nil, fmt.Errorf("jwt auth issuer %q is configured twice", ic.GetIssuer())
+		}
+		issuers[ic.GetIssuer()] = &jwtIssuer{cfg: ic}
+	}
+	downgrade := cfg.GetOnFailure() == configv1.JWTAuthConfig_FAILURE_ACTION_DOWNGRADE
+	// An empty profile is unrestricted, so downgraded requests must be
+	// confined to an explicit one.
+	if downgrade && cfg.GetDowngradeProfile() == "" {
+		return nil, fmt.Errorf("jwt auth downgrade requires a downgrade_profile")
+	}
+	return &JWTValidator{
+		issuers:          issuers,
+		downgrade:        downgrade,
+		downgradeRoles:   cfg.GetDowngradeRoles(),
+		downgradeProfile: cfg.GetDowngradeProfile(),
+	}, nil
+}
+
+// Authenticate validates the bearer JWT of the request.
+//
+// Summary: Validates a Bearer JWT against the trusted issuers.
+//
+// Parameters:
+//   - ctx: context.Context. The request context.
+//   - r: *http.Request. The request carrying the token in the Authorization header.
+//
+// Returns:
+//   - context.Context: The context with the user, roles and profile mapped from the token claims.
+//   - error: ErrInvalidToken if the token is missing, from an unknown issuer, or fails validation.
+//
+// Side Effects:
+//   - Discovers the issuer's OpenID configuration and fetches its JWKS on first use.
+func (v *JWTValidator) Authenticate(ctx context.Context, r *http.Request) (context.Context, error) {
+	token, ok := BearerToken(r)
+	if !ok {
+		return ctx, ErrInvalidToken
+	}
+	issuerURL, err := unverifiedIssuer(token)
+	if err != nil {
+		return ctx, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	issuer, ok := v.issuers[issuerURL]
+	if !ok {
+		return ctx, fmt.Errorf("%w: untrusted issuer", ErrInvalidToken)
+	}
+	verifier, err := issuer.getVerifier(ctx)
+	if err != nil {
+		return ctx, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	idToken, err := verifier.Verify(ctx, token)
+	if err != nil {
+		return ctx, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	audiences := issuer.cfg.GetAudiences()
+	if !slices.ContainsFunc(idToken.Audience, func(aud string) bool { return slices.Contains(audiences, aud) }) {
+		return ctx, fmt.Errorf("%w: audience mismatch", ErrInvalidToken)
+	}
+
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		return ctx, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	user := idToken.Subject
+	if claim := issuer.cfg.GetUserClaim(); claim != "" {
+		user, _ = lookupClaim(claims, claim).(string)
+	}
+	if user == "" {
+		return ctx, fmt.Errorf("%w: missing user claim", ErrInvalidToken)
+	}
+	ctx = ContextWithUser(ctx, user)
+	ctx = ContextWithClaims(ctx, claims)
+	if roles := issuer.roles(claims); len(roles) > 0 {
+		ctx = ContextWithRoles(ctx, roles)
+	}
+	if claim := issuer.cfg.GetProfileClaim(); claim != "" {
+		if profile, _ := lookupClaim(claims, claim).(string); profile != "" {
+			ctx = ContextWithProfileID(ctx, profile)
+		}
+	}
+	return ctx, nil
+}
+
+// Downgrade returns the context of a request without a valid token when the
+// validator is configured to downgrade rather than reject such requests.
+//
+// Summary: Applies the downgrade roles and profile to an unauthenticated request.
+//
+// Parameters:
+//   - ctx: context.Context. The request context.
+//
+// Returns:
+//   - context.Context: The context with the downgrade roles and profile.
+//   - bool: False if such requests must be rejected.
+func (v *JWTValidator) Downgrade(ctx context.Context) (context.Context, bool) {
+	if !v.downgrade {
+		return ctx, false
+	}
+	ctx = ContextWithUser(ctx, "anonymous")
+	ctx = ContextWithRoles(ctx, v.downgradeRoles)
+	ctx = ContextWithProfileID(ctx, v.downgradeProfile)
+	return ctx, true
+}
+
+// WriteChallenge rejects the request with a Bearer WWW-Authenticate challenge.
+//
+// Parameters:
+//   - w: http.ResponseWriter. The response writer.
+//   - err: error. The authentication error; nil if no token was presented.
+//
+// Side Effects:
+//   - Writes a 401 response.
+func (v *JWTValidator) WriteChallenge(w http.ResponseWriter, err error) {
+	challenge := "Bearer"
+	if err != nil {
+		challenge += ` error="invalid_token"`
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
+
+func (i *jwtIssuer) getVerifier(ctx context.Context) (*oidc.IDTokenVerifier, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.verifier != nil {
+		return i.verifier, nil
+	}
+	if i.lastErr != nil && time.Since(i.lastTried) < issuerRetryInterval {
+		return nil, i.lastErr
+	}
+
+	// The key set outlives the request, so it must not be bound to its context.
+	keyCtx := context.WithoutCancel(ctx)
+	// Audience is checked against the configured list, so the verifier only
+	// checks signature, issuer and expiry.
+	oidcConfig := &oidc.Config{SkipClientIDCheck: true}
+	if i.cfg.GetJwksUrl() != "" {
+		oidcConfig.SupportedSigningAlgs = []string{
+			oidc.RS256, oidc.RS384, oidc.RS512,
+			oidc.ES256, oidc.ES384, oidc.ES512,
+			oidc.PS256, oidc.PS384, oidc.PS512,
+			oidc.EdDSA,
+		}
+		keySet := oidc.NewRemoteKeySet(keyCtx, i.cfg.GetJwksUrl())
+		i.verifier = oidc.NewVerifier(i.cfg.GetIssuer(), keySet, oidcConfig)
+		return i.verifier, nil
+	}
+
+	provider, err := oidc.NewProvider(keyCtx, i.cfg.GetIssuer())
+	if err != nil {
+		i.lastErr = fmt.Errorf("failed to discover issuer %q: %w", i.cfg.GetIssuer(), err)
+		i.lastTried = time.Now()
+		return nil, i.lastErr
+	}
+	i.verifier = provider.Verifier(oidcConfig)
+	i.lastErr = nil
+	return i.verifier, nil
+}
+
+// roles maps the roles claim of a token to roles.
+func (i *jwtIssuer) roles(claims map[string]any) []string {
+	claim := i.cfg.GetRolesClaim()
+	if claim == "" {
+		claim = defaultJWTRolesClaim
+	}
+	values := claimStrings(lookupClaim(claims, claim))
+
+	roles := slices.Clone(i.cfg.GetDefaultRoles())
+	if len(i.cfg.GetRoleMappings()) == 0 {
+		roles = append(roles, values...)
+	} else {
+		for _, m := range i.cfg.GetRoleMappings() {
+			if slices.Contains(values, m.GetValue()) {
+				roles = append(roles, m.GetRole())
+			}
+		}
+	}
+	slices.Sort(roles)
+	return slices.Compact(roles)
+}
+
+// lookupClaim resolves a dotted claim path, e.g. "realm_access.roles".
+func lookupClaim(claims map[string]any, path string) any {
+	if v, ok := claims[path]; ok {
+		return v
+	}
+	var cur any = claims
+	for _, part := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		cur = m[part]
+	}
+	return cur
+}
+
+// claimStrings accepts both a list of strings and a space-delimited string.
+func claimStrings(v any) []string {
+	switch val := v.(type) {
+	case string:
+		return strings.Fields(val)
+	case []any:
+		out := make([]string, 0, len(val))
+		for _, item := range val {
+			if s, ok := item.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// unverifiedIssuer reads the "iss" claim of a JWT without verifying it, to
+// select the issuer that verifies the token.
+func unverifiedIssuer(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("malformed jwt")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("malformed jwt payload: %w", err)
+	}
+	var claims struct {
+		Issuer string `json:"iss"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("malformed jwt claims: %w", err)
+	}
+	return claims.Issuer, nil
+}
@@ -0,0 +1,278 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+const testJWTAudience = "mcpany"
+
+func jwtIssuerConfig(mock *MockOAuth2Server, mutate func(*configv1.JWTIssuerConfig)) *configv1.JWTIssuerConfig {
+	cfg := configv1.JWTIssuerConfig_builder{
+		Issuer:    proto.String(mock.URL),
+		Audiences: []string{testJWTAudience},
+	}.Build()
+	if mutate != nil {
+		mutate(cfg)
+	}
+	return cfg
+}
+
+func newTestJWTValidator(t *testing.T, onFailure configv1.JWTAuthConfig_FailureAction, issuers ...*configv1.JWTIssuerConfig) *JWTValidator {
+	t.Helper()
+	v, err := NewJWTValidator(configv1.JWTAuthConfig_builder{
+		Issuers:          issuers,
+		OnFailure:        onFailure.Enum(),
+		DowngradeRoles:   []string{"guest"},
+		DowngradeProfile: proto.String("public"),
+	}.Build())
+	require.NoError(t, err)
+	return v
+}
+
+func jwtClaims(mock *MockOAuth2Server, extra jwt.MapClaims) jwt.MapClaims {
+	claims := jwt.MapClaims{
+		"iss": mock.URL,
+		"sub": "user-123",
+		"aud": testJWTAudience,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range extra {
+		claims[k] = v
+	}
+	return claims
+}
+
+func TestJWTValidator_Authenticate(t *testing.T) {
+	mock := NewMockOAuth2Server(t)
+	defer mock.Close()
+	other := NewMockOAuth2Server(t)
+	defer other.Close()
+
+	v := newTestJWTValidator(t, configv1.JWTAuthConfig_FAILURE_ACTION_REJECT,
+		jwtIssuerConfig(mock, nil),
+		jwtIssuerConfig(other, func(cfg *configv1.JWTIssuerConfig) {
+			cfg.SetJwksUrl(other.URL + "/jwks")
+			cfg.SetUserClaim("email")
+			cfg.SetRolesClaim("realm_access.roles")
+			cfg.SetDefaultRoles([]string{"viewer"})
+			cfg.SetRoleMappings([]*configv1.JWTRoleMapping{
+				configv1.JWTRoleMapping_builder{Value: proto.String("mcp-admins"), Role: proto.String("admin")}.Build(),
+			})
+			cfg.SetProfileClaim("tier")
+		}),
+	)
+
+	t.Run("subject and raw roles", func(t *testing.T) {
+		token := mock.NewIDToken(t, jwtClaims(mock, jwt.MapClaims{"roles": []string{"editor", "admin", "editor"}}))
+		ctx, err := v.Authenticate(context.Background(), bearerRequest(token))
+		require.NoError(t, err)
+
+		user, _ := UserFromContext(ctx)
+		assert.Equal(t, "user-123", user)
+		roles, _ := RolesFromContext(ctx)
+		assert.Equal(t, []string{"admin", "editor"}, roles)
+		_, ok := ProfileIDFromContext(ctx)
+		assert.False(t, ok)
+	})
+
+	t.Run("second issuer maps nested claims", func(t *testing.T) {
+		token := other.NewIDToken(t, jwtClaims(other, jwt.MapClaims{
+			"email":        "alice@example.com",
+			"realm_access": map[string]any{"roles": []string{"mcp-admins", "offline_access"}},
+			"tier":         "gold",
+		}))
+		ctx, err := v.Authenticate(context.Background(), bearerRequest(token))
+		require.NoError(t, err)
+
+		user, _ := UserFromContext(ctx)
+		assert.Equal(t, "alice@example.com", user)
+		roles, _ := RolesFromContext(ctx)
+		assert.Equal(t, []string{"admin", "viewer"}, roles)
+		profile, _ := ProfileIDFromContext(ctx)
+		assert.Equal(t, "gold", profile)
+		roleClaim, ok := ClaimFromContext(ctx, "realm_access.roles")
+		assert.True(t, ok)
+		assert.Equal(t, []any{"mcp-admins", "offline_access"}, roleClaim)
+		_, ok = ClaimFromContext(ctx, "org.team")
+		assert.False(t, ok)
+	})
+
+	t.Run("missing user claim", func(t *testing.T) {
+		token := other.NewIDToken(t, jwtClaims(other, nil))
+		_, err := v.Authenticate(context.Background(), bearerRequest(token))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("untrusted issuer", func(t *testing.T) {
+		token := mock.NewIDToken(t, jwtClaims(mock, jwt.MapClaims{"iss": "https://evil.example.com"}))
+		_, err := v.Authenticate(context.Background(), bearerRequest(token))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("token signed by another issuer's key", func(t *testing.T) {
+		token := other.NewIDToken(t, jwtClaims(mock, nil))
+		_, err := v.Authenticate(context.Background(), bearerRequest(token))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("audience mismatch", func(t *testing.T) {
+		token := mock.NewIDToken(t, jwtClaims(mock, jwt.MapClaims{"aud": "someone-else"}))
+		_, err := v.Authenticate(context.Background(), bearerRequest(token))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		token := mock.NewIDToken(t, jwtClaims(mock, jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}))
+		_, err := v.Authenticate(context.Background(), bearerRequest(token))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("malformed token", func(t *testing.T) {
+		_, err := v.Authenticate(context.Background(), bearerRequest("not-a-jwt"))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func TestJWTValidator_UnreachableIssuer(t *testing.T) {
+	mock := NewMockOAuth2Server(t)
+	url := mock.URL
+	mock.Close()
+
+	v, err := NewJWTValidator(configv1.JWTAuthConfig_builder{
+		Issuers: []*configv1.JWTIssuerConfig{
+			configv1.JWTIssuerConfig_builder{Issuer: proto.String(url), Audiences: []string{testJWTAudience}}.Build(),
+		},
+	}.Build())
+	require.NoError(t, err, "discovery is deferred to the first request")
+
+	token := mock.NewIDToken(t, jwtClaims(mock, nil))
+	_, err = v.Authenticate(context.Background(), bearerRequest(token))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestJWTValidator_Downgrade(t *testing.T) {
+	mock := NewMockOAuth2Server(t)
+	defer mock.Close()
+
+	reject := newTestJWTValidator(t, configv1.JWTAuthConfig_FAILURE_ACTION_REJECT, jwtIssuerConfig(mock, nil))
+	_, ok := reject.Downgrade(context.Background())
+	assert.False(t, ok)
+
+	downgrade := newTestJWTValidator(t, configv1.JWTAuthConfig_FAILURE_ACTION_DOWNGRADE, jwtIssuerConfig(mock, nil))
+	ctx, ok := downgrade.Downgrade(context.Background())
+	require.True(t, ok)
+	user, _ := UserFromContext(ctx)
+	assert.Equal(t, "anonymous", user)
+	roles, _ := RolesFromContext(ctx)
+	assert.Equal(t, []string{"guest"}, roles)
+	profile, _ := ProfileIDFromContext(ctx)
+	assert.Equal(t, "public", profile)
+
+	// Without a profile, downgraded requests would see every tool.
+	_, err := NewJWTValidator(configv1.JWTAuthConfig_builder{
+		Issuers:   []*configv1.JWTIssuerConfig{jwtIssuerConfig(mock, nil)},
+		OnFailure: configv1.JWTAuthConfig_FAILURE_ACTION_DOWNGRADE.Enum(),
+	}.Build())
+	assert.ErrorContains(t, err, "downgrade_profile")
+}
+
+func TestJWTValidator_WriteChallenge(t *testing.T) {
+	mock := NewMockOAuth2Server(t)
+	defer mock.Close()
+	v := newTestJWTValidator(t, configv1.JWTAuthConfig_FAILURE_ACTION_REJECT, jwtIssuerConfig(mock, nil))
+
+	rec := httptest.NewRecorder()
+	v.WriteChallenge(rec, nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+
+	rec = httptest.NewRecorder()
+	v.WriteChallenge(rec, ErrInvalidToken)
+	assert.Equal(t, `Bearer error="invalid_token"`, rec.Header().Get("WWW-Authenticate"))
+}
+
+func TestNewJWTValidator_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		issuers []*configv1.JWTIssuerConfig
+	}{
+		{name: "no issuers"},
+		{name: "missing issuer url", issuers: []*configv1.JWTIssuerConfig{
+			configv1.JWTIssuerConfig_builder{Audiences: []string{"a"}}.Build(),
+		}},
+		{name: "missing audience", issuers: []*configv1.JWTIssuerConfig{
+			configv1.JWTIssuerConfig_builder{Issuer: proto.String("https://idp.example.com")}.Build(),
+		}},
+		{name: "duplicate issuer", issuers: []*configv1.JWTIssuerConfig{
+			configv1.JWTIssuerConfig_builder{Issuer: proto.String("https://idp.example.com"), Audiences: []string{"a"}}.Build(),
+			configv1.JWTIssuerConfig_builder{Issuer: proto.String("https://idp.example.com"), Audiences: []string{"b"}}.Build(),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewJWTValidator(configv1.JWTAuthConfig_builder{Issuers: tt.issuers}.Build())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestManager_Authenticate_JWT(t *testing.T) {
+	mock := NewMockOAuth2Server(t)
+	defer mock.Close()
+
+	t.Run("reject", func(t *testing.T) {
+		am := NewManager()
+		am.SetAPIKey("global-api-key")
+		am.SetJWTValidator(newTestJWTValidator(t, configv1.JWTAuthConfig_FAILURE_ACTION_REJECT, jwtIssuerConfig(mock, nil)))
+
+		token := mock.NewIDToken(t, jwtClaims(mock, nil))
+		ctx, err := am.Authenticate(context.Background(), "", bearerRequest(token))
+		require.NoError(t, err)
+		user, _ := UserFromContext(ctx)
+		assert.Equal(t, "user-123", user)
+
+		_, err = am.Authenticate(context.Background(), "", bearerRequest("not-a-jwt"))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+
+		_, err = am.Authenticate(context.Background(), "", httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Error(t, err)
+
+		// The API key keeps working.
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-API-Key", "global-api-key")
+		_, err = am.Authenticate(context.Background(), "", req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("downgrade", func(t *testing.T) {
+		am := NewManager()
+		am.SetAPIKey("global-api-key")
+		am.SetJWTValidator(newTestJWTValidator(t, configv1.JWTAuthConfig_FAILURE_ACTION_DOWNGRADE, jwtIssuerConfig(mock, nil)))
+
+		for _, req := range []*http.Request{bearerRequest("not-a-jwt"), httptest.NewRequest(http.MethodGet, "/", nil)} {
+			ctx, err := am.Authenticate(context.Background(), "", req)
+			require.NoError(t, err)
+			roles, _ := RolesFromContext(ctx)
+			assert.Equal(t, []string{"guest"}, roles)
+		}
+
+		// A wrong API key is still rejected.
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-API-Key", "wrong")
+		_, err := am.Authenticate(context.Background(), "", req)
+		assert.Error(t, err)
+	})
+}
@@ -4,11 +4,19 @@
 package auth
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
 	configv1 "github.com/mcpany/core/proto/config/v1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -30,3 +38,194 @@ func TestOAuth2Auth_Authenticate(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+// tokenServer is an OAuth2 token endpoint that records the form of each
+// token request and issues numbered tokens.
+type tokenServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []map[string]string
+	fail     bool
+	// hits counts all the token requests, the failed ones included.
+	hits int
+}
+
+func newTokenServer(t *testing.T, expiresIn int) *tokenServer {
+	t.Helper()
+	ts := &tokenServer{}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		ts.mu.Lock()
+		defer ts.mu.Unlock()
+		ts.hits++
+		if ts.fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		form := map[string]string{}
+		for k := range r.PostForm {
+			form[k] = r.PostForm.Get(k)
+		}
+		ts.requests = append(ts.requests, form)
+		n := len(ts.requests)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  fmt.Sprintf("access-%d", n),
+			"token_type":    "Bearer",
+			"expires_in":    expiresIn,
+			"refresh_token": fmt.Sprintf("refresh-%d", n),
+		})
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func (ts *tokenServer) forms() []map[string]string {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return append([]map[string]string(nil), ts.requests...)
+}
+
+func plainSecret(v string) *configv1.SecretValue {
+	return configv1.SecretValue_builder{PlainText: proto.String(v)}.Build()
+}
+
+func authorize(t *testing.T, a *OAuth2Auth) string {
+	t.Helper()
+	req, _ := http.NewRequest("GET", "/", nil)
+	require.NoError(t, a.Authenticate(req))
+	return req.Header.Get("Authorization")
+}
+
+func TestOAuth2Auth_TokenCaching(t *testing.T) {
+	ts := newTokenServer(t, 3600)
+	a := &OAuth2Auth{
+		ClientID:     plainSecret("id"),
+		ClientSecret: plainSecret("secret"),
+		TokenURL:     ts.URL,
+		Scopes:       []string{"read"},
+		TokenParams:  map[string]string{"audience": "https://api.example.com"},
+	}
+
+	assert.Equal(t, "Bearer access-1", authorize(t, a))
+	assert.Equal(t, "Bearer access-1", authorize(t, a), "the token is cached")
+	forms := ts.forms()
+	require.Len(t, forms, 1)
+	assert.Equal(t, "client_credentials", forms[0]["grant_type"])
+	assert.Equal(t, "read", forms[0]["scope"])
+	assert.Equal(t, "https://api.example.com", forms[0]["audience"])
+
+	// Due for refresh.
+	a.refreshAt = time.Now().Add(-time.Second)
+	assert.Equal(t, "Bearer access-2", authorize(t, a))
+
+	// A rotated client secret invalidates the cached token.
+	a.ClientSecret = plainSecret("rotated")
+	assert.Equal(t, "Bearer access-3", authorize(t, a))
+
+	// A failed refresh keeps using the cached token while it is valid.
+	ts.mu.Lock()
+	ts.fail = true
+	ts.mu.Unlock()
+	a.refreshAt = time.Now().Add(-time.Second)
+	assert.Equal(t, "Bearer access-3", authorize(t, a))
+	a.token.Expiry = time.Now().Add(-time.Second)
+	a.refreshAt = a.token.Expiry
+	req, _ := http.NewRequest("GET", "/", nil)
+	assert.ErrorContains(t, a.Authenticate(req), "failed to obtain OAuth2 token")
+}
+
+func TestOAuth2Auth_RefreshBackoff(t *testing.T) {
+	ts := newTokenServer(t, 3600)
+	a := &OAuth2Auth{ClientID: plainSecret("id"), ClientSecret: plainSecret("secret"), TokenURL: ts.URL}
+	hits := func() int {
+		ts.mu.Lock()
+		defer ts.mu.Unlock()
+		return ts.hits
+	}
+	assert.Equal(t, "Bearer access-1", authorize(t, a))
+
+	ts.mu.Lock()
+	ts.fail = true
+	ts.mu.Unlock()
+	a.refreshAt = time.Now().Add(-time.Second)
+	assert.Equal(t, "Bearer access-1", authorize(t, a))
+	failed := hits()
+	require.Greater(t, failed, 1)
+	for range 5 {
+		assert.Equal(t, "Bearer access-1", authorize(t, a))
+	}
+	assert.Equal(t, failed, hits(), "a failed refresh is not retried at once")
+
+	a.refreshAt = time.Now().Add(-time.Second)
+	assert.Equal(t, "Bearer access-1", authorize(t, a))
+	assert.Greater(t, hits(), failed, "the refresh is retried after the backoff")
+
+	// The backoff ends when the cached token expires.
+	a.token.Expiry = time.Now().Add(15 * time.Second)
+	a.refreshAt = time.Now().Add(-time.Second)
+	assert.Equal(t, "Bearer access-1", authorize(t, a))
+	assert.False(t, a.refreshAt.After(a.token.Expiry))
+}
+
+func TestOAuth2Auth_RefreshTokenGrant(t *testing.T) {
+	ts := newTokenServer(t, 3600)
+	config := configv1.Authentication_builder{
+		Oauth2: configv1.OAuth2Auth_builder{
+			ClientId:     plainSecret("public-client"),
+			TokenUrl:     proto.String(ts.URL),
+			GrantType:    configv1.OAuth2Auth_GRANT_TYPE_REFRESH_TOKEN.Enum(),
+			RefreshToken: plainSecret("refresh-0"),
+		}.Build(),
+	}.Build()
+	authenticator, err := NewUpstreamAuthenticator(config)
+	require.NoError(t, err)
+	a := authenticator.(*OAuth2Auth)
+
+	assert.Equal(t, "Bearer access-1", authorize(t, a))
+	a.refreshAt = time.Now().Add(-time.Second)
+	assert.Equal(t, "Bearer access-2", authorize(t, a))
+
+	forms := ts.forms()
+	require.Len(t, forms, 2)
+	assert.Equal(t, "refresh_token", forms[0]["grant_type"])
+	assert.Equal(t, "refresh-0", forms[0]["refresh_token"])
+	assert.Equal(t, "refresh-1", forms[1]["refresh_token"], "the rotated refresh token is used")
+
+	_, err = NewUpstreamAuthenticator(configv1.Authentication_builder{
+		Oauth2: configv1.OAuth2Auth_builder{
+			ClientId:  plainSecret("public-client"),
+			TokenUrl:  proto.String(ts.URL),
+			GrantType: configv1.OAuth2Auth_GRANT_TYPE_REFRESH_TOKEN.Enum(),
+		}.Build(),
+	}.Build())
+	assert.ErrorContains(t, err, "requires a refresh token")
+}
+
+func TestOAuth2Auth_ConcurrentRequestsShareFetch(t *testing.T) {
+	ts := newTokenServer(t, 3600)
+	a := &OAuth2Auth{ClientID: plainSecret("id"), ClientSecret: plainSecret("secret"), TokenURL: ts.URL}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := a.Token(context.Background())
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, ts.forms(), 1)
+}
+
+func TestOAuth2Auth_RefreshTime(t *testing.T) {
+	now := time.Now()
+	a := &OAuth2Auth{}
+	assert.WithinDuration(t, now.Add(59*time.Minute), a.refreshTime(&oauth2.Token{Expiry: now.Add(time.Hour)}, now), 0)
+	assert.WithinDuration(t, now.Add(15*time.Second), a.refreshTime(&oauth2.Token{Expiry: now.Add(30 * time.Second)}, now), 0, "no earlier than half the lifetime")
+	assert.True(t, a.refreshTime(&oauth2.Token{}, now).IsZero())
+
+	a.RefreshBeforeExpiry = 10 * time.Minute
+	assert.WithinDuration(t, now.Add(50*time.Minute), a.refreshTime(&oauth2.Token{Expiry: now.Add(time.Hour)}, now), 0)
+}
@@ -0,0 +1,289 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+)
+
+// ProtectedResourceMetadataPath is the well-known path of the OAuth 2.0
+// Protected Resource Metadata document (RFC 9728).
+const ProtectedResourceMetadataPath = "/.well-known/oauth-protected-resource"
+
+// ErrInvalidToken is returned when a bearer token is missing, malformed,
+// expired, or not issued for this resource.
+var ErrInvalidToken = errors.New("invalid token")
+
+// InsufficientScopeError is returned when a valid token lacks a required scope.
+type InsufficientScopeError struct {
+	// Scopes are the scopes required to access the resource.
+	Scopes []string
+}
+
+// Error implements the error interface.
+//
+// Returns:
+//   - string: The error message.
+func (e *InsufficientScopeError) Error() string {
+	return fmt.Sprintf("insufficient scope: requires %s", strings.Join(e.Scopes, " "))
+}
+
+// ResourceServer validates OAuth 2.1 access tokens presented by downstream MCP
+// clients, following the MCP authorization specification. Tokens are JWTs
+// signed by the configured authorization server and bound to this resource
+// through their audience.
+type ResourceServer struct {
+	verifier        *oidc.IDTokenVerifier
+	issuer          string
+	resource        string
+	audiences       []string
+	scopesSupported []string
+	requiredScopes  []string
+	scopeProfiles   []*configv1.OAuthScopeProfile
+}
+
+// NewResourceServer creates a ResourceServer from the configuration.
+//
+// Summary: Initializes OAuth 2.1 access token validation.
+//
+// Parameters:
+//   - ctx: context.Context. The context used for issuer discovery.
+//   - cfg: *configv1.OAuthResourceServerConfig. The resource server configuration.
+//
+// Returns:
+//   - *ResourceServer: The resource server.
+//   - error: An error if the issuer, or both the resource and the audiences, are
+//     missing, or if the issuer cannot be discovered.
+//
+// Side Effects:
+//   - Fetches the issuer's OpenID configuration when no JWKS URL is configured.
+func NewResourceServer(ctx context.Context, cfg *configv1.OAuthResourceServerConfig) (*ResourceServer, error) {
+	if cfg.GetIssuer() == "" {
+		return nil, fmt.Errorf("oauth resource server issuer is required")
+	}
+	// The expected audience is never derived from the request, whose Host
+	// header the client controls.
+	audiences := cfg.GetAudiences()
+	if len(audiences) == 0 && cfg.GetResource() != "" {
+		audiences = []string{cfg.GetResource()}
+	}
+	if len(audiences) == 0 {
+		return nil, fmt.Errorf("oauth resource server requires a resource or audiences")
+	}
+
+	// Audience is checked manually against the resource, so the verifier only
+	// checks signature, issuer and expiry.
+	oidcConfig := &oidc.Config{SkipClientIDCheck: true}
+	var verifier *oidc.IDTokenVerifier
+	if cfg.GetJwksUrl() != "" {
+		oidcConfig.SupportedSigningAlgs = []string{
+			oidc.RS256, oidc.RS384, oidc.RS512,
+			oidc.ES256, oidc.ES384, oidc.ES512,
+			oidc.PS256, oidc.PS384, oidc.PS512,
+			oidc.EdDSA,
+		}
+		keySet := oidc.NewRemoteKeySet(ctx, cfg.GetJwksUrl())
+		verifier = oidc.NewVerifier(cfg.GetIssuer(), keySet, oidcConfig)
+	} else {
+		provider, err := oidc.NewProvider(ctx, cfg.GetIssuer())
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover oauth issuer: %w", err)
+		}
+		verifier = provider.Verifier(oidcConfig)
+	}
+
+	return &ResourceServer{
+		verifier:        verifier,
+		issuer:          cfg.GetIssuer(),
+		resource:        cfg.GetResource(),
+		audiences:       audiences,
+		scopesSupported: cfg.GetScopesSupported(),
+		requiredScopes:  cfg.GetRequiredScopes(),
+		scopeProfiles:   cfg.GetScopeProfiles(),
+	}, nil
+}
+
+// Authenticate validates the bearer access token of the request.
+//
+// Summary: Validates an OAuth 2.1 access token.
+//
+// Parameters:
+//   - ctx: context.Context. The request context.
+//   - r: *http.Request. The request carrying the token in the Authorization header.
+//
+// Returns:
+//   - context.Context: The context with the token subject as user and, if a
+//     scope mapping matched, the mapped profile.
+//   - error: ErrInvalidToken or *InsufficientScopeError on failure.
+func (rs *ResourceServer) Authenticate(ctx context.Context, r *http.Request) (context.Context, error) {
+	token, ok := BearerToken(r)
+	if !ok {
+		return ctx, ErrInvalidToken
+	}
+
+	idToken, err := rs.verifier.Verify(ctx, token)
+	if err != nil {
+		return ctx, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	// The token must have been issued for this resource (RFC 8707), otherwise
+	// a token obtained for another server could be replayed here.
+	if !slices.ContainsFunc(idToken.Audience, func(aud string) bool { return slices.Contains(rs.audiences, aud) }) {
+		return ctx, fmt.Errorf("%w: audience mismatch", ErrInvalidToken)
+	}
+
+	var claims struct {
+		Scope json.RawMessage `json:"scope"`
+		Scp   json.RawMessage `json:"scp"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return ctx, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	scopes := parseScopes(claims.Scope)
+	if len(scopes) == 0 {
+		scopes = parseScopes(claims.Scp)
+	}
+
+	for _, required := range rs.requiredScopes {
+		if !slices.Contains(scopes, required) {
+			return ctx, &InsufficientScopeError{Scopes: rs.requiredScopes}
+		}
+	}
+
+	ctx = ContextWithUser(ctx, idToken.Subject)
+	for _, mapping := range rs.scopeProfiles {
+		if slices.Contains(scopes, mapping.GetScope()) {
+			ctx = ContextWithProfileID(ctx, mapping.GetProfile())
+			break
+		}
+	}
+	return ctx, nil
+}
+
+// ResourceURI returns the canonical URI of this server for the request.
+//
+// Parameters:
+//   - r: *http.Request. The request.
+//
+// Returns:
+//   - string: The configured resource, or one derived from the request's scheme and host.
+func (rs *ResourceServer) ResourceURI(r *http.Request) string {
+	if rs.resource != "" {
+		return rs.resource
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// MetadataURL returns the URL of the protected resource metadata document for
+// the request, as advertised in WWW-Authenticate challenges.
+//
+// Parameters:
+//   - r: *http.Request. The request.
+//
+// Returns:
+//   - string: The metadata URL.
+func (rs *ResourceServer) MetadataURL(r *http.Request) string {
+	resource := rs.ResourceURI(r)
+	u, err := url.Parse(resource)
+	if err != nil || u.Host == "" {
+		return resource + ProtectedResourceMetadataPath
+	}
+	return u.Scheme + "://" + u.Host + ProtectedResourceMetadataPath + strings.TrimSuffix(u.Path, "/")
+}
+
+// MetadataHandler serves the protected resource metadata document (RFC 9728),
+// which tells MCP clients which authorization server to obtain tokens from.
+//
+// Returns:
+//   - http.Handler: The handler. It must be mounted without authentication.
+func (rs *ResourceServer) MetadataHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		metadata := map[string]any{
+			"resource":                 rs.ResourceURI(r),
+			"authorization_servers":    []string{rs.issuer},
+			"bearer_methods_supported": []string{"header"},
+		}
+		if len(rs.scopesSupported) > 0 {
+			metadata["scopes_supported"] = rs.scopesSupported
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(metadata)
+	})
+}
+
+// WriteChallenge rejects the request with a WWW-Authenticate challenge that
+// points the client at the protected resource metadata.
+//
+// Parameters:
+//   - w: http.ResponseWriter. The response writer.
+//   - r: *http.Request. The rejected request.
+//   - err: error. The authentication error; nil if no token was presented.
+//
+// Side Effects:
+//   - Writes a 401 response, or 403 for insufficient scope.
+func (rs *ResourceServer) WriteChallenge(w http.ResponseWriter, r *http.Request, err error) {
+	challenge := fmt.Sprintf("Bearer resource_metadata=%q", rs.MetadataURL(r))
+	status := http.StatusUnauthorized
+	var scopeErr *InsufficientScopeError
+	switch {
+	case errors.As(err, &scopeErr):
+		status = http.StatusForbidden
+		challenge += fmt.Sprintf(", error=\"insufficient_scope\", scope=%q", strings.Join(scopeErr.Scopes, " "))
+	case err != nil:
+		challenge += `, error="invalid_token"`
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	http.Error(w, http.StatusText(status), status)
+}
+
+// BearerToken extracts the bearer token from the Authorization header.
+//
+// Parameters:
+//   - r: *http.Request. The request.
+//
+// Returns:
+//   - string: The token.
+//   - bool: True if a bearer token was present.
+func BearerToken(r *http.Request) (string, bool) {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "bearer") || token == "" {
+		return "", false
+	}
+	return token, true
+}
+
+// parseScopes accepts both the space-delimited "scope" string (RFC 9068) and
+// the array form some authorization servers use.
+func parseScopes(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return strings.Fields(s)
+	}
+	var list []string
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return list
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+const testResource = "https://mcp.example.com"
+
+func newTestResourceServer(t *testing.T, mock *MockOAuth2Server, mutate func(*configv1.OAuthResourceServerConfig)) *ResourceServer {
+	t.Helper()
+	cfg := configv1.OAuthResourceServerConfig_builder{
+		Issuer:          proto.String(mock.URL),
+		Resource:        proto.String(testResource),
+		ScopesSupported: []string{"mcp:read", "mcp:admin"},
+		ScopeProfiles: []*configv1.OAuthScopeProfile{
+			configv1.OAuthScopeProfile_builder{Scope: proto.String("mcp:admin"), Profile: proto.String("admin")}.Build(),
+			configv1.OAuthScopeProfile_builder{Scope: proto.String("mcp:read"), Profile: proto.String("readonly")}.Build(),
+		},
+	}.Build()
+	if mutate != nil {
+		mutate(cfg)
+	}
+	rs, err := NewResourceServer(context.Background(), cfg)
+	require.NoError(t, err)
+	return rs
+}
+
+func tokenClaims(mock *MockOAuth2Server, extra jwt.MapClaims) jwt.MapClaims {
+	claims := jwt.MapClaims{
+		"iss": mock.URL,
+		"sub": "user-123",
+		"aud": testResource,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range extra {
+		claims[k] = v
+	}
+	return claims
+}
+
+func bearerRequest(token string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "https://mcp.example.com/mcp", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func TestResourceServer_Authenticate(t *testing.T) {
+	mock := NewMockOAuth2Server(t)
+	defer mock.Close()
+	rs := newTestResourceServer(t, mock, nil)
+
+	t.Run("valid token maps scope to profile", func(t *testing.T) {
+		token := mock.NewIDToken(t, tokenClaims(mock, jwt.MapClaims{"scope": "openid mcp:read"}))
+		ctx, err := rs.Authenticate(context.Background(), bearerRequest(token))
+		require.NoError(t, err)
+
+		user, ok := UserFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, "user-123", user)
+		profile, ok := ProfileIDFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, "readonly", profile)
+	})
+
+	t.Run("first matching mapping wins and scp array is accepted", func(t *testing.T) {
+		token := mock.NewIDToken(t, tokenClaims(mock, jwt.MapClaims{"scp": []string{"mcp:read", "mcp:admin"}}))
+		ctx, err := rs.Authenticate(context.Background(), bearerRequest(token))
+		require.NoError(t, err)
+		profile, _ := ProfileIDFromContext(ctx)
+		assert.Equal(t, "admin", profile)
+	})
+
+	t.Run("audience mismatch", func(t *testing.T) {
+		token := mock.NewIDToken(t, tokenClaims(mock, jwt.MapClaims{"aud": "https://other.example.com"}))
+		_, err := rs.Authenticate(context.Background(), bearerRequest(token))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		token := mock.NewIDToken(t, tokenClaims(mock, jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}))
+		_, err := rs.Authenticate(context.Background(), bearerRequest(token))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("wrong issuer", func(t *testing.T) {
+		token := mock.NewIDToken(t, tokenClaims(mock, jwt.MapClaims{"iss": "https://evil.example.com"}))
+		_, err := rs.Authenticate(context.Background(), bearerRequest(token))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		_, err := rs.Authenticate(context.Background(), httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func TestResourceServer_RequiredScopes(t *testing.T) {
+	mock := NewMockOAuth2Server(t)
+	defer mock.Close()
+	rs := newTestResourceServer(t, mock, func(cfg *configv1.OAuthResourceServerConfig) {
+		cfg.SetRequiredScopes([]string{"mcp:read"})
+	})
+
+	token := mock.NewIDToken(t, tokenClaims(mock, jwt.MapClaims{"scope": "openid"}))
+	_, err := rs.Authenticate(context.Background(), bearerRequest(token))
+	var scopeErr *InsufficientScopeError
+	require.True(t, errors.As(err, &scopeErr))
+	assert.Equal(t, []string{"mcp:read"}, scopeErr.Scopes)
+
+	rec := httptest.NewRecorder()
+	rs.WriteChallenge(rec, bearerRequest(token), err)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t,
+		`Bearer resource_metadata="https://mcp.example.com/.well-known/oauth-protected-resource", error="insufficient_scope", scope="mcp:read"`,
+		rec.Header().Get("WWW-Authenticate"))
+}
+
+func TestResourceServer_AudiencesAndJWKSURL(t *testing.T) {
+	mock := NewMockOAuth2Server(t)
+	defer mock.Close()
+	rs := newTestResourceServer(t, mock, func(cfg *configv1.OAuthResourceServerConfig) {
+		cfg.ClearResource()
+		cfg.SetAudiences([]string{"http://localhost:50050"})
+		cfg.SetJwksUrl(mock.URL + "/jwks")
+	})
+
+	token := mock.NewIDToken(t, tokenClaims(mock, jwt.MapClaims{"aud": "http://localhost:50050"}))
+	req := httptest.NewRequest(http.MethodPost, "http://localhost:50050/mcp", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	_, err := rs.Authenticate(context.Background(), req)
+	require.NoError(t, err)
+}
+
+func TestResourceServer_ForeignAudienceWithSpoofedHost(t *testing.T) {
+	mock := NewMockOAuth2Server(t)
+	defer mock.Close()
+	rs := newTestResourceServer(t, mock, func(cfg *configv1.OAuthResourceServerConfig) {
+		cfg.ClearResource()
+		cfg.SetAudiences([]string{testResource})
+	})
+
+	// A token minted for another resource is rejected, even when the Host
+	// header names that resource.
+	token := mock.NewIDToken(t, tokenClaims(mock, jwt.MapClaims{"aud": "http://attacker.example.com"}))
+	req := httptest.NewRequest(http.MethodPost, "http://attacker.example.com/mcp", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	_, err := rs.Authenticate(context.Background(), req)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestResourceServer_Metadata(t *testing.T) {
+	mock := NewMockOAuth2Server(t)
+	defer mock.Close()
+	rs := newTestResourceServer(t, mock, nil)
+
+	rec := httptest.NewRecorder()
+	rs.MetadataHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ProtectedResourceMetadataPath, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var metadata map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &metadata))
+	assert.Equal(t, testResource, metadata["resource"])
+	assert.Equal(t, []any{mock.URL}, metadata["authorization_servers"])
+	assert.Equal(t, []any{"mcp:read", "mcp:admin"}, metadata["scopes_supported"])
+	assert.Equal(t, []any{"header"}, metadata["bearer_methods_supported"])
+}
+
+func TestResourceServer_WriteChallenge(t *testing.T) {
+	mock := NewMockOAuth2Server(t)
+	defer mock.Close()
+	rs := newTestResourceServer(t, mock, func(cfg *configv1.OAuthResourceServerConfig) {
+		cfg.SetResource("https://mcp.example.com/mcp")
+	})
+	req := httptest.NewRequest(http.MethodGet, "/mcp", nil)
+
+	rec := httptest.NewRecorder()
+	rs.WriteChallenge(rec, req, nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, `Bearer resource_metadata="https://mcp.example.com/.well-known/oauth-protected-resource/mcp"`, rec.Header().Get("WWW-Authenticate"))
+
+	rec = httptest.NewRecorder()
+	rs.WriteChallenge(rec, req, ErrInvalidToken)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Header().Get("WWW-Authenticate"), `error="invalid_token"`)
+}
+
+func TestNewResourceServer_RequiresIssuer(t *testing.T) {
+	_, err := NewResourceServer(context.Background(), configv1.OAuthResourceServerConfig_builder{}.Build())
+	assert.Error(t, err)
+}
+
+func TestNewResourceServer_RequiresResourceOrAudiences(t *testing.T) {
+	_, err := NewResourceServer(context.Background(), configv1.OAuthResourceServerConfig_builder{
+		Issuer:  proto.String("https://auth.example.com"),
+		JwksUrl: proto.String("https://auth.example.com/jwks"),
+	}.Build())
+	assert.EqualError(t, err, "oauth resource server requires a resource or audiences")
+}
+
+func TestManager_Authenticate_OAuth(t *testing.T) {
+	mock := NewMockOAuth2Server(t)
+	defer mock.Close()
+
+	am := NewManager()
+	am.SetAPIKey("global-api-key")
+	am.SetResourceServer(newTestResourceServer(t, mock, nil))
+
+	// A valid access token is accepted in place of the API key.
+	token := mock.NewIDToken(t, tokenClaims(mock, nil))
+	ctx, err := am.Authenticate(context.Background(), "", bearerRequest(token))
+	require.NoError(t, err)
+	user, _ := UserFromContext(ctx)
+	assert.Equal(t, "user-123", user)
+
+	// An invalid token is rejected.
+	_, err = am.Authenticate(context.Background(), "", bearerRequest("not-a-jwt"))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	// The API key keeps working.
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-API-Key", "global-api-key")
+	_, err = am.Authenticate(context.Background(), "", req)
+	assert.NoError(t, err)
+}
@@ -8,11 +8,15 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/mcpany/core/server/pkg/logging"
 	"github.com/mcpany/core/server/pkg/util"
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
 
 	configv1 "github.com/mcpany/core/proto/config/v1"
@@ -102,22 +106,30 @@ func NewUpstreamAuthenticator(authConfig *configv1.Authentication) (UpstreamAuth
 		}, nil
 	}
 
-	if oauth2 := authConfig.GetOauth2(); oauth2 != nil {
-		if oauth2.GetClientId() == nil {
+	if oauth2Auth := authConfig.GetOauth2(); oauth2Auth != nil {
+		if oauth2Auth.GetClientId() == nil {
 			return nil, errors.New("OAuth2 authentication requires a client ID")
 		}
-		if oauth2.GetClientSecret() == nil {
+		refreshGrant := oauth2Auth.GetGrantType() == configv1.OAuth2Auth_GRANT_TYPE_REFRESH_TOKEN
+		if refreshGrant && oauth2Auth.GetRefreshToken() == nil {
+			return nil, errors.New("OAuth2 refresh token grant requires a refresh token")
+		}
+		if !refreshGrant && oauth2Auth.GetClientSecret() == nil {
 			return nil, errors.New("OAuth2 authentication requires a client secret")
 		}
-		if oauth2.GetTokenUrl() == "" && oauth2.GetIssuerUrl() == "" {
+		if oauth2Auth.GetTokenUrl() == "" && oauth2Auth.GetIssuerUrl() == "" {
 			return nil, errors.New("OAuth2 authentication requires a token URL or an issuer URL")
 		}
 		return &OAuth2Auth{
-			ClientID:     oauth2.GetClientId(),
-			ClientSecret: oauth2.GetClientSecret(),
-			TokenURL:     oauth2.GetTokenUrl(),
-			IssuerURL:    oauth2.GetIssuerUrl(),
-			Scopes:       strings.Split(oauth2.GetScopes(), " "),
+			ClientID:            oauth2Auth.GetClientId(),
+			ClientSecret:        oauth2Auth.GetClientSecret(),
+			TokenURL:            oauth2Auth.GetTokenUrl(),
+			IssuerURL:           oauth2Auth.GetIssuerUrl(),
+			Scopes:              strings.Fields(oauth2Auth.GetScopes()),
+			GrantType:           oauth2Auth.GetGrantType(),
+			RefreshToken:        oauth2Auth.GetRefreshToken(),
+			TokenParams:         oauth2Auth.GetTokenParams(),
+			RefreshBeforeExpiry: oauth2Auth.GetRefreshBeforeExpiry().AsDuration(),
 		}, nil
 	}
 
@@ -217,15 +229,42 @@ func (b *BasicAuth) Authenticate(req *http.Request) error {
 	return nil
 }
 
-// OAuth2Auth implements UpstreamAuthenticator for OAuth2 client credentials flow.
+// defaultRefreshBeforeExpiry is how long before its expiry a cached OAuth2
+// token is refreshed by default.
+const defaultRefreshBeforeExpiry = time.Minute
+
+// refreshRetryInterval is how long a failed refresh is not retried while the
+// cached token is still valid.
+const refreshRetryInterval = 10 * time.Second
+
+// OAuth2Auth implements UpstreamAuthenticator for the OAuth2 client
+// credentials and refresh token grants. Tokens are cached and refreshed
+// before they expire.
 type OAuth2Auth struct {
 	ClientID     *configv1.SecretValue
 	ClientSecret *configv1.SecretValue
 	TokenURL     string
 	IssuerURL    string
 	Scopes       []string
+	GrantType    configv1.OAuth2Auth_GrantType
+	RefreshToken *configv1.SecretValue
+	// TokenParams are additional parameters of client credentials token
+	// requests.
+	TokenParams map[string]string
+	// RefreshBeforeExpiry is how long before its expiry a cached token is
+	// refreshed. Zero means one minute.
+	RefreshBeforeExpiry time.Duration
 
 	discoveryMu sync.Mutex
+
+	mu sync.Mutex
+	// credentialsKey identifies the resolved credentials the cached token
+	// was issued for, so that rotated secrets invalidate it.
+	credentialsKey string
+	token          *oauth2.Token
+	refreshAt      time.Time
+	// rotatedRefreshToken is the last refresh token returned by the server.
+	rotatedRefreshToken string
 }
 
 // getTokenURL returns the token URL, performing discovery if necessary.
@@ -250,7 +289,8 @@ func (o *OAuth2Auth) getTokenURL(ctx context.Context) (string, error) {
 	return "", errors.New("OAuth2 authentication requires a token URL (and no issuer provided)")
 }
 
-// Authenticate fetches a token and adds it to the request's "Authorization" header.
+// Authenticate adds a cached or newly fetched token to the request's
+// "Authorization" header.
 //
 // Parameters:
 //   - req: The HTTP request to be modified.
@@ -258,35 +298,140 @@ func (o *OAuth2Auth) getTokenURL(ctx context.Context) (string, error) {
 // Returns:
 //   - nil on success, or an error if the token cannot be obtained.
 func (o *OAuth2Auth) Authenticate(req *http.Request) error {
-	tokenURL, err := o.getTokenURL(req.Context())
+	token, err := o.Token(req.Context())
 	if err != nil {
 		return err
 	}
+	token.SetAuthHeader(req)
+	return nil
+}
 
-	if o.ClientID == nil {
-		return errors.New("oauth2 client id secret is not configured")
+// Token returns the cached access token, fetching a new one when there is
+// none, when it is about to expire or when the credentials have changed.
+// Concurrent callers share a single fetch. If a refresh fails while the
+// cached token is still valid, the cached token is returned and the refresh
+// is retried after a short backoff.
+//
+// Parameters:
+//   - ctx: The context for resolving secrets and requesting the token.
+//
+// Returns:
+//   - *oauth2.Token: The access token.
+//   - error: An error if the token cannot be obtained.
+func (o *OAuth2Auth) Token(ctx context.Context) (*oauth2.Token, error) {
+	tokenURL, err := o.getTokenURL(ctx)
+	if err != nil {
+		return nil, err
 	}
-	if o.ClientSecret == nil {
-		return errors.New("oauth2 client secret is not configured")
+
+	if o.ClientID == nil {
+		return nil, errors.New("oauth2 client id secret is not configured")
 	}
-	clientID, err := util.ResolveSecret(req.Context(), o.ClientID)
+	clientID, err := util.ResolveSecret(ctx, o.ClientID)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	clientSecret, err := util.ResolveSecret(req.Context(), o.ClientSecret)
-	if err != nil {
-		return err
+	var clientSecret, refreshToken string
+	if o.GrantType == configv1.OAuth2Auth_GRANT_TYPE_REFRESH_TOKEN {
+		if o.RefreshToken == nil {
+			return nil, errors.New("oauth2 refresh token secret is not configured")
+		}
+		if refreshToken, err = util.ResolveSecret(ctx, o.RefreshToken); err != nil {
+			return nil, err
+		}
+		// Public clients refresh tokens without a secret.
+		if o.ClientSecret != nil {
+			if clientSecret, err = util.ResolveSecret(ctx, o.ClientSecret); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		if o.ClientSecret == nil {
+			return nil, errors.New("oauth2 client secret is not configured")
+		}
+		if clientSecret, err = util.ResolveSecret(ctx, o.ClientSecret); err != nil {
+			return nil, err
+		}
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	key := strings.Join([]string{tokenURL, clientID, clientSecret, refreshToken}, "\x00")
+	if key != o.credentialsKey {
+		o.credentialsKey = key
+		o.token = nil
+		o.rotatedRefreshToken = ""
+	}
+	now := time.Now()
+	if o.token != nil && (o.refreshAt.IsZero() || now.Before(o.refreshAt)) {
+		return o.token, nil
 	}
-	cfg := &clientcredentials.Config{
-		ClientID:     clientID,
-		ClientSecret: clientSecret,
-		TokenURL:     tokenURL,
-		Scopes:       o.Scopes,
+
+	var token *oauth2.Token
+	if o.GrantType == configv1.OAuth2Auth_GRANT_TYPE_REFRESH_TOKEN {
+		if o.rotatedRefreshToken != "" {
+			refreshToken = o.rotatedRefreshToken
+		}
+		cfg := &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			Endpoint:     oauth2.Endpoint{TokenURL: tokenURL},
+			Scopes:       o.Scopes,
+		}
+		// The source holds an expired token, so it always refreshes.
+		token, err = cfg.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+		if err == nil && token.RefreshToken != "" {
+			o.rotatedRefreshToken = token.RefreshToken
+		}
+	} else {
+		cfg := &clientcredentials.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			TokenURL:     tokenURL,
+			Scopes:       o.Scopes,
+		}
+		if len(o.TokenParams) > 0 {
+			cfg.EndpointParams = url.Values{}
+			for k, v := range o.TokenParams {
+				cfg.EndpointParams.Set(k, v)
+			}
+		}
+		token, err = cfg.Token(ctx)
 	}
-	token, err := cfg.TokenSource(req.Context()).Token()
 	if err != nil {
-		return err
+		if o.token != nil && o.token.Valid() {
+			logging.GetLogger().WarnContext(ctx, "Failed to refresh OAuth2 token, using the cached token until it expires",
+				"token_url", tokenURL, "expiry", o.token.Expiry, "error", err)
+			// Back off, so that the calls do not all wait on a failing
+			// token endpoint.
+			o.refreshAt = now.Add(refreshRetryInterval)
+			if !o.token.Expiry.IsZero() && o.refreshAt.After(o.token.Expiry) {
+				o.refreshAt = o.token.Expiry
+			}
+			return o.token, nil
+		}
+		return nil, fmt.Errorf("failed to obtain OAuth2 token from %s: %w", tokenURL, err)
 	}
-	token.SetAuthHeader(req)
-	return nil
+
+	o.token = token
+	o.refreshAt = o.refreshTime(token, now)
+	return token, nil
+}
+
+// refreshTime returns when a token fetched at now should be refreshed: the
+// configured time before its expiry, but no earlier than half its lifetime.
+// It is zero for a token without an expiry, which is never refreshed.
+func (o *OAuth2Auth) refreshTime(token *oauth2.Token, now time.Time) time.Time {
+	if token.Expiry.IsZero() {
+		return time.Time{}
+	}
+	before := o.RefreshBeforeExpiry
+	if before <= 0 {
+		before = defaultRefreshBeforeExpiry
+	}
+	if half := token.Expiry.Sub(now) / 2; before > half {
+		before = half
+	}
+	return token.Expiry.Add(-before)
 }
@@ -0,0 +1,119 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package buildinfo
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"runtime"
+	"runtime/debug"
+)
+
+const (
+	// SBOMFile is the name of the embedded SBOM in the attestations directory.
+	SBOMFile = "sbom.json"
+	// ProvenanceFile is the name of the embedded SLSA provenance in the attestations directory.
+	ProvenanceFile = "provenance.json"
+)
+
+//go:embed attestations
+var attestations embed.FS
+
+// Module describes a Go module compiled into the binary.
+type Module struct {
+	Path    string  `json:"path"`
+	Version string  `json:"version"`
+	Sum     string  `json:"sum,omitempty"`
+	Replace *Module `json:"replace,omitempty"`
+}
+
+// Info describes the build of the running binary.
+type Info struct {
+	Version    string            `json:"version"`
+	GoVersion  string            `json:"go_version"`
+	OS         string            `json:"os"`
+	Arch       string            `json:"arch"`
+	Path       string            `json:"path,omitempty"`
+	Main       *Module           `json:"main,omitempty"`
+	VCS        string            `json:"vcs,omitempty"`
+	Revision   string            `json:"revision,omitempty"`
+	RevisionAt string            `json:"revision_time,omitempty"`
+	Modified   bool              `json:"modified,omitempty"`
+	Settings   map[string]string `json:"settings,omitempty"`
+	Modules    []Module          `json:"modules,omitempty"`
+	SBOM       json.RawMessage   `json:"sbom,omitempty"`
+	Provenance json.RawMessage   `json:"provenance,omitempty"`
+}
+
+// Get collects the build information of the running binary.
+//
+// Summary: Returns the version, Go modules and embedded attestations of the binary.
+//
+// Parameters:
+//   - version: string. The release version, usually injected at build time.
+//
+// Returns:
+//   - Info: The build information. Modules and VCS details are empty if the
+//     binary was built without module support.
+func Get(version string) Info {
+	info := Info{
+		Version:    version,
+		GoVersion:  runtime.Version(),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		SBOM:       readAttestation(attestations, SBOMFile),
+		Provenance: readAttestation(attestations, ProvenanceFile),
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.Path = bi.Path
+	if bi.Main.Path != "" {
+		mainModule := toModule(&bi.Main)
+		info.Main = &mainModule
+	}
+	for _, dep := range bi.Deps {
+		info.Modules = append(info.Modules, toModule(dep))
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs":
+			info.VCS = s.Value
+		case "vcs.revision":
+			info.Revision = s.Value
+		case "vcs.time":
+			info.RevisionAt = s.Value
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		default:
+			if info.Settings == nil {
+				info.Settings = make(map[string]string)
+			}
+			info.Settings[s.Key] = s.Value
+		}
+	}
+	return info
+}
+
+func toModule(m *debug.Module) Module {
+	mod := Module{Path: m.Path, Version: m.Version, Sum: m.Sum}
+	if m.Replace != nil {
+		replace := toModule(m.Replace)
+		mod.Replace = &replace
+	}
+	return mod
+}
+
+// readAttestation returns the named attestation if it is embedded and is
+// valid JSON, or nil otherwise.
+func readAttestation(fsys fs.FS, name string) json.RawMessage {
+	data, err := fs.ReadFile(fsys, "attestations/"+name)
+	if err != nil || !json.Valid(data) {
+		return nil
+	}
+	return data
+}
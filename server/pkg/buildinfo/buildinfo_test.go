@@ -0,0 +1,43 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package buildinfo
+
+import (
+	"runtime"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	info := Get("v1.2.3")
+
+	assert.Equal(t, "v1.2.3", info.Version)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+	assert.Equal(t, runtime.GOOS, info.OS)
+	assert.Equal(t, runtime.GOARCH, info.Arch)
+	require.NotEmpty(t, info.Modules)
+
+	var found bool
+	for _, m := range info.Modules {
+		if m.Path == "github.com/stretchr/testify" {
+			found = true
+			assert.NotEmpty(t, m.Version)
+		}
+	}
+	assert.True(t, found, "expected testify in the module list")
+}
+
+func TestReadAttestation(t *testing.T) {
+	fsys := fstest.MapFS{
+		"attestations/sbom.json":       {Data: []byte(`{"bomFormat":"CycloneDX"}`)},
+		"attestations/provenance.json": {Data: []byte(`not json`)},
+	}
+
+	assert.JSONEq(t, `{"bomFormat":"CycloneDX"}`, string(readAttestation(fsys, SBOMFile)))
+	assert.Nil(t, readAttestation(fsys, ProvenanceFile), "invalid JSON is not served")
+	assert.Nil(t, readAttestation(fsys, "missing.json"))
+}
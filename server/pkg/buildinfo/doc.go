@@ -0,0 +1,7 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package buildinfo reports what a running MCP Any binary was built from: its
+// version, VCS revision, Go module dependencies and, for release builds, the
+// embedded SBOM and SLSA provenance.
+package buildinfo
@@ -94,8 +94,27 @@ type ToolExecutionRequest struct {
 	Context    context.Context
 	ToolName   string
 	ToolInputs json.RawMessage
+	// PriorityClass is the worker queue the request waits in. Empty selects
+	// the first priority class.
+	PriorityClass string
+	// Tenant is who the request is made for, typically the user. The
+	// tenants of a priority class take turns.
+	Tenant string
+	// IdempotencyKey identifies the request across its deliveries. A worker
+	// runs the tool once for the deliveries with the same key, and answers
+	// the others with the first result. Empty runs each delivery.
+	IdempotencyKey string
 }
 
+const (
+	// PriorityInteractive is the default priority class of the tool calls
+	// a client waits for.
+	PriorityInteractive = "interactive"
+	// PriorityBatch is the default priority class of the calls of the
+	// asynchronous tools.
+	PriorityBatch = "batch"
+)
+
 // ToolExecutionResult is a message published in response to a
 // ToolExecutionRequest. It contains the result of the tool execution, in raw
 // JSON format, or an error if the execution failed.
@@ -0,0 +1,232 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package capture stores recorded tool calls so that they can be replayed
+// with `mcpctl replay`.
+//
+// Every capture is a JSON file named after its ID in the capture directory.
+// IDs are version 7 UUIDs, so the file names sort by the time of the call.
+package capture
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultDir is the directory captures are written to by default.
+	DefaultDir = "data/captures"
+	// DefaultMaxCaptures is the number of captures kept by default.
+	DefaultMaxCaptures = 1000
+
+	fileSuffix = ".json"
+)
+
+// ErrNotFound is returned when no capture has the requested ID.
+var ErrNotFound = errors.New("capture not found")
+
+// Capture is a recorded tool call.
+type Capture struct {
+	// ID identifies the capture.
+	ID string `json:"id"`
+	// Timestamp is when the call started.
+	Timestamp time.Time `json:"timestamp"`
+	// ToolName is the fully qualified name of the tool.
+	ToolName string `json:"tool_name"`
+	// ServiceID is the ID of the service providing the tool.
+	ServiceID string `json:"service_id,omitempty"`
+	// TraceID correlates the capture with the logs and traces of the call.
+	TraceID string `json:"trace_id,omitempty"`
+	// UserID is the user who made the call.
+	UserID string `json:"user_id,omitempty"`
+	// ProfileID is the profile the call was made with.
+	ProfileID string `json:"profile_id,omitempty"`
+	// Arguments are the redacted arguments of the call.
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+	// Result is the redacted result of the call.
+	Result json.RawMessage `json:"result,omitempty"`
+	// Error is the error of the call.
+	Error string `json:"error,omitempty"`
+	// DurationMs is the duration of the call in milliseconds.
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// Store keeps captures as files in a directory.
+//
+// Summary: A directory of captured tool calls.
+type Store struct {
+	dir         string
+	maxCaptures int
+	mu          sync.Mutex
+}
+
+// NewStore creates a store of the captures in dir, creating the directory if
+// needed.
+//
+// Summary: Opens a capture directory.
+//
+// Parameters:
+//   - dir: string. The capture directory.
+//   - maxCaptures: int. The number of captures kept by Save, or 0 for DefaultMaxCaptures.
+//
+// Returns:
+//   - *Store: The store.
+//   - error: An error if the directory cannot be created.
+func NewStore(dir string, maxCaptures int) (*Store, error) {
+	if dir == "" {
+		dir = DefaultDir
+	}
+	if maxCaptures <= 0 {
+		maxCaptures = DefaultMaxCaptures
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create capture directory: %w", err)
+	}
+	return &Store{dir: dir, maxCaptures: maxCaptures}, nil
+}
+
+// NewID returns a new capture ID.
+//
+// Summary: Generates a time-ordered capture ID.
+//
+// Returns:
+//   - string: The ID.
+func NewID() string {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.NewString()
+	}
+	return id.String()
+}
+
+// Save writes a capture and deletes the oldest captures beyond the limit.
+//
+// Summary: Persists a capture.
+//
+// Parameters:
+//   - c: *Capture. The capture; an empty ID is replaced by a new one.
+//
+// Returns:
+//   - error: An error if the capture cannot be written.
+//
+// Side Effects:
+//   - Writes a file to the capture directory and may delete older ones.
+func (s *Store) Save(c *Capture) error {
+	if c.ID == "" {
+		c.ID = NewID()
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal capture: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path := filepath.Join(s.dir, c.ID+fileSuffix)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write capture: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write capture: %w", err)
+	}
+	return s.prune()
+}
+
+// prune deletes the oldest captures beyond maxCaptures.
+func (s *Store) prune() error {
+	names, err := s.names()
+	if err != nil {
+		return err
+	}
+	for len(names) > s.maxCaptures {
+		if err := os.Remove(filepath.Join(s.dir, names[0])); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete capture: %w", err)
+		}
+		names = names[1:]
+	}
+	return nil
+}
+
+// names returns the file names of the captures, oldest first.
+func (s *Store) names() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read capture directory: %w", err)
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), fileSuffix) {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Load reads the capture with an ID.
+//
+// Summary: Retrieves a capture.
+//
+// Parameters:
+//   - id: string. The capture ID.
+//
+// Returns:
+//   - *Capture: The capture.
+//   - error: ErrNotFound if there is no such capture, or an error if it cannot be read.
+func (s *Store) Load(id string) (*Capture, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("invalid capture id %q", id)
+	}
+	data, err := os.ReadFile(filepath.Join(s.dir, id+fileSuffix))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read capture: %w", err)
+	}
+	var c Capture
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse capture %s: %w", id, err)
+	}
+	return &c, nil
+}
+
+// List returns the newest captures, newest first.
+//
+// Summary: Lists recent captures.
+//
+// Parameters:
+//   - limit: int. The maximum number of captures, or 0 for all.
+//
+// Returns:
+//   - []*Capture: The captures.
+//   - error: An error if the directory cannot be read. Unreadable captures are skipped.
+func (s *Store) List(limit int) ([]*Capture, error) {
+	names, err := s.names()
+	if err != nil {
+		return nil, err
+	}
+	var captures []*Capture
+	for i := len(names) - 1; i >= 0; i-- {
+		if limit > 0 && len(captures) >= limit {
+			break
+		}
+		c, err := s.Load(strings.TrimSuffix(names[i], fileSuffix))
+		if err != nil {
+			continue
+		}
+		captures = append(captures, c)
+	}
+	return captures, nil
+}
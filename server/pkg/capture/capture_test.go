@@ -0,0 +1,72 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package capture
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	store, err := NewStore(t.TempDir(), 2)
+	require.NoError(t, err)
+
+	var ids []string
+	for _, tool := range []string{"a", "b", "c"} {
+		c := &Capture{
+			Timestamp: time.Now(),
+			ToolName:  tool,
+			Arguments: json.RawMessage(`{"city":"Paris"}`),
+			Result:    json.RawMessage(`{"temp":21}`),
+		}
+		require.NoError(t, store.Save(c))
+		require.NotEmpty(t, c.ID)
+		ids = append(ids, c.ID)
+	}
+
+	// Only the newest two captures are kept.
+	_, err = store.Load(ids[0])
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	c, err := store.Load(ids[2])
+	require.NoError(t, err)
+	assert.Equal(t, "c", c.ToolName)
+	assert.JSONEq(t, `{"city":"Paris"}`, string(c.Arguments))
+
+	captures, err := store.List(0)
+	require.NoError(t, err)
+	require.Len(t, captures, 2)
+	assert.Equal(t, "c", captures[0].ToolName)
+	assert.Equal(t, "b", captures[1].ToolName)
+
+	captures, err = store.List(1)
+	require.NoError(t, err)
+	assert.Len(t, captures, 1)
+}
+
+func TestStore_LoadInvalidID(t *testing.T) {
+	store, err := NewStore(t.TempDir(), 0)
+	require.NoError(t, err)
+
+	_, err = store.Load("../../etc/passwd")
+	assert.ErrorContains(t, err, "invalid capture id")
+}
+
+func TestStore_SkipsOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir, 0)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(dir+"/notes.txt", []byte("hello"), 0o600))
+	require.NoError(t, store.Save(&Capture{ToolName: "a"}))
+
+	captures, err := store.List(0)
+	require.NoError(t, err)
+	assert.Len(t, captures, 1)
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudsecrets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+)
+
+// awsFetcher reads secrets from AWS Secrets Manager. The path is the name or
+// ARN of the secret.
+type awsFetcher struct {
+	client *secretsmanager.Client
+}
+
+func newAWSFetcher(ctx context.Context, cfg *configv1.AwsSecretsManagerBackend) (*awsFetcher, error) {
+	var loadOptions []func(*config.LoadOptions) error
+	if cfg.GetRegion() != "" {
+		loadOptions = append(loadOptions, config.WithRegion(cfg.GetRegion()))
+	}
+	if cfg.GetProfile() != "" {
+		loadOptions = append(loadOptions, config.WithSharedConfigProfile(cfg.GetProfile()))
+	}
+	awsCfg, err := config.LoadDefaultConfig(ctx, loadOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load aws config: %w", err)
+	}
+	if cfg.GetRoleArn() != "" {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(awsCfg), cfg.GetRoleArn(), func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = "mcpany-secrets"
+		})
+		awsCfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+	client := secretsmanager.NewFromConfig(awsCfg, func(o *secretsmanager.Options) {
+		if cfg.GetEndpoint() != "" {
+			o.BaseEndpoint = aws.String(cfg.GetEndpoint())
+		}
+	})
+	return &awsFetcher{client: client}, nil
+}
+
+func (f *awsFetcher) fetch(ctx context.Context, path string) (string, error) {
+	out, err := f.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(path)})
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret %s from aws secrets manager: %w", path, err)
+	}
+	if out.SecretString != nil {
+		return *out.SecretString, nil
+	}
+	if out.SecretBinary != nil {
+		return string(out.SecretBinary), nil
+	}
+	return "", fmt.Errorf("aws secret %s has no value", path)
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudsecrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAWSFetcher(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		assert.Contains(t, r.Header.Get("Authorization"), "Credential=test/")
+		var in struct{ SecretId string }
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		if in.SecretId != "prod/app/db" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"Secrets Manager can't find the specified secret."}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"Name": in.SecretId, "SecretString": `{"password":"s3cret"}`})
+	}))
+	defer server.Close()
+
+	f, err := newAWSFetcher(context.Background(), configv1.AwsSecretsManagerBackend_builder{
+		Region:   proto.String("eu-west-1"),
+		Endpoint: proto.String(server.URL),
+	}.Build())
+	require.NoError(t, err)
+	b := newBackend(SchemeAWS, f, 0)
+
+	value, err := b.Resolve(context.Background(), "prod/app/db", "password")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", value)
+
+	_, err = b.Resolve(context.Background(), "prod/app/missing", "")
+	assert.ErrorContains(t, err, "ResourceNotFoundException")
+}
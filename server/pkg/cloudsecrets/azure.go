@@ -0,0 +1,216 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudsecrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/util"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	defaultAzureDNSSuffix     = "vault.azure.net"
+	defaultAzureAuthorityHost = "https://login.microsoftonline.com"
+	azureKeyVaultAPIVersion   = "7.4"
+	azureIMDSEndpoint         = "http://169.254.169.254/metadata/identity/oauth2/token"
+	azureAssertionType        = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+)
+
+// azureFetcher reads secrets from Azure Key Vault through its REST API. The
+// path is "<vault>/<secret>", optionally followed by "/<version>".
+type azureFetcher struct {
+	client  *http.Client
+	tokens  oauth2.TokenSource
+	baseURL func(vault string) string
+}
+
+func newAzureFetcher(ctx context.Context, cfg *configv1.AzureKeyVaultBackend) (*azureFetcher, error) {
+	suffix := cfg.GetDnsSuffix()
+	if suffix == "" {
+		suffix = defaultAzureDNSSuffix
+	}
+	tokens, err := azureTokenSource(ctx, cfg, "https://"+suffix)
+	if err != nil {
+		return nil, err
+	}
+	return &azureFetcher{
+		client:  &http.Client{Timeout: fetchTimeout},
+		tokens:  tokens,
+		baseURL: func(vault string) string { return "https://" + vault + "." + suffix },
+	}, nil
+}
+
+// azureTokenSource returns the tokens of the client secret, the workload
+// identity or the managed identity, in that order of preference.
+func azureTokenSource(ctx context.Context, cfg *configv1.AzureKeyVaultBackend, resource string) (oauth2.TokenSource, error) {
+	authority := cfg.GetAuthorityHost()
+	if authority == "" {
+		authority = defaultAzureAuthorityHost
+	}
+	tenantID, clientID := cfg.GetTenantId(), cfg.GetClientId()
+	tokenURL := func(tenant string) string {
+		return strings.TrimRight(authority, "/") + "/" + url.PathEscape(tenant) + "/oauth2/v2.0/token"
+	}
+	scopes := []string{resource + "/.default"}
+
+	if cfg.HasClientSecret() {
+		secret, err := util.ResolveSecret(ctx, cfg.GetClientSecret())
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve azure client secret: %w", err)
+		}
+		cc := &clientcredentials.Config{
+			ClientID:     clientID,
+			ClientSecret: secret,
+			TokenURL:     tokenURL(tenantID),
+			Scopes:       scopes,
+			AuthStyle:    oauth2.AuthStyleInParams,
+		}
+		return cc.TokenSource(context.Background()), nil
+	}
+
+	if tokenFile := os.Getenv("AZURE_FEDERATED_TOKEN_FILE"); tokenFile != "" {
+		if tenantID == "" {
+			tenantID = os.Getenv("AZURE_TENANT_ID")
+		}
+		if clientID == "" {
+			clientID = os.Getenv("AZURE_CLIENT_ID")
+		}
+		if tenantID == "" || clientID == "" {
+			return nil, fmt.Errorf("azure workload identity requires tenant_id and client_id, or AZURE_TENANT_ID and AZURE_CLIENT_ID")
+		}
+		return oauth2.ReuseTokenSource(nil, tokenSourceFunc(func() (*oauth2.Token, error) {
+			// The projected token is rotated on disk, so it is read for every exchange.
+			assertion, err := os.ReadFile(tokenFile) //nolint:gosec // The path comes from the pod environment.
+			if err != nil {
+				return nil, fmt.Errorf("failed to read azure federated token: %w", err)
+			}
+			cc := &clientcredentials.Config{
+				ClientID: clientID,
+				TokenURL: tokenURL(tenantID),
+				Scopes:   scopes,
+				EndpointParams: url.Values{
+					"client_assertion_type": {azureAssertionType},
+					"client_assertion":      {strings.TrimSpace(string(assertion))},
+				},
+				AuthStyle: oauth2.AuthStyleInParams,
+			}
+			return cc.Token(context.Background())
+		})), nil
+	}
+
+	return oauth2.ReuseTokenSource(nil, &managedIdentityTokenSource{
+		client:   &http.Client{Timeout: fetchTimeout},
+		endpoint: azureIMDSEndpoint,
+		resource: resource,
+		clientID: clientID,
+	}), nil
+}
+
+// tokenSourceFunc adapts a function to oauth2.TokenSource.
+type tokenSourceFunc func() (*oauth2.Token, error)
+
+func (f tokenSourceFunc) Token() (*oauth2.Token, error) {
+	return f()
+}
+
+// managedIdentityTokenSource gets tokens from the Azure instance metadata
+// service.
+type managedIdentityTokenSource struct {
+	client   *http.Client
+	endpoint string
+	resource string
+	clientID string
+}
+
+func (s *managedIdentityTokenSource) Token() (*oauth2.Token, error) {
+	query := url.Values{"api-version": {"2018-02-01"}, "resource": {s.resource}}
+	if s.clientID != "" {
+		query.Set("client_id", s.clientID)
+	}
+	req, err := http.NewRequest(http.MethodGet, s.endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata", "true")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get azure managed identity token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("failed to get azure managed identity token: %s %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	var out struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   string `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode azure managed identity token: %w", err)
+	}
+	token := &oauth2.Token{AccessToken: out.AccessToken, TokenType: "Bearer"}
+	if seconds, err := strconv.Atoi(out.ExpiresIn); err == nil {
+		token.Expiry = time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+	return token, nil
+}
+
+func (f *azureFetcher) fetch(ctx context.Context, path string) (string, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return "", fmt.Errorf("azure secret %q must be <vault>/<secret>[/<version>]", path)
+	}
+	secretURL := f.baseURL(parts[0]) + "/secrets/" + url.PathEscape(parts[1])
+	if len(parts) == 3 {
+		secretURL += "/" + url.PathEscape(parts[2])
+	}
+	secretURL += "?api-version=" + azureKeyVaultAPIVersion
+
+	token, err := f.tokens.Token()
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, secretURL, nil)
+	if err != nil {
+		return "", err
+	}
+	token.SetAuthHeader(req)
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get azure secret %s: %w", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read azure secret %s: %w", path, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.Unmarshal(body, &apiErr)
+		return "", fmt.Errorf("failed to get azure secret %s: %s %s", path, resp.Status, apiErr.Error.Message)
+	}
+	var out struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf("failed to decode azure secret %s: %w", path, err)
+	}
+	return out.Value, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudsecrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAzureFetcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer kv-token", r.Header.Get("Authorization"))
+		assert.Equal(t, azureKeyVaultAPIVersion, r.URL.Query().Get("api-version"))
+		switch r.URL.Path {
+		case "/my-vault/secrets/db-password":
+			_, _ = w.Write([]byte(`{"value":"latest"}`))
+		case "/my-vault/secrets/db-password/abc123":
+			_, _ = w.Write([]byte(`{"value":"pinned"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":"SecretNotFound","message":"A secret with (name/id) missing was not found in this key vault."}}`))
+		}
+	}))
+	defer server.Close()
+
+	f := &azureFetcher{
+		client:  server.Client(),
+		tokens:  oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "kv-token"}),
+		baseURL: func(vault string) string { return server.URL + "/" + vault },
+	}
+	b := newBackend(SchemeAzure, f, 0)
+	ctx := context.Background()
+
+	value, err := b.Resolve(ctx, "my-vault/db-password", "")
+	require.NoError(t, err)
+	assert.Equal(t, "latest", value)
+
+	value, err = b.Resolve(ctx, "my-vault/db-password/abc123", "")
+	require.NoError(t, err)
+	assert.Equal(t, "pinned", value)
+
+	_, err = b.Resolve(ctx, "my-vault/missing", "")
+	assert.ErrorContains(t, err, "was not found")
+
+	_, err = b.Resolve(ctx, "db-password", "")
+	assert.ErrorContains(t, err, "must be <vault>/<secret>[/<version>]")
+}
+
+func TestAzureTokenSource_ClientSecret(t *testing.T) {
+	authority := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tenant/oauth2/v2.0/token", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "app", r.PostForm.Get("client_id"))
+		assert.Equal(t, "secret", r.PostForm.Get("client_secret"))
+		assert.Equal(t, "https://vault.azure.net/.default", r.PostForm.Get("scope"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"app-token","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer authority.Close()
+
+	tokens, err := azureTokenSource(context.Background(), configv1.AzureKeyVaultBackend_builder{
+		TenantId:      proto.String("tenant"),
+		ClientId:      proto.String("app"),
+		ClientSecret:  configv1.SecretValue_builder{PlainText: proto.String("secret")}.Build(),
+		AuthorityHost: proto.String(authority.URL),
+	}.Build(), "https://vault.azure.net")
+	require.NoError(t, err)
+	token, err := tokens.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "app-token", token.AccessToken)
+}
+
+func TestAzureTokenSource_WorkloadIdentity(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("federated-jwt\n"), 0o600))
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", tokenFile)
+	t.Setenv("AZURE_TENANT_ID", "tenant")
+	t.Setenv("AZURE_CLIENT_ID", "workload")
+
+	authority := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "workload", r.PostForm.Get("client_id"))
+		assert.Equal(t, azureAssertionType, r.PostForm.Get("client_assertion_type"))
+		assert.Equal(t, "federated-jwt", r.PostForm.Get("client_assertion"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"workload-token","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer authority.Close()
+
+	tokens, err := azureTokenSource(context.Background(), configv1.AzureKeyVaultBackend_builder{
+		AuthorityHost: proto.String(authority.URL),
+	}.Build(), "https://vault.azure.net")
+	require.NoError(t, err)
+	token, err := tokens.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "workload-token", token.AccessToken)
+}
+
+func TestManagedIdentityTokenSource(t *testing.T) {
+	imds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.Header.Get("Metadata"))
+		assert.Equal(t, "https://vault.azure.net", r.URL.Query().Get("resource"))
+		assert.Equal(t, "identity", r.URL.Query().Get("client_id"))
+		_, _ = w.Write([]byte(`{"access_token":"mi-token","expires_in":"3599","token_type":"Bearer"}`))
+	}))
+	defer imds.Close()
+
+	source := &managedIdentityTokenSource{client: imds.Client(), endpoint: imds.URL, resource: "https://vault.azure.net", clientID: "identity"}
+	token, err := source.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "mi-token", token.AccessToken)
+	assert.True(t, token.Valid())
+}
@@ -0,0 +1,316 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cloudsecrets resolves "aws-sm:", "gcp-sm:" and "azure-kv:" secret
+// references against the AWS, Google Cloud and Azure secret managers. Each
+// backend authenticates with the platform identity of the host, caches the
+// read secrets and fetches them again periodically.
+package cloudsecrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+)
+
+// The schemes of the secret references resolved by the backends.
+const (
+	SchemeAWS   = "aws-sm"
+	SchemeGCP   = "gcp-sm"
+	SchemeAzure = "azure-kv"
+)
+
+const (
+	defaultRefreshInterval = 5 * time.Minute
+	// fetchTimeout bounds one read from a secret manager.
+	fetchTimeout = 30 * time.Second
+)
+
+// fetcher reads the raw value of a secret from a secret manager.
+type fetcher interface {
+	fetch(ctx context.Context, path string) (string, error)
+}
+
+// Backend resolves the secret references of one secret manager.
+type Backend struct {
+	scheme          string
+	fetcher         fetcher
+	refreshInterval time.Duration
+	now             func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]*cachedSecret
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// cachedSecret is a read secret. It is served until the refresh interval
+// passes, and afterwards too if fetching it again fails.
+type cachedSecret struct {
+	value     string
+	fetchedAt time.Time
+}
+
+// Option configures a Backend.
+type Option func(*Backend)
+
+// WithClock sets the clock used to age cached secrets.
+//
+// Parameters:
+//   - now: func() time.Time. The clock; time.Now by default.
+//
+// Returns:
+//   - Option: The option.
+func WithClock(now func() time.Time) Option {
+	return func(b *Backend) { b.now = now }
+}
+
+// New creates a Backend for each secret manager in the configuration. It
+// loads the credentials but does not read any secret.
+//
+// Summary: Initializes the cloud secret manager backends.
+//
+// Parameters:
+//   - ctx: context.Context. The context of loading the credentials.
+//   - cfg: *configv1.SecretBackendsConfig. The enabled secret managers.
+//   - opts: ...Option. The options of every backend.
+//
+// Returns:
+//   - []*Backend: The backends.
+//   - error: An error if the credentials of a backend cannot be loaded.
+func New(ctx context.Context, cfg *configv1.SecretBackendsConfig, opts ...Option) ([]*Backend, error) {
+	refreshInterval := defaultRefreshInterval
+	if cfg.HasRefreshInterval() {
+		refreshInterval = cfg.GetRefreshInterval().AsDuration()
+	}
+	var backends []*Backend
+	add := func(scheme string, f fetcher) {
+		backends = append(backends, newBackend(scheme, f, refreshInterval, opts...))
+	}
+	if cfg.HasAwsSecretsManager() {
+		f, err := newAWSFetcher(ctx, cfg.GetAwsSecretsManager())
+		if err != nil {
+			return nil, err
+		}
+		add(SchemeAWS, f)
+	}
+	if cfg.HasGcpSecretManager() {
+		f, err := newGCPFetcher(ctx, cfg.GetGcpSecretManager())
+		if err != nil {
+			return nil, err
+		}
+		add(SchemeGCP, f)
+	}
+	if cfg.HasAzureKeyVault() {
+		f, err := newAzureFetcher(ctx, cfg.GetAzureKeyVault())
+		if err != nil {
+			return nil, err
+		}
+		add(SchemeAzure, f)
+	}
+	return backends, nil
+}
+
+func newBackend(scheme string, f fetcher, refreshInterval time.Duration, opts ...Option) *Backend {
+	b := &Backend{
+		scheme:          scheme,
+		fetcher:         f,
+		refreshInterval: refreshInterval,
+		now:             time.Now,
+		cache:           make(map[string]*cachedSecret),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Scheme returns the scheme of the references the backend resolves.
+//
+// Returns:
+//   - string: The scheme, e.g. "aws-sm".
+func (b *Backend) Scheme() string {
+	return b.scheme
+}
+
+// Resolve returns the secret at the path, or the value of the key when the
+// secret is a JSON object.
+//
+// Summary: Reads a secret from the secret manager.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the read.
+//   - path: string. The secret, e.g. "prod/my-app/db".
+//   - key: string. The key within the JSON secret, or empty for the whole secret.
+//
+// Returns:
+//   - string: The value.
+//   - error: An error if the secret or key does not exist or cannot be read.
+//
+// Side Effects:
+//   - Caches the secret.
+func (b *Backend) Resolve(ctx context.Context, path, key string) (string, error) {
+	value, err := b.get(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	if key == "" {
+		return value, nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return "", fmt.Errorf("secret %s is not a JSON object, so it has no key %q", path, key)
+	}
+	field, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found in secret %s", key, path)
+	}
+	if s, ok := field.(string); ok {
+		return s, nil
+	}
+	encoded, err := json.Marshal(field)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode key %q of secret %s: %w", key, path, err)
+	}
+	return string(encoded), nil
+}
+
+func (b *Backend) get(ctx context.Context, path string) (string, error) {
+	b.mu.Lock()
+	cached, ok := b.cache[path]
+	b.mu.Unlock()
+	if ok && b.now().Sub(cached.fetchedAt) < b.refreshInterval {
+		return cached.value, nil
+	}
+
+	value, err := b.fetch(ctx, path)
+	if err != nil {
+		if ok {
+			logging.GetLogger().Warn("Failed to refresh secret; using the cached value", "scheme", b.scheme, "path", path, "error", err)
+			return cached.value, nil
+		}
+		return "", err
+	}
+	if b.refreshInterval > 0 {
+		b.mu.Lock()
+		b.cache[path] = &cachedSecret{value: value, fetchedAt: b.now()}
+		b.mu.Unlock()
+	}
+	return value, nil
+}
+
+func (b *Backend) fetch(ctx context.Context, path string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+	value, err := b.fetcher.fetch(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// Refresh fetches every cached secret again. A secret that cannot be
+// fetched keeps its cached value.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the reads.
+//
+// Returns:
+//   - error: An error naming how many secrets could not be fetched.
+//
+// Side Effects:
+//   - Updates the cache.
+func (b *Backend) Refresh(ctx context.Context) error {
+	b.mu.Lock()
+	paths := make([]string, 0, len(b.cache))
+	for path := range b.cache {
+		paths = append(paths, path)
+	}
+	b.mu.Unlock()
+
+	failed := 0
+	for _, path := range paths {
+		value, err := b.fetch(ctx, path)
+		if err != nil {
+			failed++
+			logging.GetLogger().Warn("Failed to refresh secret", "scheme", b.scheme, "path", path, "error", err)
+			continue
+		}
+		b.mu.Lock()
+		b.cache[path] = &cachedSecret{value: value, fetchedAt: b.now()}
+		b.mu.Unlock()
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to refresh %d of %d %s secrets", failed, len(paths), b.scheme)
+	}
+	return nil
+}
+
+// Start refreshes the cached secrets every refresh interval until Stop is
+// called. Nothing is started when the interval is zero, as nothing is cached.
+//
+// Parameters:
+//   - ctx: context.Context. Refreshing also stops when it is cancelled.
+//
+// Returns:
+//   - error: Always nil.
+//
+// Side Effects:
+//   - Starts a goroutine.
+func (b *Backend) Start(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cancel != nil || b.refreshInterval <= 0 {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	b.cancel = cancel
+	b.done = make(chan struct{})
+	done := b.done
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(b.refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = b.Refresh(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the refresh and clears the cache.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the wait.
+//
+// Returns:
+//   - error: An error if the context expires first.
+func (b *Backend) Stop(ctx context.Context) error {
+	b.mu.Lock()
+	cancel, done := b.cancel, b.done
+	b.cancel, b.done = nil, nil
+	b.cache = make(map[string]*cachedSecret)
+	b.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudsecrets
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type fakeFetcher struct {
+	mu     sync.Mutex
+	values map[string]string
+	err    error
+	reads  int
+}
+
+func (f *fakeFetcher) fetch(_ context.Context, path string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.reads++
+	if f.err != nil {
+		return "", f.err
+	}
+	value, ok := f.values[path]
+	if !ok {
+		return "", errors.New("secret not found")
+	}
+	return value, nil
+}
+
+func (f *fakeFetcher) set(path, value string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.values[path] = value
+	f.err = err
+}
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func TestBackend_ResolveKeys(t *testing.T) {
+	f := &fakeFetcher{values: map[string]string{
+		"prod/db":  `{"username":"app","password":"s3cret","port":5432}`,
+		"prod/api": "plain-token\n",
+	}}
+	b := newBackend(SchemeAWS, f, time.Minute)
+
+	value, err := b.Resolve(context.Background(), "prod/db", "password")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", value)
+
+	value, err = b.Resolve(context.Background(), "prod/db", "port")
+	require.NoError(t, err)
+	assert.Equal(t, "5432", value)
+
+	value, err = b.Resolve(context.Background(), "prod/api", "")
+	require.NoError(t, err)
+	assert.Equal(t, "plain-token", value)
+
+	_, err = b.Resolve(context.Background(), "prod/db", "host")
+	assert.ErrorContains(t, err, `key "host" not found`)
+	_, err = b.Resolve(context.Background(), "prod/api", "token")
+	assert.ErrorContains(t, err, "not a JSON object")
+	_, err = b.Resolve(context.Background(), "prod/missing", "")
+	assert.ErrorContains(t, err, "secret not found")
+
+	assert.Equal(t, 3, f.reads, "cached secrets are not read again")
+}
+
+func TestBackend_RefreshInterval(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	f := &fakeFetcher{values: map[string]string{"prod/api": "v1"}}
+	b := newBackend(SchemeGCP, f, time.Minute, WithClock(clock.Now))
+	ctx := context.Background()
+
+	value, err := b.Resolve(ctx, "prod/api", "")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", value)
+
+	f.set("prod/api", "v2", nil)
+	clock.now = clock.now.Add(30 * time.Second)
+	value, _ = b.Resolve(ctx, "prod/api", "")
+	assert.Equal(t, "v1", value)
+
+	clock.now = clock.now.Add(time.Minute)
+	value, _ = b.Resolve(ctx, "prod/api", "")
+	assert.Equal(t, "v2", value)
+
+	// The cached value is served while the secret manager cannot be reached.
+	f.set("prod/api", "v3", errors.New("unavailable"))
+	clock.now = clock.now.Add(2 * time.Minute)
+	value, err = b.Resolve(ctx, "prod/api", "")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", value)
+	assert.ErrorContains(t, b.Refresh(ctx), "failed to refresh 1 of 1")
+
+	f.set("prod/api", "v3", nil)
+	require.NoError(t, b.Refresh(ctx))
+	value, _ = b.Resolve(ctx, "prod/api", "")
+	assert.Equal(t, "v3", value)
+
+	require.NoError(t, b.Stop(ctx))
+	f.set("prod/api", "v4", nil)
+	value, _ = b.Resolve(ctx, "prod/api", "")
+	assert.Equal(t, "v4", value, "Stop clears the cache")
+}
+
+func TestBackend_NoCache(t *testing.T) {
+	f := &fakeFetcher{values: map[string]string{"vault/api": "v1"}}
+	b := newBackend(SchemeAzure, f, 0)
+	for i := 0; i < 3; i++ {
+		_, err := b.Resolve(context.Background(), "vault/api", "")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, f.reads)
+
+	require.NoError(t, b.Start(context.Background()))
+	assert.Nil(t, b.cancel, "nothing is refreshed without a cache")
+}
+
+func TestBackend_StartStop(t *testing.T) {
+	f := &fakeFetcher{values: map[string]string{"prod/api": "v1"}}
+	b := newBackend(SchemeAWS, f, 10*time.Millisecond)
+	_, err := b.Resolve(context.Background(), "prod/api", "")
+	require.NoError(t, err)
+
+	require.NoError(t, b.Start(context.Background()))
+	assert.Eventually(t, func() bool {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		return f.reads > 2
+	}, time.Second, 5*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, b.Stop(ctx))
+}
+
+func TestNew_EnabledBackends(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_REGION", "us-east-1")
+	cfg := configv1.SecretBackendsConfig_builder{
+		AwsSecretsManager: configv1.AwsSecretsManagerBackend_builder{}.Build(),
+		AzureKeyVault: configv1.AzureKeyVaultBackend_builder{
+			TenantId:     proto.String("tenant"),
+			ClientId:     proto.String("app"),
+			ClientSecret: configv1.SecretValue_builder{PlainText: proto.String("secret")}.Build(),
+		}.Build(),
+		RefreshInterval: durationpb.New(time.Hour),
+	}.Build()
+
+	backends, err := New(context.Background(), cfg)
+	require.NoError(t, err)
+	require.Len(t, backends, 2)
+	assert.Equal(t, SchemeAWS, backends[0].Scheme())
+	assert.Equal(t, SchemeAzure, backends[1].Scheme())
+	assert.Equal(t, time.Hour, backends[0].refreshInterval)
+
+	backends, err = New(context.Background(), configv1.SecretBackendsConfig_builder{}.Build())
+	require.NoError(t, err)
+	assert.Empty(t, backends)
+}
+
+func TestBackend_SecretRef(t *testing.T) {
+	f := &fakeFetcher{values: map[string]string{"projects/p/secrets/api": `{"token":"abc"}`}}
+	b := newBackend(SchemeGCP, f, time.Minute)
+	util.SetSecretRefResolver(b.Scheme(), b.Resolve)
+	t.Cleanup(func() { util.SetSecretRefResolver(b.Scheme(), nil) })
+
+	value, err := util.ResolveSecret(context.Background(), configv1.SecretValue_builder{
+		SecretRef: proto.String("gcp-sm:projects/p/secrets/api#token"),
+	}.Build())
+	require.NoError(t, err)
+	assert.Equal(t, "abc", value)
+}
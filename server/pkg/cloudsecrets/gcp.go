@@ -0,0 +1,130 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudsecrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	defaultGCPEndpoint = "https://secretmanager.googleapis.com"
+	gcpScope           = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+// gcpFetcher reads secrets from Google Cloud Secret Manager through its
+// REST API. The path is "projects/<project>/secrets/<secret>", optionally
+// followed by "/versions/<version>", or just the secret of the configured
+// project. The latest version is read by default.
+type gcpFetcher struct {
+	client   *http.Client
+	endpoint string
+	project  string
+}
+
+func newGCPFetcher(ctx context.Context, cfg *configv1.GcpSecretManagerBackend) (*gcpFetcher, error) {
+	var creds *google.Credentials
+	if cfg.GetCredentialsFile() != "" {
+		data, err := os.ReadFile(cfg.GetCredentialsFile())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gcp credentials file: %w", err)
+		}
+		creds, err = google.CredentialsFromJSON(ctx, data, gcpScope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse gcp credentials file: %w", err)
+		}
+	} else {
+		var err error
+		creds, err = google.FindDefaultCredentials(ctx, gcpScope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find gcp application default credentials: %w", err)
+		}
+	}
+	project := cfg.GetProject()
+	if project == "" {
+		project = creds.ProjectID
+	}
+	endpoint := defaultGCPEndpoint
+	if cfg.GetEndpoint() != "" {
+		endpoint = cfg.GetEndpoint()
+	}
+	return newGCPFetcherWithTokens(creds.TokenSource, endpoint, project), nil
+}
+
+func newGCPFetcherWithTokens(tokens oauth2.TokenSource, endpoint, project string) *gcpFetcher {
+	client := oauth2.NewClient(context.Background(), tokens)
+	client.Timeout = fetchTimeout
+	return &gcpFetcher{client: client, endpoint: strings.TrimRight(endpoint, "/"), project: project}
+}
+
+// versionName returns the full name of the secret version at the path.
+func (f *gcpFetcher) versionName(path string) (string, error) {
+	if !strings.HasPrefix(path, "projects/") {
+		if f.project == "" || strings.Contains(path, "/") {
+			return "", fmt.Errorf("gcp secret %q must be projects/<project>/secrets/<secret>, or a secret name when a project is configured", path)
+		}
+		path = "projects/" + f.project + "/secrets/" + path
+	}
+	parts := strings.Split(path, "/")
+	switch {
+	case len(parts) == 4 && parts[2] == "secrets":
+		return path + "/versions/latest", nil
+	case len(parts) == 6 && parts[2] == "secrets" && parts[4] == "versions":
+		return path, nil
+	default:
+		return "", fmt.Errorf("gcp secret %q must be projects/<project>/secrets/<secret>[/versions/<version>]", path)
+	}
+}
+
+func (f *gcpFetcher) fetch(ctx context.Context, path string) (string, error) {
+	name, err := f.versionName(path)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.endpoint+"/v1/"+name+":access", nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to access gcp secret %s: %w", name, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read gcp secret %s: %w", name, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.Unmarshal(body, &apiErr)
+		return "", fmt.Errorf("failed to access gcp secret %s: %s %s", name, resp.Status, apiErr.Error.Message)
+	}
+	var out struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf("failed to decode gcp secret %s: %w", name, err)
+	}
+	data, err := base64.StdEncoding.DecodeString(out.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode gcp secret %s: %w", name, err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudsecrets
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestGCPFetcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer gcp-token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/v1/projects/my-project/secrets/api-key/versions/latest:access":
+			_, _ = fmt.Fprintf(w, `{"payload":{"data":%q}}`, base64.StdEncoding.EncodeToString([]byte("latest-key")))
+		case "/v1/projects/other/secrets/db/versions/3:access":
+			_, _ = fmt.Fprintf(w, `{"payload":{"data":%q}}`, base64.StdEncoding.EncodeToString([]byte(`{"password":"v3"}`)))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":404,"message":"Secret not found"}}`))
+		}
+	}))
+	defer server.Close()
+
+	f := newGCPFetcherWithTokens(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "gcp-token"}), server.URL, "my-project")
+	b := newBackend(SchemeGCP, f, 0)
+	ctx := context.Background()
+
+	value, err := b.Resolve(ctx, "api-key", "")
+	require.NoError(t, err)
+	assert.Equal(t, "latest-key", value)
+
+	value, err = b.Resolve(ctx, "projects/my-project/secrets/api-key", "")
+	require.NoError(t, err)
+	assert.Equal(t, "latest-key", value)
+
+	value, err = b.Resolve(ctx, "projects/other/secrets/db/versions/3", "password")
+	require.NoError(t, err)
+	assert.Equal(t, "v3", value)
+
+	_, err = b.Resolve(ctx, "missing", "")
+	assert.ErrorContains(t, err, "Secret not found")
+
+	_, err = b.Resolve(ctx, "projects/my-project/api-key", "")
+	assert.ErrorContains(t, err, "must be projects/<project>/secrets/<secret>")
+
+	noProject := newGCPFetcherWithTokens(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "gcp-token"}), server.URL, "")
+	_, err = noProject.fetch(ctx, "api-key")
+	assert.ErrorContains(t, err, "when a project is configured")
+}
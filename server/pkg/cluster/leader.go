@@ -0,0 +1,69 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cluster tells the singleton duties of the server whether this
+// replica leads the cluster. The duties that must run once among the replicas,
+// such as pruning or rotating what they share in the database, skip their
+// runs on the replicas that do not lead.
+package cluster
+
+import "sync/atomic"
+
+// Leader reports whether this replica leads the cluster.
+type Leader interface {
+	// IsLeader reports whether this replica leads the cluster.
+	//
+	// Returns:
+	//   - bool: True if the singleton duties run here.
+	IsLeader() bool
+}
+
+// Standalone leads alone. It is the leader election of a server whose
+// storage no other replica shares.
+var Standalone Leader = fixed(true)
+
+// fixed is a leader election with a fixed outcome.
+type fixed bool
+
+func (f fixed) IsLeader() bool { return bool(f) }
+
+var leader atomic.Pointer[Leader]
+
+// SetLeader installs the leader election of the replicas. Passing nil removes
+// it, and this replica no longer leads.
+//
+// Parameters:
+//   - l: The leader election, or nil.
+//
+// Side Effects:
+//   - Replaces the process-wide leader election.
+func SetLeader(l Leader) {
+	if l == nil {
+		leader.Store(nil)
+		return
+	}
+	leader.Store(&l)
+}
+
+// Resign ends the leadership of this replica as it stops: from then on it
+// does not lead, whatever election was installed.
+//
+// Side Effects:
+//   - Replaces the process-wide leader election.
+func Resign() {
+	SetLeader(fixed(false))
+}
+
+// IsLeader reports whether the singleton duties run on this replica. A server
+// without leader election does not lead, so that the replicas sharing a
+// database never all run the duties; a server with storage of its own
+// installs Standalone.
+//
+// Summary: Reports whether this replica leads the cluster.
+//
+// Returns:
+//   - bool: True if this replica leads.
+func IsLeader() bool {
+	l := leader.Load()
+	return l != nil && (*l).IsLeader()
+}
@@ -0,0 +1,32 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fixedLeader bool
+
+func (l fixedLeader) IsLeader() bool { return bool(l) }
+
+func TestIsLeader(t *testing.T) {
+	t.Cleanup(func() { SetLeader(nil) })
+	assert.False(t, IsLeader(), "a replica without election does not lead")
+
+	SetLeader(fixedLeader(false))
+	assert.False(t, IsLeader())
+	SetLeader(fixedLeader(true))
+	assert.True(t, IsLeader())
+
+	SetLeader(nil)
+	assert.False(t, IsLeader())
+
+	SetLeader(Standalone)
+	assert.True(t, IsLeader())
+	Resign()
+	assert.False(t, IsLeader(), "a stopping replica does not lead")
+}
@@ -35,7 +35,8 @@ func BindRootFlags(cmd *cobra.Command) {
 	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
 
 	cmd.PersistentFlags().String("mcp-listen-address", ":50050", "MCP server's bind address. Env: MCPANY_MCP_LISTEN_ADDRESS")
-	cmd.PersistentFlags().StringSlice("config-path", []string{}, "Paths to configuration files or directories for pre-registering services. Can be specified multiple times. Env: MCPANY_CONFIG_PATH")
+	cmd.PersistentFlags().StringSlice("config-path", []string{}, "Paths to configuration files, directories, URLs, etcd:// and consul:// key prefixes or k8s:// resources for pre-registering services. Can be specified multiple times. Env: MCPANY_CONFIG_PATH")
+	cmd.PersistentFlags().String("environment", "", "Environment whose config overlays are applied, e.g. 'prod' to merge config.prod.yaml into config.yaml. Env: MCPANY_ENVIRONMENT")
 	cmd.PersistentFlags().String("metrics-listen-address", "", "Address to expose Prometheus metrics on. If not specified, metrics are disabled. Env: MCPANY_METRICS_LISTEN_ADDRESS")
 	cmd.PersistentFlags().Bool("debug", false, "Enable debug logging. Env: MCPANY_DEBUG")
 	cmd.PersistentFlags().String("log-level", "info", "Set the log level (debug, info, warn, error). Env: MCPANY_LOG_LEVEL")
@@ -51,6 +52,10 @@ func BindRootFlags(cmd *cobra.Command) {
 		fmt.Fprintf(os.Stderr, "Error binding config-path flag: %v\n", err)
 		os.Exit(1)
 	}
+	if err := viper.BindPFlag("environment", cmd.PersistentFlags().Lookup("environment")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding environment flag: %v\n", err)
+		os.Exit(1)
+	}
 	if err := viper.BindPFlag("metrics-listen-address", cmd.PersistentFlags().Lookup("metrics-listen-address")); err != nil {
 		fmt.Fprintf(os.Stderr, "Error binding metrics-listen-address flag: %v\n", err)
 		os.Exit(1)
@@ -95,10 +100,21 @@ func BindRootFlags(cmd *cobra.Command) {
 func BindServerFlags(cmd *cobra.Command) {
 	cmd.Flags().String("grpc-port", "", "Port for the gRPC registration server. If not specified, gRPC registration is disabled. Env: MCPANY_GRPC_PORT")
 	cmd.Flags().Bool("stdio", false, "Enable stdio mode for JSON-RPC communication. Env: MCPANY_STDIO")
+	cmd.Flags().Bool("stdio-with-network", false, "In stdio mode, also start the HTTP and gRPC listeners so remote clients can share the same instance. Env: MCPANY_STDIO_WITH_NETWORK")
 	cmd.Flags().Duration("shutdown-timeout", 5*time.Second, "Graceful shutdown timeout. Env: MCPANY_SHUTDOWN_TIMEOUT")
 	cmd.Flags().String("api-key", "", "API key for securing the MCP server. If set, all requests must include this key in the 'X-API-Key' header. Env: MCPANY_API_KEY")
 	cmd.Flags().StringSlice("profiles", []string{"default"}, "Comma-separated list of active profiles. Env: MCPANY_PROFILES")
 	cmd.Flags().String("db-path", "data/mcpany.db", "Path to the SQLite database file. Env: MCPANY_DB_PATH")
+	cmd.Flags().String("tls-cert", "", "Path to the PEM certificate served on the MCP listen address. Enables TLS. Env: MCPANY_TLS_CERT")
+	cmd.Flags().String("tls-key", "", "Path to the PEM private key of --tls-cert. Env: MCPANY_TLS_KEY")
+	cmd.Flags().String("tls-client-ca", "", "Path to the CA bundle verifying client certificates. Enables client certificate authentication. Env: MCPANY_TLS_CLIENT_CA")
+	cmd.Flags().String("tls-client-auth", "", "Client certificate policy with --tls-client-ca: 'require' (default) or 'optional'. Env: MCPANY_TLS_CLIENT_AUTH")
+	cmd.Flags().StringSlice("acme-domains", []string{}, "Domains to obtain TLS certificates for via ACME (Let's Encrypt) instead of --tls-cert. Env: MCPANY_ACME_DOMAINS")
+	cmd.Flags().String("acme-email", "", "Contact email for the ACME account. Env: MCPANY_ACME_EMAIL")
+	cmd.Flags().String("acme-cache-dir", "data/acme", "Directory to store ACME account keys and certificates. Env: MCPANY_ACME_CACHE_DIR")
+	cmd.Flags().String("acme-directory-url", "", "ACME directory URL. Defaults to Let's Encrypt production. Env: MCPANY_ACME_DIRECTORY_URL")
+	cmd.Flags().Duration("config-poll-interval", DefaultConfigPollInterval, "How often configs loaded from http(s) URLs are checked for changes. 0 disables polling. Env: MCPANY_CONFIG_POLL_INTERVAL")
+	cmd.Flags().String("config-public-key", "", "Path to a cosign PEM or minisign public key. Configs loaded from http(s) URLs must then be signed with it, in <url>.sig or <url>.minisig. Env: MCPANY_CONFIG_PUBLIC_KEY")
 
 	if err := viper.BindPFlag("grpc-port", cmd.Flags().Lookup("grpc-port")); err != nil {
 		fmt.Fprintf(os.Stderr, "Error binding grpc-port flag: %v\n", err)
@@ -108,6 +124,10 @@ func BindServerFlags(cmd *cobra.Command) {
 		fmt.Fprintf(os.Stderr, "Error binding stdio flag: %v\n", err)
 		os.Exit(1)
 	}
+	if err := viper.BindPFlag("stdio-with-network", cmd.Flags().Lookup("stdio-with-network")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding stdio-with-network flag: %v\n", err)
+		os.Exit(1)
+	}
 	if err := viper.BindPFlag("shutdown-timeout", cmd.Flags().Lookup("shutdown-timeout")); err != nil {
 		fmt.Fprintf(os.Stderr, "Error binding shutdown-timeout flag: %v\n", err)
 		os.Exit(1)
@@ -124,6 +144,46 @@ func BindServerFlags(cmd *cobra.Command) {
 		fmt.Fprintf(os.Stderr, "Error binding db-path flag: %v\n", err)
 		os.Exit(1)
 	}
+	if err := viper.BindPFlag("tls-cert", cmd.Flags().Lookup("tls-cert")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding tls-cert flag: %v\n", err)
+		os.Exit(1)
+	}
+	if err := viper.BindPFlag("tls-key", cmd.Flags().Lookup("tls-key")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding tls-key flag: %v\n", err)
+		os.Exit(1)
+	}
+	if err := viper.BindPFlag("tls-client-ca", cmd.Flags().Lookup("tls-client-ca")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding tls-client-ca flag: %v\n", err)
+		os.Exit(1)
+	}
+	if err := viper.BindPFlag("tls-client-auth", cmd.Flags().Lookup("tls-client-auth")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding tls-client-auth flag: %v\n", err)
+		os.Exit(1)
+	}
+	if err := viper.BindPFlag("acme-domains", cmd.Flags().Lookup("acme-domains")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding acme-domains flag: %v\n", err)
+		os.Exit(1)
+	}
+	if err := viper.BindPFlag("acme-email", cmd.Flags().Lookup("acme-email")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding acme-email flag: %v\n", err)
+		os.Exit(1)
+	}
+	if err := viper.BindPFlag("acme-cache-dir", cmd.Flags().Lookup("acme-cache-dir")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding acme-cache-dir flag: %v\n", err)
+		os.Exit(1)
+	}
+	if err := viper.BindPFlag("acme-directory-url", cmd.Flags().Lookup("acme-directory-url")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding acme-directory-url flag: %v\n", err)
+		os.Exit(1)
+	}
+	if err := viper.BindPFlag("config-poll-interval", cmd.Flags().Lookup("config-poll-interval")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding config-poll-interval flag: %v\n", err)
+		os.Exit(1)
+	}
+	if err := viper.BindPFlag("config-public-key", cmd.Flags().Lookup("config-public-key")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding config-public-key flag: %v\n", err)
+		os.Exit(1)
+	}
 }
 
 // BindFlags binds both root and server-specific command line flags to the Viper configuration registry.
@@ -127,12 +127,14 @@ func TestGlobalSettings(t *testing.T) {
 	assert.Equal(t, ":50050", s.MCPListenAddress())
 	assert.False(t, s.IsDebug())
 	assert.False(t, s.Stdio())
+	assert.False(t, s.StdioWithNetwork())
 
 	// Test with values from viper
 	viper.Set("grpc-port", "6001")
 	viper.Set("mcp-listen-address", "0.0.0.0:6000")
 	viper.Set("debug", true)
 	viper.Set("stdio", true)
+	viper.Set("stdio-with-network", true)
 
 	// Reload settings to apply viper changes
 	err = s.Load(cmd, nil)
@@ -142,6 +144,7 @@ func TestGlobalSettings(t *testing.T) {
 	assert.Equal(t, "0.0.0.0:6000", s.MCPListenAddress())
 	assert.True(t, s.IsDebug())
 	assert.True(t, s.Stdio())
+	assert.True(t, s.StdioWithNetwork())
 }
 
 // Copyright 2025 Author(s) of MCP Any
@@ -262,6 +265,12 @@ func TestBindServerFlags_Comprehensive(t *testing.T) {
 			envVal:   "true",
 			checkVal: "true",
 		},
+		{
+			flag:     "stdio-with-network",
+			envVar:   "MCPANY_STDIO_WITH_NETWORK",
+			envVal:   "true",
+			checkVal: "true",
+		},
 		{
 			flag:     "shutdown-timeout",
 			envVar:   "MCPANY_SHUTDOWN_TIMEOUT",
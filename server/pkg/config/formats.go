@@ -0,0 +1,161 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+// tomlEngine implements the Engine interface for TOML configuration files.
+type tomlEngine struct {
+	yamlEngine
+}
+
+// Unmarshal parses a TOML byte slice into a `proto.Message`.
+//
+// Parameters:
+//   - b ([]byte): The TOML document.
+//   - v (proto.Message): The destination message.
+//
+// Returns:
+//   - error: An error if the document is not valid TOML, or does not match the schema.
+func (e *tomlEngine) Unmarshal(b []byte, v proto.Message) error {
+	m, err := decodeTOML(b)
+	if err != nil {
+		return err
+	}
+	return e.unmarshalInternal(m, v, b)
+}
+
+// DecodeDocument parses a YAML, JSON or TOML configuration document into a
+// map of JSON values, as the file store does before it applies the overrides
+// and validates the document. The extension of path selects the format.
+//
+// Summary: Decodes a configuration document into a generic map.
+//
+// Parameters:
+//   - path: string. The path of the document, for its extension.
+//   - b: []byte. The document.
+//
+// Returns:
+//   - map[string]interface{}: The document, with JSON types.
+//   - error: An error if the format is not YAML, JSON or TOML, or the document does not parse.
+func DecodeDocument(path string, b []byte) (map[string]interface{}, error) {
+	var (
+		m   map[string]interface{}
+		err error
+	)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err = yaml.Unmarshal(b, &m); err != nil {
+			err = fmt.Errorf("failed to unmarshal YAML: %w", err)
+		}
+	case ".json":
+		m, err = decodeJSON(b)
+	case ".toml":
+		m, err = decodeTOML(b)
+	default:
+		return nil, fmt.Errorf("unsupported document format '%s'", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Round-trip through JSON, so that the values have the types the schema
+	// validation expects whatever the format.
+	jsonData, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal document to JSON: %w", err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(jsonData, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal document JSON: %w", err)
+	}
+	if doc == nil {
+		doc = map[string]interface{}{}
+	}
+	return doc, nil
+}
+
+// decodeJSON parses a JSON document into a map. Numbers are kept as written.
+func decodeJSON(b []byte) (map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var m map[string]interface{}
+	if err := dec.Decode(&m); err != nil {
+		var (
+			syntaxErr *json.SyntaxError
+			typeErr   *json.UnmarshalTypeError
+		)
+		switch {
+		case errors.As(err, &syntaxErr):
+			return nil, fmt.Errorf("failed to unmarshal JSON: line %d: %w", lineAtOffset(b, syntaxErr.Offset), err)
+		case errors.As(err, &typeErr):
+			return nil, fmt.Errorf("failed to unmarshal JSON: line %d: %w", lineAtOffset(b, typeErr.Offset), err)
+		}
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	if m == nil {
+		m = map[string]interface{}{}
+	}
+	return m, nil
+}
+
+// decodeTOML parses a TOML document into a map.
+func decodeTOML(b []byte) (map[string]interface{}, error) {
+	var m map[string]interface{}
+	if err := toml.Unmarshal(b, &m); err != nil {
+		var decodeErr *toml.DecodeError
+		if errors.As(err, &decodeErr) {
+			row, _ := decodeErr.Position()
+			return nil, fmt.Errorf("failed to unmarshal TOML: line %d: %w", row, err)
+		}
+		return nil, fmt.Errorf("failed to unmarshal TOML: %w", err)
+	}
+	if m == nil {
+		m = map[string]interface{}{}
+	}
+	return m, nil
+}
+
+// lineAtOffset returns the line of a byte offset in a document, from 1.
+func lineAtOffset(b []byte, offset int64) int {
+	offset = min(max(offset, 0), int64(len(b)))
+	return bytes.Count(b[:offset], []byte("\n")) + 1
+}
+
+// findTOMLKeyLine returns the line of the first key, or table header, named
+// key in a TOML document, or 0.
+func findTOMLKeyLine(b []byte, key string) int {
+	for i, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		var name string
+		if strings.HasPrefix(line, "[") {
+			// [table] or [[array.of.tables]], maybe followed by a comment.
+			name = strings.Trim(line[:strings.LastIndex(line, "]")+1], "[] ")
+		} else {
+			k, _, ok := strings.Cut(line, "=")
+			if !ok || strings.HasPrefix(line, "#") {
+				continue
+			}
+			name = k
+		}
+		// Dotted keys: a.b.c = 1
+		for _, part := range strings.Split(name, ".") {
+			if strings.Trim(strings.TrimSpace(part), `"'`) == key {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,181 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+const formatsYAML = `
+global_settings:
+  log_level: debug
+upstream_services:
+  - name: weather
+    priority: 2
+    http_service:
+      address: https://api.weather.example.com
+      tools:
+        - name: get_forecast
+          call_id: forecast
+      calls:
+        forecast:
+          id: forecast
+          endpoint_path: /forecast
+          method: HTTP_METHOD_GET
+`
+
+const formatsJSON = `{
+  "global_settings": {"log_level": "debug"},
+  "upstream_services": [{
+    "name": "weather",
+    "priority": 2,
+    "http_service": {
+      "address": "https://api.weather.example.com",
+      "tools": [{"name": "get_forecast", "call_id": "forecast"}],
+      "calls": {"forecast": {"id": "forecast", "endpoint_path": "/forecast", "method": "HTTP_METHOD_GET"}}
+    }
+  }]
+}`
+
+const formatsTOML = `
+[global_settings]
+log_level = "debug"
+
+[[upstream_services]]
+name = "weather"
+priority = 2
+
+[upstream_services.http_service]
+address = "https://api.weather.example.com"
+
+[[upstream_services.http_service.tools]]
+name = "get_forecast"
+call_id = "forecast"
+
+[upstream_services.http_service.calls.forecast]
+id = "forecast"
+endpoint_path = "/forecast"
+method = "HTTP_METHOD_GET"
+`
+
+func TestFileStore_Formats(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/config/weather.yaml", []byte(formatsYAML), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/config/weather.json", []byte(formatsJSON), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/config/weather.toml", []byte(formatsTOML), 0o644))
+
+	want, err := NewFileStore(fs, []string{"/config/weather.yaml"}).Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, want.GetUpstreamServices(), 1)
+
+	for _, path := range []string{"/config/weather.json", "/config/weather.toml"} {
+		t.Run(path, func(t *testing.T) {
+			got, err := NewFileStore(fs, []string{path}).Load(context.Background())
+			require.NoError(t, err)
+			assert.True(t, proto.Equal(want, got), "got %v, want %v", got, want)
+		})
+	}
+
+	t.Run("directory", func(t *testing.T) {
+		files, err := NewFileStore(fs, []string{"/config"}).collectFilePaths()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/config/weather.json", "/config/weather.toml", "/config/weather.yaml"}, files)
+	})
+}
+
+func TestFileStore_FormatErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		wantErr []string
+	}{
+		{
+			name:    "json syntax",
+			path:    "/config.json",
+			content: "{\n  \"upstream_services\": [\n    {\"name\": \"weather\",}\n  ]\n}",
+			wantErr: []string{"failed to unmarshal JSON: line 3"},
+		},
+		{
+			name:    "toml syntax",
+			path:    "/config.toml",
+			content: "[global_settings]\nlog_level = \"debug\"\napi_key = \n",
+			wantErr: []string{"failed to unmarshal TOML: line 3"},
+		},
+		{
+			name:    "json unknown field",
+			path:    "/config.json",
+			content: "{\n  \"global_settings\": {\n    \"log_levle\": \"debug\"\n  }\n}",
+			wantErr: []string{"line 3", `unknown field "log_levle"`, `Did you mean "log_level"?`},
+		},
+		{
+			name:    "toml unknown field",
+			path:    "/config.toml",
+			content: "[global_settings]\napi_key = \"k\"\nlog_levle = \"debug\"\n",
+			wantErr: []string{"line 3", `unknown field "log_levle"`, `Did you mean "log_level"?`},
+		},
+		{
+			name:    "toml services alias",
+			path:    "/config.toml",
+			content: "[[services]]\nname = \"weather\"\n",
+			wantErr: []string{"line 1", `Did you mean "upstream_services"?`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			require.NoError(t, afero.WriteFile(fs, tt.path, []byte(tt.content), 0o644))
+			_, err := NewFileStore(fs, []string{tt.path}).Load(context.Background())
+			require.Error(t, err)
+			for _, want := range tt.wantErr {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestDecodeDocument(t *testing.T) {
+	for _, tc := range []struct{ path, content string }{
+		{"a.yaml", formatsYAML},
+		{"a.json", formatsJSON},
+		{"a.toml", formatsTOML},
+	} {
+		doc, err := DecodeDocument(tc.path, []byte(tc.content))
+		require.NoError(t, err, tc.path)
+		services, ok := doc["upstream_services"].([]interface{})
+		require.True(t, ok, tc.path)
+		assert.Equal(t, "weather", services[0].(map[string]interface{})["name"], tc.path)
+		assert.NoError(t, ValidateConfigAgainstSchema(doc), tc.path)
+	}
+
+	doc, err := DecodeDocument("a.toml", []byte("priority = 3\n"))
+	require.NoError(t, err)
+	assert.Equal(t, float64(3), doc["priority"])
+
+	_, err = DecodeDocument("a.textproto", []byte(""))
+	assert.ErrorContains(t, err, "unsupported document format")
+}
+
+func TestFindTOMLKeyLine(t *testing.T) {
+	doc := []byte(`# services
+[[upstream_services]] # the first one
+name = "weather"
+http_service.address = "https://example.com"
+
+[upstream_services.http_service.calls.forecast]
+"endpoint_path" = "/forecast"
+`)
+	assert.Equal(t, 2, findTOMLKeyLine(doc, "upstream_services"))
+	assert.Equal(t, 3, findTOMLKeyLine(doc, "name"))
+	assert.Equal(t, 4, findTOMLKeyLine(doc, "address"))
+	assert.Equal(t, 6, findTOMLKeyLine(doc, "forecast"))
+	assert.Equal(t, 7, findTOMLKeyLine(doc, "endpoint_path"))
+	assert.Equal(t, 0, findTOMLKeyLine(doc, "services"))
+}
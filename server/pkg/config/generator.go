@@ -7,6 +7,7 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 	"text/template"
@@ -17,12 +18,15 @@ import (
 // Summary: Interactive configuration generator.
 //
 // It prompts the user for input and uses templates to generate YAML configuration
-// for different types of services (HTTP, gRPC, OpenAPI, GraphQL).
+// for different types of services (HTTP, gRPC, OpenAPI, GraphQL). Given a base
+// URL instead of a service type, it discovers the service served there.
 //
 // Fields:
 //   - Reader (*bufio.Reader): The reader to use for user input.
+//   - HTTPClient (*http.Client): The client of the discovery probes. If nil, a default client is used.
 type Generator struct {
-	Reader *bufio.Reader
+	Reader     *bufio.Reader
+	HTTPClient *http.Client
 }
 
 // NewGenerator creates a new Generator instance that reads from standard input.
@@ -59,12 +63,17 @@ func NewGenerator() *Generator {
 // Side Effects:
 //   - None
 func (g *Generator) Generate() ([]byte, error) {
-	serviceType, err := g.prompt("🤖 Enter service type (http, grpc, openapi, graphql): ")
+	serviceType, err := g.prompt("🤖 Enter service type (discover, http, grpc, openapi, graphql), or a base URL to discover: ")
 	if err != nil {
 		return nil, err
 	}
 
+	if strings.Contains(serviceType, "://") {
+		return g.generateDiscoveredService(serviceType)
+	}
 	switch strings.ToLower(serviceType) {
+	case "discover":
+		return g.generateDiscoveredService("")
 	case "http":
 		return g.generateHTTPService()
 	case "grpc":
@@ -0,0 +1,607 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	reflectpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+// The kinds of upstream that DiscoverUpstream detects.
+const (
+	UpstreamKindMCP     = "mcp"
+	UpstreamKindOpenAPI = "openapi"
+	UpstreamKindGRPC    = "grpc"
+)
+
+const (
+	// discoveryProbeTimeout bounds each probe of DiscoverUpstream.
+	discoveryProbeTimeout = 5 * time.Second
+	// maxDiscoveryBody bounds the responses that the probes read.
+	maxDiscoveryBody = 10 << 20
+)
+
+// openAPISpecPaths are the paths, relative to the base URL, where the
+// OpenAPI probe looks for a spec, in order.
+var openAPISpecPaths = []string{
+	"/openapi.json",
+	"/openapi.yaml",
+	"/swagger.json",
+	"/v3/api-docs",
+	"/swagger/v1/swagger.json",
+	"/api-docs",
+}
+
+// mcpEndpointPaths are the paths, relative to the base URL, where the MCP
+// probe looks for a streamable HTTP endpoint, in order.
+var mcpEndpointPaths = []string{"", "/mcp"}
+
+// UpstreamCandidate is an upstream that DiscoverUpstream found at a base URL.
+//
+// Summary: A service discovered at a base URL.
+//
+// Fields:
+//   - Kind (string): The kind of upstream: UpstreamKindMCP, UpstreamKindOpenAPI or UpstreamKindGRPC.
+//   - Address (string): The address of the upstream: a URL, or host:port for gRPC.
+//   - SpecURL (string): The URL of the OpenAPI spec, for UpstreamKindOpenAPI.
+//   - TLS (bool): Whether the gRPC upstream is served over TLS.
+//   - Detail (string): A description of what was found, for display.
+type UpstreamCandidate struct {
+	Kind    string
+	Address string
+	SpecURL string
+	TLS     bool
+	Detail  string
+}
+
+// UpstreamDiscovery is the outcome of DiscoverUpstream.
+//
+// Summary: The upstreams found at a base URL.
+//
+// Fields:
+//   - Candidates ([]UpstreamCandidate): The upstreams found, MCP first, then OpenAPI, then gRPC.
+//   - AuthRequired (bool): Whether a probe was refused with 401 or 403.
+type UpstreamDiscovery struct {
+	Candidates   []UpstreamCandidate
+	AuthRequired bool
+}
+
+// DiscoverUpstream probes a base URL for an MCP streamable HTTP endpoint, an
+// OpenAPI spec at the usual paths, and gRPC reflection on its host and port.
+// The probes run concurrently, each within a few seconds.
+//
+// Summary: Detects the kind of service served at a base URL.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the probes.
+//   - baseURL: string. The base URL, e.g. "https://api.example.com" or "localhost:50051".
+//   - client: *http.Client. The client of the HTTP probes. If nil, a default client is used.
+//
+// Returns:
+//   - *UpstreamDiscovery: The upstreams found, maybe none.
+//   - error: An error if the base URL is invalid.
+//
+// Side Effects:
+//   - Sends HTTP requests and a gRPC reflection request to the base URL.
+//   - Opens, then closes, an MCP session on the endpoint found.
+func DiscoverUpstream(ctx context.Context, baseURL string, client *http.Client) (*UpstreamDiscovery, error) {
+	u, err := parseBaseURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		client = &http.Client{Timeout: discoveryProbeTimeout}
+	}
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		found     = make(map[string]UpstreamCandidate)
+		discovery UpstreamDiscovery
+	)
+	report := func(c *UpstreamCandidate, authRequired bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if c != nil {
+			found[c.Kind] = *c
+		}
+		discovery.AuthRequired = discovery.AuthRequired || authRequired
+	}
+	probe := func(f func() (*UpstreamCandidate, bool)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report(f())
+		}()
+	}
+	if u.Scheme == "http" || u.Scheme == "https" {
+		probe(func() (*UpstreamCandidate, bool) { return probeMCP(ctx, client, u) })
+		probe(func() (*UpstreamCandidate, bool) { return probeOpenAPI(ctx, client, u) })
+	}
+	probe(func() (*UpstreamCandidate, bool) { return probeGRPC(ctx, u), false })
+	wg.Wait()
+
+	for _, kind := range []string{UpstreamKindMCP, UpstreamKindOpenAPI, UpstreamKindGRPC} {
+		if c, ok := found[kind]; ok {
+			discovery.Candidates = append(discovery.Candidates, c)
+		}
+	}
+	return &discovery, nil
+}
+
+// parseBaseURL parses a base URL. A host:port without a scheme is taken as
+// http.
+func parseBaseURL(baseURL string) (*url.URL, error) {
+	baseURL = strings.TrimSpace(baseURL)
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "http://" + baseURL
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "grpc", "grpcs":
+	default:
+		return nil, fmt.Errorf("invalid base URL %q: unsupported scheme %q", baseURL, u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: missing host", baseURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawQuery, u.Fragment = "", ""
+	return u, nil
+}
+
+// isAuthStatus reports whether an HTTP status refuses a request for lack of
+// credentials.
+func isAuthStatus(code int) bool {
+	return code == http.StatusUnauthorized || code == http.StatusForbidden
+}
+
+// probeOpenAPI looks for an OpenAPI or Swagger spec at the base URL, then at
+// the usual paths below it.
+func probeOpenAPI(ctx context.Context, client *http.Client, base *url.URL) (*UpstreamCandidate, bool) {
+	authRequired := false
+	for _, p := range append([]string{""}, openAPISpecPaths...) {
+		specURL := base.String() + p
+		doc, code := fetchDocument(ctx, client, specURL)
+		authRequired = authRequired || isAuthStatus(code)
+		if doc == nil {
+			continue
+		}
+		version, _ := doc["openapi"].(string)
+		if version == "" {
+			version, _ = doc["swagger"].(string)
+		}
+		if version == "" {
+			continue
+		}
+		info, _ := doc["info"].(map[string]interface{})
+		title, _ := info["title"].(string)
+		address := base.String()
+		if p == "" {
+			// The base URL is the spec itself: the API is served from its host.
+			address = (&url.URL{Scheme: base.Scheme, Host: base.Host}).String()
+		}
+		detail := fmt.Sprintf("OpenAPI %s spec at %s", version, specURL)
+		if title != "" {
+			detail = fmt.Sprintf("OpenAPI %s spec %q at %s", version, title, specURL)
+		}
+		return &UpstreamCandidate{Kind: UpstreamKindOpenAPI, Address: address, SpecURL: specURL, Detail: detail}, authRequired
+	}
+	return nil, authRequired
+}
+
+// fetchDocument gets a JSON or YAML document. It returns nil if the
+// request fails or the response is not a document, and the status code.
+func fetchDocument(ctx context.Context, client *http.Client, target string) (map[string]interface{}, int) {
+	ctx, cancel := context.WithTimeout(ctx, discoveryProbeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, 0
+	}
+	req.Header.Set("Accept", "application/json, application/yaml;q=0.9, */*;q=0.8")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDiscoveryBody))
+	if err != nil {
+		return nil, resp.StatusCode
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		if err := yaml.Unmarshal(body, &doc); err != nil {
+			return nil, resp.StatusCode
+		}
+	}
+	return doc, resp.StatusCode
+}
+
+// probeMCP looks for an MCP streamable HTTP endpoint at the base URL, then
+// at /mcp below it, by sending an initialize request.
+func probeMCP(ctx context.Context, client *http.Client, base *url.URL) (*UpstreamCandidate, bool) {
+	authRequired := false
+	for _, p := range mcpEndpointPaths {
+		endpoint := base.String() + p
+		serverInfo, code := initializeMCP(ctx, client, endpoint)
+		authRequired = authRequired || isAuthStatus(code)
+		if serverInfo == "" {
+			continue
+		}
+		return &UpstreamCandidate{
+			Kind:    UpstreamKindMCP,
+			Address: endpoint,
+			Detail:  fmt.Sprintf("MCP server %s at %s", serverInfo, endpoint),
+		}, authRequired
+	}
+	return nil, authRequired
+}
+
+// initializeMCP sends an MCP initialize request to an endpoint, and returns
+// the name and version of the server, or empty if the endpoint is not an MCP
+// server, and the status code.
+func initializeMCP(ctx context.Context, client *http.Client, endpoint string) (string, int) {
+	ctx, cancel := context.WithTimeout(ctx, discoveryProbeTimeout)
+	defer cancel()
+	body := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"mcpany-config-generator","version":"1.0.0"}}}`
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(body))
+	if err != nil {
+		return "", 0
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", 0
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if sessionID := resp.Header.Get("Mcp-Session-Id"); sessionID != "" {
+		defer closeMCPSession(ctx, client, endpoint, sessionID)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", resp.StatusCode
+	}
+
+	// The response is a JSON object, or an event stream whose first data
+	// line is the JSON object.
+	var data []byte
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		scanner := bufio.NewScanner(io.LimitReader(resp.Body, 1<<20))
+		for scanner.Scan() {
+			if line, ok := strings.CutPrefix(scanner.Text(), "data:"); ok {
+				data = []byte(line)
+				break
+			}
+		}
+	} else if data, err = io.ReadAll(io.LimitReader(resp.Body, 1<<20)); err != nil {
+		return "", resp.StatusCode
+	}
+	var result struct {
+		JSONRPC string `json:"jsonrpc"`
+		Result  struct {
+			ProtocolVersion string `json:"protocolVersion"`
+			ServerInfo      struct {
+				Name    string `json:"name"`
+				Version string `json:"version"`
+			} `json:"serverInfo"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(data), &result); err != nil || result.JSONRPC != "2.0" || result.Result.ProtocolVersion == "" {
+		return "", resp.StatusCode
+	}
+	info := strings.TrimSpace(result.Result.ServerInfo.Name + " " + result.Result.ServerInfo.Version)
+	if info == "" {
+		info = "(protocol " + result.Result.ProtocolVersion + ")"
+	}
+	return info, resp.StatusCode
+}
+
+// closeMCPSession ends the session that a probe opened, as a well-behaved
+// client does.
+func closeMCPSession(ctx context.Context, client *http.Client, endpoint, sessionID string) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Mcp-Session-Id", sessionID)
+	if resp, err := client.Do(req); err == nil {
+		_ = resp.Body.Close()
+	}
+}
+
+// probeGRPC lists the services of the host and port of the base URL with gRPC
+// reflection. TLS is used for https and grpcs.
+func probeGRPC(ctx context.Context, base *url.URL) *UpstreamCandidate {
+	useTLS := base.Scheme == "https" || base.Scheme == "grpcs"
+	address := base.Host
+	if base.Port() == "" {
+		port := "80"
+		if useTLS {
+			port = "443"
+		}
+		address = net.JoinHostPort(base.Hostname(), port)
+	}
+
+	creds := insecure.NewCredentials()
+	if useTLS {
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = conn.Close() }()
+
+	ctx, cancel := context.WithTimeout(ctx, discoveryProbeTimeout)
+	defer cancel()
+	stream, err := reflectpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil
+	}
+	if err := stream.Send(&reflectpb.ServerReflectionRequest{
+		MessageRequest: &reflectpb.ServerReflectionRequest_ListServices{},
+	}); err != nil {
+		return nil
+	}
+	resp, err := stream.Recv()
+	if err != nil || resp.GetListServicesResponse() == nil {
+		return nil
+	}
+	var services []string
+	for _, s := range resp.GetListServicesResponse().GetService() {
+		name := s.GetName()
+		if strings.HasPrefix(name, "grpc.reflection.") || strings.HasPrefix(name, "grpc.health.") {
+			continue
+		}
+		services = append(services, name)
+	}
+	detail := fmt.Sprintf("gRPC reflection at %s", address)
+	if len(services) > 0 {
+		detail += " (" + strings.Join(services, ", ") + ")"
+	}
+	return &UpstreamCandidate{Kind: UpstreamKindGRPC, Address: address, TLS: useTLS, Detail: detail}
+}
+
+// ServiceConfig returns a ready-to-run service for the upstream.
+//
+// Summary: Builds the service configuration of a discovered upstream.
+//
+// Parameters:
+//   - name: string. The name of the service.
+//   - auth: *configv1.Authentication. The credentials sent to the upstream, or nil.
+//
+// Returns:
+//   - *configv1.UpstreamServiceConfig: The service.
+func (c *UpstreamCandidate) ServiceConfig(name string, auth *configv1.Authentication) *configv1.UpstreamServiceConfig {
+	svc := configv1.UpstreamServiceConfig_builder{
+		Name:         proto.String(name),
+		UpstreamAuth: auth,
+	}
+	switch c.Kind {
+	case UpstreamKindMCP:
+		svc.McpService = configv1.McpUpstreamService_builder{
+			HttpConnection: configv1.McpStreamableHttpConnection_builder{
+				HttpAddress: proto.String(c.Address),
+			}.Build(),
+			ToolAutoDiscovery: proto.Bool(true),
+		}.Build()
+	case UpstreamKindOpenAPI:
+		svc.OpenapiService = configv1.OpenapiUpstreamService_builder{
+			Address: proto.String(c.Address),
+			SpecUrl: proto.String(c.SpecURL),
+		}.Build()
+	case UpstreamKindGRPC:
+		grpcService := configv1.GrpcUpstreamService_builder{
+			Address:       proto.String(c.Address),
+			UseReflection: proto.Bool(true),
+		}
+		if c.TLS {
+			grpcService.TlsConfig = &configv1.TLSConfig{}
+		}
+		svc.GrpcService = grpcService.Build()
+	}
+	return svc.Build()
+}
+
+// generateDiscoveredService probes a base URL, lets the user pick one of the
+// upstreams found, and prompts for its name and credentials.
+func (g *Generator) generateDiscoveredService(baseURL string) ([]byte, error) {
+	var err error
+	if baseURL == "" {
+		baseURL, err = g.prompt("🔗 Enter the base URL of the upstream: ")
+		if err != nil {
+			return nil, err
+		}
+	}
+	base, err := parseBaseURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("🔍 Probing %s for MCP, OpenAPI and gRPC reflection...\n", base)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*discoveryProbeTimeout)
+	defer cancel()
+	discovery, err := DiscoverUpstream(ctx, base.String(), g.HTTPClient)
+	if err != nil {
+		return nil, err
+	}
+	if len(discovery.Candidates) == 0 {
+		if discovery.AuthRequired {
+			return nil, fmt.Errorf("no MCP endpoint, OpenAPI spec or gRPC reflection found at %s: the upstream refused the probes with 401 or 403, so enter a service type instead", base)
+		}
+		return nil, fmt.Errorf("no MCP endpoint, OpenAPI spec or gRPC reflection found at %s: enter a service type instead", base)
+	}
+
+	candidate := discovery.Candidates[0]
+	for i, c := range discovery.Candidates {
+		fmt.Printf("  %d. %s\n", i+1, c.Detail)
+	}
+	if len(discovery.Candidates) > 1 {
+		choices := make([]string, len(discovery.Candidates))
+		for i := range discovery.Candidates {
+			choices[i] = fmt.Sprint(i + 1)
+		}
+		choice, err := g.promptChoice("🧭 Which one should the service use?", choices, "1")
+		if err != nil {
+			return nil, err
+		}
+		for i, c := range choices {
+			if c == choice {
+				candidate = discovery.Candidates[i]
+			}
+		}
+	}
+
+	name, err := g.promptDefault("🏷️  Enter service name", defaultServiceName(base))
+	if err != nil {
+		return nil, err
+	}
+	if discovery.AuthRequired {
+		fmt.Println("🔒 The upstream refused some probes with 401 or 403: it probably requires credentials.")
+	}
+	auth, err := g.promptAuth(name, discovery.AuthRequired)
+	if err != nil {
+		return nil, err
+	}
+	return marshalServiceYAML(candidate.ServiceConfig(name, auth))
+}
+
+// promptAuth asks whether the upstream requires credentials and, if so, how
+// they are sent. The secrets are read from environment variables, so the
+// configuration holds none.
+func (g *Generator) promptAuth(serviceName string, required bool) (*configv1.Authentication, error) {
+	ok, err := g.promptBool("🔐 Does the upstream require authentication", required)
+	if err != nil || !ok {
+		return nil, err
+	}
+	kind, err := g.promptChoice("🔑 Authentication type", []string{"api_key", "bearer", "basic"}, "bearer")
+	if err != nil {
+		return nil, err
+	}
+	envPrefix := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(serviceName))
+	fromEnv := func(prompt, def string) (*configv1.SecretValue, error) {
+		name, err := g.promptDefault(prompt, def)
+		if err != nil {
+			return nil, err
+		}
+		return configv1.SecretValue_builder{EnvironmentVariable: proto.String(name)}.Build(), nil
+	}
+
+	switch kind {
+	case "api_key":
+		header, err := g.promptDefault("📨 Enter the API key header", "X-API-Key")
+		if err != nil {
+			return nil, err
+		}
+		value, err := fromEnv("🌱 Enter the environment variable holding the API key", envPrefix+"_API_KEY")
+		if err != nil {
+			return nil, err
+		}
+		return configv1.Authentication_builder{
+			ApiKey: configv1.APIKeyAuth_builder{
+				ParamName: proto.String(header),
+				In:        configv1.APIKeyAuth_HEADER.Enum(),
+				Value:     value,
+			}.Build(),
+		}.Build(), nil
+	case "basic":
+		username, err := g.prompt("👤 Enter the username: ")
+		if err != nil {
+			return nil, err
+		}
+		password, err := fromEnv("🌱 Enter the environment variable holding the password", envPrefix+"_PASSWORD")
+		if err != nil {
+			return nil, err
+		}
+		return configv1.Authentication_builder{
+			BasicAuth: configv1.BasicAuth_builder{
+				Username: proto.String(username),
+				Password: password,
+			}.Build(),
+		}.Build(), nil
+	default:
+		token, err := fromEnv("🌱 Enter the environment variable holding the token", envPrefix+"_TOKEN")
+		if err != nil {
+			return nil, err
+		}
+		return configv1.Authentication_builder{
+			BearerToken: configv1.BearerTokenAuth_builder{Token: token}.Build(),
+		}.Build(), nil
+	}
+}
+
+// promptDefault prompts for a value, which defaults to def.
+func (g *Generator) promptDefault(prompt, def string) (string, error) {
+	input, err := g.prompt(fmt.Sprintf("%s [%s]: ", prompt, def))
+	if err != nil {
+		return "", err
+	}
+	if input == "" {
+		return def, nil
+	}
+	return input, nil
+}
+
+// promptChoice prompts for one of choices, which defaults to def, until the
+// input is valid.
+func (g *Generator) promptChoice(prompt string, choices []string, def string) (string, error) {
+	for {
+		input, err := g.promptDefault(fmt.Sprintf("%s (%s)", prompt, strings.Join(choices, ", ")), def)
+		if err != nil {
+			return "", err
+		}
+		for _, c := range choices {
+			if strings.EqualFold(input, c) {
+				return c, nil
+			}
+		}
+		fmt.Printf("❌ Invalid input. Please enter one of: %s.\n", strings.Join(choices, ", "))
+	}
+}
+
+// defaultServiceName derives a service name from the host of a base URL,
+// e.g. "api-example-com" for https://api.example.com.
+func defaultServiceName(base *url.URL) string {
+	return strings.NewReplacer(".", "-", ":", "-").Replace(base.Host)
+}
+
+// marshalServiceYAML renders a service as a configuration file.
+func marshalServiceYAML(svc *configv1.UpstreamServiceConfig) ([]byte, error) {
+	cfg := configv1.McpAnyServerConfig_builder{
+		UpstreamServices: []*configv1.UpstreamServiceConfig{svc},
+	}.Build()
+	jsonData, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return sigsyaml.JSONToYAML(jsonData)
+}
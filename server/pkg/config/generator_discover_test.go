@@ -0,0 +1,212 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+const discoverySpec = `{"openapi": "3.0.3", "info": {"title": "Weather", "version": "1.0"}, "paths": {}}`
+
+const discoveryInitializeResult = `{"jsonrpc":"2.0","id":1,"result":{"protocolVersion":"2025-06-18","capabilities":{},"serverInfo":{"name":"weather-mcp","version":"0.3.0"}}}`
+
+func TestDiscoverUpstream_OpenAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == "/api/v3/api-docs" {
+			_, _ = w.Write([]byte(discoverySpec))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	d, err := DiscoverUpstream(context.Background(), server.URL+"/api/", nil)
+	require.NoError(t, err)
+	require.Len(t, d.Candidates, 1)
+	c := d.Candidates[0]
+	assert.Equal(t, UpstreamKindOpenAPI, c.Kind)
+	assert.Equal(t, server.URL+"/api", c.Address)
+	assert.Equal(t, server.URL+"/api/v3/api-docs", c.SpecURL)
+	assert.Contains(t, c.Detail, `"Weather"`)
+	assert.False(t, d.AuthRequired)
+
+	// The base URL may be the spec itself.
+	d, err = DiscoverUpstream(context.Background(), server.URL+"/api/v3/api-docs", nil)
+	require.NoError(t, err)
+	require.Len(t, d.Candidates, 1)
+	assert.Equal(t, server.URL, d.Candidates[0].Address)
+	assert.Equal(t, server.URL+"/api/v3/api-docs", d.Candidates[0].SpecURL)
+}
+
+func TestDiscoverUpstream_MCP(t *testing.T) {
+	var closed atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/mcp" && r.Method == http.MethodPost:
+			w.Header().Set("Mcp-Session-Id", "s1")
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprintf(w, "event: message\ndata: %s\n\n", discoveryInitializeResult)
+		case r.URL.Path == "/mcp" && r.Method == http.MethodDelete:
+			closed.Store(r.Header.Get("Mcp-Session-Id") == "s1")
+		case r.URL.Path == "/openapi.json":
+			_, _ = w.Write([]byte(discoverySpec))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	d, err := DiscoverUpstream(context.Background(), server.URL, nil)
+	require.NoError(t, err)
+	require.Len(t, d.Candidates, 2)
+	assert.Equal(t, UpstreamKindMCP, d.Candidates[0].Kind, "MCP comes first")
+	assert.Equal(t, server.URL+"/mcp", d.Candidates[0].Address)
+	assert.Contains(t, d.Candidates[0].Detail, "weather-mcp 0.3.0")
+	assert.Equal(t, UpstreamKindOpenAPI, d.Candidates[1].Kind)
+	assert.True(t, closed.Load(), "the probe closes its session")
+}
+
+func TestDiscoverUpstream_GRPC(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	reflection.Register(server)
+	go func() { _ = server.Serve(l) }()
+	defer server.Stop()
+
+	d, err := DiscoverUpstream(context.Background(), l.Addr().String(), nil)
+	require.NoError(t, err)
+	require.Len(t, d.Candidates, 1)
+	c := d.Candidates[0]
+	assert.Equal(t, UpstreamKindGRPC, c.Kind)
+	assert.Equal(t, l.Addr().String(), c.Address)
+	assert.False(t, c.TLS)
+	assert.NotContains(t, c.Detail, "grpc.reflection", "the reflection service is not listed")
+}
+
+func TestDiscoverUpstream_AuthRequired(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	d, err := DiscoverUpstream(context.Background(), server.URL, nil)
+	require.NoError(t, err)
+	assert.Empty(t, d.Candidates)
+	assert.True(t, d.AuthRequired)
+}
+
+func TestParseBaseURL(t *testing.T) {
+	u, err := parseBaseURL("localhost:50051")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:50051", u.String())
+
+	u, err = parseBaseURL("https://api.example.com/v1/?x=1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.example.com/v1", u.String())
+	assert.Equal(t, "api-example-com", defaultServiceName(u))
+
+	_, err = parseBaseURL("ftp://example.com")
+	assert.ErrorContains(t, err, "unsupported scheme")
+	_, err = parseBaseURL("http://")
+	assert.ErrorContains(t, err, "missing host")
+}
+
+func TestGenerator_Discover(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(discoveryInitializeResult))
+			return
+		}
+		if r.URL.Path == "/openapi.json" {
+			_, _ = w.Write([]byte(discoverySpec))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	load := func(t *testing.T, b []byte) *configv1.UpstreamServiceConfig {
+		t.Helper()
+		cfg := &configv1.McpAnyServerConfig{}
+		require.NoError(t, (&yamlEngine{}).Unmarshal(b, cfg), string(b))
+		require.Len(t, cfg.GetUpstreamServices(), 1)
+		return cfg.GetUpstreamServices()[0]
+	}
+	generate := func(inputs ...string) ([]byte, error) {
+		g := &Generator{Reader: bufio.NewReader(bytes.NewBufferString(strings.Join(inputs, "\n") + "\n"))}
+		return g.Generate()
+	}
+
+	t.Run("mcp with bearer token", func(t *testing.T) {
+		out, err := generate(server.URL, "", "", "y", "", "")
+		require.NoError(t, err)
+		svc := load(t, out)
+		assert.Equal(t, defaultServiceName(mustParseBaseURL(t, server.URL)), svc.GetName())
+		assert.Equal(t, server.URL, svc.GetMcpService().GetHttpConnection().GetHttpAddress())
+		assert.True(t, svc.GetMcpService().GetToolAutoDiscovery())
+		envVar := strings.ToUpper(strings.ReplaceAll(svc.GetName(), "-", "_")) + "_TOKEN"
+		assert.Equal(t, envVar, svc.GetUpstreamAuth().GetBearerToken().GetToken().GetEnvironmentVariable())
+	})
+
+	t.Run("openapi with api key", func(t *testing.T) {
+		out, err := generate("discover", server.URL, "2", "weather", "yes", "api_key", "X-Weather-Key", "WEATHER_KEY")
+		require.NoError(t, err)
+		svc := load(t, out)
+		assert.Equal(t, "weather", svc.GetName())
+		assert.Equal(t, server.URL, svc.GetOpenapiService().GetAddress())
+		assert.Equal(t, server.URL+"/openapi.json", svc.GetOpenapiService().GetSpecUrl())
+		apiKey := svc.GetUpstreamAuth().GetApiKey()
+		assert.Equal(t, "X-Weather-Key", apiKey.GetParamName())
+		assert.Equal(t, "WEATHER_KEY", apiKey.GetValue().GetEnvironmentVariable())
+		assert.NotContains(t, string(out), "plain_text", "no secret is written")
+	})
+
+	t.Run("no auth", func(t *testing.T) {
+		out, err := generate(server.URL, "1", "weather", "n")
+		require.NoError(t, err)
+		assert.False(t, load(t, out).HasUpstreamAuth())
+	})
+
+	t.Run("nothing found", func(t *testing.T) {
+		empty := httptest.NewServer(http.NotFoundHandler())
+		defer empty.Close()
+		_, err := generate(empty.URL)
+		assert.ErrorContains(t, err, "no MCP endpoint, OpenAPI spec or gRPC reflection found")
+	})
+}
+
+func TestUpstreamCandidate_ServiceConfig_GRPCTLS(t *testing.T) {
+	c := UpstreamCandidate{Kind: UpstreamKindGRPC, Address: "api.example.com:443", TLS: true}
+	svc := c.ServiceConfig("api", nil)
+	assert.True(t, svc.GetGrpcService().GetUseReflection())
+	assert.True(t, svc.GetGrpcService().HasTlsConfig())
+	out, err := marshalServiceYAML(svc)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "tls_config: {}")
+}
+
+func mustParseBaseURL(t *testing.T, s string) *url.URL {
+	t.Helper()
+	u, err := parseBaseURL(s)
+	require.NoError(t, err)
+	return u
+}
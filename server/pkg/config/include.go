@@ -0,0 +1,221 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/spf13/afero"
+)
+
+// includeLoader loads a config with the files it includes, recursively.
+type includeLoader struct {
+	store *FileStore
+	// stack is the chain of includes being loaded, to detect cycles.
+	stack []string
+	// loaded holds the configs loaded, to include each one once.
+	loaded map[string]bool
+}
+
+func newIncludeLoader(store *FileStore) *includeLoader {
+	return &includeLoader{store: store, loaded: make(map[string]bool)}
+}
+
+// load loads the config at the path and merges the files it includes, in
+// order, before it. A config included earlier is not included again.
+func (l *includeLoader) load(ctx context.Context, path string) (*configv1.McpAnyServerConfig, error) {
+	key := includeKey(path)
+	if i := slices.Index(l.stack, key); i >= 0 {
+		chain := append(append([]string{}, l.stack[i:]...), key)
+		return nil, fmt.Errorf("include cycle: %s", strings.Join(chain, " -> "))
+	}
+	if l.loaded[key] {
+		return nil, nil
+	}
+	l.loaded[key] = true
+	l.stack = append(l.stack, key)
+	defer func() { l.stack = l.stack[:len(l.stack)-1] }()
+
+	cfg, err := l.store.loadOneConfig(ctx, path)
+	if err != nil || cfg == nil || len(cfg.GetInclude()) == 0 {
+		return cfg, err
+	}
+	includes := cfg.GetInclude()
+	cfg.SetInclude(nil)
+
+	var configs []*configv1.McpAnyServerConfig
+	for _, include := range includes {
+		paths, err := l.store.resolveInclude(path, include)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include %q in %s: %w", include, path, err)
+		}
+		for _, p := range paths {
+			included, err := l.load(ctx, p)
+			if err != nil {
+				return nil, err
+			}
+			configs = append(configs, included)
+		}
+	}
+	return mergeConfigs(append(configs, cfg)), nil
+}
+
+// included returns the keys of the configs loaded by includes, without the
+// ones of the roots.
+func (l *includeLoader) included(roots ...string) []string {
+	var keys []string
+	for key := range l.loaded {
+		if !slices.ContainsFunc(roots, func(root string) bool { return includeKey(root) == key }) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// includeKey identifies a config for cycle detection: the URL, or the
+// absolute path of a file.
+func includeKey(path string) string {
+	if IsRemotePath(path) {
+		return path
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// resolveInclude returns the configs an include of the config at the path
+// refers to:
+//   - A URL, or a path relative to the URL of the including config.
+//   - A file, a directory scanned recursively, or a glob, relative to the
+//     directory of the including config. Globs and directories are sorted.
+func (s *FileStore) resolveInclude(from, include string) ([]string, error) {
+	if include == "" {
+		return nil, errors.New("empty include")
+	}
+	if isURL(from) {
+		// A remote config cannot read local files or key-value stores.
+		if isURL(include) {
+			return []string{include}, nil
+		}
+		if IsRemotePath(include) || filepath.IsAbs(include) || strings.ContainsAny(include, "*[") {
+			return nil, errors.New("a config loaded from a URL can only include URLs and paths relative to it")
+		}
+		base, err := url.Parse(from)
+		if err != nil {
+			return nil, err
+		}
+		ref, err := url.Parse(include)
+		if err != nil {
+			return nil, err
+		}
+		return []string{base.ResolveReference(ref).String()}, nil
+	}
+	if IsRemotePath(include) {
+		return []string{include}, nil
+	}
+	if IsRemotePath(from) {
+		return nil, errors.New("a config loaded from a key-value store can only include URLs")
+	}
+
+	path := include
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(from), path)
+	}
+	if strings.ContainsAny(path, "*?[") {
+		matches, err := afero.Glob(s.fs, path)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(matches)
+		var files []string
+		for _, match := range matches {
+			if info, err := s.fs.Stat(match); err == nil && !info.IsDir() {
+				if _, err := NewEngine(match); err == nil {
+					files = append(files, match)
+				}
+			}
+		}
+		return files, nil
+	}
+
+	info, err := s.fs.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	var files []string
+	err = afero.Walk(s.fs, path, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() {
+			if _, err := NewEngine(p); err == nil {
+				files = append(files, p)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// includeRegistry holds the local files included by the configs loaded so far, for
+// the watchers.
+var includeRegistry = struct {
+	sync.Mutex
+	files    map[string]bool
+	handlers map[int]func(path string)
+	next     int
+}{files: make(map[string]bool), handlers: make(map[int]func(string))}
+
+// recordIncludes notes the local files included by a config, and notifies the
+// subscribers of the new ones.
+func recordIncludes(keys []string) {
+	includeRegistry.Lock()
+	defer includeRegistry.Unlock()
+	for _, key := range keys {
+		if IsRemotePath(key) || includeRegistry.files[key] {
+			continue
+		}
+		includeRegistry.files[key] = true
+		for _, handler := range includeRegistry.handlers {
+			handler(key)
+		}
+	}
+}
+
+// subscribeIncludes calls handler with the absolute path of each local file
+// included by the configs loaded so far, and then by the ones loaded until it
+// unsubscribes.
+func subscribeIncludes(handler func(path string)) (unsubscribe func()) {
+	includeRegistry.Lock()
+	defer includeRegistry.Unlock()
+	for file := range includeRegistry.files {
+		handler(file)
+	}
+	id := includeRegistry.next
+	includeRegistry.next++
+	includeRegistry.handlers[id] = handler
+	return func() {
+		includeRegistry.Lock()
+		defer includeRegistry.Unlock()
+		delete(includeRegistry.handlers, id)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serviceNames(cfg *configv1.McpAnyServerConfig) []string {
+	var names []string
+	for _, svc := range cfg.GetUpstreamServices() {
+		names = append(names, svc.GetName())
+	}
+	return names
+}
+
+func TestFileStore_Include(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/etc/mcpany/config.yaml", []byte(`
+include:
+  - teams/*.yaml
+  - shared
+global_settings:
+  log_level: warn
+upstream_services:
+  - name: root
+    http_service:
+      address: http://root.example.com
+`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/etc/mcpany/teams/b.yaml", []byte(serviceDoc("team-b")), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/etc/mcpany/teams/a.yaml", []byte("include: [../shared/common.yaml]\n"+serviceDoc("team-a")), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/etc/mcpany/teams/notes.txt", []byte("not a config"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/etc/mcpany/shared/common.yaml", []byte(serviceDoc("common")), 0o644))
+
+	for name, paths := range map[string][]string{
+		"file":      {"/etc/mcpany/config.yaml"},
+		"directory": {"/etc/mcpany"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := NewFileStore(fs, paths).Load(context.Background())
+			require.NoError(t, err)
+			// The includes come first, in order, and each file is merged once,
+			// even when the directory given holds the included files too.
+			assert.Equal(t, []string{"common", "team-a", "team-b", "root"}, serviceNames(cfg))
+			assert.Empty(t, cfg.GetInclude())
+			assert.Equal(t, configv1.GlobalSettings_LOG_LEVEL_WARN, cfg.GetGlobalSettings().GetLogLevel())
+		})
+	}
+}
+
+func TestFileStore_IncludeErrors(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/cfg/a.yaml", []byte("include: [b.yaml]\n"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/cfg/b.yaml", []byte("include: [sub/c.yaml]\n"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/cfg/sub/c.yaml", []byte("include: [../a.yaml]\n"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/cfg/missing.yaml", []byte("include: [nope.yaml]\n"), 0o644))
+
+	_, err := NewFileStore(fs, []string{"/cfg/a.yaml"}).Load(context.Background())
+	abs := func(p string) string {
+		a, _ := filepath.Abs(p)
+		return a
+	}
+	assert.EqualError(t, err, "include cycle: "+abs("/cfg/a.yaml")+" -> "+abs("/cfg/b.yaml")+" -> "+abs("/cfg/sub/c.yaml")+" -> "+abs("/cfg/a.yaml"))
+
+	_, err = NewFileStore(fs, []string{"/cfg/missing.yaml"}).Load(context.Background())
+	assert.ErrorContains(t, err, `invalid include "nope.yaml" in /cfg/missing.yaml`)
+}
+
+func TestResolveInclude_Remote(t *testing.T) {
+	s := NewFileStore(afero.NewMemMapFs(), nil)
+
+	paths, err := s.resolveInclude("https://example.com/mcpany/config.yaml", "teams/a.yaml?v=1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com/mcpany/teams/a.yaml?v=1"}, paths)
+
+	paths, err = s.resolveInclude("/etc/config.yaml", "https://example.com/shared.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com/shared.yaml"}, paths)
+
+	for _, include := range []string{"/etc/passwd", "teams/*.yaml", "etcd://localhost:2379/mcpany/"} {
+		_, err = s.resolveInclude("https://example.com/config.yaml", include)
+		assert.EqualError(t, err, "a config loaded from a URL can only include URLs and paths relative to it", include)
+	}
+	_, err = s.resolveInclude("consul://localhost:8500/mcpany/", "services.yaml")
+	assert.EqualError(t, err, "a config loaded from a key-value store can only include URLs")
+}
+
+func TestFileStore_IncludeURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/config.yaml":
+			_, _ = w.Write([]byte("include: [services.yaml]\n"))
+		case "/services.yaml":
+			_, _ = w.Write([]byte(serviceDoc("remote")))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	originalClient := httpClient
+	t.Cleanup(func() { httpClient = originalClient })
+	httpClient = server.Client()
+
+	cfg, err := NewFileStore(afero.NewMemMapFs(), []string{server.URL + "/config.yaml"}).Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"remote"}, serviceNames(cfg))
+}
+
+func TestSubscribeIncludes(t *testing.T) {
+	recordIncludes([]string{"/tmp/include-test/a.yaml", "https://example.com/ignored.yaml"})
+	var got []string
+	unsubscribe := subscribeIncludes(func(path string) { got = append(got, path) })
+	assert.Contains(t, got, "/tmp/include-test/a.yaml")
+	assert.NotContains(t, got, "https://example.com/ignored.yaml")
+
+	recordIncludes([]string{"/tmp/include-test/b.yaml", "/tmp/include-test/a.yaml"})
+	assert.Equal(t, "/tmp/include-test/b.yaml", got[len(got)-1])
+	count := len(got)
+
+	unsubscribe()
+	recordIncludes([]string{"/tmp/include-test/c.yaml"})
+	assert.Len(t, got, count)
+}
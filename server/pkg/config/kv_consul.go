@@ -0,0 +1,117 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// consulWaitTime is how long a blocking query of Consul waits for a change.
+const consulWaitTime = 5 * time.Minute
+
+// consulSource reads config documents from the KV store of Consul.
+type consulSource struct {
+	endpoint   *url.URL
+	prefix     string
+	datacenter string
+}
+
+type consulKeyValue struct {
+	Key   string `json:"Key"`
+	Value string `json:"Value"`
+}
+
+// List returns the documents under the prefix and the index of the store.
+func (s *consulSource) List(ctx context.Context) (map[string][]byte, uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, kvRequestTimeout)
+	defer cancel()
+	return s.query(ctx, 0)
+}
+
+// Watch runs blocking queries until the index of the prefix moves past the
+// revision.
+func (s *consulSource) Watch(ctx context.Context, revision uint64) (uint64, error) {
+	for {
+		ctx, cancel := context.WithTimeout(ctx, consulWaitTime+kvRequestTimeout)
+		_, index, err := s.query(ctx, revision)
+		cancel()
+		switch {
+		case err != nil:
+			return 0, err
+		case index < revision:
+			// The index went backwards, e.g. after a restore: relist.
+			return 0, nil
+		case index > revision:
+			return index, nil
+		}
+	}
+}
+
+// query reads the keys under the prefix. With an index, it blocks until the
+// index changes or the wait time elapses.
+func (s *consulSource) query(ctx context.Context, index uint64) (map[string][]byte, uint64, error) {
+	u := s.endpoint.JoinPath("/v1/kv", s.prefix)
+	q := url.Values{"recurse": {"true"}}
+	if s.datacenter != "" {
+		q.Set("dc", s.datacenter)
+	}
+	if index > 0 {
+		q.Set("index", strconv.FormatUint(index, 10))
+		q.Set("wait", consulWaitTime.String())
+	}
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if token := os.Getenv("CONSUL_HTTP_TOKEN"); token != "" {
+		req.Header.Set("X-Consul-Token", token)
+	}
+	resp, err := kvHTTPClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	newIndex, err := strconv.ParseUint(resp.Header.Get("X-Consul-Index"), 10, 64)
+	if err != nil && resp.StatusCode == http.StatusOK {
+		return nil, 0, fmt.Errorf("invalid consul index %q", resp.Header.Get("X-Consul-Index"))
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		// No key under the prefix.
+		return map[string][]byte{}, newIndex, nil
+	}
+	body, err := readKVResponse(resp)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var kvs []consulKeyValue
+	if err := json.Unmarshal(body, &kvs); err != nil {
+		return nil, 0, fmt.Errorf("invalid consul response: %w", err)
+	}
+	docs := make(map[string][]byte, len(kvs))
+	for _, kv := range kvs {
+		if strings.HasSuffix(kv.Key, "/") {
+			// A folder.
+			continue
+		}
+		value, err := base64.StdEncoding.DecodeString(kv.Value)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid value of consul key %s: %w", kv.Key, err)
+		}
+		docs[kv.Key] = value
+	}
+	return docs, newIndex, nil
+}
@@ -0,0 +1,199 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// etcdSource reads config documents from etcd through the JSON gateway of
+// its v3 API.
+type etcdSource struct {
+	endpoint *url.URL
+	prefix   string
+	username string
+	password string
+}
+
+type etcdHeader struct {
+	Revision string `json:"revision"`
+}
+
+type etcdKeyValue struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+type etcdRangeResponse struct {
+	Header etcdHeader     `json:"header"`
+	Kvs    []etcdKeyValue `json:"kvs"`
+}
+
+type etcdWatchResponse struct {
+	Result *struct {
+		Header          etcdHeader        `json:"header"`
+		Canceled        bool              `json:"canceled"`
+		CancelReason    string            `json:"cancel_reason"`
+		CompactRevision string            `json:"compact_revision"`
+		Events          []json.RawMessage `json:"events"`
+	} `json:"result"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// List returns the documents under the prefix and the revision of the store.
+func (s *etcdSource) List(ctx context.Context) (map[string][]byte, uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, kvRequestTimeout)
+	defer cancel()
+
+	key, rangeEnd := s.keyRange()
+	resp, err := s.post(ctx, "/v3/kv/range", map[string][]byte{"key": key, "range_end": rangeEnd})
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := readKVResponse(resp)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var result etcdRangeResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, 0, fmt.Errorf("invalid etcd range response: %w", err)
+	}
+	revision, err := strconv.ParseUint(result.Header.Revision, 10, 64)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid etcd revision %q", result.Header.Revision)
+	}
+	docs := make(map[string][]byte, len(result.Kvs))
+	for _, kv := range result.Kvs {
+		docs[string(kv.Key)] = kv.Value
+	}
+	return docs, revision, nil
+}
+
+// Watch streams the events under the prefix after the revision, and returns
+// at the first one.
+func (s *etcdSource) Watch(ctx context.Context, revision uint64) (uint64, error) {
+	key, rangeEnd := s.keyRange()
+	resp, err := s.post(ctx, "/v3/watch", map[string]any{
+		"create_request": map[string]any{
+			"key":            key,
+			"range_end":      rangeEnd,
+			"start_revision": strconv.FormatUint(revision+1, 10),
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		_, err := readKVResponse(resp)
+		return 0, err
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var msg etcdWatchResponse
+		if err := decoder.Decode(&msg); err != nil {
+			return 0, fmt.Errorf("etcd watch stream closed: %w", err)
+		}
+		switch {
+		case msg.Error != nil:
+			return 0, fmt.Errorf("etcd watch failed: %s", msg.Error.Message)
+		case msg.Result == nil:
+			continue
+		case msg.Result.Canceled && msg.Result.CompactRevision != "" && msg.Result.CompactRevision != "0":
+			// The revision was compacted: changes may have been missed.
+			return 0, nil
+		case msg.Result.Canceled:
+			return 0, fmt.Errorf("etcd watch canceled: %s", msg.Result.CancelReason)
+		case len(msg.Result.Events) > 0:
+			next, err := strconv.ParseUint(msg.Result.Header.Revision, 10, 64)
+			if err != nil {
+				return 0, nil
+			}
+			return next, nil
+		}
+	}
+}
+
+// keyRange returns the range of the keys under the prefix.
+func (s *etcdSource) keyRange() (key, rangeEnd []byte) {
+	if s.prefix == "" {
+		// From the first key to the end of the keyspace.
+		return []byte{0}, []byte{0}
+	}
+	end := []byte(s.prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return []byte(s.prefix), end[:i+1]
+		}
+	}
+	return []byte(s.prefix), []byte{0}
+}
+
+// post sends a request to the gateway, authenticated if the source has
+// credentials.
+func (s *etcdSource) post(ctx context.Context, path string, payload any) (*http.Response, error) {
+	var token string
+	if s.username != "" {
+		var err error
+		if token, err = s.authenticate(ctx); err != nil {
+			return nil, err
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint.JoinPath(path).String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+	return kvHTTPClient.Do(req)
+}
+
+func (s *etcdSource) authenticate(ctx context.Context) (string, error) {
+	body, err := json.Marshal(map[string]string{"name": s.username, "password": s.password})
+	if err != nil {
+		return "", err
+	}
+	authCtx, cancel := context.WithTimeout(ctx, kvRequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(authCtx, http.MethodPost, s.endpoint.JoinPath("/v3/auth/authenticate").String(), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := kvHTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, err := readKVResponse(resp)
+	if err != nil {
+		return "", fmt.Errorf("etcd authentication failed: %w", err)
+	}
+	var result struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil || result.Token == "" {
+		return "", errors.New("etcd authentication failed: no token in response")
+	}
+	return result.Token, nil
+}
@@ -0,0 +1,326 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Resources of a Kubernetes config source.
+const (
+	kubeUpstreamServices = "upstreamservices"
+	kubeConfigMaps       = "configmaps"
+)
+
+const (
+	// kubeUpstreamServiceAPI is the API group and version of the
+	// UpstreamService custom resources.
+	kubeUpstreamServiceAPI = "/apis/mcp.any/v1alpha1"
+	// kubeWatchTimeout is how long the API server keeps a watch open before
+	// it is renewed.
+	kubeWatchTimeout = 5 * time.Minute
+)
+
+// kubeServiceAccountDir holds the credentials of the pod service account.
+// It is a variable for tests.
+var kubeServiceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// kubeClient calls the Kubernetes API server with the pod service account.
+type kubeClient struct {
+	server *url.URL
+	client *http.Client
+}
+
+func newKubeClient() (*kubeClient, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, errors.New("not running in a Kubernetes cluster: KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT are not set")
+	}
+	ca, err := os.ReadFile(filepath.Join(kubeServiceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the Kubernetes CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("invalid Kubernetes CA")
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	return &kubeClient{
+		server: &url.URL{Scheme: "https", Host: net.JoinHostPort(host, port)},
+		client: &http.Client{Transport: transport},
+	}, nil
+}
+
+// do sends a request to the API server. The token is read for each request,
+// as the kubelet rotates it.
+func (c *kubeClient) do(ctx context.Context, method, apiPath string, query url.Values, body []byte) (*http.Response, error) {
+	token, err := os.ReadFile(filepath.Join(kubeServiceAccountDir, "token"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the service account token: %w", err)
+	}
+	u := c.server.JoinPath(apiPath)
+	u.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	req.Header.Set("Accept", "application/json")
+	if method == http.MethodPatch {
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+	}
+	return c.client.Do(req)
+}
+
+type kubeObjectMeta struct {
+	Name            string `json:"name"`
+	ResourceVersion string `json:"resourceVersion"`
+	Generation      int64  `json:"generation"`
+}
+
+// kubeServiceStatus is the status of an UpstreamService resource.
+type kubeServiceStatus struct {
+	Ready              bool   `json:"ready"`
+	Message            string `json:"message"`
+	ObservedGeneration int64  `json:"observedGeneration"`
+}
+
+// kubeObject is a ConfigMap or an UpstreamService resource.
+type kubeObject struct {
+	Metadata kubeObjectMeta    `json:"metadata"`
+	Data     map[string]string `json:"data"`
+	Spec     map[string]any    `json:"spec"`
+	Status   kubeServiceStatus `json:"status"`
+}
+
+type kubeList struct {
+	Metadata struct {
+		ResourceVersion string `json:"resourceVersion"`
+	} `json:"metadata"`
+	Items []kubeObject `json:"items"`
+}
+
+type kubeWatchEvent struct {
+	Type   string          `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+// kubernetesSource reads config documents from the UpstreamService resources
+// or the ConfigMaps of a namespace. Each UpstreamService defines a service,
+// and each key of a ConfigMap is a document, as in a config directory.
+type kubernetesSource struct {
+	client    *kubeClient
+	namespace string
+	resource  string
+	selector  string
+	// generations holds the generation of the listed resources. Changes of
+	// their status or metadata, which leave it unchanged, are not reported.
+	generations map[string]int64
+}
+
+func newKubernetesSource(u *url.URL) (*kubernetesSource, error) {
+	resource := strings.Trim(u.Path, "/")
+	if resource != kubeUpstreamServices && resource != kubeConfigMaps {
+		return nil, fmt.Errorf("invalid Kubernetes source %q: the path must be /%s or /%s", u.Redacted(), kubeUpstreamServices, kubeConfigMaps)
+	}
+	namespace := u.Host
+	if namespace == "" {
+		b, err := os.ReadFile(filepath.Join(kubeServiceAccountDir, "namespace"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid Kubernetes source %q: no namespace, and failed to read the pod namespace: %w", u.Redacted(), err)
+		}
+		namespace = strings.TrimSpace(string(b))
+	}
+	client, err := newKubeClient()
+	if err != nil {
+		return nil, err
+	}
+	return &kubernetesSource{
+		client:    client,
+		namespace: namespace,
+		resource:  resource,
+		selector:  u.Query().Get("selector"),
+	}, nil
+}
+
+func (s *kubernetesSource) apiPath() string {
+	if s.resource == kubeConfigMaps {
+		return path.Join("/api/v1/namespaces", s.namespace, kubeConfigMaps)
+	}
+	return path.Join(kubeUpstreamServiceAPI, "namespaces", s.namespace, kubeUpstreamServices)
+}
+
+// list returns the resources of the source.
+func (s *kubernetesSource) list(ctx context.Context) (*kubeList, error) {
+	ctx, cancel := context.WithTimeout(ctx, kvRequestTimeout)
+	defer cancel()
+	query := url.Values{}
+	if s.selector != "" {
+		query.Set("labelSelector", s.selector)
+	}
+	resp, err := s.client.do(ctx, http.MethodGet, s.apiPath(), query, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := readKVResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	var list kubeList
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("invalid Kubernetes %s list: %w", s.resource, err)
+	}
+	return &list, nil
+}
+
+// List returns the documents of the resources and their resource version.
+func (s *kubernetesSource) List(ctx context.Context) (map[string][]byte, uint64, error) {
+	list, err := s.list(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	revision, err := strconv.ParseUint(list.Metadata.ResourceVersion, 10, 64)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unsupported Kubernetes resource version %q", list.Metadata.ResourceVersion)
+	}
+	docs := make(map[string][]byte)
+	s.generations = make(map[string]int64, len(list.Items))
+	for _, item := range list.Items {
+		s.generations[item.Metadata.Name] = item.Metadata.Generation
+		if err := s.addDocuments(docs, item); err != nil {
+			return nil, 0, err
+		}
+	}
+	return docs, revision, nil
+}
+
+// addDocuments adds the documents of a resource to docs.
+func (s *kubernetesSource) addDocuments(docs map[string][]byte, item kubeObject) error {
+	if s.resource == kubeConfigMaps {
+		for key, value := range item.Data {
+			docs[path.Join(kubeConfigMaps, item.Metadata.Name, key)] = []byte(value)
+		}
+		return nil
+	}
+	spec := item.Spec
+	if spec == nil {
+		spec = make(map[string]any)
+	}
+	if _, ok := spec["name"]; !ok {
+		spec["name"] = item.Metadata.Name
+	}
+	doc, err := json.Marshal(map[string]any{"upstream_services": []any{spec}})
+	if err != nil {
+		return fmt.Errorf("invalid spec of UpstreamService %s: %w", item.Metadata.Name, err)
+	}
+	docs[path.Join(kubeUpstreamServices, item.Metadata.Name+".json")] = doc
+	return nil
+}
+
+// Watch watches the resources after the resource version, and returns at the
+// first change. The watch is renewed when the API server closes it.
+func (s *kubernetesSource) Watch(ctx context.Context, revision uint64) (uint64, error) {
+	if s.generations == nil {
+		s.generations = make(map[string]int64)
+	}
+	resourceVersion := strconv.FormatUint(revision, 10)
+	for {
+		next, changed, err := s.watchOnce(ctx, &resourceVersion)
+		if err != nil || changed {
+			return next, err
+		}
+	}
+}
+
+// watchOnce reads a watch stream until a change, or until the API server
+// closes it. resourceVersion is advanced past the events it skips.
+func (s *kubernetesSource) watchOnce(ctx context.Context, resourceVersion *string) (uint64, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, kubeWatchTimeout+kvRequestTimeout)
+	defer cancel()
+	query := url.Values{
+		"watch":               {"true"},
+		"resourceVersion":     {*resourceVersion},
+		"allowWatchBookmarks": {"true"},
+		"timeoutSeconds":      {strconv.Itoa(int(kubeWatchTimeout.Seconds()))},
+	}
+	if s.selector != "" {
+		query.Set("labelSelector", s.selector)
+	}
+	resp, err := s.client.do(ctx, http.MethodGet, s.apiPath(), query, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		_, err := readKVResponse(resp)
+		return 0, false, err
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var event kubeWatchEvent
+		if err := decoder.Decode(&event); err != nil {
+			if ctx.Err() == nil && errors.Is(err, io.EOF) {
+				// The watch timed out: renew it.
+				return 0, false, nil
+			}
+			return 0, false, fmt.Errorf("watch of Kubernetes %s closed: %w", s.resource, err)
+		}
+		if event.Type == "ERROR" {
+			var status struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			}
+			_ = json.Unmarshal(event.Object, &status)
+			if status.Code == http.StatusGone {
+				// The resource version is too old: relist.
+				return 0, true, nil
+			}
+			return 0, false, fmt.Errorf("watch of Kubernetes %s failed: %s", s.resource, status.Message)
+		}
+
+		var object kubeObject
+		if err := json.Unmarshal(event.Object, &object); err != nil {
+			return 0, false, fmt.Errorf("invalid Kubernetes watch event: %w", err)
+		}
+		*resourceVersion = object.Metadata.ResourceVersion
+		name, generation := object.Metadata.Name, object.Metadata.Generation
+		switch event.Type {
+		case "BOOKMARK":
+			continue
+		case "MODIFIED":
+			if previous, ok := s.generations[name]; ok && generation != 0 && previous == generation {
+				continue
+			}
+			s.generations[name] = generation
+		case "ADDED":
+			s.generations[name] = generation
+		case "DELETED":
+			delete(s.generations, name)
+		}
+		next, err := strconv.ParseUint(object.Metadata.ResourceVersion, 10, 64)
+		if err != nil {
+			return 0, true, nil
+		}
+		return next, true, nil
+	}
+}
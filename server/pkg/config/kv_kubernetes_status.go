@@ -0,0 +1,237 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/logging"
+)
+
+// kubeStatusInterval is how often the status of the UpstreamService
+// resources is refreshed, to follow the health of the services.
+const kubeStatusInterval = 30 * time.Second
+
+// KubernetesServiceStatus reports whether a service is ready, or why not.
+//
+// Summary: Reports the state of a service by name.
+type KubernetesServiceStatus func(service string) (ready bool, message string)
+
+// KubernetesStatusWriter writes the state of the services defined by
+// UpstreamService resources back to the status of the resources.
+//
+// Summary: Writes service states to UpstreamService resources.
+type KubernetesStatusWriter struct {
+	sources []*kubernetesSource
+	status  KubernetesServiceStatus
+	trigger chan struct{}
+
+	mu      sync.Mutex
+	loadErr error
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewKubernetesStatusWriter creates a status writer for the UpstreamService
+// sources among the config paths.
+//
+// Summary: Creates a KubernetesStatusWriter.
+//
+// Parameters:
+//   - paths ([]string): The config paths.
+//   - status (KubernetesServiceStatus): Reports the state of a service.
+//
+// Returns:
+//   - (*KubernetesStatusWriter): The writer, or nil if no path is a k8s:// UpstreamService source.
+//   - (error): An error if a source is invalid.
+func NewKubernetesStatusWriter(paths []string, status KubernetesServiceStatus) (*KubernetesStatusWriter, error) {
+	var sources []*kubernetesSource
+	for _, p := range paths {
+		if !strings.HasPrefix(strings.ToLower(p), schemeKubernetes+"://") {
+			continue
+		}
+		u, err := url.Parse(p)
+		if err != nil {
+			return nil, err
+		}
+		source, err := newKubernetesSource(u)
+		if err != nil {
+			return nil, err
+		}
+		if source.resource == kubeUpstreamServices {
+			sources = append(sources, source)
+		}
+	}
+	if len(sources) == 0 {
+		return nil, nil
+	}
+	return &KubernetesStatusWriter{
+		sources: sources,
+		status:  status,
+		trigger: make(chan struct{}, 1),
+	}, nil
+}
+
+// Reloaded records the result of a config reload and writes the status.
+//
+// Summary: Reports a config reload.
+//
+// Parameters:
+//   - err (error): The error of the reload, or nil if it succeeded.
+//
+// Side Effects:
+//   - Triggers a status update in the background.
+func (w *KubernetesStatusWriter) Reloaded(err error) {
+	w.mu.Lock()
+	w.loadErr = err
+	w.mu.Unlock()
+	select {
+	case w.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Start writes the status now, after each reload, and every 30 seconds.
+//
+// Summary: Starts writing the status.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the start.
+//
+// Returns:
+//   - (error): Always nil.
+//
+// Side Effects:
+//   - Starts a background goroutine.
+func (w *KubernetesStatusWriter) Start(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.cancel != nil {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	w.cancel = cancel
+	w.done = make(chan struct{})
+	done := w.done
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(kubeStatusInterval)
+		defer ticker.Stop()
+		for {
+			w.Update(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-w.trigger:
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops writing the status.
+//
+// Summary: Stops the background updates.
+//
+// Parameters:
+//   - ctx (context.Context): Bounds the wait for the running update.
+//
+// Returns:
+//   - (error): The error of ctx if it is done first.
+func (w *KubernetesStatusWriter) Stop(ctx context.Context) error {
+	w.mu.Lock()
+	cancel, done := w.cancel, w.done
+	w.cancel, w.done = nil, nil
+	w.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Update writes the status of the resources whose status changed.
+//
+// Summary: Writes the status of the UpstreamService resources.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the requests.
+//
+// Side Effects:
+//   - Patches the status subresource of the UpstreamService resources.
+func (w *KubernetesStatusWriter) Update(ctx context.Context) {
+	w.mu.Lock()
+	loadErr := w.loadErr
+	w.mu.Unlock()
+
+	for _, source := range w.sources {
+		list, err := source.list(ctx)
+		if err != nil {
+			logging.GetLogger().Warn("Failed to list UpstreamService resources", "namespace", source.namespace, "error", err)
+			continue
+		}
+		for _, item := range list.Items {
+			status := w.desiredStatus(item, loadErr)
+			if status == item.Status {
+				continue
+			}
+			if err := source.patchStatus(ctx, item.Metadata.Name, status); err != nil {
+				logging.GetLogger().Warn("Failed to write UpstreamService status", "namespace", source.namespace, "name", item.Metadata.Name, "error", err)
+			}
+		}
+	}
+}
+
+// desiredStatus returns the status of a resource. When the last reload
+// failed, resources changed since their last applied generation report the
+// error, and the others the state of their service.
+func (w *KubernetesStatusWriter) desiredStatus(item kubeObject, loadErr error) kubeServiceStatus {
+	if loadErr != nil && item.Status.ObservedGeneration != item.Metadata.Generation {
+		return kubeServiceStatus{
+			Message:            "not applied: " + loadErr.Error(),
+			ObservedGeneration: item.Status.ObservedGeneration,
+		}
+	}
+	name, _ := item.Spec["name"].(string)
+	if name == "" {
+		name = item.Metadata.Name
+	}
+	ready, message := w.status(name)
+	return kubeServiceStatus{Ready: ready, Message: message, ObservedGeneration: item.Metadata.Generation}
+}
+
+func (s *kubernetesSource) patchStatus(ctx context.Context, name string, status kubeServiceStatus) error {
+	body, err := json.Marshal(map[string]any{"status": status})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, kvRequestTimeout)
+	defer cancel()
+	resp, err := s.client.do(ctx, http.MethodPatch, path.Join(s.apiPath(), name, "status"), nil, body)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if _, err := readKVResponse(resp); err != nil {
+		if resp.StatusCode == http.StatusNotFound {
+			return errors.New("the UpstreamService CRD has no status subresource, or the resource was deleted")
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,310 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKube serves the list, watch and status calls of the API server for the
+// resources of one namespace.
+type fakeKube struct {
+	t        *testing.T
+	mu       sync.Mutex
+	objects  map[string]map[string]*kubeObject // By resource and name.
+	revision int
+	last     kubeWatchEvent
+	changed  chan struct{}
+	patches  []string
+	query    url.Values
+}
+
+func newFakeKube(t *testing.T) *fakeKube {
+	t.Helper()
+	f := &fakeKube{
+		t:        t,
+		objects:  map[string]map[string]*kubeObject{kubeUpstreamServices: {}, kubeConfigMaps: {}},
+		revision: 100,
+		changed:  make(chan struct{}),
+	}
+	server := httptest.NewTLSServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ca.crt"), ca, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "token"), []byte("secret-token\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "namespace"), []byte("team"), 0o600))
+	original := kubeServiceAccountDir
+	kubeServiceAccountDir = dir
+	t.Cleanup(func() { kubeServiceAccountDir = original })
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	t.Setenv("KUBERNETES_SERVICE_HOST", u.Hostname())
+	t.Setenv("KUBERNETES_SERVICE_PORT", u.Port())
+	return f
+}
+
+// put stores an object, as a change of its spec or data when bumpGeneration
+// is set, or of its status otherwise.
+func (f *fakeKube) put(resource string, object kubeObject, bumpGeneration bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	eventType := "MODIFIED"
+	previous, ok := f.objects[resource][object.Metadata.Name]
+	switch {
+	case !ok:
+		eventType = "ADDED"
+		object.Metadata.Generation = 1
+	case bumpGeneration:
+		object.Metadata.Generation = previous.Metadata.Generation + 1
+	default:
+		object.Metadata.Generation = previous.Metadata.Generation
+	}
+	f.revision++
+	object.Metadata.ResourceVersion = strconv.Itoa(f.revision)
+	f.objects[resource][object.Metadata.Name] = &object
+	raw, err := json.Marshal(object)
+	require.NoError(f.t, err)
+	f.last = kubeWatchEvent{Type: eventType, Object: raw}
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func (f *fakeKube) get(resource, name string) kubeObject {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return *f.objects[resource][name]
+}
+
+func (f *fakeKube) patchCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.patches)
+}
+
+func (f *fakeKube) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer secret-token" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var resource, rest string
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/apis/mcp.any/v1alpha1/namespaces/team/upstreamservices"):
+		resource, rest = kubeUpstreamServices, strings.TrimPrefix(r.URL.Path, "/apis/mcp.any/v1alpha1/namespaces/team/upstreamservices")
+	case strings.HasPrefix(r.URL.Path, "/api/v1/namespaces/team/configmaps"):
+		resource, rest = kubeConfigMaps, strings.TrimPrefix(r.URL.Path, "/api/v1/namespaces/team/configmaps")
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	f.mu.Lock()
+	f.query = r.URL.Query()
+	f.mu.Unlock()
+	switch {
+	case r.Method == http.MethodPatch && strings.HasSuffix(rest, "/status"):
+		f.patchStatus(w, r, resource, strings.TrimSuffix(strings.TrimPrefix(rest, "/"), "/status"))
+	case r.URL.Query().Get("watch") == "true":
+		f.watch(w, r)
+	default:
+		f.list(w, resource)
+	}
+}
+
+func (f *fakeKube) list(w http.ResponseWriter, resource string) {
+	f.mu.Lock()
+	list := kubeList{}
+	list.Metadata.ResourceVersion = strconv.Itoa(f.revision)
+	names := make([]string, 0, len(f.objects[resource]))
+	for name := range f.objects[resource] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		list.Items = append(list.Items, *f.objects[resource][name])
+	}
+	f.mu.Unlock()
+	_ = json.NewEncoder(w).Encode(list)
+}
+
+func (f *fakeKube) watch(w http.ResponseWriter, r *http.Request) {
+	sent, _ := strconv.Atoi(r.URL.Query().Get("resourceVersion"))
+	w.(http.Flusher).Flush()
+	for {
+		f.mu.Lock()
+		revision, event, changed := f.revision, f.last, f.changed
+		f.mu.Unlock()
+		if revision > sent {
+			_ = json.NewEncoder(w).Encode(event)
+			w.(http.Flusher).Flush()
+			sent = revision
+		}
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (f *fakeKube) patchStatus(w http.ResponseWriter, r *http.Request, resource, name string) {
+	var patch struct {
+		Status kubeServiceStatus `json:"status"`
+	}
+	require.NoError(f.t, json.NewDecoder(r.Body).Decode(&patch))
+	f.mu.Lock()
+	object, ok := f.objects[resource][name]
+	f.patches = append(f.patches, name)
+	f.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	updated := *object
+	updated.Status = patch.Status
+	f.put(resource, updated, false)
+	_ = json.NewEncoder(w).Encode(updated)
+}
+
+func upstreamService(name string, spec map[string]any) kubeObject {
+	return kubeObject{Metadata: kubeObjectMeta{Name: name}, Spec: spec}
+}
+
+func TestKubernetesSource_Errors(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	_, err := newKVSource("k8s://team/upstreamservices")
+	assert.ErrorContains(t, err, "not running in a Kubernetes cluster")
+
+	_, err = newKVSource("k8s://team/secrets")
+	assert.EqualError(t, err, `invalid Kubernetes source "k8s://team/secrets": the path must be /upstreamservices or /configmaps`)
+}
+
+func TestKubernetesSource_Load(t *testing.T) {
+	kube := newFakeKube(t)
+	kube.put(kubeUpstreamServices, upstreamService("weather", map[string]any{
+		"http_service": map[string]any{"address": "http://weather.example.com"},
+	}), true)
+	kube.put(kubeUpstreamServices, upstreamService("github", map[string]any{
+		"name":         "github-api",
+		"http_service": map[string]any{"address": "http://api.github.com"},
+	}), true)
+	kube.put(kubeConfigMaps, kubeObject{
+		Metadata: kubeObjectMeta{Name: "mcpany-services"},
+		Data:     map[string]string{"tools.yaml": serviceDoc("tools"), "README": "ignored"},
+	}, true)
+
+	store := NewFileStore(afero.NewMemMapFs(), []string{"k8s:///upstreamservices"})
+	cfg, err := store.Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, cfg.GetUpstreamServices(), 2)
+	assert.Equal(t, "github-api", cfg.GetUpstreamServices()[0].GetName())
+	assert.Equal(t, "weather", cfg.GetUpstreamServices()[1].GetName(), "the name defaults to the resource name")
+	assert.Equal(t, "http://weather.example.com", cfg.GetUpstreamServices()[1].GetHttpService().GetAddress())
+
+	store = NewFileStore(afero.NewMemMapFs(), []string{"k8s://team/configmaps?selector=mcpany.io%2Fconfig%3Dtrue"})
+	cfg, err = store.Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, cfg.GetUpstreamServices(), 1)
+	assert.Equal(t, "tools", cfg.GetUpstreamServices()[0].GetName())
+	assert.Equal(t, "mcpany.io/config=true", kube.query.Get("labelSelector"))
+}
+
+func TestKubernetesSource_Watch(t *testing.T) {
+	kube := newFakeKube(t)
+	kube.put(kubeUpstreamServices, upstreamService("weather", nil), true)
+
+	source, err := newKVSource("k8s:///upstreamservices")
+	require.NoError(t, err)
+	_, revision, err := source.List(context.Background())
+	require.NoError(t, err)
+
+	result := make(chan uint64, 1)
+	go func() {
+		next, err := source.Watch(context.Background(), revision)
+		assert.NoError(t, err)
+		result <- next
+	}()
+
+	// A status change leaves the generation unchanged and is skipped.
+	object := kube.get(kubeUpstreamServices, "weather")
+	object.Status.Ready = true
+	kube.put(kubeUpstreamServices, object, false)
+	select {
+	case <-result:
+		t.Fatal("a status change was reported")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	object.Spec = map[string]any{"http_service": map[string]any{"address": "http://weather.example.com"}}
+	kube.put(kubeUpstreamServices, object, true)
+	select {
+	case next := <-result:
+		assert.Equal(t, kube.get(kubeUpstreamServices, "weather").Metadata.ResourceVersion, strconv.FormatUint(next, 10))
+	case <-time.After(5 * time.Second):
+		t.Fatal("a spec change was not reported")
+	}
+}
+
+func TestKubernetesStatusWriter(t *testing.T) {
+	kube := newFakeKube(t)
+	kube.put(kubeUpstreamServices, upstreamService("weather", nil), true)
+	kube.put(kubeUpstreamServices, upstreamService("github", map[string]any{"name": "github-api"}), true)
+
+	writer, err := NewKubernetesStatusWriter([]string{"config.yaml", "k8s://team/configmaps"}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, writer, "configmaps have no status")
+
+	writer, err = NewKubernetesStatusWriter([]string{"k8s:///upstreamservices"}, func(service string) (bool, string) {
+		if service == "github-api" {
+			return false, "connection refused"
+		}
+		return true, ""
+	})
+	require.NoError(t, err)
+	require.NotNil(t, writer)
+
+	writer.Update(context.Background())
+	assert.Equal(t, kubeServiceStatus{Ready: true, ObservedGeneration: 1}, kube.get(kubeUpstreamServices, "weather").Status)
+	assert.Equal(t, kubeServiceStatus{Message: "connection refused", ObservedGeneration: 1}, kube.get(kubeUpstreamServices, "github").Status)
+
+	patches := kube.patchCount()
+	writer.Update(context.Background())
+	assert.Equal(t, patches, kube.patchCount(), "unchanged statuses are not written")
+
+	// A failed reload is reported on the resources changed since they were
+	// applied.
+	object := kube.get(kubeUpstreamServices, "weather")
+	kube.put(kubeUpstreamServices, object, true)
+	writer.Reloaded(errors.New("invalid config"))
+	writer.Update(context.Background())
+	assert.Equal(t, kubeServiceStatus{Message: "not applied: invalid config", ObservedGeneration: 1}, kube.get(kubeUpstreamServices, "weather").Status)
+	assert.Equal(t, kubeServiceStatus{Message: "connection refused", ObservedGeneration: 1}, kube.get(kubeUpstreamServices, "github").Status)
+
+	writer.Reloaded(nil)
+	require.NoError(t, writer.Start(context.Background()))
+	assert.Eventually(t, func() bool {
+		return kube.get(kubeUpstreamServices, "weather").Status == kubeServiceStatus{Ready: true, ObservedGeneration: 2}
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, writer.Stop(context.Background()))
+}
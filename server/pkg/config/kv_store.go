@@ -0,0 +1,226 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+)
+
+// URL schemes of the key-value config sources.
+const (
+	schemeEtcd       = "etcd"
+	schemeConsul     = "consul"
+	schemeKubernetes = "k8s"
+)
+
+const (
+	// kvRequestTimeout bounds the reads of a key-value source. Watches
+	// block longer.
+	kvRequestTimeout = 10 * time.Second
+	// kvMaxResponseSize bounds the responses of a key-value source, which
+	// hold all documents under the prefix.
+	kvMaxResponseSize = 32 * 1024 * 1024
+	kvMinRetryDelay   = time.Second
+	kvMaxRetryDelay   = time.Minute
+)
+
+// kvHTTPClient talks to etcd and Consul. Unlike the client of the config
+// URLs it reaches private addresses, where these stores usually run: the
+// operator names them explicitly on the command line.
+var kvHTTPClient = &http.Client{
+	CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// kvSource is a key-value store holding config documents under a prefix.
+type kvSource interface {
+	// List returns the documents under the prefix by key, and the revision
+	// of the store.
+	List(ctx context.Context) (map[string][]byte, uint64, error)
+	// Watch blocks until a key under the prefix changes after the revision,
+	// or ctx is done, and returns the revision of the change, or 0 if it is
+	// unknown.
+	Watch(ctx context.Context, revision uint64) (uint64, error)
+}
+
+// IsRemotePath reports whether the config path is a URL or a key-value
+// source, rather than a file or directory.
+//
+// Summary: Tells remote config paths from local ones.
+//
+// Parameters:
+//   - path (string): The config path.
+//
+// Returns:
+//   - (bool): True for http(s), etcd, consul and k8s URLs.
+func IsRemotePath(path string) bool {
+	return isURL(path) || isKVURL(path)
+}
+
+func isKVURL(path string) bool {
+	lower := strings.ToLower(path)
+	for _, scheme := range []string{schemeEtcd, schemeConsul, schemeKubernetes} {
+		if strings.HasPrefix(lower, scheme+"://") {
+			return true
+		}
+	}
+	return false
+}
+
+// newKVSource parses a key-value source URL:
+//
+//	etcd://[user:password@]host:2379/prefix[?tls=true]
+//	consul://host:8500/prefix[?tls=true&dc=datacenter]
+//	k8s://[namespace]/upstreamservices|configmaps[?selector=label-selector]
+//
+// The Consul ACL token is read from CONSUL_HTTP_TOKEN.
+func newKVSource(path string) (kvSource, error) {
+	u, err := url.Parse(path)
+	if err != nil {
+		// The error of url.Parse quotes the URL, with its password.
+		return nil, fmt.Errorf("invalid key-value source: %w", errors.Unwrap(err))
+	}
+	if strings.EqualFold(u.Scheme, schemeKubernetes) {
+		return newKubernetesSource(u)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid key-value source %q: missing host", u.Redacted())
+	}
+	scheme := "http"
+	if u.Query().Get("tls") == "true" {
+		scheme = "https"
+	}
+	endpoint := &url.URL{Scheme: scheme, Host: u.Host}
+	prefix := strings.TrimPrefix(u.Path, "/")
+
+	switch strings.ToLower(u.Scheme) {
+	case schemeEtcd:
+		source := &etcdSource{endpoint: endpoint, prefix: prefix}
+		if u.User != nil {
+			source.username = u.User.Username()
+			source.password, _ = u.User.Password()
+		}
+		return source, nil
+	case schemeConsul:
+		return &consulSource{endpoint: endpoint, prefix: prefix, datacenter: u.Query().Get("dc")}, nil
+	default:
+		return nil, fmt.Errorf("invalid key-value source %q: unsupported scheme", u.Redacted())
+	}
+}
+
+// redactKVURL removes the password of a key-value source URL, for logs and
+// errors.
+func redactKVURL(path string) string {
+	u, err := url.Parse(path)
+	if err != nil {
+		return path
+	}
+	return u.Redacted()
+}
+
+// loadKV loads the documents of a key-value source and merges them in key
+// order, like the files of a directory. Keys whose extension is not a config
+// format are skipped.
+func (s *FileStore) loadKV(ctx context.Context, path string) (*configv1.McpAnyServerConfig, error) {
+	source, err := newKVSource(path)
+	if err != nil {
+		return nil, err
+	}
+	docs, _, err := source.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config from %s: %w", redactKVURL(path), err)
+	}
+
+	keys := make([]string, 0, len(docs))
+	for key := range docs {
+		if _, err := NewEngine(key); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	configs := make([]*configv1.McpAnyServerConfig, 0, len(keys))
+	for _, key := range keys {
+		cfg, err := s.parseConfig(key, docs[key])
+		if err != nil {
+			return nil, fmt.Errorf("failed to load key %s of %s: %w", key, redactKVURL(path), err)
+		}
+		configs = append(configs, cfg)
+	}
+	return mergeConfigs(configs), nil
+}
+
+// watchKV calls reloadFunc whenever a key of the source changes, until ctx
+// is done. Failed watches are retried with exponential backoff.
+func watchKV(ctx context.Context, path string, reloadFunc func()) {
+	log := logging.GetLogger().With("source", redactKVURL(path))
+	source, err := newKVSource(path)
+	if err != nil {
+		log.Error("Cannot watch key-value config source", "error", err)
+		return
+	}
+
+	delay := kvMinRetryDelay
+	retry := func(err error) bool {
+		log.Warn("Failed to watch key-value config source, retrying", "error", err, "delay", delay)
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, kvMaxRetryDelay)
+		return true
+	}
+
+	// A revision of 0 means that the current one must be listed first.
+	var revision uint64
+	for ctx.Err() == nil {
+		if revision == 0 {
+			_, rev, err := source.List(ctx)
+			if err != nil {
+				if !retry(err) {
+					return
+				}
+				continue
+			}
+			revision = rev
+		}
+		next, err := source.Watch(ctx, revision)
+		if err != nil {
+			if ctx.Err() != nil || !retry(err) {
+				return
+			}
+			revision = 0
+			continue
+		}
+		delay = kvMinRetryDelay
+		revision = next
+		reloadFunc()
+	}
+}
+
+// readKVResponse reads the body of a response of a key-value source, or
+// returns its error.
+func readKVResponse(resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, kvMaxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status code %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
@@ -46,6 +46,9 @@ type UpstreamServiceManager struct {
 	// New fields for profile management
 	profileServiceOverrides map[string]*configv1.ProfileServiceConfig // Stores overrides from profiles
 	profileSecrets          map[string]*configv1.SecretValue          // Stores secrets resolved from profiles
+	// disabledServices are the IDs and names of the services disabled at
+	// runtime through global_settings.disabled_services.
+	disabledServices map[string]bool
 }
 
 // NewUpstreamServiceManager creates a new instance of UpstreamServiceManager.
@@ -107,6 +110,13 @@ func (m *UpstreamServiceManager) LoadAndMergeServices(ctx context.Context, confi
 		}
 	}
 
+	m.disabledServices = make(map[string]bool)
+	for _, name := range config.GetGlobalSettings().GetDisabledServices() {
+		if name != "" {
+			m.disabledServices[name] = true
+		}
+	}
+
 	// Initialize Profile Manager and resolve overrides
 	pm := profile.NewManager(config.GetGlobalSettings().GetProfileDefinitions())
 	for _, profileName := range m.enabledProfiles {
@@ -369,6 +379,11 @@ func (m *UpstreamServiceManager) addService(service *configv1.UpstreamServiceCon
 			isOverrideDisabled = !activeConfig.GetEnabled()
 		}
 	}
+	// A service disabled at runtime stays out even if a profile enables it.
+	if m.disabledServices[service.GetId()] || m.disabledServices[service.GetName()] {
+		m.log.Info("Service disabled at runtime, skipping", "service_name", service.GetName())
+		return nil
+	}
 
 	// If the service has a config error, we skip hydration and adding it to active service lists if it's not safe.
 	// But we DO want it in the config list for the UI.
@@ -429,3 +429,38 @@ func TestUpstreamServiceManager_Profiles_Overrides(t *testing.T) {
 		})
 	}
 }
+
+func TestUpstreamServiceManager_DisabledServices(t *testing.T) {
+	service := func(id, name string) *configv1.UpstreamServiceConfig {
+		return configv1.UpstreamServiceConfig_builder{
+			Id:   proto.String(id),
+			Name: proto.String(name),
+			HttpService: configv1.HttpUpstreamService_builder{
+				Address: proto.String("http://" + name),
+			}.Build(),
+		}.Build()
+	}
+	devProfile := configv1.ProfileDefinition_builder{
+		Name: proto.String("dev"),
+		ServiceConfig: map[string]*configv1.ProfileServiceConfig{
+			"weather": configv1.ProfileServiceConfig_builder{Enabled: proto.Bool(true)}.Build(),
+		},
+	}.Build()
+	config := configv1.McpAnyServerConfig_builder{
+		GlobalSettings: configv1.GlobalSettings_builder{
+			ProfileDefinitions: []*configv1.ProfileDefinition{devProfile},
+			DisabledServices:   []string{"weather", "gh-id"},
+		}.Build(),
+		UpstreamServices: []*configv1.UpstreamServiceConfig{
+			service("weather-id", "weather"),
+			service("gh-id", "github"),
+			service("shop-id", "shop"),
+		},
+	}.Build()
+
+	manager := NewUpstreamServiceManager([]string{"dev"})
+	services, err := manager.LoadAndMergeServices(context.Background(), config)
+	require.NoError(t, err)
+	require.Len(t, services, 1, "disabled by name, by ID, and over the profile")
+	assert.Equal(t, "shop", services[0].GetName())
+}
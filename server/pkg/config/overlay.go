@@ -0,0 +1,197 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// configEnvironment is the environment whose overlays are applied to the
+// config files, such as "prod" for config.prod.yaml, or empty for none.
+var configEnvironment atomic.Pointer[string]
+
+// setConfigEnvironment selects the environment whose overlays are applied to
+// the config files loaded afterwards.
+func setConfigEnvironment(env string) {
+	configEnvironment.Store(&env)
+}
+
+// currentEnvironment returns the environment selected by setConfigEnvironment.
+func currentEnvironment() string {
+	if env := configEnvironment.Load(); env != nil {
+		return *env
+	}
+	return ""
+}
+
+// overlayPath returns the path of the overlay of a config file for an
+// environment: config.prod.yaml for config.yaml and "prod".
+func overlayPath(path, env string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + env + ext
+}
+
+// overlayBase splits the path of a potential overlay, such as
+// config.prod.yaml, into the path of its base file and its environment.
+func overlayBase(path string) (base, env string, ok bool) {
+	ext := filepath.Ext(path)
+	stem := strings.TrimSuffix(path, ext)
+	envExt := filepath.Ext(stem)
+	if len(envExt) < 2 || filepath.Base(stem) == envExt {
+		return "", "", false
+	}
+	return strings.TrimSuffix(stem, envExt) + ext, envExt[1:], true
+}
+
+// applyEnvironment separates the overlays from the base config files, and
+// returns the base files with the overlay of each one for the environment.
+// The files of a directory with a base next to them are overlays, and are
+// dropped unless they are for the environment. Without an environment, all
+// the files are base files.
+func (s *FileStore) applyEnvironment(files []string) ([]string, map[string]string) {
+	if s.environment == "" {
+		return files, nil
+	}
+	listed := make(map[string]bool, len(files))
+	for _, f := range files {
+		listed[f] = true
+	}
+
+	overlays := make(map[string]string)
+	var bases []string
+	for _, f := range files {
+		if IsRemotePath(f) {
+			bases = append(bases, f)
+			continue
+		}
+		if base, env, ok := overlayBase(f); ok && listed[base] {
+			if env == s.environment {
+				overlays[base] = f
+			}
+			continue
+		}
+		bases = append(bases, f)
+	}
+	// The overlays of files given explicitly are looked up next to them.
+	for _, base := range bases {
+		if _, ok := overlays[base]; ok || IsRemotePath(base) {
+			continue
+		}
+		overlay := overlayPath(base, s.environment)
+		if info, err := s.fs.Stat(overlay); err == nil && !info.IsDir() {
+			overlays[base] = overlay
+		}
+	}
+	return bases, overlays
+}
+
+// overlayFiles returns the overlays of the paths for the environment, for
+// watching them. They may not exist.
+func overlayFiles(paths []string, env string) []string {
+	if env == "" {
+		return nil
+	}
+	var overlays []string
+	for _, path := range paths {
+		if IsRemotePath(path) || filepath.Ext(path) == "" {
+			continue
+		}
+		overlays = append(overlays, overlayPath(path, env))
+	}
+	return overlays
+}
+
+// mergeOverlay merges an overlay into a config:
+//   - Scalars and lists of scalars in the overlay replace the ones of the
+//     config.
+//   - Messages and the message values of maps are merged recursively.
+//   - Lists of messages are merged by id: an element with the id, or else the
+//     name, of an element of the config is merged into it, and the others are
+//     appended. If an element of the overlay has neither, the list of the
+//     overlay replaces the one of the config.
+func mergeOverlay(dst, src proto.Message) {
+	mergeOverlayMessage(dst.ProtoReflect(), src.ProtoReflect())
+}
+
+func mergeOverlayMessage(dst, src protoreflect.Message) {
+	src.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			mergeOverlayList(dst, fd, v.List())
+		case fd.IsMap():
+			dstMap := dst.Mutable(fd).Map()
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				if fd.MapValue().Message() != nil && dstMap.Has(k) {
+					mergeOverlayMessage(dstMap.Mutable(k).Message(), mv.Message())
+				} else {
+					dstMap.Set(k, mv)
+				}
+				return true
+			})
+		case fd.Message() != nil && dst.Has(fd):
+			mergeOverlayMessage(dst.Mutable(fd).Message(), v.Message())
+		default:
+			dst.Set(fd, v)
+		}
+		return true
+	})
+}
+
+func mergeOverlayList(dst protoreflect.Message, fd protoreflect.FieldDescriptor, src protoreflect.List) {
+	if fd.Message() != nil && listKeyed(src) {
+		dstList := dst.Mutable(fd).List()
+		index := make(map[string]int, dstList.Len())
+		for i := 0; i < dstList.Len(); i++ {
+			if key := elementKey(dstList.Get(i).Message()); key != "" {
+				index[key] = i
+			}
+		}
+		for i := 0; i < src.Len(); i++ {
+			elem := src.Get(i).Message()
+			if j, ok := index[elementKey(elem)]; ok {
+				mergeOverlayMessage(dstList.Get(j).Message(), elem)
+			} else {
+				dstList.Append(src.Get(i))
+			}
+		}
+		return
+	}
+
+	dst.Clear(fd)
+	dstList := dst.Mutable(fd).List()
+	for i := 0; i < src.Len(); i++ {
+		dstList.Append(src.Get(i))
+	}
+}
+
+// listKeyed reports whether all the elements of a list of messages have an id
+// or a name.
+func listKeyed(list protoreflect.List) bool {
+	for i := 0; i < list.Len(); i++ {
+		if elementKey(list.Get(i).Message()) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// elementKey returns the id of a message, or else its name, or empty if it
+// has neither.
+func elementKey(m protoreflect.Message) string {
+	for _, name := range []protoreflect.Name{"id", "name"} {
+		fd := m.Descriptor().Fields().ByName(name)
+		if fd == nil || fd.Kind() != protoreflect.StringKind || fd.IsList() {
+			continue
+		}
+		if key := m.Get(fd).String(); key != "" {
+			return string(name) + ":" + key
+		}
+	}
+	return ""
+}
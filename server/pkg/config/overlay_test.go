@@ -0,0 +1,178 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverlayBase(t *testing.T) {
+	tests := []struct {
+		path, base, env string
+		ok              bool
+	}{
+		{"config.prod.yaml", "config.yaml", "prod", true},
+		{"/etc/mcpany/services.staging.json", "/etc/mcpany/services.json", "staging", true},
+		{"config.yaml", "", "", false},
+		{".mcpany.yaml", "", "", false},
+		{"config..yaml", "", "", false},
+	}
+	for _, tt := range tests {
+		base, env, ok := overlayBase(tt.path)
+		assert.Equal(t, tt.ok, ok, tt.path)
+		assert.Equal(t, tt.base, base, tt.path)
+		assert.Equal(t, tt.env, env, tt.path)
+	}
+	assert.Equal(t, "/etc/config.prod.yaml", overlayPath("/etc/config.yaml", "prod"))
+}
+
+func TestMergeOverlay(t *testing.T) {
+	base := configv1.McpAnyServerConfig_builder{
+		GlobalSettings: configv1.GlobalSettings_builder{
+			LogLevel: configv1.GlobalSettings_LOG_LEVEL_INFO.Enum(),
+			ApiKey:   ptr("base-key-12345678"),
+			Profiles: []string{"dev", "debug"},
+		}.Build(),
+		UpstreamServices: []*configv1.UpstreamServiceConfig{
+			configv1.UpstreamServiceConfig_builder{
+				Name: ptr("weather"),
+				HttpService: configv1.HttpUpstreamService_builder{
+					Address: ptr("http://localhost:8080"),
+				}.Build(),
+			}.Build(),
+			configv1.UpstreamServiceConfig_builder{Name: ptr("debugger")}.Build(),
+		},
+	}.Build()
+	overlay := configv1.McpAnyServerConfig_builder{
+		GlobalSettings: configv1.GlobalSettings_builder{
+			LogLevel: configv1.GlobalSettings_LOG_LEVEL_WARN.Enum(),
+			Profiles: []string{"prod"},
+		}.Build(),
+		UpstreamServices: []*configv1.UpstreamServiceConfig{
+			configv1.UpstreamServiceConfig_builder{
+				Name: ptr("weather"),
+				HttpService: configv1.HttpUpstreamService_builder{
+					Address: ptr("https://weather.internal"),
+				}.Build(),
+			}.Build(),
+			configv1.UpstreamServiceConfig_builder{Name: ptr("debugger"), Disable: ptr(true)}.Build(),
+			configv1.UpstreamServiceConfig_builder{Name: ptr("billing")}.Build(),
+		},
+	}.Build()
+
+	mergeOverlay(base, overlay)
+
+	gs := base.GetGlobalSettings()
+	assert.Equal(t, configv1.GlobalSettings_LOG_LEVEL_WARN, gs.GetLogLevel())
+	assert.Equal(t, "base-key-12345678", gs.GetApiKey(), "fields absent from the overlay are kept")
+	assert.Equal(t, []string{"prod"}, gs.GetProfiles(), "lists of scalars are replaced")
+
+	services := base.GetUpstreamServices()
+	require.Len(t, services, 3)
+	assert.Equal(t, "https://weather.internal", services[0].GetHttpService().GetAddress())
+	assert.Equal(t, "debugger", services[1].GetName())
+	assert.True(t, services[1].GetDisable())
+	assert.Equal(t, "billing", services[2].GetName())
+}
+
+func TestMergeOverlay_ListWithoutKeys(t *testing.T) {
+	base := configv1.McpAnyServerConfig_builder{
+		UpstreamServices: []*configv1.UpstreamServiceConfig{
+			configv1.UpstreamServiceConfig_builder{Name: ptr("a")}.Build(),
+		},
+	}.Build()
+	overlay := configv1.McpAnyServerConfig_builder{
+		UpstreamServices: []*configv1.UpstreamServiceConfig{
+			configv1.UpstreamServiceConfig_builder{Disable: ptr(true)}.Build(),
+		},
+	}.Build()
+
+	mergeOverlay(base, overlay)
+
+	require.Len(t, base.GetUpstreamServices(), 1)
+	assert.Empty(t, base.GetUpstreamServices()[0].GetName())
+	assert.True(t, base.GetUpstreamServices()[0].GetDisable())
+}
+
+func TestFileStore_Environment(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/config/config.yaml", []byte(`
+global_settings:
+  log_level: info
+upstream_services:
+  - name: weather
+    http_service:
+      address: http://localhost:8080
+`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/config/config.prod.yaml", []byte(`
+global_settings:
+  log_level: warn
+upstream_services:
+  - name: weather
+    http_service:
+      address: https://weather.internal
+`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/config/config.dev.yaml", []byte(`
+upstream_services:
+  - name: debugger
+    http_service:
+      address: http://localhost:9090
+`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/config/billing.v2.yaml", []byte(`
+upstream_services:
+  - name: billing
+    http_service:
+      address: http://localhost:7070
+`), 0o644))
+
+	load := func(t *testing.T, env string, paths ...string) *configv1.McpAnyServerConfig {
+		t.Helper()
+		store := NewFileStore(fs, paths)
+		store.environment = env
+		cfg, err := store.Load(context.Background())
+		require.NoError(t, err)
+		return cfg
+	}
+	names := func(cfg *configv1.McpAnyServerConfig) []string {
+		var names []string
+		for _, svc := range cfg.GetUpstreamServices() {
+			names = append(names, svc.GetName())
+		}
+		return names
+	}
+
+	t.Run("directory", func(t *testing.T) {
+		cfg := load(t, "prod", "/config")
+		assert.Equal(t, configv1.GlobalSettings_LOG_LEVEL_WARN, cfg.GetGlobalSettings().GetLogLevel())
+		assert.Equal(t, []string{"billing", "weather"}, names(cfg), "the dev overlay is not loaded, and files without a base are")
+		assert.Equal(t, "https://weather.internal", cfg.GetUpstreamServices()[1].GetHttpService().GetAddress())
+	})
+
+	t.Run("file", func(t *testing.T) {
+		cfg := load(t, "dev", "/config/config.yaml")
+		assert.Equal(t, []string{"weather", "debugger"}, names(cfg))
+		assert.Equal(t, "http://localhost:8080", cfg.GetUpstreamServices()[0].GetHttpService().GetAddress())
+	})
+
+	t.Run("missing overlay", func(t *testing.T) {
+		cfg := load(t, "staging", "/config/config.yaml")
+		assert.Equal(t, []string{"weather"}, names(cfg))
+	})
+
+	t.Run("no environment", func(t *testing.T) {
+		cfg := load(t, "", "/config")
+		assert.Len(t, cfg.GetUpstreamServices(), 4, "without an environment all the files of a directory are loaded")
+	})
+}
+
+func TestOverlayFiles(t *testing.T) {
+	assert.Nil(t, overlayFiles([]string{"config.yaml"}, ""))
+	assert.Equal(t, []string{"config.prod.yaml"}, overlayFiles([]string{"config.yaml", "conf", "https://example.com/c.yaml"}, "prod"))
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/logging"
+)
+
+const (
+	// DefaultConfigPollInterval is how often the configs loaded from http(s)
+	// URLs are checked for changes by default.
+	DefaultConfigPollInterval = 30 * time.Second
+	// urlMaxPollDelay bounds the exponential backoff of failed polls.
+	urlMaxPollDelay = 10 * time.Minute
+)
+
+// signatureURL returns the URL of the detached signature of a config.
+func signatureURL(configURL, suffix string) (string, error) {
+	u, err := url.Parse(configURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid config url: %w", err)
+	}
+	u.Path += suffix
+	if u.RawPath != "" {
+		u.RawPath += suffix
+	}
+	return u.String(), nil
+}
+
+// urlPoller checks a remote config for changes with conditional requests.
+type urlPoller struct {
+	url          string
+	etag         string
+	lastModified string
+	digest       [sha256.Size]byte
+	polled       bool
+}
+
+// poll fetches the config unless it is unchanged since the last poll, and
+// reports whether it changed. The first poll records the config.
+func (p *urlPoller) poll(ctx context.Context) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return false, err
+	}
+	if p.etag != "" {
+		req.Header.Set("If-None-Match", p.etag)
+	}
+	if p.lastModified != "" {
+		req.Header.Set("If-Modified-Since", p.lastModified)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return false, nil
+	case http.StatusOK:
+	default:
+		return false, fmt.Errorf("status code %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(nil, resp.Body, 1024*1024))
+	if err != nil {
+		return false, err
+	}
+
+	p.etag = resp.Header.Get("ETag")
+	p.lastModified = resp.Header.Get("Last-Modified")
+	// Servers without validators return the config each time: compare it.
+	digest := sha256.Sum256(body)
+	changed := p.polled && digest != p.digest
+	p.digest, p.polled = digest, true
+	return changed, nil
+}
+
+// watchURL polls a remote config every interval and calls reloadFunc when it
+// changes, until ctx is done. Failed polls are retried with exponential
+// backoff. The reload reads the config again, and verifies its signature.
+func watchURL(ctx context.Context, configURL string, interval time.Duration, reloadFunc func()) {
+	log := logging.GetLogger().With("url", configURL)
+	poller := &urlPoller{url: configURL}
+	delay := time.Duration(0)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		changed, err := poller.poll(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			delay = min(max(delay*2, interval), max(interval, urlMaxPollDelay))
+			log.Warn("Failed to poll remote config, retrying", "error", err, "delay", delay)
+			continue
+		}
+		delay = interval
+		if changed {
+			log.Info("Remote config changed")
+			reloadFunc()
+		}
+	}
+}
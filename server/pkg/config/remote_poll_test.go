@@ -0,0 +1,144 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// remoteConfig serves a config with an ETag or a Last-Modified date, or
+// neither.
+type remoteConfig struct {
+	mu           sync.Mutex
+	body         string
+	etag         string
+	lastModified string
+	fail         bool
+	conditional  int
+}
+
+func (c *remoteConfig) set(body, etag, lastModified string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.body, c.etag, c.lastModified = body, etag, lastModified
+}
+
+func (c *remoteConfig) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fail {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	if (c.etag != "" && r.Header.Get("If-None-Match") == c.etag) ||
+		(c.lastModified != "" && r.Header.Get("If-Modified-Since") == c.lastModified) {
+		c.conditional++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if c.etag != "" {
+		w.Header().Set("ETag", c.etag)
+	}
+	if c.lastModified != "" {
+		w.Header().Set("Last-Modified", c.lastModified)
+	}
+	_, _ = w.Write([]byte(c.body))
+}
+
+func newRemoteConfigServer(t *testing.T, c *remoteConfig) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(c)
+	t.Cleanup(server.Close)
+	originalClient := httpClient
+	t.Cleanup(func() { httpClient = originalClient })
+	httpClient = server.Client()
+	return server
+}
+
+func TestURLPoller(t *testing.T) {
+	for name, validators := range map[string][2]string{
+		"etag":          {`"v1"`, ""},
+		"last modified": {"", "Mon, 12 Oct 2026 10:00:00 GMT"},
+		"none":          {"", ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := &remoteConfig{}
+			c.set(serviceDoc("first"), validators[0], validators[1])
+			server := newRemoteConfigServer(t, c)
+			poller := &urlPoller{url: server.URL}
+
+			changed, err := poller.poll(context.Background())
+			require.NoError(t, err)
+			assert.False(t, changed, "the first poll records the config")
+			changed, err = poller.poll(context.Background())
+			require.NoError(t, err)
+			assert.False(t, changed)
+			if validators != [2]string{} {
+				assert.Equal(t, 1, c.conditional, "the request is conditional")
+			}
+
+			newValidators := validators
+			if validators[0] != "" {
+				newValidators[0] = `"v2"`
+			}
+			if validators[1] != "" {
+				newValidators[1] = "Mon, 12 Oct 2026 11:00:00 GMT"
+			}
+			c.set(serviceDoc("second"), newValidators[0], newValidators[1])
+			changed, err = poller.poll(context.Background())
+			require.NoError(t, err)
+			assert.True(t, changed)
+		})
+	}
+}
+
+func TestWatchURL(t *testing.T) {
+	c := &remoteConfig{fail: true}
+	c.set(serviceDoc("first"), `"v1"`, "")
+	server := newRemoteConfigServer(t, c)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reloads := make(chan struct{}, 10)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watchURL(ctx, server.URL, 10*time.Millisecond, func() { reloads <- struct{}{} })
+	}()
+
+	// Failed polls are retried.
+	time.Sleep(50 * time.Millisecond)
+	c.mu.Lock()
+	c.fail = false
+	c.mu.Unlock()
+	assert.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.conditional > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Empty(t, reloads)
+
+	c.set(serviceDoc("second"), `"v2"`, "")
+	select {
+	case <-reloads:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the change was not detected")
+	}
+
+	cancel()
+	<-done
+}
+
+func TestSignatureURL(t *testing.T) {
+	u, err := signatureURL("https://config.example.com/mcpany/config.yaml?token=abc", ".sig")
+	require.NoError(t, err)
+	assert.Equal(t, "https://config.example.com/mcpany/config.yaml.sig?token=abc", u)
+}
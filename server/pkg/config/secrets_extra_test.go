@@ -20,6 +20,9 @@ func TestStripSecretsFromAuth_OAuth2(t *testing.T) {
 			ClientSecret: configv1.SecretValue_builder{
 				PlainText: proto.String("client-secret"),
 			}.Build(),
+			RefreshToken: configv1.SecretValue_builder{
+				PlainText: proto.String("refresh-token"),
+			}.Build(),
 		}.Build(),
 	}.Build()
 
@@ -33,6 +36,9 @@ func TestStripSecretsFromAuth_OAuth2(t *testing.T) {
 
 	// ClientSecret should be scrubbed
 	assert.Empty(t, scrubbedOauth.GetClientSecret().GetPlainText(), "Plain text ClientSecret should be cleared")
+
+	// RefreshToken should be scrubbed
+	assert.Empty(t, scrubbedOauth.GetRefreshToken().GetPlainText(), "Plain text RefreshToken should be cleared")
 }
 
 func TestStripSecretsFromService_MoreTypes(t *testing.T) {
@@ -25,7 +25,9 @@ type Settings struct {
 	proto           *configv1.GlobalSettings
 	grpcPort        string
 	stdio           bool
+	stdioNetwork    bool
 	configPaths     []string
+	environment     string
 	debug           bool
 	logLevel        string
 	logFile         string
@@ -33,6 +35,8 @@ type Settings struct {
 	shutdownTimeout time.Duration
 	profiles        []string
 	dbPath          string
+	listenerTLS     ListenerTLS
+	configPoll      time.Duration
 	setValues       []string
 	fs              afero.Fs
 	cmd             *cobra.Command
@@ -101,8 +105,11 @@ func (s *Settings) Load(cmd *cobra.Command, fs afero.Fs) error {
 
 	s.grpcPort = viper.GetString("grpc-port")
 	s.stdio = viper.GetBool("stdio") // Corrected from "std"
+	s.stdioNetwork = viper.GetBool("stdio-with-network")
 	// Bind config paths
 	s.configPaths = getStringSlice("config-path")
+	s.environment = viper.GetString("environment")
+	setConfigEnvironment(s.environment)
 	s.debug = viper.GetBool("debug")
 	s.logLevel = viper.GetString("log-level")
 
@@ -142,7 +149,30 @@ func (s *Settings) Load(cmd *cobra.Command, fs afero.Fs) error {
 	s.shutdownTimeout = viper.GetDuration("shutdown-timeout")
 	s.profiles = getStringSlice("profiles")
 	s.dbPath = viper.GetString("db-path")
+	s.listenerTLS = ListenerTLS{
+		CertFile:         viper.GetString("tls-cert"),
+		KeyFile:          viper.GetString("tls-key"),
+		ClientCAFile:     viper.GetString("tls-client-ca"),
+		ClientAuth:       viper.GetString("tls-client-auth"),
+		ACMEDomains:      getStringSlice("acme-domains"),
+		ACMEEmail:        viper.GetString("acme-email"),
+		ACMECacheDir:     viper.GetString("acme-cache-dir"),
+		ACMEDirectoryURL: viper.GetString("acme-directory-url"),
+	}
 	s.setValues = getStringSlice("set")
+	s.configPoll = viper.GetDuration("config-poll-interval")
+
+	// Verify the remote configs before the first one is read
+	var publicKey []byte
+	if keyPath := viper.GetString("config-public-key"); keyPath != "" {
+		var err error
+		if publicKey, err = afero.ReadFile(fs, keyPath); err != nil {
+			return fmt.Errorf("failed to read config public key: %w", err)
+		}
+	}
+	if err := setConfigPublicKey(publicKey); err != nil {
+		return err
+	}
 
 	// Special handling for MCPListenAddress to respect config file precedence
 	mcpListenAddress := viper.GetString("mcp-listen-address")
@@ -272,6 +302,23 @@ func (s *Settings) Stdio() bool {
 	return s.stdio
 }
 
+// StdioWithNetwork returns whether the network listeners should also be
+// started while in stdio mode.
+//
+// Summary: Checks if stdio mode runs alongside the HTTP and gRPC listeners.
+//
+// Parameters:
+//   - None.
+//
+// Returns:
+//   - bool: True if stdio mode is enabled and network listeners were requested.
+//
+// Side Effects:
+//   - None.
+func (s *Settings) StdioWithNetwork() bool {
+	return s.stdio && s.stdioNetwork
+}
+
 // ConfigPaths returns the paths to the configuration files.
 //
 // Summary: Retrieves configuration file paths.
@@ -288,6 +335,22 @@ func (s *Settings) ConfigPaths() []string {
 	return s.configPaths
 }
 
+// Environment returns the environment whose config overlays are applied.
+//
+// Summary: Retrieves the config environment.
+//
+// Parameters:
+//   - None.
+//
+// Returns:
+//   - string: The environment, such as "prod", or empty for none.
+//
+// Side Effects:
+//   - None.
+func (s *Settings) Environment() string {
+	return s.environment
+}
+
 // IsDebug returns whether debug mode is enabled.
 //
 // Summary: Checks if debug mode is enabled.
@@ -336,6 +399,23 @@ func (s *Settings) PersistentLog() string {
 	return s.persistentLog
 }
 
+// ConfigPollInterval returns how often the configs loaded from http(s) URLs
+// are checked for changes.
+//
+// Summary: Retrieves the remote config polling interval.
+//
+// Parameters:
+//   - None.
+//
+// Returns:
+//   - time.Duration: The interval, or 0 if polling is disabled.
+//
+// Side Effects:
+//   - None.
+func (s *Settings) ConfigPollInterval() time.Duration {
+	return s.configPoll
+}
+
 // ShutdownTimeout returns the graceful shutdown timeout.
 //
 // Summary: Retrieves the shutdown timeout.
@@ -482,6 +562,35 @@ func (s *Settings) DBPath() string {
 	return s.dbPath
 }
 
+// ListenerTLS is the TLS termination of the MCP listen address, as set by
+// the --tls-* and --acme-* flags.
+type ListenerTLS struct {
+	CertFile         string
+	KeyFile          string
+	ClientCAFile     string
+	ClientAuth       string
+	ACMEDomains      []string
+	ACMEEmail        string
+	ACMECacheDir     string
+	ACMEDirectoryURL string
+}
+
+// ListenerTLS returns the TLS settings of the MCP listen address.
+//
+// Summary: Retrieves the listener TLS settings.
+//
+// Parameters:
+//   - None.
+//
+// Returns:
+//   - ListenerTLS: The TLS settings; TLS is disabled if no certificate or ACME domain is set.
+//
+// Side Effects:
+//   - None.
+func (s *Settings) ListenerTLS() ListenerTLS {
+	return s.listenerTLS
+}
+
 // SetValues returns configuration values to override.
 //
 // Summary: Retrieves configuration override values.
@@ -562,6 +671,26 @@ func (s *Settings) GetDlp() *configv1.DLPConfig {
 	return s.proto.GetDlp()
 }
 
+// GetOutputGuard returns the output guard configuration.
+//
+// Summary: Retrieves the output guard configuration.
+//
+// Returns:
+//   - *configv1.OutputGuardConfig: The output guard config.
+func (s *Settings) GetOutputGuard() *configv1.OutputGuardConfig {
+	return s.proto.GetOutputGuard()
+}
+
+// GetContextBudget returns the context budget configuration.
+//
+// Summary: Retrieves the context budget configuration.
+//
+// Returns:
+//   - *configv1.ContextBudgetConfig: The context budget config.
+func (s *Settings) GetContextBudget() *configv1.ContextBudgetConfig {
+	return s.proto.GetContextBudget()
+}
+
 // SetDlp sets the DLP configuration.
 //
 // Summary: Sets the DLP configuration.
@@ -71,6 +71,31 @@ upstream_services: []
 	assert.Equal(t, configv1.GlobalSettings_LOG_LEVEL_DEBUG, settings.LogLevel())
 }
 
+func TestSettings_ListenerTLS(t *testing.T) {
+	viper.Reset()
+	t.Setenv("MCPANY_ACME_DOMAINS", "mcp.example.com,mcp2.example.com")
+	cmd := &cobra.Command{}
+	BindFlags(cmd)
+	require.NoError(t, cmd.ParseFlags([]string{
+		"--tls-client-ca", "/etc/mcpany/clients.pem",
+		"--tls-client-auth", "optional",
+		"--acme-email", "ops@example.com",
+	}))
+
+	settings := &Settings{
+		proto: configv1.GlobalSettings_builder{}.Build(),
+	}
+	require.NoError(t, settings.Load(cmd, afero.NewMemMapFs()))
+
+	assert.Equal(t, ListenerTLS{
+		ClientCAFile: "/etc/mcpany/clients.pem",
+		ClientAuth:   "optional",
+		ACMEDomains:  []string{"mcp.example.com", "mcp2.example.com"},
+		ACMEEmail:    "ops@example.com",
+		ACMECacheDir: "data/acme",
+	}, settings.ListenerTLS())
+}
+
 func TestSettings_Defaults(t *testing.T) {
 	viper.Reset()
 	fs := afero.NewMemMapFs()
@@ -0,0 +1,161 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// signatureVerifier verifies the detached signature of a remote config,
+// published next to it at its URL with a suffix.
+type signatureVerifier interface {
+	// suffix is appended to the path of the config URL to get the URL of
+	// its signature.
+	suffix() string
+	verify(data, signature []byte) error
+}
+
+// remoteConfigVerifier verifies the configs loaded from http(s) URLs, or is
+// nil if they are not signed.
+var remoteConfigVerifier atomic.Pointer[signatureVerifier]
+
+// setConfigPublicKey makes the configs loaded from http(s) URLs require a
+// signature by the key: a PEM public key of cosign, or a minisign public
+// key. Without a key, they are not verified.
+func setConfigPublicKey(key []byte) error {
+	if len(bytes.TrimSpace(key)) == 0 {
+		remoteConfigVerifier.Store(nil)
+		return nil
+	}
+	var verifier signatureVerifier
+	var err error
+	if block, _ := pem.Decode(key); block != nil {
+		verifier, err = newCosignVerifier(block)
+	} else {
+		verifier, err = newMinisignVerifier(key)
+	}
+	if err != nil {
+		return err
+	}
+	remoteConfigVerifier.Store(&verifier)
+	return nil
+}
+
+// cosignVerifier verifies the signatures of `cosign sign-blob --key`: the
+// base64 signature of the SHA-256 digest of the config.
+type cosignVerifier struct {
+	key crypto.PublicKey
+}
+
+func newCosignVerifier(block *pem.Block) (*cosignVerifier, error) {
+	if block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("invalid config public key: unexpected PEM block %q", block.Type)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config public key: %w", err)
+	}
+	switch key.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+		return &cosignVerifier{key: key}, nil
+	default:
+		return nil, fmt.Errorf("invalid config public key: unsupported key type %T", key)
+	}
+}
+
+func (v *cosignVerifier) suffix() string { return ".sig" }
+
+func (v *cosignVerifier) verify(data, signature []byte) error {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	digest := sha256.Sum256(data)
+	var ok bool
+	switch key := v.key.(type) {
+	case *ecdsa.PublicKey:
+		ok = ecdsa.VerifyASN1(key, digest[:], sig)
+	case *rsa.PublicKey:
+		ok = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig) == nil
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(key, data, sig)
+	}
+	if !ok {
+		return errors.New("signature does not match the config public key")
+	}
+	return nil
+}
+
+// minisignVerifier verifies the signatures of minisign, including their
+// trusted comment.
+type minisignVerifier struct {
+	keyID [8]byte
+	key   ed25519.PublicKey
+}
+
+func newMinisignVerifier(data []byte) (*minisignVerifier, error) {
+	// The key file holds an untrusted comment and the key, or only the key.
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[len(lines)-1]))
+	if err != nil || len(raw) != 2+8+ed25519.PublicKeySize || string(raw[:2]) != "Ed" {
+		return nil, errors.New("invalid config public key: not a PEM public key or a minisign public key")
+	}
+	v := &minisignVerifier{key: ed25519.PublicKey(raw[10:])}
+	copy(v.keyID[:], raw[2:10])
+	return v, nil
+}
+
+func (v *minisignVerifier) suffix() string { return ".minisig" }
+
+func (v *minisignVerifier) verify(data, signature []byte) error {
+	lines := strings.Split(strings.TrimSpace(string(signature)), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return errors.New("invalid minisign signature file")
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(sig) != 2+8+ed25519.SignatureSize {
+		return errors.New("invalid minisign signature")
+	}
+	if !bytes.Equal(sig[2:10], v.keyID[:]) {
+		return errors.New("signature was made with another key than the config public key")
+	}
+	message := data
+	switch string(sig[:2]) {
+	case "Ed":
+	case "ED":
+		// Pre-hashed, the default of minisign since 0.10.
+		digest := blake2b.Sum512(data)
+		message = digest[:]
+	default:
+		return fmt.Errorf("unsupported minisign signature algorithm %q", sig[:2])
+	}
+	if !ed25519.Verify(v.key, message, sig[10:]) {
+		return errors.New("signature does not match the config public key")
+	}
+
+	globalSig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil {
+		return errors.New("invalid minisign global signature")
+	}
+	trustedComment := strings.TrimPrefix(strings.TrimRight(lines[2], "\r"), "trusted comment: ")
+	signed := append(append([]byte{}, sig[10:]...), trustedComment...)
+	if !ed25519.Verify(v.key, signed, globalSig) {
+		return errors.New("trusted comment signature does not match the config public key")
+	}
+	return nil
+}
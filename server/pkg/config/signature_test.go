@@ -0,0 +1,172 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+)
+
+func pemPublicKey(t *testing.T, key crypto.PublicKey) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+// minisignKey is a minisign key pair, as generated by `minisign -G`.
+type minisignKey struct {
+	id   []byte
+	priv ed25519.PrivateKey
+}
+
+func newMinisignKey(t *testing.T) *minisignKey {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return &minisignKey{id: []byte("keyid123"), priv: priv}
+}
+
+func (k *minisignKey) publicKey() []byte {
+	raw := append(append([]byte("Ed"), k.id...), k.priv.Public().(ed25519.PublicKey)...)
+	return []byte("untrusted comment: minisign public key\n" + base64.StdEncoding.EncodeToString(raw) + "\n")
+}
+
+// sign returns a signature file of `minisign -S`, pre-hashed unless legacy.
+func (k *minisignKey) sign(data []byte, trustedComment string, legacy bool) []byte {
+	alg, message := "ED", data
+	if legacy {
+		alg = "Ed"
+	} else {
+		digest := blake2b.Sum512(data)
+		message = digest[:]
+	}
+	sig := ed25519.Sign(k.priv, message)
+	globalSig := ed25519.Sign(k.priv, append(append([]byte{}, sig...), trustedComment...))
+	raw := append(append([]byte(alg), k.id...), sig...)
+	return []byte("untrusted comment: signature from minisign secret key\n" +
+		base64.StdEncoding.EncodeToString(raw) + "\n" +
+		"trusted comment: " + trustedComment + "\n" +
+		base64.StdEncoding.EncodeToString(globalSig) + "\n")
+}
+
+func TestSetConfigPublicKey_Invalid(t *testing.T) {
+	t.Cleanup(func() { remoteConfigVerifier.Store(nil) })
+	assert.EqualError(t, setConfigPublicKey([]byte("not a key")), "invalid config public key: not a PEM public key or a minisign public key")
+	assert.EqualError(t, setConfigPublicKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte{1}})), `invalid config public key: unexpected PEM block "PRIVATE KEY"`)
+	require.NoError(t, setConfigPublicKey(nil))
+	assert.Nil(t, remoteConfigVerifier.Load())
+}
+
+func TestCosignVerifier(t *testing.T) {
+	data := []byte("upstream_services: []\n")
+	digest := sha256.Sum256(data)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecSig, err := ecdsa.SignASN1(rand.Reader, ecKey, digest[:])
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rsaSig, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		key crypto.PublicKey
+		sig []byte
+	}{
+		"ecdsa":   {&ecKey.PublicKey, ecSig},
+		"rsa":     {&rsaKey.PublicKey, rsaSig},
+		"ed25519": {edPub, ed25519.Sign(edPriv, data)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			block, _ := pem.Decode(pemPublicKey(t, tc.key))
+			v, err := newCosignVerifier(block)
+			require.NoError(t, err)
+			assert.Equal(t, ".sig", v.suffix())
+			signature := []byte(base64.StdEncoding.EncodeToString(tc.sig) + "\n")
+			assert.NoError(t, v.verify(data, signature))
+			assert.EqualError(t, v.verify([]byte("upstream_services: [evil]\n"), signature), "signature does not match the config public key")
+		})
+	}
+}
+
+func TestMinisignVerifier(t *testing.T) {
+	data := []byte("upstream_services: []\n")
+	key := newMinisignKey(t)
+	v, err := newMinisignVerifier(key.publicKey())
+	require.NoError(t, err)
+	assert.Equal(t, ".minisig", v.suffix())
+
+	assert.NoError(t, v.verify(data, key.sign(data, "timestamp:1760000000", false)))
+	assert.NoError(t, v.verify(data, key.sign(data, "timestamp:1760000000", true)))
+	assert.EqualError(t, v.verify([]byte("tampered"), key.sign(data, "c", false)), "signature does not match the config public key")
+
+	other := newMinisignKey(t)
+	other.id = []byte("otherkey")
+	assert.EqualError(t, v.verify(data, other.sign(data, "c", false)), "signature was made with another key than the config public key")
+
+	// The trusted comment is signed too.
+	lines := strings.Split(string(key.sign(data, "file:config.yaml", false)), "\n")
+	lines[2] = "trusted comment: file:evil.yaml"
+	assert.EqualError(t, v.verify(data, []byte(strings.Join(lines, "\n"))), "trusted comment signature does not match the config public key")
+}
+
+func TestReadURL_Signature(t *testing.T) {
+	data := []byte("upstream_services: []\n")
+	key := newMinisignKey(t)
+	signature := key.sign(data, "c", false)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/config.yaml", "/unsigned.yaml":
+			_, _ = w.Write(data)
+		case "/config.yaml.minisig":
+			_, _ = w.Write(signature)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	originalClient := httpClient
+	t.Cleanup(func() {
+		httpClient = originalClient
+		remoteConfigVerifier.Store(nil)
+	})
+	httpClient = server.Client()
+
+	body, err := readURL(context.Background(), server.URL+"/unsigned.yaml")
+	require.NoError(t, err, "without a public key, configs are not verified")
+	assert.Equal(t, data, body)
+
+	require.NoError(t, setConfigPublicKey(key.publicKey()))
+	body, err = readURL(context.Background(), server.URL+"/config.yaml?v=2")
+	require.NoError(t, err)
+	assert.Equal(t, data, body)
+
+	_, err = readURL(context.Background(), server.URL+"/unsigned.yaml")
+	assert.ErrorContains(t, err, "failed to get the signature of config from url")
+
+	signature = key.sign([]byte("other"), "c", false)
+	_, err = readURL(context.Background(), server.URL+"/config.yaml")
+	assert.ErrorContains(t, err, "invalid signature of config from url")
+}
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -20,6 +21,7 @@ import (
 
 	configv1 "github.com/mcpany/core/proto/config/v1"
 	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/sops"
 	"github.com/mcpany/core/server/pkg/util"
 	"github.com/spf13/afero"
 	"golang.org/x/sync/errgroup"
@@ -103,6 +105,8 @@ func NewEngine(path string) (Engine, error) {
 	switch ext {
 	case ".json":
 		return &jsonEngine{}, nil
+	case ".toml":
+		return &tomlEngine{yamlEngine{keyLine: findTOMLKeyLine}}, nil
 	case ".yaml", ".yml":
 		return &yamlEngine{}, nil
 	case ".textproto", ".prototxt", ".pb", ".pb.txt":
@@ -113,9 +117,14 @@ func NewEngine(path string) (Engine, error) {
 }
 
 // yamlEngine implements the Engine interface for YAML configuration files.
+// The JSON and TOML engines embed it, so that the three formats get the same
+// overrides, schema validation and error reporting.
 type yamlEngine struct {
 	skipValidation bool
 	ignoreEnv      bool
+	// keyLine returns the line of a key in the document, or 0. It is
+	// findKeyLine if nil.
+	keyLine func(b []byte, key string) int
 }
 
 // SetSkipValidation sets whether to skip schema validation.
@@ -230,9 +239,13 @@ func (e *yamlEngine) unmarshalInternal(yamlMap map[string]interface{}, v proto.M
 	if err := protojson.Unmarshal(jsonData, v); err != nil {
 		// Attempt to find the line number in the original YAML
 		if originalBytes != nil {
+			keyLine := e.keyLine
+			if keyLine == nil {
+				keyLine = findKeyLine
+			}
 			if matches := unknownFieldRegex.FindStringSubmatch(err.Error()); len(matches) > 1 {
 				unknownField := matches[1]
-				if line := findKeyLine(originalBytes, unknownField); line > 0 {
+				if line := keyLine(originalBytes, unknownField); line > 0 {
 					err = fmt.Errorf("line %d: %w", line, err)
 				}
 			}
@@ -316,52 +329,24 @@ func (e *textprotoEngine) Unmarshal(b []byte, v proto.Message) error {
 }
 
 // jsonEngine implements the Engine interface for JSON configuration files.
-type jsonEngine struct{}
+type jsonEngine struct {
+	yamlEngine
+}
 
 // Unmarshal parses a JSON byte slice into a `proto.Message`.
 //
 // Parameters:
-//   - b ([]byte): The parameter.
-//   - v (proto.Message): The parameter.
+//   - b ([]byte): The JSON document.
+//   - v (proto.Message): The destination message.
 //
 // Returns:
-//   - error: An error if the operation fails.
-//
-// Errors:
-//   - Returns an error if ...
-//
-// Side Effects:
-//   - None.
+//   - error: An error if the document is not valid JSON, or does not match the schema.
 func (e *jsonEngine) Unmarshal(b []byte, v proto.Message) error {
-	if err := protojson.Unmarshal(b, v); err != nil {
-		// Detect if the user is using Claude Desktop config format
-		if strings.Contains(err.Error(), "unknown field \"mcpServers\"") {
-			// revive:disable-next-line:error-strings // This error message is user facing and needs to be descriptive
-
-			return fmt.Errorf("%w\n\nDid you mean \"upstream_services\"? It looks like you might be using a Claude Desktop configuration format. MCP Any uses a different configuration structure. See documentation for details.", err) //nolint:revive // Long user-facing error message
-		}
-
-		// Detect if the user is using "services" which is a common alias for "upstream_services"
-		if strings.Contains(err.Error(), "unknown field \"services\"") {
-			// revive:disable-next-line:error-strings // This error message is user facing and needs to be descriptive
-
-			return fmt.Errorf("%w\n\nDid you mean \"upstream_services\"? \"services\" is not a valid top-level key.", err) //nolint:revive // Long user-facing error message
-		}
-
-		// Check for unknown fields and suggest fuzzy matches
-		if strings.Contains(err.Error(), "unknown field") {
-			matches := unknownFieldRegex.FindStringSubmatch(err.Error())
-			if len(matches) > 1 {
-				unknownField := matches[1]
-				suggestion := suggestFix(unknownField, v)
-				if suggestion != "" {
-					return fmt.Errorf("%w\n\n%s", err, suggestion)
-				}
-			}
-		}
+	m, err := decodeJSON(b)
+	if err != nil {
 		return err
 	}
-	return nil
+	return e.unmarshalInternal(m, v, b)
 }
 
 // Store defines the interface for loading MCP-X server configurations.
@@ -448,7 +433,8 @@ var unknownFieldRegex = regexp.MustCompile(`unknown field "([^"]+)"`)
 
 const maxExpandRecursionDepth = 100
 
-// expand replaces ${VAR}, $VAR, or ${VAR:default} with environment variables.
+// expand replaces ${VAR}, $VAR, or ${VAR:default} with environment variables,
+// and evaluates template expressions such as ${VAR | default "x" | upper}.
 //
 // Summary: Expands environment variables in a byte slice.
 //
@@ -459,16 +445,30 @@ const maxExpandRecursionDepth = 100
 //   - ([]byte): The expanded bytes.
 //   - (error): An error if expansion fails or recursion limit is exceeded.
 func expand(b []byte) ([]byte, error) {
-	return expandRecursive(b, 0)
+	return expandRecursive(b, 0, false)
+}
+
+// expandRemote expands a config loaded from a URL or a key-value store, where
+// the template functions reading local files are disabled.
+func expandRemote(b []byte) ([]byte, error) {
+	return expandRecursive(b, 0, true)
 }
 
-func expandRecursive(b []byte, depth int) ([]byte, error) {
+// expansion collects the errors of an expansion.
+type expansion struct {
+	remote       bool
+	missing      strings.Builder
+	missingCount int
+	invalid      strings.Builder
+	invalidCount int
+}
+
+func expandRecursive(b []byte, depth int, remote bool) ([]byte, error) {
 	if depth > maxExpandRecursionDepth {
 		return nil, fmt.Errorf("environment variable expansion recursion depth exceeded (max %d)", maxExpandRecursionDepth)
 	}
 
-	var missingErrBuilder strings.Builder
-	missingCount := 0
+	e := &expansion{remote: remote}
 
 	var buf bytes.Buffer
 	// Estimate capacity
@@ -493,7 +493,7 @@ func expandRecursive(b []byte, depth int) ([]byte, error) {
 
 		// Case 1: ${...}
 		if b[i+1] == '{' {
-			consumed := handleBracedVar(b, i, &buf, &missingErrBuilder, &missingCount, depth)
+			consumed := handleBracedVar(b, i, &buf, e, depth)
 			if consumed > 0 {
 				i += consumed
 				continue
@@ -505,7 +505,7 @@ func expandRecursive(b []byte, depth int) ([]byte, error) {
 		}
 
 		// Case 2: $VAR (alphanumeric + _)
-		consumed := handleSimpleVar(b, i, &buf, &missingErrBuilder, &missingCount)
+		consumed := handleSimpleVar(b, i, &buf, e)
 		if consumed > 0 {
 			i += consumed
 			continue
@@ -516,16 +516,28 @@ func expandRecursive(b []byte, depth int) ([]byte, error) {
 		i++
 	}
 
-	if missingCount > 0 {
-		// revive:disable-next-line:error-strings // This error message is user facing and needs to be descriptive
-
-		return buf.Bytes(), fmt.Errorf("missing environment variables:%s\n    -> Fix: Set these environment variables in your shell or .env file, or provide a default value (e.g., ${VAR:default}).", missingErrBuilder.String()) //nolint:revive // Long user-facing error message
+	var errs []string
+	if e.missingCount > 0 {
+		errs = append(errs, fmt.Sprintf("missing environment variables:%s\n    -> Fix: Set these environment variables in your shell or .env file, or provide a default value (e.g., ${VAR:default}).", e.missing.String())) //nolint:revive // Long user-facing error message
+	}
+	if e.invalidCount > 0 {
+		errs = append(errs, "invalid template expressions:"+e.invalid.String())
+	}
+	if len(errs) > 0 {
+		return buf.Bytes(), errors.New(strings.Join(errs, "\n"))
 	}
 
 	return buf.Bytes(), nil
 }
 
-func handleBracedVar(b []byte, startIdx int, buf *bytes.Buffer, missingErrBuilder *strings.Builder, missingCount *int, recursionDepth int) int {
+// position returns the line and column of an offset, starting at 1.
+func position(b []byte, offset int) (int, int) {
+	line := bytes.Count(b[:offset], []byte("\n")) + 1
+	column := offset - bytes.LastIndexByte(b[:offset], '\n')
+	return line, column
+}
+
+func handleBracedVar(b []byte, startIdx int, buf *bytes.Buffer, e *expansion, recursionDepth int) int {
 	// Find matching '}' accounting for nesting
 	innerStart := startIdx + 2
 	depth := 1
@@ -549,6 +561,11 @@ func handleBracedVar(b []byte, startIdx int, buf *bytes.Buffer, missingErrBuilde
 
 	// Content inside ${...}
 	content := string(b[innerStart:j])
+	if isTemplateExpr(content) {
+		handleTemplateExpr(b, startIdx, j, content, buf, e)
+		return j + 1 - startIdx
+	}
+
 	parts := strings.SplitN(content, ":", 2)
 	varName := parts[0]
 	var hasDefault bool
@@ -560,9 +577,9 @@ func handleBracedVar(b []byte, startIdx int, buf *bytes.Buffer, missingErrBuilde
 	}
 
 	if !util.IsEnvVarAllowed(varName) {
-		*missingCount++
+		e.missingCount++
 		lineNum := bytes.Count(b[:startIdx], []byte("\n")) + 1
-		fmt.Fprintf(missingErrBuilder, "\n  - Line %d: variable %s is restricted", lineNum, varName)
+		fmt.Fprintf(&e.missing, "\n  - Line %d: variable %s is restricted", lineNum, varName)
 		// Write the original string to preserve structure
 		buf.Write(b[startIdx : j+1])
 		return j + 1 - startIdx
@@ -570,9 +587,9 @@ func handleBracedVar(b []byte, startIdx int, buf *bytes.Buffer, missingErrBuilde
 
 	val, ok := os.LookupEnv(varName)
 	if !ok && !hasDefault {
-		*missingCount++
+		e.missingCount++
 		lineNum := bytes.Count(b[:startIdx], []byte("\n")) + 1
-		fmt.Fprintf(missingErrBuilder, "\n  - Line %d: variable %s is missing", lineNum, varName)
+		fmt.Fprintf(&e.missing, "\n  - Line %d: variable %s is missing", lineNum, varName)
 		// Write the original string to preserve structure
 		buf.Write(b[startIdx : j+1])
 		return j + 1 - startIdx
@@ -581,14 +598,14 @@ func handleBracedVar(b []byte, startIdx int, buf *bytes.Buffer, missingErrBuilde
 	useDefault := (ok && val == "" && hasDefault) || (!ok && hasDefault)
 
 	if useDefault {
-		expanded, err := expandRecursive([]byte(defaultValue), recursionDepth+1)
+		expanded, err := expandRecursive([]byte(defaultValue), recursionDepth+1, e.remote)
 		if err != nil {
-			*missingCount++
+			e.missingCount++
 			// Clean up error message from recursive call
 			errMsg := err.Error()
 			prefix := "missing environment variables:"
 			errMsg = strings.TrimPrefix(errMsg, prefix)
-			fmt.Fprintf(missingErrBuilder, "\n  - In default value for %s:%s", varName, errMsg)
+			fmt.Fprintf(&e.missing, "\n  - In default value for %s:%s", varName, errMsg)
 		}
 		buf.Write(expanded)
 	} else {
@@ -598,7 +615,27 @@ func handleBracedVar(b []byte, startIdx int, buf *bytes.Buffer, missingErrBuilde
 	return j + 1 - startIdx
 }
 
-func handleSimpleVar(b []byte, startIdx int, buf *bytes.Buffer, missingErrBuilder *strings.Builder, missingCount *int) int {
+// handleTemplateExpr evaluates the template expression of ${...} from startIdx
+// to endIdx, the index of the closing brace.
+func handleTemplateExpr(b []byte, startIdx, endIdx int, expr string, buf *bytes.Buffer, e *expansion) {
+	value, err := (&templateEval{remote: e.remote}).eval(expr)
+	line, column := position(b, startIdx)
+	switch {
+	case err != nil:
+		e.invalidCount++
+		fmt.Fprintf(&e.invalid, "\n  - Line %d, column %d: %s: %v", line, column, b[startIdx:endIdx+1], err)
+	case value.missing != "":
+		e.missingCount++
+		fmt.Fprintf(&e.missing, "\n  - Line %d: variable %s is missing", line, value.missing)
+	default:
+		buf.WriteString(value.value)
+		return
+	}
+	// Write the original string to preserve structure
+	buf.Write(b[startIdx : endIdx+1])
+}
+
+func handleSimpleVar(b []byte, startIdx int, buf *bytes.Buffer, e *expansion) int {
 	// Scan for variable name
 	// First char must be [a-zA-Z_]
 	if startIdx+1 >= len(b) {
@@ -623,9 +660,9 @@ func handleSimpleVar(b []byte, startIdx int, buf *bytes.Buffer, missingErrBuilde
 
 	varName := string(b[startIdx+1 : j])
 	if !util.IsEnvVarAllowed(varName) {
-		*missingCount++
+		e.missingCount++
 		lineNum := bytes.Count(b[:startIdx], []byte("\n")) + 1
-		fmt.Fprintf(missingErrBuilder, "\n  - Line %d: variable %s is restricted", lineNum, varName)
+		fmt.Fprintf(&e.missing, "\n  - Line %d: variable %s is restricted", lineNum, varName)
 		// Write the original string to preserve structure
 		buf.Write(b[startIdx:j])
 		return j - startIdx
@@ -633,9 +670,9 @@ func handleSimpleVar(b []byte, startIdx int, buf *bytes.Buffer, missingErrBuilde
 
 	val, ok := os.LookupEnv(varName)
 	if !ok {
-		*missingCount++
+		e.missingCount++
 		lineNum := bytes.Count(b[:startIdx], []byte("\n")) + 1
-		fmt.Fprintf(missingErrBuilder, "\n  - Line %d: variable %s is missing", lineNum, varName)
+		fmt.Fprintf(&e.missing, "\n  - Line %d: variable %s is missing", lineNum, varName)
 		// Write the original string to preserve structure
 		buf.Write(b[startIdx:j])
 		return j - startIdx
@@ -654,6 +691,7 @@ type FileStore struct {
 	skipErrors       bool
 	IgnoreMissingEnv bool
 	skipValidation   bool
+	environment      string
 }
 
 // SetSkipValidation configures whether to skip schema validation during loading.
@@ -689,7 +727,7 @@ func (s *FileStore) SetIgnoreMissingEnv(ignore bool) {
 // Returns:
 //   - (*FileStore): A new instance of FileStore.
 func NewFileStore(fs afero.Fs, paths []string) *FileStore {
-	return &FileStore{fs: fs, paths: paths}
+	return &FileStore{fs: fs, paths: paths, environment: currentEnvironment()}
 }
 
 // NewFileStoreWithSkipErrors creates a new FileStore that skips malformed config files.
@@ -703,7 +741,7 @@ func NewFileStore(fs afero.Fs, paths []string) *FileStore {
 // Returns:
 //   - (*FileStore): A new instance of FileStore.
 func NewFileStoreWithSkipErrors(fs afero.Fs, paths []string) *FileStore {
-	return &FileStore{fs: fs, paths: paths, skipErrors: true}
+	return &FileStore{fs: fs, paths: paths, skipErrors: true, environment: currentEnvironment()}
 }
 
 // HasConfigSources returns true if the store has configuration paths configured. Side Effects: - None.
@@ -738,20 +776,35 @@ func (s *FileStore) Load(ctx context.Context) (*configv1.McpAnyServerConfig, err
 	if err != nil {
 		return nil, fmt.Errorf("failed to collect config file paths: %w", err)
 	}
+	filePaths, overlays := s.applyEnvironment(filePaths)
 
 	// ⚡ BOLT: Parallelized config loading using errgroup.
 	// Randomized Selection from Top 5 High-Impact Targets
 	configs := make([]*configv1.McpAnyServerConfig, len(filePaths))
+	included := make([][]string, len(filePaths))
 	g, ctx := errgroup.WithContext(ctx)
 
 	for i, path := range filePaths {
 		i, path := i, path // Capture loop variables
 		g.Go(func() error {
-			cfg, err := s.loadOneConfig(ctx, path)
+			loader := newIncludeLoader(s)
+			cfg, err := loader.load(ctx, path)
 			if err != nil {
 				return err
 			}
+			if overlay, ok := overlays[path]; ok {
+				overlayCfg, err := loader.load(ctx, overlay)
+				if err != nil {
+					return err
+				}
+				if cfg == nil {
+					cfg = overlayCfg
+				} else if overlayCfg != nil {
+					mergeOverlay(cfg, overlayCfg)
+				}
+			}
 			configs[i] = cfg
+			included[i] = loader.included(path, overlays[path])
 			return nil
 		})
 	}
@@ -760,6 +813,27 @@ func (s *FileStore) Load(ctx context.Context) (*configv1.McpAnyServerConfig, err
 		return nil, err
 	}
 
+	// A file included by another one, such as a file of the same directory,
+	// is merged where it is included only.
+	includedKeys := make(map[string]bool)
+	for _, keys := range included {
+		recordIncludes(keys)
+		for _, key := range keys {
+			includedKeys[key] = true
+		}
+	}
+	for i, path := range filePaths {
+		if includedKeys[includeKey(path)] {
+			configs[i] = nil
+		}
+	}
+
+	return mergeConfigs(configs), nil
+}
+
+// mergeConfigs merges the configurations in order, applying the merge
+// strategy of each one. Nil configurations are skipped.
+func mergeConfigs(configs []*configv1.McpAnyServerConfig) *configv1.McpAnyServerConfig {
 	var mergedConfig *configv1.McpAnyServerConfig
 	for _, cfg := range configs {
 		if cfg == nil {
@@ -788,7 +862,7 @@ func (s *FileStore) Load(ctx context.Context) (*configv1.McpAnyServerConfig, err
 		}
 	}
 
-	return mergedConfig, nil
+	return mergedConfig
 }
 
 // httpClient is a safe http client for loading configurations.
@@ -803,7 +877,32 @@ var httpClient = func() *http.Client {
 	return client
 }()
 
+// readURL reads a config from a URL, and verifies its signature when a config
+// public key is set.
 func readURL(ctx context.Context, url string) ([]byte, error) {
+	body, err := fetchURL(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	verifier := remoteConfigVerifier.Load()
+	if verifier == nil {
+		return body, nil
+	}
+	sigURL, err := signatureURL(url, (*verifier).suffix())
+	if err != nil {
+		return nil, err
+	}
+	signature, err := fetchURL(ctx, sigURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the signature of config from url %s: %w", url, err)
+	}
+	if err := (*verifier).verify(body, signature); err != nil {
+		return nil, fmt.Errorf("invalid signature of config from url %s: %w", url, err)
+	}
+	return body, nil
+}
+
+func fetchURL(ctx context.Context, url string) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request for url %s: %w", url, err)
@@ -834,7 +933,7 @@ func readURL(ctx context.Context, url string) ([]byte, error) {
 func (s *FileStore) collectFilePaths() ([]string, error) {
 	var files []string
 	for _, path := range s.paths {
-		if isURL(path) {
+		if IsRemotePath(path) {
 			files = append(files, path)
 			continue
 		}
@@ -1163,7 +1262,15 @@ func fixTypes(m map[string]interface{}, md protoreflect.MessageDescriptor) {
 			if valSlice, ok := val.([]interface{}); ok {
 				if fd.Kind() == protoreflect.MessageKind {
 					msgDesc := fd.Message()
-					for _, item := range valSlice {
+					for i, item := range valSlice {
+						// A bare name stands for an empty message field, e.g.
+						// "transforms: [html_to_markdown]".
+						if name, ok := item.(string); ok {
+							if f := findField(msgDesc, name); f != nil && f.Kind() == protoreflect.MessageKind && !f.IsList() && !f.IsMap() {
+								item = map[string]interface{}{name: map[string]interface{}{}}
+								valSlice[i] = item
+							}
+						}
 						if itemMap, ok := item.(map[string]interface{}); ok {
 							fixTypes(itemMap, msgDesc)
 						}
@@ -1303,7 +1410,9 @@ func suggestFix(unknownField string, root proto.Message) string {
 		// ⚡ BOLT: Replaced inefficient local Levenshtein implementation with optimized utility function.
 		// Randomized Selection from Top 5 High-Impact Targets
 		dist := util.LevenshteinDistance(unknownField, name)
-		if dist < minDist {
+		// Break ties by name, so that the suggestion does not depend on the
+		// order of the map.
+		if dist < minDist || (dist == minDist && name < bestMatch) {
 			minDist = dist
 			bestMatch = name
 		}
@@ -1463,6 +1572,10 @@ func unquoteCSV(s string) string {
 }
 
 func (s *FileStore) loadOneConfig(ctx context.Context, path string) (*configv1.McpAnyServerConfig, error) {
+	if isKVURL(path) {
+		return s.loadKV(ctx, path)
+	}
+
 	var b []byte
 	var err error
 	if isURL(path) {
@@ -1477,11 +1590,38 @@ func (s *FileStore) loadOneConfig(ctx context.Context, path string) (*configv1.M
 		}
 	}
 
+	return s.parseConfig(path, b)
+}
+
+// parseConfig decrypts, expands and unmarshals the document read from the
+// path. The path selects the format by its extension.
+func (s *FileStore) parseConfig(path string, b []byte) (*configv1.McpAnyServerConfig, error) {
 	if len(b) == 0 {
 		return nil, nil
 	}
 
-	b, err = expand(b)
+	var err error
+	// SOPS files are decrypted before environment variables are expanded, as
+	// their MAC covers the values as written.
+	if sops.IsEncrypted(b) {
+		format, err := sops.FormatForPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt config file %s: %w", path, err)
+		}
+		b, err = sops.Decrypt(b, format)
+		if err != nil {
+			return nil, &ActionableError{
+				Err:        fmt.Errorf("failed to decrypt sops config file %s: %w", path, err),
+				Suggestion: "Set SOPS_AGE_KEY_FILE (or SOPS_AGE_KEY) to the age identity the file was encrypted to, or grant this host access to its KMS key.",
+			}
+		}
+	}
+
+	if IsRemotePath(path) {
+		b, err = expandRemote(b)
+	} else {
+		b, err = expand(b)
+	}
 	if err != nil {
 		if !s.IgnoreMissingEnv {
 			return nil, WrapActionableError(fmt.Sprintf("failed to expand environment variables in %s", path), err)
@@ -84,3 +84,23 @@ global_settings:
 	assert.Equal(t, configv1.GlobalSettings_LOG_LEVEL_INFO, cfg.GetGlobalSettings().GetLogLevel())
 	assert.Equal(t, "my-key", cfg.GetGlobalSettings().GetApiKey())
 }
+
+func TestYamlEngine_Unmarshal_BareMessageName(t *testing.T) {
+	engine := &yamlEngine{ignoreEnv: true}
+	yamlData := []byte(`
+upstream_services:
+  - name: web
+    http_service:
+      address: http://example.com
+      tools:
+        - name: fetch
+          transforms: [html_to_markdown, truncate: {max_chars: 4000}]
+`)
+	cfg := configv1.McpAnyServerConfig_builder{}.Build()
+	require.NoError(t, engine.Unmarshal(yamlData, cfg))
+
+	transforms := cfg.GetUpstreamServices()[0].GetHttpService().GetTools()[0].GetTransforms()
+	require.Len(t, transforms, 2)
+	assert.True(t, transforms[0].HasHtmlToMarkdown())
+	assert.Equal(t, int32(4000), transforms[1].GetTruncate().GetMaxChars())
+}
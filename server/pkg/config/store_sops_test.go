@@ -0,0 +1,88 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mcpany/core/server/pkg/sops"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAgeIdentity  = "AGE-SECRET-KEY-1GFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPQ4EGAEX"
+	testAgeRecipient = "age1zvkyg2lqzraa2lnjvqej32nkuu0ues2s82hzrye869xeexvn73equnujwj"
+)
+
+// unsetSOPSAgeKeys unsets the age key variables of sops for the test. sops
+// reads SOPS_AGE_KEY_FILE whenever it is set, even to "".
+func unsetSOPSAgeKeys(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{"SOPS_AGE_KEY_FILE", "SOPS_AGE_KEY"} {
+		t.Setenv(name, "")
+		require.NoError(t, os.Unsetenv(name))
+	}
+}
+
+func TestFileStore_LoadSOPS(t *testing.T) {
+	unsetSOPSAgeKeys(t)
+	t.Setenv("SOPS_AGE_KEY", testAgeIdentity)
+	t.Setenv("SOPS_TEST_ADDRESS", "https://orders.example.com")
+
+	plain := `upstream_services:
+  - name: orders
+    http_service:
+      address: "${SOPS_TEST_ADDRESS}"
+    upstream_auth:
+      api_key:
+        param_name: X-API-Key
+        value:
+          plain_text: s3cr3t
+`
+	encrypted, err := sops.Encrypt([]byte(plain), sops.FormatYAML, sops.EncryptOptions{AgeRecipients: []string{testAgeRecipient}})
+	require.NoError(t, err)
+	require.NotContains(t, string(encrypted), "s3cr3t")
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/config/secrets.enc.yaml", encrypted, 0o600))
+
+	cfg, err := NewFileStore(fs, []string{"/config"}).Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, cfg.GetUpstreamServices(), 1)
+	service := cfg.GetUpstreamServices()[0]
+	assert.Equal(t, "https://orders.example.com", service.GetHttpService().GetAddress())
+	assert.Equal(t, "s3cr3t", service.GetUpstreamAuth().GetApiKey().GetValue().GetPlainText())
+
+	// The file on disk stays encrypted.
+	onDisk, err := afero.ReadFile(fs, "/config/secrets.enc.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, encrypted, onDisk)
+}
+
+func TestFileStore_LoadSOPS_Errors(t *testing.T) {
+	unsetSOPSAgeKeys(t)
+	encrypted, err := sops.Encrypt([]byte("global_settings:\n  log_level: LOG_LEVEL_DEBUG\n"), sops.FormatYAML, sops.EncryptOptions{AgeRecipients: []string{testAgeRecipient}})
+	require.NoError(t, err)
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/config/config.yaml", encrypted, 0o600))
+	_, err = NewFileStore(fs, []string{"/config/config.yaml"}).Load(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decrypt sops config file /config/config.yaml")
+	var ae *ActionableError
+	require.ErrorAs(t, err, &ae)
+	assert.Contains(t, ae.Suggestion, "SOPS_AGE_KEY_FILE")
+
+	t.Setenv("SOPS_AGE_KEY", testAgeIdentity)
+	tampered := strings.Replace(string(encrypted), "lastmodified: ", "lastmodified: \"2020-01-01T00:00:00Z\"\n    old_lastmodified: ", 1)
+	require.NoError(t, afero.WriteFile(fs, "/config/config.yaml", []byte(tampered), 0o600))
+	_, err = NewFileStore(fs, []string{"/config/config.yaml"}).Load(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "original mac")
+}
@@ -0,0 +1,299 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mcpany/core/server/pkg/util"
+	"github.com/mcpany/core/server/pkg/validation"
+)
+
+// isTemplateExpr reports whether the content of ${...} is a template
+// expression, such as ${PORT | default "8080"}, rather than a variable with an
+// optional default, such as ${PORT:8080}.
+func isTemplateExpr(content string) bool {
+	name, _, _ := strings.Cut(content, ":")
+	return strings.ContainsAny(name, " \t|\"'")
+}
+
+// templateValue is the value flowing through a template pipeline.
+type templateValue struct {
+	value string
+	// missing is the name of the environment variable the value was read
+	// from, if it is not set.
+	missing string
+}
+
+// templateFunc is a template function. The value piped into it is its last
+// argument.
+type templateFunc struct {
+	// args is the number of arguments, including the piped value.
+	args int
+	call func(t *templateEval, args []templateValue) (templateValue, error)
+}
+
+// templateFuncs are the functions of template expressions.
+var templateFuncs map[string]templateFunc
+
+func init() {
+	// Initialized here to break the initialization cycle through eval.
+	templateFuncs = map[string]templateFunc{
+		"env": {1, func(t *templateEval, args []templateValue) (templateValue, error) {
+			return t.lookupEnv(args[0].value)
+		}},
+		"default": {2, func(_ *templateEval, args []templateValue) (templateValue, error) {
+			if args[1].value == "" {
+				return templateValue{value: args[0].value}, nil
+			}
+			return templateValue{value: args[1].value}, nil
+		}},
+		"required": {2, func(_ *templateEval, args []templateValue) (templateValue, error) {
+			if args[1].value == "" {
+				return templateValue{}, errors.New(args[0].value)
+			}
+			return args[1], nil
+		}},
+		"file": {1, func(t *templateEval, args []templateValue) (templateValue, error) {
+			return t.readFile(args[0])
+		}},
+		"base64": {1, mapValue(func(s string) (string, error) {
+			return base64.StdEncoding.EncodeToString([]byte(s)), nil
+		})},
+		"base64decode": {1, mapValue(func(s string) (string, error) {
+			b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+			if err != nil {
+				return "", fmt.Errorf("base64decode: %w", err)
+			}
+			return string(b), nil
+		})},
+		"lower": {1, mapValue(func(s string) (string, error) { return strings.ToLower(s), nil })},
+		"upper": {1, mapValue(func(s string) (string, error) { return strings.ToUpper(s), nil })},
+		"trim":  {1, mapValue(func(s string) (string, error) { return strings.TrimSpace(s), nil })},
+		"quote": {1, mapValue(func(s string) (string, error) { return strconv.Quote(s), nil })},
+		"host":  {1, mapValue(splitHost)},
+		"port":  {1, mapValue(splitPort)},
+	}
+}
+
+// mapValue returns the call of a function of the piped value only. A missing
+// value stays missing.
+func mapValue(fn func(string) (string, error)) func(*templateEval, []templateValue) (templateValue, error) {
+	return func(_ *templateEval, args []templateValue) (templateValue, error) {
+		if args[0].missing != "" {
+			return args[0], nil
+		}
+		value, err := fn(args[0].value)
+		return templateValue{value: value}, err
+	}
+}
+
+// hostPort splits an address, host:port or a URL, into its host and port. The
+// port of a URL defaults to the one of its scheme.
+func hostPort(address string) (string, string, error) {
+	if strings.Contains(address, "://") {
+		u, err := url.Parse(address)
+		if err != nil {
+			return "", "", err
+		}
+		port := u.Port()
+		if port == "" {
+			switch strings.ToLower(u.Scheme) {
+			case "http", "ws":
+				port = "80"
+			case "https", "wss":
+				port = "443"
+			}
+		}
+		return u.Hostname(), port, nil
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		// A host without a port.
+		return strings.Trim(address, "[]"), "", nil
+	}
+	return host, port, nil
+}
+
+func splitHost(address string) (string, error) {
+	host, _, err := hostPort(address)
+	if err != nil {
+		return "", fmt.Errorf("host: %w", err)
+	}
+	return host, nil
+}
+
+func splitPort(address string) (string, error) {
+	_, port, err := hostPort(address)
+	if err != nil {
+		return "", fmt.Errorf("port: %w", err)
+	}
+	if port == "" {
+		return "", fmt.Errorf("port: no port in %q", address)
+	}
+	return port, nil
+}
+
+// templateEval evaluates a template expression.
+type templateEval struct {
+	// remote disables the file function, for the configs loaded from URLs and
+	// key-value stores.
+	remote bool
+}
+
+// eval evaluates a pipeline of commands separated by "|": a variable name, a
+// quoted string or a function call, piped into function calls.
+func (t *templateEval) eval(expr string) (templateValue, error) {
+	tokens, err := tokenizeTemplate(expr)
+	if err != nil {
+		return templateValue{}, err
+	}
+	var commands [][]templateToken
+	command := []templateToken{}
+	for _, token := range tokens {
+		if token.pipe {
+			commands = append(commands, command)
+			command = []templateToken{}
+			continue
+		}
+		command = append(command, token)
+	}
+	commands = append(commands, command)
+
+	var value templateValue
+	for i, command := range commands {
+		if len(command) == 0 {
+			return templateValue{}, errors.New("empty command in pipeline")
+		}
+		head := command[0]
+		if i == 0 && len(command) == 1 {
+			// A single word is a variable, or a string if quoted.
+			if head.quoted {
+				value = templateValue{value: head.text}
+				continue
+			}
+			if _, ok := templateFuncs[head.text]; !ok {
+				if value, err = t.lookupEnv(head.text); err != nil {
+					return templateValue{}, err
+				}
+				continue
+			}
+		}
+		fn, ok := templateFuncs[head.text]
+		if head.quoted || !ok {
+			return templateValue{}, fmt.Errorf("unknown function %q", head.text)
+		}
+		args := make([]templateValue, 0, len(command))
+		for _, arg := range command[1:] {
+			args = append(args, templateValue{value: arg.text})
+		}
+		if i > 0 {
+			args = append(args, value)
+		}
+		if len(args) != fn.args {
+			return templateValue{}, fmt.Errorf("%s takes %d argument(s), got %d", head.text, fn.args, len(args))
+		}
+		if value, err = fn.call(t, args); err != nil {
+			return templateValue{}, err
+		}
+	}
+	return value, nil
+}
+
+func (t *templateEval) lookupEnv(name string) (templateValue, error) {
+	if !util.IsEnvVarAllowed(name) {
+		return templateValue{}, fmt.Errorf("variable %s is restricted", name)
+	}
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return templateValue{missing: name}, nil
+	}
+	return templateValue{value: value}, nil
+}
+
+func (t *templateEval) readFile(path templateValue) (templateValue, error) {
+	if t.remote {
+		return templateValue{}, errors.New("file is not allowed in configs loaded from URLs or key-value stores")
+	}
+	if path.missing != "" {
+		return path, nil
+	}
+	if err := validation.IsSecurePath(path.value); err != nil {
+		return templateValue{}, fmt.Errorf("file: %w", err)
+	}
+	if err := validation.IsSensitivePath(path.value); err != nil {
+		return templateValue{}, fmt.Errorf("file: %w", err)
+	}
+	b, err := os.ReadFile(path.value)
+	if err != nil {
+		return templateValue{}, fmt.Errorf("file: %w", err)
+	}
+	return templateValue{value: strings.TrimRight(string(b), "\r\n")}, nil
+}
+
+// templateToken is a word, a quoted string or a pipe of a template
+// expression.
+type templateToken struct {
+	text   string
+	quoted bool
+	pipe   bool
+}
+
+func tokenizeTemplate(expr string) ([]templateToken, error) {
+	var tokens []templateToken
+	i := 0
+	for i < len(expr) {
+		switch c := expr[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '|':
+			tokens = append(tokens, templateToken{pipe: true})
+			i++
+		case c == '"':
+			// A Go string, with escapes.
+			j := i + 1
+			for j < len(expr) && expr[j] != '"' {
+				if expr[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(expr) {
+				return nil, errors.New("unterminated string")
+			}
+			text, err := strconv.Unquote(expr[i : j+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string %s", expr[i:j+1])
+			}
+			tokens = append(tokens, templateToken{text: text, quoted: true})
+			i = j + 1
+		case c == '\'':
+			// A raw string.
+			j := strings.IndexByte(expr[i+1:], '\'')
+			if j < 0 {
+				return nil, errors.New("unterminated string")
+			}
+			tokens = append(tokens, templateToken{text: expr[i+1 : i+1+j], quoted: true})
+			i += j + 2
+		default:
+			j := i
+			for j < len(expr) && !strings.ContainsRune(" \t\n\r|\"'", rune(expr[j])) {
+				j++
+			}
+			tokens = append(tokens, templateToken{text: expr[i:j]})
+			i = j
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("empty expression")
+	}
+	return tokens, nil
+}
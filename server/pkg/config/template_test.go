@@ -0,0 +1,95 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpand_Template(t *testing.T) {
+	t.Setenv("TPL_HOST", "Example.COM")
+	t.Setenv("TPL_EMPTY", "")
+	t.Setenv("TPL_URL", "https://api.example.com/v1")
+	t.Setenv("TPL_ADDR", "db.internal:5432")
+	t.Setenv("TPL_B64", "c2VjcmV0")
+
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("s3cr3t\n"), 0o600))
+
+	tests := []struct {
+		input, want string
+	}{
+		{`${TPL_HOST | lower}`, "example.com"},
+		{`${ TPL_HOST | upper }`, "EXAMPLE.COM"},
+		{`${TPL_MISSING | default "localhost"}`, "localhost"},
+		{`${TPL_EMPTY | default 'fallback'}`, "fallback"},
+		{`${TPL_HOST | default "x" | lower}`, "example.com"},
+		{`${TPL_HOST | required "TPL_HOST must be set"}`, "Example.COM"},
+		{`${env "TPL_HOST"}`, "Example.COM"},
+		{`${"plain" | base64}`, "cGxhaW4="},
+		{`${TPL_B64 | base64decode}`, "secret"},
+		{`${file "` + tokenFile + `"}`, "s3cr3t"},
+		{`${file "` + tokenFile + `" | base64}`, "czNjcjN0"},
+		{`${TPL_URL | host}:${TPL_URL | port}`, "api.example.com:443"},
+		{`${TPL_ADDR | host}/${TPL_ADDR | port}`, "db.internal/5432"},
+		{`${"[::1]:8080" | host}`, "::1"},
+		{`${"a \"b\"" | quote}`, `"a \"b\""`},
+		{`${"  x  " | trim}`, "x"},
+		// Variables with defaults keep their meaning, even with pipes in the
+		// default value.
+		{`${TPL_MISSING:a|b}`, "a|b"},
+		{`${TPL_MISSING:"quoted"}`, `"quoted"`},
+	}
+	for _, tt := range tests {
+		got, err := expand([]byte(tt.input))
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.want, string(got), tt.input)
+	}
+}
+
+func TestExpand_TemplateErrors(t *testing.T) {
+	t.Setenv("TPL_HOST", "example.com")
+
+	tests := []struct {
+		input, wantErr string
+	}{
+		{
+			"a: 1\nb: x ${TPL_MISSING | required \"set TPL_MISSING to the API host\"}\n",
+			"invalid template expressions:\n  - Line 2, column 6: ${TPL_MISSING | required \"set TPL_MISSING to the API host\"}: set TPL_MISSING to the API host",
+		},
+		{
+			`${TPL_HOST | shout}`,
+			`invalid template expressions:` + "\n" + `  - Line 1, column 1: ${TPL_HOST | shout}: unknown function "shout"`,
+		},
+		{`${TPL_HOST | default}`, "default takes 2 argument(s), got 1"},
+		{`${TPL_HOST | port}`, `port: no port in "example.com"`},
+		{`${"unterminated | lower}`, "unterminated string"},
+		{`${TPL_HOST | | lower}`, "empty command in pipeline"},
+		{`${"Zm9v!" | base64decode}`, "base64decode: illegal base64 data"},
+		{`${file "../etc/passwd"}`, "file: "},
+		{`${MCPANY_API_KEY | lower}`, "variable MCPANY_API_KEY is restricted"},
+		{"\n${TPL_MISSING | lower}", "missing environment variables:\n  - Line 2: variable TPL_MISSING is missing"},
+	}
+	for _, tt := range tests {
+		got, err := expand([]byte(tt.input))
+		require.Error(t, err, tt.input)
+		assert.Contains(t, err.Error(), tt.wantErr, tt.input)
+		assert.Equal(t, tt.input, string(got), "expressions in error are kept")
+	}
+}
+
+func TestExpandRemote_NoFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(path, []byte("s3cr3t"), 0o600))
+
+	_, err := expandRemote([]byte(`token: ${file "` + path + `"}`))
+	assert.ErrorContains(t, err, "file is not allowed in configs loaded from URLs or key-value stores")
+}
@@ -7,19 +7,27 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"mime"
 	"net"
 	"net/url"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/metrics"
 	"github.com/mcpany/core/server/pkg/util"
 	"github.com/mcpany/core/server/pkg/validation"
+	"github.com/mcpany/core/server/pkg/webhooks"
 	"github.com/santhosh-tekuri/jsonschema/v5"
+	standardwebhooks "github.com/standard-webhooks/standard-webhooks/libraries/go"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/types/known/structpb"
 )
@@ -390,6 +398,24 @@ func validateSecretValue(ctx context.Context, secret *configv1.SecretValue) erro
 		if u.Scheme != schemeHTTP && u.Scheme != schemeHTTPS {
 			return fmt.Errorf("remote secret has invalid http_url scheme: %s", u.Scheme)
 		}
+	case configv1.SecretValue_SecretRef_case:
+		scheme, path, key, err := util.ParseSecretRef(secret.GetSecretRef())
+		if err != nil {
+			return err
+		}
+		switch scheme {
+		case "vault":
+			if key == "" {
+				return fmt.Errorf("invalid secret reference %q: expected vault:path#key", secret.GetSecretRef())
+			}
+		case "aws-sm", "gcp-sm":
+		case "azure-kv":
+			if n := len(strings.Split(path, "/")); n < 2 || n > 3 {
+				return fmt.Errorf("azure-kv secret reference %q must be azure-kv:<vault>/<secret>[/<version>]", secret.GetSecretRef())
+			}
+		default:
+			return fmt.Errorf("unsupported secret reference scheme %q: expected vault, aws-sm, gcp-sm or azure-kv", scheme)
+		}
 	}
 
 	if secret.GetValidationRegex() != "" {
@@ -428,6 +454,13 @@ func validateSecretValue(ctx context.Context, secret *configv1.SecretValue) erro
 	return nil
 }
 
+// isSecretRef reports whether the secret references a secrets backend. Such
+// secrets are validated by syntax only: the backend is connected after the
+// configuration is loaded, and the value is read when it is used.
+func isSecretRef(secret *configv1.SecretValue) bool {
+	return secret.WhichValue() == configv1.SecretValue_SecretRef_case
+}
+
 func validateGlobalSettings(ctx context.Context, gs *configv1.GlobalSettings, binaryType BinaryType) error {
 	switch binaryType {
 	case Server:
@@ -483,10 +516,120 @@ func validateGlobalSettings(ctx context.Context, gs *configv1.GlobalSettings, bi
 		return fmt.Errorf("dlp config error: %w", err)
 	}
 
+	if err := validateOutputGuardConfig(gs.GetOutputGuard()); err != nil {
+		return fmt.Errorf("output guard config error: %w", err)
+	}
+
+	if err := validateContextBudgetConfig(gs.GetContextBudget()); err != nil {
+		return fmt.Errorf("context budget config error: %w", err)
+	}
+
+	if err := validateNetworkAccessConfig(gs.GetNetworkAccess()); err != nil {
+		return fmt.Errorf("network access config error: %w", err)
+	}
+
+	if err := validateTelemetryConfig(gs.GetTelemetry()); err != nil {
+		return fmt.Errorf("telemetry config error: %w", err)
+	}
+
+	if err := validateRetentionConfig(gs.GetLogRetention()); err != nil {
+		return fmt.Errorf("log retention config error: %w", err)
+	}
+
+	if err := validateCaptureConfig(gs.GetCapture()); err != nil {
+		return fmt.Errorf("capture config error: %w", err)
+	}
+
+	if err := validateLogSinks(gs.GetLogSinks()); err != nil {
+		return fmt.Errorf("log sinks error: %w", err)
+	}
+
+	if err := validateMiddlewares(gs.GetMiddlewares()); err != nil {
+		return fmt.Errorf("middlewares error: %w", err)
+	}
+
+	if err := validateLabelEnrichers(gs.GetLabelEnrichers()); err != nil {
+		return fmt.Errorf("label enrichers error: %w", err)
+	}
+
+	for _, name := range gs.GetReadiness().GetCriticalServices() {
+		if name == "" {
+			return fmt.Errorf("readiness config error: critical service name is empty")
+		}
+	}
+
+	if err := validateDebugListener(gs.GetDebugListener()); err != nil {
+		return fmt.Errorf("debug listener error: %w", err)
+	}
+
+	if err := validateSlowCalls(gs.GetSlowCalls()); err != nil {
+		return fmt.Errorf("slow calls error: %w", err)
+	}
+
+	if err := validateUpstreamHealth(gs.GetUpstreamHealth()); err != nil {
+		return fmt.Errorf("upstream health error: %w", err)
+	}
+
+	if err := validateDoctor(gs.GetDoctor()); err != nil {
+		return fmt.Errorf("doctor error: %w", err)
+	}
+
+	if err := validateOpenAIBridge(ctx, gs.GetOpenaiBridge()); err != nil {
+		return fmt.Errorf("openai bridge error: %w", err)
+	}
+
+	if err := validateNotifications(ctx, gs.GetNotifications()); err != nil {
+		return fmt.Errorf("notifications error: %w", err)
+	}
+
+	if err := validateOutboundWebhooks(ctx, gs.GetOutboundWebhooks()); err != nil {
+		return fmt.Errorf("outbound webhooks error: %w", err)
+	}
+
+	if err := validateResponseCache(gs.GetResponseCache()); err != nil {
+		return fmt.Errorf("response cache error: %w", err)
+	}
+
+	if err := validateQuotas(gs.GetQuotas()); err != nil {
+		return fmt.Errorf("quotas error: %w", err)
+	}
+
+	if err := validateStartupDiscovery(gs.GetStartupDiscovery()); err != nil {
+		return fmt.Errorf("startup discovery error: %w", err)
+	}
+
+	if err := validateToolCatalogSnapshot(gs.GetToolCatalogSnapshot()); err != nil {
+		return fmt.Errorf("tool catalog snapshot error: %w", err)
+	}
+
+	if err := validateWorkerConfig(gs.GetWorker()); err != nil {
+		return fmt.Errorf("worker error: %w", err)
+	}
+
+	if err := validateSharedState(gs.GetSharedState()); err != nil {
+		return fmt.Errorf("shared state error: %w", err)
+	}
+
 	if err := validateGCSettings(ctx, gs.GetGcSettings()); err != nil {
 		return fmt.Errorf("gc settings error: %w", err)
 	}
 
+	if err := validateOAuthResourceServer(gs.GetOauthResourceServer()); err != nil {
+		return fmt.Errorf("oauth resource server error: %w", err)
+	}
+
+	if err := validateJWTAuth(gs.GetJwtAuth()); err != nil {
+		return fmt.Errorf("jwt auth error: %w", err)
+	}
+
+	if err := validateVaultConfig(ctx, gs.GetVault()); err != nil {
+		return fmt.Errorf("vault config error: %w", err)
+	}
+
+	if err := validateSecretBackends(ctx, gs.GetSecretBackends()); err != nil {
+		return fmt.Errorf("secret backends error: %w", err)
+	}
+
 	profileNames := make(map[string]bool)
 	for _, profile := range gs.GetProfileDefinitions() {
 		if profile.GetName() == "" {
@@ -535,6 +678,11 @@ func validateUpstreamService(ctx context.Context, service *configv1.UpstreamServ
 		if service.GetCache().GetTtl() != nil && service.GetCache().GetTtl().GetSeconds() < 0 {
 			return fmt.Errorf("invalid cache timeout: %v", service.GetCache().GetTtl().AsDuration())
 		}
+		for _, field := range service.GetCache().GetKeyFields() {
+			if field == "" || slices.Contains(strings.Split(field, "."), "") {
+				return fmt.Errorf("invalid cache key field %q", field)
+			}
+		}
 	}
 
 	if authConfig := service.GetUpstreamAuth(); authConfig != nil {
@@ -542,6 +690,279 @@ func validateUpstreamService(ctx context.Context, service *configv1.UpstreamServ
 			return err
 		}
 	}
+
+	if err := validateSLOs(service.GetSlos()); err != nil {
+		return &ActionableError{
+			Err:        err,
+			Suggestion: "Give each objective a unique 'name' and a 'target' percentage between 0 and 100 (e.g., 99.9).",
+		}
+	}
+
+	if err := validateToolAliases(service.GetToolAliases()); err != nil {
+		return &ActionableError{
+			Err:        err,
+			Suggestion: "Set 'name' to the old tool name, 'tool' to the new one and 'deprecated_until' to an RFC3339 timestamp (e.g., 2026-06-30T00:00:00Z).",
+		}
+	}
+
+	if err := validateToolTransforms(serviceTools(service)); err != nil {
+		return &ActionableError{
+			Err:        err,
+			Suggestion: "Set each transform to one of html_to_markdown, truncate (e.g., truncate: {max_chars: 4000}) or paginate (e.g., paginate: {page_size: 1000}).",
+		}
+	}
+
+	if canary := service.GetCanary(); canary != nil {
+		if err := validateCanary(service.GetName(), canary); err != nil {
+			return &ActionableError{
+				Err:        err,
+				Suggestion: "Set 'stable_service' to the name of the service being upgraded, 'weight' to the percentage of its calls sent to this service (e.g., 5) and 'max_error_rate' to a rate between 0 and 1 (e.g., 0.2).",
+			}
+		}
+	}
+
+	if err := validateConnectionPool(service.GetConnectionPool()); err != nil {
+		return fmt.Errorf("connection_pool: %w", err)
+	}
+
+	if service.GetLatencyBudget().AsDuration() < 0 {
+		return fmt.Errorf("latency_budget must not be negative")
+	}
+
+	for _, hook := range append(slices.Clone(service.GetPreCallHooks()), service.GetPostCallHooks()...) {
+		if webhook := hook.GetWebhook(); webhook != nil {
+			if err := validateWebhookConfig(ctx, webhook); err != nil {
+				return fmt.Errorf("call hook %q: %w", hook.GetName(), err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateWebhookConfig(ctx context.Context, webhook *configv1.WebhookConfig) error {
+	if webhook.GetTimeout().AsDuration() < 0 {
+		return fmt.Errorf("webhook timeout must not be negative")
+	}
+	if webhook.GetMaxRetries() < 0 {
+		return fmt.Errorf("webhook max_retries must not be negative")
+	}
+	if webhook.GetRetryBackoff().AsDuration() < 0 {
+		return fmt.Errorf("webhook retry_backoff must not be negative")
+	}
+	if _, err := webhooks.NewMatcher(webhook.GetMatch()); err != nil {
+		return fmt.Errorf("webhook match: %w", err)
+	}
+	if webhook.GetWebhookSecret() != "" {
+		if _, err := standardwebhooks.NewWebhook(webhook.GetWebhookSecret()); err != nil {
+			return fmt.Errorf("webhook webhook_secret is not a Standard Webhooks secret: %w", err)
+		}
+	}
+	if len(webhook.GetPreviousSecrets()) > 0 && webhook.GetWebhookSecret() == "" {
+		return fmt.Errorf("webhook previous_secrets require a webhook_secret")
+	}
+	for i, secret := range webhook.GetPreviousSecrets() {
+		if _, err := standardwebhooks.NewWebhook(secret); err != nil {
+			return fmt.Errorf("webhook previous_secrets[%d] is not a Standard Webhooks secret: %w", i, err)
+		}
+	}
+	if mtls := webhook.GetMtls(); mtls != nil {
+		if err := validateMtlsAuth(ctx, mtls); err != nil {
+			return fmt.Errorf("webhook %w", err)
+		}
+	}
+	switch webhook.GetProtocol() {
+	case configv1.WebhookConfig_PROTOCOL_UNSPECIFIED, configv1.WebhookConfig_HTTP:
+		if webhook.GetPlaintext() {
+			return fmt.Errorf("webhook plaintext only applies to grpc webhooks, use an http:// url")
+		}
+	case configv1.WebhookConfig_GRPC:
+		if webhook.GetPlaintext() && webhook.HasMtls() {
+			return fmt.Errorf("webhook plaintext cannot be combined with mtls")
+		}
+		// A gRPC webhook is dialed at a gRPC target such as "host:port" or
+		// "dns:///host:port", not at a URL.
+		if webhook.GetUrl() == "" {
+			return fmt.Errorf("webhook url is empty")
+		}
+		if strings.HasPrefix(webhook.GetUrl(), "http://") || strings.HasPrefix(webhook.GetUrl(), "https://") {
+			return fmt.Errorf("webhook url %q of a grpc webhook must be a gRPC target such as host:port", webhook.GetUrl())
+		}
+	default:
+		return fmt.Errorf("webhook protocol %d is not supported", webhook.GetProtocol())
+	}
+	return nil
+}
+
+func validateMiddlewares(middlewares []*configv1.Middleware) error {
+	for i, m := range middlewares {
+		if !m.HasPlugin() {
+			continue
+		}
+		if m.GetName() == "" {
+			return fmt.Errorf("middleware %d: a plugin middleware must have a name", i)
+		}
+		if err := validatePlugin(m.GetPlugin()); err != nil {
+			return fmt.Errorf("middleware %q: %w", m.GetName(), err)
+		}
+	}
+	return nil
+}
+
+func validateLabelEnrichers(enrichers []*configv1.LabelEnricher) error {
+	for i, e := range enrichers {
+		if !e.HasPlugin() {
+			continue
+		}
+		if e.GetName() == "" {
+			return fmt.Errorf("label enricher %d: a plugin enricher must have a name", i)
+		}
+		if err := validatePlugin(e.GetPlugin()); err != nil {
+			return fmt.Errorf("label enricher %q: %w", e.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// validatePlugin checks the executable of a middleware or label enricher
+// plugin.
+func validatePlugin(p *configv1.MiddlewarePlugin) error {
+	if p.GetWasm() != "" {
+		return fmt.Errorf("wasm plugins are not supported, use a command")
+	}
+	if p.GetCommand() == "" {
+		return fmt.Errorf("plugin must set a command")
+	}
+	return nil
+}
+
+func validateConnectionPool(connectionPool *configv1.ConnectionPoolConfig) error {
+	if connectionPool == nil {
+		return nil
+	}
+	if connectionPool.GetMaxConnections() < 0 || connectionPool.GetMaxIdleConnections() < 0 || connectionPool.GetMinConnections() < 0 {
+		return fmt.Errorf("connection counts must not be negative")
+	}
+	if connectionPool.GetMaxConnections() > 0 && connectionPool.GetMinConnections() > connectionPool.GetMaxConnections() {
+		return fmt.Errorf("min_connections (%d) exceeds max_connections (%d)", connectionPool.GetMinConnections(), connectionPool.GetMaxConnections())
+	}
+	if connectionPool.GetIdleTimeout().AsDuration() < 0 || connectionPool.GetHealthCheckInterval().AsDuration() < 0 {
+		return fmt.Errorf("idle_timeout and health_check_interval must not be negative")
+	}
+	return nil
+}
+
+func validateCanary(serviceName string, canary *configv1.CanaryConfig) error {
+	if canary.GetStableService() == "" {
+		return fmt.Errorf("canary stable_service is empty")
+	}
+	if canary.GetStableService() == serviceName {
+		return fmt.Errorf("canary stable_service %q is the service itself", serviceName)
+	}
+	if canary.GetWeight() < 0 || canary.GetWeight() > 100 {
+		return fmt.Errorf("canary weight must be between 0 and 100, got %d", canary.GetWeight())
+	}
+	if canary.GetMaxErrorRate() < 0 || canary.GetMaxErrorRate() > 1 {
+		return fmt.Errorf("canary max_error_rate must be between 0 and 1, got %v", canary.GetMaxErrorRate())
+	}
+	if canary.GetMinCalls() < 0 {
+		return fmt.Errorf("canary min_calls must not be negative, got %d", canary.GetMinCalls())
+	}
+	if canary.HasWindow() && canary.GetWindow().AsDuration() <= 0 {
+		return fmt.Errorf("canary window must be positive")
+	}
+	return nil
+}
+
+func validateToolAliases(aliases []*configv1.ToolAlias) error {
+	names := make(map[string]bool, len(aliases))
+	for _, alias := range aliases {
+		if alias.GetName() == "" {
+			return fmt.Errorf("tool alias name is empty")
+		}
+		if names[alias.GetName()] {
+			return fmt.Errorf("duplicate tool alias %q", alias.GetName())
+		}
+		names[alias.GetName()] = true
+		if alias.GetTool() == "" {
+			return fmt.Errorf("tool alias %q does not name the new tool", alias.GetName())
+		}
+		if alias.GetTool() == alias.GetName() {
+			return fmt.Errorf("tool alias %q points to itself", alias.GetName())
+		}
+		if v := alias.GetDeprecatedUntil(); v != "" {
+			if _, err := time.Parse(time.RFC3339, v); err != nil {
+				return fmt.Errorf("tool alias %q deprecated_until %q is not an RFC3339 timestamp", alias.GetName(), v)
+			}
+		}
+	}
+	return nil
+}
+
+func validateToolTransforms(tools []*configv1.ToolDefinition) error {
+	for _, def := range tools {
+		for _, transform := range def.GetTransforms() {
+			switch transform.WhichTransform() {
+			case configv1.ToolTransform_Transform_not_set_case:
+				return fmt.Errorf("tool %q has an empty transform", def.GetName())
+			case configv1.ToolTransform_Truncate_case:
+				if transform.GetTruncate().GetMaxChars() < 0 {
+					return fmt.Errorf("tool %q truncate max_chars must not be negative, got %d", def.GetName(), transform.GetTruncate().GetMaxChars())
+				}
+			case configv1.ToolTransform_Paginate_case:
+				if transform.GetPaginate().GetPageSize() < 0 {
+					return fmt.Errorf("tool %q paginate page_size must not be negative, got %d", def.GetName(), transform.GetPaginate().GetPageSize())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// serviceTools returns the tools declared in the configuration of a service.
+func serviceTools(service *configv1.UpstreamServiceConfig) []*configv1.ToolDefinition {
+	switch {
+	case service.HasHttpService():
+		return service.GetHttpService().GetTools()
+	case service.HasGrpcService():
+		return service.GetGrpcService().GetTools()
+	case service.HasOpenapiService():
+		return service.GetOpenapiService().GetTools()
+	case service.HasCommandLineService():
+		return service.GetCommandLineService().GetTools()
+	case service.HasMcpService():
+		return service.GetMcpService().GetTools()
+	case service.HasWebsocketService():
+		return service.GetWebsocketService().GetTools()
+	case service.HasWebrtcService():
+		return service.GetWebrtcService().GetTools()
+	case service.HasFilesystemService():
+		return service.GetFilesystemService().GetTools()
+	case service.HasVectorService():
+		return service.GetVectorService().GetTools()
+	}
+	return nil
+}
+
+func validateSLOs(slos []*configv1.ServiceLevelObjective) error {
+	names := make(map[string]bool, len(slos))
+	for _, slo := range slos {
+		if slo.GetName() == "" {
+			return fmt.Errorf("slo name is empty")
+		}
+		if names[slo.GetName()] {
+			return fmt.Errorf("duplicate slo name %q", slo.GetName())
+		}
+		names[slo.GetName()] = true
+		if slo.GetTarget() <= 0 || slo.GetTarget() >= 100 {
+			return fmt.Errorf("slo %q target must be between 0 and 100 exclusive, got %v", slo.GetName(), slo.GetTarget())
+		}
+		if slo.HasWindow() && slo.GetWindow().AsDuration() <= 0 {
+			return fmt.Errorf("slo %q window must be positive", slo.GetName())
+		}
+		if slo.HasLatencyThreshold() && slo.GetLatencyThreshold().AsDuration() <= 0 {
+			return fmt.Errorf("slo %q latency_threshold must be positive", slo.GetName())
+		}
+	}
 	return nil
 }
 
@@ -590,6 +1011,9 @@ func validateBasicAuth(ctx context.Context, basicAuth *configv1.BasicAuth, authC
 	if err := validateSecretValue(ctx, basicAuth.GetPassword()); err != nil {
 		return WrapActionableError("basic auth password validation failed", err)
 	}
+	if isSecretRef(basicAuth.GetPassword()) {
+		return nil
+	}
 	passwordValue, err := util.ResolveSecret(ctx, basicAuth.GetPassword())
 	if err != nil {
 		return fmt.Errorf("failed to resolve basic auth password secret: %w", err)
@@ -660,6 +1084,97 @@ func validateHTTPService(httpService *configv1.HttpUpstreamService) error {
 		if err := validateSchema(call.GetOutputSchema()); err != nil {
 			return WrapActionableError(fmt.Sprintf("http call %q output_schema error", name), err)
 		}
+		if err := validatePagination(call); err != nil {
+			return &ActionableError{
+				Err:        fmt.Errorf("http call %q pagination error: %w", name, err),
+				Suggestion: "Set 'param' to the upstream's page query parameter, and 'next_cursor_path' (STYLE_CURSOR) or 'items_path' (STYLE_PAGE, STYLE_OFFSET) to a JSONPath such as '{.items}'.",
+			}
+		}
+		if err := validateOutputTransformer(call.GetOutputTransformer()); err != nil {
+			return &ActionableError{
+				Err:        fmt.Errorf("http call %q output_transformer error: %w", name, err),
+				Suggestion: "Set 'xml_options' only with format XML_TO_JSON and 'csv_options' only with format CSV, and use a single character 'delimiter'.",
+			}
+		}
+		if err := validateResponseContent(call); err != nil {
+			return &ActionableError{
+				Err:        fmt.Errorf("http call %q response_content error: %w", name, err),
+				Suggestion: "Set 'mode' to MODE_AUTO, MODE_CONVERT, MODE_RESOURCE or MODE_BASE64, and remove 'output_transformer', which takes precedence.",
+			}
+		}
+	}
+	if err := validateTLSConfig(httpService.GetTlsConfig()); err != nil {
+		return WrapActionableError("http tls_config error", err)
+	}
+	return nil
+}
+
+func validatePagination(call *configv1.HttpCallDefinition) error {
+	p := call.GetPagination()
+	if p == nil {
+		return nil
+	}
+	if p.GetStyle() == configv1.PaginationConfig_STYLE_UNSPECIFIED {
+		return fmt.Errorf("style is required")
+	}
+	if p.GetParam() == "" {
+		return fmt.Errorf("param is required")
+	}
+	switch p.GetStyle() {
+	case configv1.PaginationConfig_STYLE_CURSOR:
+		if p.GetNextCursorPath() == "" {
+			return fmt.Errorf("next_cursor_path is required for STYLE_CURSOR")
+		}
+	case configv1.PaginationConfig_STYLE_PAGE, configv1.PaginationConfig_STYLE_OFFSET:
+		if p.GetItemsPath() == "" {
+			return fmt.Errorf("items_path is required for %s", p.GetStyle())
+		}
+	}
+	if p.GetPageSize() < 0 || p.GetFirstPage() < 0 {
+		return fmt.Errorf("page_size and first_page must not be negative")
+	}
+	for _, param := range call.GetParameters() {
+		if param.GetSchema().GetName() == "cursor" {
+			return fmt.Errorf("parameter %q is reserved for the pagination cursor", "cursor")
+		}
+	}
+	return nil
+}
+
+func validateOutputTransformer(ot *configv1.OutputTransformer) error {
+	if ot == nil {
+		return nil
+	}
+	if ot.HasXmlOptions() && ot.GetFormat() != configv1.OutputTransformer_XML_TO_JSON {
+		return fmt.Errorf("xml_options requires format XML_TO_JSON, got %s", ot.GetFormat())
+	}
+	if ot.HasCsvOptions() && ot.GetFormat() != configv1.OutputTransformer_CSV {
+		return fmt.Errorf("csv_options requires format CSV, got %s", ot.GetFormat())
+	}
+	if d := ot.GetCsvOptions().GetDelimiter(); d != "" && utf8.RuneCountInString(d) != 1 {
+		return fmt.Errorf("csv_options.delimiter must be a single character, got %q", d)
+	}
+	return nil
+}
+
+func validateResponseContent(call *configv1.HttpCallDefinition) error {
+	rc := call.GetResponseContent()
+	if rc == nil {
+		return nil
+	}
+	if rc.GetMode() == configv1.ResponseContentConfig_MODE_UNSPECIFIED {
+		return fmt.Errorf("mode is required")
+	}
+	if call.HasOutputTransformer() {
+		return fmt.Errorf("response_content cannot be combined with output_transformer")
+	}
+	if d := rc.GetCsvDelimiter(); d != "" && utf8.RuneCountInString(d) != 1 {
+		return fmt.Errorf("csv_delimiter must be a single character, got %q", d)
+	}
+	if mt := rc.GetMimeType(); mt != "" {
+		if _, _, err := mime.ParseMediaType(mt); err != nil {
+			return fmt.Errorf("invalid mime_type %q: %w", mt, err)
+		}
 	}
 	return nil
 }
@@ -698,6 +1213,15 @@ func validateWebSocketService(websocketService *configv1.WebsocketUpstreamServic
 		if err := validateSchema(call.GetOutputSchema()); err != nil {
 			return WrapActionableError(fmt.Sprintf("websocket call %q output_schema error", name), err)
 		}
+		if err := validateOutputTransformer(call.GetOutputTransformer()); err != nil {
+			return &ActionableError{
+				Err:        fmt.Errorf("websocket call %q output_transformer error: %w", name, err),
+				Suggestion: "Set 'xml_options' only with format XML_TO_JSON and 'csv_options' only with format CSV, and use a single character 'delimiter'.",
+			}
+		}
+	}
+	if err := validateTLSConfig(websocketService.GetTlsConfig()); err != nil {
+		return WrapActionableError("websocket tls_config error", err)
 	}
 	return nil
 }
@@ -718,6 +1242,9 @@ func validateGrpcService(grpcService *configv1.GrpcUpstreamService) error {
 			return WrapActionableError(fmt.Sprintf("grpc call %q output_schema error", name), err)
 		}
 	}
+	if err := validateTLSConfig(grpcService.GetTlsConfig()); err != nil {
+		return WrapActionableError("grpc tls_config error", err)
+	}
 	return nil
 }
 
@@ -756,6 +1283,9 @@ func validateOpenAPIService(openapiService *configv1.OpenapiUpstreamService) err
 			}
 		}
 	}
+	if err := validateTLSConfig(openapiService.GetTlsConfig()); err != nil {
+		return WrapActionableError("openapi tls_config error", err)
+	}
 	return nil
 }
 
@@ -812,6 +1342,9 @@ func validateContainerEnvironment(ctx context.Context, env *configv1.ContainerEn
 }
 
 func validateMcpService(ctx context.Context, mcpService *configv1.McpUpstreamService) error {
+	if hc := mcpService.GetHealthCheck(); hc.GetInterval().AsDuration() < 0 || hc.GetTimeout().AsDuration() < 0 {
+		return fmt.Errorf("mcp service health_check interval and timeout must not be negative")
+	}
 	switch mcpService.WhichConnectionType() {
 	case configv1.McpUpstreamService_HttpConnection_case:
 		httpConn := mcpService.GetHttpConnection()
@@ -880,6 +1413,21 @@ func validateMcpService(ctx context.Context, mcpService *configv1.McpUpstreamSer
 		return fmt.Errorf("mcp service has no connection_type")
 	}
 
+	if peer := mcpService.GetPeer(); peer != nil {
+		if err := validateMcpPeer(ctx, mcpService, peer); err != nil {
+			return err
+		}
+	}
+
+	if affinity := mcpService.GetSessionAffinity(); affinity != nil {
+		if affinity.GetIdleTimeout().AsDuration() < 0 {
+			return fmt.Errorf("mcp session_affinity idle_timeout must not be negative")
+		}
+		if affinity.GetMaxSessions() < 0 {
+			return fmt.Errorf("mcp session_affinity max_sessions must not be negative")
+		}
+	}
+
 	for name, call := range mcpService.GetCalls() {
 		if err := validateSchema(call.GetInputSchema()); err != nil {
 			return WrapActionableError(fmt.Sprintf("mcp call %q input_schema error", name), err)
@@ -891,6 +1439,48 @@ func validateMcpService(ctx context.Context, mcpService *configv1.McpUpstreamSer
 	return nil
 }
 
+func validateMcpPeer(ctx context.Context, mcpService *configv1.McpUpstreamService, peer *configv1.McpPeerConfig) error {
+	if !mcpService.HasHttpConnection() {
+		return &ActionableError{
+			Err:        fmt.Errorf("mcp peer requires http_connection"),
+			Suggestion: "Set 'http_connection.http_address' to the MCP endpoint of the peer mcpany instance.",
+		}
+	}
+	if peer.GetMaxHops() < 0 {
+		return fmt.Errorf("mcp peer max_hops must not be negative")
+	}
+	for local, remote := range peer.GetProfileMapping() {
+		if local == "" || remote == "" {
+			return fmt.Errorf("mcp peer profile_mapping has an empty profile (%q -> %q)", local, remote)
+		}
+	}
+	switch peer.WhichCredentials() {
+	case configv1.McpPeerConfig_ApiKey_case:
+		if err := validateSecretValue(ctx, peer.GetApiKey()); err != nil {
+			return WrapActionableError("mcp peer api_key validation failed", err)
+		}
+	case configv1.McpPeerConfig_Oauth2_case:
+		oauth := peer.GetOauth2()
+		if oauth.GetTokenUrl() != "" || oauth.GetIssuerUrl() != "" {
+			if err := validateOAuth2Auth(ctx, oauth); err != nil {
+				return WrapActionableError("mcp peer oauth2 validation failed", err)
+			}
+			break
+		}
+		// The authorization server is discovered from the peer.
+		if oauth.GetClientId() == nil {
+			return fmt.Errorf("mcp peer oauth2 requires a client_id")
+		}
+		if err := validateSecretValue(ctx, oauth.GetClientId()); err != nil {
+			return WrapActionableError("mcp peer oauth2 client_id validation failed", err)
+		}
+		if err := validateSecretValue(ctx, oauth.GetClientSecret()); err != nil {
+			return WrapActionableError("mcp peer oauth2 client_secret validation failed", err)
+		}
+	}
+	return nil
+}
+
 func validateSQLService(sqlService *configv1.SqlUpstreamService) error {
 	if sqlService.GetDriver() == "" {
 		return &ActionableError{
@@ -1032,7 +1622,7 @@ func validateAPIKeyAuth(ctx context.Context, apiKey *configv1.APIKeyAuth, authCt
 
 		// If we are skipping secret validation, we should also skip attempting to resolve it for "not empty" check
 		// because ResolveSecret will read the value.
-		if skip, ok := ctx.Value(SkipSecretValidationKey).(bool); ok && skip {
+		if skip, ok := ctx.Value(SkipSecretValidationKey).(bool); (ok && skip) || isSecretRef(apiKey.GetValue()) {
 			return nil
 		}
 
@@ -1055,7 +1645,7 @@ func validateBearerTokenAuth(ctx context.Context, bearerToken *configv1.BearerTo
 		return WrapActionableError("bearer token validation failed", err)
 	}
 
-	if skip, ok := ctx.Value(SkipSecretValidationKey).(bool); ok && skip {
+	if skip, ok := ctx.Value(SkipSecretValidationKey).(bool); (ok && skip) || isSecretRef(bearerToken.GetToken()) {
 		return nil
 	}
 
@@ -1107,7 +1697,7 @@ func validateOAuth2Auth(ctx context.Context, oauth *configv1.OAuth2Auth) error {
 		return WrapActionableError("oauth2 client_id validation failed", err)
 	}
 
-	if skip, ok := ctx.Value(SkipSecretValidationKey).(bool); !ok || !skip {
+	if skip, ok := ctx.Value(SkipSecretValidationKey).(bool); (!ok || !skip) && !isSecretRef(oauth.GetClientId()) {
 		clientID, err := util.ResolveSecret(ctx, oauth.GetClientId())
 		if err != nil {
 			return fmt.Errorf("failed to resolve oauth2 client_id: %w", err)
@@ -1121,7 +1711,9 @@ func validateOAuth2Auth(ctx context.Context, oauth *configv1.OAuth2Auth) error {
 		return WrapActionableError("oauth2 client_secret validation failed", err)
 	}
 
-	if skip, ok := ctx.Value(SkipSecretValidationKey).(bool); !ok || !skip {
+	// The refresh token grant does not need a client secret for public clients.
+	refreshGrant := oauth.GetGrantType() == configv1.OAuth2Auth_GRANT_TYPE_REFRESH_TOKEN
+	if skip, ok := ctx.Value(SkipSecretValidationKey).(bool); (!ok || !skip) && !isSecretRef(oauth.GetClientSecret()) && (!refreshGrant || oauth.HasClientSecret()) {
 		clientSecret, err := util.ResolveSecret(ctx, oauth.GetClientSecret())
 		if err != nil {
 			return fmt.Errorf("failed to resolve oauth2 client_secret: %w", err)
@@ -1131,7 +1723,23 @@ func validateOAuth2Auth(ctx context.Context, oauth *configv1.OAuth2Auth) error {
 		}
 	}
 
-	return nil
+	if refreshGrant {
+		if !oauth.HasRefreshToken() {
+			return &ActionableError{
+				Err:        fmt.Errorf("oauth2 refresh_token is required for GRANT_TYPE_REFRESH_TOKEN"),
+				Suggestion: "Set 'refresh_token' to a secret holding the refresh token, e.g. 'refresh_token: {environment_variable: UPSTREAM_REFRESH_TOKEN}'.",
+			}
+		}
+		if err := validateSecretValue(ctx, oauth.GetRefreshToken()); err != nil {
+			return WrapActionableError("oauth2 refresh_token validation failed", err)
+		}
+	}
+
+	if d := oauth.GetRefreshBeforeExpiry(); d != nil && d.AsDuration() < 0 {
+		return fmt.Errorf("oauth2 refresh_before_expiry must not be negative")
+	}
+
+	return nil
 }
 
 func validateOIDCAuth(_ context.Context, oidc *configv1.OIDCAuth) error {
@@ -1160,6 +1768,39 @@ func validateTrustedHeaderAuth(th *configv1.TrustedHeaderAuth) error {
 	return nil
 }
 
+func validateTLSConfig(tlsConfig *configv1.TLSConfig) error {
+	if tlsConfig == nil {
+		return nil
+	}
+	if (tlsConfig.GetClientCertPath() == "") != (tlsConfig.GetClientKeyPath() == "") {
+		return &ActionableError{
+			Err:        fmt.Errorf("'client_cert_path' and 'client_key_path' must be set together"),
+			Suggestion: "Set both the client certificate and its private key to use mTLS, or neither.",
+		}
+	}
+	for _, f := range []struct{ name, path string }{
+		{"ca_cert_path", tlsConfig.GetCaCertPath()},
+		{"client_cert_path", tlsConfig.GetClientCertPath()},
+		{"client_key_path", tlsConfig.GetClientKeyPath()},
+	} {
+		if f.path == "" {
+			continue
+		}
+		if err := validation.IsSecurePath(f.path); err != nil {
+			return fmt.Errorf("'%s' is not a secure path: %w", f.name, err)
+		}
+	}
+	switch tlsConfig.GetMinVersion() {
+	case configv1.TLSConfig_VERSION_UNSPECIFIED, configv1.TLSConfig_TLS_1_2, configv1.TLSConfig_TLS_1_3:
+	default:
+		return &ActionableError{
+			Err:        fmt.Errorf("unsupported 'min_version': %v", tlsConfig.GetMinVersion()),
+			Suggestion: "Use 'TLS_1_2' or 'TLS_1_3'.",
+		}
+	}
+	return nil
+}
+
 func validateMtlsAuth(ctx context.Context, mtls *configv1.MTLSAuth) error {
 	if mtls.GetClientCertPath() == "" {
 		return fmt.Errorf("mtls 'client_cert_path' is empty")
@@ -1297,6 +1938,328 @@ func validateAuditConfig(audit *configv1.AuditConfig) error {
 			return fmt.Errorf("invalid webhook_url: %s", audit.GetWebhookUrl())
 		}
 	}
+	if signing := audit.GetSigning(); signing != nil {
+		switch audit.GetStorageType() {
+		case configv1.AuditConfig_STORAGE_TYPE_UNSPECIFIED, configv1.AuditConfig_STORAGE_TYPE_FILE, configv1.AuditConfig_STORAGE_TYPE_SQLITE:
+		default:
+			return fmt.Errorf("signing is only supported by the file and sqlite storage types")
+		}
+		if signing.GetPrivateKey() == nil {
+			return fmt.Errorf("signing.private_key is required")
+		}
+		if signing.GetCheckpointInterval() < 0 {
+			return fmt.Errorf("signing.checkpoint_interval must not be negative")
+		}
+	}
+	if export := audit.GetExport(); export != nil {
+		if err := validateAuditExportConfig(export); err != nil {
+			return fmt.Errorf("export: %w", err)
+		}
+	}
+	if retention := audit.GetRetention(); retention != nil {
+		if audit.GetStorageType() != configv1.AuditConfig_STORAGE_TYPE_SQLITE {
+			return fmt.Errorf("retention is only supported by the sqlite storage type")
+		}
+		if err := validateRetentionConfig(retention); err != nil {
+			return fmt.Errorf("retention: %w", err)
+		}
+	}
+	return nil
+}
+
+func validateCaptureConfig(capture *configv1.CaptureConfig) error {
+	if !capture.GetEnabled() {
+		return nil
+	}
+	if capture.GetMaxCaptures() < 0 {
+		return fmt.Errorf("max_captures must not be negative")
+	}
+	for _, pattern := range capture.GetTools() {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid tool pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+func validateDebugListener(debug *configv1.DebugListenerConfig) error {
+	if debug.GetAddress() == "" {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(debug.GetAddress()); err != nil {
+		return fmt.Errorf("invalid address %q: %w", debug.GetAddress(), err)
+	}
+	return nil
+}
+
+func validateSlowCalls(slow *configv1.SlowCallConfig) error {
+	if slow.GetThreshold().AsDuration() < 0 || slow.GetWindow().AsDuration() < 0 {
+		return fmt.Errorf("threshold and window must not be negative")
+	}
+	if slow.GetTopN() < 0 {
+		return fmt.Errorf("top_n must not be negative")
+	}
+	for name, threshold := range slow.GetToolThresholds() {
+		if _, err := path.Match(name, ""); err != nil {
+			return fmt.Errorf("invalid tool pattern %q: %w", name, err)
+		}
+		if threshold.AsDuration() < 0 {
+			return fmt.Errorf("threshold of %q must not be negative", name)
+		}
+	}
+	return nil
+}
+
+func validateUpstreamHealth(upstreamHealth *configv1.UpstreamHealthConfig) error {
+	if upstreamHealth.GetInterval().AsDuration() < 0 {
+		return fmt.Errorf("interval must not be negative")
+	}
+	if upstreamHealth.GetUnhealthyThreshold() < 0 || upstreamHealth.GetHealthyThreshold() < 0 {
+		return fmt.Errorf("thresholds must not be negative")
+	}
+	return nil
+}
+
+func validateDoctor(doctor *configv1.DoctorConfig) error {
+	if doctor.GetLatencyBudget().AsDuration() < 0 {
+		return fmt.Errorf("latency_budget must not be negative")
+	}
+	if doctor.GetLatencySamples() < 0 || doctor.GetLatencySamples() > 100 {
+		return fmt.Errorf("latency_samples must be between 0 and 100")
+	}
+	if doctor.GetDnsLookups() < 0 || doctor.GetDnsLookups() > 20 {
+		return fmt.Errorf("dns_lookups must be between 0 and 20")
+	}
+	return nil
+}
+
+func validateOpenAIBridge(ctx context.Context, bridge *configv1.OpenAIBridgeConfig) error {
+	if bridge == nil {
+		return nil
+	}
+	if bridge.GetUpstreamUrl() == "" {
+		if bridge.HasUpstreamApiKey() {
+			return fmt.Errorf("upstream_api_key requires an upstream_url")
+		}
+		return nil
+	}
+	if err := validateAbsoluteURL(bridge.GetUpstreamUrl()); err != nil {
+		return fmt.Errorf("invalid upstream_url: %w", err)
+	}
+	if err := validateSecretValue(ctx, bridge.GetUpstreamApiKey()); err != nil {
+		return fmt.Errorf("invalid upstream_api_key: %w", err)
+	}
+	return nil
+}
+
+func validateResponseCache(responseCache *configv1.ResponseCacheConfig) error {
+	if responseCache.GetMaxEntries() < 0 {
+		return fmt.Errorf("max_entries must not be negative")
+	}
+	if responseCache.HasRedis() && responseCache.GetRedis().GetAddress() == "" {
+		return fmt.Errorf("redis address is empty")
+	}
+	return nil
+}
+
+func validateQuotas(quotas []*configv1.QuotaConfig) error {
+	names := make(map[string]bool, len(quotas))
+	for i, q := range quotas {
+		if q.GetName() == "" {
+			return fmt.Errorf("quota %d has an empty name", i)
+		}
+		if names[q.GetName()] {
+			return fmt.Errorf("duplicate quota name %q", q.GetName())
+		}
+		names[q.GetName()] = true
+		if q.GetKeyBy() == configv1.QuotaConfig_KEY_BY_UNSPECIFIED {
+			return fmt.Errorf("quota %q: key_by is required", q.GetName())
+		}
+		if q.GetMaxConcurrentCalls() < 0 || q.GetMaxCallsPerHour() < 0 || q.GetMaxCallsPerDay() < 0 {
+			return fmt.Errorf("quota %q: limits must not be negative", q.GetName())
+		}
+		if q.GetMaxConcurrentCalls() == 0 && q.GetMaxCallsPerHour() == 0 && q.GetMaxCallsPerDay() == 0 {
+			return fmt.Errorf("quota %q: at least one of max_concurrent_calls, max_calls_per_hour and max_calls_per_day is required", q.GetName())
+		}
+	}
+	return nil
+}
+
+func validateStartupDiscovery(discovery *configv1.StartupDiscoveryConfig) error {
+	if discovery.GetMaxConcurrency() < 0 {
+		return fmt.Errorf("max_concurrency must not be negative")
+	}
+	if discovery.HasServiceTimeout() && discovery.GetServiceTimeout().AsDuration() < 0 {
+		return fmt.Errorf("service_timeout must not be negative")
+	}
+	return nil
+}
+
+func validateToolCatalogSnapshot(snapshot *configv1.ToolCatalogSnapshotConfig) error {
+	if snapshot.HasMaxAge() && snapshot.GetMaxAge().AsDuration() <= 0 {
+		return fmt.Errorf("max_age must be positive")
+	}
+	return nil
+}
+
+func validateWorkerConfig(worker *configv1.WorkerConfig) error {
+	if worker.GetMaxConcurrency() < 0 {
+		return fmt.Errorf("max_concurrency must not be negative")
+	}
+	names := make(map[string]bool, len(worker.GetPriorityClasses()))
+	for i, c := range worker.GetPriorityClasses() {
+		if c.GetName() == "" {
+			return fmt.Errorf("priority class %d has an empty name", i)
+		}
+		if names[c.GetName()] {
+			return fmt.Errorf("duplicate priority class name %q", c.GetName())
+		}
+		names[c.GetName()] = true
+		if c.GetWeight() < 0 || c.GetMaxQueueSize() < 0 || c.GetMaxQueueSizePerTenant() < 0 {
+			return fmt.Errorf("priority class %q: weight and queue sizes must not be negative", c.GetName())
+		}
+	}
+	return nil
+}
+
+func validateSharedState(sharedState *configv1.SharedStateConfig) error {
+	if sharedState == nil {
+		return nil
+	}
+	if sharedState.GetRedis().GetAddress() == "" {
+		return fmt.Errorf("redis address is empty")
+	}
+	if addr := sharedState.GetAdvertiseAddress(); addr != "" {
+		u, err := url.Parse(addr)
+		if err != nil || (u.Scheme != schemeHTTP && u.Scheme != schemeHTTPS) || u.Host == "" {
+			return fmt.Errorf("advertise_address %q must be an http or https URL", addr)
+		}
+	}
+	if sharedState.GetSessionTtl().AsDuration() < 0 {
+		return fmt.Errorf("session_ttl must not be negative")
+	}
+	if sharedState.GetHeartbeatInterval().AsDuration() < 0 {
+		return fmt.Errorf("heartbeat_interval must not be negative")
+	}
+	return nil
+}
+
+func validateNotifications(ctx context.Context, notifications *configv1.NotificationConfig) error {
+	if notifications.GetCooldown().AsDuration() < 0 || notifications.GetAuthFailureWindow().AsDuration() < 0 {
+		return fmt.Errorf("cooldown and auth_failure_window must not be negative")
+	}
+	if notifications.GetAuthFailureThreshold() < 0 {
+		return fmt.Errorf("auth_failure_threshold must not be negative")
+	}
+	for i, sink := range notifications.GetSinks() {
+		if !validation.IsValidURL(sink.GetUrl()) {
+			return fmt.Errorf("sink %d: invalid url %q", i, sink.GetUrl())
+		}
+		u, _ := url.Parse(sink.GetUrl())
+		if u.Scheme != schemeHTTP && u.Scheme != schemeHTTPS {
+			return fmt.Errorf("sink %d: invalid url scheme: %s", i, u.Scheme)
+		}
+		if sink.GetFormat() == configv1.NotificationSinkConfig_FORMAT_STANDARD_WEBHOOKS {
+			if sink.GetSecret() == nil {
+				return fmt.Errorf("sink %d: the standard webhooks format requires a secret", i)
+			}
+			if err := validateSecretValue(ctx, sink.GetSecret()); err != nil {
+				return fmt.Errorf("sink %d: secret: %w", i, err)
+			}
+		}
+		for _, pattern := range sink.GetEvents() {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("sink %d: invalid event pattern %q: %w", i, pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateOutboundWebhooks(ctx context.Context, outbound *configv1.OutboundWebhookConfig) error {
+	if outbound == nil {
+		return nil
+	}
+	if _, err := webhooks.NewSigners(outbound.GetWebhookSecret(), outbound.GetPreviousSecrets()); err != nil {
+		return err
+	}
+	if mtls := outbound.GetMtls(); mtls != nil {
+		return validateMtlsAuth(ctx, mtls)
+	}
+	return nil
+}
+
+func validateLogSinks(sinks []*configv1.LogSinkConfig) error {
+	names := make(map[string]bool)
+	for i, sinkConfig := range sinks {
+		sink, err := logging.NewSink(sinkConfig)
+		if err != nil {
+			return fmt.Errorf("log sink %d: %w", i, err)
+		}
+		_ = sink.Close()
+		if name := sinkConfig.GetName(); name != "" {
+			if names[name] {
+				return fmt.Errorf("duplicate log sink name %q", name)
+			}
+			names[name] = true
+		}
+		if sinkConfig.GetBatchSize() < 0 || sinkConfig.GetQueueSize() < 0 {
+			return fmt.Errorf("log sink %d: batch_size and queue_size must not be negative", i)
+		}
+		if sinkConfig.GetFlushInterval().AsDuration() < 0 {
+			return fmt.Errorf("log sink %d: flush_interval must not be negative", i)
+		}
+	}
+	return nil
+}
+
+func validateRetentionConfig(retention *configv1.RetentionConfig) error {
+	if retention == nil {
+		return nil
+	}
+	if retention.GetMaxAge().AsDuration() < 0 {
+		return fmt.Errorf("max_age must not be negative")
+	}
+	if retention.GetMaxRows() < 0 {
+		return fmt.Errorf("max_rows must not be negative")
+	}
+	if retention.GetMaxSizeBytes() < 0 {
+		return fmt.Errorf("max_size_bytes must not be negative")
+	}
+	if retention.GetInterval().AsDuration() < 0 {
+		return fmt.Errorf("interval must not be negative")
+	}
+	return nil
+}
+
+func validateAuditExportConfig(export *configv1.AuditExportConfig) error {
+	switch {
+	case export.HasS3():
+		if export.GetS3().GetBucket() == "" {
+			return fmt.Errorf("s3.bucket is required")
+		}
+	case export.HasGcs():
+		if export.GetGcs().GetBucket() == "" {
+			return fmt.Errorf("gcs.bucket is required")
+		}
+	default:
+		return fmt.Errorf("either s3 or gcs is required")
+	}
+	if export.GetSpillDir() == "" {
+		return fmt.Errorf("spill_dir is required")
+	}
+	if export.GetBatchSize() < 0 {
+		return fmt.Errorf("batch_size must not be negative")
+	}
+	if export.GetSpillMaxBytes() < 0 {
+		return fmt.Errorf("spill_max_bytes must not be negative")
+	}
+	if export.GetFlushInterval().AsDuration() < 0 {
+		return fmt.Errorf("flush_interval must not be negative")
+	}
+	if export.GetRetention().AsDuration() < 0 {
+		return fmt.Errorf("retention must not be negative")
+	}
 	return nil
 }
 
@@ -1309,6 +2272,258 @@ func validateDLPConfig(dlp *configv1.DLPConfig) error {
 			return fmt.Errorf("invalid regex pattern %q: %w", pattern, err)
 		}
 	}
+	for _, pattern := range dlp.GetAllowPatterns() {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid allow pattern %q: %w", pattern, err)
+		}
+	}
+	for i, rule := range dlp.GetToolRules() {
+		if len(rule.GetTools()) == 0 {
+			return fmt.Errorf("tool rule %d: tools is required", i)
+		}
+		for _, tool := range rule.GetTools() {
+			if _, err := path.Match(tool, ""); err != nil {
+				return fmt.Errorf("tool rule %d: invalid tool pattern %q: %w", i, tool, err)
+			}
+		}
+		for _, patterns := range [][]string{rule.GetAllowPatterns(), rule.GetDenyPatterns()} {
+			for _, pattern := range patterns {
+				if _, err := regexp.Compile(pattern); err != nil {
+					return fmt.Errorf("tool rule %d: invalid regex pattern %q: %w", i, pattern, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func validateOutputGuardConfig(guard *configv1.OutputGuardConfig) error {
+	if guard == nil {
+		return nil
+	}
+	for _, pattern := range guard.GetCustomPatterns() {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid regex pattern %q: %w", pattern, err)
+		}
+	}
+	for _, domain := range guard.GetAllowedDomains() {
+		if domain == "" || strings.ContainsAny(domain, "/:* ") {
+			return fmt.Errorf("invalid allowed domain %q: expected a host name such as \"example.com\"", domain)
+		}
+	}
+	for i, rule := range guard.GetToolRules() {
+		if len(rule.GetTools()) == 0 {
+			return fmt.Errorf("tool rule %d: tools is required", i)
+		}
+		for _, tool := range rule.GetTools() {
+			if _, err := path.Match(tool, ""); err != nil {
+				return fmt.Errorf("tool rule %d: invalid tool pattern %q: %w", i, tool, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateContextBudgetConfig(budget *configv1.ContextBudgetConfig) error {
+	if !budget.GetEnabled() {
+		return nil
+	}
+	if budget.GetMaxTokens() <= 0 {
+		return fmt.Errorf("max_tokens must be greater than 0")
+	}
+	for i, stage := range budget.GetStages() {
+		if stage.GetThresholdPercent() <= 0 {
+			return fmt.Errorf("stage %d: threshold_percent must be greater than 0", i)
+		}
+		if stage.GetMaxChars() < 0 {
+			return fmt.Errorf("stage %d: max_chars must not be negative", i)
+		}
+	}
+	return nil
+}
+
+func validateTelemetryConfig(telemetry *configv1.TelemetryConfig) error {
+	if telemetry.HasSamplingRatio() && (telemetry.GetSamplingRatio() < 0 || telemetry.GetSamplingRatio() > 1) {
+		return fmt.Errorf("sampling_ratio must be between 0 and 1, got %g", telemetry.GetSamplingRatio())
+	}
+	for _, exporter := range []string{telemetry.GetTracesExporter(), telemetry.GetMetricsExporter()} {
+		switch exporter {
+		case "", "otlp", "stdout", "none":
+		default:
+			return fmt.Errorf("unknown exporter %q, must be otlp, stdout or none", exporter)
+		}
+	}
+	if err := metrics.ValidateBuckets(telemetry.GetLatencyBuckets()); err != nil {
+		return fmt.Errorf("invalid latency_buckets: %w", err)
+	}
+	return nil
+}
+
+func validateNetworkAccessConfig(access *configv1.NetworkAccessConfig) error {
+	if access == nil {
+		return nil
+	}
+	lists := []struct {
+		name  string
+		addrs []string
+	}{
+		{"mcp.allow", access.GetMcp().GetAllow()},
+		{"mcp.deny", access.GetMcp().GetDeny()},
+		{"admin.allow", access.GetAdmin().GetAllow()},
+		{"admin.deny", access.GetAdmin().GetDeny()},
+		{"trusted_proxies", access.GetTrustedProxies()},
+	}
+	for _, list := range lists {
+		for _, addr := range list.addrs {
+			if _, _, err := net.ParseCIDR(addr); err != nil && net.ParseIP(addr) == nil {
+				return fmt.Errorf("%s: invalid IP or CIDR %q", list.name, addr)
+			}
+		}
+	}
+	return nil
+}
+
+func validateOAuthResourceServer(rs *configv1.OAuthResourceServerConfig) error {
+	if rs == nil {
+		return nil
+	}
+	if rs.GetIssuer() == "" {
+		return fmt.Errorf("issuer is required")
+	}
+	if err := validateAbsoluteURL(rs.GetIssuer()); err != nil {
+		return fmt.Errorf("invalid issuer: %w", err)
+	}
+	if rs.GetJwksUrl() != "" {
+		if err := validateAbsoluteURL(rs.GetJwksUrl()); err != nil {
+			return fmt.Errorf("invalid jwks_url: %w", err)
+		}
+	}
+	if rs.GetResource() != "" {
+		if err := validateAbsoluteURL(rs.GetResource()); err != nil {
+			return fmt.Errorf("invalid resource: %w", err)
+		}
+	}
+	for _, m := range rs.GetScopeProfiles() {
+		if m.GetScope() == "" || m.GetProfile() == "" {
+			return fmt.Errorf("scope_profiles entries require both scope and profile")
+		}
+	}
+	if rs.GetResource() == "" && len(rs.GetAudiences()) == 0 {
+		return fmt.Errorf("resource or audiences is required")
+	}
+	return nil
+}
+
+func validateVaultConfig(ctx context.Context, vc *configv1.VaultConfig) error {
+	if vc == nil {
+		return nil
+	}
+	if err := validateAbsoluteURL(vc.GetAddress()); err != nil {
+		return fmt.Errorf("invalid address: %w", err)
+	}
+	if vc.HasCacheTtl() && vc.GetCacheTtl().AsDuration() < 0 {
+		return fmt.Errorf("cache_ttl must not be negative")
+	}
+	switch vc.WhichAuth() {
+	case configv1.VaultConfig_Token_case:
+		return validateSecretValue(ctx, vc.GetToken())
+	case configv1.VaultConfig_AppRole_case:
+		if vc.GetAppRole().GetRoleId() == "" {
+			return fmt.Errorf("app_role requires role_id")
+		}
+		if !vc.GetAppRole().HasSecretId() {
+			return fmt.Errorf("app_role requires secret_id")
+		}
+		return validateSecretValue(ctx, vc.GetAppRole().GetSecretId())
+	case configv1.VaultConfig_Kubernetes_case:
+		if vc.GetKubernetes().GetRole() == "" {
+			return fmt.Errorf("kubernetes requires role")
+		}
+		return nil
+	default:
+		return fmt.Errorf("one of token, app_role or kubernetes is required")
+	}
+}
+
+func validateSecretBackends(ctx context.Context, sb *configv1.SecretBackendsConfig) error {
+	if sb == nil {
+		return nil
+	}
+	if sb.HasRefreshInterval() && sb.GetRefreshInterval().AsDuration() < 0 {
+		return fmt.Errorf("refresh_interval must not be negative")
+	}
+	if aws := sb.GetAwsSecretsManager(); aws.GetEndpoint() != "" {
+		if err := validateAbsoluteURL(aws.GetEndpoint()); err != nil {
+			return fmt.Errorf("invalid aws_secrets_manager endpoint: %w", err)
+		}
+	}
+	if gcp := sb.GetGcpSecretManager(); gcp.GetEndpoint() != "" {
+		if err := validateAbsoluteURL(gcp.GetEndpoint()); err != nil {
+			return fmt.Errorf("invalid gcp_secret_manager endpoint: %w", err)
+		}
+	}
+	azure := sb.GetAzureKeyVault()
+	if azure.GetAuthorityHost() != "" {
+		if err := validateAbsoluteURL(azure.GetAuthorityHost()); err != nil {
+			return fmt.Errorf("invalid azure_key_vault authority_host: %w", err)
+		}
+	}
+	if azure.HasClientSecret() {
+		if azure.GetTenantId() == "" || azure.GetClientId() == "" {
+			return fmt.Errorf("azure_key_vault client_secret requires tenant_id and client_id")
+		}
+		return validateSecretValue(ctx, azure.GetClientSecret())
+	}
+	return nil
+}
+
+func validateJWTAuth(ja *configv1.JWTAuthConfig) error {
+	if ja == nil {
+		return nil
+	}
+	if len(ja.GetIssuers()) == 0 {
+		return fmt.Errorf("at least one issuer is required")
+	}
+	seen := make(map[string]bool, len(ja.GetIssuers()))
+	for _, issuer := range ja.GetIssuers() {
+		if issuer.GetIssuer() == "" {
+			return fmt.Errorf("issuer is required")
+		}
+		if err := validateAbsoluteURL(issuer.GetIssuer()); err != nil {
+			return fmt.Errorf("invalid issuer: %w", err)
+		}
+		if seen[issuer.GetIssuer()] {
+			return fmt.Errorf("issuer %q is configured more than once", issuer.GetIssuer())
+		}
+		seen[issuer.GetIssuer()] = true
+		if issuer.GetJwksUrl() != "" {
+			if err := validateAbsoluteURL(issuer.GetJwksUrl()); err != nil {
+				return fmt.Errorf("invalid jwks_url: %w", err)
+			}
+		}
+		if len(issuer.GetAudiences()) == 0 {
+			return fmt.Errorf("issuer %q requires at least one audience", issuer.GetIssuer())
+		}
+		for _, m := range issuer.GetRoleMappings() {
+			if m.GetValue() == "" || m.GetRole() == "" {
+				return fmt.Errorf("role_mappings entries require both value and role")
+			}
+		}
+	}
+	// Downgraded requests without a profile would see every tool.
+	if ja.GetOnFailure() == configv1.JWTAuthConfig_FAILURE_ACTION_DOWNGRADE && ja.GetDowngradeProfile() == "" {
+		return fmt.Errorf("on_failure DOWNGRADE requires a downgrade_profile")
+	}
+	return nil
+}
+
+func validateAbsoluteURL(raw string) error {
+	if !validation.IsValidURL(raw) {
+		return fmt.Errorf("%q is not a valid URL", raw)
+	}
+	if u, _ := url.Parse(raw); u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%q must use http or https", raw)
+	}
 	return nil
 }
 
@@ -7,10 +7,12 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	configv1 "github.com/mcpany/core/proto/config/v1"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 func TestValidateOIDCAuth(t *testing.T) {
@@ -204,6 +206,45 @@ func TestValidateOAuth2Auth_Coverage(t *testing.T) {
 			}.Build(),
 			expectErr: "oauth2 client_secret is missing or empty",
 		},
+		{
+			name: "refresh_token_grant_without_client_secret",
+			oauth: configv1.OAuth2Auth_builder{
+				TokenUrl: proto.String("https://example.com/token"),
+				ClientId: configv1.SecretValue_builder{
+					EnvironmentVariable: proto.String("CLIENT_ID"),
+				}.Build(),
+				GrantType: configv1.OAuth2Auth_GRANT_TYPE_REFRESH_TOKEN.Enum(),
+				RefreshToken: configv1.SecretValue_builder{
+					PlainText: proto.String("refresh"),
+				}.Build(),
+			}.Build(),
+			expectErr: "",
+		},
+		{
+			name: "refresh_token_grant_missing_refresh_token",
+			oauth: configv1.OAuth2Auth_builder{
+				TokenUrl: proto.String("https://example.com/token"),
+				ClientId: configv1.SecretValue_builder{
+					EnvironmentVariable: proto.String("CLIENT_ID"),
+				}.Build(),
+				GrantType: configv1.OAuth2Auth_GRANT_TYPE_REFRESH_TOKEN.Enum(),
+			}.Build(),
+			expectErr: "oauth2 refresh_token is required",
+		},
+		{
+			name: "negative_refresh_before_expiry",
+			oauth: configv1.OAuth2Auth_builder{
+				TokenUrl: proto.String("https://example.com/token"),
+				ClientId: configv1.SecretValue_builder{
+					EnvironmentVariable: proto.String("CLIENT_ID"),
+				}.Build(),
+				ClientSecret: configv1.SecretValue_builder{
+					EnvironmentVariable: proto.String("CLIENT_SECRET"),
+				}.Build(),
+				RefreshBeforeExpiry: durationpb.New(-time.Second),
+			}.Build(),
+			expectErr: "refresh_before_expiry must not be negative",
+		},
         {
 			name: "invalid_client_id_secret_validation",
 			oauth: configv1.OAuth2Auth_builder{
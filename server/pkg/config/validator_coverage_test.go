@@ -7,12 +7,15 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/mcpany/core/proto/bus"
 	configv1 "github.com/mcpany/core/proto/config/v1"
 	"github.com/mcpany/core/server/pkg/validation"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
@@ -75,6 +78,110 @@ func TestValidateAuditConfig(t *testing.T) {
 		WebhookUrl:  proto.String("not-a-url"),
 	}.Build())
 	assert.Error(t, err)
+
+	// Case 6: Signing
+	key := configv1.SecretValue_builder{EnvironmentVariable: proto.String("AUDIT_SIGNING_KEY")}.Build()
+	assert.NoError(t, validateAuditConfig(configv1.AuditConfig_builder{
+		Enabled:     proto.Bool(true),
+		StorageType: configv1.AuditConfig_STORAGE_TYPE_SQLITE.Enum(),
+		Signing:     configv1.AuditSigningConfig_builder{PrivateKey: key}.Build(),
+	}.Build()))
+
+	err = validateAuditConfig(configv1.AuditConfig_builder{
+		Enabled:     proto.Bool(true),
+		StorageType: configv1.AuditConfig_STORAGE_TYPE_WEBHOOK.Enum(),
+		WebhookUrl:  proto.String("https://example.com/audit"),
+		Signing:     configv1.AuditSigningConfig_builder{PrivateKey: key}.Build(),
+	}.Build())
+	assert.ErrorContains(t, err, "signing is only supported by the file and sqlite storage types")
+
+	err = validateAuditConfig(configv1.AuditConfig_builder{
+		Enabled:     proto.Bool(true),
+		StorageType: configv1.AuditConfig_STORAGE_TYPE_SQLITE.Enum(),
+		Signing:     configv1.AuditSigningConfig_builder{CheckpointInterval: proto.Int32(10)}.Build(),
+	}.Build())
+	assert.ErrorContains(t, err, "signing.private_key is required")
+
+	err = validateAuditConfig(configv1.AuditConfig_builder{
+		Enabled:     proto.Bool(true),
+		StorageType: configv1.AuditConfig_STORAGE_TYPE_SQLITE.Enum(),
+		Signing:     configv1.AuditSigningConfig_builder{PrivateKey: key, CheckpointInterval: proto.Int32(-1)}.Build(),
+	}.Build())
+	assert.ErrorContains(t, err, "signing.checkpoint_interval must not be negative")
+
+	// Case 7: Export
+	s3 := configv1.S3Fs_builder{Bucket: proto.String("audit-archive")}.Build()
+	assert.NoError(t, validateAuditConfig(configv1.AuditConfig_builder{
+		Enabled:     proto.Bool(true),
+		StorageType: configv1.AuditConfig_STORAGE_TYPE_SQLITE.Enum(),
+		Export: configv1.AuditExportConfig_builder{
+			S3:        s3,
+			SpillDir:  proto.String("/var/lib/mcpany/audit-spill"),
+			Retention: durationpb.New(365 * 24 * time.Hour),
+		}.Build(),
+	}.Build()))
+
+	err = validateAuditConfig(configv1.AuditConfig_builder{
+		Enabled: proto.Bool(true),
+		Export:  configv1.AuditExportConfig_builder{SpillDir: proto.String("/tmp/spill")}.Build(),
+	}.Build())
+	assert.ErrorContains(t, err, "export: either s3 or gcs is required")
+
+	err = validateAuditConfig(configv1.AuditConfig_builder{
+		Enabled: proto.Bool(true),
+		Export:  configv1.AuditExportConfig_builder{Gcs: configv1.GcsFs_builder{Bucket: proto.String("b")}.Build()}.Build(),
+	}.Build())
+	assert.ErrorContains(t, err, "export: spill_dir is required")
+
+	err = validateAuditConfig(configv1.AuditConfig_builder{
+		Enabled: proto.Bool(true),
+		Export: configv1.AuditExportConfig_builder{
+			S3:            s3,
+			SpillDir:      proto.String("/tmp/spill"),
+			FlushInterval: durationpb.New(-time.Second),
+		}.Build(),
+	}.Build())
+	assert.ErrorContains(t, err, "export: flush_interval must not be negative")
+
+	// Case 8: Retention
+	assert.NoError(t, validateAuditConfig(configv1.AuditConfig_builder{
+		Enabled:     proto.Bool(true),
+		StorageType: configv1.AuditConfig_STORAGE_TYPE_SQLITE.Enum(),
+		Retention: configv1.RetentionConfig_builder{
+			MaxAge:  durationpb.New(90 * 24 * time.Hour),
+			MaxRows: proto.Int64(1000000),
+		}.Build(),
+	}.Build()))
+
+	err = validateAuditConfig(configv1.AuditConfig_builder{
+		Enabled:     proto.Bool(true),
+		StorageType: configv1.AuditConfig_STORAGE_TYPE_FILE.Enum(),
+		OutputPath:  proto.String("/tmp/audit.log"),
+		Retention:   configv1.RetentionConfig_builder{MaxRows: proto.Int64(10)}.Build(),
+	}.Build())
+	assert.ErrorContains(t, err, "retention is only supported by the sqlite storage type")
+
+	err = validateAuditConfig(configv1.AuditConfig_builder{
+		Enabled:     proto.Bool(true),
+		StorageType: configv1.AuditConfig_STORAGE_TYPE_SQLITE.Enum(),
+		Retention:   configv1.RetentionConfig_builder{MaxSizeBytes: proto.Int64(-1)}.Build(),
+	}.Build())
+	assert.ErrorContains(t, err, "retention: max_size_bytes must not be negative")
+}
+
+func TestValidateRetentionConfig(t *testing.T) {
+	assert.NoError(t, validateRetentionConfig(nil))
+	assert.NoError(t, validateRetentionConfig(configv1.RetentionConfig_builder{
+		MaxAge:   durationpb.New(7 * 24 * time.Hour),
+		Interval: durationpb.New(10 * time.Minute),
+	}.Build()))
+
+	err := validateRetentionConfig(configv1.RetentionConfig_builder{MaxAge: durationpb.New(-time.Hour)}.Build())
+	assert.EqualError(t, err, "max_age must not be negative")
+	err = validateRetentionConfig(configv1.RetentionConfig_builder{MaxRows: proto.Int64(-1)}.Build())
+	assert.EqualError(t, err, "max_rows must not be negative")
+	err = validateRetentionConfig(configv1.RetentionConfig_builder{Interval: durationpb.New(-time.Minute)}.Build())
+	assert.EqualError(t, err, "interval must not be negative")
 }
 
 func TestValidateDLPConfig(t *testing.T) {
@@ -93,6 +200,119 @@ func TestValidateDLPConfig(t *testing.T) {
 	}.Build())
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid regex pattern")
+
+	// Case 4: Invalid allow pattern
+	err = validateDLPConfig(configv1.DLPConfig_builder{
+		AllowPatterns: []string{"("},
+	}.Build())
+	assert.ErrorContains(t, err, "invalid allow pattern")
+
+	// Case 5: Tool rules
+	err = validateDLPConfig(configv1.DLPConfig_builder{
+		ToolRules: []*configv1.DLPToolRule{
+			configv1.DLPToolRule_builder{Tools: []string{"crm.*"}, AllowPatterns: []string{"@example\\.com$"}, DenyPatterns: []string{"cust-\\d+"}}.Build(),
+		},
+	}.Build())
+	assert.NoError(t, err)
+
+	err = validateDLPConfig(configv1.DLPConfig_builder{
+		ToolRules: []*configv1.DLPToolRule{configv1.DLPToolRule_builder{DenyPatterns: []string{"x"}}.Build()},
+	}.Build())
+	assert.ErrorContains(t, err, "tool rule 0: tools is required")
+
+	err = validateDLPConfig(configv1.DLPConfig_builder{
+		ToolRules: []*configv1.DLPToolRule{configv1.DLPToolRule_builder{Tools: []string{"crm.["}}.Build()},
+	}.Build())
+	assert.ErrorContains(t, err, "invalid tool pattern")
+
+	err = validateDLPConfig(configv1.DLPConfig_builder{
+		ToolRules: []*configv1.DLPToolRule{configv1.DLPToolRule_builder{Tools: []string{"crm.*"}, DenyPatterns: []string{"["}}.Build()},
+	}.Build())
+	assert.ErrorContains(t, err, "tool rule 0: invalid regex pattern")
+}
+
+func TestValidateOutputGuardConfig(t *testing.T) {
+	assert.NoError(t, validateOutputGuardConfig(nil))
+
+	err := validateOutputGuardConfig(configv1.OutputGuardConfig_builder{
+		CustomPatterns: []string{`(?i)send .* to \S+@\S+`},
+		AllowedDomains: []string{"example.com"},
+		ToolRules: []*configv1.OutputGuardToolRule{
+			configv1.OutputGuardToolRule_builder{Tools: []string{"web.*"}, Action: configv1.OutputGuardConfig_ACTION_BLOCK.Enum()}.Build(),
+		},
+	}.Build())
+	assert.NoError(t, err)
+
+	err = validateOutputGuardConfig(configv1.OutputGuardConfig_builder{CustomPatterns: []string{"["}}.Build())
+	assert.ErrorContains(t, err, "invalid regex pattern")
+
+	err = validateOutputGuardConfig(configv1.OutputGuardConfig_builder{AllowedDomains: []string{"https://example.com"}}.Build())
+	assert.ErrorContains(t, err, "invalid allowed domain")
+
+	err = validateOutputGuardConfig(configv1.OutputGuardConfig_builder{
+		ToolRules: []*configv1.OutputGuardToolRule{configv1.OutputGuardToolRule_builder{Disabled: proto.Bool(true)}.Build()},
+	}.Build())
+	assert.ErrorContains(t, err, "tool rule 0: tools is required")
+}
+
+func TestValidateTelemetryConfig(t *testing.T) {
+	assert.NoError(t, validateTelemetryConfig(nil))
+	assert.NoError(t, validateTelemetryConfig(configv1.TelemetryConfig_builder{
+		TracesExporter: proto.String("otlp"),
+		SamplingRatio:  proto.Float64(0),
+	}.Build()))
+
+	err := validateTelemetryConfig(configv1.TelemetryConfig_builder{SamplingRatio: proto.Float64(1.5)}.Build())
+	assert.ErrorContains(t, err, "sampling_ratio must be between 0 and 1, got 1.5")
+
+	err = validateTelemetryConfig(configv1.TelemetryConfig_builder{MetricsExporter: proto.String("prometheus")}.Build())
+	assert.ErrorContains(t, err, `unknown exporter "prometheus"`)
+
+	err = validateTelemetryConfig(configv1.TelemetryConfig_builder{LatencyBuckets: []float64{0.1, 1, 0.5}}.Build())
+	assert.ErrorContains(t, err, "invalid latency_buckets: bucket 2 (0.5) must be greater than bucket 1 (1)")
+}
+
+func TestValidateContextBudgetConfig(t *testing.T) {
+	assert.NoError(t, validateContextBudgetConfig(nil))
+	assert.NoError(t, validateContextBudgetConfig(configv1.ContextBudgetConfig_builder{}.Build()))
+
+	err := validateContextBudgetConfig(configv1.ContextBudgetConfig_builder{
+		Enabled:   proto.Bool(true),
+		MaxTokens: proto.Int64(200000),
+		Stages: []*configv1.ContextBudgetStage{
+			configv1.ContextBudgetStage_builder{ThresholdPercent: proto.Int32(80), MaxChars: proto.Int32(4000)}.Build(),
+		},
+	}.Build())
+	assert.NoError(t, err)
+
+	err = validateContextBudgetConfig(configv1.ContextBudgetConfig_builder{Enabled: proto.Bool(true)}.Build())
+	assert.ErrorContains(t, err, "max_tokens must be greater than 0")
+
+	err = validateContextBudgetConfig(configv1.ContextBudgetConfig_builder{
+		Enabled:   proto.Bool(true),
+		MaxTokens: proto.Int64(200000),
+		Stages:    []*configv1.ContextBudgetStage{configv1.ContextBudgetStage_builder{MaxChars: proto.Int32(4000)}.Build()},
+	}.Build())
+	assert.ErrorContains(t, err, "stage 0: threshold_percent must be greater than 0")
+}
+
+func TestValidateNetworkAccessConfig(t *testing.T) {
+	assert.NoError(t, validateNetworkAccessConfig(nil))
+
+	err := validateNetworkAccessConfig(configv1.NetworkAccessConfig_builder{
+		Mcp:            configv1.NetworkAccessRules_builder{Allow: []string{"10.0.0.0/8", "192.168.1.7"}, Deny: []string{"10.0.13.0/24"}}.Build(),
+		Admin:          configv1.NetworkAccessRules_builder{Allow: []string{"fd00::/8"}}.Build(),
+		TrustedProxies: []string{"172.16.0.10"},
+	}.Build())
+	assert.NoError(t, err)
+
+	err = validateNetworkAccessConfig(configv1.NetworkAccessConfig_builder{
+		Admin: configv1.NetworkAccessRules_builder{Deny: []string{"10.0.0.0/33"}}.Build(),
+	}.Build())
+	assert.ErrorContains(t, err, `admin.deny: invalid IP or CIDR "10.0.0.0/33"`)
+
+	err = validateNetworkAccessConfig(configv1.NetworkAccessConfig_builder{TrustedProxies: []string{"proxy.internal"}}.Build())
+	assert.ErrorContains(t, err, `trusted_proxies: invalid IP or CIDR "proxy.internal"`)
 }
 
 func TestValidateSecretValue_RemoteContent_Errors(t *testing.T) {
@@ -320,6 +540,424 @@ func TestValidateHTTPService_SchemaErrors(t *testing.T) {
 	assert.Contains(t, err.Error(), "input_schema")
 }
 
+func TestValidateHTTPService_Pagination(t *testing.T) {
+	tests := []struct {
+		name         string
+		call         *configv1.HttpCallDefinition
+		errSubstring string
+	}{
+		{
+			name: "valid cursor pagination",
+			call: configv1.HttpCallDefinition_builder{
+				Pagination: configv1.PaginationConfig_builder{
+					Style:          configv1.PaginationConfig_STYLE_CURSOR.Enum(),
+					Param:          proto.String("after"),
+					NextCursorPath: proto.String("{.next}"),
+				}.Build(),
+			}.Build(),
+		},
+		{
+			name: "missing style",
+			call: configv1.HttpCallDefinition_builder{
+				Pagination: configv1.PaginationConfig_builder{Param: proto.String("page")}.Build(),
+			}.Build(),
+			errSubstring: "style is required",
+		},
+		{
+			name: "missing param",
+			call: configv1.HttpCallDefinition_builder{
+				Pagination: configv1.PaginationConfig_builder{
+					Style: configv1.PaginationConfig_STYLE_LINK_HEADER.Enum(),
+				}.Build(),
+			}.Build(),
+			errSubstring: "param is required",
+		},
+		{
+			name: "offset without items path",
+			call: configv1.HttpCallDefinition_builder{
+				Pagination: configv1.PaginationConfig_builder{
+					Style: configv1.PaginationConfig_STYLE_OFFSET.Enum(),
+					Param: proto.String("offset"),
+				}.Build(),
+			}.Build(),
+			errSubstring: "items_path is required",
+		},
+		{
+			name: "reserved cursor parameter",
+			call: configv1.HttpCallDefinition_builder{
+				Pagination: configv1.PaginationConfig_builder{
+					Style: configv1.PaginationConfig_STYLE_LINK_HEADER.Enum(),
+					Param: proto.String("page"),
+				}.Build(),
+				Parameters: []*configv1.HttpParameterMapping{
+					configv1.HttpParameterMapping_builder{
+						Schema: configv1.ParameterSchema_builder{Name: proto.String("cursor")}.Build(),
+					}.Build(),
+				},
+			}.Build(),
+			errSubstring: "reserved for the pagination cursor",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateHTTPService(configv1.HttpUpstreamService_builder{
+				Address: proto.String("http://example.com"),
+				Calls:   map[string]*configv1.HttpCallDefinition{"list": tt.call},
+			}.Build())
+			if tt.errSubstring == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errSubstring)
+		})
+	}
+}
+
+func TestValidateHTTPService_ResponseContent(t *testing.T) {
+	content := func(mode configv1.ResponseContentConfig_Mode) *configv1.ResponseContentConfig_builder {
+		return &configv1.ResponseContentConfig_builder{Mode: mode.Enum()}
+	}
+	withDelimiter := content(configv1.ResponseContentConfig_MODE_CONVERT)
+	withDelimiter.CsvDelimiter = proto.String("||")
+	withMimeType := content(configv1.ResponseContentConfig_MODE_BASE64)
+	withMimeType.MimeType = proto.String("not a mime type")
+
+	tests := []struct {
+		name         string
+		call         *configv1.HttpCallDefinition
+		errSubstring string
+	}{
+		{
+			name: "valid",
+			call: configv1.HttpCallDefinition_builder{
+				ResponseContent: content(configv1.ResponseContentConfig_MODE_AUTO).Build(),
+			}.Build(),
+		},
+		{
+			name: "missing mode",
+			call: configv1.HttpCallDefinition_builder{
+				ResponseContent: configv1.ResponseContentConfig_builder{Accept: proto.String("text/csv")}.Build(),
+			}.Build(),
+			errSubstring: "mode is required",
+		},
+		{
+			name: "with output transformer",
+			call: configv1.HttpCallDefinition_builder{
+				ResponseContent:   content(configv1.ResponseContentConfig_MODE_CONVERT).Build(),
+				OutputTransformer: configv1.OutputTransformer_builder{}.Build(),
+			}.Build(),
+			errSubstring: "cannot be combined with output_transformer",
+		},
+		{
+			name:         "long csv delimiter",
+			call:         configv1.HttpCallDefinition_builder{ResponseContent: withDelimiter.Build()}.Build(),
+			errSubstring: "csv_delimiter must be a single character",
+		},
+		{
+			name:         "invalid mime type",
+			call:         configv1.HttpCallDefinition_builder{ResponseContent: withMimeType.Build()}.Build(),
+			errSubstring: "invalid mime_type",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateHTTPService(configv1.HttpUpstreamService_builder{
+				Address: proto.String("http://example.com"),
+				Calls:   map[string]*configv1.HttpCallDefinition{"get": tt.call},
+			}.Build())
+			if tt.errSubstring == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errSubstring)
+		})
+	}
+}
+
+func TestValidateHTTPService_OutputTransformer(t *testing.T) {
+	csvOptions := configv1.CsvConversionOptions_builder{Delimiter: proto.String(";")}.Build()
+	tests := []struct {
+		name         string
+		ot           *configv1.OutputTransformer
+		errSubstring string
+	}{
+		{
+			name: "valid csv",
+			ot: configv1.OutputTransformer_builder{
+				Format:     configv1.OutputTransformer_CSV.Enum(),
+				CsvOptions: csvOptions,
+			}.Build(),
+		},
+		{
+			name: "valid xml",
+			ot: configv1.OutputTransformer_builder{
+				Format:     configv1.OutputTransformer_XML_TO_JSON.Enum(),
+				XmlOptions: configv1.XmlConversionOptions_builder{CoerceTypes: proto.Bool(true)}.Build(),
+			}.Build(),
+		},
+		{
+			name: "csv options with another format",
+			ot: configv1.OutputTransformer_builder{
+				Format:     configv1.OutputTransformer_JSON.Enum(),
+				CsvOptions: csvOptions,
+			}.Build(),
+			errSubstring: "csv_options requires format CSV",
+		},
+		{
+			name: "xml options with another format",
+			ot: configv1.OutputTransformer_builder{
+				Format:     configv1.OutputTransformer_XML.Enum(),
+				XmlOptions: configv1.XmlConversionOptions_builder{}.Build(),
+			}.Build(),
+			errSubstring: "xml_options requires format XML_TO_JSON",
+		},
+		{
+			name: "long csv delimiter",
+			ot: configv1.OutputTransformer_builder{
+				Format:     configv1.OutputTransformer_CSV.Enum(),
+				CsvOptions: configv1.CsvConversionOptions_builder{Delimiter: proto.String("||")}.Build(),
+			}.Build(),
+			errSubstring: "delimiter must be a single character",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateHTTPService(configv1.HttpUpstreamService_builder{
+				Address: proto.String("http://example.com"),
+				Calls: map[string]*configv1.HttpCallDefinition{"get": configv1.HttpCallDefinition_builder{
+					OutputTransformer: tt.ot,
+				}.Build()},
+			}.Build())
+			if tt.errSubstring == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errSubstring)
+		})
+	}
+}
+
+func TestValidateUpstreamService_SLOs(t *testing.T) {
+	objective := func(name string, target float64) *configv1.ServiceLevelObjective {
+		return configv1.ServiceLevelObjective_builder{Name: proto.String(name), Target: proto.Float64(target)}.Build()
+	}
+	withWindow := objective("availability", 99)
+	withWindow.SetWindow(durationpb.New(0))
+	withLatency := objective("latency", 99)
+	withLatency.SetLatencyThreshold(durationpb.New(-1))
+
+	tests := []struct {
+		name         string
+		slos         []*configv1.ServiceLevelObjective
+		errSubstring string
+	}{
+		{name: "valid", slos: []*configv1.ServiceLevelObjective{objective("availability", 99.9), objective("latency", 95)}},
+		{name: "missing name", slos: []*configv1.ServiceLevelObjective{objective("", 99)}, errSubstring: "slo name is empty"},
+		{name: "duplicate name", slos: []*configv1.ServiceLevelObjective{objective("a", 99), objective("a", 95)}, errSubstring: "duplicate slo name"},
+		{name: "target 100", slos: []*configv1.ServiceLevelObjective{objective("a", 100)}, errSubstring: "target must be between 0 and 100"},
+		{name: "missing target", slos: []*configv1.ServiceLevelObjective{objective("a", 0)}, errSubstring: "target must be between 0 and 100"},
+		{name: "zero window", slos: []*configv1.ServiceLevelObjective{withWindow}, errSubstring: "window must be positive"},
+		{name: "negative latency", slos: []*configv1.ServiceLevelObjective{withLatency}, errSubstring: "latency_threshold must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateUpstreamService(context.Background(), configv1.UpstreamServiceConfig_builder{
+				Name: proto.String("svc"),
+				HttpService: configv1.HttpUpstreamService_builder{
+					Address: proto.String("http://example.com"),
+				}.Build(),
+				Slos: tt.slos,
+			}.Build())
+			if tt.errSubstring == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errSubstring)
+		})
+	}
+}
+
+func TestValidateUpstreamService_ToolAliases(t *testing.T) {
+	alias := func(name, tool, until string) *configv1.ToolAlias {
+		return configv1.ToolAlias_builder{Name: proto.String(name), Tool: proto.String(tool), DeprecatedUntil: proto.String(until)}.Build()
+	}
+
+	tests := []struct {
+		name         string
+		aliases      []*configv1.ToolAlias
+		errSubstring string
+	}{
+		{name: "valid", aliases: []*configv1.ToolAlias{alias("forecast", "get_forecast", "2026-06-30T00:00:00Z"), alias("alerts", "get_alerts", "")}},
+		{name: "missing name", aliases: []*configv1.ToolAlias{alias("", "get_forecast", "")}, errSubstring: "tool alias name is empty"},
+		{name: "missing tool", aliases: []*configv1.ToolAlias{alias("forecast", "", "")}, errSubstring: "does not name the new tool"},
+		{name: "duplicate name", aliases: []*configv1.ToolAlias{alias("a", "b", ""), alias("a", "c", "")}, errSubstring: "duplicate tool alias"},
+		{name: "self", aliases: []*configv1.ToolAlias{alias("a", "a", "")}, errSubstring: "points to itself"},
+		{name: "bad timestamp", aliases: []*configv1.ToolAlias{alias("a", "b", "next month")}, errSubstring: "is not an RFC3339 timestamp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateUpstreamService(context.Background(), configv1.UpstreamServiceConfig_builder{
+				Name: proto.String("svc"),
+				HttpService: configv1.HttpUpstreamService_builder{
+					Address: proto.String("http://example.com"),
+				}.Build(),
+				ToolAliases: tt.aliases,
+			}.Build())
+			if tt.errSubstring == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errSubstring)
+		})
+	}
+}
+
+func TestValidateUpstreamService_ToolTransforms(t *testing.T) {
+	tests := []struct {
+		name         string
+		transform    *configv1.ToolTransform
+		errSubstring string
+	}{
+		{name: "valid", transform: configv1.ToolTransform_builder{Truncate: configv1.TruncateTransform_builder{MaxChars: proto.Int32(4000)}.Build()}.Build()},
+		{name: "defaults", transform: configv1.ToolTransform_builder{Paginate: configv1.PaginateTransform_builder{}.Build()}.Build()},
+		{name: "empty", transform: configv1.ToolTransform_builder{}.Build(), errSubstring: `tool "fetch" has an empty transform`},
+		{name: "negative max_chars", transform: configv1.ToolTransform_builder{Truncate: configv1.TruncateTransform_builder{MaxChars: proto.Int32(-1)}.Build()}.Build(), errSubstring: "max_chars must not be negative"},
+		{name: "negative page_size", transform: configv1.ToolTransform_builder{Paginate: configv1.PaginateTransform_builder{PageSize: proto.Int32(-1)}.Build()}.Build(), errSubstring: "page_size must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateUpstreamService(context.Background(), configv1.UpstreamServiceConfig_builder{
+				Name: proto.String("svc"),
+				HttpService: configv1.HttpUpstreamService_builder{
+					Address: proto.String("http://example.com"),
+					Tools: []*configv1.ToolDefinition{
+						configv1.ToolDefinition_builder{Name: proto.String("fetch"), Transforms: []*configv1.ToolTransform{tt.transform}}.Build(),
+					},
+				}.Build(),
+			}.Build())
+			if tt.errSubstring == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errSubstring)
+		})
+	}
+}
+
+func TestValidateUpstreamService_Canary(t *testing.T) {
+	canary := func(stable string, weight int32, maxErrorRate float64) *configv1.CanaryConfig {
+		return configv1.CanaryConfig_builder{
+			StableService: proto.String(stable),
+			Weight:        proto.Int32(weight),
+			MaxErrorRate:  proto.Float64(maxErrorRate),
+		}.Build()
+	}
+
+	tests := []struct {
+		name         string
+		canary       *configv1.CanaryConfig
+		errSubstring string
+	}{
+		{name: "valid", canary: canary("weather", 5, 0.2)},
+		{name: "missing stable", canary: canary("", 5, 0.2), errSubstring: "stable_service is empty"},
+		{name: "self", canary: canary("svc", 5, 0.2), errSubstring: "is the service itself"},
+		{name: "weight", canary: canary("weather", 101, 0.2), errSubstring: "weight must be between 0 and 100"},
+		{name: "error rate", canary: canary("weather", 5, 20), errSubstring: "max_error_rate must be between 0 and 1"},
+		{name: "window", canary: configv1.CanaryConfig_builder{
+			StableService: proto.String("weather"),
+			Window:        durationpb.New(0),
+		}.Build(), errSubstring: "window must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateUpstreamService(context.Background(), configv1.UpstreamServiceConfig_builder{
+				Name: proto.String("svc"),
+				HttpService: configv1.HttpUpstreamService_builder{
+					Address: proto.String("http://example.com"),
+				}.Build(),
+				Canary: tt.canary,
+			}.Build())
+			if tt.errSubstring == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errSubstring)
+		})
+	}
+}
+
+func TestValidateConnectionPool(t *testing.T) {
+	assert.NoError(t, validateConnectionPool(nil))
+	assert.NoError(t, validateConnectionPool(configv1.ConnectionPoolConfig_builder{
+		MaxConnections:      proto.Int32(8),
+		MinConnections:      proto.Int32(2),
+		IdleTimeout:         durationpb.New(5 * time.Minute),
+		HealthCheckInterval: durationpb.New(30 * time.Second),
+	}.Build()))
+
+	err := validateConnectionPool(configv1.ConnectionPoolConfig_builder{MinConnections: proto.Int32(-1)}.Build())
+	assert.EqualError(t, err, "connection counts must not be negative")
+
+	err = validateConnectionPool(configv1.ConnectionPoolConfig_builder{
+		MaxConnections: proto.Int32(2),
+		MinConnections: proto.Int32(4),
+	}.Build())
+	assert.EqualError(t, err, "min_connections (4) exceeds max_connections (2)")
+
+	err = validateConnectionPool(configv1.ConnectionPoolConfig_builder{HealthCheckInterval: durationpb.New(-time.Second)}.Build())
+	assert.EqualError(t, err, "idle_timeout and health_check_interval must not be negative")
+}
+
+func TestValidateUpstreamService_TLSConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		tls          *configv1.TLSConfig
+		errSubstring string
+	}{
+		{name: "valid", tls: configv1.TLSConfig_builder{
+			ServerName:     proto.String("internal.example.com"),
+			CaCertPath:     proto.String("certs/ca.pem"),
+			ClientCertPath: proto.String("certs/client.pem"),
+			ClientKeyPath:  proto.String("certs/client.key"),
+			MinVersion:     configv1.TLSConfig_TLS_1_3.Enum(),
+		}.Build()},
+		{name: "cert without key", tls: configv1.TLSConfig_builder{
+			ClientCertPath: proto.String("certs/client.pem"),
+		}.Build(), errSubstring: "must be set together"},
+		{name: "path traversal", tls: configv1.TLSConfig_builder{
+			CaCertPath: proto.String("../../etc/ca.pem"),
+		}.Build(), errSubstring: "'ca_cert_path' is not a secure path"},
+		{name: "unknown version", tls: configv1.TLSConfig_builder{
+			MinVersion: configv1.TLSConfig_Version(9).Enum(),
+		}.Build(), errSubstring: "unsupported 'min_version'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateUpstreamService(context.Background(), configv1.UpstreamServiceConfig_builder{
+				Name: proto.String("svc"),
+				HttpService: configv1.HttpUpstreamService_builder{
+					Address:   proto.String("https://example.com"),
+					TlsConfig: tt.tls,
+				}.Build(),
+			}.Build())
+			if tt.errSubstring == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "http tls_config error")
+			assert.Contains(t, err.Error(), tt.errSubstring)
+		})
+	}
+}
+
 func TestValidateAPIKeyAuth_Errors(t *testing.T) {
 	ctx := context.Background()
 	// Value missing
@@ -377,6 +1015,74 @@ func TestValidateMcpService_BundleErrors(t *testing.T) {
 	assert.Contains(t, err.Error(), "empty bundle_path")
 }
 
+func TestValidateMcpService_Peer(t *testing.T) {
+	httpConn := configv1.McpStreamableHttpConnection_builder{HttpAddress: proto.String("https://org-proxy.example.com/mcp")}.Build()
+	secret := configv1.SecretValue_builder{PlainText: proto.String("secret")}.Build()
+
+	// API key with profile mapping
+	err := validateMcpService(context.Background(), configv1.McpUpstreamService_builder{
+		HttpConnection: httpConn,
+		Peer: configv1.McpPeerConfig_builder{
+			ApiKey:         secret,
+			ProfileMapping: map[string]string{"dev": "team-dev"},
+		}.Build(),
+	}.Build())
+	assert.NoError(t, err)
+
+	// OAuth2 with a discovered authorization server
+	err = validateMcpService(context.Background(), configv1.McpUpstreamService_builder{
+		HttpConnection: httpConn,
+		Peer: configv1.McpPeerConfig_builder{
+			Oauth2: configv1.OAuth2Auth_builder{ClientId: secret, ClientSecret: secret}.Build(),
+		}.Build(),
+	}.Build())
+	assert.NoError(t, err)
+
+	err = validateMcpService(context.Background(), configv1.McpUpstreamService_builder{
+		HttpConnection: httpConn,
+		Peer:           configv1.McpPeerConfig_builder{Oauth2: configv1.OAuth2Auth_builder{}.Build()}.Build(),
+	}.Build())
+	assert.ErrorContains(t, err, "mcp peer oauth2 requires a client_id")
+
+	err = validateMcpService(context.Background(), configv1.McpUpstreamService_builder{
+		BundleConnection: configv1.McpBundleConnection_builder{BundlePath: proto.String("bundle.zip")}.Build(),
+		Peer:             configv1.McpPeerConfig_builder{}.Build(),
+	}.Build())
+	assert.ErrorContains(t, err, "mcp peer requires http_connection")
+
+	err = validateMcpService(context.Background(), configv1.McpUpstreamService_builder{
+		HttpConnection: httpConn,
+		Peer:           configv1.McpPeerConfig_builder{MaxHops: proto.Int32(-1)}.Build(),
+	}.Build())
+	assert.ErrorContains(t, err, "max_hops must not be negative")
+
+	err = validateMcpService(context.Background(), configv1.McpUpstreamService_builder{
+		HttpConnection: httpConn,
+		Peer:           configv1.McpPeerConfig_builder{ProfileMapping: map[string]string{"dev": ""}}.Build(),
+	}.Build())
+	assert.ErrorContains(t, err, "profile_mapping has an empty profile")
+}
+
+func TestValidateMcpService_SessionAffinity(t *testing.T) {
+	service := func(affinity *configv1.SessionAffinityConfig) *configv1.McpUpstreamService {
+		return configv1.McpUpstreamService_builder{
+			HttpConnection:  configv1.McpStreamableHttpConnection_builder{HttpAddress: proto.String("https://mcp.example.com/mcp")}.Build(),
+			SessionAffinity: affinity,
+		}.Build()
+	}
+	assert.NoError(t, validateMcpService(context.Background(), service(configv1.SessionAffinityConfig_builder{
+		IsEnabled:   proto.Bool(true),
+		IdleTimeout: durationpb.New(5 * time.Minute),
+		MaxSessions: proto.Int32(10),
+	}.Build())))
+
+	err := validateMcpService(context.Background(), service(configv1.SessionAffinityConfig_builder{IdleTimeout: durationpb.New(-time.Second)}.Build()))
+	assert.EqualError(t, err, "mcp session_affinity idle_timeout must not be negative")
+
+	err = validateMcpService(context.Background(), service(configv1.SessionAffinityConfig_builder{MaxSessions: proto.Int32(-1)}.Build()))
+	assert.EqualError(t, err, "mcp session_affinity max_sessions must not be negative")
+}
+
 func TestValidateSQLService_SchemaErrors(t *testing.T) {
 	// Invalid Input Schema
 	s := configv1.SqlUpstreamService_builder{
@@ -523,3 +1229,449 @@ func TestValidateCollection_Coverage(t *testing.T) {
 	}.Build())
 	assert.Error(t, validateCollection(ctx, coll))
 }
+
+func TestValidateCaptureConfig(t *testing.T) {
+	assert.NoError(t, validateCaptureConfig(nil))
+	assert.NoError(t, validateCaptureConfig(configv1.CaptureConfig_builder{
+		Enabled: proto.Bool(true),
+		Tools:   []string{"weather.*"},
+	}.Build()))
+	// A disabled capture is not validated.
+	assert.NoError(t, validateCaptureConfig(configv1.CaptureConfig_builder{MaxCaptures: proto.Int32(-1)}.Build()))
+
+	err := validateCaptureConfig(configv1.CaptureConfig_builder{Enabled: proto.Bool(true), MaxCaptures: proto.Int32(-1)}.Build())
+	assert.EqualError(t, err, "max_captures must not be negative")
+	err = validateCaptureConfig(configv1.CaptureConfig_builder{Enabled: proto.Bool(true), Tools: []string{"weather.["}}.Build())
+	assert.ErrorContains(t, err, `invalid tool pattern "weather.["`)
+}
+
+func TestValidateLogSinks(t *testing.T) {
+	assert.NoError(t, validateLogSinks(nil))
+	assert.NoError(t, validateLogSinks([]*configv1.LogSinkConfig{
+		configv1.LogSinkConfig_builder{
+			Syslog: configv1.SyslogSinkConfig_builder{Address: proto.String("localhost:514")}.Build(),
+		}.Build(),
+		configv1.LogSinkConfig_builder{
+			Loki: configv1.LokiSinkConfig_builder{Url: proto.String("http://loki:3100/loki/api/v1/push")}.Build(),
+		}.Build(),
+	}))
+
+	err := validateLogSinks([]*configv1.LogSinkConfig{configv1.LogSinkConfig_builder{}.Build()})
+	assert.EqualError(t, err, "log sink 0: log sink has no syslog, loki or otlp configuration")
+
+	otlp := configv1.OtlpLogSinkConfig_builder{Endpoint: proto.String("http://collector:4318/v1/logs")}.Build()
+	err = validateLogSinks([]*configv1.LogSinkConfig{
+		configv1.LogSinkConfig_builder{Name: proto.String("a"), Otlp: otlp}.Build(),
+		configv1.LogSinkConfig_builder{Name: proto.String("a"), Otlp: otlp}.Build(),
+	})
+	assert.EqualError(t, err, `duplicate log sink name "a"`)
+
+	err = validateLogSinks([]*configv1.LogSinkConfig{
+		configv1.LogSinkConfig_builder{Otlp: otlp, QueueSize: proto.Int32(-1)}.Build(),
+	})
+	assert.EqualError(t, err, "log sink 0: batch_size and queue_size must not be negative")
+
+	err = validateLogSinks([]*configv1.LogSinkConfig{
+		configv1.LogSinkConfig_builder{Otlp: configv1.OtlpLogSinkConfig_builder{Endpoint: proto.String("collector:4318")}.Build()}.Build(),
+	})
+	assert.ErrorContains(t, err, "log sink 0: invalid otlp endpoint")
+}
+
+func TestValidateDebugListener(t *testing.T) {
+	assert.NoError(t, validateDebugListener(nil))
+	assert.NoError(t, validateDebugListener(configv1.DebugListenerConfig_builder{
+		Enabled: proto.Bool(true),
+		Address: proto.String("127.0.0.1:6060"),
+	}.Build()))
+
+	err := validateDebugListener(configv1.DebugListenerConfig_builder{Address: proto.String("6060")}.Build())
+	assert.ErrorContains(t, err, `invalid address "6060"`)
+}
+
+func TestValidateSlowCalls(t *testing.T) {
+	assert.NoError(t, validateSlowCalls(nil))
+	assert.NoError(t, validateSlowCalls(configv1.SlowCallConfig_builder{
+		Threshold:      durationpb.New(2 * time.Second),
+		ToolThresholds: map[string]*durationpb.Duration{"weather.*": durationpb.New(5 * time.Second)},
+	}.Build()))
+
+	err := validateSlowCalls(configv1.SlowCallConfig_builder{Threshold: durationpb.New(-time.Second)}.Build())
+	assert.EqualError(t, err, "threshold and window must not be negative")
+
+	err = validateSlowCalls(configv1.SlowCallConfig_builder{
+		ToolThresholds: map[string]*durationpb.Duration{"weather.[": durationpb.New(time.Second)},
+	}.Build())
+	assert.ErrorContains(t, err, `invalid tool pattern "weather.["`)
+
+	err = validateSlowCalls(configv1.SlowCallConfig_builder{TopN: proto.Int32(-1)}.Build())
+	assert.EqualError(t, err, "top_n must not be negative")
+}
+
+func TestValidateUpstreamHealth(t *testing.T) {
+	assert.NoError(t, validateUpstreamHealth(nil))
+	assert.NoError(t, validateUpstreamHealth(configv1.UpstreamHealthConfig_builder{
+		Interval:           durationpb.New(10 * time.Second),
+		UnhealthyThreshold: proto.Int32(5),
+	}.Build()))
+
+	err := validateUpstreamHealth(configv1.UpstreamHealthConfig_builder{Interval: durationpb.New(-time.Second)}.Build())
+	assert.EqualError(t, err, "interval must not be negative")
+
+	err = validateUpstreamHealth(configv1.UpstreamHealthConfig_builder{HealthyThreshold: proto.Int32(-1)}.Build())
+	assert.EqualError(t, err, "thresholds must not be negative")
+}
+
+func TestValidateDoctor(t *testing.T) {
+	assert.NoError(t, validateDoctor(nil))
+	assert.NoError(t, validateDoctor(configv1.DoctorConfig_builder{
+		LatencyBudget:  durationpb.New(500 * time.Millisecond),
+		LatencySamples: proto.Int32(10),
+		DnsLookups:     proto.Int32(5),
+	}.Build()))
+
+	err := validateDoctor(configv1.DoctorConfig_builder{LatencyBudget: durationpb.New(-time.Second)}.Build())
+	assert.EqualError(t, err, "latency_budget must not be negative")
+
+	err = validateDoctor(configv1.DoctorConfig_builder{LatencySamples: proto.Int32(1000)}.Build())
+	assert.EqualError(t, err, "latency_samples must be between 0 and 100")
+
+	err = validateDoctor(configv1.DoctorConfig_builder{DnsLookups: proto.Int32(-1)}.Build())
+	assert.EqualError(t, err, "dns_lookups must be between 0 and 20")
+}
+
+func TestValidateResponseCache(t *testing.T) {
+	assert.NoError(t, validateResponseCache(nil))
+	redis := &bus.RedisBus{}
+	redis.SetAddress("localhost:6379")
+	assert.NoError(t, validateResponseCache(configv1.ResponseCacheConfig_builder{
+		MaxEntries: proto.Int32(1000),
+		Redis:      redis,
+	}.Build()))
+
+	err := validateResponseCache(configv1.ResponseCacheConfig_builder{MaxEntries: proto.Int32(-1)}.Build())
+	assert.EqualError(t, err, "max_entries must not be negative")
+
+	err = validateResponseCache(configv1.ResponseCacheConfig_builder{Redis: &bus.RedisBus{}}.Build())
+	assert.EqualError(t, err, "redis address is empty")
+}
+
+func TestValidateQuotas(t *testing.T) {
+	quota := func(name string, keyBy configv1.QuotaConfig_KeyBy, concurrent int32, hourly int64) *configv1.QuotaConfig {
+		return configv1.QuotaConfig_builder{
+			Name:               proto.String(name),
+			KeyBy:              keyBy.Enum(),
+			MaxConcurrentCalls: proto.Int32(concurrent),
+			MaxCallsPerHour:    proto.Int64(hourly),
+		}.Build()
+	}
+	assert.NoError(t, validateQuotas(nil))
+	assert.NoError(t, validateQuotas([]*configv1.QuotaConfig{
+		quota("per-session", configv1.QuotaConfig_KEY_BY_SESSION, 4, 0),
+		quota("per-user", configv1.QuotaConfig_KEY_BY_USER_ID, 0, 1000),
+	}))
+
+	err := validateQuotas([]*configv1.QuotaConfig{quota("", configv1.QuotaConfig_KEY_BY_SESSION, 4, 0)})
+	assert.EqualError(t, err, "quota 0 has an empty name")
+
+	err = validateQuotas([]*configv1.QuotaConfig{
+		quota("q", configv1.QuotaConfig_KEY_BY_SESSION, 4, 0),
+		quota("q", configv1.QuotaConfig_KEY_BY_USER_ID, 4, 0),
+	})
+	assert.EqualError(t, err, `duplicate quota name "q"`)
+
+	err = validateQuotas([]*configv1.QuotaConfig{quota("q", configv1.QuotaConfig_KEY_BY_UNSPECIFIED, 4, 0)})
+	assert.EqualError(t, err, `quota "q": key_by is required`)
+
+	err = validateQuotas([]*configv1.QuotaConfig{quota("q", configv1.QuotaConfig_KEY_BY_API_KEY, -1, 0)})
+	assert.EqualError(t, err, `quota "q": limits must not be negative`)
+
+	err = validateQuotas([]*configv1.QuotaConfig{quota("q", configv1.QuotaConfig_KEY_BY_API_KEY, 0, 0)})
+	assert.ErrorContains(t, err, `quota "q": at least one of`)
+}
+
+func TestValidateStartupDiscovery(t *testing.T) {
+	assert.NoError(t, validateStartupDiscovery(nil))
+	assert.NoError(t, validateStartupDiscovery(configv1.StartupDiscoveryConfig_builder{
+		MaxConcurrency: proto.Int32(16),
+		ServiceTimeout: durationpb.New(30 * time.Second),
+	}.Build()))
+	assert.EqualError(t, validateStartupDiscovery(configv1.StartupDiscoveryConfig_builder{
+		MaxConcurrency: proto.Int32(-1),
+	}.Build()), "max_concurrency must not be negative")
+	assert.EqualError(t, validateStartupDiscovery(configv1.StartupDiscoveryConfig_builder{
+		ServiceTimeout: durationpb.New(-time.Second),
+	}.Build()), "service_timeout must not be negative")
+}
+
+func TestValidateToolCatalogSnapshot(t *testing.T) {
+	assert.NoError(t, validateToolCatalogSnapshot(nil))
+	assert.NoError(t, validateToolCatalogSnapshot(configv1.ToolCatalogSnapshotConfig_builder{
+		Enabled: proto.Bool(true),
+		MaxAge:  durationpb.New(24 * time.Hour),
+	}.Build()))
+	assert.EqualError(t, validateToolCatalogSnapshot(configv1.ToolCatalogSnapshotConfig_builder{
+		MaxAge: durationpb.New(0),
+	}.Build()), "max_age must be positive")
+}
+
+func TestValidateWorkerConfig(t *testing.T) {
+	assert.NoError(t, validateWorkerConfig(nil))
+	assert.NoError(t, validateWorkerConfig(configv1.WorkerConfig_builder{
+		MaxConcurrency: proto.Int32(8),
+		PriorityClasses: []*configv1.WorkerPriorityClass{
+			configv1.WorkerPriorityClass_builder{Name: proto.String("interactive"), Weight: proto.Int32(4)}.Build(),
+			configv1.WorkerPriorityClass_builder{
+				Name:                  proto.String("batch"),
+				MaxQueueSize:          proto.Int32(100),
+				MaxQueueSizePerTenant: proto.Int32(10),
+				RejectionPolicy:       configv1.WorkerPriorityClass_DROP_OLDEST.Enum(),
+			}.Build(),
+		},
+	}.Build()))
+	assert.EqualError(t, validateWorkerConfig(configv1.WorkerConfig_builder{
+		MaxConcurrency: proto.Int32(-1),
+	}.Build()), "max_concurrency must not be negative")
+	assert.EqualError(t, validateWorkerConfig(configv1.WorkerConfig_builder{
+		PriorityClasses: []*configv1.WorkerPriorityClass{configv1.WorkerPriorityClass_builder{}.Build()},
+	}.Build()), "priority class 0 has an empty name")
+	assert.EqualError(t, validateWorkerConfig(configv1.WorkerConfig_builder{
+		PriorityClasses: []*configv1.WorkerPriorityClass{
+			configv1.WorkerPriorityClass_builder{Name: proto.String("batch")}.Build(),
+			configv1.WorkerPriorityClass_builder{Name: proto.String("batch")}.Build(),
+		},
+	}.Build()), `duplicate priority class name "batch"`)
+	assert.EqualError(t, validateWorkerConfig(configv1.WorkerConfig_builder{
+		PriorityClasses: []*configv1.WorkerPriorityClass{
+			configv1.WorkerPriorityClass_builder{Name: proto.String("batch"), Weight: proto.Int32(-1)}.Build(),
+		},
+	}.Build()), `priority class "batch": weight and queue sizes must not be negative`)
+}
+
+func TestValidateWebhookConfig(t *testing.T) {
+	assert.NoError(t, validateWebhookConfig(context.Background(), configv1.WebhookConfig_builder{
+		Url:           "https://hooks.example.com/pre",
+		Timeout:       durationpb.New(2 * time.Second),
+		MaxRetries:    3,
+		RetryBackoff:  durationpb.New(200 * time.Millisecond),
+		FailurePolicy: configv1.WebhookConfig_FAIL_OPEN,
+	}.Build()))
+	assert.EqualError(t, validateWebhookConfig(context.Background(), configv1.WebhookConfig_builder{MaxRetries: -1}.Build()),
+		"webhook max_retries must not be negative")
+	assert.EqualError(t, validateWebhookConfig(context.Background(), configv1.WebhookConfig_builder{RetryBackoff: durationpb.New(-time.Second)}.Build()),
+		"webhook retry_backoff must not be negative")
+	assert.EqualError(t, validateWebhookConfig(context.Background(), configv1.WebhookConfig_builder{Timeout: durationpb.New(-time.Second)}.Build()),
+		"webhook timeout must not be negative")
+	assert.NoError(t, validateWebhookConfig(context.Background(), configv1.WebhookConfig_builder{
+		Match: configv1.WebhookMatchConfig_builder{Tools: []string{"github.*"}, Condition: `arguments.branch == "main"`}.Build(),
+	}.Build()))
+	assert.ErrorContains(t, validateWebhookConfig(context.Background(), configv1.WebhookConfig_builder{
+		Match: configv1.WebhookMatchConfig_builder{Tools: []string{"github.["}}.Build(),
+	}.Build()), `webhook match: invalid tool glob "github.["`)
+	assert.ErrorContains(t, validateWebhookConfig(context.Background(), configv1.WebhookConfig_builder{
+		Match: configv1.WebhookMatchConfig_builder{Condition: "arguments.branch =="}.Build(),
+	}.Build()), "webhook match: invalid condition")
+	assert.ErrorContains(t, validateWebhookConfig(context.Background(), configv1.WebhookConfig_builder{
+		Match: configv1.WebhookMatchConfig_builder{Condition: "tool + service"}.Build(),
+	}.Build()), "not bool")
+}
+
+func TestValidateWebhookConfig_Signing(t *testing.T) {
+	const secret = "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+	assert.NoError(t, validateWebhookConfig(context.Background(), configv1.WebhookConfig_builder{
+		WebhookSecret:   secret,
+		PreviousSecrets: []string{"dGVzdC1zZWNyZXQtdmFsaWQtYmFzZTY0"},
+	}.Build()))
+	assert.ErrorContains(t, validateWebhookConfig(context.Background(), configv1.WebhookConfig_builder{
+		WebhookSecret: "not base64!",
+	}.Build()), "webhook webhook_secret is not a Standard Webhooks secret")
+	assert.EqualError(t, validateWebhookConfig(context.Background(), configv1.WebhookConfig_builder{
+		PreviousSecrets: []string{secret},
+	}.Build()), "webhook previous_secrets require a webhook_secret")
+	assert.ErrorContains(t, validateWebhookConfig(context.Background(), configv1.WebhookConfig_builder{
+		WebhookSecret:   secret,
+		PreviousSecrets: []string{"not base64!"},
+	}.Build()), "webhook previous_secrets[0] is not a Standard Webhooks secret")
+
+	ctx := context.WithValue(context.Background(), SkipFilesystemCheckKey, true)
+	assert.NoError(t, validateWebhookConfig(ctx, configv1.WebhookConfig_builder{
+		Mtls: configv1.MTLSAuth_builder{ClientCertPath: proto.String("certs/client.crt"), ClientKeyPath: proto.String("certs/client.key")}.Build(),
+	}.Build()))
+	assert.EqualError(t, validateWebhookConfig(ctx, configv1.WebhookConfig_builder{
+		Mtls: configv1.MTLSAuth_builder{ClientCertPath: proto.String("certs/client.crt")}.Build(),
+	}.Build()), "webhook mtls 'client_key_path' is empty")
+}
+
+func TestValidateWebhookConfig_Protocol(t *testing.T) {
+	assert.NoError(t, validateWebhookConfig(context.Background(), configv1.WebhookConfig_builder{
+		Url:      "review.internal:9000",
+		Protocol: configv1.WebhookConfig_GRPC,
+	}.Build()))
+	assert.NoError(t, validateWebhookConfig(context.Background(), configv1.WebhookConfig_builder{
+		Url:      "https://review.internal/hook",
+		Protocol: configv1.WebhookConfig_HTTP,
+	}.Build()))
+	assert.EqualError(t, validateWebhookConfig(context.Background(), configv1.WebhookConfig_builder{
+		Protocol: configv1.WebhookConfig_GRPC,
+	}.Build()), "webhook url is empty")
+	assert.EqualError(t, validateWebhookConfig(context.Background(), configv1.WebhookConfig_builder{
+		Url:      "https://review.internal",
+		Protocol: configv1.WebhookConfig_GRPC,
+	}.Build()), `webhook url "https://review.internal" of a grpc webhook must be a gRPC target such as host:port`)
+	assert.NoError(t, validateWebhookConfig(context.Background(), configv1.WebhookConfig_builder{
+		Url:       "localhost:9000",
+		Protocol:  configv1.WebhookConfig_GRPC,
+		Plaintext: true,
+	}.Build()))
+	assert.EqualError(t, validateWebhookConfig(context.Background(), configv1.WebhookConfig_builder{
+		Url:       "http://review.internal/hook",
+		Plaintext: true,
+	}.Build()), "webhook plaintext only applies to grpc webhooks, use an http:// url")
+	assert.EqualError(t, validateWebhookConfig(context.WithValue(context.Background(), SkipFilesystemCheckKey, true), configv1.WebhookConfig_builder{
+		Url:       "review.internal:9000",
+		Protocol:  configv1.WebhookConfig_GRPC,
+		Plaintext: true,
+		Mtls:      configv1.MTLSAuth_builder{ClientCertPath: proto.String("certs/client.crt"), ClientKeyPath: proto.String("certs/client.key")}.Build(),
+	}.Build()), "webhook plaintext cannot be combined with mtls")
+	assert.EqualError(t, validateWebhookConfig(context.Background(), configv1.WebhookConfig_builder{
+		Protocol: configv1.WebhookConfig_Protocol(7),
+	}.Build()), "webhook protocol 7 is not supported")
+}
+
+func TestValidateSharedState(t *testing.T) {
+	assert.NoError(t, validateSharedState(nil))
+	redis := &bus.RedisBus{}
+	redis.SetAddress("localhost:6379")
+	assert.NoError(t, validateSharedState(configv1.SharedStateConfig_builder{
+		Redis:            redis,
+		AdvertiseAddress: proto.String("http://10.0.0.5:50050"),
+		SessionTtl:       durationpb.New(time.Hour),
+	}.Build()))
+
+	err := validateSharedState(configv1.SharedStateConfig_builder{}.Build())
+	assert.EqualError(t, err, "redis address is empty")
+
+	err = validateSharedState(configv1.SharedStateConfig_builder{
+		Redis:            redis,
+		AdvertiseAddress: proto.String("10.0.0.5:50050"),
+	}.Build())
+	assert.EqualError(t, err, `advertise_address "10.0.0.5:50050" must be an http or https URL`)
+
+	err = validateSharedState(configv1.SharedStateConfig_builder{
+		Redis:      redis,
+		SessionTtl: durationpb.New(-time.Second),
+	}.Build())
+	assert.EqualError(t, err, "session_ttl must not be negative")
+
+	err = validateSharedState(configv1.SharedStateConfig_builder{
+		Redis:             redis,
+		HeartbeatInterval: durationpb.New(-time.Second),
+	}.Build())
+	assert.EqualError(t, err, "heartbeat_interval must not be negative")
+}
+
+func TestValidateUpstreamService_CacheKeyFields(t *testing.T) {
+	service := func(fields ...string) *configv1.UpstreamServiceConfig {
+		return configv1.UpstreamServiceConfig_builder{
+			Name: proto.String("weather"),
+			HttpService: configv1.HttpUpstreamService_builder{
+				Address: proto.String("https://api.weather.example.com"),
+			}.Build(),
+			Cache: configv1.CacheConfig_builder{
+				IsEnabled: proto.Bool(true),
+				KeyFields: fields,
+			}.Build(),
+		}.Build()
+	}
+	assert.NoError(t, ValidateOrError(context.Background(), service("city", "location.lat")))
+
+	err := ValidateOrError(context.Background(), service("location..lat"))
+	assert.ErrorContains(t, err, `invalid cache key field "location..lat"`)
+}
+
+func TestValidateNotifications(t *testing.T) {
+	ctx := context.Background()
+	sink := func(format configv1.NotificationSinkConfig_Format, url string, secret *configv1.SecretValue, events ...string) *configv1.NotificationConfig {
+		return configv1.NotificationConfig_builder{
+			Sinks: []*configv1.NotificationSinkConfig{configv1.NotificationSinkConfig_builder{
+				Format: format.Enum(),
+				Url:    proto.String(url),
+				Secret: secret,
+				Events: events,
+			}.Build()},
+		}.Build()
+	}
+	secret := configv1.SecretValue_builder{PlainText: proto.String("whsec_c2VjcmV0")}.Build()
+
+	assert.NoError(t, validateNotifications(ctx, nil))
+	assert.NoError(t, validateNotifications(ctx, sink(configv1.NotificationSinkConfig_FORMAT_SLACK, "https://hooks.slack.com/services/T/B/X", nil, "upstream.*")))
+	assert.NoError(t, validateNotifications(ctx, sink(configv1.NotificationSinkConfig_FORMAT_STANDARD_WEBHOOKS, "https://example.com/hooks", secret)))
+
+	err := validateNotifications(ctx, sink(configv1.NotificationSinkConfig_FORMAT_CLOUDEVENTS, "ftp://example.com", nil))
+	assert.EqualError(t, err, "sink 0: invalid url scheme: ftp")
+
+	err = validateNotifications(ctx, sink(configv1.NotificationSinkConfig_FORMAT_STANDARD_WEBHOOKS, "https://example.com/hooks", nil))
+	assert.EqualError(t, err, "sink 0: the standard webhooks format requires a secret")
+
+	err = validateNotifications(ctx, sink(configv1.NotificationSinkConfig_FORMAT_CLOUDEVENTS, "https://example.com/hooks", nil, "upstream.["))
+	assert.ErrorContains(t, err, `sink 0: invalid event pattern "upstream.["`)
+
+	err = validateNotifications(ctx, configv1.NotificationConfig_builder{Cooldown: durationpb.New(-time.Minute)}.Build())
+	assert.EqualError(t, err, "cooldown and auth_failure_window must not be negative")
+}
+
+func TestValidateOutboundWebhooks(t *testing.T) {
+	ctx := context.Background()
+	assert.NoError(t, validateOutboundWebhooks(ctx, nil))
+	assert.NoError(t, validateOutboundWebhooks(ctx, configv1.OutboundWebhookConfig_builder{
+		WebhookSecret:   proto.String("whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"),
+		PreviousSecrets: []string{"whsec_dGVzdC1zZWNyZXQtdmFsaWQtYmFzZTY0"},
+	}.Build()))
+
+	err := validateOutboundWebhooks(ctx, configv1.OutboundWebhookConfig_builder{
+		WebhookSecret: proto.String("whsec_!!"),
+	}.Build())
+	assert.ErrorContains(t, err, "webhook_secret is not a Standard Webhooks secret")
+
+	err = validateOutboundWebhooks(ctx, configv1.OutboundWebhookConfig_builder{
+		PreviousSecrets: []string{"whsec_dGVzdC1zZWNyZXQtdmFsaWQtYmFzZTY0"},
+	}.Build())
+	assert.EqualError(t, err, "previous_secrets require a webhook_secret")
+}
+
+func TestValidateMiddlewares(t *testing.T) {
+	assert.NoError(t, validateMiddlewares([]*configv1.Middleware{
+		configv1.Middleware_builder{Name: proto.String("logging")}.Build(),
+		configv1.Middleware_builder{
+			Name:   proto.String("guard"),
+			Plugin: configv1.MiddlewarePlugin_builder{Command: proto.String("/usr/local/bin/guard")}.Build(),
+		}.Build(),
+	}))
+	assert.EqualError(t, validateMiddlewares([]*configv1.Middleware{
+		configv1.Middleware_builder{Plugin: configv1.MiddlewarePlugin_builder{Command: proto.String("guard")}.Build()}.Build(),
+	}), "middleware 0: a plugin middleware must have a name")
+	assert.EqualError(t, validateMiddlewares([]*configv1.Middleware{
+		configv1.Middleware_builder{Name: proto.String("guard"), Plugin: configv1.MiddlewarePlugin_builder{}.Build()}.Build(),
+	}), `middleware "guard": plugin must set a command`)
+	assert.EqualError(t, validateMiddlewares([]*configv1.Middleware{
+		configv1.Middleware_builder{
+			Name:   proto.String("redact"),
+			Plugin: configv1.MiddlewarePlugin_builder{Wasm: proto.String("plugins/redact.wasm")}.Build(),
+		}.Build(),
+	}), `middleware "redact": wasm plugins are not supported, use a command`)
+}
+
+func TestValidateLabelEnrichers(t *testing.T) {
+	assert.NoError(t, validateLabelEnrichers([]*configv1.LabelEnricher{
+		configv1.LabelEnricher_builder{Name: proto.String("jwt_claims")}.Build(),
+		configv1.LabelEnricher_builder{
+			Name:   proto.String("cost_center"),
+			Plugin: configv1.MiddlewarePlugin_builder{Command: proto.String("/usr/local/bin/cost-center")}.Build(),
+		}.Build(),
+	}))
+	assert.EqualError(t, validateLabelEnrichers([]*configv1.LabelEnricher{
+		configv1.LabelEnricher_builder{Plugin: configv1.MiddlewarePlugin_builder{Command: proto.String("cost-center")}.Build()}.Build(),
+	}), "label enricher 0: a plugin enricher must have a name")
+	assert.EqualError(t, validateLabelEnrichers([]*configv1.LabelEnricher{
+		configv1.LabelEnricher_builder{Name: proto.String("cost_center"), Plugin: configv1.MiddlewarePlugin_builder{}.Build()}.Build(),
+	}), `label enricher "cost_center": plugin must set a command`)
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 func strPtr(s string) *string {
@@ -163,6 +164,44 @@ func TestValidateSecretValue(t *testing.T) {
 			expectErr: true,
 			errMsg:    "secret value does not match validation regex",
 		},
+		{
+			name: "Valid secret reference",
+			secret: configv1.SecretValue_builder{
+				SecretRef: proto.String("vault:secret/data/app#password"),
+			}.Build(),
+			expectErr: false,
+		},
+		{
+			name: "Secret reference without key",
+			secret: configv1.SecretValue_builder{
+				SecretRef: proto.String("vault:secret/data/app"),
+			}.Build(),
+			expectErr: true,
+			errMsg:    "expected vault:path#key",
+		},
+		{
+			name: "Secret reference with unknown scheme",
+			secret: configv1.SecretValue_builder{
+				SecretRef: proto.String("consul:app/db#password"),
+			}.Build(),
+			expectErr: true,
+			errMsg:    "unsupported secret reference scheme",
+		},
+		{
+			name: "Valid cloud secret reference without key",
+			secret: configv1.SecretValue_builder{
+				SecretRef: proto.String("aws-sm:prod/app/api-key"),
+			}.Build(),
+			expectErr: false,
+		},
+		{
+			name: "Azure secret reference without vault",
+			secret: configv1.SecretValue_builder{
+				SecretRef: proto.String("azure-kv:api-key#token"),
+			}.Build(),
+			expectErr: true,
+			errMsg:    "must be azure-kv:<vault>/<secret>[/<version>]",
+		},
 		{
 			name: "Invalid regex pattern",
 			secret: configv1.SecretValue_builder{
@@ -1327,6 +1366,191 @@ func TestValidateGlobalSettings_Extended(t *testing.T) {
 			expectErr:    true,
 			errSubstring: "must be absolute",
 		},
+		{
+			name: "OAuth Resource Server Missing Issuer",
+			gs: configv1.GlobalSettings_builder{
+				OauthResourceServer: configv1.OAuthResourceServerConfig_builder{
+					Resource: proto.String("https://mcp.example.com"),
+				}.Build(),
+			}.Build(),
+			expectErr:    true,
+			errSubstring: "issuer is required",
+		},
+		{
+			name: "OAuth Resource Server Invalid JWKS URL",
+			gs: configv1.GlobalSettings_builder{
+				OauthResourceServer: configv1.OAuthResourceServerConfig_builder{
+					Issuer:  proto.String("https://auth.example.com"),
+					JwksUrl: proto.String("file:///etc/jwks.json"),
+				}.Build(),
+			}.Build(),
+			expectErr:    true,
+			errSubstring: "invalid jwks_url",
+		},
+		{
+			name: "OAuth Resource Server Incomplete Scope Mapping",
+			gs: configv1.GlobalSettings_builder{
+				OauthResourceServer: configv1.OAuthResourceServerConfig_builder{
+					Issuer: proto.String("https://auth.example.com"),
+					ScopeProfiles: []*configv1.OAuthScopeProfile{
+						configv1.OAuthScopeProfile_builder{Scope: proto.String("mcp:read")}.Build(),
+					},
+				}.Build(),
+			}.Build(),
+			expectErr:    true,
+			errSubstring: "require both scope and profile",
+		},
+		{
+			name: "OAuth Resource Server Without Resource Or Audiences",
+			gs: configv1.GlobalSettings_builder{
+				OauthResourceServer: configv1.OAuthResourceServerConfig_builder{
+					Issuer: proto.String("https://auth.example.com"),
+				}.Build(),
+			}.Build(),
+			expectErr:    true,
+			errSubstring: "resource or audiences is required",
+		},
+		{
+			name: "JWT Auth Without Issuers",
+			gs: configv1.GlobalSettings_builder{
+				JwtAuth: configv1.JWTAuthConfig_builder{}.Build(),
+			}.Build(),
+			expectErr:    true,
+			errSubstring: "at least one issuer is required",
+		},
+		{
+			name: "JWT Auth Missing Audience",
+			gs: configv1.GlobalSettings_builder{
+				JwtAuth: configv1.JWTAuthConfig_builder{
+					Issuers: []*configv1.JWTIssuerConfig{
+						configv1.JWTIssuerConfig_builder{Issuer: proto.String("https://idp.example.com")}.Build(),
+					},
+				}.Build(),
+			}.Build(),
+			expectErr:    true,
+			errSubstring: "requires at least one audience",
+		},
+		{
+			name: "JWT Auth Incomplete Role Mapping",
+			gs: configv1.GlobalSettings_builder{
+				JwtAuth: configv1.JWTAuthConfig_builder{
+					Issuers: []*configv1.JWTIssuerConfig{
+						configv1.JWTIssuerConfig_builder{
+							Issuer:    proto.String("https://idp.example.com"),
+							Audiences: []string{"mcpany"},
+							RoleMappings: []*configv1.JWTRoleMapping{
+								configv1.JWTRoleMapping_builder{Value: proto.String("admins")}.Build(),
+							},
+						}.Build(),
+					},
+				}.Build(),
+			}.Build(),
+			expectErr:    true,
+			errSubstring: "require both value and role",
+		},
+		{
+			name: "JWT Auth Downgrade Without Profile",
+			gs: configv1.GlobalSettings_builder{
+				JwtAuth: configv1.JWTAuthConfig_builder{
+					Issuers: []*configv1.JWTIssuerConfig{
+						configv1.JWTIssuerConfig_builder{
+							Issuer:    proto.String("https://idp.example.com"),
+							Audiences: []string{"mcpany"},
+						}.Build(),
+					},
+					OnFailure:      configv1.JWTAuthConfig_FAILURE_ACTION_DOWNGRADE.Enum(),
+					DowngradeRoles: []string{"guest"},
+				}.Build(),
+			}.Build(),
+			expectErr:    true,
+			errSubstring: "requires a downgrade_profile",
+		},
+		{
+			name: "Vault Without Auth Method",
+			gs: configv1.GlobalSettings_builder{
+				Vault: configv1.VaultConfig_builder{Address: proto.String("https://vault.example.com")}.Build(),
+			}.Build(),
+			expectErr:    true,
+			errSubstring: "one of token, app_role or kubernetes is required",
+		},
+		{
+			name: "OpenAI Bridge Invalid Upstream URL",
+			gs: configv1.GlobalSettings_builder{
+				OpenaiBridge: configv1.OpenAIBridgeConfig_builder{
+					UpstreamUrl: proto.String("api.openai.com/v1"),
+				}.Build(),
+			}.Build(),
+			expectErr:    true,
+			errSubstring: "invalid upstream_url",
+		},
+		{
+			name: "OpenAI Bridge API Key Without Upstream",
+			gs: configv1.GlobalSettings_builder{
+				OpenaiBridge: configv1.OpenAIBridgeConfig_builder{
+					UpstreamApiKey: configv1.SecretValue_builder{PlainText: proto.String("sk-test")}.Build(),
+				}.Build(),
+			}.Build(),
+			expectErr:    true,
+			errSubstring: "upstream_api_key requires an upstream_url",
+		},
+		{
+			name: "OpenAI Bridge Valid",
+			gs: configv1.GlobalSettings_builder{
+				OpenaiBridge: configv1.OpenAIBridgeConfig_builder{
+					UpstreamUrl:    proto.String("https://api.openai.com/v1"),
+					UpstreamApiKey: configv1.SecretValue_builder{PlainText: proto.String("sk-test")}.Build(),
+				}.Build(),
+			}.Build(),
+			expectErr: false,
+		},
+		{
+			name: "Vault Invalid Address",
+			gs: configv1.GlobalSettings_builder{
+				Vault: configv1.VaultConfig_builder{
+					Address:    proto.String("vault.example.com"),
+					Kubernetes: configv1.VaultKubernetesAuth_builder{Role: proto.String("mcpany")}.Build(),
+				}.Build(),
+			}.Build(),
+			expectErr:    true,
+			errSubstring: "invalid address",
+		},
+		{
+			name: "Vault AppRole Missing Role ID",
+			gs: configv1.GlobalSettings_builder{
+				Vault: configv1.VaultConfig_builder{
+					Address: proto.String("https://vault.example.com"),
+					AppRole: configv1.VaultAppRoleAuth_builder{
+						SecretId: configv1.SecretValue_builder{PlainText: proto.String("secret")}.Build(),
+					}.Build(),
+				}.Build(),
+			}.Build(),
+			expectErr:    true,
+			errSubstring: "app_role requires role_id",
+		},
+		{
+			name: "Secret Backends Negative Refresh Interval",
+			gs: configv1.GlobalSettings_builder{
+				SecretBackends: configv1.SecretBackendsConfig_builder{
+					AwsSecretsManager: configv1.AwsSecretsManagerBackend_builder{}.Build(),
+					RefreshInterval:   durationpb.New(-time.Minute),
+				}.Build(),
+			}.Build(),
+			expectErr:    true,
+			errSubstring: "refresh_interval must not be negative",
+		},
+		{
+			name: "Secret Backends Azure Client Secret Without Tenant",
+			gs: configv1.GlobalSettings_builder{
+				SecretBackends: configv1.SecretBackendsConfig_builder{
+					AzureKeyVault: configv1.AzureKeyVaultBackend_builder{
+						ClientId:     proto.String("app"),
+						ClientSecret: configv1.SecretValue_builder{PlainText: proto.String("secret")}.Build(),
+					}.Build(),
+				}.Build(),
+			}.Build(),
+			expectErr:    true,
+			errSubstring: "requires tenant_id and client_id",
+		},
 		{
 			name: "Duplicate Profile Name",
 			gs: configv1.GlobalSettings_builder{
@@ -4,8 +4,10 @@
 package config
 
 import (
+	"context"
 	"log"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -25,11 +27,18 @@ import (
 //   - done (chan bool): Channel to signal shutdown.
 //   - mu (sync.Mutex): Mutex to protect concurrent access.
 //   - timer (*time.Timer): Timer for debouncing reload events.
+//   - pollInterval (time.Duration): How often http(s) configs are polled.
+//   - files (map[string][]string): The watched files, by directory.
 type Watcher struct {
-	watcher *fsnotify.Watcher
-	done    chan bool
-	mu      sync.Mutex
-	timer   *time.Timer
+	watcher      *fsnotify.Watcher
+	done         chan bool
+	mu           sync.Mutex
+	timer        *time.Timer
+	pollInterval time.Duration
+	// files maps the watched directories to the names of the watched files
+	// in them.
+	files   map[string][]string
+	filesMu sync.Mutex
 }
 
 // NewWatcher creates a new file watcher.
@@ -53,11 +62,26 @@ func NewWatcher() (*Watcher, error) {
 	}
 
 	return &Watcher{
-		watcher: watcher,
-		done:    make(chan bool),
+		watcher:      watcher,
+		done:         make(chan bool),
+		pollInterval: DefaultConfigPollInterval,
 	}, nil
 }
 
+// SetPollInterval sets how often the configs loaded from http(s) URLs are
+// checked for changes.
+//
+// Summary: Sets the polling interval of remote configs.
+//
+// Parameters:
+//   - interval (time.Duration): The interval. Zero disables polling.
+//
+// Side Effects:
+//   - Applies to the next call to Watch.
+func (w *Watcher) SetPollInterval(interval time.Duration) {
+	w.pollInterval = interval
+}
+
 // Watch starts monitoring the specified configuration paths.
 //
 // Summary: Starts watching the specified paths for changes.
@@ -74,29 +98,29 @@ func NewWatcher() (*Watcher, error) {
 //
 // Side Effects:
 //   - Starts a goroutine to process file events.
-//   - Registers directories with the OS watcher.
+//   - Starts a goroutine per etcd, Consul or Kubernetes source to watch it,
+//     and per http(s) URL to poll it.
+//   - Registers directories with the OS watcher, including the ones of the
+//     files included by the configs loaded while watching.
 func (w *Watcher) Watch(paths []string, reloadFunc func()) error {
-	// Map of parent directory -> list of filenames to watch in that directory
-	watchedFiles := make(map[string][]string)
+	remoteCtx, cancelRemote := context.WithCancel(context.Background())
+	defer cancelRemote()
 
+	// The overlays of the environment are watched even if they do not exist
+	// yet, so that creating one applies it.
+	paths = append(append([]string{}, paths...), overlayFiles(paths, currentEnvironment())...)
 	for _, path := range paths {
-		if isURL(path) {
+		if isKVURL(path) {
+			go watchKV(remoteCtx, path, func() { w.scheduleReload(reloadFunc) })
 			continue
 		}
-		absPath, err := filepath.Abs(path)
-		if err != nil {
-			log.Printf("Failed to get absolute path for %s: %v", path, err)
+		if isURL(path) {
+			if w.pollInterval > 0 {
+				go watchURL(remoteCtx, path, w.pollInterval, func() { w.scheduleReload(reloadFunc) })
+			}
 			continue
 		}
-
-		// Since we want to handle atomic saves (rename), we MUST watch the parent directory of files.
-		parent := filepath.Dir(absPath)
-		filename := filepath.Base(absPath)
-
-		if _, exists := watchedFiles[parent]; !exists {
-			watchedFiles[parent] = []string{}
-		}
-		watchedFiles[parent] = append(watchedFiles[parent], filename)
+		w.addFile(path)
 	}
 
 	go func() {
@@ -107,42 +131,17 @@ func (w *Watcher) Watch(paths []string, reloadFunc func()) error {
 					return
 				}
 
-				// Check if this event is relevant
-				relevant := false
-
-				// Logic:
-				// 1. If we are watching the directory of this event
-				// 2. And the event name matches one of the files we are interested in.
-
-				parent := filepath.Dir(event.Name)
-				filename := filepath.Base(event.Name)
-
-				if files, ok := watchedFiles[parent]; ok {
-					for _, f := range files {
-						if f == filename {
-							relevant = true
-							break
-						}
-					}
-				}
-
-				// Also check absolute path matching
+				// Check if this event is relevant: the event name matches one
+				// of the files we are interested in, in a watched directory.
+				relevant := w.isWatched(event.Name)
 				if !relevant {
+					// Also check absolute path matching
 					absName, _ := filepath.Abs(event.Name)
-					parent = filepath.Dir(absName)
-					filename = filepath.Base(absName)
-					if files, ok := watchedFiles[parent]; ok {
-						for _, f := range files {
-							if f == filename {
-								relevant = true
-								break
-							}
-						}
-					}
+					relevant = w.isWatched(absName)
 				}
 
 				// Handle Vim backup files (ends with ~)
-				if strings.HasSuffix(filename, "~") {
+				if strings.HasSuffix(event.Name, "~") {
 					relevant = false
 				}
 
@@ -153,16 +152,7 @@ func (w *Watcher) Watch(paths []string, reloadFunc func()) error {
 					// Or Rename (target->backup).
 					// If we see any change to the target filename, we reload.
 					if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Chmod) != 0 {
-						w.mu.Lock()
-						if w.timer != nil {
-							w.timer.Stop()
-						}
-						// Debounce for 500ms to avoid multiple reloads for a single save event
-						w.timer = time.AfterFunc(500*time.Millisecond, func() {
-							log.Println("Reloading configuration...")
-							reloadFunc()
-						})
-						w.mu.Unlock()
+						w.scheduleReload(reloadFunc)
 					}
 				}
 
@@ -177,16 +167,79 @@ func (w *Watcher) Watch(paths []string, reloadFunc func()) error {
 		}
 	}()
 
-	for parent := range watchedFiles {
+	w.filesMu.Lock()
+	parents := make([]string, 0, len(w.files))
+	for parent := range w.files {
+		parents = append(parents, parent)
+	}
+	w.filesMu.Unlock()
+	for _, parent := range parents {
 		if err := w.watcher.Add(parent); err != nil {
 			return err
 		}
 	}
 
+	// The files included by the configs are watched as they are loaded.
+	unsubscribe := subscribeIncludes(func(path string) {
+		if w.addFile(path) {
+			if err := w.watcher.Add(filepath.Dir(path)); err != nil {
+				log.Printf("Failed to watch included config %s: %v", path, err)
+			}
+		}
+	})
+	defer unsubscribe()
+
 	<-w.done
 	return nil
 }
 
+// addFile adds a file to the watched files, and reports whether its parent
+// directory was not watched yet.
+func (w *Watcher) addFile(path string) bool {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		log.Printf("Failed to get absolute path for %s: %v", path, err)
+		return false
+	}
+
+	// Since we want to handle atomic saves (rename), we MUST watch the parent directory of files.
+	parent := filepath.Dir(absPath)
+	filename := filepath.Base(absPath)
+
+	w.filesMu.Lock()
+	defer w.filesMu.Unlock()
+	if w.files == nil {
+		w.files = make(map[string][]string)
+	}
+	files, exists := w.files[parent]
+	if slices.Contains(files, filename) {
+		return false
+	}
+	w.files[parent] = append(files, filename)
+	return !exists
+}
+
+// isWatched reports whether a file is one of the watched files.
+func (w *Watcher) isWatched(path string) bool {
+	w.filesMu.Lock()
+	defer w.filesMu.Unlock()
+	return slices.Contains(w.files[filepath.Dir(path)], filepath.Base(path))
+}
+
+// scheduleReload calls reloadFunc after 500ms, unless another change comes
+// first, to avoid multiple reloads for a single save or a batch of key writes.
+func (w *Watcher) scheduleReload(reloadFunc func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.timer = time.AfterFunc(500*time.Millisecond, func() {
+		log.Println("Reloading configuration...")
+		reloadFunc()
+	})
+}
+
 // Close stops the file watcher and releases resources.
 //
 // Parameters:
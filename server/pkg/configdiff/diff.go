@@ -0,0 +1,421 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package configdiff compares two configurations semantically: services,
+// tools and users are matched by name rather than by position, and the
+// changes that weaken the security of the server are flagged.
+package configdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/mcpany/core/server/pkg/util"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Kind is how a field or a tool changed.
+type Kind string
+
+const (
+	// KindAdded means the field or tool is new.
+	KindAdded Kind = "added"
+	// KindRemoved means the field or tool is gone.
+	KindRemoved Kind = "removed"
+	// KindChanged means the value of the field or the definition of the tool
+	// changed.
+	KindChanged Kind = "changed"
+)
+
+// redacted replaces the values of sensitive fields.
+const redacted = "[REDACTED]"
+
+// maxValueLength is the length past which values are truncated.
+const maxValueLength = 120
+
+// Change is a change of a configuration field.
+type Change struct {
+	// Path locates the field, e.g. upstream_services[weather].http_service.address.
+	// Elements of lists are identified by their name or ID.
+	Path string
+	// Kind is how the field changed.
+	Kind Kind
+	// Before and After are the JSON values of the field, empty when it is
+	// absent. The values of sensitive fields are redacted.
+	Before, After string
+	// Auth reports whether the change is to authentication, API keys or
+	// users.
+	Auth bool
+	// Risk explains why the change weakens the security of the server. It is
+	// empty for other changes.
+	Risk string
+}
+
+// ToolChange is a configured tool that is added, removed or changed.
+type ToolChange struct {
+	// Name is the fully qualified name of the tool, e.g. weather.get_forecast.
+	Name string
+	// Kind is how the tool changed.
+	Kind Kind
+	// Fields lists the changed fields of the definition of a changed tool.
+	Fields []string
+}
+
+// Report is the difference between two configurations.
+type Report struct {
+	// Tools holds the configured tools that are added, removed or changed,
+	// sorted by name. Tools of disabled services and disabled tools count as
+	// absent.
+	Tools []ToolChange
+	// Changes holds the changed fields, in the order of the configurations.
+	Changes []Change
+}
+
+// Empty reports whether the configurations are the same.
+//
+// Returns:
+//   - bool: True if there is no change.
+func (r *Report) Empty() bool {
+	return len(r.Tools) == 0 && len(r.Changes) == 0
+}
+
+// AuthChanges returns the changes to authentication, API keys and users.
+//
+// Returns:
+//   - []Change: The changes with Auth set.
+func (r *Report) AuthChanges() []Change {
+	var changes []Change
+	for _, c := range r.Changes {
+		if c.Auth {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// Risky returns the changes that weaken the security of the server.
+//
+// Returns:
+//   - []Change: The changes with a Risk.
+func (r *Report) Risky() []Change {
+	var changes []Change
+	for _, c := range r.Changes {
+		if c.Risk != "" {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// Diff compares the current configuration with a proposed one.
+//
+// Summary: Compares two configurations semantically.
+//
+// Parameters:
+//   - current: *configv1.McpAnyServerConfig. The running configuration. It may be nil.
+//   - proposed: *configv1.McpAnyServerConfig. The configuration to compare with it. It may be nil.
+//
+// Returns:
+//   - *Report: The tools and fields that differ.
+//   - error: An error if a configuration cannot be marshaled.
+func Diff(current, proposed *configv1.McpAnyServerConfig) (*Report, error) {
+	before, err := marshal(current)
+	if err != nil {
+		return nil, err
+	}
+	after, err := marshal(proposed)
+	if err != nil {
+		return nil, err
+	}
+	return DiffJSON(before, after)
+}
+
+// DiffJSON compares the JSON encodings of two configurations, with the
+// field names of the proto files, as /debug/config serves them.
+//
+// Summary: Compares two JSON-encoded configurations semantically.
+//
+// Parameters:
+//   - current: []byte. The running configuration. It may be empty.
+//   - proposed: []byte. The configuration to compare with it. It may be empty.
+//
+// Returns:
+//   - *Report: The tools and fields that differ.
+//   - error: An error if a configuration is not a JSON object.
+func DiffJSON(current, proposed []byte) (*Report, error) {
+	before, err := decode(current)
+	if err != nil {
+		return nil, fmt.Errorf("invalid current configuration: %w", err)
+	}
+	after, err := decode(proposed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proposed configuration: %w", err)
+	}
+	d := &differ{}
+	d.diff("", nil, before, after)
+	return &Report{Tools: diffTools(before, after), Changes: d.changes}, nil
+}
+
+// RedactedJSON returns a configuration the way /debug/config serves it:
+// plain-text secrets removed and the values of sensitive keys redacted. A
+// proposed configuration must be redacted this way to be compared with the
+// one of a running server.
+//
+// Summary: Encodes a configuration with its secrets redacted.
+//
+// Parameters:
+//   - cfg: *configv1.McpAnyServerConfig. The configuration. It is not modified.
+//
+// Returns:
+//   - []byte: The redacted JSON.
+//   - error: An error if the configuration cannot be marshaled.
+func RedactedJSON(cfg *configv1.McpAnyServerConfig) ([]byte, error) {
+	if cfg != nil {
+		cfg = proto.Clone(cfg).(*configv1.McpAnyServerConfig)
+		util.StripSecretsFromConfig(cfg)
+	}
+	b, err := marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return util.RedactJSON(b), nil
+}
+
+func marshal(cfg *configv1.McpAnyServerConfig) ([]byte, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+	return b, nil
+}
+
+func decode(b []byte) (map[string]any, error) {
+	m := map[string]any{}
+	if len(strings.TrimSpace(string(b))) == 0 {
+		return m, nil
+	}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// differ walks two JSON trees and records the fields that differ.
+type differ struct {
+	changes []Change
+}
+
+// diff compares two values at a path. fields holds the field names of the
+// path, without the keys of list elements.
+func (d *differ) diff(path string, fields []string, before, after any) {
+	if reflect.DeepEqual(before, after) {
+		return
+	}
+	if bm, ok := before.(map[string]any); ok {
+		if am, ok := after.(map[string]any); ok {
+			for _, key := range unionKeys(bm, am) {
+				d.diff(joinPath(path, key), appendField(fields, key), bm[key], am[key])
+			}
+			return
+		}
+	}
+	if bl, ok := before.([]any); ok {
+		if al, ok := after.([]any); ok {
+			bk, bok := keyElements(bl)
+			ak, aok := keyElements(al)
+			if bok && aok {
+				for _, key := range unionOrder(bk.order, ak.order) {
+					d.diff(fmt.Sprintf("%s[%s]", path, key), fields, bk.items[key], ak.items[key])
+				}
+				return
+			}
+		}
+	}
+
+	c := Change{
+		Path:   path,
+		Kind:   KindChanged,
+		Before: render(fields, before),
+		After:  render(fields, after),
+		Auth:   isAuth(fields),
+		Risk:   strings.Join(risks(fields, before, after), "; "),
+	}
+	switch {
+	case before == nil:
+		c.Kind = KindAdded
+	case after == nil:
+		c.Kind = KindRemoved
+	}
+	d.changes = append(d.changes, c)
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+func appendField(fields []string, field string) []string {
+	return append(fields[:len(fields):len(fields)], field)
+}
+
+func unionKeys(a, b map[string]any) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// unionOrder returns the keys of a, in order, followed by the new keys of b.
+func unionOrder(a, b []string) []string {
+	keys := append([]string{}, a...)
+	for _, k := range b {
+		if !slices.Contains(a, k) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// keyedList holds the elements of a list by their name or ID.
+type keyedList struct {
+	order []string
+	items map[string]any
+}
+
+// keyElements indexes a list of objects by name, or by ID when they have no
+// name. It fails if an element has neither or if two share one, in which
+// case the list is compared as a whole.
+func keyElements(list []any) (keyedList, bool) {
+	kl := keyedList{items: make(map[string]any, len(list))}
+	for _, elem := range list {
+		m, ok := elem.(map[string]any)
+		if !ok {
+			return kl, false
+		}
+		key, _ := m["name"].(string)
+		if key == "" {
+			key, _ = m["id"].(string)
+		}
+		if _, dup := kl.items[key]; key == "" || dup {
+			return kl, false
+		}
+		kl.order = append(kl.order, key)
+		kl.items[key] = elem
+	}
+	return kl, true
+}
+
+// authFields are the fields whose changes are authentication changes.
+var authFields = []string{"authentication", "upstream_auth", "api_key", "users"}
+
+func isAuth(fields []string) bool {
+	return slices.ContainsFunc(fields, func(f string) bool { return slices.Contains(authFields, f) })
+}
+
+// render returns the JSON of a value, redacted if a field of its path is
+// sensitive, and truncated if long.
+func render(fields []string, v any) string {
+	if v == nil {
+		return ""
+	}
+	for _, f := range fields {
+		if f == "plain_text" || util.IsSensitiveKey(f) {
+			return redacted
+		}
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	s := string(util.RedactJSON(b))
+	if len(s) > maxValueLength {
+		s = s[:maxValueLength-3] + "..."
+	}
+	return s
+}
+
+// diffTools compares the configured tools of two configurations.
+func diffTools(before, after map[string]any) []ToolChange {
+	bt := configuredTools(before)
+	at := configuredTools(after)
+	var changes []ToolChange
+	for name := range bt {
+		if at[name] == nil {
+			changes = append(changes, ToolChange{Name: name, Kind: KindRemoved})
+		}
+	}
+	for name, a := range at {
+		b := bt[name]
+		switch {
+		case b == nil:
+			changes = append(changes, ToolChange{Name: name, Kind: KindAdded})
+		case !reflect.DeepEqual(b, a):
+			var fields []string
+			for _, key := range unionKeys(b, a) {
+				if !reflect.DeepEqual(b[key], a[key]) {
+					fields = append(fields, key)
+				}
+			}
+			changes = append(changes, ToolChange{Name: name, Kind: KindChanged, Fields: fields})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
+
+// configuredTools returns the tool definitions of the enabled services of a
+// configuration by fully qualified name.
+func configuredTools(cfg map[string]any) map[string]map[string]any {
+	tools := make(map[string]map[string]any)
+	services, _ := cfg["upstream_services"].([]any)
+	for _, s := range services {
+		svc, _ := s.(map[string]any)
+		if disabled, _ := svc["disable"].(bool); disabled {
+			continue
+		}
+		name, _ := svc["name"].(string)
+		serviceID, err := util.SanitizeServiceName(name)
+		if err != nil {
+			continue
+		}
+		for _, key := range unionKeys(svc, nil) {
+			kind, ok := svc[key].(map[string]any)
+			if !ok || !strings.HasSuffix(key, "_service") {
+				continue
+			}
+			defs, _ := kind["tools"].([]any)
+			for _, d := range defs {
+				def, _ := d.(map[string]any)
+				if disabled, _ := def["disable"].(bool); disabled {
+					continue
+				}
+				toolName, _ := def["name"].(string)
+				sanitized, err := util.SanitizeToolName(toolName)
+				if toolName == "" || err != nil {
+					continue
+				}
+				tools[tool.GetFullyQualifiedToolName(serviceID, sanitized)] = def
+			}
+		}
+	}
+	return tools
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package configdiff
+
+import (
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func parse(t *testing.T, s string) *configv1.McpAnyServerConfig {
+	t.Helper()
+	cfg := &configv1.McpAnyServerConfig{}
+	require.NoError(t, protojson.Unmarshal([]byte(s), cfg))
+	return cfg
+}
+
+func byPath(changes []Change) map[string]Change {
+	m := make(map[string]Change, len(changes))
+	for _, c := range changes {
+		m[c.Path] = c
+	}
+	return m
+}
+
+const current = `{
+  "global_settings": {"log_level": "LOG_LEVEL_INFO", "api_key": "server-key-1234567", "audit": {"enabled": true}},
+  "upstream_services": [
+    {
+      "name": "weather",
+      "http_service": {
+        "address": "https://weather.example.com",
+        "tools": [
+          {"name": "get_forecast", "call_id": "forecast", "description": "Forecast"},
+          {"name": "get_alerts", "call_id": "alerts"}
+        ]
+      },
+      "authentication": {"api_key": {"param_name": "X-Key", "value": {"plain_text": "hunter2"}}},
+      "call_policies": [{"default_action": "DENY"}]
+    },
+    {"name": "billing", "http_service": {"address": "https://billing.example.com", "tools": [{"name": "charge"}]}}
+  ],
+  "users": [{"id": "alice", "roles": ["viewer"]}]
+}`
+
+func TestDiff(t *testing.T) {
+	proposed := `{
+  "global_settings": {"log_level": "LOG_LEVEL_WARN", "audit": {"enabled": false}},
+  "upstream_services": [
+    {"name": "billing", "http_service": {"address": "https://billing.example.com", "tools": [{"name": "charge"}]}},
+    {
+      "name": "weather",
+      "http_service": {
+        "address": "http://weather.example.com",
+        "tools": [
+          {"name": "get_forecast", "call_id": "forecast", "description": "Daily forecast"},
+          {"name": "get_history", "call_id": "history"}
+        ]
+      },
+      "authentication": {"api_key": {"param_name": "X-Key", "value": {"plain_text": "hunter3"}}}
+    },
+    {"name": "shell", "command_line_service": {"command": "bash", "tools": [{"name": "run"}]}}
+  ],
+  "users": [{"id": "alice", "roles": ["viewer", "admin"]}]
+}`
+
+	report, err := Diff(parse(t, current), parse(t, proposed))
+	require.NoError(t, err)
+
+	assert.Equal(t, []ToolChange{
+		{Name: "shell.run", Kind: KindAdded},
+		{Name: "weather.get_alerts", Kind: KindRemoved},
+		{Name: "weather.get_forecast", Kind: KindChanged, Fields: []string{"description"}},
+		{Name: "weather.get_history", Kind: KindAdded},
+	}, report.Tools)
+
+	changes := byPath(report.Changes)
+	assert.Equal(t, Change{
+		Path: "global_settings.log_level", Kind: KindChanged,
+		Before: `"LOG_LEVEL_INFO"`, After: `"LOG_LEVEL_WARN"`,
+	}, changes["global_settings.log_level"])
+
+	key := changes["global_settings.api_key"]
+	assert.Equal(t, KindRemoved, key.Kind)
+	assert.Equal(t, redacted, key.Before, "secrets are never shown")
+	assert.True(t, key.Auth)
+	assert.Equal(t, "removes the API key of the server", key.Risk)
+
+	secret := changes["upstream_services[weather].authentication.api_key.value.plain_text"]
+	assert.Equal(t, redacted, secret.Before)
+	assert.Equal(t, redacted, secret.After)
+	assert.True(t, secret.Auth)
+	assert.Empty(t, secret.Risk)
+
+	assert.Equal(t, "disables audit logging", changes["global_settings.audit.enabled"].Risk)
+	assert.Equal(t, "switches from HTTPS to plain HTTP", changes["upstream_services[weather].http_service.address"].Risk)
+	assert.Equal(t, "removes call policies", changes["upstream_services[weather].call_policies"].Risk)
+	assert.Equal(t, KindAdded, changes["upstream_services[shell]"].Kind)
+	assert.Equal(t, "runs a local command", changes["upstream_services[shell]"].Risk)
+	assert.Equal(t, "grants the admin role", changes["users[alice].roles"].Risk)
+	assert.True(t, changes["users[alice].roles"].Auth)
+
+	_, reordered := changes["upstream_services[billing]"]
+	assert.False(t, reordered, "services are matched by name")
+
+	assert.Len(t, report.Risky(), 6)
+	assert.Len(t, report.AuthChanges(), 3)
+	assert.False(t, report.Empty())
+}
+
+func TestDiff_Same(t *testing.T) {
+	report, err := Diff(parse(t, current), parse(t, current))
+	require.NoError(t, err)
+	assert.True(t, report.Empty())
+
+	report, err = Diff(nil, nil)
+	require.NoError(t, err)
+	assert.True(t, report.Empty())
+}
+
+func TestDiff_AddedServiceRisks(t *testing.T) {
+	report, err := Diff(nil, parse(t, `{"upstream_services": [{
+  "name": "internal",
+  "grpc_service": {"address": "internal:443", "tls_config": {"insecure_skip_verify": true}}
+}]}`))
+	require.NoError(t, err)
+	require.Len(t, report.Changes, 1)
+	assert.Equal(t, "upstream_services", report.Changes[0].Path)
+	assert.Equal(t, "disables TLS certificate verification", report.Changes[0].Risk)
+}
+
+func TestDiffJSON_Redacted(t *testing.T) {
+	// The configuration of a running server is redacted, so the proposed
+	// one is redacted the same way to compare equal.
+	cfg := parse(t, current)
+	running, err := RedactedJSON(cfg)
+	require.NoError(t, err)
+	assert.NotContains(t, string(running), "hunter2")
+	assert.NotContains(t, string(running), "server-key-1234567")
+
+	report, err := DiffJSON(running, running)
+	require.NoError(t, err)
+	assert.True(t, report.Empty())
+
+	cfg.GetUpstreamServices()[0].ClearAuthentication()
+	proposed, err := RedactedJSON(cfg)
+	require.NoError(t, err)
+	report, err = DiffJSON(running, proposed)
+	require.NoError(t, err)
+	require.Len(t, report.Changes, 1)
+	assert.Equal(t, "upstream_services[weather].authentication", report.Changes[0].Path)
+	assert.Equal(t, "removes authentication", report.Changes[0].Risk)
+
+	_, err = DiffJSON([]byte("[1]"), nil)
+	assert.ErrorContains(t, err, "invalid current configuration")
+}
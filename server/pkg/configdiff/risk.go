@@ -0,0 +1,145 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package configdiff
+
+import (
+	"slices"
+	"strings"
+)
+
+// rule returns why a change of a field weakens the security of the server,
+// or "" if it does not. fields holds the field names of the path of the
+// field; before and after are nil when it is absent.
+type rule func(fields []string, before, after any) string
+
+// rules are the risky changes that are flagged.
+var rules = []rule{
+	removed("authentication", "removes authentication"),
+	func(fields []string, before, after any) string {
+		if slices.Equal(fields, []string{"global_settings", "api_key"}) && before != nil && after == nil {
+			return "removes the API key of the server"
+		}
+		return ""
+	},
+	func(fields []string, _, after any) string {
+		if last(fields) == "insecure_skip_verify" && after == true {
+			return "disables TLS certificate verification"
+		}
+		return ""
+	},
+	func(fields []string, before, after any) string {
+		if (last(fields) == "command_line_service" || last(fields) == "stdio_connection") && before == nil && after != nil {
+			return "runs a local command"
+		}
+		return ""
+	},
+	func(fields []string, before, after any) string {
+		if last(fields) == "command" && before != nil && after != nil && under(fields, "command_line_service", "stdio_connection") {
+			return "changes a local command"
+		}
+		return ""
+	},
+	removed("call_policies", "removes call policies"),
+	removed("pre_call_hooks", "removes pre-call hooks"),
+	func(fields []string, before, after any) string {
+		if last(fields) == "default_action" && under(fields, "call_policies") && before == "DENY" && after != "DENY" {
+			return "allows the calls no policy rule matches"
+		}
+		return ""
+	},
+	removed("tool_export_policy", "removes the tool export policy"),
+	disabled("rate_limit", "is_enabled", "disables rate limiting"),
+	disabled("audit", "enabled", "disables audit logging"),
+	disabled("dlp", "enabled", "disables DLP redaction"),
+	removed("allowed_ips", "removes the IP allowlist"),
+	func(fields []string, before, after any) string {
+		from, _ := before.(string)
+		to, _ := after.(string)
+		if (last(fields) == "address" || last(fields) == "http_address") &&
+			strings.HasPrefix(strings.ToLower(from), "https://") && strings.HasPrefix(strings.ToLower(to), "http://") {
+			return "switches from HTTPS to plain HTTP"
+		}
+		return ""
+	},
+	func(fields []string, before, after any) string {
+		if last(fields) == "roles" && hasString(after, "admin") && !hasString(before, "admin") {
+			return "grants the admin role"
+		}
+		return ""
+	},
+}
+
+// risks returns why a change weakens the security of the server. The fields
+// of an added or changed object are checked too; the ones of a removed
+// object go away with it.
+func risks(fields []string, before, after any) []string {
+	var reasons []string
+	for _, r := range rules {
+		if reason := r(fields, before, after); reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+	switch a := after.(type) {
+	case map[string]any:
+		b, _ := before.(map[string]any)
+		for _, key := range unionKeys(a, nil) {
+			reasons = append(reasons, risks(appendField(fields, key), b[key], a[key])...)
+		}
+	case []any:
+		b, _ := before.([]any)
+		bk, bok := keyElements(b)
+		ak, aok := keyElements(a)
+		if bok && aok {
+			for _, key := range ak.order {
+				reasons = append(reasons, risks(fields, bk.items[key], ak.items[key])...)
+			}
+		}
+	}
+	slices.Sort(reasons)
+	return slices.Compact(reasons)
+}
+
+func last(fields []string) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[len(fields)-1]
+}
+
+func under(fields []string, parents ...string) bool {
+	return slices.ContainsFunc(fields[:max(len(fields)-1, 0)], func(f string) bool { return slices.Contains(parents, f) })
+}
+
+func hasString(v any, s string) bool {
+	list, _ := v.([]any)
+	return slices.Contains(list, any(s))
+}
+
+// removed flags the removal of a field.
+func removed(field, reason string) rule {
+	return func(fields []string, before, after any) string {
+		if last(fields) == field && before != nil && after == nil {
+			return reason
+		}
+		return ""
+	}
+}
+
+// disabled flags turning off the enabled flag of a section, or removing an
+// enabled section.
+func disabled(section, enabled, reason string) rule {
+	return func(fields []string, before, after any) string {
+		wasEnabled := func() bool {
+			m, _ := before.(map[string]any)
+			return m[enabled] == true
+		}
+		switch {
+		case last(fields) == section && after == nil && wasEnabled():
+			return reason
+		case last(fields) == enabled && under(fields, section) && before == true && after != true:
+			return reason
+		}
+		return ""
+	}
+}
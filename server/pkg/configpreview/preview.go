@@ -0,0 +1,382 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package configpreview replays audited tool calls against a proposed
+// configuration to show how it would have treated recent traffic.
+//
+// Calls are evaluated at the argument level only: nothing is sent to the
+// upstream services.
+package configpreview
+
+import (
+	"fmt"
+	"strings"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/audit"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/mcpany/core/server/pkg/util"
+)
+
+// Outcome is how the proposed configuration treats a past call.
+type Outcome string
+
+const (
+	// OutcomeUnchanged means the call would behave as it did.
+	OutcomeUnchanged Outcome = "unchanged"
+	// OutcomeBlocked means the call would be rejected.
+	OutcomeBlocked Outcome = "blocked"
+	// OutcomeRenamed means the call's tool now has another name.
+	OutcomeRenamed Outcome = "renamed"
+	// OutcomeRerouted means the call would reach another upstream or endpoint.
+	OutcomeRerouted Outcome = "rerouted"
+)
+
+// Result is the outcome of replaying one audited call.
+type Result struct {
+	// Entry is the audited call.
+	Entry audit.Entry
+	// Outcome is how the proposed configuration treats the call.
+	Outcome Outcome
+	// Reason explains the outcome. It is empty for unchanged calls.
+	Reason string
+	// ToolName is the name of the tool under the proposed configuration, if
+	// it still has one.
+	ToolName string
+}
+
+// Report is the outcome of replaying a set of audited calls.
+type Report struct {
+	// Results holds one result per call, in the order of the calls.
+	Results []Result
+	// Counts holds the number of calls per outcome.
+	Counts map[Outcome]int
+}
+
+// Changed returns the results of the calls that would not behave as they did.
+//
+// Returns:
+//   - []Result: The blocked, renamed and rerouted calls.
+func (r *Report) Changed() []Result {
+	var changed []Result
+	for _, res := range r.Results {
+		if res.Outcome != OutcomeUnchanged {
+			changed = append(changed, res)
+		}
+	}
+	return changed
+}
+
+// Replay evaluates audited calls against a proposed configuration.
+//
+// Each call is resolved to a tool of the proposed configuration, following
+// renames, and then checked against the service and tool disable flags, the
+// tool export policy, the call policies and pre-call hook policies, and the
+// service config of the caller's profile. Calls that pass are compared with
+// the current configuration to find those reaching another upstream address,
+// endpoint or service. Argument rules only see the arguments the audit log
+// recorded.
+//
+// Summary: Replays audited calls against a proposed configuration.
+//
+// Parameters:
+//   - current: *configv1.McpAnyServerConfig. The running configuration. It may be nil, in which case renames and routing changes are not detected.
+//   - proposed: *configv1.McpAnyServerConfig. The configuration to evaluate.
+//   - entries: []audit.Entry. The audited calls.
+//
+// Returns:
+//   - *Report: The outcome of each call.
+func Replay(current, proposed *configv1.McpAnyServerConfig, entries []audit.Entry) *Report {
+	cur := newIndex(current)
+	prop := newIndex(proposed)
+	report := &Report{Results: make([]Result, 0, len(entries)), Counts: make(map[Outcome]int)}
+	for _, entry := range entries {
+		res := replayOne(cur, prop, proposed, entry)
+		report.Results = append(report.Results, res)
+		report.Counts[res.Outcome]++
+	}
+	return report
+}
+
+func replayOne(cur, prop *index, proposed *configv1.McpAnyServerConfig, entry audit.Entry) Result {
+	res := Result{Entry: entry, Outcome: OutcomeUnchanged, ToolName: entry.ToolName}
+	serviceID, _, err := tool.ParseToolName(entry.ToolName)
+	if err != nil {
+		return blocked(res, err.Error())
+	}
+
+	before := cur.tools[entry.ToolName]
+	after := prop.tools[entry.ToolName]
+	if after == nil && before != nil {
+		if renamed := prop.calls[before.callKey()]; renamed != nil {
+			after = renamed
+			res.Outcome = OutcomeRenamed
+			res.ToolName = renamed.name
+			res.Reason = fmt.Sprintf("now %s", renamed.name)
+		}
+	}
+
+	var svc *service
+	if after != nil {
+		svc = after.service
+	} else {
+		svc = prop.services[serviceID]
+	}
+	switch {
+	case svc == nil:
+		return blocked(res, fmt.Sprintf("service %q is not configured", serviceID))
+	case svc.config.GetDisable():
+		return blocked(res, fmt.Sprintf("service %q is disabled", svc.config.GetName()))
+	case after == nil && before != nil:
+		// The tool was configured and is gone; tools the configuration does
+		// not list are discovered from the upstream and cannot be judged.
+		return blocked(res, fmt.Sprintf("tool %q is no longer configured", entry.ToolName))
+	}
+
+	toolName := res.ToolName
+	_, namePart, _ := tool.ParseToolName(toolName)
+	callID := ""
+	if after != nil {
+		namePart = after.def.GetName()
+		callID = after.def.GetCallId()
+		if after.def.GetDisable() {
+			return blocked(res, fmt.Sprintf("tool %q is disabled", toolName))
+		}
+	}
+	if !tool.ShouldExport(namePart, svc.config.GetToolExportPolicy()) {
+		return blocked(res, fmt.Sprintf("tool %q is not exported by tool_export_policy", toolName))
+	}
+	if reason := checkPolicies(svc.config, toolName, namePart, callID, entry.Arguments); reason != "" {
+		return blocked(res, reason)
+	}
+	if reason := checkProfile(proposed, entry.ProfileID, svc, namePart); reason != "" {
+		return blocked(res, reason)
+	}
+
+	if res.Outcome == OutcomeRenamed {
+		return res
+	}
+	if from, to := cur.route(before, serviceID), prop.route(after, serviceID); from != "" && to != "" && from != to {
+		res.Outcome = OutcomeRerouted
+		res.Reason = fmt.Sprintf("%s -> %s", from, to)
+	}
+	return res
+}
+
+func blocked(res Result, reason string) Result {
+	if res.Outcome == OutcomeRenamed {
+		reason = res.Reason + "; " + reason
+	}
+	res.Outcome = OutcomeBlocked
+	res.Reason = reason
+	return res
+}
+
+// checkPolicies evaluates the call policies of a service the way the server
+// does: once by the call policy middleware, with the full tool name and the
+// arguments, and once by the tool itself, with its own name and call ID.
+func checkPolicies(cfg *configv1.UpstreamServiceConfig, toolName, namePart, callID string, arguments []byte) string {
+	policies := cfg.GetCallPolicies()
+	for _, hook := range cfg.GetPreCallHooks() {
+		if p := hook.GetCallPolicy(); p != nil {
+			policies = append(policies, p)
+		}
+	}
+	if len(policies) == 0 {
+		return ""
+	}
+	if len(arguments) == 0 {
+		arguments = nil
+	}
+	if allowed, _ := tool.EvaluateCallPolicy(policies, toolName, "", arguments); !allowed {
+		return "denied by call policy"
+	}
+	if allowed, _ := tool.EvaluateCallPolicy(policies, namePart, callID, arguments); !allowed {
+		return "denied by call policy"
+	}
+	return ""
+}
+
+// checkProfile checks the service config the caller's profile has for the
+// service, by ID or by name. Profile selectors are not evaluated.
+func checkProfile(cfg *configv1.McpAnyServerConfig, profileID string, svc *service, namePart string) string {
+	if profileID == "" {
+		return ""
+	}
+	for _, def := range cfg.GetGlobalSettings().GetProfileDefinitions() {
+		if def.GetName() != profileID {
+			continue
+		}
+		sc, ok := def.GetServiceConfig()[svc.id]
+		if !ok {
+			sc, ok = def.GetServiceConfig()[svc.config.GetName()]
+		}
+		if !ok {
+			return ""
+		}
+		if sc.HasEnabled() && !sc.GetEnabled() {
+			return fmt.Sprintf("profile %q disables service %q", profileID, svc.config.GetName())
+		}
+		if tc, ok := sc.GetTools()[namePart]; ok && tc.GetDisabled() {
+			return fmt.Sprintf("profile %q disables tool %q", profileID, namePart)
+		}
+		return ""
+	}
+	return ""
+}
+
+type service struct {
+	id     string
+	config *configv1.UpstreamServiceConfig
+	// address is where the service's calls go.
+	address string
+}
+
+type toolEntry struct {
+	name    string
+	service *service
+	def     *configv1.ToolDefinition
+	// endpoint identifies the call within the service, e.g. its HTTP method
+	// and path.
+	endpoint string
+}
+
+// callKey identifies a tool across renames: by its service name and call ID,
+// or its name if it has no call ID.
+func (t *toolEntry) callKey() string {
+	id := t.def.GetCallId()
+	if id == "" {
+		id = "name:" + t.def.GetName()
+	}
+	return t.service.config.GetName() + "\x00" + id
+}
+
+// index holds the services and configured tools of a configuration by the
+// names the server registers them under.
+type index struct {
+	services map[string]*service
+	tools    map[string]*toolEntry
+	calls    map[string]*toolEntry
+}
+
+func newIndex(cfg *configv1.McpAnyServerConfig) *index {
+	idx := &index{
+		services: make(map[string]*service),
+		tools:    make(map[string]*toolEntry),
+		calls:    make(map[string]*toolEntry),
+	}
+	for _, sc := range cfg.GetUpstreamServices() {
+		id, err := util.SanitizeServiceName(sc.GetName())
+		if err != nil {
+			continue
+		}
+		svc := &service{id: id, config: sc, address: serviceAddress(sc)}
+		idx.services[id] = svc
+		endpoints := callEndpoints(sc)
+		for _, def := range serviceTools(sc) {
+			if def.GetName() == "" {
+				continue
+			}
+			name, err := util.SanitizeToolName(def.GetName())
+			if err != nil {
+				continue
+			}
+			t := &toolEntry{
+				name:     tool.GetFullyQualifiedToolName(id, name),
+				service:  svc,
+				def:      def,
+				endpoint: endpoints[def.GetCallId()],
+			}
+			idx.tools[t.name] = t
+			idx.calls[t.callKey()] = t
+		}
+	}
+	return idx
+}
+
+// route describes where a call goes: the service and its address, and the
+// endpoint of configured tools. It is empty if the service is unknown.
+func (idx *index) route(t *toolEntry, serviceID string) string {
+	svc := idx.services[serviceID]
+	endpoint := ""
+	if t != nil {
+		svc = t.service
+		endpoint = t.endpoint
+	}
+	if svc == nil {
+		return ""
+	}
+	parts := []string{svc.id}
+	if svc.address != "" {
+		parts = append(parts, svc.address)
+	}
+	if endpoint != "" {
+		parts = append(parts, endpoint)
+	}
+	return strings.Join(parts, " ")
+}
+
+func serviceTools(sc *configv1.UpstreamServiceConfig) []*configv1.ToolDefinition {
+	switch {
+	case sc.HasHttpService():
+		return sc.GetHttpService().GetTools()
+	case sc.HasGrpcService():
+		return sc.GetGrpcService().GetTools()
+	case sc.HasOpenapiService():
+		return sc.GetOpenapiService().GetTools()
+	case sc.HasCommandLineService():
+		return sc.GetCommandLineService().GetTools()
+	case sc.HasMcpService():
+		return sc.GetMcpService().GetTools()
+	case sc.HasWebsocketService():
+		return sc.GetWebsocketService().GetTools()
+	case sc.HasWebrtcService():
+		return sc.GetWebrtcService().GetTools()
+	case sc.HasFilesystemService():
+		return sc.GetFilesystemService().GetTools()
+	case sc.HasVectorService():
+		return sc.GetVectorService().GetTools()
+	}
+	return nil
+}
+
+func serviceAddress(sc *configv1.UpstreamServiceConfig) string {
+	switch {
+	case sc.HasHttpService():
+		return sc.GetHttpService().GetAddress()
+	case sc.HasGrpcService():
+		return sc.GetGrpcService().GetAddress()
+	case sc.HasOpenapiService():
+		return sc.GetOpenapiService().GetAddress()
+	case sc.HasWebsocketService():
+		return sc.GetWebsocketService().GetAddress()
+	case sc.HasWebrtcService():
+		return sc.GetWebrtcService().GetAddress()
+	case sc.HasGraphqlService():
+		return sc.GetGraphqlService().GetAddress()
+	case sc.HasCommandLineService():
+		return sc.GetCommandLineService().GetCommand()
+	case sc.HasMcpService():
+		mcp := sc.GetMcpService()
+		if mcp.HasHttpConnection() {
+			return mcp.GetHttpConnection().GetHttpAddress()
+		}
+		return strings.TrimSpace(mcp.GetStdioConnection().GetCommand() + " " + strings.Join(mcp.GetStdioConnection().GetArgs(), " "))
+	}
+	return ""
+}
+
+// callEndpoints returns the method and path of the HTTP calls of a service,
+// by call ID.
+func callEndpoints(sc *configv1.UpstreamServiceConfig) map[string]string {
+	calls := sc.GetHttpService().GetCalls()
+	if len(calls) == 0 {
+		return nil
+	}
+	endpoints := make(map[string]string, len(calls))
+	for id, call := range calls {
+		method := strings.TrimPrefix(call.GetMethod().String(), "HTTP_METHOD_")
+		endpoints[id] = method + " " + call.GetEndpointPath()
+	}
+	return endpoints
+}
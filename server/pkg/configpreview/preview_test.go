@@ -0,0 +1,179 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package configpreview
+
+import (
+	"encoding/json"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func parseConfig(t *testing.T, js string) *configv1.McpAnyServerConfig {
+	t.Helper()
+	cfg := &configv1.McpAnyServerConfig{}
+	require.NoError(t, protojson.Unmarshal([]byte(js), cfg))
+	return cfg
+}
+
+const currentConfig = `{
+  "upstream_services": [{
+    "name": "weather",
+    "http_service": {
+      "address": "https://api.weather.example",
+      "tools": [
+        {"name": "get_forecast", "call_id": "forecast"},
+        {"name": "get_alerts", "call_id": "alerts"},
+        {"name": "delete_station", "call_id": "delete"}
+      ],
+      "calls": {
+        "forecast": {"method": "HTTP_METHOD_GET", "endpoint_path": "/forecast"},
+        "alerts": {"method": "HTTP_METHOD_GET", "endpoint_path": "/alerts"},
+        "delete": {"method": "HTTP_METHOD_DELETE", "endpoint_path": "/stations/{{id}}"}
+      }
+    }
+  }, {
+    "name": "github",
+    "mcp_service": {"http_connection": {"http_address": "https://mcp.github.example"}}
+  }]
+}`
+
+const proposedConfig = `{
+  "global_settings": {
+    "profile_definitions": [{
+      "name": "readonly",
+      "service_config": {"github": {"enabled": false}}
+    }]
+  },
+  "upstream_services": [{
+    "name": "weather",
+    "http_service": {
+      "address": "https://api.weather.example",
+      "tools": [
+        {"name": "forecast", "call_id": "forecast"},
+        {"name": "get_alerts", "call_id": "alerts"},
+        {"name": "delete_station", "call_id": "delete"}
+      ],
+      "calls": {
+        "forecast": {"method": "HTTP_METHOD_GET", "endpoint_path": "/forecast"},
+        "alerts": {"method": "HTTP_METHOD_GET", "endpoint_path": "/v2/alerts"},
+        "delete": {"method": "HTTP_METHOD_DELETE", "endpoint_path": "/stations/{{id}}"}
+      }
+    },
+    "call_policies": [{
+      "default_action": "ALLOW",
+      "rules": [{"action": "DENY", "name_regex": "delete_.*", "argument_regex": "\"id\":\"prod-"}]
+    }]
+  }, {
+    "name": "github",
+    "mcp_service": {"http_connection": {"http_address": "https://mcp.github.example"}}
+  }]
+}`
+
+func entry(toolName, args, profile string) audit.Entry {
+	return audit.Entry{ToolName: toolName, Arguments: json.RawMessage(args), ProfileID: profile}
+}
+
+func TestReplay(t *testing.T) {
+	current := parseConfig(t, currentConfig)
+	proposed := parseConfig(t, proposedConfig)
+
+	report := Replay(current, proposed, []audit.Entry{
+		entry("weather.get_forecast", `{"city":"Paris"}`, ""),
+		entry("weather.get_alerts", `{}`, ""),
+		entry("weather.delete_station", `{"id":"prod-1"}`, ""),
+		entry("weather.delete_station", `{"id":"test-1"}`, ""),
+		entry("github.search_issues", `{}`, ""),
+		entry("github.search_issues", `{}`, "readonly"),
+		entry("slack.post_message", `{}`, ""),
+	})
+
+	require.Len(t, report.Results, 7)
+	outcomes := make([]Outcome, len(report.Results))
+	for i, res := range report.Results {
+		outcomes[i] = res.Outcome
+	}
+	assert.Equal(t, []Outcome{
+		OutcomeRenamed,
+		OutcomeRerouted,
+		OutcomeBlocked,
+		OutcomeUnchanged,
+		OutcomeUnchanged,
+		OutcomeBlocked,
+		OutcomeBlocked,
+	}, outcomes)
+
+	assert.Equal(t, "weather.forecast", report.Results[0].ToolName)
+	assert.Equal(t, "weather https://api.weather.example GET /alerts -> weather https://api.weather.example GET /v2/alerts", report.Results[1].Reason)
+	assert.Equal(t, "denied by call policy", report.Results[2].Reason)
+	assert.Equal(t, `profile "readonly" disables service "github"`, report.Results[5].Reason)
+	assert.Equal(t, `service "slack" is not configured`, report.Results[6].Reason)
+
+	assert.Equal(t, map[Outcome]int{
+		OutcomeRenamed:   1,
+		OutcomeRerouted:  1,
+		OutcomeBlocked:   3,
+		OutcomeUnchanged: 2,
+	}, report.Counts)
+	assert.Len(t, report.Changed(), 5)
+}
+
+func TestReplay_DisabledAndRemoved(t *testing.T) {
+	current := parseConfig(t, currentConfig)
+	proposed := parseConfig(t, `{
+  "upstream_services": [{
+    "name": "weather",
+    "http_service": {
+      "address": "https://api.weather.example",
+      "tools": [
+        {"name": "get_forecast", "call_id": "forecast", "disable": true},
+        {"name": "get_alerts", "call_id": "alerts"}
+      ],
+      "calls": {
+        "forecast": {"method": "HTTP_METHOD_GET", "endpoint_path": "/forecast"},
+        "alerts": {"method": "HTTP_METHOD_GET", "endpoint_path": "/alerts"}
+      }
+    },
+    "tool_export_policy": {"default_action": "EXPORT", "rules": [{"name_regex": "^get_alerts$", "action": "UNEXPORT"}]}
+  }, {
+    "name": "github",
+    "disable": true,
+    "mcp_service": {"http_connection": {"http_address": "https://mcp.github.example"}}
+  }]
+}`)
+
+	report := Replay(current, proposed, []audit.Entry{
+		entry("weather.get_forecast", `{}`, ""),
+		entry("weather.get_alerts", `{}`, ""),
+		entry("weather.delete_station", `{}`, ""),
+		entry("github.search_issues", `{}`, ""),
+	})
+
+	require.Len(t, report.Results, 4)
+	for _, res := range report.Results {
+		assert.Equal(t, OutcomeBlocked, res.Outcome, res.Entry.ToolName)
+	}
+	assert.Equal(t, `tool "weather.get_forecast" is disabled`, report.Results[0].Reason)
+	assert.Equal(t, `tool "weather.get_alerts" is not exported by tool_export_policy`, report.Results[1].Reason)
+	assert.Equal(t, `tool "weather.delete_station" is no longer configured`, report.Results[2].Reason)
+	assert.Equal(t, `service "github" is disabled`, report.Results[3].Reason)
+}
+
+func TestReplay_WithoutCurrentConfig(t *testing.T) {
+	proposed := parseConfig(t, proposedConfig)
+
+	report := Replay(nil, proposed, []audit.Entry{
+		entry("weather.get_forecast", `{}`, ""),
+		entry("weather.get_alerts", `{}`, ""),
+	})
+
+	// Without the current configuration renames and routing changes are
+	// unknown; the old name is judged as a discovered tool.
+	assert.Equal(t, OutcomeUnchanged, report.Results[0].Outcome)
+	assert.Equal(t, OutcomeUnchanged, report.Results[1].Outcome)
+}
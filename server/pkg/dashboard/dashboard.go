@@ -0,0 +1,360 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package dashboard serves the built-in web dashboard: a single page embedded
+// in the server binary that shows the connected sessions, the tool catalog,
+// recent calls, the health of the upstreams and the live logs.
+//
+// The page reads a read-only API open to the viewer and admin roles. Admins
+// also get the actions of the admin API, such as closing a session or
+// resetting a circuit breaker.
+package dashboard
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	pb "github.com/mcpany/core/proto/admin/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/logging"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Roles are the roles allowed to open the dashboard.
+var Roles = []string{"admin", "viewer"}
+
+const (
+	// defaultCallLimit is the number of recent calls returned by default.
+	defaultCallLimit = 50
+	// maxCallLimit caps the number of recent calls a request may ask for.
+	maxCallLimit = 500
+	// logKeepAlive is how often an idle log stream is kept alive.
+	logKeepAlive = 15 * time.Second
+)
+
+//go:embed static
+var static embed.FS
+
+// Backend provides the runtime state the dashboard shows. The admin service
+// implements it.
+type Backend interface {
+	ListServices(ctx context.Context, req *pb.ListServicesRequest) (*pb.ListServicesResponse, error)
+	ListTools(ctx context.Context, req *pb.ListToolsRequest) (*pb.ListToolsResponse, error)
+	ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error)
+	ListCircuitBreakers(ctx context.Context, req *pb.ListCircuitBreakersRequest) (*pb.ListCircuitBreakersResponse, error)
+	ListAuditLogs(ctx context.Context, req *pb.ListAuditLogsRequest) (*pb.ListAuditLogsResponse, error)
+}
+
+// Me is the identity of the dashboard user.
+type Me struct {
+	User  string   `json:"user"`
+	Roles []string `json:"roles"`
+	// Admin reports whether the user may take the actions of the admin API.
+	Admin bool `json:"admin"`
+}
+
+// Session is a connected MCP session.
+type Session struct {
+	ID              string `json:"id"`
+	ClientName      string `json:"client_name,omitempty"`
+	ClientVersion   string `json:"client_version,omitempty"`
+	ProtocolVersion string `json:"protocol_version,omitempty"`
+	Transport       string `json:"transport,omitempty"`
+	UserID          string `json:"user_id,omitempty"`
+	StartedAt       string `json:"started_at,omitempty"`
+	ToolCallCount   int64  `json:"tool_call_count,omitempty"`
+}
+
+// Tool is an entry of the tool catalog.
+type Tool struct {
+	Name        string         `json:"name"`
+	ServiceID   string         `json:"service_id"`
+	Description string         `json:"description,omitempty"`
+	InputSchema map[string]any `json:"input_schema,omitempty"`
+	ReadOnly    bool           `json:"read_only,omitempty"`
+	Destructive bool           `json:"destructive,omitempty"`
+}
+
+// Call is a recent tool call from the audit store. Its arguments and result
+// are shown to admins only.
+type Call struct {
+	Timestamp string `json:"timestamp"`
+	ToolName  string `json:"tool_name"`
+	UserID    string `json:"user_id,omitempty"`
+	ProfileID string `json:"profile_id,omitempty"`
+	Duration  string `json:"duration,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+	Result    string `json:"result,omitempty"`
+}
+
+// Calls lists the recent tool calls.
+type Calls struct {
+	// AuditEnabled is false when the server keeps no audit log.
+	AuditEnabled bool   `json:"audit_enabled"`
+	Calls        []Call `json:"calls"`
+}
+
+// Upstream is the health of an upstream service and the state of its
+// circuit breaker.
+type Upstream struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Circuit is the state of the circuit breaker: closed, open or
+	// half-open; empty before the first call of the service.
+	Circuit             string `json:"circuit,omitempty"`
+	ConsecutiveFailures int32  `json:"consecutive_failures,omitempty"`
+	OpenedAt            string `json:"opened_at,omitempty"`
+}
+
+// Assets returns the handler of the page and its scripts. They hold no data,
+// so they are served without authentication; mount it with the prefix of the
+// dashboard stripped.
+//
+// Returns:
+//   - http.Handler: The handler of the embedded files.
+func Assets() http.Handler {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(fmt.Sprintf("dashboard: embedded files: %v", err))
+	}
+	fileServer := http.FileServer(http.FS(files))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-cache")
+		fileServer.ServeHTTP(w, r)
+	})
+}
+
+// API returns the read-only API of the dashboard. It expects an
+// authenticated request whose user has one of the Roles; mount it with the
+// prefix of the dashboard stripped.
+//
+// Parameters:
+//   - backend (Backend): The source of the runtime state.
+//
+// Returns:
+//   - http.Handler: The API handler, serving /api/me, /api/sessions,
+//     /api/tools, /api/calls, /api/upstreams and /api/logs.
+func API(backend Backend) http.Handler {
+	h := &apiHandler{backend: backend}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/me", h.me)
+	mux.HandleFunc("GET /api/sessions", h.sessions)
+	mux.HandleFunc("GET /api/tools", h.tools)
+	mux.HandleFunc("GET /api/calls", h.calls)
+	mux.HandleFunc("GET /api/upstreams", h.upstreams)
+	mux.HandleFunc("GET /api/logs", h.logs)
+	return mux
+}
+
+type apiHandler struct {
+	backend Backend
+}
+
+func (h *apiHandler) me(w http.ResponseWriter, r *http.Request) {
+	user, _ := auth.UserFromContext(r.Context())
+	roles, _ := auth.RolesFromContext(r.Context())
+	writeJSON(w, Me{
+		User:  user,
+		Roles: roles,
+		Admin: auth.NewRBACEnforcer().HasRoleInContext(r.Context(), "admin"),
+	})
+}
+
+func (h *apiHandler) sessions(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.backend.ListSessions(r.Context(), &pb.ListSessionsRequest{})
+	if err != nil {
+		writeError(w, "failed to list sessions", err)
+		return
+	}
+	sessions := make([]Session, 0, len(resp.GetSessions()))
+	for _, s := range resp.GetSessions() {
+		sessions = append(sessions, Session{
+			ID:              s.GetId(),
+			ClientName:      s.GetClientName(),
+			ClientVersion:   s.GetClientVersion(),
+			ProtocolVersion: s.GetProtocolVersion(),
+			Transport:       s.GetTransport(),
+			UserID:          s.GetUserId(),
+			StartedAt:       s.GetStartedAt(),
+			ToolCallCount:   s.GetToolCallCount(),
+		})
+	}
+	writeJSON(w, sessions)
+}
+
+func (h *apiHandler) tools(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.backend.ListTools(r.Context(), &pb.ListToolsRequest{})
+	if err != nil {
+		writeError(w, "failed to list tools", err)
+		return
+	}
+	tools := make([]Tool, 0, len(resp.GetTools()))
+	for _, t := range resp.GetTools() {
+		tools = append(tools, Tool{
+			Name:        t.GetName(),
+			ServiceID:   t.GetServiceId(),
+			Description: t.GetDescription(),
+			InputSchema: t.GetInputSchema().AsMap(),
+			ReadOnly:    t.GetAnnotations().GetReadOnlyHint(),
+			Destructive: t.GetAnnotations().GetDestructiveHint(),
+		})
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	writeJSON(w, tools)
+}
+
+func (h *apiHandler) calls(w http.ResponseWriter, r *http.Request) {
+	limit := defaultCallLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		limit = min(n, maxCallLimit)
+	}
+	resp, err := h.backend.ListAuditLogs(r.Context(), pb.ListAuditLogsRequest_builder{
+		ToolName: proto.String(r.URL.Query().Get("tool")),
+		Limit:    proto.Int32(int32(limit)), //nolint:gosec // capped by maxCallLimit
+	}.Build())
+	if status.Code(err) == codes.FailedPrecondition {
+		writeJSON(w, Calls{Calls: []Call{}})
+		return
+	}
+	if err != nil {
+		writeError(w, "failed to list calls", err)
+		return
+	}
+	admin := auth.NewRBACEnforcer().HasRoleInContext(r.Context(), "admin")
+	calls := make([]Call, 0, len(resp.GetEntries()))
+	for _, e := range resp.GetEntries() {
+		call := Call{
+			Timestamp: e.GetTimestamp(),
+			ToolName:  e.GetToolName(),
+			UserID:    e.GetUserId(),
+			ProfileID: e.GetProfileId(),
+			Duration:  e.GetDuration(),
+			Error:     e.GetError(),
+		}
+		if admin {
+			call.Arguments = e.GetArguments()
+			call.Result = e.GetResult()
+		}
+		calls = append(calls, call)
+	}
+	// Newest first, whatever order the audit store keeps.
+	sort.SliceStable(calls, func(i, j int) bool { return calls[i].Timestamp > calls[j].Timestamp })
+	writeJSON(w, Calls{AuditEnabled: true, Calls: calls})
+}
+
+func (h *apiHandler) upstreams(w http.ResponseWriter, r *http.Request) {
+	services, err := h.backend.ListServices(r.Context(), &pb.ListServicesRequest{})
+	if err != nil {
+		writeError(w, "failed to list services", err)
+		return
+	}
+	breakers, err := h.backend.ListCircuitBreakers(r.Context(), &pb.ListCircuitBreakersRequest{})
+	if err != nil {
+		writeError(w, "failed to list circuit breakers", err)
+		return
+	}
+	circuits := make(map[string]*pb.CircuitBreakerState, len(breakers.GetCircuitBreakers()))
+	for _, cb := range breakers.GetCircuitBreakers() {
+		circuits[cb.GetServiceId()] = cb
+	}
+	upstreams := make([]Upstream, 0, len(services.GetServiceStates()))
+	for _, s := range services.GetServiceStates() {
+		u := Upstream{
+			ID:     s.GetConfig().GetId(),
+			Name:   s.GetConfig().GetName(),
+			Status: s.GetStatus(),
+			Error:  s.GetError(),
+		}
+		if cb, ok := circuits[u.ID]; ok {
+			u.Circuit = cb.GetState()
+			u.ConsecutiveFailures = cb.GetConsecutiveFailures()
+			u.OpenedAt = cb.GetOpenedAt()
+		}
+		upstreams = append(upstreams, u)
+	}
+	sort.Slice(upstreams, func(i, j int) bool { return upstreams[i].Name < upstreams[j].Name })
+	writeJSON(w, upstreams)
+}
+
+// logs streams the server logs as server-sent events, starting with the
+// recent history.
+func (h *apiHandler) logs(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	ch, history := logging.GlobalBroadcaster.SubscribeWithHistory()
+	defer logging.GlobalBroadcaster.Unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	for _, msg := range history {
+		if err := writeEvent(w, msg); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(logKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, msg); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// writeEvent writes a log message as a server-sent event.
+func writeEvent(w http.ResponseWriter, msg any) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil // Skip messages that cannot be encoded.
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+	return err
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logging.GetLogger().Error("failed to write dashboard response", "error", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, msg string, err error) {
+	logging.GetLogger().Error(msg, "error", err)
+	http.Error(w, msg, http.StatusInternalServerError)
+}
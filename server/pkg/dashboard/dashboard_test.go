@@ -0,0 +1,214 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package dashboard
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/mcpany/core/proto/admin/v1"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	routerv1 "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type fakeBackend struct {
+	auditErr error
+}
+
+func (fakeBackend) ListServices(context.Context, *pb.ListServicesRequest) (*pb.ListServicesResponse, error) {
+	state := func(id, name, st, errMsg string) *pb.ServiceState {
+		b := pb.ServiceState_builder{
+			Config: configv1.UpstreamServiceConfig_builder{Id: proto.String(id), Name: proto.String(name)}.Build(),
+			Status: proto.String(st),
+		}
+		if errMsg != "" {
+			b.Error = proto.String(errMsg)
+		}
+		return b.Build()
+	}
+	return pb.ListServicesResponse_builder{ServiceStates: []*pb.ServiceState{
+		state("s2", "weather", "OK", ""),
+		state("s1", "github", "ERROR", "connection refused"),
+	}}.Build(), nil
+}
+
+func (fakeBackend) ListTools(context.Context, *pb.ListToolsRequest) (*pb.ListToolsResponse, error) {
+	schema, err := structpb.NewStruct(map[string]any{"type": "object"})
+	if err != nil {
+		return nil, err
+	}
+	return pb.ListToolsResponse_builder{Tools: []*routerv1.Tool{
+		routerv1.Tool_builder{
+			Name:        proto.String("weather.get_forecast"),
+			ServiceId:   proto.String("s2"),
+			Description: proto.String("Gets the forecast"),
+			InputSchema: schema,
+			Annotations: routerv1.ToolAnnotations_builder{ReadOnlyHint: proto.Bool(true)}.Build(),
+		}.Build(),
+	}}.Build(), nil
+}
+
+func (fakeBackend) ListSessions(context.Context, *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	return pb.ListSessionsResponse_builder{Sessions: []*pb.Session{
+		pb.Session_builder{Id: proto.String("abc"), ClientName: proto.String("claude")}.Build(),
+	}}.Build(), nil
+}
+
+func (fakeBackend) ListCircuitBreakers(context.Context, *pb.ListCircuitBreakersRequest) (*pb.ListCircuitBreakersResponse, error) {
+	return pb.ListCircuitBreakersResponse_builder{CircuitBreakers: []*pb.CircuitBreakerState{
+		pb.CircuitBreakerState_builder{
+			ServiceId:           proto.String("s1"),
+			State:               proto.String("open"),
+			ConsecutiveFailures: proto.Int32(5),
+			OpenedAt:            proto.String("2026-10-16T12:00:00Z"),
+		}.Build(),
+	}}.Build(), nil
+}
+
+func (b fakeBackend) ListAuditLogs(_ context.Context, req *pb.ListAuditLogsRequest) (*pb.ListAuditLogsResponse, error) {
+	if b.auditErr != nil {
+		return nil, b.auditErr
+	}
+	entry := func(ts, tool string) *pb.AuditLogEntry {
+		return pb.AuditLogEntry_builder{
+			Timestamp: proto.String(ts),
+			ToolName:  proto.String(tool),
+			UserId:    proto.String("alice"),
+			Arguments: proto.String(`{"city":"Paris"}`),
+			Result:    proto.String(`"sunny"`),
+		}.Build()
+	}
+	entries := []*pb.AuditLogEntry{
+		entry("2026-10-16T11:00:00Z", "weather.get_forecast"),
+		entry("2026-10-16T12:00:00Z", "weather.get_forecast"),
+	}
+	return pb.ListAuditLogsResponse_builder{Entries: entries[:min(len(entries), int(req.GetLimit()))]}.Build(), nil
+}
+
+func get(t *testing.T, h http.Handler, path string, roles ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	ctx := auth.ContextWithUser(req.Context(), "alice")
+	if len(roles) > 0 {
+		ctx = auth.ContextWithRoles(ctx, roles)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req.WithContext(ctx))
+	return rec
+}
+
+func decode[T any](t *testing.T, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var v T
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &v))
+	return v
+}
+
+func TestAssets(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Assets().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "MCP Any Dashboard")
+
+	for _, file := range []string{"/app.js", "/style.css"} {
+		rec := httptest.NewRecorder()
+		Assets().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, file, nil))
+		assert.Equal(t, http.StatusOK, rec.Code, file)
+	}
+}
+
+func TestAPI_Me(t *testing.T) {
+	h := API(fakeBackend{})
+
+	me := decode[Me](t, get(t, h, "/api/me", "viewer"))
+	assert.Equal(t, "alice", me.User)
+	assert.False(t, me.Admin)
+
+	me = decode[Me](t, get(t, h, "/api/me", "admin"))
+	assert.True(t, me.Admin)
+}
+
+func TestAPI_SessionsAndTools(t *testing.T) {
+	h := API(fakeBackend{})
+
+	sessions := decode[[]Session](t, get(t, h, "/api/sessions", "viewer"))
+	assert.Equal(t, []Session{{ID: "abc", ClientName: "claude"}}, sessions)
+
+	tools := decode[[]Tool](t, get(t, h, "/api/tools", "viewer"))
+	require.Len(t, tools, 1)
+	assert.Equal(t, "weather.get_forecast", tools[0].Name)
+	assert.Equal(t, map[string]any{"type": "object"}, tools[0].InputSchema)
+	assert.True(t, tools[0].ReadOnly)
+}
+
+func TestAPI_Calls(t *testing.T) {
+	h := API(fakeBackend{})
+
+	calls := decode[Calls](t, get(t, h, "/api/calls", "viewer"))
+	assert.True(t, calls.AuditEnabled)
+	require.Len(t, calls.Calls, 2)
+	assert.Equal(t, "2026-10-16T12:00:00Z", calls.Calls[0].Timestamp, "newest first")
+	assert.Empty(t, calls.Calls[0].Arguments, "viewers do not see arguments")
+	assert.Empty(t, calls.Calls[0].Result)
+
+	calls = decode[Calls](t, get(t, h, "/api/calls?limit=1", "admin"))
+	require.Len(t, calls.Calls, 1)
+	assert.Equal(t, `{"city":"Paris"}`, calls.Calls[0].Arguments)
+
+	assert.Equal(t, http.StatusBadRequest, get(t, h, "/api/calls?limit=-1", "admin").Code)
+
+	disabled := API(fakeBackend{auditErr: status.Error(codes.FailedPrecondition, "audit logging is not enabled")})
+	calls = decode[Calls](t, get(t, disabled, "/api/calls", "viewer"))
+	assert.False(t, calls.AuditEnabled)
+	assert.Empty(t, calls.Calls)
+}
+
+func TestAPI_Upstreams(t *testing.T) {
+	upstreams := decode[[]Upstream](t, get(t, API(fakeBackend{}), "/api/upstreams", "viewer"))
+	assert.Equal(t, []Upstream{
+		{ID: "s1", Name: "github", Status: "ERROR", Error: "connection refused", Circuit: "open", ConsecutiveFailures: 5, OpenedAt: "2026-10-16T12:00:00Z"},
+		{ID: "s2", Name: "weather", Status: "OK"},
+	}, upstreams)
+}
+
+func TestAPI_Logs(t *testing.T) {
+	srv := httptest.NewServer(API(fakeBackend{}))
+	defer srv.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/logs", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	logging.GlobalBroadcaster.Broadcast(logging.LogEntry{ID: "1", Level: "INFO", Message: "dashboard-test"})
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if line, ok := strings.CutPrefix(scanner.Text(), "data: "); ok && strings.Contains(line, "dashboard-test") {
+			var entry logging.LogEntry
+			require.NoError(t, json.Unmarshal([]byte(line), &entry))
+			assert.Equal(t, "INFO", entry.Level)
+			return
+		}
+	}
+	t.Fatal("the log entry was not streamed")
+}
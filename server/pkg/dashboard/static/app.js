@@ -0,0 +1,292 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// The built-in dashboard. It reads the read-only API under ./api and, for
+// admins, calls the admin API under /v1/admin.
+(() => {
+  'use strict';
+
+  const CREDENTIALS = 'mcpany-dashboard-credentials';
+  const MAX_LOG_LINES = 2000;
+  const REFRESH_MS = 5000;
+
+  const $ = (sel, root = document) => root.querySelector(sel);
+
+  let me = null;
+  let currentTab = 'sessions';
+  let refreshTimer = null;
+  let logSource = null;
+  let logsPaused = false;
+  let tools = [];
+
+  // Credentials are kept for the browser session only.
+  function credentials() {
+    try {
+      return JSON.parse(sessionStorage.getItem(CREDENTIALS)) || null;
+    } catch {
+      return null;
+    }
+  }
+
+  function authHeaders() {
+    const c = credentials();
+    if (!c) return {};
+    if (c.key) return { 'X-API-Key': c.key };
+    return { Authorization: 'Basic ' + c.basic };
+  }
+
+  // EventSource cannot send headers: the credentials go in the query.
+  function authQuery() {
+    const c = credentials();
+    if (!c) return '';
+    if (c.key) return '?api_key=' + encodeURIComponent(c.key);
+    return '?auth_token=' + encodeURIComponent(c.basic);
+  }
+
+  async function request(method, url) {
+    const resp = await fetch(url, {
+      method,
+      headers: { ...authHeaders(), 'X-MCP-Any-CSRF': '1' },
+      credentials: 'same-origin',
+    });
+    if (resp.status === 401 || resp.status === 403) {
+      showLogin(resp.status === 403 ? 'Your user has neither the viewer nor the admin role.' : '');
+      throw new Error('unauthorized');
+    }
+    if (!resp.ok) throw new Error(`${method} ${url}: ${resp.status} ${await resp.text()}`);
+    return resp.status === 204 ? null : resp.json();
+  }
+
+  const api = (path) => request('GET', 'api/' + path);
+
+  function el(tag, attrs = {}, ...children) {
+    const node = document.createElement(tag);
+    for (const [k, v] of Object.entries(attrs)) {
+      if (k === 'class') node.className = v;
+      else if (k.startsWith('on')) node.addEventListener(k.slice(2), v);
+      else node.setAttribute(k, v);
+    }
+    for (const child of children) {
+      if (child != null) node.append(child);
+    }
+    return node;
+  }
+
+  function fill(tbody, rows, empty, columns) {
+    tbody.replaceChildren();
+    if (rows.length === 0) {
+      tbody.append(el('tr', {}, el('td', { colspan: String(columns), class: 'muted' }, empty)));
+      return;
+    }
+    tbody.append(...rows);
+  }
+
+  function adminButton(label, confirmText, action) {
+    return el('td', { class: 'admin-only' }, el('button', {
+      onclick: async () => {
+        if (!confirm(confirmText)) return;
+        try {
+          await action();
+        } catch (err) {
+          alert(err.message);
+        }
+        refresh();
+      },
+    }, label));
+  }
+
+  async function renderSessions() {
+    const sessions = await api('sessions');
+    fill($('#sessions tbody'), sessions.map((s) => el('tr', {},
+      el('td', {}, el('code', {}, s.id)),
+      el('td', {}, s.client_name || '—'),
+      el('td', {}, s.client_version || '—'),
+      el('td', {}, s.protocol_version || '—'),
+      el('td', {}, s.transport || '—'),
+      el('td', {}, s.user_id || '—'),
+      el('td', {}, s.started_at || '—'),
+      el('td', {}, String(s.tool_call_count || 0)),
+      adminButton('Close', `Close session ${s.id}?`,
+        () => request('DELETE', '/v1/admin/sessions/' + encodeURIComponent(s.id))),
+    )), 'No connected sessions.', 9);
+  }
+
+  function renderToolList() {
+    const filter = $('#tool-filter').value.toLowerCase();
+    const list = $('#tool-list');
+    list.replaceChildren();
+    const shown = tools.filter((t) => !filter || t.name.toLowerCase().includes(filter) ||
+      (t.description || '').toLowerCase().includes(filter));
+    if (shown.length === 0) {
+      list.append(el('p', { class: 'muted' }, 'No tools.'));
+      return;
+    }
+    for (const t of shown) {
+      const hints = [t.read_only ? 'read-only' : null, t.destructive ? 'destructive' : null].filter(Boolean);
+      list.append(el('details', { class: 'tool' },
+        el('summary', {},
+          el('strong', {}, t.name), ' ',
+          el('span', { class: 'muted' }, t.service_id), ' ',
+          ...hints.map((h) => el('span', { class: 'badge' }, h))),
+        el('p', {}, t.description || ''),
+        el('pre', {}, JSON.stringify(t.input_schema || {}, null, 2))));
+    }
+  }
+
+  async function renderTools() {
+    tools = await api('tools');
+    renderToolList();
+  }
+
+  async function renderCalls() {
+    const result = await api('calls');
+    $('#calls-disabled').hidden = result.audit_enabled;
+    fill($('#calls tbody'), result.calls.map((c) => {
+      const tool = me.admin && (c.arguments || c.result)
+        ? el('details', {}, el('summary', {}, c.tool_name),
+          el('pre', {}, 'arguments: ' + (c.arguments || '') + '\nresult: ' + (c.result || '')))
+        : c.tool_name;
+      return el('tr', {},
+        el('td', {}, new Date(c.timestamp).toLocaleString()),
+        el('td', {}, tool),
+        el('td', {}, c.user_id || '—'),
+        el('td', {}, c.duration || ''),
+        el('td', { class: 'error' }, c.error || ''));
+    }), 'No calls recorded.', 5);
+  }
+
+  async function renderUpstreams() {
+    const upstreams = await api('upstreams');
+    const circuitClass = { closed: 'ok', 'half-open': 'warn', open: 'bad' };
+    fill($('#upstreams tbody'), upstreams.map((u) => el('tr', {},
+      el('td', {}, u.name || u.id),
+      el('td', { class: u.status === 'OK' ? 'ok' : 'bad', title: u.error || '' }, u.status),
+      el('td', { class: circuitClass[u.circuit] || 'muted' }, u.circuit || 'no calls yet'),
+      el('td', {}, String(u.consecutive_failures || 0)),
+      el('td', {}, u.opened_at ? new Date(u.opened_at).toLocaleString() : ''),
+      u.circuit && u.circuit !== 'closed'
+        ? adminButton('Reset circuit', `Close the circuit breaker of ${u.name || u.id}?`,
+          () => request('POST', '/v1/admin/circuit-breakers/' + encodeURIComponent(u.id) + '/reset'))
+        : el('td', { class: 'admin-only' }),
+    )), 'No upstream services.', 6);
+  }
+
+  function appendLog(entry) {
+    if (logsPaused) return;
+    const level = $('#log-level').value;
+    if (level && entry.level !== level) return;
+    const lines = $('#log-lines');
+    const atBottom = lines.scrollTop + lines.clientHeight >= lines.scrollHeight - 4;
+    const meta = entry.metadata ? ' ' + JSON.stringify(entry.metadata) : '';
+    lines.append(el('div', { class: entry.level || '' },
+      `${entry.timestamp} ${entry.level} ${entry.message}${meta}`));
+    while (lines.childElementCount > MAX_LOG_LINES) lines.firstElementChild.remove();
+    if (atBottom) lines.scrollTop = lines.scrollHeight;
+  }
+
+  function startLogs() {
+    if (logSource) return;
+    $('#log-lines').replaceChildren();
+    logSource = new EventSource('api/logs' + authQuery());
+    logSource.onmessage = (event) => {
+      try {
+        appendLog(JSON.parse(event.data));
+      } catch {
+        // Ignore malformed entries.
+      }
+    };
+  }
+
+  function stopLogs() {
+    if (logSource) logSource.close();
+    logSource = null;
+  }
+
+  const renderers = {
+    sessions: renderSessions,
+    tools: renderTools,
+    calls: renderCalls,
+    upstreams: renderUpstreams,
+  };
+
+  async function refresh() {
+    const render = renderers[currentTab];
+    if (!render || !me) return;
+    try {
+      await render();
+    } catch (err) {
+      if (err.message !== 'unauthorized') console.error(err);
+    }
+  }
+
+  function selectTab(tab) {
+    currentTab = tab;
+    for (const button of document.querySelectorAll('#tabs button')) {
+      button.classList.toggle('active', button.dataset.tab === tab);
+    }
+    for (const section of document.querySelectorAll('section.tab')) {
+      section.hidden = section.id !== tab;
+    }
+    if (tab === 'logs') startLogs(); else stopLogs();
+    clearInterval(refreshTimer);
+    // The tool catalog changes rarely: it is loaded when its tab opens.
+    if (tab !== 'logs' && tab !== 'tools') refreshTimer = setInterval(refresh, REFRESH_MS);
+    refresh();
+  }
+
+  function showLogin(message) {
+    me = null;
+    stopLogs();
+    clearInterval(refreshTimer);
+    document.body.classList.remove('admin');
+    $('#identity').hidden = true;
+    $('#tabs').hidden = true;
+    for (const section of document.querySelectorAll('section.tab')) section.hidden = true;
+    $('#login').hidden = false;
+    $('#login-error').textContent = message || '';
+  }
+
+  async function start() {
+    try {
+      me = await api('me');
+    } catch {
+      return;
+    }
+    $('#login').hidden = true;
+    $('#tabs').hidden = false;
+    $('#identity').hidden = false;
+    $('#user').textContent = me.user || 'anonymous';
+    $('#role').textContent = me.admin ? 'admin' : 'read-only';
+    document.body.classList.toggle('admin', me.admin);
+    selectTab(currentTab);
+  }
+
+  function signIn(creds) {
+    sessionStorage.setItem(CREDENTIALS, JSON.stringify(creds));
+    start();
+  }
+
+  $('#login-key').addEventListener('submit', (event) => {
+    event.preventDefault();
+    signIn({ key: new FormData(event.target).get('key') });
+  });
+  $('#login-user').addEventListener('submit', (event) => {
+    event.preventDefault();
+    const data = new FormData(event.target);
+    signIn({ basic: btoa(`${data.get('username')}:${data.get('password')}`) });
+  });
+  $('#logout').addEventListener('click', () => {
+    sessionStorage.removeItem(CREDENTIALS);
+    showLogin('');
+  });
+  for (const button of document.querySelectorAll('#tabs button')) {
+    button.addEventListener('click', () => selectTab(button.dataset.tab));
+  }
+  $('#tool-filter').addEventListener('input', renderToolList);
+  $('#log-pause').addEventListener('click', (event) => {
+    logsPaused = !logsPaused;
+    event.target.textContent = logsPaused ? 'Resume' : 'Pause';
+  });
+
+  start();
+})();
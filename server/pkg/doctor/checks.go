@@ -0,0 +1,419 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/expiry"
+	"github.com/mcpany/core/server/pkg/util"
+)
+
+const (
+	// CheckTLS is the kind of the expiry checks of the certificates of the services.
+	CheckTLS = "tls"
+	// CheckDNS is the kind of the resolution checks of the hosts of the services.
+	CheckDNS = "dns"
+	// CheckAuth is the kind of the checks of the credentials of the services.
+	CheckAuth = "auth"
+	// CheckLatency is the kind of the checks of the latency budgets of the services.
+	CheckLatency = "latency"
+)
+
+const (
+	defaultLatencySamples = 5
+	defaultDNSLookups     = 3
+	checkTimeout          = 5 * time.Second
+)
+
+// lookupIPAddr resolves a host. It is a variable so that tests can fake the
+// resolver.
+var lookupIPAddr = net.DefaultResolver.LookupIPAddr
+
+// RunAllChecks performs the connectivity checks of RunChecks, then the checks
+// of the certificates, DNS resolution, credentials and latency budget of each
+// enabled service, where they apply.
+//
+// The DNS check runs for every service with a host name. The other checks only
+// run when the service is reachable, since they would repeat its connectivity
+// error otherwise.
+//
+// Parameters:
+//   - ctx: context.Context. The context for the request, used for timeouts and cancellation.
+//   - config: *configv1.McpAnyServerConfig. The server configuration containing upstream service definitions.
+//
+// Returns:
+//   - []CheckResult: The results of the connectivity checks, followed by the results of the other checks.
+//
+// Side Effects:
+//   - Performs network I/O to resolve and connect to upstream services.
+func RunAllChecks(ctx context.Context, config *configv1.McpAnyServerConfig) []CheckResult {
+	results := RunChecks(ctx, config)
+	reachable := make(map[string]bool, len(results))
+	for _, res := range results {
+		reachable[res.ServiceName] = res.Status == StatusOk || res.Status == StatusWarning
+	}
+
+	settings := config.GetGlobalSettings()
+	warnBefore := expiry.DefaultWarnBefore
+	if d := settings.GetExpiryAlerts().GetWarnBefore().AsDuration(); d > 0 {
+		warnBefore = d
+	}
+	lookups := int(settings.GetDoctor().GetDnsLookups())
+	if lookups <= 0 {
+		lookups = defaultDNSLookups
+	}
+
+	for _, service := range config.GetUpstreamServices() {
+		if service.GetDisable() {
+			continue
+		}
+		if host := serviceHost(service); host != "" {
+			results = append(results, runCheck(CheckDNS, service, func() CheckResult {
+				return checkDNS(ctx, host, lookups)
+			}))
+		}
+		if !reachable[service.GetName()] {
+			continue
+		}
+		if res := runCheck(CheckTLS, service, func() CheckResult {
+			return checkCertificates(ctx, service, warnBefore, time.Now())
+		}); res.Status != "" {
+			results = append(results, res)
+		}
+		if addr := serviceURL(service); addr != "" && hasCredentials(service.GetUpstreamAuth()) {
+			results = append(results, runCheck(CheckAuth, service, func() CheckResult {
+				return checkCredentials(ctx, addr, service.GetUpstreamAuth())
+			}))
+		}
+		if budget := latencyBudget(service, settings.GetDoctor()); budget > 0 {
+			samples := int(settings.GetDoctor().GetLatencySamples())
+			if samples <= 0 {
+				samples = defaultLatencySamples
+			}
+			results = append(results, runCheck(CheckLatency, service, func() CheckResult {
+				return checkLatency(ctx, service, budget, samples)
+			}))
+		}
+	}
+	return results
+}
+
+// runCheck runs a check of a kind on a service and fills in the ID, service
+// name and duration of its result. A check that does not apply returns a
+// result without a status.
+func runCheck(kind string, service *configv1.UpstreamServiceConfig, check func() CheckResult) CheckResult {
+	start := time.Now()
+	res := check()
+	res.ID = CheckID(kind, service.GetName())
+	res.ServiceName = service.GetName()
+	res.Duration = time.Since(start)
+	return res
+}
+
+// serviceURL returns the address of a service that answers HTTP requests, or
+// "".
+func serviceURL(service *configv1.UpstreamServiceConfig) string {
+	switch service.WhichServiceConfig() {
+	case configv1.UpstreamServiceConfig_HttpService_case:
+		return service.GetHttpService().GetAddress()
+	case configv1.UpstreamServiceConfig_GraphqlService_case:
+		return service.GetGraphqlService().GetAddress()
+	case configv1.UpstreamServiceConfig_OpenapiService_case:
+		return service.GetOpenapiService().GetAddress()
+	case configv1.UpstreamServiceConfig_McpService_case:
+		return service.GetMcpService().GetHttpConnection().GetHttpAddress()
+	}
+	return ""
+}
+
+// serviceHost returns the host name of a service, or "" if it has none or
+// its address is an IP address.
+func serviceHost(service *configv1.UpstreamServiceConfig) string {
+	var host string
+	switch service.WhichServiceConfig() {
+	case configv1.UpstreamServiceConfig_GrpcService_case:
+		host, _, _ = net.SplitHostPort(service.GetGrpcService().GetAddress())
+	case configv1.UpstreamServiceConfig_WebsocketService_case:
+		host = urlHost(service.GetWebsocketService().GetAddress())
+	case configv1.UpstreamServiceConfig_WebrtcService_case:
+		host = urlHost(service.GetWebrtcService().GetAddress())
+	default:
+		host = urlHost(serviceURL(service))
+	}
+	if net.ParseIP(host) != nil {
+		return ""
+	}
+	return host
+}
+
+func urlHost(address string) string {
+	u, err := url.Parse(address)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// checkCertificates checks that the certificates of a service, its client
+// certificate and the chain of its TLS upstream, are not expired or due to
+// expire within warnBefore. It returns a result without a status for the
+// services that use no TLS.
+func checkCertificates(ctx context.Context, service *configv1.UpstreamServiceConfig, warnBefore time.Duration, now time.Time) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	certs, err := expiry.ServiceCertificates(ctx, service)
+	if err != nil {
+		return CheckResult{Status: StatusError, Message: err.Error(), Error: err}
+	}
+	if len(certs) == 0 {
+		return CheckResult{}
+	}
+	sort.SliceStable(certs, func(i, j int) bool { return certs[i].NotAfter.Before(certs[j].NotAfter) })
+	first := certs[0]
+	what := fmt.Sprintf("Certificate %s (%s)", first.Subject, first.Source)
+	date := first.NotAfter.UTC().Format(time.RFC3339)
+	switch left := first.NotAfter.Sub(now); {
+	case left <= 0:
+		return CheckResult{Status: StatusError, Message: fmt.Sprintf("%s expired on %s", what, date)}
+	case left <= warnBefore:
+		return CheckResult{Status: StatusWarning, Message: fmt.Sprintf("%s expires in %s, on %s", what, left.Round(time.Minute), date)}
+	}
+	return CheckResult{Status: StatusOk, Message: fmt.Sprintf("Certificates valid until %s", date)}
+}
+
+// checkDNS resolves a host several times and checks that the answers agree:
+// that every lookup succeeds, that the lookups share an address, and that the
+// addresses are not a mix of private and public ones, which is the sign of a
+// split-horizon DNS answering differently depending on the network.
+func checkDNS(ctx context.Context, host string, lookups int) CheckResult {
+	var answers [][]string
+	var lastErr error
+	for i := 0; i < lookups; i++ {
+		lookupCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+		addrs, err := lookupIPAddr(lookupCtx, host)
+		cancel()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		ips := make([]string, 0, len(addrs))
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP.String())
+		}
+		slices.Sort(ips)
+		answers = append(answers, slices.Compact(ips))
+	}
+
+	if len(answers) == 0 {
+		return CheckResult{
+			Status:  StatusError,
+			Message: fmt.Sprintf("Failed to resolve %s: %v", host, lastErr),
+			Error:   lastErr,
+		}
+	}
+	if failed := lookups - len(answers); failed > 0 {
+		return CheckResult{
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("%d of %d lookups of %s failed: %v", failed, lookups, host, lastErr),
+			Error:   lastErr,
+		}
+	}
+
+	shared := answers[0]
+	var all []string
+	for _, ips := range answers {
+		shared = slices.DeleteFunc(slices.Clone(shared), func(ip string) bool { return !slices.Contains(ips, ip) })
+		all = append(all, ips...)
+	}
+	slices.Sort(all)
+	all = slices.Compact(all)
+	if len(shared) == 0 {
+		return CheckResult{
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("Lookups of %s returned no common address: %s", host, formatAnswers(answers)),
+		}
+	}
+	var private, public bool
+	for _, ip := range all {
+		parsed := net.ParseIP(ip)
+		if parsed.IsPrivate() || parsed.IsLoopback() || parsed.IsLinkLocalUnicast() {
+			private = true
+		} else {
+			public = true
+		}
+	}
+	if private && public {
+		return CheckResult{
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("%s resolves to both private and public addresses: %s", host, strings.Join(all, ", ")),
+		}
+	}
+	return CheckResult{Status: StatusOk, Message: fmt.Sprintf("%s resolves to %s", host, strings.Join(all, ", "))}
+}
+
+func formatAnswers(answers [][]string) string {
+	parts := make([]string, len(answers))
+	for i, ips := range answers {
+		parts[i] = "[" + strings.Join(ips, ", ") + "]"
+	}
+	return strings.Join(parts, " ")
+}
+
+// hasCredentials reports whether an authentication sends credentials that a
+// probe of the service can verify.
+func hasCredentials(authConfig *configv1.Authentication) bool {
+	switch authConfig.WhichAuthMethod() {
+	case configv1.Authentication_ApiKey_case, configv1.Authentication_BearerToken_case,
+		configv1.Authentication_BasicAuth_case, configv1.Authentication_Oauth2_case:
+		return true
+	}
+	return false
+}
+
+// checkCredentials sends a request with the credentials of a service, then
+// one without, to tell whether the service accepts the credentials or does
+// not ask for any. For OAuth2, applying the credentials also fetches a token.
+func checkCredentials(ctx context.Context, address string, authConfig *configv1.Authentication) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	authenticator, err := auth.NewUpstreamAuthenticator(authConfig)
+	if err != nil {
+		return CheckResult{Status: StatusError, Message: fmt.Sprintf("Invalid credentials configuration: %v", err), Error: err}
+	}
+
+	client := util.NewSafeHTTPClient()
+	client.Timeout = checkTimeout
+	authenticated, err := probe(ctx, client, address, authenticator)
+	if err != nil {
+		return CheckResult{Status: StatusError, Message: err.Error(), Error: err}
+	}
+	if authenticated.StatusCode == http.StatusUnauthorized || authenticated.StatusCode == http.StatusForbidden {
+		return CheckResult{Status: StatusError, Message: fmt.Sprintf("Credentials rejected (%s)", authenticated.Status)}
+	}
+
+	anonymous, err := probe(ctx, client, address, nil)
+	if err != nil {
+		return CheckResult{Status: StatusError, Message: err.Error(), Error: err}
+	}
+	if anonymous.StatusCode == http.StatusUnauthorized || anonymous.StatusCode == http.StatusForbidden {
+		return CheckResult{
+			Status:  StatusOk,
+			Message: fmt.Sprintf("Credentials accepted (%s, %s without them)", authenticated.Status, anonymous.Status),
+		}
+	}
+	return CheckResult{
+		Status:  StatusSkipped,
+		Message: fmt.Sprintf("Credentials not verified: the service answers without them too (%s)", anonymous.Status),
+	}
+}
+
+// probe sends a GET request to an address, authenticated if authenticator is
+// not nil, and returns its response with the body closed.
+func probe(ctx context.Context, client *http.Client, address string, authenticator auth.UpstreamAuthenticator) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %s", util.RedactDSN(err.Error()))
+	}
+	if authenticator != nil {
+		if err := authenticator.Authenticate(req); err != nil {
+			return nil, fmt.Errorf("failed to apply credentials: %w", err)
+		}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	_ = resp.Body.Close()
+	return resp, nil
+}
+
+// latencyBudget returns the latency budget of a service, defaulting to the
+// one of the doctor settings, or 0 if there is none.
+func latencyBudget(service *configv1.UpstreamServiceConfig, settings *configv1.DoctorConfig) time.Duration {
+	if d := service.GetLatencyBudget().AsDuration(); d > 0 {
+		return d
+	}
+	return settings.GetLatencyBudget().AsDuration()
+}
+
+// checkLatency measures the latency of a service over several requests, or
+// TCP connections for gRPC services, and warns when its p95 exceeds the
+// budget.
+func checkLatency(ctx context.Context, service *configv1.UpstreamServiceConfig, budget time.Duration, samples int) CheckResult {
+	var sample func(context.Context) error
+	if addr := serviceURL(service); addr != "" {
+		authenticator, err := auth.NewUpstreamAuthenticator(service.GetUpstreamAuth())
+		if err != nil {
+			return CheckResult{Status: StatusSkipped, Message: fmt.Sprintf("Invalid credentials configuration: %v", err), Error: err}
+		}
+		client := util.NewSafeHTTPClient()
+		client.Timeout = checkTimeout
+		sample = func(ctx context.Context) error {
+			_, err := probe(ctx, client, addr, authenticator)
+			return err
+		}
+	} else if service.HasGrpcService() {
+		dialer := newCheckDialer(checkTimeout)
+		sample = func(ctx context.Context) error {
+			conn, err := dialer.DialContext(ctx, "tcp", service.GetGrpcService().GetAddress())
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		}
+	} else {
+		return CheckResult{Status: StatusSkipped, Message: "No latency probe for this service type"}
+	}
+
+	var latencies []time.Duration
+	var lastErr error
+	for i := 0; i < samples; i++ {
+		sampleCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+		start := time.Now()
+		err := sample(sampleCtx)
+		elapsed := time.Since(start)
+		cancel()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		latencies = append(latencies, elapsed)
+	}
+	if len(latencies) == 0 {
+		return CheckResult{Status: StatusSkipped, Message: fmt.Sprintf("No latency sample succeeded: %v", lastErr), Error: lastErr}
+	}
+
+	p95 := percentile(latencies, 0.95).Round(time.Millisecond)
+	if p95 > budget {
+		return CheckResult{
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("p95 latency %s exceeds the budget of %s (%d samples)", p95, budget, len(latencies)),
+		}
+	}
+	return CheckResult{
+		Status:  StatusOk,
+		Message: fmt.Sprintf("p95 latency %s within the budget of %s (%d samples)", p95, budget, len(latencies)),
+	}
+}
+
+// percentile returns the nearest-rank percentile p, between 0 and 1, of
+// latencies.
+func percentile(latencies []time.Duration, p float64) time.Duration {
+	sorted := slices.Clone(latencies)
+	slices.Sort(sorted)
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
@@ -0,0 +1,170 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func fakeResolver(t *testing.T, answers ...[]string) {
+	t.Helper()
+	original := lookupIPAddr
+	t.Cleanup(func() { lookupIPAddr = original })
+	i := 0
+	lookupIPAddr = func(context.Context, string) ([]net.IPAddr, error) {
+		answer := answers[i%len(answers)]
+		i++
+		if answer == nil {
+			return nil, errors.New("no such host")
+		}
+		addrs := make([]net.IPAddr, len(answer))
+		for j, ip := range answer {
+			addrs[j] = net.IPAddr{IP: net.ParseIP(ip)}
+		}
+		return addrs, nil
+	}
+}
+
+func TestCheckDNS(t *testing.T) {
+	tests := []struct {
+		name    string
+		answers [][]string
+		status  Status
+		message string
+	}{
+		{"consistent", [][]string{{"93.184.216.34", "93.184.216.35"}, {"93.184.216.35", "93.184.216.34"}}, StatusOk, "api.example.com resolves to 93.184.216.34, 93.184.216.35"},
+		{"round robin", [][]string{{"93.184.216.34", "93.184.216.35"}, {"93.184.216.35", "93.184.216.36"}}, StatusOk, "api.example.com resolves to 93.184.216.34, 93.184.216.35, 93.184.216.36"},
+		{"failing", [][]string{nil}, StatusError, "Failed to resolve api.example.com: no such host"},
+		{"flaky", [][]string{{"93.184.216.34"}, nil, {"93.184.216.34"}}, StatusWarning, "1 of 3 lookups of api.example.com failed: no such host"},
+		{"inconsistent", [][]string{{"93.184.216.34"}, {"93.184.216.35"}}, StatusWarning, "Lookups of api.example.com returned no common address: [93.184.216.34] [93.184.216.35] [93.184.216.34]"},
+		{"split horizon", [][]string{{"10.0.0.5", "93.184.216.34"}}, StatusWarning, "api.example.com resolves to both private and public addresses: 10.0.0.5, 93.184.216.34"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeResolver(t, tt.answers...)
+			res := checkDNS(context.Background(), "api.example.com", 3)
+			assert.Equal(t, tt.status, res.Status)
+			assert.Equal(t, tt.message, res.Message)
+		})
+	}
+}
+
+func TestCheckCertificates(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+	notAfter := server.Certificate().NotAfter
+	service := configv1.UpstreamServiceConfig_builder{
+		Name:        proto.String("api"),
+		HttpService: configv1.HttpUpstreamService_builder{Address: proto.String(server.URL)}.Build(),
+	}.Build()
+	warnBefore := 14 * 24 * time.Hour
+
+	res := checkCertificates(context.Background(), service, warnBefore, notAfter.Add(-30*24*time.Hour))
+	assert.Equal(t, StatusOk, res.Status)
+	assert.Equal(t, "Certificates valid until "+notAfter.UTC().Format(time.RFC3339), res.Message)
+
+	res = checkCertificates(context.Background(), service, warnBefore, notAfter.Add(-48*time.Hour))
+	assert.Equal(t, StatusWarning, res.Status)
+	assert.Contains(t, res.Message, "expires in 48h0m0s")
+
+	res = checkCertificates(context.Background(), service, warnBefore, notAfter.Add(time.Hour))
+	assert.Equal(t, StatusError, res.Status)
+	assert.Contains(t, res.Message, "expired on "+notAfter.UTC().Format(time.RFC3339))
+
+	// Services without TLS have no certificate to check.
+	plain := configv1.UpstreamServiceConfig_builder{
+		Name:        proto.String("plain"),
+		HttpService: configv1.HttpUpstreamService_builder{Address: proto.String("http://127.0.0.1:1")}.Build(),
+	}.Build()
+	assert.Empty(t, checkCertificates(context.Background(), plain, warnBefore, time.Now()).Status)
+}
+
+func TestCheckCredentials(t *testing.T) {
+	t.Setenv("MCPANY_ALLOW_LOOPBACK_RESOURCES", "true")
+	protected := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer protected.Close()
+	open := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer open.Close()
+	bearer := func(token string) *configv1.Authentication {
+		return configv1.Authentication_builder{
+			BearerToken: configv1.BearerTokenAuth_builder{
+				Token: configv1.SecretValue_builder{PlainText: proto.String(token)}.Build(),
+			}.Build(),
+		}.Build()
+	}
+
+	res := checkCredentials(context.Background(), protected.URL, bearer("secret"))
+	assert.Equal(t, StatusOk, res.Status)
+	assert.Equal(t, "Credentials accepted (200 OK, 401 Unauthorized without them)", res.Message)
+
+	res = checkCredentials(context.Background(), protected.URL, bearer("expired"))
+	assert.Equal(t, StatusError, res.Status)
+	assert.Equal(t, "Credentials rejected (401 Unauthorized)", res.Message)
+
+	res = checkCredentials(context.Background(), open.URL, bearer("secret"))
+	assert.Equal(t, StatusSkipped, res.Status)
+	assert.Equal(t, "Credentials not verified: the service answers without them too (200 OK)", res.Message)
+}
+
+func TestRunAllChecks_Latency(t *testing.T) {
+	t.Setenv("MCPANY_ALLOW_LOOPBACK_RESOURCES", "true")
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer server.Close()
+	config := func(budget time.Duration) *configv1.McpAnyServerConfig {
+		return configv1.McpAnyServerConfig_builder{
+			GlobalSettings: configv1.GlobalSettings_builder{
+				Doctor: configv1.DoctorConfig_builder{
+					LatencyBudget:  durationpb.New(time.Minute),
+					LatencySamples: proto.Int32(3),
+				}.Build(),
+			}.Build(),
+			UpstreamServices: []*configv1.UpstreamServiceConfig{
+				configv1.UpstreamServiceConfig_builder{
+					Name:          proto.String("api"),
+					HttpService:   configv1.HttpUpstreamService_builder{Address: proto.String(server.URL)}.Build(),
+					LatencyBudget: durationpb.New(budget),
+				}.Build(),
+			},
+		}.Build()
+	}
+
+	results := RunAllChecks(context.Background(), config(time.Millisecond))
+	require.Len(t, results, 2)
+	assert.Equal(t, "upstream/api", results[0].ID)
+	assert.Equal(t, "latency/api", results[1].ID)
+	assert.Equal(t, "api (latency)", results[1].Label())
+	assert.Equal(t, StatusWarning, results[1].Status)
+	assert.Contains(t, results[1].Message, "exceeds the budget of 1ms (3 samples)")
+
+	// Without a budget of its own, the service has the one of the doctor settings.
+	results = RunAllChecks(context.Background(), config(0))
+	require.Len(t, results, 2)
+	assert.Equal(t, StatusOk, results[1].Status)
+	assert.Contains(t, results[1].Message, "within the budget of 1m0s (3 samples)")
+}
+
+func TestPercentile(t *testing.T) {
+	latencies := []time.Duration{5, 1, 4, 2, 3}
+	assert.Equal(t, time.Duration(5), percentile(latencies, 0.95))
+	assert.Equal(t, time.Duration(3), percentile(latencies, 0.5))
+	assert.Equal(t, time.Duration(1), percentile(latencies, 0))
+}
@@ -43,6 +43,9 @@ const (
 //
 // It aggregates the status, any message, and potential error encountered during the check.
 type CheckResult struct {
+	// ID identifies the check in reports, as "<kind>/<subject>", such as "upstream/weather".
+	// It only depends on the configuration, so that it is stable across runs.
+	ID string
 	// ServiceName is the name of the service being checked.
 	ServiceName string
 	// Status is the outcome of the check (OK, WARNING, ERROR, SKIPPED).
@@ -51,6 +54,25 @@ type CheckResult struct {
 	Message string
 	// Error contains the underlying error object if the check failed.
 	Error error
+	// Duration is how long the check took.
+	Duration time.Duration
+	// Fixes are the automated remediations offered for the problem found, attached by OfferFixes.
+	Fixes []Fix
+}
+
+// CheckUpstream is the kind of the connectivity checks of the upstream services.
+const CheckUpstream = "upstream"
+
+// CheckID returns the ID of a check of a kind on a subject, such as a service.
+//
+// Parameters:
+//   - kind: string. The kind of the check, such as CheckUpstream.
+//   - subject: string. What is checked, such as the name of a service.
+//
+// Returns:
+//   - string: The ID of the check.
+func CheckID(kind, subject string) string {
+	return kind + "/" + subject
 }
 
 // RunChecks performs connectivity and health checks on the provided configuration.
@@ -74,8 +96,10 @@ func RunChecks(ctx context.Context, config *configv1.McpAnyServerConfig) []Check
 
 	// Check upstream services
 	for _, service := range services {
+		id := CheckID(CheckUpstream, service.GetName())
 		if service.GetDisable() {
 			results = append(results, CheckResult{
+				ID:          id,
 				ServiceName: service.GetName(),
 				Status:      StatusSkipped,
 				Message:     "Service is disabled",
@@ -83,8 +107,11 @@ func RunChecks(ctx context.Context, config *configv1.McpAnyServerConfig) []Check
 			continue
 		}
 
+		start := time.Now()
 		res := CheckService(ctx, service)
+		res.ID = id
 		res.ServiceName = service.GetName()
+		res.Duration = time.Since(start)
 		results = append(results, res)
 	}
 
@@ -345,18 +372,7 @@ func checkGRPCService(ctx context.Context, s *configv1.GrpcUpstreamService) Chec
 		timeout = time.Until(deadline)
 	}
 
-	dialer := util.NewSafeDialer()
-	// Check environment variables to allow unsafe connections if configured (consistent with other components)
-	if os.Getenv("MCPANY_DANGEROUS_ALLOW_LOCAL_IPS") == util.TrueStr || os.Getenv("MCPANY_ALLOW_LOOPBACK_RESOURCES") == util.TrueStr {
-		dialer.AllowLoopback = true
-		dialer.AllowPrivate = true
-	}
-	if os.Getenv("MCPANY_ALLOW_PRIVATE_NETWORK_RESOURCES") == util.TrueStr {
-		dialer.AllowPrivate = true
-	}
-	dialer.Dialer = &net.Dialer{Timeout: timeout}
-
-	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	conn, err := newCheckDialer(timeout).DialContext(ctx, "tcp", net.JoinHostPort(host, port))
 	if err != nil {
 		return CheckResult{
 			Status:  StatusError,
@@ -511,6 +527,22 @@ func checkFilesystemService(_ context.Context, s *configv1.FilesystemUpstreamSer
 	}
 }
 
+// newCheckDialer returns the dialer of the TCP checks, which only reaches
+// local and private addresses when the environment allows it.
+func newCheckDialer(timeout time.Duration) *util.SafeDialer {
+	dialer := util.NewSafeDialer()
+	// Check environment variables to allow unsafe connections if configured (consistent with other components)
+	if os.Getenv("MCPANY_DANGEROUS_ALLOW_LOCAL_IPS") == util.TrueStr || os.Getenv("MCPANY_ALLOW_LOOPBACK_RESOURCES") == util.TrueStr {
+		dialer.AllowLoopback = true
+		dialer.AllowPrivate = true
+	}
+	if os.Getenv("MCPANY_ALLOW_PRIVATE_NETWORK_RESOURCES") == util.TrueStr {
+		dialer.AllowPrivate = true
+	}
+	dialer.Dialer = &net.Dialer{Timeout: timeout}
+	return dialer
+}
+
 // applyAuthentication applies the given authentication configuration to the request.
 // It resolves secrets using util.ResolveSecret.
 func applyAuthentication(ctx context.Context, req *http.Request, auth *configv1.Authentication) error {
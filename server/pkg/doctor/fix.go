@@ -0,0 +1,508 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/util"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// FixEnvPlaceholder adds placeholders for the missing environment variables to the environment file.
+	FixEnvPlaceholder = "env-placeholder"
+	// FixAddressScheme adds the missing scheme of an address, or corrects a scheme that does not match its port.
+	FixAddressScheme = "address-scheme"
+	// FixPinOpenAPISpec fetches the OpenAPI spec of a service and pins it in the configuration.
+	FixPinOpenAPISpec = "pin-openapi-spec"
+	// FixCredentialStub creates a placeholder for a missing credential.
+	FixCredentialStub = "credential-stub"
+)
+
+// environmentCheck is the name of the result of the check of the environment
+// variables that the configuration files reference, of the kind CheckConfig.
+const environmentCheck = "environment"
+
+// CheckConfig is the kind of the checks of the configuration files.
+const CheckConfig = "config"
+
+// credentialPlaceholder is the content of the credential stubs.
+const credentialPlaceholder = "REPLACE_WITH_SECRET"
+
+// maxSpecSize bounds the size of the OpenAPI specs that are pinned.
+const maxSpecSize = 10 << 20
+
+// Fix is an automated remediation that a check offers for the problem it
+// found.
+type Fix struct {
+	// ID is the kind of the fix, such as FixEnvPlaceholder.
+	ID string
+	// ServiceName is the name of the service the fix applies to, or empty for the whole configuration.
+	ServiceName string
+	// Description tells what the fix changes.
+	Description string
+
+	apply func(ctx context.Context, ws *Workspace) error
+}
+
+// Apply makes the change of the fix in the workspace. Nothing is written to
+// disk until the workspace is written.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the fix, used by the fixes that fetch content.
+//   - ws: *Workspace. The workspace that holds the changed files.
+//
+// Returns:
+//   - error: An error if the fix cannot be applied.
+//
+// Side Effects:
+//   - May perform network I/O, to fetch an OpenAPI spec.
+func (f Fix) Apply(ctx context.Context, ws *Workspace) error {
+	if f.apply == nil {
+		return fmt.Errorf("fix %s has nothing to apply", f.ID)
+	}
+	return f.apply(ctx, ws)
+}
+
+// OfferFixes attaches the fixes that apply to the results of the checks. The
+// missing environment variables referenced by the configuration files are
+// reported in an additional result.
+//
+// Parameters:
+//   - ws: *Workspace. The workspace over the configuration files, used to locate the values to fix.
+//   - config: *configv1.McpAnyServerConfig. The configuration that was checked.
+//   - results: []CheckResult. The results of RunChecks.
+//
+// Returns:
+//   - []CheckResult: The results, with their fixes.
+func OfferFixes(ws *Workspace, config *configv1.McpAnyServerConfig, results []CheckResult) []CheckResult {
+	// The fixes are attached to the connectivity checks of the services.
+	byName := make(map[string]int, len(results))
+	for i, res := range results {
+		if kind, _ := splitCheckID(res); kind == CheckUpstream {
+			byName[res.ServiceName] = i
+		}
+	}
+	var missingEnv []string
+	for _, service := range config.GetUpstreamServices() {
+		i, ok := byName[service.GetName()]
+		if !ok || service.GetDisable() {
+			continue
+		}
+		results[i].Fixes = append(results[i].Fixes, serviceFixes(ws, service, results[i])...)
+	}
+	for _, path := range ws.configs {
+		b, err := ws.content(path)
+		if err != nil {
+			continue
+		}
+		missingEnv = append(missingEnv, missingEnvVars(b)...)
+	}
+	missingEnv = dedupe(missingEnv)
+	if len(missingEnv) > 0 {
+		results = append(results, CheckResult{
+			ID:          CheckID(CheckConfig, environmentCheck),
+			ServiceName: environmentCheck,
+			Status:      StatusWarning,
+			Message:     "Missing environment variables: " + strings.Join(missingEnv, ", "),
+			Fixes:       []Fix{envPlaceholderFix("", missingEnv, ws.EnvFile())},
+		})
+	}
+	return results
+}
+
+func serviceFixes(ws *Workspace, service *configv1.UpstreamServiceConfig, res CheckResult) []Fix {
+	var fixes []Fix
+	for _, field := range addressFields(service) {
+		if fix, ok := addressFix(ws, service.GetName(), field); ok {
+			fixes = append(fixes, fix)
+		}
+	}
+	if openapi := service.GetOpenapiService(); openapi.GetSpecUrl() != "" && res.Status != StatusError {
+		fixes = append(fixes, pinSpecFix(ws, service.GetName(), openapi.GetSpecUrl(), service.GetUpstreamAuth())...)
+	}
+	return append(fixes, credentialFixes(ws, service)...)
+}
+
+// missingEnvPattern matches the references to environment variables that the
+// configuration loader expands: ${VAR} without a default and $VAR.
+var missingEnvPattern = regexp.MustCompile(`\$(?:\{([A-Za-z_][A-Za-z0-9_]*)\}|([A-Za-z_][A-Za-z0-9_]*))`)
+
+// missingEnvVars returns the environment variables referenced by a
+// configuration file that are not set.
+func missingEnvVars(b []byte) []string {
+	var names []string
+	for _, m := range missingEnvPattern.FindAllSubmatch(b, -1) {
+		name := string(m[1])
+		if name == "" {
+			name = string(m[2])
+		}
+		if _, ok := os.LookupEnv(name); !ok && util.IsEnvVarAllowed(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func dedupe(names []string) []string {
+	sort.Strings(names)
+	out := names[:0]
+	for i, name := range names {
+		if i == 0 || name != names[i-1] {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// envPlaceholderFix adds empty assignments of environment variables to the
+// environment file, which the server loads on start, to be filled in.
+func envPlaceholderFix(serviceName string, names []string, envFile string) Fix {
+	id := FixEnvPlaceholder
+	if serviceName != "" {
+		id = FixCredentialStub
+	}
+	return Fix{
+		ID:          id,
+		ServiceName: serviceName,
+		Description: fmt.Sprintf("Add placeholders for %s to %s", strings.Join(names, ", "), envFile),
+		apply: func(_ context.Context, ws *Workspace) error {
+			b, err := ws.content(ws.EnvFile())
+			if err != nil {
+				return err
+			}
+			out := bytes.Clone(b)
+			added := false
+			for _, name := range names {
+				if envFileDefines(out, name) {
+					continue
+				}
+				if !added {
+					if len(out) > 0 && !bytes.HasSuffix(out, []byte("\n")) {
+						out = append(out, '\n')
+					}
+					out = append(out, "# Added by mcpany doctor --fix: set the values below.\n"...)
+					added = true
+				}
+				out = append(out, name+"=\n"...)
+			}
+			return ws.setContent(ws.EnvFile(), out)
+		},
+	}
+}
+
+func envFileDefines(b []byte, name string) bool {
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimPrefix(strings.TrimSpace(line), "export ")
+		if strings.HasPrefix(strings.TrimSpace(line), name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// addressField is an address of a service, by its path in the service
+// mapping.
+type addressField struct {
+	keys      []string
+	value     string
+	websocket bool
+}
+
+func addressFields(service *configv1.UpstreamServiceConfig) []addressField {
+	switch service.WhichServiceConfig() {
+	case configv1.UpstreamServiceConfig_HttpService_case:
+		return []addressField{{keys: []string{"http_service", "address"}, value: service.GetHttpService().GetAddress()}}
+	case configv1.UpstreamServiceConfig_GraphqlService_case:
+		return []addressField{{keys: []string{"graphql_service", "address"}, value: service.GetGraphqlService().GetAddress()}}
+	case configv1.UpstreamServiceConfig_WebrtcService_case:
+		return []addressField{{keys: []string{"webrtc_service", "address"}, value: service.GetWebrtcService().GetAddress()}}
+	case configv1.UpstreamServiceConfig_WebsocketService_case:
+		return []addressField{{keys: []string{"websocket_service", "address"}, value: service.GetWebsocketService().GetAddress(), websocket: true}}
+	case configv1.UpstreamServiceConfig_OpenapiService_case:
+		return []addressField{
+			{keys: []string{"openapi_service", "address"}, value: service.GetOpenapiService().GetAddress()},
+			{keys: []string{"openapi_service", "spec_url"}, value: service.GetOpenapiService().GetSpecUrl()},
+		}
+	case configv1.UpstreamServiceConfig_McpService_case:
+		if conn := service.GetMcpService().GetHttpConnection(); conn != nil {
+			return []addressField{{keys: []string{"mcp_service", "http_connection", "http_address"}, value: conn.GetHttpAddress()}}
+		}
+	}
+	return nil
+}
+
+// correctAddress adds the scheme that an address without one needs, and
+// corrects a scheme that does not match the well-known port of the address,
+// such as https on port 80.
+func correctAddress(addr string, websocket bool) (string, bool) {
+	plain, secure := "http", "https"
+	if websocket {
+		plain, secure = "ws", "wss"
+	}
+	if addr == "" || strings.ContainsAny(addr, "${} ") {
+		return "", false
+	}
+	schemeEnd := strings.Index(addr, "://")
+	if schemeEnd < 0 {
+		host := addr
+		if i := strings.IndexAny(host, "/?#"); i >= 0 {
+			host = host[:i]
+		}
+		if host == "" {
+			return "", false
+		}
+		scheme := plain
+		if _, port, err := net.SplitHostPort(host); err == nil && port == "443" {
+			scheme = secure
+		}
+		return scheme + "://" + addr, true
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return "", false
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if websocket {
+		switch scheme {
+		case "http":
+			scheme = "ws"
+		case "https":
+			scheme = "wss"
+		}
+	}
+	switch {
+	case scheme == plain && u.Port() == "443":
+		scheme = secure
+	case scheme == secure && u.Port() == "80":
+		scheme = plain
+	}
+	if scheme == u.Scheme {
+		return "", false
+	}
+	return scheme + addr[schemeEnd:], true
+}
+
+func addressFix(ws *Workspace, serviceName string, field addressField) (Fix, bool) {
+	corrected, ok := correctAddress(field.value, field.websocket)
+	if !ok {
+		return Fix{}, false
+	}
+	// Only the values written literally are fixed: an address that comes from
+	// an environment variable is fixed where the variable is set.
+	svc, found := ws.findService(serviceName)
+	if !found {
+		return Fix{}, false
+	}
+	if _, node := lookupNode(svc.node, field.keys...); node == nil || node.Value != field.value {
+		return Fix{}, false
+	}
+	key := strings.Join(field.keys, ".")
+	return Fix{
+		ID:          FixAddressScheme,
+		ServiceName: serviceName,
+		Description: fmt.Sprintf("Change %s from %q to %q", key, field.value, corrected),
+		apply: func(_ context.Context, ws *Workspace) error {
+			svc, found := ws.findService(serviceName)
+			if !found {
+				return fmt.Errorf("service %q not found in the configuration files", serviceName)
+			}
+			_, node := lookupNode(svc.node, field.keys...)
+			if node == nil || node.Value != field.value {
+				return fmt.Errorf("%s of service %q changed", key, serviceName)
+			}
+			return ws.replaceRange(svc.path, node, node, quoteScalar(node, corrected))
+		},
+	}, true
+}
+
+// pinSpecFix replaces the spec_url of an OpenAPI service by the content of
+// the spec, so that the tools of the service no longer depend on fetching it
+// on start, nor change when it changes.
+func pinSpecFix(ws *Workspace, serviceName, specURL string, auth *configv1.Authentication) []Fix {
+	svc, found := ws.findService(serviceName)
+	if !found {
+		return nil
+	}
+	if key, _ := lookupNode(svc.node, "openapi_service", "spec_url"); key == nil {
+		return nil
+	}
+	return []Fix{{
+		ID:          FixPinOpenAPISpec,
+		ServiceName: serviceName,
+		Description: fmt.Sprintf("Fetch the OpenAPI spec from %s and pin it in spec_content", specURL),
+		apply: func(ctx context.Context, ws *Workspace) error {
+			spec, err := fetchSpec(ctx, specURL, auth)
+			if err != nil {
+				return err
+			}
+			svc, found := ws.findService(serviceName)
+			if !found {
+				return fmt.Errorf("service %q not found in the configuration files", serviceName)
+			}
+			_, openapi := lookupNode(svc.node, "openapi_service")
+			key, value := lookupNode(openapi, "spec_url")
+			if key == nil {
+				return fmt.Errorf("spec_url of service %q not found", serviceName)
+			}
+			return ws.replaceRange(svc.path, key, value, specContentEntry(openapi, key, spec))
+		},
+	}}
+}
+
+func fetchSpec(ctx context.Context, specURL string, auth *configv1.Authentication) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, specURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid spec URL: %w", err)
+	}
+	if err := applyAuthentication(ctx, req, auth); err != nil {
+		return "", fmt.Errorf("failed to apply authentication: %w", err)
+	}
+	resp, err := util.NewSafeHTTPClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch the OpenAPI spec: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch the OpenAPI spec: %s", resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxSpecSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read the OpenAPI spec: %w", err)
+	}
+	if len(b) > maxSpecSize {
+		return "", fmt.Errorf("the OpenAPI spec is larger than %d bytes", maxSpecSize)
+	}
+	if len(bytes.TrimSpace(b)) == 0 {
+		return "", fmt.Errorf("the OpenAPI spec at %s is empty", specURL)
+	}
+	return string(b), nil
+}
+
+// specContentEntry writes the spec_content entry that replaces a spec_url
+// entry: a literal block in a block mapping, and a quoted string in a flow
+// mapping or when the spec cannot be written as a literal block.
+func specContentEntry(mapping, key *yaml.Node, spec string) string {
+	name := quoteScalar(key, "spec_content")
+	if mapping.Style&yaml.FlowStyle == 0 && literalBlockSafe(spec) {
+		indent := strings.Repeat(" ", key.Column+1)
+		var sb strings.Builder
+		sb.WriteString(name + ": |")
+		for _, line := range strings.Split(strings.TrimRight(spec, "\n"), "\n") {
+			sb.WriteString("\n")
+			if line != "" {
+				sb.WriteString(indent + line)
+			}
+		}
+		return sb.String()
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(spec)
+	return name + ": " + strings.TrimSuffix(buf.String(), "\n")
+}
+
+func literalBlockSafe(s string) bool {
+	if strings.HasPrefix(s, " ") || strings.HasPrefix(s, "\t") || strings.HasPrefix(s, "\n") {
+		return false
+	}
+	for _, r := range s {
+		if r == '\t' || r == '\n' {
+			continue
+		}
+		if r < 0x20 || r == 0x7f || r == '\u2028' || r == '\u2029' || r == 0x85 || r == '\ufeff' {
+			return false
+		}
+	}
+	return true
+}
+
+// credentialFixes creates placeholders for the secrets of a service that
+// point to a file or an environment variable that does not exist.
+func credentialFixes(ws *Workspace, service *configv1.UpstreamServiceConfig) []Fix {
+	var files, envVars []string
+	walkSecrets(service.ProtoReflect(), func(secret *configv1.SecretValue) {
+		switch secret.WhichValue() {
+		case configv1.SecretValue_FilePath_case:
+			if path := secret.GetFilePath(); path != "" && !ws.exists(path) {
+				files = append(files, path)
+			}
+		case configv1.SecretValue_EnvironmentVariable_case:
+			if name := secret.GetEnvironmentVariable(); name != "" && util.IsEnvVarAllowed(name) {
+				if _, ok := os.LookupEnv(name); !ok {
+					envVars = append(envVars, name)
+				}
+			}
+		}
+	})
+	var fixes []Fix
+	for _, path := range dedupe(files) {
+		fixes = append(fixes, Fix{
+			ID:          FixCredentialStub,
+			ServiceName: service.GetName(),
+			Description: fmt.Sprintf("Create the credential file %s with a placeholder", path),
+			apply: func(_ context.Context, ws *Workspace) error {
+				if ws.exists(path) {
+					return nil
+				}
+				return ws.setContent(path, []byte(credentialPlaceholder+"\n"))
+			},
+		})
+	}
+	if envVars = dedupe(envVars); len(envVars) > 0 {
+		fixes = append(fixes, envPlaceholderFix(service.GetName(), envVars, ws.EnvFile()))
+	}
+	return fixes
+}
+
+var secretValueName = (&configv1.SecretValue{}).ProtoReflect().Descriptor().FullName()
+
+// walkSecrets calls fn for every SecretValue set in a message.
+func walkSecrets(m protoreflect.Message, fn func(*configv1.SecretValue)) {
+	if m.Descriptor().FullName() == secretValueName {
+		if secret, ok := m.Interface().(*configv1.SecretValue); ok {
+			fn(secret)
+		}
+		return
+	}
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Message() == nil {
+			return true
+		}
+		switch {
+		case fd.IsList():
+			for i := 0; i < v.List().Len(); i++ {
+				walkSecrets(v.List().Get(i).Message(), fn)
+			}
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					walkSecrets(mv.Message(), fn)
+					return true
+				})
+			}
+		default:
+			walkSecrets(v.Message(), fn)
+		}
+		return true
+	})
+}
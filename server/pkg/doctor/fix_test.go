@@ -0,0 +1,202 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+const fixSpec = `openapi: 3.0.0
+info:
+  title: Pets
+
+paths: {}
+`
+
+func TestCorrectAddress(t *testing.T) {
+	tests := []struct {
+		addr      string
+		websocket bool
+		want      string
+	}{
+		{addr: "localhost:8080", want: "http://localhost:8080"},
+		{addr: "api.example.com:443/v1", want: "https://api.example.com:443/v1"},
+		{addr: "https://api.example.com:80", want: "http://api.example.com:80"},
+		{addr: "http://api.example.com:443", want: "https://api.example.com:443"},
+		{addr: "https://api.example.com"},
+		{addr: "http://api.example.com:8080"},
+		{addr: "${API_ADDRESS}"},
+		{addr: "https://chat.example.com/ws", websocket: true, want: "wss://chat.example.com/ws"},
+		{addr: "chat.example.com:443", websocket: true, want: "wss://chat.example.com:443"},
+		{addr: "ws://chat.example.com"},
+	}
+	for _, tt := range tests {
+		got, ok := correctAddress(tt.addr, tt.websocket)
+		assert.Equal(t, tt.want != "", ok, tt.addr)
+		assert.Equal(t, tt.want, got, tt.addr)
+	}
+}
+
+func TestOfferFixes(t *testing.T) {
+	t.Setenv("MCPANY_ALLOW_LOOPBACK_RESOURCES", "true")
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(fixSpec))
+	}))
+	defer ts.Close()
+
+	fs := afero.NewMemMapFs()
+	configYAML := `upstream_services:
+  - name: weather
+    http_service:
+      address: 'localhost:8080'
+    upstream_auth:
+      bearer_token:
+        token:
+          file_path: secrets/weather-token
+  - name: pets
+    openapi_service:
+      address: ${PETS_ADDRESS}
+      spec_url: ` + ts.URL + `/openapi.yaml
+  - name: search
+    http_service:
+      address: https://search.example.com
+    upstream_auth:
+      api_key:
+        param_name: X-Key
+        value:
+          environment_variable: DOCTOR_FIX_SEARCH_KEY
+`
+	require.NoError(t, afero.WriteFile(fs, "config.yaml", []byte(configYAML), 0o644))
+	require.NoError(t, afero.WriteFile(fs, ".env", []byte("EXISTING=1"), 0o600))
+
+	cfg := configv1.McpAnyServerConfig_builder{
+		UpstreamServices: []*configv1.UpstreamServiceConfig{
+			configv1.UpstreamServiceConfig_builder{
+				Name:        proto.String("weather"),
+				HttpService: configv1.HttpUpstreamService_builder{Address: proto.String("localhost:8080")}.Build(),
+				UpstreamAuth: configv1.Authentication_builder{
+					BearerToken: configv1.BearerTokenAuth_builder{
+						Token: configv1.SecretValue_builder{FilePath: proto.String("secrets/weather-token")}.Build(),
+					}.Build(),
+				}.Build(),
+			}.Build(),
+			configv1.UpstreamServiceConfig_builder{
+				Name: proto.String("pets"),
+				OpenapiService: configv1.OpenapiUpstreamService_builder{
+					Address: proto.String("${PETS_ADDRESS}"),
+					SpecUrl: proto.String(ts.URL + "/openapi.yaml"),
+				}.Build(),
+			}.Build(),
+			configv1.UpstreamServiceConfig_builder{
+				Name:        proto.String("search"),
+				HttpService: configv1.HttpUpstreamService_builder{Address: proto.String("https://search.example.com")}.Build(),
+				UpstreamAuth: configv1.Authentication_builder{
+					ApiKey: configv1.APIKeyAuth_builder{
+						ParamName: proto.String("X-Key"),
+						Value:     configv1.SecretValue_builder{EnvironmentVariable: proto.String("DOCTOR_FIX_SEARCH_KEY")}.Build(),
+					}.Build(),
+				}.Build(),
+			}.Build(),
+		},
+	}.Build()
+	results := []CheckResult{
+		{ServiceName: "weather", Status: StatusError},
+		{ServiceName: "pets", Status: StatusWarning},
+		{ServiceName: "search", Status: StatusOk},
+	}
+
+	ws, err := NewWorkspace(fs, []string{"config.yaml"}, "")
+	require.NoError(t, err)
+	results = OfferFixes(ws, cfg, results)
+	require.Len(t, results, 4)
+	assert.Equal(t, []string{FixAddressScheme, FixCredentialStub}, fixIDs(results[0].Fixes))
+	assert.Equal(t, []string{FixPinOpenAPISpec}, fixIDs(results[1].Fixes))
+	assert.Equal(t, []string{FixCredentialStub}, fixIDs(results[2].Fixes))
+	assert.Equal(t, environmentCheck, results[3].ServiceName)
+	assert.Equal(t, "Missing environment variables: PETS_ADDRESS", results[3].Message)
+	assert.Empty(t, ws.Changed(), "offering fixes changes nothing")
+
+	for _, res := range results {
+		for _, fix := range res.Fixes {
+			require.NoError(t, fix.Apply(context.Background(), ws), fix.Description)
+		}
+	}
+	assert.Equal(t, []string{".env", "config.yaml", "secrets/weather-token"}, ws.Changed())
+
+	patch, err := ws.Patch()
+	require.NoError(t, err)
+	assert.Contains(t, patch, "--- a/config.yaml\n+++ b/config.yaml\n")
+	assert.Contains(t, patch, "-      address: 'localhost:8080'\n+      address: 'http://localhost:8080'\n")
+	assert.Contains(t, patch, "--- /dev/null\n+++ b/secrets/weather-token\n")
+	assert.Contains(t, patch, "+# Added by mcpany doctor --fix: set the values below.\n+PETS_ADDRESS=\n")
+	assert.Contains(t, patch, "+DOCTOR_FIX_SEARCH_KEY=\n")
+
+	require.NoError(t, ws.Write())
+	assert.Empty(t, ws.Changed())
+	env, err := afero.ReadFile(fs, ".env")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(env), "EXISTING=1\n# Added by mcpany doctor --fix"))
+	info, err := fs.Stat("secrets/weather-token")
+	require.NoError(t, err)
+	assert.Equal(t, "-rw-------", info.Mode().Perm().String())
+
+	b, err := afero.ReadFile(fs, "config.yaml")
+	require.NoError(t, err)
+	var parsed struct {
+		UpstreamServices []struct {
+			OpenapiService map[string]string `yaml:"openapi_service"`
+		} `yaml:"upstream_services"`
+	}
+	require.NoError(t, yaml.Unmarshal(b, &parsed))
+	assert.Equal(t, map[string]string{"address": "${PETS_ADDRESS}", "spec_content": fixSpec}, parsed.UpstreamServices[1].OpenapiService)
+}
+
+func TestPinSpec_JSON(t *testing.T) {
+	t.Setenv("MCPANY_ALLOW_LOOPBACK_RESOURCES", "true")
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(fixSpec))
+	}))
+	defer ts.Close()
+
+	fs := afero.NewMemMapFs()
+	configJSON := `{"upstream_services": [{"name": "pets", "openapi_service": {"spec_url": "` + ts.URL + `"}}]}`
+	require.NoError(t, afero.WriteFile(fs, "config.json", []byte(configJSON), 0o644))
+	cfg := configv1.McpAnyServerConfig_builder{
+		UpstreamServices: []*configv1.UpstreamServiceConfig{
+			configv1.UpstreamServiceConfig_builder{
+				Name:           proto.String("pets"),
+				OpenapiService: configv1.OpenapiUpstreamService_builder{SpecUrl: proto.String(ts.URL)}.Build(),
+			}.Build(),
+		},
+	}.Build()
+
+	ws, err := NewWorkspace(fs, []string{"config.json"}, "")
+	require.NoError(t, err)
+	results := OfferFixes(ws, cfg, []CheckResult{{ServiceName: "pets", Status: StatusOk}})
+	require.Len(t, results[0].Fixes, 1)
+	require.NoError(t, results[0].Fixes[0].Apply(context.Background(), ws))
+
+	b, err := ws.content("config.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"upstream_services": [{"name": "pets", "openapi_service": {"spec_content": "openapi: 3.0.0\ninfo:\n  title: Pets\n\npaths: {}\n"}}]}`, string(b))
+}
+
+func fixIDs(fixes []Fix) []string {
+	ids := make([]string, 0, len(fixes))
+	for _, fix := range fixes {
+		ids = append(ids, fix.ID)
+	}
+	return ids
+}
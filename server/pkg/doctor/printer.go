@@ -46,7 +46,24 @@ func PrintResults(w io.Writer, results []CheckResult) {
 			icon = "?"
 		}
 
-		_, _ = fmt.Fprintf(tw, "%s\t[%s]\t%s\t: %s\n", icon, res.Status, res.ServiceName, res.Message)
+		_, _ = fmt.Fprintf(tw, "%s\t[%s]\t%s\t: %s\n", icon, res.Status, res.Label(), res.Message)
+		for _, fix := range res.Fixes {
+			_, _ = fmt.Fprintf(tw, "\t\t\t  fix available: %s\n", fix.Description)
+		}
 	}
 	_ = tw.Flush()
 }
+
+// Label returns the subject of a result in tables and logs: its service
+// name, followed by the kind of the check for the checks other than the
+// connectivity and configuration ones, such as "weather (tls)".
+//
+// Returns:
+//   - string: The label.
+func (r CheckResult) Label() string {
+	kind, subject := splitCheckID(r)
+	if kind == CheckUpstream || kind == CheckConfig {
+		return subject
+	}
+	return fmt.Sprintf("%s (%s)", subject, kind)
+}
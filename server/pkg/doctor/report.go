@@ -0,0 +1,278 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// FormatText is the table printed by PrintResults.
+	FormatText = "text"
+	// FormatJSON is a JSON report of the checks.
+	FormatJSON = "json"
+	// FormatJUnit is a JUnit XML report, with a test case per check.
+	FormatJUnit = "junit"
+)
+
+// Formats are the formats of the reports of the checks.
+var Formats = []string{FormatText, FormatJSON, FormatJUnit}
+
+const (
+	// SeverityError is the severity of the checks that failed.
+	SeverityError = "error"
+	// SeverityWarning is the severity of the checks that found a non-critical issue.
+	SeverityWarning = "warning"
+	// SeverityInfo is the severity of the checks that passed or were skipped.
+	SeverityInfo = "info"
+)
+
+// Severity returns the severity of a status in reports.
+//
+// Returns:
+//   - string: SeverityError, SeverityWarning or SeverityInfo.
+func (s Status) Severity() string {
+	switch s {
+	case StatusError:
+		return SeverityError
+	case StatusWarning:
+		return SeverityWarning
+	}
+	return SeverityInfo
+}
+
+// Failed reports whether a result fails a run of the doctor: an error always
+// does, and a warning does when failOn is StatusWarning.
+//
+// Parameters:
+//   - res: CheckResult. The result of a check.
+//   - failOn: Status. The lowest status that fails the run, StatusError or StatusWarning.
+//
+// Returns:
+//   - bool: True if the result fails the run.
+func Failed(res CheckResult, failOn Status) bool {
+	return res.Status == StatusError || (failOn == StatusWarning && res.Status == StatusWarning)
+}
+
+// Report is the JSON report of the checks.
+type Report struct {
+	// Status is the worst status of the checks: ERROR, WARNING or OK.
+	Status Status `json:"status"`
+	// Summary counts the checks by status.
+	Summary ReportSummary `json:"summary"`
+	// DurationSeconds is the total duration of the checks.
+	DurationSeconds float64 `json:"duration_seconds"`
+	// Checks are the results of the checks.
+	Checks []ReportCheck `json:"checks"`
+}
+
+// ReportSummary counts the checks of a report by status.
+type ReportSummary struct {
+	Total   int `json:"total"`
+	Ok      int `json:"ok"`
+	Warning int `json:"warning"`
+	Error   int `json:"error"`
+	Skipped int `json:"skipped"`
+}
+
+// ReportCheck is the result of a check in a report.
+type ReportCheck struct {
+	ID              string      `json:"id"`
+	Kind            string      `json:"kind"`
+	Subject         string      `json:"subject"`
+	Status          Status      `json:"status"`
+	Severity        string      `json:"severity"`
+	Message         string      `json:"message"`
+	DurationSeconds float64     `json:"duration_seconds"`
+	Fixes           []ReportFix `json:"fixes,omitempty"`
+}
+
+// ReportFix is a fix offered by a check in a report.
+type ReportFix struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+}
+
+// NewReport builds the JSON report of the results of checks.
+//
+// Parameters:
+//   - results: []CheckResult. The results of the checks.
+//
+// Returns:
+//   - Report: The report.
+func NewReport(results []CheckResult) Report {
+	report := Report{Status: StatusOk, Checks: make([]ReportCheck, 0, len(results))}
+	var total time.Duration
+	for _, res := range results {
+		kind, subject := splitCheckID(res)
+		check := ReportCheck{
+			ID:              CheckID(kind, subject),
+			Kind:            kind,
+			Subject:         subject,
+			Status:          res.Status,
+			Severity:        res.Status.Severity(),
+			Message:         res.Message,
+			DurationSeconds: res.Duration.Seconds(),
+		}
+		for _, fix := range res.Fixes {
+			check.Fixes = append(check.Fixes, ReportFix{ID: fix.ID, Description: fix.Description})
+		}
+		report.Checks = append(report.Checks, check)
+		total += res.Duration
+
+		report.Summary.Total++
+		switch res.Status {
+		case StatusOk:
+			report.Summary.Ok++
+		case StatusWarning:
+			report.Summary.Warning++
+			if report.Status == StatusOk {
+				report.Status = StatusWarning
+			}
+		case StatusError:
+			report.Summary.Error++
+			report.Status = StatusError
+		case StatusSkipped:
+			report.Summary.Skipped++
+		}
+	}
+	report.DurationSeconds = total.Seconds()
+	return report
+}
+
+// splitCheckID returns the kind and the subject of the check of a result,
+// defaulting to a check of the upstream service for results without an ID.
+func splitCheckID(res CheckResult) (string, string) {
+	if kind, subject, ok := strings.Cut(res.ID, "/"); ok {
+		return kind, subject
+	}
+	return CheckUpstream, res.ServiceName
+}
+
+// WriteReport writes the results of checks in a format.
+//
+// Parameters:
+//   - w: io.Writer. The writer of the report.
+//   - format: string. One of Formats.
+//   - results: []CheckResult. The results of the checks.
+//   - failOn: Status. The lowest status reported as a failure in JUnit reports, StatusError or StatusWarning.
+//
+// Returns:
+//   - error: An error if the format is unknown or the report cannot be written.
+//
+// Side Effects:
+//   - Writes the report to w.
+func WriteReport(w io.Writer, format string, results []CheckResult, failOn Status) error {
+	switch format {
+	case FormatText, "":
+		PrintResults(w, results)
+		return nil
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(NewReport(results))
+	case FormatJUnit:
+		return writeJUnit(w, results, failOn)
+	}
+	return fmt.Errorf("invalid output format %q: must be one of %s", format, strings.Join(Formats, ", "))
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+	duration time.Duration
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes a JUnit report, with a test suite per kind of check and a
+// test case per check. Warnings only fail their test case when failOn is
+// StatusWarning; otherwise they are reported in its output.
+func writeJUnit(w io.Writer, results []CheckResult, failOn Status) error {
+	root := junitTestSuites{Name: "mcpany doctor"}
+	suites := map[string]int{}
+	var total time.Duration
+	for _, res := range results {
+		kind, subject := splitCheckID(res)
+		i, ok := suites[kind]
+		if !ok {
+			i = len(root.Suites)
+			suites[kind] = i
+			root.Suites = append(root.Suites, junitTestSuite{Name: kind})
+		}
+		suite := &root.Suites[i]
+		tc := junitTestCase{Name: subject, ClassName: "mcpany.doctor." + kind, Time: junitTime(res.Duration)}
+		switch {
+		case Failed(res, failOn):
+			tc.Failure = &junitMessage{Message: res.Message, Type: res.Status.Severity(), Text: res.Message}
+			suite.Failures++
+			root.Failures++
+		case res.Status == StatusSkipped:
+			tc.Skipped = &junitMessage{Message: res.Message}
+			suite.Skipped++
+			root.Skipped++
+		case res.Status == StatusWarning:
+			tc.SystemOut = "WARNING: " + res.Message
+		}
+		for _, fix := range res.Fixes {
+			tc.SystemOut = strings.TrimPrefix(tc.SystemOut+"\nfix available: "+fix.Description, "\n")
+		}
+		suite.Cases = append(suite.Cases, tc)
+		suite.Tests++
+		suite.duration += res.Duration
+		root.Tests++
+		total += res.Duration
+	}
+	for i := range root.Suites {
+		root.Suites[i].Time = junitTime(root.Suites[i].duration)
+	}
+	root.Time = junitTime(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func junitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var reportResults = []CheckResult{
+	{ID: "upstream/weather", ServiceName: "weather", Status: StatusOk, Message: "Service reachable (200 OK)", Duration: 120 * time.Millisecond},
+	{ID: "upstream/news", ServiceName: "news", Status: StatusError, Message: "Failed to connect", Duration: 2 * time.Second},
+	{ID: "upstream/legacy", ServiceName: "legacy", Status: StatusSkipped, Message: "Service is disabled"},
+	{ID: "config/environment", ServiceName: "environment", Status: StatusWarning, Message: "Missing environment variables: TOKEN",
+		Fixes: []Fix{{ID: FixEnvPlaceholder, Description: "Add placeholders for TOKEN to .env"}}},
+}
+
+func TestWriteReport_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteReport(&buf, FormatJSON, reportResults, StatusError))
+
+	var report Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, StatusError, report.Status)
+	assert.Equal(t, ReportSummary{Total: 4, Ok: 1, Warning: 1, Error: 1, Skipped: 1}, report.Summary)
+	assert.InDelta(t, 2.12, report.DurationSeconds, 0.001)
+	require.Len(t, report.Checks, 4)
+	assert.Equal(t, ReportCheck{
+		ID: "upstream/news", Kind: "upstream", Subject: "news", Status: StatusError, Severity: SeverityError,
+		Message: "Failed to connect", DurationSeconds: 2,
+	}, report.Checks[1])
+	assert.Equal(t, SeverityInfo, report.Checks[2].Severity)
+	assert.Equal(t, []ReportFix{{ID: FixEnvPlaceholder, Description: "Add placeholders for TOKEN to .env"}}, report.Checks[3].Fixes)
+
+	// Results without an ID are the checks of the upstream services.
+	assert.Equal(t, "upstream/weather", NewReport([]CheckResult{{ServiceName: "weather", Status: StatusOk}}).Checks[0].ID)
+	assert.Equal(t, StatusWarning, NewReport(reportResults[2:]).Status)
+
+	assert.EqualError(t, WriteReport(&buf, "yaml", reportResults, StatusError), `invalid output format "yaml": must be one of text, json, junit`)
+}
+
+func TestWriteReport_JUnit(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteReport(&buf, FormatJUnit, reportResults, StatusError))
+
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+	assert.Equal(t, 4, suites.Tests)
+	assert.Equal(t, 1, suites.Failures)
+	assert.Equal(t, 1, suites.Skipped)
+	assert.Equal(t, "2.120", suites.Time)
+	require.Len(t, suites.Suites, 2)
+	upstream := suites.Suites[0]
+	assert.Equal(t, "upstream", upstream.Name)
+	assert.Equal(t, 3, upstream.Tests)
+	assert.Equal(t, "news", upstream.Cases[1].Name)
+	assert.Equal(t, "mcpany.doctor.upstream", upstream.Cases[1].ClassName)
+	require.NotNil(t, upstream.Cases[1].Failure)
+	assert.Equal(t, "Failed to connect", upstream.Cases[1].Failure.Message)
+	assert.NotNil(t, upstream.Cases[2].Skipped)
+	environment := suites.Suites[1].Cases[0]
+	assert.Nil(t, environment.Failure, "warnings pass by default")
+	assert.Equal(t, "WARNING: Missing environment variables: TOKEN\nfix available: Add placeholders for TOKEN to .env", environment.SystemOut)
+
+	buf.Reset()
+	require.NoError(t, WriteReport(&buf, FormatJUnit, reportResults, StatusWarning))
+	var strict junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &strict))
+	assert.Equal(t, 2, strict.Failures)
+	require.NotNil(t, strict.Suites[1].Cases[0].Failure)
+	assert.Equal(t, SeverityWarning, strict.Suites[1].Cases[0].Failure.Type)
+}
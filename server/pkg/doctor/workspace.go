@@ -0,0 +1,370 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// Workspace holds the files that the fixes of the doctor change, in memory,
+// until they are written or exported as a patch.
+type Workspace struct {
+	fs      afero.Fs
+	envFile string
+	configs []string
+	files   map[string]*workspaceFile
+}
+
+type workspaceFile struct {
+	original []byte
+	content  []byte
+	exists   bool
+	perm     os.FileMode
+}
+
+// NewWorkspace creates a workspace over the configuration files and the
+// environment file of the server.
+//
+// Parameters:
+//   - fs: afero.Fs. The filesystem of the files.
+//   - configPaths: []string. The configuration files and directories, as given to the server. Remote paths are ignored.
+//   - envFile: string. The environment file that placeholders are added to, ".env" if empty.
+//
+// Returns:
+//   - *Workspace: The workspace.
+//   - error: An error if a configuration path cannot be read.
+//
+// Side Effects:
+//   - Reads the configuration files.
+func NewWorkspace(fs afero.Fs, configPaths []string, envFile string) (*Workspace, error) {
+	if envFile == "" {
+		envFile = ".env"
+	}
+	w := &Workspace{fs: fs, envFile: envFile, files: make(map[string]*workspaceFile)}
+	for _, path := range configPaths {
+		lower := strings.ToLower(path)
+		if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") {
+			continue
+		}
+		info, err := fs.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat config path %s: %w", path, err)
+		}
+		if !info.IsDir() {
+			if isConfigFile(path) {
+				w.configs = append(w.configs, path)
+			}
+			continue
+		}
+		err = afero.Walk(fs, path, func(p string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !fi.IsDir() && isConfigFile(p) {
+				w.configs = append(w.configs, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk config directory %s: %w", path, err)
+		}
+	}
+	sort.Strings(w.configs)
+	for _, path := range w.configs {
+		if _, err := w.file(path); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// isConfigFile reports whether the fixes can edit a configuration file: YAML,
+// and JSON as a subset of it.
+func isConfigFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// EnvFile returns the path of the environment file of the workspace.
+//
+// Returns:
+//   - string: The path of the environment file.
+func (w *Workspace) EnvFile() string {
+	return w.envFile
+}
+
+// file returns a file of the workspace, reading it on first use. A file that
+// does not exist yet is empty.
+func (w *Workspace) file(path string) (*workspaceFile, error) {
+	if f, ok := w.files[path]; ok {
+		return f, nil
+	}
+	f := &workspaceFile{perm: 0o600}
+	info, err := w.fs.Stat(path)
+	switch {
+	case err == nil:
+		b, err := afero.ReadFile(w.fs, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		f.original, f.content, f.exists, f.perm = b, b, true, info.Mode().Perm()
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	w.files[path] = f
+	return f, nil
+}
+
+// content returns the current content of a file of the workspace.
+func (w *Workspace) content(path string) ([]byte, error) {
+	f, err := w.file(path)
+	if err != nil {
+		return nil, err
+	}
+	return f.content, nil
+}
+
+// setContent replaces the content of a file of the workspace.
+func (w *Workspace) setContent(path string, content []byte) error {
+	f, err := w.file(path)
+	if err != nil {
+		return err
+	}
+	f.content = content
+	return nil
+}
+
+// exists reports whether a file exists on disk or was created by a fix.
+func (w *Workspace) exists(path string) bool {
+	f, err := w.file(path)
+	return err == nil && (f.exists || f.content != nil)
+}
+
+// Changed returns the paths of the files that the applied fixes changed.
+//
+// Returns:
+//   - []string: The sorted paths of the changed files.
+func (w *Workspace) Changed() []string {
+	var paths []string
+	for path, f := range w.files {
+		if !bytes.Equal(f.original, f.content) || (!f.exists && f.content != nil) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Patch returns the changes of the applied fixes as a unified diff, which
+// `git apply` and `patch -p1` accept.
+//
+// Returns:
+//   - string: The patch, empty if nothing changed.
+//   - error: An error if the diff cannot be computed.
+func (w *Workspace) Patch() (string, error) {
+	var sb strings.Builder
+	for _, path := range w.Changed() {
+		f := w.files[path]
+		name := filepath.ToSlash(filepath.Clean(path))
+		from := "a/" + name
+		if !f.exists {
+			from = "/dev/null"
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        diffLines(f.original),
+			B:        diffLines(f.content),
+			FromFile: from,
+			ToFile:   "b/" + name,
+			Context:  3,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to diff %s: %w", path, err)
+		}
+		sb.WriteString(diff)
+	}
+	return sb.String(), nil
+}
+
+// diffLines splits a file in lines for a diff, marking a last line without a
+// newline the way diff does.
+func diffLines(b []byte) []string {
+	if len(b) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(b), "\n")
+	if last := len(lines) - 1; lines[last] == "" {
+		lines = lines[:last]
+	} else {
+		lines[last] += "\n\\ No newline at end of file\n"
+	}
+	return lines
+}
+
+// Write writes the changed files to disk. The files it creates, such as
+// credential stubs, are only readable by their owner.
+//
+// Returns:
+//   - error: An error if a file cannot be written.
+//
+// Side Effects:
+//   - Creates and modifies files on the filesystem.
+func (w *Workspace) Write() error {
+	for _, path := range w.Changed() {
+		f := w.files[path]
+		if dir := filepath.Dir(path); dir != "." {
+			if err := w.fs.MkdirAll(dir, 0o700); err != nil {
+				return fmt.Errorf("failed to create directory %s: %w", dir, err)
+			}
+		}
+		if err := afero.WriteFile(w.fs, path, f.content, f.perm); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		f.original, f.exists = f.content, true
+	}
+	return nil
+}
+
+// serviceNode is the location of an upstream service in a configuration file.
+type serviceNode struct {
+	path string
+	node *yaml.Node
+}
+
+// findService finds the mapping of an upstream service in the configuration
+// files.
+func (w *Workspace) findService(name string) (serviceNode, bool) {
+	for _, path := range w.configs {
+		b, err := w.content(path)
+		if err != nil {
+			continue
+		}
+		var doc yaml.Node
+		if err := yaml.Unmarshal(b, &doc); err != nil || len(doc.Content) == 0 {
+			continue
+		}
+		_, services := lookupNode(doc.Content[0], "upstream_services")
+		if services == nil || services.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, svc := range services.Content {
+			if _, n := lookupNode(svc, "name"); n != nil && n.Value == name {
+				return serviceNode{path: path, node: svc}, true
+			}
+		}
+	}
+	return serviceNode{}, false
+}
+
+// lookupNode follows a path of keys from a mapping node, and returns the key
+// and value nodes of the last one.
+func lookupNode(node *yaml.Node, keys ...string) (*yaml.Node, *yaml.Node) {
+	var key *yaml.Node
+	for _, k := range keys {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil, nil
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == k {
+				key, next = node.Content[i], node.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return nil, nil
+		}
+		node = next
+	}
+	return key, node
+}
+
+// offset returns the byte offset of the line and column of a node.
+func offset(b []byte, node *yaml.Node) (int, bool) {
+	off := 0
+	for line := 1; line < node.Line; line++ {
+		i := bytes.IndexByte(b[off:], '\n')
+		if i < 0 {
+			return 0, false
+		}
+		off += i + 1
+	}
+	// Columns count characters, not bytes.
+	for col := 1; col < node.Column; col++ {
+		if off >= len(b) || b[off] == '\n' {
+			return 0, false
+		}
+		_, size := utf8.DecodeRune(b[off:])
+		off += size
+	}
+	return off, true
+}
+
+// rawScalar returns the text of a scalar node as it is written in the file.
+func rawScalar(node *yaml.Node) (string, bool) {
+	switch node.Style {
+	case 0:
+		return node.Value, true
+	case yaml.DoubleQuotedStyle:
+		if strings.ContainsAny(node.Value, "\"\\\n") {
+			return "", false
+		}
+		return `"` + node.Value + `"`, true
+	case yaml.SingleQuotedStyle:
+		if strings.Contains(node.Value, "\n") {
+			return "", false
+		}
+		return "'" + strings.ReplaceAll(node.Value, "'", "''") + "'", true
+	}
+	return "", false
+}
+
+// quoteScalar writes a value in the style of a scalar node.
+func quoteScalar(node *yaml.Node, value string) string {
+	switch node.Style {
+	case yaml.DoubleQuotedStyle:
+		return `"` + value + `"`
+	case yaml.SingleQuotedStyle:
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	}
+	return value
+}
+
+// replaceRange replaces the text from the start of the node "from" to the end
+// of the scalar node "to" in a file of the workspace, after checking that the
+// text of "to" is still the one of the node.
+func (w *Workspace) replaceRange(path string, from, to *yaml.Node, text string) error {
+	b, err := w.content(path)
+	if err != nil {
+		return err
+	}
+	raw, ok := rawScalar(to)
+	if !ok {
+		return fmt.Errorf("%s:%d: the value is written in a syntax that cannot be edited automatically", path, to.Line)
+	}
+	start, ok1 := offset(b, from)
+	end, ok2 := offset(b, to)
+	if !ok1 || !ok2 || end < start || !bytes.HasPrefix(b[end:], []byte(raw)) {
+		return fmt.Errorf("%s:%d: the file does not match its parsed content", path, to.Line)
+	}
+	end += len(raw)
+	out := make([]byte, 0, len(b)-(end-start)+len(text))
+	out = append(out, b[:start]...)
+	out = append(out, text...)
+	out = append(out, b[end:]...)
+	return w.setContent(path, out)
+}
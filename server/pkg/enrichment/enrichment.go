@@ -0,0 +1,240 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package enrichment adds labels derived from the request context, such as a
+// team name taken from the JWT claims, to the tool call metrics and audit
+// entries.
+//
+// Enrichers are plugins. A build-time plugin registers a Factory under a
+// name, usually from the init function of a package linked into the binary,
+// and a global_settings.label_enrichers entry with that name enables it at
+// runtime. A runtime plugin is an executable set as the plugin of the entry;
+// the plugin package registers the factory starting it with RegisterPlugin.
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"sort"
+	"sync"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+)
+
+// Call describes the tool call being enriched.
+type Call struct {
+	// ToolName is the fully qualified name of the tool.
+	ToolName string
+	// ServiceID is the ID of the service providing the tool.
+	ServiceID string
+}
+
+// Enricher derives labels from the context of a tool call.
+//
+// Summary: A source of extra metric and audit labels.
+type Enricher interface {
+	// Labels returns the names of the labels the enricher sets. The set must
+	// not change over the lifetime of the enricher.
+	Labels() []string
+	// Enrich returns the label values for a call. Labels it cannot determine
+	// are left out. It is called on every tool call and must be cheap.
+	Enrich(ctx context.Context, call Call) map[string]string
+}
+
+// Factory creates an enricher from its configuration.
+type Factory func(cfg *configv1.LabelEnricher) (Enricher, error)
+
+// reservedLabels are the labels the tool call metrics set themselves.
+var reservedLabels = []string{"tool", "service_id", "status", "error_type", "direction"}
+
+var labelNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+var (
+	mu            sync.RWMutex
+	factories     = make(map[string]Factory)
+	pluginFactory Factory
+	active        []Enricher
+	names     []string
+	// known maps each label of the active enrichers to the enricher name.
+	known map[string]string
+)
+
+// Register registers an enricher factory.
+//
+// Summary: Makes an enricher available to the label_enrichers setting.
+//
+// Parameters:
+//   - name: string. The name the configuration refers to.
+//   - factory: Factory. Creates the enricher from its configuration.
+//
+// Side Effects:
+//   - Replaces any factory registered under the same name.
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	factories[name] = factory
+}
+
+// RegisterPlugin registers the factory of the enrichers loaded from an
+// executable, those whose configuration sets a plugin.
+//
+// Summary: Makes runtime enricher plugins available.
+//
+// Parameters:
+//   - factory: Factory. Starts the plugin of the enricher. The enricher it
+//     returns may implement io.Closer, which stops the plugin once the
+//     enricher is replaced.
+//
+// Side Effects:
+//   - Replaces any previously registered plugin factory.
+func RegisterPlugin(factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	pluginFactory = factory
+}
+
+// Configure replaces the active enrichers with the ones enabled by configs.
+//
+// Summary: Applies the label_enrichers setting.
+//
+// Parameters:
+//   - configs: []*configv1.LabelEnricher. The enricher configurations.
+//
+// Returns:
+//   - error: An error if an enricher is unknown, fails to build, or sets an
+//     invalid, reserved or duplicate label. The active enrichers are left
+//     unchanged in that case.
+//
+// Side Effects:
+//   - Updates the package-level set of active enrichers.
+//   - Starts the plugins of the new enrichers, and stops those of the
+//     replaced ones.
+func Configure(configs []*configv1.LabelEnricher) error {
+	mu.RLock()
+	registered, plugin := maps.Clone(factories), pluginFactory
+	mu.RUnlock()
+
+	var enrichers []Enricher
+	var labels []string
+	owner := make(map[string]string)
+	fail := func(err error) error {
+		_ = closeEnrichers(enrichers)
+		return err
+	}
+	for _, cfg := range configs {
+		if cfg.GetDisabled() {
+			continue
+		}
+		factory, ok := registered[cfg.GetName()]
+		if cfg.HasPlugin() {
+			factory, ok = plugin, plugin != nil
+		}
+		if !ok {
+			return fail(fmt.Errorf("unknown label enricher %q", cfg.GetName()))
+		}
+		e, err := factory(cfg)
+		if err != nil {
+			return fail(fmt.Errorf("label enricher %q: %w", cfg.GetName(), err))
+		}
+		enrichers = append(enrichers, e)
+		for _, label := range e.Labels() {
+			switch {
+			case !labelNameRegex.MatchString(label):
+				return fail(fmt.Errorf("label enricher %q: invalid label name %q", cfg.GetName(), label))
+			case slices.Contains(reservedLabels, label):
+				return fail(fmt.Errorf("label enricher %q: label %q is reserved", cfg.GetName(), label))
+			case owner[label] != "":
+				return fail(fmt.Errorf("label enricher %q: label %q is already set by %q", cfg.GetName(), label, owner[label]))
+			}
+			owner[label] = cfg.GetName()
+			labels = append(labels, label)
+		}
+	}
+	sort.Strings(labels)
+
+	mu.Lock()
+	replaced := active
+	active, names, known = enrichers, labels, owner
+	mu.Unlock()
+	// The replaced plugins are stopped regardless, so an error stopping one
+	// does not fail the update.
+	_ = closeEnrichers(replaced)
+	return nil
+}
+
+// Close stops the plugins of the active enrichers, and leaves none active.
+//
+// Summary: Stops the label enrichers.
+//
+// Returns:
+//   - error: An error if a plugin fails to stop.
+//
+// Side Effects:
+//   - Clears the package-level set of active enrichers.
+func Close() error {
+	mu.Lock()
+	replaced := active
+	active, names, known = nil, nil, nil
+	mu.Unlock()
+	return closeEnrichers(replaced)
+}
+
+// closeEnrichers closes the enrichers that run in a plugin.
+func closeEnrichers(enrichers []Enricher) error {
+	var errs []error
+	for _, e := range enrichers {
+		if c, ok := e.(io.Closer); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LabelNames returns the names of the labels set by the active enrichers.
+//
+// Summary: Lists the enriched label names.
+//
+// Returns:
+//   - []string: The sorted label names.
+func LabelNames() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return slices.Clone(names)
+}
+
+// Enrich returns the labels of a call from all active enrichers.
+//
+// Summary: Derives the extra labels of a tool call.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the call.
+//   - call: Call. The call being enriched.
+//
+// Returns:
+//   - map[string]string: The non-empty label values, or nil if there are none.
+func Enrich(ctx context.Context, call Call) map[string]string {
+	mu.RLock()
+	enrichers, labelSet := active, known
+	mu.RUnlock()
+
+	var labels map[string]string
+	for _, e := range enrichers {
+		for k, v := range e.Enrich(ctx, call) {
+			// Labels an enricher did not declare would be dropped by the
+			// metrics, so they are dropped here too.
+			if v == "" || labelSet[k] == "" {
+				continue
+			}
+			if labels == nil {
+				labels = make(map[string]string)
+			}
+			labels[k] = v
+		}
+	}
+	return labels
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package enrichment
+
+import (
+	"context"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+type staticEnricher map[string]string
+
+func (e staticEnricher) Labels() []string {
+	labels := make([]string, 0, len(e))
+	for k := range e {
+		labels = append(labels, k)
+	}
+	return labels
+}
+
+func (e staticEnricher) Enrich(context.Context, Call) map[string]string {
+	return e
+}
+
+func labelEnricher(name string, labels map[string]string) *configv1.LabelEnricher {
+	return configv1.LabelEnricher_builder{Name: proto.String(name), Labels: labels}.Build()
+}
+
+func TestConfigure(t *testing.T) {
+	t.Cleanup(func() { require.NoError(t, Configure(nil)) })
+	Register("static", func(cfg *configv1.LabelEnricher) (Enricher, error) {
+		return staticEnricher(cfg.GetLabels()), nil
+	})
+
+	require.NoError(t, Configure([]*configv1.LabelEnricher{
+		labelEnricher("static", map[string]string{"region": "eu", "cost_center": ""}),
+		labelEnricher(JWTClaimsEnricher, map[string]string{"team": "org.team"}),
+		configv1.LabelEnricher_builder{Name: proto.String("unknown"), Disabled: proto.Bool(true)}.Build(),
+	}))
+	assert.Equal(t, []string{"cost_center", "region", "team"}, LabelNames())
+
+	ctx := auth.ContextWithClaims(context.Background(), map[string]any{
+		"org": map[string]any{"team": "payments"},
+	})
+	assert.Equal(t, map[string]string{"region": "eu", "team": "payments"}, Enrich(ctx, Call{ToolName: "svc.tool"}))
+	assert.Equal(t, map[string]string{"region": "eu"}, Enrich(context.Background(), Call{ToolName: "svc.tool"}))
+
+	for name, cfgs := range map[string][]*configv1.LabelEnricher{
+		"unknown enricher": {labelEnricher("unknown", nil)},
+		"invalid label":    {labelEnricher("static", map[string]string{"cost-center": "x"})},
+		"reserved label":   {labelEnricher("static", map[string]string{"service_id": "x"})},
+		"duplicate label":  {labelEnricher("static", map[string]string{"team": "x"}), labelEnricher(JWTClaimsEnricher, map[string]string{"team": "team"})},
+		"no claims":        {labelEnricher(JWTClaimsEnricher, nil)},
+		"empty claim":      {labelEnricher(JWTClaimsEnricher, map[string]string{"team": ""})},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, Configure(cfgs))
+			// A failed update keeps the active enrichers.
+			assert.Equal(t, []string{"cost_center", "region", "team"}, LabelNames())
+		})
+	}
+}
+
+func TestClaimLabelValue(t *testing.T) {
+	assert.Equal(t, "payments", claimLabelValue("payments"))
+	assert.Equal(t, "42", claimLabelValue(float64(42)))
+	assert.Equal(t, "true", claimLabelValue(true))
+	assert.Equal(t, "a,b", claimLabelValue([]any{"a", map[string]any{}, "b"}))
+	assert.Equal(t, "", claimLabelValue(map[string]any{"a": "b"}))
+}
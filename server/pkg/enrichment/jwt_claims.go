@@ -0,0 +1,82 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package enrichment
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+)
+
+// JWTClaimsEnricher is the name of the built-in enricher that reads labels
+// from the claims of the JWT that authenticated the request.
+const JWTClaimsEnricher = "jwt_claims"
+
+func init() {
+	Register(JWTClaimsEnricher, newJWTClaimsEnricher)
+}
+
+// jwtClaimsEnricher maps label names to claim paths.
+type jwtClaimsEnricher struct {
+	claims map[string]string
+	labels []string
+}
+
+func newJWTClaimsEnricher(cfg *configv1.LabelEnricher) (Enricher, error) {
+	if len(cfg.GetLabels()) == 0 {
+		return nil, fmt.Errorf("no labels configured")
+	}
+	e := &jwtClaimsEnricher{claims: make(map[string]string, len(cfg.GetLabels()))}
+	for label, claim := range cfg.GetLabels() {
+		if claim == "" {
+			return nil, fmt.Errorf("label %q has no claim", label)
+		}
+		e.claims[label] = claim
+		e.labels = append(e.labels, label)
+	}
+	sort.Strings(e.labels)
+	return e, nil
+}
+
+func (e *jwtClaimsEnricher) Labels() []string {
+	return e.labels
+}
+
+func (e *jwtClaimsEnricher) Enrich(ctx context.Context, _ Call) map[string]string {
+	labels := make(map[string]string, len(e.claims))
+	for label, claim := range e.claims {
+		if v, ok := auth.ClaimFromContext(ctx, claim); ok {
+			labels[label] = claimLabelValue(v)
+		}
+	}
+	return labels
+}
+
+// claimLabelValue formats a claim as a label value. Lists are joined with
+// commas; objects are not used as labels.
+func claimLabelValue(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case bool:
+		return strconv.FormatBool(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case []any:
+		parts := make([]string, 0, len(val))
+		for _, item := range val {
+			if s := claimLabelValue(item); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ",")
+	default:
+		return ""
+	}
+}
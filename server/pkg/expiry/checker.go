@@ -0,0 +1,619 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package expiry warns about stored credentials and upstream TLS
+// certificates that expire or are due for rotation soon.
+package expiry
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/health"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultWarnBefore is how long before an expiry or a due rotation the
+// checker starts warning when the configuration does not say.
+const DefaultWarnBefore = 14 * 24 * time.Hour
+
+const (
+	defaultCheckInterval = time.Hour
+	dialTimeout          = 10 * time.Second
+)
+
+// Kinds of Finding.
+const (
+	// KindCredential is a stored credential.
+	KindCredential = "credential"
+	// KindTLSCertificate is a client certificate file or the certificate an
+	// upstream presents.
+	KindTLSCertificate = "tls_certificate"
+)
+
+// State says why a Finding is reported.
+type State string
+
+const (
+	// StateExpiring means the credential or certificate expires within the
+	// warning window.
+	StateExpiring State = "expiring"
+	// StateExpired means the credential or certificate has expired.
+	StateExpired State = "expired"
+	// StateRotationDue means the rotation policy of the credential asks for a
+	// rotation within the warning window, or already did.
+	StateRotationDue State = "rotation_due"
+)
+
+var (
+	registerMetricsOnce sync.Once
+
+	expiryTimestamp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mcpany_expiry_timestamp_seconds",
+			Help: "Unix time at which a credential or TLS certificate expires, or a credential rotation is due.",
+		},
+		[]string{"kind", "name", "source", "event"},
+	)
+)
+
+// Finding is a credential or certificate that needs attention.
+type Finding struct {
+	// Kind is KindCredential or KindTLSCertificate.
+	Kind string `json:"kind"`
+	// Name is the name of the credential, or of the service using the
+	// certificate.
+	Name string `json:"name"`
+	// Source locates it: the credential ID, the certificate file, or the
+	// upstream address.
+	Source string `json:"source"`
+	// Subject is the subject of the certificate.
+	Subject string `json:"subject,omitempty"`
+	// State says why it is reported.
+	State State `json:"state"`
+	// At is when it expires, or when its rotation is due.
+	At time.Time `json:"at"`
+}
+
+// String describes the finding, e.g.
+// `credential "github" (github-pat) expires in 72h0m0s`.
+//
+// Returns:
+//   - string: The description.
+func (f Finding) String() string {
+	return f.summary(time.Now())
+}
+
+func (f Finding) summary(now time.Time) string {
+	what := fmt.Sprintf("%s %q (%s)", strings.ReplaceAll(f.Kind, "_", " "), f.Name, f.Source)
+	if f.Subject != "" {
+		what += " " + f.Subject
+	}
+	return what + " " + f.describe(now)
+}
+
+func (f Finding) describe(now time.Time) string {
+	left := f.At.Sub(now).Round(time.Minute)
+	switch f.State {
+	case StateExpired:
+		return fmt.Sprintf("expired %s ago", -left)
+	case StateRotationDue:
+		if left <= 0 {
+			return fmt.Sprintf("rotation overdue by %s", -left)
+		}
+		return fmt.Sprintf("rotation due in %s", left)
+	default:
+		return fmt.Sprintf("expires in %s", left)
+	}
+}
+
+// CredentialLister lists the stored credentials. storage.Storage implements
+// it.
+type CredentialLister interface {
+	// ListCredentials returns every stored credential.
+	ListCredentials(ctx context.Context) ([]*configv1.Credential, error)
+}
+
+// Checker periodically looks for stored credentials and upstream TLS
+// certificates that expire or are due for rotation soon. It logs a warning
+// for each, exports their deadlines as metrics and reports them to the
+// doctor.
+type Checker struct {
+	credentials CredentialLister
+	now         func() time.Time
+
+	mu         sync.Mutex
+	services   []*configv1.UpstreamServiceConfig
+	disabled   bool
+	warnBefore time.Duration
+	interval   time.Duration
+	findings   []Finding
+	checked    bool
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+// Option configures a Checker.
+type Option func(*Checker)
+
+// WithClock sets the clock of the checker.
+//
+// Parameters:
+//   - now: func() time.Time. The clock; time.Now by default.
+//
+// Returns:
+//   - Option: The option.
+func WithClock(now func() time.Time) Option {
+	return func(c *Checker) { c.now = now }
+}
+
+// NewChecker creates a Checker.
+//
+// Summary: Initializes expiry checks of credentials and TLS certificates.
+//
+// Parameters:
+//   - credentials: CredentialLister. The stored credentials; nil checks certificates only.
+//   - opts: ...Option. The options.
+//
+// Returns:
+//   - *Checker: The checker.
+func NewChecker(credentials CredentialLister, opts ...Option) *Checker {
+	registerMetricsOnce.Do(func() {
+		prometheus.MustRegister(expiryTimestamp)
+	})
+	c := &Checker{
+		credentials: credentials,
+		now:         time.Now,
+		disabled:    true,
+		warnBefore:  DefaultWarnBefore,
+		interval:    defaultCheckInterval,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Configure applies the expiry_alerts settings and the upstream services of
+// the configuration.
+//
+// Summary: Applies the server configuration.
+//
+// Parameters:
+//   - cfg: *configv1.McpAnyServerConfig. The configuration.
+func (c *Checker) Configure(cfg *configv1.McpAnyServerConfig) {
+	settings := cfg.GetGlobalSettings().GetExpiryAlerts()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.services = cfg.GetUpstreamServices()
+	c.disabled = !settings.GetEnabled()
+	c.warnBefore = DefaultWarnBefore
+	if d := settings.GetWarnBefore().AsDuration(); d > 0 {
+		c.warnBefore = d
+	}
+	c.interval = defaultCheckInterval
+	if d := settings.GetCheckInterval().AsDuration(); d > 0 {
+		c.interval = d
+	}
+}
+
+// deadline is a point in time tracked by the checker.
+type deadline struct {
+	kind     string
+	name     string
+	source   string
+	subject  string
+	at       time.Time
+	rotation bool
+}
+
+// Check looks at every credential and certificate once.
+//
+// Summary: Runs one expiry check.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the storage reads and the TLS handshakes.
+//
+// Returns:
+//   - []Finding: The credentials and certificates that need attention, soonest first.
+//
+// Side Effects:
+//   - Logs a warning per finding, updates the metrics and connects to the TLS upstreams.
+func (c *Checker) Check(ctx context.Context) []Finding {
+	c.mu.Lock()
+	services, disabled, warnBefore := c.services, c.disabled, c.warnBefore
+	c.mu.Unlock()
+	if disabled {
+		expiryTimestamp.Reset()
+		c.setFindings(nil)
+		return nil
+	}
+
+	deadlines := c.credentialDeadlines(ctx)
+	for _, svc := range services {
+		if !svc.GetDisable() {
+			deadlines = append(deadlines, certificateDeadlines(ctx, svc)...)
+		}
+	}
+
+	now := c.now()
+	expiryTimestamp.Reset()
+	var findings []Finding
+	for _, d := range deadlines {
+		event := "expiry"
+		if d.rotation {
+			event = "rotation"
+		}
+		expiryTimestamp.WithLabelValues(d.kind, d.name, d.source, event).Set(float64(d.at.Unix()))
+		if d.at.Sub(now) > warnBefore {
+			continue
+		}
+		f := Finding{Kind: d.kind, Name: d.name, Source: d.source, Subject: d.subject, At: d.at}
+		switch {
+		case d.rotation:
+			f.State = StateRotationDue
+		case !now.Before(d.at):
+			f.State = StateExpired
+		default:
+			f.State = StateExpiring
+		}
+		findings = append(findings, f)
+	}
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].At.Before(findings[j].At) })
+
+	log := logging.GetLogger()
+	for _, f := range findings {
+		log.Warn("Credential or certificate needs attention",
+			"kind", f.Kind,
+			"name", f.Name,
+			"source", f.Source,
+			"subject", f.Subject,
+			"state", string(f.State),
+			"at", f.At.Format(time.RFC3339),
+			"detail", f.describe(now))
+	}
+	c.setFindings(findings)
+	return findings
+}
+
+func (c *Checker) setFindings(findings []Finding) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.findings = findings
+	c.checked = true
+}
+
+// Findings returns the findings of the last check.
+//
+// Returns:
+//   - []Finding: The findings, soonest first.
+func (c *Checker) Findings() []Finding {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Finding(nil), c.findings...)
+}
+
+// HealthCheck reports the findings of the last check to the doctor, running
+// a check first if there was none.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the first check.
+//
+// Returns:
+//   - health.CheckResult: "degraded" with the findings, or "ok".
+func (c *Checker) HealthCheck(ctx context.Context) health.CheckResult {
+	c.mu.Lock()
+	checked, disabled := c.checked, c.disabled
+	c.mu.Unlock()
+	if disabled {
+		return health.CheckResult{Status: "ok", Message: "expiry alerts are disabled"}
+	}
+	if !checked {
+		c.Check(ctx)
+	}
+	findings := c.Findings()
+	if len(findings) == 0 {
+		return health.CheckResult{Status: "ok"}
+	}
+	now := c.now()
+	messages := make([]string, len(findings))
+	for i, f := range findings {
+		messages[i] = f.summary(now)
+	}
+	return health.CheckResult{Status: "degraded", Message: strings.Join(messages, "; ")}
+}
+
+// Start checks periodically until Stop is called.
+//
+// Summary: Starts background expiry checks.
+//
+// Parameters:
+//   - ctx: context.Context. Checking also stops when it is cancelled.
+//
+// Returns:
+//   - error: Always nil.
+//
+// Side Effects:
+//   - Starts a goroutine.
+func (c *Checker) Start(ctx context.Context) error {
+	c.mu.Lock()
+	if c.cancel != nil {
+		c.mu.Unlock()
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	c.cancel = cancel
+	c.done = make(chan struct{})
+	done := c.done
+	c.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		for {
+			c.Check(ctx)
+			c.mu.Lock()
+			interval := c.interval
+			c.mu.Unlock()
+			timer := time.NewTimer(interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the periodic checks.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the wait for a running check.
+//
+// Returns:
+//   - error: The context error if the check did not stop in time.
+func (c *Checker) Stop(ctx context.Context) error {
+	c.mu.Lock()
+	cancel, done := c.cancel, c.done
+	c.cancel, c.done = nil, nil
+	c.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// credentialDeadlines returns the expiries and due rotations of the stored
+// credentials.
+func (c *Checker) credentialDeadlines(ctx context.Context) []deadline {
+	if c.credentials == nil {
+		return nil
+	}
+	creds, err := c.credentials.ListCredentials(ctx)
+	if err != nil {
+		logging.GetLogger().Warn("Failed to list credentials for expiry check", "error", err)
+		return nil
+	}
+	var deadlines []deadline
+	for _, cred := range creds {
+		name := cred.GetName()
+		if name == "" {
+			name = cred.GetId()
+		}
+		if v := cred.GetExpiresAt(); v != "" {
+			if at, err := time.Parse(time.RFC3339, v); err == nil {
+				deadlines = append(deadlines, deadline{kind: KindCredential, name: name, source: cred.GetId(), at: at})
+			} else {
+				logging.GetLogger().Warn("Ignoring malformed credential expiry", "credential", cred.GetId(), "expires_at", v)
+			}
+		}
+		interval := cred.GetRotationPolicy().GetInterval().AsDuration()
+		if interval <= 0 || cred.GetRotatedAt() == "" {
+			continue
+		}
+		if rotatedAt, err := time.Parse(time.RFC3339, cred.GetRotatedAt()); err == nil {
+			deadlines = append(deadlines, deadline{kind: KindCredential, name: name, source: cred.GetId(), at: rotatedAt.Add(interval), rotation: true})
+		}
+	}
+	return deadlines
+}
+
+// certificateDeadlines returns the expiries of the client certificate of a
+// service and of the certificate chain its TLS upstream presents.
+func certificateDeadlines(ctx context.Context, svc *configv1.UpstreamServiceConfig) []deadline {
+	address, tlsConfig := tlsEndpoint(svc)
+	tlsConfig = util.TLSConfigWithMTLS(tlsConfig, svc.GetUpstreamAuth().GetMtls())
+	var deadlines []deadline
+
+	if path := tlsConfig.GetClientCertPath(); path != "" {
+		if cert, err := readCertificate(path); err == nil {
+			deadlines = append(deadlines, deadline{
+				kind: KindTLSCertificate, name: svc.GetName(), source: path,
+				subject: cert.Subject.String(), at: cert.NotAfter,
+			})
+		} else {
+			logging.GetLogger().Warn("Failed to read client certificate for expiry check", "service", svc.GetName(), "path", path, "error", err)
+		}
+	}
+
+	if address == "" {
+		return deadlines
+	}
+	chain, err := peerCertificates(ctx, address, tlsConfig)
+	if err != nil {
+		// Reachability is reported by the health checks.
+		logging.GetLogger().Debug("Failed to fetch upstream certificate for expiry check", "service", svc.GetName(), "address", address, "error", err)
+		return deadlines
+	}
+	// The chain is only as valid as its first certificate to expire.
+	var earliest *x509.Certificate
+	for _, cert := range chain {
+		if earliest == nil || cert.NotAfter.Before(earliest.NotAfter) {
+			earliest = cert
+		}
+	}
+	if earliest != nil {
+		deadlines = append(deadlines, deadline{
+			kind: KindTLSCertificate, name: svc.GetName(), source: address,
+			subject: earliest.Subject.String(), at: earliest.NotAfter,
+		})
+	}
+	return deadlines
+}
+
+// Certificate is a certificate a service uses: its client certificate, or
+// the first certificate to expire in the chain its TLS upstream presents.
+type Certificate struct {
+	// Source is the certificate file, or the upstream address.
+	Source string
+	// Subject is the subject of the certificate.
+	Subject string
+	// NotAfter is when the certificate expires.
+	NotAfter time.Time
+}
+
+// ServiceCertificates returns the certificates of a service, for the doctor.
+// Unlike Check, it fails when a certificate cannot be read or the upstream
+// cannot be reached.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the TLS handshake.
+//   - svc: *configv1.UpstreamServiceConfig. The service.
+//
+// Returns:
+//   - []Certificate: The certificates, none if the service uses no TLS.
+//   - error: An error if a certificate cannot be read or fetched.
+//
+// Side Effects:
+//   - Connects to the upstream of the service.
+func ServiceCertificates(ctx context.Context, svc *configv1.UpstreamServiceConfig) ([]Certificate, error) {
+	address, tlsConfig := tlsEndpoint(svc)
+	tlsConfig = util.TLSConfigWithMTLS(tlsConfig, svc.GetUpstreamAuth().GetMtls())
+	var certs []Certificate
+	if path := tlsConfig.GetClientCertPath(); path != "" {
+		cert, err := readCertificate(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client certificate %s: %w", path, err)
+		}
+		certs = append(certs, Certificate{Source: path, Subject: cert.Subject.String(), NotAfter: cert.NotAfter})
+	}
+	if address == "" {
+		return certs, nil
+	}
+	chain, err := peerCertificates(ctx, address, tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("TLS handshake with %s failed: %w", address, err)
+	}
+	var earliest *x509.Certificate
+	for _, cert := range chain {
+		if earliest == nil || cert.NotAfter.Before(earliest.NotAfter) {
+			earliest = cert
+		}
+	}
+	if earliest != nil {
+		certs = append(certs, Certificate{Source: address, Subject: earliest.Subject.String(), NotAfter: earliest.NotAfter})
+	}
+	return certs, nil
+}
+
+// tlsEndpoint returns the host:port of a service reached over TLS, or "" if
+// it is not, and the service's TLS settings.
+func tlsEndpoint(svc *configv1.UpstreamServiceConfig) (string, *configv1.TLSConfig) {
+	switch {
+	case svc.HasHttpService():
+		return urlEndpoint(svc.GetHttpService().GetAddress()), svc.GetHttpService().GetTlsConfig()
+	case svc.HasOpenapiService():
+		return urlEndpoint(svc.GetOpenapiService().GetAddress()), svc.GetOpenapiService().GetTlsConfig()
+	case svc.HasWebsocketService():
+		return urlEndpoint(svc.GetWebsocketService().GetAddress()), svc.GetWebsocketService().GetTlsConfig()
+	case svc.HasWebrtcService():
+		return urlEndpoint(svc.GetWebrtcService().GetAddress()), svc.GetWebrtcService().GetTlsConfig()
+	case svc.HasMcpService():
+		conn := svc.GetMcpService().GetHttpConnection()
+		return urlEndpoint(conn.GetHttpAddress()), conn.GetTlsConfig()
+	case svc.HasGrpcService():
+		// gRPC upstreams use TLS when they have TLS settings.
+		grpc := svc.GetGrpcService()
+		if grpc.HasTlsConfig() {
+			return grpc.GetAddress(), grpc.GetTlsConfig()
+		}
+		return "", nil
+	case svc.HasGraphqlService():
+		return urlEndpoint(svc.GetGraphqlService().GetAddress()), nil
+	}
+	return "", nil
+}
+
+// urlEndpoint returns the host:port of an https or wss URL, or "".
+func urlEndpoint(address string) string {
+	u, err := url.Parse(address)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "wss") || u.Hostname() == "" {
+		return ""
+	}
+	port := u.Port()
+	if port == "" {
+		port = "443"
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// peerCertificates returns the certificate chain an upstream presents. The
+// chain is not verified, so that expired certificates are reported rather
+// than failing the handshake.
+func peerCertificates(ctx context.Context, address string, tlsConfig *configv1.TLSConfig) ([]*x509.Certificate, error) {
+	cfg, err := util.NewTLSClientConfig(tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	cfg.InsecureSkipVerify = true //nolint:gosec // Only the certificate dates are read.
+	if cfg.ServerName == "" {
+		cfg.ServerName, _, _ = net.SplitHostPort(address)
+	}
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+	dialer := &tls.Dialer{Config: cfg}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+	return conn.(*tls.Conn).ConnectionState().PeerCertificates, nil
+}
+
+// readCertificate returns the first certificate of a PEM file.
+func readCertificate(path string) (*x509.Certificate, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // The path comes from the configuration.
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("no certificate in %s", path)
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package expiry
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type credentialList []*configv1.Credential
+
+func (l credentialList) ListCredentials(context.Context) ([]*configv1.Credential, error) {
+	return l, nil
+}
+
+// enabled returns a configuration with the check enabled and the services.
+func enabled(services []*configv1.UpstreamServiceConfig) *configv1.McpAnyServerConfig {
+	return configv1.McpAnyServerConfig_builder{
+		GlobalSettings: configv1.GlobalSettings_builder{
+			ExpiryAlerts: configv1.ExpiryAlertConfig_builder{Enabled: proto.Bool(true)}.Build(),
+		}.Build(),
+		UpstreamServices: services,
+	}.Build()
+}
+
+func writeCertificate(t *testing.T, notAfter time.Time) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mcpany-client"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	dir := t.TempDir()
+	certPath, keyPath := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certPath, keyPath
+}
+
+func TestChecker_Credentials(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	creds := credentialList{
+		configv1.Credential_builder{Id: proto.String("github-pat"), Name: proto.String("GitHub"), ExpiresAt: proto.String(now.Add(72 * time.Hour).Format(time.RFC3339))}.Build(),
+		configv1.Credential_builder{Id: proto.String("stripe"), ExpiresAt: proto.String(now.Add(-time.Hour).Format(time.RFC3339))}.Build(),
+		configv1.Credential_builder{Id: proto.String("far"), ExpiresAt: proto.String(now.Add(60 * 24 * time.Hour).Format(time.RFC3339))}.Build(),
+		configv1.Credential_builder{
+			Id:             proto.String("slack"),
+			RotatedAt:      proto.String(now.Add(-100 * 24 * time.Hour).Format(time.RFC3339)),
+			RotationPolicy: configv1.CredentialRotationPolicy_builder{Interval: durationpb.New(90 * 24 * time.Hour)}.Build(),
+		}.Build(),
+		configv1.Credential_builder{Id: proto.String("never-rotated"), RotationPolicy: configv1.CredentialRotationPolicy_builder{Interval: durationpb.New(time.Hour)}.Build()}.Build(),
+		configv1.Credential_builder{Id: proto.String("malformed"), ExpiresAt: proto.String("tomorrow")}.Build(),
+	}
+	c := NewChecker(creds, WithClock(func() time.Time { return now }))
+	c.Configure(enabled(nil))
+
+	findings := c.Check(context.Background())
+	require.Len(t, findings, 3)
+	assert.Equal(t, Finding{Kind: KindCredential, Name: "slack", Source: "slack", State: StateRotationDue, At: now.Add(-10 * 24 * time.Hour)}, findings[0])
+	assert.Equal(t, Finding{Kind: KindCredential, Name: "stripe", Source: "stripe", State: StateExpired, At: now.Add(-time.Hour)}, findings[1])
+	assert.Equal(t, Finding{Kind: KindCredential, Name: "GitHub", Source: "github-pat", State: StateExpiring, At: now.Add(72 * time.Hour)}, findings[2])
+	assert.Equal(t, findings, c.Findings())
+
+	result := c.HealthCheck(context.Background())
+	assert.Equal(t, "degraded", result.Status)
+	assert.Equal(t, `credential "slack" (slack) rotation overdue by 240h0m0s; `+
+		`credential "stripe" (stripe) expired 1h0m0s ago; `+
+		`credential "GitHub" (github-pat) expires in 72h0m0s`, result.Message)
+
+	// A wider window includes the credential expiring in 60 days.
+	c.Configure(configv1.McpAnyServerConfig_builder{
+		GlobalSettings: configv1.GlobalSettings_builder{
+			ExpiryAlerts: configv1.ExpiryAlertConfig_builder{Enabled: proto.Bool(true), WarnBefore: durationpb.New(90 * 24 * time.Hour)}.Build(),
+		}.Build(),
+	}.Build())
+	assert.Len(t, c.Check(context.Background()), 4)
+
+	// The check is off unless enabled.
+	c.Configure(&configv1.McpAnyServerConfig{})
+	assert.Empty(t, c.Check(context.Background()))
+	assert.Equal(t, "ok", c.HealthCheck(context.Background()).Status)
+}
+
+func TestChecker_Certificates(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+	serverCert := server.Certificate()
+
+	// Pretend the server certificate expires in two days.
+	now := serverCert.NotAfter.Add(-48 * time.Hour)
+	clientCertPath, clientKeyPath := writeCertificate(t, now.Add(24*time.Hour))
+
+	c := NewChecker(nil, WithClock(func() time.Time { return now }))
+	c.Configure(enabled([]*configv1.UpstreamServiceConfig{
+		configv1.UpstreamServiceConfig_builder{
+			Name: proto.String("api"),
+			HttpService: configv1.HttpUpstreamService_builder{
+				Address: proto.String(server.URL),
+				TlsConfig: configv1.TLSConfig_builder{
+					ClientCertPath: proto.String(clientCertPath),
+					ClientKeyPath:  proto.String(clientKeyPath),
+				}.Build(),
+			}.Build(),
+		}.Build(),
+		configv1.UpstreamServiceConfig_builder{
+			Name:        proto.String("plain"),
+			HttpService: configv1.HttpUpstreamService_builder{Address: proto.String("http://127.0.0.1:1")}.Build(),
+		}.Build(),
+	}))
+
+	findings := c.Check(context.Background())
+	require.Len(t, findings, 2)
+	assert.Equal(t, KindTLSCertificate, findings[0].Kind)
+	assert.Equal(t, clientCertPath, findings[0].Source)
+	assert.Equal(t, "CN=mcpany-client", findings[0].Subject)
+	assert.Equal(t, StateExpiring, findings[0].State)
+
+	assert.Equal(t, "api", findings[1].Name)
+	assert.Equal(t, server.Listener.Addr().String(), findings[1].Source)
+	assert.Equal(t, serverCert.NotAfter, findings[1].At)
+	assert.Equal(t, StateExpiring, findings[1].State)
+}
+
+func TestServiceCertificates(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+
+	certs, err := ServiceCertificates(context.Background(), configv1.UpstreamServiceConfig_builder{
+		Name:        proto.String("api"),
+		HttpService: configv1.HttpUpstreamService_builder{Address: proto.String(server.URL)}.Build(),
+	}.Build())
+	require.NoError(t, err)
+	require.Len(t, certs, 1)
+	assert.Equal(t, server.Listener.Addr().String(), certs[0].Source)
+	assert.Equal(t, server.Certificate().NotAfter, certs[0].NotAfter)
+
+	certs, err = ServiceCertificates(context.Background(), configv1.UpstreamServiceConfig_builder{
+		Name:        proto.String("plain"),
+		HttpService: configv1.HttpUpstreamService_builder{Address: proto.String("http://127.0.0.1:1")}.Build(),
+	}.Build())
+	require.NoError(t, err)
+	assert.Empty(t, certs)
+
+	_, err = ServiceCertificates(context.Background(), configv1.UpstreamServiceConfig_builder{
+		Name:        proto.String("down"),
+		HttpService: configv1.HttpUpstreamService_builder{Address: proto.String("https://127.0.0.1:1")}.Build(),
+	}.Build())
+	assert.ErrorContains(t, err, "TLS handshake with 127.0.0.1:1 failed")
+}
+
+func TestURLEndpoint(t *testing.T) {
+	assert.Equal(t, "api.example.com:443", urlEndpoint("https://api.example.com/v1"))
+	assert.Equal(t, "ws.example.com:8443", urlEndpoint("wss://ws.example.com:8443"))
+	assert.Equal(t, "", urlEndpoint("http://api.example.com"))
+	assert.Equal(t, "", urlEndpoint("localhost:50051"))
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package expiry
+
+import (
+	"fmt"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// StampRotation validates the lifecycle timestamps of a credential being
+// saved and records when it was rotated. A new credential, or one whose
+// authentication changed, counts as rotated now unless rotated_at is given.
+//
+// Parameters:
+//   - cred (*configv1.Credential): The credential being saved.
+//   - existing (*configv1.Credential): The stored credential it replaces, or nil.
+//   - now (time.Time): The current time.
+//
+// Returns:
+//   - error: An error if expires_at or rotated_at is not an RFC3339 timestamp.
+//
+// Side Effects:
+//   - Sets the rotated_at of cred.
+func StampRotation(cred, existing *configv1.Credential, now time.Time) error {
+	for field, value := range map[string]string{"expires_at": cred.GetExpiresAt(), "rotated_at": cred.GetRotatedAt()} {
+		if value == "" {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return fmt.Errorf("invalid %s %q: must be an RFC3339 timestamp", field, value)
+		}
+	}
+	if cred.GetRotatedAt() != "" {
+		return nil
+	}
+	if existing != nil && proto.Equal(existing.GetAuthentication(), cred.GetAuthentication()) {
+		cred.SetRotatedAt(existing.GetRotatedAt())
+		return nil
+	}
+	cred.SetRotatedAt(now.UTC().Format(time.RFC3339))
+	return nil
+}
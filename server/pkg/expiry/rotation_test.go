@@ -0,0 +1,43 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package expiry
+
+import (
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestStampRotation(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	auth1 := configv1.Authentication_builder{
+		BearerToken: configv1.BearerTokenAuth_builder{Token: configv1.SecretValue_builder{PlainText: proto.String("one")}.Build()}.Build(),
+	}.Build()
+	auth2 := configv1.Authentication_builder{
+		BearerToken: configv1.BearerTokenAuth_builder{Token: configv1.SecretValue_builder{PlainText: proto.String("two")}.Build()}.Build(),
+	}.Build()
+
+	created := configv1.Credential_builder{Id: proto.String("c"), Authentication: auth1}.Build()
+	require.NoError(t, StampRotation(created, nil, now))
+	assert.Equal(t, "2026-03-01T12:00:00Z", created.GetRotatedAt())
+
+	unchanged := configv1.Credential_builder{Id: proto.String("c"), Name: proto.String("renamed"), Authentication: auth1}.Build()
+	require.NoError(t, StampRotation(unchanged, created, now.Add(time.Hour)))
+	assert.Equal(t, "2026-03-01T12:00:00Z", unchanged.GetRotatedAt())
+
+	rotated := configv1.Credential_builder{Id: proto.String("c"), Authentication: auth2}.Build()
+	require.NoError(t, StampRotation(rotated, created, now.Add(time.Hour)))
+	assert.Equal(t, "2026-03-01T13:00:00Z", rotated.GetRotatedAt())
+
+	explicit := configv1.Credential_builder{Id: proto.String("c"), Authentication: auth2, RotatedAt: proto.String("2026-01-01T00:00:00Z")}.Build()
+	require.NoError(t, StampRotation(explicit, created, now))
+	assert.Equal(t, "2026-01-01T00:00:00Z", explicit.GetRotatedAt())
+
+	invalid := configv1.Credential_builder{Id: proto.String("c"), ExpiresAt: proto.String("next week")}.Build()
+	assert.ErrorContains(t, StampRotation(invalid, nil, now), `invalid expires_at "next week"`)
+}
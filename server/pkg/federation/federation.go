@@ -0,0 +1,220 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package federation lets one mcpany instance mount another as an MCP
+// upstream (a peer), so that deployments can be layered, e.g. team proxies
+// behind an org-level proxy.
+//
+// Calls between instances carry the chain of instances they passed through in
+// the ViaHeader. An instance rejects calls that already passed through it,
+// which breaks loops between peers that mount each other.
+package federation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	// ViaHeader carries the comma-separated IDs of the mcpany instances a
+	// call passed through, oldest first.
+	ViaHeader = "X-MCP-Any-Via"
+	// ProfileHeader selects the profile a peer serves the call with.
+	ProfileHeader = "X-MCP-Any-Profile"
+	// PeerRole is the role of the credentials a calling mcpany instance
+	// presents. Only these may select any profile with the ProfileHeader.
+	PeerRole = "federation-peer"
+	// DefaultMaxHops is the number of instances a call may pass through
+	// before reaching a peer whose max_hops is not set.
+	DefaultMaxHops = 8
+)
+
+var (
+	// ErrLoopDetected is returned when a call reaches an instance it already
+	// passed through.
+	ErrLoopDetected = errors.New("federation loop detected")
+	// ErrTooManyHops is returned when a call would exceed the max_hops of a
+	// peer.
+	ErrTooManyHops = errors.New("too many federation hops")
+	// ErrProfileNotAllowed is returned when a caller that is not a peer
+	// requests a profile it may not use.
+	ErrProfileNotAllowed = errors.New("profile not allowed")
+)
+
+// instanceID identifies this process in the ViaHeader.
+var instanceID = uuid.NewString()
+
+// InstanceID returns the ID of this mcpany instance.
+//
+// Summary: Identifies the instance in federated calls.
+//
+// Returns:
+//   - string: A random ID generated at startup.
+func InstanceID() string {
+	return instanceID
+}
+
+type viaContextKey struct{}
+
+// ContextWithVia returns a new context carrying the instances a call passed
+// through before reaching this one.
+//
+// Parameters:
+//   - ctx: context.Context. The parent context.
+//   - via: []string. The instance IDs, oldest first.
+//
+// Returns:
+//   - context.Context: The new context.
+func ContextWithVia(ctx context.Context, via []string) context.Context {
+	return context.WithValue(ctx, viaContextKey{}, via)
+}
+
+// ViaFromContext returns the instances a call passed through before reaching
+// this one.
+//
+// Parameters:
+//   - ctx: context.Context. The context to read from.
+//
+// Returns:
+//   - []string: The instance IDs, oldest first, or nil for a direct call.
+func ViaFromContext(ctx context.Context) []string {
+	via, _ := ctx.Value(viaContextKey{}).([]string)
+	return via
+}
+
+// ParseVia parses the value of the ViaHeader.
+//
+// Parameters:
+//   - header: string. The header value.
+//
+// Returns:
+//   - []string: The non-empty instance IDs.
+func ParseVia(header string) []string {
+	var via []string
+	for _, id := range strings.Split(header, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			via = append(via, id)
+		}
+	}
+	return via
+}
+
+// Middleware rejects calls that already passed through this instance and
+// records the chain of a federated call in the request context.
+//
+// Summary: HTTP middleware for federation loop detection.
+//
+// Parameters:
+//   - next: http.Handler. The handler to wrap.
+//
+// Returns:
+//   - http.Handler: The wrapped handler. Looping calls get 508 Loop Detected.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		via := ParseVia(r.Header.Get(ViaHeader))
+		if len(via) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if slices.Contains(via, instanceID) {
+			http.Error(w, ErrLoopDetected.Error(), http.StatusLoopDetected)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ContextWithVia(r.Context(), via)))
+	})
+}
+
+// ProfileAccess looks up the users and profiles a requested profile is
+// checked against.
+type ProfileAccess interface {
+	// GetUser returns a user by ID.
+	GetUser(id string) (*configv1.User, bool)
+	// GetProfileDefinition returns a profile definition by name.
+	GetProfileDefinition(name string) (*configv1.ProfileDefinition, bool)
+}
+
+// ApplyProfile serves a call with the profile requested in the
+// ProfileHeader.
+//
+// Summary: Honors the profile selected by a calling mcpany instance.
+//
+// Credentials with the PeerRole, i.e. those of a calling mcpany instance,
+// may select any profile. Other callers may only select a profile of their
+// user that they hold a required role of, as on the
+// /mcp/u/{user}/profile/{profile} route.
+//
+// Parameters:
+//   - ctx: context.Context. The authenticated context of the call.
+//   - r: *http.Request. The HTTP request of the call.
+//   - access: ProfileAccess. The users and profile definitions.
+//
+// Returns:
+//   - context.Context: The context with the requested profile, or ctx if no
+//     profile was requested.
+//   - error: An error if the credentials are bound to a different profile or
+//     the caller may not use the requested profile.
+func ApplyProfile(ctx context.Context, r *http.Request, access ProfileAccess) (context.Context, error) {
+	requested := r.Header.Get(ProfileHeader)
+	if requested == "" {
+		return ctx, nil
+	}
+	if current, ok := auth.ProfileIDFromContext(ctx); ok && current != "" {
+		if current != requested {
+			return ctx, fmt.Errorf("credentials are bound to profile %q, not %q", current, requested)
+		}
+		return ctx, nil
+	}
+	if roles, _ := auth.RolesFromContext(ctx); slices.Contains(roles, PeerRole) {
+		return auth.ContextWithProfileID(ctx, requested), nil
+	}
+
+	userID, _ := auth.UserFromContext(ctx)
+	user, ok := access.GetUser(userID)
+	if !ok || !slices.Contains(user.GetProfileIds(), requested) {
+		return ctx, fmt.Errorf("%w: %q", ErrProfileNotAllowed, requested)
+	}
+	if def, ok := access.GetProfileDefinition(requested); ok && len(def.GetRequiredRoles()) > 0 {
+		if !slices.ContainsFunc(def.GetRequiredRoles(), func(role string) bool {
+			return slices.Contains(user.GetRoles(), role)
+		}) {
+			return ctx, fmt.Errorf("%w: %q", ErrProfileNotAllowed, requested)
+		}
+	}
+	return auth.ContextWithProfileID(ctx, requested), nil
+}
+
+// Resilience returns the resilience settings of a peer service. Peers get a
+// circuit breaker by default so that an unavailable peer fails fast instead
+// of holding up every call routed through it.
+//
+// Parameters:
+//   - cfg: *configv1.ResilienceConfig. The configured settings; may be nil.
+//
+// Returns:
+//   - *configv1.ResilienceConfig: cfg, or a copy with the default breaker.
+func Resilience(cfg *configv1.ResilienceConfig) *configv1.ResilienceConfig {
+	if cfg.GetCircuitBreaker() != nil {
+		return cfg
+	}
+	out := &configv1.ResilienceConfig{}
+	if cfg != nil {
+		out = proto.Clone(cfg).(*configv1.ResilienceConfig)
+	}
+	out.SetCircuitBreaker(configv1.CircuitBreakerConfig_builder{
+		ConsecutiveFailures: proto.Int32(5),
+		OpenDuration:        durationpb.New(30 * time.Second),
+		HalfOpenRequests:    proto.Int32(1),
+	}.Build())
+	return out
+}
@@ -0,0 +1,166 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package federation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestParseVia(t *testing.T) {
+	assert.Nil(t, ParseVia(""))
+	assert.Equal(t, []string{"a", "b"}, ParseVia(" a, ,b "))
+}
+
+func TestMiddleware(t *testing.T) {
+	var gotVia []string
+	handler := Middleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		gotVia = ViaFromContext(r.Context())
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/mcp", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Nil(t, gotVia)
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set(ViaHeader, "org, team")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []string{"org", "team"}, gotVia)
+
+	req = httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set(ViaHeader, "org,"+InstanceID()+",team")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusLoopDetected, rec.Code)
+}
+
+type fakeProfileAccess struct {
+	users    map[string]*configv1.User
+	profiles map[string]*configv1.ProfileDefinition
+}
+
+func (f fakeProfileAccess) GetUser(id string) (*configv1.User, bool) {
+	u, ok := f.users[id]
+	return u, ok
+}
+
+func (f fakeProfileAccess) GetProfileDefinition(name string) (*configv1.ProfileDefinition, bool) {
+	p, ok := f.profiles[name]
+	return p, ok
+}
+
+func TestApplyProfile(t *testing.T) {
+	access := fakeProfileAccess{
+		users: map[string]*configv1.User{
+			"alice": configv1.User_builder{
+				Id:         proto.String("alice"),
+				Roles:      []string{"viewer"},
+				ProfileIds: []string{"team-dev", "team-admin"},
+			}.Build(),
+		},
+		profiles: map[string]*configv1.ProfileDefinition{
+			"team-admin": configv1.ProfileDefinition_builder{
+				Name:          proto.String("team-admin"),
+				RequiredRoles: []string{"admin"},
+			}.Build(),
+		},
+	}
+	peer := auth.ContextWithRoles(auth.ContextWithUser(context.Background(), "apikey:org"), []string{PeerRole})
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	ctx, err := ApplyProfile(context.Background(), req, access)
+	require.NoError(t, err)
+	_, ok := auth.ProfileIDFromContext(ctx)
+	assert.False(t, ok)
+
+	req.Header.Set(ProfileHeader, "team-dev")
+	ctx, err = ApplyProfile(peer, req, access)
+	require.NoError(t, err)
+	profile, _ := auth.ProfileIDFromContext(ctx)
+	assert.Equal(t, "team-dev", profile)
+
+	_, err = ApplyProfile(auth.ContextWithProfileID(context.Background(), "team-dev"), req, access)
+	assert.NoError(t, err)
+
+	_, err = ApplyProfile(auth.ContextWithProfileID(peer, "readonly"), req, access)
+	assert.ErrorContains(t, err, `credentials are bound to profile "readonly"`)
+
+	// Users that are not peers may only select their own profiles.
+	ctx, err = ApplyProfile(auth.ContextWithUser(context.Background(), "alice"), req, access)
+	require.NoError(t, err)
+	profile, _ = auth.ProfileIDFromContext(ctx)
+	assert.Equal(t, "team-dev", profile)
+}
+
+func TestApplyProfile_RejectsCallersThatAreNotPeers(t *testing.T) {
+	access := fakeProfileAccess{
+		users: map[string]*configv1.User{
+			"alice": configv1.User_builder{
+				Id:         proto.String("alice"),
+				Roles:      []string{"viewer"},
+				ProfileIds: []string{"team-dev", "team-admin"},
+			}.Build(),
+		},
+		profiles: map[string]*configv1.ProfileDefinition{
+			"team-admin": configv1.ProfileDefinition_builder{
+				Name:          proto.String("team-admin"),
+				RequiredRoles: []string{"admin"},
+			}.Build(),
+		},
+	}
+	request := func(profile string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.Header.Set(ProfileHeader, profile)
+		return req
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		profile string
+	}{
+		{"profile of another user", auth.ContextWithUser(context.Background(), "alice"), "payments-admin"},
+		{"missing required role", auth.ContextWithUser(context.Background(), "alice"), "team-admin"},
+		{"role claimed by context only", auth.ContextWithRoles(auth.ContextWithUser(context.Background(), "alice"), []string{"admin"}), "team-admin"},
+		{"unknown user", auth.ContextWithUser(context.Background(), "apikey:ci"), "team-dev"},
+		{"anonymous", context.Background(), "team-dev"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := ApplyProfile(tt.ctx, request(tt.profile), access)
+			assert.ErrorIs(t, err, ErrProfileNotAllowed)
+			_, ok := auth.ProfileIDFromContext(ctx)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestResilience(t *testing.T) {
+	cfg := Resilience(nil)
+	assert.Equal(t, int32(5), cfg.GetCircuitBreaker().GetConsecutiveFailures())
+
+	timeout := configv1.ResilienceConfig_builder{Timeout: durationpb.New(time.Second)}.Build()
+	cfg = Resilience(timeout)
+	assert.NotNil(t, cfg.GetCircuitBreaker())
+	assert.Equal(t, time.Second, cfg.GetTimeout().AsDuration())
+	assert.Nil(t, timeout.GetCircuitBreaker(), "the configuration must not be modified")
+
+	breaker := configv1.ResilienceConfig_builder{
+		CircuitBreaker: configv1.CircuitBreakerConfig_builder{ConsecutiveFailures: proto.Int32(2)}.Build(),
+	}.Build()
+	assert.Same(t, breaker, Resilience(breaker))
+}
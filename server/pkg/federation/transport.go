@@ -0,0 +1,209 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package federation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"google.golang.org/protobuf/proto"
+)
+
+// Transport is an http.RoundTripper for calls to a peer. It authenticates
+// with the peer, selects the peer profile mapped from the caller's profile
+// and extends the ViaHeader chain.
+type Transport struct {
+	base           http.RoundTripper
+	address        string
+	defaultProfile string
+	profileMapping map[string]string
+	maxHops        int
+
+	mu            sync.Mutex
+	oauth2        *configv1.OAuth2Auth
+	authenticator auth.UpstreamAuthenticator
+}
+
+// NewTransport creates the transport for calls to a peer.
+//
+// Summary: Wraps a transport with the peer handshake.
+//
+// Parameters:
+//   - address: string. The MCP endpoint of the peer.
+//   - cfg: *configv1.McpPeerConfig. The peer configuration.
+//   - base: http.RoundTripper. The transport that sends the requests; nil
+//     means http.DefaultTransport.
+//
+// Returns:
+//   - *Transport: The transport.
+//   - error: An error if the credentials are invalid.
+func NewTransport(address string, cfg *configv1.McpPeerConfig, base http.RoundTripper) (*Transport, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &Transport{
+		base:           base,
+		address:        address,
+		defaultProfile: cfg.GetDefaultProfile(),
+		profileMapping: cfg.GetProfileMapping(),
+		maxHops:        int(cfg.GetMaxHops()),
+	}
+	if t.maxHops <= 0 {
+		t.maxHops = DefaultMaxHops
+	}
+
+	switch cfg.WhichCredentials() {
+	case configv1.McpPeerConfig_ApiKey_case:
+		authenticator, err := auth.NewUpstreamAuthenticator(configv1.Authentication_builder{
+			ApiKey: configv1.APIKeyAuth_builder{
+				ParamName: proto.String("X-API-Key"),
+				Value:     cfg.GetApiKey(),
+			}.Build(),
+		}.Build())
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer api_key: %w", err)
+		}
+		t.authenticator = authenticator
+	case configv1.McpPeerConfig_Oauth2_case:
+		oauth2 := cfg.GetOauth2()
+		if oauth2.GetTokenUrl() == "" && oauth2.GetIssuerUrl() == "" {
+			// The authorization server is discovered on the first call.
+			t.oauth2 = oauth2
+			break
+		}
+		authenticator, err := auth.NewUpstreamAuthenticator(configv1.Authentication_builder{Oauth2: oauth2}.Build())
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer oauth2: %w", err)
+		}
+		t.authenticator = authenticator
+	}
+	return t, nil
+}
+
+// Authenticates reports whether the transport presents its own credentials,
+// which replace the service's upstream_auth.
+//
+// Returns:
+//   - bool: True if api_key or oauth2 is configured.
+func (t *Transport) Authenticates() bool {
+	return t.authenticator != nil || t.oauth2 != nil
+}
+
+// RoundTrip sends a request to the peer.
+//
+// Parameters:
+//   - req: *http.Request. The request; its context carries the caller's
+//     profile and federation chain.
+//
+// Returns:
+//   - *http.Response: The response of the peer.
+//   - error: ErrTooManyHops if the chain is too long, or an error if the
+//     authentication fails.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	via := ViaFromContext(req.Context())
+	if len(via)+1 > t.maxHops {
+		return nil, fmt.Errorf("%w: call passed through %d instances, max_hops is %d", ErrTooManyHops, len(via), t.maxHops)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set(ViaHeader, strings.Join(append(via[:len(via):len(via)], instanceID), ","))
+	if profile := t.remoteProfile(req); profile != "" {
+		req.Header.Set(ProfileHeader, profile)
+	}
+
+	authenticator, err := t.getAuthenticator(req)
+	if err != nil {
+		return nil, err
+	}
+	if authenticator != nil {
+		if err := authenticator.Authenticate(req); err != nil {
+			return nil, fmt.Errorf("failed to authenticate with peer: %w", err)
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+// remoteProfile returns the peer profile for the caller's profile.
+func (t *Transport) remoteProfile(req *http.Request) string {
+	if profile, ok := auth.ProfileIDFromContext(req.Context()); ok {
+		if mapped, ok := t.profileMapping[profile]; ok {
+			return mapped
+		}
+	}
+	return t.defaultProfile
+}
+
+// getAuthenticator returns the authenticator, discovering the peer's
+// authorization server on first use.
+func (t *Transport) getAuthenticator(req *http.Request) (auth.UpstreamAuthenticator, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.authenticator != nil || t.oauth2 == nil {
+		return t.authenticator, nil
+	}
+
+	metadata, err := t.discover(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover peer authorization server: %w", err)
+	}
+	oauth2 := proto.Clone(t.oauth2).(*configv1.OAuth2Auth)
+	oauth2.SetIssuerUrl(metadata.AuthorizationServers[0])
+	if _, ok := oauth2.GetTokenParams()["resource"]; !ok && metadata.Resource != "" && oauth2.GetAudience() == "" {
+		// Bind the token to the peer (RFC 8707).
+		params := make(map[string]string, len(oauth2.GetTokenParams())+1)
+		for k, v := range oauth2.GetTokenParams() {
+			params[k] = v
+		}
+		params["resource"] = metadata.Resource
+		oauth2.SetTokenParams(params)
+	}
+	authenticator, err := auth.NewUpstreamAuthenticator(configv1.Authentication_builder{Oauth2: oauth2}.Build())
+	if err != nil {
+		return nil, fmt.Errorf("invalid peer oauth2: %w", err)
+	}
+	t.authenticator = authenticator
+	return authenticator, nil
+}
+
+// resourceMetadata is the part of the protected resource metadata (RFC 9728)
+// the handshake uses.
+type resourceMetadata struct {
+	Resource             string   `json:"resource"`
+	AuthorizationServers []string `json:"authorization_servers"`
+}
+
+// discover fetches the protected resource metadata of the peer.
+func (t *Transport) discover(req *http.Request) (*resourceMetadata, error) {
+	u, err := url.Parse(t.address)
+	if err != nil {
+		return nil, err
+	}
+	metadataURL := u.Scheme + "://" + u.Host + auth.ProtectedResourceMetadataPath + strings.TrimSuffix(u.Path, "/")
+	mreq, err := http.NewRequestWithContext(req.Context(), http.MethodGet, metadataURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(mreq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", metadataURL, resp.Status)
+	}
+	var metadata resourceMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+		return nil, fmt.Errorf("GET %s: %w", metadataURL, err)
+	}
+	if len(metadata.AuthorizationServers) == 0 {
+		return nil, fmt.Errorf("GET %s: no authorization_servers", metadataURL)
+	}
+	return &metadata, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestTransport_APIKeyAndProfiles(t *testing.T) {
+	var got http.Header
+	peer := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer peer.Close()
+
+	tr, err := NewTransport(peer.URL+"/mcp", configv1.McpPeerConfig_builder{
+		ApiKey:         configv1.SecretValue_builder{PlainText: proto.String("team-key")}.Build(),
+		DefaultProfile: proto.String("team-default"),
+		ProfileMapping: map[string]string{"dev": "team-dev"},
+	}.Build(), nil)
+	require.NoError(t, err)
+	assert.True(t, tr.Authenticates())
+	client := &http.Client{Transport: tr}
+
+	send := func(ctx context.Context) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, peer.URL+"/mcp", nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	send(context.Background())
+	assert.Equal(t, "team-key", got.Get("X-API-Key"))
+	assert.Equal(t, "team-default", got.Get(ProfileHeader))
+	assert.Equal(t, InstanceID(), got.Get(ViaHeader))
+
+	send(ContextWithVia(auth.ContextWithProfileID(context.Background(), "dev"), []string{"edge"}))
+	assert.Equal(t, "team-dev", got.Get(ProfileHeader))
+	assert.Equal(t, "edge,"+InstanceID(), got.Get(ViaHeader))
+}
+
+func TestTransport_MaxHops(t *testing.T) {
+	tr, err := NewTransport("http://peer.invalid/mcp", configv1.McpPeerConfig_builder{MaxHops: proto.Int32(2)}.Build(), nil)
+	require.NoError(t, err)
+	assert.False(t, tr.Authenticates())
+
+	req, err := http.NewRequestWithContext(ContextWithVia(context.Background(), []string{"a", "b"}), http.MethodPost, "http://peer.invalid/mcp", nil)
+	require.NoError(t, err)
+	_, err = tr.RoundTrip(req)
+	assert.ErrorIs(t, err, ErrTooManyHops)
+}
+
+func TestTransport_OAuth2Discovery(t *testing.T) {
+	var authorization, resource string
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	mux.HandleFunc(auth.ProtectedResourceMetadataPath+"/mcp", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"resource":              srv.URL + "/mcp",
+			"authorization_servers": []string{srv.URL},
+		})
+	})
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":         srv.URL,
+			"token_endpoint": srv.URL + "/token",
+			"jwks_uri":       srv.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		resource = r.Form.Get("resource")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "peer-token", "token_type": "Bearer", "expires_in": 3600})
+	})
+	mux.HandleFunc("/mcp", func(_ http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	})
+
+	secret := configv1.SecretValue_builder{PlainText: proto.String("secret")}.Build()
+	tr, err := NewTransport(srv.URL+"/mcp", configv1.McpPeerConfig_builder{
+		Oauth2: configv1.OAuth2Auth_builder{ClientId: secret, ClientSecret: secret}.Build(),
+	}.Build(), nil)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/mcp", nil)
+	require.NoError(t, err)
+	resp, err := tr.RoundTrip(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, "Bearer peer-token", authorization)
+	assert.Equal(t, srv.URL+"/mcp", resource)
+}
@@ -0,0 +1,222 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package fixtures
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/audit"
+	"github.com/mcpany/core/server/pkg/skill"
+	"github.com/mcpany/core/server/pkg/storage"
+	"github.com/mcpany/core/server/pkg/util/passhash"
+	"google.golang.org/protobuf/proto"
+)
+
+// Target is where fixtures are applied.
+type Target struct {
+	// Storage receives services, credentials, secrets, profiles, users and
+	// service templates.
+	Storage storage.Storage
+	// Audit receives audit entries. Audit entries are skipped if nil.
+	Audit audit.Store
+	// Skills receives skills. Skills are skipped if nil.
+	Skills *skill.Manager
+	// Now is the time audit entry ages are relative to; time.Now if nil.
+	Now func() time.Time
+}
+
+// Summary counts the records written by Apply.
+type Summary struct {
+	Services         int
+	Credentials      int
+	Secrets          int
+	Profiles         int
+	Users            int
+	ServiceTemplates int
+	AuditEntries     int
+	Skills           int
+	// Unchanged counts audit entries that were already present.
+	Unchanged int
+	// Skipped lists the record kinds that had no target.
+	Skipped []string
+}
+
+// String formats the summary for the command line.
+//
+// Returns:
+//   - string: The summary.
+func (s Summary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "services: %d, credentials: %d, secrets: %d, profiles: %d, users: %d, service templates: %d, audit entries: %d (%d already present), skills: %d",
+		s.Services, s.Credentials, s.Secrets, s.Profiles, s.Users, s.ServiceTemplates, s.AuditEntries, s.Unchanged, s.Skills)
+	if len(s.Skipped) > 0 {
+		fmt.Fprintf(&b, "; skipped: %s", strings.Join(s.Skipped, ", "))
+	}
+	return b.String()
+}
+
+// Apply writes the fixtures to the target. Records are upserted by their
+// name or ID, and audit entries that already exist are left alone, so applying
+// the same fixtures again leaves the target unchanged.
+//
+// Summary: Applies fixtures idempotently.
+//
+// Parameters:
+//   - ctx: context.Context. The context for the operation.
+//   - target: Target. Where the records are written.
+//   - fixtures: []*Fixture. The fixtures, applied in order.
+//
+// Returns:
+//   - Summary: The number of records written.
+//   - error: An error if a record cannot be written.
+func Apply(ctx context.Context, target Target, fixtures []*Fixture) (Summary, error) {
+	var sum Summary
+	if target.Storage == nil {
+		return sum, fmt.Errorf("fixtures: no storage")
+	}
+	now := time.Now
+	if target.Now != nil {
+		now = target.Now
+	}
+	existingAudit := make(map[string]map[string]bool)
+
+	for _, f := range fixtures {
+		if err := applyStorage(ctx, target.Storage, f, &sum); err != nil {
+			return sum, wrapSource(f, err)
+		}
+
+		if len(f.AuditEntries) > 0 && target.Audit == nil {
+			sum.Skipped = appendOnce(sum.Skipped, "audit entries")
+		} else {
+			for _, e := range f.AuditEntries {
+				ids, ok := existingAudit[e.ToolName]
+				if !ok {
+					entries, err := target.Audit.Read(ctx, audit.Filter{ToolName: e.ToolName})
+					if err != nil {
+						return sum, wrapSource(f, fmt.Errorf("failed to read audit entries: %w", err))
+					}
+					ids = make(map[string]bool, len(entries))
+					for _, existing := range entries {
+						ids[existing.ID] = true
+					}
+					existingAudit[e.ToolName] = ids
+				}
+				if ids[e.ID] {
+					sum.Unchanged++
+					continue
+				}
+				entry := e.Entry
+				if entry.Timestamp.IsZero() {
+					entry.Timestamp = now().Add(-e.Age).UTC()
+				}
+				if err := target.Audit.Write(ctx, entry); err != nil {
+					return sum, wrapSource(f, fmt.Errorf("failed to write audit entry %s: %w", e.ID, err))
+				}
+				ids[e.ID] = true
+				sum.AuditEntries++
+			}
+		}
+
+		if len(f.Skills) > 0 && target.Skills == nil {
+			sum.Skipped = appendOnce(sum.Skipped, "skills")
+		} else {
+			for _, s := range f.Skills {
+				var err error
+				if existing, _ := target.Skills.GetSkill(s.Name); existing != nil {
+					err = target.Skills.UpdateSkill(s.Name, s)
+				} else {
+					err = target.Skills.CreateSkill(s)
+				}
+				if err != nil {
+					return sum, wrapSource(f, fmt.Errorf("failed to save skill %s: %w", s.Name, err))
+				}
+				sum.Skills++
+			}
+		}
+	}
+	return sum, nil
+}
+
+func applyStorage(ctx context.Context, store storage.Storage, f *Fixture, sum *Summary) error {
+	for _, s := range f.Services {
+		if err := store.SaveService(ctx, s); err != nil {
+			return fmt.Errorf("failed to save service %s: %w", s.GetName(), err)
+		}
+		sum.Services++
+	}
+	for _, c := range f.Credentials {
+		if err := store.SaveCredential(ctx, c); err != nil {
+			return fmt.Errorf("failed to save credential %s: %w", c.GetId(), err)
+		}
+		sum.Credentials++
+	}
+	for _, s := range f.Secrets {
+		if err := store.SaveSecret(ctx, s); err != nil {
+			return fmt.Errorf("failed to save secret %s: %w", s.GetId(), err)
+		}
+		sum.Secrets++
+	}
+	for _, p := range f.Profiles {
+		if err := store.SaveProfile(ctx, p); err != nil {
+			return fmt.Errorf("failed to save profile %s: %w", p.GetName(), err)
+		}
+		sum.Profiles++
+	}
+	for _, u := range f.Users {
+		if err := saveUser(ctx, store, u); err != nil {
+			return fmt.Errorf("failed to save user %s: %w", u.GetId(), err)
+		}
+		sum.Users++
+	}
+	for _, t := range f.ServiceTemplates {
+		if err := store.SaveServiceTemplate(ctx, t); err != nil {
+			return fmt.Errorf("failed to save service template %s: %w", t.GetId(), err)
+		}
+		sum.ServiceTemplates++
+	}
+	return nil
+}
+
+// saveUser creates or replaces a user. Like the admin API, a password_hash
+// that is not a bcrypt hash is treated as a plain text password and hashed.
+func saveUser(ctx context.Context, store storage.Storage, u *configv1.User) error {
+	u = proto.Clone(u).(*configv1.User)
+	if basic := u.GetAuthentication().GetBasicAuth(); basic != nil {
+		if basic.GetPasswordHash() != "" && !strings.HasPrefix(basic.GetPasswordHash(), "$2") {
+			hashed, err := passhash.Password(basic.GetPasswordHash())
+			if err != nil {
+				return fmt.Errorf("failed to hash password: %w", err)
+			}
+			basic.SetPasswordHash(hashed)
+		}
+	}
+
+	existing, err := store.GetUser(ctx, u.GetId())
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return store.UpdateUser(ctx, u)
+	}
+	return store.CreateUser(ctx, u)
+}
+
+func wrapSource(f *Fixture, err error) error {
+	if f.Source == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %w", f.Source, err)
+}
+
+func appendOnce(list []string, s string) []string {
+	if slices.Contains(list, s) {
+		return list
+	}
+	return append(list, s)
+}
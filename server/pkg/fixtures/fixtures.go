@@ -0,0 +1,358 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package fixtures loads declarative seed data (services, users, audit
+// entries, skills, ...) from YAML or JSON files and applies it idempotently to
+// a server's storage.
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/audit"
+	"github.com/mcpany/core/server/pkg/skill"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+// Fixture is a set of seed data, usually read from one file.
+type Fixture struct {
+	// Source is the file the fixture was read from, if any.
+	Source string
+	// Scenarios are the scenarios the fixture belongs to. A fixture without
+	// scenarios belongs to every scenario.
+	Scenarios        []string
+	Services         []*configv1.UpstreamServiceConfig
+	Credentials      []*configv1.Credential
+	Secrets          []*configv1.Secret
+	Profiles         []*configv1.ProfileDefinition
+	Users            []*configv1.User
+	ServiceTemplates []*configv1.ServiceTemplate
+	AuditEntries     []AuditEntry
+	Skills           []*skill.Skill
+}
+
+// AuditEntry is an audit log entry of a fixture.
+type AuditEntry struct {
+	audit.Entry
+	// Age places the entry relative to the time the fixture is applied, so
+	// demo data stays recent. It is used when Timestamp is zero.
+	Age time.Duration
+}
+
+// document is the file format of a fixture. Protobuf messages are decoded
+// with protojson so they use the same field names as the server config.
+type document struct {
+	Scenarios        []string          `json:"scenarios"`
+	Services         []json.RawMessage `json:"upstream_services"`
+	Credentials      []json.RawMessage `json:"credentials"`
+	Secrets          []json.RawMessage `json:"secrets"`
+	Profiles         []json.RawMessage `json:"profiles"`
+	Users            []json.RawMessage `json:"users"`
+	ServiceTemplates []json.RawMessage `json:"service_templates"`
+	AuditEntries     []auditDocument   `json:"audit_entries"`
+	Skills           []skillDocument   `json:"skills"`
+}
+
+type auditDocument struct {
+	ID         string          `json:"id"`
+	Timestamp  time.Time       `json:"timestamp"`
+	Age        string          `json:"age"`
+	ToolName   string          `json:"tool_name"`
+	UserID     string          `json:"user_id"`
+	ProfileID  string          `json:"profile_id"`
+	APIKeyID   string          `json:"api_key_id"`
+	Arguments  json.RawMessage `json:"arguments"`
+	Result     any             `json:"result"`
+	Error      string          `json:"error"`
+	DurationMs int64           `json:"duration_ms"`
+}
+
+type skillDocument struct {
+	Name          string            `json:"name"`
+	Description   string            `json:"description"`
+	License       string            `json:"license"`
+	Compatibility string            `json:"compatibility"`
+	Metadata      map[string]string `json:"metadata"`
+	AllowedTools  []string          `json:"allowed_tools"`
+	Instructions  string            `json:"instructions"`
+}
+
+// Parse decodes a fixture from YAML or JSON.
+//
+// Summary: Parses a fixture document.
+//
+// Parameters:
+//   - data: []byte. The YAML or JSON document.
+//
+// Returns:
+//   - *Fixture: The fixture.
+//   - error: An error if the document is malformed or a record is invalid.
+func Parse(data []byte) (*Fixture, error) {
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid fixture: %w", err)
+	}
+	jsonData, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fixture: %w", err)
+	}
+	var doc document
+	if err := json.Unmarshal(jsonData, &doc); err != nil {
+		return nil, fmt.Errorf("invalid fixture: %w", err)
+	}
+
+	f := &Fixture{Scenarios: doc.Scenarios}
+	if f.Services, err = decodeAll(doc.Services, "upstream_services", func() *configv1.UpstreamServiceConfig { return &configv1.UpstreamServiceConfig{} }); err != nil {
+		return nil, err
+	}
+	if f.Credentials, err = decodeAll(doc.Credentials, "credentials", func() *configv1.Credential { return &configv1.Credential{} }); err != nil {
+		return nil, err
+	}
+	if f.Secrets, err = decodeAll(doc.Secrets, "secrets", func() *configv1.Secret { return &configv1.Secret{} }); err != nil {
+		return nil, err
+	}
+	if f.Profiles, err = decodeAll(doc.Profiles, "profiles", func() *configv1.ProfileDefinition { return &configv1.ProfileDefinition{} }); err != nil {
+		return nil, err
+	}
+	if f.Users, err = decodeAll(doc.Users, "users", func() *configv1.User { return &configv1.User{} }); err != nil {
+		return nil, err
+	}
+	if f.ServiceTemplates, err = decodeAll(doc.ServiceTemplates, "service_templates", func() *configv1.ServiceTemplate { return &configv1.ServiceTemplate{} }); err != nil {
+		return nil, err
+	}
+	for i, a := range doc.AuditEntries {
+		entry, err := a.entry()
+		if err != nil {
+			return nil, fmt.Errorf("audit_entries[%d]: %w", i, err)
+		}
+		f.AuditEntries = append(f.AuditEntries, entry)
+	}
+	for i, s := range doc.Skills {
+		if s.Name == "" {
+			return nil, fmt.Errorf("skills[%d]: name is required", i)
+		}
+		f.Skills = append(f.Skills, &skill.Skill{
+			Frontmatter: skill.Frontmatter{
+				Name:          s.Name,
+				Description:   s.Description,
+				License:       s.License,
+				Compatibility: s.Compatibility,
+				Metadata:      s.Metadata,
+				AllowedTools:  s.AllowedTools,
+			},
+			Instructions: s.Instructions,
+		})
+	}
+	if err := f.validate(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func decodeAll[M proto.Message](raws []json.RawMessage, field string, newMsg func() M) ([]M, error) {
+	out := make([]M, 0, len(raws))
+	for i, raw := range raws {
+		m := newMsg()
+		if err := protojson.Unmarshal(raw, m); err != nil {
+			return nil, fmt.Errorf("%s[%d]: %w", field, i, err)
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+func (a auditDocument) entry() (AuditEntry, error) {
+	// The ID makes re-applying the fixture idempotent.
+	if a.ID == "" {
+		return AuditEntry{}, fmt.Errorf("id is required")
+	}
+	if a.ToolName == "" {
+		return AuditEntry{}, fmt.Errorf("tool_name is required")
+	}
+	entry := AuditEntry{Entry: audit.Entry{
+		ID:         a.ID,
+		Timestamp:  a.Timestamp,
+		ToolName:   a.ToolName,
+		UserID:     a.UserID,
+		ProfileID:  a.ProfileID,
+		APIKeyID:   a.APIKeyID,
+		Arguments:  a.Arguments,
+		Result:     a.Result,
+		Error:      a.Error,
+		DurationMs: a.DurationMs,
+		Duration:   (time.Duration(a.DurationMs) * time.Millisecond).String(),
+	}}
+	if a.Age != "" {
+		age, err := time.ParseDuration(a.Age)
+		if err != nil || age < 0 {
+			return AuditEntry{}, fmt.Errorf("invalid age %q", a.Age)
+		}
+		entry.Age = age
+	}
+	return entry, nil
+}
+
+// validate checks that every record has the key it is upserted by.
+func (f *Fixture) validate() error {
+	for i, s := range f.Services {
+		if s.GetName() == "" {
+			return fmt.Errorf("upstream_services[%d]: name is required", i)
+		}
+	}
+	for i, c := range f.Credentials {
+		if c.GetId() == "" {
+			return fmt.Errorf("credentials[%d]: id is required", i)
+		}
+	}
+	for i, s := range f.Secrets {
+		if s.GetId() == "" {
+			return fmt.Errorf("secrets[%d]: id is required", i)
+		}
+	}
+	for i, p := range f.Profiles {
+		if p.GetName() == "" {
+			return fmt.Errorf("profiles[%d]: name is required", i)
+		}
+	}
+	for i, u := range f.Users {
+		if u.GetId() == "" {
+			return fmt.Errorf("users[%d]: id is required", i)
+		}
+	}
+	for i, t := range f.ServiceTemplates {
+		if t.GetId() == "" {
+			return fmt.Errorf("service_templates[%d]: id is required", i)
+		}
+	}
+	return nil
+}
+
+// InScenario reports whether the fixture belongs to the scenario. Every
+// fixture belongs to the empty scenario.
+//
+// Parameters:
+//   - scenario: string. The scenario name.
+//
+// Returns:
+//   - bool: True if the fixture should be applied for the scenario.
+func (f *Fixture) InScenario(scenario string) bool {
+	return scenario == "" || len(f.Scenarios) == 0 || slices.Contains(f.Scenarios, scenario)
+}
+
+// Load reads the fixtures of a scenario from a file or a directory.
+//
+// Summary: Loads fixture files.
+//
+// Parameters:
+//   - path: string. A fixture file, or a directory whose .yaml, .yml and .json
+//     files are read recursively in lexical order. Files with an additional
+//     .tmpl extension, e.g. load-test.yaml.tmpl, are rendered with
+//     text/template first, see templateFuncs.
+//   - scenario: string. Only fixtures of this scenario are returned; empty
+//     returns all.
+//
+// Returns:
+//   - []*Fixture: The fixtures.
+//   - error: An error if a file cannot be read, rendered or parsed.
+func Load(path, scenario string) ([]*Fixture, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	if info.IsDir() {
+		files = nil
+		err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && isFixtureFile(p) {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+	}
+
+	var fixtures []*Fixture
+	for _, file := range files {
+		data, err := os.ReadFile(file) //nolint:gosec // The path is chosen by the operator.
+		if err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(filepath.Ext(file), ".tmpl") {
+			if data, err = render(file, data); err != nil {
+				return nil, err
+			}
+		}
+		f, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		f.Source = file
+		if f.InScenario(scenario) {
+			fixtures = append(fixtures, f)
+		}
+	}
+	return fixtures, nil
+}
+
+// isFixtureFile reports whether a file in a fixture directory is a fixture,
+// or the template of one.
+func isFixtureFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".tmpl" {
+		path = strings.TrimSuffix(path, filepath.Ext(path))
+		ext = strings.ToLower(filepath.Ext(path))
+	}
+	switch ext {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// templateFuncs are the functions of the fixture templates, besides the
+// built-in ones such as printf: seq N returns 1 to N, and add, mul and mod
+// do integer arithmetic.
+var templateFuncs = template.FuncMap{
+	"seq": func(n int) []int {
+		seq := make([]int, n)
+		for i := range seq {
+			seq[i] = i + 1
+		}
+		return seq
+	},
+	"add": func(a, b int) int { return a + b },
+	"mul": func(a, b int) int { return a * b },
+	"mod": func(a, b int) int { return a % b },
+}
+
+// render executes a fixture template.
+func render(file string, data []byte) ([]byte, error) {
+	tmpl, err := template.New(filepath.Base(file)).Funcs(templateFuncs).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, nil); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return buf.Bytes(), nil
+}
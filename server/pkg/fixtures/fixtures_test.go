@@ -0,0 +1,252 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package fixtures_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/audit"
+	"github.com/mcpany/core/server/pkg/config"
+	"github.com/mcpany/core/server/pkg/fixtures"
+	"github.com/mcpany/core/server/pkg/skill"
+	"github.com/mcpany/core/server/pkg/storage/memory"
+	"github.com/mcpany/core/server/pkg/util/passhash"
+	"github.com/mcpany/core/server/pkg/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const demoFixture = `
+scenarios: ["demo"]
+upstream_services:
+  - name: "weather"
+    http_service:
+      address: "https://wttr.in"
+users:
+  - id: "alice"
+    roles: ["admin"]
+    authentication:
+      basic_auth:
+        username: "alice"
+        password_hash: "secret"
+profiles:
+  - name: "default"
+audit_entries:
+  - id: "a1"
+    age: "1h"
+    tool_name: "weather.get_weather"
+    user_id: "alice"
+    arguments: {"city": "Paris"}
+    duration_ms: 120
+skills:
+  - name: "weather-report"
+    description: "Summarize the weather."
+    allowed_tools: ["weather.get_weather"]
+    instructions: "Call the tool."
+`
+
+func TestParse(t *testing.T) {
+	f, err := fixtures.Parse([]byte(demoFixture))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"demo"}, f.Scenarios)
+	require.Len(t, f.Services, 1)
+	assert.Equal(t, "https://wttr.in", f.Services[0].GetHttpService().GetAddress())
+	require.Len(t, f.Users, 1)
+	assert.Equal(t, "alice", f.Users[0].GetAuthentication().GetBasicAuth().GetUsername())
+	require.Len(t, f.AuditEntries, 1)
+	assert.Equal(t, time.Hour, f.AuditEntries[0].Age)
+	assert.JSONEq(t, `{"city": "Paris"}`, string(f.AuditEntries[0].Arguments))
+	require.Len(t, f.Skills, 1)
+	assert.Equal(t, []string{"weather.get_weather"}, f.Skills[0].AllowedTools)
+
+	// JSON is accepted as well.
+	f, err = fixtures.Parse([]byte(`{"upstream_services": [{"name": "a"}]}`))
+	require.NoError(t, err)
+	assert.Len(t, f.Services, 1)
+}
+
+func TestParse_Errors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		doc     string
+		wantErr string
+	}{
+		"malformed":           {doc: "upstream_services: [", wantErr: "invalid fixture"},
+		"unknown field":       {doc: "upstream_services: [{name: a, nope: 1}]", wantErr: "upstream_services[0]"},
+		"service name":        {doc: "upstream_services: [{version: '1'}]", wantErr: "name is required"},
+		"user id":             {doc: "users: [{roles: [admin]}]", wantErr: "users[0]: id is required"},
+		"audit id":            {doc: "audit_entries: [{tool_name: t}]", wantErr: "audit_entries[0]: id is required"},
+		"audit age":           {doc: "audit_entries: [{id: a, tool_name: t, age: soon}]", wantErr: "invalid age"},
+		"skill name":          {doc: "skills: [{description: d}]", wantErr: "skills[0]: name is required"},
+		"credential id":       {doc: "credentials: [{name: c}]", wantErr: "credentials[0]: id is required"},
+		"service template id": {doc: "service_templates: [{name: t}]", wantErr: "service_templates[0]: id is required"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := fixtures.Parse([]byte(tc.doc))
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestLoad_Scenarios(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	write("common.yaml", `profiles: [{name: default}]`)
+	write("demo/services.yaml", `{scenarios: [demo], upstream_services: [{name: demo}]}`)
+	write("load.yml", `{scenarios: [load-test, demo], upstream_services: [{name: load}]}`)
+	write("README.md", `not a fixture`)
+
+	sources := func(fs []*fixtures.Fixture) []string {
+		var out []string
+		for _, f := range fs {
+			out = append(out, strings.TrimPrefix(f.Source, dir+string(filepath.Separator)))
+		}
+		return out
+	}
+
+	demo, err := fixtures.Load(dir, "demo")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"common.yaml", filepath.Join("demo", "services.yaml"), "load.yml"}, sources(demo))
+
+	load, err := fixtures.Load(dir, "load-test")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"common.yaml", "load.yml"}, sources(load))
+
+	all, err := fixtures.Load(dir, "")
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	single, err := fixtures.Load(filepath.Join(dir, "load.yml"), "docs")
+	require.NoError(t, err)
+	assert.Empty(t, single)
+
+	write("broken.yaml", `users: [{}]`)
+	_, err = fixtures.Load(dir, "demo")
+	assert.ErrorContains(t, err, "broken.yaml")
+}
+
+func TestApply_Idempotent(t *testing.T) {
+	ctx := context.Background()
+	f, err := fixtures.Parse([]byte(demoFixture))
+	require.NoError(t, err)
+
+	store := memory.NewStore()
+	validation.SetAllowedPaths([]string{os.TempDir()})
+	defer validation.SetAllowedPaths(nil)
+	auditStore, err := audit.NewSQLiteAuditStore(filepath.Join(t.TempDir(), "audit.db"))
+	require.NoError(t, err)
+	defer func() { _ = auditStore.Close() }()
+	skills, err := skill.NewManager(t.TempDir())
+	require.NoError(t, err)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	target := fixtures.Target{Storage: store, Audit: auditStore, Skills: skills, Now: func() time.Time { return now }}
+
+	sum, err := fixtures.Apply(ctx, target, []*fixtures.Fixture{f})
+	require.NoError(t, err)
+	assert.Equal(t, 1, sum.Services)
+	assert.Equal(t, 1, sum.Users)
+	assert.Equal(t, 1, sum.AuditEntries)
+	assert.Equal(t, 1, sum.Skills)
+
+	user, err := store.GetUser(ctx, "alice")
+	require.NoError(t, err)
+	require.NotNil(t, user)
+	hash := user.GetAuthentication().GetBasicAuth().GetPasswordHash()
+	assert.True(t, passhash.CheckPassword("secret", hash), "plain text passwords are hashed")
+
+	entries, err := auditStore.Read(ctx, audit.Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "a1", entries[0].ID)
+	assert.True(t, entries[0].Timestamp.Equal(now.Add(-time.Hour)))
+
+	// Applying again updates records in place and does not duplicate audit entries.
+	now = now.Add(time.Hour)
+	sum, err = fixtures.Apply(ctx, target, []*fixtures.Fixture{f})
+	require.NoError(t, err)
+	assert.Equal(t, 0, sum.AuditEntries)
+	assert.Equal(t, 1, sum.Unchanged)
+
+	services, err := store.ListServices(ctx)
+	require.NoError(t, err)
+	assert.Len(t, services, 1)
+	users, err := store.ListUsers(ctx)
+	require.NoError(t, err)
+	assert.Len(t, users, 1)
+	entries, err = auditStore.Read(ctx, audit.Filter{})
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	s, err := skills.GetSkill("weather-report")
+	require.NoError(t, err)
+	assert.Equal(t, "Summarize the weather.", s.Description)
+}
+
+func TestApply_SkipsKindsWithoutTarget(t *testing.T) {
+	f, err := fixtures.Parse([]byte(demoFixture))
+	require.NoError(t, err)
+
+	sum, err := fixtures.Apply(context.Background(), fixtures.Target{Storage: memory.NewStore()}, []*fixtures.Fixture{f})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"audit entries", "skills"}, sum.Skipped)
+	assert.Contains(t, sum.String(), "skipped: audit entries, skills")
+
+	_, err = fixtures.Apply(context.Background(), fixtures.Target{}, nil)
+	assert.Error(t, err)
+}
+
+func TestLoad_Template(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := `scenarios: [load-test]
+upstream_services:
+{{- range $i := seq 3}}
+  - name: "load-{{printf "%02d" $i}}"
+{{- end}}
+audit_entries:
+{{- range $i := seq 2}}
+  - {id: "audit-{{$i}}", age: "{{mul $i 2}}m", tool_name: "load-01.list", duration_ms: {{add (mod 7 $i) 10}}}
+{{- end}}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "load.yaml.tmpl"), []byte(tmpl), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt.tmpl"), []byte("{{"), 0o600))
+
+	fs, err := fixtures.Load(dir, "load-test")
+	require.NoError(t, err)
+	require.Len(t, fs, 1)
+	var names []string
+	for _, svc := range fs[0].Services {
+		names = append(names, svc.GetName())
+	}
+	assert.Equal(t, []string{"load-01", "load-02", "load-03"}, names)
+	require.Len(t, fs[0].AuditEntries, 2)
+	assert.Equal(t, int64(10), fs[0].AuditEntries[0].DurationMs)
+	assert.Equal(t, int64(11), fs[0].AuditEntries[1].DurationMs)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.yaml.tmpl"), []byte("{{seq}}"), 0o600))
+	_, err = fixtures.Load(dir, "load-test")
+	assert.ErrorContains(t, err, "broken.yaml.tmpl")
+}
+
+// TestRepositoryFixtures checks that the fixtures shipped in the repository
+// parse and hold valid services.
+func TestRepositoryFixtures(t *testing.T) {
+	for _, scenario := range []string{"demo", "docs", "load-test"} {
+		t.Run(scenario, func(t *testing.T) {
+			fs, err := fixtures.Load(filepath.Join("..", "..", "fixtures"), scenario)
+			require.NoError(t, err)
+			require.NotEmpty(t, fs)
+			for _, f := range fs {
+				for _, svc := range f.Services {
+					assert.NoError(t, config.ValidateOrError(context.Background(), svc), "%s: %s", f.Source, svc.GetName())
+				}
+			}
+		})
+	}
+}
@@ -59,3 +59,31 @@ func TestNewChecker_WithoutInterval(t *testing.T) {
 		c.Stop()
 	}
 }
+
+func TestProbeIntervalAndKind(t *testing.T) {
+	mcpService := configv1.UpstreamServiceConfig_builder{
+		Name: proto.String("mcp-service"),
+		McpService: configv1.McpUpstreamService_builder{
+			HttpConnection: configv1.McpStreamableHttpConnection_builder{HttpAddress: proto.String("http://localhost:1234/mcp")}.Build(),
+			HealthCheck:    configv1.McpHealthCheck_builder{Interval: durationpb.New(15 * time.Second)}.Build(),
+		}.Build(),
+	}.Build()
+	assert.Equal(t, 15*time.Second, ProbeInterval(mcpService))
+	assert.Equal(t, "mcp_ping", ProbeKind(mcpService))
+
+	grpcService := configv1.UpstreamServiceConfig_builder{
+		Name: proto.String("grpc-service"),
+		GrpcService: configv1.GrpcUpstreamService_builder{
+			Address:     proto.String("localhost:50051"),
+			HealthCheck: configv1.GrpcHealthCheck_builder{Service: proto.String("weather")}.Build(),
+		}.Build(),
+	}.Build()
+	assert.Zero(t, ProbeInterval(grpcService), "the default interval applies")
+	assert.Equal(t, "grpc", ProbeKind(grpcService))
+
+	httpService := configv1.UpstreamServiceConfig_builder{
+		Name:        proto.String("http-service"),
+		HttpService: configv1.HttpUpstreamService_builder{Address: proto.String("http://localhost:1234")}.Build(),
+	}.Build()
+	assert.Equal(t, "connection", ProbeKind(httpService), "without a health check, only the connection is checked")
+}
@@ -42,8 +42,23 @@ type Doctor struct {
 	checks     map[string]CheckFunc
 	mu         sync.RWMutex
 	httpClient *http.Client
+
+	// lastStatus is the status of each check in the previous report.
+	lastStatus map[string]string
+	regression RegressionObserver
+	statusMu   sync.Mutex
 }
 
+// RegressionObserver is notified when a check that passed in the previous
+// report fails.
+//
+// Summary: Callback of the doctor check regressions.
+//
+// Parameters:
+//   - name: The name of the check.
+//   - result: The failed result.
+type RegressionObserver func(name string, result CheckResult)
+
 // NewDoctor creates a new Doctor.
 //
 // Summary: Initializes a new Doctor instance.
@@ -57,6 +72,36 @@ func NewDoctor() *Doctor {
 	return &Doctor{
 		checks:     make(map[string]CheckFunc),
 		httpClient: http.DefaultClient,
+		lastStatus: make(map[string]string),
+	}
+}
+
+// SetRegressionObserver sets the observer notified when a check regresses
+// from "ok" to another status between two reports.
+//
+// Summary: Registers the callback of the check regressions.
+//
+// Parameters:
+//   - observer: RegressionObserver. The observer, or nil.
+//
+// Side Effects:
+//   - Replaces the observer of the doctor.
+func (d *Doctor) SetRegressionObserver(observer RegressionObserver) {
+	d.statusMu.Lock()
+	defer d.statusMu.Unlock()
+	d.regression = observer
+}
+
+// recordStatuses compares the checks of a report with the previous one and
+// notifies the regression observer of the checks that stopped passing.
+func (d *Doctor) recordStatuses(checks map[string]CheckResult) {
+	d.statusMu.Lock()
+	defer d.statusMu.Unlock()
+	for name, result := range checks {
+		if d.lastStatus[name] == "ok" && result.Status != "ok" && d.regression != nil {
+			d.regression(name, result)
+		}
+		d.lastStatus[name] = result.Status
 	}
 }
 
@@ -85,6 +130,7 @@ func (d *Doctor) AddCheck(name string, check CheckFunc) {
 //
 // Side Effects:
 //   - Executes all registered health checks.
+//   - Notifies the regression observer of the checks that stopped passing.
 //   - Makes an external network call to google.com (connectivity check).
 //   - Reads environment variables (Auth checks).
 //   - Writes JSON response to the client.
@@ -138,6 +184,7 @@ func (d *Doctor) Handler() http.HandlerFunc {
 			}
 		}
 		d.mu.RUnlock()
+		d.recordStatuses(report.Checks)
 
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
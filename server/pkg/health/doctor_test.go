@@ -99,3 +99,40 @@ func TestDoctor_AddCheck(t *testing.T) {
 	assert.Contains(t, report.Checks, "custom_fail")
 	assert.Equal(t, "error", report.Checks["custom_fail"].Status)
 }
+
+func TestDoctor_RegressionObserver(t *testing.T) {
+	doctor := NewDoctor()
+	doctor.httpClient = &http.Client{
+		Transport: &mockTransport{
+			roundTripFunc: func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewBufferString("OK")),
+				}, nil
+			},
+		},
+	}
+
+	status := "error"
+	doctor.AddCheck("storage", func(ctx context.Context) CheckResult {
+		return CheckResult{Status: status, Message: "disk full"}
+	})
+	var regressions []string
+	doctor.SetRegressionObserver(func(name string, result CheckResult) {
+		regressions = append(regressions, name+": "+result.Message)
+	})
+
+	run := func() {
+		req, _ := http.NewRequest("GET", "/doctor", nil)
+		doctor.Handler().ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	run() // Failing from the start is not a regression.
+	status = "ok"
+	run()
+	status = "error"
+	run()
+	run() // Still failing: no new regression.
+
+	assert.Equal(t, []string{"storage: disk full"}, regressions)
+}
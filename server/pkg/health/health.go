@@ -14,6 +14,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/alexliesenfeld/health"
@@ -23,6 +24,7 @@ import (
 	"github.com/mcpany/core/server/pkg/logging"
 	"github.com/mcpany/core/server/pkg/metrics"
 	"github.com/mcpany/core/server/pkg/util"
+	"github.com/mcpany/core/server/pkg/webhooks"
 	"github.com/samber/lo"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -58,6 +60,33 @@ func SetGlobalAlertConfig(cfg *configv1.AlertConfig) {
 	globalAlertConfig = cfg
 }
 
+// StatusObserver is notified when the health status of an upstream service
+// changes.
+//
+// Parameters:
+//   - service: The name of the service.
+//   - from: The previous status, e.g. "up", or empty for the first check.
+//   - to: The new status, e.g. "down".
+type StatusObserver func(service, from, to string)
+
+var statusObserver atomic.Pointer[StatusObserver]
+
+// SetStatusObserver installs the observer notified of the health status
+// changes of the upstream services. Passing nil removes it.
+//
+// Parameters:
+//   - observer: StatusObserver. The observer, or nil.
+//
+// Side Effects:
+//   - Replaces the process-wide observer.
+func SetStatusObserver(observer StatusObserver) {
+	if observer == nil {
+		statusObserver.Store(nil)
+		return
+	}
+	statusObserver.Store(&observer)
+}
+
 // HTTPServiceWithHealthCheck is an interface for services that have an address and an HTTP health check.
 type HTTPServiceWithHealthCheck interface {
 	// GetAddress returns the address of the service.
@@ -163,6 +192,10 @@ func NewChecker(uc *configv1.UpstreamServiceConfig) health.Checker {
 			// Record history
 			AddHealthStatus(serviceName, string(state.Status))
 
+			if observer := statusObserver.Load(); observer != nil {
+				(*observer)(serviceName, string(prev), string(state.Status))
+			}
+
 			globalAlertConfigMu.RLock()
 			alertConfig := globalAlertConfig
 			globalAlertConfigMu.RUnlock()
@@ -222,6 +255,59 @@ func httpCheckFunc(ctx context.Context, _ string, hc *configv1.HttpHealthCheck)
 	return nil
 }
 
+// ProbeInterval returns the interval between the active probes of an upstream
+// service configured by its health check.
+//
+// Parameters:
+//   - uc: *configv1.UpstreamServiceConfig. The configuration of the upstream service.
+//
+// Returns:
+//   - time.Duration: The interval, or 0 if the health check does not set one.
+func ProbeInterval(uc *configv1.UpstreamServiceConfig) time.Duration {
+	interval, _ := getHealthCheckConfig(uc)
+	return interval
+}
+
+// ProbeKind names how an upstream service is probed: "http" for an HTTP
+// health check path, "grpc" for the gRPC health service, "mcp_ping" for MCP
+// pings, "websocket" and "command" for their health checks, and "connection"
+// when only the reachability of the service is checked.
+//
+// Parameters:
+//   - uc: *configv1.UpstreamServiceConfig. The configuration of the upstream service.
+//
+// Returns:
+//   - string: The kind of probe.
+func ProbeKind(uc *configv1.UpstreamServiceConfig) string {
+	switch uc.WhichServiceConfig() {
+	case configv1.UpstreamServiceConfig_HttpService_case:
+		if uc.GetHttpService().GetHealthCheck() != nil {
+			return "http"
+		}
+	case configv1.UpstreamServiceConfig_OpenapiService_case:
+		if uc.GetOpenapiService().GetHealthCheck() != nil {
+			return "http"
+		}
+	case configv1.UpstreamServiceConfig_GrpcService_case:
+		if uc.GetGrpcService().GetHealthCheck() != nil {
+			return "grpc"
+		}
+	case configv1.UpstreamServiceConfig_McpService_case:
+		if uc.GetMcpService().GetHealthCheck() != nil {
+			return "mcp_ping"
+		}
+	case configv1.UpstreamServiceConfig_WebsocketService_case:
+		if uc.GetWebsocketService().GetHealthCheck() != nil {
+			return "websocket"
+		}
+	case configv1.UpstreamServiceConfig_CommandLineService_case:
+		if uc.GetCommandLineService().GetHealthCheck() != nil {
+			return "command"
+		}
+	}
+	return "connection"
+}
+
 func getHealthCheckConfig(uc *configv1.UpstreamServiceConfig) (time.Duration, time.Duration) {
 	var interval time.Duration
 	var timeout = 5 * time.Second
@@ -257,6 +343,15 @@ func getHealthCheckConfig(uc *configv1.UpstreamServiceConfig) (time.Duration, ti
 				interval = hc.GetInterval().AsDuration()
 			}
 		}
+	case configv1.UpstreamServiceConfig_McpService_case:
+		if hc := uc.GetMcpService().GetHealthCheck(); hc != nil {
+			if hc.GetInterval() != nil {
+				interval = hc.GetInterval().AsDuration()
+			}
+			if hc.GetTimeout() != nil {
+				timeout = hc.GetTimeout().AsDuration()
+			}
+		}
 	case configv1.UpstreamServiceConfig_WebrtcService_case:
 		if hc := uc.GetWebrtcService().GetHealthCheck(); hc != nil {
 			if hc.GetHttp() != nil {
@@ -505,7 +600,7 @@ func sendWebhook(ctx context.Context, url, serviceName string, status health.Ava
 	req.Header.Set("Content-Type", "application/json")
 
 	// Use a short timeout for webhooks
-	client := &http.Client{Timeout: 5 * time.Second}
+	client := webhooks.NewOutboundClient(5 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		logging.GetLogger().Error("failed to send webhook", "error", err)
@@ -0,0 +1,210 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// StatusOK is the status of a passing check.
+	StatusOK = "ok"
+	// StatusDegraded is the status of a check that found a problem that does
+	// not make the probe fail, e.g. a failed configuration reload while the
+	// previous configuration is still served.
+	StatusDegraded = "degraded"
+	// StatusFail is the status of a failing check. It makes the probe fail.
+	StatusFail = "fail"
+
+	defaultProbeTimeout = 5 * time.Second
+)
+
+// ProbeReport is the response of a liveness or readiness probe.
+//
+// Summary: The status of a probe and of each of its checks.
+type ProbeReport struct {
+	Status    string                 `json:"status"`
+	Timestamp time.Time              `json:"timestamp"`
+	Checks    map[string]CheckResult `json:"checks"`
+}
+
+type probeCheck struct {
+	name  string
+	check CheckFunc
+}
+
+// Probe runs the checks of a liveness or readiness endpoint, such as
+// /livez or /readyz. Unlike the doctor, a probe only runs the checks it was
+// given, in parallel and with a timeout, and answers 503 when one of them
+// fails, so that Kubernetes and load balancers can act on it.
+//
+// Summary: Registry and handler of the checks of a probe endpoint.
+type Probe struct {
+	mu      sync.RWMutex
+	checks  []probeCheck
+	timeout time.Duration
+}
+
+// NewProbe creates a new Probe.
+//
+// Summary: Initializes a probe without checks, which always passes.
+//
+// Returns:
+//   - *Probe: The probe.
+func NewProbe() *Probe {
+	return &Probe{timeout: defaultProbeTimeout}
+}
+
+// AddCheck adds a named check to the probe. A check whose result has the
+// StatusFail status, or that does not return before the probe timeout,
+// makes the probe fail.
+//
+// Summary: Registers a check of the probe.
+//
+// Parameters:
+//   - name: string. The name of the check in the report.
+//   - check: CheckFunc. The check.
+//
+// Side Effects:
+//   - Updates the checks of the probe.
+func (p *Probe) AddCheck(name string, check CheckFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.checks = append(p.checks, probeCheck{name: name, check: check})
+}
+
+// Run runs the checks of the probe in parallel.
+//
+// Summary: Runs the probe.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the checks.
+//   - exclude: []string. The names of the checks to skip.
+//
+// Returns:
+//   - ProbeReport: The report, with the StatusFail status if a check failed.
+func (p *Probe) Run(ctx context.Context, exclude ...string) ProbeReport {
+	p.mu.RLock()
+	checks := make([]probeCheck, 0, len(p.checks))
+	for _, c := range p.checks {
+		skip := false
+		for _, name := range exclude {
+			if name == c.name {
+				skip = true
+				break
+			}
+		}
+		if !skip {
+			checks = append(checks, c)
+		}
+	}
+	p.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runProbeCheck(ctx, c.check)
+		}()
+	}
+	wg.Wait()
+
+	report := ProbeReport{
+		Status:    StatusOK,
+		Timestamp: time.Now(),
+		Checks:    make(map[string]CheckResult, len(checks)),
+	}
+	for i, c := range checks {
+		report.Checks[c.name] = results[i]
+		if results[i].Status == StatusFail {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+// runProbeCheck runs a check, failing it when the context is done first.
+func runProbeCheck(ctx context.Context, check CheckFunc) CheckResult {
+	start := time.Now()
+	done := make(chan CheckResult, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+	var res CheckResult
+	select {
+	case res = <-done:
+	case <-ctx.Done():
+		res = CheckResult{Status: StatusFail, Message: "check timed out"}
+	}
+	if res.Latency == "" {
+		res.Latency = time.Since(start).String()
+	}
+	return res
+}
+
+// Handler returns the HTTP handler of the probe. It answers 200 when all
+// checks pass and 503 otherwise, with the report as JSON. Checks can be
+// skipped with the "exclude" query parameter, e.g. "?exclude=upstreams".
+// The report only has the status of each check: the probe endpoints are
+// served without authentication, and the messages of the checks can carry
+// errors and service names. DetailsHandler serves them.
+//
+// Summary: Returns an HTTP handler that runs the probe.
+//
+// Returns:
+//   - http.HandlerFunc: The HTTP handler.
+//
+// Side Effects:
+//   - Runs the checks of the probe.
+func (p *Probe) Handler() http.HandlerFunc {
+	return p.handler(false)
+}
+
+// DetailsHandler returns the HTTP handler of the probe, like Handler, with
+// the messages of the checks in the report. Serve it behind authentication.
+//
+// Summary: Returns an HTTP handler that runs the probe and reports the messages of its checks.
+//
+// Returns:
+//   - http.HandlerFunc: The HTTP handler.
+//
+// Side Effects:
+//   - Runs the checks of the probe.
+func (p *Probe) DetailsHandler() http.HandlerFunc {
+	return p.handler(true)
+}
+
+func (p *Probe) handler(details bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var exclude []string
+		for _, v := range r.URL.Query()["exclude"] {
+			exclude = append(exclude, strings.Split(v, ",")...)
+		}
+		report := p.Run(r.Context(), exclude...)
+		if !details {
+			for name, res := range report.Checks {
+				report.Checks[name] = CheckResult{Status: res.Status, Latency: res.Latency}
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if report.Status == StatusFail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+		_ = json.NewEncoder(w).Encode(report)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbe(t *testing.T) {
+	probe := NewProbe()
+	probe.AddCheck("config", func(context.Context) CheckResult {
+		return CheckResult{Status: StatusDegraded, Message: "reload failed"}
+	})
+	probe.AddCheck("storage", func(context.Context) CheckResult {
+		return CheckResult{Status: StatusOK}
+	})
+
+	w := httptest.NewRecorder()
+	probe.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusOK, w.Code, "a degraded check does not fail the probe")
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var report ProbeReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, StatusOK, report.Status)
+	assert.Equal(t, StatusDegraded, report.Checks["config"].Status)
+	assert.Empty(t, report.Checks["config"].Message, "the messages are only served with the details")
+	assert.NotEmpty(t, report.Checks["storage"].Latency)
+
+	w = httptest.NewRecorder()
+	probe.DetailsHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz/details", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	report = ProbeReport{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, StatusDegraded, report.Checks["config"].Status)
+	assert.Equal(t, "reload failed", report.Checks["config"].Message)
+}
+
+func TestProbe_Fail(t *testing.T) {
+	probe := NewProbe()
+	probe.AddCheck("storage", func(context.Context) CheckResult {
+		return CheckResult{Status: StatusFail, Message: "database is locked"}
+	})
+	probe.AddCheck("upstreams", func(context.Context) CheckResult {
+		return CheckResult{Status: StatusOK}
+	})
+
+	w := httptest.NewRecorder()
+	probe.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	var report ProbeReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, StatusFail, report.Status)
+	assert.Len(t, report.Checks, 2)
+
+	// Excluded checks are skipped.
+	w = httptest.NewRecorder()
+	probe.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz?exclude=storage", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	report = ProbeReport{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.NotContains(t, report.Checks, "storage")
+}
+
+func TestProbe_Timeout(t *testing.T) {
+	probe := NewProbe()
+	probe.timeout = 50 * time.Millisecond
+	probe.AddCheck("slow", func(ctx context.Context) CheckResult {
+		<-ctx.Done()
+		time.Sleep(100 * time.Millisecond)
+		return CheckResult{Status: StatusOK}
+	})
+
+	report := probe.Run(context.Background())
+	assert.Equal(t, StatusFail, report.Status)
+	assert.Equal(t, "check timed out", report.Checks["slow"].Message)
+}
+
+func TestProbe_NoChecks(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewProbe().Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var report ProbeReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, StatusOK, report.Status)
+	assert.Empty(t, report.Checks)
+}
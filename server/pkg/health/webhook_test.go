@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -153,3 +154,48 @@ func TestSendWebhook(t *testing.T) {
 		}
 	})
 }
+
+func TestStatusObserver(t *testing.T) {
+	changes := make(chan string, 4)
+	SetStatusObserver(func(service, from, to string) {
+		if service == "observed-service" {
+			changes <- from + "->" + to
+		}
+	})
+	t.Cleanup(func() { SetStatusObserver(nil) })
+
+	var statusCode atomic.Int32
+	statusCode.Store(http.StatusOK)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(int(statusCode.Load()))
+	}))
+	defer server.Close()
+
+	addr := server.Listener.Addr().String()
+	checker := NewChecker(configv1.UpstreamServiceConfig_builder{
+		Name: lo.ToPtr("observed-service"),
+		HttpService: configv1.HttpUpstreamService_builder{
+			Address: &addr,
+			HealthCheck: configv1.HttpHealthCheck_builder{
+				Url:          lo.ToPtr(server.URL),
+				ExpectedCode: lo.ToPtr(int32(http.StatusOK)),
+			}.Build(),
+		}.Build(),
+	}.Build())
+	require.NotNil(t, checker)
+
+	checker.Check(context.Background())
+	statusCode.Store(http.StatusInternalServerError)
+	// Wait for the cached result to expire.
+	time.Sleep(1100 * time.Millisecond)
+	checker.Check(context.Background())
+
+	for _, want := range []string{"->up", "up->down"} {
+		select {
+		case got := <-changes:
+			assert.Equal(t, want, got)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for %s", want)
+		}
+	}
+}
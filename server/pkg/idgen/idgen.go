@@ -0,0 +1,145 @@
+// Copyright 2025 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package idgen provides pluggable identifier generation for audit entries,
+// sessions, and correlation IDs.
+package idgen
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+)
+
+// traceSeparator separates the base ID from an embedded trace ID.
+const traceSeparator = "."
+
+// Generator produces unique identifiers.
+type Generator interface {
+	// NewID returns a new unique identifier.
+	//
+	// Returns:
+	//   - string: The generated identifier.
+	NewID() string
+}
+
+// GeneratorFunc adapts a plain function to the Generator interface.
+type GeneratorFunc func() string
+
+// NewID returns the result of calling f.
+//
+// Returns:
+//   - string: The generated identifier.
+func (f GeneratorFunc) NewID() string {
+	return f()
+}
+
+// UUIDv7 generates time-ordered (version 7) UUIDs.
+//
+// IDs generated by UUIDv7 sort lexicographically in creation order, which
+// keeps audit entries and sessions naturally ordered in storage.
+type UUIDv7 struct{}
+
+// NewID returns a new version 7 UUID. It falls back to a random (version 4)
+// UUID if the time-ordered generator fails.
+//
+// Returns:
+//   - string: The generated UUID.
+func (UUIDv7) NewID() string {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.New().String()
+	}
+	return id.String()
+}
+
+// UUIDv4 generates random (version 4) UUIDs.
+type UUIDv4 struct{}
+
+// NewID returns a new version 4 UUID.
+//
+// Returns:
+//   - string: The generated UUID.
+func (UUIDv4) NewID() string {
+	return uuid.New().String()
+}
+
+type holder struct {
+	gen Generator
+}
+
+var defaultGenerator atomic.Pointer[holder]
+
+func init() {
+	defaultGenerator.Store(&holder{gen: UUIDv7{}})
+}
+
+// SetDefault replaces the process-wide generator. Its identifiers must not
+// contain ".", which separates the embedded trace IDs.
+//
+// Parameters:
+//   - g (Generator): The generator to use. A nil value restores the UUIDv7 default.
+//
+// Side Effects:
+//   - Modifies global state used by New and NewWithTrace.
+func SetDefault(g Generator) {
+	if g == nil {
+		g = UUIDv7{}
+	}
+	defaultGenerator.Store(&holder{gen: g})
+}
+
+// Default returns the process-wide generator.
+//
+// Returns:
+//   - Generator: The current generator.
+func Default() Generator {
+	return defaultGenerator.Load().gen
+}
+
+// New returns a new identifier from the process-wide generator. An
+// identifier containing the trace separator is rejected for a UUIDv7, so
+// that TraceIDFromID never mistakes part of it for a trace ID.
+//
+// Returns:
+//   - string: The generated identifier.
+func New() string {
+	id := Default().NewID()
+	if strings.Contains(id, traceSeparator) {
+		return UUIDv7{}.NewID()
+	}
+	return id
+}
+
+// NewWithTrace returns a new identifier with the given trace ID embedded as a
+// suffix, so that entries stay sortable by their generated prefix while still
+// being joinable against traces in other systems.
+//
+// Parameters:
+//   - traceID (string): The trace ID to embed. If empty, a plain ID is returned.
+//
+// Returns:
+//   - string: The generated identifier.
+func NewWithTrace(traceID string) string {
+	id := New()
+	if traceID == "" {
+		return id
+	}
+	return id + traceSeparator + traceID
+}
+
+// TraceIDFromID extracts the trace ID embedded by NewWithTrace.
+//
+// Parameters:
+//   - id (string): The identifier to inspect.
+//
+// Returns:
+//   - string: The embedded trace ID, or empty if none is present.
+func TraceIDFromID(id string) string {
+	idx := strings.LastIndex(id, traceSeparator)
+	if idx < 0 {
+		return ""
+	}
+	return id[idx+len(traceSeparator):]
+}
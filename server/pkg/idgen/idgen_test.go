@@ -0,0 +1,70 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package idgen
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultIsUUIDv7(t *testing.T) {
+	id := New()
+	parsed, err := uuid.Parse(id)
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(7), parsed.Version())
+}
+
+func TestUUIDv7IsSortable(t *testing.T) {
+	ids := make([]string, 100)
+	for i := range ids {
+		ids[i] = UUIDv7{}.NewID()
+	}
+	assert.True(t, sort.StringsAreSorted(ids))
+}
+
+func TestUUIDv4(t *testing.T) {
+	parsed, err := uuid.Parse(UUIDv4{}.NewID())
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(4), parsed.Version())
+}
+
+func TestSetDefault(t *testing.T) {
+	t.Cleanup(func() { SetDefault(nil) })
+
+	SetDefault(GeneratorFunc(func() string { return "fixed" }))
+	assert.Equal(t, "fixed", New())
+
+	SetDefault(nil)
+	_, ok := Default().(UUIDv7)
+	assert.True(t, ok)
+}
+
+func TestNewWithTrace(t *testing.T) {
+	t.Cleanup(func() { SetDefault(nil) })
+	SetDefault(GeneratorFunc(func() string { return "0192-abc" }))
+
+	id := NewWithTrace("4bf92f3577b34da6a3ce929d0e0e4736")
+	assert.Equal(t, "0192-abc.4bf92f3577b34da6a3ce929d0e0e4736", id)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", TraceIDFromID(id))
+
+	assert.Equal(t, "0192-abc", NewWithTrace(""))
+	assert.Empty(t, TraceIDFromID("0192-abc"))
+}
+
+func TestNewWithTrace_DottedCustomID(t *testing.T) {
+	t.Cleanup(func() { SetDefault(nil) })
+	SetDefault(GeneratorFunc(func() string { return "tenant.0192-abc" }))
+
+	id := New()
+	assert.NotContains(t, id, ".", "a dotted ID is rejected")
+	_, err := uuid.Parse(id)
+	assert.NoError(t, err)
+
+	assert.Empty(t, TraceIDFromID(NewWithTrace("")))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", TraceIDFromID(NewWithTrace("4bf92f3577b34da6a3ce929d0e0e4736")))
+}
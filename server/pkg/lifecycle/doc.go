@@ -0,0 +1,8 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package lifecycle provides a registry of hooks that run when the server
+// starts, reloads its configuration and shuts down. Internal subsystems and
+// extensions register with it instead of relying on defer chains, so they are
+// started and stopped in a well-defined order with bounded timeouts.
+package lifecycle
@@ -0,0 +1,364 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+)
+
+// Orders of the built-in subsystems. Start hooks run in ascending order and
+// shutdown hooks in descending order, so a hook with the default order (0)
+// is stopped before storage and telemetry are closed.
+const (
+	// OrderStorage is the order of the storage backend.
+	OrderStorage = -1000
+	// OrderTelemetry is the order of the telemetry exporters.
+	OrderTelemetry = -900
+	// OrderMiddlewares is the order of the standard middlewares, such as audit.
+	OrderMiddlewares = -600
+	// OrderWorkers is the order of the background workers.
+	OrderWorkers = -500
+	// OrderDefault is the order of hooks registered without WithOrder.
+	OrderDefault = 0
+)
+
+// Hook is run on startup or shutdown.
+type Hook func(ctx context.Context) error
+
+// ReloadHook is run after the configuration has been reloaded successfully.
+type ReloadHook func(ctx context.Context, cfg *configv1.McpAnyServerConfig) error
+
+// Option configures a registered hook.
+type Option func(*hookOptions)
+
+type hookOptions struct {
+	order   int
+	timeout time.Duration
+}
+
+// WithOrder sets the order of a hook relative to the others. Lower orders
+// start first and shut down last.
+//
+// Parameters:
+//   - order: int. The order of the hook.
+//
+// Returns:
+//   - Option: The option.
+func WithOrder(order int) Option {
+	return func(o *hookOptions) {
+		o.order = order
+	}
+}
+
+// WithTimeout bounds how long a hook may run. A hook that exceeds it is
+// reported as failed, its context is canceled and the next hooks run. A
+// start or reload hook is no longer waited for; Shutdown still waits for a
+// shutdown hook to return.
+//
+// Parameters:
+//   - timeout: time.Duration. The maximum duration of the hook.
+//
+// Returns:
+//   - Option: The option.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *hookOptions) {
+		o.timeout = timeout
+	}
+}
+
+type entry struct {
+	name string
+	hookOptions
+	start    Hook
+	reload   ReloadHook
+	shutdown Hook
+}
+
+// Registry holds the lifecycle hooks of a server.
+//
+// Summary: An ordered registry of start, reload and shutdown hooks.
+type Registry struct {
+	mu           sync.Mutex
+	entries      []*entry
+	shutdownOnce sync.Once
+	shutdownErr  error
+}
+
+var globalRegistry = NewRegistry()
+
+// NewRegistry creates an empty registry.
+//
+// Returns:
+//   - *Registry: The registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Default returns the process-wide registry. Extensions register with it,
+// typically from an init function, and every server run includes its hooks.
+//
+// Returns:
+//   - *Registry: The default registry.
+func Default() *Registry {
+	return globalRegistry
+}
+
+// OnStart registers a start hook with the default registry.
+//
+// Parameters:
+//   - name: string. The name of the hook, used in logs and errors.
+//   - fn: Hook. The hook.
+//   - opts: ...Option. The order and timeout of the hook.
+func OnStart(name string, fn Hook, opts ...Option) {
+	globalRegistry.OnStart(name, fn, opts...)
+}
+
+// OnConfigReload registers a config reload hook with the default registry.
+//
+// Parameters:
+//   - name: string. The name of the hook, used in logs and errors.
+//   - fn: ReloadHook. The hook.
+//   - opts: ...Option. The order and timeout of the hook.
+func OnConfigReload(name string, fn ReloadHook, opts ...Option) {
+	globalRegistry.OnConfigReload(name, fn, opts...)
+}
+
+// OnShutdown registers a shutdown hook with the default registry.
+//
+// Parameters:
+//   - name: string. The name of the hook, used in logs and errors.
+//   - fn: Hook. The hook.
+//   - opts: ...Option. The order and timeout of the hook.
+func OnShutdown(name string, fn Hook, opts ...Option) {
+	globalRegistry.OnShutdown(name, fn, opts...)
+}
+
+// Merge creates a registry holding the hooks of the given registries.
+// Hooks keep their registration order, with the registries taken in turn.
+//
+// Parameters:
+//   - registries: ...*Registry. The registries to merge. Nil entries are skipped.
+//
+// Returns:
+//   - *Registry: The merged registry.
+func Merge(registries ...*Registry) *Registry {
+	merged := NewRegistry()
+	for _, r := range registries {
+		if r == nil {
+			continue
+		}
+		r.mu.Lock()
+		merged.entries = append(merged.entries, r.entries...)
+		r.mu.Unlock()
+	}
+	return merged
+}
+
+// OnStart registers a hook that runs when the server starts.
+//
+// Parameters:
+//   - name: string. The name of the hook, used in logs and errors.
+//   - fn: Hook. The hook.
+//   - opts: ...Option. The order and timeout of the hook.
+func (r *Registry) OnStart(name string, fn Hook, opts ...Option) {
+	r.add(&entry{name: name, start: fn}, opts)
+}
+
+// OnConfigReload registers a hook that runs after each successful
+// configuration reload. The hook must not trigger another reload.
+//
+// Parameters:
+//   - name: string. The name of the hook, used in logs and errors.
+//   - fn: ReloadHook. The hook.
+//   - opts: ...Option. The order and timeout of the hook.
+func (r *Registry) OnConfigReload(name string, fn ReloadHook, opts ...Option) {
+	r.add(&entry{name: name, reload: fn}, opts)
+}
+
+// OnShutdown registers a hook that runs when the server shuts down.
+//
+// Parameters:
+//   - name: string. The name of the hook, used in logs and errors.
+//   - fn: Hook. The hook.
+//   - opts: ...Option. The order and timeout of the hook.
+func (r *Registry) OnShutdown(name string, fn Hook, opts ...Option) {
+	r.add(&entry{name: name, shutdown: fn}, opts)
+}
+
+func (r *Registry) add(e *entry, opts []Option) {
+	e.order = OrderDefault
+	for _, opt := range opts {
+		opt(&e.hookOptions)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, e)
+}
+
+// Start runs the start hooks in ascending order, stopping at the first failure.
+//
+// Parameters:
+//   - ctx: context.Context. The context passed to the hooks.
+//
+// Returns:
+//   - error: The error of the first failing hook.
+func (r *Registry) Start(ctx context.Context) error {
+	for _, e := range r.sorted(func(e *entry) bool { return e.start != nil }, false) {
+		if _, err := run(ctx, "start", e, e.start); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reload runs the config reload hooks in ascending order. A failing hook does
+// not prevent the others from running.
+//
+// Parameters:
+//   - ctx: context.Context. The context passed to the hooks.
+//   - cfg: *configv1.McpAnyServerConfig. The reloaded configuration.
+//
+// Returns:
+//   - error: The errors of all failing hooks, joined.
+func (r *Registry) Reload(ctx context.Context, cfg *configv1.McpAnyServerConfig) error {
+	var errs []error
+	for _, e := range r.sorted(func(e *entry) bool { return e.reload != nil }, false) {
+		fn := e.reload
+		if _, err := run(ctx, "config reload", e, func(ctx context.Context) error { return fn(ctx, cfg) }); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Shutdown runs the shutdown hooks in descending order. Hooks with the same
+// order run in reverse registration order, like deferred calls. A failing
+// hook does not prevent the others from running. The hooks of an order start
+// once the hooks of the previous order have returned, including those that
+// exceeded their timeout, so that e.g. the storage is not closed under a hook
+// still using it. Shutdown waits for them until ctx is done; it then gives up
+// on the remaining hooks, so that one hung hook does not block the exit. Only
+// the first call runs the hooks; later calls return the same result.
+//
+// Parameters:
+//   - ctx: context.Context. The context passed to the hooks.
+//
+// Returns:
+//   - error: The errors of all failing hooks, joined, and ctx.Err() if a hook
+//     was still running when ctx was done.
+func (r *Registry) Shutdown(ctx context.Context) error {
+	r.shutdownOnce.Do(func() {
+		var errs []error
+		entries := r.sorted(func(e *entry) bool { return e.shutdown != nil }, true)
+		for len(entries) > 0 {
+			order := entries[0].order
+			var late []<-chan error
+			for len(entries) > 0 && entries[0].order == order {
+				e := entries[0]
+				entries = entries[1:]
+				pending, err := run(ctx, "shutdown", e, e.shutdown)
+				if err != nil {
+					logging.GetLogger().Error("Shutdown hook failed", "hook", e.name, "error", err)
+					errs = append(errs, err)
+				}
+				if pending != nil {
+					late = append(late, pending)
+				}
+			}
+			if err := await(ctx, late); err != nil {
+				logging.GetLogger().Error("Shutdown hooks still running, skipping the next ones", "order", order, "error", err)
+				errs = append(errs, err)
+				break
+			}
+		}
+		r.shutdownErr = errors.Join(errs...)
+	})
+	return r.shutdownErr
+}
+
+// await waits for the hooks that exceeded their timeout to return, until ctx
+// is done.
+func await(ctx context.Context, late []<-chan error) error {
+	for _, pending := range late {
+		select {
+		case <-pending:
+		case <-ctx.Done():
+			select {
+			case <-pending:
+			default:
+				return ctx.Err()
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Registry) sorted(keep func(*entry) bool, reverse bool) []*entry {
+	r.mu.Lock()
+	var entries []*entry
+	for _, e := range r.entries {
+		if keep(e) {
+			entries = append(entries, e)
+		}
+	}
+	r.mu.Unlock()
+
+	if reverse {
+		for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+			entries[i], entries[j] = entries[j], entries[i]
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if reverse {
+			return entries[i].order > entries[j].order
+		}
+		return entries[i].order < entries[j].order
+	})
+	return entries
+}
+
+// run calls a hook, bounded by its timeout, and converts panics into errors.
+// If the hook is still running when run gives up on it, run also returns the
+// channel its result arrives on once it returns.
+func run(ctx context.Context, phase string, e *entry, fn Hook) (<-chan error, error) {
+	if e.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				done <- fmt.Errorf("panic: %v", rec)
+			}
+		}()
+		done <- fn(ctx)
+	}()
+
+	var err error
+	var pending <-chan error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		select {
+		case err = <-done:
+		default:
+			err, pending = ctx.Err(), done
+		}
+	}
+	if err != nil {
+		return pending, fmt.Errorf("%s hook %q failed: %w", phase, e.name, err)
+	}
+	return nil, nil
+}
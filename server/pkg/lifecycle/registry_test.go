@@ -0,0 +1,157 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Ordering(t *testing.T) {
+	r := NewRegistry()
+	var calls []string
+	record := func(name string) Hook {
+		return func(context.Context) error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+
+	r.OnStart("ext", record("start ext"))
+	r.OnStart("storage", record("start storage"), WithOrder(OrderStorage))
+	r.OnStart("late", record("start late"), WithOrder(10))
+	r.OnShutdown("ext-a", record("stop ext-a"))
+	r.OnShutdown("storage", record("stop storage"), WithOrder(OrderStorage))
+	r.OnShutdown("ext-b", record("stop ext-b"))
+	r.OnShutdown("late", record("stop late"), WithOrder(10))
+
+	require.NoError(t, r.Start(context.Background()))
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	assert.Equal(t, []string{
+		"start storage", "start ext", "start late",
+		"stop late", "stop ext-b", "stop ext-a", "stop storage",
+	}, calls)
+}
+
+func TestRegistry_StartStopsAtFirstError(t *testing.T) {
+	r := NewRegistry()
+	var ran bool
+	r.OnStart("broken", func(context.Context) error { return errors.New("boom") })
+	r.OnStart("next", func(context.Context) error {
+		ran = true
+		return nil
+	}, WithOrder(1))
+
+	err := r.Start(context.Background())
+	assert.ErrorContains(t, err, `start hook "broken" failed: boom`)
+	assert.False(t, ran)
+}
+
+func TestRegistry_ShutdownContinuesAndRunsOnce(t *testing.T) {
+	r := NewRegistry()
+	var count int
+	r.OnShutdown("counted", func(context.Context) error {
+		count++
+		return nil
+	})
+	r.OnShutdown("broken", func(context.Context) error { return errors.New("boom") })
+	r.OnShutdown("panics", func(context.Context) error { panic("oops") })
+
+	err := r.Shutdown(context.Background())
+	assert.ErrorContains(t, err, `shutdown hook "broken" failed: boom`)
+	assert.ErrorContains(t, err, `shutdown hook "panics" failed: panic: oops`)
+	assert.Equal(t, 1, count)
+
+	assert.Equal(t, err, r.Shutdown(context.Background()))
+	assert.Equal(t, 1, count)
+}
+
+func TestRegistry_Timeout(t *testing.T) {
+	r := NewRegistry()
+	var stuckDone, storageRan, storageAfterStuck atomic.Bool
+	r.OnShutdown("stuck", func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond)
+		stuckDone.Store(true)
+		return nil
+	}, WithTimeout(10*time.Millisecond))
+	r.OnShutdown("storage", func(context.Context) error {
+		storageAfterStuck.Store(stuckDone.Load())
+		storageRan.Store(true)
+		return nil
+	}, WithOrder(OrderStorage))
+
+	err := r.Shutdown(context.Background())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, storageRan.Load(), "the next hooks run after a timeout")
+	assert.True(t, storageAfterStuck.Load(), "the next order waits for the hooks that timed out")
+}
+
+func TestRegistry_ShutdownBoundedByContext(t *testing.T) {
+	r := NewRegistry()
+	release := make(chan struct{})
+	defer close(release)
+	var storageRan atomic.Bool
+	r.OnShutdown("hung", func(context.Context) error {
+		// A hook that does not watch its context.
+		<-release
+		return nil
+	}, WithTimeout(10*time.Millisecond))
+	r.OnShutdown("storage", func(context.Context) error {
+		storageRan.Store(true)
+		return nil
+	}, WithOrder(OrderStorage))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := r.Shutdown(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second, "a hung hook does not block the exit")
+	assert.False(t, storageRan.Load(), "the next order does not run under a hung hook")
+}
+
+func TestRegistry_Reload(t *testing.T) {
+	r := NewRegistry()
+	cfg := &configv1.McpAnyServerConfig{}
+	var got *configv1.McpAnyServerConfig
+	r.OnConfigReload("broken", func(context.Context, *configv1.McpAnyServerConfig) error { return errors.New("boom") })
+	r.OnConfigReload("ok", func(_ context.Context, c *configv1.McpAnyServerConfig) error {
+		got = c
+		return nil
+	})
+
+	err := r.Reload(context.Background(), cfg)
+	assert.ErrorContains(t, err, "boom")
+	assert.Same(t, cfg, got)
+}
+
+func TestMerge(t *testing.T) {
+	a, b := NewRegistry(), NewRegistry()
+	var calls []string
+	a.OnShutdown("a", func(context.Context) error {
+		calls = append(calls, "a")
+		return nil
+	})
+	b.OnShutdown("b", func(context.Context) error {
+		calls = append(calls, "b")
+		return nil
+	})
+
+	merged := Merge(a, nil, b)
+	require.NoError(t, merged.Shutdown(context.Background()))
+	assert.Equal(t, []string{"b", "a"}, calls)
+
+	// The source registries are unaffected.
+	require.NoError(t, a.Shutdown(context.Background()))
+	assert.Equal(t, []string{"b", "a", "a"}, calls)
+}
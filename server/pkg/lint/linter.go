@@ -167,6 +167,7 @@ func (l *Linter) checkPlainTextSecrets() []Result {
 			}
 			if oauth := auth.GetOauth2(); oauth != nil {
 				checkSecret(oauth.GetClientSecret(), "upstream_auth.oauth2.client_secret", s.GetName())
+				checkSecret(oauth.GetRefreshToken(), "upstream_auth.oauth2.refresh_token", s.GetName())
 			}
 		}
 
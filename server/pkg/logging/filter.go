@@ -0,0 +1,110 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/consts"
+)
+
+// serviceKeys are the attributes the server logs the service of an entry
+// under.
+var serviceKeys = []string{"service", "serviceID", "service_id", "serviceName", "service_name"}
+
+// Filter selects log entries, e.g. for a filtered log stream. The zero Filter
+// matches every entry.
+type Filter struct {
+	// Service keeps the entries about the service with this ID or name.
+	Service string
+	// MinLevel keeps the entries at or above this level; nil keeps all.
+	MinLevel *slog.Level
+	// Since keeps the entries logged at or after this time; zero keeps all.
+	Since time.Time
+}
+
+// ParseLevel parses a level name as used in the configuration and the log
+// entries: debug, info, warn (or warning) or error, in any case.
+//
+// Parameters:
+//   - name (string): The level name.
+//
+// Returns:
+//   - slog.Level: The level.
+//   - error: An error if the name is not a level.
+func ParseLevel(name string) (slog.Level, error) {
+	if strings.EqualFold(name, "warning") {
+		return slog.LevelWarn, nil
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q: must be debug, info, warn or error", name)
+	}
+	return level, nil
+}
+
+// Match reports whether the entry passes the filter.
+//
+// Parameters:
+//   - entry (LogEntry): The log entry.
+//
+// Returns:
+//   - bool: True if the entry passes.
+func (f Filter) Match(entry LogEntry) bool {
+	if f.MinLevel != nil {
+		level, err := ParseLevel(entry.Level)
+		if err != nil || level < *f.MinLevel {
+			return false
+		}
+	}
+	if !f.Since.IsZero() {
+		ts, err := time.Parse(time.RFC3339, entry.Timestamp)
+		// The timestamps have a precision of one second.
+		if err != nil || ts.Before(f.Since.Truncate(time.Second)) {
+			return false
+		}
+	}
+	if f.Service != "" && !entry.aboutService(f.Service) {
+		return false
+	}
+	return true
+}
+
+// aboutService reports whether the entry names the service in one of its
+// service attributes, or comes from one of its tools.
+func (e LogEntry) aboutService(service string) bool {
+	for _, key := range serviceKeys {
+		if v, ok := e.Metadata[key].(string); ok && v == service {
+			return true
+		}
+	}
+	// Tool names are prefixed with the ID of their service.
+	if tool, ok := e.Metadata["toolName"].(string); ok && strings.HasPrefix(tool, service+consts.ToolNameServiceSeparator) {
+		return true
+	}
+	return false
+}
+
+// AsLogEntry returns the log entry of a message of the log broadcaster.
+//
+// Parameters:
+//   - msg (any): The message, a LogEntry or a *LogEntry.
+//
+// Returns:
+//   - LogEntry: The entry.
+//   - bool: False if the message is not a log entry.
+func AsLogEntry(msg any) (LogEntry, bool) {
+	switch e := msg.(type) {
+	case LogEntry:
+		return e, true
+	case *LogEntry:
+		if e != nil {
+			return *e, true
+		}
+	}
+	return LogEntry{}, false
+}
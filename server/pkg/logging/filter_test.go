@@ -0,0 +1,61 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLevel(t *testing.T) {
+	for name, want := range map[string]slog.Level{
+		"debug": slog.LevelDebug, "INFO": slog.LevelInfo, "warn": slog.LevelWarn, "Warning": slog.LevelWarn, "error": slog.LevelError,
+	} {
+		got, err := ParseLevel(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, got, name)
+	}
+	_, err := ParseLevel("loud")
+	assert.ErrorContains(t, err, `invalid log level "loud"`)
+}
+
+func TestFilter_Match(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	entry := LogEntry{
+		Timestamp: now.Format(time.RFC3339),
+		Level:     "WARN",
+		Message:   "upstream slow",
+		Metadata:  map[string]any{"service": "weather"},
+	}
+	warn, errLevel := slog.LevelWarn, slog.LevelError
+
+	assert.True(t, Filter{}.Match(entry))
+	assert.True(t, Filter{Service: "weather", MinLevel: &warn, Since: now.Add(500 * time.Millisecond)}.Match(entry), "same second")
+	assert.False(t, Filter{Service: "github"}.Match(entry))
+	assert.False(t, Filter{MinLevel: &errLevel}.Match(entry))
+	assert.False(t, Filter{Since: now.Add(time.Minute)}.Match(entry))
+
+	tool := LogEntry{Timestamp: entry.Timestamp, Level: "ERROR", Metadata: map[string]any{"toolName": "weather.get_forecast"}}
+	assert.True(t, Filter{Service: "weather"}.Match(tool), "a tool of the service")
+	assert.False(t, Filter{Service: "weath"}.Match(tool))
+	assert.True(t, Filter{Service: "w1"}.Match(LogEntry{Metadata: map[string]any{"service_id": "w1"}}))
+}
+
+func TestAsLogEntry(t *testing.T) {
+	entry := LogEntry{ID: "1"}
+	got, ok := AsLogEntry(entry)
+	assert.True(t, ok)
+	assert.Equal(t, entry, got)
+	got, ok = AsLogEntry(&entry)
+	assert.True(t, ok)
+	assert.Equal(t, entry, got)
+	_, ok = AsLogEntry("text")
+	assert.False(t, ok)
+	_, ok = AsLogEntry((*LogEntry)(nil))
+	assert.False(t, ok)
+}
@@ -91,7 +91,7 @@ func (h *BroadcastHandler) Handle(_ context.Context, r slog.Record) error {
 		ID:        uuid.New().String(),
 		Timestamp: r.Time.Format(time.RFC3339),
 		Level:     r.Level.String(),
-		Message:   r.Message,
+		Message:   redactValue(r.Message),
 		Metadata:  make(map[string]any),
 	}
 
@@ -114,6 +114,10 @@ func (h *BroadcastHandler) Handle(_ context.Context, r slog.Record) error {
 			}
 		}
 
+		if a.Value.Kind() == slog.KindString {
+			targetMap[a.Key] = redactValue(a.Value.String())
+			return
+		}
 		targetMap[a.Key] = a.Value.Any()
 	}
 
@@ -21,6 +21,8 @@ var (
 	defaultLogger atomic.Pointer[slog.Logger]
 	// programLevel is the dynamic log level control.
 	programLevel = new(slog.LevelVar)
+	// valueRedactor masks sensitive values in log messages and string attributes.
+	valueRedactor atomic.Pointer[func(string) string]
 )
 
 // SetLevel updates the global log level dynamically.
@@ -34,6 +36,30 @@ func SetLevel(level slog.Level) {
 	programLevel.Set(level)
 }
 
+// SetValueRedactor sets the function that masks sensitive values, such as
+// email addresses or API keys, in log messages and string attributes.
+//
+// Parameters:
+//   - fn (func(string) string): Returns its input with sensitive values masked. nil turns masking off.
+//
+// Side Effects:
+//   - Applies to all loggers, including ones created before the call.
+func SetValueRedactor(fn func(string) string) {
+	if fn == nil {
+		valueRedactor.Store(nil)
+		return
+	}
+	valueRedactor.Store(&fn)
+}
+
+// redactValue masks the sensitive values of s with the value redactor, if any.
+func redactValue(s string) string {
+	if fn := valueRedactor.Load(); fn != nil {
+		return (*fn)(s)
+	}
+	return s
+}
+
 // ForTestsOnlyResetLogger is for use in tests to reset the `sync.Once` mechanism. This allows the global logger to be re-initialized in different test cases. This function should not be used in production code.
 //
 // Parameters:
@@ -92,6 +118,11 @@ func Init(level slog.Level, output io.Writer, logFilePath string, format ...stri
 			if util.IsSensitiveKey(a.Key) {
 				return slog.String(a.Key, "[REDACTED]")
 			}
+			if a.Value.Kind() == slog.KindString {
+				if v := redactValue(a.Value.String()); v != a.Value.String() {
+					return slog.String(a.Key, v)
+				}
+			}
 			return a
 		},
 	}
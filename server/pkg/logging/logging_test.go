@@ -169,6 +169,33 @@ func TestBroadcaster(t *testing.T) {
 	assert.Len(t, b.subscribers, 0)
 }
 
+func TestValueRedactor(t *testing.T) {
+	SetValueRedactor(func(s string) string { return strings.ReplaceAll(s, "alice@corp.com", "***") })
+	defer SetValueRedactor(nil)
+
+	for _, format := range []string{"text", "json"} {
+		t.Run(format, func(t *testing.T) {
+			ForTestsOnlyResetLogger()
+			var buf bytes.Buffer
+			Init(slog.LevelInfo, &buf, "", format)
+
+			GetLogger().Info("mail from alice@corp.com", "to", "alice@corp.com", "count", 1)
+			assert.NotContains(t, buf.String(), "alice@corp.com")
+			assert.Contains(t, buf.String(), "mail from ***")
+		})
+	}
+
+	b := NewBroadcaster()
+	ch := b.Subscribe()
+	defer b.Unsubscribe(ch)
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "mail from alice@corp.com", 0)
+	r.AddAttrs(slog.String("to", "alice@corp.com"))
+	require.NoError(t, NewBroadcastHandler(b, slog.LevelInfo).Handle(context.Background(), r))
+	entry := (<-ch).(LogEntry)
+	assert.Equal(t, "mail from ***", entry.Message)
+	assert.Equal(t, "***", entry.Metadata["to"])
+}
+
 func TestBroadcastHandler(t *testing.T) {
 	b := NewBroadcaster()
 	h := NewBroadcastHandler(b, slog.LevelInfo)
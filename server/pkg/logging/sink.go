@@ -0,0 +1,381 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/metrics"
+)
+
+const (
+	defaultSinkBatchSize     = 100
+	defaultSinkFlushInterval = time.Second
+	defaultSinkQueueSize     = 10000
+	// sinkSubscriberBuffer is the buffer of the broadcaster subscription. The
+	// collector drains it into the queue right away, so it only absorbs bursts.
+	sinkSubscriberBuffer = 1024
+	sinkSendTimeout      = 30 * time.Second
+)
+
+var (
+	metricLogSinkSent    = []string{"logging", "sink", "sent"}
+	metricLogSinkFailed  = []string{"logging", "sink", "failed"}
+	metricLogSinkDropped = []string{"logging", "sink", "dropped"}
+)
+
+// Sink is an external system the server logs are shipped to.
+//
+// Summary: Destination of the log shipper.
+type Sink interface {
+	// Send sends a batch of entries, oldest first.
+	Send(ctx context.Context, entries []LogEntry) error
+	// Close releases the resources of the sink.
+	Close() error
+}
+
+// NewSink creates the sink of a log sink configuration.
+//
+// Summary: Creates a syslog, Loki or OTLP sink.
+//
+// Parameters:
+//   - config: *configv1.LogSinkConfig. The sink configuration.
+//
+// Returns:
+//   - Sink: The sink.
+//   - error: An error if the configuration has no sink or is invalid.
+func NewSink(config *configv1.LogSinkConfig) (Sink, error) {
+	switch {
+	case config.HasSyslog():
+		return NewSyslogSink(config.GetSyslog())
+	case config.HasLoki():
+		return NewLokiSink(config.GetLoki())
+	case config.HasOtlp():
+		return NewOTLPSink(config.GetOtlp())
+	default:
+		return nil, errors.New("log sink has no syslog, loki or otlp configuration")
+	}
+}
+
+// sinkName returns the name of a sink in the metrics and error messages.
+func sinkName(config *configv1.LogSinkConfig) string {
+	if name := config.GetName(); name != "" {
+		return name
+	}
+	switch {
+	case config.HasSyslog():
+		return "syslog"
+	case config.HasLoki():
+		return "loki"
+	case config.HasOtlp():
+		return "otlp"
+	default:
+		return "unknown"
+	}
+}
+
+// Shipper ships the entries of a broadcaster to a sink in batches.
+//
+// Entries are collected into a bounded queue and sent when a batch is full
+// or at the flush interval. A batch that cannot be sent goes back to the
+// queue and is retried at the next flush. When the queue is full, the oldest
+// entries are dropped, so a slow or unavailable sink never blocks logging.
+//
+// Summary: Batching, non-blocking log shipper.
+type Shipper struct {
+	name          string
+	sink          Sink
+	level         slog.Level
+	batchSize     int
+	flushInterval time.Duration
+	queueSize     int
+	labels        []metrics.Label
+
+	broadcaster *Broadcaster
+	ch          chan any
+
+	mu      sync.Mutex
+	queue   []LogEntry
+	failing bool
+	// shipMu keeps the batches in order when Flush ships along with run.
+	shipMu sync.Mutex
+
+	flush     chan struct{}
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// NewShipper creates the sink of a configuration and starts shipping the
+// entries of a broadcaster to it.
+//
+// Summary: Initializes a log sink and its shipper.
+//
+// Parameters:
+//   - broadcaster: *Broadcaster. The broadcaster of the entries, usually GlobalBroadcaster.
+//   - config: *configv1.LogSinkConfig. The sink configuration.
+//
+// Returns:
+//   - *Shipper: The shipper.
+//   - error: An error if the sink cannot be created.
+//
+// Side Effects:
+//   - Subscribes to the broadcaster and starts background goroutines.
+func NewShipper(broadcaster *Broadcaster, config *configv1.LogSinkConfig) (*Shipper, error) {
+	sink, err := NewSink(config)
+	if err != nil {
+		return nil, fmt.Errorf("log sink %q: %w", sinkName(config), err)
+	}
+	return newShipper(broadcaster, config, sink), nil
+}
+
+func newShipper(broadcaster *Broadcaster, config *configv1.LogSinkConfig, sink Sink) *Shipper {
+	name := sinkName(config)
+	s := &Shipper{
+		name:          name,
+		sink:          sink,
+		level:         slog.LevelInfo,
+		batchSize:     int(config.GetBatchSize()),
+		flushInterval: config.GetFlushInterval().AsDuration(),
+		queueSize:     int(config.GetQueueSize()),
+		labels:        []metrics.Label{{Name: "sink", Value: name}},
+		broadcaster:   broadcaster,
+		ch:            broadcaster.SubscribeBuffered(sinkSubscriberBuffer),
+		flush:         make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}
+	if config.HasLevel() && config.GetLevel() != configv1.GlobalSettings_LOG_LEVEL_UNSPECIFIED {
+		s.level = ToSlogLevel(config.GetLevel())
+	}
+	if s.batchSize <= 0 {
+		s.batchSize = defaultSinkBatchSize
+	}
+	if s.flushInterval <= 0 {
+		s.flushInterval = defaultSinkFlushInterval
+	}
+	if s.queueSize <= 0 {
+		s.queueSize = defaultSinkQueueSize
+	}
+	if s.queueSize < s.batchSize {
+		s.queueSize = s.batchSize
+	}
+
+	s.wg.Add(2)
+	go s.collect()
+	go s.run()
+	return s
+}
+
+// Name returns the name of the sink.
+//
+// Returns:
+//   - string: The name.
+func (s *Shipper) Name() string {
+	return s.name
+}
+
+// collect moves the broadcast entries into the queue.
+func (s *Shipper) collect() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.done:
+			return
+		case msg, ok := <-s.ch:
+			if !ok {
+				return
+			}
+			var entry LogEntry
+			switch e := msg.(type) {
+			case LogEntry:
+				entry = e
+			case *LogEntry:
+				entry = *e
+			default:
+				continue
+			}
+			if parseEntryLevel(entry.Level) < s.level {
+				continue
+			}
+			s.enqueue(entry)
+		}
+	}
+}
+
+func (s *Shipper) enqueue(entry LogEntry) {
+	s.mu.Lock()
+	s.queue = append(s.queue, entry)
+	dropped := s.trimLocked()
+	full := len(s.queue) >= s.batchSize
+	s.mu.Unlock()
+
+	if dropped > 0 {
+		metrics.IncrCounterWithLabels(metricLogSinkDropped, float32(dropped), s.labels)
+	}
+	if full {
+		select {
+		case s.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// trimLocked drops the oldest entries beyond the queue size and returns how
+// many were dropped.
+func (s *Shipper) trimLocked() int {
+	over := len(s.queue) - s.queueSize
+	if over <= 0 {
+		return 0
+	}
+	s.queue = append(s.queue[:0], s.queue[over:]...)
+	return over
+}
+
+func (s *Shipper) run() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.ship(context.Background(), true)
+		case <-s.flush:
+			s.ship(context.Background(), false)
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// ship sends the queued entries in batches; unless all is set, only full
+// batches are sent. It stops at the first batch that cannot be sent, which
+// goes back to the front of the queue.
+func (s *Shipper) ship(ctx context.Context, all bool) {
+	s.shipMu.Lock()
+	defer s.shipMu.Unlock()
+	for {
+		s.mu.Lock()
+		n := min(len(s.queue), s.batchSize)
+		if n == 0 || (!all && n < s.batchSize) {
+			s.mu.Unlock()
+			return
+		}
+		batch := make([]LogEntry, n)
+		copy(batch, s.queue)
+		s.queue = append(s.queue[:0], s.queue[n:]...)
+		s.mu.Unlock()
+
+		sendCtx, cancel := context.WithTimeout(ctx, sinkSendTimeout)
+		err := s.sink.Send(sendCtx, batch)
+		cancel()
+		if err != nil {
+			s.requeue(batch, err)
+			return
+		}
+		metrics.IncrCounterWithLabels(metricLogSinkSent, float32(n), s.labels)
+		s.setFailing(false, nil)
+	}
+}
+
+func (s *Shipper) requeue(batch []LogEntry, err error) {
+	metrics.IncrCounterWithLabels(metricLogSinkFailed, 1, s.labels)
+	s.mu.Lock()
+	s.queue = append(batch, s.queue...)
+	dropped := s.trimLocked()
+	s.mu.Unlock()
+	if dropped > 0 {
+		metrics.IncrCounterWithLabels(metricLogSinkDropped, float32(dropped), s.labels)
+	}
+	s.setFailing(true, err)
+}
+
+// setFailing reports the changes of the sink state. Errors go to stderr
+// rather than the logger, whose entries would be shipped to the failing sink.
+func (s *Shipper) setFailing(failing bool, err error) {
+	s.mu.Lock()
+	changed := s.failing != failing
+	s.failing = failing
+	s.mu.Unlock()
+	if !changed {
+		return
+	}
+	if failing {
+		fmt.Fprintf(os.Stderr, "Log sink %s failed, queueing entries until it recovers: %v\n", s.name, err)
+	} else {
+		fmt.Fprintf(os.Stderr, "Log sink %s recovered\n", s.name)
+	}
+}
+
+// Flush sends the queued entries.
+//
+// Summary: Ships the queued entries now.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the sends.
+//
+// Returns:
+//   - error: An error if entries are left in the queue, because the sink
+//     failed or ctx is done. They are retried at the next flush.
+func (s *Shipper) Flush(ctx context.Context) error {
+	s.ship(ctx, true)
+	s.mu.Lock()
+	left := len(s.queue)
+	s.mu.Unlock()
+	if left > 0 {
+		return fmt.Errorf("log sink %s: %d entries not sent", s.name, left)
+	}
+	return nil
+}
+
+// Close stops shipping, sends the queued entries and closes the sink.
+//
+// Summary: Flushes and closes the shipper.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the final flush.
+//
+// Returns:
+//   - error: An error if the sink cannot be closed.
+//
+// Side Effects:
+//   - Unsubscribes from the broadcaster. Entries that cannot be sent before
+//     ctx is done are dropped.
+func (s *Shipper) Close(ctx context.Context) error {
+	var err error
+	s.closeOnce.Do(func() {
+		s.broadcaster.Unsubscribe(s.ch)
+		close(s.done)
+		s.wg.Wait()
+		s.ship(ctx, true)
+		err = s.sink.Close()
+	})
+	return err
+}
+
+// parseEntryLevel returns the level of an entry, as written by
+// slog.Level.String, e.g. "INFO" or "WARN+2".
+func parseEntryLevel(level string) slog.Level {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.ToUpper(level))); err != nil {
+		return slog.LevelInfo
+	}
+	return l
+}
+
+// entryTime returns the timestamp of an entry, or the current time if it
+// cannot be parsed.
+func entryTime(entry LogEntry) time.Time {
+	if t, err := time.Parse(time.RFC3339Nano, entry.Timestamp); err == nil {
+		return t
+	}
+	return time.Now()
+}
@@ -0,0 +1,164 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+)
+
+// LokiSink sends the logs to the push API of Grafana Loki. Entries are
+// grouped into streams by level and source.
+//
+// Summary: Log sink for Grafana Loki.
+type LokiSink struct {
+	url      string
+	labels   map[string]string
+	tenantID string
+	headers  map[string]string
+	client   *http.Client
+}
+
+type lokiPush struct {
+	Streams []*lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// NewLokiSink creates a new LokiSink.
+//
+// Summary: Initializes a Loki sink.
+//
+// Parameters:
+//   - config: *configv1.LokiSinkConfig. The Loki configuration.
+//
+// Returns:
+//   - *LokiSink: The sink.
+//   - error: An error if the push URL is invalid.
+func NewLokiSink(config *configv1.LokiSinkConfig) (*LokiSink, error) {
+	if err := validateSinkURL(config.GetUrl()); err != nil {
+		return nil, fmt.Errorf("invalid loki url: %w", err)
+	}
+	return &LokiSink{
+		url:      config.GetUrl(),
+		labels:   config.GetLabels(),
+		tenantID: config.GetTenantId(),
+		headers:  config.GetHeaders(),
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Send implements the Sink interface.
+//
+// Summary: Pushes a batch of entries to Loki.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the request.
+//   - entries: []LogEntry. The entries.
+//
+// Returns:
+//   - error: An error if Loki cannot be reached or rejects the entries.
+func (s *LokiSink) Send(ctx context.Context, entries []LogEntry) error {
+	push := lokiPush{}
+	streams := make(map[[2]string]*lokiStream)
+	for _, entry := range entries {
+		key := [2]string{strings.ToLower(entry.Level), entry.Source}
+		stream, ok := streams[key]
+		if !ok {
+			labels := maps.Clone(s.labels)
+			if labels == nil {
+				labels = make(map[string]string)
+			}
+			labels["level"] = key[0]
+			if entry.Source != "" {
+				labels["source"] = entry.Source
+			}
+			stream = &lokiStream{Stream: labels}
+			streams[key] = stream
+			push.Streams = append(push.Streams, stream)
+		}
+		line := make(map[string]any, len(entry.Metadata)+1)
+		maps.Copy(line, entry.Metadata)
+		line["msg"] = entry.Message
+		data, err := json.Marshal(line)
+		if err != nil {
+			data, _ = json.Marshal(map[string]string{"msg": entry.Message})
+		}
+		ts := strconv.FormatInt(entryTime(entry).UnixNano(), 10)
+		stream.Values = append(stream.Values, [2]string{ts, string(data)})
+	}
+
+	body, err := json.Marshal(push)
+	if err != nil {
+		return fmt.Errorf("failed to encode loki push: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create loki request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	if s.tenantID != "" {
+		req.Header.Set("X-Scope-OrgID", s.tenantID)
+	}
+	return doSinkRequest(s.client, req)
+}
+
+// Close implements the Sink interface.
+//
+// Returns:
+//   - error: Always nil.
+func (s *LokiSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// validateSinkURL checks that a sink URL is an absolute HTTP(S) URL.
+func validateSinkURL(raw string) error {
+	if raw == "" {
+		return fmt.Errorf("url is required")
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("scheme must be http or https, got %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("host is required")
+	}
+	return nil
+}
+
+// doSinkRequest sends a request of an HTTP sink and checks its status.
+func doSinkRequest(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send logs: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("logs rejected with status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestLokiSink(t *testing.T) {
+	var push lokiPush
+	var tenant, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/loki/api/v1/push", r.URL.Path)
+		tenant = r.Header.Get("X-Scope-OrgID")
+		auth = r.Header.Get("Authorization")
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&push))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sink, err := NewLokiSink(configv1.LokiSinkConfig_builder{
+		Url:      proto.String(server.URL + "/loki/api/v1/push"),
+		Labels:   map[string]string{"env": "prod"},
+		TenantId: proto.String("team-a"),
+		Headers:  map[string]string{"Authorization": "Bearer token"},
+	}.Build())
+	require.NoError(t, err)
+	defer func() { _ = sink.Close() }()
+
+	require.NoError(t, sink.Send(context.Background(), []LogEntry{
+		{Timestamp: "2026-01-02T03:04:05Z", Level: "INFO", Message: "started"},
+		{Timestamp: "2026-01-02T03:04:06Z", Level: "ERROR", Message: "failed", Source: "weather.get", Metadata: map[string]any{"status": 502}},
+		{Timestamp: "2026-01-02T03:04:07Z", Level: "INFO", Message: "ready"},
+	}))
+
+	assert.Equal(t, "team-a", tenant)
+	assert.Equal(t, "Bearer token", auth)
+	require.Len(t, push.Streams, 2)
+	assert.Equal(t, map[string]string{"env": "prod", "level": "info"}, push.Streams[0].Stream)
+	assert.Equal(t, [][2]string{
+		{"1767323045000000000", `{"msg":"started"}`},
+		{"1767323047000000000", `{"msg":"ready"}`},
+	}, push.Streams[0].Values)
+	assert.Equal(t, map[string]string{"env": "prod", "level": "error", "source": "weather.get"}, push.Streams[1].Stream)
+	assert.Equal(t, [][2]string{{"1767323046000000000", `{"msg":"failed","status":502}`}}, push.Streams[1].Values)
+}
+
+func TestLokiSink_Rejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "entry too far behind", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	sink, err := NewLokiSink(configv1.LokiSinkConfig_builder{Url: proto.String(server.URL)}.Build())
+	require.NoError(t, err)
+	err = sink.Send(context.Background(), []LogEntry{{Level: "INFO", Message: "old"}})
+	assert.ErrorContains(t, err, "logs rejected with status 400 Bad Request: entry too far behind")
+}
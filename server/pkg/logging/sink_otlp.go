@@ -0,0 +1,217 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+)
+
+// OTLPSink exports the logs with OTLP over HTTP, in the JSON encoding.
+//
+// Summary: Log sink for OpenTelemetry collectors.
+type OTLPSink struct {
+	endpoint    string
+	headers     map[string]string
+	serviceName string
+	client      *http.Client
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 otlpAnyValue   `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+	TraceID              string         `json:"traceId,omitempty"`
+}
+
+type otlpScopeLogs struct {
+	Scope      map[string]string `json:"scope"`
+	LogRecords []otlpLogRecord   `json:"logRecords"`
+}
+
+type otlpResourceLogs struct {
+	Resource  map[string][]otlpKeyValue `json:"resource"`
+	ScopeLogs []otlpScopeLogs           `json:"scopeLogs"`
+}
+
+type otlpExportLogsRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+// NewOTLPSink creates a new OTLPSink.
+//
+// Summary: Initializes an OTLP log sink.
+//
+// Parameters:
+//   - config: *configv1.OtlpLogSinkConfig. The OTLP configuration.
+//
+// Returns:
+//   - *OTLPSink: The sink.
+//   - error: An error if the endpoint is invalid.
+func NewOTLPSink(config *configv1.OtlpLogSinkConfig) (*OTLPSink, error) {
+	if err := validateSinkURL(config.GetEndpoint()); err != nil {
+		return nil, fmt.Errorf("invalid otlp endpoint: %w", err)
+	}
+	serviceName := config.GetServiceName()
+	if serviceName == "" {
+		serviceName = "mcpany"
+	}
+	return &OTLPSink{
+		endpoint:    config.GetEndpoint(),
+		headers:     config.GetHeaders(),
+		serviceName: serviceName,
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Send implements the Sink interface.
+//
+// Summary: Exports a batch of entries to the collector.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the request.
+//   - entries: []LogEntry. The entries.
+//
+// Returns:
+//   - error: An error if the collector cannot be reached or rejects the entries.
+func (s *OTLPSink) Send(ctx context.Context, entries []LogEntry) error {
+	observed := strconv.FormatInt(time.Now().UnixNano(), 10)
+	records := make([]otlpLogRecord, 0, len(entries))
+	for _, entry := range entries {
+		record := otlpLogRecord{
+			TimeUnixNano:         strconv.FormatInt(entryTime(entry).UnixNano(), 10),
+			ObservedTimeUnixNano: observed,
+			SeverityNumber:       otlpSeverity(entry.Level),
+			SeverityText:         entry.Level,
+			Body:                 otlpString(entry.Message),
+			Attributes:           otlpAttributes(entry),
+		}
+		if traceID, ok := entry.Metadata["trace_id"].(string); ok && len(traceID) == 32 {
+			record.TraceID = traceID
+		}
+		records = append(records, record)
+	}
+	export := otlpExportLogsRequest{ResourceLogs: []otlpResourceLogs{{
+		Resource: map[string][]otlpKeyValue{
+			"attributes": {{Key: "service.name", Value: otlpString(s.serviceName)}},
+		},
+		ScopeLogs: []otlpScopeLogs{{
+			Scope:      map[string]string{"name": "github.com/mcpany/core/server/pkg/logging"},
+			LogRecords: records,
+		}},
+	}}}
+
+	body, err := json.Marshal(export)
+	if err != nil {
+		return fmt.Errorf("failed to encode otlp export: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create otlp request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	return doSinkRequest(s.client, req)
+}
+
+// Close implements the Sink interface.
+//
+// Returns:
+//   - error: Always nil.
+func (s *OTLPSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// otlpSeverity maps a log level to an OTLP severity number.
+func otlpSeverity(level string) int {
+	switch l := parseEntryLevel(level); {
+	case l >= slog.LevelError:
+		return 17
+	case l >= slog.LevelWarn:
+		return 13
+	case l >= slog.LevelInfo:
+		return 9
+	default:
+		return 5
+	}
+}
+
+// otlpAttributes returns the source and the metadata of an entry as
+// attributes, sorted by key. Nested values are encoded as JSON strings.
+func otlpAttributes(entry LogEntry) []otlpKeyValue {
+	attrs := make([]otlpKeyValue, 0, len(entry.Metadata)+1)
+	if entry.Source != "" {
+		attrs = append(attrs, otlpKeyValue{Key: "source", Value: otlpString(entry.Source)})
+	}
+	keys := make([]string, 0, len(entry.Metadata))
+	for k := range entry.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		attrs = append(attrs, otlpKeyValue{Key: k, Value: otlpValue(entry.Metadata[k])})
+	}
+	return attrs
+}
+
+func otlpValue(v any) otlpAnyValue {
+	switch v := v.(type) {
+	case string:
+		return otlpString(v)
+	case bool:
+		return otlpAnyValue{BoolValue: &v}
+	case int:
+		s := strconv.Itoa(v)
+		return otlpAnyValue{IntValue: &s}
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		return otlpAnyValue{IntValue: &s}
+	case uint64:
+		s := strconv.FormatUint(v, 10)
+		return otlpAnyValue{IntValue: &s}
+	case float64:
+		return otlpAnyValue{DoubleValue: &v}
+	case fmt.Stringer:
+		return otlpString(v.String())
+	case error:
+		return otlpString(v.Error())
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return otlpString(fmt.Sprint(v))
+		}
+		return otlpString(string(data))
+	}
+}
+
+func otlpString(s string) otlpAnyValue {
+	return otlpAnyValue{StringValue: &s}
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestOTLPSink(t *testing.T) {
+	var export map[string]any
+	var apiKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/logs", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		apiKey = r.Header.Get("X-Api-Key")
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&export))
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	sink, err := NewOTLPSink(configv1.OtlpLogSinkConfig_builder{
+		Endpoint:    proto.String(server.URL + "/v1/logs"),
+		Headers:     map[string]string{"X-Api-Key": "secret"},
+		ServiceName: proto.String("gateway"),
+	}.Build())
+	require.NoError(t, err)
+	defer func() { _ = sink.Close() }()
+
+	require.NoError(t, sink.Send(context.Background(), []LogEntry{{
+		Timestamp: "2026-01-02T03:04:05Z",
+		Level:     "WARN",
+		Message:   "slow call",
+		Source:    "weather.get",
+		Metadata: map[string]any{
+			"trace_id":    "4bf92f3577b34da6a3ce929d0e0e4736",
+			"duration_ms": 1500,
+			"cached":      false,
+			"upstream":    map[string]any{"status": 200},
+		},
+	}}))
+
+	assert.Equal(t, "secret", apiKey)
+	resourceLogs := export["resourceLogs"].([]any)[0].(map[string]any)
+	assert.Equal(t, []any{map[string]any{"key": "service.name", "value": map[string]any{"stringValue": "gateway"}}},
+		resourceLogs["resource"].(map[string]any)["attributes"])
+	record := resourceLogs["scopeLogs"].([]any)[0].(map[string]any)["logRecords"].([]any)[0].(map[string]any)
+	assert.Equal(t, "1767323045000000000", record["timeUnixNano"])
+	assert.Equal(t, float64(13), record["severityNumber"])
+	assert.Equal(t, "WARN", record["severityText"])
+	assert.Equal(t, map[string]any{"stringValue": "slow call"}, record["body"])
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record["traceId"])
+	assert.Equal(t, []any{
+		map[string]any{"key": "source", "value": map[string]any{"stringValue": "weather.get"}},
+		map[string]any{"key": "cached", "value": map[string]any{"boolValue": false}},
+		map[string]any{"key": "duration_ms", "value": map[string]any{"intValue": "1500"}},
+		map[string]any{"key": "trace_id", "value": map[string]any{"stringValue": "4bf92f3577b34da6a3ce929d0e0e4736"}},
+		map[string]any{"key": "upstream", "value": map[string]any{"stringValue": `{"status":200}`}},
+	}, record["attributes"])
+}
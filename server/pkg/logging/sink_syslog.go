@@ -0,0 +1,175 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+)
+
+// syslogFacilities maps the facility names to their codes (RFC 5424).
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5,
+	"lpr": 6, "news": 7, "uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// SyslogSink sends the logs to a syslog server in the RFC 5424 format. Over
+// TCP, messages are framed with octet counting (RFC 6587).
+//
+// Summary: Log sink for syslog servers.
+type SyslogSink struct {
+	network  string
+	address  string
+	facility int
+	appName  string
+	hostname string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslogSink creates a new SyslogSink. The server is connected to on the
+// first send.
+//
+// Summary: Initializes a syslog sink.
+//
+// Parameters:
+//   - config: *configv1.SyslogSinkConfig. The syslog configuration.
+//
+// Returns:
+//   - *SyslogSink: The sink.
+//   - error: An error if the network or the facility is unknown.
+func NewSyslogSink(config *configv1.SyslogSinkConfig) (*SyslogSink, error) {
+	s := &SyslogSink{
+		network: config.GetNetwork(),
+		address: config.GetAddress(),
+		appName: config.GetAppName(),
+	}
+	switch s.network {
+	case "":
+		s.network = "udp"
+	case "udp", "tcp", "unix", "unixgram":
+	default:
+		return nil, fmt.Errorf("unsupported syslog network %q", s.network)
+	}
+	if s.address == "" {
+		return nil, fmt.Errorf("syslog address is required")
+	}
+	facility := config.GetFacility()
+	if facility == "" {
+		facility = "local0"
+	}
+	code, ok := syslogFacilities[facility]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility %q", facility)
+	}
+	s.facility = code
+	if s.appName == "" {
+		s.appName = "mcpany"
+	}
+	if s.hostname, _ = os.Hostname(); s.hostname == "" {
+		s.hostname = "-"
+	}
+	return s, nil
+}
+
+// Send implements the Sink interface.
+//
+// Summary: Writes a batch of entries to the syslog server.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the connection and the writes.
+//   - entries: []LogEntry. The entries.
+//
+// Returns:
+//   - error: An error if the server cannot be reached. The connection is
+//     reopened on the next send.
+func (s *SyslogSink) Send(ctx context.Context, entries []LogEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, s.network, s.address)
+		if err != nil {
+			return fmt.Errorf("failed to connect to syslog server: %w", err)
+		}
+		s.conn = conn
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.conn.SetWriteDeadline(deadline)
+	}
+
+	stream := s.network == "tcp" || s.network == "unix"
+	for _, entry := range entries {
+		msg := s.format(entry)
+		if stream {
+			msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+		}
+		if _, err := s.conn.Write(msg); err != nil {
+			_ = s.conn.Close()
+			s.conn = nil
+			return fmt.Errorf("failed to write to syslog server: %w", err)
+		}
+	}
+	return nil
+}
+
+// format returns the RFC 5424 message of an entry. The metadata of the entry
+// follows its message as JSON.
+func (s *SyslogSink) format(entry LogEntry) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "<%d>1 %s %s %s %d - - %s",
+		s.facility*8+syslogSeverity(entry.Level),
+		entryTime(entry).Format(time.RFC3339Nano),
+		s.hostname, s.appName, os.Getpid(), entry.Message)
+	if len(entry.Metadata) > 0 {
+		if data, err := json.Marshal(entry.Metadata); err == nil {
+			b.WriteByte(' ')
+			b.Write(data)
+		}
+	}
+	return b.Bytes()
+}
+
+// syslogSeverity maps a log level to a syslog severity.
+func syslogSeverity(level string) int {
+	switch l := parseEntryLevel(level); {
+	case l >= slog.LevelError:
+		return 3 // err
+	case l >= slog.LevelWarn:
+		return 4 // warning
+	case l >= slog.LevelInfo:
+		return 6 // info
+	default:
+		return 7 // debug
+	}
+}
+
+// Close implements the Sink interface.
+//
+// Returns:
+//   - error: An error if the connection cannot be closed.
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSyslogSink_UDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	sink, err := NewSyslogSink(configv1.SyslogSinkConfig_builder{
+		Address:  proto.String(conn.LocalAddr().String()),
+		Facility: proto.String("daemon"),
+		AppName:  proto.String("gateway"),
+	}.Build())
+	require.NoError(t, err)
+	defer func() { _ = sink.Close() }()
+
+	require.NoError(t, sink.Send(context.Background(), []LogEntry{{
+		Timestamp: "2026-01-02T03:04:05Z",
+		Level:     "ERROR",
+		Message:   "upstream failed",
+		Metadata:  map[string]any{"service": "weather"},
+	}}))
+
+	buf := make([]byte, 1024)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	msg := string(buf[:n])
+	// daemon (3) * 8 + err (3) = 27
+	assert.True(t, strings.HasPrefix(msg, "<27>1 2026-01-02T03:04:05Z "), msg)
+	assert.Contains(t, msg, " gateway ")
+	assert.True(t, strings.HasSuffix(msg, ` - - upstream failed {"service":"weather"}`), msg)
+}
+
+func TestSyslogSink_TCP(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = lis.Close() }()
+	received := make(chan string, 2)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		r := bufio.NewReader(conn)
+		for {
+			length, err := r.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(length))
+			msg := make([]byte, n)
+			if _, err := io.ReadFull(r, msg); err != nil {
+				return
+			}
+			received <- string(msg)
+		}
+	}()
+
+	sink, err := NewSyslogSink(configv1.SyslogSinkConfig_builder{
+		Network: proto.String("tcp"),
+		Address: proto.String(lis.Addr().String()),
+	}.Build())
+	require.NoError(t, err)
+	defer func() { _ = sink.Close() }()
+
+	require.NoError(t, sink.Send(context.Background(), []LogEntry{
+		{Level: "INFO", Message: "first"},
+		{Level: "DEBUG", Message: "second"},
+	}))
+	// local0 (16) * 8 + info (6) = 134, and debug (7) = 135
+	assert.True(t, strings.HasPrefix(<-received, "<134>1 "))
+	assert.True(t, strings.HasSuffix(<-received, " - - second"))
+}
+
+func TestNewSyslogSink_Invalid(t *testing.T) {
+	_, err := NewSyslogSink(configv1.SyslogSinkConfig_builder{Address: proto.String("localhost:514"), Network: proto.String("sctp")}.Build())
+	assert.ErrorContains(t, err, `unsupported syslog network "sctp"`)
+	_, err = NewSyslogSink(configv1.SyslogSinkConfig_builder{Address: proto.String("localhost:514"), Facility: proto.String("local9")}.Build())
+	assert.ErrorContains(t, err, `unknown syslog facility "local9"`)
+	_, err = NewSyslogSink(configv1.SyslogSinkConfig_builder{}.Build())
+	assert.ErrorContains(t, err, "syslog address is required")
+}
@@ -0,0 +1,173 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type fakeSink struct {
+	mu       sync.Mutex
+	batches  [][]LogEntry
+	fail     bool
+	failures int
+	closed   bool
+}
+
+func (s *fakeSink) Send(_ context.Context, entries []LogEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail {
+		s.failures++
+		return errors.New("sink unavailable")
+	}
+	s.batches = append(s.batches, entries)
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *fakeSink) setFail(fail bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fail = fail
+}
+
+func (s *fakeSink) failed() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.failures
+}
+
+func (s *fakeSink) messages() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var messages []string
+	for _, batch := range s.batches {
+		for _, e := range batch {
+			messages = append(messages, e.Message)
+		}
+	}
+	return messages
+}
+
+func (s *fakeSink) batchSizes() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var sizes []int
+	for _, batch := range s.batches {
+		sizes = append(sizes, len(batch))
+	}
+	return sizes
+}
+
+func TestShipper_Batches(t *testing.T) {
+	b := NewBroadcaster()
+	sink := &fakeSink{}
+	shipper := newShipper(b, configv1.LogSinkConfig_builder{
+		BatchSize:     proto.Int32(3),
+		FlushInterval: durationpb.New(time.Hour),
+		Level:         configv1.GlobalSettings_LOG_LEVEL_WARN.Enum(),
+	}.Build(), sink)
+
+	b.Broadcast(LogEntry{Level: "DEBUG", Message: "skipped"})
+	for i := 0; i < 7; i++ {
+		b.Broadcast(LogEntry{Level: "WARN", Message: fmt.Sprintf("m%d", i)})
+	}
+	b.Broadcast("not an entry")
+
+	// Full batches are sent without waiting for the flush interval.
+	require.Eventually(t, func() bool { return len(sink.messages()) == 6 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []int{3, 3}, sink.batchSizes())
+
+	// The rest is sent on close.
+	require.NoError(t, shipper.Close(context.Background()))
+	assert.Equal(t, []string{"m0", "m1", "m2", "m3", "m4", "m5", "m6"}, sink.messages())
+	assert.True(t, sink.closed)
+}
+
+func TestShipper_Backpressure(t *testing.T) {
+	b := NewBroadcaster()
+	sink := &fakeSink{fail: true}
+	shipper := newShipper(b, configv1.LogSinkConfig_builder{
+		BatchSize:     proto.Int32(2),
+		QueueSize:     proto.Int32(4),
+		FlushInterval: durationpb.New(10 * time.Millisecond),
+	}.Build(), sink)
+	defer func() { _ = shipper.Close(context.Background()) }()
+
+	for i := 0; i < 10; i++ {
+		b.Broadcast(LogEntry{Level: "INFO", Message: fmt.Sprintf("m%d", i)})
+	}
+	// While the sink fails, the queue keeps only the newest entries.
+	require.Eventually(t, func() bool {
+		shipper.mu.Lock()
+		defer shipper.mu.Unlock()
+		return len(shipper.queue) == 4 && shipper.queue[3].Message == "m9"
+	}, 5*time.Second, 10*time.Millisecond)
+	// Wait for a send that started after the last entry was queued.
+	failures := sink.failed()
+	require.Eventually(t, func() bool { return sink.failed() >= failures+2 }, 5*time.Second, 10*time.Millisecond)
+	assert.Empty(t, sink.messages())
+
+	sink.setFail(false)
+	require.Eventually(t, func() bool { return len(sink.messages()) == 4 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"m6", "m7", "m8", "m9"}, sink.messages())
+}
+
+func TestShipper_Flush(t *testing.T) {
+	b := NewBroadcaster()
+	sink := &fakeSink{}
+	shipper := newShipper(b, configv1.LogSinkConfig_builder{
+		BatchSize:     proto.Int32(10),
+		FlushInterval: durationpb.New(time.Hour),
+	}.Build(), sink)
+	defer func() { _ = shipper.Close(context.Background()) }()
+
+	b.Broadcast(LogEntry{Level: "INFO", Message: "m0"})
+	require.Eventually(t, func() bool {
+		shipper.mu.Lock()
+		defer shipper.mu.Unlock()
+		return len(shipper.queue) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// A partial batch is sent without waiting for the flush interval.
+	require.NoError(t, shipper.Flush(context.Background()))
+	assert.Equal(t, []string{"m0"}, sink.messages())
+
+	sink.setFail(true)
+	b.Broadcast(LogEntry{Level: "INFO", Message: "m1"})
+	require.Eventually(t, func() bool {
+		shipper.mu.Lock()
+		defer shipper.mu.Unlock()
+		return len(shipper.queue) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.EqualError(t, shipper.Flush(context.Background()), "log sink "+shipper.Name()+": 1 entries not sent")
+}
+
+func TestNewShipper_InvalidConfig(t *testing.T) {
+	_, err := NewShipper(NewBroadcaster(), configv1.LogSinkConfig_builder{Name: proto.String("empty")}.Build())
+	assert.ErrorContains(t, err, `log sink "empty": log sink has no syslog, loki or otlp configuration`)
+
+	_, err = NewShipper(NewBroadcaster(), configv1.LogSinkConfig_builder{
+		Loki: configv1.LokiSinkConfig_builder{Url: proto.String("loki:3100")}.Build(),
+	}.Build())
+	assert.ErrorContains(t, err, `log sink "loki": invalid loki url`)
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package marketplace
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// gitSource is a registry published as a Git repository. The repository holds
+// the packages of a bundle, and their signatures, in a directory per kind and
+// name:
+//
+//	skills/report-writer/report-writer-1.2.0.tar.gz
+//	skills/report-writer/report-writer-1.2.0.tar.gz.sig
+//	services/weather/weather-1.0.0.tar.gz
+//
+// The repository is shallow cloned once, with the git command, on first use.
+type gitSource struct {
+	url string
+	ref string
+
+	once sync.Once
+	dir  string
+	err  error
+}
+
+func (s *gitSource) checkout(ctx context.Context) (string, error) {
+	s.once.Do(func() {
+		dir, err := os.MkdirTemp("", "mcpany-registry-")
+		if err != nil {
+			s.err = fmt.Errorf("failed to create the checkout directory: %w", err)
+			return
+		}
+		s.dir = dir
+		args := []string{"clone", "--depth", "1", "--quiet"}
+		if s.ref != "" {
+			args = append(args, "--branch", s.ref)
+		}
+		args = append(args, "--", s.url, dir)
+		cmd := exec.CommandContext(ctx, "git", args...)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			s.err = fmt.Errorf("failed to clone registry %s: %w: %s", s.url, err, strings.TrimSpace(stderr.String()))
+		}
+	})
+	return s.dir, s.err
+}
+
+func (s *gitSource) bundleDir(ctx context.Context, kind Kind, name string) (string, error) {
+	if strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return "", fmt.Errorf("invalid bundle name %q", name)
+	}
+	root, err := s.checkout(ctx)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, string(kind)+"s", name), nil
+}
+
+// Versions lists the packages of a bundle in the repository.
+func (s *gitSource) Versions(ctx context.Context, kind Kind, name string) ([]string, error) {
+	dir, err := s.bundleDir(ctx, kind, name)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s/%s", ErrNotFound, kind, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s/%s: %w", kind, name, err)
+	}
+	var versions []string
+	for _, e := range entries {
+		version, ok := strings.CutPrefix(e.Name(), name+"-")
+		if !ok || e.IsDir() {
+			continue
+		}
+		if version, ok = strings.CutSuffix(version, ".tar.gz"); ok {
+			versions = append(versions, version)
+		}
+	}
+	return versions, nil
+}
+
+// Fetch reads a package of a bundle, and its signature if any.
+func (s *gitSource) Fetch(ctx context.Context, kind Kind, name, version string) (*Artifact, error) {
+	dir, err := s.bundleDir(ctx, kind, name)
+	if err != nil {
+		return nil, err
+	}
+	if strings.ContainsAny(version, `/\`) {
+		return nil, fmt.Errorf("invalid version %q", version)
+	}
+	file := filepath.Join(dir, fmt.Sprintf("%s-%s.tar.gz", name, version))
+	pkg, err := os.ReadFile(file) //nolint:gosec // file is under the checkout.
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s/%s %s", ErrNotFound, kind, name, version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s/%s %s: %w", kind, name, version, err)
+	}
+	sig, err := os.ReadFile(file + SignatureFileSuffix) //nolint:gosec // file is under the checkout.
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read the signature of %s/%s %s: %w", kind, name, version, err)
+	}
+	return &Artifact{Package: pkg, Signature: sig}, nil
+}
+
+// Close removes the checkout.
+func (s *gitSource) Close() error {
+	if s.dir == "" {
+		return nil
+	}
+	return os.RemoveAll(s.dir)
+}
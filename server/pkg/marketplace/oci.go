@@ -0,0 +1,268 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package marketplace
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// BundleMediaType is the media type of the layer holding the package of a
+	// bundle in an OCI artifact.
+	BundleMediaType = "application/vnd.mcpany.bundle.v1.tar+gzip"
+
+	// SignatureAnnotation is the manifest annotation holding the base64
+	// encoded signature of the package of an OCI artifact.
+	SignatureAnnotation = "dev.mcpany.bundle.signature"
+
+	// maxArtifactSize bounds the manifests and packages read from a registry.
+	maxArtifactSize = 64 << 20
+)
+
+// ociSource is a registry published as OCI artifacts. The versions of a
+// bundle are the tags of the repository <path>/<kind>s/<name>, e.g.
+// ghcr.io/acme/registry/skills/report-writer:1.2.0. The artifact has one
+// layer of BundleMediaType, and its signature in the SignatureAnnotation of
+// its manifest.
+type ociSource struct {
+	scheme string
+	host   string
+	path   string
+	opts   Options
+
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+func newOCISource(ref, scheme string, opts Options) (*ociSource, error) {
+	host, path, _ := strings.Cut(strings.TrimSuffix(ref, "/"), "/")
+	if host == "" {
+		return nil, fmt.Errorf("invalid OCI registry %q: must be oci://<host>/<path>", ref)
+	}
+	return &ociSource{scheme: scheme, host: host, path: path, opts: opts, tokens: make(map[string]string)}, nil
+}
+
+func (s *ociSource) repository(kind Kind, name string) (string, error) {
+	if strings.ContainsAny(name, `/\:@`) || name == "" {
+		return "", fmt.Errorf("invalid bundle name %q", name)
+	}
+	repo := string(kind) + "s/" + name
+	if s.path != "" {
+		repo = s.path + "/" + repo
+	}
+	return repo, nil
+}
+
+// Versions lists the tags of the repository of a bundle.
+func (s *ociSource) Versions(ctx context.Context, kind Kind, name string) ([]string, error) {
+	repo, err := s.repository(kind, name)
+	if err != nil {
+		return nil, err
+	}
+	body, err := s.get(ctx, repo, "/tags/list", "application/json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s/%s: %w", kind, name, err)
+	}
+	var tags struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.Unmarshal(body, &tags); err != nil {
+		return nil, fmt.Errorf("failed to decode the tags of %s/%s: %w", kind, name, err)
+	}
+	return tags.Tags, nil
+}
+
+// Fetch pulls the manifest of a tag, then the bundle layer it references.
+func (s *ociSource) Fetch(ctx context.Context, kind Kind, name, version string) (*Artifact, error) {
+	repo, err := s.repository(kind, name)
+	if err != nil {
+		return nil, err
+	}
+	body, err := s.get(ctx, repo, "/manifests/"+url.PathEscape(version), ocispec.MediaTypeImageManifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s/%s %s: %w", kind, name, version, err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode the manifest of %s/%s %s: %w", kind, name, version, err)
+	}
+	var layer *ocispec.Descriptor
+	for i := range manifest.Layers {
+		if manifest.Layers[i].MediaType == BundleMediaType {
+			layer = &manifest.Layers[i]
+			break
+		}
+	}
+	if layer == nil {
+		return nil, fmt.Errorf("%s/%s %s has no layer of media type %s", kind, name, version, BundleMediaType)
+	}
+	digest := string(layer.Digest)
+	want, ok := strings.CutPrefix(digest, "sha256:")
+	if !ok {
+		return nil, fmt.Errorf("%s/%s %s: unsupported layer digest %q", kind, name, version, digest)
+	}
+	pkg, err := s.get(ctx, repo, "/blobs/"+digest, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the package of %s/%s %s: %w", kind, name, version, err)
+	}
+	sum := sha256.Sum256(pkg)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return nil, fmt.Errorf("%s/%s %s: layer digest mismatch: got sha256:%s, want %s", kind, name, version, got, digest)
+	}
+	artifact := &Artifact{Package: pkg}
+	if sig := manifest.Annotations[SignatureAnnotation]; sig != "" {
+		artifact.Signature = []byte(sig)
+	}
+	return artifact, nil
+}
+
+// Close does nothing.
+func (s *ociSource) Close() error {
+	return nil
+}
+
+// get sends a GET request to the distribution API of the registry,
+// authenticating with the token challenge of the registry if it asks for one.
+func (s *ociSource) get(ctx context.Context, repo, path, accept string) ([]byte, error) {
+	endpoint := fmt.Sprintf("%s://%s/v2/%s%s", s.scheme, s.host, repo, path)
+	resp, err := s.do(ctx, endpoint, accept, repo)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		_ = resp.Body.Close()
+		if err := s.authenticate(ctx, repo, challenge); err != nil {
+			return nil, err
+		}
+		if resp, err = s.do(ctx, endpoint, accept, repo); err != nil {
+			return nil, err
+		}
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxArtifactSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("registry returned %s", resp.Status)
+	case len(body) > maxArtifactSize:
+		return nil, fmt.Errorf("response is larger than %d bytes", maxArtifactSize)
+	}
+	return body, nil
+}
+
+func (s *ociSource) do(ctx context.Context, endpoint, accept, repo string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	s.mu.Lock()
+	token := s.tokens[repo]
+	s.mu.Unlock()
+	switch {
+	case token != "":
+		req.Header.Set("Authorization", "Bearer "+token)
+	case s.opts.Username != "":
+		req.SetBasicAuth(s.opts.Username, s.opts.Password)
+	}
+	resp, err := s.opts.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach the registry: %w", err)
+	}
+	return resp, nil
+}
+
+// authenticate fetches a pull token of a repository from the realm of a
+// Bearer challenge.
+func (s *ociSource) authenticate(ctx context.Context, repo, challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return fmt.Errorf("registry requires %s authentication; set the registry credentials", scheme)
+	}
+	attrs := parseChallenge(params)
+	realm, err := url.Parse(attrs["realm"])
+	if err != nil || attrs["realm"] == "" {
+		return fmt.Errorf("registry sent an invalid authentication challenge %q", challenge)
+	}
+	query := realm.Query()
+	if service := attrs["service"]; service != "" {
+		query.Set("service", service)
+	}
+	scope := attrs["scope"]
+	if scope == "" {
+		scope = "repository:" + repo + ":pull"
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return err
+	}
+	if s.opts.Username != "" {
+		req.SetBasicAuth(s.opts.Username, s.opts.Password)
+	}
+	resp, err := s.opts.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach the token service: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("token service returned %s", resp.Status)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&token); err != nil {
+		return fmt.Errorf("failed to decode the token: %w", err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	if token.Token == "" {
+		return fmt.Errorf("token service returned no token")
+	}
+	s.mu.Lock()
+	s.tokens[repo] = token.Token
+	s.mu.Unlock()
+	return nil
+}
+
+// parseChallenge parses the comma separated key="value" parameters of a
+// WWW-Authenticate challenge.
+func parseChallenge(params string) map[string]string {
+	attrs := make(map[string]string)
+	for params != "" {
+		var key, value string
+		key, params, _ = strings.Cut(params, "=")
+		key = strings.TrimSpace(key)
+		if strings.HasPrefix(params, `"`) {
+			value, params, _ = strings.Cut(params[1:], `"`)
+			params = strings.TrimPrefix(params, ",")
+		} else {
+			value, params, _ = strings.Cut(params, ",")
+		}
+		attrs[strings.ToLower(key)] = value
+	}
+	return attrs
+}
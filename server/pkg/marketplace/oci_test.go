@@ -0,0 +1,89 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package marketplace
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_OCI(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	pkg := packageSkill(t, "1.2.0")
+	sum := sha256.Sum256(pkg)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	manifest, err := json.Marshal(map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.manifest.v1+json",
+		"artifactType":  "application/vnd.mcpany.skill.v1",
+		"config":        map[string]any{"mediaType": "application/vnd.oci.empty.v1+json", "digest": "sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a", "size": 2},
+		"layers":        []map[string]any{{"mediaType": BundleMediaType, "digest": digest, "size": len(pkg)}},
+		"annotations":   map[string]string{SignatureAnnotation: strings.TrimSpace(string(Sign(priv, pkg)))},
+	})
+	require.NoError(t, err)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			assert.Equal(t, "registry.test", r.URL.Query().Get("service"))
+			assert.Equal(t, "repository:acme/skills/report-writer:pull", r.URL.Query().Get("scope"))
+			_, _ = w.Write([]byte(`{"token": "pull-token"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer pull-token" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="registry.test",scope="repository:acme/skills/report-writer:pull"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/acme/skills/report-writer/tags/list":
+			_, _ = w.Write([]byte(`{"name": "acme/skills/report-writer", "tags": ["1.0.0", "1.2.0", "1.1.0"]}`))
+		case "/v2/acme/skills/report-writer/manifests/1.2.0":
+			assert.Equal(t, "application/vnd.oci.image.manifest.v1+json", r.Header.Get("Accept"))
+			_, _ = w.Write(manifest)
+		case "/v2/acme/skills/report-writer/blobs/" + digest:
+			_, _ = w.Write(pkg)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	source, err := Open("oci+http://"+strings.TrimPrefix(server.URL, "http://")+"/acme", Options{HTTPClient: server.Client()})
+	require.NoError(t, err)
+	client := NewClient(source, &Verifier{Keys: []ed25519.PublicKey{pub}})
+	ctx := context.Background()
+
+	bundle, err := client.Fetch(ctx, KindSkill, "report-writer", "")
+	require.NoError(t, err)
+	assert.True(t, bundle.Signed)
+	assert.Equal(t, "1.2.0", bundle.Manifest.Version)
+	assert.Equal(t, pkg, bundle.Package)
+
+	_, err = client.Fetch(ctx, KindSkill, "report-writer", "1.0.0")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = client.Latest(ctx, KindService, "weather")
+	assert.Error(t, err)
+}
+
+func TestParseChallenge(t *testing.T) {
+	attrs := parseChallenge(`realm="https://auth.example.com/token",service="registry.example.com",scope="repository:a/b:pull,push"`)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "registry.example.com",
+		"scope":   "repository:a/b:pull,push",
+	}, attrs)
+}
@@ -0,0 +1,316 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package marketplace installs skills and upstream service definitions from
+// remote registries, like a package manager for tool configs. A registry is a
+// Git repository or an OCI registry publishing bundles, the packages written
+// by skill.PackageDir, by kind, name and version. Bundles are signed with
+// Ed25519 keys, and verified against the keys trusted by the installer.
+package marketplace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/mcpany/core/server/pkg/skill"
+)
+
+// Kind is the kind of a bundle.
+type Kind string
+
+const (
+	// KindSkill is a skill package, installed through the skill manager.
+	KindSkill Kind = "skill"
+	// KindService is a bundle of upstream service definitions, installed in
+	// the service catalog.
+	KindService Kind = "service"
+)
+
+// ErrNotFound is returned when a registry does not publish a bundle or a
+// version of it.
+var ErrNotFound = errors.New("bundle not found in the registry")
+
+// ParseKind parses the kind of a bundle.
+//
+// Parameters:
+//   - s (string): "skill" or "service".
+//
+// Returns:
+//   - Kind: The kind.
+//   - error: An error if the kind is unknown.
+func ParseKind(s string) (Kind, error) {
+	switch Kind(s) {
+	case KindSkill, KindService:
+		return Kind(s), nil
+	default:
+		return "", fmt.Errorf("unknown bundle kind %q: must be skill or service", s)
+	}
+}
+
+// ParseRef parses a bundle reference of the form "<kind>/<name>[@<version>]".
+//
+// Parameters:
+//   - ref (string): The reference, e.g. "skill/report-writer@1.2.0".
+//
+// Returns:
+//   - Kind: The kind of the bundle.
+//   - string: The name of the bundle.
+//   - string: The version of the bundle, or empty for the latest.
+//   - error: An error if the reference is malformed.
+func ParseRef(ref string) (Kind, string, string, error) {
+	kindName, version, _ := strings.Cut(ref, "@")
+	kind, name, ok := strings.Cut(kindName, "/")
+	if !ok || name == "" {
+		return "", "", "", fmt.Errorf("invalid bundle reference %q: must be <kind>/<name>[@<version>]", ref)
+	}
+	k, err := ParseKind(kind)
+	if err != nil {
+		return "", "", "", err
+	}
+	return k, name, version, nil
+}
+
+// Artifact is a bundle as published by a registry.
+type Artifact struct {
+	// Package is the gzipped tar package of the bundle.
+	Package []byte
+	// Signature is the base64 encoded Ed25519 signature of Package, or nil if
+	// the bundle is unsigned.
+	Signature []byte
+}
+
+// Source is a registry publishing bundles.
+type Source interface {
+	// Versions lists the published versions of a bundle.
+	//
+	// Returns ErrNotFound if the bundle is not published.
+	Versions(ctx context.Context, kind Kind, name string) ([]string, error)
+	// Fetch fetches a version of a bundle.
+	//
+	// Returns ErrNotFound if the version is not published.
+	Fetch(ctx context.Context, kind Kind, name, version string) (*Artifact, error)
+	// Close releases the resources of the source, such as a Git checkout.
+	Close() error
+}
+
+// Options configures the access to a registry.
+type Options struct {
+	// HTTPClient is the client of OCI registries. Defaults to
+	// http.DefaultClient.
+	HTTPClient *http.Client
+	// Username and Password authenticate to OCI registries, or are empty for
+	// anonymous access.
+	Username string
+	Password string
+}
+
+// Open opens a registry.
+//
+// Parameters:
+//   - ref (string): The registry. "oci://<host>/<path>" is an OCI registry,
+//     "oci+http://" one served over plain HTTP; anything else, such as
+//     "https://github.com/acme/skills.git#main" or a local path, is a Git
+//     repository, at the optional branch or tag after "#".
+//   - opts (Options): The access to the registry.
+//
+// Returns:
+//   - Source: The registry.
+//   - error: An error if the reference is malformed.
+func Open(ref string, opts Options) (Source, error) {
+	if ref == "" {
+		return nil, errors.New("no registry given")
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	switch {
+	case strings.HasPrefix(ref, "oci://"):
+		return newOCISource(strings.TrimPrefix(ref, "oci://"), "https", opts)
+	case strings.HasPrefix(ref, "oci+http://"):
+		return newOCISource(strings.TrimPrefix(ref, "oci+http://"), "http", opts)
+	default:
+		url, branch, _ := strings.Cut(strings.TrimPrefix(ref, "git+"), "#")
+		return &gitSource{url: url, ref: branch}, nil
+	}
+}
+
+// Bundle is a verified bundle.
+type Bundle struct {
+	Kind     Kind
+	Manifest *skill.Manifest
+	// Package is the gzipped tar package of the bundle.
+	Package []byte
+	// Signed is true if the signature of the bundle was verified.
+	Signed bool
+}
+
+// Client fetches verified bundles from a registry.
+type Client struct {
+	source   Source
+	verifier *Verifier
+}
+
+// NewClient creates a client of a registry.
+//
+// Parameters:
+//   - source (Source): The registry.
+//   - verifier (*Verifier): The keys the bundles must be signed with.
+//
+// Returns:
+//   - *Client: The client.
+func NewClient(source Source, verifier *Verifier) *Client {
+	return &Client{source: source, verifier: verifier}
+}
+
+// Versions lists the valid published versions of a bundle, oldest first.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - kind (Kind): The kind of the bundle.
+//   - name (string): The name of the bundle.
+//
+// Returns:
+//   - []string: The versions, sorted by semantic version precedence.
+//   - error: ErrNotFound if the bundle is not published.
+func (c *Client) Versions(ctx context.Context, kind Kind, name string) ([]string, error) {
+	versions, err := c.source.Versions(ctx, kind, name)
+	if err != nil {
+		return nil, err
+	}
+	return SortVersions(versions), nil
+}
+
+// Latest returns the latest published version of a bundle, by semantic
+// version precedence. Pre-releases are only considered when no release is
+// published.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - kind (Kind): The kind of the bundle.
+//   - name (string): The name of the bundle.
+//
+// Returns:
+//   - string: The latest version.
+//   - error: ErrNotFound if no valid version is published.
+func (c *Client) Latest(ctx context.Context, kind Kind, name string) (string, error) {
+	sorted, err := c.Versions(ctx, kind, name)
+	if err != nil {
+		return "", err
+	}
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if v, _ := semver.StrictNewVersion(sorted[i]); v.Prerelease() == "" {
+			return sorted[i], nil
+		}
+	}
+	if len(sorted) == 0 {
+		return "", fmt.Errorf("%w: %s/%s has no valid version", ErrNotFound, kind, name)
+	}
+	return sorted[len(sorted)-1], nil
+}
+
+// Fetch fetches a bundle and verifies its signature and checksums.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - kind (Kind): The kind of the bundle.
+//   - name (string): The name of the bundle.
+//   - version (string): The version, or empty for the latest.
+//
+// Returns:
+//   - *Bundle: The verified bundle.
+//   - error: ErrNotFound if the bundle is not published, or an error if its
+//     signature or content is invalid.
+func (c *Client) Fetch(ctx context.Context, kind Kind, name, version string) (*Bundle, error) {
+	if version == "" {
+		latest, err := c.Latest(ctx, kind, name)
+		if err != nil {
+			return nil, err
+		}
+		version = latest
+	}
+	artifact, err := c.source.Fetch(ctx, kind, name, version)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := c.verifier.Verify(artifact.Package, artifact.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("%s/%s %s: %w", kind, name, version, err)
+	}
+	manifest, err := skill.ReadManifest(artifact.Package)
+	if err != nil {
+		return nil, fmt.Errorf("%s/%s %s: %w", kind, name, version, err)
+	}
+	// The signature covers the package, not where the registry publishes it.
+	if manifest.Name != name || manifest.Version != version {
+		return nil, fmt.Errorf("%s/%s %s: the registry published %s %s instead", kind, name, version, manifest.Name, manifest.Version)
+	}
+	if kind == KindSkill && !hasFile(manifest, skill.SkillFileName) {
+		return nil, fmt.Errorf("%s/%s %s: the package has no %s", kind, name, version, skill.SkillFileName)
+	}
+	return &Bundle{Kind: kind, Manifest: manifest, Package: artifact.Package, Signed: signed}, nil
+}
+
+// Close closes the registry of the client.
+//
+// Returns:
+//   - error: An error if the registry cannot be closed.
+func (c *Client) Close() error {
+	return c.source.Close()
+}
+
+// IsNewer reports whether a version is newer than an installed one. Any valid
+// version is newer than an installed bundle without a valid version.
+//
+// Parameters:
+//   - version (string): The candidate version.
+//   - installed (string): The installed version.
+//
+// Returns:
+//   - bool: True if version should replace installed.
+func IsNewer(version, installed string) bool {
+	candidate, err := semver.StrictNewVersion(version)
+	if err != nil {
+		return false
+	}
+	current, err := semver.StrictNewVersion(installed)
+	if err != nil {
+		return true
+	}
+	return candidate.GreaterThan(current)
+}
+
+// SortVersions returns the valid semantic versions of a list, oldest first.
+//
+// Parameters:
+//   - versions ([]string): The versions.
+//
+// Returns:
+//   - []string: The valid versions, sorted by precedence.
+func SortVersions(versions []string) []string {
+	parsed := make([]*semver.Version, 0, len(versions))
+	for _, v := range versions {
+		if sv, err := semver.StrictNewVersion(v); err == nil {
+			parsed = append(parsed, sv)
+		}
+	}
+	sort.Sort(semver.Collection(parsed))
+	sorted := make([]string, len(parsed))
+	for i, v := range parsed {
+		sorted[i] = v.Original()
+	}
+	return sorted
+}
+
+func hasFile(manifest *skill.Manifest, path string) bool {
+	for _, f := range manifest.Files {
+		if f.Path == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package marketplace
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/mcpany/core/server/pkg/skill"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// packageSkill writes the package of a report-writer skill version.
+func packageSkill(t *testing.T, version string) []byte {
+	t.Helper()
+	dir := t.TempDir()
+	content := "---\nname: report-writer\ndescription: Writes reports\nversion: " + version + "\n---\n\nWrite the report."
+	require.NoError(t, os.WriteFile(filepath.Join(dir, skill.SkillFileName), []byte(content), 0644))
+	var buf bytes.Buffer
+	_, err := skill.Package(dir, &buf)
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+// gitRegistry creates a Git repository publishing the given files.
+func gitRegistry(t *testing.T, files map[string][]byte) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, content, 0644))
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet", "--initial-branch", "main"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "publish"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	return dir
+}
+
+func TestParseRef(t *testing.T) {
+	kind, name, version, err := ParseRef("skill/report-writer@1.2.0")
+	require.NoError(t, err)
+	assert.Equal(t, KindSkill, kind)
+	assert.Equal(t, "report-writer", name)
+	assert.Equal(t, "1.2.0", version)
+
+	kind, name, version, err = ParseRef("service/weather")
+	require.NoError(t, err)
+	assert.Equal(t, KindService, kind)
+	assert.Equal(t, "weather", name)
+	assert.Empty(t, version)
+
+	_, _, _, err = ParseRef("report-writer")
+	assert.ErrorContains(t, err, "invalid bundle reference")
+	_, _, _, err = ParseRef("plugin/report-writer")
+	assert.ErrorContains(t, err, "unknown bundle kind")
+}
+
+func TestVersions(t *testing.T) {
+	assert.Equal(t, []string{"0.9.0", "1.0.0-rc.1", "1.0.0", "1.10.0"}, SortVersions([]string{"1.10.0", "latest", "1.0.0", "0.9.0", "1.0.0-rc.1"}))
+	assert.True(t, IsNewer("1.10.0", "1.9.0"))
+	assert.False(t, IsNewer("1.0.0", "1.0.0"))
+	assert.True(t, IsNewer("0.1.0", ""), "any version replaces an unversioned bundle")
+	assert.False(t, IsNewer("", "1.0.0"))
+}
+
+func TestVerifier(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	pkg := []byte("package")
+	sig := Sign(priv, pkg)
+
+	signed, err := (&Verifier{Keys: []ed25519.PublicKey{otherPub, pub}}).Verify(pkg, sig)
+	require.NoError(t, err)
+	assert.True(t, signed)
+
+	_, err = (&Verifier{Keys: []ed25519.PublicKey{pub}}).Verify([]byte("tampered"), sig)
+	assert.ErrorContains(t, err, "does not match any trusted key")
+	_, err = (&Verifier{Keys: []ed25519.PublicKey{otherPub}}).Verify(pkg, sig)
+	assert.ErrorContains(t, err, "does not match any trusted key")
+	_, err = (&Verifier{Keys: []ed25519.PublicKey{pub}}).Verify(pkg, []byte("bm90IGEgc2lnbmF0dXJl"))
+	assert.ErrorContains(t, err, "malformed signature")
+
+	_, err = (&Verifier{Keys: []ed25519.PublicKey{pub}}).Verify(pkg, nil)
+	assert.ErrorIs(t, err, ErrUnsigned)
+	signed, err = (&Verifier{AllowUnsigned: true}).Verify(pkg, nil)
+	require.NoError(t, err)
+	assert.False(t, signed)
+	_, err = (&Verifier{Keys: []ed25519.PublicKey{pub}, AllowUnsigned: true}).Verify([]byte("tampered"), sig)
+	assert.Error(t, err, "signed bundles are verified even if unsigned ones are allowed")
+}
+
+func TestClient_Git(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	v1 := packageSkill(t, "1.0.0")
+	v2 := packageSkill(t, "1.1.0")
+	repo := gitRegistry(t, map[string][]byte{
+		"skills/report-writer/report-writer-1.0.0.tar.gz":      v1,
+		"skills/report-writer/report-writer-1.0.0.tar.gz.sig":  Sign(priv, v1),
+		"skills/report-writer/report-writer-1.1.0.tar.gz":      v2,
+		"skills/report-writer/report-writer-1.1.0.tar.gz.sig":  Sign(priv, v1), // Signs another package.
+		"skills/report-writer/report-writer-2.0.0-rc.1.tar.gz": packageSkill(t, "2.0.0-rc.1"),
+		// Published under the wrong version.
+		"skills/report-writer/report-writer-0.1.0.tar.gz":     v1,
+		"skills/report-writer/report-writer-0.1.0.tar.gz.sig": Sign(priv, v1),
+	})
+	source, err := Open(repo+"#main", Options{})
+	require.NoError(t, err)
+	client := NewClient(source, &Verifier{Keys: []ed25519.PublicKey{pub}})
+	defer func() { require.NoError(t, client.Close()) }()
+	ctx := context.Background()
+
+	latest, err := client.Latest(ctx, KindSkill, "report-writer")
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", latest, "pre-releases are not the latest")
+
+	bundle, err := client.Fetch(ctx, KindSkill, "report-writer", "1.0.0")
+	require.NoError(t, err)
+	assert.True(t, bundle.Signed)
+	assert.Equal(t, "1.0.0", bundle.Manifest.Version)
+	assert.Equal(t, v1, bundle.Package)
+
+	_, err = client.Fetch(ctx, KindSkill, "report-writer", "")
+	assert.ErrorContains(t, err, "does not match any trusted key")
+	_, err = client.Fetch(ctx, KindSkill, "report-writer", "2.0.0-rc.1")
+	assert.ErrorIs(t, err, ErrUnsigned)
+	_, err = client.Fetch(ctx, KindSkill, "report-writer", "0.1.0")
+	assert.ErrorContains(t, err, "the registry published report-writer 1.0.0 instead")
+	_, err = client.Fetch(ctx, KindSkill, "report-writer", "3.0.0")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = client.Latest(ctx, KindService, "report-writer")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
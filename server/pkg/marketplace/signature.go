@@ -0,0 +1,142 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package marketplace
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// SignatureFileSuffix is appended to the file name of a package to name its
+// signature.
+const SignatureFileSuffix = ".sig"
+
+// ErrUnsigned is returned when a bundle has no signature and unsigned bundles
+// are not allowed.
+var ErrUnsigned = errors.New("bundle is not signed")
+
+// Verifier verifies the signatures of bundles.
+type Verifier struct {
+	// Keys are the trusted public keys. A bundle must be signed by one of them.
+	Keys []ed25519.PublicKey
+	// AllowUnsigned accepts the bundles without a signature. Signed bundles
+	// are still verified.
+	AllowUnsigned bool
+}
+
+// Verify verifies the signature of a package.
+//
+// Parameters:
+//   - pkg ([]byte): The package.
+//   - signature ([]byte): The base64 encoded signature, or nil.
+//
+// Returns:
+//   - bool: True if the package is signed by a trusted key.
+//   - error: ErrUnsigned if the package is unsigned and that is not allowed,
+//     or an error if the signature is invalid.
+func (v *Verifier) Verify(pkg, signature []byte) (bool, error) {
+	if len(bytes.TrimSpace(signature)) == 0 {
+		if v.AllowUnsigned {
+			return false, nil
+		}
+		return false, ErrUnsigned
+	}
+	raw, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signature)))
+	if err != nil || len(raw) != ed25519.SignatureSize {
+		return false, errors.New("malformed signature")
+	}
+	if len(v.Keys) == 0 {
+		if v.AllowUnsigned {
+			return false, nil
+		}
+		return false, errors.New("bundle is signed, but no key is trusted")
+	}
+	for _, key := range v.Keys {
+		if ed25519.Verify(key, pkg, raw) {
+			return true, nil
+		}
+	}
+	return false, errors.New("signature does not match any trusted key")
+}
+
+// Sign signs a package.
+//
+// Parameters:
+//   - key (ed25519.PrivateKey): The signing key.
+//   - pkg ([]byte): The package.
+//
+// Returns:
+//   - []byte: The base64 encoded signature, followed by a newline, as written
+//     to the signature file.
+func Sign(key ed25519.PrivateKey, pkg []byte) []byte {
+	return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(key, pkg)) + "\n")
+}
+
+// LoadPublicKey reads a PEM encoded Ed25519 public key, as written by
+// "openssl pkey -pubout".
+//
+// Parameters:
+//   - path (string): The key file.
+//
+// Returns:
+//   - ed25519.PublicKey: The key.
+//   - error: An error if the file is not an Ed25519 public key.
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	der, err := readPEM(path, "PUBLIC KEY")
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	ed, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an Ed25519 public key", path)
+	}
+	return ed, nil
+}
+
+// LoadPrivateKey reads a PEM encoded Ed25519 private key, as written by
+// "openssl genpkey -algorithm ed25519".
+//
+// Parameters:
+//   - path (string): The key file.
+//
+// Returns:
+//   - ed25519.PrivateKey: The key.
+//   - error: An error if the file is not an Ed25519 private key.
+func LoadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	der, err := readPEM(path, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	ed, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an Ed25519 private key", path)
+	}
+	return ed, nil
+}
+
+func readPEM(path, blockType string) ([]byte, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // The key file is chosen by the user.
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != blockType {
+		return nil, fmt.Errorf("%s has no PEM %s block", path, blockType)
+	}
+	return block.Bytes, nil
+}
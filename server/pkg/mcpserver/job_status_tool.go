@@ -0,0 +1,166 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	v1 "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// JobGetter retrieves the jobs of the asynchronous tools.
+type JobGetter interface {
+	// GetJob returns a job visible to the caller.
+	//
+	// Summary: Retrieves a job.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context of the caller.
+	//   - id (string): The ID of the job.
+	//
+	// Returns:
+	//   - *tool.Job: The job.
+	//   - error: tool.ErrJobNotFound if there is no such job.
+	GetJob(ctx context.Context, id string) (*tool.Job, error)
+}
+
+// jobStatusToolSetter is a tool manager that adds the job status tool when it
+// is needed.
+type jobStatusToolSetter interface {
+	JobGetter
+	SetJobStatusTool(t tool.Tool)
+}
+
+// JobStatusTool implements the Tool interface for polling jobs.
+//
+// It provides a built-in tool ("mcp:get_job_status") that reports the status
+// of the job returned by a call of an asynchronous tool, and its result once
+// it is done.
+type JobStatusTool struct {
+	tool    *v1.Tool
+	mcpTool *mcp.Tool
+	jobs    JobGetter
+}
+
+// NewJobStatusTool creates a new instance of the JobStatusTool.
+//
+// Parameters:
+//   - jobs (JobGetter): Where the jobs are looked up, usually the tool manager.
+//
+// Returns:
+//   - *JobStatusTool: A new instance of JobStatusTool.
+//
+// Side Effects:
+//   - None.
+func NewJobStatusTool(jobs JobGetter) *JobStatusTool {
+	inputSchema := &structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			"type": structpb.NewStringValue("object"),
+			"properties": structpb.NewStructValue(&structpb.Struct{
+				Fields: map[string]*structpb.Value{
+					"job_id": structpb.NewStructValue(&structpb.Struct{
+						Fields: map[string]*structpb.Value{
+							"type":        structpb.NewStringValue("string"),
+							"description": structpb.NewStringValue("The ID of the job, returned by the call of the asynchronous tool."),
+						},
+					}),
+				},
+			}),
+			"required": structpb.NewListValue(&structpb.ListValue{
+				Values: []*structpb.Value{structpb.NewStringValue("job_id")},
+			}),
+		},
+	}
+	t := v1.Tool_builder{
+		Name:        proto.String("mcp:get_job_status"),
+		DisplayName: proto.String("Get Job Status"),
+		Description: proto.String("Gets the status of a job started by an asynchronous tool, and its result once it is done."),
+		InputSchema: inputSchema,
+		ServiceId:   proto.String("builtin"),
+		Annotations: v1.ToolAnnotations_builder{ReadOnlyHint: proto.Bool(true)}.Build(),
+	}.Build()
+
+	mcpTool, _ := tool.ConvertProtoToMCPTool(t)
+	return &JobStatusTool{
+		tool:    t,
+		mcpTool: mcpTool,
+		jobs:    jobs,
+	}
+}
+
+// Tool returns the protobuf definition of the tool.
+//
+// Returns:
+//   - *v1.Tool: The protobuf tool definition.
+//
+// Side Effects:
+//   - None.
+func (t *JobStatusTool) Tool() *v1.Tool {
+	return t.tool
+}
+
+// MCPTool returns the MCP-compliant tool definition.
+//
+// Returns:
+//   - *mcp.Tool: The MCP tool definition.
+//
+// Side Effects:
+//   - None.
+func (t *JobStatusTool) MCPTool() *mcp.Tool {
+	return t.mcpTool
+}
+
+// Execute executes the "mcp:get_job_status" tool.
+//
+// Parameters:
+//   - ctx (context.Context): The request context, carrying the caller.
+//   - req (*tool.ExecutionRequest): The execution request, with the job_id argument.
+//
+// Returns:
+//   - any: The job, with its result or error once it is done.
+//   - error: An error if the job_id is missing, or the job is not found.
+//
+// Side Effects:
+//   - None.
+func (t *JobStatusTool) Execute(ctx context.Context, req *tool.ExecutionRequest) (any, error) {
+	var args struct {
+		JobID string `json:"job_id"`
+	}
+	if req != nil && len(req.ToolInputs) > 0 {
+		if err := json.Unmarshal(req.ToolInputs, &args); err != nil {
+			return nil, fmt.Errorf("%w: %v", tool.ErrInvalidArguments, err)
+		}
+	} else if req != nil {
+		args.JobID, _ = req.Arguments["job_id"].(string)
+	}
+	if args.JobID == "" {
+		return nil, fmt.Errorf("%w: job_id is required", tool.ErrInvalidArguments)
+	}
+	job, err := t.jobs.GetJob(ctx, args.JobID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", err, args.JobID)
+	}
+	return job, nil
+}
+
+// GetCacheConfig returns the caching configuration for this tool.
+//
+// Returns:
+//   - *configv1.CacheConfig: Always nil (caching disabled).
+//
+// Side Effects:
+//   - None.
+func (t *JobStatusTool) GetCacheConfig() *configv1.CacheConfig {
+	return nil
+}
+
+// Verify that JobStatusTool implements tool.Tool.
+var _ tool.Tool = (*JobStatusTool)(nil)
@@ -0,0 +1,63 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package mcpserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeJobs holds a single job.
+type fakeJobs struct {
+	job *tool.Job
+}
+
+func (f *fakeJobs) GetJob(_ context.Context, id string) (*tool.Job, error) {
+	if f.job == nil || f.job.ID != id {
+		return nil, tool.ErrJobNotFound
+	}
+	return f.job, nil
+}
+
+func TestJobStatusTool_Execute(t *testing.T) {
+	job := &tool.Job{ID: "job-1", ToolName: "reports.build_report", Status: tool.JobRunning}
+	statusTool := NewJobStatusTool(&fakeJobs{job: job})
+
+	t.Run("tool inputs", func(t *testing.T) {
+		result, err := statusTool.Execute(context.Background(), &tool.ExecutionRequest{ToolInputs: []byte(`{"job_id":"job-1"}`)})
+		require.NoError(t, err)
+		assert.Equal(t, job, result)
+	})
+
+	t.Run("arguments", func(t *testing.T) {
+		result, err := statusTool.Execute(context.Background(), &tool.ExecutionRequest{Arguments: map[string]any{"job_id": "job-1"}})
+		require.NoError(t, err)
+		assert.Equal(t, job, result)
+	})
+
+	t.Run("missing job id", func(t *testing.T) {
+		_, err := statusTool.Execute(context.Background(), &tool.ExecutionRequest{ToolInputs: []byte(`{}`)})
+		assert.ErrorIs(t, err, tool.ErrInvalidArguments)
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		_, err := statusTool.Execute(context.Background(), &tool.ExecutionRequest{ToolInputs: []byte(`{"job_id":"job-2"}`)})
+		assert.ErrorIs(t, err, tool.ErrJobNotFound)
+		assert.EqualError(t, err, `job not found: "job-2"`)
+	})
+}
+
+func TestJobStatusTool_Metadata(t *testing.T) {
+	statusTool := NewJobStatusTool(&fakeJobs{})
+
+	assert.Equal(t, "mcp:get_job_status", statusTool.Tool().GetName())
+	assert.Equal(t, "builtin", statusTool.Tool().GetServiceId())
+	assert.True(t, statusTool.Tool().GetAnnotations().GetReadOnlyHint())
+	assert.NotNil(t, statusTool.MCPTool())
+	assert.Nil(t, statusTool.GetCacheConfig())
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package mcpserver_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	bus_pb "github.com/mcpany/core/proto/bus"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/bus"
+	"github.com/mcpany/core/server/pkg/mcpserver"
+	"github.com/mcpany/core/server/pkg/pool"
+	"github.com/mcpany/core/server/pkg/prompt"
+	"github.com/mcpany/core/server/pkg/resource"
+	"github.com/mcpany/core/server/pkg/serviceregistry"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/mcpany/core/server/pkg/upstream/factory"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestLazyInitialization(t *testing.T) {
+	t.Setenv("MCPANY_ALLOW_LOOPBACK_RESOURCES", "true")
+	t.Setenv("MCPANY_DANGEROUS_ALLOW_LOCAL_IPS", "true")
+	var hits atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer upstream.Close()
+
+	ctx := context.Background()
+	messageBus := bus_pb.MessageBus_builder{}.Build()
+	messageBus.SetInMemory(bus_pb.InMemoryBus_builder{}.Build())
+	busProvider, err := bus.NewProvider(messageBus)
+	require.NoError(t, err)
+	toolManager := tool.NewManager(busProvider)
+	promptManager := prompt.NewManager()
+	resourceManager := resource.NewManager()
+	authManager := auth.NewManager()
+	serviceRegistry := serviceregistry.New(factory.NewUpstreamServiceFactory(pool.NewManager(), nil), toolManager, promptManager, resourceManager, authManager)
+	server, err := mcpserver.NewServer(ctx, toolManager, promptManager, resourceManager, authManager, serviceRegistry, nil, busProvider, false)
+	require.NoError(t, err)
+
+	serviceID, _, _, err := serviceRegistry.RegisterService(ctx, configv1.UpstreamServiceConfig_builder{
+		Name:           proto.String("lazy-http"),
+		Initialization: configv1.UpstreamServiceConfig_LAZY.Enum(),
+		HttpService: configv1.HttpUpstreamService_builder{
+			Address: proto.String(upstream.URL),
+			Calls: map[string]*configv1.HttpCallDefinition{
+				"status": configv1.HttpCallDefinition_builder{
+					EndpointPath: proto.String("/status"),
+					Method:       configv1.HttpCallDefinition_HTTP_METHOD_GET.Enum(),
+				}.Build(),
+			},
+			Tools: []*configv1.ToolDefinition{
+				configv1.ToolDefinition_builder{Name: proto.String("status"), CallId: proto.String("status")}.Build(),
+			},
+		}.Build(),
+	}.Build())
+	require.NoError(t, err)
+	assert.Equal(t, 0, toolManager.GetToolCountForService(serviceID), "no tools before the first use")
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Server().Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer func() { _ = serverSession.Close() }()
+	clientSession, err := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil).Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer func() { _ = clientSession.Close() }()
+
+	res, err := clientSession.CallTool(ctx, &mcp.CallToolParams{Name: serviceID + ".status"})
+	require.NoError(t, err)
+	assert.False(t, res.IsError, "the first call initializes the service")
+	assert.Equal(t, int32(1), hits.Load())
+
+	tools, err := clientSession.ListTools(ctx, nil)
+	require.NoError(t, err)
+	var names []string
+	for _, tl := range tools.Tools {
+		names = append(names, tl.Name)
+	}
+	assert.Contains(t, names, serviceID+".status")
+}
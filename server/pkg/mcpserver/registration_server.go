@@ -9,11 +9,11 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/google/uuid"
 	v1 "github.com/mcpany/core/proto/api/v1"
 	"github.com/mcpany/core/server/pkg/auth"
 	"github.com/mcpany/core/server/pkg/bus"
 	"github.com/mcpany/core/server/pkg/config"
+	"github.com/mcpany/core/server/pkg/idgen"
 	"github.com/mcpany/core/server/pkg/logging"
 	"github.com/mcpany/core/server/pkg/pool"
 	"github.com/mcpany/core/server/pkg/upstream/factory"
@@ -161,7 +161,7 @@ func (s *RegistrationServer) RegisterService(ctx context.Context, req *v1.Regist
 		return nil, status.Errorf(codes.InvalidArgument, "invalid config: %v", err)
 	}
 
-	correlationID := uuid.New().String()
+	correlationID := idgen.New()
 	resultChan := make(chan *bus.ServiceRegistrationResult, 1)
 
 	resultBus, _ := bus.GetBus[*bus.ServiceRegistrationResult](s.bus, "service_registration_results")
@@ -327,7 +327,7 @@ func (s *RegistrationServer) GetService(ctx context.Context, req *v1.GetServiceR
 		return nil, status.Errorf(codes.InvalidArgument, "service_name is required")
 	}
 
-	correlationID := uuid.New().String()
+	correlationID := idgen.New()
 	resultChan := make(chan *bus.ServiceGetResult, 1)
 
 	resultBus, _ := bus.GetBus[*bus.ServiceGetResult](s.bus, "service_get_results")
@@ -379,7 +379,7 @@ func (s *RegistrationServer) mustEmbedUnimplementedRegistrationServiceServer() {
 //   - codes.DeadlineExceeded: If the request times out.
 //   - codes.Internal: If an internal error occurs.
 func (s *RegistrationServer) ListServices(ctx context.Context, _ *v1.ListServicesRequest) (*v1.ListServicesResponse, error) {
-	correlationID := uuid.New().String()
+	correlationID := idgen.New()
 	resultChan := make(chan *bus.ServiceListResult, 1)
 
 	resultBus, _ := bus.GetBus[*bus.ServiceListResult](s.bus, "service_list_results")
@@ -5,6 +5,7 @@ package mcpserver
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"mime"
 	"os"
@@ -13,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/mcpany/core/server/pkg/auth"
 	"github.com/mcpany/core/server/pkg/logging"
 	"github.com/mcpany/core/server/pkg/resource"
 	"github.com/mcpany/core/server/pkg/skill"
@@ -138,6 +140,21 @@ func (r *SkillResource) Resource() *mcp.Resource {
 	}
 }
 
+// VisibleToProfile reports whether the skill is served to the sessions of a
+// profile.
+//
+// Parameters:
+//   - profileID (string): The profile of the session.
+//
+// Returns:
+//   - bool: True if the skill lists the profile.
+//
+// Side Effects:
+//   - None.
+func (r *SkillResource) VisibleToProfile(profileID string) bool {
+	return r.skill.VisibleTo(profileID)
+}
+
 // resolvePath determines the absolute path to the resource file.
 func (r *SkillResource) resolvePath() (string, error) {
 	if r.assetPath == "" {
@@ -302,10 +319,140 @@ func (r *SkillResource) Subscribe(_ context.Context) error {
 	return nil
 }
 
+// SkillIndexURI is the URI of the resource listing the skills.
+const SkillIndexURI = "skills://index"
+
+// skillIndexEntry is a skill in the skill index.
+type skillIndexEntry struct {
+	Name        string `json:"name"`
+	Version     string `json:"version,omitempty"`
+	Description string `json:"description"`
+	URI         string `json:"uri"`
+}
+
+// SkillIndexResource lists, as JSON, the skills served to the session reading
+// it.
+type SkillIndexResource struct {
+	manager *skill.Manager
+}
+
+// Ensure SkillIndexResource implements resource.Resource.
+var _ resource.Resource = &SkillIndexResource{}
+
+// NewSkillIndexResource creates the resource listing the skills of a manager.
+//
+// Parameters:
+//   - sm (*skill.Manager): The skill manager to list the skills of.
+//
+// Returns:
+//   - *SkillIndexResource: The index resource.
+//
+// Side Effects:
+//   - None.
+func NewSkillIndexResource(sm *skill.Manager) *SkillIndexResource {
+	return &SkillIndexResource{manager: sm}
+}
+
+// Resource returns the underlying MCP resource definition.
+//
+// Returns:
+//   - *mcp.Resource: The MCP resource definition.
+//
+// Side Effects:
+//   - None.
+func (r *SkillIndexResource) Resource() *mcp.Resource {
+	return &mcp.Resource{
+		Name:        "Skill Index",
+		URI:         SkillIndexURI,
+		MIMEType:    "application/json",
+		Description: "The name, version and description of the available skills.",
+	}
+}
+
+// Service returns the service identifier associated with the resource.
+//
+// Returns:
+//   - string: The service identifier ("skills").
+//
+// Side Effects:
+//   - None.
+func (r *SkillIndexResource) Service() string {
+	return "skills"
+}
+
+// VisibleToProfile reports whether the index is served to the sessions of a
+// profile. It always is; its content depends on the profile.
+//
+// Parameters:
+//   - _ (string): Unused.
+//
+// Returns:
+//   - bool: Always true.
+//
+// Side Effects:
+//   - None.
+func (r *SkillIndexResource) VisibleToProfile(_ string) bool {
+	return true
+}
+
+// Read returns the skills served to the profile of the session.
+//
+// Parameters:
+//   - ctx (context.Context): The context, carrying the profile of the session.
+//
+// Returns:
+//   - *mcp.ReadResourceResult: The JSON list of the skills.
+//   - error: An error if the skills cannot be listed.
+//
+// Side Effects:
+//   - Reads the skill directory, unless the listing is cached.
+func (r *SkillIndexResource) Read(ctx context.Context) (*mcp.ReadResourceResult, error) {
+	skills, err := r.manager.ListSkills()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list skills: %w", err)
+	}
+	profileID, _ := auth.ProfileIDFromContext(ctx)
+	entries := make([]skillIndexEntry, 0, len(skills))
+	for _, s := range skills {
+		if !s.VisibleTo(profileID) {
+			continue
+		}
+		entries = append(entries, skillIndexEntry{
+			Name:        s.Name,
+			Version:     s.Version,
+			Description: s.Description,
+			URI:         NewSkillResource(s).URI(),
+		})
+	}
+	content, err := json.Marshal(entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the skill index: %w", err)
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{URI: SkillIndexURI, MIMEType: "application/json", Text: string(content)}},
+	}, nil
+}
+
+// Subscribe subscribes to changes on the resource. It is a no-op; the clients
+// are notified of the changes to the resource list instead.
+//
+// Parameters:
+//   - _ (context.Context): Unused.
+//
+// Returns:
+//   - error: Always returns nil.
+//
+// Side Effects:
+//   - None.
+func (r *SkillIndexResource) Subscribe(_ context.Context) error {
+	return nil
+}
+
 // RegisterSkillResources registers all skills from the manager into the resource manager.
 //
 // It iterates through all available skills and registers their documentation (SKILL.md)
-// and associated assets as resources in the provided Resource Manager.
+// and associated assets as resources in the provided Resource Manager, along
+// with the skill index.
 //
 // Parameters:
 //   - rm (resource.ManagerInterface): The resource manager to register resources with.
@@ -331,5 +478,23 @@ func RegisterSkillResources(rm resource.ManagerInterface, sm *skill.Manager) err
 			rm.AddResource(NewSkillAssetResource(s, asset))
 		}
 	}
+	rm.AddResource(NewSkillIndexResource(sm))
 	return nil
 }
+
+// SyncSkillResources replaces the skill resources of the resource manager with
+// the current skills, after they were created, updated, installed or removed.
+//
+// Parameters:
+//   - rm (resource.ManagerInterface): The resource manager.
+//   - sm (*skill.Manager): The skill manager.
+//
+// Returns:
+//   - error: An error if listing skills fails.
+//
+// Side Effects:
+//   - Removes and registers resources, notifying the clients of the changes.
+func SyncSkillResources(rm resource.ManagerInterface, sm *skill.Manager) error {
+	rm.ClearResourcesForService("skills")
+	return RegisterSkillResources(rm, sm)
+}
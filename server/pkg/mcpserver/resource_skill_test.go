@@ -5,10 +5,12 @@ package mcpserver
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/mcpany/core/server/pkg/auth"
 	"github.com/mcpany/core/server/pkg/resource"
 	"github.com/mcpany/core/server/pkg/skill"
 	"github.com/stretchr/testify/assert"
@@ -196,8 +198,8 @@ func TestRegisterSkillResources(t *testing.T) {
 
 	// Verify
 	resources := rm.ListResources()
-	// Should have main skill + 1 asset = 2 resources
-	assert.Len(t, resources, 2)
+	// Should have main skill + 1 asset + the index = 3 resources
+	assert.Len(t, resources, 3)
 
 	// Check main skill
 	res1, found := rm.GetResource("skills://my-skill/SKILL.md")
@@ -209,3 +211,51 @@ func TestRegisterSkillResources(t *testing.T) {
 	assert.True(t, found)
 	assert.Equal(t, "Skill Asset: script.py (my-skill)", res2.Resource().Name)
 }
+
+func TestSyncSkillResources(t *testing.T) {
+	sm, err := skill.NewManager(t.TempDir())
+	require.NoError(t, err)
+	rm := resource.NewManager()
+	require.NoError(t, RegisterSkillResources(rm, sm))
+	sm.OnChange(func() { require.NoError(t, SyncSkillResources(rm, sm)) })
+
+	require.NoError(t, sm.CreateSkill(&skill.Skill{Frontmatter: skill.Frontmatter{Name: "my-skill"}}))
+	_, found := rm.GetResource("skills://my-skill/SKILL.md")
+	assert.True(t, found, "a created skill is served")
+
+	require.NoError(t, sm.UpdateSkill("my-skill", &skill.Skill{Frontmatter: skill.Frontmatter{Name: "renamed-skill"}}))
+	_, found = rm.GetResource("skills://my-skill/SKILL.md")
+	assert.False(t, found)
+	_, found = rm.GetResource("skills://renamed-skill/SKILL.md")
+	assert.True(t, found, "a renamed skill is served under its new name")
+
+	require.NoError(t, sm.DeleteSkill("renamed-skill"))
+	resources := rm.ListResources()
+	require.Len(t, resources, 1, "only the index is left")
+	assert.Equal(t, SkillIndexURI, resources[0].Resource().URI)
+}
+
+func TestSkillIndexResource_Read(t *testing.T) {
+	sm, err := skill.NewManager(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, sm.CreateSkill(&skill.Skill{Frontmatter: skill.Frontmatter{Name: "internal", Description: "Internal"}}))
+	require.NoError(t, sm.CreateSkill(&skill.Skill{Frontmatter: skill.Frontmatter{
+		Name: "reports", Description: "Writes reports", Version: "1.2.0", Profiles: []string{"finance"},
+	}}))
+	index := NewSkillIndexResource(sm)
+
+	read := func(ctx context.Context) []skillIndexEntry {
+		result, err := index.Read(ctx)
+		require.NoError(t, err)
+		require.Len(t, result.Contents, 1)
+		var entries []skillIndexEntry
+		require.NoError(t, json.Unmarshal([]byte(result.Contents[0].Text), &entries))
+		return entries
+	}
+
+	assert.Len(t, read(context.Background()), 2, "a session without a profile sees every skill")
+	assert.Equal(t, []skillIndexEntry{{
+		Name: "reports", Version: "1.2.0", Description: "Writes reports", URI: "skills://reports/SKILL.md",
+	}}, read(auth.ContextWithProfileID(context.Background(), "finance")))
+	assert.Empty(t, read(auth.ContextWithProfileID(context.Background(), "support")))
+}
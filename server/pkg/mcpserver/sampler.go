@@ -89,5 +89,49 @@ func (s *MCPSession) ListRoots(ctx context.Context) (*mcp.ListRootsResult, error
 	return s.session.ListRoots(ctx, nil)
 }
 
-// Verify that MCPSession implements tool.Session.
-var _ tool.Session = (*MCPSession)(nil)
+// SessionKey returns the underlying server session, which is the same for
+// every call of the client session.
+//
+// Summary: Identifies the client session.
+//
+// Returns:
+//   - any: The key of the session.
+func (s *MCPSession) SessionKey() any {
+	return s.session
+}
+
+// jobsLogger is the logger of the notifications of completed jobs.
+const jobsLogger = "mcpany.jobs"
+
+// NotifyJobDone sends a logging notification with the completed job to the
+// client. Like all logging notifications, it is only sent once the client
+// has set a logging level.
+//
+// Summary: Notifies the client of a completed job.
+//
+// Parameters:
+//   - ctx: context.Context. The context for the notification.
+//   - job: *tool.Job. The completed job.
+//
+// Returns:
+//   - error: An error if no active session is available or if the notification fails.
+func (s *MCPSession) NotifyJobDone(ctx context.Context, job *tool.Job) error {
+	if s.session == nil {
+		return fmt.Errorf("no active session available for notifications")
+	}
+	level := mcp.LoggingLevel("info")
+	if job.Status == tool.JobFailed {
+		level = "error"
+	}
+	return s.session.Log(ctx, &mcp.LoggingMessageParams{
+		Level:  level,
+		Logger: jobsLogger,
+		Data:   job,
+	})
+}
+
+// Verify that MCPSession implements tool.KeyedSession and tool.JobNotifier.
+var (
+	_ tool.KeyedSession = (*MCPSession)(nil)
+	_ tool.JobNotifier  = (*MCPSession)(nil)
+)
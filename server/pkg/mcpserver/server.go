@@ -6,8 +6,11 @@ package mcpserver
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"slices"
+	"sync"
 	"time"
 
 	jsoniter "github.com/json-iterator/go"
@@ -19,6 +22,7 @@ import (
 	"github.com/mcpany/core/server/pkg/catalog"
 	"github.com/mcpany/core/server/pkg/config"
 	"github.com/mcpany/core/server/pkg/consts"
+	"github.com/mcpany/core/server/pkg/idgen"
 	"github.com/mcpany/core/server/pkg/logging"
 	"github.com/mcpany/core/server/pkg/metrics"
 	"github.com/mcpany/core/server/pkg/middleware"
@@ -70,6 +74,13 @@ type Server struct {
 	catalogManager  *catalog.Manager
 	reloadFunc      func(context.Context) error
 	debug           bool
+	sessions        *SessionTracker
+
+	middlewareMu sync.Mutex
+	// middlewares are the receiving middlewares, innermost first.
+	middlewares []mcp.Middleware
+	// dispatch is the chain built from middlewares, nil until first use.
+	dispatch mcp.MethodHandler
 }
 
 // Server returns the underlying *mcp.Server instance.
@@ -91,6 +102,15 @@ func (s *Server) Server() *mcp.Server {
 	return s.server
 }
 
+// SessionTracker returns the tracker of the identity and activity of the MCP
+// sessions of the server.
+//
+// Returns:
+//   - *SessionTracker: The tracker.
+func (s *Server) SessionTracker() *SessionTracker {
+	return s.sessions
+}
+
 // NewServer creates and initializes a new MCP Any Server.
 //
 // It sets up the necessary managers for tools, prompts, and resources, configures the router
@@ -253,8 +273,9 @@ func NewServer(
 					ToolInputs: r.Params.Arguments,
 				}
 
+				// Calls of the other transports have no session.
 				session := req.GetSession()
-				if serverSession, ok := session.(*mcp.ServerSession); ok {
+				if serverSession, ok := session.(*mcp.ServerSession); ok && serverSession != nil {
 					mcpSession := NewMCPSession(serverSession)
 					ctx = tool.NewContextWithSession(ctx, mcpSession)
 				}
@@ -294,6 +315,7 @@ func NewServer(
 		HasPrompts:   true,
 		HasTools:     true,
 		HasResources: true,
+		GetSessionID: idgen.New,
 	})
 	s.server = mcpServer
 
@@ -306,6 +328,10 @@ func NewServer(
 		// Assuming logging is initialized
 		logging.GetLogger().Error("Failed to register built-in tools", "error", err)
 	}
+	// The job status tool is added with the first asynchronous tool
+	if jobs, ok := s.toolManager.(jobStatusToolSetter); ok {
+		jobs.SetJobStatusTool(NewJobStatusTool(jobs))
+	}
 
 	s.resourceManager.OnListChanged(func() {
 		if s.server != nil {
@@ -328,16 +354,91 @@ func NewServer(
 
 	// Register DLP middleware
 	// Note: config.GlobalSettings() returns *configv1.GlobalSettings
-	s.server.AddReceivingMiddleware(middleware.DLPMiddleware(config.GlobalSettings().GetDlp(), logging.GetLogger()))
+	s.AddReceivingMiddleware(middleware.DLPMiddleware(config.GlobalSettings().GetDlp(), logging.GetLogger()))
+
+	// Register the output guard, which scans tool outputs for prompt injection
+	s.AddReceivingMiddleware(middleware.OutputGuardMiddleware(config.GlobalSettings().GetOutputGuard(), logging.GetLogger()))
+
+	// Register the context budget last, so it accounts for the tool outputs as they reach the client
+	s.AddReceivingMiddleware(middleware.ContextBudgetMiddleware(config.GlobalSettings().GetContextBudget(), logging.GetLogger()))
 
-	s.server.AddReceivingMiddleware(s.routerMiddleware)
-	s.server.AddReceivingMiddleware(s.toolListFilteringMiddleware)
-	s.server.AddReceivingMiddleware(s.resourceListFilteringMiddleware)
-	s.server.AddReceivingMiddleware(s.promptListFilteringMiddleware)
+	s.AddReceivingMiddleware(s.routerMiddleware)
+	s.AddReceivingMiddleware(s.toolListFilteringMiddleware)
+	s.AddReceivingMiddleware(s.resourceListFilteringMiddleware)
+	s.AddReceivingMiddleware(s.promptListFilteringMiddleware)
+	s.AddReceivingMiddleware(s.lazyInitializationMiddleware)
+
+	// Track the sessions outside the middleware that may reject a request
+	s.sessions = NewSessionTracker()
+	s.AddReceivingMiddleware(s.sessions.Middleware)
+
+	// Register tracing last, so its span covers all other middleware
+	s.AddReceivingMiddleware(middleware.TracingMiddleware())
 
 	return s, nil
 }
 
+// AddReceivingMiddleware adds middleware to the MCP server, the last one added
+// outermost. The middleware is recorded, so that the tool calls of the other
+// transports go through the same chain as the MCP calls.
+//
+// Parameters:
+//   - middleware (...mcp.Middleware): The middleware, the first one outermost.
+//
+// Side Effects:
+//   - Wraps the receiving method handler of the MCP server.
+func (s *Server) AddReceivingMiddleware(middleware ...mcp.Middleware) {
+	s.middlewareMu.Lock()
+	defer s.middlewareMu.Unlock()
+	s.server.AddReceivingMiddleware(middleware...)
+	for _, m := range slices.Backward(middleware) {
+		s.middlewares = append(s.middlewares, m)
+	}
+	s.dispatch = nil
+}
+
+// CallToolThroughMiddleware executes a tool call of a transport other than
+// MCP, such as the REST gateway, through the receiving middleware of the MCP
+// server. The call is authenticated, audited, rate limited, counted against
+// the quotas, masked by DLP and guarded like an MCP tools/call.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the call, with its identity.
+//   - req (*tool.ExecutionRequest): The tool name and arguments.
+//
+// Returns:
+//   - *mcp.CallToolResult: The result; a failed execution has IsError set.
+//   - error: An error if a middleware rejected the call.
+func (s *Server) CallToolThroughMiddleware(ctx context.Context, req *tool.ExecutionRequest) (*mcp.CallToolResult, error) {
+	s.middlewareMu.Lock()
+	if s.dispatch == nil {
+		var h mcp.MethodHandler = func(_ context.Context, method string, _ mcp.Request) (mcp.Result, error) {
+			return nil, fmt.Errorf("method %q is not handled", method)
+		}
+		for _, m := range s.middlewares {
+			h = m(h)
+		}
+		s.dispatch = h
+	}
+	dispatch := s.dispatch
+	s.middlewareMu.Unlock()
+
+	args := req.ToolInputs
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+	callReq := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: req.ToolName, Arguments: args}}
+	result, err := dispatch(ctx, consts.MethodToolsCall, callReq)
+	if err != nil {
+		return nil, err
+	}
+	ctr, ok := result.(*mcp.CallToolResult)
+	if !ok {
+		return nil, fmt.Errorf("unexpected result type %T for tool %q", result, req.ToolName)
+	}
+	return ctr, nil
+}
+
 func (s *Server) routerMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
 	return func(
 		ctx context.Context,
@@ -351,6 +452,35 @@ func (s *Server) routerMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
 	}
 }
 
+// lazyInitializationMiddleware connects the services with LAZY initialization
+// on first use: a listing initializes all of them, a tool call the service of
+// the tool.
+func (s *Server) lazyInitializationMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(
+		ctx context.Context,
+		method string,
+		req mcp.Request,
+	) (mcp.Result, error) {
+		if s.serviceRegistry == nil {
+			return next(ctx, method, req)
+		}
+		switch method {
+		case consts.MethodToolsList, consts.MethodPromptsList, consts.MethodResourcesList:
+			s.serviceRegistry.InitializeLazyServices(ctx)
+		case consts.MethodToolsCall:
+			if r, ok := req.(*mcp.CallToolRequest); ok && r.Params != nil {
+				if err := s.serviceRegistry.InitializeServiceForTool(ctx, r.Params.Name); err != nil {
+					return &mcp.CallToolResult{
+						Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Tool execution failed: %v", err)}},
+						IsError: true,
+					}, nil
+				}
+			}
+		}
+		return next(ctx, method, req)
+	}
+}
+
 func (s *Server) toolListFilteringMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
 	return func(
 		ctx context.Context,
@@ -564,7 +694,12 @@ func (s *Server) ReadResource(
 	}
 
 	profileID, _ := auth.ProfileIDFromContext(ctx)
-	if profileID != "" {
+	if scoped, ok := r.(profileScopedResource); ok && profileID != "" {
+		if !scoped.VisibleToProfile(profileID) {
+			logging.GetLogger().Warn("Access denied to resource by profile", "resourceURI", req.Params.URI, "profileID", profileID)
+			return nil, fmt.Errorf("access denied to resource %q", req.Params.URI)
+		}
+	} else if profileID != "" {
 		serviceID := r.Service()
 		if serviceID != "" && !s.toolManager.IsServiceAllowed(serviceID, profileID) {
 			logging.GetLogger().Warn("Access denied to resource by profile", "resourceURI", req.Params.URI, "profileID", profileID)
@@ -936,7 +1071,7 @@ func convertMapToCallToolResult(m map[string]any) (*mcp.CallToolResult, error) {
 		}
 		// Maybe it's just error?
 		isError, _ := m["isError"].(bool)
-		return &mcp.CallToolResult{IsError: isError}, nil
+		return &mcp.CallToolResult{IsError: isError, Meta: resultMeta(m)}, nil
 	}
 
 	contentList, ok := contentRaw.([]any)
@@ -1022,9 +1157,17 @@ func convertMapToCallToolResult(m map[string]any) (*mcp.CallToolResult, error) {
 	return &mcp.CallToolResult{
 		Content: contents,
 		IsError: isError,
+		Meta:    resultMeta(m),
 	}, nil
 }
 
+// resultMeta returns the "_meta" of a map result, e.g. the deprecation notice
+// of a tool called by its old name.
+func resultMeta(m map[string]any) mcp.Meta {
+	meta, _ := m["_meta"].(map[string]any)
+	return meta
+}
+
 // LazyRedact is a byte slice that implements slog.LogValuer to lazily redact
 // its JSON content only when logged.
 type LazyRedact []byte
@@ -1122,6 +1265,13 @@ func summarizeCallToolResult(ctr *mcp.CallToolResult) slog.Value {
 	return slog.GroupValue(attrs...)
 }
 
+// profileScopedResource is a resource that decides itself which profiles it
+// is served to, instead of the services selected by the profiles, such as the
+// skills.
+type profileScopedResource interface {
+	VisibleToProfile(profileID string) bool
+}
+
 func (s *Server) resourceListFilteringMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
 	return func(
 		ctx context.Context,
@@ -1141,7 +1291,11 @@ func (s *Server) resourceListFilteringMiddleware(next mcp.MethodHandler) mcp.Met
 
 			for _, resourceInstance := range managedResources {
 				// Profile filtering
-				if profileID != "" {
+				if scoped, ok := resourceInstance.(profileScopedResource); ok && profileID != "" {
+					if !scoped.VisibleToProfile(profileID) {
+						continue
+					}
+				} else if profileID != "" {
 					serviceID := resourceInstance.Service()
 					// Optimized O(1) map lookup
 					if allowedServices != nil {
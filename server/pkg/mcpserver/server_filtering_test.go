@@ -18,6 +18,7 @@ import (
 	"github.com/mcpany/core/server/pkg/prompt"
 	"github.com/mcpany/core/server/pkg/resource"
 	"github.com/mcpany/core/server/pkg/serviceregistry"
+	"github.com/mcpany/core/server/pkg/skill"
 	"github.com/mcpany/core/server/pkg/tool"
 	"github.com/mcpany/core/server/pkg/upstream/factory"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -317,3 +318,40 @@ func TestPromptListFilteringMiddleware(t *testing.T) {
 	_, ok = res.(*mcp.CallToolResult)
 	require.True(t, ok)
 }
+
+func TestResourceListFilteringMiddleware_SkillProfiles(t *testing.T) {
+	poolManager := pool.NewManager()
+	factory := factory.NewUpstreamServiceFactory(poolManager, nil)
+	messageBus := bus_pb.MessageBus_builder{}.Build()
+	messageBus.SetInMemory(bus_pb.InMemoryBus_builder{}.Build())
+	busProvider, err := bus.NewProvider(messageBus)
+	require.NoError(t, err)
+
+	tm := &serviceInfoProviderToolManager{services: map[string]*tool.ServiceInfo{}}
+	finance := &skill.Skill{Frontmatter: skill.Frontmatter{Name: "reports", Profiles: []string{"finance"}}}
+	rm := &mockResourceManager{
+		resources: []resource.Resource{
+			mcpserver.NewSkillResource(finance),
+			mcpserver.NewSkillResource(&skill.Skill{Frontmatter: skill.Frontmatter{Name: "internal"}}),
+		},
+	}
+	pm := &mockPromptManager{}
+	authManager := auth.NewManager()
+	serviceRegistry := serviceregistry.New(factory, tm, pm, rm, authManager)
+	ctx := context.Background()
+	server, err := mcpserver.NewServer(ctx, tm, pm, rm, authManager, serviceRegistry, nil, busProvider, false)
+	require.NoError(t, err)
+
+	list := func(ctx context.Context) []string {
+		res, err := server.ResourceListFilteringMiddleware(nil)(ctx, consts.MethodResourcesList, &mcp.ListResourcesRequest{})
+		require.NoError(t, err)
+		var uris []string
+		for _, r := range res.(*mcp.ListResourcesResult).Resources {
+			uris = append(uris, r.URI)
+		}
+		return uris
+	}
+	assert.Len(t, list(ctx), 2)
+	assert.Equal(t, []string{"skills://reports/SKILL.md"}, list(auth.ContextWithProfileID(ctx, "finance")))
+	assert.Empty(t, list(auth.ContextWithProfileID(ctx, "support")))
+}
@@ -0,0 +1,173 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package mcpserver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/consts"
+	"github.com/mcpany/core/server/pkg/util"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Transports of the MCP sessions.
+const (
+	// TransportHTTP is the streamable HTTP transport.
+	TransportHTTP = "http"
+	// TransportWebSocket is the WebSocket transport.
+	TransportWebSocket = "websocket"
+	// TransportStdio is the stdio transport.
+	TransportStdio = "stdio"
+)
+
+type transportContextKey struct{}
+
+// ContextWithTransport returns a copy of ctx that tags the MCP sessions
+// connected with it with their transport.
+//
+// Parameters:
+//   - ctx: context.Context. The context passed to Connect or Run.
+//   - transport: string. The transport, e.g. TransportStdio.
+//
+// Returns:
+//   - context.Context: The tagged context.
+func ContextWithTransport(ctx context.Context, transport string) context.Context {
+	return context.WithValue(ctx, transportContextKey{}, transport)
+}
+
+// SessionInfo is what the server knows about a connected MCP session beyond
+// its initialize parameters.
+//
+// Summary: The identity and activity of an MCP session.
+type SessionInfo struct {
+	// Transport is the transport of the session, e.g. TransportHTTP.
+	Transport string
+	// UserID is the authenticated user that started the session, if any.
+	UserID string
+	// ProfileID is the profile the session was started with, if any.
+	ProfileID string
+	// RemoteAddr is the address of the client, if known.
+	RemoteAddr string
+	// StartedAt is when the server first saw the session.
+	StartedAt time.Time
+	// LastActiveAt is when the session last sent a request.
+	LastActiveAt time.Time
+	// Requests is the number of requests of the session.
+	Requests int64
+	// ToolCalls is the number of tool calls of the session.
+	ToolCalls int64
+	// Errors is the number of requests and tool calls that failed.
+	Errors int64
+}
+
+// SessionTracker records the identity and activity of the MCP sessions, so
+// that the admin API can tell which clients are connected and drop the stuck
+// or abusive ones.
+//
+// Summary: Tracks the connected MCP sessions.
+type SessionTracker struct {
+	mu       sync.Mutex
+	sessions map[*mcp.ServerSession]*SessionInfo
+}
+
+// NewSessionTracker creates an empty SessionTracker.
+//
+// Returns:
+//   - *SessionTracker: The tracker.
+func NewSessionTracker() *SessionTracker {
+	return &SessionTracker{sessions: make(map[*mcp.ServerSession]*SessionInfo)}
+}
+
+// Middleware records the requests of the sessions. A session is recorded on
+// its first request and forgotten when it ends.
+//
+// Summary: Creates an MCP middleware that tracks the sessions.
+//
+// Parameters:
+//   - next: mcp.MethodHandler. The next handler in the chain.
+//
+// Returns:
+//   - mcp.MethodHandler: The wrapped handler.
+//
+// Side Effects:
+//   - Starts a goroutine per session that waits for its end.
+func (t *SessionTracker) Middleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		ss, ok := req.GetSession().(*mcp.ServerSession)
+		if !ok || ss == nil {
+			return next(ctx, method, req)
+		}
+		t.begin(ctx, ss, req)
+
+		result, err := next(ctx, method, req)
+
+		failed := err != nil
+		if r, ok := result.(*mcp.CallToolResult); ok && r != nil && r.IsError {
+			failed = true
+		}
+		t.mu.Lock()
+		if info, ok := t.sessions[ss]; ok {
+			if method == consts.MethodToolsCall {
+				info.ToolCalls++
+			}
+			if failed {
+				info.Errors++
+			}
+		}
+		t.mu.Unlock()
+		return result, err
+	}
+}
+
+// begin records a request of a session, and the session itself on its first
+// request.
+func (t *SessionTracker) begin(ctx context.Context, ss *mcp.ServerSession, req mcp.Request) {
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if info, ok := t.sessions[ss]; ok {
+		info.LastActiveAt = now
+		info.Requests++
+		return
+	}
+
+	info := &SessionInfo{StartedAt: now, LastActiveAt: now, Requests: 1}
+	if transport, ok := ctx.Value(transportContextKey{}).(string); ok {
+		info.Transport = transport
+	} else if extra := req.GetExtra(); extra != nil && extra.Header != nil {
+		info.Transport = TransportHTTP
+	}
+	info.UserID, _ = auth.UserFromContext(ctx)
+	info.ProfileID, _ = auth.ProfileIDFromContext(ctx)
+	info.RemoteAddr, _ = util.RemoteIPFromContext(ctx)
+	t.sessions[ss] = info
+
+	go func() {
+		_ = ss.Wait()
+		t.mu.Lock()
+		delete(t.sessions, ss)
+		t.mu.Unlock()
+	}()
+}
+
+// Info returns what the tracker knows about a session.
+//
+// Parameters:
+//   - ss: *mcp.ServerSession. The session.
+//
+// Returns:
+//   - SessionInfo: A copy of the info of the session.
+//   - bool: False if the session has not sent a request yet.
+func (t *SessionTracker) Info(ss *mcp.ServerSession) (SessionInfo, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	info, ok := t.sessions[ss]
+	if !ok {
+		return SessionInfo{}, false
+	}
+	return *info, true
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sessionTrackerTestArgs struct {
+	Fail bool `json:"fail,omitempty"`
+}
+
+func TestSessionTracker(t *testing.T) {
+	tracker := NewSessionTracker()
+	server := mcp.NewServer(&mcp.Implementation{Name: "mcpany"}, nil)
+	server.AddReceivingMiddleware(tracker.Middleware)
+	mcp.AddTool(server, &mcp.Tool{Name: "echo"}, func(_ context.Context, _ *mcp.CallToolRequest, in sessionTrackerTestArgs) (*mcp.CallToolResult, any, error) {
+		if in.Fail {
+			return nil, nil, errors.New("boom")
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "ok"}}}, nil, nil
+	})
+
+	ctx := auth.ContextWithProfileID(auth.ContextWithUser(ContextWithTransport(context.Background(), TransportWebSocket), "alice"), "dev")
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "claude"}, nil).Connect(context.Background(), clientTransport, nil)
+	require.NoError(t, err)
+
+	_, err = cs.CallTool(context.Background(), &mcp.CallToolParams{Name: "echo", Arguments: map[string]any{}})
+	require.NoError(t, err)
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: "echo", Arguments: map[string]any{"fail": true}})
+	require.NoError(t, err)
+	assert.True(t, res.IsError)
+
+	info, ok := tracker.Info(ss)
+	require.True(t, ok)
+	assert.Equal(t, TransportWebSocket, info.Transport)
+	assert.Equal(t, "alice", info.UserID)
+	assert.Equal(t, "dev", info.ProfileID)
+	assert.Equal(t, int64(2), info.ToolCalls)
+	assert.Equal(t, int64(1), info.Errors)
+	assert.GreaterOrEqual(t, info.Requests, int64(3), "initialize and the tool calls")
+	assert.False(t, info.StartedAt.After(info.LastActiveAt))
+
+	require.NoError(t, cs.Close())
+	assert.Eventually(t, func() bool {
+		_, ok := tracker.Info(ss)
+		return !ok
+	}, 5*time.Second, 10*time.Millisecond, "an ended session is forgotten")
+}
@@ -0,0 +1,257 @@
+// Copyright 2025 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/mcpany/core/server/pkg/idgen"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// WebSocketSubprotocol is the WebSocket subprotocol negotiated with MCP clients.
+	WebSocketSubprotocol = "mcp"
+
+	defaultWebSocketPingInterval = 30 * time.Second
+	defaultWebSocketPingTimeout  = 10 * time.Second
+	defaultWebSocketReadLimit    = 10 << 20 // 10MB
+)
+
+// WebSocketHandlerOptions configures a WebSocketHandler.
+type WebSocketHandlerOptions struct {
+	// PingInterval is the interval between keepalive pings. Zero uses the
+	// default of 30 seconds; a negative value disables pings.
+	PingInterval time.Duration
+	// PingTimeout is how long to wait for a pong before the connection is
+	// considered dead. Zero uses the default of 10 seconds.
+	PingTimeout time.Duration
+	// ReadLimit is the maximum size in bytes of a single message. Zero uses
+	// the default of 10MB.
+	ReadLimit int64
+	// OriginPatterns lists additional hosts allowed to open cross-origin
+	// connections. Same-origin connections are always allowed.
+	OriginPatterns []string
+}
+
+// WebSocketHandler serves MCP sessions over WebSocket connections.
+//
+// Each accepted connection becomes one MCP session on the server returned by
+// getServer, with the same semantics as the stdio and streamable HTTP
+// transports. Authentication is expected to happen before the upgrade, so
+// the handler should be wrapped with the same auth middleware as the HTTP
+// endpoints; the request context (and the identity it carries) is propagated
+// to every call made on the session.
+type WebSocketHandler struct {
+	getServer func(*http.Request) *mcp.Server
+	opts      WebSocketHandlerOptions
+}
+
+// NewWebSocketHandler creates a new WebSocketHandler.
+//
+// Parameters:
+//   - getServer (func(*http.Request) *mcp.Server): Returns the MCP server for an incoming request.
+//     Returning nil rejects the request with 400 Bad Request.
+//   - opts (*WebSocketHandlerOptions): Optional settings. May be nil.
+//
+// Returns:
+//   - *WebSocketHandler: The initialized handler.
+//
+// Side Effects:
+//   - None.
+func NewWebSocketHandler(getServer func(*http.Request) *mcp.Server, opts *WebSocketHandlerOptions) *WebSocketHandler {
+	h := &WebSocketHandler{getServer: getServer}
+	if opts != nil {
+		h.opts = *opts
+	}
+	if h.opts.PingInterval == 0 {
+		h.opts.PingInterval = defaultWebSocketPingInterval
+	}
+	if h.opts.PingTimeout <= 0 {
+		h.opts.PingTimeout = defaultWebSocketPingTimeout
+	}
+	if h.opts.ReadLimit <= 0 {
+		h.opts.ReadLimit = defaultWebSocketReadLimit
+	}
+	return h
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection and runs an MCP
+// session over it until either side closes the connection.
+//
+// Parameters:
+//   - w (http.ResponseWriter): The response writer.
+//   - r (*http.Request): The upgrade request.
+//
+// Side Effects:
+//   - Hijacks the underlying connection and blocks until the session ends.
+func (h *WebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	server := h.getServer(r)
+	if server == nil {
+		http.Error(w, "no server available", http.StatusBadRequest)
+		return
+	}
+
+	ws, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		Subprotocols:   []string{WebSocketSubprotocol},
+		OriginPatterns: h.opts.OriginPatterns,
+	})
+	if err != nil {
+		// Accept has already written an error response.
+		logging.GetLogger().Warn("Failed to accept websocket connection", "error", err, "remote_addr", r.RemoteAddr)
+		return
+	}
+	ws.SetReadLimit(h.opts.ReadLimit)
+
+	// Detach from request cancellation (the hijacked request context may be
+	// canceled once the upgrade completes) while keeping auth values.
+	ctx, cancel := context.WithCancel(ContextWithTransport(context.WithoutCancel(r.Context()), TransportWebSocket))
+	defer cancel()
+
+	conn := newWebSocketConn(ws, idgen.New())
+	session, err := server.Connect(ctx, &webSocketTransport{conn: conn}, nil)
+	if err != nil {
+		logging.GetLogger().Error("Failed to start websocket session", "error", err)
+		_ = ws.Close(websocket.StatusInternalError, "failed to start session")
+		return
+	}
+
+	if h.opts.PingInterval > 0 {
+		go h.keepalive(ctx, conn)
+	}
+
+	_ = session.Wait()
+}
+
+// keepalive periodically pings the peer and closes the connection if a pong
+// is not received in time.
+func (h *WebSocketHandler) keepalive(ctx context.Context, conn *webSocketConn) {
+	ticker := time.NewTicker(h.opts.PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-conn.done:
+			return
+		case <-ticker.C:
+			pingCtx, cancel := context.WithTimeout(ctx, h.opts.PingTimeout)
+			err := conn.ws.Ping(pingCtx)
+			cancel()
+			if err != nil {
+				logging.GetLogger().Debug("WebSocket keepalive failed, closing connection", "session_id", conn.sessionID, "error", err)
+				_ = conn.ws.Close(websocket.StatusPolicyViolation, "keepalive timeout")
+				_ = conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// webSocketTransport adapts an accepted WebSocket connection to mcp.Transport.
+type webSocketTransport struct {
+	conn *webSocketConn
+}
+
+// Connect returns the already-established connection.
+//
+// Parameters:
+//   - _ (context.Context): Unused.
+//
+// Returns:
+//   - mcp.Connection: The WebSocket connection.
+//   - error: Always nil.
+func (t *webSocketTransport) Connect(_ context.Context) (mcp.Connection, error) {
+	return t.conn, nil
+}
+
+// webSocketConn implements mcp.Connection over a single WebSocket, carrying
+// one JSON-RPC message per text frame.
+type webSocketConn struct {
+	ws        *websocket.Conn
+	sessionID string
+	writeMu   sync.Mutex
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func newWebSocketConn(ws *websocket.Conn, sessionID string) *webSocketConn {
+	return &webSocketConn{
+		ws:        ws,
+		sessionID: sessionID,
+		done:      make(chan struct{}),
+	}
+}
+
+// Read reads the next JSON-RPC message from the WebSocket.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the read.
+//
+// Returns:
+//   - jsonrpc.Message: The decoded message.
+//   - error: An error if the connection is closed or the frame is invalid.
+func (c *webSocketConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	typ, data, err := c.ws.Read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if typ != websocket.MessageText {
+		return nil, fmt.Errorf("unsupported websocket message type: %v", typ)
+	}
+	return jsonrpc.DecodeMessage(data)
+}
+
+// Write writes a JSON-RPC message to the WebSocket as a single text frame.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the write.
+//   - msg (jsonrpc.Message): The message to send.
+//
+// Returns:
+//   - error: An error if encoding or writing fails.
+func (c *webSocketConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	data, err := jsonrpc.EncodeMessage(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.ws.Write(ctx, websocket.MessageText, data)
+}
+
+// Close closes the WebSocket with a normal closure status. It is safe to call
+// multiple times.
+//
+// Returns:
+//   - error: An error if closing fails.
+func (c *webSocketConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.done)
+		err = c.ws.Close(websocket.StatusNormalClosure, "")
+		var closeErr websocket.CloseError
+		if errors.As(err, &closeErr) || errors.Is(err, net.ErrClosed) {
+			err = nil
+		}
+	})
+	return err
+}
+
+// SessionID returns the session ID assigned to this connection.
+//
+// Returns:
+//   - string: The session ID.
+func (c *webSocketConn) SessionID() string {
+	return c.sessionID
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package mcpserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type wsClientTransport struct {
+	ws *websocket.Conn
+}
+
+func (t *wsClientTransport) Connect(_ context.Context) (mcp.Connection, error) {
+	return newWebSocketConn(t.ws, ""), nil
+}
+
+func newTestWebSocketServer(t *testing.T, opts *WebSocketHandlerOptions) (*httptest.Server, chan string) {
+	t.Helper()
+	users := make(chan string, 1)
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "v1"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "whoami"}, func(ctx context.Context, _ *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, any, error) {
+		user, _ := auth.UserFromContext(ctx)
+		users <- user
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: user}}}, nil, nil
+	})
+
+	handler := NewWebSocketHandler(func(_ *http.Request) *mcp.Server { return server }, opts)
+	// Simulate the auth middleware injecting the caller identity.
+	withAuth := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r.WithContext(auth.ContextWithUser(r.Context(), "alice")))
+	})
+	ts := httptest.NewServer(withAuth)
+	t.Cleanup(ts.Close)
+	return ts, users
+}
+
+func dialTestWebSocket(t *testing.T, ctx context.Context, url string) *websocket.Conn {
+	t.Helper()
+	ws, resp, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(url, "http"), &websocket.DialOptions{
+		Subprotocols: []string{WebSocketSubprotocol},
+	})
+	require.NoError(t, err)
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+	assert.Equal(t, WebSocketSubprotocol, ws.Subprotocol())
+	return ws
+}
+
+func TestWebSocketHandler_Session(t *testing.T) {
+	ts, users := newTestWebSocketServer(t, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ws := dialTestWebSocket(t, ctx, ts.URL)
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v1"}, nil)
+	session, err := client.Connect(ctx, &wsClientTransport{ws: ws}, nil)
+	require.NoError(t, err)
+	defer func() { _ = session.Close() }()
+
+	tools, err := session.ListTools(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, tools.Tools, 1)
+	assert.Equal(t, "whoami", tools.Tools[0].Name)
+
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "whoami"})
+	require.NoError(t, err)
+	require.Len(t, res.Content, 1)
+	assert.Equal(t, "alice", res.Content[0].(*mcp.TextContent).Text)
+	assert.Equal(t, "alice", <-users)
+}
+
+func TestWebSocketHandler_Keepalive(t *testing.T) {
+	ts, _ := newTestWebSocketServer(t, &WebSocketHandlerOptions{
+		PingInterval: 20 * time.Millisecond,
+		PingTimeout:  time.Second,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ws := dialTestWebSocket(t, ctx, ts.URL)
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v1"}, nil)
+	session, err := client.Connect(ctx, &wsClientTransport{ws: ws}, nil)
+	require.NoError(t, err)
+	defer func() { _ = session.Close() }()
+
+	// Pings are answered by the client's read loop, so the session must
+	// survive several keepalive intervals.
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, session.Ping(ctx, nil))
+}
+
+func TestWebSocketHandler_NoServer(t *testing.T) {
+	handler := NewWebSocketHandler(func(_ *http.Request) *mcp.Server { return nil }, nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/mcp/ws", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestWebSocketHandler_RejectsPlainHTTP(t *testing.T) {
+	ts, _ := newTestWebSocketServer(t, nil)
+	resp, err := http.Get(ts.URL)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusUpgradeRequired, resp.StatusCode)
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"fmt"
+	"sync"
+)
+
+// DefaultLatencyBuckets are the upper bounds in seconds of the buckets of the
+// latency histograms. Compared to the Prometheus defaults they reach further,
+// as tool calls to LLM-backed or batch upstreams often take tens of seconds.
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+var (
+	latencyBucketsMu sync.RWMutex
+	latencyBuckets   []float64
+)
+
+// SetLatencyBuckets sets the buckets of the latency histograms.
+//
+// Summary: Configures the latency histogram buckets.
+//
+// Histograms keep the buckets they were created with, so the buckets must be
+// set before the histograms are registered.
+//
+// Parameters:
+//   - buckets: []float64. The upper bounds in seconds, in increasing order. Empty restores the defaults.
+//
+// Returns:
+//   - error: An error if a bound is not positive or the bounds are not increasing.
+func SetLatencyBuckets(buckets []float64) error {
+	if err := ValidateBuckets(buckets); err != nil {
+		return err
+	}
+	latencyBucketsMu.Lock()
+	defer latencyBucketsMu.Unlock()
+	latencyBuckets = append([]float64(nil), buckets...)
+	return nil
+}
+
+// LatencyBuckets returns the buckets of the latency histograms.
+//
+// Summary: Retrieves the latency histogram buckets.
+//
+// Returns:
+//   - []float64: The configured buckets, or DefaultLatencyBuckets if none are set.
+func LatencyBuckets() []float64 {
+	latencyBucketsMu.RLock()
+	defer latencyBucketsMu.RUnlock()
+	if len(latencyBuckets) == 0 {
+		return append([]float64(nil), DefaultLatencyBuckets...)
+	}
+	return append([]float64(nil), latencyBuckets...)
+}
+
+// ValidateBuckets checks that histogram buckets are positive and increasing.
+//
+// Summary: Validates histogram buckets.
+//
+// Parameters:
+//   - buckets: []float64. The upper bounds of the buckets.
+//
+// Returns:
+//   - error: An error describing the first invalid bound.
+func ValidateBuckets(buckets []float64) error {
+	for i, b := range buckets {
+		if b <= 0 {
+			return fmt.Errorf("bucket %d (%g) must be positive", i, b)
+		}
+		if i > 0 && b <= buckets[i-1] {
+			return fmt.Errorf("bucket %d (%g) must be greater than bucket %d (%g)", i, b, i-1, buckets[i-1])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyBuckets(t *testing.T) {
+	defer func() { require.NoError(t, SetLatencyBuckets(nil)) }()
+
+	assert.Equal(t, DefaultLatencyBuckets, LatencyBuckets())
+
+	require.NoError(t, SetLatencyBuckets([]float64{0.1, 1, 10}))
+	assert.Equal(t, []float64{0.1, 1, 10}, LatencyBuckets())
+
+	// The returned slice is a copy.
+	LatencyBuckets()[0] = 5
+	assert.Equal(t, []float64{0.1, 1, 10}, LatencyBuckets())
+
+	assert.EqualError(t, SetLatencyBuckets([]float64{0.1, 0.1}), "bucket 1 (0.1) must be greater than bucket 0 (0.1)")
+	assert.EqualError(t, SetLatencyBuckets([]float64{0, 1}), "bucket 0 (0) must be positive")
+	assert.Equal(t, []float64{0.1, 1, 10}, LatencyBuckets())
+
+	require.NoError(t, SetLatencyBuckets(nil))
+	assert.Equal(t, DefaultLatencyBuckets, LatencyBuckets())
+}
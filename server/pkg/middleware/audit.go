@@ -17,8 +17,12 @@ import (
 	"github.com/mcpany/core/server/pkg/audit"
 	"github.com/mcpany/core/server/pkg/auth"
 	"github.com/mcpany/core/server/pkg/config"
+	"github.com/mcpany/core/server/pkg/enrichment"
+	"github.com/mcpany/core/server/pkg/idgen"
 	"github.com/mcpany/core/server/pkg/logging"
 	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/mcpany/core/server/pkg/util"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -93,11 +97,79 @@ func (m *AuditMiddleware) initializeStore(config *configv1.AuditConfig) error {
 		if err != nil {
 			return fmt.Errorf("failed to initialize audit store: %w", err)
 		}
+		if signing := config.GetSigning(); signing != nil {
+			if err := setCheckpointSigner(store, signing); err != nil {
+				_ = store.Close()
+				return fmt.Errorf("failed to initialize audit signing: %w", err)
+			}
+		}
+		if retention := config.GetRetention(); retention != nil {
+			if err := setRetention(store, retention); err != nil {
+				_ = store.Close()
+				return fmt.Errorf("failed to initialize audit retention: %w", err)
+			}
+		}
+		if export := config.GetExport(); export != nil {
+			exporter, err := newExportStore(export)
+			if err != nil {
+				_ = store.Close()
+				return fmt.Errorf("failed to initialize audit export: %w", err)
+			}
+			store = audit.NewTeeStore(store, exporter)
+		}
 		m.store = store
 	}
 	return nil
 }
 
+// checkpointSignable is implemented by the audit stores that support signed
+// checkpoints of their hash chain.
+type checkpointSignable interface {
+	SetCheckpointSigner(signer *audit.CheckpointSigner) error
+}
+
+func setCheckpointSigner(store audit.Store, config *configv1.AuditSigningConfig) error {
+	s, ok := store.(checkpointSignable)
+	if !ok {
+		return fmt.Errorf("signed checkpoints are only supported by the file and sqlite storage types")
+	}
+	key, err := util.ResolveSecret(context.Background(), config.GetPrivateKey())
+	if err != nil {
+		return fmt.Errorf("failed to resolve private_key: %w", err)
+	}
+	signer, err := audit.NewCheckpointSigner([]byte(key), int(config.GetCheckpointInterval()))
+	if err != nil {
+		return err
+	}
+	return s.SetCheckpointSigner(signer)
+}
+
+// retainable is implemented by the audit stores that can prune old entries.
+type retainable interface {
+	SetRetention(config *configv1.RetentionConfig) error
+}
+
+func setRetention(store audit.Store, config *configv1.RetentionConfig) error {
+	s, ok := store.(retainable)
+	if !ok {
+		return fmt.Errorf("retention is only supported by the sqlite storage type")
+	}
+	return s.SetRetention(config)
+}
+
+func newExportStore(config *configv1.AuditExportConfig) (*audit.ExportStore, error) {
+	objects, err := audit.NewObjectStore(context.Background(), config)
+	if err != nil {
+		return nil, err
+	}
+	exporter, err := audit.NewExportStore(config, objects)
+	if err != nil {
+		_ = objects.Close()
+		return nil, err
+	}
+	return exporter, nil
+}
+
 // SetStore sets the audit store.
 // This is primarily used for testing.
 //
@@ -191,7 +263,7 @@ func (m *AuditMiddleware) Execute(ctx context.Context, req *tool.ExecutionReques
 	m.mu.RLock()
 	auditConfig := m.config
 	store := m.store
-	redactor := m.redactor
+	redactor := m.redactor.ForTool(req.ToolName)
 	m.mu.RUnlock()
 
 	if auditConfig == nil || !auditConfig.GetEnabled() {
@@ -202,10 +274,15 @@ func (m *AuditMiddleware) Execute(ctx context.Context, req *tool.ExecutionReques
 
 	// Trace Context
 	traceID := GetTraceID(ctx)
+	parentID := GetSpanID(ctx) // The parent is the current span in context
+	if sc := trace.SpanContextFromContext(ctx); traceID == "" && sc.IsValid() {
+		// Correlate the entry with the OpenTelemetry trace of the request.
+		traceID = sc.TraceID().String()
+		parentID = sc.SpanID().String()
+	}
 	if traceID == "" {
 		traceID = strings.ReplaceAll(uuid.New().String(), "-", "")
 	}
-	parentID := GetSpanID(ctx) // The parent is the current span in context
 	spanID := strings.ReplaceAll(uuid.New().String(), "-", "")[:16]
 
 	// Update context for downstream (Recursive Tracing)
@@ -218,6 +295,7 @@ func (m *AuditMiddleware) Execute(ctx context.Context, req *tool.ExecutionReques
 
 	// Prepare audit entry
 	entry := audit.Entry{
+		ID:         idgen.NewWithTrace(traceID),
 		Timestamp:  start,
 		ToolName:   req.ToolName,
 		Duration:   duration.String(),
@@ -233,6 +311,14 @@ func (m *AuditMiddleware) Execute(ctx context.Context, req *tool.ExecutionReques
 	if profileID, ok := auth.ProfileIDFromContext(ctx); ok {
 		entry.ProfileID = profileID
 	}
+	if apiKeyID, ok := auth.APIKeyIDFromContext(ctx); ok {
+		entry.APIKeyID = apiKeyID
+	}
+	var serviceID string
+	if t, ok := tool.GetFromContext(ctx); ok && t.Tool() != nil {
+		serviceID = t.Tool().GetServiceId()
+	}
+	entry.Labels = enrichment.Enrich(ctx, enrichment.Call{ToolName: req.ToolName, ServiceID: serviceID})
 
 	if auditConfig.GetLogArguments() {
 		// Try to marshal arguments to RawMessage to avoid double escaping if it's already structured
@@ -387,6 +473,25 @@ func (m *AuditMiddleware) Close() error {
 	return nil
 }
 
+// Flush sends the audit entries buffered by the store.
+//
+// Summary: Flushes the audit store.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the wait.
+//
+// Returns:
+//   - error: An error if the entries cannot be sent in time.
+func (m *AuditMiddleware) Flush(ctx context.Context) error {
+	m.mu.RLock()
+	store := m.store
+	m.mu.RUnlock()
+	if store == nil {
+		return nil
+	}
+	return audit.Flush(ctx, store)
+}
+
 // Write writes an audit entry directly to the store.
 //
 // Parameters:
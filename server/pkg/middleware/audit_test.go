@@ -13,10 +13,13 @@ import (
 
 	configv1 "github.com/mcpany/core/proto/config/v1"
 	"github.com/mcpany/core/server/pkg/audit"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/idgen"
 	"github.com/mcpany/core/server/pkg/tool"
 	"github.com/mcpany/core/server/pkg/validation"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -102,6 +105,49 @@ func TestAuditMiddleware_Execute(t *testing.T) {
 	assert.Equal(t, "test-tool", entry.ToolName)
 	assert.Contains(t, string(entry.Arguments), "value")
 	assert.Equal(t, "success", entry.Result)
+	assert.NotEmpty(t, entry.ID)
+	assert.Equal(t, entry.TraceID, idgen.TraceIDFromID(entry.ID))
+	assert.Empty(t, entry.APIKeyID)
+}
+
+func TestAuditMiddleware_Execute_OpenTelemetryTrace(t *testing.T) {
+	mockStore := &MockAuditStore{}
+	mw, err := NewAuditMiddleware(configv1.AuditConfig_builder{Enabled: proto.Bool(true)}.Build())
+	require.NoError(t, err)
+	mw.SetStore(mockStore)
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+	_, err = mw.Execute(ctx, &tool.ExecutionRequest{ToolName: "test-tool"}, func(context.Context, *tool.ExecutionRequest) (any, error) {
+		return "success", nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, mockStore.Entries, 1)
+	entry := mockStore.Entries[0]
+	assert.Equal(t, sc.TraceID().String(), entry.TraceID, "entries are correlated with the request trace")
+	assert.Equal(t, sc.SpanID().String(), entry.ParentID)
+}
+
+func TestAuditMiddleware_Execute_APIKeyID(t *testing.T) {
+	mockStore := &MockAuditStore{}
+	mw, err := NewAuditMiddleware(configv1.AuditConfig_builder{Enabled: proto.Bool(true)}.Build())
+	require.NoError(t, err)
+	mw.SetStore(mockStore)
+
+	ctx := auth.ContextWithAPIKeyID(auth.ContextWithUser(context.Background(), "apikey:k1"), "k1")
+	_, err = mw.Execute(ctx, &tool.ExecutionRequest{ToolName: "test-tool"}, func(context.Context, *tool.ExecutionRequest) (any, error) {
+		return "ok", nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, mockStore.Entries, 1)
+	assert.Equal(t, "k1", mockStore.Entries[0].APIKeyID)
+	assert.Equal(t, "apikey:k1", mockStore.Entries[0].UserID)
 }
 
 func TestAuditMiddleware_Execute_Disabled(t *testing.T) {
@@ -12,6 +12,7 @@ import (
 
 	"github.com/mcpany/core/server/pkg/auth"
 	"github.com/mcpany/core/server/pkg/consts"
+	"github.com/mcpany/core/server/pkg/federation"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -88,6 +89,11 @@ func AuthMiddleware(authManager *auth.Manager) mcp.Middleware {
 				if err != nil {
 					return nil, fmt.Errorf("unauthorized: %w", err)
 				}
+				// A calling mcpany peer, or a user with access, may select the profile to serve.
+				newCtx, err = federation.ApplyProfile(newCtx, httpReq, authManager)
+				if err != nil {
+					return nil, fmt.Errorf("forbidden: %w", err)
+				}
 				return next(newCtx, method, req)
 			}
 
@@ -96,6 +102,10 @@ func AuthMiddleware(authManager *auth.Manager) mcp.Middleware {
 			if err != nil {
 				return nil, fmt.Errorf("unauthorized: %w", err)
 			}
+			newCtx, err = federation.ApplyProfile(newCtx, httpReq, authManager)
+			if err != nil {
+				return nil, fmt.Errorf("forbidden: %w", err)
+			}
 
 			// If authentication is successful, proceed to the next handler.
 			return next(newCtx, method, req)
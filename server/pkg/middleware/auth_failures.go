@@ -0,0 +1,77 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/mcpany/core/server/pkg/util"
+)
+
+// AuthFailureMiddleware reports the requests rejected with 401 Unauthorized,
+// whichever handler rejected them, so that repeated failures of a client can
+// be detected.
+//
+// Summary: Reports failed authentications with the client IP.
+//
+// Parameters:
+//   - onFailure: func(ip string). Called with the IP of the client of each rejected request.
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware.
+func AuthFailureMiddleware(onFailure func(ip string)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := &authFailureResponseWriter{ResponseWriter: w}
+			next.ServeHTTP(rw, r)
+			if rw.status != http.StatusUnauthorized {
+				return
+			}
+			ip, ok := util.RemoteIPFromContext(r.Context())
+			if !ok {
+				ip = util.ExtractIP(r.RemoteAddr)
+			}
+			onFailure(ip)
+		})
+	}
+}
+
+// authFailureResponseWriter records the status code of the response.
+type authFailureResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code and writes it.
+//
+// Summary: Records the status code of the response.
+//
+// Parameters:
+//   - code: int. The HTTP status code.
+func (w *authFailureResponseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Flush implements http.Flusher to support streaming.
+//
+// Summary: Flushes the response to the client.
+func (w *authFailureResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying http.ResponseWriter.
+//
+// Summary: Exposes the wrapped writer so that http.ResponseController and
+// WebSocket upgrades can reach the Hijacker implementation.
+//
+// Returns:
+//   - http.ResponseWriter: The wrapped writer.
+func (w *authFailureResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,44 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mcpany/core/server/pkg/middleware"
+	"github.com/mcpany/core/server/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthFailureMiddleware(t *testing.T) {
+	var failures []string
+	handler := middleware.AuthFailureMiddleware(func(ip string) {
+		failures = append(failures, ip)
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "secret" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	serve := func(key string, withContextIP bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/mcp", nil)
+		req.RemoteAddr = "192.0.2.10:4321"
+		req.Header.Set("X-API-Key", key)
+		if withContextIP {
+			req = req.WithContext(util.ContextWithRemoteIP(req.Context(), "203.0.113.7"))
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, serve("secret", false).Code)
+	assert.Equal(t, http.StatusUnauthorized, serve("wrong", false).Code)
+	assert.Equal(t, http.StatusUnauthorized, serve("wrong", true).Code)
+	assert.Equal(t, []string{"192.0.2.10", "203.0.113.7"}, failures)
+}
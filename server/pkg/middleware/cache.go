@@ -6,6 +6,7 @@ package middleware
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"hash"
 	"hash/fnv"
@@ -15,14 +16,14 @@ import (
 
 	"github.com/eko/gocache/lib/v4/cache"
 	"github.com/eko/gocache/lib/v4/store"
-	gocache_store "github.com/eko/gocache/store/go_cache/v4"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/mcpany/core/server/pkg/logging"
 	"github.com/mcpany/core/server/pkg/metrics"
 	"github.com/mcpany/core/server/pkg/tool"
 	"github.com/mcpany/core/server/pkg/util"
 	configv1 "github.com/mcpany/core/proto/config/v1"
-	go_cache "github.com/patrickmn/go-cache"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
 )
 
 // ProviderFactory is a function that creates an EmbeddingProvider.
@@ -39,7 +40,14 @@ var (
 
 // CachingMiddleware handles caching of tool execution results.
 type CachingMiddleware struct {
-	cache           *cache.Cache[any]
+	mu sync.RWMutex // Guards cache, config, redisClient and sharedRedis
+	// cache holds the exact-match entries, in memory or in Redis.
+	cache       *cache.Cache[any]
+	config      *configv1.ResponseCacheConfig
+	redisClient *redis.Client
+	// sharedRedis, if set, holds the entries when the config has no Redis.
+	// It is owned by the caller.
+	sharedRedis     *redis.Client
 	toolManager     tool.ManagerInterface
 	semanticCaches  sync.Map
 	initMu          sync.Mutex // Guards semantic cache initialization
@@ -61,10 +69,8 @@ type CachingMiddleware struct {
 // Side Effects:
 //   - None
 func NewCachingMiddleware(toolManager tool.ManagerInterface) *CachingMiddleware {
-	goCacheStore := gocache_store.NewGoCache(go_cache.New(5*time.Minute, 10*time.Minute))
-	cacheManager := cache.New[any](goCacheStore)
 	return &CachingMiddleware{
-		cache:       cacheManager,
+		cache:       cache.New[any](newLRUStore(defaultCacheMaxEntries)),
 		toolManager: toolManager,
 		hasherPool: &sync.Pool{
 			New: func() any {
@@ -191,16 +197,23 @@ func (m *CachingMiddleware) Execute(ctx context.Context, req *tool.ExecutionRequ
 		ctx = tool.NewContextWithCacheControl(ctx, cacheControl)
 	}
 
-	cacheKey := m.getCacheKey(req)
+	cacheKey := m.buildCacheKey(req, cacheConfig)
+	entries := m.getCache()
 
 	// Check cache ONLY if action is not DeleteCache
 	if cacheControl.Action != tool.ActionDeleteCache {
 		// If normal allow (0), check cache.
-		if cached, err := m.cache.Get(ctx, cacheKey); err == nil {
+		cached, err := entries.Get(ctx, cacheKey)
+		if err == nil {
 			// Found in cache
 			metrics.IncrCounterWithLabels(metricCacheHits, 1, labels)
 			return cached, nil
 		}
+		if !errors.Is(err, store.NotFound{}) {
+			// E.g. Redis is unavailable: serve the call uncached.
+			metrics.IncrCounterWithLabels(metricCacheErrors, 1, labels)
+			logging.GetLogger().Warn("Failed to read cache", "error", err, "tool", toolName)
+		}
 		// Not found in cache
 		metrics.IncrCounterWithLabels(metricCacheMisses, 1, labels)
 	} else {
@@ -215,14 +228,14 @@ func (m *CachingMiddleware) Execute(ctx context.Context, req *tool.ExecutionRequ
 
 	// Check CacheControl
 	if cacheControl.Action == tool.ActionDeleteCache {
-		if err := m.cache.Delete(ctx, cacheKey); err != nil {
+		if err := entries.Delete(ctx, cacheKey); err != nil {
 			metrics.IncrCounterWithLabels(metricCacheErrors, 1, labels)
 			logging.GetLogger().Error("Failed to delete cache", "error", err, "tool", toolName)
 		}
 		return result, nil
 	}
 
-	if err := m.cache.Set(ctx, cacheKey, result, store.WithExpiration(cacheConfig.GetTtl().AsDuration())); err != nil {
+	if err := entries.Set(ctx, cacheKey, result, store.WithExpiration(cacheConfig.GetTtl().AsDuration()), store.WithTags([]string{req.ToolName})); err != nil {
 		metrics.IncrCounterWithLabels(metricCacheErrors, 1, labels)
 		logging.GetLogger().Error("Failed to set cache", "error", err, "tool", toolName)
 	}
@@ -414,6 +427,55 @@ func (m *CachingMiddleware) getCacheKey(req *tool.ExecutionRequest) string {
 		normalizedInputs = req.ToolInputs
 	}
 
+	return m.hashCacheKey(req.ToolName, normalizedInputs)
+}
+
+// buildCacheKey returns the cache key of the request. With key_fields, the
+// key is built from the selected input fields only.
+func (m *CachingMiddleware) buildCacheKey(req *tool.ExecutionRequest, config *configv1.CacheConfig) string {
+	fields := config.GetKeyFields()
+	if len(fields) == 0 {
+		return m.getCacheKey(req)
+	}
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+	args := req.Arguments
+	if args == nil && len(req.ToolInputs) > 0 {
+		if err := json.Unmarshal(req.ToolInputs, &args); err != nil {
+			// Not an object: key on all inputs rather than on none.
+			return m.getCacheKey(req)
+		}
+	}
+	selected := make(map[string]any, len(fields))
+	for _, field := range fields {
+		if value, ok := lookupField(args, field); ok {
+			selected[field] = value
+		}
+	}
+	marshaled, err := json.Marshal(selected)
+	if err != nil {
+		return m.getCacheKey(req)
+	}
+	return m.hashCacheKey(req.ToolName, marshaled)
+}
+
+// lookupField returns the value at the dot-separated path of the arguments.
+func lookupField(args map[string]any, path string) (any, bool) {
+	var value any = args
+	for _, name := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[name]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// hashCacheKey returns the key "toolName:hash" of the normalized inputs.
+func (m *CachingMiddleware) hashCacheKey(toolName string, normalizedInputs []byte) string {
 	// Optimization: Hash the normalized inputs to keep the cache key short and fixed length.
 	// This avoids using potentially large JSON strings as map keys.
 	// We use FNV-1a 128-bit hash which is significantly faster than SHA256 (>2x)
@@ -432,8 +494,8 @@ func (m *CachingMiddleware) getCacheKey(req *tool.ExecutionRequest) string {
 	// and use stack buffer for hex encoding.
 	var sb strings.Builder
 	// 32 is hex encoded length of 16 bytes
-	sb.Grow(len(req.ToolName) + 1 + 32)
-	sb.WriteString(req.ToolName)
+	sb.Grow(len(toolName) + 1 + 32)
+	sb.WriteString(toolName)
 	sb.WriteByte(':')
 
 	var hexBuf [32]byte
@@ -457,5 +519,141 @@ func (m *CachingMiddleware) getCacheKey(req *tool.ExecutionRequest) string {
 // Side Effects:
 //   - None
 func (m *CachingMiddleware) Clear(ctx context.Context) error {
-	return m.cache.Clear(ctx)
+	return m.getCache().Clear(ctx)
+}
+
+// InvalidateTool removes the cached results of a tool.
+//
+// Summary: Invalidates the cache entries of a tool.
+//
+// Parameters:
+//   - ctx: context.Context. The context for the request.
+//   - toolName: string. The name of the tool, as called by the clients.
+//
+// Returns:
+//   - error: An error if the store cannot be updated.
+func (m *CachingMiddleware) InvalidateTool(ctx context.Context, toolName string) error {
+	return m.getCache().Invalidate(ctx, store.WithInvalidateTags([]string{toolName}))
+}
+
+// InvalidateCall removes the cached result of a tool call. With key_fields,
+// the results of the calls with the same selected fields are removed too.
+//
+// Summary: Invalidates the cache entry of a tool call.
+//
+// Parameters:
+//   - ctx: context.Context. The context for the request.
+//   - toolName: string. The name of the tool, as called by the clients.
+//   - arguments: map[string]any. The arguments of the call.
+//
+// Returns:
+//   - error: An error if the tool is unknown or the store cannot be updated.
+func (m *CachingMiddleware) InvalidateCall(ctx context.Context, toolName string, arguments map[string]any) error {
+	t, ok := m.toolManager.GetTool(toolName)
+	if !ok {
+		return fmt.Errorf("tool %q not found", toolName)
+	}
+	req := &tool.ExecutionRequest{ToolName: toolName, Arguments: arguments}
+	return m.getCache().Delete(ctx, m.buildCacheKey(req, m.getCacheConfig(t)))
+}
+
+// Configure selects the store of the cached results. The entries of the
+// previous store are dropped, unless the configuration is unchanged.
+//
+// Summary: Applies the response cache settings.
+//
+// Parameters:
+//   - config: *configv1.ResponseCacheConfig. The settings; nil selects the
+//     default store.
+//
+// Returns:
+//   - error: An error if the Redis address is missing.
+//
+// Side Effects:
+//   - Creates a Redis client, and closes the client of the previous store.
+func (m *CachingMiddleware) Configure(config *configv1.ResponseCacheConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if proto.Equal(m.config, config) {
+		return nil
+	}
+	return m.configureLocked(config)
+}
+
+// SetSharedRedis stores the cached results in the Redis shared by the
+// replicas when the configuration has no Redis of its own, instead of in
+// memory. Passing nil stores them in memory again.
+//
+// Summary: Shares the response cache through Redis.
+//
+// Parameters:
+//   - client: *redis.Client. The shared client, which stays owned by the
+//     caller, or nil.
+//
+// Side Effects:
+//   - Drops the entries of the previous store if the store changes.
+func (m *CachingMiddleware) SetSharedRedis(client *redis.Client) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sharedRedis = client
+	if m.config.GetRedis() == nil {
+		// The configuration was valid when applied, so it still is.
+		_ = m.configureLocked(m.config)
+	}
+}
+
+// configureLocked replaces the store. m.mu must be held.
+func (m *CachingMiddleware) configureLocked(config *configv1.ResponseCacheConfig) error {
+	var (
+		entries *cache.Cache[any]
+		client  *redis.Client
+	)
+	switch redisConfig := config.GetRedis(); {
+	case redisConfig != nil:
+		if redisConfig.GetAddress() == "" {
+			return fmt.Errorf("redis address is missing")
+		}
+		client = redisClientCreator(&redis.Options{
+			Addr:     redisConfig.GetAddress(),
+			Password: redisConfig.GetPassword(),
+			DB:       int(redisConfig.GetDb()),
+		})
+		entries = cache.New[any](newRedisStore(client, config.GetKeyPrefix()))
+	case m.sharedRedis != nil:
+		entries = cache.New[any](newRedisStore(m.sharedRedis, config.GetKeyPrefix()))
+	default:
+		entries = cache.New[any](newLRUStore(int(config.GetMaxEntries())))
+	}
+
+	if m.redisClient != nil {
+		_ = m.redisClient.Close()
+	}
+	m.cache, m.config, m.redisClient = entries, config, client
+	return nil
+}
+
+// Close closes the Redis client of the store, if any.
+//
+// Summary: Releases the connections of the response cache.
+//
+// Returns:
+//   - error: An error if the client cannot be closed.
+func (m *CachingMiddleware) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.redisClient == nil {
+		return nil
+	}
+	err := m.redisClient.Close()
+	m.redisClient = nil
+	return err
+}
+
+func (m *CachingMiddleware) getCache() *cache.Cache[any] {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.cache
 }
@@ -0,0 +1,178 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/eko/gocache/lib/v4/store"
+	"github.com/mcpany/core/server/pkg/metrics"
+)
+
+const (
+	// defaultCacheMaxEntries bounds the in-memory cache when max_entries is
+	// not set.
+	defaultCacheMaxEntries = 10000
+	// defaultCacheTTL is the TTL of the entries of the tools without one.
+	defaultCacheTTL = 5 * time.Minute
+)
+
+var metricCacheEvictions = []string{"cache", "evictions"}
+
+// lruStore is a size-bounded in-memory cache store that evicts the least
+// recently used entries first.
+type lruStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    *list.List // of *lruEntry, most recently used first
+	items      map[string]*list.Element
+	tags       map[string]map[string]struct{}
+	now        func() time.Time
+}
+
+type lruEntry struct {
+	key       string
+	value     any
+	expiresAt time.Time
+	tags      []string
+}
+
+// newLRUStore creates an in-memory store holding at most maxEntries entries,
+// or defaultCacheMaxEntries if maxEntries is not positive.
+func newLRUStore(maxEntries int) *lruStore {
+	if maxEntries <= 0 {
+		maxEntries = defaultCacheMaxEntries
+	}
+	return &lruStore{
+		maxEntries: maxEntries,
+		entries:    list.New(),
+		items:      make(map[string]*list.Element),
+		tags:       make(map[string]map[string]struct{}),
+		now:        time.Now,
+	}
+}
+
+// Get returns the value of the key and marks it as recently used.
+func (s *lruStore) Get(ctx context.Context, key any) (any, error) {
+	value, _, err := s.GetWithTTL(ctx, key)
+	return value, err
+}
+
+// GetWithTTL returns the value of the key and its remaining TTL.
+func (s *lruStore) GetWithTTL(_ context.Context, key any) (any, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.items[fmt.Sprint(key)]
+	if !ok {
+		return nil, 0, store.NotFoundWithCause(fmt.Errorf("key %v not found", key))
+	}
+	entry := el.Value.(*lruEntry)
+	ttl := entry.expiresAt.Sub(s.now())
+	if ttl <= 0 {
+		s.remove(el)
+		return nil, 0, store.NotFoundWithCause(fmt.Errorf("key %v expired", key))
+	}
+	s.entries.MoveToFront(el)
+	return entry.value, ttl, nil
+}
+
+// Set stores the value with the expiration and tags of the options, and
+// evicts the least recently used entries above the size limit.
+func (s *lruStore) Set(_ context.Context, key any, value any, options ...store.Option) error {
+	opts := store.ApplyOptions(options...)
+	ttl := opts.Expiration
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+	k := fmt.Sprint(key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.items[k]; ok {
+		s.remove(el)
+	}
+	entry := &lruEntry{key: k, value: value, expiresAt: s.now().Add(ttl), tags: opts.Tags}
+	s.items[k] = s.entries.PushFront(entry)
+	for _, tag := range opts.Tags {
+		keys, ok := s.tags[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			s.tags[tag] = keys
+		}
+		keys[k] = struct{}{}
+	}
+
+	var evicted int
+	for s.entries.Len() > s.maxEntries {
+		s.remove(s.entries.Back())
+		evicted++
+	}
+	if evicted > 0 {
+		metrics.IncrCounter(metricCacheEvictions, float32(evicted))
+	}
+	return nil
+}
+
+// Delete removes the key.
+func (s *lruStore) Delete(_ context.Context, key any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.items[fmt.Sprint(key)]; ok {
+		s.remove(el)
+	}
+	return nil
+}
+
+// Invalidate removes the entries with any of the tags of the options.
+func (s *lruStore) Invalidate(_ context.Context, options ...store.InvalidateOption) error {
+	opts := store.ApplyInvalidateOptions(options...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, tag := range opts.Tags {
+		for k := range s.tags[tag] {
+			if el, ok := s.items[k]; ok {
+				s.remove(el)
+			}
+		}
+	}
+	return nil
+}
+
+// Clear removes all entries.
+func (s *lruStore) Clear(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries.Init()
+	s.items = make(map[string]*list.Element)
+	s.tags = make(map[string]map[string]struct{})
+	return nil
+}
+
+// GetType returns the type of the store.
+func (s *lruStore) GetType() string {
+	return "lru"
+}
+
+// remove deletes an entry and its tag references. s.mu must be held.
+func (s *lruStore) remove(el *list.Element) {
+	entry := s.entries.Remove(el).(*lruEntry)
+	delete(s.items, entry.key)
+	for _, tag := range entry.tags {
+		keys := s.tags[tag]
+		delete(keys, entry.key)
+		if len(keys) == 0 {
+			delete(s.tags, tag)
+		}
+	}
+}
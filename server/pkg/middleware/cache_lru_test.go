@@ -0,0 +1,73 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/eko/gocache/lib/v4/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	s := newLRUStore(2)
+
+	require.NoError(t, s.Set(ctx, "a", 1))
+	require.NoError(t, s.Set(ctx, "b", 2))
+	_, err := s.Get(ctx, "a") // "b" is now the least recently used.
+	require.NoError(t, err)
+	require.NoError(t, s.Set(ctx, "c", 3))
+
+	_, err = s.Get(ctx, "b")
+	assert.ErrorIs(t, err, store.NotFound{})
+	v, err := s.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+	v, err = s.Get(ctx, "c")
+	require.NoError(t, err)
+	assert.Equal(t, 3, v)
+}
+
+func TestLRUStore_Expiration(t *testing.T) {
+	ctx := context.Background()
+	s := newLRUStore(0)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	require.NoError(t, s.Set(ctx, "short", "v", store.WithExpiration(time.Second)))
+	require.NoError(t, s.Set(ctx, "default", "v"))
+	_, ttl, err := s.GetWithTTL(ctx, "default")
+	require.NoError(t, err)
+	assert.Equal(t, defaultCacheTTL, ttl)
+
+	now = now.Add(2 * time.Second)
+	_, err = s.Get(ctx, "short")
+	assert.ErrorIs(t, err, store.NotFound{})
+	assert.Len(t, s.items, 1, "the expired entry is removed")
+}
+
+func TestLRUStore_InvalidateTags(t *testing.T) {
+	ctx := context.Background()
+	s := newLRUStore(0)
+	require.NoError(t, s.Set(ctx, "weather:1", 1, store.WithTags([]string{"weather"})))
+	require.NoError(t, s.Set(ctx, "weather:2", 2, store.WithTags([]string{"weather"})))
+	require.NoError(t, s.Set(ctx, "news:1", 3, store.WithTags([]string{"news"})))
+
+	require.NoError(t, s.Invalidate(ctx, store.WithInvalidateTags([]string{"weather"})))
+	_, err := s.Get(ctx, "weather:1")
+	assert.ErrorIs(t, err, store.NotFound{})
+	_, err = s.Get(ctx, "weather:2")
+	assert.ErrorIs(t, err, store.NotFound{})
+	_, err = s.Get(ctx, "news:1")
+	assert.NoError(t, err)
+	assert.NotContains(t, s.tags, "weather")
+
+	require.NoError(t, s.Clear(ctx))
+	_, err = s.Get(ctx, "news:1")
+	assert.ErrorIs(t, err, store.NotFound{})
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/eko/gocache/lib/v4/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultCacheKeyPrefix is the prefix of the Redis keys of the cache when
+// key_prefix is not set.
+const defaultCacheKeyPrefix = "mcpany:cache:"
+
+// redisStore is a cache store shared by the replicas through Redis. The
+// values are stored as JSON; *mcp.CallToolResult values are decoded back to
+// their type, and other values to their generic JSON form.
+type redisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// cachedValue is the JSON envelope of a value in Redis.
+type cachedValue struct {
+	// CallToolResult is set if the value is an *mcp.CallToolResult.
+	CallToolResult bool            `json:"call_tool_result,omitempty"`
+	Value          json.RawMessage `json:"value"`
+}
+
+// newRedisStore creates a store keeping its entries under the prefix, or
+// defaultCacheKeyPrefix if the prefix is empty.
+func newRedisStore(client *redis.Client, prefix string) *redisStore {
+	if prefix == "" {
+		prefix = defaultCacheKeyPrefix
+	}
+	return &redisStore{client: client, prefix: prefix}
+}
+
+// Get returns the value of the key.
+func (s *redisStore) Get(ctx context.Context, key any) (any, error) {
+	data, err := s.client.Get(ctx, s.key(key)).Bytes()
+	if err != nil {
+		return nil, s.notFound(err)
+	}
+	return decodeCachedValue(data)
+}
+
+// GetWithTTL returns the value of the key and its remaining TTL.
+func (s *redisStore) GetWithTTL(ctx context.Context, key any) (any, time.Duration, error) {
+	value, err := s.Get(ctx, key)
+	if err != nil {
+		return nil, 0, err
+	}
+	ttl, err := s.client.PTTL(ctx, s.key(key)).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+	return value, ttl, nil
+}
+
+// Set stores the value with the expiration of the options, and adds the key
+// to the set of each tag.
+func (s *redisStore) Set(ctx context.Context, key any, value any, options ...store.Option) error {
+	opts := store.ApplyOptions(options...)
+	ttl := opts.Expiration
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+	data, err := encodeCachedValue(value)
+	if err != nil {
+		return err
+	}
+
+	k := s.key(key)
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, k, data, ttl)
+		for _, tag := range opts.Tags {
+			pipe.SAdd(ctx, s.tagKey(tag), k)
+			pipe.Expire(ctx, s.tagKey(tag), ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+// Delete removes the key.
+func (s *redisStore) Delete(ctx context.Context, key any) error {
+	return s.client.Del(ctx, s.key(key)).Err()
+}
+
+// Invalidate removes the entries with any of the tags of the options.
+func (s *redisStore) Invalidate(ctx context.Context, options ...store.InvalidateOption) error {
+	opts := store.ApplyInvalidateOptions(options...)
+	for _, tag := range opts.Tags {
+		keys, err := s.client.SMembers(ctx, s.tagKey(tag)).Result()
+		if err != nil {
+			return err
+		}
+		if err := s.client.Del(ctx, append(keys, s.tagKey(tag))...).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Clear removes the keys under the prefix. Other keys of the database are
+// kept.
+func (s *redisStore) Clear(ctx context.Context) error {
+	iter := s.client.Scan(ctx, 0, s.prefix+"*", 100).Iterator()
+	var keys []string
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == 100 {
+			if err := s.client.Del(ctx, keys...).Err(); err != nil {
+				return err
+			}
+			keys = keys[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		return s.client.Del(ctx, keys...).Err()
+	}
+	return nil
+}
+
+// GetType returns the type of the store.
+func (s *redisStore) GetType() string {
+	return "redis"
+}
+
+func (s *redisStore) key(key any) string {
+	return s.prefix + fmt.Sprint(key)
+}
+
+// tagKey returns the key of the set of the keys with the tag. Cache keys
+// have the form "tool:hash", so they never start with "tags:".
+func (s *redisStore) tagKey(tag string) string {
+	return s.prefix + "tags:" + tag
+}
+
+func (s *redisStore) notFound(err error) error {
+	if errors.Is(err, redis.Nil) {
+		return store.NotFoundWithCause(err)
+	}
+	return err
+}
+
+func encodeCachedValue(value any) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cached value: %w", err)
+	}
+	_, isResult := value.(*mcp.CallToolResult)
+	return json.Marshal(cachedValue{CallToolResult: isResult, Value: data})
+}
+
+func decodeCachedValue(data []byte) (any, error) {
+	var envelope cachedValue
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode cached value: %w", err)
+	}
+	if envelope.CallToolResult {
+		result := &mcp.CallToolResult{}
+		if err := json.Unmarshal(envelope.Value, result); err != nil {
+			return nil, fmt.Errorf("failed to decode cached value: %w", err)
+		}
+		return result, nil
+	}
+	var value any
+	if err := json.Unmarshal(envelope.Value, &value); err != nil {
+		return nil, fmt.Errorf("failed to decode cached value: %w", err)
+	}
+	return value, nil
+}
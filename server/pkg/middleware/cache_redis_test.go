@@ -0,0 +1,79 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/eko/gocache/lib/v4/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisStore(t *testing.T) (*redisStore, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return newRedisStore(client, ""), mr
+}
+
+func TestRedisStore_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	s, mr := newTestRedisStore(t)
+
+	result := &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "sunny"}}}
+	require.NoError(t, s.Set(ctx, "weather:1", result, store.WithExpiration(time.Minute)))
+	require.NoError(t, s.Set(ctx, "weather:2", map[string]any{"temp": 21}))
+
+	v, ttl, err := s.GetWithTTL(ctx, "weather:1")
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, ttl)
+	require.IsType(t, &mcp.CallToolResult{}, v)
+	assert.Equal(t, "sunny", v.(*mcp.CallToolResult).Content[0].(*mcp.TextContent).Text)
+
+	v, err = s.Get(ctx, "weather:2")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"temp": float64(21)}, v)
+	assert.Equal(t, defaultCacheTTL, mr.TTL(defaultCacheKeyPrefix+"weather:2"))
+
+	mr.FastForward(2 * time.Minute)
+	_, err = s.Get(ctx, "weather:1")
+	assert.ErrorIs(t, err, store.NotFound{})
+}
+
+func TestRedisStore_InvalidateAndClear(t *testing.T) {
+	ctx := context.Background()
+	s, mr := newTestRedisStore(t)
+	require.NoError(t, mr.Set("unrelated", "kept"))
+
+	require.NoError(t, s.Set(ctx, "weather:1", "a", store.WithTags([]string{"weather"})))
+	require.NoError(t, s.Set(ctx, "news:1", "b", store.WithTags([]string{"news"})))
+
+	require.NoError(t, s.Invalidate(ctx, store.WithInvalidateTags([]string{"weather"})))
+	_, err := s.Get(ctx, "weather:1")
+	assert.ErrorIs(t, err, store.NotFound{})
+	_, err = s.Get(ctx, "news:1")
+	assert.NoError(t, err)
+
+	require.NoError(t, s.Clear(ctx))
+	_, err = s.Get(ctx, "news:1")
+	assert.ErrorIs(t, err, store.NotFound{})
+	assert.False(t, mr.Exists(defaultCacheKeyPrefix+"tags:news"))
+	assert.True(t, mr.Exists("unrelated"), "keys outside the prefix are kept")
+}
+
+func TestRedisStore_Unavailable(t *testing.T) {
+	s, mr := newTestRedisStore(t)
+	mr.Close()
+
+	_, err := s.Get(context.Background(), "weather:1")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, store.NotFound{})
+}
@@ -8,11 +8,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
+	"github.com/mcpany/core/proto/bus"
 	configv1 "github.com/mcpany/core/proto/config/v1"
 	v1 "github.com/mcpany/core/proto/mcp_router/v1"
 	"github.com/mcpany/core/server/pkg/middleware"
 	"github.com/mcpany/core/server/pkg/tool"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
@@ -609,3 +612,113 @@ func (m *mockToolManager) GetAllowedServiceIDs(_ string) (map[string]bool, bool)
 func (m *mockToolManager) GetToolCountForService(serviceID string) int {
 	return 0
 }
+
+func TestCachingMiddleware_KeyFields(t *testing.T) {
+	cacheMiddleware := middleware.NewCachingMiddleware(&mockToolManager{})
+	testTool := &mockTool{
+		tool: v1.Tool_builder{
+			Name:      proto.String(testToolName),
+			ServiceId: proto.String(testServiceName),
+		}.Build(),
+		cacheConfig: configv1.CacheConfig_builder{
+			IsEnabled: proto.Bool(true),
+			Ttl:       durationpb.New(time.Minute),
+			KeyFields: []string{"location.city"},
+		}.Build(),
+	}
+	ctx := tool.NewContextWithTool(context.Background(), testTool)
+	nextFunc := func(ctx context.Context, req *tool.ExecutionRequest) (any, error) {
+		return testTool.Execute(ctx, req)
+	}
+	call := func(inputs string) {
+		_, err := cacheMiddleware.Execute(ctx, &tool.ExecutionRequest{ToolName: testServiceToolName, ToolInputs: []byte(inputs)}, nextFunc)
+		require.NoError(t, err)
+	}
+
+	call(`{"location": {"city": "Paris"}, "request_id": "1"}`)
+	call(`{"location": {"city": "Paris"}, "request_id": "2"}`)
+	assert.Equal(t, 1, testTool.executeCount, "fields outside key_fields do not change the key")
+
+	call(`{"location": {"city": "Berlin"}, "request_id": "1"}`)
+	assert.Equal(t, 2, testTool.executeCount, "a different key field is a different entry")
+}
+
+func TestCachingMiddleware_InvalidateTool(t *testing.T) {
+	cacheMiddleware := middleware.NewCachingMiddleware(&mockToolManager{})
+	testTool := &mockTool{
+		tool: v1.Tool_builder{
+			Name:      proto.String(testToolName),
+			ServiceId: proto.String(testServiceName),
+		}.Build(),
+		cacheConfig: configv1.CacheConfig_builder{
+			IsEnabled: proto.Bool(true),
+			Ttl:       durationpb.New(time.Minute),
+		}.Build(),
+	}
+	ctx := tool.NewContextWithTool(context.Background(), testTool)
+	nextFunc := func(ctx context.Context, req *tool.ExecutionRequest) (any, error) {
+		return testTool.Execute(ctx, req)
+	}
+	req := &tool.ExecutionRequest{ToolName: testServiceToolName, ToolInputs: []byte(`{"a": 1}`)}
+
+	_, err := cacheMiddleware.Execute(ctx, req, nextFunc)
+	require.NoError(t, err)
+	require.NoError(t, cacheMiddleware.InvalidateTool(ctx, "other-tool"))
+	_, err = cacheMiddleware.Execute(ctx, req, nextFunc)
+	require.NoError(t, err)
+	assert.Equal(t, 1, testTool.executeCount)
+
+	require.NoError(t, cacheMiddleware.InvalidateTool(ctx, testServiceToolName))
+	_, err = cacheMiddleware.Execute(ctx, req, nextFunc)
+	require.NoError(t, err)
+	assert.Equal(t, 2, testTool.executeCount)
+}
+
+func TestCachingMiddleware_Configure(t *testing.T) {
+	cacheMiddleware := middleware.NewCachingMiddleware(&mockToolManager{})
+	assert.ErrorContains(t, cacheMiddleware.Configure(configv1.ResponseCacheConfig_builder{
+		Redis: &bus.RedisBus{},
+	}.Build()), "redis address is missing")
+	require.NoError(t, cacheMiddleware.Configure(configv1.ResponseCacheConfig_builder{
+		MaxEntries: proto.Int32(10),
+	}.Build()))
+}
+
+func TestCachingMiddleware_SharedRedis(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	testTool := &mockTool{
+		tool: v1.Tool_builder{
+			Name:      proto.String(testToolName),
+			ServiceId: proto.String(testServiceName),
+		}.Build(),
+		cacheConfig: configv1.CacheConfig_builder{
+			IsEnabled: proto.Bool(true),
+			Ttl:       durationpb.New(time.Minute),
+		}.Build(),
+	}
+	ctx := tool.NewContextWithTool(context.Background(), testTool)
+	nextFunc := func(ctx context.Context, req *tool.ExecutionRequest) (any, error) {
+		return testTool.Execute(ctx, req)
+	}
+	req := &tool.ExecutionRequest{ToolName: testServiceToolName, ToolInputs: []byte(`{"a": 1}`)}
+
+	// Two replicas share the entries.
+	a := middleware.NewCachingMiddleware(&mockToolManager{})
+	b := middleware.NewCachingMiddleware(&mockToolManager{})
+	a.SetSharedRedis(client)
+	b.SetSharedRedis(client)
+	require.NoError(t, b.Configure(configv1.ResponseCacheConfig_builder{MaxEntries: proto.Int32(10)}.Build()))
+
+	_, err := a.Execute(ctx, req, nextFunc)
+	require.NoError(t, err)
+	_, err = b.Execute(ctx, req, nextFunc)
+	require.NoError(t, err)
+	assert.Equal(t, 1, testTool.executeCount)
+
+	// The shared client is not closed with the cache.
+	require.NoError(t, a.Close())
+	assert.NoError(t, client.Ping(ctx).Err())
+}
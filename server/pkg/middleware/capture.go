@@ -0,0 +1,161 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/capture"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/mcpany/core/server/pkg/util"
+	"github.com/mcpany/core/server/pkg/validation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CaptureMiddleware records tool calls, with their sensitive fields redacted
+// like the logs and by the DLP settings, for replay with `mcpctl replay`.
+//
+// Summary: Middleware that captures tool calls for debugging.
+type CaptureMiddleware struct {
+	config   *configv1.CaptureConfig
+	store    *capture.Store
+	redactor *Redactor
+}
+
+// NewCaptureMiddleware creates a new CaptureMiddleware.
+//
+// Summary: Initializes the capture middleware and its capture directory.
+//
+// Parameters:
+//   - config: *configv1.CaptureConfig. The capture configuration.
+//   - dlp: *configv1.DLPConfig. The DLP configuration also redacting the captures, or nil.
+//
+// Returns:
+//   - *CaptureMiddleware: The middleware.
+//   - error: An error if the capture directory is not allowed or cannot be created.
+//
+// Side Effects:
+//   - Creates the capture directory.
+func NewCaptureMiddleware(config *configv1.CaptureConfig, dlp *configv1.DLPConfig) (*CaptureMiddleware, error) {
+	dir := config.GetDir()
+	if dir == "" {
+		dir = capture.DefaultDir
+	}
+	if err := validation.IsAllowedPath(dir); err != nil {
+		return nil, fmt.Errorf("capture dir is not allowed: %w", err)
+	}
+	store, err := capture.NewStore(dir, int(config.GetMaxCaptures()))
+	if err != nil {
+		return nil, err
+	}
+	return &CaptureMiddleware{
+		config:   config,
+		store:    store,
+		redactor: NewRedactor(dlp, logging.GetLogger()),
+	}, nil
+}
+
+// Execute captures the tool call.
+//
+// Summary: Wraps tool execution to record the call and its outcome.
+//
+// Parameters:
+//   - ctx: context.Context. The execution context.
+//   - req: *tool.ExecutionRequest. The request containing tool execution details.
+//   - next: tool.ExecutionFunc. The next handler in the execution chain.
+//
+// Returns:
+//   - any: The result of the tool execution.
+//   - error: An error if the execution fails.
+//
+// Side Effects:
+//   - Writes a capture file. A capture that cannot be written is logged, and
+//     does not fail the call.
+func (m *CaptureMiddleware) Execute(ctx context.Context, req *tool.ExecutionRequest, next tool.ExecutionFunc) (any, error) {
+	if !m.captures(req.ToolName) {
+		return next(ctx, req)
+	}
+
+	start := time.Now()
+	result, err := next(ctx, req)
+	if m.config.GetErrorsOnly() && classifyError(result, err) == errorTypeNone {
+		return result, err
+	}
+
+	c := &capture.Capture{
+		ID:         capture.NewID(),
+		Timestamp:  start,
+		ToolName:   req.ToolName,
+		TraceID:    GetTraceID(ctx),
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if sc := trace.SpanContextFromContext(ctx); c.TraceID == "" && sc.IsValid() {
+		c.TraceID = sc.TraceID().String()
+	}
+	if t, ok := tool.GetFromContext(ctx); ok && t.Tool() != nil {
+		c.ServiceID = t.Tool().GetServiceId()
+	}
+	if userID, ok := auth.UserFromContext(ctx); ok {
+		c.UserID = userID
+	}
+	if profileID, ok := auth.ProfileIDFromContext(ctx); ok {
+		c.ProfileID = profileID
+	}
+
+	redactor := m.redactor.ForTool(req.ToolName)
+	if len(req.ToolInputs) > 0 {
+		c.Arguments = redactCaptured(redactor, req.ToolInputs)
+	}
+	if err != nil {
+		c.Error = err.Error()
+	}
+	if result != nil {
+		if data, marshalErr := json.Marshal(result); marshalErr == nil {
+			c.Result = redactCaptured(redactor, data)
+		}
+	}
+
+	log := logging.GetLogger()
+	if saveErr := m.store.Save(c); saveErr != nil {
+		log.Warn("Failed to capture tool call", "tool", req.ToolName, "error", saveErr)
+	} else {
+		log.Info("Captured tool call", "tool", req.ToolName, "capture_id", c.ID)
+	}
+	return result, err
+}
+
+// captures reports whether calls of a tool are captured.
+func (m *CaptureMiddleware) captures(toolName string) bool {
+	patterns := m.config.GetTools()
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, toolName); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// redactCaptured redacts the sensitive keys of JSON data, then applies the
+// DLP redactor if DLP is enabled. Data that is not valid JSON is dropped
+// rather than stored unredacted.
+func redactCaptured(redactor *Redactor, data []byte) json.RawMessage {
+	if !json.Valid(data) {
+		return nil
+	}
+	redacted, err := redactor.RedactJSON(util.RedactJSON(data))
+	if err != nil {
+		return nil
+	}
+	return json.RawMessage(redacted)
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/capture"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/mcpany/core/server/pkg/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+var testCaptureDLP = configv1.DLPConfig_builder{
+	Enabled:        proto.Bool(true),
+	CustomPatterns: []string{`secret-\d+`},
+}.Build()
+
+func newTestCaptureMiddleware(t *testing.T, config *configv1.CaptureConfig, dlp *configv1.DLPConfig) (*CaptureMiddleware, *capture.Store) {
+	t.Helper()
+	dir := t.TempDir()
+	validation.SetAllowedPaths([]string{dir})
+	t.Cleanup(func() { validation.SetAllowedPaths(nil) })
+	config.SetDir(dir)
+	m, err := NewCaptureMiddleware(config, dlp)
+	require.NoError(t, err)
+	store, err := capture.NewStore(dir, 0)
+	require.NoError(t, err)
+	return m, store
+}
+
+func TestCaptureMiddleware(t *testing.T) {
+	m, store := newTestCaptureMiddleware(t, configv1.CaptureConfig_builder{Enabled: proto.Bool(true)}.Build(), testCaptureDLP)
+
+	req := &tool.ExecutionRequest{ToolName: "weather.get", ToolInputs: json.RawMessage(`{"city":"Paris","query":"secret-123","token":"abc"}`)}
+	result, err := m.Execute(context.Background(), req, func(_ context.Context, _ *tool.ExecutionRequest) (any, error) {
+		return map[string]any{"temp": 21, "note": "secret-456"}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"temp": 21, "note": "secret-456"}, result, "the result is returned unredacted")
+
+	captures, err := store.List(0)
+	require.NoError(t, err)
+	require.Len(t, captures, 1)
+	c := captures[0]
+	assert.Equal(t, "weather.get", c.ToolName)
+	assert.JSONEq(t, `{"city":"Paris","query":"***REDACTED***","token":"[REDACTED]"}`, string(c.Arguments))
+	assert.JSONEq(t, `{"temp":21,"note":"***REDACTED***"}`, string(c.Result))
+	assert.Empty(t, c.Error)
+}
+
+func TestCaptureMiddleware_WithoutDLP(t *testing.T) {
+	m, store := newTestCaptureMiddleware(t, configv1.CaptureConfig_builder{Enabled: proto.Bool(true)}.Build(), nil)
+
+	req := &tool.ExecutionRequest{ToolName: "github.login", ToolInputs: json.RawMessage(`{"user":"octocat","password":"hunter2"}`)}
+	_, err := m.Execute(context.Background(), req, func(_ context.Context, _ *tool.ExecutionRequest) (any, error) {
+		return map[string]any{"api_key": "sk-live-123", "expires": 3600}, nil
+	})
+	require.NoError(t, err)
+
+	captures, err := store.List(0)
+	require.NoError(t, err)
+	require.Len(t, captures, 1)
+	assert.JSONEq(t, `{"user":"octocat","password":"[REDACTED]"}`, string(captures[0].Arguments))
+	assert.JSONEq(t, `{"api_key":"[REDACTED]","expires":3600}`, string(captures[0].Result))
+}
+
+func TestCaptureMiddleware_Filters(t *testing.T) {
+	m, store := newTestCaptureMiddleware(t, configv1.CaptureConfig_builder{
+		Enabled:    proto.Bool(true),
+		Tools:      []string{"weather.*"},
+		ErrorsOnly: proto.Bool(true),
+	}.Build(), testCaptureDLP)
+
+	ok := func(_ context.Context, _ *tool.ExecutionRequest) (any, error) { return "ok", nil }
+	fail := func(_ context.Context, _ *tool.ExecutionRequest) (any, error) {
+		return nil, errors.New("upstream returned 502")
+	}
+
+	_, _ = m.Execute(context.Background(), &tool.ExecutionRequest{ToolName: "weather.get"}, ok)
+	_, _ = m.Execute(context.Background(), &tool.ExecutionRequest{ToolName: "stocks.get"}, fail)
+	_, err := m.Execute(context.Background(), &tool.ExecutionRequest{ToolName: "weather.get"}, fail)
+	assert.EqualError(t, err, "upstream returned 502")
+
+	captures, err := store.List(0)
+	require.NoError(t, err)
+	require.Len(t, captures, 1)
+	assert.Equal(t, "weather.get", captures[0].ToolName)
+	assert.Equal(t, "upstream returned 502", captures[0].Error)
+}
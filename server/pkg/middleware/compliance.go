@@ -190,6 +190,17 @@ func (w *smartResponseWriter) Flush() {
 	}
 }
 
+// Unwrap returns the underlying http.ResponseWriter.
+//
+// Summary: Exposes the wrapped writer so that http.ResponseController and
+// WebSocket upgrades can reach the Hijacker implementation.
+//
+// Returns:
+//   - http.ResponseWriter: The wrapped writer.
+func (w *smartResponseWriter) Unwrap() http.ResponseWriter {
+	return w.w
+}
+
 func (w *smartResponseWriter) rewriteError() {
 	bodyStr := strings.TrimSpace(w.body.String())
 
@@ -0,0 +1,289 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"sync"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/metrics"
+	"github.com/mcpany/core/server/pkg/tokenizer"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ContextBudgetMetaKey is the result metadata key that reports how much of
+// its context budget the session used.
+const ContextBudgetMetaKey = "contextBudget"
+
+// contextBudgetLogger is the logger name of the notifications sent to clients.
+const contextBudgetLogger = "mcpany.context_budget"
+
+var metricContextBudgetStage = []string{"context_budget", "stage"}
+
+// defaultContextBudgetStages are used if the configuration lists no stages.
+var defaultContextBudgetStages = []contextBudgetStage{
+	{thresholdPercent: 50, notify: true},
+	{thresholdPercent: 80, maxChars: 8000, strategy: configv1.ContextBudgetStage_STRATEGY_HEAD_TAIL, notify: true},
+	{thresholdPercent: 100, maxChars: 2000, strategy: configv1.ContextBudgetStage_STRATEGY_HEAD_TAIL, notify: true},
+}
+
+type contextBudgetStage struct {
+	thresholdPercent int
+	maxChars         int
+	strategy         configv1.ContextBudgetStage_Strategy
+	notify           bool
+}
+
+// sessionBudget is the context budget usage of a session.
+type sessionBudget struct {
+	mu         sync.Mutex
+	usedTokens int64
+	// stage is the index of the last stage reached, -1 if none.
+	stage int
+}
+
+// ContextBudget tracks the estimated tokens of the tool results returned to
+// each session and shapes the results of sessions that used up a share of
+// their budget.
+//
+// Summary: Per-session context size budgeting.
+type ContextBudget struct {
+	maxTokens int64
+	stages    []contextBudgetStage
+	tokenizer tokenizer.Tokenizer
+
+	mu       sync.Mutex
+	sessions map[string]*sessionBudget
+}
+
+// NewContextBudget creates a new ContextBudget.
+//
+// Summary: Initializes a ContextBudget from the configuration.
+//
+// Parameters:
+//   - config: *configv1.ContextBudgetConfig. The context budget configuration.
+//
+// Returns:
+//   - *ContextBudget: The context budget, or nil if it is disabled or has no max_tokens.
+func NewContextBudget(config *configv1.ContextBudgetConfig) *ContextBudget {
+	if !config.GetEnabled() || config.GetMaxTokens() <= 0 {
+		return nil
+	}
+	b := &ContextBudget{
+		maxTokens: config.GetMaxTokens(),
+		tokenizer: tokenizer.NewSimpleTokenizer(),
+		sessions:  make(map[string]*sessionBudget),
+	}
+	for _, s := range config.GetStages() {
+		b.stages = append(b.stages, contextBudgetStage{
+			thresholdPercent: int(s.GetThresholdPercent()),
+			maxChars:         int(s.GetMaxChars()),
+			strategy:         s.GetStrategy(),
+			notify:           s.GetNotify(),
+		})
+	}
+	if len(b.stages) == 0 {
+		b.stages = defaultContextBudgetStages
+	}
+	sort.SliceStable(b.stages, func(i, j int) bool { return b.stages[i].thresholdPercent < b.stages[j].thresholdPercent })
+	return b
+}
+
+// ContextBudgetNotice describes a session that reached a new stage of its
+// context budget.
+type ContextBudgetNotice struct {
+	UsedTokens       int64 `json:"usedTokens"`
+	MaxTokens        int64 `json:"maxTokens"`
+	ThresholdPercent int   `json:"thresholdPercent"`
+	// MaxChars is the limit applied to the text of later results, 0 if none.
+	MaxChars int `json:"maxChars,omitempty"`
+	// Notify is whether the client should be warned.
+	Notify bool `json:"-"`
+}
+
+// Message describes the notice for the client.
+//
+// Returns:
+//   - string: e.g. "This session used 80% of its context budget (160000 of 200000 tokens)...".
+func (n *ContextBudgetNotice) Message() string {
+	msg := fmt.Sprintf("This session used %d%% of its context budget (%d of %d tokens).",
+		n.ThresholdPercent, n.UsedTokens, n.MaxTokens)
+	if n.MaxChars > 0 {
+		msg += fmt.Sprintf(" Text in further tool results is shortened to %d characters.", n.MaxChars)
+	}
+	return msg
+}
+
+// Apply shapes a tool result according to the stage the session is in and
+// adds the size of the result to the usage of the session.
+//
+// Summary: Accounts and shapes a tool result of a session.
+//
+// Parameters:
+//   - sessionID: string. The ID of the session receiving the result.
+//   - result: *mcp.CallToolResult. The result, modified in place.
+//
+// Returns:
+//   - *ContextBudgetNotice: The stage the session reached with this result, or nil if it is still in the same stage.
+func (b *ContextBudget) Apply(sessionID string, result *mcp.CallToolResult) *ContextBudgetNotice {
+	sb, _ := b.session(sessionID)
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	if sb.stage >= 0 {
+		if stage := b.stages[sb.stage]; stage.maxChars > 0 {
+			for _, c := range result.Content {
+				if tc, ok := c.(*mcp.TextContent); ok {
+					tc.Text = shortenText(tc.Text, stage.maxChars, stage.strategy)
+				}
+			}
+		}
+	}
+
+	sb.usedTokens += int64(CalculateToolResultTokens(b.tokenizer, result))
+	percent := int(sb.usedTokens * 100 / b.maxTokens)
+
+	var notice *ContextBudgetNotice
+	reached := sb.stage
+	for i := sb.stage + 1; i < len(b.stages) && b.stages[i].thresholdPercent <= percent; i++ {
+		reached = i
+	}
+	if reached != sb.stage {
+		sb.stage = reached
+		stage := b.stages[reached]
+		notice = &ContextBudgetNotice{
+			UsedTokens:       sb.usedTokens,
+			MaxTokens:        b.maxTokens,
+			ThresholdPercent: stage.thresholdPercent,
+			MaxChars:         stage.maxChars,
+			Notify:           stage.notify,
+		}
+	}
+
+	if result.Meta == nil {
+		result.Meta = mcp.Meta{}
+	}
+	status := map[string]any{
+		"usedTokens": sb.usedTokens,
+		"maxTokens":  b.maxTokens,
+	}
+	if sb.stage >= 0 {
+		status["thresholdPercent"] = b.stages[sb.stage].thresholdPercent
+	}
+	result.Meta[ContextBudgetMetaKey] = status
+	return notice
+}
+
+// session returns the usage of a session and whether it was created.
+func (b *ContextBudget) session(sessionID string) (*sessionBudget, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if sb, ok := b.sessions[sessionID]; ok {
+		return sb, false
+	}
+	sb := &sessionBudget{stage: -1}
+	b.sessions[sessionID] = sb
+	return sb, true
+}
+
+// forget drops the usage of a closed session.
+func (b *ContextBudget) forget(sessionID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.sessions, sessionID)
+}
+
+// shortenText shortens text to maxChars runes with the given strategy.
+func shortenText(text string, maxChars int, strategy configv1.ContextBudgetStage_Strategy) string {
+	runes := []rune(text)
+	if len(runes) <= maxChars {
+		return text
+	}
+	removed := len(runes) - maxChars
+	if strategy == configv1.ContextBudgetStage_STRATEGY_HEAD_TAIL {
+		head := maxChars - maxChars/2
+		return string(runes[:head]) + fmt.Sprintf("\n...[TRUNCATED %d chars]...\n", removed) + string(runes[len(runes)-maxChars/2:])
+	}
+	return string(runes[:maxChars]) + fmt.Sprintf("...[TRUNCATED %d chars]", removed)
+}
+
+// ContextBudgetMiddleware creates a middleware that enforces a context budget
+// for the tool results of each session.
+//
+// Summary: Middleware that protects the context window of the model from runaway tool output.
+//
+// Parameters:
+//   - config: *configv1.ContextBudgetConfig. The context budget configuration.
+//   - log: *slog.Logger. The logger for reporting sessions that reach a stage.
+//
+// Returns:
+//   - mcp.Middleware: The configured middleware function.
+func ContextBudgetMiddleware(config *configv1.ContextBudgetConfig, log *slog.Logger) mcp.Middleware {
+	budget := NewContextBudget(config)
+	if budget == nil {
+		return noOpMiddleware
+	}
+	if log == nil {
+		log = logging.GetLogger()
+	}
+
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			r, ok := req.(*mcp.CallToolRequest)
+			if !ok || r.Params == nil || r.Session == nil || r.Session.ID() == "" {
+				// Without a session ID, calls cannot be attributed to a session.
+				return next(ctx, method, req)
+			}
+			ss := r.Session
+			sessionID := ss.ID()
+			if _, created := budget.session(sessionID); created {
+				go func() {
+					_ = ss.Wait()
+					budget.forget(sessionID)
+				}()
+			}
+
+			result, err := next(ctx, method, req)
+			if err != nil {
+				return result, err
+			}
+			ctr, ok := result.(*mcp.CallToolResult)
+			if !ok {
+				return result, nil
+			}
+			notice := budget.Apply(sessionID, ctr)
+			if notice == nil {
+				return ctr, nil
+			}
+
+			log.WarnContext(ctx, "Session reached a context budget stage",
+				"sessionID", sessionID, "tool", r.Params.Name,
+				"usedTokens", notice.UsedTokens, "maxTokens", notice.MaxTokens, "thresholdPercent", notice.ThresholdPercent)
+			metrics.IncrCounterWithLabels(metricContextBudgetStage, 1, []metrics.Label{
+				{Name: "threshold_percent", Value: strconv.Itoa(notice.ThresholdPercent)},
+			})
+			if notice.Notify {
+				if err := ss.Log(ctx, &mcp.LoggingMessageParams{
+					Level:  "warning",
+					Logger: contextBudgetLogger,
+					Data: map[string]any{
+						"message":          notice.Message(),
+						"usedTokens":       notice.UsedTokens,
+						"maxTokens":        notice.MaxTokens,
+						"thresholdPercent": notice.ThresholdPercent,
+					},
+				}); err != nil {
+					log.DebugContext(ctx, "Failed to notify client about context budget", "sessionID", sessionID, "error", err)
+				}
+			}
+			return ctr, nil
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func textResult(text string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}
+}
+
+func TestContextBudget_Apply(t *testing.T) {
+	assert.Nil(t, NewContextBudget(nil))
+	assert.Nil(t, NewContextBudget(configv1.ContextBudgetConfig_builder{Enabled: proto.Bool(true)}.Build()))
+
+	b := NewContextBudget(configv1.ContextBudgetConfig_builder{
+		Enabled:   proto.Bool(true),
+		MaxTokens: proto.Int64(100),
+		Stages: []*configv1.ContextBudgetStage{
+			configv1.ContextBudgetStage_builder{
+				ThresholdPercent: proto.Int32(90),
+				MaxChars:         proto.Int32(10),
+				Strategy:         configv1.ContextBudgetStage_STRATEGY_HEAD_TAIL.Enum(),
+				Notify:           proto.Bool(true),
+			}.Build(),
+			configv1.ContextBudgetStage_builder{ThresholdPercent: proto.Int32(50), Notify: proto.Bool(true)}.Build(),
+		},
+	}.Build())
+	require.NotNil(t, b)
+
+	// The simple tokenizer counts 4 characters as a token.
+	chunk := strings.Repeat("abcd", 30)
+
+	result := textResult(chunk)
+	assert.Nil(t, b.Apply("s1", result))
+	assert.Equal(t, map[string]any{"usedTokens": int64(30), "maxTokens": int64(100)}, result.Meta[ContextBudgetMetaKey])
+
+	result = textResult(chunk)
+	notice := b.Apply("s1", result)
+	require.NotNil(t, notice)
+	assert.Equal(t, &ContextBudgetNotice{UsedTokens: 60, MaxTokens: 100, ThresholdPercent: 50, Notify: true}, notice)
+	assert.Equal(t, "This session used 50% of its context budget (60 of 100 tokens).", notice.Message())
+	assert.Equal(t, chunk, result.Content[0].(*mcp.TextContent).Text, "text is not shortened at 50%")
+
+	notice = b.Apply("s1", textResult(chunk))
+	require.NotNil(t, notice)
+	assert.Equal(t, 90, notice.ThresholdPercent)
+	assert.Equal(t, "This session used 90% of its context budget (90 of 100 tokens). Text in further tool results is shortened to 10 characters.", notice.Message())
+
+	result = textResult(chunk)
+	assert.Nil(t, b.Apply("s1", result))
+	assert.Equal(t, "abcda\n...[TRUNCATED 110 chars]...\ndabcd", result.Content[0].(*mcp.TextContent).Text)
+	assert.Equal(t, 90, result.Meta[ContextBudgetMetaKey].(map[string]any)["thresholdPercent"])
+
+	// Other sessions have their own budget.
+	result = textResult(chunk)
+	assert.Nil(t, b.Apply("s2", result))
+	assert.Equal(t, chunk, result.Content[0].(*mcp.TextContent).Text)
+
+	b.forget("s1")
+	_, created := b.session("s1")
+	assert.True(t, created)
+}
+
+func TestShortenText(t *testing.T) {
+	assert.Equal(t, "short", shortenText("short", 10, configv1.ContextBudgetStage_STRATEGY_TRUNCATE))
+	assert.Equal(t, "0123...[TRUNCATED 6 chars]", shortenText("0123456789", 4, configv1.ContextBudgetStage_STRATEGY_UNSPECIFIED))
+	assert.Equal(t, "01\n...[TRUNCATED 6 chars]...\n89", shortenText("0123456789", 4, configv1.ContextBudgetStage_STRATEGY_HEAD_TAIL))
+}
+
+func TestContextBudgetMiddleware_WithoutSession(t *testing.T) {
+	cfg := configv1.ContextBudgetConfig_builder{Enabled: proto.Bool(true), MaxTokens: proto.Int64(1)}.Build()
+	handler := ContextBudgetMiddleware(cfg, logging.GetLogger())(func(context.Context, string, mcp.Request) (mcp.Result, error) {
+		return textResult(strings.Repeat("x", 100)), nil
+	})
+	result, err := handler(context.Background(), "tools/call", &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "logs.tail"}})
+	require.NoError(t, err)
+	assert.Nil(t, result.(*mcp.CallToolResult).Meta, "calls without a session are not budgeted")
+}
@@ -237,3 +237,13 @@ func (w *responseBuffer) WriteHeader(statusCode int) {
 		w.ResponseWriter.WriteHeader(statusCode)
 	}
 }
+
+// Unwrap returns the underlying http.ResponseWriter.
+//
+// Summary: Exposes the wrapped writer for http.ResponseController and connection hijacking.
+//
+// Returns:
+//   - http.ResponseWriter: The wrapped writer.
+func (w *responseBuffer) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
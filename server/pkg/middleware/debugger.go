@@ -162,6 +162,16 @@ func (w *bodyLogWriter) WriteHeader(statusCode int) {
 	w.ResponseWriter.WriteHeader(statusCode)
 }
 
+// Unwrap returns the underlying http.ResponseWriter.
+//
+// Summary: Exposes the wrapped writer for http.ResponseController and connection hijacking.
+//
+// Returns:
+//   - http.ResponseWriter: The wrapped writer.
+func (w *bodyLogWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 // readCloserWrapper wraps a Reader and a Closer.
 type readCloserWrapper struct {
 	io.Reader
@@ -29,6 +29,13 @@ func DLPMiddleware(config *configv1.DLPConfig, log *slog.Logger) mcp.Middleware
 
 	return func(next mcp.MethodHandler) mcp.MethodHandler {
 		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			// Tool calls follow the per-tool rules; a nil redactor leaves
+			// the call untouched.
+			redactor := redactor
+			if r, ok := req.(*mcp.CallToolRequest); ok && r.Params != nil {
+				redactor = redactor.ForTool(r.Params.Name)
+			}
+
 			// 1. Redact Request Inputs
 			switch r := req.(type) {
 			case *mcp.CallToolRequest:
@@ -147,3 +147,29 @@ func TestDLPMiddleware(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestDLPMiddleware_ToolRules(t *testing.T) {
+	enabled, disabled := true, true
+	cfg := configv1.DLPConfig_builder{
+		Enabled: &enabled,
+		ToolRules: []*configv1.DLPToolRule{
+			configv1.DLPToolRule_builder{Tools: []string{"crm.*"}, Disabled: &disabled}.Build(),
+		},
+	}.Build()
+	mw := DLPMiddleware(cfg, logging.GetLogger())
+
+	handler := mw(func(_ context.Context, _ string, req mcp.Request) (mcp.Result, error) {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: string(req.(*mcp.CallToolRequest).Params.Arguments)}},
+		}, nil
+	})
+	call := func(name string) string {
+		req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: name, Arguments: json.RawMessage(`{"email":"alice@corp.com"}`)}}
+		result, err := handler(context.Background(), "tools/call", req)
+		assert.NoError(t, err)
+		return result.(*mcp.CallToolResult).Content[0].(*mcp.TextContent).Text
+	}
+
+	assert.Equal(t, `{"email":"alice@corp.com"}`, call("crm.lookup"))
+	assert.Equal(t, `{"email":"***REDACTED***"}`, call("weather.get"))
+}
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/mcpany/core/server/pkg/auth"
@@ -34,6 +35,9 @@ type GlobalRateLimitMiddleware struct {
 	limiters *cache.Cache
 	// redisClients caches Redis clients. Key is "global".
 	redisClients sync.Map
+	// sharedRedis holds the counters of the limit without a storage when the
+	// replicas share their state through Redis.
+	sharedRedis atomic.Pointer[redis.Client]
 }
 
 // NewGlobalRateLimitMiddleware creates a new GlobalRateLimitMiddleware.
@@ -74,6 +78,23 @@ func (m *GlobalRateLimitMiddleware) UpdateConfig(config *configv1.RateLimitConfi
 	// So we generally don't need to clear cache unless KeyBy changes.
 }
 
+// SetSharedRedis keeps the counters of the limit without a storage in the
+// Redis shared by the replicas, so that they enforce the limit together.
+// Passing nil keeps them in memory again.
+//
+// Summary: Shares the global rate limit counters through Redis.
+//
+// Parameters:
+//   - client: *redis.Client. The shared client, or nil.
+//
+// Side Effects:
+//   - Drops the cached limiters, so that the counters restart in their new
+//     store.
+func (m *GlobalRateLimitMiddleware) SetSharedRedis(client *redis.Client) {
+	m.sharedRedis.Store(client)
+	m.limiters.Flush()
+}
+
 // Execute executes the rate limiting middleware.
 //
 // Summary: Intercepts requests and enforces the configured rate limits.
@@ -112,7 +133,7 @@ func (m *GlobalRateLimitMiddleware) Execute(ctx context.Context, method string,
 		}
 		if !allowed {
 			m.recordMetrics("blocked")
-			return nil, fmt.Errorf("global rate limit exceeded")
+			return nil, fmt.Errorf("global %w", ErrRateLimited)
 		}
 		m.recordMetrics("allowed")
 	}
@@ -140,13 +161,20 @@ func (m *GlobalRateLimitMiddleware) getLimiter(ctx context.Context, config *conf
 	cacheKey := partitionKey
 
 	isRedis := config.GetStorage() == configv1.RateLimitConfig_STORAGE_REDIS
+	var shared *redis.Client
+	if config.GetStorage() == configv1.RateLimitConfig_STORAGE_UNSPECIFIED {
+		shared = m.sharedRedis.Load()
+	}
 
 	// Try to get from cache
 	if val, found := m.limiters.Get(cacheKey); found {
 		limiter := val.(Limiter)
 		// Verify type matches config
 		var validType bool
-		if isRedis {
+		switch {
+		case shared != nil:
+			_, validType = limiter.(*RedisLimiter)
+		case isRedis:
 			rl, ok := limiter.(*RedisLimiter)
 			validType = ok
 			// Check if Redis config changed
@@ -156,7 +184,7 @@ func (m *GlobalRateLimitMiddleware) getLimiter(ctx context.Context, config *conf
 					validType = false // Force creation of new limiter
 				}
 			}
-		} else {
+		default:
 			_, validType = limiter.(*LocalLimiter)
 		}
 
@@ -171,14 +199,17 @@ func (m *GlobalRateLimitMiddleware) getLimiter(ctx context.Context, config *conf
 	// Create new limiter
 	var limiter Limiter
 
-	if isRedis {
+	switch {
+	case shared != nil:
+		limiter = NewRedisLimiterWithClient(shared, "global", "", partitionKey, config)
+	case isRedis:
 		if config.GetRedis() == nil {
 			return nil, fmt.Errorf("redis config is missing")
 		}
 		client := m.getRedisClient(config.GetRedis())
 		// Pass global identifier
 		limiter = NewRedisLimiterWithClient(client, "global", "", partitionKey, config)
-	} else {
+	default:
 		limiter = &LocalLimiter{
 			Limiter: rate.NewLimiter(rate.Limit(rps), burst),
 		}
@@ -8,12 +8,15 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/mcpany/core/proto/bus"
 	configv1 "github.com/mcpany/core/proto/config/v1"
 	"github.com/mcpany/core/server/pkg/auth"
 	"github.com/mcpany/core/server/pkg/util"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -381,3 +384,37 @@ func TestGlobalRateLimitMiddleware_GetLimiter(t *testing.T) {
 	assert.Nil(t, limiter4)
 	assert.Contains(t, err.Error(), "redis config is missing")
 }
+
+func TestGlobalRateLimitMiddleware_SharedRedis(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	cfg := configv1.RateLimitConfig_builder{
+		IsEnabled:         true,
+		RequestsPerSecond: 0.001,
+		Burst:             1,
+		KeyBy:             configv1.RateLimitConfig_KEY_BY_GLOBAL,
+	}.Build()
+	next := func(context.Context, string, mcp.Request) (mcp.Result, error) {
+		return &mcp.CallToolResult{}, nil
+	}
+	ctx := context.Background()
+
+	// Two replicas share the burst of the limit.
+	a := NewGlobalRateLimitMiddleware(cfg)
+	b := NewGlobalRateLimitMiddleware(cfg)
+	_, err := a.Execute(ctx, "tools/call", nil, next)
+	require.NoError(t, err)
+	a.SetSharedRedis(client)
+	b.SetSharedRedis(client)
+	_, err = a.Execute(ctx, "tools/call", nil, next)
+	require.NoError(t, err, "the local counter is dropped")
+	_, err = b.Execute(ctx, "tools/call", nil, next)
+	assert.ErrorIs(t, err, ErrRateLimited)
+
+	// An explicit MEMORY storage stays per replica.
+	cfg.SetStorage(configv1.RateLimitConfig_STORAGE_MEMORY)
+	_, err = b.Execute(ctx, "tools/call", nil, next)
+	assert.NoError(t, err)
+}
@@ -48,8 +48,22 @@ func NewIPAllowlistMiddleware(allowedCIDRs []string) (*IPAllowlistMiddleware, er
 // Returns:
 //   - error: An error if any of the provided CIDRs are invalid.
 func (m *IPAllowlistMiddleware) Update(allowedCIDRs []string) error {
-	nets := make([]*net.IPNet, 0, len(allowedCIDRs))
-	for _, cidr := range allowedCIDRs {
+	nets, err := parseIPNets(allowedCIDRs)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.allowedIPNets = nets
+	m.mu.Unlock()
+	return nil
+}
+
+// parseIPNets parses a list of IP addresses and CIDR blocks. A single IP is
+// converted to a /32 or /128 network.
+func parseIPNets(cidrs []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
 		// Try parsing as CIDR first
 		_, ipNet, err := net.ParseCIDR(cidr)
 		if err == nil {
@@ -60,7 +74,7 @@ func (m *IPAllowlistMiddleware) Update(allowedCIDRs []string) error {
 		// If not CIDR, try as single IP
 		ip := net.ParseIP(cidr)
 		if ip == nil {
-			return fmt.Errorf("invalid IP or CIDR: %s", cidr)
+			return nil, fmt.Errorf("invalid IP or CIDR: %s", cidr)
 		}
 
 		// Convert single IP to /32 or /128
@@ -70,11 +84,7 @@ func (m *IPAllowlistMiddleware) Update(allowedCIDRs []string) error {
 		}
 		nets = append(nets, &net.IPNet{IP: ip, Mask: mask})
 	}
-
-	m.mu.Lock()
-	m.allowedIPNets = nets
-	m.mu.Unlock()
-	return nil
+	return nets, nil
 }
 
 // Allow checks if the given remote address is allowed.
@@ -0,0 +1,319 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/audit"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/metrics"
+	"github.com/mcpany/core/server/pkg/util"
+)
+
+// Listeners that have their own network access rules.
+const (
+	// ListenerMCP is the MCP endpoints of the HTTP listener.
+	ListenerMCP = "mcp"
+	// ListenerAdmin is the admin API and UI of the HTTP listener and the gRPC listener.
+	ListenerAdmin = "admin"
+)
+
+// NetworkAccessAuditTool is the tool name of the audit entries of rejected
+// connection attempts.
+const NetworkAccessAuditTool = "network:rejected"
+
+// networkAccessAuditInterval is how often a rejected client is audited per
+// listener, so a scan does not flood the audit log.
+const networkAccessAuditInterval = time.Minute
+
+var metricNetworkAccessRejected = []string{"network_access", "rejected"}
+
+// adminPathPrefixes are the paths of the HTTP listener that serve the admin
+// API and UI, including the login of the UI, the upstream health and the
+// readiness details, which report errors. All other paths serve MCP, and
+// /healthz and /readyz, which only report a status, stay reachable for the
+// probes under the rules of the MCP listener.
+var adminPathPrefixes = []string{
+	"/api/", "/v1/", "/ui/", "/dashboard/", "/metrics", "/debug/", "/upload", "/credentials", "/context/", "/auth/", "/healthz/upstreams",
+	"/readyz/details",
+}
+
+// AuditWriter writes audit entries. It is implemented by AuditMiddleware.
+type AuditWriter interface {
+	// Write writes an audit entry.
+	Write(ctx context.Context, entry audit.Entry) error
+}
+
+type networkRules struct {
+	allow []*net.IPNet
+	deny  []*net.IPNet
+}
+
+// check returns whether ip may connect and, if not, why.
+func (r networkRules) check(ip net.IP) (bool, string) {
+	if len(r.allow) == 0 && len(r.deny) == 0 {
+		return true, ""
+	}
+	if ip == nil {
+		return false, "client address is not an IP"
+	}
+	for _, n := range r.deny {
+		if n.Contains(ip) {
+			return false, "denied by " + n.String()
+		}
+	}
+	if len(r.allow) == 0 {
+		return true, ""
+	}
+	for _, n := range r.allow {
+		if n.Contains(ip) {
+			return true, ""
+		}
+	}
+	return false, "not in the allow list"
+}
+
+// NetworkAccess enforces the allowed and denied client addresses of each
+// listener and audits rejected attempts.
+//
+// Summary: Per-listener CIDR access rules with trusted proxy handling.
+type NetworkAccess struct {
+	mu             sync.RWMutex
+	rules          map[string]networkRules
+	trustedProxies []*net.IPNet
+	auditor        AuditWriter
+
+	auditMu   sync.Mutex
+	lastAudit map[string]time.Time
+}
+
+// NewNetworkAccess creates a new NetworkAccess.
+//
+// Summary: Initializes the network access rules.
+//
+// Parameters:
+//   - config: *configv1.NetworkAccessConfig. The rules; nil allows all clients.
+//
+// Returns:
+//   - *NetworkAccess: The initialized rules.
+//   - error: An error if an IP or CIDR is invalid.
+func NewNetworkAccess(config *configv1.NetworkAccessConfig) (*NetworkAccess, error) {
+	n := &NetworkAccess{lastAudit: make(map[string]time.Time)}
+	if err := n.Update(config); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// Update replaces the rules.
+//
+// Summary: Dynamically updates the network access rules.
+//
+// Parameters:
+//   - config: *configv1.NetworkAccessConfig. The new rules.
+//
+// Returns:
+//   - error: An error if an IP or CIDR is invalid. The old rules stay in effect.
+func (n *NetworkAccess) Update(config *configv1.NetworkAccessConfig) error {
+	rules := make(map[string]networkRules, 2)
+	for listener, cfg := range map[string]*configv1.NetworkAccessRules{
+		ListenerMCP:   config.GetMcp(),
+		ListenerAdmin: config.GetAdmin(),
+	} {
+		allow, err := parseIPNets(cfg.GetAllow())
+		if err != nil {
+			return fmt.Errorf("%s allow: %w", listener, err)
+		}
+		deny, err := parseIPNets(cfg.GetDeny())
+		if err != nil {
+			return fmt.Errorf("%s deny: %w", listener, err)
+		}
+		rules[listener] = networkRules{allow: allow, deny: deny}
+	}
+	trusted, err := parseIPNets(config.GetTrustedProxies())
+	if err != nil {
+		return fmt.Errorf("trusted proxies: %w", err)
+	}
+
+	n.mu.Lock()
+	n.rules = rules
+	n.trustedProxies = trusted
+	n.mu.Unlock()
+	return nil
+}
+
+// SetAuditor sets where rejected attempts are audited.
+//
+// Parameters:
+//   - auditor: AuditWriter. The audit log, or nil to only log rejections.
+func (n *NetworkAccess) SetAuditor(auditor AuditWriter) {
+	n.mu.Lock()
+	n.auditor = auditor
+	n.mu.Unlock()
+}
+
+// ClientIP returns the address of the client of a request. If the request
+// comes from a trusted proxy, the X-Forwarded-For header is walked from the
+// right, and the first address that is not a trusted proxy is the client.
+//
+// Summary: Determines the client address behind trusted proxies.
+//
+// Parameters:
+//   - r: *http.Request. The request.
+//
+// Returns:
+//   - net.IP: The client address, or nil if RemoteAddr is not an IP.
+func (n *NetworkAccess) ClientIP(r *http.Request) net.IP {
+	n.mu.RLock()
+	trusted := n.trustedProxies
+	n.mu.RUnlock()
+
+	client := net.ParseIP(util.ExtractIP(r.RemoteAddr))
+	if client == nil || !containsIP(trusted, client) {
+		return client
+	}
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := net.ParseIP(util.ExtractIP(strings.TrimSpace(hops[i])))
+		if ip == nil {
+			// A malformed hop was not added by a trusted proxy.
+			break
+		}
+		client = ip
+		if !containsIP(trusted, ip) {
+			break
+		}
+	}
+	return client
+}
+
+// Allow checks whether a client may connect to a listener. Rejections are
+// logged, counted and audited.
+//
+// Summary: Checks a client address against the rules of a listener.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the attempt.
+//   - listener: string. ListenerMCP or ListenerAdmin.
+//   - ip: net.IP. The client address.
+//   - labels: map[string]string. Details of the attempt added to the audit entry, such as the path.
+//
+// Returns:
+//   - bool: True if the client may connect.
+func (n *NetworkAccess) Allow(ctx context.Context, listener string, ip net.IP, labels map[string]string) bool {
+	n.mu.RLock()
+	rules := n.rules[listener]
+	auditor := n.auditor
+	n.mu.RUnlock()
+
+	ok, reason := rules.check(ip)
+	if ok {
+		return true
+	}
+
+	clientIP := ip.String()
+	if ip == nil {
+		clientIP = ""
+	}
+	logging.GetLogger().WarnContext(ctx, "Connection rejected by network access rules",
+		"listener", listener, "client_ip", clientIP, "reason", reason)
+	metrics.IncrCounterWithLabels(metricNetworkAccessRejected, 1, []metrics.Label{
+		{Name: "listener", Value: listener},
+	})
+	if auditor != nil && n.shouldAudit(listener, clientIP) {
+		entryLabels := map[string]string{"listener": listener, "client_ip": clientIP, "reason": reason}
+		for k, v := range labels {
+			entryLabels[k] = v
+		}
+		entry := audit.Entry{
+			Timestamp: time.Now(),
+			ToolName:  NetworkAccessAuditTool,
+			Labels:    entryLabels,
+			Error:     fmt.Sprintf("connection from %q to the %s listener rejected: %s", clientIP, listener, reason),
+		}
+		if err := auditor.Write(ctx, entry); err != nil {
+			logging.GetLogger().DebugContext(ctx, "Failed to audit rejected connection", "error", err)
+		}
+	}
+	return false
+}
+
+// shouldAudit reports whether a rejection of the client was not audited
+// within the last networkAccessAuditInterval.
+func (n *NetworkAccess) shouldAudit(listener, clientIP string) bool {
+	key := listener + "|" + clientIP
+	now := time.Now()
+	n.auditMu.Lock()
+	defer n.auditMu.Unlock()
+	if last, ok := n.lastAudit[key]; ok && now.Sub(last) < networkAccessAuditInterval {
+		return false
+	}
+	if len(n.lastAudit) >= 10000 {
+		for k, t := range n.lastAudit {
+			if now.Sub(t) >= networkAccessAuditInterval {
+				delete(n.lastAudit, k)
+			}
+		}
+	}
+	n.lastAudit[key] = now
+	return true
+}
+
+// Handler returns an HTTP handler that enforces the rules of the listener
+// serving the request path.
+//
+// Summary: Returns an HTTP handler that rejects clients not allowed by the rules.
+//
+// Parameters:
+//   - next: http.Handler. The next handler in the chain.
+//
+// Returns:
+//   - http.Handler: The wrapped handler.
+func (n *NetworkAccess) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		listener := ListenerForRequest(r)
+		if !n.Allow(r.Context(), listener, n.ClientIP(r), map[string]string{"path": r.URL.Path, "remote_addr": r.RemoteAddr}) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ListenerForRequest returns the listener whose rules apply to a request of
+// the HTTP listener.
+//
+// Parameters:
+//   - r: *http.Request. The request.
+//
+// Returns:
+//   - string: ListenerAdmin for the admin API, UI and gRPC-Web, ListenerMCP otherwise.
+func ListenerForRequest(r *http.Request) string {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc-web") {
+		return ListenerAdmin
+	}
+	for _, prefix := range adminPathPrefixes {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return ListenerAdmin
+		}
+	}
+	return ListenerMCP
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
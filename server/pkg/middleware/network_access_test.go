@@ -0,0 +1,150 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingAuditor struct {
+	entries []audit.Entry
+}
+
+func (a *recordingAuditor) Write(_ context.Context, entry audit.Entry) error {
+	a.entries = append(a.entries, entry)
+	return nil
+}
+
+func TestNetworkAccess_Allow(t *testing.T) {
+	n, err := NewNetworkAccess(configv1.NetworkAccessConfig_builder{
+		Mcp: configv1.NetworkAccessRules_builder{
+			Allow: []string{"10.0.0.0/8"},
+			Deny:  []string{"10.0.13.0/24"},
+		}.Build(),
+		Admin: configv1.NetworkAccessRules_builder{Deny: []string{"203.0.113.7"}}.Build(),
+	}.Build())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	assert.True(t, n.Allow(ctx, ListenerMCP, net.ParseIP("10.1.2.3"), nil))
+	assert.False(t, n.Allow(ctx, ListenerMCP, net.ParseIP("10.0.13.5"), nil), "deny wins over allow")
+	assert.False(t, n.Allow(ctx, ListenerMCP, net.ParseIP("192.168.1.1"), nil))
+	assert.False(t, n.Allow(ctx, ListenerMCP, nil, nil))
+
+	assert.True(t, n.Allow(ctx, ListenerAdmin, net.ParseIP("192.168.1.1"), nil), "an empty allow list allows all")
+	assert.False(t, n.Allow(ctx, ListenerAdmin, net.ParseIP("203.0.113.7"), nil))
+
+	open, err := NewNetworkAccess(nil)
+	require.NoError(t, err)
+	assert.True(t, open.Allow(ctx, ListenerMCP, nil, nil))
+
+	_, err = NewNetworkAccess(configv1.NetworkAccessConfig_builder{
+		Admin: configv1.NetworkAccessRules_builder{Allow: []string{"not-an-ip"}}.Build(),
+	}.Build())
+	assert.ErrorContains(t, err, "admin allow: invalid IP or CIDR: not-an-ip")
+}
+
+func TestNetworkAccess_ClientIP(t *testing.T) {
+	n, err := NewNetworkAccess(configv1.NetworkAccessConfig_builder{
+		TrustedProxies: []string{"172.16.0.0/12"},
+	}.Build())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		xff        []string
+		want       string
+	}{
+		{name: "direct", remoteAddr: "198.51.100.4:5000", want: "198.51.100.4"},
+		{name: "untrusted proxy", remoteAddr: "198.51.100.4:5000", xff: []string{"10.9.9.9"}, want: "198.51.100.4"},
+		{name: "trusted proxy", remoteAddr: "172.16.0.10:5000", xff: []string{"10.9.9.9"}, want: "10.9.9.9"},
+		{name: "spoofed hop before client", remoteAddr: "172.16.0.10:5000", xff: []string{"1.2.3.4, 10.9.9.9"}, want: "10.9.9.9"},
+		{name: "chain of trusted proxies", remoteAddr: "172.16.0.10:5000", xff: []string{"10.9.9.9", "172.20.0.1"}, want: "10.9.9.9"},
+		{name: "malformed hop", remoteAddr: "172.16.0.10:5000", xff: []string{"10.9.9.9, garbage"}, want: "172.16.0.10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/mcp", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.xff {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+			assert.Equal(t, tt.want, n.ClientIP(r).String())
+		})
+	}
+}
+
+func TestNetworkAccess_Handler(t *testing.T) {
+	n, err := NewNetworkAccess(configv1.NetworkAccessConfig_builder{
+		Admin: configv1.NetworkAccessRules_builder{Allow: []string{"10.0.0.0/8"}}.Build(),
+	}.Build())
+	require.NoError(t, err)
+	auditor := &recordingAuditor{}
+	n.SetAuditor(auditor)
+	handler := n.Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(path, remoteAddr string) int {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve("/mcp", "198.51.100.4:5000"))
+	assert.Equal(t, http.StatusOK, serve("/api/v1/services", "10.1.2.3:5000"))
+	assert.Equal(t, http.StatusForbidden, serve("/api/v1/services", "198.51.100.4:5000"))
+	assert.Equal(t, http.StatusForbidden, serve("/v1/services", "198.51.100.4:5001"))
+	assert.Equal(t, http.StatusForbidden, serve("/dashboard/api/me", "198.51.100.4:5002"))
+	assert.Equal(t, http.StatusOK, serve("/dashboard/api/me", "10.1.2.3:5000"))
+
+	require.Len(t, auditor.entries, 1, "repeated rejections of a client are audited once per interval")
+	entry := auditor.entries[0]
+	assert.Equal(t, NetworkAccessAuditTool, entry.ToolName)
+	assert.Equal(t, map[string]string{
+		"listener":    ListenerAdmin,
+		"client_ip":   "198.51.100.4",
+		"reason":      "not in the allow list",
+		"path":        "/api/v1/services",
+		"remote_addr": "198.51.100.4:5000",
+	}, entry.Labels)
+	assert.Equal(t, `connection from "198.51.100.4" to the admin listener rejected: not in the allow list`, entry.Error)
+}
+
+func TestListenerForRequest(t *testing.T) {
+	for path, want := range map[string]string{
+		"/":                  ListenerMCP,
+		"/mcp":               ListenerMCP,
+		"/mcp/u/alice":       ListenerMCP,
+		"/api/v1/services":   ListenerAdmin,
+		"/ui/index.html":     ListenerAdmin,
+		"/dashboard/":        ListenerAdmin,
+		"/dashboard/api/me":  ListenerAdmin,
+		"/metrics":           ListenerAdmin,
+		"/auth/oauth/start":  ListenerAdmin,
+		"/auth/login":        ListenerAdmin,
+		"/auth/callback":     ListenerAdmin,
+		"/healthz/upstreams": ListenerAdmin,
+		"/healthz":           ListenerMCP,
+		"/readyz":            ListenerMCP,
+		"/readyz/details":    ListenerAdmin,
+	} {
+		assert.Equal(t, want, ListenerForRequest(httptest.NewRequest(http.MethodGet, path, nil)), path)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set("Content-Type", "application/grpc-web+proto")
+	assert.Equal(t, ListenerAdmin, ListenerForRequest(r))
+}
@@ -0,0 +1,266 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/consts"
+	"github.com/mcpany/core/server/pkg/metrics"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ErrQuotaExceeded is returned when a tool call does not fit a quota.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// quotaSweepInterval is how often the counters of the idle keys are dropped.
+const quotaSweepInterval = 10 * time.Minute
+
+// quotaUsage holds the counters of a quota for one key.
+type quotaUsage struct {
+	inFlight  int32
+	hour      time.Time
+	hourCalls int64
+	day       time.Time
+	dayCalls  int64
+}
+
+// roll restarts the hourly and daily counters when their window has passed.
+func (u *quotaUsage) roll(now time.Time) {
+	if hour := now.Truncate(time.Hour); !hour.Equal(u.hour) {
+		u.hour = hour
+		u.hourCalls = 0
+	}
+	if day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC); !day.Equal(u.day) {
+		u.day = day
+		u.dayCalls = 0
+	}
+}
+
+// QuotaMiddleware enforces the quotas on the tool calls of each session, API
+// key or user, so that one runaway agent cannot starve the others. A call
+// must fit every configured quota.
+//
+// Summary: Middleware that enforces per-session, per-key and per-user call quotas.
+type QuotaMiddleware struct {
+	mu        sync.Mutex
+	quotas    []*configv1.QuotaConfig
+	usage     map[string]*quotaUsage
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewQuotaMiddleware creates a new QuotaMiddleware.
+//
+// Summary: Initializes the quota middleware with the provided quotas.
+//
+// Parameters:
+//   - quotas: []*configv1.QuotaConfig. The quotas to enforce.
+//
+// Returns:
+//   - *QuotaMiddleware: The initialized middleware instance.
+func NewQuotaMiddleware(quotas []*configv1.QuotaConfig) *QuotaMiddleware {
+	return &QuotaMiddleware{
+		quotas: quotas,
+		usage:  make(map[string]*quotaUsage),
+		now:    func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// UpdateConfig replaces the quotas at runtime. The counters of the quotas
+// that keep their name are kept.
+//
+// Summary: Updates the quotas at runtime.
+//
+// Parameters:
+//   - quotas: []*configv1.QuotaConfig. The new quotas.
+//
+// Side Effects:
+//   - Drops the counters of the removed quotas.
+func (m *QuotaMiddleware) UpdateConfig(quotas []*configv1.QuotaConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.quotas = quotas
+	names := make(map[string]bool, len(quotas))
+	for _, q := range quotas {
+		names[q.GetName()] = true
+	}
+	for key, u := range m.usage {
+		if name, _, _ := strings.Cut(key, "\x00"); !names[name] && u.inFlight == 0 {
+			delete(m.usage, key)
+		}
+	}
+}
+
+// Execute enforces the quotas on a tool call.
+//
+// Summary: Intercepts tool calls and enforces the configured quotas.
+//
+// Parameters:
+//   - ctx: context.Context. The request context.
+//   - method: string. The MCP method being called.
+//   - req: mcp.Request. The request payload.
+//   - next: mcp.MethodHandler. The next handler in the chain.
+//
+// Returns:
+//   - mcp.Result: The result of the next handler if allowed.
+//   - error: An error if a quota is exceeded or the next handler fails.
+//
+// Errors:
+//   - Returns an error wrapping ErrQuotaExceeded that names the quota, the
+//     exceeded limit and, for the hourly and daily limits, when it resets.
+//
+// Side Effects:
+//   - Counts the call against every quota until it returns.
+//   - Records metrics for allowed and blocked calls.
+func (m *QuotaMiddleware) Execute(ctx context.Context, method string, req mcp.Request, next mcp.MethodHandler) (mcp.Result, error) {
+	if method != consts.MethodToolsCall {
+		return next(ctx, method, req)
+	}
+	admitted, err := m.admit(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(admitted) == 0 {
+		return next(ctx, method, req)
+	}
+	defer m.release(admitted)
+	return next(ctx, method, req)
+}
+
+// admit checks a call against every quota and, if it fits them all, counts
+// it. It returns the counters the call was counted in.
+func (m *QuotaMiddleware) admit(ctx context.Context, req mcp.Request) ([]*quotaUsage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.quotas) == 0 {
+		return nil, nil
+	}
+	now := m.now()
+	m.sweepLocked(now)
+
+	admitted := make([]*quotaUsage, 0, len(m.quotas))
+	names := make([]string, 0, len(m.quotas))
+	for _, q := range m.quotas {
+		key, who, ok := quotaKey(ctx, req, q.GetKeyBy())
+		if !ok {
+			continue
+		}
+		usageKey := q.GetName() + "\x00" + key
+		u, ok := m.usage[usageKey]
+		if !ok {
+			u = &quotaUsage{}
+			m.usage[usageKey] = u
+		}
+		u.roll(now)
+		if err := checkQuota(q, u, who); err != nil {
+			recordQuotaMetrics(q.GetName(), "blocked")
+			return nil, err
+		}
+		admitted = append(admitted, u)
+		names = append(names, q.GetName())
+	}
+	for _, u := range admitted {
+		u.inFlight++
+		u.hourCalls++
+		u.dayCalls++
+	}
+	for _, name := range names {
+		recordQuotaMetrics(name, "allowed")
+	}
+	return admitted, nil
+}
+
+// release ends the calls counted by admit.
+func (m *QuotaMiddleware) release(admitted []*quotaUsage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, u := range admitted {
+		u.inFlight--
+	}
+}
+
+// sweepLocked drops the counters of the keys without calls in flight or
+// today. The caller must hold m.mu.
+func (m *QuotaMiddleware) sweepLocked(now time.Time) {
+	if now.Sub(m.lastSweep) < quotaSweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for key, u := range m.usage {
+		u.roll(now)
+		if u.inFlight == 0 && u.dayCalls == 0 {
+			delete(m.usage, key)
+		}
+	}
+}
+
+// checkQuota returns an error if one more call does not fit the quota.
+func checkQuota(q *configv1.QuotaConfig, u *quotaUsage, who string) error {
+	if limit := q.GetMaxConcurrentCalls(); limit > 0 && u.inFlight >= limit {
+		return fmt.Errorf("%w: quota %q exceeded for %s: %d calls in flight (max %d); retry when one of them completes",
+			ErrQuotaExceeded, q.GetName(), who, u.inFlight, limit)
+	}
+	if limit := q.GetMaxCallsPerHour(); limit > 0 && u.hourCalls >= limit {
+		return fmt.Errorf("%w: quota %q exceeded for %s: %d calls this hour (max %d); resets at %s",
+			ErrQuotaExceeded, q.GetName(), who, u.hourCalls, limit, u.hour.Add(time.Hour).Format(time.RFC3339))
+	}
+	if limit := q.GetMaxCallsPerDay(); limit > 0 && u.dayCalls >= limit {
+		return fmt.Errorf("%w: quota %q exceeded for %s: %d calls today (max %d); resets at %s",
+			ErrQuotaExceeded, q.GetName(), who, u.dayCalls, limit, u.day.AddDate(0, 0, 1).Format(time.RFC3339))
+	}
+	return nil
+}
+
+// quotaKey returns the key a call is counted by and how to name it in the
+// errors. The calls without an API key are not counted by the quotas by API
+// key.
+func quotaKey(ctx context.Context, req mcp.Request, keyBy configv1.QuotaConfig_KeyBy) (key, who string, ok bool) {
+	switch keyBy {
+	case configv1.QuotaConfig_KEY_BY_SESSION:
+		if req == nil {
+			return "", "", false
+		}
+		ss, isSession := req.GetSession().(*mcp.ServerSession)
+		if !isSession || ss == nil {
+			return "", "", false
+		}
+		if id := ss.ID(); id != "" {
+			return "session:" + id, "session " + id, true
+		}
+		// Sessions without an ID, such as stdio ones, are told apart by
+		// their address.
+		return fmt.Sprintf("session:%p", ss), "this session", true
+	case configv1.QuotaConfig_KEY_BY_USER_ID:
+		if uid, found := auth.UserFromContext(ctx); found && uid != "" {
+			return "user:" + uid, "user " + uid, true
+		}
+		return "user:anonymous", "anonymous users", true
+	case configv1.QuotaConfig_KEY_BY_API_KEY:
+		apiKey, found := auth.APIKeyFromContext(ctx)
+		if !found || apiKey == "" {
+			return "", "", false
+		}
+		if id, found := auth.APIKeyIDFromContext(ctx); found && id != "" {
+			return hashKey("apikey:", apiKey), "API key " + id, true
+		}
+		return hashKey("apikey:", apiKey), "this API key", true
+	default:
+		return "", "", false
+	}
+}
+
+func recordQuotaMetrics(quota, status string) {
+	metrics.IncrCounterWithLabels([]string{"quota", "calls_total"}, 1, []metrics.Label{
+		{Name: "quota", Value: quota},
+		{Name: "status", Value: status},
+	})
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestQuota(name string, keyBy configv1.QuotaConfig_KeyBy, concurrent int32, hourly, daily int64) *configv1.QuotaConfig {
+	return configv1.QuotaConfig_builder{
+		Name:               proto.String(name),
+		KeyBy:              keyBy.Enum(),
+		MaxConcurrentCalls: proto.Int32(concurrent),
+		MaxCallsPerHour:    proto.Int64(hourly),
+		MaxCallsPerDay:     proto.Int64(daily),
+	}.Build()
+}
+
+func quotaOKHandler(_ context.Context, _ string, _ mcp.Request) (mcp.Result, error) {
+	return &mcp.CallToolResult{}, nil
+}
+
+func TestQuotaMiddleware_Concurrency(t *testing.T) {
+	mw := NewQuotaMiddleware([]*configv1.QuotaConfig{newTestQuota("per-user", configv1.QuotaConfig_KEY_BY_USER_ID, 1, 0, 0)})
+	alice := auth.ContextWithUser(context.Background(), "alice")
+	bob := auth.ContextWithUser(context.Background(), "bob")
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		_, err := mw.Execute(alice, "tools/call", nil, func(_ context.Context, _ string, _ mcp.Request) (mcp.Result, error) {
+			close(started)
+			<-release
+			return &mcp.CallToolResult{}, nil
+		})
+		done <- err
+	}()
+	<-started
+
+	_, err := mw.Execute(alice, "tools/call", nil, quotaOKHandler)
+	require.ErrorIs(t, err, ErrQuotaExceeded)
+	assert.Contains(t, err.Error(), `quota "per-user" exceeded for user alice: 1 calls in flight (max 1)`)
+
+	_, err = mw.Execute(bob, "tools/call", nil, quotaOKHandler)
+	assert.NoError(t, err, "other users have their own quota")
+	_, err = mw.Execute(alice, "tools/list", nil, quotaOKHandler)
+	assert.NoError(t, err, "only tool calls are counted")
+
+	close(release)
+	require.NoError(t, <-done)
+	_, err = mw.Execute(alice, "tools/call", nil, quotaOKHandler)
+	assert.NoError(t, err, "the slot is freed when the call returns")
+}
+
+func TestQuotaMiddleware_Windows(t *testing.T) {
+	now := time.Date(2026, 10, 16, 14, 30, 0, 0, time.UTC)
+	mw := NewQuotaMiddleware([]*configv1.QuotaConfig{newTestQuota("per-key", configv1.QuotaConfig_KEY_BY_API_KEY, 0, 2, 3)})
+	mw.now = func() time.Time { return now }
+	ctx := auth.ContextWithAPIKeyID(auth.ContextWithAPIKey(context.Background(), "mcpk_secret"), "ci")
+
+	for i := 0; i < 2; i++ {
+		_, err := mw.Execute(ctx, "tools/call", nil, quotaOKHandler)
+		require.NoError(t, err)
+	}
+	_, err := mw.Execute(ctx, "tools/call", nil, quotaOKHandler)
+	require.ErrorIs(t, err, ErrQuotaExceeded)
+	assert.Contains(t, err.Error(), `quota "per-key" exceeded for API key ci: 2 calls this hour (max 2); resets at 2026-10-16T15:00:00Z`)
+	assert.NotContains(t, err.Error(), "mcpk_secret")
+
+	now = now.Add(time.Hour)
+	_, err = mw.Execute(ctx, "tools/call", nil, quotaOKHandler)
+	require.NoError(t, err)
+	_, err = mw.Execute(ctx, "tools/call", nil, quotaOKHandler)
+	require.ErrorIs(t, err, ErrQuotaExceeded)
+	assert.Contains(t, err.Error(), "3 calls today (max 3); resets at 2026-10-17T00:00:00Z")
+
+	now = now.Add(9 * time.Hour)
+	_, err = mw.Execute(ctx, "tools/call", nil, quotaOKHandler)
+	assert.NoError(t, err, "the daily counter restarts at midnight UTC")
+
+	_, err = mw.Execute(context.Background(), "tools/call", nil, quotaOKHandler)
+	assert.NoError(t, err, "calls without an API key are not counted by API key")
+}
+
+func TestQuotaMiddleware_UpdateConfig(t *testing.T) {
+	mw := NewQuotaMiddleware(nil)
+	ctx := auth.ContextWithUser(context.Background(), "alice")
+	_, err := mw.Execute(ctx, "tools/call", nil, quotaOKHandler)
+	require.NoError(t, err)
+
+	mw.UpdateConfig([]*configv1.QuotaConfig{newTestQuota("per-user", configv1.QuotaConfig_KEY_BY_USER_ID, 0, 1, 0)})
+	_, err = mw.Execute(ctx, "tools/call", nil, quotaOKHandler)
+	require.NoError(t, err)
+	_, err = mw.Execute(ctx, "tools/call", nil, quotaOKHandler)
+	require.ErrorIs(t, err, ErrQuotaExceeded)
+
+	mw.UpdateConfig([]*configv1.QuotaConfig{newTestQuota("per-user", configv1.QuotaConfig_KEY_BY_USER_ID, 0, 2, 0)})
+	_, err = mw.Execute(ctx, "tools/call", nil, quotaOKHandler)
+	require.NoError(t, err, "the counters are kept across reloads")
+	_, err = mw.Execute(ctx, "tools/call", nil, quotaOKHandler)
+	require.ErrorIs(t, err, ErrQuotaExceeded)
+
+	mw.UpdateConfig(nil)
+	_, err = mw.Execute(ctx, "tools/call", nil, quotaOKHandler)
+	assert.NoError(t, err)
+}
+
+func TestQuotaMiddleware_Session(t *testing.T) {
+	mw := NewQuotaMiddleware([]*configv1.QuotaConfig{newTestQuota("per-session", configv1.QuotaConfig_KEY_BY_SESSION, 0, 1, 0)})
+	server := mcp.NewServer(&mcp.Implementation{Name: "mcpany"}, nil)
+	server.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			return mw.Execute(ctx, method, req, next)
+		}
+	})
+	server.AddTool(&mcp.Tool{Name: "echo", InputSchema: map[string]any{"type": "object"}}, func(_ context.Context, _ *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{}, nil
+	})
+
+	connect := func() *mcp.ClientSession {
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		_, err := server.Connect(context.Background(), serverTransport, nil)
+		require.NoError(t, err)
+		cs, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(context.Background(), clientTransport, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = cs.Close() })
+		return cs
+	}
+	first, second := connect(), connect()
+
+	_, err := first.CallTool(context.Background(), &mcp.CallToolParams{Name: "echo"})
+	require.NoError(t, err)
+	_, err = first.CallTool(context.Background(), &mcp.CallToolParams{Name: "echo"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `quota "per-session" exceeded`)
+
+	_, err = second.CallTool(context.Background(), &mcp.CallToolParams{Name: "echo"})
+	assert.NoError(t, err, "each session has its own quota")
+}
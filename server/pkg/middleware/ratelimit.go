@@ -8,10 +8,12 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	armonmetrics "github.com/armon/go-metrics"
@@ -22,8 +24,12 @@ import (
 	"github.com/mcpany/core/server/pkg/tool"
 	"github.com/mcpany/core/server/pkg/util"
 	"github.com/patrickmn/go-cache"
+	"github.com/redis/go-redis/v9"
 )
 
+// ErrRateLimited is returned when a call is blocked by a rate limit.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
 // metricRateLimitRequestsTotal is the metric name for rate limit requests.
 // Pre-allocated to avoid allocation on every request.
 var metricRateLimitRequestsTotal = []string{"rate_limit", "requests_total"}
@@ -39,6 +45,9 @@ type RateLimitMiddleware struct {
 	limiters *cache.Cache
 	// strategies maps storage types to strategies.
 	strategies map[configv1.RateLimitConfig_Storage]RateLimitStrategy
+	// shared is the strategy of the limits without a storage when the
+	// replicas share their state through Redis.
+	shared atomic.Pointer[RedisStrategy]
 }
 
 // Option defines a functional option for RateLimitMiddleware.
@@ -93,6 +102,28 @@ func NewRateLimitMiddleware(toolManager tool.ManagerInterface, opts ...Option) *
 	return m
 }
 
+// SetSharedRedis keeps the counters of the limits without a storage in the
+// Redis shared by the replicas, so that they enforce the limits together.
+// Limits with the MEMORY storage stay per replica. Passing nil keeps all
+// counters without a storage in memory again.
+//
+// Summary: Shares the rate limit counters through Redis.
+//
+// Parameters:
+//   - client (*redis.Client): The shared client, or nil.
+//
+// Side Effects:
+//   - Drops the cached limiters, so that the counters restart in their new
+//     store.
+func (m *RateLimitMiddleware) SetSharedRedis(client *redis.Client) {
+	if client == nil {
+		m.shared.Store(nil)
+	} else {
+		m.shared.Store(NewSharedRedisStrategy(client))
+	}
+	m.limiters.Flush()
+}
+
 // Execute executes the rate limiting middleware.
 //
 // Summary: Executes rate limiting logic before passing to the next handler.
@@ -149,7 +180,7 @@ func (m *RateLimitMiddleware) Execute(ctx context.Context, req *tool.ExecutionRe
 			}
 			if err := m.checkLimit(ctx, toolLimiter, toolConfig, req); err != nil {
 				m.recordMetrics(serviceID, "tool", "blocked")
-				return nil, fmt.Errorf("rate limit exceeded for tool %s: %w", req.ToolName, err)
+				return nil, fmt.Errorf("%w for tool %s: %w", ErrRateLimited, req.ToolName, err)
 			}
 			m.recordMetrics(serviceID, "tool", "allowed")
 			// If we checked tool limit, we return early (override logic).
@@ -165,7 +196,7 @@ func (m *RateLimitMiddleware) Execute(ctx context.Context, req *tool.ExecutionRe
 		}
 		if err := m.checkLimit(ctx, serviceLimiter, serviceRateLimitConfig, req); err != nil {
 			m.recordMetrics(serviceID, "service", "blocked")
-			return nil, fmt.Errorf("rate limit exceeded for service %s: %w", serviceInfo.Name, err)
+			return nil, fmt.Errorf("%w for service %s: %w", ErrRateLimited, serviceInfo.Name, err)
 		}
 		m.recordMetrics(serviceID, "service", "allowed")
 	}
@@ -262,6 +293,11 @@ func (m *RateLimitMiddleware) getLimiter(ctx context.Context, serviceID string,
 		// Fallback to local if strategy not found or default to memory
 		strategy = m.strategies[configv1.RateLimitConfig_STORAGE_MEMORY]
 	}
+	if storageType == configv1.RateLimitConfig_STORAGE_UNSPECIFIED {
+		if shared := m.shared.Load(); shared != nil {
+			strategy = shared
+		}
+	}
 
 	// Try to get from cache
 	if val, found := m.limiters.Get(cacheKey); found {
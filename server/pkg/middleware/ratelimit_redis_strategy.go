@@ -20,6 +20,9 @@ import (
 type RedisStrategy struct {
 	// redisClients caches Redis clients per config. Key is configHash.
 	redisClients sync.Map
+	// client, if set, is used for all limiters instead of the Redis of
+	// their configuration.
+	client *redis.Client
 }
 
 // NewRedisStrategy creates a new RedisStrategy.
@@ -32,6 +35,20 @@ func NewRedisStrategy() *RedisStrategy {
 	return &RedisStrategy{}
 }
 
+// NewSharedRedisStrategy creates a RedisStrategy whose limiters all use the
+// client, whatever the Redis of their configuration.
+//
+// Summary: Initializes a RedisStrategy over a shared client.
+//
+// Parameters:
+//   - client: *redis.Client. The client shared by the limiters.
+//
+// Returns:
+//   - *RedisStrategy: The initialized strategy.
+func NewSharedRedisStrategy(client *redis.Client) *RedisStrategy {
+	return &RedisStrategy{client: client}
+}
+
 // Create creates a new RedisLimiter.
 //
 // Summary: Creates a new Redis-backed rate limiter.
@@ -48,11 +65,14 @@ func NewRedisStrategy() *RedisStrategy {
 //   - error: An error if the Redis configuration is missing.
 //
 // Errors:
-//   - Returns "redis config is missing" if the config does not contain Redis settings.
+//   - Returns "redis config is missing" if the strategy has no shared client and the config does not contain Redis settings.
 //
 // Side Effects:
 //   - Establishes or reuses a Redis connection.
 func (s *RedisStrategy) Create(_ context.Context, serviceID, limitScopeKey, partitionKey string, config *configv1.RateLimitConfig) (Limiter, error) {
+	if s.client != nil {
+		return NewRedisLimiterWithClient(s.client, serviceID, limitScopeKey, partitionKey, config), nil
+	}
 	if config.GetRedis() == nil {
 		return nil, fmt.Errorf("redis config is missing")
 	}
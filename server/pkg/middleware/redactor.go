@@ -7,7 +7,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"log/slog"
+	"path"
 	"regexp"
+	"slices"
 	"strings"
 
 	configv1 "github.com/mcpany/core/proto/config/v1"
@@ -20,6 +22,10 @@ var (
 	emailRegex      = regexp.MustCompile(`(?i)[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}`)
 	creditCardRegex = regexp.MustCompile(`(?:\d{4}[-\s]?){3}\d{4}`)
 	ssnRegex        = regexp.MustCompile(`\d{3}-\d{2}-\d{4}`)
+	// apiKeyRegex matches the formats of common API keys and tokens: OpenAI and
+	// Anthropic, GitHub, Slack, AWS access key IDs, Google API keys, Stripe
+	// and JWTs.
+	apiKeyRegex = regexp.MustCompile(`\b(?:sk-[A-Za-z0-9_\-]{20,}|gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,}|xox[abposr]-[A-Za-z0-9\-]{10,}|AKIA[0-9A-Z]{16}|AIza[0-9A-Za-z_\-]{35}|[rsp]k_(?:live|test)_[0-9A-Za-z]{16,}|eyJ[A-Za-z0-9_\-]+\.eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+)`)
 
 	redactedStr = "***REDACTED***"
 )
@@ -30,6 +36,20 @@ var (
 type Redactor struct {
 	customPattern  *regexp.Regexp
 	customPatterns []*regexp.Regexp
+
+	email, creditCard, ssn, apiKey bool
+	// allowPatterns match values that are kept even if a detector or custom
+	// pattern matches them.
+	allowPatterns []*regexp.Regexp
+	// toolRules override the redaction of some tools. A nil redactor turns
+	// redaction off.
+	toolRules []toolRedactor
+}
+
+// toolRedactor is the redactor of the tools matching a DLPToolRule.
+type toolRedactor struct {
+	tools    []string
+	redactor *Redactor
 }
 
 // NewRedactor creates a new Redactor from the given DLP config.
@@ -51,15 +71,62 @@ func NewRedactor(config *configv1.DLPConfig, log *slog.Logger) *Redactor {
 		return nil
 	}
 
+	r := newPatternRedactor(config.GetDetectors(), config.GetCustomPatterns(), config.GetAllowPatterns(), log)
+	for _, rule := range config.GetToolRules() {
+		tr := toolRedactor{tools: rule.GetTools()}
+		if !rule.GetDisabled() {
+			detectors := rule.GetDetectors()
+			if len(detectors) == 0 {
+				detectors = config.GetDetectors()
+			}
+			tr.redactor = newPatternRedactor(
+				detectors,
+				append(slices.Clone(config.GetCustomPatterns()), rule.GetDenyPatterns()...),
+				append(slices.Clone(config.GetAllowPatterns()), rule.GetAllowPatterns()...),
+				log,
+			)
+		}
+		r.toolRules = append(r.toolRules, tr)
+	}
+	return r
+}
+
+// newPatternRedactor builds a redactor from detectors and custom and allow
+// patterns. Invalid patterns are logged and ignored.
+func newPatternRedactor(detectors []configv1.DLPConfig_Detector, customPatterns, allowPatterns []string, log *slog.Logger) *Redactor {
+	r := &Redactor{}
+	if len(detectors) == 0 {
+		r.email, r.creditCard, r.ssn = true, true, true
+	}
+	for _, d := range detectors {
+		switch d {
+		case configv1.DLPConfig_DETECTOR_EMAIL:
+			r.email = true
+		case configv1.DLPConfig_DETECTOR_CREDIT_CARD:
+			r.creditCard = true
+		case configv1.DLPConfig_DETECTOR_SSN:
+			r.ssn = true
+		case configv1.DLPConfig_DETECTOR_API_KEY:
+			r.apiKey = true
+		}
+	}
+	for _, p := range allowPatterns {
+		if re, err := regexp.Compile(p); err == nil {
+			r.allowPatterns = append(r.allowPatterns, re)
+		} else if log != nil {
+			log.Warn("Invalid DLP allow pattern, ignoring", "pattern", p, "error", err)
+		}
+	}
+
 	// Separate custom patterns from default ones for optimized processing
 	var validPatterns []string
 	var individualPatterns []*regexp.Regexp
 
-	for _, p := range config.GetCustomPatterns() {
-		if r, err := regexp.Compile(p); err == nil {
+	for _, p := range customPatterns {
+		if re, err := regexp.Compile(p); err == nil {
 			// Wrap in non-capturing group to isolate alternatives
 			validPatterns = append(validPatterns, "(?:"+p+")")
-			individualPatterns = append(individualPatterns, r)
+			individualPatterns = append(individualPatterns, re)
 		} else if log != nil {
 			log.Warn("Invalid custom DLP pattern, ignoring", "pattern", p, "error", err)
 		}
@@ -81,10 +148,48 @@ func NewRedactor(config *configv1.DLPConfig, log *slog.Logger) *Redactor {
 		}
 	}
 
-	return &Redactor{
-		customPattern:  combined,
-		customPatterns: individualPatterns,
+	r.customPattern = combined
+	r.customPatterns = individualPatterns
+	return r
+}
+
+// ForTool returns the redactor for the results and audit entries of a tool.
+//
+// Summary: Applies the per-tool DLP rules.
+//
+// Parameters:
+//   - toolName: string. The fully qualified tool name.
+//
+// Returns:
+//   - *Redactor: The redactor of the first matching tool rule, r itself if
+//     no rule matches, or nil if redaction is disabled for the tool.
+func (r *Redactor) ForTool(toolName string) *Redactor {
+	if r == nil {
+		return nil
+	}
+	for _, rule := range r.toolRules {
+		for _, pattern := range rule.tools {
+			if ok, _ := path.Match(pattern, toolName); ok {
+				return rule.redactor
+			}
+		}
+	}
+	return r
+}
+
+// replace masks the matches of re that no allow pattern matches.
+func (r *Redactor) replace(re *regexp.Regexp, s string) string {
+	if len(r.allowPatterns) == 0 {
+		return re.ReplaceAllString(s, redactedStr)
 	}
+	return re.ReplaceAllStringFunc(s, func(match string) string {
+		for _, allow := range r.allowPatterns {
+			if allow.MatchString(match) {
+				return match
+			}
+		}
+		return redactedStr
+	})
 }
 
 // RedactJSON redacts sensitive information from a JSON byte slice.
@@ -114,7 +219,7 @@ func (r *Redactor) RedactJSON(data []byte) ([]byte, error) {
 		// If we have no custom patterns, we can skip unmarshaling if the raw bytes
 		// (including quotes) don't contain indicators of PII: '@', digits, or escapes.
 		// This avoids expensive json.Unmarshal for the vast majority of safe strings.
-		if r.customPattern == nil && len(r.customPatterns) == 0 {
+		if r.customPattern == nil && len(r.customPatterns) == 0 && !r.apiKey {
 			hasIndicator := false
 			// Check for '@' and '\' first using optimized SIMD scan
 			if bytes.IndexByte(raw, '@') != -1 || bytes.IndexByte(raw, '\\') != -1 {
@@ -195,22 +300,31 @@ func (r *Redactor) RedactString(s string) string {
 	res := s
 
 	// Only run email regex if '@' is present
-	if hasAt {
-		res = emailRegex.ReplaceAllString(res, redactedStr)
+	if hasAt && r.email {
+		res = r.replace(emailRegex, res)
 	}
 
 	// Only run CC and SSN regexes if digits are present
 	if hasDigit {
-		res = creditCardRegex.ReplaceAllString(res, redactedStr)
-		res = ssnRegex.ReplaceAllString(res, redactedStr)
+		if r.creditCard {
+			res = r.replace(creditCardRegex, res)
+		}
+		if r.ssn {
+			res = r.replace(ssnRegex, res)
+		}
+	}
+
+	// API keys are at least 16 characters long
+	if r.apiKey && len(res) >= 16 {
+		res = r.replace(apiKeyRegex, res)
 	}
 
 	// Always run custom patterns as we don't know their characteristics
 	if r.customPattern != nil {
-		res = r.customPattern.ReplaceAllString(res, redactedStr)
+		res = r.replace(r.customPattern, res)
 	} else {
 		for _, p := range r.customPatterns {
-			res = p.ReplaceAllString(res, redactedStr)
+			res = r.replace(p, res)
 		}
 	}
 	return res
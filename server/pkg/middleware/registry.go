@@ -120,7 +120,9 @@ func GetMCPMiddlewares(configs []*configv1.Middleware) []func(mcp.MethodHandler)
 // StandardMiddlewares holds the standard middlewares that might need to be updated.
 type StandardMiddlewares struct {
 	Audit            *AuditMiddleware
+	RateLimit        *RateLimitMiddleware
 	GlobalRateLimit  *GlobalRateLimitMiddleware
+	Quota            *QuotaMiddleware
 	ContextOptimizer *ContextOptimizer
 	Debugger         *Debugger
 	SmartRecovery    *SmartRecoveryMiddleware
@@ -295,6 +297,16 @@ func InitStandardMiddlewares(
 		}
 	})
 
+	// Quotas, configured by the caller through UpdateConfig.
+	quota := NewQuotaMiddleware(nil)
+	RegisterMCP("quota", func(_ *configv1.Middleware) func(mcp.MethodHandler) mcp.MethodHandler {
+		return func(next mcp.MethodHandler) mcp.MethodHandler {
+			return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+				return quota.Execute(ctx, method, req, next)
+			}
+		}
+	})
+
 	// DLP
 	RegisterMCP("dlp", func(_ *configv1.Middleware) func(mcp.MethodHandler) mcp.MethodHandler {
 		// Logger will be injected by DLPMiddleware constructor or we use default?
@@ -373,7 +385,9 @@ func InitStandardMiddlewares(
 
 	return &StandardMiddlewares{
 		Audit:            audit,
+		RateLimit:        rateLimit,
 		GlobalRateLimit:  globalRateLimit,
+		Quota:            quota,
 		ContextOptimizer: contextOptimizer,
 		Debugger:         debugger,
 		SmartRecovery:    smartRecovery,
@@ -7,6 +7,7 @@ import (
 	"context"
 	"sync"
 
+	"github.com/mcpany/core/server/pkg/federation"
 	"github.com/mcpany/core/server/pkg/resilience"
 	"github.com/mcpany/core/server/pkg/tool"
 )
@@ -52,7 +53,12 @@ func NewResilienceMiddleware(toolManager tool.ManagerInterface) *ResilienceMiddl
 //   - May retry the execution on failure.
 //   - Records success/failure to update circuit breaker stats.
 func (m *ResilienceMiddleware) Execute(ctx context.Context, req *tool.ExecutionRequest, next tool.ExecutionFunc) (any, error) {
-	t, ok := m.toolManager.GetTool(req.ToolName)
+	// The tool in the context is the one called, e.g. the canary of the tool
+	// named in the request, whose failures must not open the stable breaker.
+	t, ok := tool.GetFromContext(ctx)
+	if !ok {
+		t, ok = m.toolManager.GetTool(req.ToolName)
+	}
 	if !ok {
 		return next(ctx, req)
 	}
@@ -73,13 +79,40 @@ func (m *ResilienceMiddleware) Execute(ctx context.Context, req *tool.ExecutionR
 	return result, err
 }
 
+// CircuitBreakers returns the circuit breakers created so far, by the ID of
+// their service. A breaker is created on the first call of its service.
+//
+// Summary: Lists the circuit breakers of the services.
+//
+// Returns:
+//   - map[string]*resilience.CircuitBreaker: The breakers, by service ID.
+func (m *ResilienceMiddleware) CircuitBreakers() map[string]*resilience.CircuitBreaker {
+	breakers := make(map[string]*resilience.CircuitBreaker)
+	m.managers.Range(func(key, value any) bool {
+		if cb := value.(*resilience.Manager).CircuitBreaker(); cb != nil {
+			breakers[key.(string)] = cb
+		}
+		return true
+	})
+	return breakers
+}
+
 func (m *ResilienceMiddleware) getManager(serviceID string) *resilience.Manager {
 	if val, ok := m.managers.Load(serviceID); ok {
 		return val.(*resilience.Manager)
 	}
 
 	serviceInfo, ok := m.toolManager.GetServiceInfo(serviceID)
-	if !ok || serviceInfo.Config == nil || serviceInfo.Config.GetResilience() == nil {
+	if !ok || serviceInfo.Config == nil {
+		return nil
+	}
+	config := serviceInfo.Config.GetResilience()
+	// mcpany peers get a circuit breaker even if none is configured, so a
+	// failing peer does not hold up the calls routed through it.
+	if serviceInfo.Config.GetMcpService().GetPeer() != nil {
+		config = federation.Resilience(config)
+	}
+	if config == nil {
 		// Store nil to avoid repeated lookups if config is missing?
 		// But config might be updated later. For now, let's not cache nil eagerly unless we handle updates.
 		// However, syncing relies on GetServiceInfo which is fast.
@@ -87,12 +120,11 @@ func (m *ResilienceMiddleware) getManager(serviceID string) *resilience.Manager
 	}
 
 	// Double check if config actually has anything enabled
-	config := serviceInfo.Config.GetResilience()
 	if config.GetCircuitBreaker() == nil && config.GetRetryPolicy() == nil && config.GetTimeout() == nil {
 		return nil
 	}
 
-	manager := resilience.NewManager(config)
+	manager := resilience.NewManager(config, resilience.WithName(serviceID))
 
 	// We need to use LoadOrStore to avoid race conditions creating multiple managers
 	val, loaded := m.managers.LoadOrStore(serviceID, manager)
@@ -0,0 +1,113 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/slowcall"
+	"github.com/mcpany/core/server/pkg/tool"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SlowCallMiddleware logs the tool calls slower than their threshold with
+// their timing breakdown and records them in the slow call report.
+//
+// Summary: Middleware that detects and reports slow tool calls.
+type SlowCallMiddleware struct {
+	recorder *slowcall.Recorder
+}
+
+// NewSlowCallMiddleware creates a new SlowCallMiddleware.
+//
+// Summary: Initializes the slow call middleware.
+//
+// Parameters:
+//   - recorder: *slowcall.Recorder. The recorder holding the thresholds and the report.
+//
+// Returns:
+//   - *SlowCallMiddleware: The middleware.
+func NewSlowCallMiddleware(recorder *slowcall.Recorder) *SlowCallMiddleware {
+	return &SlowCallMiddleware{recorder: recorder}
+}
+
+// Execute times the tool call. It must be the first middleware, so that the
+// time spent in the other middlewares counts towards the call.
+//
+// Summary: Wraps tool execution to detect slow calls.
+//
+// Parameters:
+//   - ctx: context.Context. The execution context.
+//   - req: *tool.ExecutionRequest. The request containing tool execution details.
+//   - next: tool.ExecutionFunc. The next handler in the execution chain.
+//
+// Returns:
+//   - any: The result of the tool execution.
+//   - error: An error if the execution fails.
+//
+// Side Effects:
+//   - Logs and records the call when it is slower than its threshold.
+func (m *SlowCallMiddleware) Execute(ctx context.Context, req *tool.ExecutionRequest, next tool.ExecutionFunc) (any, error) {
+	threshold := m.recorder.Threshold(req.ToolName)
+	if threshold <= 0 {
+		return next(ctx, req)
+	}
+
+	// The timings start before the pre-call hooks, which run ahead of the
+	// middlewares.
+	start := time.Now()
+	timings, ok := tool.GetCallTimings(ctx)
+	if ok {
+		start = timings.Start()
+	} else {
+		timings = tool.NewCallTimings()
+	}
+
+	result, err := next(ctx, req)
+	duration := time.Since(start)
+	if duration < threshold {
+		return result, err
+	}
+
+	call := slowcall.Call{
+		Timestamp:   start,
+		Tool:        req.ToolName,
+		TraceID:     GetTraceID(ctx),
+		DurationMs:  duration.Milliseconds(),
+		ThresholdMs: threshold.Milliseconds(),
+		QueueMs:     timings.Get(tool.PhaseQueue).Milliseconds(),
+		WebhookMs:   timings.Get(tool.PhaseWebhook).Milliseconds(),
+		UpstreamMs:  timings.Get(tool.PhaseUpstream).Milliseconds(),
+		TransformMs: timings.Get(tool.PhaseTransform).Milliseconds(),
+	}
+	if sc := trace.SpanContextFromContext(ctx); call.TraceID == "" && sc.IsValid() {
+		call.TraceID = sc.TraceID().String()
+	}
+	if t, ok := tool.GetFromContext(ctx); ok && t.Tool() != nil {
+		call.ServiceID = t.Tool().GetServiceId()
+	}
+	if err != nil {
+		call.Error = err.Error()
+	}
+	m.recorder.Record(call)
+
+	args := []any{
+		"tool", req.ToolName,
+		"service_id", call.ServiceID,
+		"trace_id", call.TraceID,
+		"duration", duration.String(),
+		"threshold", threshold.String(),
+		"queue", timings.Get(tool.PhaseQueue).String(),
+		"webhook", timings.Get(tool.PhaseWebhook).String(),
+		"upstream", timings.Get(tool.PhaseUpstream).String(),
+		"transform", timings.Get(tool.PhaseTransform).String(),
+	}
+	if err != nil {
+		args = append(args, "error", err)
+	}
+	logging.GetLogger().Warn("Slow tool call", args...)
+	return result, err
+}
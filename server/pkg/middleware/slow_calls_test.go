@@ -0,0 +1,54 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/slowcall"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestSlowCallMiddleware(t *testing.T) {
+	recorder := slowcall.NewRecorder(configv1.SlowCallConfig_builder{
+		ToolThresholds: map[string]*durationpb.Duration{"weather.*": durationpb.New(20 * time.Millisecond)},
+	}.Build())
+	m := NewSlowCallMiddleware(recorder)
+
+	slow := func(ctx context.Context, _ *tool.ExecutionRequest) (any, error) {
+		timings, _ := tool.GetCallTimings(ctx)
+		timings.Add(tool.PhaseQueue, 5*time.Millisecond)
+		timings.Add(tool.PhaseUpstream, 30*time.Millisecond)
+		time.Sleep(30 * time.Millisecond)
+		return "done", errors.New("upstream returned 503")
+	}
+
+	ctx := tool.NewContextWithCallTimings(context.Background(), tool.NewCallTimings())
+	result, err := m.Execute(ctx, &tool.ExecutionRequest{ToolName: "weather.get_forecast"}, slow)
+	assert.Equal(t, "done", result)
+	assert.EqualError(t, err, "upstream returned 503")
+
+	// Calls of untracked tools and fast calls are not recorded.
+	_, _ = m.Execute(ctx, &tool.ExecutionRequest{ToolName: "github.list_issues"}, slow)
+	_, _ = m.Execute(context.Background(), &tool.ExecutionRequest{ToolName: "weather.get_alerts"}, func(context.Context, *tool.ExecutionRequest) (any, error) {
+		return nil, nil
+	})
+
+	report := recorder.Report(0)
+	require.Len(t, report.Calls, 1)
+	call := report.Calls[0]
+	assert.Equal(t, "weather.get_forecast", call.Tool)
+	assert.GreaterOrEqual(t, call.DurationMs, int64(30))
+	assert.Equal(t, int64(20), call.ThresholdMs)
+	assert.Equal(t, int64(5), call.QueueMs)
+	assert.Equal(t, int64(30), call.UpstreamMs)
+	assert.Equal(t, "upstream returned 503", call.Error)
+}
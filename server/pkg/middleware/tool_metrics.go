@@ -9,6 +9,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/mcpany/core/server/pkg/enrichment"
+	"github.com/mcpany/core/server/pkg/metrics"
+	"github.com/mcpany/core/server/pkg/resilience"
 	"github.com/mcpany/core/server/pkg/tokenizer"
 	"github.com/mcpany/core/server/pkg/tool"
 	"github.com/mcpany/core/server/pkg/util"
@@ -19,25 +22,15 @@ import (
 var (
 	registerMetricsOnce sync.Once
 
-	// Define Prometheus metrics.
-	toolExecutionDuration = prometheus.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name:    "mcpany_tools_call_latency_seconds",
-			Help:    "Histogram of tool execution duration in seconds.",
-			Buckets: prometheus.DefBuckets, // Use default buckets or customize
-		},
-		[]string{"tool", "service_id", "status", "error_type"},
-	)
+	// toolExecutionDuration, toolExecutionTotal and toolExecutionTokensTotal
+	// also carry the labels of the label enrichers, so they are created when
+	// the middleware is first built.
+	toolExecutionDuration    *prometheus.HistogramVec
+	toolExecutionTotal       *prometheus.CounterVec
+	toolExecutionTokensTotal *prometheus.CounterVec
 
-	toolExecutionTotal = prometheus.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "mcpany_tools_call_total",
-			// Help string must match the existing registration to avoid conflicts.
-			// The conflicting registration seems to use the name as the help string.
-			Help: "mcpany_tools_call_total",
-		},
-		[]string{"tool", "service_id", "status", "error_type"},
-	)
+	// enrichedLabels are the enrichment labels the metrics were created with.
+	enrichedLabels []string
 
 	toolExecutionInputBytes = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -59,14 +52,6 @@ var (
 		[]string{"tool", "service_id"},
 	)
 
-	toolExecutionTokensTotal = prometheus.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "mcpany_tools_call_tokens_total",
-			Help: "Total number of tokens in tool executions.",
-		},
-		[]string{"tool", "service_id", "direction"}, // direction: input, output
-	)
-
 	toolExecutionsInFlight = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "mcpany_tools_call_in_flight",
@@ -74,6 +59,39 @@ var (
 		},
 		[]string{"tool", "service_id"},
 	)
+
+	// serviceCallDuration is created with the configured latency buckets
+	// when the middleware is first built.
+	serviceCallDuration *prometheus.HistogramVec
+
+	serviceCallTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mcpany_service_calls_total",
+			Help: "Total number of tool calls per upstream service.",
+		},
+		[]string{"service_id", "status", "error_type"},
+	)
+
+	serviceCallsInFlight = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mcpany_service_calls_in_flight",
+			Help: "Current number of tool calls in flight per upstream service.",
+		},
+		[]string{"service_id"},
+	)
+)
+
+// Values of the error_type label of the tool and service call metrics.
+const (
+	errorTypeNone             = "none"
+	errorTypeToolError        = "tool_error"
+	errorTypeCanceled         = "context_canceled"
+	errorTypeDeadlineExceeded = "deadline_exceeded"
+	errorTypeToolNotFound     = "tool_not_found"
+	errorTypeInvalidArguments = "invalid_arguments"
+	errorTypeRateLimited      = "rate_limited"
+	errorTypeCircuitOpen      = "circuit_open"
+	errorTypeExecutionFailed  = "execution_failed"
 )
 
 // ToolMetricsMiddleware provides detailed metrics for tool executions.
@@ -95,9 +113,49 @@ type ToolMetricsMiddleware struct {
 //   - *ToolMetricsMiddleware: A new instance of ToolMetricsMiddleware with metrics registered.
 //
 // Side Effects:
-//   - Registers Prometheus metrics (globally, once).
+//   - Registers Prometheus metrics (globally, once), labelled with the labels
+//     of the label enrichers and using the latency buckets active at that time.
 func NewToolMetricsMiddleware(t tokenizer.Tokenizer) *ToolMetricsMiddleware {
 	registerMetricsOnce.Do(func() {
+		// Label names and buckets cannot change once registered; enrichers
+		// configured later only add their labels to audit entries until
+		// restart.
+		enrichedLabels = enrichment.LabelNames()
+		buckets := metrics.LatencyBuckets()
+		resultLabels := append([]string{"tool", "service_id", "status", "error_type"}, enrichedLabels...)
+		toolExecutionDuration = prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "mcpany_tools_call_latency_seconds",
+				Help:    "Histogram of tool execution duration in seconds.",
+				Buckets: buckets,
+			},
+			resultLabels,
+		)
+		serviceCallDuration = prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "mcpany_service_call_latency_seconds",
+				Help:    "Histogram of tool call duration per upstream service in seconds.",
+				Buckets: buckets,
+			},
+			[]string{"service_id", "status"},
+		)
+		toolExecutionTotal = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "mcpany_tools_call_total",
+				// Help string must match the existing registration to avoid conflicts.
+				// The conflicting registration seems to use the name as the help string.
+				Help: "mcpany_tools_call_total",
+			},
+			resultLabels,
+		)
+		toolExecutionTokensTotal = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "mcpany_tools_call_tokens_total",
+				Help: "Total number of tokens in tool executions.",
+			},
+			append([]string{"tool", "service_id", "direction"}, enrichedLabels...), // direction: input, output
+		)
+
 		// Register metrics with the default registry (which server/pkg/metrics also uses/exposes)
 		prometheus.MustRegister(toolExecutionDuration)
 		prometheus.MustRegister(toolExecutionTotal)
@@ -105,6 +163,9 @@ func NewToolMetricsMiddleware(t tokenizer.Tokenizer) *ToolMetricsMiddleware {
 		prometheus.MustRegister(toolExecutionOutputBytes)
 		prometheus.MustRegister(toolExecutionTokensTotal)
 		prometheus.MustRegister(toolExecutionsInFlight)
+		prometheus.MustRegister(serviceCallDuration)
+		prometheus.MustRegister(serviceCallTotal)
+		prometheus.MustRegister(serviceCallsInFlight)
 	})
 	if t == nil {
 		t = tokenizer.NewSimpleTokenizer()
@@ -142,8 +203,15 @@ func (m *ToolMetricsMiddleware) Execute(ctx context.Context, req *tool.Execution
 		"service_id": serviceID,
 	}
 
+	var extra map[string]string
+	if len(enrichedLabels) > 0 {
+		extra = enrichment.Enrich(ctx, enrichment.Call{ToolName: req.ToolName, ServiceID: serviceID})
+	}
+
 	toolExecutionsInFlight.With(labels).Inc()
 	defer toolExecutionsInFlight.With(labels).Dec()
+	serviceCallsInFlight.WithLabelValues(serviceID).Inc()
+	defer serviceCallsInFlight.WithLabelValues(serviceID).Dec()
 
 	start := time.Now()
 
@@ -152,54 +220,85 @@ func (m *ToolMetricsMiddleware) Execute(ctx context.Context, req *tool.Execution
 	inputTokens := m.countInputTokens(req)
 
 	toolExecutionInputBytes.With(labels).Observe(float64(inputSize))
-	toolExecutionTokensTotal.With(prometheus.Labels{
+	toolExecutionTokensTotal.With(withEnrichedLabels(prometheus.Labels{
 		"tool":       req.ToolName,
 		"service_id": serviceID,
 		"direction":  "input",
-	}).Add(float64(inputTokens))
+	}, extra)).Add(float64(inputTokens))
 
 	result, err := next(ctx, req)
 
 	duration := time.Since(start).Seconds()
 
 	status := "success"
-	errorType := "none"
-	if err != nil {
+	errorType := classifyError(result, err)
+	if errorType != errorTypeNone {
 		status = "error"
-		errorType = "execution_failed"
-
-		switch {
-		case errors.Is(err, context.Canceled):
-			errorType = "context_canceled"
-		case errors.Is(err, context.DeadlineExceeded):
-			errorType = "deadline_exceeded"
-		}
 	}
 
-	resultLabels := prometheus.Labels{
+	resultLabels := withEnrichedLabels(prometheus.Labels{
 		"tool":       req.ToolName,
 		"service_id": serviceID,
 		"status":     status,
 		"error_type": errorType,
-	}
+	}, extra)
 
 	toolExecutionTotal.With(resultLabels).Inc()
 	toolExecutionDuration.With(resultLabels).Observe(duration)
+	serviceCallTotal.WithLabelValues(serviceID, status, errorType).Inc()
+	serviceCallDuration.WithLabelValues(serviceID, status).Observe(duration)
 
 	// Calculate output size and tokens
 	outputSize := calculateOutputSize(result)
 	outputTokens := m.countOutputTokens(result)
 
 	toolExecutionOutputBytes.With(labels).Observe(float64(outputSize))
-	toolExecutionTokensTotal.With(prometheus.Labels{
+	toolExecutionTokensTotal.With(withEnrichedLabels(prometheus.Labels{
 		"tool":       req.ToolName,
 		"service_id": serviceID,
 		"direction":  "output",
-	}).Add(float64(outputTokens))
+	}, extra)).Add(float64(outputTokens))
 
 	return result, err
 }
 
+// classifyError returns the error_type label of a call. A tool result that
+// reports an error is classified as tool_error; failures of the call itself
+// by their cause.
+func classifyError(result any, err error) string {
+	var circuitOpen *resilience.CircuitBreakerOpenError
+	switch {
+	case err == nil:
+		if ctr, ok := result.(*mcp.CallToolResult); ok && ctr.IsError {
+			return errorTypeToolError
+		}
+		return errorTypeNone
+	case errors.Is(err, context.Canceled):
+		return errorTypeCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return errorTypeDeadlineExceeded
+	case errors.Is(err, tool.ErrToolNotFound):
+		return errorTypeToolNotFound
+	case errors.Is(err, tool.ErrInvalidArguments):
+		return errorTypeInvalidArguments
+	case errors.Is(err, ErrRateLimited):
+		return errorTypeRateLimited
+	case errors.As(err, &circuitOpen):
+		return errorTypeCircuitOpen
+	default:
+		return errorTypeExecutionFailed
+	}
+}
+
+// withEnrichedLabels adds the enrichment labels the metrics were created with,
+// leaving the ones without a value empty.
+func withEnrichedLabels(labels prometheus.Labels, values map[string]string) prometheus.Labels {
+	for _, name := range enrichedLabels {
+		labels[name] = values[name]
+	}
+	return labels
+}
+
 func (m *ToolMetricsMiddleware) countInputTokens(req *tool.ExecutionRequest) int {
 	// Try to use Arguments map first as it's already parsed
 	if req.Arguments != nil {
@@ -6,10 +6,13 @@ package middleware
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	v1 "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/mcpany/core/server/pkg/resilience"
 	"github.com/mcpany/core/server/pkg/tool"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/prometheus/client_golang/prometheus"
@@ -301,6 +304,77 @@ func TestToolMetricsMiddleware_Execute(t *testing.T) {
 	})
 }
 
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name   string
+		result any
+		err    error
+		want   string
+	}{
+		{"success", &mcp.CallToolResult{}, nil, "none"},
+		{"tool error result", &mcp.CallToolResult{IsError: true}, nil, "tool_error"},
+		{"canceled", nil, fmt.Errorf("call: %w", context.Canceled), "context_canceled"},
+		{"deadline", nil, context.DeadlineExceeded, "deadline_exceeded"},
+		{"not found", nil, tool.ErrToolNotFound, "tool_not_found"},
+		{"invalid arguments", nil, &tool.ArgumentValidationError{Tool: "t"}, "invalid_arguments"},
+		{"rate limited", nil, fmt.Errorf("%w for tool t: %w", ErrRateLimited, errors.New("blocked")), "rate_limited"},
+		{"circuit open", nil, &resilience.CircuitBreakerOpenError{}, "circuit_open"},
+		{"other", nil, errors.New("connection refused"), "execution_failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, classifyError(tt.result, tt.err))
+		})
+	}
+}
+
+func TestToolMetricsMiddleware_ServiceMetrics(t *testing.T) {
+	middleware := NewToolMetricsMiddleware(nil)
+	serviceID := "service_metrics_test"
+	mockProtoTool := v1.Tool_builder{ServiceId: &serviceID}.Build()
+	ctx := tool.NewContextWithTool(context.Background(), &tool.MockTool{ToolFunc: func() *v1.Tool { return mockProtoTool }})
+
+	// gathered returns the value of the counter or gauge with the given labels.
+	gathered := func(name string, labels map[string]string) float64 {
+		families, err := prometheus.DefaultGatherer.Gather()
+		require.NoError(t, err)
+		for _, mf := range families {
+			if mf.GetName() != name {
+				continue
+			}
+			for _, m := range mf.GetMetric() {
+				matched := 0
+				for _, l := range m.GetLabel() {
+					if v, ok := labels[l.GetName()]; ok && v == l.GetValue() {
+						matched++
+					}
+				}
+				if matched == len(labels) {
+					return m.GetCounter().GetValue() + m.GetGauge().GetValue()
+				}
+			}
+		}
+		return 0
+	}
+
+	next := func(_ context.Context, _ *tool.ExecutionRequest) (any, error) {
+		assert.Equal(t, float64(1), gathered("mcpany_service_calls_in_flight", map[string]string{"service_id": serviceID}))
+		return &mcp.CallToolResult{IsError: true}, nil
+	}
+	_, err := middleware.Execute(ctx, &tool.ExecutionRequest{ToolName: "service_metrics_a"}, next)
+	require.NoError(t, err)
+	_, err = middleware.Execute(ctx, &tool.ExecutionRequest{ToolName: "service_metrics_b"}, next)
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(0), gathered("mcpany_service_calls_in_flight", map[string]string{"service_id": serviceID}))
+	assert.Equal(t, float64(2), gathered("mcpany_service_calls_total", map[string]string{
+		"service_id": serviceID, "status": "error", "error_type": "tool_error",
+	}))
+	assert.Equal(t, float64(1), gathered("mcpany_tools_call_total", map[string]string{
+		"tool": "service_metrics_a", "service_id": serviceID, "status": "error", "error_type": "tool_error",
+	}))
+}
+
 func BenchmarkToolMetricsMiddleware_Execute(b *testing.B) {
 	middleware := NewToolMetricsMiddleware(nil)
 	req := &tool.ExecutionRequest{
@@ -0,0 +1,62 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mcpany/core/server/pkg/telemetry"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span attributes of MCP requests.
+const (
+	attrMCPMethod    = attribute.Key("mcp.method.name")
+	attrMCPSessionID = attribute.Key("mcp.session.id")
+	attrMCPToolName  = attribute.Key("gen_ai.tool.name")
+	attrMCPIsError   = attribute.Key("mcp.tool.is_error")
+)
+
+// errToolResult marks the span of a tool call whose result is an error.
+var errToolResult = errors.New("tool returned an error result")
+
+// TracingMiddleware creates a middleware that records a span for every MCP
+// request. The span is the parent of the spans of the upstream calls made
+// for the request, which carry its context to the upstreams.
+//
+// Summary: Middleware that traces MCP request handling.
+//
+// Returns:
+//   - mcp.Middleware: The configured middleware function.
+func TracingMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			name := method
+			attrs := []attribute.KeyValue{attrMCPMethod.String(method)}
+			if r, ok := req.(*mcp.CallToolRequest); ok && r.Params != nil {
+				name += " " + r.Params.Name
+				attrs = append(attrs, attrMCPToolName.String(r.Params.Name))
+			}
+			if session := req.GetSession(); session != nil {
+				if ss, ok := session.(*mcp.ServerSession); !ok || ss != nil {
+					if id := session.ID(); id != "" {
+						attrs = append(attrs, attrMCPSessionID.String(id))
+					}
+				}
+			}
+
+			ctx, span := telemetry.StartSpan(ctx, name, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+			result, err := next(ctx, method, req)
+			if ctr, ok := result.(*mcp.CallToolResult); ok && err == nil && ctr.IsError {
+				telemetry.EndSpan(span, errToolResult, attrMCPIsError.Bool(true))
+				return result, err
+			}
+			telemetry.EndSpan(span, err)
+			return result, err
+		}
+	}
+}
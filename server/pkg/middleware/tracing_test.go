@@ -0,0 +1,62 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracingMiddleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(prev)
+
+	var inner trace.SpanContext
+	handler := TracingMiddleware()(func(ctx context.Context, method string, _ mcp.Request) (mcp.Result, error) {
+		inner = trace.SpanContextFromContext(ctx)
+		switch method {
+		case "tools/call":
+			return &mcp.CallToolResult{IsError: true}, nil
+		case "resources/read":
+			return nil, errors.New("not found")
+		}
+		return &mcp.ListToolsResult{}, nil
+	})
+
+	ctx := context.Background()
+	_, err := handler(ctx, "tools/list", &mcp.ListToolsRequest{})
+	require.NoError(t, err)
+	_, err = handler(ctx, "tools/call", &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "weather.get"}})
+	require.NoError(t, err)
+	_, err = handler(ctx, "resources/read", &mcp.ReadResourceRequest{})
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	assert.Equal(t, "tools/list", spans[0].Name())
+	assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	assert.Equal(t, "tools/call weather.get", spans[1].Name())
+	assert.Contains(t, spans[1].Attributes(), attrMCPToolName.String("weather.get"))
+	assert.Contains(t, spans[1].Attributes(), attrMCPIsError.Bool(true))
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+
+	assert.Equal(t, "resources/read", spans[2].Name())
+	assert.Equal(t, "not found", spans[2].Status().Description)
+	assert.Equal(t, spans[2].SpanContext().SpanID(), inner.SpanID(), "the handler runs in the span")
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package notify posts operational events, such as opened circuit breakers,
+// failed configuration reloads and unhealthy upstream services, to webhooks
+// and chat channels.
+package notify
+
+import "time"
+
+// Types of the events.
+const (
+	// EventCircuitBreakerOpened is sent when the circuit breaker of a service
+	// opens and starts rejecting calls.
+	EventCircuitBreakerOpened = "circuit_breaker.opened"
+	// EventCircuitBreakerClosed is sent when the circuit breaker of a service
+	// closes again after a successful probe.
+	EventCircuitBreakerClosed = "circuit_breaker.closed"
+	// EventConfigReloadFailed is sent when the configuration cannot be
+	// reloaded. The server keeps running with the previous configuration.
+	EventConfigReloadFailed = "config.reload_failed"
+	// EventUpstreamDown is sent when the health check of an upstream service
+	// starts failing.
+	EventUpstreamDown = "upstream.down"
+	// EventUpstreamUp is sent when an upstream service is healthy again.
+	EventUpstreamUp = "upstream.up"
+	// EventDoctorCheckRegressed is sent when a doctor check that passed
+	// fails.
+	EventDoctorCheckRegressed = "doctor.check_regressed"
+	// EventAuthRepeatedFailures is sent when a client IP fails to
+	// authenticate repeatedly.
+	EventAuthRepeatedFailures = "auth.repeated_failures"
+	// EventCanaryRolledBack is sent when the canary of a service is rolled
+	// back because its error rate exceeds the maximum.
+	EventCanaryRolledBack = "canary.rolled_back"
+)
+
+// Severity is the severity of an event.
+type Severity string
+
+const (
+	// SeverityCritical needs immediate attention.
+	SeverityCritical Severity = "critical"
+	// SeverityWarning may need attention.
+	SeverityWarning Severity = "warning"
+	// SeverityInfo is informational, e.g. a recovery.
+	SeverityInfo Severity = "info"
+)
+
+// Event is an operational event sent to the notification sinks.
+//
+// Summary: A notification of an operational event.
+type Event struct {
+	// ID is the unique ID of the event. Set by Notify if empty.
+	ID string `json:"id"`
+	// Type is the type of the event, e.g. EventCircuitBreakerOpened.
+	Type string `json:"type"`
+	// Severity is the severity of the event.
+	Severity Severity `json:"severity"`
+	// Subject is what the event is about, e.g. the ID of the service.
+	Subject string `json:"subject,omitempty"`
+	// Message is a human-readable description of the event.
+	Message string `json:"message"`
+	// Time is when the event occurred. Set by Notify if zero.
+	Time time.Time `json:"time"`
+	// Data holds the details of the event.
+	Data map[string]any `json:"data,omitempty"`
+}
@@ -0,0 +1,347 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/idgen"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/metrics"
+)
+
+const (
+	defaultCooldown             = 5 * time.Minute
+	defaultAuthFailureThreshold = 10
+	defaultAuthFailureWindow    = 5 * time.Minute
+	// queueSize bounds the events waiting to be sent. Events are dropped
+	// when it is full, so notifications never block the server.
+	queueSize    = 256
+	sendAttempts = 3
+	sendTimeout  = 10 * time.Second
+	// maxAuthFailureClients bounds the client IPs whose failures are
+	// tracked. Clients without recent failures are forgotten first.
+	maxAuthFailureClients = 10000
+)
+
+var (
+	metricNotifySent    = []string{"notify", "sent"}
+	metricNotifyFailed  = []string{"notify", "failed"}
+	metricNotifyDropped = []string{"notify", "dropped"}
+)
+
+// Notifier sends operational events to the configured sinks.
+//
+// Events are queued and sent in the background, retrying failed requests. A
+// full queue drops events, so a slow or unavailable sink never holds up the
+// server. Events of the same type and subject are sent at most once per
+// cooldown, so a flapping service does not flood the sinks.
+//
+// Summary: Non-blocking sender of operational notifications.
+type Notifier struct {
+	client     *http.Client
+	now        func() time.Time
+	retryDelay time.Duration
+
+	mu            sync.Mutex
+	sinks         []*sink
+	cooldown      time.Duration
+	authThreshold int
+	authWindow    time.Duration
+	// lastSent maps the type and subject of the events to when they were
+	// last queued.
+	lastSent map[string]time.Time
+	// authFailures maps client IPs to the times of their recent failures.
+	authFailures map[string][]time.Time
+
+	queue  chan Event
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewNotifier creates a notifier. Call Start to send the queued events.
+//
+// Summary: Initializes the notifier.
+//
+// Parameters:
+//   - config: *configv1.NotificationConfig. The configuration, or nil for no sinks.
+//
+// Returns:
+//   - *Notifier: The notifier.
+func NewNotifier(config *configv1.NotificationConfig) *Notifier {
+	n := &Notifier{
+		client:       &http.Client{Timeout: sendTimeout},
+		now:          time.Now,
+		retryDelay:   time.Second,
+		lastSent:     make(map[string]time.Time),
+		authFailures: make(map[string][]time.Time),
+		queue:        make(chan Event, queueSize),
+	}
+	n.SetConfig(config)
+	return n
+}
+
+// SetConfig replaces the sinks and settings of the notifier.
+//
+// Summary: Applies a new notification configuration.
+//
+// Parameters:
+//   - config: *configv1.NotificationConfig. The configuration, or nil for no sinks.
+//
+// Side Effects:
+//   - Events already queued are sent to the new sinks.
+func (n *Notifier) SetConfig(config *configv1.NotificationConfig) {
+	sinks := make([]*sink, 0, len(config.GetSinks()))
+	for _, sc := range config.GetSinks() {
+		sinks = append(sinks, newSink(sc))
+	}
+	cooldown := defaultCooldown
+	if config.HasCooldown() {
+		cooldown = config.GetCooldown().AsDuration()
+	}
+	threshold := defaultAuthFailureThreshold
+	if t := config.GetAuthFailureThreshold(); t > 0 {
+		threshold = int(t)
+	}
+	window := defaultAuthFailureWindow
+	if w := config.GetAuthFailureWindow().AsDuration(); w > 0 {
+		window = w
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sinks = sinks
+	n.cooldown = cooldown
+	n.authThreshold = threshold
+	n.authWindow = window
+}
+
+// Notify queues an event for the sinks subscribed to its type. It never
+// blocks.
+//
+// Summary: Sends an operational event.
+//
+// Parameters:
+//   - event: Event. The event. ID and Time are set if empty.
+//
+// Side Effects:
+//   - Queues the event, or drops it if it is within the cooldown of the
+//     previous event of the same type and subject or the queue is full.
+func (n *Notifier) Notify(event Event) {
+	if event.ID == "" {
+		event.ID = idgen.New()
+	}
+	if event.Time.IsZero() {
+		event.Time = n.now()
+	}
+
+	n.mu.Lock()
+	if len(n.sinks) == 0 {
+		n.mu.Unlock()
+		return
+	}
+	key := event.Type + "|" + event.Subject
+	if last, ok := n.lastSent[key]; ok && event.Time.Sub(last) < n.cooldown {
+		n.mu.Unlock()
+		logging.GetLogger().Debug("Notification suppressed by cooldown", "type", event.Type, "subject", event.Subject)
+		return
+	}
+	n.lastSent[key] = event.Time
+	n.mu.Unlock()
+
+	select {
+	case n.queue <- event:
+	default:
+		metrics.IncrCounter(metricNotifyDropped, 1)
+		logging.GetLogger().Warn("Notification queue is full, dropping event", "type", event.Type, "subject", event.Subject)
+	}
+}
+
+// RecordAuthFailure records a failed authentication of a client and sends
+// an auth.repeated_failures event when the client reaches the failure
+// threshold within the window.
+//
+// Summary: Tracks failed authentications per client IP.
+//
+// Parameters:
+//   - ip: string. The IP of the client.
+//
+// Side Effects:
+//   - May queue an EventAuthRepeatedFailures event.
+func (n *Notifier) RecordAuthFailure(ip string) {
+	now := n.now()
+
+	n.mu.Lock()
+	window, threshold := n.authWindow, n.authThreshold
+	if len(n.authFailures) >= maxAuthFailureClients {
+		n.pruneAuthFailures(now)
+	}
+	failures := n.authFailures[ip]
+	i := 0
+	for i < len(failures) && now.Sub(failures[i]) > window {
+		i++
+	}
+	failures = append(failures[i:], now)
+	if len(failures) < threshold {
+		n.authFailures[ip] = failures
+		n.mu.Unlock()
+		return
+	}
+	// Start counting again, so that the next event needs as many failures.
+	delete(n.authFailures, ip)
+	n.mu.Unlock()
+
+	n.Notify(Event{
+		Type:     EventAuthRepeatedFailures,
+		Severity: SeverityWarning,
+		Subject:  ip,
+		Message:  fmt.Sprintf("%d failed authentications from %s within %s.", len(failures), ip, window),
+		Data: map[string]any{
+			"ip":       ip,
+			"failures": len(failures),
+			"window":   window.String(),
+		},
+	})
+}
+
+// pruneAuthFailures forgets the clients without failures within the window,
+// or all of them if they all failed recently. Caller must hold the mutex.
+func (n *Notifier) pruneAuthFailures(now time.Time) {
+	for ip, failures := range n.authFailures {
+		if now.Sub(failures[len(failures)-1]) > n.authWindow {
+			delete(n.authFailures, ip)
+		}
+	}
+	if len(n.authFailures) >= maxAuthFailureClients {
+		n.authFailures = make(map[string][]time.Time)
+	}
+}
+
+// Start starts sending the queued events in the background.
+//
+// Summary: Starts the notification sender.
+//
+// Parameters:
+//   - ctx: context.Context. The context; the sender runs until Stop is called.
+//
+// Returns:
+//   - error: Always nil.
+//
+// Side Effects:
+//   - Starts a background goroutine.
+func (n *Notifier) Start(ctx context.Context) error {
+	n.mu.Lock()
+	if n.cancel != nil {
+		n.mu.Unlock()
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	n.cancel = cancel
+	n.done = make(chan struct{})
+	done := n.done
+	n.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-n.queue:
+				n.deliver(ctx, event)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops sending events. Events still queued are dropped.
+//
+// Summary: Stops the notification sender.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the wait for the event being sent.
+//
+// Returns:
+//   - error: The error of the context if the sender did not stop in time.
+func (n *Notifier) Stop(ctx context.Context) error {
+	n.mu.Lock()
+	cancel, done := n.cancel, n.done
+	n.cancel, n.done = nil, nil
+	n.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// deliver sends an event to the sinks subscribed to its type.
+func (n *Notifier) deliver(ctx context.Context, event Event) {
+	n.mu.Lock()
+	sinks := n.sinks
+	n.mu.Unlock()
+
+	for _, s := range sinks {
+		if !s.accepts(event.Type) {
+			continue
+		}
+		labels := []metrics.Label{{Name: "sink", Value: s.name}}
+		if err := n.send(ctx, s, event); err != nil {
+			metrics.IncrCounterWithLabels(metricNotifyFailed, 1, labels)
+			logging.GetLogger().Warn("Failed to send notification", "sink", s.name, "type", event.Type, "subject", event.Subject, "error", err)
+			continue
+		}
+		metrics.IncrCounterWithLabels(metricNotifySent, 1, labels)
+	}
+}
+
+// send posts an event to a sink, retrying network errors, 429 and 5xx
+// responses.
+func (n *Notifier) send(ctx context.Context, s *sink, event Event) error {
+	var err error
+	for attempt := 1; attempt <= sendAttempts; attempt++ {
+		if attempt > 1 {
+			timer := time.NewTimer(time.Duration(attempt-1) * n.retryDelay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		var req *http.Request
+		req, err = s.newRequest(ctx, event)
+		if err != nil {
+			return err
+		}
+		var resp *http.Response
+		resp, err = n.client.Do(req)
+		if err != nil {
+			continue
+		}
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+		_ = resp.Body.Close()
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		err = fmt.Errorf("sink returned %s", resp.Status)
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			return err
+		}
+	}
+	return err
+}
@@ -0,0 +1,231 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type received struct {
+	header http.Header
+	body   map[string]any
+	raw    []byte
+}
+
+// receiver starts a server forwarding the requests it receives.
+func receiver(t *testing.T) (*httptest.Server, chan received) {
+	t.Helper()
+	ch := make(chan received, 16)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		var body map[string]any
+		_ = json.Unmarshal(raw, &body)
+		ch <- received{header: r.Header.Clone(), body: body, raw: raw}
+	}))
+	t.Cleanup(server.Close)
+	return server, ch
+}
+
+func next(t *testing.T, ch chan received) received {
+	t.Helper()
+	select {
+	case r := <-ch:
+		return r
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for notification")
+		return received{}
+	}
+}
+
+func startNotifier(t *testing.T, config *configv1.NotificationConfig) *Notifier {
+	t.Helper()
+	n := NewNotifier(config)
+	n.retryDelay = time.Millisecond
+	require.NoError(t, n.Start(context.Background()))
+	t.Cleanup(func() { _ = n.Stop(context.Background()) })
+	return n
+}
+
+var opened = Event{
+	ID:       "evt-1",
+	Type:     EventCircuitBreakerOpened,
+	Severity: SeverityWarning,
+	Subject:  "weather",
+	Message:  "The circuit breaker of weather opened.",
+	Time:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	Data:     map[string]any{"service": "weather"},
+}
+
+func TestNotifier_Formats(t *testing.T) {
+	server, ch := receiver(t)
+	secret := "whsec_" + base64.StdEncoding.EncodeToString([]byte("signing-key"))
+
+	t.Run("cloudevents", func(t *testing.T) {
+		n := startNotifier(t, configv1.NotificationConfig_builder{
+			Sinks: []*configv1.NotificationSinkConfig{configv1.NotificationSinkConfig_builder{
+				Url:     proto.String(server.URL),
+				Headers: map[string]string{"Authorization": "Bearer token"},
+			}.Build()},
+		}.Build())
+		n.Notify(opened)
+
+		r := next(t, ch)
+		assert.Equal(t, "application/cloudevents+json", r.header.Get("Content-Type"))
+		assert.Equal(t, "Bearer token", r.header.Get("Authorization"))
+		assert.Equal(t, "1.0", r.body["specversion"])
+		assert.Equal(t, "evt-1", r.body["id"])
+		assert.Equal(t, "circuit_breaker.opened", r.body["type"])
+		assert.Equal(t, "weather", r.body["subject"])
+		assert.Equal(t, "2026-01-02T03:04:05Z", r.body["time"])
+		assert.Contains(t, r.body["source"], "/mcpany")
+		assert.Equal(t, map[string]any{
+			"service":  "weather",
+			"subject":  "weather",
+			"severity": "warning",
+			"message":  "The circuit breaker of weather opened.",
+		}, r.body["data"])
+	})
+
+	t.Run("standard webhooks", func(t *testing.T) {
+		n := startNotifier(t, configv1.NotificationConfig_builder{
+			Sinks: []*configv1.NotificationSinkConfig{configv1.NotificationSinkConfig_builder{
+				Format: configv1.NotificationSinkConfig_FORMAT_STANDARD_WEBHOOKS.Enum(),
+				Url:    proto.String(server.URL),
+				Secret: configv1.SecretValue_builder{PlainText: proto.String(secret)}.Build(),
+			}.Build()},
+		}.Build())
+		n.Notify(opened)
+
+		r := next(t, ch)
+		assert.Equal(t, "application/json", r.header.Get("Content-Type"))
+		assert.Equal(t, "circuit_breaker.opened", r.body["type"])
+		assert.Equal(t, "evt-1", r.header.Get("webhook-id"))
+
+		mac := hmac.New(sha256.New, []byte("signing-key"))
+		_, _ = mac.Write([]byte("evt-1." + r.header.Get("webhook-timestamp") + "."))
+		_, _ = mac.Write(r.raw)
+		assert.Equal(t, "v1,"+base64.StdEncoding.EncodeToString(mac.Sum(nil)), r.header.Get("webhook-signature"))
+	})
+
+	t.Run("slack", func(t *testing.T) {
+		n := startNotifier(t, configv1.NotificationConfig_builder{
+			Sinks: []*configv1.NotificationSinkConfig{configv1.NotificationSinkConfig_builder{
+				Format: configv1.NotificationSinkConfig_FORMAT_SLACK.Enum(),
+				Url:    proto.String(server.URL),
+			}.Build()},
+		}.Build())
+		n.Notify(opened)
+
+		r := next(t, ch)
+		assert.Equal(t, ":warning: *circuit_breaker.opened* `weather`\nThe circuit breaker of weather opened.", r.body["text"])
+	})
+}
+
+func TestNotifier_FilterAndCooldown(t *testing.T) {
+	server, ch := receiver(t)
+	n := startNotifier(t, configv1.NotificationConfig_builder{
+		Sinks: []*configv1.NotificationSinkConfig{configv1.NotificationSinkConfig_builder{
+			Url:    proto.String(server.URL),
+			Events: []string{"circuit_breaker.*", EventConfigReloadFailed},
+		}.Build()},
+		Cooldown: durationpb.New(time.Minute),
+	}.Build())
+
+	n.Notify(Event{Type: EventUpstreamDown, Subject: "weather"})
+	n.Notify(Event{Type: EventCircuitBreakerOpened, Subject: "weather"})
+	n.Notify(Event{Type: EventCircuitBreakerOpened, Subject: "weather"}) // Within the cooldown.
+	n.Notify(Event{Type: EventCircuitBreakerOpened, Subject: "github"})
+	n.Notify(Event{Type: EventConfigReloadFailed, Subject: "config"})
+
+	var got []string
+	for i := 0; i < 3; i++ {
+		r := next(t, ch)
+		got = append(got, r.body["type"].(string)+" "+r.body["subject"].(string))
+	}
+	assert.Equal(t, []string{"circuit_breaker.opened weather", "circuit_breaker.opened github", "config.reload_failed config"}, got)
+	select {
+	case r := <-ch:
+		t.Fatalf("unexpected notification %s", r.raw)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestNotifier_Retry(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	n := NewNotifier(configv1.NotificationConfig_builder{
+		Sinks: []*configv1.NotificationSinkConfig{configv1.NotificationSinkConfig_builder{Url: proto.String(server.URL)}.Build()},
+	}.Build())
+	n.retryDelay = time.Millisecond
+	s := n.sinks[0]
+
+	require.NoError(t, n.send(context.Background(), s, opened))
+	assert.Equal(t, int32(2), calls.Load())
+
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer rejecting.Close()
+	calls.Store(0)
+	s = newSink(configv1.NotificationSinkConfig_builder{Url: proto.String(rejecting.URL)}.Build())
+	assert.ErrorContains(t, n.send(context.Background(), s, opened), "400 Bad Request")
+	assert.Equal(t, int32(1), calls.Load(), "client errors are not retried")
+}
+
+func TestNotifier_RecordAuthFailure(t *testing.T) {
+	server, ch := receiver(t)
+	n := startNotifier(t, configv1.NotificationConfig_builder{
+		Sinks:                []*configv1.NotificationSinkConfig{configv1.NotificationSinkConfig_builder{Url: proto.String(server.URL)}.Build()},
+		AuthFailureThreshold: proto.Int32(3),
+		AuthFailureWindow:    durationpb.New(time.Minute),
+	}.Build())
+	now := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	n.now = func() time.Time { return now }
+
+	n.RecordAuthFailure("203.0.113.7")
+	n.RecordAuthFailure("203.0.113.7")
+	now = now.Add(2 * time.Minute) // The first failures leave the window.
+	n.RecordAuthFailure("203.0.113.7")
+	n.RecordAuthFailure("198.51.100.1")
+	n.RecordAuthFailure("203.0.113.7")
+	n.RecordAuthFailure("203.0.113.7")
+
+	r := next(t, ch)
+	assert.Equal(t, "auth.repeated_failures", r.body["type"])
+	assert.Equal(t, "203.0.113.7", r.body["subject"])
+	data := r.body["data"].(map[string]any)
+	assert.Equal(t, float64(3), data["failures"])
+	assert.Equal(t, "1m0s", data["window"])
+	select {
+	case r := <-ch:
+		t.Fatalf("unexpected notification %s", r.raw)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/util"
+)
+
+// standardWebhooksSecretPrefix prefixes the base64 encoded secrets of the
+// Standard Webhooks specification.
+const standardWebhooksSecretPrefix = "whsec_"
+
+// eventSource is the CloudEvents source of the events of this server.
+var eventSource = func() string {
+	if host, err := os.Hostname(); err == nil && host != "" {
+		return "/mcpany/" + host
+	}
+	return "/mcpany"
+}()
+
+// sink is a destination of the notifications.
+type sink struct {
+	name   string
+	config *configv1.NotificationSinkConfig
+}
+
+func newSink(config *configv1.NotificationSinkConfig) *sink {
+	name := config.GetName()
+	if name == "" {
+		if u, err := url.Parse(config.GetUrl()); err == nil && u.Host != "" {
+			name = u.Host
+		} else {
+			name = "sink"
+		}
+	}
+	return &sink{name: name, config: config}
+}
+
+// accepts reports whether the sink subscribes to an event type.
+func (s *sink) accepts(eventType string) bool {
+	events := s.config.GetEvents()
+	if len(events) == 0 {
+		return true
+	}
+	for _, pattern := range events {
+		if pattern == eventType {
+			return true
+		}
+		if ok, _ := path.Match(pattern, eventType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// newRequest builds the request posting an event in the format of the sink.
+func (s *sink) newRequest(ctx context.Context, event Event) (*http.Request, error) {
+	var (
+		body        []byte
+		contentType = "application/json"
+		headers     = make(http.Header)
+		err         error
+	)
+	switch s.config.GetFormat() {
+	case configv1.NotificationSinkConfig_FORMAT_SLACK:
+		body, err = slackBody(event)
+	case configv1.NotificationSinkConfig_FORMAT_STANDARD_WEBHOOKS:
+		body, err = standardWebhookBody(event)
+		if err != nil {
+			break
+		}
+		var secret string
+		secret, err = util.ResolveSecret(ctx, s.config.GetSecret())
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve the secret: %w", err)
+		}
+		err = signStandardWebhook(headers, secret, event.ID, time.Now(), body)
+	default:
+		body, err = cloudEventBody(event)
+		contentType = "application/cloudevents+json"
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.GetUrl(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for k, v := range s.config.GetHeaders() {
+		req.Header.Set(k, v)
+	}
+	for k, v := range headers {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", contentType)
+	return req, nil
+}
+
+// cloudEventBody encodes an event as a CloudEvents 1.0 event in the
+// structured JSON mode.
+func cloudEventBody(event Event) ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"specversion":     "1.0",
+		"id":              event.ID,
+		"source":          eventSource,
+		"type":            event.Type,
+		"subject":         event.Subject,
+		"time":            event.Time.UTC().Format(time.RFC3339Nano),
+		"datacontenttype": "application/json",
+		"data":            eventData(event),
+	})
+}
+
+// standardWebhookBody encodes an event as the payload of a Standard Webhooks
+// message.
+func standardWebhookBody(event Event) ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"type":      event.Type,
+		"timestamp": event.Time.UTC().Format(time.RFC3339Nano),
+		"data":      eventData(event),
+	})
+}
+
+// eventData returns the data of an event with its subject, severity and
+// message.
+func eventData(event Event) map[string]any {
+	data := make(map[string]any, len(event.Data)+3)
+	for k, v := range event.Data {
+		data[k] = v
+	}
+	data["subject"] = event.Subject
+	data["severity"] = event.Severity
+	data["message"] = event.Message
+	return data
+}
+
+// signStandardWebhook sets the webhook-id, webhook-timestamp and
+// webhook-signature headers of a Standard Webhooks message.
+func signStandardWebhook(headers http.Header, secret, id string, timestamp time.Time, body []byte) error {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, standardWebhooksSecretPrefix))
+	if err != nil {
+		return fmt.Errorf("the secret is not a base64 %s secret: %w", standardWebhooksSecretPrefix, err)
+	}
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(id + "." + ts + "."))
+	_, _ = mac.Write(body)
+
+	headers.Set("webhook-id", id)
+	headers.Set("webhook-timestamp", ts)
+	headers.Set("webhook-signature", "v1,"+base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	return nil
+}
+
+// slackBody encodes an event as the message of a Slack incoming webhook.
+func slackBody(event Event) ([]byte, error) {
+	icon := ":information_source:"
+	switch event.Severity {
+	case SeverityCritical:
+		icon = ":rotating_light:"
+	case SeverityWarning:
+		icon = ":warning:"
+	}
+	text := fmt.Sprintf("%s *%s*", icon, event.Type)
+	if event.Subject != "" {
+		text += " `" + event.Subject + "`"
+	}
+	text += "\n" + event.Message
+	return json.Marshal(map[string]string{"text": text})
+}
@@ -0,0 +1,282 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/logging"
+)
+
+const (
+	// MaxToolRounds bounds the model turns of a chat completion whose tool
+	// calls are run server-side.
+	MaxToolRounds = 8
+
+	// maxRequestBytes bounds the body of the bridge requests.
+	maxRequestBytes = 5 << 20
+	// maxResponseBytes bounds the body of the upstream responses.
+	maxResponseBytes = 10 << 20
+)
+
+// Upstream is the OpenAI-compatible model server chat completions are
+// forwarded to.
+type Upstream struct {
+	// BaseURL is the base URL of the API, e.g. "https://api.openai.com/v1".
+	BaseURL string
+	// APIKey is sent as a Bearer token, or empty for none.
+	APIKey string
+	// HTTPClient is the client of the upstream. Defaults to a client with a
+	// two minutes timeout.
+	HTTPClient *http.Client
+}
+
+// Handler serves the bridge endpoints, relative to the base path of the
+// bridge:
+//
+//   - GET /tools lists the tools as OpenAI function definitions.
+//   - POST /tools/call executes the tool calls of an assistant message and
+//     returns the "tool" messages answering them.
+//   - POST /chat/completions forwards a chat completion to the upstream. If
+//     the request defines no tools, the MCP tools are offered to the model and
+//     its tool calls are executed server-side until it answers.
+type Handler struct {
+	tools    ToolSet
+	upstream atomic.Pointer[Upstream]
+	mux      *http.ServeMux
+}
+
+// NewHandler creates the handler of the bridge.
+//
+// Parameters:
+//   - tools (ToolSet): The aggregated MCP toolset.
+//   - upstream (*Upstream): The model server, or nil to disable the chat
+//     completions endpoint.
+//
+// Returns:
+//   - *Handler: The handler.
+func NewHandler(tools ToolSet, upstream *Upstream) *Handler {
+	h := &Handler{tools: tools, mux: http.NewServeMux()}
+	h.SetUpstream(upstream)
+	h.mux.HandleFunc("/tools", h.handleTools)
+	h.mux.HandleFunc("/tools/call", h.handleToolCalls)
+	h.mux.HandleFunc("/chat/completions", h.handleChatCompletions)
+	return h
+}
+
+// SetUpstream replaces the model server chat completions are forwarded to.
+// The chat completions in progress keep the previous one.
+//
+// Parameters:
+//   - upstream (*Upstream): The model server, or nil to disable the chat
+//     completions endpoint.
+func (h *Handler) SetUpstream(upstream *Upstream) {
+	if upstream != nil && upstream.HTTPClient == nil {
+		copied := *upstream
+		copied.HTTPClient = &http.Client{Timeout: 2 * time.Minute}
+		upstream = &copied
+	}
+	h.upstream.Store(upstream)
+}
+
+// ServeHTTP serves a bridge request.
+//
+// Parameters:
+//   - w (http.ResponseWriter): The response writer.
+//   - r (*http.Request): The request, with the base path of the bridge
+//     stripped.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) handleTools(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "invalid_request_error", "method not allowed")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"object": "list",
+		"data":   NewCatalog(r.Context(), h.tools).Tools(),
+	})
+}
+
+func (h *Handler) handleToolCalls(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "invalid_request_error", "method not allowed")
+		return
+	}
+	// The body is the assistant message holding the tool calls, or any
+	// object with a "tool_calls" field.
+	var req struct {
+		ToolCalls []ToolCall `json:"tool_calls"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid request body: "+err.Error())
+		return
+	}
+	catalog := NewCatalog(r.Context(), h.tools)
+	messages := make([]ToolMessage, 0, len(req.ToolCalls))
+	for _, call := range req.ToolCalls {
+		messages = append(messages, catalog.Call(r.Context(), h.tools, call))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"messages": messages})
+}
+
+func (h *Handler) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "invalid_request_error", "method not allowed")
+		return
+	}
+	upstream := h.upstream.Load()
+	if upstream == nil || upstream.BaseURL == "" {
+		writeError(w, http.StatusNotImplemented, "not_implemented", "no upstream model server is configured for chat completions")
+		return
+	}
+	var req map[string]json.RawMessage
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid request body: "+err.Error())
+		return
+	}
+	if stream, ok := req["stream"]; ok && string(stream) == "true" {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "streaming is not supported")
+		return
+	}
+
+	// The caller defines its own tools: it executes the tool calls itself.
+	if tools, ok := req["tools"]; ok && string(tools) != "null" {
+		status, body, err := complete(r.Context(), upstream, req)
+		if err != nil {
+			h.upstreamFailed(w, err)
+			return
+		}
+		writeRaw(w, status, body)
+		return
+	}
+
+	var messages []json.RawMessage
+	if err := json.Unmarshal(req["messages"], &messages); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "messages must be an array")
+		return
+	}
+	catalog := NewCatalog(r.Context(), h.tools)
+	if len(catalog.Tools()) > 0 {
+		req["tools"], _ = json.Marshal(catalog.Tools())
+	}
+	for round := 1; ; round++ {
+		req["messages"], _ = json.Marshal(messages)
+		status, body, err := complete(r.Context(), upstream, req)
+		if err != nil {
+			h.upstreamFailed(w, err)
+			return
+		}
+		assistant, calls := toolCalls(status, body)
+		if len(calls) == 0 || round == MaxToolRounds || !allKnown(catalog, calls) {
+			writeRaw(w, status, body)
+			return
+		}
+		messages = append(messages, assistant)
+		for _, call := range calls {
+			answer, _ := json.Marshal(catalog.Call(r.Context(), h.tools, call))
+			messages = append(messages, answer)
+		}
+	}
+}
+
+// complete sends a chat completion request to the upstream.
+func complete(ctx context.Context, upstream *Upstream, req map[string]json.RawMessage) (int, []byte, error) {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	url := strings.TrimSuffix(upstream.BaseURL, "/") + "/chat/completions"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if upstream.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+upstream.APIKey)
+	}
+	resp, err := upstream.HTTPClient.Do(httpReq)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(body) > maxResponseBytes {
+		return 0, nil, errors.New("the response is too large")
+	}
+	return resp.StatusCode, body, nil
+}
+
+func (h *Handler) upstreamFailed(w http.ResponseWriter, err error) {
+	logging.GetLogger().Error("OpenAI bridge upstream request failed", "error", err)
+	writeError(w, http.StatusBadGateway, "upstream_error", fmt.Sprintf("the upstream model server failed: %v", err))
+}
+
+// toolCalls returns the assistant message of a successful completion and the
+// tool calls it requests.
+func toolCalls(status int, body []byte) (json.RawMessage, []ToolCall) {
+	if status != http.StatusOK {
+		return nil, nil
+	}
+	var resp struct {
+		Choices []struct {
+			Message json.RawMessage `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || len(resp.Choices) == 0 {
+		return nil, nil
+	}
+	var msg struct {
+		ToolCalls []ToolCall `json:"tool_calls"`
+	}
+	if err := json.Unmarshal(resp.Choices[0].Message, &msg); err != nil {
+		return nil, nil
+	}
+	return resp.Choices[0].Message, msg.ToolCalls
+}
+
+// allKnown reports whether all the tool calls are calls of MCP tools.
+func allKnown(catalog *Catalog, calls []ToolCall) bool {
+	for _, call := range calls {
+		if !catalog.Has(call.Function.Name) {
+			return false
+		}
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logging.GetLogger().Error("Failed to encode OpenAI bridge response", "error", err)
+	}
+}
+
+func writeRaw(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// writeError writes an error in the format of the OpenAI API.
+func writeError(w http.ResponseWriter, status int, errType, message string) {
+	writeJSON(w, status, map[string]any{
+		"error": map[string]string{"message": message, "type": errType},
+	})
+}
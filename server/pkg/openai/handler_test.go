@@ -0,0 +1,166 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v1 "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeToolSet serves a weather tool, and a billing tool only the "finance"
+// profile may use.
+type fakeToolSet struct {
+	calls []*tool.ExecutionRequest
+}
+
+func (f *fakeToolSet) ListTools() []tool.Tool {
+	newTool := func(service, name string, schema any) tool.Tool {
+		return &tool.MockTool{
+			ToolFunc: func() *v1.Tool {
+				return v1.Tool_builder{ServiceId: proto.String(service), Name: proto.String(name)}.Build()
+			},
+			MCPToolFunc: func() *mcp.Tool {
+				return &mcp.Tool{Name: service + "." + name, Description: "The " + name + " tool", InputSchema: schema}
+			},
+		}
+	}
+	return []tool.Tool{
+		newTool("weather", "get_forecast", map[string]any{"type": "object", "properties": map[string]any{"city": map[string]any{"type": "string"}}}),
+		newTool("billing", "list_invoices", nil),
+	}
+}
+
+func (f *fakeToolSet) CallTool(_ context.Context, req *tool.ExecutionRequest) (any, error) {
+	f.calls = append(f.calls, req)
+	if req.ToolName == "billing.list_invoices" {
+		return nil, errors.New("upstream unavailable")
+	}
+	var args struct{ City string }
+	_ = json.Unmarshal(req.ToolInputs, &args)
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "Sunny in " + args.City}}}, nil
+}
+
+func (f *fakeToolSet) IsServiceAllowed(serviceID, profileID string) bool {
+	return serviceID != "billing" || profileID == "finance"
+}
+
+func TestFunctionName(t *testing.T) {
+	assert.Equal(t, "weather__get_forecast", FunctionName("weather.get_forecast"))
+	assert.Equal(t, "my_svc__tool-1", FunctionName("my svc.tool-1"))
+	long := FunctionName("service." + strings.Repeat("a", 80))
+	assert.Len(t, long, 64)
+	assert.NotEqual(t, long, FunctionName("service."+strings.Repeat("a", 81)))
+}
+
+func TestHandler_Tools(t *testing.T) {
+	tools := &fakeToolSet{}
+	h := NewHandler(tools, nil)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tools", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list struct {
+		Data []Tool `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list.Data, 2)
+	assert.Equal(t, "function", list.Data[0].Type)
+	assert.Equal(t, "weather__get_forecast", list.Data[0].Function.Name)
+	assert.JSONEq(t, `{"type":"object","properties":{"city":{"type":"string"}}}`, string(list.Data[0].Function.Parameters))
+	assert.JSONEq(t, `{"type":"object","properties":{}}`, string(list.Data[1].Function.Parameters))
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/tools", nil)
+	h.ServeHTTP(rec, req.WithContext(auth.ContextWithProfileID(req.Context(), "support")))
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list.Data, 1, "the profile cannot use the billing service")
+	assert.Equal(t, "weather__get_forecast", list.Data[0].Function.Name)
+
+	body := `{"role": "assistant", "tool_calls": [
+		{"id": "call_1", "type": "function", "function": {"name": "weather__get_forecast", "arguments": "{\"city\": \"Paris\"}"}},
+		{"id": "call_2", "type": "function", "function": {"name": "billing__list_invoices", "arguments": ""}},
+		{"id": "call_3", "type": "function", "function": {"name": "weather__get_forecast", "arguments": "{"}}
+	]}`
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/tools/call", strings.NewReader(body))
+	h.ServeHTTP(rec, req.WithContext(auth.ContextWithProfileID(req.Context(), "support")))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"messages": [
+		{"role": "tool", "tool_call_id": "call_1", "content": "Sunny in Paris"},
+		{"role": "tool", "tool_call_id": "call_2", "content": "Tool execution failed: unknown function \"billing__list_invoices\""},
+		{"role": "tool", "tool_call_id": "call_3", "content": "Tool execution failed: the arguments are not valid JSON"}
+	]}`, rec.Body.String())
+	require.Len(t, tools.calls, 1)
+	assert.Equal(t, "weather.get_forecast", tools.calls[0].ToolName)
+}
+
+func TestHandler_ChatCompletions(t *testing.T) {
+	var requests []map[string]json.RawMessage
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer upstream-key", r.Header.Get("Authorization"))
+		var req map[string]json.RawMessage
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &req))
+		requests = append(requests, req)
+		var messages []map[string]any
+		require.NoError(t, json.Unmarshal(req["messages"], &messages))
+		if messages[len(messages)-1]["role"] == "tool" {
+			_, _ = w.Write([]byte(`{"id": "chatcmpl-2", "choices": [{"index": 0, "message": {"role": "assistant", "content": "It is sunny."}, "finish_reason": "stop"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "chatcmpl-1", "choices": [{"index": 0, "message": {"role": "assistant", "content": null, "tool_calls": [
+			{"id": "call_1", "type": "function", "function": {"name": "weather__get_forecast", "arguments": "{\"city\": \"Oslo\"}"}}
+		]}, "finish_reason": "tool_calls"}]}`))
+	}))
+	defer upstream.Close()
+
+	tools := &fakeToolSet{}
+	h := NewHandler(tools, &Upstream{BaseURL: upstream.URL + "/v1", APIKey: "upstream-key"})
+	post := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/chat/completions", strings.NewReader(body)))
+		return rec
+	}
+
+	rec := post(`{"model": "gpt-4o", "messages": [{"role": "user", "content": "Weather in Oslo?"}]}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"content": "It is sunny."`)
+	require.Len(t, requests, 2, "the tool call is run server-side")
+	assert.Contains(t, string(requests[0]["tools"]), "weather__get_forecast")
+	assert.JSONEq(t, `"gpt-4o"`, string(requests[1]["model"]))
+	var messages []map[string]any
+	require.NoError(t, json.Unmarshal(requests[1]["messages"], &messages))
+	require.Len(t, messages, 3)
+	assert.Equal(t, map[string]any{"role": "tool", "tool_call_id": "call_1", "content": "Sunny in Oslo"}, messages[2])
+
+	// Tools defined by the caller are executed by the caller.
+	requests = nil
+	rec = post(`{"model": "gpt-4o", "messages": [{"role": "user", "content": "Hi"}], "tools": []}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"finish_reason": "tool_calls"`)
+	assert.Len(t, requests, 1)
+
+	rec = post(`{"model": "gpt-4o", "messages": [], "stream": true}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "streaming is not supported")
+
+	h.SetUpstream(nil)
+	rec = post(`{}`)
+	assert.Equal(t, http.StatusNotImplemented, rec.Code, "a reload can remove the upstream")
+}
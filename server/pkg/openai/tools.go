@@ -0,0 +1,247 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package openai bridges OpenAI function calling and the aggregated MCP
+// toolset, so that agents and SDKs which do not speak MCP can list and call
+// the same tools. Tools are exposed as OpenAI function definitions, tool calls
+// are executed through the MCP server, and an OpenAI-compatible chat
+// completions endpoint runs the tool calls of an upstream model server-side.
+package openai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxFunctionNameLength is the longest function name OpenAI accepts.
+const maxFunctionNameLength = 64
+
+// ToolSet is the aggregated MCP toolset served by the bridge.
+type ToolSet interface {
+	// ListTools lists the registered tools.
+	ListTools() []tool.Tool
+	// CallTool executes a tool, enforcing the profile of the context.
+	CallTool(ctx context.Context, req *tool.ExecutionRequest) (any, error)
+	// IsServiceAllowed reports whether a profile may use the tools of a
+	// service.
+	IsServiceAllowed(serviceID, profileID string) bool
+}
+
+// Tool is an OpenAI tool definition.
+type Tool struct {
+	Type     string             `json:"type"`
+	Function FunctionDefinition `json:"function"`
+}
+
+// FunctionDefinition is the function of an OpenAI tool definition.
+type FunctionDefinition struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters"`
+}
+
+// ToolCall is a call of a function requested by a model.
+type ToolCall struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Function FunctionCall `json:"function"`
+}
+
+// FunctionCall is the function and JSON encoded arguments of a tool call.
+type FunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// ToolMessage is the "tool" role message answering a tool call.
+type ToolMessage struct {
+	Role       string `json:"role"`
+	ToolCallID string `json:"tool_call_id"`
+	Content    string `json:"content"`
+}
+
+// FunctionName maps an MCP tool name to a valid OpenAI function name.
+//
+// OpenAI function names only allow letters, digits, "_" and "-", and are at
+// most 64 characters long, while MCP tool names are qualified by their
+// service with ".". The separator becomes "__", other characters become "_",
+// and long names are truncated and suffixed with a hash to stay unique.
+//
+// Parameters:
+//   - toolName (string): The fully qualified MCP tool name.
+//
+// Returns:
+//   - string: The function name.
+func FunctionName(toolName string) string {
+	var b strings.Builder
+	for _, r := range toolName {
+		switch {
+		case r == '.':
+			b.WriteString("__")
+		case r == '_' || r == '-' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9'):
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	name := b.String()
+	if len(name) <= maxFunctionNameLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(toolName))
+	suffix := hex.EncodeToString(sum[:4])
+	return name[:maxFunctionNameLength-len(suffix)-1] + "_" + suffix
+}
+
+// Catalog is the toolset visible to a caller, by function name.
+type Catalog struct {
+	tools     []Tool
+	toolNames map[string]string
+}
+
+// NewCatalog lists the tools of a toolset the profile of the context may use.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the caller.
+//   - tools (ToolSet): The toolset.
+//
+// Returns:
+//   - *Catalog: The visible tools.
+func NewCatalog(ctx context.Context, tools ToolSet) *Catalog {
+	profileID, _ := auth.ProfileIDFromContext(ctx)
+	c := &Catalog{tools: []Tool{}, toolNames: make(map[string]string)}
+	for _, t := range tools.ListTools() {
+		mcpTool := t.MCPTool()
+		if mcpTool == nil {
+			continue
+		}
+		if profileID != "" && t.Tool() != nil && !tools.IsServiceAllowed(t.Tool().GetServiceId(), profileID) {
+			continue
+		}
+		name := FunctionName(mcpTool.Name)
+		if _, dup := c.toolNames[name]; dup {
+			continue
+		}
+		c.toolNames[name] = mcpTool.Name
+		c.tools = append(c.tools, Tool{
+			Type: "function",
+			Function: FunctionDefinition{
+				Name:        name,
+				Description: mcpTool.Description,
+				Parameters:  parameters(mcpTool.InputSchema),
+			},
+		})
+	}
+	return c
+}
+
+// Tools returns the OpenAI definitions of the tools.
+//
+// Returns:
+//   - []Tool: The tool definitions.
+func (c *Catalog) Tools() []Tool {
+	return c.tools
+}
+
+// Has reports whether a function is a tool of the catalog, as opposed to a
+// function the caller defined itself.
+//
+// Parameters:
+//   - function (string): The function name.
+//
+// Returns:
+//   - bool: True if the function is an MCP tool.
+func (c *Catalog) Has(function string) bool {
+	_, ok := c.toolNames[function]
+	return ok
+}
+
+// Call executes a tool call and answers it. Failures are reported to the model
+// in the content of the message, like MCP reports them in the tool result.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the caller.
+//   - tools (ToolSet): The toolset.
+//   - call (ToolCall): The tool call.
+//
+// Returns:
+//   - ToolMessage: The answer to the call.
+//
+// Side Effects:
+//   - Executes the tool.
+func (c *Catalog) Call(ctx context.Context, tools ToolSet, call ToolCall) ToolMessage {
+	msg := ToolMessage{Role: "tool", ToolCallID: call.ID}
+	toolName, ok := c.toolNames[call.Function.Name]
+	if !ok {
+		msg.Content = fmt.Sprintf("Tool execution failed: unknown function %q", call.Function.Name)
+		return msg
+	}
+	args := strings.TrimSpace(call.Function.Arguments)
+	if args == "" {
+		args = "{}"
+	}
+	if !json.Valid([]byte(args)) {
+		msg.Content = "Tool execution failed: the arguments are not valid JSON"
+		return msg
+	}
+	result, err := tools.CallTool(ctx, &tool.ExecutionRequest{ToolName: toolName, ToolInputs: json.RawMessage(args)})
+	if err != nil {
+		msg.Content = fmt.Sprintf("Tool execution failed: %v", err)
+		return msg
+	}
+	msg.Content = resultText(result)
+	return msg
+}
+
+// parameters returns the JSON schema of the arguments of a tool.
+func parameters(schema any) json.RawMessage {
+	if schema != nil {
+		if b, err := json.Marshal(schema); err == nil && string(b) != "null" {
+			return b
+		}
+	}
+	return json.RawMessage(`{"type":"object","properties":{}}`)
+}
+
+// resultText flattens a tool result into the content of a tool message.
+func resultText(result any) string {
+	ctr, ok := result.(*mcp.CallToolResult)
+	if !ok {
+		if s, ok := result.(string); ok {
+			return s
+		}
+		b, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Sprint(result)
+		}
+		return string(b)
+	}
+	if len(ctr.Content) == 0 && ctr.StructuredContent != nil {
+		if b, err := json.Marshal(ctr.StructuredContent); err == nil {
+			return string(b)
+		}
+	}
+	parts := make([]string, 0, len(ctr.Content))
+	for _, content := range ctr.Content {
+		if text, ok := content.(*mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+			continue
+		}
+		if b, err := json.Marshal(content); err == nil {
+			parts = append(parts, string(b))
+		}
+	}
+	text := strings.Join(parts, "\n")
+	if ctr.IsError {
+		return "Tool execution failed: " + text
+	}
+	return text
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	pluginv1 "github.com/mcpany/core/proto/plugin/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/enrichment"
+	"github.com/mcpany/core/server/pkg/logging"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func init() {
+	enrichment.RegisterPlugin(startEnricher)
+}
+
+// enrichTimeout bounds the Enrich call of an enricher plugin, which runs on
+// every tool call.
+var enrichTimeout = 200 * time.Millisecond
+
+// enricherProcess is a running label enricher plugin.
+type enricherProcess struct {
+	*process
+	client pluginv1.EnricherServiceClient
+	labels []string
+}
+
+// startEnricher starts the plugin of a label enricher, and passes it its
+// name and labels.
+func startEnricher(cfg *configv1.LabelEnricher) (enrichment.Enricher, error) {
+	if len(cfg.GetLabels()) == 0 {
+		return nil, fmt.Errorf("no labels configured")
+	}
+	if cfg.GetPlugin().GetWasm() != "" {
+		return nil, fmt.Errorf("wasm plugins are not supported, use a command")
+	}
+	config := &structpb.Struct{Fields: make(map[string]*structpb.Value, len(cfg.GetLabels()))}
+	labels := make([]string, 0, len(cfg.GetLabels()))
+	for label, source := range cfg.GetLabels() {
+		config.Fields[label] = structpb.NewStringValue(source)
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	ctx := context.Background()
+	p, err := startProcess(ctx, EnricherMagicCookieValue, cfg.GetName(), cfg.GetPlugin())
+	if err != nil {
+		return nil, err
+	}
+	e := &enricherProcess{process: p, client: pluginv1.NewEnricherServiceClient(p.conn), labels: labels}
+	configure := pluginv1.ConfigureRequest_builder{Name: cfg.GetName(), Config: config}.Build()
+	if err := p.configure(ctx, e.client.Configure, configure); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Labels returns the configured labels of the enricher.
+//
+// Summary: Lists the labels of the enricher plugin.
+//
+// Returns:
+//   - []string: The sorted label names.
+func (e *enricherProcess) Labels() []string {
+	return e.labels
+}
+
+// Enrich asks the plugin for the labels of a call. A plugin that fails or
+// does not answer in time sets no labels.
+//
+// Summary: Derives the labels of a call with the enricher plugin.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - call (enrichment.Call): The call being enriched.
+//
+// Returns:
+//   - map[string]string: The labels set by the plugin.
+func (e *enricherProcess) Enrich(ctx context.Context, call enrichment.Call) map[string]string {
+	req := pluginv1.EnrichRequest_builder{ToolName: call.ToolName, ServiceId: call.ServiceID}.Build()
+	if user, ok := auth.UserFromContext(ctx); ok {
+		req.SetUserId(user)
+	}
+	if profile, ok := auth.ProfileIDFromContext(ctx); ok {
+		req.SetProfileId(profile)
+	}
+	if keyID, ok := auth.APIKeyIDFromContext(ctx); ok {
+		req.SetApiKeyId(keyID)
+	}
+	if roles, ok := auth.RolesFromContext(ctx); ok {
+		req.SetRoles(roles)
+	}
+	if claims, ok := ctx.Value(auth.ClaimsContextKey).(map[string]any); ok {
+		if s, err := structpb.NewStruct(claims); err == nil {
+			req.SetClaims(s)
+		}
+	}
+
+	enrichCtx, cancel := context.WithTimeout(ctx, enrichTimeout)
+	defer cancel()
+	resp, err := e.client.Enrich(enrichCtx, req)
+	if err != nil {
+		logging.GetLogger().Warn("Label enricher plugin failed", "enricher", e.name, "tool", call.ToolName, "error", err)
+		return nil
+	}
+	return resp.GetLabels()
+}
+
+// Close stops the plugin.
+//
+// Returns:
+//   - error: An error if the connection to the plugin fails to close.
+func (e *enricherProcess) Close() error {
+	return e.close()
+}
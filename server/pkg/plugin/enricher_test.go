@@ -0,0 +1,115 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/enrichment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// testPluginEnricher sets each label from the user, the tool or a JWT claim,
+// as the source of the label says.
+type testPluginEnricher struct {
+	labels map[string]string
+}
+
+func newTestPluginEnricher(cfg *configv1.LabelEnricher) (enrichment.Enricher, error) {
+	if _, ok := cfg.GetLabels()["fail"]; ok {
+		return nil, errors.New("fail is not a label")
+	}
+	return &testPluginEnricher{labels: cfg.GetLabels()}, nil
+}
+
+func (e *testPluginEnricher) Labels() []string {
+	return nil
+}
+
+func (e *testPluginEnricher) Enrich(ctx context.Context, call enrichment.Call) map[string]string {
+	labels := make(map[string]string, len(e.labels))
+	for label, source := range e.labels {
+		switch source {
+		case "user":
+			labels[label], _ = auth.UserFromContext(ctx)
+		case "tool":
+			labels[label] = call.ToolName
+		default:
+			if v, ok := auth.ClaimFromContext(ctx, source); ok {
+				labels[label], _ = v.(string)
+			}
+		}
+	}
+	return labels
+}
+
+func testEnricherConfig(t *testing.T, labels map[string]string) *configv1.LabelEnricher {
+	t.Helper()
+	executable, err := os.Executable()
+	require.NoError(t, err)
+	return configv1.LabelEnricher_builder{
+		Name:   proto.String("custom"),
+		Labels: labels,
+		Plugin: configv1.MiddlewarePlugin_builder{
+			Command: proto.String(executable),
+			Env:     map[string]string{enricherTestEnv: "1"},
+		}.Build(),
+	}.Build()
+}
+
+func TestEnricherPlugin(t *testing.T) {
+	var started []*enricherProcess
+	enrichment.RegisterPlugin(func(cfg *configv1.LabelEnricher) (enrichment.Enricher, error) {
+		e, err := startEnricher(cfg)
+		if err == nil {
+			started = append(started, e.(*enricherProcess))
+		}
+		return e, err
+	})
+	t.Cleanup(func() { enrichment.RegisterPlugin(startEnricher) })
+	exited := func(p *enricherProcess) bool {
+		select {
+		case <-p.exited:
+			return true
+		default:
+			return false
+		}
+	}
+
+	require.NoError(t, enrichment.Configure([]*configv1.LabelEnricher{
+		testEnricherConfig(t, map[string]string{"caller": "user", "team": "org.team", "tool_label": "tool"}),
+	}))
+	assert.Equal(t, []string{"caller", "team", "tool_label"}, enrichment.LabelNames())
+
+	ctx := auth.ContextWithUser(context.Background(), "alice")
+	ctx = auth.ContextWithClaims(ctx, map[string]any{"org": map[string]any{"team": "payments"}})
+	assert.Equal(t, map[string]string{"caller": "alice", "team": "payments", "tool_label": "svc.tool"},
+		enrichment.Enrich(ctx, enrichment.Call{ToolName: "svc.tool"}))
+	assert.Equal(t, map[string]string{"tool_label": "svc.tool"}, enrichment.Enrich(context.Background(), enrichment.Call{ToolName: "svc.tool"}))
+
+	// A reload replaces the plugin.
+	require.NoError(t, enrichment.Configure([]*configv1.LabelEnricher{testEnricherConfig(t, map[string]string{"caller": "user"})}))
+	require.Len(t, started, 2)
+	assert.True(t, exited(started[0]), "the replaced plugin is stopped")
+	assert.Equal(t, map[string]string{"caller": "alice"}, enrichment.Enrich(ctx, enrichment.Call{ToolName: "svc.tool"}))
+
+	err := enrichment.Configure([]*configv1.LabelEnricher{testEnricherConfig(t, map[string]string{"fail": "user"})})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "fail is not a label")
+	assert.Equal(t, []string{"caller"}, enrichment.LabelNames(), "a failed update keeps the active enrichers")
+
+	err = enrichment.Configure([]*configv1.LabelEnricher{testEnricherConfig(t, nil)})
+	require.EqualError(t, err, `label enricher "custom": no labels configured`)
+
+	require.NoError(t, enrichment.Close())
+	assert.True(t, exited(started[1]), "the plugin is stopped on shutdown")
+	assert.Empty(t, enrichment.LabelNames())
+}
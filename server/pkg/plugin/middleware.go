@@ -0,0 +1,247 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package plugin runs custom tool middlewares in the process: middlewares
+// compiled into the server with Register, and plugins loaded from an
+// executable.
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/tool"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Middleware is custom logic run around the tool calls.
+//
+// Summary: A tool middleware with pre-call, post-call and error handlers.
+type Middleware interface {
+	// PreCall runs before the tool call.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context of the call.
+	//   - req (*tool.ExecutionRequest): The call.
+	//
+	// Returns:
+	//   - *tool.ExecutionRequest: The call with modified arguments, or nil to keep it.
+	//   - error: An error denying the call.
+	PreCall(ctx context.Context, req *tool.ExecutionRequest) (*tool.ExecutionRequest, error)
+
+	// PostCall runs after a successful tool call.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context of the call.
+	//   - req (*tool.ExecutionRequest): The call.
+	//   - result (any): The result of the call.
+	//
+	// Returns:
+	//   - any: The result, possibly replaced.
+	//   - error: An error failing the call.
+	PostCall(ctx context.Context, req *tool.ExecutionRequest, result any) (any, error)
+
+	// OnError runs after a failed tool call, or a call denied by a later
+	// middleware.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context of the call.
+	//   - req (*tool.ExecutionRequest): The call.
+	//   - err (error): The error of the call.
+	//
+	// Returns:
+	//   - any: A result recovering the call, if the returned error is nil.
+	//   - error: The error the call fails with: err, or a replacement.
+	OnError(ctx context.Context, req *tool.ExecutionRequest, err error) (any, error)
+}
+
+// Factory creates a middleware from its settings.
+//
+// Summary: Creates a configured middleware.
+type Factory func(config *structpb.Struct) (Middleware, error)
+
+var registry = struct {
+	sync.RWMutex
+	factories map[string]Factory
+}{factories: make(map[string]Factory)}
+
+// Register makes a middleware compiled into the server available to the
+// configuration, under its name. It is meant to be called from an init
+// function.
+//
+// Summary: Registers a compiled-in tool middleware.
+//
+// Parameters:
+//   - name (string): The name of the middleware in the configuration.
+//   - factory (Factory): The factory of the middleware.
+//
+// Side Effects:
+//   - Replaces a middleware registered under the same name.
+func Register(name string, factory Factory) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.factories[name] = factory
+}
+
+// Registered reports whether a middleware is compiled in under the name.
+//
+// Summary: Checks for a compiled-in tool middleware.
+//
+// Parameters:
+//   - name (string): The name of the middleware.
+//
+// Returns:
+//   - bool: True if a factory is registered under the name.
+func Registered(name string) bool {
+	registry.RLock()
+	defer registry.RUnlock()
+	return registry.factories[name] != nil
+}
+
+// namedMiddleware is a middleware of the chain with its name and the
+// function releasing it.
+type namedMiddleware struct {
+	name  string
+	m     Middleware
+	close func() error
+}
+
+// Chain runs the configured tool middlewares around the tool calls.
+//
+// Summary: The tool middlewares of the configuration, as an execution middleware.
+type Chain struct {
+	middlewares []namedMiddleware
+}
+
+// NewChain creates the middlewares of the configuration that are compiled in
+// or implemented by a plugin, ordered by priority. The other middlewares of
+// the configuration are the HTTP and MCP ones, which are left out.
+//
+// Summary: Creates the tool middlewares of the configuration.
+//
+// Parameters:
+//   - ctx (context.Context): The context bounding the start of the plugins.
+//   - configs ([]*configv1.Middleware): The middlewares of the configuration.
+//
+// Returns:
+//   - *Chain: The chain of the middlewares.
+//   - error: An error if a middleware cannot be created.
+//
+// Side Effects:
+//   - Starts the processes of the executable plugins.
+func NewChain(ctx context.Context, configs []*configv1.Middleware) (*Chain, error) {
+	active := make([]*configv1.Middleware, 0, len(configs))
+	for _, cfg := range configs {
+		if !cfg.GetDisabled() && (cfg.HasPlugin() || Registered(cfg.GetName())) {
+			active = append(active, cfg)
+		}
+	}
+	sort.SliceStable(active, func(i, j int) bool {
+		return active[i].GetPriority() < active[j].GetPriority()
+	})
+
+	c := &Chain{}
+	for _, cfg := range active {
+		m, closeFn, err := newMiddleware(ctx, cfg)
+		if err != nil {
+			_ = c.Close()
+			return nil, fmt.Errorf("middleware %q: %w", cfg.GetName(), err)
+		}
+		c.middlewares = append(c.middlewares, namedMiddleware{name: cfg.GetName(), m: m, close: closeFn})
+	}
+	return c, nil
+}
+
+// newMiddleware creates a middleware from its plugin, or from the factory
+// registered under its name.
+func newMiddleware(ctx context.Context, cfg *configv1.Middleware) (Middleware, func() error, error) {
+	if cfg.HasPlugin() {
+		return loadPlugin(ctx, cfg)
+	}
+	registry.RLock()
+	factory := registry.factories[cfg.GetName()]
+	registry.RUnlock()
+	m, err := factory(cfg.GetConfig())
+	if err != nil {
+		return nil, nil, err
+	}
+	return m, func() error { return nil }, nil
+}
+
+// Len returns the number of middlewares of the chain.
+//
+// Summary: Counts the tool middlewares.
+//
+// Returns:
+//   - int: The number of middlewares.
+func (c *Chain) Len() int {
+	return len(c.middlewares)
+}
+
+// Execute runs the PreCall handlers of the middlewares in order, the call,
+// then the PostCall or OnError handlers in reverse order. A middleware whose
+// PreCall denies the call stops the chain: the call is not made, and the
+// middlewares before it see the error in OnError.
+//
+// Summary: Runs the tool middlewares around a call.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*tool.ExecutionRequest): The call.
+//   - next (tool.ExecutionFunc): The rest of the execution chain.
+//
+// Returns:
+//   - any: The result of the call.
+//   - error: The error of the call.
+func (c *Chain) Execute(ctx context.Context, req *tool.ExecutionRequest, next tool.ExecutionFunc) (any, error) {
+	var result any
+	var err error
+	ran := 0
+	for _, nm := range c.middlewares {
+		modified, preErr := nm.m.PreCall(ctx, req)
+		if preErr != nil {
+			logging.GetLogger().Warn("Tool call denied by middleware", "middleware", nm.name, "tool", req.ToolName, "error", preErr)
+			err = preErr
+			break
+		}
+		if modified != nil {
+			req = modified
+		}
+		ran++
+	}
+	if err == nil {
+		result, err = next(ctx, req)
+	}
+	for i := ran - 1; i >= 0; i-- {
+		m := c.middlewares[i].m
+		if err != nil {
+			result, err = m.OnError(ctx, req, err)
+		} else {
+			result, err = m.PostCall(ctx, req, result)
+		}
+	}
+	return result, err
+}
+
+// Close releases the middlewares of the chain.
+//
+// Summary: Stops the tool middleware plugins.
+//
+// Returns:
+//   - error: The first error releasing a middleware.
+//
+// Side Effects:
+//   - Stops the processes of the executable plugins.
+func (c *Chain) Close() error {
+	var firstErr error
+	for _, nm := range c.middlewares {
+		if err := nm.close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("middleware %q: %w", nm.name, err)
+		}
+	}
+	return firstErr
+}
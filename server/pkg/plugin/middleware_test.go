@@ -0,0 +1,119 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// recordingMiddleware appends the handlers it runs to calls, and denies the
+// tools named deny.
+type recordingMiddleware struct {
+	name  string
+	deny  string
+	calls *[]string
+}
+
+func (m *recordingMiddleware) PreCall(_ context.Context, req *tool.ExecutionRequest) (*tool.ExecutionRequest, error) {
+	*m.calls = append(*m.calls, m.name+".pre")
+	if req.ToolName == m.deny {
+		return nil, errors.New("denied by " + m.name)
+	}
+	return nil, nil
+}
+
+func (m *recordingMiddleware) PostCall(_ context.Context, _ *tool.ExecutionRequest, result any) (any, error) {
+	*m.calls = append(*m.calls, m.name+".post")
+	return result.(string) + "+" + m.name, nil
+}
+
+func (m *recordingMiddleware) OnError(_ context.Context, _ *tool.ExecutionRequest, err error) (any, error) {
+	*m.calls = append(*m.calls, m.name+".error")
+	if strings.Contains(err.Error(), "recoverable") {
+		return "recovered by " + m.name, nil
+	}
+	return nil, err
+}
+
+func TestChain(t *testing.T) {
+	var calls []string
+	for _, name := range []string{"test_outer", "test_inner"} {
+		Register(name, func(config *structpb.Struct) (Middleware, error) {
+			return &recordingMiddleware{name: name, deny: config.GetFields()["deny"].GetStringValue(), calls: &calls}, nil
+		})
+	}
+	chain, err := NewChain(context.Background(), []*configv1.Middleware{
+		configv1.Middleware_builder{
+			Name:     proto.String("test_inner"),
+			Priority: proto.Int32(2),
+			Config:   &structpb.Struct{Fields: map[string]*structpb.Value{"deny": structpb.NewStringValue("svc.forbidden")}},
+		}.Build(),
+		configv1.Middleware_builder{Name: proto.String("test_outer"), Priority: proto.Int32(1)}.Build(),
+		configv1.Middleware_builder{Name: proto.String("test_outer"), Disabled: proto.Bool(true)}.Build(),
+		configv1.Middleware_builder{Name: proto.String("logging")}.Build(),
+	})
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, chain.Close()) }()
+	assert.Equal(t, 2, chain.Len(), "the disabled middlewares and the HTTP and MCP ones are left out")
+
+	t.Run("success", func(t *testing.T) {
+		calls = nil
+		result, err := chain.Execute(context.Background(), &tool.ExecutionRequest{ToolName: "svc.tool"}, func(context.Context, *tool.ExecutionRequest) (any, error) {
+			calls = append(calls, "call")
+			return "result", nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "result+test_inner+test_outer", result)
+		assert.Equal(t, []string{"test_outer.pre", "test_inner.pre", "call", "test_inner.post", "test_outer.post"}, calls)
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		calls = nil
+		_, err := chain.Execute(context.Background(), &tool.ExecutionRequest{ToolName: "svc.forbidden"}, func(context.Context, *tool.ExecutionRequest) (any, error) {
+			calls = append(calls, "call")
+			return "result", nil
+		})
+		require.EqualError(t, err, "denied by test_inner")
+		assert.Equal(t, []string{"test_outer.pre", "test_inner.pre", "test_outer.error"}, calls, "the call is not made, and the middlewares before the denial see it")
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		calls = nil
+		_, err := chain.Execute(context.Background(), &tool.ExecutionRequest{ToolName: "svc.tool"}, func(context.Context, *tool.ExecutionRequest) (any, error) {
+			return nil, errors.New("upstream failed")
+		})
+		require.EqualError(t, err, "upstream failed")
+		assert.Equal(t, []string{"test_outer.pre", "test_inner.pre", "test_inner.error", "test_outer.error"}, calls)
+	})
+
+	t.Run("recovered", func(t *testing.T) {
+		calls = nil
+		result, err := chain.Execute(context.Background(), &tool.ExecutionRequest{ToolName: "svc.tool"}, func(context.Context, *tool.ExecutionRequest) (any, error) {
+			return nil, errors.New("recoverable failure")
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "recovered by test_inner+test_outer", result, "the middlewares outside the recovery see a success")
+		assert.Equal(t, []string{"test_outer.pre", "test_inner.pre", "test_inner.error", "test_outer.post"}, calls)
+	})
+}
+
+func TestNewChain_FactoryError(t *testing.T) {
+	Register("test_broken", func(*structpb.Struct) (Middleware, error) {
+		return nil, errors.New("missing setting")
+	})
+	_, err := NewChain(context.Background(), []*configv1.Middleware{
+		configv1.Middleware_builder{Name: proto.String("test_broken")}.Build(),
+	})
+	require.EqualError(t, err, `middleware "test_broken": missing setting`)
+}
@@ -0,0 +1,233 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	pluginv1 "github.com/mcpany/core/proto/plugin/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	// MagicCookieKey and MagicCookieValue are the environment variable set for
+	// an executable plugin, following the HashiCorp go-plugin handshake. A
+	// go-plugin plugin uses them as the MagicCookieKey and MagicCookieValue of
+	// its HandshakeConfig.
+	MagicCookieKey   = "MCPANY_PLUGIN"
+	MagicCookieValue = "middleware"
+	// EnricherMagicCookieValue is the MagicCookieValue of a label enricher
+	// plugin.
+	EnricherMagicCookieValue = "enricher"
+	// ProtocolVersion is the version of the MiddlewareService and of the
+	// EnricherService, the ProtocolVersion of the HandshakeConfig of a
+	// go-plugin plugin.
+	ProtocolVersion = 1
+
+	// coreProtocolVersion is the version of the go-plugin handshake.
+	coreProtocolVersion = 1
+)
+
+// startTimeout bounds the wait for the handshake of an executable plugin.
+var startTimeout = 10 * time.Second
+
+// process is a running executable plugin.
+type process struct {
+	// kind is the magic cookie value of the plugin, "middleware" or
+	// "enricher".
+	kind   string
+	name   string
+	cmd    *exec.Cmd
+	conn   *grpc.ClientConn
+	exited chan struct{}
+}
+
+// middlewareProcess is a running middleware plugin.
+type middlewareProcess struct {
+	*process
+	pluginv1.MiddlewareServiceClient
+}
+
+// startMiddlewareProcess starts a middleware plugin, connects to it, and
+// passes it its settings.
+func startMiddlewareProcess(ctx context.Context, name string, cfg *configv1.MiddlewarePlugin, configure *pluginv1.ConfigureRequest) (*middlewareProcess, error) {
+	p, err := startProcess(ctx, MagicCookieValue, name, cfg)
+	if err != nil {
+		return nil, err
+	}
+	m := &middlewareProcess{process: p, MiddlewareServiceClient: pluginv1.NewMiddlewareServiceClient(p.conn)}
+	if err := p.configure(ctx, m.Configure, configure); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// startProcess starts an executable plugin of a kind and connects to it.
+func startProcess(ctx context.Context, kind, name string, cfg *configv1.MiddlewarePlugin) (*process, error) {
+	log := logging.GetLogger().With(kind, name)
+
+	cmd := exec.Command(cfg.GetCommand(), cfg.GetArgs()...) //nolint:gosec // The command comes from the configuration.
+	cmd.Env = append(os.Environ(),
+		MagicCookieKey+"="+kind,
+		"PLUGIN_PROTOCOL_VERSIONS="+strconv.Itoa(ProtocolVersion),
+		"PLUGIN_MIN_PORT=10000",
+		"PLUGIN_MAX_PORT=25000",
+	)
+	keys := make([]string, 0, len(cfg.GetEnv()))
+	for k := range cfg.GetEnv() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		cmd.Env = append(cmd.Env, k+"="+cfg.GetEnv()[k])
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start plugin: %w", err)
+	}
+	p := &process{kind: kind, name: name, cmd: cmd, exited: make(chan struct{})}
+	go logOutput(log, stderr)
+
+	// The first line of output is the handshake; the rest is logged.
+	lines := make(chan string, 1)
+	go func() {
+		r := bufio.NewReader(stdout)
+		line, err := r.ReadString('\n')
+		if err == nil {
+			lines <- strings.TrimSpace(line)
+		}
+		logOutput(log, r)
+		_ = cmd.Wait()
+		close(p.exited)
+	}()
+
+	timer := time.NewTimer(startTimeout)
+	defer timer.Stop()
+	var line string
+	select {
+	case line = <-lines:
+	case <-p.exited:
+		return nil, fmt.Errorf("plugin exited before its handshake: %v", cmd.ProcessState)
+	case <-timer.C:
+		p.kill()
+		return nil, fmt.Errorf("plugin did not complete its handshake within %s", startTimeout)
+	case <-ctx.Done():
+		p.kill()
+		return nil, ctx.Err()
+	}
+
+	target, err := parseHandshake(line)
+	if err != nil {
+		p.kill()
+		return nil, err
+	}
+	p.conn, err = grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		p.kill()
+		return nil, fmt.Errorf("failed to connect to plugin: %w", err)
+	}
+	log.Info("Started "+kind+" plugin", "command", cfg.GetCommand(), "pid", cmd.Process.Pid)
+	return p, nil
+}
+
+// configure passes its settings to a started plugin with the Configure
+// method of its client, and stops the plugin if that fails.
+func (p *process) configure(ctx context.Context, configure func(context.Context, *pluginv1.ConfigureRequest, ...grpc.CallOption) (*pluginv1.ConfigureResponse, error), req *pluginv1.ConfigureRequest) error {
+	configureCtx, cancel := context.WithTimeout(ctx, startTimeout)
+	defer cancel()
+	if _, err := configure(configureCtx, req); err != nil {
+		_ = p.close()
+		return fmt.Errorf("failed to configure plugin: %w", err)
+	}
+	return nil
+}
+
+// parseHandshake returns the gRPC target of the handshake line of a plugin,
+// "CORE-PROTOCOL-VERSION|APP-PROTOCOL-VERSION|NETWORK-TYPE|NETWORK-ADDR|PROTOCOL".
+func parseHandshake(line string) (string, error) {
+	parts := strings.Split(line, "|")
+	if len(parts) < 5 {
+		return "", fmt.Errorf("invalid plugin handshake %q", line)
+	}
+	if parts[0] != strconv.Itoa(coreProtocolVersion) {
+		return "", fmt.Errorf("unsupported plugin handshake version %s", parts[0])
+	}
+	if parts[1] != strconv.Itoa(ProtocolVersion) {
+		return "", fmt.Errorf("unsupported plugin protocol version %s, want %d", parts[1], ProtocolVersion)
+	}
+	if parts[4] != "grpc" {
+		return "", fmt.Errorf("unsupported plugin protocol %q, want grpc", parts[4])
+	}
+	switch parts[2] {
+	case "unix":
+		return "unix://" + parts[3], nil
+	case "tcp":
+		return "passthrough:///" + parts[3], nil
+	}
+	return "", fmt.Errorf("unsupported plugin network %q", parts[2])
+}
+
+// logOutput logs the lines a plugin writes.
+func logOutput(log interface{ Info(string, ...any) }, r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		log.Info("Plugin output", "line", scanner.Text())
+	}
+}
+
+func (p *middlewareProcess) preCall(ctx context.Context, req *pluginv1.PreCallRequest) (*pluginv1.PreCallResponse, error) {
+	return p.PreCall(ctx, req)
+}
+
+func (p *middlewareProcess) postCall(ctx context.Context, req *pluginv1.PostCallRequest) (*pluginv1.PostCallResponse, error) {
+	return p.PostCall(ctx, req)
+}
+
+func (p *middlewareProcess) onError(ctx context.Context, req *pluginv1.OnErrorRequest) (*pluginv1.OnErrorResponse, error) {
+	return p.OnError(ctx, req)
+}
+
+// close disconnects from the plugin and stops it, giving it a moment to exit
+// on its own once interrupted.
+func (p *process) close() error {
+	var err error
+	if p.conn != nil {
+		err = p.conn.Close()
+	}
+	_ = p.cmd.Process.Signal(os.Interrupt)
+	select {
+	case <-p.exited:
+	case <-time.After(2 * time.Second):
+		p.kill()
+	}
+	return err
+}
+
+// kill stops the plugin and waits for it to exit.
+func (p *process) kill() {
+	if err := p.cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		logging.GetLogger().Warn("Failed to kill plugin", p.kind, p.name, "error", err)
+	}
+	<-p.exited
+}
@@ -0,0 +1,189 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// pluginTestEnv makes the test binary serve testPluginMiddleware as a
+// plugin, and enricherTestEnv testPluginEnricher.
+const (
+	pluginTestEnv   = "MCPANY_PLUGIN_TEST_SERVE"
+	enricherTestEnv = "MCPANY_ENRICHER_TEST_SERVE"
+)
+
+func TestMain(m *testing.M) {
+	serve := func(err error) {
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if os.Getenv(pluginTestEnv) != "" {
+		serve(Serve(newTestPluginMiddleware))
+	}
+	if os.Getenv(enricherTestEnv) != "" {
+		serve(ServeEnricher(newTestPluginEnricher))
+	}
+	os.Exit(m.Run())
+}
+
+// testPluginMiddleware denies the tools of its deny setting, adds a tag
+// argument, wraps the results and recovers from the "recoverable" errors.
+type testPluginMiddleware struct {
+	deny string
+}
+
+func newTestPluginMiddleware(config *structpb.Struct) (Middleware, error) {
+	deny := config.GetFields()["deny"].GetStringValue()
+	if deny == "" {
+		return nil, errors.New("deny is required")
+	}
+	return &testPluginMiddleware{deny: deny}, nil
+}
+
+func (m *testPluginMiddleware) PreCall(_ context.Context, req *tool.ExecutionRequest) (*tool.ExecutionRequest, error) {
+	if req.ToolName == m.deny {
+		return nil, errors.New("tool is blocked")
+	}
+	modified := *req
+	modified.Arguments = map[string]any{"tag": "plugin"}
+	for k, v := range req.Arguments {
+		modified.Arguments[k] = v
+	}
+	modified.ToolInputs = nil
+	return &modified, nil
+}
+
+func (m *testPluginMiddleware) PostCall(_ context.Context, req *tool.ExecutionRequest, result any) (any, error) {
+	return map[string]any{"tool": req.ToolName, "wrapped": result}, nil
+}
+
+func (m *testPluginMiddleware) OnError(_ context.Context, _ *tool.ExecutionRequest, err error) (any, error) {
+	if strings.Contains(err.Error(), "recoverable") {
+		return "fallback", nil
+	}
+	return nil, fmt.Errorf("plugin saw: %w", err)
+}
+
+func testPluginConfig(t *testing.T, deny string) *configv1.Middleware {
+	t.Helper()
+	executable, err := os.Executable()
+	require.NoError(t, err)
+	return configv1.Middleware_builder{
+		Name: proto.String("guard"),
+		Plugin: configv1.MiddlewarePlugin_builder{
+			Command: proto.String(executable),
+			Env:     map[string]string{pluginTestEnv: "1"},
+		}.Build(),
+		Config: &structpb.Struct{Fields: map[string]*structpb.Value{"deny": structpb.NewStringValue(deny)}},
+	}.Build()
+}
+
+func TestChain_ExecutablePlugin(t *testing.T) {
+	chain, err := NewChain(context.Background(), []*configv1.Middleware{testPluginConfig(t, "svc.forbidden")})
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, chain.Close()) }()
+	require.Equal(t, 1, chain.Len())
+
+	t.Run("pre and post call", func(t *testing.T) {
+		var seen *tool.ExecutionRequest
+		result, err := chain.Execute(context.Background(), &tool.ExecutionRequest{ToolName: "svc.tool", ToolInputs: []byte(`{"q":"x"}`)},
+			func(_ context.Context, req *tool.ExecutionRequest) (any, error) {
+				seen = req
+				return map[string]any{"answer": 42.0}, nil
+			})
+		require.NoError(t, err)
+		require.NotNil(t, seen)
+		assert.Equal(t, map[string]any{"q": "x", "tag": "plugin"}, seen.Arguments)
+		assert.JSONEq(t, `{"q":"x","tag":"plugin"}`, string(seen.ToolInputs))
+		assert.Equal(t, map[string]any{"tool": "svc.tool", "wrapped": map[string]any{"answer": 42.0}}, result)
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		_, err := chain.Execute(context.Background(), &tool.ExecutionRequest{ToolName: "svc.forbidden"}, func(context.Context, *tool.ExecutionRequest) (any, error) {
+			t.Fatal("a denied call is not made")
+			return nil, nil
+		})
+		require.EqualError(t, err, `denied by middleware "guard": tool is blocked`)
+	})
+
+	t.Run("error replaced", func(t *testing.T) {
+		_, err := chain.Execute(context.Background(), &tool.ExecutionRequest{ToolName: "svc.tool"}, func(context.Context, *tool.ExecutionRequest) (any, error) {
+			return nil, errors.New("upstream failed")
+		})
+		require.EqualError(t, err, "plugin saw: upstream failed")
+	})
+
+	t.Run("error recovered", func(t *testing.T) {
+		result, err := chain.Execute(context.Background(), &tool.ExecutionRequest{ToolName: "svc.tool"}, func(context.Context, *tool.ExecutionRequest) (any, error) {
+			return nil, errors.New("recoverable failure")
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "fallback", result)
+	})
+}
+
+func TestChain_ExecutablePluginConfigureError(t *testing.T) {
+	_, err := NewChain(context.Background(), []*configv1.Middleware{testPluginConfig(t, "")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "deny is required")
+}
+
+func TestChain_ExecutablePluginWithoutHandshake(t *testing.T) {
+	cfg := configv1.Middleware_builder{
+		Name:   proto.String("broken"),
+		Plugin: configv1.MiddlewarePlugin_builder{Command: proto.String("/bin/sh"), Args: []string{"-c", "echo not a plugin"}}.Build(),
+	}.Build()
+	_, err := NewChain(context.Background(), []*configv1.Middleware{cfg})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid plugin handshake "not a plugin"`)
+}
+
+func TestParseHandshake(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    string
+		wantErr string
+	}{
+		{line: "1|1|unix|/tmp/plugin/plugin.sock|grpc", want: "unix:///tmp/plugin/plugin.sock"},
+		{line: "1|1|tcp|127.0.0.1:10000|grpc|Y2VydA", want: "passthrough:///127.0.0.1:10000"},
+		{line: "1|2|unix|/tmp/p.sock|grpc", wantErr: "unsupported plugin protocol version 2, want 1"},
+		{line: "1|1|unix|/tmp/p.sock|netrpc", wantErr: `unsupported plugin protocol "netrpc", want grpc`},
+		{line: "2|1|unix|/tmp/p.sock|grpc", wantErr: "unsupported plugin handshake version 2"},
+		{line: "1|1|udp|:1|grpc", wantErr: `unsupported plugin network "udp"`},
+		{line: "hello", wantErr: `invalid plugin handshake "hello"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := parseHandshake(tt.line)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestServe_NotStartedByServer(t *testing.T) {
+	t.Setenv(MagicCookieKey, "")
+	err := Serve(newTestPluginMiddleware)
+	require.Error(t, err)
+}
@@ -0,0 +1,170 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	pluginv1 "github.com/mcpany/core/proto/plugin/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/tool"
+)
+
+// handler is the transport of a plugin: the MiddlewareService of an
+// executable.
+type handler interface {
+	preCall(ctx context.Context, req *pluginv1.PreCallRequest) (*pluginv1.PreCallResponse, error)
+	postCall(ctx context.Context, req *pluginv1.PostCallRequest) (*pluginv1.PostCallResponse, error)
+	onError(ctx context.Context, req *pluginv1.OnErrorRequest) (*pluginv1.OnErrorResponse, error)
+}
+
+// loadPlugin starts the plugin of a middleware.
+func loadPlugin(ctx context.Context, cfg *configv1.Middleware) (Middleware, func() error, error) {
+	p := cfg.GetPlugin()
+	configure := pluginv1.ConfigureRequest_builder{Name: cfg.GetName(), Config: cfg.GetConfig()}.Build()
+	switch {
+	case p.GetCommand() != "" && p.GetWasm() != "":
+		return nil, nil, fmt.Errorf("plugin sets both a command and a wasm module")
+	case p.GetCommand() != "":
+		proc, err := startMiddlewareProcess(ctx, cfg.GetName(), p, configure)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &remoteMiddleware{name: cfg.GetName(), h: proc}, proc.close, nil
+	case p.GetWasm() != "":
+		return nil, nil, fmt.Errorf("wasm plugins are not supported, use a command")
+	}
+	return nil, nil, fmt.Errorf("plugin sets neither a command nor a wasm module")
+}
+
+// remoteMiddleware is a middleware implemented by a plugin.
+type remoteMiddleware struct {
+	name string
+	h    handler
+}
+
+// PreCall asks the plugin whether the call goes on.
+//
+// Summary: Runs the pre-call handler of a plugin.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*tool.ExecutionRequest): The call.
+//
+// Returns:
+//   - *tool.ExecutionRequest: The call with the arguments of the plugin, or nil to keep it.
+//   - error: An error if the plugin denies the call or fails.
+func (r *remoteMiddleware) PreCall(ctx context.Context, req *tool.ExecutionRequest) (*tool.ExecutionRequest, error) {
+	arguments, err := requestArguments(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.h.preCall(ctx, pluginv1.PreCallRequest_builder{ToolName: req.ToolName, Arguments: arguments}.Build())
+	if err != nil {
+		return nil, fmt.Errorf("middleware %q failed: %w", r.name, err)
+	}
+	if resp.GetDeny() {
+		if resp.GetMessage() != "" {
+			return nil, fmt.Errorf("denied by middleware %q: %s", r.name, resp.GetMessage())
+		}
+		return nil, fmt.Errorf("denied by middleware %q", r.name)
+	}
+	if len(resp.GetArguments()) == 0 {
+		return nil, nil
+	}
+	modified := *req
+	modified.ToolInputs = resp.GetArguments()
+	modified.Arguments = nil
+	if err := json.Unmarshal(modified.ToolInputs, &modified.Arguments); err != nil {
+		return nil, fmt.Errorf("middleware %q returned invalid arguments: %w", r.name, err)
+	}
+	return &modified, nil
+}
+
+// PostCall lets the plugin replace the result of the call.
+//
+// Summary: Runs the post-call handler of a plugin.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*tool.ExecutionRequest): The call.
+//   - result (any): The result of the call.
+//
+// Returns:
+//   - any: The result, possibly replaced by the plugin.
+//   - error: An error if the plugin fails the call.
+func (r *remoteMiddleware) PostCall(ctx context.Context, req *tool.ExecutionRequest, result any) (any, error) {
+	arguments, err := requestArguments(req)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the result for middleware %q: %w", r.name, err)
+	}
+	resp, err := r.h.postCall(ctx, pluginv1.PostCallRequest_builder{ToolName: req.ToolName, Arguments: arguments, Result: encoded}.Build())
+	if err != nil {
+		return nil, fmt.Errorf("middleware %q failed: %w", r.name, err)
+	}
+	if resp.GetError() != "" {
+		return nil, errors.New(resp.GetError())
+	}
+	if len(resp.GetResult()) == 0 {
+		return result, nil
+	}
+	var replaced any
+	if err := json.Unmarshal(resp.GetResult(), &replaced); err != nil {
+		return nil, fmt.Errorf("middleware %q returned an invalid result: %w", r.name, err)
+	}
+	return replaced, nil
+}
+
+// OnError lets the plugin recover from the error of the call, or replace it.
+// A plugin that fails leaves the error as it is.
+//
+// Summary: Runs the error handler of a plugin.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*tool.ExecutionRequest): The call.
+//   - callErr (error): The error of the call.
+//
+// Returns:
+//   - any: The result the plugin recovers with.
+//   - error: The error of the call, possibly replaced by the plugin.
+func (r *remoteMiddleware) OnError(ctx context.Context, req *tool.ExecutionRequest, callErr error) (any, error) {
+	arguments, _ := requestArguments(req)
+	resp, err := r.h.onError(ctx, pluginv1.OnErrorRequest_builder{ToolName: req.ToolName, Arguments: arguments, Error: callErr.Error()}.Build())
+	if err != nil {
+		logging.GetLogger().Warn("Middleware plugin failed to handle an error", "middleware", r.name, "tool", req.ToolName, "error", err)
+		return nil, callErr
+	}
+	if len(resp.GetResult()) > 0 {
+		var recovered any
+		if err := json.Unmarshal(resp.GetResult(), &recovered); err != nil {
+			return nil, fmt.Errorf("middleware %q returned an invalid result: %w", r.name, err)
+		}
+		return recovered, nil
+	}
+	if resp.GetError() != "" {
+		return nil, errors.New(resp.GetError())
+	}
+	return nil, callErr
+}
+
+// requestArguments returns the arguments of a call as JSON.
+func requestArguments(req *tool.ExecutionRequest) ([]byte, error) {
+	if len(req.ToolInputs) > 0 || req.Arguments == nil {
+		return req.ToolInputs, nil
+	}
+	arguments, err := json.Marshal(req.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the arguments: %w", err)
+	}
+	return arguments, nil
+}
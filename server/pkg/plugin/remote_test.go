@@ -0,0 +1,33 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"context"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestLoadPlugin_InvalidConfig(t *testing.T) {
+	_, _, err := loadPlugin(context.Background(), configv1.Middleware_builder{
+		Name:   proto.String("both"),
+		Plugin: configv1.MiddlewarePlugin_builder{Command: proto.String("plugin"), Wasm: proto.String("plugin.wasm")}.Build(),
+	}.Build())
+	require.EqualError(t, err, "plugin sets both a command and a wasm module")
+
+	_, _, err = loadPlugin(context.Background(), configv1.Middleware_builder{
+		Name:   proto.String("none"),
+		Plugin: configv1.MiddlewarePlugin_builder{}.Build(),
+	}.Build())
+	require.EqualError(t, err, "plugin sets neither a command nor a wasm module")
+
+	_, _, err = loadPlugin(context.Background(), configv1.Middleware_builder{
+		Name:   proto.String("redact"),
+		Plugin: configv1.MiddlewarePlugin_builder{Wasm: proto.String("redact.wasm")}.Build(),
+	}.Build())
+	require.EqualError(t, err, "wasm plugins are not supported, use a command")
+}
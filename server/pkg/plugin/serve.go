@@ -0,0 +1,324 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	pluginv1 "github.com/mcpany/core/proto/plugin/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/enrichment"
+	"github.com/mcpany/core/server/pkg/tool"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Serve runs a middleware as an executable plugin: it completes the
+// handshake with the server that started it, and serves the
+// MiddlewareService until it is interrupted. It is meant to be called from
+// the main function of the plugin.
+//
+// Summary: Serves a tool middleware as an executable plugin.
+//
+// Parameters:
+//   - factory (Factory): The factory of the middleware, called with its settings.
+//
+// Returns:
+//   - error: An error if the executable was not started by the server, or
+//     if serving fails.
+//
+// Side Effects:
+//   - Writes the handshake to the standard output.
+//   - Listens on a Unix socket in a temporary directory.
+func Serve(factory Factory) error {
+	return serve(MagicCookieValue, func(s *grpc.Server) {
+		pluginv1.RegisterMiddlewareServiceServer(s, &server{factory: factory})
+	})
+}
+
+// ServeEnricher runs a label enricher as an executable plugin: it completes
+// the handshake with the server that started it, and serves the
+// EnricherService until it is interrupted. The enricher sees the identity of
+// the caller in the context of Enrich, as a compiled-in enricher does. It is
+// meant to be called from the main function of the plugin.
+//
+// Summary: Serves a label enricher as an executable plugin.
+//
+// Parameters:
+//   - factory (enrichment.Factory): The factory of the enricher, called with
+//     its name and labels.
+//
+// Returns:
+//   - error: An error if the executable was not started by the server, or
+//     if serving fails.
+//
+// Side Effects:
+//   - Writes the handshake to the standard output.
+//   - Listens on a Unix socket in a temporary directory.
+func ServeEnricher(factory enrichment.Factory) error {
+	return serve(EnricherMagicCookieValue, func(s *grpc.Server) {
+		pluginv1.RegisterEnricherServiceServer(s, &enricherServer{factory: factory})
+	})
+}
+
+// serve completes the handshake of a plugin of a kind, and serves the
+// services registered by register until it is interrupted.
+func serve(kind string, register func(*grpc.Server)) error {
+	if os.Getenv(MagicCookieKey) != kind {
+		return fmt.Errorf("this executable is an MCP Any %s plugin, started by the server", kind)
+	}
+	dir, err := os.MkdirTemp("", "mcpany-plugin")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	lis, err := net.Listen("unix", filepath.Join(dir, "plugin.sock"))
+	if err != nil {
+		return err
+	}
+
+	s := grpc.NewServer()
+	register(s)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		s.GracefulStop()
+	}()
+
+	if _, err := fmt.Fprintf(os.Stdout, "%d|%d|unix|%s|grpc\n", coreProtocolVersion, ProtocolVersion, lis.Addr().String()); err != nil {
+		return err
+	}
+	if err := s.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+// server is the MiddlewareService of a middleware served as a plugin.
+type server struct {
+	pluginv1.UnimplementedMiddlewareServiceServer
+	factory Factory
+
+	mu sync.RWMutex
+	m  Middleware
+}
+
+// Configure creates the middleware with its settings.
+//
+// Summary: Configures the served middleware.
+//
+// Parameters:
+//   - _ (context.Context): Unused.
+//   - req (*pluginv1.ConfigureRequest): The settings of the middleware.
+//
+// Returns:
+//   - *pluginv1.ConfigureResponse: An empty response.
+//   - error: An error if the middleware cannot be created.
+func (s *server) Configure(_ context.Context, req *pluginv1.ConfigureRequest) (*pluginv1.ConfigureResponse, error) {
+	m, err := s.factory(req.GetConfig())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	s.mu.Lock()
+	s.m = m
+	s.mu.Unlock()
+	return &pluginv1.ConfigureResponse{}, nil
+}
+
+func (s *server) middleware() (Middleware, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.m == nil {
+		return nil, status.Error(codes.FailedPrecondition, "the middleware is not configured")
+	}
+	return s.m, nil
+}
+
+// PreCall runs the PreCall handler of the middleware.
+//
+// Summary: Runs the pre-call handler of the served middleware.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*pluginv1.PreCallRequest): The call.
+//
+// Returns:
+//   - *pluginv1.PreCallResponse: The decision of the middleware.
+//   - error: An error if the middleware is not configured.
+func (s *server) PreCall(ctx context.Context, req *pluginv1.PreCallRequest) (*pluginv1.PreCallResponse, error) {
+	m, err := s.middleware()
+	if err != nil {
+		return nil, err
+	}
+	modified, err := m.PreCall(ctx, executionRequest(req.GetToolName(), req.GetArguments()))
+	if err != nil {
+		return pluginv1.PreCallResponse_builder{Deny: true, Message: err.Error()}.Build(), nil
+	}
+	resp := &pluginv1.PreCallResponse{}
+	if modified != nil {
+		arguments, err := requestArguments(modified)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		resp.SetArguments(arguments)
+	}
+	return resp, nil
+}
+
+// PostCall runs the PostCall handler of the middleware.
+//
+// Summary: Runs the post-call handler of the served middleware.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*pluginv1.PostCallRequest): The call and its result.
+//
+// Returns:
+//   - *pluginv1.PostCallResponse: The result of the middleware.
+//   - error: An error if the middleware is not configured.
+func (s *server) PostCall(ctx context.Context, req *pluginv1.PostCallRequest) (*pluginv1.PostCallResponse, error) {
+	m, err := s.middleware()
+	if err != nil {
+		return nil, err
+	}
+	var result any
+	if err := json.Unmarshal(req.GetResult(), &result); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid result: %v", err)
+	}
+	result, err = m.PostCall(ctx, executionRequest(req.GetToolName(), req.GetArguments()), result)
+	if err != nil {
+		return pluginv1.PostCallResponse_builder{Error: err.Error()}.Build(), nil
+	}
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return pluginv1.PostCallResponse_builder{Result: encoded}.Build(), nil
+}
+
+// OnError runs the OnError handler of the middleware.
+//
+// Summary: Runs the error handler of the served middleware.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*pluginv1.OnErrorRequest): The call and its error.
+//
+// Returns:
+//   - *pluginv1.OnErrorResponse: The recovered result or the replaced error.
+//   - error: An error if the middleware is not configured.
+func (s *server) OnError(ctx context.Context, req *pluginv1.OnErrorRequest) (*pluginv1.OnErrorResponse, error) {
+	m, err := s.middleware()
+	if err != nil {
+		return nil, err
+	}
+	result, err := m.OnError(ctx, executionRequest(req.GetToolName(), req.GetArguments()), errors.New(req.GetError()))
+	if err != nil {
+		resp := &pluginv1.OnErrorResponse{}
+		if err.Error() != req.GetError() {
+			resp.SetError(err.Error())
+		}
+		return resp, nil
+	}
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return pluginv1.OnErrorResponse_builder{Result: encoded}.Build(), nil
+}
+
+// executionRequest returns the call a plugin receives.
+func executionRequest(toolName string, arguments []byte) *tool.ExecutionRequest {
+	req := &tool.ExecutionRequest{ToolName: toolName, ToolInputs: arguments}
+	if len(arguments) > 0 {
+		_ = json.Unmarshal(arguments, &req.Arguments)
+	}
+	return req
+}
+
+// enricherServer is the EnricherService of a label enricher served as a
+// plugin.
+type enricherServer struct {
+	pluginv1.UnimplementedEnricherServiceServer
+	factory enrichment.Factory
+
+	mu sync.RWMutex
+	e  enrichment.Enricher
+}
+
+// Configure creates the enricher with its name and labels.
+//
+// Summary: Configures the served enricher.
+//
+// Parameters:
+//   - _ (context.Context): Unused.
+//   - req (*pluginv1.ConfigureRequest): The name and labels of the enricher.
+//
+// Returns:
+//   - *pluginv1.ConfigureResponse: An empty response.
+//   - error: An error if the enricher cannot be created.
+func (s *enricherServer) Configure(_ context.Context, req *pluginv1.ConfigureRequest) (*pluginv1.ConfigureResponse, error) {
+	labels := make(map[string]string, len(req.GetConfig().GetFields()))
+	for label, source := range req.GetConfig().GetFields() {
+		labels[label] = source.GetStringValue()
+	}
+	e, err := s.factory(configv1.LabelEnricher_builder{Name: proto.String(req.GetName()), Labels: labels}.Build())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	s.mu.Lock()
+	s.e = e
+	s.mu.Unlock()
+	return &pluginv1.ConfigureResponse{}, nil
+}
+
+// Enrich returns the labels of a call from the enricher.
+//
+// Summary: Runs the served enricher on a call.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*pluginv1.EnrichRequest): The call and the identity of its caller.
+//
+// Returns:
+//   - *pluginv1.EnrichResponse: The labels of the call.
+//   - error: An error if the enricher is not configured.
+func (s *enricherServer) Enrich(ctx context.Context, req *pluginv1.EnrichRequest) (*pluginv1.EnrichResponse, error) {
+	s.mu.RLock()
+	e := s.e
+	s.mu.RUnlock()
+	if e == nil {
+		return nil, status.Error(codes.FailedPrecondition, "the enricher is not configured")
+	}
+	if req.GetUserId() != "" {
+		ctx = auth.ContextWithUser(ctx, req.GetUserId())
+	}
+	if req.GetProfileId() != "" {
+		ctx = auth.ContextWithProfileID(ctx, req.GetProfileId())
+	}
+	if req.GetApiKeyId() != "" {
+		ctx = auth.ContextWithAPIKeyID(ctx, req.GetApiKeyId())
+	}
+	if len(req.GetRoles()) > 0 {
+		ctx = auth.ContextWithRoles(ctx, req.GetRoles())
+	}
+	if req.HasClaims() {
+		ctx = auth.ContextWithClaims(ctx, req.GetClaims().AsMap())
+	}
+	labels := e.Enrich(ctx, enrichment.Call{ToolName: req.GetToolName(), ServiceID: req.GetServiceId()})
+	return pluginv1.EnrichResponse_builder{Labels: labels}.Build(), nil
+}
@@ -25,6 +25,83 @@ const (
 	StateHalfOpen
 )
 
+// String returns the name of the state.
+//
+// Summary: Returns "closed", "open" or "half-open".
+//
+// Returns:
+//   - string: The name of the state.
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// StateChangeObserver is notified when a circuit breaker changes state. It
+// is called with the lock of the breaker held, so it must not block.
+//
+// Parameters:
+//   - name: The name of the breaker, usually the ID of the service.
+//   - from: The previous state.
+//   - to: The new state.
+type StateChangeObserver func(name string, from, to State)
+
+var stateChangeObserver atomic.Pointer[StateChangeObserver]
+
+// SetStateChangeObserver installs the observer notified of the state changes
+// of all circuit breakers. Passing nil removes it.
+//
+// Parameters:
+//   - observer: The observer, or nil.
+//
+// Side Effects:
+//   - Replaces the process-wide observer.
+func SetStateChangeObserver(observer StateChangeObserver) {
+	if observer == nil {
+		stateChangeObserver.Store(nil)
+		return
+	}
+	stateChangeObserver.Store(&observer)
+}
+
+// SharedState shares the opening of the circuit breakers between the
+// replicas of the server. Its methods are called on the request path with the
+// lock of the breaker held, so they must not block.
+type SharedState interface {
+	// OpenUntil returns the time until which a replica opened the breaker of
+	// the name, or the zero time.
+	OpenUntil(name string) time.Time
+	// Opened records that this replica opened the breaker of the name until
+	// the time.
+	Opened(name string, until time.Time)
+}
+
+var sharedState atomic.Pointer[SharedState]
+
+// SetSharedState installs the state shared by the named circuit breakers of
+// all replicas. A breaker opened by another replica opens here too, until the
+// same time. Passing nil removes it.
+//
+// Parameters:
+//   - state: The shared state, or nil.
+//
+// Side Effects:
+//   - Replaces the process-wide shared state.
+func SetSharedState(state SharedState) {
+	if state == nil {
+		sharedState.Store(nil)
+		return
+	}
+	sharedState.Store(&state)
+}
+
 // CircuitBreaker implements the circuit breaker pattern. It prevents the
 // application from performing operations that are likely to fail.
 type CircuitBreaker struct {
@@ -36,6 +113,8 @@ type CircuitBreaker struct {
 	halfOpenHits int
 
 	config *configv1.CircuitBreakerConfig
+	// name identifies the breaker to the state change observer.
+	name string
 }
 
 // NewCircuitBreaker creates a new CircuitBreaker with the given configuration.
@@ -75,6 +154,7 @@ func NewCircuitBreaker(config *configv1.CircuitBreakerConfig) *CircuitBreaker {
 //   - Executes the provided function.
 func (cb *CircuitBreaker) Execute(ctx context.Context, work func(context.Context) error) error {
 	originState := StateClosed
+	cb.syncSharedState()
 
 	// Optimization: Optimistically check if Closed without lock.
 	// This covers the "Happy Path" (most common case).
@@ -135,14 +215,94 @@ func (cb *CircuitBreaker) Execute(ctx context.Context, work func(context.Context
 	return nil
 }
 
+// Status is a snapshot of a circuit breaker.
+type Status struct {
+	// State is the state of the breaker.
+	State State
+	// Failures is the number of consecutive failures counted while closed.
+	Failures int32
+	// OpenedAt is when the breaker last opened; zero while it is closed.
+	OpenedAt time.Time
+}
+
+// Status returns a snapshot of the breaker. An open breaker whose open
+// duration has passed is reported open until the next call half-opens it.
+//
+// Summary: Returns the state of the breaker.
+//
+// Returns:
+//   - Status: The snapshot.
+func (cb *CircuitBreaker) Status() Status {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	status := Status{State: cb.getState(), Failures: atomic.LoadInt32(&cb.failures)}
+	if status.State != StateClosed {
+		status.OpenedAt = cb.openTime
+	}
+	return status
+}
+
+// Reset closes the breaker and clears its failures, so that calls are served
+// again at once. A breaker opened by another replica through the shared
+// state opens again on the next call.
+//
+// Summary: Closes the breaker.
+//
+// Side Effects:
+//   - Notifies the state change observer if the breaker was not closed.
+func (cb *CircuitBreaker) Reset() {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	cb.setState(StateClosed)
+	atomic.StoreInt32(&cb.failures, 0)
+	cb.halfOpenHits = 0
+	cb.openTime = time.Time{}
+}
+
 // getState reads the state atomically.
 func (cb *CircuitBreaker) getState() State {
 	return State(atomic.LoadInt32((*int32)(&cb.state)))
 }
 
-// setState updates the state atomically. Caller must hold the mutex.
+// setState updates the state atomically and notifies the state change
+// observer. Caller must hold the mutex.
 func (cb *CircuitBreaker) setState(newState State) {
-	atomic.StoreInt32((*int32)(&cb.state), int32(newState))
+	oldState := State(atomic.SwapInt32((*int32)(&cb.state), int32(newState)))
+	if oldState == newState {
+		return
+	}
+	if observer := stateChangeObserver.Load(); observer != nil {
+		(*observer)(cb.name, oldState, newState)
+	}
+}
+
+// trip opens the breaker and shares its opening. Caller must hold the mutex.
+func (cb *CircuitBreaker) trip() {
+	cb.setState(StateOpen)
+	cb.openTime = time.Now()
+	if state := sharedState.Load(); state != nil && cb.name != "" {
+		(*state).Opened(cb.name, cb.openTime.Add(cb.config.GetOpenDuration().AsDuration()))
+	}
+}
+
+// syncSharedState opens the closed breaker if another replica opened it, so
+// that it half-opens at the same time as there.
+func (cb *CircuitBreaker) syncSharedState() {
+	state := sharedState.Load()
+	if state == nil || cb.name == "" || cb.getState() != StateClosed {
+		return
+	}
+	until := (*state).OpenUntil(cb.name)
+	if !until.After(time.Now()) {
+		return
+	}
+
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	if cb.getState() == StateClosed {
+		cb.setState(StateOpen)
+		cb.openTime = until.Add(-cb.config.GetOpenDuration().AsDuration())
+	}
 }
 
 func (cb *CircuitBreaker) onSuccess(originState State) {
@@ -181,8 +341,7 @@ func (cb *CircuitBreaker) onFailure(originState State) {
 
 			// Re-check state to handle races
 			if cb.getState() == StateClosed {
-				cb.setState(StateOpen)
-				cb.openTime = time.Now()
+				cb.trip()
 			}
 		}
 		return
@@ -212,15 +371,13 @@ func (cb *CircuitBreaker) onFailure(originState State) {
 		if originState != StateHalfOpen {
 			return
 		}
-		cb.setState(StateOpen)
-		cb.openTime = time.Now()
+		cb.trip()
 		return
 	}
 
 	newFailures := atomic.AddInt32(&cb.failures, 1)
 	if newFailures >= cb.config.GetConsecutiveFailures() {
-		cb.setState(StateOpen)
-		cb.openTime = time.Now()
+		cb.trip()
 	}
 }
 
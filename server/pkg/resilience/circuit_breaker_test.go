@@ -13,6 +13,7 @@ import (
 	configv1 "github.com/mcpany/core/proto/config/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/durationpb"
 )
 
@@ -243,3 +244,133 @@ func TestCircuitBreaker_ZombieSuccess_ClosesBreaker(t *testing.T) {
 
 	assert.Equal(t, StateHalfOpen, state, "Breaker should remain HalfOpen after zombie success")
 }
+
+func TestCircuitBreaker_StateChangeObserver(t *testing.T) {
+	var mu sync.Mutex
+	var changes []string
+	SetStateChangeObserver(func(name string, from, to State) {
+		if name != "weather" {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, from.String()+"->"+to.String())
+	})
+	t.Cleanup(func() { SetStateChangeObserver(nil) })
+
+	m := NewManager(configv1.ResilienceConfig_builder{
+		CircuitBreaker: configv1.CircuitBreakerConfig_builder{
+			ConsecutiveFailures: proto.Int32(2),
+			OpenDuration:        durationpb.New(10 * time.Millisecond),
+			HalfOpenRequests:    proto.Int32(1),
+		}.Build(),
+	}.Build(), WithName("weather"))
+
+	ctx := context.Background()
+	fail := func(context.Context) error { return errors.New("error") }
+	_ = m.Execute(ctx, fail)
+	_ = m.Execute(ctx, fail)
+	_ = m.Execute(ctx, fail) // Rejected while open: no change.
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, m.Execute(ctx, func(context.Context) error { return nil }))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->closed"}, changes)
+}
+
+func TestCircuitBreaker_StatusAndReset(t *testing.T) {
+	var mu sync.Mutex
+	var changes []string
+	SetStateChangeObserver(func(name string, from, to State) {
+		if name != "github" {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, from.String()+"->"+to.String())
+	})
+	t.Cleanup(func() { SetStateChangeObserver(nil) })
+
+	m := NewManager(configv1.ResilienceConfig_builder{
+		CircuitBreaker: configv1.CircuitBreakerConfig_builder{
+			ConsecutiveFailures: proto.Int32(2),
+			OpenDuration:        durationpb.New(time.Hour),
+		}.Build(),
+	}.Build(), WithName("github"))
+	cb := m.CircuitBreaker()
+	require.NotNil(t, cb)
+
+	ctx := context.Background()
+	fail := func(context.Context) error { return errors.New("error") }
+	_ = m.Execute(ctx, fail)
+	assert.Equal(t, Status{State: StateClosed, Failures: 1}, cb.Status())
+	_ = m.Execute(ctx, fail)
+	status := cb.Status()
+	assert.Equal(t, StateOpen, status.State)
+	assert.False(t, status.OpenedAt.IsZero())
+
+	cb.Reset()
+	assert.Equal(t, Status{State: StateClosed}, cb.Status())
+	require.NoError(t, m.Execute(ctx, func(context.Context) error { return nil }))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"closed->open", "open->closed"}, changes)
+}
+
+// fakeSharedState is an in-memory SharedState.
+type fakeSharedState struct {
+	mu        sync.Mutex
+	openUntil map[string]time.Time
+}
+
+func (s *fakeSharedState) OpenUntil(name string) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.openUntil[name]
+}
+
+func (s *fakeSharedState) Opened(name string, until time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.openUntil[name] = until
+}
+
+func TestCircuitBreaker_SharedState(t *testing.T) {
+	shared := &fakeSharedState{openUntil: make(map[string]time.Time)}
+	SetSharedState(shared)
+	t.Cleanup(func() { SetSharedState(nil) })
+
+	config := configv1.CircuitBreakerConfig_builder{
+		ConsecutiveFailures: proto.Int32(1),
+		OpenDuration:        durationpb.New(50 * time.Millisecond),
+		HalfOpenRequests:    proto.Int32(1),
+	}.Build()
+	newBreaker := func() *CircuitBreaker {
+		cb := NewCircuitBreaker(config)
+		cb.name = "weather"
+		return cb
+	}
+	ctx := context.Background()
+	ok := func(context.Context) error { return nil }
+
+	// Replica a opens its breaker, and shares it.
+	a := newBreaker()
+	_ = a.Execute(ctx, func(context.Context) error { return errors.New("error") })
+	until := shared.OpenUntil("weather")
+	assert.WithinDuration(t, time.Now().Add(50*time.Millisecond), until, 20*time.Millisecond)
+
+	// Replica b rejects calls until the same time, then probes again.
+	b := newBreaker()
+	var openErr *CircuitBreakerOpenError
+	assert.ErrorAs(t, b.Execute(ctx, ok), &openErr)
+	time.Sleep(time.Until(until) + 10*time.Millisecond)
+	require.NoError(t, b.Execute(ctx, ok))
+	assert.Equal(t, StateClosed, b.getState())
+
+	// Unnamed breakers are not shared.
+	c := NewCircuitBreaker(config)
+	shared.Opened("", time.Now().Add(time.Minute))
+	require.NoError(t, c.Execute(ctx, ok))
+}
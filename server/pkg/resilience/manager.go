@@ -18,23 +18,50 @@ type Manager struct {
 	timeout        *Timeout
 }
 
+// Option configures a Manager.
+type Option func(*managerOptions)
+
+type managerOptions struct {
+	name string
+}
+
+// WithName sets the name of the manager, usually the ID of the service it
+// protects. It identifies the circuit breaker to the state change observer.
+//
+// Parameters:
+//   - name: string. The name.
+//
+// Returns:
+//   - Option: The option.
+func WithName(name string) Option {
+	return func(o *managerOptions) {
+		o.name = name
+	}
+}
+
 // NewManager creates a new Manager with the given resilience configuration.
 //
 // Summary: Initializes a new Resilience Manager.
 //
 // Parameters:
 //   - config: *configv1.ResilienceConfig. The resilience configuration.
+//   - opts: ...Option. Options such as WithName.
 //
 // Returns:
 //   - *Manager: The initialized manager, or nil if no resilience features are enabled.
-func NewManager(config *configv1.ResilienceConfig) *Manager {
+func NewManager(config *configv1.ResilienceConfig, opts ...Option) *Manager {
 	if config == nil {
 		return nil
 	}
+	var options managerOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
 
 	var cb *CircuitBreaker
 	if config.GetCircuitBreaker() != nil {
 		cb = NewCircuitBreaker(config.GetCircuitBreaker())
+		cb.name = options.name
 	}
 
 	var r *Retry
@@ -58,6 +85,17 @@ func NewManager(config *configv1.ResilienceConfig) *Manager {
 	}
 }
 
+// CircuitBreaker returns the circuit breaker of the manager.
+//
+// Returns:
+//   - *CircuitBreaker: The breaker, or nil if none is configured.
+func (m *Manager) CircuitBreaker() *CircuitBreaker {
+	if m == nil {
+		return nil
+	}
+	return m.circuitBreaker
+}
+
 // Execute wraps the given function with resilience features.
 //
 // Summary: Executes the work function with configured resilience policies (timeout, retry, circuit breaker).
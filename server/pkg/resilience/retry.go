@@ -9,7 +9,10 @@ import (
 	"time"
 
 	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/telemetry"
 	"github.com/mcpany/core/server/pkg/util"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/types/known/durationpb"
 )
 
@@ -75,7 +78,7 @@ func (r *Retry) Execute(ctx context.Context, work func(context.Context) error) e
 			return ctx.Err()
 		}
 
-		err = work(ctx)
+		err = r.attempt(ctx, i, retries, work)
 		if err == nil {
 			return nil
 		}
@@ -100,6 +103,21 @@ func (r *Retry) Execute(ctx context.Context, work func(context.Context) error) e
 	return err
 }
 
+// attempt runs one attempt of the work. If retries are configured, the
+// attempt gets its own span, so retried upstream calls are visible in traces.
+func (r *Retry) attempt(ctx context.Context, i, retries int, work func(context.Context) error) error {
+	if retries == 0 {
+		return work(ctx)
+	}
+	ctx, span := telemetry.StartSpan(ctx, "resilience.retry.attempt", trace.WithAttributes(
+		attribute.Int("resilience.retry.attempt", i+1),
+		attribute.Int("resilience.retry.max_attempts", retries+1),
+	))
+	err := work(ctx)
+	telemetry.EndSpan(span, err)
+	return err
+}
+
 func (r *Retry) backoff(attempt int) time.Duration {
 	if attempt < 0 {
 		return r.config.GetBaseBackoff().AsDuration()
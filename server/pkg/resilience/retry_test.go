@@ -10,7 +10,13 @@ import (
 	"time"
 
 	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"google.golang.org/protobuf/types/known/durationpb"
 )
 
@@ -151,3 +157,35 @@ func TestRetry(t *testing.T) {
 		require.Less(t, elapsed, 600*time.Millisecond, "should not wait after the last attempt")
 	})
 }
+
+func TestRetry_AttemptSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(prev)
+
+	config := &configv1.RetryConfig{}
+	config.SetNumberOfRetries(2)
+	config.SetBaseBackoff(durationpb.New(time.Millisecond))
+	var attempts int
+	err := NewRetry(config).Execute(context.Background(), func(_ context.Context) error {
+		attempts++
+		if attempts < 2 {
+			return errors.New("transient error")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "resilience.retry.attempt", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Contains(t, spans[0].Attributes(), attribute.Int("resilience.retry.attempt", 1))
+	assert.Contains(t, spans[1].Attributes(), attribute.Int("resilience.retry.max_attempts", 3))
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+
+	// Without retries, no attempt spans are recorded.
+	require.NoError(t, NewRetry(&configv1.RetryConfig{}).Execute(context.Background(), func(context.Context) error { return nil }))
+	assert.Len(t, recorder.Ended(), 2)
+}
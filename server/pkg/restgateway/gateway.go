@@ -0,0 +1,190 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package restgateway serves every tool of the aggregated catalog as a plain
+// HTTP endpoint, POST /tools/{name}, described by a generated OpenAPI
+// document, so that HTTP consumers and curl-based smoke tests can call tools
+// without an MCP client.
+package restgateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/middleware"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxRequestBytes bounds the arguments of a call.
+const maxRequestBytes = 5 << 20
+
+// ToolSet is the aggregated MCP toolset served by the gateway.
+type ToolSet interface {
+	// ListTools lists the registered tools.
+	ListTools() []tool.Tool
+	// CallTool executes a tool, enforcing the profile of the context.
+	CallTool(ctx context.Context, req *tool.ExecutionRequest) (any, error)
+	// IsServiceAllowed reports whether a profile may use the tools of a
+	// service.
+	IsServiceAllowed(serviceID, profileID string) bool
+}
+
+// Options configures the gateway.
+type Options struct {
+	// Path is the base path the gateway is served under, e.g. "/rest/v1".
+	Path string
+	// Version is the version of the API in the OpenAPI document.
+	Version string
+}
+
+// Gateway is the REST facade of the toolset.
+type Gateway struct {
+	tools ToolSet
+	opts  Options
+	mux   *http.ServeMux
+}
+
+// New creates a REST gateway.
+//
+// Parameters:
+//   - tools (ToolSet): The toolset.
+//   - opts (Options): The configuration of the gateway.
+//
+// Returns:
+//   - *Gateway: The gateway.
+func New(tools ToolSet, opts Options) *Gateway {
+	g := &Gateway{tools: tools, opts: opts, mux: http.NewServeMux()}
+	g.mux.HandleFunc("/openapi.json", g.handleOpenAPI)
+	g.mux.HandleFunc("/tools/", g.handleCall)
+	return g
+}
+
+// ServeHTTP serves a gateway request.
+//
+// Parameters:
+//   - w (http.ResponseWriter): The response writer.
+//   - r (*http.Request): The request, with the base path of the gateway
+//     stripped.
+//
+// Side Effects:
+//   - Executes the tool of a POST /tools/{name} request.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+// visibleTools lists the tools the profile of the context may use, by name.
+func (g *Gateway) visibleTools(ctx context.Context) []tool.Tool {
+	profileID, _ := auth.ProfileIDFromContext(ctx)
+	var visible []tool.Tool
+	for _, t := range g.tools.ListTools() {
+		if t.MCPTool() == nil || t.Tool() == nil {
+			continue
+		}
+		if profileID != "" && !g.tools.IsServiceAllowed(t.Tool().GetServiceId(), profileID) {
+			continue
+		}
+		visible = append(visible, t)
+	}
+	return visible
+}
+
+func (g *Gateway) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	writeJSON(w, http.StatusOK, g.OpenAPI(r))
+}
+
+func (g *Gateway) handleCall(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/tools/")
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	// Tools the profile cannot use are not found, so that they do not leak.
+	var target tool.Tool
+	for _, t := range g.visibleTools(r.Context()) {
+		if t.MCPTool().Name == name {
+			target = t
+			break
+		}
+	}
+	if target == nil {
+		writeError(w, http.StatusNotFound, "unknown tool "+name)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, "the arguments are too large")
+		return
+	}
+	args := bytes.TrimSpace(body)
+	if len(args) == 0 {
+		args = []byte("{}")
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(args, &obj); err != nil {
+		writeError(w, http.StatusBadRequest, "the body must be a JSON object holding the arguments of the tool")
+		return
+	}
+
+	result, err := g.tools.CallTool(r.Context(), &tool.ExecutionRequest{ToolName: name, ToolInputs: args})
+	switch {
+	case errors.Is(err, middleware.ErrRateLimited):
+		w.Header().Set("Retry-After", "1")
+		writeError(w, http.StatusTooManyRequests, err.Error())
+		return
+	case errors.Is(err, middleware.ErrQuotaExceeded):
+		writeError(w, http.StatusTooManyRequests, err.Error())
+		return
+	case err != nil:
+		logging.GetLogger().Warn("REST gateway tool call failed", "tool", name, "error", err)
+		writeError(w, http.StatusBadGateway, "Tool execution failed: "+err.Error())
+		return
+	}
+
+	ctr, ok := result.(*mcp.CallToolResult)
+	if !ok {
+		ctr = &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: toText(result)}}}
+	}
+	status := http.StatusOK
+	if ctr.IsError {
+		status = http.StatusBadGateway
+	}
+	writeJSON(w, status, ctr)
+}
+
+// toText renders a result that is not an MCP tool result.
+func toText(result any) string {
+	if s, ok := result.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logging.GetLogger().Error("Failed to encode REST gateway response", "error", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,176 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package restgateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v1 "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/middleware"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeToolSet serves a weather tool, and a billing tool only the "finance"
+// profile may use.
+type fakeToolSet struct{}
+
+func (fakeToolSet) ListTools() []tool.Tool {
+	newTool := func(service, name string, output any) tool.Tool {
+		return &tool.MockTool{
+			ToolFunc: func() *v1.Tool {
+				return v1.Tool_builder{ServiceId: proto.String(service), Name: proto.String(name)}.Build()
+			},
+			MCPToolFunc: func() *mcp.Tool {
+				return &mcp.Tool{
+					Name:         service + "." + name,
+					Description:  "The " + name + " tool",
+					InputSchema:  map[string]any{"type": "object", "properties": map[string]any{"city": map[string]any{"type": "string"}}},
+					OutputSchema: output,
+				}
+			},
+		}
+	}
+	return []tool.Tool{
+		newTool("weather", "get_forecast", map[string]any{"type": "object", "properties": map[string]any{"sky": map[string]any{"type": "string"}}}),
+		newTool("billing", "list_invoices", nil),
+	}
+}
+
+func (fakeToolSet) CallTool(_ context.Context, req *tool.ExecutionRequest) (any, error) {
+	if req.ToolName == "billing.list_invoices" {
+		return nil, errors.New("upstream unavailable")
+	}
+	var args struct{ City string }
+	_ = json.Unmarshal(req.ToolInputs, &args)
+	if args.City == "" {
+		return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: "city is required"}}}, nil
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "Sunny in " + args.City}}}, nil
+}
+
+func (fakeToolSet) IsServiceAllowed(serviceID, profileID string) bool {
+	return serviceID != "billing" || profileID == "finance"
+}
+
+// limitedToolSet refuses the calls of the tools it blocks, as the chained
+// toolset of the server does with the errors of the rate limits and quotas
+// of the MCP middleware, and records the calls.
+type limitedToolSet struct {
+	fakeToolSet
+	blocked   string
+	exhausted string
+	calls     []string
+}
+
+func (l *limitedToolSet) CallTool(ctx context.Context, req *tool.ExecutionRequest) (any, error) {
+	l.calls = append(l.calls, req.ToolName)
+	if req.ToolName == l.blocked {
+		return nil, fmt.Errorf("%w for tool %s", middleware.ErrRateLimited, req.ToolName)
+	}
+	if req.ToolName == l.exhausted {
+		return nil, fmt.Errorf("%w: quota %q exceeded", middleware.ErrQuotaExceeded, "daily")
+	}
+	return l.fakeToolSet.CallTool(ctx, req)
+}
+
+func TestGateway_Call(t *testing.T) {
+	l := &limitedToolSet{blocked: "weather.blocked"}
+	g := New(l, Options{Path: "/rest/v1"})
+	do := func(method, path, body, profile string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if profile != "" {
+			req = req.WithContext(auth.ContextWithProfileID(req.Context(), profile))
+		}
+		rec := httptest.NewRecorder()
+		g.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/tools/weather.get_forecast", `{"city": "Oslo"}`, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"content": [{"type": "text", "text": "Sunny in Oslo"}]}`, rec.Body.String())
+	assert.Equal(t, []string{"weather.get_forecast"}, l.calls, "calls go through the toolset")
+
+	rec = do(http.MethodPost, "/tools/weather.get_forecast", "", "")
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	assert.JSONEq(t, `{"content": [{"type": "text", "text": "city is required"}], "isError": true}`, rec.Body.String())
+
+	rec = do(http.MethodPost, "/tools/billing.list_invoices", "{}", "finance")
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	assert.JSONEq(t, `{"error": "Tool execution failed: upstream unavailable"}`, rec.Body.String())
+
+	rec = do(http.MethodPost, "/tools/billing.list_invoices", "{}", "support")
+	assert.Equal(t, http.StatusNotFound, rec.Code, "tools the profile cannot use are not found")
+	rec = do(http.MethodPost, "/tools/weather.missing", "{}", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = do(http.MethodPost, "/tools/weather.get_forecast", `["Oslo"]`, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = do(http.MethodGet, "/tools/weather.get_forecast", "", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, http.MethodPost, rec.Header().Get("Allow"))
+
+	l.blocked = "weather.get_forecast"
+	rec = do(http.MethodPost, "/tools/weather.get_forecast", `{"city": "Oslo"}`, "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	l.blocked, l.exhausted = "", "weather.get_forecast"
+	rec = do(http.MethodPost, "/tools/weather.get_forecast", `{"city": "Oslo"}`, "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Empty(t, rec.Header().Get("Retry-After"))
+}
+
+func TestGateway_OpenAPI(t *testing.T) {
+	g := New(fakeToolSet{}, Options{Path: "/rest/v1", Version: "1.2.3"})
+	req := httptest.NewRequest(http.MethodGet, "http://mcpany.test/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	g.ServeHTTP(rec, req.WithContext(auth.ContextWithProfileID(req.Context(), "support")))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+		Info    struct {
+			Version string `json:"version"`
+		} `json:"info"`
+		Servers []struct {
+			URL string `json:"url"`
+		} `json:"servers"`
+		Paths map[string]struct {
+			Post struct {
+				OperationID string   `json:"operationId"`
+				Tags        []string `json:"tags"`
+				RequestBody struct {
+					Content map[string]struct {
+						Schema map[string]any `json:"schema"`
+					} `json:"content"`
+				} `json:"requestBody"`
+				Responses map[string]json.RawMessage `json:"responses"`
+			} `json:"post"`
+		} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "3.1.0", doc.OpenAPI)
+	assert.Equal(t, "1.2.3", doc.Info.Version)
+	require.Len(t, doc.Servers, 1)
+	assert.Equal(t, "http://mcpany.test/rest/v1", doc.Servers[0].URL)
+	require.Len(t, doc.Paths, 1, "the profile cannot use the billing service")
+	op := doc.Paths["/tools/weather.get_forecast"].Post
+	assert.Equal(t, "weather_get_forecast", op.OperationID)
+	assert.Equal(t, []string{"weather"}, op.Tags)
+	assert.Equal(t, "object", op.RequestBody.Content["application/json"].Schema["type"])
+	assert.Contains(t, string(op.Responses["200"]), `"structuredContent":{"properties":{"sky"`)
+	assert.Contains(t, op.Responses, "429")
+}
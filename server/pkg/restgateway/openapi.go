@@ -0,0 +1,147 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package restgateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// OpenAPI generates the OpenAPI 3.1 document of the tools the caller may use.
+//
+// Parameters:
+//   - r (*http.Request): The request of the caller, which determines the URL
+//     of the server and the profile of the caller.
+//
+// Returns:
+//   - map[string]any: The OpenAPI document.
+func (g *Gateway) OpenAPI(r *http.Request) map[string]any {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	errorResponse := map[string]any{"$ref": "#/components/responses/Error"}
+	paths := map[string]any{}
+	for _, t := range g.visibleTools(r.Context()) {
+		mcpTool := t.MCPTool()
+		resultSchema := map[string]any{"$ref": "#/components/schemas/CallToolResult"}
+		if outputSchema := schema(mcpTool.OutputSchema); outputSchema != nil {
+			resultSchema = map[string]any{
+				"allOf": []any{
+					resultSchema,
+					map[string]any{"type": "object", "properties": map[string]any{"structuredContent": outputSchema}},
+				},
+			}
+		}
+		inputSchema := schema(mcpTool.InputSchema)
+		if inputSchema == nil {
+			inputSchema = map[string]any{"type": "object"}
+		}
+		paths["/tools/"+mcpTool.Name] = map[string]any{
+			"post": map[string]any{
+				"operationId": operationID(mcpTool.Name),
+				"summary":     mcpTool.Name,
+				"description": mcpTool.Description,
+				"tags":        []string{t.Tool().GetServiceId()},
+				"requestBody": map[string]any{
+					"required": false,
+					"content":  map[string]any{"application/json": map[string]any{"schema": inputSchema}},
+				},
+				"responses": map[string]any{
+					"200": map[string]any{
+						"description": "The result of the tool.",
+						"content":     map[string]any{"application/json": map[string]any{"schema": resultSchema}},
+					},
+					"400": errorResponse,
+					"404": errorResponse,
+					"429": errorResponse,
+					"502": map[string]any{
+						"description": "The tool failed. Its error result, or the error of the call.",
+						"content":     map[string]any{"application/json": map[string]any{"schema": map[string]any{"oneOf": []any{resultSchema, map[string]any{"$ref": "#/components/schemas/Error"}}}}},
+					},
+				},
+			},
+		}
+	}
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":       "MCP Any Tools",
+			"description": "The tools of the upstream services aggregated by MCP Any.",
+			"version":     g.opts.Version,
+		},
+		"servers":  []any{map[string]any{"url": scheme + "://" + r.Host + g.opts.Path}},
+		"paths":    paths,
+		"security": []any{map[string]any{"apiKey": []string{}}, map[string]any{"bearer": []string{}}},
+		"components": map[string]any{
+			"securitySchemes": map[string]any{
+				"apiKey": map[string]any{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+				"bearer": map[string]any{"type": "http", "scheme": "bearer"},
+			},
+			"responses": map[string]any{
+				"Error": map[string]any{
+					"description": "The call was refused.",
+					"content":     map[string]any{"application/json": map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/Error"}}},
+				},
+			},
+			"schemas": map[string]any{
+				"Error": map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"error": map[string]any{"type": "string"}},
+					"required":   []string{"error"},
+				},
+				"CallToolResult": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"content": map[string]any{
+							"type": "array",
+							"items": map[string]any{
+								"type": "object",
+								"properties": map[string]any{
+									"type":     map[string]any{"type": "string", "enum": []string{"text", "image", "audio", "resource_link", "resource"}},
+									"text":     map[string]any{"type": "string"},
+									"data":     map[string]any{"type": "string", "contentEncoding": "base64"},
+									"mimeType": map[string]any{"type": "string"},
+								},
+								"required": []string{"type"},
+							},
+						},
+						"structuredContent": map[string]any{"type": "object"},
+						"isError":           map[string]any{"type": "boolean"},
+					},
+					"required": []string{"content"},
+				},
+			},
+		},
+	}
+}
+
+// schema returns a JSON schema of a tool as a generic value, or nil if there
+// is none.
+func schema(s any) any {
+	if s == nil {
+		return nil
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil
+	}
+	return v
+}
+
+// operationID derives an identifier usable by code generators from a tool
+// name.
+func operationID(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
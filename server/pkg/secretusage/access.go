@@ -0,0 +1,40 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package secretusage
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+)
+
+// AdminRole can reveal every secret.
+const AdminRole = "admin"
+
+// CanReveal reports whether the caller may read the value of the secret
+// through the admin API. Admins can reveal every secret; otherwise the caller
+// needs one of the secret's allowed roles, if it has any.
+//
+// Parameters:
+//   - ctx: context.Context. Carries the caller's roles, see auth.ContextWithRoles.
+//   - secret: *configv1.Secret. The secret.
+//
+// Returns:
+//   - bool: True if the caller may reveal the secret.
+func CanReveal(ctx context.Context, secret *configv1.Secret) bool {
+	allowed := secret.GetAllowedRoles()
+	if len(allowed) == 0 {
+		return true
+	}
+	roles, _ := auth.RolesFromContext(ctx)
+	for _, role := range roles {
+		if strings.EqualFold(role, AdminRole) || slices.Contains(allowed, role) {
+			return true
+		}
+	}
+	return false
+}
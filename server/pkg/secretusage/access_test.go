@@ -0,0 +1,25 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package secretusage
+
+import (
+	"context"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanReveal(t *testing.T) {
+	open := &configv1.Secret{}
+	restricted := configv1.Secret_builder{AllowedRoles: []string{"ops"}}.Build()
+
+	ctx := context.Background()
+	assert.True(t, CanReveal(ctx, open))
+	assert.False(t, CanReveal(ctx, restricted))
+	assert.False(t, CanReveal(auth.ContextWithRoles(ctx, []string{"viewer"}), restricted))
+	assert.True(t, CanReveal(auth.ContextWithRoles(ctx, []string{"viewer", "ops"}), restricted))
+	assert.True(t, CanReveal(auth.ContextWithRoles(ctx, []string{"Admin"}), restricted))
+}
@@ -0,0 +1,295 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package secretusage tracks which consumers read the stored secrets, finds
+// where the upstream services reference them, and decides who may reveal them
+// through the admin API.
+package secretusage
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/storage"
+	"github.com/mcpany/core/server/pkg/util"
+)
+
+// DefaultConsumer is the consumer of reads made outside a tool call, e.g.
+// while the server registers its services.
+const DefaultConsumer = "server"
+
+const defaultFlushInterval = 30 * time.Second
+
+// ServiceConsumer returns the consumer name of an upstream service.
+//
+// Parameters:
+//   - service: string. The service name.
+//
+// Returns:
+//   - string: The consumer name, "service:<name>".
+func ServiceConsumer(service string) string {
+	return "service:" + service
+}
+
+// AdminConsumer returns the consumer name of an admin API caller.
+//
+// Parameters:
+//   - user: string. The user ID; "unknown" if empty.
+//
+// Returns:
+//   - string: The consumer name, "admin-api:<user>".
+func AdminConsumer(user string) string {
+	if user == "" {
+		user = "unknown"
+	}
+	return "admin-api:" + user
+}
+
+// Recorder collects the reads of secrets reported by util.ResolveSecret and
+// periodically writes them to the stored secrets, so reads on the hot path
+// never wait for the database.
+type Recorder struct {
+	store    storage.Storage
+	interval time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	pending map[string]map[string]*read
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// read is the reads of one secret key by one consumer since the last flush.
+type read struct {
+	count int64
+	last  time.Time
+}
+
+// Option configures a Recorder.
+type Option func(*Recorder)
+
+// WithFlushInterval sets how often reads are written to the store.
+//
+// Parameters:
+//   - d: time.Duration. The interval; 30 seconds by default.
+//
+// Returns:
+//   - Option: The option.
+func WithFlushInterval(d time.Duration) Option {
+	return func(r *Recorder) { r.interval = d }
+}
+
+// WithClock sets the clock of the recorder.
+//
+// Parameters:
+//   - now: func() time.Time. The clock; time.Now by default.
+//
+// Returns:
+//   - Option: The option.
+func WithClock(now func() time.Time) Option {
+	return func(r *Recorder) { r.now = now }
+}
+
+// NewRecorder creates a Recorder.
+//
+// Summary: Initializes secret usage tracking.
+//
+// Parameters:
+//   - store: storage.Storage. The store holding the secrets.
+//   - opts: ...Option. The options.
+//
+// Returns:
+//   - *Recorder: The recorder.
+func NewRecorder(store storage.Storage, opts ...Option) *Recorder {
+	r := &Recorder{
+		store:    store,
+		interval: defaultFlushInterval,
+		now:      time.Now,
+		pending:  make(map[string]map[string]*read),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Observe records a read of the secret with the key. It has the signature of
+// util.SecretUsageObserver.
+//
+// Parameters:
+//   - ctx: context.Context. Names the consumer, see util.ContextWithSecretConsumer.
+//   - key: string. The key of the secret.
+func (r *Recorder) Observe(ctx context.Context, key string) {
+	consumer := util.SecretConsumerFromContext(ctx)
+	if consumer == "" {
+		consumer = DefaultConsumer
+	}
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	byConsumer, ok := r.pending[key]
+	if !ok {
+		byConsumer = make(map[string]*read)
+		r.pending[key] = byConsumer
+	}
+	rd, ok := byConsumer[consumer]
+	if !ok {
+		rd = &read{}
+		byConsumer[consumer] = rd
+	}
+	rd.count++
+	rd.last = now
+}
+
+// Flush writes the reads recorded since the last flush to the secrets with
+// matching keys. Reads of keys without a stored secret are dropped.
+//
+// Summary: Persists recorded secret reads.
+//
+// Parameters:
+//   - ctx: context.Context. The context for the storage calls.
+//
+// Returns:
+//   - error: An error if the secrets cannot be listed or saved.
+func (r *Recorder) Flush(ctx context.Context) error {
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = make(map[string]map[string]*read)
+	r.mu.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	secrets, err := r.store.ListSecrets(ctx)
+	if err != nil {
+		return err
+	}
+	for _, secret := range secrets {
+		byConsumer, ok := pending[secret.GetKey()]
+		if !ok {
+			continue
+		}
+		for consumer, rd := range byConsumer {
+			addUsage(secret, consumer, rd.count, rd.last)
+		}
+		if err := r.store.SaveSecret(ctx, secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start flushes the recorded reads periodically until Stop is called.
+//
+// Parameters:
+//   - ctx: context.Context. Flushing also stops when it is cancelled.
+//
+// Returns:
+//   - error: Always nil.
+//
+// Side Effects:
+//   - Starts a goroutine.
+func (r *Recorder) Start(ctx context.Context) error {
+	r.mu.Lock()
+	if r.cancel != nil {
+		r.mu.Unlock()
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	r.cancel = cancel
+	r.done = make(chan struct{})
+	done := r.done
+	r.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.Flush(ctx); err != nil {
+					logging.GetLogger().Warn("Failed to record secret usage", "error", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the periodic flush and writes the remaining reads.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the wait and the final flush.
+//
+// Returns:
+//   - error: An error if the final flush fails or the context expires.
+func (r *Recorder) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.cancel, r.done = nil, nil
+	r.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return r.Flush(ctx)
+}
+
+// RecordRead records a read of the secret by the consumer on the secret
+// itself. The caller saves the secret.
+//
+// Parameters:
+//   - secret: *configv1.Secret. The secret that was read.
+//   - consumer: string. The reader, e.g. AdminConsumer(user).
+//   - at: time.Time. The time of the read.
+//
+// Side Effects:
+//   - Updates the usage and last_used of the secret.
+func RecordRead(secret *configv1.Secret, consumer string, at time.Time) {
+	addUsage(secret, consumer, 1, at)
+}
+
+func addUsage(secret *configv1.Secret, consumer string, count int64, at time.Time) {
+	stamp := at.UTC().Format(time.RFC3339)
+	usage := secret.GetUsage()
+	var entry *configv1.SecretUsage
+	for _, u := range usage {
+		if u.GetConsumer() == consumer {
+			entry = u
+			break
+		}
+	}
+	if entry == nil {
+		entry = &configv1.SecretUsage{}
+		entry.SetConsumer(consumer)
+		usage = append(usage, entry)
+	}
+	entry.SetCount(entry.GetCount() + count)
+	if after(at, entry.GetLastUsed()) {
+		entry.SetLastUsed(stamp)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].GetConsumer() < usage[j].GetConsumer() })
+	secret.SetUsage(usage)
+	if after(at, secret.GetLastUsed()) {
+		secret.SetLastUsed(stamp)
+	}
+}
+
+// after reports whether t is later than the RFC3339 timestamp, or the
+// timestamp is empty or malformed.
+func after(t time.Time, stamp string) bool {
+	prev, err := time.Parse(time.RFC3339, stamp)
+	return err != nil || t.After(prev)
+}
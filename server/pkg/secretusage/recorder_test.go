@@ -0,0 +1,94 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package secretusage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/storage/memory"
+	"github.com/mcpany/core/server/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRecorder_Flush(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	require.NoError(t, store.SaveSecret(ctx, configv1.Secret_builder{
+		Id:  proto.String("gh"),
+		Key: proto.String("GITHUB_TOKEN"),
+	}.Build()))
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	r := NewRecorder(store, WithClock(func() time.Time { return now }))
+
+	svcCtx := util.ContextWithSecretConsumer(ctx, ServiceConsumer("github"))
+	r.Observe(svcCtx, "GITHUB_TOKEN")
+	r.Observe(svcCtx, "GITHUB_TOKEN")
+	r.Observe(ctx, "GITHUB_TOKEN")
+	r.Observe(svcCtx, "UNSTORED_KEY")
+	require.NoError(t, r.Flush(ctx))
+
+	secret, err := store.GetSecret(ctx, "gh")
+	require.NoError(t, err)
+	assert.Equal(t, "2026-01-02T03:04:05Z", secret.GetLastUsed())
+	require.Len(t, secret.GetUsage(), 2)
+	assert.Equal(t, DefaultConsumer, secret.GetUsage()[0].GetConsumer())
+	assert.Equal(t, int64(1), secret.GetUsage()[0].GetCount())
+	assert.Equal(t, "service:github", secret.GetUsage()[1].GetConsumer())
+	assert.Equal(t, int64(2), secret.GetUsage()[1].GetCount())
+
+	// Counts accumulate across flushes.
+	now = now.Add(time.Hour)
+	r.Observe(svcCtx, "GITHUB_TOKEN")
+	require.NoError(t, r.Flush(ctx))
+	secret, err = store.GetSecret(ctx, "gh")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), secret.GetUsage()[1].GetCount())
+	assert.Equal(t, "2026-01-02T04:04:05Z", secret.GetUsage()[1].GetLastUsed())
+	assert.Equal(t, "2026-01-02T04:04:05Z", secret.GetLastUsed())
+}
+
+func TestRecorder_ObservesResolveSecret(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	require.NoError(t, store.SaveSecret(ctx, configv1.Secret_builder{
+		Id:  proto.String("usage-test"),
+		Key: proto.String("SECRET_USAGE_TEST_KEY"),
+	}.Build()))
+	t.Setenv("SECRET_USAGE_TEST_KEY", "value")
+
+	r := NewRecorder(store)
+	util.SetSecretUsageObserver(r.Observe)
+	t.Cleanup(func() { util.SetSecretUsageObserver(nil) })
+
+	value, err := util.ResolveSecret(util.ContextWithSecretConsumer(ctx, ServiceConsumer("svc")), envSecret("SECRET_USAGE_TEST_KEY"))
+	require.NoError(t, err)
+	assert.Equal(t, "value", value)
+	require.NoError(t, r.Start(ctx))
+	require.NoError(t, r.Stop(ctx))
+
+	secret, err := store.GetSecret(ctx, "usage-test")
+	require.NoError(t, err)
+	require.Len(t, secret.GetUsage(), 1)
+	assert.Equal(t, "service:svc", secret.GetUsage()[0].GetConsumer())
+	assert.NotEmpty(t, secret.GetLastUsed())
+}
+
+func TestRecordRead_KeepsLatest(t *testing.T) {
+	secret := &configv1.Secret{}
+	later := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	RecordRead(secret, AdminConsumer(""), later)
+	RecordRead(secret, AdminConsumer(""), later.Add(-time.Hour))
+
+	require.Len(t, secret.GetUsage(), 1)
+	assert.Equal(t, "admin-api:unknown", secret.GetUsage()[0].GetConsumer())
+	assert.Equal(t, int64(2), secret.GetUsage()[0].GetCount())
+	assert.Equal(t, "2026-05-01T00:00:00Z", secret.GetUsage()[0].GetLastUsed())
+	assert.Equal(t, "2026-05-01T00:00:00Z", secret.GetLastUsed())
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package secretusage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/storage"
+	"github.com/mcpany/core/server/pkg/util"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Reference is a place in a service configuration that reads a secret.
+type Reference struct {
+	// Service is the name of the upstream service.
+	Service string `json:"service"`
+	// Field is the path of the secret value in the service configuration,
+	// e.g. "upstream_auth.bearer_token.token".
+	Field string `json:"field"`
+}
+
+// Report is where a secret is referenced and who has read it.
+type Report struct {
+	Secret     *configv1.Secret
+	References []Reference
+}
+
+// FindReferences returns the places in the services that read the secret
+// with the key, i.e. the secret values whose util.SecretUsageKey is the key.
+//
+// Summary: Finds the references to a secret.
+//
+// Parameters:
+//   - key: string. The key of the secret.
+//   - services: []*configv1.UpstreamServiceConfig. The services to search.
+//
+// Returns:
+//   - []Reference: The references, sorted by service and field.
+func FindReferences(key string, services []*configv1.UpstreamServiceConfig) []Reference {
+	if key == "" {
+		return nil
+	}
+	var refs []Reference
+	for _, svc := range services {
+		walk(svc.ProtoReflect(), "", func(path string, sv *configv1.SecretValue) {
+			if util.SecretUsageKey(sv) == key {
+				refs = append(refs, Reference{Service: svc.GetName(), Field: path})
+			}
+		})
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Service != refs[j].Service {
+			return refs[i].Service < refs[j].Service
+		}
+		return refs[i].Field < refs[j].Field
+	})
+	return refs
+}
+
+var secretValueName = (&configv1.SecretValue{}).ProtoReflect().Descriptor().FullName()
+
+// walk calls fn for every secret value set in the message.
+func walk(m protoreflect.Message, path string, fn func(string, *configv1.SecretValue)) {
+	if m.Descriptor().FullName() == secretValueName {
+		if sv, ok := m.Interface().(*configv1.SecretValue); ok {
+			fn(path, sv)
+		}
+		return
+	}
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		name := join(path, string(fd.Name()))
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Message() == nil {
+				return true
+			}
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				walk(mv.Message(), fmt.Sprintf("%s[%s]", name, k.String()), fn)
+				return true
+			})
+		case fd.IsList():
+			if fd.Message() == nil {
+				return true
+			}
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				walk(list.Get(i).Message(), fmt.Sprintf("%s[%d]", name, i), fn)
+			}
+		case fd.Message() != nil:
+			walk(v.Message(), name, fn)
+		}
+		return true
+	})
+}
+
+func join(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+// Lookup reports where the secret with the ID or name is referenced in the
+// services and who has read it.
+//
+// Summary: Builds the usage report of a secret.
+//
+// Parameters:
+//   - ctx: context.Context. The context for the storage calls.
+//   - store: storage.Storage. The store holding the secret.
+//   - idOrName: string. The ID or name of the secret.
+//   - services: []*configv1.UpstreamServiceConfig. The services to search for references.
+//
+// Returns:
+//   - *Report: The report, nil if there is no such secret.
+//   - error: An error if the secrets cannot be read.
+func Lookup(ctx context.Context, store storage.Storage, idOrName string, services []*configv1.UpstreamServiceConfig) (*Report, error) {
+	secret, err := store.GetSecret(ctx, idOrName)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		secrets, err := store.ListSecrets(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range secrets {
+			if s.GetName() == idOrName {
+				secret = s
+				break
+			}
+		}
+	}
+	if secret == nil {
+		return nil, nil
+	}
+	secret = proto.Clone(secret).(*configv1.Secret)
+	secret.ClearValue()
+	return &Report{Secret: secret, References: FindReferences(secret.GetKey(), services)}, nil
+}
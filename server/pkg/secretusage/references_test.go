@@ -0,0 +1,79 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package secretusage
+
+import (
+	"context"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func envSecret(key string) *configv1.SecretValue {
+	return configv1.SecretValue_builder{EnvironmentVariable: proto.String(key)}.Build()
+}
+
+func testServices() []*configv1.UpstreamServiceConfig {
+	return []*configv1.UpstreamServiceConfig{
+		configv1.UpstreamServiceConfig_builder{
+			Name: proto.String("github"),
+			UpstreamAuth: configv1.Authentication_builder{
+				BearerToken: configv1.BearerTokenAuth_builder{Token: envSecret("GITHUB_TOKEN")}.Build(),
+			}.Build(),
+		}.Build(),
+		configv1.UpstreamServiceConfig_builder{
+			Name: proto.String("cli"),
+			CommandLineService: configv1.CommandLineUpstreamService_builder{
+				Command: proto.String("gh"),
+				Env: map[string]*configv1.SecretValue{
+					"GH_TOKEN": envSecret("GITHUB_TOKEN"),
+					"OTHER":    envSecret("OTHER_KEY"),
+				},
+			}.Build(),
+		}.Build(),
+	}
+}
+
+func TestFindReferences(t *testing.T) {
+	refs := FindReferences("GITHUB_TOKEN", testServices())
+	assert.Equal(t, []Reference{
+		{Service: "cli", Field: "command_line_service.env[GH_TOKEN]"},
+		{Service: "github", Field: "upstream_auth.bearer_token.token"},
+	}, refs)
+
+	assert.Empty(t, FindReferences("MISSING", testServices()))
+	assert.Empty(t, FindReferences("", testServices()))
+}
+
+func TestLookup(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	require.NoError(t, store.SaveSecret(ctx, configv1.Secret_builder{
+		Id:    proto.String("gh"),
+		Name:  proto.String("GitHub token"),
+		Key:   proto.String("GITHUB_TOKEN"),
+		Value: proto.String("s3cr3t"),
+	}.Build()))
+
+	for _, idOrName := range []string{"gh", "GitHub token"} {
+		report, err := Lookup(ctx, store, idOrName, testServices())
+		require.NoError(t, err)
+		require.NotNil(t, report, idOrName)
+		assert.Equal(t, "gh", report.Secret.GetId())
+		assert.Empty(t, report.Secret.GetValue(), "the value must not leave the store")
+		assert.Len(t, report.References, 2)
+	}
+
+	report, err := Lookup(ctx, store, "missing", nil)
+	require.NoError(t, err)
+	assert.Nil(t, report)
+
+	stored, err := store.GetSecret(ctx, "gh")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", stored.GetValue())
+}
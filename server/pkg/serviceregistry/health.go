@@ -0,0 +1,370 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package serviceregistry
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	config "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/health"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/metrics"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/mcpany/core/server/pkg/upstream"
+)
+
+const (
+	// HealthStatusUnknown is the status of a service whose probes have not
+	// reached a threshold yet.
+	HealthStatusUnknown = "unknown"
+
+	defaultUnhealthyThreshold = 3
+	defaultHealthyThreshold   = 1
+	// maxProbeResolution bounds how late a due probe may run.
+	maxProbeResolution = time.Second
+	probeTimeout       = 5 * time.Second
+
+	upstreamHealthGauge        = "mcp_any_upstream_health_status"
+	upstreamFailuresGauge      = "mcp_any_upstream_health_consecutive_failures"
+	upstreamProbeLatencyMetric = "mcp_any_upstream_health_probe_latency_seconds"
+)
+
+// HealthChangeHook is called when the background probes find that a service
+// became unhealthy, or recovered.
+type HealthChangeHook func(serviceID, from, to string)
+
+// UpstreamHealth is the result of the active probes of an upstream service.
+//
+// Summary: The health of an upstream service.
+type UpstreamHealth struct {
+	Service              string     `json:"service"`
+	Status               string     `json:"status"`
+	Probe                string     `json:"probe"`
+	Interval             string     `json:"interval"`
+	ConsecutiveFailures  int        `json:"consecutive_failures"`
+	ConsecutiveSuccesses int        `json:"consecutive_successes"`
+	LatencyMs            float64    `json:"latency_ms"`
+	LastChecked          *time.Time `json:"last_checked,omitempty"`
+	LastSuccess          *time.Time `json:"last_success,omitempty"`
+	LastError            string     `json:"last_error,omitempty"`
+}
+
+// probeState is the result of the probes of a service so far.
+type probeState struct {
+	status               string
+	consecutiveFailures  int
+	consecutiveSuccesses int
+	latency              time.Duration
+	lastChecked          time.Time
+	lastSuccess          time.Time
+	lastError            string
+	nextProbe            time.Time
+}
+
+// healthSettings are the thresholds and the default interval of the probes.
+type healthSettings struct {
+	// baseInterval is the interval given to StartHealthChecks, used when the
+	// configuration sets none.
+	baseInterval       time.Duration
+	interval           time.Duration
+	unhealthyThreshold int
+	healthyThreshold   int
+}
+
+func (s healthSettings) defaultInterval() time.Duration {
+	if s.interval > 0 {
+		return s.interval
+	}
+	return s.baseInterval
+}
+
+// healthStatusSetter is implemented by the tool managers that hide the tools
+// of unhealthy services, such as *tool.Manager.
+type healthStatusSetter interface {
+	SetServiceHealthStatus(serviceID, status string)
+}
+
+// SetHealthConfig sets the default interval and the thresholds of the active
+// probes of the upstream services. It may be called again on reload.
+//
+// Parameters:
+//   - cfg (*config.UpstreamHealthConfig): The settings; nil restores the defaults.
+func (r *ServiceRegistry) SetHealthConfig(cfg *config.UpstreamHealthConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.health.interval = cfg.GetInterval().AsDuration()
+	r.health.unhealthyThreshold = int(cfg.GetUnhealthyThreshold())
+	if r.health.unhealthyThreshold <= 0 {
+		r.health.unhealthyThreshold = defaultUnhealthyThreshold
+	}
+	r.health.healthyThreshold = int(cfg.GetHealthyThreshold())
+	if r.health.healthyThreshold <= 0 {
+		r.health.healthyThreshold = defaultHealthyThreshold
+	}
+}
+
+// OnHealthChange adds a hook called when the background probes find that a
+// service became unhealthy or recovered. The hooks run without the lock of the
+// registry held.
+//
+// Parameters:
+//   - hook (HealthChangeHook): The hook to add.
+func (r *ServiceRegistry) OnHealthChange(hook HealthChangeHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.healthChangeHooks = append(r.healthChangeHooks, hook)
+}
+
+// UpstreamHealth returns the result of the active probes of each upstream
+// service, sorted by service.
+//
+// Returns:
+//   - []UpstreamHealth: The health of the probed services.
+func (r *ServiceRegistry) UpstreamHealth() []UpstreamHealth {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	summary := make([]UpstreamHealth, 0, len(r.probes))
+	for id, state := range r.probes {
+		cfg := r.serviceConfigs[id]
+		h := UpstreamHealth{
+			Service:              id,
+			Status:               state.status,
+			Probe:                health.ProbeKind(cfg),
+			Interval:             r.probeInterval(cfg).String(),
+			ConsecutiveFailures:  state.consecutiveFailures,
+			ConsecutiveSuccesses: state.consecutiveSuccesses,
+			LatencyMs:            float64(state.latency.Microseconds()) / 1000,
+			LastError:            state.lastError,
+		}
+		if !state.lastChecked.IsZero() {
+			lastChecked := state.lastChecked
+			h.LastChecked = &lastChecked
+		}
+		if !state.lastSuccess.IsZero() {
+			lastSuccess := state.lastSuccess
+			h.LastSuccess = &lastSuccess
+		}
+		summary = append(summary, h)
+	}
+	sort.Slice(summary, func(i, j int) bool { return summary[i].Service < summary[j].Service })
+	return summary
+}
+
+// probeInterval returns the interval between the probes of a service: the one
+// of its health check, or the default one. Caller MUST hold r.mu lock.
+func (r *ServiceRegistry) probeInterval(cfg *config.UpstreamServiceConfig) time.Duration {
+	if interval := health.ProbeInterval(cfg); interval > 0 {
+		return interval
+	}
+	return r.health.defaultInterval()
+}
+
+// recordProbe updates the state of a service with the result of a probe, and
+// reports whether its status changed. Caller MUST hold r.mu.Lock().
+func (r *ServiceRegistry) recordProbe(serviceID string, err error, latency time.Duration, now time.Time) (from, to string, changed bool) {
+	state, ok := r.probes[serviceID]
+	if !ok {
+		state = &probeState{status: HealthStatusUnknown}
+		r.probes[serviceID] = state
+	}
+	state.lastChecked = now
+	state.latency = latency
+	state.nextProbe = now.Add(r.probeInterval(r.serviceConfigs[serviceID]))
+
+	from = state.status
+	if err != nil {
+		state.lastError = err.Error()
+		state.consecutiveFailures++
+		state.consecutiveSuccesses = 0
+		r.healthErrors[serviceID] = state.lastError
+		if state.consecutiveFailures >= r.health.unhealthyThreshold {
+			state.status = tool.HealthStatusUnhealthy
+		}
+	} else {
+		state.lastError = ""
+		state.lastSuccess = now
+		state.consecutiveSuccesses++
+		state.consecutiveFailures = 0
+		delete(r.healthErrors, serviceID)
+		if state.consecutiveSuccesses >= r.health.healthyThreshold {
+			state.status = tool.HealthStatusHealthy
+		}
+	}
+
+	healthy := float32(0)
+	if state.status != tool.HealthStatusUnhealthy {
+		healthy = 1
+	}
+	metrics.SetGauge(upstreamHealthGauge, healthy, serviceID)
+	metrics.SetGauge(upstreamFailuresGauge, float32(state.consecutiveFailures), serviceID)
+	metrics.AddSampleWithLabels([]string{upstreamProbeLatencyMetric}, float32(latency.Seconds()), []metrics.Label{
+		{Name: "service_name", Value: serviceID},
+	})
+
+	if state.status == from {
+		return from, state.status, false
+	}
+	if setter, ok := r.toolManager.(healthStatusSetter); ok {
+		setter.SetServiceHealthStatus(serviceID, state.status)
+	}
+	return from, state.status, true
+}
+
+// StartHealthChecks starts probing the upstream services in the background,
+// each at the interval of its health check, or at the default interval.
+// After the unhealthy threshold of consecutive failures, the tools of a
+// service are hidden and their calls refused, until the healthy threshold of
+// consecutive successes.
+//
+// Parameters:
+//   - ctx (context.Context): The context to control the loop.
+//   - interval (time.Duration): The default interval between the probes of a service.
+//
+// Side Effects:
+//   - Starts a background goroutine.
+func (r *ServiceRegistry) StartHealthChecks(ctx context.Context, interval time.Duration) {
+	r.mu.Lock()
+	r.health.baseInterval = interval
+	r.mu.Unlock()
+
+	go func() {
+		for {
+			r.mu.RLock()
+			resolution := min(r.health.defaultInterval(), maxProbeResolution)
+			r.mu.RUnlock()
+
+			timer := time.NewTimer(resolution)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+				r.probeDue(ctx, time.Now())
+			}
+		}
+	}()
+}
+
+// checkAllHealth probes every upstream service now.
+func (r *ServiceRegistry) checkAllHealth(ctx context.Context) {
+	r.probeServices(ctx, func(string, time.Time) bool { return true })
+}
+
+// probeDue probes the upstream services whose next probe is due. A service
+// seen for the first time is probed one interval later.
+func (r *ServiceRegistry) probeDue(ctx context.Context, now time.Time) {
+	r.mu.Lock()
+	for id, u := range r.upstreams {
+		if _, ok := u.(upstream.HealthChecker); !ok {
+			continue
+		}
+		if _, ok := r.probes[id]; !ok {
+			r.probes[id] = &probeState{
+				status:    HealthStatusUnknown,
+				nextProbe: now.Add(r.probeInterval(r.serviceConfigs[id])),
+			}
+		}
+	}
+	r.mu.Unlock()
+
+	r.probeServices(ctx, func(id string, nextProbe time.Time) bool {
+		return !nextProbe.IsZero() && !now.Before(nextProbe)
+	})
+}
+
+// probeServices probes the upstream services selected by due, given their ID
+// and the time of their next probe.
+func (r *ServiceRegistry) probeServices(ctx context.Context, due func(id string, nextProbe time.Time) bool) {
+	type job struct {
+		id      string
+		checker upstream.HealthChecker
+		u       upstream.Upstream
+	}
+	r.mu.RLock()
+	// Copy upstreams to avoid holding lock during network calls
+	var targets []job
+	for id, u := range r.upstreams {
+		checker, ok := u.(upstream.HealthChecker)
+		if !ok {
+			continue
+		}
+		var nextProbe time.Time
+		if state, ok := r.probes[id]; ok {
+			nextProbe = state.nextProbe
+		}
+		if due(id, nextProbe) {
+			targets = append(targets, job{id: id, checker: checker, u: u})
+		}
+	}
+	r.mu.RUnlock()
+	if len(targets) == 0 {
+		return
+	}
+
+	// ⚡ BOLT: Parallelize health checks using a fixed-size worker pool.
+	// Randomized Selection from Top 5 High-Impact Targets.
+	// Instead of spawning N goroutines (one per service), we spawn a fixed number of workers
+	// to process the health checks. This reduces memory overhead and scheduler pressure at scale.
+	const numWorkers = 20
+	jobs := make(chan job, len(targets))
+	var wg sync.WaitGroup
+
+	// Spawn workers
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				// Use a short timeout for health checks
+				checkCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+				start := time.Now()
+				err := j.checker.CheckHealth(checkCtx)
+				latency := time.Since(start)
+				cancel()
+
+				r.mu.Lock()
+				if r.upstreams[j.id] != j.u {
+					// The service was unregistered or replaced during the probe.
+					r.mu.Unlock()
+					continue
+				}
+				from, to, changed := r.recordProbe(j.id, err, latency, time.Now())
+				hooks := r.healthChangeHooks
+				r.mu.Unlock()
+
+				if changed {
+					r.notifyHealthChange(hooks, j.id, from, to)
+				}
+			}
+		}()
+	}
+
+	// Submit jobs
+	for _, j := range targets {
+		jobs <- j
+	}
+	close(jobs)
+
+	wg.Wait()
+}
+
+// notifyHealthChange calls the hooks when a service became unhealthy or
+// recovered; a service leaving the unknown status for healthy is not a change
+// the hooks are told about.
+func (r *ServiceRegistry) notifyHealthChange(hooks []HealthChangeHook, serviceID, from, to string) {
+	switch {
+	case to == tool.HealthStatusUnhealthy:
+		logging.GetLogger().Warn("Upstream service is unhealthy, its tools are disabled", "service", serviceID)
+	case from == tool.HealthStatusUnhealthy:
+		logging.GetLogger().Info("Upstream service recovered, its tools are enabled", "service", serviceID)
+	default:
+		return
+	}
+	for _, hook := range hooks {
+		hook(serviceID, from, to)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package serviceregistry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	config "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/util"
+)
+
+// lazyRetryDelay is how long a lazy service that failed to initialize is not
+// retried, so that every request does not wait for an unreachable upstream.
+const lazyRetryDelay = 10 * time.Second
+
+// lazyService is a service with LAZY initialization that is not connected
+// yet.
+type lazyService struct {
+	config *config.UpstreamServiceConfig
+	// done is closed when the running initialization ends; nil if none runs.
+	done     chan struct{}
+	err      error
+	failedAt time.Time
+}
+
+// deferService records a service with LAZY initialization without connecting
+// it.
+func (r *ServiceRegistry) deferService(serviceConfig *config.UpstreamServiceConfig) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	serviceID, err := util.SanitizeServiceName(serviceConfig.GetName())
+	if err != nil {
+		return "", fmt.Errorf("failed to generate service key: %w", err)
+	}
+	if _, isActive := r.upstreams[serviceID]; isActive {
+		return "", fmt.Errorf("%w: %q", ErrServiceAlreadyRegistered, serviceConfig.GetName())
+	}
+	if l, ok := r.lazy[serviceID]; ok && l.done != nil {
+		return "", fmt.Errorf("%w: %q", ErrServiceAlreadyRegistered, serviceConfig.GetName())
+	}
+
+	r.injectProvenance(serviceConfig)
+	r.serviceConfigs[serviceID] = serviceConfig
+	delete(r.serviceErrors, serviceID)
+	r.lazy[serviceID] = &lazyService{config: serviceConfig}
+	logging.GetLogger().Info("Deferred initialization of lazy service until first use", "service", serviceConfig.GetName())
+	return serviceID, nil
+}
+
+// InitializeService connects a service with LAZY initialization and registers
+// its capabilities, if it was not done yet. Concurrent callers wait for the
+// same initialization. After a failure, the initialization is not retried for
+// a few seconds and the failure is returned instead.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the request that uses the service.
+//   - serviceID (string): The unique identifier of the service.
+//
+// Returns:
+//   - error: An error if the service failed to initialize.
+//
+// Side Effects:
+//   - Initiates network connections to the upstream service.
+//   - Registers tools, prompts, and resources with their respective managers.
+func (r *ServiceRegistry) InitializeService(ctx context.Context, serviceID string) error {
+	r.mu.Lock()
+	l, ok := r.lazy[serviceID]
+	if !ok {
+		r.mu.Unlock()
+		return nil
+	}
+	done := l.done
+	if done == nil {
+		if !l.failedAt.IsZero() && time.Since(l.failedAt) < lazyRetryDelay {
+			err := l.err
+			r.mu.Unlock()
+			return err
+		}
+		done = make(chan struct{})
+		l.done = done
+		go r.initialize(context.WithoutCancel(ctx), serviceID, l)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return l.err
+}
+
+// initialize runs the initialization of a lazy service. It outlives the
+// request that started it, so that the other requests waiting for it are not
+// failed if that request is cancelled.
+func (r *ServiceRegistry) initialize(ctx context.Context, serviceID string, l *lazyService) {
+	if timeout := l.config.GetResilience().GetTimeout().AsDuration(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	_, tools, _, err := r.register(ctx, l.config)
+	if err == nil {
+		r.notifyRegistered(ctx, serviceID, l.config)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		l.err = fmt.Errorf("failed to initialize lazy service %q: %w", l.config.GetName(), err)
+		l.failedAt = time.Now()
+		logging.GetLogger().Error("Failed to initialize lazy service", "service", l.config.GetName(), "error", err)
+	} else {
+		l.err = nil
+		if r.lazy[serviceID] == l {
+			delete(r.lazy, serviceID)
+		}
+		logging.GetLogger().Info("Initialized lazy service on first use", "service", l.config.GetName(),
+			"tools_count", len(tools), "duration", time.Since(start))
+	}
+	close(l.done)
+	l.done = nil
+}
+
+// InitializeLazyServices initializes all the services with LAZY
+// initialization that are not connected yet, in parallel. A failure is
+// logged and recorded as the error of the service.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the request that uses the services.
+//
+// Side Effects:
+//   - Initiates network connections to the upstream services.
+func (r *ServiceRegistry) InitializeLazyServices(ctx context.Context) {
+	r.mu.RLock()
+	if len(r.lazy) == 0 {
+		r.mu.RUnlock()
+		return
+	}
+	serviceIDs := make([]string, 0, len(r.lazy))
+	for serviceID := range r.lazy {
+		serviceIDs = append(serviceIDs, serviceID)
+	}
+	r.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, serviceID := range serviceIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = r.InitializeService(ctx, serviceID)
+		}()
+	}
+	wg.Wait()
+}
+
+// InitializeServiceForTool initializes the lazy service that a fully
+// qualified tool name belongs to, if any.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the tool call.
+//   - toolName (string): The fully qualified name of the tool.
+//
+// Returns:
+//   - error: An error if the service failed to initialize.
+func (r *ServiceRegistry) InitializeServiceForTool(ctx context.Context, toolName string) error {
+	serviceID, _, err := util.ParseToolName(toolName)
+	if err != nil || serviceID == "" {
+		return nil
+	}
+	return r.InitializeService(ctx, serviceID)
+}
@@ -0,0 +1,161 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package serviceregistry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/prompt"
+	"github.com/mcpany/core/server/pkg/resource"
+	"github.com/mcpany/core/server/pkg/upstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func newLazyTestRegistry(registerErr *atomic.Pointer[error], registered *atomic.Int32) *ServiceRegistry {
+	f := &mockFactory{
+		newUpstreamFunc: func() (upstream.Upstream, error) {
+			return &mockUpstream{
+				registerFunc: func(serviceName string) (string, []*configv1.ToolDefinition, []*configv1.ResourceDefinition, error) {
+					registered.Add(1)
+					if err := registerErr.Load(); err != nil {
+						return "", nil, nil, *err
+					}
+					return serviceName, []*configv1.ToolDefinition{configv1.ToolDefinition_builder{Name: proto.String("echo")}.Build()}, nil, nil
+				},
+			}, nil
+		},
+	}
+	return New(f, &mockToolManager{}, prompt.NewManager(), resource.NewManager(), auth.NewManager())
+}
+
+func lazyServiceConfig(name string) *configv1.UpstreamServiceConfig {
+	return configv1.UpstreamServiceConfig_builder{
+		Name:           proto.String(name),
+		Initialization: configv1.UpstreamServiceConfig_LAZY.Enum(),
+		HttpService: configv1.HttpUpstreamService_builder{
+			Address: proto.String("http://127.0.0.1"),
+		}.Build(),
+	}.Build()
+}
+
+func TestServiceRegistry_LazyInitialization(t *testing.T) {
+	var registerErr atomic.Pointer[error]
+	var registered atomic.Int32
+	registry := newLazyTestRegistry(&registerErr, &registered)
+
+	serviceID, tools, _, err := registry.RegisterService(context.Background(), lazyServiceConfig("lazy-svc"))
+	require.NoError(t, err)
+	assert.Empty(t, tools)
+	assert.Equal(t, int32(0), registered.Load(), "the upstream is not connected at registration")
+	_, ok := registry.GetServiceConfig(serviceID)
+	assert.True(t, ok, "the service is listed before its first use")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, registry.InitializeServiceForTool(context.Background(), serviceID+".echo"))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), registered.Load(), "concurrent first uses share one initialization")
+
+	require.NoError(t, registry.InitializeService(context.Background(), serviceID))
+	registry.InitializeLazyServices(context.Background())
+	assert.Equal(t, int32(1), registered.Load())
+	require.NoError(t, registry.InitializeServiceForTool(context.Background(), "unknown.echo"))
+}
+
+func TestServiceRegistry_LazyInitializationFailure(t *testing.T) {
+	var registerErr atomic.Pointer[error]
+	var registered atomic.Int32
+	registry := newLazyTestRegistry(&registerErr, &registered)
+	failure := errors.New("connection refused")
+	registerErr.Store(&failure)
+
+	serviceID, _, _, err := registry.RegisterService(context.Background(), lazyServiceConfig("flaky"))
+	require.NoError(t, err)
+
+	registry.InitializeLazyServices(context.Background())
+	assert.Equal(t, int32(1), registered.Load())
+	errMsg, ok := registry.GetServiceError(serviceID)
+	require.True(t, ok)
+	assert.Contains(t, errMsg, "connection refused")
+
+	err = registry.InitializeService(context.Background(), serviceID)
+	require.ErrorIs(t, err, failure)
+	assert.Contains(t, err.Error(), `failed to initialize lazy service "flaky"`)
+	assert.Equal(t, int32(1), registered.Load(), "a failed service is not retried right away")
+
+	registerErr.Store(nil)
+	registry.mu.Lock()
+	registry.lazy[serviceID].failedAt = time.Now().Add(-lazyRetryDelay)
+	registry.mu.Unlock()
+	require.NoError(t, registry.InitializeService(context.Background(), serviceID))
+	assert.Equal(t, int32(2), registered.Load())
+	_, ok = registry.GetServiceError(serviceID)
+	assert.False(t, ok)
+}
+
+func TestServiceRegistry_LazyUnregister(t *testing.T) {
+	var registerErr atomic.Pointer[error]
+	var registered atomic.Int32
+	registry := newLazyTestRegistry(&registerErr, &registered)
+
+	serviceID, _, _, err := registry.RegisterService(context.Background(), lazyServiceConfig("lazy-svc"))
+	require.NoError(t, err)
+	require.NoError(t, registry.UnregisterService(context.Background(), "lazy-svc"))
+
+	require.NoError(t, registry.InitializeService(context.Background(), serviceID))
+	assert.Equal(t, int32(0), registered.Load(), "a removed lazy service is not connected")
+}
+
+func TestServiceRegistry_OnServiceRegistered(t *testing.T) {
+	var registerErr atomic.Pointer[error]
+	var registered atomic.Int32
+	registry := newLazyTestRegistry(&registerErr, &registered)
+	var mu sync.Mutex
+	var notified []string
+	registry.OnServiceRegistered(func(_ context.Context, serviceID string, _ *configv1.UpstreamServiceConfig) {
+		mu.Lock()
+		defer mu.Unlock()
+		notified = append(notified, serviceID)
+	})
+
+	eager := lazyServiceConfig("eager-svc")
+	eager.SetInitialization(configv1.UpstreamServiceConfig_EAGER)
+	_, _, _, err := registry.RegisterService(context.Background(), eager)
+	require.NoError(t, err)
+	lazyID, _, _, err := registry.RegisterService(context.Background(), lazyServiceConfig("lazy-svc"))
+	require.NoError(t, err)
+	mu.Lock()
+	assert.Equal(t, []string{"eager-svc"}, notified, "a lazy service is not notified before its first use")
+	mu.Unlock()
+
+	require.NoError(t, registry.InitializeService(context.Background(), lazyID))
+	mu.Lock()
+	assert.Equal(t, []string{"eager-svc", lazyID}, notified)
+	mu.Unlock()
+
+	failure := errors.New("connection refused")
+	registerErr.Store(&failure)
+	_, _, _, err = registry.RegisterService(context.Background(), configv1.UpstreamServiceConfig_builder{
+		Name:        proto.String("broken"),
+		HttpService: configv1.HttpUpstreamService_builder{Address: proto.String("http://127.0.0.1")}.Build(),
+	}.Build())
+	require.Error(t, err)
+	mu.Lock()
+	assert.Len(t, notified, 2, "a failed registration is not notified")
+	mu.Unlock()
+}
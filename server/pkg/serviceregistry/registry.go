@@ -113,8 +113,20 @@ type ServiceRegistry struct {
 	promptManager   prompt.ManagerInterface
 	resourceManager resource.ManagerInterface
 	authManager     *auth.Manager
+	// lazy holds the services with LAZY initialization not connected yet.
+	lazy map[string]*lazyService
+	// registeredHooks are called after each successful registration.
+	registeredHooks []RegisteredHook
+	// probes holds the results of the active health probes of the services.
+	probes            map[string]*probeState
+	health            healthSettings
+	healthChangeHooks []HealthChangeHook
 }
 
+// RegisteredHook is called after the capabilities of a service are
+// registered, whether at startup, on reload or on first use.
+type RegisteredHook func(ctx context.Context, serviceID string, serviceConfig *config.UpstreamServiceConfig)
+
 // New creates and initializes a new ServiceRegistry.
 //
 // Parameters:
@@ -141,6 +153,12 @@ func New(factory factory.Factory, toolManager tool.ManagerInterface, promptManag
 		promptManager:   promptManager,
 		resourceManager: resourceManager,
 		authManager:     authManager,
+		lazy:            make(map[string]*lazyService),
+		probes:          make(map[string]*probeState),
+		health: healthSettings{
+			unhealthyThreshold: defaultUnhealthyThreshold,
+			healthyThreshold:   defaultHealthyThreshold,
+		},
 	}
 }
 
@@ -154,6 +172,9 @@ func New(factory factory.Factory, toolManager tool.ManagerInterface, promptManag
 // 5. Performs an initial health check.
 // 6. Sets up authentication if configured.
 //
+// A service with LAZY initialization is only recorded; it is connected on
+// first use, see InitializeService.
+//
 // Parameters:
 //   - ctx (context.Context): The registration context.
 //   - serviceConfig (*config.UpstreamServiceConfig): The configuration for the service.
@@ -174,6 +195,40 @@ func New(factory factory.Factory, toolManager tool.ManagerInterface, promptManag
 //   - Initiates network connections to upstream services.
 //   - Registers tools, prompts, and resources with their respective managers.
 func (r *ServiceRegistry) RegisterService(ctx context.Context, serviceConfig *config.UpstreamServiceConfig) (string, []*config.ToolDefinition, []*config.ResourceDefinition, error) {
+	if serviceConfig.GetInitialization() == config.UpstreamServiceConfig_LAZY {
+		serviceID, err := r.deferService(serviceConfig)
+		return serviceID, nil, nil, err
+	}
+	serviceID, tools, resources, err := r.register(ctx, serviceConfig)
+	if err == nil {
+		r.notifyRegistered(ctx, serviceID, serviceConfig)
+	}
+	return serviceID, tools, resources, err
+}
+
+// OnServiceRegistered adds a hook called after the capabilities of a service
+// are registered. The hooks run in the goroutine of the registration, without
+// the lock of the registry held.
+//
+// Parameters:
+//   - hook (RegisteredHook): The hook to add.
+func (r *ServiceRegistry) OnServiceRegistered(hook RegisteredHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.registeredHooks = append(r.registeredHooks, hook)
+}
+
+func (r *ServiceRegistry) notifyRegistered(ctx context.Context, serviceID string, serviceConfig *config.UpstreamServiceConfig) {
+	r.mu.RLock()
+	hooks := r.registeredHooks
+	r.mu.RUnlock()
+	for _, hook := range hooks {
+		hook(ctx, serviceID, serviceConfig)
+	}
+}
+
+// register connects a service and registers its capabilities.
+func (r *ServiceRegistry) register(ctx context.Context, serviceConfig *config.UpstreamServiceConfig) (string, []*config.ToolDefinition, []*config.ResourceDefinition, error) {
 	r.mu.Lock()
 
 	serviceID, err := util.SanitizeServiceName(serviceConfig.GetName())
@@ -267,15 +322,14 @@ func (r *ServiceRegistry) RegisterService(ctx context.Context, serviceConfig *co
 		return "", nil, nil, err
 	}
 
-	// Perform initial health check
+	// Perform initial health check, the first probe of the service
+	delete(r.probes, serviceID)
 	if checker, ok := u.(upstream.HealthChecker); ok {
 		// Use a short timeout for health checks
-		checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-		if hErr := checker.CheckHealth(checkCtx); hErr != nil {
-			r.healthErrors[serviceID] = hErr.Error()
-		} else {
-			delete(r.healthErrors, serviceID)
-		}
+		checkCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+		start := time.Now()
+		hErr := checker.CheckHealth(checkCtx)
+		r.recordProbe(serviceID, hErr, time.Since(start), time.Now())
 		cancel()
 	}
 
@@ -409,8 +463,11 @@ func (r *ServiceRegistry) UnregisterService(ctx context.Context, serviceName str
 	}
 
 	delete(r.serviceConfigs, serviceID)
+	delete(r.lazy, serviceID)
 	delete(r.serviceInfo, serviceID)
 	delete(r.serviceErrors, serviceID)
+	delete(r.healthErrors, serviceID)
+	delete(r.probes, serviceID)
 	r.toolManager.ClearToolsForService(serviceID)
 	r.promptManager.ClearPromptsForService(serviceID)
 	r.resourceManager.ClearResourcesForService(serviceID)
@@ -439,87 +496,6 @@ func (r *ServiceRegistry) GetServiceError(serviceID string) (string, bool) {
 	return err, ok
 }
 
-// StartHealthChecks initiates a background loop to periodically check the health of services.
-//
-// Parameters:
-//   - ctx (context.Context): The context to control the loop.
-//   - interval (time.Duration): The frequency of health checks.
-//
-// Side Effects:
-//   - Starts a background goroutine.
-func (r *ServiceRegistry) StartHealthChecks(ctx context.Context, interval time.Duration) {
-	go func() {
-		ticker := time.NewTicker(interval)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				r.checkAllHealth(ctx)
-			}
-		}
-	}()
-}
-
-func (r *ServiceRegistry) checkAllHealth(ctx context.Context) {
-	r.mu.RLock()
-	// Copy upstreams to avoid holding lock during network calls
-	targets := make(map[string]upstream.Upstream)
-	for id, u := range r.upstreams {
-		targets[id] = u
-	}
-	r.mu.RUnlock()
-
-	// ⚡ BOLT: Parallelize health checks using a fixed-size worker pool.
-	// Randomized Selection from Top 5 High-Impact Targets.
-	// Instead of spawning N goroutines (one per service), we spawn a fixed number of workers
-	// to process the health checks. This reduces memory overhead and scheduler pressure at scale.
-	const numWorkers = 20
-	type job struct {
-		id string
-		u  upstream.Upstream
-	}
-	jobs := make(chan job, len(targets))
-	var wg sync.WaitGroup
-
-	// Spawn workers
-	for i := 0; i < numWorkers; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for j := range jobs {
-				var errStr string
-				if checker, ok := j.u.(upstream.HealthChecker); ok {
-					// Use a short timeout for health checks
-					checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-					if err := checker.CheckHealth(checkCtx); err != nil {
-						errStr = err.Error()
-					}
-					cancel()
-				}
-
-				r.mu.Lock()
-				if errStr != "" {
-					r.healthErrors[j.id] = errStr
-				} else {
-					delete(r.healthErrors, j.id)
-				}
-				r.mu.Unlock()
-			}
-		}()
-	}
-
-	// Submit jobs
-	for id, u := range targets {
-		jobs <- job{id: id, u: u}
-	}
-	close(jobs)
-
-	wg.Wait()
-}
-
 // Close gracefully shuts down the registry and all registered services.
 //
 // Parameters:
@@ -6,16 +6,22 @@ package serviceregistry
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	configv1 "github.com/mcpany/core/proto/config/v1"
+	v1 "github.com/mcpany/core/proto/mcp_router/v1"
 	"github.com/mcpany/core/server/pkg/auth"
 	"github.com/mcpany/core/server/pkg/prompt"
 	"github.com/mcpany/core/server/pkg/resource"
+	"github.com/mcpany/core/server/pkg/tool"
 	"github.com/mcpany/core/server/pkg/upstream"
 	"github.com/mcpany/core/server/pkg/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 type mockHealthCheckerUpstream struct {
@@ -85,3 +91,117 @@ func TestHealthCheck(t *testing.T) {
 	msg, ok = registry.GetServiceError(serviceID)
 	assert.False(t, ok, "Service should be healthy now")
 }
+
+func TestActiveProbes_Thresholds(t *testing.T) {
+	var down atomic.Bool
+	tm := tool.NewManager(nil)
+	f := &mockFactory{
+		newUpstreamFunc: func() (upstream.Upstream, error) {
+			return &mockHealthCheckerUpstream{
+				mockUpstream: mockUpstream{
+					registerFunc: func(serviceName string) (string, []*configv1.ToolDefinition, []*configv1.ResourceDefinition, error) {
+						tm.AddServiceInfo(serviceName, &tool.ServiceInfo{Name: serviceName})
+						require.NoError(t, tm.AddTool(&tool.MockTool{ToolFunc: func() *v1.Tool {
+							return v1.Tool_builder{ServiceId: proto.String(serviceName), Name: proto.String("lookup")}.Build()
+						}}))
+						return serviceName, nil, nil, nil
+					},
+				},
+				checkHealthFunc: func(context.Context) error {
+					if down.Load() {
+						return errors.New("connection refused")
+					}
+					return nil
+				},
+			}, nil
+		},
+	}
+	registry := New(f, tm, prompt.NewManager(), resource.NewManager(), auth.NewManager())
+	registry.SetHealthConfig(configv1.UpstreamHealthConfig_builder{
+		UnhealthyThreshold: proto.Int32(2),
+		HealthyThreshold:   proto.Int32(2),
+	}.Build())
+	var changes []string
+	registry.OnHealthChange(func(serviceID, from, to string) {
+		changes = append(changes, serviceID+": "+from+" -> "+to)
+	})
+
+	serviceConfig := &configv1.UpstreamServiceConfig{}
+	serviceConfig.SetName("crm")
+	_, _, _, err := registry.RegisterService(context.Background(), serviceConfig)
+	require.NoError(t, err)
+	require.Len(t, tm.ListTools(), 1)
+	summary := registry.UpstreamHealth()
+	require.Len(t, summary, 1)
+	assert.Equal(t, HealthStatusUnknown, summary[0].Status, "the registration is the first probe, below the healthy threshold")
+	assert.Equal(t, 1, summary[0].ConsecutiveSuccesses)
+	assert.Equal(t, "connection", summary[0].Probe)
+
+	down.Store(true)
+	registry.checkAllHealth(context.Background())
+	assert.Len(t, tm.ListTools(), 1, "a single failure is below the threshold")
+	assert.Equal(t, HealthStatusUnknown, registry.UpstreamHealth()[0].Status)
+
+	registry.checkAllHealth(context.Background())
+	summary = registry.UpstreamHealth()
+	assert.Equal(t, tool.HealthStatusUnhealthy, summary[0].Status)
+	assert.Equal(t, 2, summary[0].ConsecutiveFailures)
+	assert.Equal(t, "connection refused", summary[0].LastError)
+	assert.Empty(t, tm.ListTools(), "the tools of an unhealthy service are hidden")
+
+	down.Store(false)
+	registry.checkAllHealth(context.Background())
+	assert.Empty(t, tm.ListTools(), "a single success is below the threshold")
+	registry.checkAllHealth(context.Background())
+	assert.Len(t, tm.ListTools(), 1)
+	assert.Equal(t, []string{"crm: unknown -> unhealthy", "crm: unhealthy -> healthy"}, changes)
+
+	require.NoError(t, registry.UnregisterService(context.Background(), "crm"))
+	assert.Empty(t, registry.UpstreamHealth())
+}
+
+func TestActiveProbes_Intervals(t *testing.T) {
+	probes := map[string]*atomic.Int32{"fast": {}, "slow": {}}
+	f := &mockFactory{
+		newUpstreamFunc: func() (upstream.Upstream, error) {
+			var name string
+			return &mockHealthCheckerUpstream{
+				mockUpstream: mockUpstream{
+					registerFunc: func(serviceName string) (string, []*configv1.ToolDefinition, []*configv1.ResourceDefinition, error) {
+						name = serviceName
+						return serviceName, nil, nil, nil
+					},
+				},
+				checkHealthFunc: func(context.Context) error {
+					probes[name].Add(1)
+					return nil
+				},
+			}, nil
+		},
+	}
+	registry := New(f, &mockToolManager{}, prompt.NewManager(), resource.NewManager(), auth.NewManager())
+	for name, interval := range map[string]time.Duration{"fast": 20 * time.Millisecond, "slow": time.Hour} {
+		serviceConfig := configv1.UpstreamServiceConfig_builder{
+			Name: proto.String(name),
+			GrpcService: configv1.GrpcUpstreamService_builder{
+				Address:     proto.String("localhost:50051"),
+				HealthCheck: configv1.GrpcHealthCheck_builder{Interval: durationpb.New(interval)}.Build(),
+			}.Build(),
+		}.Build()
+		_, _, _, err := registry.RegisterService(context.Background(), serviceConfig)
+		require.NoError(t, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	registry.StartHealthChecks(ctx, time.Hour)
+
+	assert.Eventually(t, func() bool { return probes["fast"].Load() >= 3 }, 5*time.Second, 10*time.Millisecond,
+		"each service is probed at the interval of its health check")
+	assert.Equal(t, int32(1), probes["slow"].Load(), "only the registration probed the slow service")
+	summary := registry.UpstreamHealth()
+	require.Len(t, summary, 2)
+	assert.Equal(t, "fast", summary[0].Service)
+	assert.Equal(t, "grpc", summary[0].Probe)
+	assert.Equal(t, "20ms", summary[0].Interval)
+}
@@ -0,0 +1,313 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package sharedstate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/idgen"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultHeartbeatInterval is how often a replica renews its registration
+// and its leadership when heartbeat_interval is not set.
+const DefaultHeartbeatInterval = 5 * time.Second
+
+// missedHeartbeats is the number of heartbeats after which a replica is
+// dropped from the registry, and its leadership expires.
+const missedHeartbeats = 3
+
+// renewLeadership extends the lease of the leader, if this replica holds it.
+var renewLeadership = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// releaseLeadership deletes the lease of the leader, if this replica holds it.
+var releaseLeadership = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// Instance is a replica in the instance registry.
+type Instance struct {
+	// ID identifies the replica.
+	ID string `json:"id"`
+	// Address is the advertise address of the replica, if any.
+	Address string `json:"address,omitempty"`
+	// Version is the version of the server.
+	Version string `json:"version,omitempty"`
+	// StartedAt is when the replica joined the cluster.
+	StartedAt time.Time `json:"startedAt"`
+	// LastSeen is the last heartbeat of the replica.
+	LastSeen time.Time `json:"lastSeen"`
+	// Leader reports whether the replica leads the cluster.
+	Leader bool `json:"leader"`
+}
+
+// Membership registers this replica in the instance registry, and elects one
+// of the replicas as the leader of the cluster, which runs the singleton
+// duties. The leader holds a lease in Redis, which it renews at each
+// heartbeat; when it stops or misses its renewals, another replica takes it
+// over. It implements cluster.Leader.
+//
+// Summary: Registers the replica and elects the leader of the cluster.
+type Membership struct {
+	store    *Store
+	interval time.Duration
+	ttl      time.Duration
+
+	mu   sync.Mutex
+	self Instance
+	// leaseUntil is when the lease of this replica expires, if it leads. The
+	// leadership ends then even if Redis cannot be reached to say so.
+	leaseUntil time.Time
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+// NewMembership creates the membership of this replica.
+//
+// Summary: Creates the membership of the replica.
+//
+// Parameters:
+//   - instanceID: string. The ID of the replica, or empty for the host name
+//     followed by a random suffix.
+//   - version: string. The version of the server.
+//   - interval: time.Duration. How often the replica renews its registration,
+//     or 0 for DefaultHeartbeatInterval.
+//
+// Returns:
+//   - *Membership: The membership, not yet started.
+func (s *Store) NewMembership(instanceID, version string, interval time.Duration) *Membership {
+	if instanceID == "" {
+		host, err := os.Hostname()
+		if err != nil || host == "" {
+			host = "mcpany"
+		}
+		id := idgen.New()
+		instanceID = host + "-" + id[len(id)-8:]
+	}
+	if interval <= 0 {
+		interval = DefaultHeartbeatInterval
+	}
+	self := Instance{ID: instanceID, Version: version}
+	if s.advertise != nil {
+		self.Address = s.advertise.String()
+	}
+	return &Membership{store: s, interval: interval, ttl: missedHeartbeats * interval, self: self}
+}
+
+// ID returns the ID of this replica.
+//
+// Returns:
+//   - string: The ID.
+func (m *Membership) ID() string {
+	return m.self.ID
+}
+
+// Start registers the replica, runs for the leadership, and keeps both
+// renewed until Stop.
+//
+// Summary: Joins the cluster.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the first heartbeat.
+//
+// Returns:
+//   - error: Always nil; a failed heartbeat is logged and retried.
+//
+// Side Effects:
+//   - Starts a goroutine writing to Redis.
+func (m *Membership) Start(ctx context.Context) error {
+	m.mu.Lock()
+	if m.cancel != nil {
+		m.mu.Unlock()
+		return nil
+	}
+	m.self.StartedAt = time.Now().UTC()
+	loopCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	m.cancel = cancel
+	m.done = make(chan struct{})
+	done := m.done
+	m.mu.Unlock()
+
+	m.heartbeat(ctx)
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-loopCtx.Done():
+				return
+			case <-ticker.C:
+				m.heartbeat(loopCtx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the heartbeats, gives up the leadership and leaves the registry,
+// so that another replica takes over at once.
+//
+// Summary: Leaves the cluster.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the wait and the writes to Redis.
+//
+// Returns:
+//   - error: An error if the context expires or Redis cannot be reached.
+func (m *Membership) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	cancel, done := m.cancel, m.done
+	m.cancel, m.done = nil, nil
+	m.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	m.mu.Lock()
+	m.leaseUntil = time.Time{}
+	m.mu.Unlock()
+	if err := releaseLeadership.Run(ctx, m.store.client, []string{m.store.leaderKey()}, m.self.ID).Err(); err != nil {
+		return fmt.Errorf("failed to release the leadership: %w", err)
+	}
+	if err := m.store.client.HDel(ctx, m.store.instancesKey(), m.self.ID).Err(); err != nil {
+		return fmt.Errorf("failed to leave the instance registry: %w", err)
+	}
+	return nil
+}
+
+// IsLeader reports whether this replica holds an unexpired lease of the
+// leadership.
+//
+// Returns:
+//   - bool: True if this replica leads the cluster.
+func (m *Membership) IsLeader() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return time.Now().Before(m.leaseUntil)
+}
+
+// Instances returns the replicas of the registry, and drops the ones that
+// missed their heartbeats.
+//
+// Summary: Lists the replicas of the cluster.
+//
+// Parameters:
+//   - ctx: context.Context. The request context.
+//
+// Returns:
+//   - []Instance: The replicas, oldest first.
+//   - error: An error if Redis cannot be reached.
+func (m *Membership) Instances(ctx context.Context) ([]Instance, error) {
+	entries, err := m.store.client.HGetAll(ctx, m.store.instancesKey()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list instances: %w", err)
+	}
+	leader, err := m.store.client.Get(ctx, m.store.leaderKey()).Result()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get the leader: %w", err)
+	}
+	now := time.Now()
+	var instances []Instance
+	var stale []string
+	for id, data := range entries {
+		var instance Instance
+		if err := json.Unmarshal([]byte(data), &instance); err != nil || now.Sub(instance.LastSeen) > m.ttl {
+			stale = append(stale, id)
+			continue
+		}
+		instance.Leader = instance.ID == leader
+		instances = append(instances, instance)
+	}
+	if len(stale) > 0 {
+		if err := m.store.client.HDel(ctx, m.store.instancesKey(), stale...).Err(); err != nil {
+			logging.GetLogger().Warn("Failed to drop stale instances", "error", err)
+		}
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		if !instances[i].StartedAt.Equal(instances[j].StartedAt) {
+			return instances[i].StartedAt.Before(instances[j].StartedAt)
+		}
+		return instances[i].ID < instances[j].ID
+	})
+	return instances, nil
+}
+
+// heartbeat renews the registration of the replica, and renews or acquires
+// the leadership.
+func (m *Membership) heartbeat(ctx context.Context) {
+	log := logging.GetLogger().With("instance", m.self.ID)
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+	now := time.Now()
+
+	m.mu.Lock()
+	self := m.self
+	wasLeader := now.Before(m.leaseUntil)
+	m.mu.Unlock()
+	self.LastSeen = now.UTC()
+	if data, err := json.Marshal(self); err == nil {
+		if err := m.store.client.HSet(ctx, m.store.instancesKey(), self.ID, data).Err(); err != nil {
+			log.Warn("Failed to renew the registration of the instance", "error", err)
+		}
+	}
+
+	var leads bool
+	if wasLeader {
+		renewed, err := renewLeadership.Run(ctx, m.store.client, []string{m.store.leaderKey()}, self.ID, m.ttl.Milliseconds()).Int()
+		if err != nil {
+			// The lease runs until it expires
+			log.Warn("Failed to renew the leadership", "error", err)
+			return
+		}
+		leads = renewed == 1
+	} else {
+		acquired, err := m.store.client.SetNX(ctx, m.store.leaderKey(), self.ID, m.ttl).Result()
+		if err != nil {
+			log.Warn("Failed to run for the leadership", "error", err)
+			return
+		}
+		leads = acquired
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if leads {
+		m.leaseUntil = now.Add(m.ttl)
+	} else {
+		m.leaseUntil = time.Time{}
+	}
+	switch {
+	case leads && !wasLeader:
+		log.Info("This instance now leads the cluster")
+	case !leads && wasLeader:
+		log.Warn("This instance no longer leads the cluster")
+	}
+}
+
+func (s *Store) instancesKey() string {
+	return s.prefix + "instances"
+}
+
+func (s *Store) leaderKey() string {
+	return s.prefix + "leader"
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package sharedstate
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMembership_LeaderElection(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+	a := newTestStore(t, mr, "http://10.0.0.1:50050").NewMembership("a", "1.2.0", time.Hour)
+	b := newTestStore(t, mr, "").NewMembership("b", "1.2.0", time.Hour)
+
+	require.NoError(t, a.Start(ctx))
+	require.NoError(t, b.Start(ctx))
+	assert.True(t, a.IsLeader(), "the first replica leads")
+	assert.False(t, b.IsLeader())
+
+	instances, err := b.Instances(ctx)
+	require.NoError(t, err)
+	require.Len(t, instances, 2)
+	assert.Equal(t, "a", instances[0].ID, "the oldest replica is listed first")
+	assert.True(t, instances[0].Leader)
+	assert.Equal(t, "http://10.0.0.1:50050", instances[0].Address)
+	assert.Equal(t, "1.2.0", instances[0].Version)
+	assert.False(t, instances[1].Leader)
+
+	// The leader renews its lease, and the other replica does not take it
+	a.heartbeat(ctx)
+	b.heartbeat(ctx)
+	assert.True(t, a.IsLeader())
+	assert.False(t, b.IsLeader())
+
+	require.NoError(t, a.Stop(ctx))
+	assert.False(t, a.IsLeader())
+	b.heartbeat(ctx)
+	assert.True(t, b.IsLeader(), "a replica takes over when the leader stops")
+	instances, err = b.Instances(ctx)
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, "b", instances[0].ID)
+	require.NoError(t, b.Stop(ctx))
+}
+
+func TestMembership_LeaseExpires(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+	a := newTestStore(t, mr, "").NewMembership("a", "", 20*time.Millisecond)
+	require.NoError(t, a.Start(ctx))
+	require.True(t, a.IsLeader())
+
+	// Redis is lost: the leader steps down when its lease expires
+	mr.Close()
+	assert.Eventually(t, func() bool { return !a.IsLeader() }, 2*time.Second, 10*time.Millisecond)
+	_ = a.Stop(ctx)
+}
+
+func TestMembership_Instances_DropsStale(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+	s := newTestStore(t, mr, "")
+	m := s.NewMembership("", "", time.Hour)
+	assert.NotEmpty(t, m.ID(), "the ID defaults to the host name")
+	require.NoError(t, m.Start(ctx))
+	defer func() { _ = m.Stop(ctx) }()
+
+	stale, err := json.Marshal(Instance{ID: "gone", LastSeen: time.Now().Add(-4 * time.Hour)})
+	require.NoError(t, err)
+	mr.HSet(s.instancesKey(), "gone", string(stale))
+	mr.HSet(s.instancesKey(), "corrupt", "{")
+
+	instances, err := m.Instances(ctx)
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, m.ID(), instances[0].ID)
+	keys, err := mr.HKeys(s.instancesKey())
+	require.NoError(t, err)
+	assert.Equal(t, []string{m.ID()}, keys, "the stale entries are dropped")
+}
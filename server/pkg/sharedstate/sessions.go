@@ -0,0 +1,154 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package sharedstate
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+
+	"github.com/mcpany/core/server/pkg/consts"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/redis/go-redis/v9"
+)
+
+// HeaderForwardedBy marks a request forwarded by another replica, so that it
+// is served where it arrives and never forwarded again.
+const HeaderForwardedBy = "X-Mcpany-Forwarded-By"
+
+// SessionAffinity returns an HTTP middleware for the streamable HTTP MCP
+// endpoint. It registers the sessions that next creates as owned by this
+// replica, and forwards the requests of the sessions owned by another replica
+// to it. Without an advertise address, next is returned unchanged.
+//
+// Summary: Routes the requests of an MCP session to the replica holding it.
+//
+// Parameters:
+//   - next: http.Handler. The MCP handler.
+//
+// Returns:
+//   - http.Handler: The wrapped handler.
+//
+// Side Effects:
+//   - Registers, refreshes and removes sessions in Redis, and proxies
+//     requests to the other replicas.
+func (s *Store) SessionAffinity(next http.Handler) http.Handler {
+	if s.advertise == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(consts.HeaderMcpSessionID)
+		if id == "" {
+			next.ServeHTTP(&sessionRecorder{ResponseWriter: w, store: s, ctx: r.Context()}, r)
+			return
+		}
+
+		if r.Header.Get(HeaderForwardedBy) == "" {
+			owner, err := s.client.Get(r.Context(), s.sessionKey(id)).Result()
+			switch {
+			case err == nil && owner != s.advertise.String():
+				s.forward(w, r, id, owner)
+				return
+			case err != nil && !errors.Is(err, redis.Nil):
+				logging.GetLogger().Warn("Failed to look up the owner of the MCP session, serving it locally", "error", err)
+			}
+		}
+
+		if r.Method == http.MethodDelete {
+			next.ServeHTTP(w, r)
+			s.client.Del(context.WithoutCancel(r.Context()), s.sessionKey(id))
+			return
+		}
+		s.client.Expire(r.Context(), s.sessionKey(id), s.sessionTTL)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// forward proxies the request to the replica owning the session. If the
+// replica cannot be reached, the session is dropped and the client gets a
+// 404, which tells MCP clients to start a new session.
+func (s *Store) forward(w http.ResponseWriter, r *http.Request, id, owner string) {
+	if proxy, ok := s.proxies.Load(owner); ok {
+		proxy.(*httputil.ReverseProxy).ServeHTTP(w, r)
+		return
+	}
+	target, err := url.Parse(owner)
+	if err != nil {
+		logging.GetLogger().Warn("Dropping MCP session with an invalid owner", "owner", owner, "error", err)
+		s.client.Del(r.Context(), s.sessionKey(id))
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			// The endpoint may be mounted under a stripped prefix: forward
+			// the path the client requested.
+			if u, err := url.ParseRequestURI(pr.In.RequestURI); err == nil {
+				pr.Out.URL.Path, pr.Out.URL.RawPath, pr.Out.URL.RawQuery = u.Path, u.RawPath, u.RawQuery
+			}
+			pr.SetURL(target)
+			pr.SetXForwarded()
+			pr.Out.Header.Set(HeaderForwardedBy, s.advertise.String())
+		},
+		// Stream the server-sent events as they come.
+		FlushInterval: -1,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			logging.GetLogger().Warn("Failed to forward MCP session request, dropping the session", "owner", owner, "error", err)
+			s.client.Del(context.WithoutCancel(r.Context()), s.sessionKey(r.Header.Get(consts.HeaderMcpSessionID)))
+			http.Error(w, "session not found", http.StatusNotFound)
+		},
+	}
+	actual, _ := s.proxies.LoadOrStore(owner, proxy)
+	actual.(*httputil.ReverseProxy).ServeHTTP(w, r)
+}
+
+func (s *Store) sessionKey(id string) string {
+	return s.prefix + "sessions:" + id
+}
+
+// sessionRecorder registers the session that the response creates.
+type sessionRecorder struct {
+	http.ResponseWriter
+	store       *Store
+	ctx         context.Context
+	wroteHeader bool
+}
+
+// WriteHeader registers the session of the response header, if any.
+func (w *sessionRecorder) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if id := w.Header().Get(consts.HeaderMcpSessionID); id != "" && code < http.StatusBadRequest {
+			if err := w.store.client.Set(w.ctx, w.store.sessionKey(id), w.store.advertise.String(), w.store.sessionTTL).Err(); err != nil {
+				logging.GetLogger().Warn("Failed to register MCP session", "error", err)
+			}
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write writes the header first, so that the session is registered.
+func (w *sessionRecorder) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends the buffered data of the streamed responses.
+func (w *sessionRecorder) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController.
+func (w *sessionRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
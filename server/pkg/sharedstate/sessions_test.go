@@ -0,0 +1,113 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package sharedstate
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/mcpany/core/server/pkg/consts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// replica serves a fake MCP endpoint under the /mcp prefix, behind the
+// session affinity of its store.
+type replica struct {
+	name     string
+	server   *httptest.Server
+	store    *Store
+	sessions atomic.Int32
+}
+
+func newReplica(t *testing.T, mr *miniredis.Miniredis, name string) *replica {
+	t.Helper()
+	r := &replica{name: name}
+	r.server = httptest.NewUnstartedServer(nil)
+	r.store = newTestStore(t, mr, "http://"+r.server.Listener.Addr().String())
+
+	mcpHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get(consts.HeaderMcpSessionID) == "" {
+			w.Header().Set(consts.HeaderMcpSessionID, fmt.Sprintf("%s-%d", name, r.sessions.Add(1)))
+		}
+		_, _ = io.WriteString(w, name+" "+req.URL.Path)
+	})
+	mux := http.NewServeMux()
+	mux.Handle("/mcp/", http.StripPrefix("/mcp", r.store.SessionAffinity(mcpHandler)))
+	r.server.Config.Handler = mux
+	r.server.Start()
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func (r *replica) do(t *testing.T, method, session string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, r.server.URL+"/mcp/stream", strings.NewReader("{}"))
+	require.NoError(t, err)
+	if session != "" {
+		req.Header.Set(consts.HeaderMcpSessionID, session)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestSessionAffinity(t *testing.T) {
+	mr := miniredis.RunT(t)
+	a := newReplica(t, mr, "a")
+	b := newReplica(t, mr, "b")
+
+	resp, body := a.do(t, http.MethodPost, "")
+	session := resp.Header.Get(consts.HeaderMcpSessionID)
+	require.Equal(t, "a-1", session)
+	assert.Equal(t, "a /stream", body)
+	owner, err := mr.Get("mcpany:sessions:a-1")
+	require.NoError(t, err)
+	assert.Equal(t, a.store.advertise.String(), owner)
+
+	t.Run("forwarded to the owner", func(t *testing.T) {
+		_, body := b.do(t, http.MethodPost, session)
+		assert.Equal(t, "a /stream", body, "the path of the client is kept")
+	})
+
+	t.Run("served by the owner", func(t *testing.T) {
+		_, body := a.do(t, http.MethodPost, session)
+		assert.Equal(t, "a /stream", body)
+	})
+
+	t.Run("unknown sessions are served locally", func(t *testing.T) {
+		_, body := b.do(t, http.MethodPost, "unknown")
+		assert.Equal(t, "b /stream", body)
+	})
+
+	t.Run("delete unregisters", func(t *testing.T) {
+		b.do(t, http.MethodDelete, session)
+		assert.False(t, mr.Exists("mcpany:sessions:a-1"))
+	})
+
+	t.Run("unreachable owner", func(t *testing.T) {
+		resp, _ := a.do(t, http.MethodPost, "")
+		session := resp.Header.Get(consts.HeaderMcpSessionID)
+		a.server.Close()
+
+		resp, _ = b.do(t, http.MethodPost, session)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.False(t, mr.Exists("mcpany:sessions:"+session), "the session is dropped")
+	})
+}
+
+func TestSessionAffinity_NoAdvertiseAddress(t *testing.T) {
+	s := newTestStore(t, miniredis.RunT(t), "")
+	next := http.NotFoundHandler()
+	assert.Equal(t, fmt.Sprint(next), fmt.Sprint(s.SessionAffinity(next)))
+}
@@ -0,0 +1,253 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sharedstate keeps the state that the replicas of the server behind
+// a load balancer must agree on in Redis: the open circuit breakers and the
+// replica owning each MCP session. The rate limits and the response cache
+// keep their entries in the same Redis through its client.
+package sharedstate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultKeyPrefix is the prefix of the Redis keys when key_prefix is not
+	// set.
+	DefaultKeyPrefix = "mcpany:"
+	// DefaultSessionTTL is how long a session stays registered after its
+	// last request when session_ttl is not set.
+	DefaultSessionTTL = time.Hour
+
+	writeTimeout = 5 * time.Second
+)
+
+// Store is the state shared by the replicas. It implements
+// resilience.SharedState.
+type Store struct {
+	client     *redis.Client
+	prefix     string
+	advertise  *url.URL
+	sessionTTL time.Duration
+
+	mu sync.RWMutex
+	// openUntil is the local copy of the open breakers, kept up to date by
+	// the messages of the other replicas.
+	openUntil map[string]time.Time
+
+	pubsub    *redis.PubSub
+	closeOnce sync.Once
+	done      chan struct{}
+	proxies   sync.Map // of owner address to *httputil.ReverseProxy
+}
+
+// breakerMessage announces that a replica opened a circuit breaker.
+type breakerMessage struct {
+	Name string `json:"name"`
+	// Until is the time until which the breaker is open, in Unix
+	// milliseconds.
+	Until int64 `json:"until"`
+}
+
+// New connects to the Redis of the configuration and loads the open circuit
+// breakers.
+//
+// Summary: Connects the replica to the shared state.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the connection.
+//   - config: *configv1.SharedStateConfig. The Redis and the address of the
+//     replica.
+//
+// Returns:
+//   - *Store: The connected store.
+//   - error: An error if the configuration is invalid or Redis cannot be
+//     reached.
+//
+// Side Effects:
+//   - Subscribes to the circuit breaker messages until Close.
+func New(ctx context.Context, config *configv1.SharedStateConfig) (*Store, error) {
+	if config.GetRedis().GetAddress() == "" {
+		return nil, fmt.Errorf("shared state redis address is missing")
+	}
+	var advertise *url.URL
+	if addr := config.GetAdvertiseAddress(); addr != "" {
+		u, err := url.Parse(addr)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid advertise address %q: must be an http or https URL", addr)
+		}
+		advertise = u
+	}
+	prefix := config.GetKeyPrefix()
+	if prefix == "" {
+		prefix = DefaultKeyPrefix
+	}
+	sessionTTL := config.GetSessionTtl().AsDuration()
+	if sessionTTL <= 0 {
+		sessionTTL = DefaultSessionTTL
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     config.GetRedis().GetAddress(),
+		Password: config.GetRedis().GetPassword(),
+		DB:       int(config.GetRedis().GetDb()),
+	})
+	s := &Store{
+		client:     client,
+		prefix:     prefix,
+		advertise:  advertise,
+		sessionTTL: sessionTTL,
+		openUntil:  make(map[string]time.Time),
+		done:       make(chan struct{}),
+	}
+
+	// Subscribe before loading, so that no breaker opened in between is
+	// missed.
+	s.pubsub = client.Subscribe(ctx, s.breakersKey())
+	if _, err := s.pubsub.Receive(ctx); err != nil {
+		_ = s.pubsub.Close()
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to connect to shared state redis: %w", err)
+	}
+	if err := s.loadBreakers(ctx); err != nil {
+		_ = s.pubsub.Close()
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to load circuit breakers: %w", err)
+	}
+	go s.listen(s.pubsub.Channel())
+	return s, nil
+}
+
+// Client returns the Redis client, for the stores that keep their own keys
+// in the shared Redis.
+//
+// Returns:
+//   - *redis.Client: The client, owned by the store.
+func (s *Store) Client() *redis.Client {
+	return s.client
+}
+
+// OpenUntil returns the time until which a replica opened the circuit
+// breaker of the name, or the zero time. It does not reach Redis.
+//
+// Parameters:
+//   - name: string. The name of the breaker, usually the ID of the service.
+//
+// Returns:
+//   - time.Time: The end of the opening.
+func (s *Store) OpenUntil(name string) time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.openUntil[name]
+}
+
+// Opened records that this replica opened the circuit breaker of the name,
+// and announces it to the other replicas in the background.
+//
+// Parameters:
+//   - name: string. The name of the breaker.
+//   - until: time.Time. The end of the opening.
+//
+// Side Effects:
+//   - Writes the breaker to Redis and publishes it.
+func (s *Store) Opened(name string, until time.Time) {
+	s.setOpenUntil(name, until)
+
+	data, err := json.Marshal(breakerMessage{Name: name, Until: until.UnixMilli()})
+	if err != nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+		defer cancel()
+		_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, s.breakersKey(), name, until.UnixMilli())
+			pipe.Publish(ctx, s.breakersKey(), data)
+			return nil
+		})
+		if err != nil {
+			logging.GetLogger().Warn("Failed to share circuit breaker state", "breaker", name, "error", err)
+		}
+	}()
+}
+
+// Close stops listening to the other replicas and closes the Redis client.
+//
+// Returns:
+//   - error: An error if the client cannot be closed.
+func (s *Store) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		_ = s.pubsub.Close()
+		err = s.client.Close()
+	})
+	return err
+}
+
+func (s *Store) loadBreakers(ctx context.Context) error {
+	breakers, err := s.client.HGetAll(ctx, s.breakersKey()).Result()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	var expired []string
+	for name, value := range breakers {
+		ms, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		until := time.UnixMilli(ms)
+		if !until.After(now) {
+			expired = append(expired, name)
+			continue
+		}
+		s.setOpenUntil(name, until)
+	}
+	if len(expired) > 0 {
+		return s.client.HDel(ctx, s.breakersKey(), expired...).Err()
+	}
+	return nil
+}
+
+func (s *Store) listen(messages <-chan *redis.Message) {
+	for {
+		select {
+		case <-s.done:
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			var m breakerMessage
+			if err := json.Unmarshal([]byte(msg.Payload), &m); err != nil || m.Name == "" {
+				logging.GetLogger().Warn("Ignoring invalid circuit breaker message", "payload", msg.Payload)
+				continue
+			}
+			s.setOpenUntil(m.Name, time.UnixMilli(m.Until))
+		}
+	}
+}
+
+// setOpenUntil records the opening of a breaker, unless a later one is known.
+func (s *Store) setOpenUntil(name string, until time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if until.After(s.openUntil[name]) {
+		s.openUntil[name] = until
+	}
+}
+
+func (s *Store) breakersKey() string {
+	return s.prefix + "breakers"
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package sharedstate
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/mcpany/core/proto/bus"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func testConfig(addr, advertise string) *configv1.SharedStateConfig {
+	redis := &bus.RedisBus{}
+	redis.SetAddress(addr)
+	return configv1.SharedStateConfig_builder{
+		Redis:            redis,
+		AdvertiseAddress: proto.String(advertise),
+	}.Build()
+}
+
+func newTestStore(t *testing.T, mr *miniredis.Miniredis, advertise string) *Store {
+	t.Helper()
+	s, err := New(context.Background(), testConfig(mr.Addr(), advertise))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	_, err := New(context.Background(), configv1.SharedStateConfig_builder{}.Build())
+	assert.EqualError(t, err, "shared state redis address is missing")
+
+	_, err = New(context.Background(), testConfig("localhost:6379", "10.0.0.5:50050"))
+	assert.EqualError(t, err, `invalid advertise address "10.0.0.5:50050": must be an http or https URL`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = New(ctx, testConfig("127.0.0.1:1", ""))
+	assert.ErrorContains(t, err, "failed to connect to shared state redis")
+}
+
+func TestStore_SharesOpenedBreakers(t *testing.T) {
+	mr := miniredis.RunT(t)
+	a := newTestStore(t, mr, "")
+	b := newTestStore(t, mr, "")
+
+	until := time.Now().Add(time.Minute).Truncate(time.Millisecond)
+	a.Opened("weather", until)
+	assert.Equal(t, until, a.OpenUntil("weather"))
+	assert.Eventually(t, func() bool {
+		return b.OpenUntil("weather").Equal(until)
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// An earlier opening does not shorten a later one.
+	b.setOpenUntil("weather", until.Add(-time.Second))
+	assert.Equal(t, until, b.OpenUntil("weather"))
+	assert.True(t, b.OpenUntil("other").IsZero())
+}
+
+func TestStore_LoadsOpenBreakers(t *testing.T) {
+	mr := miniredis.RunT(t)
+	until := time.Now().Add(time.Minute).Truncate(time.Millisecond)
+	mr.HSet("mcpany:breakers", "weather", strconv.FormatInt(until.UnixMilli(), 10))
+	mr.HSet("mcpany:breakers", "stale", strconv.FormatInt(time.Now().Add(-time.Minute).UnixMilli(), 10))
+
+	s := newTestStore(t, mr, "")
+	assert.Equal(t, until, s.OpenUntil("weather"))
+	assert.True(t, s.OpenUntil("stale").IsZero())
+	names, err := mr.HKeys("mcpany:breakers")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"weather"}, names, "expired breakers are pruned")
+}
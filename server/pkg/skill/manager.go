@@ -10,7 +10,9 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/mcpany/core/server/pkg/logging"
 	"github.com/mcpany/core/server/pkg/validation"
 	"gopkg.in/yaml.v3"
@@ -32,6 +34,8 @@ type Manager struct {
 	rootDir string
 	mu      sync.RWMutex
 	cache   []*Skill
+
+	onChange atomic.Pointer[func()]
 }
 
 // NewManager creates a new Skill Manager. rootDir is the directory where skills are stored.
@@ -57,6 +61,26 @@ func NewManager(rootDir string) (*Manager, error) {
 	}, nil
 }
 
+// OnChange registers a function called after each change to the skills, for
+// instance to refresh the MCP resources serving them.
+//
+// Parameters:
+//   - f (func()): The function, called without the lock of the manager held.
+//     It replaces the function registered before.
+//
+// Side Effects:
+//   - None
+func (m *Manager) OnChange(f func()) {
+	m.onChange.Store(&f)
+}
+
+// changed calls the OnChange function, on the success of a change.
+func (m *Manager) changed(err error) {
+	if f := m.onChange.Load(); f != nil && err == nil {
+		(*f)()
+	}
+}
+
 // ListSkills returns all available skills. It scans the root directory for subdirectories containing SKILL.md.
 //
 // Parameters:
@@ -96,7 +120,8 @@ func (m *Manager) ListSkills() ([]*Skill, error) {
 
 	skills := make([]*Skill, 0, len(entries))
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		// Dot directories hold the packages being installed.
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
 			continue
 		}
 		skill, err := m.loadSkill(entry.Name())
@@ -144,7 +169,8 @@ func (m *Manager) GetSkill(name string) (*Skill, error) {
 //
 // Side Effects:
 //   - None
-func (m *Manager) CreateSkill(skill *Skill) error {
+func (m *Manager) CreateSkill(skill *Skill) (err error) {
+	defer func() { m.changed(err) }()
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -154,6 +180,9 @@ func (m *Manager) CreateSkill(skill *Skill) error {
 	if err := validateName(skill.Name); err != nil {
 		return err
 	}
+	if err := validateVersion(skill.Version); err != nil {
+		return err
+	}
 
 	skillDir := filepath.Join(m.rootDir, skill.Name)
 	if _, err := os.Stat(skillDir); err == nil {
@@ -186,7 +215,8 @@ func (m *Manager) CreateSkill(skill *Skill) error {
 //
 // Side Effects:
 //   - None
-func (m *Manager) UpdateSkill(originalName string, skill *Skill) error {
+func (m *Manager) UpdateSkill(originalName string, skill *Skill) (err error) {
+	defer func() { m.changed(err) }()
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -196,6 +226,9 @@ func (m *Manager) UpdateSkill(originalName string, skill *Skill) error {
 	if err := validateName(skill.Name); err != nil {
 		return err
 	}
+	if err := validateVersion(skill.Version); err != nil {
+		return err
+	}
 
 	originalDir := filepath.Join(m.rootDir, originalName)
 	newDir := filepath.Join(m.rootDir, skill.Name)
@@ -230,7 +263,8 @@ func (m *Manager) UpdateSkill(originalName string, skill *Skill) error {
 //
 // Side Effects:
 //   - None
-func (m *Manager) DeleteSkill(name string) error {
+func (m *Manager) DeleteSkill(name string) (err error) {
+	defer func() { m.changed(err) }()
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -261,7 +295,8 @@ func (m *Manager) DeleteSkill(name string) error {
 //
 // Side Effects:
 //   - None
-func (m *Manager) SaveAsset(skillName string, relPath string, content []byte) error {
+func (m *Manager) SaveAsset(skillName string, relPath string, content []byte) (err error) {
+	defer func() { m.changed(err) }()
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -296,24 +331,13 @@ func (m *Manager) loadSkill(name string) (*Skill, error) {
 		return nil, err
 	}
 
-	var skill Skill
+	skill, err := parseSkillFile(content)
+	if err != nil {
+		return nil, err
+	}
 	skill.Path = skillDir
 	skill.Name = name // Use directory name as source of truth for ID/Name context
 
-	// Parse Frontmatter + Body
-	parts := strings.SplitN(string(content), "---", 3)
-	if len(parts) >= 3 && parts[0] == "" {
-		// Valid frontmatter
-		if err := yaml.Unmarshal([]byte(parts[1]), &skill.Frontmatter); err != nil {
-			return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
-		}
-		skill.Instructions = strings.TrimSpace(parts[2])
-	} else {
-		// No frontmatter? or malformed. Spec requires frontmatter.
-		// We'll treat it as error or just body? Spec says "must contain".
-		return nil, fmt.Errorf("invalid SKILL.md format (missing frontmatter)")
-	}
-
 	// Validate name consistency (optional, but good practice)
 	// if skill.Name != name {
 	// 	// Warn? or Override? Directory name usually rules in filesystem based systems.
@@ -332,6 +356,24 @@ func (m *Manager) loadSkill(name string) (*Skill, error) {
 		return nil
 	})
 
+	return skill, nil
+}
+
+// parseSkillFile parses the frontmatter and instructions of a SKILL.md file.
+func parseSkillFile(content []byte) (*Skill, error) {
+	var skill Skill
+	parts := strings.SplitN(string(content), "---", 3)
+	if len(parts) >= 3 && parts[0] == "" {
+		// Valid frontmatter
+		if err := yaml.Unmarshal([]byte(parts[1]), &skill.Frontmatter); err != nil {
+			return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+		}
+		skill.Instructions = strings.TrimSpace(parts[2])
+	} else {
+		// No frontmatter? or malformed. Spec requires frontmatter.
+		// We'll treat it as error or just body? Spec says "must contain".
+		return nil, fmt.Errorf("invalid SKILL.md format (missing frontmatter)")
+	}
 	return &skill, nil
 }
 
@@ -355,3 +397,15 @@ func validateName(name string) error {
 	}
 	return nil
 }
+
+// validateVersion checks that a version, if set, is a strict semantic version
+// such as "1.2.0".
+func validateVersion(version string) error {
+	if version == "" {
+		return nil
+	}
+	if _, err := semver.StrictNewVersion(version); err != nil {
+		return fmt.Errorf("invalid skill version %q: must be a semantic version such as 1.2.0", version)
+	}
+	return nil
+}
@@ -0,0 +1,526 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package skill
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/mcpany/core/server/pkg/validation"
+)
+
+const (
+	// ManifestFileName is the name of the manifest in a skill package.
+	ManifestFileName = "manifest.json"
+
+	// maxPackageSize bounds both the archive and the files it unpacks to.
+	maxPackageSize = 64 << 20
+)
+
+// ErrNotNewer is returned when installing a package whose version is not
+// newer than the installed one, without forcing it.
+var ErrNotNewer = errors.New("skill package is not newer than the installed skill")
+
+// Manifest describes the content of a skill package. It is the first entry of
+// the package, and lists the checksum of every other entry.
+type Manifest struct {
+	Name    string         `json:"name"`
+	Version string         `json:"version"`
+	Files   []ManifestFile `json:"files"`
+}
+
+// ManifestFile is a file of a skill package.
+type ManifestFile struct {
+	// Path is the path of the file, relative to the skill directory.
+	Path string `json:"path"`
+	// SHA256 is the hex encoded SHA-256 digest of the file.
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// FileName returns the conventional file name of the package.
+//
+// Returns:
+//   - string: "<name>-<version>.tar.gz".
+func (m *Manifest) FileName() string {
+	return fmt.Sprintf("%s-%s.tar.gz", m.Name, m.Version)
+}
+
+// InstallOptions controls the installation of a skill package.
+type InstallOptions struct {
+	// SHA256 is the expected hex encoded SHA-256 digest of the package, or
+	// empty to skip the check.
+	SHA256 string
+	// Force installs the package even if its version is not newer than the
+	// installed skill.
+	Force bool
+}
+
+// packagedFile is a file read from a skill package.
+type packagedFile struct {
+	data       []byte
+	executable bool
+}
+
+// Package writes a skill directory as a gzipped tar package: a manifest.json
+// with the name, version and file checksums of the skill, followed by its
+// files. Dot files and directories, and a manifest.json at the root, are left
+// out.
+//
+// Parameters:
+//   - dir (string): The skill directory, holding a SKILL.md with a name and a
+//     version.
+//   - w (io.Writer): Where the package is written.
+//
+// Returns:
+//   - *Manifest: The manifest of the package.
+//   - error: An error if the skill is invalid or cannot be read.
+//
+// Side Effects:
+//   - Reads the skill directory and writes to w.
+func Package(dir string, w io.Writer) (*Manifest, error) {
+	content, err := os.ReadFile(filepath.Join(dir, SkillFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", SkillFileName, err)
+	}
+	sk, err := parseSkillFile(content)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateName(sk.Name); err != nil {
+		return nil, err
+	}
+	if sk.Version == "" {
+		return nil, fmt.Errorf("skill %s has no version; set version in its %s", sk.Name, SkillFileName)
+	}
+	return PackageDir(dir, sk.Name, sk.Version, w)
+}
+
+// PackageDir writes any directory as a package in the format of Package,
+// under the given name and version. It packages the bundles distributed
+// alongside the skills, such as upstream service definitions.
+//
+// Parameters:
+//   - dir (string): The directory to package.
+//   - name (string): The name of the package, following the skill name rules.
+//   - version (string): The semantic version of the package.
+//   - w (io.Writer): Where the package is written.
+//
+// Returns:
+//   - *Manifest: The manifest of the package.
+//   - error: An error if the name or version is invalid or the directory
+//     cannot be read.
+//
+// Side Effects:
+//   - Reads the directory and writes to w.
+func PackageDir(dir, name, version string, w io.Writer) (*Manifest, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	if version == "" {
+		return nil, fmt.Errorf("package %s has no version", name)
+	}
+	if err := validateVersion(version); err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{Name: name, Version: version}
+	files := make(map[string]packagedFile)
+	var total int64
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == dir {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == ManifestFileName {
+			// The manifest kept by Unpack is rewritten.
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return fmt.Errorf("%s is not a regular file", rel)
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if total += info.Size(); total > maxPackageSize {
+			return fmt.Errorf("%s is larger than %d bytes", name, maxPackageSize)
+		}
+		data, err := os.ReadFile(p) //nolint:gosec // p is under dir.
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		manifest.Files = append(manifest.Files, ManifestFile{Path: rel, SHA256: hex.EncodeToString(sum[:]), Size: int64(len(data))})
+		files[rel] = packagedFile{data: data, executable: info.Mode()&0o111 != 0}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the directory of %s: %w", name, err)
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the manifest: %w", err)
+	}
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	write := func(name string, f packagedFile) error {
+		mode := int64(0o644)
+		if f.executable {
+			mode = 0o755
+		}
+		// A fixed modification time keeps the package of a directory reproducible.
+		hdr := &tar.Header{Name: name, Mode: mode, Size: int64(len(f.data)), ModTime: time.Unix(0, 0), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(f.data)
+		return err
+	}
+	if err := write(ManifestFileName, packagedFile{data: manifestData}); err != nil {
+		return nil, fmt.Errorf("failed to write the package: %w", err)
+	}
+	for _, f := range manifest.Files {
+		if err := write(f.Path, files[f.Path]); err != nil {
+			return nil, fmt.Errorf("failed to write the package: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write the package: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write the package: %w", err)
+	}
+	return manifest, nil
+}
+
+// InstallPackage installs a skill package written by Package, after checking
+// its checksums. The skill replaces the installed skill of the same name,
+// which must have an older version unless the installation is forced.
+//
+// Parameters:
+//   - r (io.Reader): The package.
+//   - opts (InstallOptions): The expected checksum, and whether to force the
+//     installation.
+//
+// Returns:
+//   - *Skill: The installed skill.
+//   - error: ErrNotNewer if the installed skill is not older, or an error if
+//     the package is invalid or cannot be installed.
+//
+// Side Effects:
+//   - Replaces the skill directory atomically.
+func (m *Manager) InstallPackage(r io.Reader, opts InstallOptions) (sk *Skill, err error) {
+	defer func() { m.changed(err) }()
+
+	data, err := io.ReadAll(io.LimitReader(r, maxPackageSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the package: %w", err)
+	}
+	if len(data) > maxPackageSize {
+		return nil, fmt.Errorf("package is larger than %d bytes", maxPackageSize)
+	}
+	if opts.SHA256 != "" {
+		sum := sha256.Sum256(data)
+		if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, opts.SHA256) {
+			return nil, fmt.Errorf("package checksum mismatch: got %s, want %s", got, opts.SHA256)
+		}
+	}
+	manifest, files, err := readPackage(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSkillPackage(manifest, files); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cache = nil
+
+	if installed, err := m.loadSkill(manifest.Name); err == nil && !opts.Force {
+		if err := checkUpgrade(installed, manifest.Version); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := replaceDir(m.rootDir, manifest, files, false); err != nil {
+		return nil, err
+	}
+	return m.loadSkill(manifest.Name)
+}
+
+// Unpack installs a package written by PackageDir as the directory of its
+// name under parentDir, after checking its checksums. The directory is
+// replaced atomically, and keeps the manifest.json of the package to record
+// its version.
+//
+// Parameters:
+//   - data ([]byte): The package.
+//   - parentDir (string): The directory the package is unpacked under.
+//
+// Returns:
+//   - *Manifest: The manifest of the package.
+//   - error: An error if the package is invalid or cannot be unpacked.
+//
+// Side Effects:
+//   - Replaces parentDir/<name>.
+func Unpack(data []byte, parentDir string) (*Manifest, error) {
+	manifest, files, err := readPackage(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(parentDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", parentDir, err)
+	}
+	if err := replaceDir(parentDir, manifest, files, true); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// ReadManifest reads the manifest of a package, after checking the checksums
+// of its files.
+//
+// Parameters:
+//   - data ([]byte): The package.
+//
+// Returns:
+//   - *Manifest: The manifest of the package.
+//   - error: An error if the package is invalid.
+//
+// Side Effects:
+//   - None
+func ReadManifest(data []byte) (*Manifest, error) {
+	manifest, _, err := readPackage(data)
+	return manifest, err
+}
+
+// ReadInstalledManifest reads the manifest kept by Unpack in an unpacked
+// directory.
+//
+// Parameters:
+//   - dir (string): The unpacked directory.
+//
+// Returns:
+//   - *Manifest: The manifest of the package.
+//   - error: An error if the directory has no readable manifest.
+//
+// Side Effects:
+//   - Reads the manifest file.
+func ReadInstalledManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFileName)) //nolint:gosec // dir is chosen by the caller.
+	if err != nil {
+		return nil, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ManifestFileName, err)
+	}
+	return &manifest, nil
+}
+
+// replaceDir unpacks the files of a package next to parentDir/<name>, then
+// swaps them in.
+func replaceDir(parentDir string, manifest *Manifest, files map[string]packagedFile, keepManifest bool) error {
+	staging, err := os.MkdirTemp(parentDir, ".install-"+manifest.Name+"-")
+	if err != nil {
+		return fmt.Errorf("failed to create the staging directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+	unpacked := filepath.Join(staging, manifest.Name)
+	if err := os.MkdirAll(unpacked, 0755); err != nil {
+		return fmt.Errorf("failed to create the staging directory: %w", err)
+	}
+	for _, f := range manifest.Files {
+		file := files[f.Path]
+		dest := filepath.Join(unpacked, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return fmt.Errorf("failed to unpack %s: %w", f.Path, err)
+		}
+		mode := os.FileMode(0644)
+		if file.executable {
+			mode = 0755
+		}
+		if err := os.WriteFile(dest, file.data, mode); err != nil {
+			return fmt.Errorf("failed to unpack %s: %w", f.Path, err)
+		}
+	}
+	if keepManifest {
+		manifestData, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal the manifest: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(unpacked, ManifestFileName), manifestData, 0644); err != nil { //nolint:gosec // The manifest is not secret.
+			return fmt.Errorf("failed to write the manifest: %w", err)
+		}
+	}
+
+	dir := filepath.Join(parentDir, manifest.Name)
+	previous := filepath.Join(staging, "previous")
+	replaced := false
+	if _, err := os.Stat(dir); err == nil {
+		if err := os.Rename(dir, previous); err != nil {
+			return fmt.Errorf("failed to replace %s: %w", manifest.Name, err)
+		}
+		replaced = true
+	}
+	if err := os.Rename(unpacked, dir); err != nil {
+		if replaced {
+			_ = os.Rename(previous, dir)
+		}
+		return fmt.Errorf("failed to install %s: %w", manifest.Name, err)
+	}
+	return nil
+}
+
+// readPackage reads a package and checks it against its manifest.
+func readPackage(data []byte) (*Manifest, map[string]packagedFile, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("package is not a gzipped tar archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+	var manifest *Manifest
+	files := make(map[string]packagedFile)
+	var total int64
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read the package: %w", err)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			continue
+		case tar.TypeReg:
+		default:
+			return nil, nil, fmt.Errorf("package entry %s is not a regular file", hdr.Name)
+		}
+		if err := validation.IsSecureRelativePath(hdr.Name); err != nil {
+			return nil, nil, fmt.Errorf("invalid package entry %s: %w", hdr.Name, err)
+		}
+		name := path.Clean(hdr.Name)
+		if total += hdr.Size; total > maxPackageSize {
+			return nil, nil, fmt.Errorf("package unpacks to more than %d bytes", maxPackageSize)
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read package entry %s: %w", name, err)
+		}
+		if name == ManifestFileName {
+			if err := json.Unmarshal(content, &manifest); err != nil {
+				return nil, nil, fmt.Errorf("invalid %s: %w", ManifestFileName, err)
+			}
+			continue
+		}
+		if _, ok := files[name]; ok {
+			return nil, nil, fmt.Errorf("package entry %s is duplicated", name)
+		}
+		files[name] = packagedFile{data: content, executable: hdr.Mode&0o111 != 0}
+	}
+	if manifest == nil {
+		return nil, nil, fmt.Errorf("package has no %s", ManifestFileName)
+	}
+	if err := validateName(manifest.Name); err != nil {
+		return nil, nil, err
+	}
+	if manifest.Version == "" {
+		return nil, nil, fmt.Errorf("package %s has no version", manifest.Name)
+	}
+	if err := validateVersion(manifest.Version); err != nil {
+		return nil, nil, err
+	}
+
+	listed := make(map[string]bool, len(manifest.Files))
+	for _, f := range manifest.Files {
+		// The files are unpacked by their manifest path.
+		if f.Path != path.Clean(f.Path) {
+			return nil, nil, fmt.Errorf("manifest path %s is not clean", f.Path)
+		}
+		file, ok := files[f.Path]
+		if !ok {
+			return nil, nil, fmt.Errorf("file %s of the manifest is missing from the package", f.Path)
+		}
+		sum := sha256.Sum256(file.data)
+		if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, f.SHA256) {
+			return nil, nil, fmt.Errorf("checksum mismatch for %s: got %s, want %s", f.Path, got, f.SHA256)
+		}
+		listed[f.Path] = true
+	}
+	for name := range files {
+		if !listed[name] {
+			return nil, nil, fmt.Errorf("package entry %s is not in the manifest", name)
+		}
+	}
+
+	return manifest, files, nil
+}
+
+// checkSkillPackage checks that a package holds the SKILL.md of the skill
+// named by its manifest.
+func checkSkillPackage(manifest *Manifest, files map[string]packagedFile) error {
+	skillFile, ok := files[SkillFileName]
+	if !ok {
+		return fmt.Errorf("package has no %s", SkillFileName)
+	}
+	sk, err := parseSkillFile(skillFile.data)
+	if err != nil {
+		return err
+	}
+	if sk.Name != manifest.Name || sk.Version != manifest.Version {
+		return fmt.Errorf("%s is %s %s, but the manifest is %s %s", SkillFileName, sk.Name, sk.Version, manifest.Name, manifest.Version)
+	}
+	return nil
+}
+
+// checkUpgrade checks that a version is newer than the installed skill. A
+// skill without a valid version can always be upgraded.
+func checkUpgrade(installed *Skill, version string) error {
+	current, err := semver.StrictNewVersion(installed.Version)
+	if err != nil {
+		return nil
+	}
+	candidate, err := semver.StrictNewVersion(version)
+	if err != nil {
+		return err
+	}
+	if !candidate.GreaterThan(current) {
+		return fmt.Errorf("%w: %s %s is installed, the package is %s; force the installation to replace it", ErrNotNewer, installed.Name, installed.Version, version)
+	}
+	return nil
+}
@@ -3,14 +3,22 @@
 
 package skill
 
+import "slices"
+
 // Frontmatter represents the YAML frontmatter of a SKILL.md file.
 type Frontmatter struct {
-	Name         string            `yaml:"name" json:"name"`
-	Description  string            `yaml:"description" json:"description"`
-	License      string            `yaml:"license,omitempty" json:"license,omitempty"`
-	Compatibility string           `yaml:"compatibility,omitempty" json:"compatibility,omitempty"`
-	Metadata     map[string]string `yaml:"metadata,omitempty" json:"metadata,omitempty"`
-	AllowedTools []string          `yaml:"allowed-tools,omitempty" json:"allowedTools,omitempty"`
+	Name          string            `yaml:"name" json:"name"`
+	Description   string            `yaml:"description" json:"description"`
+	License       string            `yaml:"license,omitempty" json:"license,omitempty"`
+	Compatibility string            `yaml:"compatibility,omitempty" json:"compatibility,omitempty"`
+	Metadata      map[string]string `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+	AllowedTools  []string          `yaml:"allowed-tools,omitempty" json:"allowedTools,omitempty"`
+	// Version is the semantic version of the skill, e.g. "1.2.0". It is
+	// required to package the skill.
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
+	// Profiles are the profiles the skill is served to over MCP. A skill
+	// without profiles is only served to the sessions without a profile.
+	Profiles []string `yaml:"profiles,omitempty" json:"profiles,omitempty"`
 }
 
 // Skill represents a complete Agent Skill.
@@ -28,3 +36,17 @@ type Skill struct {
 	// This is populated by scanning the directory.
 	Assets []string `json:"assets,omitempty"`
 }
+
+// VisibleTo reports whether the skill is served to the sessions of a profile.
+//
+// Summary: Checks the visibility of the skill for a profile.
+//
+// Parameters:
+//   - profileID (string): The profile of the session, or empty for none.
+//
+// Returns:
+//   - bool: True if the session has no profile or the profile is listed in
+//     Profiles.
+func (s *Skill) VisibleTo(profileID string) bool {
+	return profileID == "" || slices.Contains(s.Profiles, profileID)
+}
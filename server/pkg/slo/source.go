@@ -0,0 +1,131 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package slo
+
+import (
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	metricToolsCallLatency = "mcpany_tools_call_latency_seconds"
+	labelServiceID         = "service_id"
+	labelStatus            = "status"
+	statusSuccess          = "success"
+)
+
+// Counts are the cumulative tool call counts of a service.
+type Counts struct {
+	// Total is the number of calls.
+	Total float64
+	// Errors is the number of failed calls.
+	Errors float64
+	// Latency is the cumulative latency histogram of the successful calls,
+	// sorted by upper bound.
+	Latency []Bucket
+}
+
+// Bucket is a cumulative histogram bucket.
+type Bucket struct {
+	// UpperBound is the inclusive upper bound in seconds.
+	UpperBound float64
+	// Count is the number of observations at or below UpperBound.
+	Count float64
+}
+
+// Source returns the cumulative tool call counts per service ID.
+type Source func() (map[string]Counts, error)
+
+// PrometheusSource reads the tool call latency histogram recorded by the tool
+// metrics middleware.
+//
+// Summary: Creates a Source backed by the server's own Prometheus metrics.
+//
+// Parameters:
+//   - g: prometheus.Gatherer. The gatherer to read, usually prometheus.DefaultGatherer.
+//
+// Returns:
+//   - Source: The source.
+func PrometheusSource(g prometheus.Gatherer) Source {
+	return func() (map[string]Counts, error) {
+		families, err := g.Gather()
+		if err != nil {
+			return nil, err
+		}
+		counts := make(map[string]Counts)
+		for _, mf := range families {
+			if mf.GetName() != metricToolsCallLatency {
+				continue
+			}
+			for _, m := range mf.GetMetric() {
+				var serviceID, status string
+				for _, l := range m.GetLabel() {
+					switch l.GetName() {
+					case labelServiceID:
+						serviceID = l.GetValue()
+					case labelStatus:
+						status = l.GetValue()
+					}
+				}
+				h := m.GetHistogram()
+				if serviceID == "" || h == nil {
+					continue
+				}
+				c := counts[serviceID]
+				n := float64(h.GetSampleCount())
+				c.Total += n
+				if status != statusSuccess {
+					c.Errors += n
+				} else {
+					c.Latency = mergeBuckets(c.Latency, h.GetBucket())
+				}
+				counts[serviceID] = c
+			}
+		}
+		return counts, nil
+	}
+}
+
+// promBucket is the part of a Prometheus histogram bucket that is read.
+type promBucket interface {
+	GetUpperBound() float64
+	GetCumulativeCount() uint64
+}
+
+func mergeBuckets[B promBucket](acc []Bucket, buckets []B) []Bucket {
+	for _, b := range buckets {
+		i := sort.Search(len(acc), func(i int) bool { return acc[i].UpperBound >= b.GetUpperBound() })
+		if i < len(acc) && acc[i].UpperBound == b.GetUpperBound() {
+			acc[i].Count += float64(b.GetCumulativeCount())
+			continue
+		}
+		acc = append(acc, Bucket{})
+		copy(acc[i+1:], acc[i:])
+		acc[i] = Bucket{UpperBound: b.GetUpperBound(), Count: float64(b.GetCumulativeCount())}
+	}
+	return acc
+}
+
+// good returns the number of good calls. Without a latency threshold every
+// successful call is good; otherwise the number of successful calls within the
+// threshold is interpolated from the histogram.
+func (c Counts) good(latencyThreshold float64) float64 {
+	success := c.Total - c.Errors
+	if latencyThreshold <= 0 {
+		return success
+	}
+	var prevBound, prevCount float64
+	for _, b := range c.Latency {
+		if latencyThreshold <= b.UpperBound {
+			if b.UpperBound == prevBound {
+				return b.Count
+			}
+			return prevCount + (b.Count-prevCount)*(latencyThreshold-prevBound)/(b.UpperBound-prevBound)
+		}
+		prevBound, prevCount = b.UpperBound, b.Count
+	}
+	// Calls above the largest finite bucket are counted as slow.
+	return prevCount
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package slo
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusSource(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	hist := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    metricToolsCallLatency,
+		Buckets: []float64{0.1, 1},
+	}, []string{"tool", labelServiceID, labelStatus, "error_type"})
+	reg.MustRegister(hist)
+
+	hist.WithLabelValues("a", "billing", "success", "").Observe(0.05)
+	hist.WithLabelValues("b", "billing", "success", "").Observe(0.5)
+	hist.WithLabelValues("b", "billing", "success", "").Observe(5)
+	hist.WithLabelValues("a", "billing", "error", "timeout").Observe(0.05)
+	hist.WithLabelValues("a", "search", "success", "").Observe(0.05)
+
+	counts, err := PrometheusSource(reg)()
+	require.NoError(t, err)
+	require.Len(t, counts, 2)
+
+	billing := counts["billing"]
+	assert.Equal(t, float64(4), billing.Total)
+	assert.Equal(t, float64(1), billing.Errors)
+	assert.Equal(t, []Bucket{{UpperBound: 0.1, Count: 1}, {UpperBound: 1, Count: 2}}, billing.Latency)
+	assert.Equal(t, float64(1), counts["search"].Total)
+}
+
+func TestCounts_Good(t *testing.T) {
+	c := Counts{
+		Total:  10,
+		Errors: 2,
+		Latency: []Bucket{
+			{UpperBound: 0.1, Count: 4},
+			{UpperBound: 1, Count: 7},
+		},
+	}
+	assert.Equal(t, float64(8), c.good(0))
+	assert.Equal(t, float64(4), c.good(0.1))
+	assert.InDelta(t, 5.5, c.good(0.55), 1e-9)
+	assert.InDelta(t, 2, c.good(0.05), 1e-9)
+	assert.Equal(t, float64(7), c.good(10), "calls above the largest bucket are slow")
+}
@@ -0,0 +1,420 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package slo tracks the service level objectives of upstream services from
+// the server's own tool call metrics.
+package slo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/alerts"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultWindow is the compliance window of objectives that do not set one.
+const DefaultWindow = 30 * 24 * time.Hour
+
+const (
+	defaultSampleInterval = time.Minute
+	alertSource           = "SLO Monitor"
+)
+
+// burnRateAlert fires when the error budget burns faster than threshold over
+// both the long and the short window, following the multiwindow, multi-burn-rate
+// alerts of the Google SRE workbook. The short window resolves the alert
+// quickly once the burn stops.
+type burnRateAlert struct {
+	severity  alerts.Severity
+	long      time.Duration
+	short     time.Duration
+	threshold float64
+}
+
+var burnRateAlerts = []burnRateAlert{
+	{severity: alerts.SeverityCritical, long: time.Hour, short: 5 * time.Minute, threshold: 14.4},
+	{severity: alerts.SeverityWarning, long: 6 * time.Hour, short: 30 * time.Minute, threshold: 6},
+}
+
+// Status is the compliance of a service level objective.
+type Status struct {
+	Service              string          `json:"service"`
+	Name                 string          `json:"name"`
+	Target               float64         `json:"target"`
+	Window               string          `json:"window"`
+	Compliance           float64         `json:"compliance"`
+	ErrorBudgetRemaining float64         `json:"errorBudgetRemaining"`
+	BurnRate1h           float64         `json:"burnRate1h"`
+	BurnRate6h           float64         `json:"burnRate6h"`
+	TotalCalls           int64           `json:"totalCalls"`
+	BadCalls             int64           `json:"badCalls"`
+	Alert                alerts.Severity `json:"alert,omitempty"`
+}
+
+// Tracker samples the tool call counts of every service with objectives and
+// tracks their compliance, error budget and burn rate over rolling windows.
+type Tracker struct {
+	source   Source
+	alerts   alerts.ManagerInterface
+	interval time.Duration
+	now      func() time.Time
+
+	mu         sync.Mutex
+	objectives map[string]*objective
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+// Option configures a Tracker.
+type Option func(*Tracker)
+
+// WithSource sets the source of the tool call counts.
+//
+// Parameters:
+//   - s: Source. The source; PrometheusSource(prometheus.DefaultGatherer) by default.
+//
+// Returns:
+//   - Option: The option.
+func WithSource(s Source) Option {
+	return func(t *Tracker) { t.source = s }
+}
+
+// WithSampleInterval sets how often the counts are sampled.
+//
+// Parameters:
+//   - d: time.Duration. The interval; one minute by default.
+//
+// Returns:
+//   - Option: The option.
+func WithSampleInterval(d time.Duration) Option {
+	return func(t *Tracker) { t.interval = d }
+}
+
+// WithClock sets the clock of the tracker.
+//
+// Parameters:
+//   - now: func() time.Time. The clock; time.Now by default.
+//
+// Returns:
+//   - Option: The option.
+func WithClock(now func() time.Time) Option {
+	return func(t *Tracker) { t.now = now }
+}
+
+// objective is a service level objective and its samples.
+type objective struct {
+	serviceID string
+	service   string
+	cfg       *configv1.ServiceLevelObjective
+	window    time.Duration
+	latency   float64
+
+	// samples are cumulative good and total counts, oldest first.
+	samples []sample
+	// activeAlerts maps the severity of firing alerts to the alert ID.
+	activeAlerts map[alerts.Severity]string
+}
+
+type sample struct {
+	at    time.Time
+	good  float64
+	total float64
+}
+
+// NewTracker creates a Tracker.
+//
+// Summary: Initializes SLO tracking.
+//
+// Parameters:
+//   - alertsManager: alerts.ManagerInterface. Receives burn-rate alerts; nil disables alerting.
+//   - opts: ...Option. The options.
+//
+// Returns:
+//   - *Tracker: The tracker.
+func NewTracker(alertsManager alerts.ManagerInterface, opts ...Option) *Tracker {
+	t := &Tracker{
+		source:     PrometheusSource(prometheus.DefaultGatherer),
+		alerts:     alertsManager,
+		interval:   defaultSampleInterval,
+		now:        time.Now,
+		objectives: make(map[string]*objective),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// SetObjectives replaces the tracked objectives with those of the services.
+//
+// Summary: Applies the SLO configuration of the upstream services.
+//
+// Parameters:
+//   - services: []*configv1.UpstreamServiceConfig. The configured services.
+//
+// Side Effects:
+//   - Keeps the samples of objectives whose service, name and latency threshold are unchanged.
+//   - Resolves the alerts of removed objectives.
+func (t *Tracker) SetObjectives(services []*configv1.UpstreamServiceConfig) {
+	next := make(map[string]*objective)
+	for _, svc := range services {
+		if svc.GetDisable() || len(svc.GetSlos()) == 0 {
+			continue
+		}
+		serviceID, err := util.SanitizeServiceName(svc.GetName())
+		if err != nil {
+			logging.GetLogger().Warn("Skipping SLOs of service with invalid name", "service", svc.GetName(), "error", err)
+			continue
+		}
+		for _, cfg := range svc.GetSlos() {
+			window := DefaultWindow
+			if cfg.HasWindow() && cfg.GetWindow().AsDuration() > 0 {
+				window = cfg.GetWindow().AsDuration()
+			}
+			next[serviceID+"/"+cfg.GetName()] = &objective{
+				serviceID:    serviceID,
+				service:      svc.GetName(),
+				cfg:          cfg,
+				window:       window,
+				latency:      cfg.GetLatencyThreshold().AsDuration().Seconds(),
+				activeAlerts: make(map[alerts.Severity]string),
+			}
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key, old := range t.objectives {
+		obj, ok := next[key]
+		if ok && obj.latency == old.latency {
+			obj.samples = old.samples
+			obj.activeAlerts = old.activeAlerts
+			continue
+		}
+		for severity := range old.activeAlerts {
+			t.resolveAlert(old, severity)
+		}
+	}
+	t.objectives = next
+}
+
+// Start samples the counts until Stop is called.
+//
+// Summary: Starts background sampling.
+//
+// Parameters:
+//   - ctx: context.Context. Sampling also stops when it is cancelled.
+//
+// Returns:
+//   - error: Always nil.
+//
+// Side Effects:
+//   - Starts a goroutine.
+func (t *Tracker) Start(ctx context.Context) error {
+	t.mu.Lock()
+	if t.cancel != nil {
+		t.mu.Unlock()
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	t.cancel = cancel
+	t.done = make(chan struct{})
+	done := t.done
+	t.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+		t.Sample()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				t.Sample()
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops background sampling.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the wait for the sampling goroutine.
+//
+// Returns:
+//   - error: The context error if the goroutine did not stop in time.
+func (t *Tracker) Stop(ctx context.Context) error {
+	t.mu.Lock()
+	cancel, done := t.cancel, t.done
+	t.cancel, t.done = nil, nil
+	t.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Sample records the current counts of every objective and evaluates the
+// burn-rate alerts.
+//
+// Side Effects:
+//   - Creates and resolves alerts.
+func (t *Tracker) Sample() {
+	counts, err := t.source()
+	if err != nil {
+		logging.GetLogger().Warn("Failed to read tool call metrics for SLOs", "error", err)
+		return
+	}
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, obj := range t.objectives {
+		c := counts[obj.serviceID]
+		obj.samples = append(obj.samples, sample{at: now, good: c.good(obj.latency), total: c.Total})
+		// Keep one sample at or before the start of the window as its base.
+		cutoff := now.Add(-obj.window)
+		drop := 0
+		for drop+1 < len(obj.samples) && !obj.samples[drop+1].at.After(cutoff) {
+			drop++
+		}
+		obj.samples = obj.samples[drop:]
+
+		if t.alerts != nil && !obj.cfg.GetDisableAlerts() {
+			t.evaluateAlerts(obj, now)
+		}
+	}
+}
+
+// Status returns the status of every objective, sorted by service and name.
+//
+// Returns:
+//   - []Status: The statuses.
+func (t *Tracker) Status() []Status {
+	now := t.now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	statuses := make([]Status, 0, len(t.objectives))
+	for _, obj := range t.objectives {
+		good, total := obj.delta(now, obj.window)
+		st := Status{
+			Service:              obj.service,
+			Name:                 obj.cfg.GetName(),
+			Target:               obj.cfg.GetTarget(),
+			Window:               obj.window.String(),
+			Compliance:           100,
+			ErrorBudgetRemaining: 1,
+			BurnRate1h:           obj.burnRate(now, time.Hour),
+			BurnRate6h:           obj.burnRate(now, 6*time.Hour),
+			TotalCalls:           int64(total),
+			BadCalls:             int64(total - good),
+		}
+		if total > 0 {
+			st.Compliance = good / total * 100
+			st.ErrorBudgetRemaining = 1 - (total-good)/total/obj.errorBudget()
+		}
+		for _, a := range burnRateAlerts {
+			if _, ok := obj.activeAlerts[a.severity]; ok {
+				st.Alert = a.severity
+				break
+			}
+		}
+		statuses = append(statuses, st)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Service != statuses[j].Service {
+			return statuses[i].Service < statuses[j].Service
+		}
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+func (t *Tracker) evaluateAlerts(obj *objective, now time.Time) {
+	for _, a := range burnRateAlerts {
+		longRate := obj.burnRate(now, a.long)
+		firing := longRate > a.threshold && obj.burnRate(now, a.short) > a.threshold
+		_, active := obj.activeAlerts[a.severity]
+		switch {
+		case firing && !active:
+			good, total := obj.delta(now, obj.window)
+			remaining := 100.0
+			if total > 0 {
+				remaining = (1 - (total-good)/total/obj.errorBudget()) * 100
+			}
+			created := t.alerts.CreateAlert(&alerts.Alert{
+				Title:    fmt.Sprintf("SLO %q is burning its error budget", obj.cfg.GetName()),
+				Message:  fmt.Sprintf("Error budget burn rate is %.1fx over the last %s (threshold %.1fx). %.1f%% of the budget remains.", longRate, a.long, a.threshold, remaining),
+				Severity: a.severity,
+				Status:   alerts.StatusActive,
+				Service:  obj.service,
+				Source:   alertSource,
+			})
+			obj.activeAlerts[a.severity] = created.ID
+		case !firing && active:
+			t.resolveAlert(obj, a.severity)
+		}
+	}
+}
+
+func (t *Tracker) resolveAlert(obj *objective, severity alerts.Severity) {
+	if t.alerts != nil {
+		t.alerts.UpdateAlert(obj.activeAlerts[severity], &alerts.Alert{Status: alerts.StatusResolved})
+	}
+	delete(obj.activeAlerts, severity)
+}
+
+// errorBudget is the allowed fraction of bad calls.
+func (o *objective) errorBudget() float64 {
+	return 1 - o.cfg.GetTarget()/100
+}
+
+// burnRate is the rate at which the error budget is spent over the last d,
+// where 1 spends exactly the budget over the window.
+func (o *objective) burnRate(now time.Time, d time.Duration) float64 {
+	good, total := o.delta(now, d)
+	if total <= 0 {
+		return 0
+	}
+	return (total - good) / total / o.errorBudget()
+}
+
+// delta returns the good and total calls over the last d. Before enough
+// samples exist it covers the time since the first sample.
+func (o *objective) delta(now time.Time, d time.Duration) (float64, float64) {
+	if len(o.samples) < 2 {
+		return 0, 0
+	}
+	latest := o.samples[len(o.samples)-1]
+	cutoff := now.Add(-d)
+	base := o.samples[0]
+	for _, s := range o.samples[1:] {
+		if s.at.After(cutoff) {
+			break
+		}
+		base = s
+	}
+	total := latest.total - base.total
+	good := latest.good - base.good
+	if total <= 0 {
+		return 0, 0
+	}
+	return min(max(good, 0), total), total
+}
@@ -0,0 +1,269 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package slo
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/alerts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// fakeSource serves counts that the test advances.
+type fakeSource struct {
+	mu     sync.Mutex
+	counts map[string]Counts
+}
+
+func (f *fakeSource) add(serviceID string, total, errors float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c := f.counts[serviceID]
+	c.Total += total
+	c.Errors += errors
+	f.counts[serviceID] = c
+}
+
+func (f *fakeSource) source() (map[string]Counts, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make(map[string]Counts, len(f.counts))
+	for k, v := range f.counts {
+		out[k] = v
+	}
+	return out, nil
+}
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func service(name string, slos ...*configv1.ServiceLevelObjective) *configv1.UpstreamServiceConfig {
+	return configv1.UpstreamServiceConfig_builder{
+		Name: proto.String(name),
+		Slos: slos,
+	}.Build()
+}
+
+func availability(target float64) *configv1.ServiceLevelObjective {
+	return configv1.ServiceLevelObjective_builder{
+		Name:   proto.String("availability"),
+		Target: proto.Float64(target),
+		Window: durationpb.New(24 * time.Hour),
+	}.Build()
+}
+
+func newTestTracker(t *testing.T) (*Tracker, *fakeSource, *fakeClock, *alerts.Manager) {
+	t.Helper()
+	src := &fakeSource{counts: make(map[string]Counts)}
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	am := alerts.NewManager()
+	return NewTracker(am, WithSource(src.source), WithClock(clock.Now)), src, clock, am
+}
+
+func sloAlerts(am *alerts.Manager, status alerts.Status) []*alerts.Alert {
+	var out []*alerts.Alert
+	for _, a := range am.ListAlerts() {
+		if a.Source == alertSource && a.Status == status {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+func TestTracker_Compliance(t *testing.T) {
+	tr, src, clock, _ := newTestTracker(t)
+	tr.SetObjectives([]*configv1.UpstreamServiceConfig{service("billing", availability(99))})
+
+	tr.Sample()
+	st := tr.Status()
+	require.Len(t, st, 1)
+	assert.Equal(t, float64(100), st[0].Compliance, "no calls yet")
+	assert.Equal(t, float64(1), st[0].ErrorBudgetRemaining)
+
+	src.add("billing", 1000, 5)
+	clock.Advance(time.Minute)
+	tr.Sample()
+
+	st = tr.Status()
+	require.Len(t, st, 1)
+	assert.Equal(t, "billing", st[0].Service)
+	assert.Equal(t, "availability", st[0].Name)
+	assert.Equal(t, "24h0m0s", st[0].Window)
+	assert.Equal(t, int64(1000), st[0].TotalCalls)
+	assert.Equal(t, int64(5), st[0].BadCalls)
+	assert.InDelta(t, 99.5, st[0].Compliance, 1e-9)
+	assert.InDelta(t, 0.5, st[0].ErrorBudgetRemaining, 1e-9)
+	assert.InDelta(t, 0.5, st[0].BurnRate1h, 1e-9)
+}
+
+func TestTracker_WindowRollsOver(t *testing.T) {
+	tr, src, clock, _ := newTestTracker(t)
+	tr.SetObjectives([]*configv1.UpstreamServiceConfig{service("billing", availability(99))})
+
+	tr.Sample()
+	src.add("billing", 100, 50)
+	clock.Advance(time.Hour)
+	tr.Sample()
+	// A day of clean traffic pushes the bad hour out of the window.
+	for i := 0; i < 25; i++ {
+		src.add("billing", 100, 0)
+		clock.Advance(time.Hour)
+		tr.Sample()
+	}
+
+	st := tr.Status()
+	require.Len(t, st, 1)
+	assert.Equal(t, int64(2400), st[0].TotalCalls)
+	assert.Equal(t, int64(0), st[0].BadCalls)
+	assert.Equal(t, float64(100), st[0].Compliance)
+}
+
+func TestTracker_LatencyThreshold(t *testing.T) {
+	tr, src, clock, _ := newTestTracker(t)
+	tr.SetObjectives([]*configv1.UpstreamServiceConfig{service("search", configv1.ServiceLevelObjective_builder{
+		Name:             proto.String("latency"),
+		Target:           proto.Float64(90),
+		LatencyThreshold: durationpb.New(500 * time.Millisecond),
+	}.Build())})
+
+	tr.Sample()
+	src.mu.Lock()
+	src.counts["search"] = Counts{
+		Total:  100,
+		Errors: 10,
+		Latency: []Bucket{
+			{UpperBound: 0.25, Count: 40},
+			{UpperBound: 1, Count: 80},
+		},
+	}
+	src.mu.Unlock()
+	clock.Advance(time.Minute)
+	tr.Sample()
+
+	st := tr.Status()
+	require.Len(t, st, 1)
+	// 40 calls within 250ms plus a third of the 40 between 250ms and 1s.
+	assert.InDelta(t, 100-(40+40.0/3), float64(st[0].BadCalls), 1)
+	assert.Equal(t, DefaultWindow.String(), st[0].Window)
+}
+
+func TestTracker_BurnRateAlerts(t *testing.T) {
+	tr, src, clock, am := newTestTracker(t)
+	tr.SetObjectives([]*configv1.UpstreamServiceConfig{service("billing", availability(99.9))})
+
+	tr.Sample()
+	// A quarter of the calls fail: a burn rate of 250.
+	for i := 0; i < 10; i++ {
+		src.add("billing", 100, 25)
+		clock.Advance(time.Minute)
+		tr.Sample()
+	}
+
+	active := sloAlerts(am, alerts.StatusActive)
+	require.Len(t, active, 2)
+	for _, a := range active {
+		assert.Equal(t, "billing", a.Service)
+		assert.Contains(t, a.Title, "availability")
+	}
+	st := tr.Status()
+	require.Len(t, st, 1)
+	assert.Equal(t, alerts.SeverityCritical, st[0].Alert)
+	assert.Less(t, st[0].ErrorBudgetRemaining, float64(0))
+
+	// The alerts stay open, not duplicated, while the burn continues.
+	src.add("billing", 100, 25)
+	clock.Advance(time.Minute)
+	tr.Sample()
+	assert.Len(t, sloAlerts(am, alerts.StatusActive), 2)
+
+	// The critical alert resolves once the short window is clean; the warning
+	// alert once its own short window is.
+	for i := 0; i < 6; i++ {
+		src.add("billing", 100, 0)
+		clock.Advance(time.Minute)
+		tr.Sample()
+	}
+	active = sloAlerts(am, alerts.StatusActive)
+	require.Len(t, active, 1)
+	assert.Equal(t, alerts.SeverityWarning, active[0].Severity)
+
+	for i := 0; i < 30; i++ {
+		src.add("billing", 100, 0)
+		clock.Advance(time.Minute)
+		tr.Sample()
+	}
+	assert.Empty(t, sloAlerts(am, alerts.StatusActive))
+	assert.Len(t, sloAlerts(am, alerts.StatusResolved), 2)
+}
+
+func TestTracker_DisableAlerts(t *testing.T) {
+	tr, src, clock, am := newTestTracker(t)
+	obj := availability(99.9)
+	obj.SetDisableAlerts(true)
+	tr.SetObjectives([]*configv1.UpstreamServiceConfig{service("billing", obj)})
+
+	tr.Sample()
+	src.add("billing", 100, 100)
+	clock.Advance(time.Minute)
+	tr.Sample()
+
+	assert.Empty(t, sloAlerts(am, alerts.StatusActive))
+	assert.InDelta(t, 1000, tr.Status()[0].BurnRate1h, 1e-9)
+}
+
+func TestTracker_SetObjectives(t *testing.T) {
+	tr, src, clock, am := newTestTracker(t)
+	disabled := service("legacy", availability(99))
+	disabled.SetDisable(true)
+	tr.SetObjectives([]*configv1.UpstreamServiceConfig{
+		service("billing", availability(99.9)),
+		service("search"),
+		disabled,
+	})
+	require.Len(t, tr.Status(), 1)
+
+	tr.Sample()
+	src.add("billing", 100, 100)
+	clock.Advance(time.Minute)
+	tr.Sample()
+	require.Len(t, sloAlerts(am, alerts.StatusActive), 2)
+
+	// An unchanged objective keeps its samples across a reload.
+	tr.SetObjectives([]*configv1.UpstreamServiceConfig{service("billing", availability(99.9))})
+	assert.Equal(t, int64(100), tr.Status()[0].TotalCalls)
+
+	// A removed objective resolves its alerts.
+	tr.SetObjectives(nil)
+	assert.Empty(t, tr.Status())
+	assert.Empty(t, sloAlerts(am, alerts.StatusActive))
+}
+
+func TestTracker_StartStop(t *testing.T) {
+	src := &fakeSource{counts: make(map[string]Counts)}
+	tr := NewTracker(nil, WithSource(src.source), WithSampleInterval(time.Millisecond))
+	tr.SetObjectives([]*configv1.UpstreamServiceConfig{service("billing", availability(99))})
+
+	require.NoError(t, tr.Start(context.Background()))
+	require.NoError(t, tr.Start(context.Background()), "starting twice is a no-op")
+	// Calls before the first sample are not attributed to the window, so keep
+	// calling until the samples pick them up.
+	assert.Eventually(t, func() bool {
+		src.add("billing", 10, 1)
+		return tr.Status()[0].TotalCalls > 0
+	}, time.Second, 5*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, tr.Stop(ctx))
+	require.NoError(t, tr.Stop(ctx), "stopping twice is a no-op")
+}
@@ -0,0 +1,254 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package slowcall keeps the tool calls slower than their threshold in a
+// rolling report of the slowest calls.
+package slowcall
+
+import (
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+)
+
+const (
+	// DefaultTopN is the number of calls in the report when none is configured.
+	DefaultTopN = 20
+	// DefaultWindow is how long a slow call stays in the report when no
+	// window is configured.
+	DefaultWindow = time.Hour
+
+	// maxCalls bounds the slow calls kept in the window. Beyond it the
+	// fastest ones are dropped, so the per-tool counts become lower bounds.
+	maxCalls = 1000
+)
+
+// Call is a tool call slower than its threshold.
+type Call struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Tool        string    `json:"tool"`
+	ServiceID   string    `json:"serviceId,omitempty"`
+	TraceID     string    `json:"traceId,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	DurationMs  int64     `json:"durationMs"`
+	ThresholdMs int64     `json:"thresholdMs"`
+	QueueMs     int64     `json:"queueMs"`
+	WebhookMs   int64     `json:"webhookMs"`
+	UpstreamMs  int64     `json:"upstreamMs"`
+	TransformMs int64     `json:"transformMs"`
+}
+
+// ToolSummary aggregates the slow calls of a tool in the window.
+type ToolSummary struct {
+	Tool  string `json:"tool"`
+	Count int    `json:"count"`
+	MaxMs int64  `json:"maxMs"`
+	AvgMs int64  `json:"avgMs"`
+}
+
+// Report is the rolling report of the slow calls.
+type Report struct {
+	Window string        `json:"window"`
+	Calls  []Call        `json:"calls"`
+	Tools  []ToolSummary `json:"tools"`
+}
+
+type toolThreshold struct {
+	pattern   string
+	threshold time.Duration
+}
+
+// Recorder decides which tool calls are slow and keeps them over a rolling
+// window.
+type Recorder struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	threshold time.Duration
+	exact     map[string]time.Duration
+	patterns  []toolThreshold
+	topN      int
+	window    time.Duration
+	calls     []Call
+}
+
+// NewRecorder creates a Recorder.
+//
+// Parameters:
+//   - config: *configv1.SlowCallConfig. The slow call configuration, or nil.
+//
+// Returns:
+//   - *Recorder: The recorder.
+func NewRecorder(config *configv1.SlowCallConfig) *Recorder {
+	r := &Recorder{now: time.Now}
+	r.SetConfig(config)
+	return r
+}
+
+// SetConfig replaces the thresholds, the report size and the window. The
+// slow calls already recorded are kept.
+//
+// Parameters:
+//   - config: *configv1.SlowCallConfig. The slow call configuration, or nil.
+//
+// Side Effects:
+//   - Updates the recorder.
+func (r *Recorder) SetConfig(config *configv1.SlowCallConfig) {
+	exact := make(map[string]time.Duration)
+	var patterns []toolThreshold
+	for name, d := range config.GetToolThresholds() {
+		if isPattern(name) {
+			patterns = append(patterns, toolThreshold{pattern: name, threshold: d.AsDuration()})
+		} else {
+			exact[name] = d.AsDuration()
+		}
+	}
+	// The longest, i.e. most specific, matching pattern wins.
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i].pattern) != len(patterns[j].pattern) {
+			return len(patterns[i].pattern) > len(patterns[j].pattern)
+		}
+		return patterns[i].pattern < patterns[j].pattern
+	})
+
+	topN := int(config.GetTopN())
+	if topN <= 0 {
+		topN = DefaultTopN
+	}
+	window := DefaultWindow
+	if config.GetWindow() != nil && config.GetWindow().AsDuration() > 0 {
+		window = config.GetWindow().AsDuration()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.threshold = config.GetThreshold().AsDuration()
+	r.exact = exact
+	r.patterns = patterns
+	r.topN = topN
+	r.window = window
+}
+
+// Threshold returns the threshold of a tool.
+//
+// Parameters:
+//   - toolName: string. The name of the tool.
+//
+// Returns:
+//   - time.Duration: The threshold, or 0 if the calls of the tool are not tracked.
+func (r *Recorder) Threshold(toolName string) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if d, ok := r.exact[toolName]; ok {
+		return d
+	}
+	for _, p := range r.patterns {
+		if ok, _ := path.Match(p.pattern, toolName); ok {
+			return p.threshold
+		}
+	}
+	return r.threshold
+}
+
+// Record adds a slow call to the report.
+//
+// Parameters:
+//   - call: Call. The slow call.
+//
+// Side Effects:
+//   - Drops the calls older than the window and, beyond the capacity of the
+//     recorder, the fastest calls.
+func (r *Recorder) Record(call Call) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pruneLocked()
+	r.calls = append(r.calls, call)
+	if len(r.calls) > maxCalls {
+		fastest := 0
+		for i, c := range r.calls {
+			if c.DurationMs < r.calls[fastest].DurationMs {
+				fastest = i
+			}
+		}
+		r.calls = append(r.calls[:fastest], r.calls[fastest+1:]...)
+	}
+}
+
+// Report returns the slowest calls of the window, slowest first, and the
+// slow calls of each tool, most frequent first.
+//
+// Parameters:
+//   - limit: int. The number of calls to return, or 0 for the configured top N.
+//
+// Returns:
+//   - Report: The report.
+func (r *Recorder) Report(limit int) Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pruneLocked()
+	if limit <= 0 {
+		limit = r.topN
+	}
+
+	calls := make([]Call, len(r.calls))
+	copy(calls, r.calls)
+	sort.SliceStable(calls, func(i, j int) bool {
+		return calls[i].DurationMs > calls[j].DurationMs
+	})
+
+	byTool := make(map[string]*ToolSummary)
+	totals := make(map[string]int64)
+	for _, c := range calls {
+		s, ok := byTool[c.Tool]
+		if !ok {
+			s = &ToolSummary{Tool: c.Tool}
+			byTool[c.Tool] = s
+		}
+		s.Count++
+		totals[c.Tool] += c.DurationMs
+		if c.DurationMs > s.MaxMs {
+			s.MaxMs = c.DurationMs
+		}
+	}
+	tools := make([]ToolSummary, 0, len(byTool))
+	for name, s := range byTool {
+		s.AvgMs = totals[name] / int64(s.Count)
+		tools = append(tools, *s)
+	}
+	sort.Slice(tools, func(i, j int) bool {
+		if tools[i].Count != tools[j].Count {
+			return tools[i].Count > tools[j].Count
+		}
+		return tools[i].Tool < tools[j].Tool
+	})
+
+	if len(calls) > limit {
+		calls = calls[:limit]
+	}
+	return Report{Window: r.window.String(), Calls: calls, Tools: tools}
+}
+
+// pruneLocked drops the calls older than the window.
+func (r *Recorder) pruneLocked() {
+	cutoff := r.now().Add(-r.window)
+	kept := r.calls[:0]
+	for _, c := range r.calls {
+		if c.Timestamp.After(cutoff) {
+			kept = append(kept, c)
+		}
+	}
+	r.calls = kept
+}
+
+func isPattern(name string) bool {
+	for _, c := range name {
+		switch c {
+		case '*', '?', '[':
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package slowcall
+
+import (
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestRecorder_Threshold(t *testing.T) {
+	r := NewRecorder(nil)
+	assert.Zero(t, r.Threshold("weather.get_forecast"), "nothing is tracked by default")
+
+	r.SetConfig(configv1.SlowCallConfig_builder{
+		Threshold: durationpb.New(2 * time.Second),
+		ToolThresholds: map[string]*durationpb.Duration{
+			"weather.*":            durationpb.New(5 * time.Second),
+			"weather.get_*":        durationpb.New(3 * time.Second),
+			"weather.get_forecast": durationpb.New(10 * time.Second),
+			"github.list_issues":   durationpb.New(0),
+		},
+	}.Build())
+
+	assert.Equal(t, 10*time.Second, r.Threshold("weather.get_forecast"), "an exact name wins")
+	assert.Equal(t, 3*time.Second, r.Threshold("weather.get_alerts"), "the longest pattern wins")
+	assert.Equal(t, 5*time.Second, r.Threshold("weather.subscribe"))
+	assert.Equal(t, 2*time.Second, r.Threshold("github.create_issue"), "the default threshold")
+	assert.Zero(t, r.Threshold("github.list_issues"), "a zero threshold disables tracking")
+}
+
+func TestRecorder_Report(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	r := NewRecorder(configv1.SlowCallConfig_builder{
+		TopN:   proto.Int32(2),
+		Window: durationpb.New(time.Hour),
+	}.Build())
+	r.now = func() time.Time { return now }
+
+	r.Record(Call{Timestamp: now.Add(-2 * time.Hour), Tool: "weather.get_forecast", DurationMs: 9000})
+	r.Record(Call{Timestamp: now.Add(-time.Minute), Tool: "weather.get_forecast", DurationMs: 3000, UpstreamMs: 2500})
+	r.Record(Call{Timestamp: now.Add(-time.Minute), Tool: "github.list_issues", DurationMs: 6000})
+	r.Record(Call{Timestamp: now, Tool: "weather.get_forecast", DurationMs: 5000})
+
+	report := r.Report(0)
+	assert.Equal(t, "1h0m0s", report.Window)
+	require.Len(t, report.Calls, 2, "the report is limited to the top N")
+	assert.Equal(t, int64(6000), report.Calls[0].DurationMs, "the call out of the window is dropped")
+	assert.Equal(t, int64(5000), report.Calls[1].DurationMs)
+
+	require.Len(t, report.Tools, 2)
+	assert.Equal(t, ToolSummary{Tool: "weather.get_forecast", Count: 2, MaxMs: 5000, AvgMs: 4000}, report.Tools[0])
+	assert.Equal(t, ToolSummary{Tool: "github.list_issues", Count: 1, MaxMs: 6000, AvgMs: 6000}, report.Tools[1])
+
+	assert.Len(t, r.Report(10).Calls, 3)
+}
+
+func TestRecorder_Capacity(t *testing.T) {
+	r := NewRecorder(nil)
+	now := time.Now()
+	for i := 0; i < maxCalls+10; i++ {
+		r.Record(Call{Timestamp: now, Tool: "slow", DurationMs: int64(i)})
+	}
+	report := r.Report(maxCalls + 10)
+	require.Len(t, report.Calls, maxCalls)
+	assert.Equal(t, int64(maxCalls+9), report.Calls[0].DurationMs)
+	assert.Equal(t, int64(10), report.Calls[maxCalls-1].DurationMs, "the fastest calls are dropped")
+}
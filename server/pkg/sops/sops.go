@@ -0,0 +1,199 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sops reads and writes configuration files encrypted with SOPS
+// (https://getsops.io), using the sops and age Go packages. Decrypted
+// documents are only returned in memory.
+package sops
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	sopsv3 "github.com/getsops/sops/v3"
+	"github.com/getsops/sops/v3/aes"
+	"github.com/getsops/sops/v3/age"
+	"github.com/getsops/sops/v3/cmd/sops/common"
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	"github.com/getsops/sops/v3/config"
+	"github.com/getsops/sops/v3/decrypt"
+	"github.com/getsops/sops/v3/gcpkms"
+	"github.com/getsops/sops/v3/keyservice"
+	"github.com/getsops/sops/v3/kms"
+	"github.com/getsops/sops/v3/version"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the file format of a SOPS document.
+type Format int
+
+const (
+	// FormatYAML is a YAML document.
+	FormatYAML Format = iota
+	// FormatJSON is a JSON document.
+	FormatJSON
+)
+
+const (
+	metadataKey              = "sops"
+	defaultUnencryptedSuffix = "_unencrypted"
+)
+
+// FormatForPath returns the format of a file by its extension.
+//
+// Parameters:
+//   - path: string. The file path.
+//
+// Returns:
+//   - Format: The format of the file.
+//   - error: An error if the extension is not .yaml, .yml or .json.
+func FormatForPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".json":
+		return FormatJSON, nil
+	default:
+		return 0, fmt.Errorf("unsupported sops file format %q, use .yaml, .yml or .json", filepath.Ext(path))
+	}
+}
+
+// sopsFormat returns the sops format of f.
+func (f Format) sopsFormat() formats.Format {
+	if f == FormatJSON {
+		return formats.Json
+	}
+	return formats.Yaml
+}
+
+// IsEncrypted reports whether data is a SOPS encrypted document, that is a
+// YAML or JSON object with a "sops" metadata entry holding a MAC.
+//
+// Parameters:
+//   - data: []byte. The document.
+//
+// Returns:
+//   - bool: True if the document is SOPS encrypted.
+func IsEncrypted(data []byte) bool {
+	if !bytes.Contains(data, []byte(metadataKey)) || !bytes.Contains(data, []byte("ENC[AES256_GCM,")) {
+		return false
+	}
+	var probe struct {
+		Sops *struct {
+			MAC string `yaml:"mac"`
+		} `yaml:"sops"`
+	}
+	return yaml.Unmarshal(data, &probe) == nil && probe.Sops != nil && probe.Sops.MAC != ""
+}
+
+// Decrypt decrypts a SOPS document and returns it without its metadata, in
+// the same format. The data key is decrypted by the key sources of the sops
+// CLI: the age identities of SOPS_AGE_KEY, SOPS_AGE_KEY_FILE or the sops key
+// file (<user config dir>/sops/age/keys.txt), PGP, or AWS KMS, Google Cloud
+// KMS, Azure Key Vault and HashiCorp Vault with the default credentials of
+// the host.
+//
+// Parameters:
+//   - data: []byte. The encrypted document.
+//   - format: Format. The format of the document.
+//
+// Returns:
+//   - []byte: The decrypted document.
+//   - error: An error if no key decrypts the data key, a value cannot be
+//     decrypted or the MAC does not match.
+func Decrypt(data []byte, format Format) ([]byte, error) {
+	return decrypt.DataWithFormat(data, format.sopsFormat())
+}
+
+// EncryptOptions configures the keys and rules of Encrypt.
+type EncryptOptions struct {
+	// AgeRecipients are the age recipients (age1...) to encrypt the data key to.
+	AgeRecipients []string
+	// KMSARNs are the AWS KMS key ARNs to encrypt the data key with.
+	KMSARNs []string
+	// GCPKMSResourceIDs are the Google Cloud KMS key resource IDs
+	// (projects/.../cryptoKeys/...) to encrypt the data key with.
+	GCPKMSResourceIDs []string
+	// EncryptedRegex, when set, encrypts only the values under a key that
+	// matches it. By default all values are encrypted except those under a
+	// key ending with "_unencrypted".
+	EncryptedRegex string
+}
+
+// keyGroup returns the master keys of the options.
+func (o EncryptOptions) keyGroup() (sopsv3.KeyGroup, error) {
+	var group sopsv3.KeyGroup
+	for _, recipient := range o.AgeRecipients {
+		ageKeys, err := age.MasterKeysFromRecipients(recipient)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an age recipient: %w", recipient, err)
+		}
+		for _, key := range ageKeys {
+			group = append(group, key)
+		}
+	}
+	for _, arn := range o.KMSARNs {
+		group = append(group, kms.NewMasterKeyFromArn(arn, nil, ""))
+	}
+	for _, id := range o.GCPKMSResourceIDs {
+		group = append(group, gcpkms.NewMasterKeyFromResourceID(id))
+	}
+	if len(group) == 0 {
+		return nil, errors.New("no keys to encrypt with: set age recipients, AWS KMS ARNs or GCP KMS resource IDs")
+	}
+	return group, nil
+}
+
+// Encrypt encrypts a YAML or JSON document with SOPS, in the same format.
+// The result can be decrypted by Decrypt and by the sops CLI.
+//
+// Parameters:
+//   - data: []byte. The plaintext document.
+//   - format: Format. The format of the document.
+//   - opts: EncryptOptions. The keys to encrypt the data key with.
+//
+// Returns:
+//   - []byte: The encrypted document.
+//   - error: An error if no key is given, the document is already encrypted
+//     or a key cannot encrypt the data key.
+func Encrypt(data []byte, format Format, opts EncryptOptions) ([]byte, error) {
+	group, err := opts.keyGroup()
+	if err != nil {
+		return nil, err
+	}
+	if IsEncrypted(data) {
+		return nil, errors.New("the document is already encrypted with sops")
+	}
+	store := common.StoreForFormat(format.sopsFormat(), config.NewStoresConfig())
+	branches, err := store.LoadPlainFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse document: %w", err)
+	}
+	if len(branches) == 0 {
+		return nil, errors.New("failed to parse document: it is empty")
+	}
+
+	tree := sopsv3.Tree{
+		Branches: branches,
+		Metadata: sopsv3.Metadata{
+			KeyGroups:      []sopsv3.KeyGroup{group},
+			EncryptedRegex: opts.EncryptedRegex,
+			Version:        version.Version,
+		},
+	}
+	if opts.EncryptedRegex == "" {
+		tree.Metadata.UnencryptedSuffix = defaultUnencryptedSuffix
+	}
+	dataKey, errs := tree.GenerateDataKeyWithKeyServices([]keyservice.KeyServiceClient{keyservice.NewLocalClient()})
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to encrypt the data key: %w", errors.Join(errs...))
+	}
+	defer clear(dataKey)
+	if err := common.EncryptTree(common.EncryptTreeOpts{Tree: &tree, Cipher: aes.NewCipher(), DataKey: dataKey}); err != nil {
+		return nil, err
+	}
+	return store.EmitEncryptedFile(tree)
+}
@@ -0,0 +1,218 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package sops
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// The files in testdata were written by the sops 3.10.2 and age 1.2.1 CLIs:
+//
+//	age-keygen -o keys.txt
+//	sops encrypt --age <recipient> config.yaml > config.enc.yaml
+//	sops encrypt --age <recipient> config.json > config.enc.json
+//	sops encrypt --age <recipient> --encrypted-regex '^(value|api_key)$' config.yaml > regex.enc.yaml
+
+// useAgeKeys makes sops read its age identities from keyFile or identity,
+// whichever is set, and from nowhere else.
+func useAgeKeys(t *testing.T, keyFile, identity string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+	// sops reads SOPS_AGE_KEY_FILE whenever it is set, even to "".
+	for name, value := range map[string]string{"SOPS_AGE_KEY_FILE": keyFile, "SOPS_AGE_KEY": identity} {
+		t.Setenv(name, value)
+		if value == "" {
+			require.NoError(t, os.Unsetenv(name))
+		}
+	}
+}
+
+// newTestIdentity returns a random age identity and its recipient string.
+func newTestIdentity(t *testing.T) (string, string) {
+	t.Helper()
+	id, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	return id.String(), id.Recipient().String()
+}
+
+func readTestdata(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return data
+}
+
+func assertSameYAML(t *testing.T, want, got []byte) {
+	t.Helper()
+	var w, g map[string]any
+	require.NoError(t, yaml.Unmarshal(want, &w))
+	require.NoError(t, yaml.Unmarshal(got, &g))
+	assert.Equal(t, w, g)
+}
+
+func TestDecrypt_CLIVectors(t *testing.T) {
+	useAgeKeys(t, filepath.Join("testdata", "keys.txt"), "")
+
+	for _, tc := range []struct {
+		encrypted, plain string
+		format           Format
+	}{
+		{"config.enc.yaml", "config.yaml", FormatYAML},
+		{"regex.enc.yaml", "config.yaml", FormatYAML},
+		{"config.enc.json", "config.json", FormatJSON},
+	} {
+		t.Run(tc.encrypted, func(t *testing.T) {
+			encrypted := readTestdata(t, tc.encrypted)
+			assert.True(t, IsEncrypted(encrypted))
+
+			decrypted, err := Decrypt(encrypted, tc.format)
+			require.NoError(t, err)
+			assert.False(t, IsEncrypted(decrypted))
+			if tc.format == FormatJSON {
+				assert.JSONEq(t, string(readTestdata(t, tc.plain)), string(decrypted))
+			} else {
+				assertSameYAML(t, readTestdata(t, tc.plain), decrypted)
+			}
+		})
+	}
+}
+
+func TestDecrypt_RejectsTampering(t *testing.T) {
+	useAgeKeys(t, filepath.Join("testdata", "keys.txt"), "")
+	encrypted := string(readTestdata(t, "config.enc.yaml"))
+
+	// A changed unencrypted value no longer matches the MAC.
+	tampered := strings.Replace(encrypted, "note_unencrypted: visible", "note_unencrypted: changed", 1)
+	_, err := Decrypt([]byte(tampered), FormatYAML)
+	assert.ErrorContains(t, err, "Failed to verify data integrity")
+
+	// A changed MAC fails its authentication.
+	var doc struct {
+		Sops struct {
+			MAC string `yaml:"mac"`
+		} `yaml:"sops"`
+	}
+	require.NoError(t, yaml.Unmarshal([]byte(encrypted), &doc))
+	mac := doc.Sops.MAC
+	forged := strings.Replace(mac, "data:K", "data:L", 1)
+	require.NotEqual(t, mac, forged)
+	_, err = Decrypt([]byte(strings.Replace(encrypted, mac, forged, 1)), FormatYAML)
+	assert.ErrorContains(t, err, "Failed to decrypt original mac")
+
+	// Moving an encrypted value under another key fails its authentication.
+	var tree yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(encrypted), &tree))
+	orders := tree.Content[0].Content[1].Content[0]
+	orders.Content[1], orders.Content[3].Content[1] = orders.Content[3].Content[1], orders.Content[1]
+	moved, err := yaml.Marshal(&tree)
+	require.NoError(t, err)
+	_, err = Decrypt(moved, FormatYAML)
+	assert.Error(t, err)
+}
+
+func TestDecrypt_WrongKey(t *testing.T) {
+	other, _ := newTestIdentity(t)
+	useAgeKeys(t, "", other)
+
+	_, err := Decrypt(readTestdata(t, "config.enc.yaml"), FormatYAML)
+	assert.ErrorContains(t, err, "Error getting data key")
+}
+
+func TestEncryptDecrypt_YAML(t *testing.T) {
+	identity, recipient := newTestIdentity(t)
+	useAgeKeys(t, "", identity)
+	plain := readTestdata(t, "config.yaml")
+
+	encrypted, err := Encrypt(plain, FormatYAML, EncryptOptions{AgeRecipients: []string{recipient}})
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(encrypted))
+	assert.NotContains(t, string(encrypted), "s3cr3t")
+	assert.NotContains(t, string(encrypted), "orders.example.com")
+	assert.Contains(t, string(encrypted), "note_unencrypted: visible")
+	assert.Contains(t, string(encrypted), "recipient: "+recipient)
+
+	decrypted, err := Decrypt(encrypted, FormatYAML)
+	require.NoError(t, err)
+	assertSameYAML(t, plain, decrypted)
+	assert.Contains(t, string(decrypted), "# the orders service")
+}
+
+func TestEncryptDecrypt_JSON(t *testing.T) {
+	identity, recipient := newTestIdentity(t)
+	useAgeKeys(t, "", identity)
+	plain := readTestdata(t, "config.json")
+
+	encrypted, err := Encrypt(plain, FormatJSON, EncryptOptions{AgeRecipients: []string{recipient}})
+	require.NoError(t, err)
+	require.True(t, json.Valid(encrypted))
+	assert.True(t, IsEncrypted(encrypted))
+
+	decrypted, err := Decrypt(encrypted, FormatJSON)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(plain), string(decrypted))
+}
+
+func TestEncrypt_EncryptedRegex(t *testing.T) {
+	identity, recipient := newTestIdentity(t)
+	useAgeKeys(t, "", identity)
+
+	encrypted, err := Encrypt(readTestdata(t, "config.yaml"), FormatYAML, EncryptOptions{
+		AgeRecipients:  []string{recipient},
+		EncryptedRegex: "^(value|api_key)$",
+	})
+	require.NoError(t, err)
+	assert.NotContains(t, string(encrypted), "s3cr3t")
+	assert.Contains(t, string(encrypted), "https://orders.example.com")
+	assert.Contains(t, string(encrypted), "encrypted_regex: ^(value|api_key)$")
+
+	decrypted, err := Decrypt(encrypted, FormatYAML)
+	require.NoError(t, err)
+	assert.Contains(t, string(decrypted), "value: s3cr3t")
+}
+
+func TestEncrypt_Errors(t *testing.T) {
+	_, recipient := newTestIdentity(t)
+	opts := EncryptOptions{AgeRecipients: []string{recipient}}
+	plain := readTestdata(t, "config.yaml")
+
+	_, err := Encrypt(plain, FormatYAML, EncryptOptions{})
+	assert.ErrorContains(t, err, "no keys to encrypt with")
+
+	_, err = Encrypt(plain, FormatYAML, EncryptOptions{AgeRecipients: []string{"age1nope"}})
+	assert.ErrorContains(t, err, "is not an age recipient")
+
+	_, err = Encrypt([]byte("- a\n- b\n"), FormatYAML, opts)
+	assert.ErrorContains(t, err, "failed to parse document")
+
+	_, err = Encrypt(readTestdata(t, "config.enc.yaml"), FormatYAML, opts)
+	assert.ErrorContains(t, err, "already encrypted")
+}
+
+func TestIsEncrypted(t *testing.T) {
+	assert.False(t, IsEncrypted(readTestdata(t, "config.yaml")))
+	assert.False(t, IsEncrypted([]byte("sops: {}\nvalue: ENC[AES256_GCM,data:x]\n")))
+	assert.True(t, IsEncrypted([]byte(`{"value": "ENC[AES256_GCM,data:x]", "sops": {"mac": "ENC[AES256_GCM,data:y]"}}`)))
+}
+
+func TestFormatForPath(t *testing.T) {
+	f, err := FormatForPath("config.enc.YAML")
+	require.NoError(t, err)
+	assert.Equal(t, FormatYAML, f)
+	f, err = FormatForPath("config.json")
+	require.NoError(t, err)
+	assert.Equal(t, FormatJSON, f)
+	_, err = FormatForPath("config.textproto")
+	assert.Error(t, err)
+}
@@ -615,6 +615,373 @@ type Storage interface {
 	//   - Removes the credential from the underlying storage.
 	DeleteCredential(ctx context.Context, id string) error
 
+	// ListAPIKeys retrieves all client API keys.
+	//
+	// Summary: Lists all client API keys.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//
+	// Returns:
+	//   - []*configv1.ClientApiKey: A list of API keys.
+	//   - error: An error if listing fails.
+	//
+	// Errors:
+	//   - Returns an error if storage read fails.
+	ListAPIKeys(ctx context.Context) ([]*configv1.ClientApiKey, error)
+
+	// GetAPIKey retrieves a client API key by ID.
+	//
+	// Summary: Retrieves a client API key by ID.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - id (string): The key ID.
+	//
+	// Returns:
+	//   - *configv1.ClientApiKey: The API key, or nil if not found.
+	//   - error: An error if retrieval fails.
+	//
+	// Errors:
+	//   - Returns an error if storage read fails.
+	GetAPIKey(ctx context.Context, id string) (*configv1.ClientApiKey, error)
+
+	// SaveAPIKey saves a client API key.
+	//
+	// Summary: Persists a client API key.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - key (*configv1.ClientApiKey): The API key to save.
+	//
+	// Returns:
+	//   - error: An error if saving fails.
+	//
+	// Errors:
+	//   - Returns an error if storage write fails.
+	//
+	// Side Effects:
+	//   - Persists the API key to the underlying storage.
+	SaveAPIKey(ctx context.Context, key *configv1.ClientApiKey) error
+
+	// DeleteAPIKey deletes a client API key by ID.
+	//
+	// Summary: Deletes a client API key.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - id (string): The key ID to delete.
+	//
+	// Returns:
+	//   - error: An error if deletion fails.
+	//
+	// Errors:
+	//   - Returns an error if storage delete fails.
+	//
+	// Side Effects:
+	//   - Removes the API key from the underlying storage.
+	DeleteAPIKey(ctx context.Context, id string) error
+
+	// SaveConfigVersion records a reload of the configuration.
+	//
+	// Summary: Persists a configuration version.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - version (*configv1.ConfigVersion): The version to save. Its version number is set to the next one.
+	//
+	// Returns:
+	//   - error: An error if saving fails.
+	//
+	// Errors:
+	//   - Returns an error if storage write fails.
+	//
+	// Side Effects:
+	//   - Persists the version to the underlying storage and sets its version number.
+	SaveConfigVersion(ctx context.Context, version *configv1.ConfigVersion) error
+
+	// ListConfigVersions retrieves the most recent configuration versions.
+	//
+	// Summary: Lists configuration versions, newest first.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - limit (int): The maximum number of versions to retrieve, or 0 for all.
+	//
+	// Returns:
+	//   - []*configv1.ConfigVersion: The versions, newest first.
+	//   - error: An error if listing fails.
+	//
+	// Errors:
+	//   - Returns an error if storage read fails.
+	ListConfigVersions(ctx context.Context, limit int) ([]*configv1.ConfigVersion, error)
+
+	// GetConfigVersion retrieves a configuration version by number.
+	//
+	// Summary: Retrieves a configuration version.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - version (int64): The version number.
+	//
+	// Returns:
+	//   - *configv1.ConfigVersion: The version, or nil if not found.
+	//   - error: An error if retrieval fails.
+	//
+	// Errors:
+	//   - Returns an error if storage read fails.
+	GetConfigVersion(ctx context.Context, version int64) (*configv1.ConfigVersion, error)
+
+	// PruneConfigVersions deletes all but the most recent configuration versions.
+	//
+	// Summary: Deletes old configuration versions.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - keep (int): The number of versions to keep.
+	//
+	// Returns:
+	//   - error: An error if deletion fails.
+	//
+	// Errors:
+	//   - Returns an error if storage delete fails.
+	//
+	// Side Effects:
+	//   - Removes the older versions from the underlying storage.
+	PruneConfigVersions(ctx context.Context, keep int) error
+
+	// SaveToolCatalogSnapshot saves the tool catalog snapshot of a service,
+	// replacing the previous one.
+	//
+	// Summary: Persists a tool catalog snapshot.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - snapshot (*configv1.ToolCatalogSnapshot): The snapshot to save.
+	//
+	// Returns:
+	//   - error: An error if saving fails.
+	//
+	// Errors:
+	//   - Returns an error if storage write fails.
+	//
+	// Side Effects:
+	//   - Persists the snapshot to the underlying storage.
+	SaveToolCatalogSnapshot(ctx context.Context, snapshot *configv1.ToolCatalogSnapshot) error
+
+	// ListToolCatalogSnapshots retrieves the tool catalog snapshots of all services.
+	//
+	// Summary: Lists tool catalog snapshots.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//
+	// Returns:
+	//   - []*configv1.ToolCatalogSnapshot: The snapshots.
+	//   - error: An error if listing fails.
+	//
+	// Errors:
+	//   - Returns an error if storage read fails.
+	ListToolCatalogSnapshots(ctx context.Context) ([]*configv1.ToolCatalogSnapshot, error)
+
+	// DeleteToolCatalogSnapshot deletes the tool catalog snapshot of a service.
+	//
+	// Summary: Deletes a tool catalog snapshot.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - serviceID (string): The ID of the service.
+	//
+	// Returns:
+	//   - error: An error if deletion fails.
+	//
+	// Errors:
+	//   - Returns an error if storage delete fails.
+	//
+	// Side Effects:
+	//   - Removes the snapshot from the underlying storage.
+	DeleteToolCatalogSnapshot(ctx context.Context, serviceID string) error
+
+	// SaveWebhookDeadLetter saves a dead-lettered webhook event, replacing the
+	// one with the same ID.
+	//
+	// Summary: Persists a webhook dead letter.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - letter (*configv1.WebhookDeadLetter): The dead letter to save.
+	//
+	// Returns:
+	//   - error: An error if saving fails.
+	//
+	// Errors:
+	//   - Returns an error if storage write fails.
+	//
+	// Side Effects:
+	//   - Persists the dead letter to the underlying storage.
+	SaveWebhookDeadLetter(ctx context.Context, letter *configv1.WebhookDeadLetter) error
+
+	// ListWebhookDeadLetters retrieves the dead-lettered webhook events.
+	//
+	// Summary: Lists webhook dead letters, newest first.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - limit (int): The maximum number of dead letters to return, 0 for all.
+	//
+	// Returns:
+	//   - []*configv1.WebhookDeadLetter: The dead letters.
+	//   - error: An error if listing fails.
+	//
+	// Errors:
+	//   - Returns an error if storage read fails.
+	ListWebhookDeadLetters(ctx context.Context, limit int) ([]*configv1.WebhookDeadLetter, error)
+
+	// GetWebhookDeadLetter retrieves a dead-lettered webhook event by ID.
+	//
+	// Summary: Retrieves a webhook dead letter.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - id (string): The ID of the dead letter.
+	//
+	// Returns:
+	//   - *configv1.WebhookDeadLetter: The dead letter, or nil if not found.
+	//   - error: An error if retrieval fails.
+	//
+	// Errors:
+	//   - Returns an error if storage read fails.
+	GetWebhookDeadLetter(ctx context.Context, id string) (*configv1.WebhookDeadLetter, error)
+
+	// DeleteWebhookDeadLetter deletes a dead-lettered webhook event.
+	//
+	// Summary: Deletes a webhook dead letter.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - id (string): The ID of the dead letter.
+	//
+	// Returns:
+	//   - error: An error if deletion fails.
+	//
+	// Errors:
+	//   - Returns an error if storage delete fails.
+	//
+	// Side Effects:
+	//   - Removes the dead letter from the underlying storage.
+	DeleteWebhookDeadLetter(ctx context.Context, id string) error
+
+	// SaveWorkerJob saves a job of an asynchronous tool, replacing the one with
+	// the same ID.
+	//
+	// Summary: Persists a worker job.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - job (*configv1.WorkerJob): The job to save.
+	//
+	// Returns:
+	//   - error: An error if saving fails.
+	//
+	// Errors:
+	//   - Returns an error if storage write fails.
+	//
+	// Side Effects:
+	//   - Persists the job to the underlying storage.
+	SaveWorkerJob(ctx context.Context, job *configv1.WorkerJob) error
+
+	// ListWorkerJobs retrieves the jobs of the asynchronous tools.
+	//
+	// Summary: Lists worker jobs, newest first.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - limit (int): The maximum number of jobs to return, 0 for all.
+	//
+	// Returns:
+	//   - []*configv1.WorkerJob: The jobs.
+	//   - error: An error if listing fails.
+	//
+	// Errors:
+	//   - Returns an error if storage read fails.
+	ListWorkerJobs(ctx context.Context, limit int) ([]*configv1.WorkerJob, error)
+
+	// GetWorkerJob retrieves a job of an asynchronous tool by ID.
+	//
+	// Summary: Retrieves a worker job.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - id (string): The ID of the job.
+	//
+	// Returns:
+	//   - *configv1.WorkerJob: The job, or nil if not found.
+	//   - error: An error if retrieval fails.
+	//
+	// Errors:
+	//   - Returns an error if storage read fails.
+	GetWorkerJob(ctx context.Context, id string) (*configv1.WorkerJob, error)
+
+	// ClaimWorkerJob leases a job that is not done to a replica, if the
+	// replica holds the lease already or the lease expired. It is atomic, so
+	// that only one of the replicas claiming a job gets it.
+	//
+	// Summary: Claims or renews the lease of a worker job.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - id (string): The ID of the job.
+	//   - owner (string): The ID of the claiming replica.
+	//   - now (string): The current time, in the RFC 3339 format of the jobs.
+	//   - leaseUntil (string): The end of the lease, in the same format.
+	//
+	// Returns:
+	//   - bool: True if the replica holds the lease now.
+	//   - error: An error if the update fails.
+	//
+	// Errors:
+	//   - Returns an error if storage write fails.
+	//
+	// Side Effects:
+	//   - Updates the lease of the job in the underlying storage.
+	ClaimWorkerJob(ctx context.Context, id, owner, now, leaseUntil string) (bool, error)
+
+	// DeleteWorkerJob deletes a job of an asynchronous tool.
+	//
+	// Summary: Deletes a worker job.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - id (string): The ID of the job.
+	//
+	// Returns:
+	//   - error: An error if deletion fails.
+	//
+	// Errors:
+	//   - Returns an error if storage delete fails.
+	//
+	// Side Effects:
+	//   - Removes the job from the underlying storage.
+	DeleteWorkerJob(ctx context.Context, id string) error
+
+	// DeleteWorkerJobsCompletedBefore deletes the jobs done before a time.
+	//
+	// Summary: Prunes the expired worker jobs.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - before (string): The time, in the RFC 3339 format of the jobs.
+	//
+	// Returns:
+	//   - error: An error if deletion fails.
+	//
+	// Errors:
+	//   - Returns an error if storage delete fails.
+	//
+	// Side Effects:
+	//   - Removes the jobs from the underlying storage.
+	DeleteWorkerJobsCompletedBefore(ctx context.Context, before string) error
+
 	// Close closes the underlying storage connection.
 	//
 	// Summary: Closes the storage connection.
@@ -36,6 +36,12 @@ type Store struct {
 	tokens             map[tokenKey]*configv1.UserToken
 	credentials        map[string]*configv1.Credential
 	serviceTemplates   map[string]*configv1.ServiceTemplate
+	apiKeys            map[string]*configv1.ClientApiKey
+	configVersions     []*configv1.ConfigVersion
+	toolSnapshots      map[string]*configv1.ToolCatalogSnapshot
+	deadLetters        map[string]*configv1.WebhookDeadLetter
+	workerJobs         map[string]*configv1.WorkerJob
+	workerJobLeases    map[string]workerJobLease
 	logs               []*logging.LogEntry
 }
 
@@ -58,6 +64,11 @@ func NewStore() *Store {
 		tokens:             make(map[tokenKey]*configv1.UserToken),
 		credentials:        make(map[string]*configv1.Credential),
 		serviceTemplates:   make(map[string]*configv1.ServiceTemplate),
+		apiKeys:            make(map[string]*configv1.ClientApiKey),
+		toolSnapshots:      make(map[string]*configv1.ToolCatalogSnapshot),
+		deadLetters:        make(map[string]*configv1.WebhookDeadLetter),
+		workerJobs:         make(map[string]*configv1.WorkerJob),
+		workerJobLeases:    make(map[string]workerJobLease),
 		logs:               make([]*logging.LogEntry, 0),
 	}
 }
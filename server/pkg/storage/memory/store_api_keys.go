@@ -0,0 +1,102 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// ListAPIKeys retrieves all client API keys.
+//
+// Summary: Lists all stored client API keys.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//
+// Returns:
+//   - []*configv1.ClientApiKey: A list of API keys, ordered by creation time.
+//   - error: Always nil.
+func (s *Store) ListAPIKeys(_ context.Context) ([]*configv1.ClientApiKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]*configv1.ClientApiKey, 0, len(s.apiKeys))
+	for _, k := range s.apiKeys {
+		list = append(list, proto.Clone(k).(*configv1.ClientApiKey))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].GetCreatedAt() != list[j].GetCreatedAt() {
+			return list[i].GetCreatedAt() < list[j].GetCreatedAt()
+		}
+		return list[i].GetId() < list[j].GetId()
+	})
+	return list, nil
+}
+
+// GetAPIKey retrieves a client API key by ID.
+//
+// Summary: Retrieves a client API key by ID.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//   - id: string. The key ID.
+//
+// Returns:
+//   - *configv1.ClientApiKey: The API key, or nil if not found.
+//   - error: Always nil.
+func (s *Store) GetAPIKey(_ context.Context, id string) (*configv1.ClientApiKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if k, ok := s.apiKeys[id]; ok {
+		return proto.Clone(k).(*configv1.ClientApiKey), nil
+	}
+	return nil, nil
+}
+
+// SaveAPIKey saves a client API key.
+//
+// Summary: Stores a client API key.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//   - key: *configv1.ClientApiKey. The API key to save.
+//
+// Returns:
+//   - error: An error if the key ID is missing.
+//
+// Side Effects:
+//   - Updates the internal API key map.
+func (s *Store) SaveAPIKey(_ context.Context, key *configv1.ClientApiKey) error {
+	if key.GetId() == "" {
+		return fmt.Errorf("api key ID is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apiKeys[key.GetId()] = proto.Clone(key).(*configv1.ClientApiKey)
+	return nil
+}
+
+// DeleteAPIKey deletes a client API key by ID.
+//
+// Summary: Deletes a client API key.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//   - id: string. The key ID to delete.
+//
+// Returns:
+//   - error: Always nil.
+//
+// Side Effects:
+//   - Removes the key from the internal map.
+func (s *Store) DeleteAPIKey(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.apiKeys, id)
+	return nil
+}
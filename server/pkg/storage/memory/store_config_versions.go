@@ -0,0 +1,105 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"context"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// SaveConfigVersion records a reload of the configuration.
+//
+// Summary: Appends a configuration version to the in-memory store.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//   - version: *configv1.ConfigVersion. The version to save. Its version number is set to the next one.
+//
+// Returns:
+//   - error: Always nil.
+//
+// Side Effects:
+//   - Sets the version number of version and stores a copy of it.
+func (s *Store) SaveConfigVersion(_ context.Context, version *configv1.ConfigVersion) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next := int64(1)
+	if n := len(s.configVersions); n > 0 {
+		next = s.configVersions[n-1].GetVersion() + 1
+	}
+	version.SetVersion(next)
+	s.configVersions = append(s.configVersions, proto.Clone(version).(*configv1.ConfigVersion))
+	return nil
+}
+
+// ListConfigVersions retrieves the most recent configuration versions.
+//
+// Summary: Lists configuration versions, newest first.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//   - limit: int. The maximum number of versions to retrieve, or 0 for all.
+//
+// Returns:
+//   - []*configv1.ConfigVersion: The versions, newest first.
+//   - error: Always nil.
+func (s *Store) ListConfigVersions(_ context.Context, limit int) ([]*configv1.ConfigVersion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	n := len(s.configVersions)
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	list := make([]*configv1.ConfigVersion, 0, n)
+	for i := len(s.configVersions) - 1; i >= 0 && len(list) < n; i-- {
+		list = append(list, proto.Clone(s.configVersions[i]).(*configv1.ConfigVersion))
+	}
+	return list, nil
+}
+
+// GetConfigVersion retrieves a configuration version by number.
+//
+// Summary: Retrieves a configuration version.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//   - version: int64. The version number.
+//
+// Returns:
+//   - *configv1.ConfigVersion: The version, or nil if not found.
+//   - error: Always nil.
+func (s *Store) GetConfigVersion(_ context.Context, version int64) (*configv1.ConfigVersion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, v := range s.configVersions {
+		if v.GetVersion() == version {
+			return proto.Clone(v).(*configv1.ConfigVersion), nil
+		}
+	}
+	return nil, nil
+}
+
+// PruneConfigVersions deletes all but the most recent configuration versions.
+//
+// Summary: Deletes old configuration versions.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//   - keep: int. The number of versions to keep.
+//
+// Returns:
+//   - error: Always nil.
+//
+// Side Effects:
+//   - Removes the older versions from the store.
+func (s *Store) PruneConfigVersions(_ context.Context, keep int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n := len(s.configVersions); n > keep {
+		s.configVersions = append([]*configv1.ConfigVersion(nil), s.configVersions[n-max(keep, 0):]...)
+	}
+	return nil
+}
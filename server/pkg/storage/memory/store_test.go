@@ -374,6 +374,114 @@ func TestMemoryStore(t *testing.T) {
 		assert.Nil(t, got)
 	})
 
+	t.Run("Config Versions", func(t *testing.T) {
+		for _, actor := range []string{"alice", "bob", "carol"} {
+			v := configv1.ConfigVersion_builder{Actor: proto.String(actor)}.Build()
+			assert.NoError(t, s.SaveConfigVersion(ctx, v))
+		}
+
+		versions, err := s.ListConfigVersions(ctx, 2)
+		assert.NoError(t, err)
+		if assert.Len(t, versions, 2) {
+			assert.Equal(t, int64(3), versions[0].GetVersion())
+			assert.Equal(t, "carol", versions[0].GetActor())
+		}
+
+		got, err := s.GetConfigVersion(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", got.GetActor())
+
+		// The stored copy is not shared with the caller.
+		got.SetActor("mallory")
+		got, _ = s.GetConfigVersion(ctx, 1)
+		assert.Equal(t, "alice", got.GetActor())
+
+		assert.NoError(t, s.PruneConfigVersions(ctx, 1))
+		versions, err = s.ListConfigVersions(ctx, 0)
+		assert.NoError(t, err)
+		assert.Len(t, versions, 1)
+		got, err = s.GetConfigVersion(ctx, 1)
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("Tool Catalog Snapshots", func(t *testing.T) {
+		for _, id := range []string{"svc-b", "svc-a", "svc-a"} {
+			snapshot := configv1.ToolCatalogSnapshot_builder{
+				ServiceId: proto.String(id),
+				Tools:     []*configv1.ToolSnapshot{configv1.ToolSnapshot_builder{Name: proto.String("echo")}.Build()},
+			}.Build()
+			assert.NoError(t, s.SaveToolCatalogSnapshot(ctx, snapshot))
+		}
+
+		snapshots, err := s.ListToolCatalogSnapshots(ctx)
+		assert.NoError(t, err)
+		if assert.Len(t, snapshots, 2, "a snapshot replaces the previous one of the service") {
+			assert.Equal(t, "svc-a", snapshots[0].GetServiceId())
+			assert.Equal(t, "echo", snapshots[0].GetTools()[0].GetName())
+		}
+
+		assert.NoError(t, s.DeleteToolCatalogSnapshot(ctx, "svc-a"))
+		snapshots, err = s.ListToolCatalogSnapshots(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, snapshots, 1)
+	})
+
+	t.Run("Webhook Dead Letters", func(t *testing.T) {
+		for id, failedAt := range map[string]string{"dl-0": "2026-01-01T00:00:00.000Z", "dl-1": "2026-01-03T00:00:00.000Z", "dl-2": "2026-01-02T00:00:00.000Z"} {
+			letter := &configv1.WebhookDeadLetter{}
+			letter.SetId(id)
+			letter.SetFailedAt(failedAt)
+			assert.NoError(t, s.SaveWebhookDeadLetter(ctx, letter))
+		}
+
+		letters, err := s.ListWebhookDeadLetters(ctx, 2)
+		assert.NoError(t, err)
+		if assert.Len(t, letters, 2) {
+			assert.Equal(t, "dl-1", letters[0].GetId(), "the newest dead letter is listed first")
+			assert.Equal(t, "dl-2", letters[1].GetId())
+		}
+
+		letter, err := s.GetWebhookDeadLetter(ctx, "dl-0")
+		assert.NoError(t, err)
+		assert.Equal(t, "2026-01-01T00:00:00.000Z", letter.GetFailedAt())
+
+		assert.NoError(t, s.DeleteWebhookDeadLetter(ctx, "dl-0"))
+		letter, err = s.GetWebhookDeadLetter(ctx, "dl-0")
+		assert.NoError(t, err)
+		assert.Nil(t, letter)
+	})
+
+	t.Run("Worker Jobs", func(t *testing.T) {
+		for id, completedAt := range map[string]string{"job-0": "2026-01-01T00:00:00.000Z", "job-1": "", "job-2": "2026-01-03T00:00:00.000Z"} {
+			job := &configv1.WorkerJob{}
+			job.SetId(id)
+			job.SetCreatedAt("2026-01-0" + id[len(id)-1:] + "T00:00:00.000Z")
+			job.SetCompletedAt(completedAt)
+			assert.NoError(t, s.SaveWorkerJob(ctx, job))
+		}
+
+		jobs, err := s.ListWorkerJobs(ctx, 2)
+		assert.NoError(t, err)
+		if assert.Len(t, jobs, 2) {
+			assert.Equal(t, "job-2", jobs[0].GetId(), "the newest job is listed first")
+			assert.Equal(t, "job-1", jobs[1].GetId())
+		}
+
+		assert.NoError(t, s.DeleteWorkerJobsCompletedBefore(ctx, "2026-01-02T00:00:00.000Z"))
+		job, err := s.GetWorkerJob(ctx, "job-0")
+		assert.NoError(t, err)
+		assert.Nil(t, job, "the job done before is deleted")
+		job, err = s.GetWorkerJob(ctx, "job-1")
+		assert.NoError(t, err)
+		assert.NotNil(t, job, "the job not done is kept")
+
+		assert.NoError(t, s.DeleteWorkerJob(ctx, "job-1"))
+		jobs, err = s.ListWorkerJobs(ctx, 0)
+		assert.NoError(t, err)
+		assert.Len(t, jobs, 1)
+	})
+
 	t.Run("Close", func(t *testing.T) {
 		err := s.Close()
 		assert.NoError(t, err)
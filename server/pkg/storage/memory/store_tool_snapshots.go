@@ -0,0 +1,74 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"context"
+	"sort"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// SaveToolCatalogSnapshot saves the tool catalog snapshot of a service,
+// replacing the previous one.
+//
+// Summary: Stores a tool catalog snapshot in memory.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//   - snapshot: *configv1.ToolCatalogSnapshot. The snapshot to save.
+//
+// Returns:
+//   - error: Always nil.
+//
+// Side Effects:
+//   - Stores a copy of the snapshot.
+func (s *Store) SaveToolCatalogSnapshot(_ context.Context, snapshot *configv1.ToolCatalogSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.toolSnapshots[snapshot.GetServiceId()] = proto.Clone(snapshot).(*configv1.ToolCatalogSnapshot)
+	return nil
+}
+
+// ListToolCatalogSnapshots retrieves the tool catalog snapshots of all services.
+//
+// Summary: Lists tool catalog snapshots, sorted by service ID.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//
+// Returns:
+//   - []*configv1.ToolCatalogSnapshot: The snapshots.
+//   - error: Always nil.
+func (s *Store) ListToolCatalogSnapshots(_ context.Context) ([]*configv1.ToolCatalogSnapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]*configv1.ToolCatalogSnapshot, 0, len(s.toolSnapshots))
+	for _, snapshot := range s.toolSnapshots {
+		list = append(list, proto.Clone(snapshot).(*configv1.ToolCatalogSnapshot))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].GetServiceId() < list[j].GetServiceId() })
+	return list, nil
+}
+
+// DeleteToolCatalogSnapshot deletes the tool catalog snapshot of a service.
+//
+// Summary: Deletes a tool catalog snapshot.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//   - serviceID: string. The ID of the service.
+//
+// Returns:
+//   - error: Always nil.
+//
+// Side Effects:
+//   - Removes the snapshot from the store.
+func (s *Store) DeleteToolCatalogSnapshot(_ context.Context, serviceID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.toolSnapshots, serviceID)
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"context"
+	"sort"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// SaveWebhookDeadLetter saves a dead-lettered webhook event, replacing the one
+// with the same ID.
+//
+// Summary: Stores a webhook dead letter in memory.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//   - letter: *configv1.WebhookDeadLetter. The dead letter to save.
+//
+// Returns:
+//   - error: Always nil.
+//
+// Side Effects:
+//   - Stores a copy of the dead letter.
+func (s *Store) SaveWebhookDeadLetter(_ context.Context, letter *configv1.WebhookDeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deadLetters[letter.GetId()] = proto.Clone(letter).(*configv1.WebhookDeadLetter)
+	return nil
+}
+
+// ListWebhookDeadLetters retrieves the dead-lettered webhook events.
+//
+// Summary: Lists webhook dead letters, newest first.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//   - limit: int. The maximum number of dead letters to return, 0 for all.
+//
+// Returns:
+//   - []*configv1.WebhookDeadLetter: The dead letters.
+//   - error: Always nil.
+func (s *Store) ListWebhookDeadLetters(_ context.Context, limit int) ([]*configv1.WebhookDeadLetter, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]*configv1.WebhookDeadLetter, 0, len(s.deadLetters))
+	for _, letter := range s.deadLetters {
+		list = append(list, proto.Clone(letter).(*configv1.WebhookDeadLetter))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].GetFailedAt() != list[j].GetFailedAt() {
+			return list[i].GetFailedAt() > list[j].GetFailedAt()
+		}
+		return list[i].GetId() > list[j].GetId()
+	})
+	if limit > 0 && len(list) > limit {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+// GetWebhookDeadLetter retrieves a dead-lettered webhook event by ID.
+//
+// Summary: Retrieves a webhook dead letter.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//   - id: string. The ID of the dead letter.
+//
+// Returns:
+//   - *configv1.WebhookDeadLetter: The dead letter, or nil if not found.
+//   - error: Always nil.
+func (s *Store) GetWebhookDeadLetter(_ context.Context, id string) (*configv1.WebhookDeadLetter, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	letter, ok := s.deadLetters[id]
+	if !ok {
+		return nil, nil
+	}
+	return proto.Clone(letter).(*configv1.WebhookDeadLetter), nil
+}
+
+// DeleteWebhookDeadLetter deletes a dead-lettered webhook event.
+//
+// Summary: Deletes a webhook dead letter.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//   - id: string. The ID of the dead letter.
+//
+// Returns:
+//   - error: Always nil.
+//
+// Side Effects:
+//   - Removes the dead letter from the store.
+func (s *Store) DeleteWebhookDeadLetter(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.deadLetters, id)
+	return nil
+}
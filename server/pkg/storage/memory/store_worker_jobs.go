@@ -0,0 +1,169 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"context"
+	"sort"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// workerJobLease is the replica that waits for a job, until when.
+type workerJobLease struct {
+	owner string
+	until string
+}
+
+// SaveWorkerJob saves a job of an asynchronous tool, replacing the one with
+// the same ID.
+//
+// Summary: Stores a worker job in memory.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//   - job: *configv1.WorkerJob. The job to save.
+//
+// Returns:
+//   - error: Always nil.
+//
+// Side Effects:
+//   - Stores a copy of the job.
+func (s *Store) SaveWorkerJob(_ context.Context, job *configv1.WorkerJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.workerJobs[job.GetId()] = proto.Clone(job).(*configv1.WorkerJob)
+	return nil
+}
+
+// ListWorkerJobs retrieves the jobs of the asynchronous tools.
+//
+// Summary: Lists worker jobs, newest first.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//   - limit: int. The maximum number of jobs to return, 0 for all.
+//
+// Returns:
+//   - []*configv1.WorkerJob: The jobs.
+//   - error: Always nil.
+func (s *Store) ListWorkerJobs(_ context.Context, limit int) ([]*configv1.WorkerJob, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]*configv1.WorkerJob, 0, len(s.workerJobs))
+	for _, job := range s.workerJobs {
+		list = append(list, proto.Clone(job).(*configv1.WorkerJob))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].GetCreatedAt() != list[j].GetCreatedAt() {
+			return list[i].GetCreatedAt() > list[j].GetCreatedAt()
+		}
+		return list[i].GetId() > list[j].GetId()
+	})
+	if limit > 0 && len(list) > limit {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+// GetWorkerJob retrieves a job of an asynchronous tool by ID.
+//
+// Summary: Retrieves a worker job.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//   - id: string. The ID of the job.
+//
+// Returns:
+//   - *configv1.WorkerJob: The job, or nil if not found.
+//   - error: Always nil.
+func (s *Store) GetWorkerJob(_ context.Context, id string) (*configv1.WorkerJob, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	job, ok := s.workerJobs[id]
+	if !ok {
+		return nil, nil
+	}
+	return proto.Clone(job).(*configv1.WorkerJob), nil
+}
+
+// ClaimWorkerJob leases a job that is not done to a replica, if the replica
+// holds the lease already or the lease expired.
+//
+// Summary: Claims or renews the lease of a worker job.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//   - id: string. The ID of the job.
+//   - owner: string. The ID of the claiming replica.
+//   - now: string. The current time, in the RFC 3339 format of the jobs.
+//   - leaseUntil: string. The end of the lease, in the same format.
+//
+// Returns:
+//   - bool: True if the replica holds the lease now.
+//   - error: Always nil.
+//
+// Side Effects:
+//   - Updates the lease of the job.
+func (s *Store) ClaimWorkerJob(_ context.Context, id, owner, now, leaseUntil string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.workerJobs[id]
+	if !ok || job.GetCompletedAt() != "" {
+		return false, nil
+	}
+	lease := s.workerJobLeases[id]
+	if lease.owner != owner && lease.until >= now {
+		return false, nil
+	}
+	s.workerJobLeases[id] = workerJobLease{owner: owner, until: leaseUntil}
+	return true, nil
+}
+
+// DeleteWorkerJob deletes a job of an asynchronous tool.
+//
+// Summary: Deletes a worker job.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//   - id: string. The ID of the job.
+//
+// Returns:
+//   - error: Always nil.
+//
+// Side Effects:
+//   - Removes the job from the store.
+func (s *Store) DeleteWorkerJob(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.workerJobs, id)
+	delete(s.workerJobLeases, id)
+	return nil
+}
+
+// DeleteWorkerJobsCompletedBefore deletes the jobs done before a time.
+//
+// Summary: Prunes the expired worker jobs.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//   - before: string. The time, in the RFC 3339 format of the jobs.
+//
+// Returns:
+//   - error: Always nil.
+//
+// Side Effects:
+//   - Removes the jobs from the store.
+func (s *Store) DeleteWorkerJobsCompletedBefore(_ context.Context, before string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, job := range s.workerJobs {
+		if job.GetCompletedAt() != "" && job.GetCompletedAt() < before {
+			delete(s.workerJobs, id)
+			delete(s.workerJobLeases, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,405 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package migrate applies versioned schema migrations to the SQL stores.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// DefaultTable is the table recording the applied migrations.
+const DefaultTable = "schema_migrations"
+
+// Migration is a versioned change of the schema.
+//
+// Summary: A schema change with its rollback.
+type Migration struct {
+	// Version orders the migrations. Versions start at 1 and are never reused.
+	Version int
+	// Name describes the change, e.g. "create_credentials".
+	Name string
+	// Up applies the change. It may contain several statements.
+	Up string
+	// Down reverts the change.
+	Down string
+}
+
+// Status is the state of a migration in a database.
+//
+// Summary: Whether a migration is applied.
+type Status struct {
+	Version   int       `json:"version"`
+	Name      string    `json:"name"`
+	Applied   bool      `json:"applied"`
+	AppliedAt time.Time `json:"applied_at,omitempty"`
+	// Unknown is true for an applied migration this binary does not know,
+	// i.e. one applied by a newer release.
+	Unknown bool `json:"unknown,omitempty"`
+}
+
+// Dialect holds the SQL that differs between the databases.
+//
+// Summary: Database specific SQL of the migrator.
+type Dialect struct {
+	name string
+	// bind returns the placeholder of the n-th argument, starting at 1.
+	bind func(n int) string
+	// lock is run first in each migration transaction to serialize the
+	// migrators of a database. Empty if the transaction itself serializes
+	// them.
+	lock string
+}
+
+var (
+	// SQLite is the dialect of SQLite. Its write transactions are
+	// serialized by the database lock.
+	SQLite = Dialect{
+		name: "sqlite",
+		bind: func(int) string { return "?" },
+	}
+	// Postgres is the dialect of PostgreSQL. Migrations hold an advisory
+	// lock, so replicas starting together do not apply them twice.
+	Postgres = Dialect{
+		name: "postgres",
+		bind: func(n int) string { return fmt.Sprintf("$%d", n) },
+		lock: "SELECT pg_advisory_xact_lock(120273854623353)", // "mcpany"
+	}
+)
+
+// Migrator applies migrations to a database and records them in the
+// schema_migrations table, or the table set by WithTable.
+//
+// Summary: Versioned schema migrator.
+type Migrator struct {
+	db         *sql.DB
+	dialect    Dialect
+	migrations []Migration
+	table      string
+	logger     *slog.Logger
+}
+
+// Option configures a migrator.
+//
+// Summary: A setting of the migrator.
+type Option func(*Migrator)
+
+// WithTable records the applied migrations in a table other than
+// schema_migrations, so that the schemas of several stores can share a
+// database and be versioned independently.
+//
+// Summary: Sets the table recording the applied migrations.
+//
+// Parameters:
+//   - table: string. The name of the table, a constant of the caller.
+//
+// Returns:
+//   - Option: The option.
+func WithTable(table string) Option {
+	return func(m *Migrator) { m.table = table }
+}
+
+// WithLogger logs the applied and reverted migrations to a logger other
+// than slog.Default.
+//
+// Summary: Sets the logger of the migrator.
+//
+// Parameters:
+//   - logger: *slog.Logger. The logger.
+//
+// Returns:
+//   - Option: The option.
+func WithLogger(logger *slog.Logger) Option {
+	return func(m *Migrator) { m.logger = logger }
+}
+
+// New creates a migrator.
+//
+// Summary: Initializes a migrator.
+//
+// Parameters:
+//   - db: *sql.DB. The database.
+//   - dialect: Dialect. The dialect of the database.
+//   - migrations: []Migration. The migrations, in any order.
+//   - opts: ...Option. The settings of the migrator.
+//
+// Returns:
+//   - *Migrator: The migrator.
+//   - error: An error if a version is not positive or used twice.
+func New(db *sql.DB, dialect Dialect, migrations []Migration, opts ...Option) (*Migrator, error) {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	for i, m := range sorted {
+		if m.Version <= 0 {
+			return nil, fmt.Errorf("migration %q has invalid version %d", m.Name, m.Version)
+		}
+		if i > 0 && sorted[i-1].Version == m.Version {
+			return nil, fmt.Errorf("migration version %d is used by %q and %q", m.Version, sorted[i-1].Name, m.Name)
+		}
+	}
+	m := &Migrator{db: db, dialect: dialect, migrations: sorted, table: DefaultTable, logger: slog.Default()}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m, nil
+}
+
+// Latest returns the version of the last known migration.
+//
+// Summary: Returns the target version of Up.
+//
+// Returns:
+//   - int: The latest version, or 0 without migrations.
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies all pending migrations.
+//
+// Summary: Migrates the database to the latest version.
+//
+// Parameters:
+//   - ctx: context.Context. The context.
+//
+// Returns:
+//   - int: The number of applied migrations.
+//   - error: An error if a migration fails. The migrations before it stay applied.
+//
+// Side Effects:
+//   - Creates the schema_migrations table if needed.
+//   - Logs a warning if the database has migrations this binary does not know.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if n := len(applied); n > 0 && applied[n-1].Version > m.Latest() {
+		m.logger.Warn("Database schema is newer than this release", "driver", m.dialect.name, "database_version", applied[n-1].Version, "latest_known_version", m.Latest())
+	}
+	return m.apply(ctx, applied, m.Latest())
+}
+
+// To applies or reverts migrations until the database is at the version.
+// Unlike Up, it reverts the applied migrations above the version.
+//
+// Summary: Migrates the database up or down to a version.
+//
+// Parameters:
+//   - ctx: context.Context. The context.
+//   - version: int. The target version; 0 reverts all migrations.
+//
+// Returns:
+//   - int: The number of applied or reverted migrations.
+//   - error: An error if a migration fails, or if reverting needs a
+//     migration this binary does not know.
+//
+// Side Effects:
+//   - Each migration runs in its own transaction with its record in
+//     schema_migrations.
+func (m *Migrator) To(ctx context.Context, version int) (int, error) {
+	if version < 0 {
+		return 0, fmt.Errorf("invalid target version %d", version)
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	// Revert the applied migrations above the target, newest first.
+	for i := len(applied) - 1; i >= 0 && applied[i].Version > version; i-- {
+		mig, ok := m.find(applied[i].Version)
+		if !ok {
+			return count, fmt.Errorf("cannot revert migration %d (%s): it is unknown to this release", applied[i].Version, applied[i].Name)
+		}
+		done, err := m.run(ctx, mig, false)
+		if err != nil {
+			return count, err
+		}
+		if done {
+			count++
+		}
+	}
+
+	n, err := m.apply(ctx, applied, version)
+	return count + n, err
+}
+
+// apply applies the known migrations up to the version that are not applied.
+func (m *Migrator) apply(ctx context.Context, applied []Status, version int) (int, error) {
+	var count int
+	isApplied := make(map[int]bool, len(applied))
+	for _, s := range applied {
+		isApplied[s.Version] = true
+	}
+	for _, mig := range m.migrations {
+		if mig.Version > version || isApplied[mig.Version] {
+			continue
+		}
+		done, err := m.run(ctx, mig, true)
+		if err != nil {
+			return count, err
+		}
+		if done {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Status returns the known migrations and the applied unknown ones, by
+// version.
+//
+// Summary: Reports which migrations are applied.
+//
+// Parameters:
+//   - ctx: context.Context. The context.
+//
+// Returns:
+//   - []Status: The migrations.
+//   - error: An error if the schema_migrations table cannot be read.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int]Status, len(applied))
+	for _, s := range applied {
+		byVersion[s.Version] = s
+	}
+
+	statuses := make([]Status, 0, len(m.migrations)+len(applied))
+	for _, mig := range m.migrations {
+		s, ok := byVersion[mig.Version]
+		if !ok {
+			s = Status{Version: mig.Version}
+		}
+		s.Name = mig.Name
+		delete(byVersion, mig.Version)
+		statuses = append(statuses, s)
+	}
+	for _, s := range byVersion {
+		s.Unknown = true
+		statuses = append(statuses, s)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+func (m *Migrator) find(version int) (Migration, bool) {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return mig, true
+		}
+	}
+	return Migration{}, false
+}
+
+// applied creates the schema_migrations table if needed and returns the
+// applied migrations by version.
+func (m *Migrator) applied(ctx context.Context) ([]Status, error) {
+	tx, err := m.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.QueryContext(ctx, "SELECT version, name, applied_at FROM "+m.table+" ORDER BY version")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", m.table, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var applied []Status
+	for rows.Next() {
+		s := Status{Applied: true}
+		var appliedAt string
+		if err := rows.Scan(&s.Version, &s.Name, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", m.table, err)
+		}
+		s.AppliedAt, _ = time.Parse(time.RFC3339, appliedAt)
+		applied = append(applied, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", m.table, err)
+	}
+	return applied, tx.Commit()
+}
+
+// run applies or reverts a migration unless another migrator did it since
+// the applied migrations were read.
+func (m *Migrator) run(ctx context.Context, mig Migration, up bool) (bool, error) {
+	direction, done := "apply", "Applied"
+	if !up {
+		direction, done = "revert", "Reverted"
+	}
+	tx, err := m.begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var count int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+m.table+" WHERE version = "+m.dialect.bind(1), mig.Version).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", m.table, err)
+	}
+	if (count > 0) == up {
+		return false, nil
+	}
+
+	statement := mig.Up
+	if !up {
+		statement = mig.Down
+	}
+	if statement != "" {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return false, fmt.Errorf("failed to %s migration %d (%s): %w", direction, mig.Version, mig.Name, err)
+		}
+	}
+	if up {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (version, name, applied_at) VALUES (%s, %s, %s)", m.table, m.dialect.bind(1), m.dialect.bind(2), m.dialect.bind(3)),
+			mig.Version, mig.Name, time.Now().UTC().Format(time.RFC3339))
+	} else {
+		_, err = tx.ExecContext(ctx, "DELETE FROM "+m.table+" WHERE version = "+m.dialect.bind(1), mig.Version)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to record migration %d (%s): %w", mig.Version, mig.Name, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to %s migration %d (%s): %w", direction, mig.Version, mig.Name, err)
+	}
+	m.logger.Info(done+" schema migration", "driver", m.dialect.name, "table", m.table, "version", mig.Version, "name", mig.Name)
+	return true, nil
+}
+
+// begin starts a transaction holding the migration lock, with the
+// schema_migrations table created.
+func (m *Migrator) begin(ctx context.Context) (*sql.Tx, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin migration transaction: %w", err)
+	}
+	if m.dialect.lock != "" {
+		if _, err := tx.ExecContext(ctx, m.dialect.lock); err != nil {
+			_ = tx.Rollback()
+			return nil, fmt.Errorf("failed to lock schema: %w", err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+m.table+` (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TEXT NOT NULL
+	)`); err != nil {
+		_ = tx.Rollback()
+		return nil, fmt.Errorf("failed to create %s table: %w", m.table, err)
+	}
+	return tx, nil
+}
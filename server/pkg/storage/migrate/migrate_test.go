@@ -0,0 +1,178 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+var testMigrations = []Migration{
+	{Version: 2, Name: "add_email", Up: "ALTER TABLE users ADD COLUMN email TEXT", Down: "ALTER TABLE users DROP COLUMN email"},
+	{Version: 1, Name: "create_users", Up: "CREATE TABLE users (id TEXT PRIMARY KEY); CREATE INDEX idx_users_id ON users(id);", Down: "DROP TABLE users"},
+}
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "migrate.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func tableHasColumn(t *testing.T, db *sql.DB, table, column string) bool {
+	t.Helper()
+	var n int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&n))
+	return n > 0
+}
+
+func versions(statuses []Status) (applied []int, pending []int) {
+	for _, s := range statuses {
+		if s.Applied {
+			applied = append(applied, s.Version)
+		} else {
+			pending = append(pending, s.Version)
+		}
+	}
+	return applied, pending
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	m, err := New(db, SQLite, testMigrations)
+	require.NoError(t, err)
+	assert.Equal(t, 2, m.Latest())
+
+	statuses, err := m.Status(ctx)
+	require.NoError(t, err)
+	applied, pending := versions(statuses)
+	assert.Empty(t, applied)
+	assert.Equal(t, []int{1, 2}, pending)
+	assert.Equal(t, "create_users", statuses[0].Name)
+
+	n, err := m.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.True(t, tableHasColumn(t, db, "users", "email"))
+
+	n, err = m.Up(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n, "applied migrations are not applied again")
+
+	statuses, err = m.Status(ctx)
+	require.NoError(t, err)
+	applied, pending = versions(statuses)
+	assert.Equal(t, []int{1, 2}, applied)
+	assert.Empty(t, pending)
+	assert.False(t, statuses[1].AppliedAt.IsZero())
+
+	n, err = m.To(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.True(t, tableHasColumn(t, db, "users", "id"))
+	assert.False(t, tableHasColumn(t, db, "users", "email"))
+
+	n, err = m.To(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.False(t, tableHasColumn(t, db, "users", "id"))
+
+	n, err = m.To(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	_, err = m.To(ctx, -1)
+	assert.Error(t, err)
+}
+
+func TestMigrator_UnknownVersions(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	newer, err := New(db, SQLite, append(append([]Migration(nil), testMigrations...),
+		Migration{Version: 3, Name: "create_teams", Up: "CREATE TABLE teams (id TEXT)", Down: "DROP TABLE teams"}))
+	require.NoError(t, err)
+	_, err = newer.Up(ctx)
+	require.NoError(t, err)
+
+	// An older release keeps working with the newer schema.
+	older, err := New(db, SQLite, testMigrations)
+	require.NoError(t, err)
+	n, err := older.Up(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	statuses, err := older.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 3)
+	assert.True(t, statuses[2].Unknown)
+	assert.Equal(t, "create_teams", statuses[2].Name)
+
+	// But it cannot revert what it does not know.
+	_, err = older.To(ctx, 1)
+	assert.ErrorContains(t, err, "cannot revert migration 3 (create_teams)")
+}
+
+func TestMigrator_FailedMigration(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	m, err := New(db, SQLite, append(append([]Migration(nil), testMigrations...),
+		Migration{Version: 3, Name: "broken", Up: "CREATE TABLE teams (id TEXT); SELECT * FROM missing"}))
+	require.NoError(t, err)
+
+	n, err := m.Up(ctx)
+	assert.ErrorContains(t, err, "failed to apply migration 3 (broken)")
+	assert.Equal(t, 2, n, "the migrations before the failed one stay applied")
+
+	var tables int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'teams'").Scan(&tables))
+	assert.Zero(t, tables, "the failed migration is rolled back")
+
+	statuses, err := m.Status(ctx)
+	require.NoError(t, err)
+	applied, pending := versions(statuses)
+	assert.Equal(t, []int{1, 2}, applied)
+	assert.Equal(t, []int{3}, pending)
+}
+
+func TestMigrator_WithTable(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	m, err := New(db, SQLite, testMigrations)
+	require.NoError(t, err)
+	_, err = m.Up(ctx)
+	require.NoError(t, err)
+
+	// A second schema in the same database is versioned on its own.
+	other, err := New(db, SQLite, []Migration{
+		{Version: 1, Name: "create_teams", Up: "CREATE TABLE teams (id TEXT)", Down: "DROP TABLE teams"},
+	}, WithTable("team_migrations"))
+	require.NoError(t, err)
+	n, err := other.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n, "the versions of the first schema do not count")
+
+	statuses, err := m.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2, "the versions of the second schema are not unknown to the first")
+	statuses, err = other.Status(ctx)
+	require.NoError(t, err)
+	applied, _ := versions(statuses)
+	assert.Equal(t, []int{1}, applied)
+}
+
+func TestNew_InvalidVersions(t *testing.T) {
+	_, err := New(nil, SQLite, []Migration{{Version: 0, Name: "zero"}})
+	assert.ErrorContains(t, err, "invalid version")
+
+	_, err = New(nil, SQLite, []Migration{{Version: 1, Name: "a"}, {Version: 1, Name: "b"}})
+	assert.ErrorContains(t, err, "migration version 1 is used by")
+}
@@ -31,6 +31,7 @@ type DB struct {
 //
 // Side Effects:
 //   - Opens a network connection to the database.
+//   - Applies the pending schema migrations.
 func NewDB(dsn string) (*DB, error) {
 	return NewDBWithDriver("postgres", dsn)
 }
@@ -49,8 +50,38 @@ func NewDB(dsn string) (*DB, error) {
 //
 // Side Effects:
 //   - Opens a network connection to the database.
-//   - Initializes the schema.
+//   - Applies the pending schema migrations.
 func NewDBWithDriver(driver, dsn string) (*DB, error) {
+	db, err := openWithDriver(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.migrateUp(context.Background()); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to init schema: %w", err)
+	}
+	return db, nil
+}
+
+// Open opens a PostgreSQL database connection without migrating its schema,
+// e.g. to report or change its schema version.
+//
+// Summary: Opens a PostgreSQL database connection.
+//
+// Parameters:
+//   - dsn (string): The data source name (connection string).
+//
+// Returns:
+//   - *DB: The database connection.
+//   - error: An error if the connection fails.
+//
+// Side Effects:
+//   - Opens a network connection to the database.
+func Open(dsn string) (*DB, error) {
+	return openWithDriver("postgres", dsn)
+}
+
+func openWithDriver(driver, dsn string) (*DB, error) {
 	db, err := sql.Open(driver, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open postgres db: %w", err)
@@ -68,11 +99,6 @@ func NewDBWithDriver(driver, dsn string) (*DB, error) {
 		return nil, fmt.Errorf("failed to ping postgres db: %w", err)
 	}
 
-	if err := initSchema(db); err != nil {
-		_ = db.Close()
-		return nil, fmt.Errorf("failed to init schema: %w", err)
-	}
-
 	return &DB{db}, nil
 }
 
@@ -89,97 +115,27 @@ func NewDBWithDriver(driver, dsn string) (*DB, error) {
 //
 // Side Effects:
 //   - Pings the database.
-//   - Initializes the schema.
+//   - Applies the pending schema migrations.
 func NewDBFromSQLDB(db *sql.DB) (*DB, error) {
 	if err := db.PingContext(context.Background()); err != nil {
 		return nil, fmt.Errorf("failed to ping db: %w", err)
 	}
 
-	if err := initSchema(db); err != nil {
+	pgDB := &DB{db}
+	if err := pgDB.migrateUp(context.Background()); err != nil {
 		return nil, fmt.Errorf("failed to init schema: %w", err)
 	}
 
-	return &DB{db}, nil
+	return pgDB, nil
 }
 
-func initSchema(db *sql.DB) error {
-	query := `
-	CREATE TABLE IF NOT EXISTS upstream_services (
-		id TEXT PRIMARY KEY,
-		name TEXT UNIQUE NOT NULL,
-		config_json TEXT NOT NULL,
-		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE IF NOT EXISTS users (
-		id TEXT PRIMARY KEY,
-		config_json TEXT NOT NULL,
-		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE IF NOT EXISTS global_settings (
-		id INTEGER PRIMARY KEY CHECK (id = 1),
-		config_json TEXT NOT NULL,
-		updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE IF NOT EXISTS secrets (
-		id TEXT PRIMARY KEY,
-		name TEXT NOT NULL,
-		key TEXT NOT NULL,
-		config_json TEXT NOT NULL,
-		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE IF NOT EXISTS profile_definitions (
-		id TEXT PRIMARY KEY,
-		name TEXT UNIQUE NOT NULL,
-		config_json TEXT NOT NULL,
-		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE IF NOT EXISTS service_collections (
-		id TEXT PRIMARY KEY,
-		name TEXT UNIQUE NOT NULL,
-		config_json TEXT NOT NULL,
-		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE IF NOT EXISTS user_tokens (
-		user_id TEXT NOT NULL,
-		service_id TEXT NOT NULL,
-		config_json TEXT NOT NULL,
-		updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
-		PRIMARY KEY (user_id, service_id)
-	);
-
-	CREATE TABLE IF NOT EXISTS service_templates (
-		id TEXT PRIMARY KEY,
-		name TEXT NOT NULL,
-		config_json TEXT NOT NULL,
-		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE IF NOT EXISTS logs (
-		id TEXT PRIMARY KEY,
-		timestamp TIMESTAMPTZ NOT NULL,
-		level TEXT NOT NULL,
-		source TEXT,
-		message TEXT NOT NULL,
-		metadata_json TEXT,
-		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
-	);
-	CREATE INDEX IF NOT EXISTS idx_logs_timestamp ON logs(timestamp);
-	`
-	_, err := db.ExecContext(context.Background(), query)
+// migrateUp applies the pending schema migrations. Replicas starting
+// together apply each migration once, under an advisory lock.
+func (db *DB) migrateUp(ctx context.Context) error {
+	m, err := db.Migrator()
 	if err != nil {
-		return fmt.Errorf("failed to create tables: %w", err)
+		return err
 	}
-	return nil
+	_, err = m.Up(ctx)
+	return err
 }
@@ -0,0 +1,208 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/logging"
+)
+
+// DefaultLeaderCheckInterval is how often a replica runs for the leadership,
+// or checks that it still holds it.
+const DefaultLeaderCheckInterval = 5 * time.Second
+
+// leaderLockKey is the key of the advisory lock held by the leader. It
+// differs from the keys of the migrations and of the log pruning.
+const leaderLockKey int64 = 0x6d6370616e79 + 2
+
+// missedLeaderChecks is the number of failed checks after which the leader
+// stops leading, even if it cannot tell whether its session is gone.
+const missedLeaderChecks = 3
+
+// LeaderElection elects one of the replicas sharing the database as the
+// leader of the cluster, which runs the singleton duties. The leader holds a
+// session-level advisory lock on a connection it keeps open; when it stops
+// or loses the connection, the lock is released and another replica takes it
+// over. It implements cluster.Leader.
+//
+// Summary: Elects the leader of the replicas sharing a PostgreSQL database.
+type LeaderElection struct {
+	db       *sql.DB
+	interval time.Duration
+
+	mu sync.Mutex
+	// conn holds the lock while this replica leads.
+	conn *sql.Conn
+	// leaseUntil is when the leadership ends unless a check renews it.
+	leaseUntil time.Time
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+// NewLeaderElection creates the leader election of the replicas sharing the
+// database.
+//
+// Parameters:
+//   - interval: time.Duration. How often the replica runs for the leadership,
+//     or 0 for DefaultLeaderCheckInterval.
+//
+// Returns:
+//   - *LeaderElection: The election, not yet started.
+func (db *DB) NewLeaderElection(interval time.Duration) *LeaderElection {
+	if interval <= 0 {
+		interval = DefaultLeaderCheckInterval
+	}
+	return &LeaderElection{db: db.DB, interval: interval}
+}
+
+// Start runs for the leadership, and keeps running for it, or checking it
+// still holds it, until Stop.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the first run.
+//
+// Returns:
+//   - error: Always nil; a failed run is logged and retried.
+//
+// Side Effects:
+//   - Starts a goroutine, which holds a database connection while it leads.
+func (e *LeaderElection) Start(ctx context.Context) error {
+	e.mu.Lock()
+	if e.cancel != nil {
+		e.mu.Unlock()
+		return nil
+	}
+	loopCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	e.cancel = cancel
+	e.done = make(chan struct{})
+	done := e.done
+	e.mu.Unlock()
+
+	e.check(ctx)
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-loopCtx.Done():
+				return
+			case <-ticker.C:
+				e.check(loopCtx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops running for the leadership and releases it, so that another
+// replica takes over at once.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the wait and the release.
+//
+// Returns:
+//   - error: An error if the context expires or the lock cannot be released.
+func (e *LeaderElection) Stop(ctx context.Context) error {
+	e.mu.Lock()
+	cancel, done := e.cancel, e.done
+	e.cancel, e.done = nil, nil
+	e.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	e.mu.Lock()
+	conn := e.conn
+	e.conn, e.leaseUntil = nil, time.Time{}
+	e.mu.Unlock()
+	if conn == nil {
+		return nil
+	}
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", leaderLockKey); err != nil {
+		discard(conn)
+		return fmt.Errorf("failed to release the leadership: %w", err)
+	}
+	return conn.Close()
+}
+
+// IsLeader reports whether this replica holds the lock, as of a check that
+// is recent enough.
+//
+// Returns:
+//   - bool: True if this replica leads the cluster.
+func (e *LeaderElection) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.conn != nil && time.Now().Before(e.leaseUntil)
+}
+
+// check runs for the leadership, or checks that the session holding the lock
+// is alive.
+func (e *LeaderElection) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, e.interval)
+	defer cancel()
+	now := time.Now()
+
+	e.mu.Lock()
+	conn := e.conn
+	e.mu.Unlock()
+
+	if conn != nil {
+		if _, err := conn.ExecContext(ctx, "SELECT 1"); err != nil {
+			logging.GetLogger().Warn("Failed to check the leadership", "error", err)
+			e.mu.Lock()
+			defer e.mu.Unlock()
+			if !time.Now().Before(e.leaseUntil) {
+				// The session may be gone, and the lock with it.
+				logging.GetLogger().Warn("This instance no longer leads the cluster")
+				discard(e.conn)
+				e.conn = nil
+			}
+			return
+		}
+		e.mu.Lock()
+		e.leaseUntil = now.Add(missedLeaderChecks * e.interval)
+		e.mu.Unlock()
+		return
+	}
+
+	conn, err := e.db.Conn(ctx)
+	if err != nil {
+		logging.GetLogger().Warn("Failed to run for the leadership", "error", err)
+		return
+	}
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", leaderLockKey).Scan(&acquired); err != nil || !acquired {
+		if err != nil {
+			logging.GetLogger().Warn("Failed to run for the leadership", "error", err)
+		}
+		_ = conn.Close()
+		return
+	}
+	e.mu.Lock()
+	e.conn = conn
+	e.leaseUntil = now.Add(missedLeaderChecks * e.interval)
+	e.mu.Unlock()
+	logging.GetLogger().Info("This instance now leads the cluster")
+}
+
+// discard ends the session of a connection rather than returning it to the
+// pool, so that the lock it may hold is released.
+func discard(conn *sql.Conn) {
+	_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+	_ = conn.Close()
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaderElection(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	// Another replica holds the lock.
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").WithArgs(leaderLockKey).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(false))
+	election := (&DB{db}).NewLeaderElection(time.Hour)
+	election.check(ctx)
+	assert.False(t, election.IsLeader())
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").WithArgs(leaderLockKey).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+	require.NoError(t, election.Start(ctx))
+	assert.True(t, election.IsLeader())
+
+	// The leader checks its session and keeps the lock.
+	mock.ExpectExec("SELECT 1").WillReturnResult(sqlmock.NewResult(0, 0))
+	election.check(ctx)
+	assert.True(t, election.IsLeader())
+
+	// Stopping releases the lock.
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(leaderLockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	require.NoError(t, election.Stop(ctx))
+	assert.False(t, election.IsLeader())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLeaderElection_LostSession(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").WithArgs(leaderLockKey).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+	election := (&DB{db}).NewLeaderElection(time.Hour)
+	election.check(ctx)
+	require.True(t, election.IsLeader())
+
+	// A failed check keeps the leadership until the lease runs out.
+	mock.ExpectExec("SELECT 1").WillReturnError(errors.New("connection reset"))
+	election.check(ctx)
+	assert.True(t, election.IsLeader())
+
+	// Then the replica stops leading, and ends the session rather than
+	// pooling a connection that may still hold the lock.
+	mock.ExpectExec("SELECT 1").WillReturnError(errors.New("connection reset"))
+	mock.ExpectClose()
+	election.mu.Lock()
+	election.leaseUntil = time.Now()
+	election.mu.Unlock()
+	election.check(ctx)
+	assert.False(t, election.IsLeader())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
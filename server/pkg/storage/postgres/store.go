@@ -13,6 +13,7 @@ import (
 
 	configv1 "github.com/mcpany/core/proto/config/v1"
 	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/storage"
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
@@ -23,6 +24,10 @@ type Store struct {
 	db *DB
 }
 
+// The application enables log persistence, secret usage tracking and key
+// rotation only on a storage.Storage, so the store must stay complete.
+var _ storage.Storage = (*Store)(nil)
+
 // NewStore creates a new PostgreSQL store.
 //
 // Summary: Creates a new PostgreSQL store.
@@ -55,6 +60,22 @@ func (s *Store) Close() error {
 	return s.db.Close()
 }
 
+// Ping checks that the database can be reached.
+//
+// Summary: Checks the database connection.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the check.
+//
+// Returns:
+//   - error: An error if the database cannot be reached.
+//
+// Side Effects:
+//   - Opens a connection to PostgreSQL if none is idle.
+func (s *Store) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
 // HasConfigSources returns true if the store has configuration sources (e.g., file paths) configured.
 //
 // Summary: Checks if the store has configuration sources.
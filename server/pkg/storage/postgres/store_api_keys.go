@@ -0,0 +1,145 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// API Keys
+
+// ListAPIKeys retrieves all client API keys.
+//
+// Summary: Lists all client API keys.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//
+// Returns:
+//   - []*configv1.ClientApiKey: A list of API keys, ordered by creation time.
+//   - error: An error if the database query fails.
+func (s *Store) ListAPIKeys(ctx context.Context) ([]*configv1.ClientApiKey, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT config_json FROM api_keys ORDER BY created_at, id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query api_keys: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var keys []*configv1.ClientApiKey
+	for rows.Next() {
+		var configJSON []byte
+		if err := rows.Scan(&configJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan api key config: %w", err)
+		}
+
+		var key configv1.ClientApiKey
+		if err := protojson.Unmarshal(configJSON, &key); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal api key: %w", err)
+		}
+		keys = append(keys, &key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return keys, nil
+}
+
+// GetAPIKey retrieves a client API key by ID.
+//
+// Summary: Retrieves a client API key by ID.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - id (string): The key ID.
+//
+// Returns:
+//   - *configv1.ClientApiKey: The API key.
+//   - error: An error if retrieval fails.
+//
+// Errors:
+//   - Returns nil, nil if the key is not found.
+//   - Returns an error if database query fails.
+func (s *Store) GetAPIKey(ctx context.Context, id string) (*configv1.ClientApiKey, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT config_json FROM api_keys WHERE id = $1", id)
+
+	var configJSON []byte
+	if err := row.Scan(&configJSON); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("failed to scan api key: %w", err)
+	}
+
+	var key configv1.ClientApiKey
+	if err := protojson.Unmarshal(configJSON, &key); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal api key: %w", err)
+	}
+	return &key, nil
+}
+
+// SaveAPIKey saves a client API key.
+//
+// Summary: Persists a client API key.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - key (*configv1.ClientApiKey): The API key to save.
+//
+// Returns:
+//   - error: An error if saving fails.
+//
+// Errors:
+//   - Returns an error if the key ID is missing or database write fails.
+//
+// Side Effects:
+//   - Inserts or updates the api_keys table.
+func (s *Store) SaveAPIKey(ctx context.Context, key *configv1.ClientApiKey) error {
+	if key.GetId() == "" {
+		return fmt.Errorf("api key ID is required")
+	}
+
+	opts := protojson.MarshalOptions{UseProtoNames: true}
+	configJSON, err := opts.Marshal(key)
+	if err != nil {
+		return fmt.Errorf("failed to marshal api key: %w", err)
+	}
+
+	query := `
+	INSERT INTO api_keys (id, name, config_json, updated_at)
+	VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
+	ON CONFLICT(id) DO UPDATE SET
+		name = excluded.name,
+		config_json = excluded.config_json,
+		updated_at = excluded.updated_at;
+	`
+	if _, err := s.db.ExecContext(ctx, query, key.GetId(), key.GetName(), string(configJSON)); err != nil {
+		return fmt.Errorf("failed to save api key: %w", err)
+	}
+	return nil
+}
+
+// DeleteAPIKey deletes a client API key by ID.
+//
+// Summary: Deletes a client API key.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - id (string): The key ID to delete.
+//
+// Returns:
+//   - error: An error if deletion fails.
+//
+// Side Effects:
+//   - Deletes the row from the api_keys table.
+func (s *Store) DeleteAPIKey(ctx context.Context, id string) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM api_keys WHERE id = $1", id); err != nil {
+		return fmt.Errorf("failed to delete api key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Config Versions
+
+// SaveConfigVersion records a reload of the configuration.
+//
+// Summary: Persists a configuration version.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - version (*configv1.ConfigVersion): The version to save. Its version number is set to the next one.
+//
+// Returns:
+//   - error: An error if saving fails.
+//
+// Side Effects:
+//   - Inserts a row into the config_versions table and sets the version number of version.
+func (s *Store) SaveConfigVersion(ctx context.Context, version *configv1.ConfigVersion) error {
+	// The version number is the primary key, so it is not duplicated in the JSON.
+	v := proto.Clone(version).(*configv1.ConfigVersion)
+	v.ClearVersion()
+	opts := protojson.MarshalOptions{UseProtoNames: true}
+	configJSON, err := opts.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config version: %w", err)
+	}
+
+	query := `
+	INSERT INTO config_versions (version, config_json)
+	SELECT COALESCE(MAX(version), 0) + 1, $1 FROM config_versions
+	RETURNING version;
+	`
+	var number int64
+	if err := s.db.QueryRowContext(ctx, query, string(configJSON)).Scan(&number); err != nil {
+		return fmt.Errorf("failed to save config version: %w", err)
+	}
+	version.SetVersion(number)
+	return nil
+}
+
+// ListConfigVersions retrieves the most recent configuration versions.
+//
+// Summary: Lists configuration versions, newest first.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - limit (int): The maximum number of versions to retrieve, or 0 for all.
+//
+// Returns:
+//   - []*configv1.ConfigVersion: The versions, newest first.
+//   - error: An error if the database query fails.
+func (s *Store) ListConfigVersions(ctx context.Context, limit int) ([]*configv1.ConfigVersion, error) {
+	query := "SELECT version, config_json FROM config_versions ORDER BY version DESC"
+	args := []any{}
+	if limit > 0 {
+		query += " LIMIT $1"
+		args = append(args, limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query config_versions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var versions []*configv1.ConfigVersion
+	for rows.Next() {
+		v, err := scanConfigVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return versions, nil
+}
+
+// GetConfigVersion retrieves a configuration version by number.
+//
+// Summary: Retrieves a configuration version.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - version (int64): The version number.
+//
+// Returns:
+//   - *configv1.ConfigVersion: The version.
+//   - error: An error if retrieval fails.
+//
+// Errors:
+//   - Returns nil, nil if the version is not found.
+//   - Returns an error if database query fails.
+func (s *Store) GetConfigVersion(ctx context.Context, version int64) (*configv1.ConfigVersion, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT version, config_json FROM config_versions WHERE version = $1", version)
+	v, err := scanConfigVersion(row)
+	if err == sql.ErrNoRows {
+		return nil, nil // Not found
+	}
+	return v, err
+}
+
+// PruneConfigVersions deletes all but the most recent configuration versions.
+//
+// Summary: Deletes old configuration versions.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - keep (int): The number of versions to keep.
+//
+// Returns:
+//   - error: An error if deletion fails.
+//
+// Side Effects:
+//   - Deletes the older rows from the config_versions table.
+func (s *Store) PruneConfigVersions(ctx context.Context, keep int) error {
+	query := `
+	DELETE FROM config_versions WHERE version NOT IN (
+		SELECT version FROM config_versions ORDER BY version DESC LIMIT $1
+	);
+	`
+	if _, err := s.db.ExecContext(ctx, query, max(keep, 0)); err != nil {
+		return fmt.Errorf("failed to prune config versions: %w", err)
+	}
+	return nil
+}
+
+func scanConfigVersion(row interface{ Scan(dest ...any) error }) (*configv1.ConfigVersion, error) {
+	var number int64
+	var configJSON []byte
+	if err := row.Scan(&number, &configJSON); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan config version: %w", err)
+	}
+
+	var v configv1.ConfigVersion
+	if err := protojson.Unmarshal(configJSON, &v); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config version: %w", err)
+	}
+	v.SetVersion(number)
+	return &v, nil
+}
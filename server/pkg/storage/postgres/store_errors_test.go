@@ -365,10 +365,18 @@ func TestNewDBFromSQLDB_SchemaError(t *testing.T) {
 	defer db.Close()
 
 	mock.ExpectPing()
+	expectMigrationTx(mock)
+	mock.ExpectQuery("SELECT version, name, applied_at FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "name", "applied_at"}))
+	mock.ExpectCommit()
+	expectMigrationTx(mock)
+	mock.ExpectQuery("SELECT COUNT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 	mock.ExpectExec("CREATE TABLE IF NOT EXISTS upstream_services").WillReturnError(errors.New("schema error"))
+	mock.ExpectRollback()
 
 	pgDB, err := NewDBFromSQLDB(db)
 	assert.Error(t, err)
 	assert.Nil(t, pgDB)
 	assert.Contains(t, err.Error(), "failed to init schema")
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -0,0 +1,124 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/metrics"
+)
+
+const (
+	// logsSizeStep is the share of the remaining logs deleted per round
+	// while the logs exceed their size limit.
+	logsSizeStep = 10
+	// pruneLockID is the key of the advisory lock held while the logs are
+	// pruned, so that a single replica prunes the shared table at a time.
+	pruneLockID int64 = 0x6d6370616e79 + 1
+)
+
+var metricLogsPrunedRows = []string{"logs", "pruned_rows"}
+
+// Log Retention
+
+// PruneLogs deletes the oldest persisted logs that exceed the retention
+// limits. When several replicas share the database, the replicas that find
+// another one pruning skip the round.
+//
+// Summary: Applies the retention limits to the persisted logs.
+//
+// Parameters:
+//   - ctx: context.Context. The context for the request.
+//   - config: *configv1.RetentionConfig. The retention limits. max_size_bytes
+//     limits the size of the log rows, not of the whole database.
+//
+// Returns:
+//   - int64: The number of deleted logs.
+//   - error: An error if the logs cannot be pruned.
+//
+// Errors:
+//   - Returns error if the transaction, the lock or a DELETE query fails.
+//
+// Side Effects:
+//   - Deletes rows from the logs table. Space is reclaimed by autovacuum.
+//   - Increments the logs_pruned_rows metric.
+func (s *Store) PruneLogs(ctx context.Context, config *configv1.RetentionConfig) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var locked bool
+	if err := tx.QueryRowContext(ctx, "SELECT pg_try_advisory_xact_lock($1)", pruneLockID).Scan(&locked); err != nil {
+		return 0, fmt.Errorf("failed to lock logs: %w", err)
+	}
+	if !locked {
+		return 0, nil
+	}
+
+	var pruned int64
+	if maxAge := config.GetMaxAge().AsDuration(); maxAge > 0 {
+		n, err := deleteLogs(ctx, tx, "DELETE FROM logs WHERE timestamp < $1", time.Now().Add(-maxAge).UTC())
+		if err != nil {
+			return 0, fmt.Errorf("failed to delete expired logs: %w", err)
+		}
+		pruned += n
+	}
+	if maxRows := config.GetMaxRows(); maxRows > 0 {
+		n, err := deleteLogs(ctx, tx, "DELETE FROM logs WHERE id IN (SELECT id FROM logs ORDER BY timestamp DESC OFFSET $1)", maxRows)
+		if err != nil {
+			return 0, fmt.Errorf("failed to delete excess logs: %w", err)
+		}
+		pruned += n
+	}
+	if maxSize := config.GetMaxSizeBytes(); maxSize > 0 {
+		n, err := pruneLogsToSize(ctx, tx, maxSize)
+		if err != nil {
+			return 0, err
+		}
+		pruned += n
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit pruned logs: %w", err)
+	}
+	if pruned > 0 {
+		metrics.IncrCounter(metricLogsPrunedRows, float32(pruned))
+	}
+	return pruned, nil
+}
+
+// pruneLogsToSize deletes the oldest logs while the size of the log rows
+// exceeds maxSize. The size of the table itself only shrinks when it is
+// vacuumed, so the rows are measured instead.
+func pruneLogsToSize(ctx context.Context, tx *sql.Tx, maxSize int64) (int64, error) {
+	var pruned int64
+	for {
+		var size, count int64
+		if err := tx.QueryRowContext(ctx, "SELECT COALESCE(SUM(pg_column_size(l.*)), 0), COUNT(*) FROM logs l").Scan(&size, &count); err != nil {
+			return pruned, fmt.Errorf("failed to read logs size: %w", err)
+		}
+		if size <= maxSize || count == 0 {
+			return pruned, nil
+		}
+		n, err := deleteLogs(ctx, tx, "DELETE FROM logs WHERE id IN (SELECT id FROM logs ORDER BY timestamp ASC LIMIT $1)", max(count/logsSizeStep, 1))
+		if err != nil {
+			return pruned, fmt.Errorf("failed to delete oldest logs: %w", err)
+		}
+		pruned += n
+	}
+}
+
+func deleteLogs(ctx context.Context, tx *sql.Tx, query string, args ...any) (int64, error) {
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestPostgresStore_PruneLogs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	store := NewStore(&DB{db})
+	ctx := context.Background()
+
+	expectLock := func(locked bool) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT pg_try_advisory_xact_lock").
+			WithArgs(pruneLockID).
+			WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(locked))
+	}
+
+	t.Run("AllLimits", func(t *testing.T) {
+		expectLock(true)
+		mock.ExpectExec("DELETE FROM logs WHERE timestamp <").
+			WithArgs(sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec("DELETE FROM logs WHERE id IN \\(SELECT id FROM logs ORDER BY timestamp DESC OFFSET").
+			WithArgs(int64(100)).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectQuery("SELECT COALESCE\\(SUM\\(pg_column_size").
+			WillReturnRows(sqlmock.NewRows([]string{"size", "count"}).AddRow(4096, 40))
+		mock.ExpectExec("DELETE FROM logs WHERE id IN \\(SELECT id FROM logs ORDER BY timestamp ASC LIMIT").
+			WithArgs(int64(4)).
+			WillReturnResult(sqlmock.NewResult(0, 4))
+		mock.ExpectQuery("SELECT COALESCE\\(SUM\\(pg_column_size").
+			WillReturnRows(sqlmock.NewRows([]string{"size", "count"}).AddRow(1000, 36))
+		mock.ExpectCommit()
+
+		pruned, err := store.PruneLogs(ctx, configv1.RetentionConfig_builder{
+			MaxAge:       durationpb.New(24 * time.Hour),
+			MaxRows:      proto.Int64(100),
+			MaxSizeBytes: proto.Int64(1024),
+		}.Build())
+		require.NoError(t, err)
+		assert.Equal(t, int64(9), pruned)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("LockedByAnotherReplica", func(t *testing.T) {
+		expectLock(false)
+		mock.ExpectRollback()
+
+		pruned, err := store.PruneLogs(ctx, configv1.RetentionConfig_builder{MaxRows: proto.Int64(1)}.Build())
+		require.NoError(t, err)
+		assert.Zero(t, pruned)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("DeleteError", func(t *testing.T) {
+		expectLock(true)
+		mock.ExpectExec("DELETE FROM logs").WillReturnError(errors.New("db error"))
+		mock.ExpectRollback()
+
+		pruned, err := store.PruneLogs(ctx, configv1.RetentionConfig_builder{MaxRows: proto.Int64(1)}.Build())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to delete excess logs")
+		assert.Zero(t, pruned)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -127,3 +127,25 @@ func (s *Store) SaveServiceTemplate(ctx context.Context, template *configv1.Serv
 	}
 	return nil
 }
+
+// DeleteServiceTemplate deletes a service template by ID.
+//
+// Summary: Removes a service template from the database.
+//
+// Parameters:
+//   - ctx: context.Context. The request context.
+//   - id: string. The unique identifier of the template to delete.
+//
+// Returns:
+//   - error: An error if the deletion fails.
+//
+// Side Effects:
+//   - Deletes a row from the service_templates table.
+func (s *Store) DeleteServiceTemplate(ctx context.Context, id string) error {
+	query := "DELETE FROM service_templates WHERE id = $1"
+	_, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete service template: %w", err)
+	}
+	return nil
+}
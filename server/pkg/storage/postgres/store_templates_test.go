@@ -142,6 +142,27 @@ func TestPostgresStore_ServiceTemplates(t *testing.T) {
 		assert.Nil(t, got)
 		assert.Contains(t, err.Error(), "failed to query service_templates")
 
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("DeleteServiceTemplate", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM service_templates").
+			WithArgs("test-template").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		require.NoError(t, store.DeleteServiceTemplate(context.Background(), "test-template"))
+
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("DeleteServiceTemplate_DBError", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM service_templates").
+			WithArgs("test-template").
+			WillReturnError(errors.New("db error"))
+
+		err := store.DeleteServiceTemplate(context.Background(), "test-template")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to delete service template")
+
 		require.NoError(t, mock.ExpectationsWereMet())
 	})
 }
@@ -583,17 +583,3 @@ func TestPostgresStore(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
-
-// initSchema test
-func TestInitSchema(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	require.NoError(t, err)
-	defer db.Close()
-
-	mock.ExpectExec("CREATE TABLE IF NOT EXISTS upstream_services").
-		WillReturnResult(sqlmock.NewResult(0, 0))
-
-	err = initSchema(db)
-	require.NoError(t, err)
-	require.NoError(t, mock.ExpectationsWereMet())
-}
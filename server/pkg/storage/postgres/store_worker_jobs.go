@@ -0,0 +1,205 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Worker Jobs
+
+// SaveWorkerJob saves a job of an asynchronous tool, replacing the one with
+// the same ID.
+//
+// Summary: Persists a worker job.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - job (*configv1.WorkerJob): The job to save.
+//
+// Returns:
+//   - error: An error if saving fails.
+//
+// Side Effects:
+//   - Inserts or updates a row in the worker_jobs table.
+func (s *Store) SaveWorkerJob(ctx context.Context, job *configv1.WorkerJob) error {
+	if job.GetId() == "" {
+		return fmt.Errorf("job id is required")
+	}
+	opts := protojson.MarshalOptions{UseProtoNames: true}
+	jobJSON, err := opts.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal worker job: %w", err)
+	}
+
+	query := `
+	INSERT INTO worker_jobs (id, job_json, status, created_at, completed_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)
+	ON CONFLICT(id) DO UPDATE SET
+		job_json = excluded.job_json,
+		status = excluded.status,
+		completed_at = excluded.completed_at,
+		updated_at = excluded.updated_at;
+	`
+	if _, err := s.db.ExecContext(ctx, query, job.GetId(), string(jobJSON), job.GetStatus(), job.GetCreatedAt(), job.GetCompletedAt()); err != nil {
+		return fmt.Errorf("failed to save worker job: %w", err)
+	}
+	return nil
+}
+
+// ListWorkerJobs retrieves the jobs of the asynchronous tools.
+//
+// Summary: Lists worker jobs, newest first.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - limit (int): The maximum number of jobs to return, 0 for all.
+//
+// Returns:
+//   - []*configv1.WorkerJob: The jobs.
+//   - error: An error if the database query fails.
+func (s *Store) ListWorkerJobs(ctx context.Context, limit int) ([]*configv1.WorkerJob, error) {
+	query := "SELECT job_json FROM worker_jobs ORDER BY created_at DESC, id DESC"
+	args := []any{}
+	if limit > 0 {
+		query += " LIMIT $1"
+		args = append(args, limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query worker_jobs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var jobs []*configv1.WorkerJob
+	for rows.Next() {
+		var jobJSON []byte
+		if err := rows.Scan(&jobJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan worker job: %w", err)
+		}
+		var job configv1.WorkerJob
+		if err := protojson.Unmarshal(jobJSON, &job); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal worker job: %w", err)
+		}
+		jobs = append(jobs, &job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return jobs, nil
+}
+
+// GetWorkerJob retrieves a job of an asynchronous tool by ID.
+//
+// Summary: Retrieves a worker job.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - id (string): The ID of the job.
+//
+// Returns:
+//   - *configv1.WorkerJob: The job.
+//   - error: An error if retrieval fails.
+//
+// Errors:
+//   - Returns nil, nil if the job is not found.
+//   - Returns an error if database query fails.
+func (s *Store) GetWorkerJob(ctx context.Context, id string) (*configv1.WorkerJob, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT job_json FROM worker_jobs WHERE id = $1", id)
+
+	var jobJSON []byte
+	if err := row.Scan(&jobJSON); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("failed to scan worker job: %w", err)
+	}
+
+	var job configv1.WorkerJob
+	if err := protojson.Unmarshal(jobJSON, &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal worker job: %w", err)
+	}
+	return &job, nil
+}
+
+// ClaimWorkerJob leases a job that is not done to a replica, if the replica
+// holds the lease already or the lease expired.
+//
+// Summary: Claims or renews the lease of a worker job.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - id (string): The ID of the job.
+//   - owner (string): The ID of the claiming replica.
+//   - now (string): The current time, in the RFC 3339 format of the jobs.
+//   - leaseUntil (string): The end of the lease, in the same format.
+//
+// Returns:
+//   - bool: True if the replica holds the lease now.
+//   - error: An error if the update fails.
+//
+// Side Effects:
+//   - Updates the lease columns of the row in the worker_jobs table.
+func (s *Store) ClaimWorkerJob(ctx context.Context, id, owner, now, leaseUntil string) (bool, error) {
+	// A single conditional update, so that two replicas cannot both claim
+	// the job.
+	query := `
+	UPDATE worker_jobs SET lease_owner = $1, lease_until = $2
+	WHERE id = $3 AND completed_at = '' AND (lease_owner = $4 OR lease_until < $5);
+	`
+	result, err := s.db.ExecContext(ctx, query, owner, leaseUntil, id, owner, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim worker job: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim worker job: %w", err)
+	}
+	return n == 1, nil
+}
+
+// DeleteWorkerJob deletes a job of an asynchronous tool.
+//
+// Summary: Deletes a worker job.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - id (string): The ID of the job.
+//
+// Returns:
+//   - error: An error if deletion fails.
+//
+// Side Effects:
+//   - Deletes the row from the worker_jobs table.
+func (s *Store) DeleteWorkerJob(ctx context.Context, id string) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM worker_jobs WHERE id = $1", id); err != nil {
+		return fmt.Errorf("failed to delete worker job: %w", err)
+	}
+	return nil
+}
+
+// DeleteWorkerJobsCompletedBefore deletes the jobs done before a time.
+//
+// Summary: Prunes the expired worker jobs.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - before (string): The time, in the RFC 3339 format of the jobs.
+//
+// Returns:
+//   - error: An error if deletion fails.
+//
+// Side Effects:
+//   - Deletes the rows from the worker_jobs table.
+func (s *Store) DeleteWorkerJobsCompletedBefore(ctx context.Context, before string) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM worker_jobs WHERE completed_at != '' AND completed_at < $1", before); err != nil {
+		return fmt.Errorf("failed to delete worker jobs: %w", err)
+	}
+	return nil
+}
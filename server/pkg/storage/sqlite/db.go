@@ -32,8 +32,34 @@ type DB struct {
 //
 // Side Effects:
 //   - Creates the database file and directories if they don't exist.
-//   - Initializes the database schema.
+//   - Applies the pending schema migrations.
 func NewDB(path string) (*DB, error) {
+	db, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.migrateUp(context.Background()); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to init schema: %w", err)
+	}
+	return db, nil
+}
+
+// Open opens or creates a SQLite database at the specified path without
+// migrating its schema, e.g. to report or change its schema version.
+//
+// Summary: Opens a SQLite database connection.
+//
+// Parameters:
+//   - path (string): The file path to the SQLite database.
+//
+// Returns:
+//   - *DB: The database connection.
+//   - error: An error if the database cannot be opened.
+//
+// Side Effects:
+//   - Creates the database file and directories if they don't exist.
+func Open(path string) (*DB, error) {
 	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
 		return nil, fmt.Errorf("failed to create db directory: %w", err)
 	}
@@ -43,22 +69,20 @@ func NewDB(path string) (*DB, error) {
 		return nil, fmt.Errorf("failed to open sqlite db: %w", err)
 	}
 
-	if err := initSchema(db); err != nil {
-		_ = db.Close()
-		return nil, fmt.Errorf("failed to init schema: %w", err)
-	}
-
 	// Set pragmas
 	if _, err := db.ExecContext(context.Background(), "PRAGMA journal_mode=WAL;"); err != nil {
+		_ = db.Close()
 		return nil, fmt.Errorf("failed to set WAL mode: %w", err)
 	}
 	// Enable synchronous=NORMAL for better performance.
 	// In WAL mode, this is safe and provides a good balance between durability and performance.
 	// It significantly reduces fsync operations (e.g. from ~700ms to ~380ms per operation).
 	if _, err := db.ExecContext(context.Background(), "PRAGMA synchronous=NORMAL;"); err != nil {
+		_ = db.Close()
 		return nil, fmt.Errorf("failed to set synchronous mode: %w", err)
 	}
 	if _, err := db.ExecContext(context.Background(), "PRAGMA busy_timeout=5000;"); err != nil {
+		_ = db.Close()
 		return nil, fmt.Errorf("failed to set busy_timeout: %w", err)
 	}
 
@@ -70,92 +94,12 @@ func NewDB(path string) (*DB, error) {
 	return &DB{db}, nil
 }
 
-func initSchema(db *sql.DB) error {
-	query := `
-	CREATE TABLE IF NOT EXISTS upstream_services (
-		id TEXT PRIMARY KEY,
-		name TEXT UNIQUE NOT NULL,
-		config_json TEXT NOT NULL,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE IF NOT EXISTS global_settings (
-		id INTEGER PRIMARY KEY CHECK (id = 1),
-		config_json TEXT NOT NULL,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE IF NOT EXISTS secrets (
-		id TEXT PRIMARY KEY,
-		name TEXT NOT NULL,
-		key TEXT NOT NULL,
-		config_json TEXT NOT NULL,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE IF NOT EXISTS users (
-		id TEXT PRIMARY KEY,
-		config_json TEXT NOT NULL,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE IF NOT EXISTS profile_definitions (
-		id TEXT PRIMARY KEY,
-		name TEXT UNIQUE NOT NULL,
-		config_json TEXT NOT NULL,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE IF NOT EXISTS service_collections (
-		id TEXT PRIMARY KEY,
-		name TEXT UNIQUE NOT NULL,
-		config_json TEXT NOT NULL,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE IF NOT EXISTS user_tokens (
-		user_id TEXT NOT NULL,
-		service_id TEXT NOT NULL,
-		config_json TEXT NOT NULL,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		PRIMARY KEY (user_id, service_id)
-	);
-
-	CREATE TABLE IF NOT EXISTS credentials (
-		id TEXT PRIMARY KEY,
-		name TEXT NOT NULL,
-		config_json TEXT NOT NULL,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE IF NOT EXISTS service_templates (
-		id TEXT PRIMARY KEY,
-		name TEXT NOT NULL,
-		config_json TEXT NOT NULL,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE IF NOT EXISTS logs (
-		id TEXT PRIMARY KEY,
-		timestamp DATETIME NOT NULL,
-		level TEXT NOT NULL,
-		source TEXT,
-		message TEXT NOT NULL,
-		metadata_json TEXT,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
-	);
-	CREATE INDEX IF NOT EXISTS idx_logs_timestamp ON logs(timestamp);
-	`
-	_, err := db.ExecContext(context.Background(), query)
+// migrateUp applies the pending schema migrations.
+func (db *DB) migrateUp(ctx context.Context) error {
+	m, err := db.Migrator()
 	if err != nil {
-		return fmt.Errorf("failed to create tables: %w", err)
+		return err
 	}
-	return nil
+	_, err = m.Up(ctx)
+	return err
 }
@@ -0,0 +1,46 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDB_Migrations(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "mcpany.db")
+
+	// A database created before the migrations were versioned.
+	db, err := Open(path)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, "CREATE TABLE upstream_services (id TEXT PRIMARY KEY, name TEXT UNIQUE NOT NULL, config_json TEXT NOT NULL)")
+	require.NoError(t, err)
+	m, err := db.Migrator()
+	require.NoError(t, err)
+	statuses, err := m.Status(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, statuses)
+	assert.False(t, statuses[0].Applied)
+	require.NoError(t, db.Close())
+
+	db, err = NewDB(path)
+	require.NoError(t, err)
+	defer db.Close()
+	m, err = db.Migrator()
+	require.NoError(t, err)
+	statuses, err = m.Status(ctx)
+	require.NoError(t, err)
+	for _, s := range statuses {
+		assert.True(t, s.Applied, "migration %d (%s)", s.Version, s.Name)
+	}
+	assert.Equal(t, m.Latest(), statuses[len(statuses)-1].Version)
+
+	_, err = NewStore(db).ListServices(ctx)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,219 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package sqlite
+
+import (
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/storage/migrate"
+)
+
+// migrations are the schema versions of the SQLite database. Append new
+// migrations; never edit or renumber the released ones, since databases
+// record the versions they applied.
+var migrations = []migrate.Migration{
+	{
+		// The tables created before migrations were versioned. IF NOT EXISTS
+		// adopts the databases created by those releases.
+		Version: 1,
+		Name:    "initial_schema",
+		Up: `
+		CREATE TABLE IF NOT EXISTS upstream_services (
+			id TEXT PRIMARY KEY,
+			name TEXT UNIQUE NOT NULL,
+			config_json TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS global_settings (
+			id INTEGER PRIMARY KEY CHECK (id = 1),
+			config_json TEXT NOT NULL,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS secrets (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			key TEXT NOT NULL,
+			config_json TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS users (
+			id TEXT PRIMARY KEY,
+			config_json TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS profile_definitions (
+			id TEXT PRIMARY KEY,
+			name TEXT UNIQUE NOT NULL,
+			config_json TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS service_collections (
+			id TEXT PRIMARY KEY,
+			name TEXT UNIQUE NOT NULL,
+			config_json TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS user_tokens (
+			user_id TEXT NOT NULL,
+			service_id TEXT NOT NULL,
+			config_json TEXT NOT NULL,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, service_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS credentials (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			config_json TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS service_templates (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			config_json TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS api_keys (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			config_json TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS logs (
+			id TEXT PRIMARY KEY,
+			timestamp DATETIME NOT NULL,
+			level TEXT NOT NULL,
+			source TEXT,
+			message TEXT NOT NULL,
+			metadata_json TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE INDEX IF NOT EXISTS idx_logs_timestamp ON logs(timestamp);
+		`,
+		Down: `
+		DROP TABLE IF EXISTS logs;
+		DROP TABLE IF EXISTS api_keys;
+		DROP TABLE IF EXISTS service_templates;
+		DROP TABLE IF EXISTS credentials;
+		DROP TABLE IF EXISTS user_tokens;
+		DROP TABLE IF EXISTS service_collections;
+		DROP TABLE IF EXISTS profile_definitions;
+		DROP TABLE IF EXISTS users;
+		DROP TABLE IF EXISTS secrets;
+		DROP TABLE IF EXISTS global_settings;
+		DROP TABLE IF EXISTS upstream_services;
+		`,
+	},
+	{
+		// The applied configurations, for the history and rollback of
+		// reloads.
+		Version: 2,
+		Name:    "create_config_versions",
+		Up: `
+		CREATE TABLE IF NOT EXISTS config_versions (
+			version INTEGER PRIMARY KEY,
+			config_json TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+		`,
+		Down: `
+		DROP TABLE IF EXISTS config_versions;
+		`,
+	},
+	{
+		// The last-known tool catalog of each service, served on boot until
+		// the service is discovered again.
+		Version: 3,
+		Name:    "create_tool_catalog_snapshots",
+		Up: `
+		CREATE TABLE IF NOT EXISTS tool_catalog_snapshots (
+			service_id TEXT PRIMARY KEY,
+			snapshot_json TEXT NOT NULL,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+		`,
+		Down: `
+		DROP TABLE IF EXISTS tool_catalog_snapshots;
+		`,
+	},
+	{
+		// The post-call webhook events that failed after their retries,
+		// kept until they are replayed.
+		Version: 4,
+		Name:    "create_webhook_dead_letters",
+		Up: `
+		CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+			id TEXT PRIMARY KEY,
+			letter_json TEXT NOT NULL,
+			failed_at TEXT NOT NULL,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE INDEX IF NOT EXISTS idx_webhook_dead_letters_failed_at ON webhook_dead_letters(failed_at);
+		`,
+		Down: `
+		DROP TABLE IF EXISTS webhook_dead_letters;
+		`,
+	},
+	{
+		// The jobs of the asynchronous tools, resumed after a restart.
+		Version: 5,
+		Name:    "create_worker_jobs",
+		Up: `
+		CREATE TABLE IF NOT EXISTS worker_jobs (
+			id TEXT PRIMARY KEY,
+			job_json TEXT NOT NULL,
+			status TEXT NOT NULL,
+			created_at TEXT NOT NULL,
+			completed_at TEXT NOT NULL DEFAULT '',
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE INDEX IF NOT EXISTS idx_worker_jobs_created_at ON worker_jobs(created_at);
+		CREATE INDEX IF NOT EXISTS idx_worker_jobs_completed_at ON worker_jobs(completed_at);
+		`,
+		Down: `
+		DROP TABLE IF EXISTS worker_jobs;
+		`,
+	},
+	{
+		// The replica that waits for each job, until when, so that only one
+		// replica resumes a job whose replica is gone.
+		Version: 6,
+		Name:    "add_worker_job_leases",
+		Up: `
+		ALTER TABLE worker_jobs ADD COLUMN lease_owner TEXT NOT NULL DEFAULT '';
+		ALTER TABLE worker_jobs ADD COLUMN lease_until TEXT NOT NULL DEFAULT '';
+		`,
+		Down: `
+		ALTER TABLE worker_jobs DROP COLUMN lease_until;
+		ALTER TABLE worker_jobs DROP COLUMN lease_owner;
+		`,
+	},
+}
+
+// Migrator returns the schema migrator of the database.
+//
+// Summary: Creates the migrator of the database.
+//
+// Returns:
+//   - *migrate.Migrator: The migrator.
+//   - error: An error if the migrations are invalid.
+func (db *DB) Migrator() (*migrate.Migrator, error) {
+	return migrate.New(db.DB, migrate.SQLite, migrations, migrate.WithLogger(logging.GetLogger()))
+}
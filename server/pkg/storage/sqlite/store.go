@@ -54,6 +54,22 @@ func (s *Store) Close() error {
 	return s.db.Close()
 }
 
+// Ping checks that the database can be reached.
+//
+// Summary: Checks the database connection.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the check.
+//
+// Returns:
+//   - error: An error if the database cannot be reached.
+//
+// Side Effects:
+//   - Opens a connection to SQLite if none is idle.
+func (s *Store) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
 // HasConfigSources returns true if the store has configuration sources (e.g., file paths) configured.
 //
 // Summary: Checks if the store has configuration sources.
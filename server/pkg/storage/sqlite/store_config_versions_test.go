@@ -0,0 +1,62 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestConfigVersions(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "versions.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	store := NewStore(db)
+	ctx := context.Background()
+
+	for _, actor := range []string{"alice", "bob", "carol"} {
+		v := configv1.ConfigVersion_builder{
+			Actor:  proto.String(actor),
+			Status: configv1.ConfigVersion_STATUS_APPLIED.Enum(),
+			Config: configv1.McpAnyServerConfig_builder{
+				GlobalSettings: configv1.GlobalSettings_builder{McpListenAddress: proto.String(actor)}.Build(),
+			}.Build(),
+		}.Build()
+		require.NoError(t, store.SaveConfigVersion(ctx, v))
+		assert.NotZero(t, v.GetVersion())
+	}
+
+	versions, err := store.ListConfigVersions(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, int64(3), versions[0].GetVersion())
+	assert.Equal(t, "carol", versions[0].GetActor())
+	assert.Equal(t, int64(2), versions[1].GetVersion())
+
+	got, err := store.GetConfigVersion(ctx, 1)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "alice", got.GetConfig().GetGlobalSettings().GetMcpListenAddress())
+
+	got, err = store.GetConfigVersion(ctx, 42)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	require.NoError(t, store.PruneConfigVersions(ctx, 1))
+	versions, err = store.ListConfigVersions(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	assert.Equal(t, int64(3), versions[0].GetVersion())
+
+	// Numbering continues after the newest version.
+	v := configv1.ConfigVersion_builder{Actor: proto.String("dave")}.Build()
+	require.NoError(t, store.SaveConfigVersion(ctx, v))
+	assert.Equal(t, int64(4), v.GetVersion())
+}
@@ -35,6 +35,16 @@ func TestStore_Close(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestStore_Ping(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	store := NewStore(db)
+
+	assert.NoError(t, store.Ping(context.Background()))
+	require.NoError(t, store.Close())
+	assert.Error(t, store.Ping(context.Background()))
+}
+
 func TestStore_GetGlobalSettings_Empty(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "mcpany-test-store-gs-*")
 	require.NoError(t, err)
@@ -0,0 +1,110 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/metrics"
+)
+
+// logsSizeStep is the share of the remaining logs deleted per round while
+// the database exceeds its size limit.
+const logsSizeStep = 10
+
+var metricLogsPrunedRows = []string{"logs", "pruned_rows"}
+
+// Log Retention
+
+// PruneLogs deletes the oldest persisted logs that exceed the retention
+// limits and vacuums the database if any were deleted.
+//
+// Summary: Applies the retention limits to the persisted logs.
+//
+// Parameters:
+//   - ctx: context.Context. The context for the request.
+//   - config: *configv1.RetentionConfig. The retention limits.
+//
+// Returns:
+//   - int64: The number of deleted logs.
+//   - error: An error if the logs cannot be pruned.
+//
+// Errors:
+//   - Returns error if a DELETE query or the VACUUM fails.
+//
+// Side Effects:
+//   - Deletes rows from the logs table and runs VACUUM.
+//   - Increments the logs_pruned_rows metric.
+func (s *Store) PruneLogs(ctx context.Context, config *configv1.RetentionConfig) (int64, error) {
+	var pruned int64
+	if maxAge := config.GetMaxAge().AsDuration(); maxAge > 0 {
+		cutoff := time.Now().Add(-maxAge).UTC().Format(time.RFC3339Nano)
+		n, err := s.deleteLogs(ctx, "DELETE FROM logs WHERE julianday(timestamp) < julianday(?)", cutoff)
+		pruned += n
+		if err != nil {
+			return pruned, fmt.Errorf("failed to delete expired logs: %w", err)
+		}
+	}
+	if maxRows := config.GetMaxRows(); maxRows > 0 {
+		n, err := s.deleteLogs(ctx, "DELETE FROM logs WHERE rowid IN (SELECT rowid FROM logs ORDER BY timestamp DESC LIMIT -1 OFFSET ?)", maxRows)
+		pruned += n
+		if err != nil {
+			return pruned, fmt.Errorf("failed to delete excess logs: %w", err)
+		}
+	}
+	if maxSize := config.GetMaxSizeBytes(); maxSize > 0 {
+		n, err := s.pruneLogsToSize(ctx, maxSize)
+		pruned += n
+		if err != nil {
+			return pruned, err
+		}
+	}
+
+	if pruned > 0 {
+		metrics.IncrCounter(metricLogsPrunedRows, float32(pruned))
+		if _, err := s.db.ExecContext(ctx, "VACUUM"); err != nil {
+			return pruned, fmt.Errorf("failed to vacuum database: %w", err)
+		}
+	}
+	return pruned, nil
+}
+
+// pruneLogsToSize deletes the oldest logs while the used size of the database
+// exceeds maxSize.
+func (s *Store) pruneLogsToSize(ctx context.Context, maxSize int64) (int64, error) {
+	var pruned int64
+	for {
+		var pageCount, freePages, pageSize int64
+		if err := s.db.QueryRowContext(ctx, "SELECT (SELECT page_count FROM pragma_page_count()), (SELECT freelist_count FROM pragma_freelist_count()), (SELECT page_size FROM pragma_page_size())").Scan(&pageCount, &freePages, &pageSize); err != nil {
+			return pruned, fmt.Errorf("failed to read database size: %w", err)
+		}
+		if (pageCount-freePages)*pageSize <= maxSize {
+			return pruned, nil
+		}
+		var count int64
+		if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM logs").Scan(&count); err != nil {
+			return pruned, fmt.Errorf("failed to count logs: %w", err)
+		}
+		if count == 0 {
+			// The rest of the database is configuration, which is never pruned.
+			return pruned, nil
+		}
+		n, err := s.deleteLogs(ctx, "DELETE FROM logs WHERE rowid IN (SELECT rowid FROM logs ORDER BY timestamp ASC LIMIT ?)", max(count/logsSizeStep, 1))
+		pruned += n
+		if err != nil {
+			return pruned, fmt.Errorf("failed to delete oldest logs: %w", err)
+		}
+	}
+}
+
+func (s *Store) deleteLogs(ctx context.Context, query string, args ...any) (int64, error) {
+	res, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
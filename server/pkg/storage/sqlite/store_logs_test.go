@@ -0,0 +1,81 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestPruneLogs(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "logs.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	store := NewStore(db)
+	ctx := context.Background()
+
+	saveLogs := func(prefix string, ts time.Time, n int) {
+		for i := 0; i < n; i++ {
+			require.NoError(t, store.SaveLog(ctx, &logging.LogEntry{
+				ID:        fmt.Sprintf("%s-%d", prefix, i),
+				Timestamp: ts.Add(time.Duration(i) * time.Second).Format(time.RFC3339),
+				Level:     "INFO",
+				Message:   "hello",
+			}))
+		}
+	}
+
+	t.Run("MaxAge", func(t *testing.T) {
+		saveLogs("old", time.Now().Add(-48*time.Hour), 3)
+		saveLogs("new", time.Now(), 2)
+
+		pruned, err := store.PruneLogs(ctx, configv1.RetentionConfig_builder{MaxAge: durationpb.New(24 * time.Hour)}.Build())
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), pruned)
+
+		logs, err := store.GetRecentLogs(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, logs, 2)
+		assert.Equal(t, "new-0", logs[0].ID)
+	})
+
+	t.Run("MaxRows", func(t *testing.T) {
+		pruned, err := store.PruneLogs(ctx, configv1.RetentionConfig_builder{MaxRows: proto.Int64(1)}.Build())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), pruned)
+
+		logs, err := store.GetRecentLogs(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		assert.Equal(t, "new-1", logs[0].ID)
+	})
+
+	t.Run("MaxSize", func(t *testing.T) {
+		saveLogs("bulk", time.Now(), 2000)
+
+		pruned, err := store.PruneLogs(ctx, configv1.RetentionConfig_builder{MaxSizeBytes: proto.Int64(128 * 1024)}.Build())
+		require.NoError(t, err)
+		assert.Positive(t, pruned)
+
+		logs, err := store.GetRecentLogs(ctx, 5000)
+		require.NoError(t, err)
+		assert.Len(t, logs, 2001-int(pruned))
+	})
+
+	t.Run("NothingToPrune", func(t *testing.T) {
+		pruned, err := store.PruneLogs(ctx, configv1.RetentionConfig_builder{}.Build())
+		require.NoError(t, err)
+		assert.Zero(t, pruned)
+	})
+}
@@ -359,6 +359,51 @@ func TestStore(t *testing.T) {
 		}
 	})
 
+	t.Run("APIKeys", func(t *testing.T) {
+		key := configv1.ClientApiKey_builder{
+			Id:        proto.String("key-1"),
+			Name:      proto.String("ci"),
+			KeyHash:   proto.String("abc123"),
+			Scopes:    []string{"admin"},
+			CreatedAt: proto.String("2026-01-01T00:00:00Z"),
+		}.Build()
+		if err := store.SaveAPIKey(context.Background(), key); err != nil {
+			t.Fatalf("failed to save api key: %v", err)
+		}
+
+		got, err := store.GetAPIKey(context.Background(), "key-1")
+		if err != nil {
+			t.Fatalf("failed to get api key: %v", err)
+		}
+		if !proto.Equal(got, key) {
+			t.Errorf("expected %v, got %v", key, got)
+		}
+
+		key.SetRevokedAt("2026-01-02T00:00:00Z")
+		if err := store.SaveAPIKey(context.Background(), key); err != nil {
+			t.Fatalf("failed to update api key: %v", err)
+		}
+		keys, err := store.ListAPIKeys(context.Background())
+		if err != nil {
+			t.Fatalf("failed to list api keys: %v", err)
+		}
+		if len(keys) != 1 || keys[0].GetRevokedAt() != "2026-01-02T00:00:00Z" {
+			t.Errorf("expected 1 revoked api key, got %v", keys)
+		}
+
+		if err := store.SaveAPIKey(context.Background(), configv1.ClientApiKey_builder{}.Build()); err == nil {
+			t.Error("expected error saving api key without ID")
+		}
+
+		if err := store.DeleteAPIKey(context.Background(), "key-1"); err != nil {
+			t.Fatalf("failed to delete api key: %v", err)
+		}
+		got, _ = store.GetAPIKey(context.Background(), "key-1")
+		if got != nil {
+			t.Errorf("expected api key to be nil, got %v", got)
+		}
+	})
+
 	t.Run("Tokens", func(t *testing.T) {
 		token := configv1.UserToken_builder{
 			UserId:      proto.String("user-1"),
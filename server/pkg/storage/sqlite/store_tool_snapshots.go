@@ -0,0 +1,106 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Tool Catalog Snapshots
+
+// SaveToolCatalogSnapshot saves the tool catalog snapshot of a service,
+// replacing the previous one.
+//
+// Summary: Persists a tool catalog snapshot.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - snapshot (*configv1.ToolCatalogSnapshot): The snapshot to save.
+//
+// Returns:
+//   - error: An error if saving fails.
+//
+// Side Effects:
+//   - Inserts or updates a row in the tool_catalog_snapshots table.
+func (s *Store) SaveToolCatalogSnapshot(ctx context.Context, snapshot *configv1.ToolCatalogSnapshot) error {
+	if snapshot.GetServiceId() == "" {
+		return fmt.Errorf("service id is required")
+	}
+	opts := protojson.MarshalOptions{UseProtoNames: true}
+	snapshotJSON, err := opts.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tool catalog snapshot: %w", err)
+	}
+
+	query := `
+	INSERT INTO tool_catalog_snapshots (service_id, snapshot_json, updated_at)
+	VALUES (?, ?, CURRENT_TIMESTAMP)
+	ON CONFLICT(service_id) DO UPDATE SET
+		snapshot_json = excluded.snapshot_json,
+		updated_at = excluded.updated_at;
+	`
+	if _, err := s.db.ExecContext(ctx, query, snapshot.GetServiceId(), string(snapshotJSON)); err != nil {
+		return fmt.Errorf("failed to save tool catalog snapshot: %w", err)
+	}
+	return nil
+}
+
+// ListToolCatalogSnapshots retrieves the tool catalog snapshots of all services.
+//
+// Summary: Lists tool catalog snapshots, sorted by service ID.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//
+// Returns:
+//   - []*configv1.ToolCatalogSnapshot: The snapshots.
+//   - error: An error if the database query fails.
+func (s *Store) ListToolCatalogSnapshots(ctx context.Context) ([]*configv1.ToolCatalogSnapshot, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT snapshot_json FROM tool_catalog_snapshots ORDER BY service_id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tool_catalog_snapshots: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var snapshots []*configv1.ToolCatalogSnapshot
+	for rows.Next() {
+		var snapshotJSON []byte
+		if err := rows.Scan(&snapshotJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan tool catalog snapshot: %w", err)
+		}
+		var snapshot configv1.ToolCatalogSnapshot
+		if err := protojson.Unmarshal(snapshotJSON, &snapshot); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal tool catalog snapshot: %w", err)
+		}
+		snapshots = append(snapshots, &snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return snapshots, nil
+}
+
+// DeleteToolCatalogSnapshot deletes the tool catalog snapshot of a service.
+//
+// Summary: Deletes a tool catalog snapshot.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - serviceID (string): The ID of the service.
+//
+// Returns:
+//   - error: An error if deletion fails.
+//
+// Side Effects:
+//   - Deletes the row from the tool_catalog_snapshots table.
+func (s *Store) DeleteToolCatalogSnapshot(ctx context.Context, serviceID string) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM tool_catalog_snapshots WHERE service_id = ?", serviceID); err != nil {
+		return fmt.Errorf("failed to delete tool catalog snapshot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestToolCatalogSnapshots(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "snapshots.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	store := NewStore(db)
+	ctx := context.Background()
+
+	schema, err := structpb.NewStruct(map[string]any{"type": "object"})
+	require.NoError(t, err)
+	for _, description := range []string{"old", "new"} {
+		snapshot := configv1.ToolCatalogSnapshot_builder{
+			ServiceId:  proto.String("weather"),
+			ConfigHash: proto.String("abc"),
+			CapturedAt: proto.String("2026-01-02T03:04:05Z"),
+			Tools: []*configv1.ToolSnapshot{configv1.ToolSnapshot_builder{
+				Name:         proto.String("forecast"),
+				Description:  proto.String(description),
+				InputSchema:  schema,
+				ReadOnlyHint: proto.Bool(true),
+			}.Build()},
+		}.Build()
+		require.NoError(t, store.SaveToolCatalogSnapshot(ctx, snapshot))
+	}
+
+	snapshots, err := store.ListToolCatalogSnapshots(ctx)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, "abc", snapshots[0].GetConfigHash())
+	tool := snapshots[0].GetTools()[0]
+	assert.Equal(t, "new", tool.GetDescription())
+	assert.True(t, tool.GetReadOnlyHint())
+	assert.Equal(t, "object", tool.GetInputSchema().GetFields()["type"].GetStringValue())
+
+	require.NoError(t, store.DeleteToolCatalogSnapshot(ctx, "weather"))
+	snapshots, err = store.ListToolCatalogSnapshots(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, snapshots)
+
+	assert.Error(t, store.SaveToolCatalogSnapshot(ctx, &configv1.ToolCatalogSnapshot{}))
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Webhook Dead Letters
+
+// SaveWebhookDeadLetter saves a dead-lettered webhook event, replacing the one
+// with the same ID.
+//
+// Summary: Persists a webhook dead letter.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - letter (*configv1.WebhookDeadLetter): The dead letter to save.
+//
+// Returns:
+//   - error: An error if saving fails.
+//
+// Side Effects:
+//   - Inserts or updates a row in the webhook_dead_letters table.
+func (s *Store) SaveWebhookDeadLetter(ctx context.Context, letter *configv1.WebhookDeadLetter) error {
+	if letter.GetId() == "" {
+		return fmt.Errorf("dead letter id is required")
+	}
+	opts := protojson.MarshalOptions{UseProtoNames: true}
+	letterJSON, err := opts.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook dead letter: %w", err)
+	}
+
+	query := `
+	INSERT INTO webhook_dead_letters (id, letter_json, failed_at, updated_at)
+	VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+	ON CONFLICT(id) DO UPDATE SET
+		letter_json = excluded.letter_json,
+		updated_at = excluded.updated_at;
+	`
+	if _, err := s.db.ExecContext(ctx, query, letter.GetId(), string(letterJSON), letter.GetFailedAt()); err != nil {
+		return fmt.Errorf("failed to save webhook dead letter: %w", err)
+	}
+	return nil
+}
+
+// ListWebhookDeadLetters retrieves the dead-lettered webhook events.
+//
+// Summary: Lists webhook dead letters, newest first.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - limit (int): The maximum number of dead letters to return, 0 for all.
+//
+// Returns:
+//   - []*configv1.WebhookDeadLetter: The dead letters.
+//   - error: An error if the database query fails.
+func (s *Store) ListWebhookDeadLetters(ctx context.Context, limit int) ([]*configv1.WebhookDeadLetter, error) {
+	query := "SELECT letter_json FROM webhook_dead_letters ORDER BY failed_at DESC, id DESC"
+	args := []any{}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook_dead_letters: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var letters []*configv1.WebhookDeadLetter
+	for rows.Next() {
+		var letterJSON []byte
+		if err := rows.Scan(&letterJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook dead letter: %w", err)
+		}
+		var letter configv1.WebhookDeadLetter
+		if err := protojson.Unmarshal(letterJSON, &letter); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal webhook dead letter: %w", err)
+		}
+		letters = append(letters, &letter)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return letters, nil
+}
+
+// GetWebhookDeadLetter retrieves a dead-lettered webhook event by ID.
+//
+// Summary: Retrieves a webhook dead letter.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - id (string): The ID of the dead letter.
+//
+// Returns:
+//   - *configv1.WebhookDeadLetter: The dead letter.
+//   - error: An error if retrieval fails.
+//
+// Errors:
+//   - Returns nil, nil if the dead letter is not found.
+//   - Returns an error if database query fails.
+func (s *Store) GetWebhookDeadLetter(ctx context.Context, id string) (*configv1.WebhookDeadLetter, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT letter_json FROM webhook_dead_letters WHERE id = ?", id)
+
+	var letterJSON []byte
+	if err := row.Scan(&letterJSON); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("failed to scan webhook dead letter: %w", err)
+	}
+
+	var letter configv1.WebhookDeadLetter
+	if err := protojson.Unmarshal(letterJSON, &letter); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook dead letter: %w", err)
+	}
+	return &letter, nil
+}
+
+// DeleteWebhookDeadLetter deletes a dead-lettered webhook event.
+//
+// Summary: Deletes a webhook dead letter.
+//
+// Parameters:
+//   - ctx (context.Context): The context for the request.
+//   - id (string): The ID of the dead letter.
+//
+// Returns:
+//   - error: An error if deletion fails.
+//
+// Side Effects:
+//   - Deletes the row from the webhook_dead_letters table.
+func (s *Store) DeleteWebhookDeadLetter(ctx context.Context, id string) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM webhook_dead_letters WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete webhook dead letter: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDeadLetters(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "dlq.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	store := NewStore(db)
+	ctx := context.Background()
+
+	for _, l := range []struct{ id, failedAt string }{
+		{"dl-1", "2026-01-01T00:00:00.000Z"},
+		{"dl-2", "2026-01-02T00:00:00.000Z"},
+		{"dl-3", "2026-01-03T00:00:00.000Z"},
+	} {
+		letter := configv1.WebhookDeadLetter_builder{
+			Id:        l.id,
+			ServiceId: "weather",
+			HookName:  "audit",
+			Payload:   `{"result":42}`,
+			Attempts:  3,
+			FailedAt:  l.failedAt,
+		}.Build()
+		require.NoError(t, store.SaveWebhookDeadLetter(ctx, letter))
+	}
+
+	letters, err := store.ListWebhookDeadLetters(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, letters, 2)
+	assert.Equal(t, "dl-3", letters[0].GetId(), "the newest dead letter is listed first")
+	assert.Equal(t, "dl-2", letters[1].GetId())
+
+	letter, err := store.GetWebhookDeadLetter(ctx, "dl-1")
+	require.NoError(t, err)
+	require.NotNil(t, letter)
+	assert.Equal(t, `{"result":42}`, letter.GetPayload())
+	letter.SetAttempts(4)
+	require.NoError(t, store.SaveWebhookDeadLetter(ctx, letter))
+	letter, err = store.GetWebhookDeadLetter(ctx, "dl-1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), letter.GetAttempts(), "saving a dead letter again updates it")
+
+	require.NoError(t, store.DeleteWebhookDeadLetter(ctx, "dl-1"))
+	letter, err = store.GetWebhookDeadLetter(ctx, "dl-1")
+	require.NoError(t, err)
+	assert.Nil(t, letter)
+	letters, err = store.ListWebhookDeadLetters(ctx, 0)
+	require.NoError(t, err)
+	assert.Len(t, letters, 2)
+
+	assert.Error(t, store.SaveWebhookDeadLetter(ctx, &configv1.WebhookDeadLetter{}))
+}
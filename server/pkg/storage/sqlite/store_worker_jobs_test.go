@@ -0,0 +1,106 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestWorkerJobs(t *testing.T) {
+	db, err := NewDB(filepath.Join(t.TempDir(), "jobs.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	store := NewStore(db)
+	ctx := context.Background()
+
+	for _, j := range []struct{ id, createdAt, completedAt string }{
+		{"job-1", "2026-01-01T00:00:00.000Z", "2026-01-01T00:01:00.000Z"},
+		{"job-2", "2026-01-02T00:00:00.000Z", ""},
+		{"job-3", "2026-01-03T00:00:00.000Z", "2026-01-03T00:01:00.000Z"},
+	} {
+		job := configv1.WorkerJob_builder{
+			Id:          proto.String(j.id),
+			ServiceId:   proto.String("reports"),
+			ToolName:    proto.String("reports.build_report"),
+			Status:      proto.String("pending"),
+			ToolInputs:  proto.String(`{"year":2026}`),
+			CreatedAt:   proto.String(j.createdAt),
+			CompletedAt: proto.String(j.completedAt),
+		}.Build()
+		require.NoError(t, store.SaveWorkerJob(ctx, job))
+	}
+
+	jobs, err := store.ListWorkerJobs(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "job-3", jobs[0].GetId(), "the newest job is listed first")
+	assert.Equal(t, "job-2", jobs[1].GetId())
+
+	job, err := store.GetWorkerJob(ctx, "job-2")
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, `{"year":2026}`, job.GetToolInputs())
+	job.SetStatus("succeeded")
+	job.SetResult(`{"pages":12}`)
+	job.SetCompletedAt("2026-01-02T00:01:00.000Z")
+	require.NoError(t, store.SaveWorkerJob(ctx, job))
+	job, err = store.GetWorkerJob(ctx, "job-2")
+	require.NoError(t, err)
+	assert.Equal(t, "succeeded", job.GetStatus(), "saving a job again updates it")
+	assert.Equal(t, `{"pages":12}`, job.GetResult())
+
+	claimed, err := store.ClaimWorkerJob(ctx, "job-3", "replica-a", "2026-01-03T00:00:00.000Z", "2026-01-03T00:01:00.000Z")
+	require.NoError(t, err)
+	assert.False(t, claimed, "a job that is done is not claimed")
+	require.NoError(t, store.SaveWorkerJob(ctx, configv1.WorkerJob_builder{
+		Id:        proto.String("job-4"),
+		Status:    proto.String("pending"),
+		CreatedAt: proto.String("2026-01-04T00:00:00.000Z"),
+	}.Build()))
+	for _, c := range []struct {
+		owner, now string
+		claimed    bool
+	}{
+		{"replica-a", "2026-01-04T00:00:00.000Z", true},
+		{"replica-b", "2026-01-04T00:00:30.000Z", false},
+		{"replica-a", "2026-01-04T00:00:30.000Z", true},
+		{"replica-b", "2026-01-04T00:02:00.000Z", true},
+	} {
+		until, err := time.Parse(time.RFC3339, c.now)
+		require.NoError(t, err)
+		claimed, err := store.ClaimWorkerJob(ctx, "job-4", c.owner, c.now, until.Add(time.Minute).Format("2006-01-02T15:04:05.000Z07:00"))
+		require.NoError(t, err)
+		assert.Equal(t, c.claimed, claimed, "%s at %s", c.owner, c.now)
+	}
+	require.NoError(t, store.SaveWorkerJob(ctx, configv1.WorkerJob_builder{
+		Id:        proto.String("job-4"),
+		Status:    proto.String("running"),
+		CreatedAt: proto.String("2026-01-04T00:00:00.000Z"),
+	}.Build()))
+	claimed, err = store.ClaimWorkerJob(ctx, "job-4", "replica-a", "2026-01-04T00:02:30.000Z", "2026-01-04T00:03:30.000Z")
+	require.NoError(t, err)
+	assert.False(t, claimed, "saving a job keeps its lease")
+	require.NoError(t, store.DeleteWorkerJob(ctx, "job-4"))
+
+	require.NoError(t, store.DeleteWorkerJobsCompletedBefore(ctx, "2026-01-02T12:00:00.000Z"))
+	jobs, err = store.ListWorkerJobs(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "job-3", jobs[0].GetId())
+
+	require.NoError(t, store.DeleteWorkerJob(ctx, "job-3"))
+	job, err = store.GetWorkerJob(ctx, "job-3")
+	require.NoError(t, err)
+	assert.Nil(t, job)
+
+	assert.Error(t, store.SaveWorkerJob(ctx, &configv1.WorkerJob{}))
+}
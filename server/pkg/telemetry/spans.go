@@ -0,0 +1,52 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation scope of the spans of MCP Any.
+const TracerName = "github.com/mcpany/core/server"
+
+// StartSpan starts a span of MCP Any. The span is a no-op if tracing is not
+// configured.
+//
+// Summary: Starts a span with the tracer of MCP Any.
+//
+// Parameters:
+//   - ctx: context.Context. The parent context.
+//   - name: string. The span name.
+//   - opts: ...trace.SpanStartOption. Options such as the span kind and attributes.
+//
+// Returns:
+//   - context.Context: The context carrying the span.
+//   - trace.Span: The span; end it with EndSpan.
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, opts...)
+}
+
+// EndSpan records the outcome of an operation and ends its span.
+//
+// Summary: Ends a span, marking it as failed if err is not nil.
+//
+// Parameters:
+//   - span: trace.Span. The span.
+//   - err: error. The error of the operation, or nil.
+//   - attrs: ...attribute.KeyValue. Attributes known only at the end of the operation.
+func EndSpan(span trace.Span, err error, attrs ...attribute.KeyValue) {
+	if len(attrs) > 0 {
+		span.SetAttributes(attrs...)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
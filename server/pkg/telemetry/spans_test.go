@@ -0,0 +1,64 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	config_v1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestStartSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(prev)
+
+	ctx, parent := StartSpan(context.Background(), "parent")
+	_, child := StartSpan(ctx, "child", trace.WithSpanKind(trace.SpanKindClient))
+	EndSpan(child, errors.New("boom"), attribute.Int("attempt", 2))
+	EndSpan(parent, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "child", spans[0].Name())
+	assert.Equal(t, TracerName, spans[0].InstrumentationScope().Name)
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "boom", spans[0].Status().Description)
+	assert.Contains(t, spans[0].Attributes(), attribute.Int("attempt", 2))
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+}
+
+func TestSampler(t *testing.T) {
+	never := sampler(config_v1.TelemetryConfig_builder{SamplingRatio: proto.Float64(0)}.Build())
+	always := sampler(&config_v1.TelemetryConfig{})
+
+	root := sdktrace.SamplingParameters{TraceID: trace.TraceID{1}, Name: "root"}
+	assert.Equal(t, sdktrace.Drop, never.ShouldSample(root).Decision)
+	assert.Equal(t, sdktrace.RecordAndSample, always.ShouldSample(root).Decision)
+
+	// A caller's sampled trace is continued regardless of the ratio.
+	parent := trace.ContextWithRemoteSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	}))
+	assert.Equal(t, sdktrace.RecordAndSample, never.ShouldSample(sdktrace.SamplingParameters{
+		ParentContext: parent, TraceID: trace.TraceID{1}, Name: "child",
+	}).Decision)
+}
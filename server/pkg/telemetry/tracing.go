@@ -71,6 +71,10 @@ func InitTelemetry(ctx context.Context, serviceName string, version string, cfg
 		return nil, fmt.Errorf("failed to create resource: %w", err)
 	}
 
+	// Propagate W3C trace context even if no traces are exported, so the
+	// traces of callers continue at the upstreams.
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
 	// Initialize Tracer
 	shutdownTracer, err := initTracer(ctx, res, cfg, writer)
 	if err != nil {
@@ -106,12 +110,16 @@ func initTracer(ctx context.Context, res *resource.Resource, cfg *config_v1.Tele
 
 	switch exporterType {
 	case exporterOTLP:
-		opts := []otlptracehttp.Option{
-			otlptracehttp.WithInsecure(),
+		var opts []otlptracehttp.Option
+		if !cfg.GetOtlpTls() {
+			opts = append(opts, otlptracehttp.WithInsecure())
 		}
 		if cfg.GetOtlpEndpoint() != "" {
 			opts = append(opts, otlptracehttp.WithEndpoint(cfg.GetOtlpEndpoint()))
 		}
+		if len(cfg.GetOtlpHeaders()) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(cfg.GetOtlpHeaders()))
+		}
 		exporter, err = otlptracehttp.New(ctx, opts...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create otlp trace exporter: %w", err)
@@ -131,14 +139,23 @@ func initTracer(ctx context.Context, res *resource.Resource, cfg *config_v1.Tele
 	tp := trace.NewTracerProvider(
 		trace.WithBatcher(exporter),
 		trace.WithResource(res),
+		trace.WithSampler(sampler(cfg)),
 	)
 
 	otel.SetTracerProvider(tp)
-	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
 
 	return tp.Shutdown, nil
 }
 
+// sampler samples sampling_ratio of the new traces and follows the sampling
+// decision of the caller otherwise.
+func sampler(cfg *config_v1.TelemetryConfig) trace.Sampler {
+	if !cfg.HasSamplingRatio() {
+		return trace.ParentBased(trace.AlwaysSample())
+	}
+	return trace.ParentBased(trace.TraceIDRatioBased(cfg.GetSamplingRatio()))
+}
+
 func initMeter(ctx context.Context, res *resource.Resource, cfg *config_v1.TelemetryConfig, _ io.Writer) (func(context.Context) error, error) {
 	exporterType := cfg.GetMetricsExporter()
 	// If OTLP endpoint is set, default to otlp if type not specified
@@ -152,12 +169,16 @@ func initMeter(ctx context.Context, res *resource.Resource, cfg *config_v1.Telem
 	switch exporterType {
 	case exporterOTLP:
 		var exp metric.Exporter
-		opts := []otlpmetrichttp.Option{
-			otlpmetrichttp.WithInsecure(),
+		var opts []otlpmetrichttp.Option
+		if !cfg.GetOtlpTls() {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
 		}
 		if cfg.GetOtlpEndpoint() != "" {
 			opts = append(opts, otlpmetrichttp.WithEndpoint(cfg.GetOtlpEndpoint()))
 		}
+		if len(cfg.GetOtlpHeaders()) > 0 {
+			opts = append(opts, otlpmetrichttp.WithHeaders(cfg.GetOtlpHeaders()))
+		}
 		exp, err = otlpmetrichttp.New(ctx, opts...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create otlp metric exporter: %w", err)
@@ -0,0 +1,105 @@
+// Copyright 2025 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package testkit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/appconsts"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const defaultConnectTimeout = 10 * time.Second
+
+// Connect opens an MCP client session to a streamable HTTP endpoint.
+//
+// Parameters:
+//   - t (testing.TB): The test handle. The session is closed on cleanup.
+//   - endpoint (string): The MCP endpoint URL.
+//   - header (http.Header): Extra headers sent with every request (e.g. auth). May be nil.
+//
+// Returns:
+//   - *mcp.ClientSession: The initialized session.
+//
+// Side Effects:
+//   - Opens a network connection.
+//   - Fails the test if the session cannot be initialized.
+func Connect(t testing.TB, endpoint string, header http.Header) *mcp.ClientSession {
+	t.Helper()
+	httpClient := &http.Client{
+		Transport: &headerRoundTripper{header: header, next: http.DefaultTransport},
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: appconsts.Name + "-testkit", Version: appconsts.Version}, nil)
+
+	// The connect context also governs the session's background SSE stream,
+	// so it must outlive this call; it is cancelled on cleanup instead.
+	ctx, cancel := context.WithCancel(context.Background())
+	timer := time.AfterFunc(defaultConnectTimeout, cancel)
+	session, err := client.Connect(ctx, &mcp.StreamableClientTransport{
+		Endpoint:   endpoint,
+		HTTPClient: httpClient,
+		MaxRetries: -1,
+	}, nil)
+	timer.Stop()
+	if err != nil {
+		cancel()
+		t.Fatalf("failed to connect MCP client to %s: %v", endpoint, err)
+	}
+	t.Cleanup(func() {
+		_ = session.Close()
+		cancel()
+	})
+	return session
+}
+
+// CallTool calls a tool and fails the test if the call errors or the tool
+// reports an error result.
+//
+// Parameters:
+//   - t (testing.TB): The test handle.
+//   - session (*mcp.ClientSession): The client session.
+//   - name (string): The tool name.
+//   - args (any): The tool arguments. Must marshal to a JSON object.
+//
+// Returns:
+//   - *mcp.CallToolResult: The successful result.
+func CallTool(t testing.TB, session *mcp.ClientSession, name string, args any) *mcp.CallToolResult {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), defaultConnectTimeout)
+	defer cancel()
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		t.Fatalf("call to tool %q failed: %v", name, err)
+	}
+	if res.IsError {
+		t.Fatalf("tool %q returned an error result: %+v", name, res.Content)
+	}
+	return res
+}
+
+type headerRoundTripper struct {
+	header http.Header
+	next   http.RoundTripper
+}
+
+// RoundTrip adds the configured headers to the request before sending it.
+//
+// Parameters:
+//   - req (*http.Request): The outgoing request.
+//
+// Returns:
+//   - *http.Response: The response.
+//   - error: An error if the request fails.
+func (h *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(h.header) > 0 {
+		req = req.Clone(req.Context())
+		for k, v := range h.header {
+			req.Header[k] = v
+		}
+	}
+	return h.next.RoundTrip(req)
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package testkit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	apiv1 "github.com/mcpany/core/proto/api/v1"
+	"github.com/mcpany/core/server/pkg/app"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/afero"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const defaultStartupTimeout = 30 * time.Second
+
+// InstanceOptions configures an in-process MCP Any instance.
+type InstanceOptions struct {
+	// Config is the configuration document to load. The format is inferred
+	// from ConfigExt. Empty starts the instance without upstream services.
+	Config string
+	// ConfigExt is the file extension used for Config (".yaml" by default).
+	ConfigExt string
+	// APIKey protects the instance. Clients created with Instance.Connect
+	// send it automatically.
+	APIKey string
+	// StartupTimeout bounds how long to wait for the instance to become
+	// ready. Zero uses a default of 30 seconds.
+	StartupTimeout time.Duration
+}
+
+// Instance is a running in-process MCP Any server.
+type Instance struct {
+	// HTTPEndpoint is the base URL of the HTTP listener, e.g. http://127.0.0.1:1234.
+	HTTPEndpoint string
+	// MCPEndpoint is the streamable HTTP MCP endpoint.
+	MCPEndpoint string
+	// GRPCEndpoint is the host:port of the gRPC listener.
+	GRPCEndpoint string
+	// APIKey is the API key the instance was started with.
+	APIKey string
+	// GRPCConn is the client connection to the gRPC listener. It is closed on
+	// cleanup.
+	GRPCConn *grpc.ClientConn
+	// RegistrationClient registers services at runtime over gRPC.
+	RegistrationClient apiv1.RegistrationServiceClient
+	// App is the underlying application, for advanced assertions.
+	App *app.Application
+}
+
+// StartInstance starts an in-process MCP Any instance on random loopback
+// ports and waits for it to become ready.
+//
+// Parameters:
+//   - t (testing.TB): The test handle. The instance is shut down on cleanup.
+//   - opts (InstanceOptions): The instance options.
+//
+// Returns:
+//   - *Instance: The running instance.
+//
+// Side Effects:
+//   - Writes the config and database to a temporary directory.
+//   - Starts HTTP and gRPC listeners.
+//   - Fails the test if the instance does not start.
+func StartInstance(t testing.TB, opts InstanceOptions) *Instance {
+	t.Helper()
+	dir := t.TempDir()
+
+	var configPaths []string
+	if opts.Config != "" {
+		ext := opts.ConfigExt
+		if ext == "" {
+			ext = ".yaml"
+		}
+		configPath := filepath.Join(dir, "config"+ext)
+		if err := os.WriteFile(configPath, []byte(opts.Config), 0o600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		configPaths = append(configPaths, configPath)
+	}
+
+	timeout := opts.StartupTimeout
+	if timeout <= 0 {
+		timeout = defaultStartupTimeout
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	application := app.NewApplication()
+	runErrCh := make(chan error, 1)
+	go func() {
+		runErrCh <- application.Run(app.RunOptions{
+			Ctx:             ctx,
+			Fs:              afero.NewOsFs(),
+			JSONRPCPort:     "127.0.0.1:0",
+			GRPCPort:        "127.0.0.1:0",
+			ConfigPaths:     configPaths,
+			APIKey:          opts.APIKey,
+			ShutdownTimeout: 5 * time.Second,
+			DBPath:          filepath.Join(dir, "mcpany.db"),
+		})
+	}()
+	t.Cleanup(func() {
+		cancel()
+		select {
+		case <-runErrCh:
+		case <-time.After(10 * time.Second):
+			t.Logf("MCP Any instance did not shut down in time")
+		}
+	})
+
+	startupCtx, startupCancel := context.WithTimeout(ctx, timeout)
+	defer startupCancel()
+	startupErrCh := make(chan error, 1)
+	go func() { startupErrCh <- application.WaitForStartup(startupCtx) }()
+	select {
+	case err := <-startupErrCh:
+		if err != nil {
+			t.Fatalf("MCP Any instance failed to start: %v", err)
+		}
+	case err := <-runErrCh:
+		t.Fatalf("MCP Any instance exited during startup: %v", err)
+	}
+
+	httpEndpoint := fmt.Sprintf("http://127.0.0.1:%d", application.BoundHTTPPort.Load())
+	grpcEndpoint := net.JoinHostPort("127.0.0.1", strconv.Itoa(int(application.BoundGRPCPort.Load())))
+
+	conn, err := grpc.NewClient(grpcEndpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to create gRPC client: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return &Instance{
+		HTTPEndpoint:       httpEndpoint,
+		MCPEndpoint:        httpEndpoint + "/mcp",
+		GRPCEndpoint:       grpcEndpoint,
+		APIKey:             opts.APIKey,
+		GRPCConn:           conn,
+		RegistrationClient: apiv1.NewRegistrationServiceClient(conn),
+		App:                application,
+	}
+}
+
+// Connect opens an MCP client session against the instance, authenticating
+// with the instance's API key if one is set.
+//
+// Parameters:
+//   - t (testing.TB): The test handle. The session is closed on cleanup.
+//
+// Returns:
+//   - *mcp.ClientSession: The initialized session.
+//
+// Side Effects:
+//   - Opens a network connection to the instance.
+func (i *Instance) Connect(t testing.TB) *mcp.ClientSession {
+	t.Helper()
+	header := http.Header{}
+	if i.APIKey != "" {
+		header.Set("X-API-Key", i.APIKey)
+	}
+	return Connect(t, i.MCPEndpoint, header)
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package testkit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+)
+
+func TestStaticResponses(t *testing.T) {
+	server := NewHTTPUpstream(t, StaticResponses(t, map[string]string{
+		"/users?id=1": `{"name":"exact"}`,
+		"/users":      `{"name":"path"}`,
+	}))
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	code, body := get("/users?id=1")
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"name":"exact"}`, body)
+
+	code, body = get("/users?id=2")
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"name":"path"}`, body)
+
+	code, _ = get("/missing")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestNewOpenAPIUpstream(t *testing.T) {
+	spec := `{"openapi":"3.0.0","info":{"title":"t","version":"1"},"paths":{}}`
+	server := NewOpenAPIUpstream(t, spec, StaticResponses(t, map[string]string{"/ping": `{}`}))
+
+	resp, err := http.Get(server.URL + OpenAPISpecPath)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.JSONEq(t, spec, string(body))
+
+	resp, err = http.Get(server.URL + "/ping")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestNewGRPCUpstream_Reflection(t *testing.T) {
+	addr := NewGRPCUpstream(t, nil)
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := grpc_reflection_v1.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&grpc_reflection_v1.ServerReflectionRequest{
+		MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_ListServices{},
+	}))
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.GetListServicesResponse().GetService())
+}
+
+func newEchoMCPServer() *mcp.Server {
+	server := mcp.NewServer(&mcp.Implementation{Name: "echo", Version: "v1"}, nil)
+	type echoArgs struct {
+		Message string `json:"message"`
+	}
+	mcp.AddTool(server, &mcp.Tool{Name: "echo"}, func(_ context.Context, _ *mcp.CallToolRequest, args echoArgs) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: args.Message}}}, nil, nil
+	})
+	return server
+}
+
+func TestNewMCPUpstream(t *testing.T) {
+	upstream := NewMCPUpstream(t, newEchoMCPServer())
+	session := Connect(t, upstream.URL, nil)
+
+	res := CallTool(t, session, "echo", map[string]any{"message": "hi"})
+	require.Len(t, res.Content, 1)
+	assert.Equal(t, "hi", res.Content[0].(*mcp.TextContent).Text)
+}
+
+func TestStartInstance_ProxiesMCPUpstream(t *testing.T) {
+	t.Setenv("MCPANY_ALLOW_LOOPBACK_RESOURCES", "true")
+	upstream := NewMCPUpstream(t, newEchoMCPServer())
+
+	instance := StartInstance(t, InstanceOptions{
+		APIKey: "test-key",
+		Config: fmt.Sprintf(`
+upstream_services:
+  - name: echo-service
+    mcp_service:
+      tool_auto_discovery: true
+      http_connection:
+        http_address: %q
+`, upstream.URL),
+	})
+	session := instance.Connect(t)
+
+	var toolName string
+	require.Eventually(t, func() bool {
+		tools, err := session.ListTools(context.Background(), nil)
+		if err != nil {
+			return false
+		}
+		for _, tl := range tools.Tools {
+			if strings.HasPrefix(tl.Name, "echo-service") {
+				toolName = tl.Name
+				return true
+			}
+		}
+		return false
+	}, 10*time.Second, 100*time.Millisecond)
+
+	res := CallTool(t, session, toolName, map[string]any{"message": "proxied"})
+	require.NotEmpty(t, res.Content)
+	assert.Contains(t, res.Content[0].(*mcp.TextContent).Text, "proxied")
+}
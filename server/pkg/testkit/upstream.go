@@ -0,0 +1,148 @@
+// Copyright 2025 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package testkit provides helpers for testing MCP Any configurations and
+// plugins programmatically: mock gRPC, HTTP, OpenAPI and MCP upstreams, an
+// in-process MCP Any instance, and an MCP test client.
+//
+// All helpers register their cleanup with the provided testing.TB, so callers
+// don't need to close anything explicitly.
+package testkit
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// NewHTTPUpstream starts a mock HTTP upstream backed by handler.
+//
+// Parameters:
+//   - t (testing.TB): The test handle. The server is closed on cleanup.
+//   - handler (http.Handler): The handler serving upstream requests.
+//
+// Returns:
+//   - *httptest.Server: The running server.
+//
+// Side Effects:
+//   - Listens on a random loopback port.
+func NewHTTPUpstream(t testing.TB, handler http.Handler) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	t.Logf("Started mock HTTP upstream at %s", server.URL)
+	return server
+}
+
+// StaticResponses returns a handler that serves canned JSON bodies keyed by
+// request URI. An exact match on the full request URI (including the query
+// string) wins over a match on the path alone; anything else is a 404.
+//
+// Parameters:
+//   - t (testing.TB): The test handle used for request logging.
+//   - responses (map[string]string): Request URI or path to response body.
+//
+// Returns:
+//   - http.Handler: The handler.
+func StaticResponses(t testing.TB, responses map[string]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyBytes, _ := io.ReadAll(r.Body)
+		t.Logf("Mock upstream received request: %s %s Body: %s", r.Method, r.URL.RequestURI(), string(bodyBytes))
+
+		body, ok := responses[r.URL.RequestURI()]
+		if !ok {
+			body, ok = responses[r.URL.Path]
+		}
+		if !ok {
+			t.Logf("Mock upstream: no response found for %s", r.URL.RequestURI())
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(body))
+	})
+}
+
+// OpenAPISpecPath is the path at which NewOpenAPIUpstream serves its spec.
+const OpenAPISpecPath = "/openapi.json"
+
+// NewOpenAPIUpstream starts a mock upstream that serves spec at
+// OpenAPISpecPath and delegates every other request to handler.
+//
+// Parameters:
+//   - t (testing.TB): The test handle. The server is closed on cleanup.
+//   - spec (string): The OpenAPI document (JSON or YAML).
+//   - handler (http.Handler): The handler implementing the API operations.
+//
+// Returns:
+//   - *httptest.Server: The running server. The spec URL is server.URL + OpenAPISpecPath.
+//
+// Side Effects:
+//   - Listens on a random loopback port.
+func NewOpenAPIUpstream(t testing.TB, spec string, handler http.Handler) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc(OpenAPISpecPath, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(spec))
+	})
+	if handler != nil {
+		mux.Handle("/", handler)
+	}
+	return NewHTTPUpstream(t, mux)
+}
+
+// NewGRPCUpstream starts a mock gRPC upstream with server reflection enabled,
+// so MCP Any can discover its services without proto files.
+//
+// Parameters:
+//   - t (testing.TB): The test handle. The server is stopped on cleanup.
+//   - register (func(*grpc.Server)): Registers the services to expose.
+//
+// Returns:
+//   - string: The listen address (host:port).
+//
+// Side Effects:
+//   - Listens on a random loopback port and serves in a background goroutine.
+func NewGRPCUpstream(t testing.TB, register func(*grpc.Server)) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen for gRPC upstream: %v", err)
+	}
+	server := grpc.NewServer()
+	if register != nil {
+		register(server)
+	}
+	reflection.Register(server)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+	t.Logf("Started mock gRPC upstream at %s", lis.Addr())
+	return lis.Addr().String()
+}
+
+// NewMCPUpstream serves server over the streamable HTTP transport.
+//
+// Parameters:
+//   - t (testing.TB): The test handle. The server is closed on cleanup.
+//   - server (*mcp.Server): The MCP server to expose, typically built with
+//     mcp.NewServer and mcp.AddTool.
+//
+// Returns:
+//   - *httptest.Server: The running server. Point an MCP upstream at server.URL.
+//
+// Side Effects:
+//   - Listens on a random loopback port.
+func NewMCPUpstream(t testing.TB, server *mcp.Server) *httptest.Server {
+	t.Helper()
+	return NewHTTPUpstream(t, mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server {
+		return server
+	}, nil))
+}
@@ -0,0 +1,394 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+	xsync "github.com/puzpuzpuz/xsync/v4"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ArgumentViolation is an argument of a tool call that does not match the
+// input schema of the tool.
+type ArgumentViolation struct {
+	// Path is the JSON pointer of the argument, "/" for the arguments object.
+	Path string `json:"path"`
+	// Message describes the violation, e.g. "expected integer, but got string".
+	Message string `json:"message"`
+}
+
+// ArgumentValidationError is returned by the ArgumentValidationHook when a
+// call is rejected. It wraps ErrInvalidArguments.
+//
+// Summary: The violations of an invalid tool call.
+type ArgumentValidationError struct {
+	// Tool is the name of the called tool.
+	Tool string
+	// Violations are the arguments that do not match the schema, sorted by path.
+	Violations []ArgumentViolation
+}
+
+// Error returns the violations in a single line.
+//
+// Returns:
+//   - string: e.g. `invalid arguments for tool "db.query": /limit: expected integer, but got string`.
+func (e *ArgumentValidationError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s for tool %q", ErrInvalidArguments, e.Tool)
+	for i, v := range e.Violations {
+		if i == 0 {
+			sb.WriteString(": ")
+		} else {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(v.Path)
+		sb.WriteString(": ")
+		sb.WriteString(v.Message)
+	}
+	return sb.String()
+}
+
+// Unwrap returns ErrInvalidArguments.
+func (e *ArgumentValidationError) Unwrap() error {
+	return ErrInvalidArguments
+}
+
+// inputSchema is the compiled input schema of a tool.
+type inputSchema struct {
+	// source is the schema it was compiled from, to notice a re-registered tool.
+	source *structpb.Struct
+	// schema is nil if the schema could not be compiled.
+	schema *jsonschema.Schema
+	// doc is the schema as JSON values, walked to inject defaults and coerce types.
+	doc map[string]any
+}
+
+// ArgumentValidationHook implements PreCallHook. It validates the arguments
+// of a call against the input schema of the tool, so that invalid calls are
+// rejected with the paths of the bad arguments instead of reaching the
+// upstream.
+//
+// Summary: Pre-call hook that validates tool arguments.
+type ArgumentValidationHook struct {
+	injectDefaults bool
+	coerceTypes    bool
+	schemas        *xsync.Map[string, *inputSchema]
+}
+
+// NewArgumentValidationHook creates a new ArgumentValidationHook.
+//
+// Summary: Initializes a new ArgumentValidationHook.
+//
+// Parameters:
+//   - cfg: *configv1.ArgumentValidationConfig. The argument validation settings of the service.
+//
+// Returns:
+//   - *ArgumentValidationHook: The initialized hook, or nil if validation is disabled.
+func NewArgumentValidationHook(cfg *configv1.ArgumentValidationConfig) *ArgumentValidationHook {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	return &ArgumentValidationHook{
+		injectDefaults: cfg.GetInjectDefaults(),
+		coerceTypes:    cfg.GetCoerceTypes(),
+		schemas:        xsync.NewMap[string, *inputSchema](),
+	}
+}
+
+// ExecutePre validates the arguments of the call.
+//
+// Summary: Validates the tool request against the input schema of the tool.
+//
+// Parameters:
+//   - ctx: context.Context. The context carrying the called tool.
+//   - req: *ExecutionRequest. The tool execution request.
+//
+// Returns:
+//   - Action: ActionAllow if the arguments are valid, ActionDeny otherwise.
+//   - *ExecutionRequest: The request with injected defaults and coerced arguments, or nil if unchanged.
+//   - error: An *ArgumentValidationError if the arguments do not match the schema.
+//
+// Side Effects:
+//   - Compiles and caches the input schema of the tool on its first call.
+func (h *ArgumentValidationHook) ExecutePre(ctx context.Context, req *ExecutionRequest) (Action, *ExecutionRequest, error) {
+	t := req.Tool
+	if t == nil {
+		t, _ = GetFromContext(ctx)
+	}
+	if t == nil || t.Tool() == nil {
+		return ActionAllow, nil, nil
+	}
+	s := h.schemaFor(req.ToolName, t.Tool().GetInputSchema())
+	if s == nil {
+		return ActionAllow, nil, nil
+	}
+
+	var args any = map[string]any{}
+	if len(bytes.TrimSpace(req.ToolInputs)) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(req.ToolInputs))
+		dec.UseNumber()
+		if err := dec.Decode(&args); err != nil {
+			return ActionDeny, nil, &ArgumentValidationError{
+				Tool:       req.ToolName,
+				Violations: []ArgumentViolation{{Path: "/", Message: "arguments are not valid JSON: " + err.Error()}},
+			}
+		}
+	}
+
+	args, changed := h.normalize(args, s.doc)
+	if err := s.schema.Validate(args); err != nil {
+		return ActionDeny, nil, &ArgumentValidationError{Tool: req.ToolName, Violations: violations(err)}
+	}
+	if !changed {
+		return ActionAllow, nil, nil
+	}
+
+	inputs, err := json.Marshal(args)
+	if err != nil {
+		return ActionDeny, nil, fmt.Errorf("failed to encode normalized arguments: %w", err)
+	}
+	modified := *req
+	modified.ToolInputs = inputs
+	modified.Arguments = nil
+	if err := json.Unmarshal(inputs, &modified.Arguments); err != nil {
+		return ActionDeny, nil, fmt.Errorf("failed to decode normalized arguments: %w", err)
+	}
+	return ActionAllow, &modified, nil
+}
+
+// schemaFor returns the compiled input schema of a tool, or nil if the tool
+// has no schema or it cannot be compiled.
+func (h *ArgumentValidationHook) schemaFor(toolName string, source *structpb.Struct) *inputSchema {
+	if len(source.GetFields()) == 0 {
+		return nil
+	}
+	if s, ok := h.schemas.Load(toolName); ok && s.source == source {
+		if s.schema == nil {
+			return nil
+		}
+		return s
+	}
+
+	s := &inputSchema{source: source, doc: source.AsMap()}
+	if raw, err := protojson.Marshal(source); err != nil {
+		logging.GetLogger().Warn("Skipping argument validation, input schema cannot be encoded", "toolName", toolName, "error", err)
+	} else {
+		c := jsonschema.NewCompiler()
+		if err := c.AddResource("schema.json", bytes.NewReader(raw)); err != nil {
+			logging.GetLogger().Warn("Skipping argument validation, input schema is invalid", "toolName", toolName, "error", err)
+		} else if s.schema, err = c.Compile("schema.json"); err != nil {
+			logging.GetLogger().Warn("Skipping argument validation, input schema is invalid", "toolName", toolName, "error", err)
+		}
+	}
+	h.schemas.Store(toolName, s)
+	if s.schema == nil {
+		return nil
+	}
+	return s
+}
+
+// normalize injects the defaults of missing properties and coerces scalars of
+// the wrong type, following the properties and items of the schema. It
+// reports whether v was changed.
+func (h *ArgumentValidationHook) normalize(v any, schema map[string]any) (any, bool) {
+	changed := false
+	if h.coerceTypes {
+		v, changed = coerce(v, schemaTypes(schema))
+	}
+	switch val := v.(type) {
+	case map[string]any:
+		props, _ := schema["properties"].(map[string]any)
+		for name, p := range props {
+			propSchema, ok := p.(map[string]any)
+			if !ok {
+				continue
+			}
+			pv, present := val[name]
+			if !present {
+				if def, ok := propSchema["default"]; ok && h.injectDefaults {
+					val[name] = cloneJSON(def)
+					changed = true
+				}
+				continue
+			}
+			if nv, c := h.normalize(pv, propSchema); c {
+				val[name] = nv
+				changed = true
+			}
+		}
+	case []any:
+		items, ok := schema["items"].(map[string]any)
+		if !ok {
+			break
+		}
+		for i, item := range val {
+			if nv, c := h.normalize(item, items); c {
+				val[i] = nv
+				changed = true
+			}
+		}
+	}
+	return v, changed
+}
+
+// schemaTypes returns the types allowed by the "type" keyword of a schema.
+func schemaTypes(schema map[string]any) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []any:
+		types := make([]string, 0, len(t))
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// coerce converts a scalar to the first of types it can be converted to,
+// unless it already has one of them.
+func coerce(v any, types []string) (any, bool) {
+	for _, t := range types {
+		if hasType(v, t) {
+			return v, false
+		}
+	}
+	for _, t := range types {
+		switch t {
+		case "integer":
+			if s, ok := v.(string); ok {
+				if i, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64); err == nil {
+					return json.Number(strconv.FormatInt(i, 10)), true
+				}
+			}
+		case "number":
+			if s, ok := v.(string); ok {
+				if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+					return json.Number(strconv.FormatFloat(f, 'g', -1, 64)), true
+				}
+			}
+		case "boolean":
+			if s, ok := v.(string); ok {
+				switch strings.ToLower(strings.TrimSpace(s)) {
+				case "true":
+					return true, true
+				case "false":
+					return false, true
+				}
+			}
+		case "string":
+			switch val := v.(type) {
+			case json.Number:
+				return val.String(), true
+			case bool:
+				return strconv.FormatBool(val), true
+			}
+		}
+	}
+	return v, false
+}
+
+// hasType reports whether a decoded JSON value has a JSON schema type.
+func hasType(v any, t string) bool {
+	switch t {
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "integer":
+		switch n := v.(type) {
+		case json.Number:
+			if _, err := n.Int64(); err == nil {
+				return true
+			}
+			f, err := n.Float64()
+			return err == nil && f == math.Trunc(f)
+		case float64:
+			return n == math.Trunc(n)
+		}
+		return false
+	case "number":
+		switch v.(type) {
+		case json.Number, float64:
+			return true
+		}
+		return false
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "null":
+		return v == nil
+	}
+	return false
+}
+
+// cloneJSON deep copies a decoded JSON value, so injected defaults do not
+// share state with the cached schema.
+func cloneJSON(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(val))
+		for k, e := range val {
+			m[k] = cloneJSON(e)
+		}
+		return m
+	case []any:
+		s := make([]any, len(val))
+		for i, e := range val {
+			s[i] = cloneJSON(e)
+		}
+		return s
+	}
+	return v
+}
+
+// violations flattens a validation error into the failed leaf keywords.
+func violations(err error) []ArgumentViolation {
+	ve, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return []ArgumentViolation{{Path: "/", Message: err.Error()}}
+	}
+	var out []ArgumentViolation
+	seen := map[ArgumentViolation]bool{}
+	var walk func(e *jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) > 0 {
+			for _, c := range e.Causes {
+				walk(c)
+			}
+			return
+		}
+		v := ArgumentViolation{Path: e.InstanceLocation, Message: e.Message}
+		if v.Path == "" {
+			v.Path = "/"
+		}
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	walk(ve)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out
+}
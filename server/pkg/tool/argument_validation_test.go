@@ -0,0 +1,157 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	v1 "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func searchInputSchema(t *testing.T) *structpb.Struct {
+	t.Helper()
+	schema, err := structpb.NewStruct(map[string]any{
+		"type":     "object",
+		"required": []any{"query"},
+		"properties": map[string]any{
+			"query": map[string]any{"type": "string", "minLength": 1},
+			"limit": map[string]any{"type": "integer", "minimum": 1, "default": 10},
+			"exact": map[string]any{"type": "boolean", "default": false},
+			"filters": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type":     "object",
+					"required": []any{"field"},
+					"properties": map[string]any{
+						"field": map[string]any{"type": "string"},
+						"value": map[string]any{"type": "string"},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	return schema
+}
+
+func TestArgumentValidationHook(t *testing.T) {
+	t.Parallel()
+	assert.Nil(t, NewArgumentValidationHook(nil))
+
+	schema := searchInputSchema(t)
+	searchTool := &MockTool{
+		ToolFunc: func() *v1.Tool {
+			return v1.Tool_builder{ServiceId: proto.String("docs"), Name: proto.String("search"), InputSchema: schema}.Build()
+		},
+	}
+	validate := func(cfg *configv1.ArgumentValidationConfig, inputs string) (*ExecutionRequest, error) {
+		h := NewArgumentValidationHook(cfg)
+		require.NotNil(t, h)
+		_, modified, err := h.ExecutePre(context.Background(), &ExecutionRequest{
+			ToolName:   "docs.search",
+			ToolInputs: []byte(inputs),
+			Tool:       searchTool,
+		})
+		return modified, err
+	}
+	enabled := configv1.ArgumentValidationConfig_builder{Enabled: proto.Bool(true)}.Build()
+
+	t.Run("valid", func(t *testing.T) {
+		modified, err := validate(enabled, `{"query": "mcp", "limit": 5}`)
+		require.NoError(t, err)
+		assert.Nil(t, modified)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := validate(enabled, `{"limit": "5", "filters": [{"value": 1}]}`)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrInvalidArguments))
+		var verr *ArgumentValidationError
+		require.True(t, errors.As(err, &verr))
+		assert.Equal(t, "docs.search", verr.Tool)
+		var paths []string
+		for _, v := range verr.Violations {
+			paths = append(paths, v.Path)
+		}
+		assert.Equal(t, []string{"/", "/filters/0", "/filters/0/value", "/limit"}, paths)
+		assert.Contains(t, err.Error(), `invalid arguments for tool "docs.search": /: missing properties: 'query'`)
+		assert.Contains(t, err.Error(), "/limit: expected integer, but got string")
+	})
+
+	t.Run("malformed JSON", func(t *testing.T) {
+		_, err := validate(enabled, `{"query": `)
+		assert.True(t, errors.Is(err, ErrInvalidArguments))
+	})
+
+	t.Run("inject defaults and coerce types", func(t *testing.T) {
+		cfg := configv1.ArgumentValidationConfig_builder{
+			Enabled:        proto.Bool(true),
+			InjectDefaults: proto.Bool(true),
+			CoerceTypes:    proto.Bool(true),
+		}.Build()
+		modified, err := validate(cfg, `{"query": 42, "exact": "TRUE", "filters": [{"field": "lang", "value": true}]}`)
+		require.NoError(t, err)
+		require.NotNil(t, modified)
+		assert.JSONEq(t, `{"query": "42", "limit": 10, "exact": true, "filters": [{"field": "lang", "value": "true"}]}`, string(modified.ToolInputs))
+		assert.Equal(t, float64(10), modified.Arguments["limit"])
+
+		modified, err = validate(cfg, `{"query": "mcp", "limit": "0"}`)
+		assert.Nil(t, modified)
+		assert.EqualError(t, err, `invalid arguments for tool "docs.search": /limit: must be >= 1 but found 0`)
+	})
+
+	t.Run("tool without schema", func(t *testing.T) {
+		h := NewArgumentValidationHook(enabled)
+		action, modified, err := h.ExecutePre(context.Background(), &ExecutionRequest{
+			ToolName:   "docs.ping",
+			ToolInputs: []byte(`"anything"`),
+			Tool:       &MockTool{ToolFunc: func() *v1.Tool { return v1.Tool_builder{Name: proto.String("ping")}.Build() }},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, ActionAllow, action)
+		assert.Nil(t, modified)
+	})
+}
+
+func TestToolManager_ExecuteTool_ArgumentValidation(t *testing.T) {
+	t.Parallel()
+	tm := NewManager(nil)
+
+	schema := searchInputSchema(t)
+	var received string
+	require.NoError(t, tm.AddTool(&MockTool{
+		ToolFunc: func() *v1.Tool {
+			return v1.Tool_builder{ServiceId: proto.String("docs"), Name: proto.String("search"), InputSchema: schema}.Build()
+		},
+		ExecuteFunc: func(_ context.Context, req *ExecutionRequest) (any, error) {
+			received = string(req.ToolInputs)
+			return "ok", nil
+		},
+	}))
+	tm.AddServiceInfo("docs", &ServiceInfo{
+		Name: "docs",
+		Config: configv1.UpstreamServiceConfig_builder{
+			Name: proto.String("docs"),
+			ArgumentValidation: configv1.ArgumentValidationConfig_builder{
+				Enabled:        proto.Bool(true),
+				InjectDefaults: proto.Bool(true),
+			}.Build(),
+		}.Build(),
+	})
+
+	_, err := tm.ExecuteTool(context.Background(), &ExecutionRequest{ToolName: "docs.search", ToolInputs: []byte(`{"limit": 3}`)})
+	assert.True(t, errors.Is(err, ErrInvalidArguments))
+	assert.Empty(t, received, "invalid call must not reach the upstream")
+
+	_, err = tm.ExecuteTool(context.Background(), &ExecutionRequest{ToolName: "docs.search", ToolInputs: []byte(`{"query": "mcp"}`)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"query": "mcp", "limit": 10, "exact": false}`, received)
+}
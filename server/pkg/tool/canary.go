@@ -0,0 +1,386 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/metrics"
+	"github.com/mcpany/core/server/pkg/util"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultCanaryMinCalls = 20
+	defaultCanaryWindow   = 5 * time.Minute
+)
+
+var (
+	metricToolsCallCanary       = []string{"tools", "call", "canary"}
+	metricCanaryRollbacksTotal  = []string{"canary", "rollbacks", "total"}
+	errCanaryWeightOutOfRange   = errors.New("canary weight must be between 0 and 100")
+	canaryRollbackObserverValue atomic.Pointer[CanaryRollbackObserver]
+)
+
+// ErrCanaryNotFound is returned when a service has no canary.
+var ErrCanaryNotFound = errors.New("canary not found")
+
+// CanaryController is implemented by the tool managers that route a share of
+// the calls of a service to its canary, so the admin API can watch and steer
+// the rollout.
+//
+// Summary: Interface for the canary rollouts of the services.
+type CanaryController interface {
+	// ListCanaries returns the canary rollouts, sorted by stable service.
+	//
+	// Summary: Lists the canary rollouts.
+	//
+	// Returns:
+	//   - []CanaryStatus: The rollouts.
+	ListCanaries() []CanaryStatus
+
+	// SetCanaryWeight sets the percentage of the calls of a stable service
+	// routed to its canary. It clears a rollback and the recorded calls.
+	//
+	// Summary: Steers a canary rollout.
+	//
+	// Parameters:
+	//   - stableServiceID: string. The ID of the stable service.
+	//   - weight: int32. The percentage, from 0 to 100.
+	//
+	// Returns:
+	//   - CanaryStatus: The rollout after the change.
+	//   - error: ErrCanaryNotFound, or an error if the weight is out of range.
+	SetCanaryWeight(stableServiceID string, weight int32) (CanaryStatus, error)
+}
+
+// CanaryStatus is the state of the canary rollout of a service.
+//
+// Summary: The state of a canary rollout.
+type CanaryStatus struct {
+	// StableService is the ID of the stable service.
+	StableService string `json:"stable_service"`
+	// CanaryService is the ID of the canary service.
+	CanaryService string `json:"canary_service"`
+	// Weight is the current percentage of the calls routed to the canary.
+	Weight int32 `json:"weight"`
+	// ConfiguredWeight is the weight of the configuration of the canary.
+	ConfiguredWeight int32 `json:"configured_weight"`
+	// MaxErrorRate is the error rate triggering a rollback, or 0.
+	MaxErrorRate float64 `json:"max_error_rate"`
+	// Calls is the number of canary calls within the window.
+	Calls int `json:"calls"`
+	// Errors is the number of failed canary calls within the window.
+	Errors int `json:"errors"`
+	// ErrorRate is Errors divided by Calls, or 0 without calls.
+	ErrorRate float64 `json:"error_rate"`
+	// RolledBack is whether the canary was rolled back for its error rate.
+	RolledBack bool `json:"rolled_back"`
+	// RolledBackAt is when the canary was rolled back.
+	RolledBackAt *time.Time `json:"rolled_back_at,omitempty"`
+}
+
+// CanaryRollbackObserver is notified when a canary is rolled back for its
+// error rate. It is called on the request path, so it must not block.
+//
+// Parameters:
+//   - status: The rollout after the rollback.
+type CanaryRollbackObserver func(status CanaryStatus)
+
+// SetCanaryRollbackObserver installs the observer notified of the automatic
+// rollbacks of all canaries. Passing nil removes it.
+//
+// Parameters:
+//   - observer: The observer, or nil.
+//
+// Side Effects:
+//   - Replaces the process-wide observer.
+func SetCanaryRollbackObserver(observer CanaryRollbackObserver) {
+	if observer == nil {
+		canaryRollbackObserverValue.Store(nil)
+		return
+	}
+	canaryRollbackObserverValue.Store(&observer)
+}
+
+// canaryOutcome is the outcome of a canary call.
+type canaryOutcome struct {
+	at     time.Time
+	failed bool
+}
+
+// canaryRoute routes a share of the calls of a stable service to a canary
+// service and rolls it back when the canary fails too often.
+type canaryRoute struct {
+	stable string
+	canary string
+	config *configv1.CanaryConfig
+
+	mu           sync.Mutex
+	weight       int32
+	outcomes     []canaryOutcome
+	rolledBackAt time.Time
+}
+
+func newCanaryRoute(stable, canary string, config *configv1.CanaryConfig) *canaryRoute {
+	return &canaryRoute{stable: stable, canary: canary, config: config, weight: config.GetWeight()}
+}
+
+func (r *canaryRoute) window() time.Duration {
+	if r.config.HasWindow() && r.config.GetWindow().AsDuration() > 0 {
+		return r.config.GetWindow().AsDuration()
+	}
+	return defaultCanaryWindow
+}
+
+func (r *canaryRoute) minCalls() int {
+	if r.config.GetMinCalls() > 0 {
+		return int(r.config.GetMinCalls())
+	}
+	return defaultCanaryMinCalls
+}
+
+// pick reports whether a call goes to the canary.
+func (r *canaryRoute) pick() bool {
+	r.mu.Lock()
+	weight := r.weight
+	r.mu.Unlock()
+	return weight > 0 && (weight >= 100 || rand.IntN(100) < int(weight)) //nolint:gosec // Traffic splitting needs no cryptographic randomness.
+}
+
+// prune drops the outcomes older than the window. It must be called with mu
+// held.
+func (r *canaryRoute) prune(now time.Time) {
+	cutoff := now.Add(-r.window())
+	i := sort.Search(len(r.outcomes), func(i int) bool { return r.outcomes[i].at.After(cutoff) })
+	r.outcomes = r.outcomes[i:]
+}
+
+// record records the outcome of a canary call and rolls the canary back if
+// its error rate exceeds the maximum.
+func (r *canaryRoute) record(failed bool) {
+	now := time.Now()
+	r.mu.Lock()
+	r.outcomes = append(r.outcomes, canaryOutcome{at: now, failed: failed})
+	r.prune(now)
+	maxRate := r.config.GetMaxErrorRate()
+	if maxRate <= 0 || r.weight == 0 || len(r.outcomes) < r.minCalls() {
+		r.mu.Unlock()
+		return
+	}
+	status := r.statusLocked()
+	if status.ErrorRate <= maxRate {
+		r.mu.Unlock()
+		return
+	}
+	r.weight = 0
+	r.rolledBackAt = now
+	status = r.statusLocked()
+	r.mu.Unlock()
+
+	metrics.IncrCounterWithLabels(metricCanaryRollbacksTotal, 1, []metrics.Label{
+		{Name: "stable_service", Value: r.stable},
+		{Name: "canary_service", Value: r.canary},
+	})
+	logging.GetLogger().Warn("Canary rolled back: error rate above the maximum",
+		"stableService", r.stable, "canaryService", r.canary,
+		"errorRate", status.ErrorRate, "maxErrorRate", maxRate, "calls", status.Calls)
+	if observer := canaryRollbackObserverValue.Load(); observer != nil {
+		(*observer)(status)
+	}
+}
+
+// setWeight sets the weight and starts a new window.
+func (r *canaryRoute) setWeight(weight int32) CanaryStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.weight = weight
+	r.outcomes = nil
+	r.rolledBackAt = time.Time{}
+	return r.statusLocked()
+}
+
+func (r *canaryRoute) status() CanaryStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	r.prune(now)
+	return r.statusLocked()
+}
+
+// statusLocked must be called with mu held.
+func (r *canaryRoute) statusLocked() CanaryStatus {
+	s := CanaryStatus{
+		StableService:    r.stable,
+		CanaryService:    r.canary,
+		Weight:           r.weight,
+		ConfiguredWeight: r.config.GetWeight(),
+		MaxErrorRate:     r.config.GetMaxErrorRate(),
+		Calls:            len(r.outcomes),
+	}
+	for _, o := range r.outcomes {
+		if o.failed {
+			s.Errors++
+		}
+	}
+	if s.Calls > 0 {
+		s.ErrorRate = float64(s.Errors) / float64(s.Calls)
+	}
+	if !r.rolledBackAt.IsZero() {
+		at := r.rolledBackAt
+		s.RolledBack = true
+		s.RolledBackAt = &at
+	}
+	return s
+}
+
+// canaryRoutes holds the canary routes, by the ID of their stable service.
+// The zero value is ready to use.
+type canaryRoutes struct {
+	mu       sync.RWMutex
+	byStable map[string]*canaryRoute
+}
+
+// forStable returns the route of the canary of a stable service, or nil.
+func (c *canaryRoutes) forStable(stableServiceID string) *canaryRoute {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.byStable[stableServiceID]
+}
+
+// isCanary reports whether a service is the canary of another.
+func (c *canaryRoutes) isCanary(serviceID string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, r := range c.byStable {
+		if r.canary == serviceID {
+			return true
+		}
+	}
+	return false
+}
+
+// configure makes a service the canary of the stable service of the config,
+// or removes its route if config is nil. A route whose config is unchanged
+// keeps its state, so a reload does not undo a rollback. It reports whether
+// the routes changed.
+func (c *canaryRoutes) configure(serviceID string, config *configv1.CanaryConfig) bool {
+	var stable string
+	if config != nil {
+		var err error
+		if stable, err = util.SanitizeServiceName(config.GetStableService()); err != nil || stable == "" {
+			logging.GetLogger().Error("Ignoring canary with an invalid stable_service", "serviceID", serviceID, "stableService", config.GetStableService(), "error", err)
+			config = nil
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if config != nil {
+		if r, ok := c.byStable[stable]; ok && r.canary == serviceID && proto.Equal(r.config, config) {
+			return false
+		}
+	}
+	changed := false
+	for s, r := range c.byStable {
+		if r.canary == serviceID {
+			delete(c.byStable, s)
+			changed = true
+		}
+	}
+	if config == nil {
+		return changed
+	}
+	if r, ok := c.byStable[stable]; ok {
+		logging.GetLogger().Warn("Replacing the canary of a service", "stableService", stable, "previousCanary", r.canary, "canary", serviceID)
+	}
+	if c.byStable == nil {
+		c.byStable = make(map[string]*canaryRoute)
+	}
+	c.byStable[stable] = newCanaryRoute(stable, serviceID, proto.Clone(config).(*configv1.CanaryConfig))
+	logging.GetLogger().Info("Canary configured", "stableService", stable, "canaryService", serviceID, "weight", config.GetWeight())
+	return true
+}
+
+// routeToCanary returns the canary tool of the same name as t if the call
+// goes to the canary of the service of t, with the route to record the
+// outcome on. Otherwise it returns t and nil.
+func (tm *Manager) routeToCanary(t Tool) (Tool, *canaryRoute) {
+	stable := t.Tool().GetServiceId()
+	route := tm.canaries.forStable(stable)
+	if route == nil || !route.pick() {
+		return t, nil
+	}
+	ct, ok := tm.GetTool(GetFullyQualifiedToolName(route.canary, t.Tool().GetName()))
+	if !ok {
+		return t, nil
+	}
+	if info, ok := tm.serviceInfo.Load(route.canary); ok && info.HealthStatus == HealthStatusUnhealthy {
+		return t, nil
+	}
+	metrics.IncrCounterWithLabels(metricToolsCallCanary, 1, []metrics.Label{
+		{Name: "stable_service", Value: stable},
+		{Name: "canary_service", Value: route.canary},
+	})
+	return ct, route
+}
+
+// ListCanaries returns the canary rollouts, sorted by stable service.
+//
+// Summary: Lists the canary rollouts.
+//
+// Returns:
+//   - []CanaryStatus: The rollouts.
+func (tm *Manager) ListCanaries() []CanaryStatus {
+	tm.canaries.mu.RLock()
+	routes := make([]*canaryRoute, 0, len(tm.canaries.byStable))
+	for _, r := range tm.canaries.byStable {
+		routes = append(routes, r)
+	}
+	tm.canaries.mu.RUnlock()
+
+	statuses := make([]CanaryStatus, 0, len(routes))
+	for _, r := range routes {
+		statuses = append(statuses, r.status())
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].StableService < statuses[j].StableService })
+	return statuses
+}
+
+// SetCanaryWeight sets the percentage of the calls of a stable service routed
+// to its canary. It clears a rollback and the recorded calls.
+//
+// Summary: Steers a canary rollout.
+//
+// Parameters:
+//   - stableServiceID: string. The ID of the stable service.
+//   - weight: int32. The percentage, from 0 to 100.
+//
+// Returns:
+//   - CanaryStatus: The rollout after the change.
+//   - error: ErrCanaryNotFound, or an error if the weight is out of range.
+//
+// Side Effects:
+//   - Changes the share of the calls routed to the canary until the next
+//     change of its configuration.
+func (tm *Manager) SetCanaryWeight(stableServiceID string, weight int32) (CanaryStatus, error) {
+	if weight < 0 || weight > 100 {
+		return CanaryStatus{}, fmt.Errorf("%w, got %d", errCanaryWeightOutOfRange, weight)
+	}
+	route := tm.canaries.forStable(stableServiceID)
+	if route == nil {
+		return CanaryStatus{}, fmt.Errorf("%w: service %q has no canary", ErrCanaryNotFound, stableServiceID)
+	}
+	status := route.setWeight(weight)
+	logging.GetLogger().Info("Canary weight set", "stableService", stableServiceID, "canaryService", route.canary, "weight", weight)
+	return status, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	v1 "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func addCanaryTestTool(t *testing.T, tm *Manager, serviceID string, err error) {
+	t.Helper()
+	require.NoError(t, tm.AddTool(&MockTool{
+		ToolFunc: func() *v1.Tool {
+			return v1.Tool_builder{ServiceId: proto.String(serviceID), Name: proto.String("get_forecast")}.Build()
+		},
+		ExecuteFunc: func(_ context.Context, _ *ExecutionRequest) (any, error) {
+			return serviceID, err
+		},
+	}))
+}
+
+func addCanaryTestService(tm *Manager, serviceID string, canary *configv1.CanaryConfig) {
+	tm.AddServiceInfo(serviceID, &ServiceInfo{
+		Name: serviceID,
+		Config: configv1.UpstreamServiceConfig_builder{
+			Name:   proto.String(serviceID),
+			Canary: canary,
+		}.Build(),
+	})
+}
+
+func TestManager_Canary(t *testing.T) {
+	tm := NewManager(nil)
+	addCanaryTestTool(t, tm, "weather", nil)
+	addCanaryTestTool(t, tm, "weather-v2", nil)
+	addCanaryTestService(tm, "weather", nil)
+	addCanaryTestService(tm, "weather-v2", configv1.CanaryConfig_builder{
+		StableService: proto.String("weather"),
+		Weight:        proto.Int32(100),
+	}.Build())
+
+	// The canary tools are hidden but still callable by their own name.
+	require.Len(t, tm.ListTools(), 1)
+	assert.Equal(t, "weather", tm.ListTools()[0].Tool().GetServiceId())
+	result, err := tm.ExecuteTool(context.Background(), &ExecutionRequest{ToolName: "weather-v2.get_forecast"})
+	require.NoError(t, err)
+	assert.Equal(t, "weather-v2", result)
+
+	result, err = tm.ExecuteTool(context.Background(), &ExecutionRequest{ToolName: "weather.get_forecast"})
+	require.NoError(t, err)
+	assert.Equal(t, "weather-v2", result, "all the calls go to the canary at weight 100")
+
+	status, err := tm.SetCanaryWeight("weather", 0)
+	require.NoError(t, err)
+	assert.Equal(t, CanaryStatus{StableService: "weather", CanaryService: "weather-v2", ConfiguredWeight: 100}, status)
+	result, err = tm.ExecuteTool(context.Background(), &ExecutionRequest{ToolName: "weather.get_forecast"})
+	require.NoError(t, err)
+	assert.Equal(t, "weather", result)
+
+	_, err = tm.SetCanaryWeight("weather", 101)
+	assert.Error(t, err)
+	_, err = tm.SetCanaryWeight("weather-v2", 10)
+	assert.ErrorIs(t, err, ErrCanaryNotFound)
+
+	// A reload with the same config keeps the runtime weight.
+	addCanaryTestService(tm, "weather-v2", configv1.CanaryConfig_builder{
+		StableService: proto.String("weather"),
+		Weight:        proto.Int32(100),
+	}.Build())
+	assert.Equal(t, int32(0), tm.ListCanaries()[0].Weight)
+
+	// Removing the canary config lists the tools of the former canary again.
+	addCanaryTestService(tm, "weather-v2", nil)
+	assert.Empty(t, tm.ListCanaries())
+	assert.Len(t, tm.ListTools(), 2)
+}
+
+func TestManager_CanaryRollback(t *testing.T) {
+	var rolledBack []CanaryStatus
+	SetCanaryRollbackObserver(func(s CanaryStatus) { rolledBack = append(rolledBack, s) })
+	defer SetCanaryRollbackObserver(nil)
+
+	tm := NewManager(nil)
+	addCanaryTestTool(t, tm, "weather", nil)
+	addCanaryTestTool(t, tm, "weather-v2", errors.New("boom"))
+	addCanaryTestService(tm, "weather", nil)
+	addCanaryTestService(tm, "weather-v2", configv1.CanaryConfig_builder{
+		StableService: proto.String("weather"),
+		Weight:        proto.Int32(100),
+		MaxErrorRate:  proto.Float64(0.5),
+		MinCalls:      proto.Int32(3),
+	}.Build())
+
+	for i := 0; i < 2; i++ {
+		_, err := tm.ExecuteTool(context.Background(), &ExecutionRequest{ToolName: "weather.get_forecast"})
+		assert.EqualError(t, err, "boom")
+	}
+	assert.Empty(t, rolledBack, "no rollback before min_calls")
+
+	_, err := tm.ExecuteTool(context.Background(), &ExecutionRequest{ToolName: "weather.get_forecast"})
+	assert.EqualError(t, err, "boom")
+	require.Len(t, rolledBack, 1)
+	assert.True(t, rolledBack[0].RolledBack)
+	assert.Equal(t, int32(0), rolledBack[0].Weight)
+	assert.Equal(t, 3, rolledBack[0].Errors)
+	assert.InDelta(t, 1.0, rolledBack[0].ErrorRate, 1e-9)
+
+	// The stable service takes the calls back.
+	result, err := tm.ExecuteTool(context.Background(), &ExecutionRequest{ToolName: "weather.get_forecast"})
+	require.NoError(t, err)
+	assert.Equal(t, "weather", result)
+
+	// Setting the weight resumes the rollout.
+	status, err := tm.SetCanaryWeight("weather", 100)
+	require.NoError(t, err)
+	assert.False(t, status.RolledBack)
+	assert.Zero(t, status.Calls)
+}
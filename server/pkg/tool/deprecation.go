@@ -0,0 +1,150 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/metrics"
+)
+
+// DeprecationMetaKey is the result metadata key that carries the deprecation
+// notice of a call made with the old name of a renamed tool.
+const DeprecationMetaKey = "deprecation"
+
+var metricToolsCallDeprecatedName = []string{"tools", "call", "deprecated_name"}
+
+// DeprecatedToolName is an old name of a renamed tool that is still accepted,
+// configured by a ToolAlias of the service.
+//
+// Summary: An old tool name and its replacement.
+type DeprecatedToolName struct {
+	// Name is the old fully qualified name of the tool.
+	Name string
+	// Tool is the current fully qualified name of the tool.
+	Tool string
+	// Until is when the old name stops working. It is zero if no date is set.
+	Until time.Time
+}
+
+// Message describes the rename for the caller.
+//
+// Returns:
+//   - string: e.g. `tool "weather.forecast" was renamed to "weather.get_forecast"`.
+func (d *DeprecatedToolName) Message() string {
+	msg := fmt.Sprintf("tool %q was renamed to %q", d.Name, d.Tool)
+	if !d.Until.IsZero() {
+		msg += "; the old name stops working on " + d.Until.Format(time.RFC3339)
+	}
+	return msg
+}
+
+// notice is the deprecation notice attached to the result of a call.
+func (d *DeprecatedToolName) notice() map[string]any {
+	n := map[string]any{
+		"deprecatedName": d.Name,
+		"replacement":    d.Tool,
+		"message":        d.Message(),
+	}
+	if !d.Until.IsZero() {
+		n["deprecatedUntil"] = d.Until.Format(time.RFC3339)
+	}
+	return n
+}
+
+// compileDeprecatedNames indexes the tool aliases of a service by their old
+// tool name.
+func compileDeprecatedNames(serviceID string, aliases []*configv1.ToolAlias) map[string]*DeprecatedToolName {
+	if len(aliases) == 0 {
+		return nil
+	}
+	names := make(map[string]*DeprecatedToolName, len(aliases))
+	for _, alias := range aliases {
+		if alias.GetName() == "" || alias.GetTool() == "" {
+			continue
+		}
+		d := &DeprecatedToolName{
+			Name: GetFullyQualifiedToolName(serviceID, alias.GetName()),
+			Tool: GetFullyQualifiedToolName(serviceID, alias.GetTool()),
+		}
+		if v := alias.GetDeprecatedUntil(); v != "" {
+			until, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				logging.GetLogger().Error("Ignoring tool alias with malformed deprecated_until", "serviceID", serviceID, "alias", alias.GetName(), "error", err)
+				continue
+			}
+			d.Until = until
+		}
+		names[alias.GetName()] = d
+	}
+	return names
+}
+
+// resolveDeprecatedName resolves a call made with the old name of a renamed
+// tool. It returns a nil Tool and error if toolName is not an old name, and
+// an error naming the new tool once the old name stopped working.
+func (tm *Manager) resolveDeprecatedName(ctx context.Context, toolName string) (Tool, *DeprecatedToolName, error) {
+	serviceID, name, err := ParseToolName(toolName)
+	if err != nil || serviceID == "" {
+		return nil, nil, nil
+	}
+	info, ok := tm.serviceInfo.Load(serviceID)
+	if !ok {
+		return nil, nil, nil
+	}
+	d, ok := info.DeprecatedNames[name]
+	if !ok {
+		return nil, nil, nil
+	}
+	if !d.Until.IsZero() && !time.Now().Before(d.Until) {
+		return nil, d, fmt.Errorf("%w: %q was renamed to %q and the old name stopped working on %s",
+			ErrToolNotFound, d.Name, d.Tool, d.Until.Format(time.RFC3339))
+	}
+	t, ok := tm.GetTool(d.Tool)
+	if !ok {
+		return nil, nil, nil
+	}
+	// The caller looked up the old name, so the profile was not checked yet.
+	if profileID, _ := auth.ProfileIDFromContext(ctx); profileID != "" && !tm.IsServiceAllowed(serviceID, profileID) {
+		return nil, d, fmt.Errorf("access denied to tool %q", toolName)
+	}
+
+	metrics.IncrCounterWithLabels(metricToolsCallDeprecatedName, 1, []metrics.Label{
+		{Name: "tool", Value: d.Name},
+		{Name: "replacement", Value: d.Tool},
+		{Name: "service_id", Value: serviceID},
+	})
+	log := logging.GetLogger().With("toolName", d.Name, "replacement", d.Tool)
+	if !d.Until.IsZero() {
+		log = log.With("deprecatedUntil", d.Until.Format(time.RFC3339))
+	}
+	log.Warn("Tool called by its deprecated name")
+	return t, d, nil
+}
+
+// withDeprecationNotice attaches the deprecation notice of d to the result
+// metadata.
+func withDeprecationNotice(result any, d *DeprecatedToolName) any {
+	// A map shaped like a CallToolResult is converted by the server, which
+	// keeps its "_meta".
+	if m, ok := result.(map[string]any); ok {
+		_, hasContent := m["content"]
+		_, hasIsError := m["isError"]
+		if hasContent || hasIsError {
+			meta, _ := m["_meta"].(map[string]any)
+			if meta == nil {
+				meta = map[string]any{}
+				m["_meta"] = meta
+			}
+			meta[DeprecationMetaKey] = d.notice()
+			return m
+		}
+	}
+	return withResultMeta(result, DeprecationMetaKey, d.notice())
+}
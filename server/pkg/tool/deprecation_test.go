@@ -0,0 +1,97 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	v1 "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestToolManager_ExecuteTool_DeprecatedName(t *testing.T) {
+	t.Parallel()
+	tm := NewManager(nil)
+
+	var executedAs string
+	require.NoError(t, tm.AddTool(&MockTool{
+		ToolFunc: func() *v1.Tool {
+			return v1.Tool_builder{ServiceId: proto.String("weather"), Name: proto.String("get_forecast")}.Build()
+		},
+		ExecuteFunc: func(_ context.Context, req *ExecutionRequest) (any, error) {
+			executedAs = req.ToolName
+			return map[string]any{"forecast": "sunny"}, nil
+		},
+	}))
+	until := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	tm.AddServiceInfo("weather", &ServiceInfo{
+		Name: "weather",
+		Config: configv1.UpstreamServiceConfig_builder{
+			Name: proto.String("weather"),
+			ToolAliases: []*configv1.ToolAlias{
+				configv1.ToolAlias_builder{Name: proto.String("forecast"), Tool: proto.String("get_forecast"), DeprecatedUntil: proto.String(until.Format(time.RFC3339))}.Build(),
+				configv1.ToolAlias_builder{Name: proto.String("weather_forecast"), Tool: proto.String("get_forecast"), DeprecatedUntil: proto.String("2020-01-01T00:00:00Z")}.Build(),
+				configv1.ToolAlias_builder{Name: proto.String("outlook"), Tool: proto.String("get_outlook")}.Build(),
+			},
+		}.Build(),
+	})
+
+	t.Run("within the window", func(t *testing.T) {
+		result, err := tm.ExecuteTool(context.Background(), &ExecutionRequest{ToolName: "weather.forecast", ToolInputs: []byte(`{}`)})
+		require.NoError(t, err)
+		assert.Equal(t, "weather.get_forecast", executedAs)
+
+		ctr, ok := result.(*mcp.CallToolResult)
+		require.True(t, ok)
+		assert.Equal(t, `{"forecast":"sunny"}`, ctr.Content[0].(*mcp.TextContent).Text)
+		assert.Equal(t, map[string]any{
+			"deprecatedName":  "weather.forecast",
+			"replacement":     "weather.get_forecast",
+			"deprecatedUntil": until.Format(time.RFC3339),
+			"message":         `tool "weather.forecast" was renamed to "weather.get_forecast"; the old name stops working on ` + until.Format(time.RFC3339),
+		}, ctr.Meta[DeprecationMetaKey])
+	})
+
+	t.Run("after the window", func(t *testing.T) {
+		_, err := tm.ExecuteTool(context.Background(), &ExecutionRequest{ToolName: "weather.weather_forecast", ToolInputs: []byte(`{}`)})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrToolNotFound))
+		assert.Contains(t, err.Error(), `"weather.weather_forecast" was renamed to "weather.get_forecast" and the old name stopped working on 2020-01-01T00:00:00Z`)
+	})
+
+	t.Run("new tool missing", func(t *testing.T) {
+		_, err := tm.ExecuteTool(context.Background(), &ExecutionRequest{ToolName: "weather.outlook", ToolInputs: []byte(`{}`)})
+		assert.True(t, errors.Is(err, ErrToolNotFound))
+	})
+
+	t.Run("current name has no notice", func(t *testing.T) {
+		result, err := tm.ExecuteTool(context.Background(), &ExecutionRequest{ToolName: "weather.get_forecast", ToolInputs: []byte(`{}`)})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"forecast": "sunny"}, result)
+	})
+}
+
+func TestWithDeprecationNotice_CallToolResultMap(t *testing.T) {
+	d := &DeprecatedToolName{Name: "svc.old", Tool: "svc.new"}
+	result := withDeprecationNotice(map[string]any{
+		"content": []any{map[string]any{"type": "text", "text": "hi"}},
+	}, d)
+
+	m, ok := result.(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, map[string]any{
+		DeprecationMetaKey: map[string]any{
+			"deprecatedName": "svc.old",
+			"replacement":    "svc.new",
+			"message":        `tool "svc.old" was renamed to "svc.new"`,
+		},
+	}, m["_meta"])
+}
@@ -0,0 +1,211 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"sync"
+)
+
+// ServiceDrainer is implemented by the tool managers that track the calls in
+// flight on each service, so that a configuration reload can let them finish
+// before it tears a service down.
+//
+// Summary: Interface for waiting for the in-flight calls of a service.
+type ServiceDrainer interface {
+	// DrainService waits until the service has no call in flight.
+	//
+	// Summary: Waits for the in-flight calls of a service.
+	//
+	// Parameters:
+	//   - ctx: context.Context. The deadline of the wait.
+	//   - serviceID: string. The ID of the service.
+	//
+	// Returns:
+	//   - int: The number of calls still in flight when ctx is done, or 0.
+	DrainService(ctx context.Context, serviceID string) int
+
+	// InFlightCalls returns the number of calls in flight on a service.
+	//
+	// Summary: Counts the in-flight calls of a service.
+	//
+	// Parameters:
+	//   - serviceID: string. The ID of the service.
+	//
+	// Returns:
+	//   - int: The number of calls in flight.
+	InFlightCalls(serviceID string) int
+}
+
+// Drainer is implemented by the tool managers that track all their calls in
+// flight, so that the server can let them finish before it terminates.
+//
+// Summary: Interface for waiting for all the in-flight calls.
+type Drainer interface {
+	// Drain waits until no call is in flight.
+	//
+	// Summary: Waits for all the in-flight calls.
+	//
+	// Parameters:
+	//   - ctx: context.Context. The deadline of the wait.
+	//
+	// Returns:
+	//   - int: The number of calls still in flight when ctx is done, or 0.
+	Drain(ctx context.Context) int
+
+	// TotalInFlightCalls returns the number of calls in flight on all the
+	// services.
+	//
+	// Summary: Counts all the in-flight calls.
+	//
+	// Returns:
+	//   - int: The number of calls in flight.
+	TotalInFlightCalls() int
+}
+
+// inFlightCalls counts the calls in flight on each service. The zero value is
+// ready to use.
+type inFlightCalls struct {
+	mu     sync.Mutex
+	counts map[string]int
+	total  int
+	// idle holds, for the services being drained, a channel closed when
+	// their last call ends.
+	idle map[string]chan struct{}
+	// allIdle, if set, is closed when the last call of all services ends.
+	allIdle chan struct{}
+}
+
+func (c *inFlightCalls) begin(serviceID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = make(map[string]int)
+	}
+	c.counts[serviceID]++
+	c.total++
+}
+
+func (c *inFlightCalls) end(serviceID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.total--; c.total == 0 && c.allIdle != nil {
+		close(c.allIdle)
+		c.allIdle = nil
+	}
+	if c.counts[serviceID]--; c.counts[serviceID] > 0 {
+		return
+	}
+	delete(c.counts, serviceID)
+	if ch, ok := c.idle[serviceID]; ok {
+		close(ch)
+		delete(c.idle, serviceID)
+	}
+}
+
+func (c *inFlightCalls) count(serviceID string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counts[serviceID]
+}
+
+// idleCh returns a channel closed when the service has no call in flight.
+func (c *inFlightCalls) idleCh(serviceID string) <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts[serviceID] == 0 {
+		ch := make(chan struct{})
+		close(ch)
+		return ch
+	}
+	if c.idle == nil {
+		c.idle = make(map[string]chan struct{})
+	}
+	ch, ok := c.idle[serviceID]
+	if !ok {
+		ch = make(chan struct{})
+		c.idle[serviceID] = ch
+	}
+	return ch
+}
+
+// allIdleCh returns a channel closed when no call is in flight.
+func (c *inFlightCalls) allIdleCh() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.total == 0 {
+		ch := make(chan struct{})
+		close(ch)
+		return ch
+	}
+	if c.allIdle == nil {
+		c.allIdle = make(chan struct{})
+	}
+	return c.allIdle
+}
+
+// DrainService waits until the service has no call in flight, or until ctx is
+// done. New calls are still accepted while it waits.
+//
+// Summary: Waits for the in-flight calls of a service.
+//
+// Parameters:
+//   - ctx: context.Context. The deadline of the wait.
+//   - serviceID: string. The ID of the service.
+//
+// Returns:
+//   - int: The number of calls still in flight when ctx is done, or 0.
+func (tm *Manager) DrainService(ctx context.Context, serviceID string) int {
+	select {
+	case <-tm.calls.idleCh(serviceID):
+		return 0
+	case <-ctx.Done():
+		return tm.calls.count(serviceID)
+	}
+}
+
+// InFlightCalls returns the number of calls in flight on a service.
+//
+// Summary: Counts the in-flight calls of a service.
+//
+// Parameters:
+//   - serviceID: string. The ID of the service.
+//
+// Returns:
+//   - int: The number of calls in flight.
+func (tm *Manager) InFlightCalls(serviceID string) int {
+	return tm.calls.count(serviceID)
+}
+
+// Drain waits until no call is in flight on any service, or until ctx is
+// done. New calls are still accepted while it waits.
+//
+// Summary: Waits for all the in-flight calls.
+//
+// Parameters:
+//   - ctx: context.Context. The deadline of the wait.
+//
+// Returns:
+//   - int: The number of calls still in flight when ctx is done, or 0.
+func (tm *Manager) Drain(ctx context.Context) int {
+	select {
+	case <-tm.calls.allIdleCh():
+		return 0
+	case <-ctx.Done():
+		return tm.TotalInFlightCalls()
+	}
+}
+
+// TotalInFlightCalls returns the number of calls in flight on all the
+// services.
+//
+// Summary: Counts all the in-flight calls.
+//
+// Returns:
+//   - int: The number of calls in flight.
+func (tm *Manager) TotalInFlightCalls() int {
+	tm.calls.mu.Lock()
+	defer tm.calls.mu.Unlock()
+	return tm.calls.total
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestManager_DrainService(t *testing.T) {
+	tm := NewManager(nil)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	require.NoError(t, tm.AddTool(&MockTool{
+		ToolFunc: func() *v1.Tool {
+			return v1.Tool_builder{ServiceId: proto.String("weather"), Name: proto.String("get_forecast")}.Build()
+		},
+		ExecuteFunc: func(_ context.Context, _ *ExecutionRequest) (any, error) {
+			started <- struct{}{}
+			<-release
+			return "ok", nil
+		},
+	}))
+
+	// A service without calls is drained right away.
+	assert.Equal(t, 0, tm.DrainService(context.Background(), "weather"))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = tm.ExecuteTool(context.Background(), &ExecutionRequest{ToolName: "weather.get_forecast"})
+	}()
+	<-started
+	assert.Equal(t, 1, tm.InFlightCalls("weather"))
+	assert.Equal(t, 0, tm.InFlightCalls("other"))
+
+	// The deadline passes while the call is in flight.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, 1, tm.DrainService(ctx, "weather"))
+
+	// The drain ends when the call does.
+	drained := make(chan int)
+	go func() { drained <- tm.DrainService(context.Background(), "weather") }()
+	close(release)
+	select {
+	case n := <-drained:
+		assert.Equal(t, 0, n)
+	case <-time.After(5 * time.Second):
+		t.Fatal("drain did not end with the call")
+	}
+	<-done
+	assert.Equal(t, 0, tm.InFlightCalls("weather"))
+}
+
+func TestManager_Drain(t *testing.T) {
+	tm := NewManager(nil)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	for _, serviceID := range []string{"weather", "news"} {
+		require.NoError(t, tm.AddTool(&MockTool{
+			ToolFunc: func() *v1.Tool {
+				return v1.Tool_builder{ServiceId: proto.String(serviceID), Name: proto.String("get")}.Build()
+			},
+			ExecuteFunc: func(_ context.Context, _ *ExecutionRequest) (any, error) {
+				started <- struct{}{}
+				<-release
+				return "ok", nil
+			},
+		}))
+	}
+
+	assert.Equal(t, 0, tm.Drain(context.Background()))
+
+	done := make(chan struct{}, 2)
+	for _, name := range []string{"weather.get", "news.get"} {
+		go func() {
+			_, _ = tm.ExecuteTool(context.Background(), &ExecutionRequest{ToolName: name})
+			done <- struct{}{}
+		}()
+		<-started
+	}
+	assert.Equal(t, 2, tm.TotalInFlightCalls())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, 2, tm.Drain(ctx))
+
+	drained := make(chan int)
+	go func() { drained <- tm.Drain(context.Background()) }()
+	close(release)
+	select {
+	case n := <-drained:
+		assert.Equal(t, 0, n)
+	case <-time.After(5 * time.Second):
+		t.Fatal("drain did not end with the calls")
+	}
+	<-done
+	<-done
+	assert.Equal(t, 0, tm.TotalInFlightCalls())
+}
@@ -7,3 +7,14 @@ import "errors"
 
 // ErrToolNotFound is returned when a requested tool cannot be found.
 var ErrToolNotFound = errors.New("unknown tool")
+
+// ErrInvalidArguments is returned when the arguments of a tool call do not
+// match the input schema of the tool.
+var ErrInvalidArguments = errors.New("invalid arguments")
+
+// ErrServiceNotDiscovered is returned when a tool listed from the catalog
+// snapshot of a service is called before the service is discovered.
+var ErrServiceNotDiscovered = errors.New("service not discovered yet")
+
+// ErrJobNotFound is returned for an unknown or expired job.
+var ErrJobNotFound = errors.New("job not found")
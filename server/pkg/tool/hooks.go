@@ -4,12 +4,11 @@
 package tool
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"net/url"
 	"regexp"
 	"time"
 
@@ -17,8 +16,14 @@ import (
 	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
 	"github.com/google/uuid"
 	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/metrics"
+	"github.com/mcpany/core/server/pkg/telemetry"
+	"github.com/mcpany/core/server/pkg/webhooks"
 	configv1 "github.com/mcpany/core/proto/config/v1"
 	webhook "github.com/standard-webhooks/standard-webhooks/libraries/go"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // compiledRule holds the pre-compiled regexes for a policy rule.
@@ -156,11 +161,30 @@ func (h *PolicyHook) ExecutePre(
 // Summary: Client for sending CloudEvents to external webhooks.
 type WebhookClient struct {
 	url     string
+	host    string
 	timeout time.Duration
 	client  *http.Client
 	webhook *webhook.Webhook
+	// maxRetries is how many times a failed attempt is retried, the first
+	// after retryBackoff and each next one after twice the previous delay.
+	maxRetries   int
+	retryBackoff time.Duration
+	failOpen     bool
+	// review calls the webhook over gRPC instead of HTTP.
+	review *reviewClient
+	// setupErr fails the calls of a webhook whose client could not be set up.
+	setupErr error
 }
 
+const defaultWebhookRetryBackoff = 100 * time.Millisecond
+
+var (
+	metricWebhookLatency    = []string{"webhook", "latency"}
+	metricWebhookFailures   = []string{"webhook", "failures"}
+	metricWebhookRetries    = []string{"webhook", "retries"}
+	metricWebhookFailedOpen = []string{"webhook", "failed_open"}
+)
+
 // NewWebhookClient creates a new WebhookClient.
 //
 // Summary: Initializes a new WebhookClient.
@@ -186,25 +210,77 @@ func NewWebhookClient(config *configv1.WebhookConfig) *WebhookClient {
 			logging.GetLogger().Error("Failed to create webhook signer", "error", err)
 		}
 	}
+	var previous []*webhook.Webhook
+	for _, secret := range config.GetPreviousSecrets() {
+		signer, err := webhook.NewWebhook(secret)
+		if err != nil {
+			logging.GetLogger().Error("Failed to create webhook signer for a previous secret", "error", err)
+			continue
+		}
+		previous = append(previous, signer)
+	}
 
-	// Create client with signing transport if webhook signer is present
-	client := &http.Client{Timeout: timeout}
-	if wh != nil {
-		client.Transport = &SigningRoundTripper{
-			signer: wh,
-			base:   http.DefaultTransport,
+	retryBackoff := defaultWebhookRetryBackoff
+	if b := config.GetRetryBackoff(); b != nil && b.AsDuration() > 0 {
+		retryBackoff = b.AsDuration()
+	}
+	c := &WebhookClient{
+		url:          config.GetUrl(),
+		timeout:      timeout,
+		webhook:      wh,
+		maxRetries:   max(int(config.GetMaxRetries()), 0),
+		retryBackoff: retryBackoff,
+		failOpen:     config.GetFailurePolicy() == configv1.WebhookConfig_FAIL_OPEN,
+	}
+
+	if config.GetProtocol() == configv1.WebhookConfig_GRPC {
+		var signers []*webhook.Webhook
+		if wh != nil {
+			signers = append([]*webhook.Webhook{wh}, previous...)
 		}
+		c.host = config.GetUrl()
+		c.review, c.setupErr = newReviewClient(config, signers)
+		if c.setupErr != nil {
+			logging.GetLogger().Error("Failed to create gRPC webhook client", "target", config.GetUrl(), "error", c.setupErr)
+		}
+		return c
+	}
+
+	// A webhook with mTLS gets its own transport. A webhook whose TLS
+	// settings cannot be loaded fails its calls.
+	base, err := webhooks.NewMTLSTransport(config.GetMtls())
+	if err != nil {
+		logging.GetLogger().Error("Failed to load webhook mTLS settings", "url", config.GetUrl(), "error", err)
+		c.setupErr = err
+		base = http.DefaultTransport
 	}
 
-	return &WebhookClient{
-		url:     config.GetUrl(),
-		timeout: timeout,
-		client:  client,
-		webhook: wh,
+	// Create client with signing transport if webhook signer is present.
+	// The trace context is propagated to the webhook.
+	c.client = &http.Client{Timeout: timeout, Transport: otelhttp.NewTransport(base)}
+	if wh != nil {
+		c.client.Transport = &webhooks.SigningTransport{
+			Signers: append([]*webhook.Webhook{wh}, previous...),
+			Base:    c.client.Transport,
+		}
+	}
+	if u, err := url.Parse(config.GetUrl()); err == nil {
+		c.host = u.Host
 	}
+	return c
+}
+
+// FailOpen reports whether a call hook goes on when the webhook fails.
+//
+// Returns:
+//   - bool: True if the failure policy of the webhook is FAIL_OPEN.
+func (c *WebhookClient) FailOpen() bool {
+	return c.failOpen
 }
 
 // Call sends a cloud event to the webhook and returns the response event.
+// A failed attempt is retried up to the max_retries of the webhook, with an
+// exponential backoff. Each attempt is bounded by the timeout of the webhook.
 //
 // Summary: Sends a synchronous CloudEvent to the webhook URL.
 //
@@ -225,8 +301,73 @@ func NewWebhookClient(config *configv1.WebhookConfig) *WebhookClient {
 // Side Effects:
 //   - Makes an external HTTP POST request.
 func (c *WebhookClient) Call(ctx context.Context, eventType string, data any) (*cloudevents.Event, error) {
+	respEvent, _, err := c.call(ctx, eventType, data)
+	return respEvent, err
+}
+
+// call is Call, also returning the number of attempts made.
+func (c *WebhookClient) call(ctx context.Context, eventType string, data any) (_ *cloudevents.Event, attempts int, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "webhook "+eventType, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("webhook.event_type", eventType),
+		attribute.String("server.address", c.host),
+	))
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	labels := []metrics.Label{
+		{Name: "event_type", Value: eventType},
+		{Name: "host", Value: c.host},
+	}
+	backoff := c.retryBackoff
+	// The retries resend the same event, so that the receiver can tell them
+	// apart from new events by their ID.
+	eventID := uuid.New().String()
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		respEvent, err := c.send(ctx, eventID, eventType, data)
+		status := "success"
+		if err != nil {
+			status = "error"
+		}
+		metrics.MeasureSinceWithLabels(metricWebhookLatency, start, append(labels, metrics.Label{Name: "status", Value: status}))
+		if err == nil {
+			span.SetAttributes(attribute.Int("webhook.attempts", attempt+1))
+			return respEvent, attempt + 1, nil
+		}
+		metrics.IncrCounterWithLabels(metricWebhookFailures, 1, labels)
+		if attempt >= c.maxRetries || ctx.Err() != nil {
+			span.SetAttributes(attribute.Int("webhook.attempts", attempt+1))
+			if attempt > 0 {
+				return nil, attempt + 1, fmt.Errorf("%w (after %d attempts)", err, attempt+1)
+			}
+			return nil, attempt + 1, err
+		}
+
+		logging.GetLogger().Warn("Webhook call failed, retrying", "url", c.url, "event_type", eventType,
+			"attempt", attempt+1, "backoff", backoff, "error", err)
+		metrics.IncrCounterWithLabels(metricWebhookRetries, 1, labels)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, attempt + 1, fmt.Errorf("%w (retry cancelled: %w)", err, ctx.Err())
+		}
+		backoff *= 2
+	}
+}
+
+// send makes one attempt of a call to the webhook.
+func (c *WebhookClient) send(ctx context.Context, eventID, eventType string, data any) (*cloudevents.Event, error) {
+	if c.setupErr != nil {
+		return nil, c.setupErr
+	}
+	if c.review != nil {
+		ctx, cancel := context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+		return c.review.send(ctx, eventID, data)
+	}
 	event := cloudevents.NewEvent()
-	event.SetID(uuid.New().String())
+	event.SetID(eventID)
 	event.SetSource("https://github.com/mcpany/core")
 	event.SetType(eventType)
 	event.SetTime(time.Now())
@@ -265,7 +406,11 @@ func (c *WebhookClient) Call(ctx context.Context, eventType string, data any) (*
 //
 // Summary: Hook implementation that delegates logic to an external webhook.
 type WebhookHook struct {
-	client *WebhookClient
+	client  *WebhookClient
+	matcher *webhooks.Matcher
+	// serviceID and name identify the hook in its dead letters.
+	serviceID string
+	name      string
 }
 
 // NewWebhookHook creates a new WebhookHook.
@@ -278,9 +423,58 @@ type WebhookHook struct {
 // Returns:
 //   - *WebhookHook: The initialized hook.
 func NewWebhookHook(config *configv1.WebhookConfig) *WebhookHook {
+	matcher, err := webhooks.NewMatcher(config.GetMatch())
+	if err != nil {
+		// The configuration is validated on load, so this is not expected;
+		// the webhook then gets every call.
+		logging.GetLogger().Error("Invalid webhook match rules, sending every call", "url", config.GetUrl(), "error", err)
+	}
 	return &WebhookHook{
-		client: NewWebhookClient(config),
+		client:  NewWebhookClient(config),
+		matcher: matcher,
+	}
+}
+
+// matches reports whether the call is sent to the webhook, according to its
+// match rules.
+func (h *WebhookHook) matches(req *ExecutionRequest, arguments map[string]any, result any) bool {
+	if h.matcher == nil {
+		return true
+	}
+	service := h.serviceID
+	if req.Tool != nil && req.Tool.Tool() != nil {
+		service = req.Tool.Tool().GetServiceId()
+	}
+	if arguments == nil {
+		arguments = req.Arguments
+	}
+	if arguments == nil && len(req.ToolInputs) > 0 {
+		_ = json.Unmarshal(req.ToolInputs, &arguments)
+	}
+	matched, err := h.matcher.Match(webhooks.Call{
+		Tool:      req.ToolName,
+		Service:   service,
+		Arguments: arguments,
+		Result:    result,
+	})
+	if err != nil {
+		logging.GetLogger().Warn("Failed to match webhook, sending the call", "url", h.client.url, "tool", req.ToolName, "error", err)
+	}
+	return matched
+}
+
+// failOpen reports whether the call goes on despite the failure of the
+// webhook, logging it if so.
+func (h *WebhookHook) failOpen(req *ExecutionRequest, err error) bool {
+	if !h.client.FailOpen() {
+		return false
 	}
+	logging.GetLogger().Warn("Webhook failed, letting the call through (fail open)", "url", h.client.url, "tool", req.ToolName, "error", err)
+	metrics.IncrCounterWithLabels(metricWebhookFailedOpen, 1, []metrics.Label{
+		{Name: "host", Value: h.client.host},
+		{Name: "tool", Value: req.ToolName},
+	})
+	return true
 }
 
 // ExecutePre executes the webhook notification before a tool is called.
@@ -292,9 +486,9 @@ func NewWebhookHook(config *configv1.WebhookConfig) *WebhookHook {
 //   - req: *ExecutionRequest. The execution request.
 //
 // Returns:
-//   - Action: Allow or Deny based on webhook response.
+//   - Action: Allow or Deny based on webhook response, Allow if the call does not match the webhook.
 //   - *ExecutionRequest: Modified request if webhook returned replacements.
-//   - error: An error if webhook denies or fails.
+//   - error: An error if webhook denies, or fails and its failure policy is FAIL_CLOSED.
 //
 // Errors:
 //   - Returns error if input marshaling fails.
@@ -316,6 +510,10 @@ func (h *WebhookHook) ExecutePre(
 		}
 	}
 
+	if !h.matches(req, inputsMap, nil) {
+		return ActionAllow, nil, nil
+	}
+
 	data := map[string]any{
 		"kind":      configv1.WebhookKind_WEBHOOK_KIND_PRE_CALL,
 		"tool_name": req.ToolName,
@@ -324,6 +522,9 @@ func (h *WebhookHook) ExecutePre(
 
 	respEvent, err := h.client.Call(ctx, "com.mcpany.tool.pre_call", data)
 	if err != nil {
+		if h.failOpen(req, err) {
+			return ActionAllow, nil, nil
+		}
 		return ActionDeny, nil, fmt.Errorf("webhook error: %w", err)
 	}
 
@@ -374,20 +575,24 @@ func (h *WebhookHook) ExecutePre(
 //   - result: any. The result of the tool execution.
 //
 // Returns:
-//   - any: The (potentially modified) result.
-//   - error: An error if the webhook call fails.
+//   - any: The (potentially modified) result, or the original result if the call does not match the webhook, or the webhook fails and its failure policy is FAIL_OPEN.
+//   - error: An error if the webhook call fails and its failure policy is FAIL_CLOSED.
 //
 // Errors:
 //   - Returns error if webhook call or response processing fails.
 //
 // Side Effects:
 //   - Invokes external webhook.
+//   - Dead-letters the event if the webhook call fails.
 func (h *WebhookHook) ExecutePost(
 	ctx context.Context,
 	req *ExecutionRequest,
 	result any,
 ) (any, error) {
 	logging.GetLogger().Info("ExecutePost called", "tool", req.ToolName)
+	if !h.matches(req, nil, result) {
+		return result, nil
+	}
 
 	data := map[string]any{
 		"kind":      configv1.WebhookKind_WEBHOOK_KIND_POST_CALL,
@@ -395,8 +600,12 @@ func (h *WebhookHook) ExecutePost(
 		"result":    result,
 	}
 
-	respEvent, err := h.client.Call(ctx, "com.mcpany.tool.post_call", data)
+	respEvent, attempts, err := h.client.call(ctx, "com.mcpany.tool.post_call", data)
 	if err != nil {
+		h.deadLetter(ctx, req, "com.mcpany.tool.post_call", data, attempts, err)
+		if h.failOpen(req, err) {
+			return result, nil
+		}
 		return nil, fmt.Errorf("webhook error: %w", err)
 	}
 
@@ -440,57 +649,3 @@ type WebhookStatus struct {
 	// Message is a descriptive message returned by the webhook.
 	Message string `json:"message"`
 }
-
-// SigningRoundTripper signs the request using the webhook signer.
-//
-// Summary: HTTP Transport that adds HMAC signatures to outgoing requests.
-type SigningRoundTripper struct {
-	signer *webhook.Webhook
-	base   http.RoundTripper
-}
-
-// RoundTrip executes the HTTP request with a signature.
-//
-// Summary: Intercepts the request to add Webhook-Id, Webhook-Timestamp, and Webhook-Signature headers.
-//
-// Parameters:
-//   - req: *http.Request. The outgoing request.
-//
-// Returns:
-//   - *http.Response: The received response.
-//   - error: An error if signing or transport fails.
-//
-// Side Effects:
-//   - Reads and buffers the request body for signing.
-//   - Modifies request headers.
-func (s *SigningRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
-	if s.signer != nil {
-		payload := []byte{} // Signing requires payload, but request body might be stream.
-
-		if req.Body != nil {
-			var err error
-			payload, err = io.ReadAll(req.Body)
-			if err != nil {
-				return nil, fmt.Errorf("failed to read request body for signing: %w", err)
-			}
-			req.Body = io.NopCloser(bytes.NewReader(payload))
-		}
-
-		msgID := uuid.New().String()
-		now := time.Now()
-		signature, err := s.signer.Sign(msgID, now, payload)
-		if err != nil {
-			return nil, fmt.Errorf("failed to sign request: %w", err)
-		}
-
-		req.Header.Set("Webhook-Id", msgID)
-		req.Header.Set("Webhook-Timestamp", fmt.Sprintf("%d", now.Unix()))
-		req.Header.Set("Webhook-Signature", signature)
-	}
-
-	base := s.base
-	if base == nil {
-		base = http.DefaultTransport
-	}
-	return base.RoundTrip(req)
-}
@@ -15,6 +15,7 @@ import (
 	"testing"
 
 	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/webhooks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
@@ -45,7 +46,7 @@ func TestSigningRoundTripper_RoundTrip(t *testing.T) {
 	hook := NewWebhookHook(config)
 
 	// Create a round tripper independently to test it
-	rt := hook.client.client.Transport.(*SigningRoundTripper)
+	rt := hook.client.client.Transport.(*webhooks.SigningTransport)
 	require.NotNil(t, rt)
 
 	// Create a request
@@ -62,7 +63,7 @@ func TestSigningRoundTripper_RoundTrip(t *testing.T) {
 			return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(""))}, nil
 		},
 	}
-	rt.base = mockTransport
+	rt.Base = mockTransport
 
 	resp, err := rt.RoundTrip(req)
 	if err == nil {
@@ -0,0 +1,56 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookHook_Match(t *testing.T) {
+	t.Parallel()
+	server, calls := newFlakyWebhook(t, 0, false, 0)
+	hook := NewWebhookHook(configv1.WebhookConfig_builder{
+		Url: server.URL,
+		Match: configv1.WebhookMatchConfig_builder{
+			Tools:     []string{"github.*"},
+			Condition: `!has(arguments.branch) || arguments.branch == "main"`,
+		}.Build(),
+	}.Build())
+
+	action, _, err := hook.ExecutePre(context.Background(), &ExecutionRequest{ToolName: "weather.forecast"})
+	require.NoError(t, err)
+	assert.Equal(t, ActionAllow, action, "a call that does not match is not sent")
+	action, _, err = hook.ExecutePre(context.Background(), &ExecutionRequest{ToolName: "github.push", ToolInputs: []byte(`{"branch":"dev"}`)})
+	require.NoError(t, err)
+	assert.Equal(t, ActionAllow, action)
+	assert.Equal(t, int32(0), calls.Load())
+
+	action, _, err = hook.ExecutePre(context.Background(), &ExecutionRequest{ToolName: "github.push", ToolInputs: []byte(`{"branch":"main"}`)})
+	require.Error(t, err)
+	assert.Equal(t, ActionDeny, action, "a matching call is sent, and denied by the webhook")
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestWebhookHook_MatchResult(t *testing.T) {
+	t.Parallel()
+	server, calls := newFlakyWebhook(t, 0, true, 0)
+	hook := NewWebhookHook(configv1.WebhookConfig_builder{
+		Url:   server.URL,
+		Match: configv1.WebhookMatchConfig_builder{Condition: "size(string(result.body)) > 10"}.Build(),
+	}.Build())
+
+	result, err := hook.ExecutePost(context.Background(), &ExecutionRequest{ToolName: "web.fetch"}, map[string]any{"body": "short"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"body": "short"}, result)
+	assert.Equal(t, int32(0), calls.Load())
+
+	_, err = hook.ExecutePost(context.Background(), &ExecutionRequest{ToolName: "web.fetch"}, map[string]any{"body": "<html>a long page</html>"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
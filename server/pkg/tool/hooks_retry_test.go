@@ -0,0 +1,115 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// newFlakyWebhook answers with a 500 to the first failures calls, then with
+// the given decision.
+func newFlakyWebhook(t *testing.T, failures int32, allowed bool, delay time.Duration) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		if n <= failures {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Ce-Id", "resp-id")
+		w.Header().Set("Ce-Specversion", "1.0")
+		w.Header().Set("Ce-Type", "resp-type")
+		w.Header().Set("Ce-Source", "server")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"allowed": allowed})
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestWebhookHook_Retries(t *testing.T) {
+	t.Parallel()
+	server, calls := newFlakyWebhook(t, 2, true, 0)
+	hook := NewWebhookHook(configv1.WebhookConfig_builder{
+		Url:          server.URL,
+		MaxRetries:   2,
+		RetryBackoff: durationpb.New(time.Millisecond),
+	}.Build())
+
+	action, _, err := hook.ExecutePre(context.Background(), &ExecutionRequest{ToolName: "svc.tool"})
+	require.NoError(t, err)
+	assert.Equal(t, ActionAllow, action)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestWebhookHook_RetriesExhausted(t *testing.T) {
+	t.Parallel()
+	server, calls := newFlakyWebhook(t, 100, true, 0)
+	hook := NewWebhookHook(configv1.WebhookConfig_builder{
+		Url:          server.URL,
+		MaxRetries:   1,
+		RetryBackoff: durationpb.New(time.Millisecond),
+	}.Build())
+
+	action, _, err := hook.ExecutePre(context.Background(), &ExecutionRequest{ToolName: "svc.tool"})
+	require.Error(t, err)
+	assert.Equal(t, ActionDeny, action, "a failed webhook fails the call by default")
+	assert.Contains(t, err.Error(), "after 2 attempts")
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestWebhookHook_DenyIsNotRetried(t *testing.T) {
+	t.Parallel()
+	server, calls := newFlakyWebhook(t, 0, false, 0)
+	hook := NewWebhookHook(configv1.WebhookConfig_builder{
+		Url:          server.URL,
+		MaxRetries:   3,
+		RetryBackoff: durationpb.New(time.Millisecond),
+	}.Build())
+
+	action, _, err := hook.ExecutePre(context.Background(), &ExecutionRequest{ToolName: "svc.tool"})
+	require.ErrorContains(t, err, "denied by webhook")
+	assert.Equal(t, ActionDeny, action)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestWebhookHook_FailOpen(t *testing.T) {
+	t.Parallel()
+	server, calls := newFlakyWebhook(t, 0, false, time.Second)
+	hook := NewWebhookHook(configv1.WebhookConfig_builder{
+		Url:           server.URL,
+		Timeout:       durationpb.New(20 * time.Millisecond),
+		FailurePolicy: configv1.WebhookConfig_FAIL_OPEN,
+	}.Build())
+	req := &ExecutionRequest{ToolName: "svc.tool"}
+
+	start := time.Now()
+	action, replaced, err := hook.ExecutePre(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, ActionAllow, action, "a timed out webhook lets the call through")
+	assert.Nil(t, replaced)
+	assert.Less(t, time.Since(start), 500*time.Millisecond, "the webhook timeout bounds the call")
+
+	result, err := hook.ExecutePost(context.Background(), req, "original")
+	require.NoError(t, err)
+	assert.Equal(t, "original", result)
+	assert.Equal(t, int32(2), calls.Load())
+}
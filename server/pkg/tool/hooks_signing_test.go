@@ -0,0 +1,173 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	webhook "github.com/standard-webhooks/standard-webhooks/libraries/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	testWebhookSecret         = "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+	testPreviousWebhookSecret = "whsec_dGVzdC1zZWNyZXQtdmFsaWQtYmFzZTY0"
+)
+
+// writeWebhookResponse answers a webhook call with an allowing CloudEvent.
+func writeWebhookResponse(w http.ResponseWriter) {
+	w.Header().Set("Ce-Id", "resp-id")
+	w.Header().Set("Ce-Specversion", "1.0")
+	w.Header().Set("Ce-Type", "resp-type")
+	w.Header().Set("Ce-Source", "server")
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"allowed": true})
+}
+
+func TestWebhookHook_SignsWithPreviousSecrets(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	var ids []string
+	var verifyErrs []error
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		ids = append(ids, r.Header.Get("Webhook-Id"))
+		for _, secret := range []string{testWebhookSecret, testPreviousWebhookSecret} {
+			wh, err := webhook.NewWebhook(secret)
+			require.NoError(t, err)
+			verifyErrs = append(verifyErrs, wh.Verify(body, r.Header))
+		}
+		first := len(ids) == 1
+		mu.Unlock()
+		if first {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeWebhookResponse(w)
+	}))
+	defer server.Close()
+
+	hook := NewWebhookHook(configv1.WebhookConfig_builder{
+		Url:             server.URL,
+		WebhookSecret:   testWebhookSecret,
+		PreviousSecrets: []string{testPreviousWebhookSecret},
+		MaxRetries:      1,
+		RetryBackoff:    durationpb.New(time.Millisecond),
+	}.Build())
+	action, _, err := hook.ExecutePre(context.Background(), &ExecutionRequest{ToolName: "svc.tool"})
+	require.NoError(t, err)
+	assert.Equal(t, ActionAllow, action)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, ids, 2)
+	assert.NotEmpty(t, ids[0])
+	assert.Equal(t, ids[0], ids[1], "a retry keeps the webhook-id of the event")
+	for _, err := range verifyErrs {
+		assert.NoError(t, err, "the receivers verifying with the new or the previous secret accept the request")
+	}
+}
+
+// writeTestKeyPair writes a self-signed certificate for the given usage and
+// its key to dir, and returns their paths.
+func writeTestKeyPair(t *testing.T, dir, name string, usage x509.ExtKeyUsage) (certPath, keyPath string) {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{usage},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+
+	certPath = filepath.Join(dir, name+".crt")
+	keyPath = filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certPath, keyPath
+}
+
+func TestWebhookHook_MTLS(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	clientCert, clientKey := writeTestKeyPair(t, dir, "client", x509.ExtKeyUsageClientAuth)
+	clientPEM, err := os.ReadFile(clientCert)
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	require.True(t, clientCAs.AppendCertsFromPEM(clientPEM))
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeWebhookResponse(w)
+	}))
+	server.TLS = &tls.Config{ClientCAs: clientCAs, ClientAuth: tls.RequireAndVerifyClientCert, MinVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+	serverCA := filepath.Join(dir, "server-ca.crt")
+	require.NoError(t, os.WriteFile(serverCA, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+
+	t.Run("client certificate", func(t *testing.T) {
+		hook := NewWebhookHook(configv1.WebhookConfig_builder{
+			Url: server.URL,
+			Mtls: configv1.MTLSAuth_builder{
+				ClientCertPath: proto.String(clientCert),
+				ClientKeyPath:  proto.String(clientKey),
+				CaCertPath:     proto.String(serverCA),
+			}.Build(),
+		}.Build())
+		action, _, err := hook.ExecutePre(context.Background(), &ExecutionRequest{ToolName: "svc.tool"})
+		require.NoError(t, err)
+		assert.Equal(t, ActionAllow, action)
+	})
+
+	t.Run("no client certificate", func(t *testing.T) {
+		hook := NewWebhookHook(configv1.WebhookConfig_builder{
+			Url:  server.URL,
+			Mtls: configv1.MTLSAuth_builder{CaCertPath: proto.String(serverCA)}.Build(),
+		}.Build())
+		_, _, err := hook.ExecutePre(context.Background(), &ExecutionRequest{ToolName: "svc.tool"})
+		require.Error(t, err)
+	})
+
+	t.Run("unreadable settings", func(t *testing.T) {
+		hook := NewWebhookHook(configv1.WebhookConfig_builder{
+			Url: server.URL,
+			Mtls: configv1.MTLSAuth_builder{
+				ClientCertPath: proto.String(filepath.Join(dir, "missing.crt")),
+				ClientKeyPath:  proto.String(clientKey),
+			}.Build(),
+		}.Build())
+		_, _, err := hook.ExecutePre(context.Background(), &ExecutionRequest{ToolName: "svc.tool"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "webhook mtls")
+	})
+}
@@ -10,6 +10,7 @@ import (
 
 	configv1 "github.com/mcpany/core/proto/config/v1"
 	v1 "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -128,3 +129,55 @@ func CalculateConfigHash(t *configv1.ToolDefinition) (string, error) {
 	hash := sha256.Sum256(data)
 	return hex.EncodeToString(hash[:]), nil
 }
+
+// CalculateMCPToolHash computes the SHA256 hash of a tool as listed by an
+// upstream MCP server. It is the hash that `mcpctl import mcp --pin` writes
+// to the integrity of the tool.
+//
+// Summary: Calculates hash for a tool listed by an MCP server.
+//
+// Parameters:
+//   - t: *mcp.Tool. The tool listed by the MCP server.
+//
+// Returns:
+//   - string: The hex-encoded SHA256 hash.
+//   - error: An error if the tool cannot be converted or marshaled.
+func CalculateMCPToolHash(t *mcp.Tool) (string, error) {
+	pbTool, err := ConvertMCPToolToProto(t)
+	if err != nil {
+		return "", err
+	}
+	return CalculateHash(pbTool)
+}
+
+// VerifyMCPToolIntegrity checks that a tool listed by an upstream MCP server
+// has not changed since its hash was pinned.
+//
+// Summary: Verifies the integrity of a tool listed by an MCP server.
+//
+// Parameters:
+//   - t: *mcp.Tool. The tool listed by the MCP server.
+//   - integrity: *configv1.Integrity. The pinned hash, or nil.
+//
+// Returns:
+//   - error: An error if the tool does not match the pinned hash.
+func VerifyMCPToolIntegrity(t *mcp.Tool, integrity *configv1.Integrity) error {
+	if integrity == nil {
+		return nil // No integrity check required
+	}
+
+	if integrity.GetAlgorithm() != "sha256" {
+		return fmt.Errorf("unsupported integrity algorithm: %s", integrity.GetAlgorithm())
+	}
+
+	calculatedHash, err := CalculateMCPToolHash(t)
+	if err != nil {
+		return fmt.Errorf("failed to calculate hash: %w", err)
+	}
+
+	if calculatedHash != integrity.GetHash() {
+		return fmt.Errorf("integrity check failed: expected %s, got %s", integrity.GetHash(), calculatedHash)
+	}
+
+	return nil
+}
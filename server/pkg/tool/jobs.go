@@ -0,0 +1,742 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/bus"
+	"github.com/mcpany/core/server/pkg/cluster"
+	"github.com/mcpany/core/server/pkg/idgen"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/metrics"
+	"github.com/mcpany/core/server/pkg/util"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultJobTTL is how long the result of a job is kept after it is done.
+const DefaultJobTTL = 24 * time.Hour
+
+var metricToolsJobs = []string{"tools", "jobs"}
+
+// JobStatus is the state of a job.
+type JobStatus string
+
+const (
+	// JobPending is a job waiting for a worker.
+	JobPending JobStatus = "pending"
+	// JobRunning is a job whose tool is running.
+	JobRunning JobStatus = "running"
+	// JobSucceeded is a job whose tool returned a result.
+	JobSucceeded JobStatus = "succeeded"
+	// JobFailed is a job whose tool failed.
+	JobFailed JobStatus = "failed"
+)
+
+// Done reports whether the job is over.
+//
+// Returns:
+//   - bool: True if the job succeeded or failed.
+func (s JobStatus) Done() bool {
+	return s == JobSucceeded || s == JobFailed
+}
+
+// Job is a call of an asynchronous tool, run in the background by the
+// workers. It is what the call returns, and what the get_job_status tool
+// reports.
+//
+// Summary: A background tool call and its outcome.
+type Job struct {
+	// ID identifies the job.
+	ID string `json:"jobId"`
+	// ToolName is the fully qualified name of the called tool.
+	ToolName string `json:"toolName"`
+	// Status is the state of the job.
+	Status JobStatus `json:"status"`
+	// Result is the JSON result of the tool, once the job succeeded.
+	Result json.RawMessage `json:"result,omitempty"`
+	// Error is the error of the tool, once the job failed.
+	Error string `json:"error,omitempty"`
+	// CreatedAt is when the call was made.
+	CreatedAt time.Time `json:"createdAt"`
+	// CompletedAt is when the job was done. It is nil until then.
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+
+	// owner is the user who made the call, the only one who may see the job.
+	owner string
+}
+
+// JobNotifier is a Session that is told when the jobs started in it are done.
+type JobNotifier interface {
+	// NotifyJobDone tells the client that a job is done.
+	//
+	// Summary: Notifies the client of a completed job.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context of the notification.
+	//   - job (*Job): The completed job.
+	//
+	// Returns:
+	//   - error: An error if the notification cannot be sent.
+	NotifyJobDone(ctx context.Context, job *Job) error
+}
+
+type jobContextKey struct{}
+
+// NewContextWithJob marks the context of a call made by a worker, which runs
+// the tool even if it is asynchronous.
+//
+// Summary: Marks a call as made by a worker.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - jobID (string): The correlation ID of the request, which is the ID of
+//     the job of an asynchronous tool.
+//
+// Returns:
+//   - context.Context: The marked context.
+func NewContextWithJob(ctx context.Context, jobID string) context.Context {
+	return context.WithValue(ctx, jobContextKey{}, jobID)
+}
+
+// jobIDFromContext returns the job a call is made by, if any.
+func jobIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(jobContextKey{}).(string)
+	return id, ok
+}
+
+// compileToolScheduling returns the tools of a service that run as jobs, and
+// the worker priority classes its tools set.
+func compileToolScheduling(serviceConfig *configv1.UpstreamServiceConfig) (async map[string]bool, classes map[string]string) {
+	for _, def := range serviceToolDefinitions(serviceConfig) {
+		if !def.GetAsync() && def.GetPriorityClass() == "" {
+			continue
+		}
+		name, err := util.SanitizeToolName(def.GetName())
+		if err != nil {
+			name = def.GetName()
+		}
+		if def.GetAsync() {
+			if async == nil {
+				async = make(map[string]bool)
+			}
+			async[name] = true
+		}
+		if def.GetPriorityClass() != "" {
+			if classes == nil {
+				classes = make(map[string]string)
+			}
+			classes[name] = def.GetPriorityClass()
+		}
+	}
+	return async, classes
+}
+
+// JobStore persists the jobs of the asynchronous tools, so that they survive a
+// restart of the server. storage.Storage implements it.
+type JobStore interface {
+	// SaveWorkerJob saves a job, replacing the one with the same ID.
+	//
+	// Summary: Persists a job.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - job (*configv1.WorkerJob): The job to save.
+	//
+	// Returns:
+	//   - error: An error if saving fails.
+	SaveWorkerJob(ctx context.Context, job *configv1.WorkerJob) error
+
+	// ListWorkerJobs retrieves the jobs, newest first.
+	//
+	// Summary: Lists the jobs.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - limit (int): The maximum number of jobs to return, 0 for all.
+	//
+	// Returns:
+	//   - []*configv1.WorkerJob: The jobs.
+	//   - error: An error if listing fails.
+	ListWorkerJobs(ctx context.Context, limit int) ([]*configv1.WorkerJob, error)
+
+	// GetWorkerJob retrieves a job by ID.
+	//
+	// Summary: Retrieves a job.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - id (string): The ID of the job.
+	//
+	// Returns:
+	//   - *configv1.WorkerJob: The job, or nil if not found.
+	//   - error: An error if retrieval fails.
+	GetWorkerJob(ctx context.Context, id string) (*configv1.WorkerJob, error)
+
+	// ClaimWorkerJob leases a job that is not done to a replica, if the
+	// replica holds the lease already or the lease expired. Only one of the
+	// replicas claiming a job at once gets it.
+	//
+	// Summary: Claims or renews the lease of a job.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - id (string): The ID of the job.
+	//   - owner (string): The ID of the claiming replica.
+	//   - now (string): The current time, in JobTimeFormat.
+	//   - leaseUntil (string): The end of the lease, in JobTimeFormat.
+	//
+	// Returns:
+	//   - bool: True if the replica holds the lease now.
+	//   - error: An error if the update fails.
+	ClaimWorkerJob(ctx context.Context, id, owner, now, leaseUntil string) (bool, error)
+
+	// DeleteWorkerJobsCompletedBefore deletes the jobs done before a time.
+	//
+	// Summary: Prunes the expired jobs.
+	//
+	// Parameters:
+	//   - ctx (context.Context): The context for the request.
+	//   - before (string): The time, in JobTimeFormat.
+	//
+	// Returns:
+	//   - error: An error if deletion fails.
+	DeleteWorkerJobsCompletedBefore(ctx context.Context, before string) error
+}
+
+// JobTimeFormat is the format of the times of the persisted jobs. It is RFC
+// 3339 with a fixed number of fractional digits, so that the times sort as
+// strings.
+const JobTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// jobPruneInterval is how often the expired jobs are deleted from a JobStore.
+const jobPruneInterval = time.Hour
+
+// jobLease is how long a replica holds the jobs it waits for without renewing
+// their leases. Once a lease expires, the leader resumes the job.
+const jobLease = time.Minute
+
+// JobLeaseRenewInterval is how often the leases of the jobs should be renewed,
+// and the jobs whose lease expired resumed.
+const JobLeaseRenewInterval = jobLease / 3
+
+// jobStore holds the jobs until ttl after they are done, in a JobStore.
+type jobStore struct {
+	ttl time.Duration
+
+	// mu serializes the updates of the jobs, which read and write them back.
+	mu        sync.Mutex
+	store     JobStore
+	lastPrune time.Time
+	// owner identifies this process in the leases of the jobs.
+	owner string
+	// active are the jobs whose results this process waits for, and holds
+	// the leases of.
+	active map[string]bool
+}
+
+func newJobStore(ttl time.Duration) *jobStore {
+	return &jobStore{ttl: ttl, store: newMemoryJobStore(), owner: idgen.New(), active: make(map[string]bool)}
+}
+
+// setStore replaces where the jobs are kept.
+func (s *jobStore) setStore(store JobStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store = store
+	s.lastPrune = time.Time{}
+}
+
+// create adds a pending job, and drops the expired ones now and then.
+func (s *jobStore) create(ctx context.Context, job *configv1.WorkerJob) error {
+	now := time.Now().UTC()
+	job.SetId(idgen.New())
+	job.SetStatus(string(JobPending))
+	job.SetCreatedAt(now.Format(JobTimeFormat))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.lastPrune) > jobPruneInterval && s.leadsHere() {
+		s.lastPrune = now
+		if err := s.store.DeleteWorkerJobsCompletedBefore(ctx, now.Add(-s.ttl).Format(JobTimeFormat)); err != nil {
+			logging.GetLogger().Warn("Failed to delete the expired jobs", "error", err)
+		}
+	}
+	if err := s.store.SaveWorkerJob(ctx, job); err != nil {
+		return fmt.Errorf("failed to save job: %w", err)
+	}
+	// A failed claim is retried by the next renewal.
+	if _, err := s.claim(ctx, job.GetId(), now); err != nil {
+		logging.GetLogger().Warn("Failed to claim job", "jobID", job.GetId(), "error", err)
+	}
+	s.active[job.GetId()] = true
+	return nil
+}
+
+// claim takes or renews the lease of a job for this process.
+func (s *jobStore) claim(ctx context.Context, id string, now time.Time) (bool, error) {
+	return s.store.ClaimWorkerJob(ctx, id, s.owner, now.Format(JobTimeFormat), now.Add(jobLease).Format(JobTimeFormat))
+}
+
+// renew extends the leases of the jobs this process waits for, and stops
+// waiting for the ones that are done or that another replica took over.
+func (s *jobStore) renew(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	for id := range s.active {
+		claimed, err := s.claim(ctx, id, now)
+		if err != nil {
+			logging.GetLogger().Warn("Failed to renew the lease of job", "jobID", id, "error", err)
+			continue
+		}
+		if !claimed {
+			delete(s.active, id)
+		}
+	}
+}
+
+// leadsHere reports whether this replica prunes and resumes the jobs: always
+// for the jobs kept in memory, and only on the leader for the shared storage.
+func (s *jobStore) leadsHere() bool {
+	if _, local := s.store.(*memoryJobStore); local {
+		return true
+	}
+	return cluster.IsLeader()
+}
+
+// start marks a job as running, and returns it. A job that is done is
+// returned as is, and nil if there is no such job.
+func (s *jobStore) start(ctx context.Context, id string) *Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, err := s.store.GetWorkerJob(ctx, id)
+	if err != nil || job == nil {
+		if err != nil {
+			logging.GetLogger().Error("Failed to load job", "jobID", id, "error", err)
+		}
+		return nil
+	}
+	if !JobStatus(job.GetStatus()).Done() {
+		job.SetStatus(string(JobRunning))
+		job.SetAttempts(job.GetAttempts() + 1)
+		if err := s.store.SaveWorkerJob(ctx, job); err != nil {
+			logging.GetLogger().Error("Failed to save job", "jobID", id, "error", err)
+		}
+	}
+	return jobFromProto(job)
+}
+
+// complete records the outcome of a job, once.
+func (s *jobStore) complete(ctx context.Context, id string, result json.RawMessage, err error) (*Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.active, id)
+	job, loadErr := s.store.GetWorkerJob(ctx, id)
+	if loadErr != nil || job == nil || JobStatus(job.GetStatus()).Done() {
+		if loadErr != nil {
+			logging.GetLogger().Error("Failed to load job", "jobID", id, "error", loadErr)
+		}
+		return nil, false
+	}
+	if err != nil {
+		job.SetStatus(string(JobFailed))
+		job.SetError(err.Error())
+	} else {
+		job.SetStatus(string(JobSucceeded))
+		job.SetResult(string(result))
+	}
+	job.SetCompletedAt(time.Now().UTC().Format(JobTimeFormat))
+	if err := s.store.SaveWorkerJob(ctx, job); err != nil {
+		logging.GetLogger().Error("Failed to save job", "jobID", id, "error", err)
+	}
+	return jobFromProto(job), true
+}
+
+// get returns a job that has not expired.
+func (s *jobStore) get(ctx context.Context, id string) (*Job, bool) {
+	s.mu.Lock()
+	store := s.store
+	s.mu.Unlock()
+	job, err := store.GetWorkerJob(ctx, id)
+	if err != nil || job == nil {
+		return nil, false
+	}
+	j := jobFromProto(job)
+	if j.CompletedAt != nil && time.Since(*j.CompletedAt) > s.ttl {
+		return nil, false
+	}
+	return j, true
+}
+
+// resumable claims the unfinished jobs of the matching services whose lease
+// expired, that is whose replica is gone, and marks them as waited for.
+func (s *jobStore) resumable(ctx context.Context, matches func(serviceID string) bool) ([]*configv1.WorkerJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs, err := s.store.ListWorkerJobs(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	// The replica that saved a job a moment ago may not have claimed it yet.
+	claimedBy := now.Add(-jobLease).Format(JobTimeFormat)
+	var resumed []*configv1.WorkerJob
+	for i := len(jobs) - 1; i >= 0; i-- {
+		job := jobs[i]
+		if !matches(job.GetServiceId()) || JobStatus(job.GetStatus()).Done() || s.active[job.GetId()] || job.GetCreatedAt() > claimedBy {
+			continue
+		}
+		claimed, err := s.claim(ctx, job.GetId(), now)
+		if err != nil {
+			logging.GetLogger().Warn("Failed to claim job", "jobID", job.GetId(), "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		s.active[job.GetId()] = true
+		resumed = append(resumed, job)
+	}
+	return resumed, nil
+}
+
+// jobFromProto returns the job of a persisted job.
+func jobFromProto(job *configv1.WorkerJob) *Job {
+	j := &Job{
+		ID:       job.GetId(),
+		ToolName: job.GetToolName(),
+		Status:   JobStatus(job.GetStatus()),
+		Error:    job.GetError(),
+		owner:    job.GetOwner(),
+	}
+	if job.GetResult() != "" {
+		j.Result = json.RawMessage(job.GetResult())
+	}
+	j.CreatedAt, _ = time.Parse(JobTimeFormat, job.GetCreatedAt())
+	if completedAt, err := time.Parse(JobTimeFormat, job.GetCompletedAt()); err == nil {
+		j.CompletedAt = &completedAt
+	}
+	return j
+}
+
+// outcome returns what the tool of a job that is done returned.
+func (j *Job) outcome() (any, error) {
+	if j.Status == JobFailed {
+		return nil, errors.New(j.Error)
+	}
+	return j.Result, nil
+}
+
+// memoryJobStore keeps the jobs in memory, when there is no storage.
+type memoryJobStore struct {
+	mu   sync.Mutex
+	jobs map[string]*configv1.WorkerJob
+	// leases are the owners of the jobs, and until when.
+	leases map[string][2]string
+}
+
+func newMemoryJobStore() *memoryJobStore {
+	return &memoryJobStore{jobs: make(map[string]*configv1.WorkerJob), leases: make(map[string][2]string)}
+}
+
+func (m *memoryJobStore) SaveWorkerJob(_ context.Context, job *configv1.WorkerJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs[job.GetId()] = proto.Clone(job).(*configv1.WorkerJob)
+	return nil
+}
+
+func (m *memoryJobStore) ListWorkerJobs(_ context.Context, limit int) ([]*configv1.WorkerJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := make([]*configv1.WorkerJob, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		list = append(list, proto.Clone(job).(*configv1.WorkerJob))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].GetCreatedAt() != list[j].GetCreatedAt() {
+			return list[i].GetCreatedAt() > list[j].GetCreatedAt()
+		}
+		return list[i].GetId() > list[j].GetId()
+	})
+	if limit > 0 && len(list) > limit {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func (m *memoryJobStore) GetWorkerJob(_ context.Context, id string) (*configv1.WorkerJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, nil
+	}
+	return proto.Clone(job).(*configv1.WorkerJob), nil
+}
+
+func (m *memoryJobStore) ClaimWorkerJob(_ context.Context, id, owner, now, leaseUntil string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok || job.GetCompletedAt() != "" {
+		return false, nil
+	}
+	if lease := m.leases[id]; lease[0] != owner && lease[1] >= now {
+		return false, nil
+	}
+	m.leases[id] = [2]string{owner, leaseUntil}
+	return true, nil
+}
+
+func (m *memoryJobStore) DeleteWorkerJobsCompletedBefore(_ context.Context, before string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, job := range m.jobs {
+		if job.GetCompletedAt() != "" && job.GetCompletedAt() < before {
+			delete(m.jobs, id)
+			delete(m.leases, id)
+		}
+	}
+	return nil
+}
+
+// SetJobStore sets where the jobs of the asynchronous tools are kept, so that
+// they survive a restart. The jobs are kept in memory until then.
+//
+// Summary: Persists the jobs.
+//
+// Parameters:
+//   - store (JobStore): The store of the jobs.
+//
+// Side Effects:
+//   - The jobs kept in memory before are no longer visible.
+func (tm *Manager) SetJobStore(store JobStore) {
+	tm.jobs.setStore(store)
+}
+
+// SetJobStatusTool sets the built-in tool that polls the jobs. It is only
+// added once a service has an asynchronous tool, so that the clients of the
+// servers without any do not see it.
+//
+// Summary: Sets the tool that polls the jobs.
+//
+// Parameters:
+//   - t (Tool): The tool.
+//
+// Side Effects:
+//   - Adds the tool at once if an asynchronous tool is already registered.
+func (tm *Manager) SetJobStatusTool(t Tool) {
+	tm.mu.Lock()
+	tm.jobStatusTool = t
+	tm.mu.Unlock()
+	if tm.hasAsyncTools.Load() {
+		tm.addJobStatusTool()
+	}
+}
+
+// addJobStatusTool adds the tool that polls the jobs, once.
+func (tm *Manager) addJobStatusTool() {
+	tm.hasAsyncTools.Store(true)
+	tm.mu.RLock()
+	t := tm.jobStatusTool
+	tm.mu.RUnlock()
+	if t == nil || !tm.jobStatusToolAdded.CompareAndSwap(false, true) {
+		return
+	}
+	if err := tm.AddTool(t); err != nil {
+		logging.GetLogger().Error("Failed to register built-in tools", "error", err)
+	}
+}
+
+// isAsync reports whether a tool runs as a job.
+func (tm *Manager) isAsync(t Tool) bool {
+	info, ok := tm.serviceInfo.Load(t.Tool().GetServiceId())
+	return ok && info.AsyncTools[t.Tool().GetName()]
+}
+
+// priorityClass returns the worker priority class of the calls of a tool,
+// or fallback if the tool does not set one.
+func (tm *Manager) priorityClass(t Tool, fallback string) string {
+	if info, ok := tm.serviceInfo.Load(t.Tool().GetServiceId()); ok {
+		if class := info.PriorityClasses[t.Tool().GetName()]; class != "" {
+			return class
+		}
+	}
+	return fallback
+}
+
+// submitJob starts a job that calls a tool through the workers, and returns
+// the job at once.
+func (tm *Manager) submitJob(ctx context.Context, t Tool, req *ExecutionRequest) (*Job, error) {
+	if tm.bus == nil {
+		return nil, fmt.Errorf("tool %s is asynchronous, and no message bus is available to run it", req.ToolName)
+	}
+	inputs := req.ToolInputs
+	if inputs == nil && req.Arguments != nil {
+		var err error
+		if inputs, err = json.Marshal(req.Arguments); err != nil {
+			return nil, fmt.Errorf("failed to marshal tool arguments: %w", err)
+		}
+	}
+
+	owner, _ := auth.UserFromContext(ctx)
+	job := configv1.WorkerJob_builder{
+		ServiceId:     proto.String(t.Tool().GetServiceId()),
+		ToolName:      proto.String(req.ToolName),
+		Owner:         proto.String(owner),
+		ToolInputs:    proto.String(string(inputs)),
+		PriorityClass: proto.String(tm.priorityClass(t, bus.PriorityBatch)),
+	}.Build()
+	if err := tm.jobs.create(ctx, job); err != nil {
+		return nil, err
+	}
+	// The job outlives the call, but keeps its user, profile and session
+	if err := tm.dispatchJob(context.WithoutCancel(ctx), job); err != nil {
+		return nil, err
+	}
+	logging.GetLogger().Info("Job submitted", "toolName", req.ToolName, "jobID", job.GetId())
+	return jobFromProto(job), nil
+}
+
+// dispatchJob publishes the request of a job for the workers, and records its
+// result when it comes back.
+func (tm *Manager) dispatchJob(ctx context.Context, job *configv1.WorkerJob) error {
+	requestBus, err := bus.GetBus[*bus.ToolExecutionRequest](tm.bus, bus.ToolExecutionRequestTopic)
+	if err != nil {
+		tm.jobs.complete(ctx, job.GetId(), nil, err)
+		return fmt.Errorf("failed to get request bus: %w", err)
+	}
+	resultBus, err := bus.GetBus[*bus.ToolExecutionResult](tm.bus, bus.ToolExecutionResultTopic)
+	if err != nil {
+		tm.jobs.complete(ctx, job.GetId(), nil, err)
+		return fmt.Errorf("failed to get result bus: %w", err)
+	}
+
+	log := logging.GetLogger().With("toolName", job.GetToolName(), "jobID", job.GetId())
+	session, _ := GetSession(ctx)
+	unsubscribe := resultBus.SubscribeOnce(ctx, job.GetId(), func(res *bus.ToolExecutionResult) {
+		done, ok := tm.jobs.complete(ctx, job.GetId(), res.Result, res.Error)
+		if !ok {
+			return
+		}
+		log.Info("Job done", "status", done.Status)
+		metrics.IncrCounterWithLabels(metricToolsJobs, 1, []metrics.Label{{Name: "status", Value: string(done.Status)}})
+		if notifier, ok := session.(JobNotifier); ok {
+			if err := notifier.NotifyJobDone(ctx, done); err != nil {
+				log.Debug("Failed to notify client about the job", "error", err)
+			}
+		}
+	})
+
+	execReq := &bus.ToolExecutionRequest{
+		Context:        ctx,
+		ToolName:       job.GetToolName(),
+		ToolInputs:     json.RawMessage(job.GetToolInputs()),
+		PriorityClass:  job.GetPriorityClass(),
+		Tenant:         job.GetOwner(),
+		IdempotencyKey: job.GetId(),
+	}
+	execReq.SetCorrelationID(job.GetId())
+	if err := requestBus.Publish(ctx, "request", execReq); err != nil {
+		unsubscribe()
+		tm.jobs.complete(ctx, job.GetId(), nil, err)
+		return fmt.Errorf("failed to publish request: %w", err)
+	}
+	return nil
+}
+
+// ResumeJobs submits again the jobs of a service that were not done when
+// their replica stopped. It is called once the service is registered, so that
+// its tools can run, and then every JobLeaseRenewInterval for the jobs of
+// replicas that stop later. A job that was running starts over.
+//
+// Only the leader resumes jobs, and only those whose lease expired; it claims
+// each one, so that no other replica runs it again.
+//
+// Summary: Resumes the unfinished jobs of a service.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the server.
+//   - serviceID (string): The ID of the service, or empty for all the
+//     registered services.
+//
+// Returns:
+//   - int: The number of resumed jobs.
+//   - error: An error if the jobs cannot be listed.
+//
+// Side Effects:
+//   - Publishes the requests of the jobs on the bus.
+func (tm *Manager) ResumeJobs(ctx context.Context, serviceID string) (int, error) {
+	if tm.bus == nil || !tm.jobs.leadsHere() {
+		return 0, nil
+	}
+	matches := func(id string) bool { return id == serviceID }
+	if serviceID == "" {
+		matches = func(id string) bool {
+			_, ok := tm.serviceInfo.Load(id)
+			return ok
+		}
+	}
+	jobs, err := tm.jobs.resumable(ctx, matches)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	for _, job := range jobs {
+		// The client that started the job is gone, but its user remains
+		jobCtx := auth.ContextWithUser(context.WithoutCancel(ctx), job.GetOwner())
+		if err := tm.dispatchJob(jobCtx, job); err != nil {
+			logging.GetLogger().Error("Failed to resume job", "toolName", job.GetToolName(), "jobID", job.GetId(), "error", err)
+			continue
+		}
+		logging.GetLogger().Info("Job resumed", "toolName", job.GetToolName(), "jobID", job.GetId())
+	}
+	return len(jobs), nil
+}
+
+// RenewJobLeases extends the leases of the jobs this replica waits for, so
+// that the leader does not resume them. It is called every
+// JobLeaseRenewInterval.
+//
+// Summary: Renews the leases of the jobs of this replica.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the server.
+//
+// Side Effects:
+//   - Updates the leases of the jobs in the store.
+func (tm *Manager) RenewJobLeases(ctx context.Context) {
+	tm.jobs.renew(ctx)
+}
+
+// GetJob returns a job started by an asynchronous tool. The jobs of a user
+// are only visible to that user.
+//
+// Summary: Retrieves a job.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the caller, carrying its user.
+//   - id (string): The ID of the job.
+//
+// Returns:
+//   - *Job: A snapshot of the job.
+//   - error: ErrJobNotFound if there is no such job, or if it has expired.
+func (tm *Manager) GetJob(ctx context.Context, id string) (*Job, error) {
+	job, ok := tm.jobs.get(ctx, id)
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	if user, _ := auth.UserFromContext(ctx); job.owner != "" && job.owner != user {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
+}
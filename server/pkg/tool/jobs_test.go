@@ -0,0 +1,389 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	v1 "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/mcpany/core/server/pkg/auth"
+	"github.com/mcpany/core/server/pkg/bus"
+	"github.com/mcpany/core/server/pkg/cluster"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// jobNotifierSession records the jobs it is notified of.
+type jobNotifierSession struct {
+	Session
+	done chan *Job
+}
+
+func (s *jobNotifierSession) NotifyJobDone(_ context.Context, job *Job) error {
+	s.done <- job
+	return nil
+}
+
+// startTestWorker runs the requests of the bus like the upstream worker.
+func startTestWorker(t *testing.T, b *bus.Provider, tm *Manager) {
+	t.Helper()
+	requests, err := bus.GetBus[*bus.ToolExecutionRequest](b, bus.ToolExecutionRequestTopic)
+	require.NoError(t, err)
+	results, err := bus.GetBus[*bus.ToolExecutionResult](b, bus.ToolExecutionResultTopic)
+	require.NoError(t, err)
+	unsubscribe := requests.Subscribe(context.Background(), "request", func(req *bus.ToolExecutionRequest) {
+		result, err := tm.ExecuteTool(NewContextWithJob(req.Context, req.CorrelationID()), &ExecutionRequest{ToolName: req.ToolName, ToolInputs: req.ToolInputs})
+		res := &bus.ToolExecutionResult{Error: err}
+		if err == nil {
+			res.Result, _ = json.Marshal(result)
+		}
+		res.SetCorrelationID(req.CorrelationID())
+		_ = results.Publish(context.Background(), req.CorrelationID(), res)
+	})
+	t.Cleanup(unsubscribe)
+}
+
+func newAsyncTestManager(t *testing.T, execute func(context.Context, *ExecutionRequest) (any, error)) *Manager {
+	t.Helper()
+	b, err := bus.NewProvider(nil)
+	require.NoError(t, err)
+	tm := NewManager(b)
+	require.NoError(t, tm.AddTool(&MockTool{
+		ToolFunc: func() *v1.Tool {
+			return v1.Tool_builder{ServiceId: proto.String("reports"), Name: proto.String("build_report")}.Build()
+		},
+		ExecuteFunc: execute,
+	}))
+	tm.AddServiceInfo("reports", &ServiceInfo{
+		Name: "reports",
+		Config: configv1.UpstreamServiceConfig_builder{
+			Name: proto.String("reports"),
+			HttpService: configv1.HttpUpstreamService_builder{
+				Tools: []*configv1.ToolDefinition{
+					configv1.ToolDefinition_builder{Name: proto.String("build_report"), Async: proto.Bool(true)}.Build(),
+				},
+			}.Build(),
+		}.Build(),
+	})
+	startTestWorker(t, b, tm)
+	return tm
+}
+
+func TestManager_ExecuteTool_Async(t *testing.T) {
+	release := make(chan struct{})
+	tm := newAsyncTestManager(t, func(_ context.Context, req *ExecutionRequest) (any, error) {
+		<-release
+		var args map[string]any
+		_ = json.Unmarshal(req.ToolInputs, &args)
+		if args["fail"] == true {
+			return nil, errors.New("report failed")
+		}
+		return map[string]any{"pages": 12}, nil
+	})
+
+	t.Run("succeeded", func(t *testing.T) {
+		session := &jobNotifierSession{done: make(chan *Job, 1)}
+		ctx := auth.ContextWithUser(NewContextWithSession(context.Background(), session), "alice")
+		result, err := tm.ExecuteTool(ctx, &ExecutionRequest{ToolName: "reports.build_report", ToolInputs: []byte(`{}`)})
+		require.NoError(t, err)
+		job, ok := result.(*Job)
+		require.True(t, ok, "the call returns the job at once")
+		assert.NotEmpty(t, job.ID)
+		assert.Equal(t, JobPending, job.Status)
+		assert.Equal(t, "reports.build_report", job.ToolName)
+
+		require.Eventually(t, func() bool {
+			j, err := tm.GetJob(ctx, job.ID)
+			return err == nil && j.Status == JobRunning
+		}, time.Second, 5*time.Millisecond)
+
+		release <- struct{}{}
+		var done *Job
+		select {
+		case done = <-session.done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the session is not notified")
+		}
+		assert.Equal(t, JobSucceeded, done.Status)
+		assert.JSONEq(t, `{"pages":12}`, string(done.Result))
+		assert.NotNil(t, done.CompletedAt)
+
+		stored, err := tm.GetJob(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, done, stored, "the result is kept for later retrieval")
+
+		_, err = tm.GetJob(auth.ContextWithUser(context.Background(), "bob"), job.ID)
+		assert.ErrorIs(t, err, ErrJobNotFound, "the jobs of a user are not visible to the others")
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		result, err := tm.ExecuteTool(context.Background(), &ExecutionRequest{ToolName: "reports.build_report", Arguments: map[string]any{"fail": true}})
+		require.NoError(t, err)
+		job := result.(*Job)
+		release <- struct{}{}
+		require.Eventually(t, func() bool {
+			j, err := tm.GetJob(context.Background(), job.ID)
+			return err == nil && j.Status.Done()
+		}, 5*time.Second, 5*time.Millisecond)
+		failed, err := tm.GetJob(context.Background(), job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, JobFailed, failed.Status)
+		assert.Equal(t, "report failed", failed.Error)
+		assert.Empty(t, failed.Result)
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		_, err := tm.GetJob(context.Background(), "missing")
+		assert.ErrorIs(t, err, ErrJobNotFound)
+	})
+}
+
+func TestManager_ExecuteTool_AsyncWithoutBus(t *testing.T) {
+	tm := NewManager(nil)
+	require.NoError(t, tm.AddTool(&MockTool{
+		ToolFunc: func() *v1.Tool {
+			return v1.Tool_builder{ServiceId: proto.String("reports"), Name: proto.String("build_report")}.Build()
+		},
+	}))
+	tm.AddServiceInfo("reports", &ServiceInfo{
+		Config: configv1.UpstreamServiceConfig_builder{
+			HttpService: configv1.HttpUpstreamService_builder{
+				Tools: []*configv1.ToolDefinition{
+					configv1.ToolDefinition_builder{Name: proto.String("build_report"), Async: proto.Bool(true)}.Build(),
+				},
+			}.Build(),
+		}.Build(),
+	})
+	_, err := tm.ExecuteTool(context.Background(), &ExecutionRequest{ToolName: "reports.build_report"})
+	require.EqualError(t, err, "tool reports.build_report is asynchronous, and no message bus is available to run it")
+}
+
+func TestManager_SetJobStatusTool(t *testing.T) {
+	statusTool := &MockTool{
+		ToolFunc: func() *v1.Tool {
+			return v1.Tool_builder{ServiceId: proto.String("builtin"), Name: proto.String("mcp:get_job_status")}.Build()
+		},
+	}
+	tm := NewManager(nil)
+	tm.SetJobStatusTool(statusTool)
+	_, ok := tm.GetTool("builtin.mcp:get_job_status")
+	assert.False(t, ok, "the tool is not added without asynchronous tools")
+
+	tm.AddServiceInfo("reports", &ServiceInfo{
+		Config: configv1.UpstreamServiceConfig_builder{
+			HttpService: configv1.HttpUpstreamService_builder{
+				Tools: []*configv1.ToolDefinition{
+					configv1.ToolDefinition_builder{Name: proto.String("build_report"), Async: proto.Bool(true)}.Build(),
+				},
+			}.Build(),
+		}.Build(),
+	})
+	_, ok = tm.GetTool("builtin.mcp:get_job_status")
+	assert.True(t, ok)
+}
+
+func TestJobStore_Expiry(t *testing.T) {
+	ctx := context.Background()
+	s := newJobStore(time.Minute)
+	job := configv1.WorkerJob_builder{ToolName: proto.String("svc.tool")}.Build()
+	require.NoError(t, s.create(ctx, job))
+	_, ok := s.complete(ctx, job.GetId(), json.RawMessage(`1`), nil)
+	require.True(t, ok)
+	_, ok = s.complete(ctx, job.GetId(), nil, errors.New("late"))
+	assert.False(t, ok, "a job is done once")
+
+	stored, err := s.store.GetWorkerJob(ctx, job.GetId())
+	require.NoError(t, err)
+	stored.SetCompletedAt(time.Now().UTC().Add(-2 * time.Minute).Format(JobTimeFormat))
+	require.NoError(t, s.store.SaveWorkerJob(ctx, stored))
+	_, ok = s.get(ctx, job.GetId())
+	assert.False(t, ok)
+
+	s.lastPrune = time.Time{}
+	require.NoError(t, s.create(ctx, configv1.WorkerJob_builder{ToolName: proto.String("svc.tool")}.Build()))
+	jobs, err := s.store.ListWorkerJobs(ctx, 0)
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1, "the expired jobs are dropped")
+}
+
+// sharedJobStore is a store of jobs shared with other replicas.
+type sharedJobStore struct {
+	*memoryJobStore
+}
+
+func TestJobStore_Leases(t *testing.T) {
+	ctx := context.Background()
+	s := newJobStore(time.Minute)
+	s.setStore(sharedJobStore{newMemoryJobStore()})
+	job := configv1.WorkerJob_builder{ToolName: proto.String("svc.tool")}.Build()
+	require.NoError(t, s.create(ctx, job))
+
+	now := time.Now().UTC()
+	claimed, err := s.store.ClaimWorkerJob(ctx, job.GetId(), "other", now.Format(JobTimeFormat), now.Add(jobLease).Format(JobTimeFormat))
+	require.NoError(t, err)
+	assert.False(t, claimed, "the job is leased to the replica that created it")
+
+	later := now.Add(2 * jobLease)
+	claimed, err = s.store.ClaimWorkerJob(ctx, job.GetId(), "other", later.Format(JobTimeFormat), later.Add(jobLease).Format(JobTimeFormat))
+	require.NoError(t, err)
+	assert.True(t, claimed, "an expired lease is taken over")
+
+	s.renew(ctx)
+	assert.False(t, s.active[job.GetId()], "a job taken over is no longer waited for")
+}
+
+func TestManager_ResumeJobs(t *testing.T) {
+	ctx := context.Background()
+	// The jobs left by a replica that stopped
+	store := sharedJobStore{newMemoryJobStore()}
+	createdAt := time.Now().UTC().Add(-2 * jobLease).Format(JobTimeFormat)
+	for id, status := range map[string]JobStatus{"pending": JobPending, "running": JobRunning, "done": JobSucceeded, "leased": JobRunning, "new": JobPending} {
+		job := configv1.WorkerJob_builder{
+			Id:         proto.String(id),
+			ServiceId:  proto.String("reports"),
+			ToolName:   proto.String("reports.build_report"),
+			Status:     proto.String(string(status)),
+			Owner:      proto.String("alice"),
+			ToolInputs: proto.String(`{"id":"` + id + `"}`),
+			CreatedAt:  proto.String(createdAt),
+		}.Build()
+		if status.Done() {
+			job.SetCompletedAt(createdAt)
+		}
+		if id == "new" {
+			// Saved a moment ago, and not claimed yet
+			job.SetCreatedAt(time.Now().UTC().Format(JobTimeFormat))
+		}
+		require.NoError(t, store.SaveWorkerJob(ctx, job))
+	}
+	// A replica that is still running waits for this one
+	now := time.Now().UTC()
+	_, err := store.ClaimWorkerJob(ctx, "leased", "other", now.Format(JobTimeFormat), now.Add(jobLease).Format(JobTimeFormat))
+	require.NoError(t, err)
+
+	var calls sync.Map
+	tm := newAsyncTestManager(t, func(ctx context.Context, req *ExecutionRequest) (any, error) {
+		user, _ := auth.UserFromContext(ctx)
+		calls.Store(string(req.ToolInputs), user)
+		return "ok", nil
+	})
+	tm.SetJobStore(store)
+
+	n, err := tm.ResumeJobs(ctx, "reports")
+	require.NoError(t, err)
+	assert.Zero(t, n, "only the leader resumes jobs")
+
+	cluster.SetLeader(cluster.Standalone)
+	t.Cleanup(func() { cluster.SetLeader(nil) })
+	n, err = tm.ResumeJobs(ctx, "other")
+	require.NoError(t, err)
+	assert.Zero(t, n, "only the jobs of the service are resumed")
+	n, err = tm.ResumeJobs(ctx, "reports")
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	alice := auth.ContextWithUser(ctx, "alice")
+	for _, id := range []string{"pending", "running"} {
+		require.Eventually(t, func() bool {
+			job, err := tm.GetJob(alice, id)
+			return err == nil && job.Status == JobSucceeded
+		}, 5*time.Second, 5*time.Millisecond, id)
+		user, ok := calls.Load(`{"id":"` + id + `"}`)
+		require.True(t, ok, id)
+		assert.Equal(t, "alice", user, "the job runs for its owner")
+	}
+	for _, id := range []string{"done", "leased", "new"} {
+		_, ran := calls.Load(`{"id":"` + id + `"}`)
+		assert.False(t, ran, "job %s is not run again", id)
+	}
+	job, err := store.GetWorkerJob(ctx, "running")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), job.GetAttempts())
+
+	n, err = tm.ResumeJobs(ctx, "reports")
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestManager_ExecuteTool_JobDeliveredAgain(t *testing.T) {
+	var calls atomic.Int32
+	tm := newAsyncTestManager(t, func(_ context.Context, req *ExecutionRequest) (any, error) {
+		calls.Add(1)
+		var args map[string]any
+		_ = json.Unmarshal(req.ToolInputs, &args)
+		if args["fail"] == true {
+			return nil, errors.New("report failed")
+		}
+		return map[string]any{"pages": 12}, nil
+	})
+	for _, tc := range []struct {
+		inputs string
+		check  func(t *testing.T, result any, err error)
+	}{
+		{`{}`, func(t *testing.T, result any, err error) {
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"pages":12}`, string(result.(json.RawMessage)))
+		}},
+		{`{"fail":true}`, func(t *testing.T, _ any, err error) {
+			assert.EqualError(t, err, "report failed")
+		}},
+	} {
+		result, err := tm.ExecuteTool(context.Background(), &ExecutionRequest{ToolName: "reports.build_report", ToolInputs: []byte(tc.inputs)})
+		require.NoError(t, err)
+		job := result.(*Job)
+		require.Eventually(t, func() bool {
+			j, err := tm.GetJob(context.Background(), job.ID)
+			return err == nil && j.Status.Done()
+		}, 5*time.Second, 5*time.Millisecond)
+
+		before := calls.Load()
+		result, err = tm.ExecuteTool(NewContextWithJob(context.Background(), job.ID), &ExecutionRequest{ToolName: "reports.build_report", ToolInputs: []byte(tc.inputs)})
+		tc.check(t, result, err)
+		assert.Equal(t, before, calls.Load(), "the tool does not run again")
+	}
+}
+
+func TestManager_ExecuteTool_AsyncPriorityClass(t *testing.T) {
+	b, err := bus.NewProvider(nil)
+	require.NoError(t, err)
+	tm := NewManager(b)
+	for _, name := range []string{"build_report", "export"} {
+		require.NoError(t, tm.AddTool(&MockTool{
+			ToolFunc: func() *v1.Tool {
+				return v1.Tool_builder{ServiceId: proto.String("reports"), Name: proto.String(name)}.Build()
+			},
+		}))
+	}
+	tm.AddServiceInfo("reports", &ServiceInfo{
+		Config: configv1.UpstreamServiceConfig_builder{
+			HttpService: configv1.HttpUpstreamService_builder{
+				Tools: []*configv1.ToolDefinition{
+					configv1.ToolDefinition_builder{Name: proto.String("build_report"), Async: proto.Bool(true)}.Build(),
+					configv1.ToolDefinition_builder{Name: proto.String("export"), Async: proto.Bool(true), PriorityClass: proto.String("exports")}.Build(),
+				},
+			}.Build(),
+		}.Build(),
+	})
+	requests, err := bus.GetBus[*bus.ToolExecutionRequest](b, bus.ToolExecutionRequestTopic)
+	require.NoError(t, err)
+	received := make(chan *bus.ToolExecutionRequest, 1)
+	defer requests.Subscribe(context.Background(), "request", func(req *bus.ToolExecutionRequest) { received <- req })()
+
+	ctx := auth.ContextWithUser(context.Background(), "alice")
+	for tool, class := range map[string]string{"reports.build_report": bus.PriorityBatch, "reports.export": "exports"} {
+		_, err := tm.ExecuteTool(ctx, &ExecutionRequest{ToolName: tool, ToolInputs: []byte(`{}`)})
+		require.NoError(t, err)
+		req := <-received
+		assert.Equal(t, class, req.PriorityClass, tool)
+		assert.Equal(t, "alice", req.Tenant)
+	}
+}
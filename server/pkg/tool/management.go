@@ -8,16 +8,19 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	// Use json-iterator for faster JSON operations.
 	json "github.com/json-iterator/go"
 
-	"github.com/google/uuid"
 	configv1 "github.com/mcpany/core/proto/config/v1"
 	v1 "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/mcpany/core/server/pkg/auth"
 	"github.com/mcpany/core/server/pkg/bus"
+	"github.com/mcpany/core/server/pkg/idgen"
 	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/secretusage"
 	"github.com/mcpany/core/server/pkg/util"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	xsync "github.com/puzpuzpuz/xsync/v4"
@@ -266,6 +269,21 @@ type Manager struct {
 	enabledProfiles      []string
 	profileDefs          map[string]*configv1.ProfileDefinition
 	allowedServicesCache map[string]map[string]bool
+
+	// calls counts the calls in flight on each service, for DrainService.
+	calls inFlightCalls
+
+	// canaries routes a share of the calls of the stable services to their
+	// canaries.
+	canaries canaryRoutes
+
+	// jobs holds the calls of the asynchronous tools and their results.
+	jobs *jobStore
+	// jobStatusTool polls the jobs. It is added with the first asynchronous
+	// tool.
+	jobStatusTool      Tool
+	hasAsyncTools      atomic.Bool
+	jobStatusToolAdded atomic.Bool
 }
 
 // NewManager creates and initializes a new Tool Manager.
@@ -290,6 +308,7 @@ func NewManager(bus *bus.Provider) *Manager {
 		serviceToolNames:     make(map[string]map[string]struct{}),
 		profileDefs:          make(map[string]*configv1.ProfileDefinition),
 		allowedServicesCache: make(map[string]map[string]bool),
+		jobs:                 newJobStore(DefaultJobTTL),
 	}
 }
 
@@ -646,6 +665,20 @@ func (tm *Manager) ExecuteTool(ctx context.Context, req *ExecutionRequest) (any,
 		t, ok = tm.GetTool(req.ToolName)
 	}
 
+	// Accept the old name of a renamed tool during its deprecation window
+	var deprecated *DeprecatedToolName
+	if !ok {
+		resolved, d, err := tm.resolveDeprecatedName(ctx, req.ToolName)
+		if err != nil {
+			log.Warn("Tool call by deprecated name rejected", "error", err)
+			return nil, err
+		}
+		if resolved != nil {
+			t, ok, deprecated = resolved, true, d
+			req.ToolName = d.Tool
+		}
+	}
+
 	if !ok {
 		log.Error("Tool not found")
 
@@ -693,12 +726,30 @@ func (tm *Manager) ExecuteTool(ctx context.Context, req *ExecutionRequest) (any,
 
 		return nil, ErrToolNotFound
 	}
+	// Asynchronous tools run as jobs, which call them again from the worker
+	if tm.isAsync(t) {
+		jobID, ok := jobIDFromContext(ctx)
+		if !ok {
+			job, err := tm.submitJob(ctx, t, req)
+			if err != nil {
+				return nil, err
+			}
+			return job, nil
+		}
+		if job := tm.jobs.start(ctx, jobID); job != nil && job.Status.Done() {
+			// The request of a job that is done was delivered again
+			return job.outcome()
+		}
+	}
+	t, canary := tm.routeToCanary(t)
 	serviceID := t.Tool().GetServiceId()
+	tm.calls.begin(serviceID)
+	defer tm.calls.end(serviceID)
 	// ⚡ Bolt Optimization: Use direct load to avoid expensive config cloning/stripping in GetServiceInfo
 	serviceInfo, ok := tm.serviceInfo.Load(serviceID)
 
 	var preHooks []PreCallHook
-	var postHooks []PostCallHook
+	var postHooks, transforms []PostCallHook
 	if ok {
 		if serviceInfo.HealthStatus == HealthStatusUnhealthy {
 			log.Warn("Service is unhealthy, denying execution", "serviceID", serviceID)
@@ -706,15 +757,26 @@ func (tm *Manager) ExecuteTool(ctx context.Context, req *ExecutionRequest) (any,
 		}
 		preHooks = serviceInfo.PreHooks
 		postHooks = serviceInfo.PostHooks
+		transforms = serviceInfo.ToolTransforms[t.Tool().GetName()]
 	}
 
 	// 2. Initialize Context with Tool and CacheControl
 	ctx = NewContextWithTool(ctx, t)
+	// Attribute the secrets read during the call to the service
+	serviceName := serviceID
+	if ok && serviceInfo.Name != "" {
+		serviceName = serviceInfo.Name
+	}
+	ctx = util.ContextWithSecretConsumer(ctx, secretusage.ServiceConsumer(serviceName))
 	ctx = NewContextWithCacheControl(ctx, &CacheControl{Action: ActionAllow})
+	timings := NewCallTimings()
+	ctx = NewContextWithCallTimings(ctx, timings)
 
 	// 3. Run Pre-execution Hooks (modifies ctx/req)
 	for _, h := range preHooks {
+		hookStart := time.Now()
 		action, modifiedReq, err := h.ExecutePre(ctx, req)
+		RecordCallPhase(ctx, PhaseWebhook, hookStart)
 		if err != nil {
 			log.Warn("Tool execution denied by pre-hook error", "error", err)
 			return nil, err
@@ -735,11 +797,29 @@ func (tm *Manager) ExecuteTool(ctx context.Context, req *ExecutionRequest) (any,
 
 	// 4. Define Core Execution (Execute + PostHooks)
 	executeCore := func(ctx context.Context, req *ExecutionRequest) (any, error) {
-		result, err := t.Execute(ctx, req)
+		execStart := time.Now()
+		queued, upstream, transformed := timings.Get(PhaseQueue), timings.Get(PhaseUpstream), timings.Get(PhaseTransform)
+		result, err := t.Execute(ctx, stripTransformInputs(req, transforms))
+		// Tools that do not break their execution down spend it all upstream.
+		if timings.Get(PhaseUpstream) == upstream {
+			accounted := timings.Get(PhaseQueue) - queued + timings.Get(PhaseTransform) - transformed
+			timings.Add(PhaseUpstream, time.Since(execStart)-accounted)
+		}
 
-		// Execute Post Hooks
+		// Apply the built-in transforms of the tool, then the Post Hooks
+		if err == nil {
+			for _, h := range transforms {
+				transformed, tfErr := h.ExecutePost(ctx, req, result)
+				if tfErr != nil {
+					return nil, tfErr
+				}
+				result = transformed
+			}
+		}
 		for _, h := range postHooks {
+			hookStart := time.Now()
 			newResult, hkErr := h.ExecutePost(ctx, req, result)
+			RecordCallPhase(ctx, PhaseWebhook, hookStart)
 			if hkErr != nil {
 				log.Warn("Post-hook execution failed", "error", hkErr)
 				return nil, hkErr
@@ -770,11 +850,17 @@ func (tm *Manager) ExecuteTool(ctx context.Context, req *ExecutionRequest) (any,
 	start := time.Now()
 	result, err := chain(ctx, req)
 	duration := time.Since(start)
+	if canary != nil {
+		canary.record(err != nil)
+	}
 
 	if err != nil {
 		log.Error("Tool execution failed", "error", err, "duration", duration.String())
 	} else {
 		log.Info("Tool execution successful", "duration", duration.String())
+		if deprecated != nil {
+			result = withDeprecationNotice(result, deprecated)
+		}
 	}
 	return result, err
 }
@@ -796,6 +882,11 @@ func (tm *Manager) AddServiceInfo(serviceID string, info *ServiceInfo) {
 		var preHooks []PreCallHook
 		var postHooks []PostCallHook
 
+		// 0. Argument validation runs first, so policies see the normalized arguments
+		if h := NewArgumentValidationHook(info.Config.GetArgumentValidation()); h != nil {
+			preHooks = append(preHooks, h)
+		}
+
 		// 1. New Call Policies -> converted to PreHook
 		for _, policy := range info.Config.GetCallPolicies() {
 			preHooks = append(preHooks, NewPolicyHook(policy))
@@ -813,19 +904,63 @@ func (tm *Manager) AddServiceInfo(serviceID string, info *ServiceInfo) {
 				preHooks = append(preHooks, NewPolicyHook(p))
 			}
 			if w := hCfg.GetWebhook(); w != nil {
-				preHooks = append(preHooks, NewWebhookHook(w))
+				h := NewWebhookHook(w)
+				h.serviceID, h.name = serviceID, hCfg.GetName()
+				preHooks = append(preHooks, h)
 			}
 		}
 		// 3. PostCallHooks
 		for _, hCfg := range info.Config.GetPostCallHooks() {
 			if w := hCfg.GetWebhook(); w != nil {
-				postHooks = append(postHooks, NewWebhookHook(w))
+				h := NewWebhookHook(w)
+				h.serviceID, h.name = serviceID, hCfg.GetName()
+				postHooks = append(postHooks, h)
 			}
 		}
 		info.PreHooks = preHooks
 		info.PostHooks = postHooks
+		info.ToolTransforms = compileToolTransforms(info.Config)
+		info.DeprecatedNames = compileDeprecatedNames(serviceID, info.Config.GetToolAliases())
+		info.AsyncTools, info.PriorityClasses = compileToolScheduling(info.Config)
 	}
 	tm.serviceInfo.Store(serviceID, info)
+	if tm.canaries.configure(serviceID, info.Config.GetCanary()) {
+		// Canary tools are hidden from the listings
+		tm.toolsMutex.Lock()
+		tm.cachedTools = nil
+		tm.cachedMCPTools = nil
+		tm.toolsMutex.Unlock()
+	}
+	if len(info.AsyncTools) > 0 {
+		tm.addJobStatusTool()
+	}
+}
+
+// SetServiceHealthStatus records the health status of a service, as found by
+// the active probes of the service registry. The tools of an unhealthy service
+// are hidden from the listings and their calls are refused until it recovers.
+//
+// Summary: Sets the health status of a service.
+//
+// Parameters:
+//   - serviceID (string): The unique identifier of the service.
+//   - status (string): The status, HealthStatusHealthy or HealthStatusUnhealthy.
+//
+// Side Effects:
+//   - Invalidates the tool listings when the status changes.
+func (tm *Manager) SetServiceHealthStatus(serviceID, status string) {
+	info, ok := tm.serviceInfo.Load(serviceID)
+	if !ok || info.HealthStatus == status {
+		return
+	}
+	// The info is shared with in-flight calls, so it is replaced, not changed.
+	updated := *info
+	updated.HealthStatus = status
+	tm.serviceInfo.Store(serviceID, &updated)
+	tm.toolsMutex.Lock()
+	tm.cachedTools = nil
+	tm.cachedMCPTools = nil
+	tm.toolsMutex.Unlock()
 }
 
 // GetServiceInfo retrieves the metadata for a registered service.
@@ -928,6 +1063,9 @@ func (tm *Manager) AddTool(tool Tool) error {
 	toolID := tool.Tool().GetServiceId() + "." + sanitizedToolName
 	log := logging.GetLogger().With("toolID", toolID)
 	log.Debug("Adding tool to Manager")
+	if info, ok := tm.serviceInfo.Load(tool.Tool().GetServiceId()); ok {
+		addTransformInputs(tool.Tool(), info.ToolTransforms[tool.Tool().GetName()])
+	}
 	tm.tools.Store(toolID, tool)
 
 	// Update indices
@@ -992,7 +1130,7 @@ func (tm *Manager) AddTool(tool Tool) error {
 		handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			logging.GetLogger().Info("Queueing tool execution", "toolName", req.Params.Name)
 
-			correlationID := uuid.New().String()
+			correlationID := idgen.New()
 			resultChan := make(chan *bus.ToolExecutionResult, 1)
 
 			resultBus, err := bus.GetBus[*bus.ToolExecutionResult](tm.bus, "tool_execution_results")
@@ -1012,10 +1150,14 @@ func (tm *Manager) AddTool(tool Tool) error {
 			if err != nil {
 				return nil, fmt.Errorf("failed to get request bus: %w", err)
 			}
+			tenant, _ := auth.UserFromContext(ctx)
 			execReq := &bus.ToolExecutionRequest{
-				Context:    ctx,
-				ToolName:   req.Params.Name,
-				ToolInputs: req.Params.Arguments,
+				Context:        ctx,
+				ToolName:       req.Params.Name,
+				ToolInputs:     req.Params.Arguments,
+				PriorityClass:  tm.priorityClass(tool, bus.PriorityInteractive),
+				Tenant:         tenant,
+				IdempotencyKey: correlationID,
 			}
 			execReq.SetCorrelationID(correlationID)
 			if err := requestBus.Publish(ctx, "request", execReq); err != nil {
@@ -1129,6 +1271,9 @@ func (tm *Manager) rebuildCachedTools() []Tool {
 				return true // Skip unhealthy tools
 			}
 		}
+		if tm.canaries.isCanary(serviceID) {
+			return true // Canary tools are reached through their stable service
+		}
 		tools = append(tools, value)
 		return true
 	})
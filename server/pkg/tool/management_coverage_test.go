@@ -86,6 +86,26 @@ func TestManager_ExecuteTool_Coverage(t *testing.T) {
 		assert.Contains(t, err.Error(), "service unhealthy-svc is currently unhealthy")
 	})
 
+	t.Run("Service Health Status Set By Probes", func(t *testing.T) {
+		manager := tool.NewManager(nil)
+
+		toolDef := v1.Tool_builder{Name: proto.String("probed-tool"), ServiceId: proto.String("probed-svc")}.Build()
+		require.NoError(t, manager.AddTool(&mockTool{toolDef: toolDef}))
+		manager.AddServiceInfo("probed-svc", &tool.ServiceInfo{Name: "probed-svc"})
+		require.Len(t, manager.ListTools(), 1)
+
+		manager.SetServiceHealthStatus("probed-svc", tool.HealthStatusUnhealthy)
+		assert.Empty(t, manager.ListTools())
+		_, err := manager.ExecuteTool(context.Background(), &tool.ExecutionRequest{ToolName: "probed-svc.probed-tool"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "service probed-svc is currently unhealthy")
+
+		manager.SetServiceHealthStatus("probed-svc", tool.HealthStatusHealthy)
+		assert.Len(t, manager.ListTools(), 1)
+		_, err = manager.ExecuteTool(context.Background(), &tool.ExecutionRequest{ToolName: "probed-svc.probed-tool"})
+		require.NoError(t, err)
+	})
+
 	t.Run("PreHook Deny", func(t *testing.T) {
 		manager := tool.NewManager(nil)
 
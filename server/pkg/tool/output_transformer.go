@@ -0,0 +1,76 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/transformer"
+)
+
+// parseOutput parses an upstream response as configured by an
+// OutputTransformer. XML_TO_JSON and CSV outputs are converted to JSON values
+// first, then the JQ query or extraction rules, if any, apply to the result.
+func parseOutput(ot *configv1.OutputTransformer, body []byte) (any, error) {
+	parser := transformer.NewTextParser()
+	var converted any
+	switch ot.GetFormat() {
+	case configv1.OutputTransformer_XML_TO_JSON:
+		doc, err := transformer.XMLToJSON(body, xmlOptions(ot.GetXmlOptions()))
+		if err != nil {
+			return nil, err
+		}
+		converted = doc
+	case configv1.OutputTransformer_CSV:
+		opts, err := csvOptions(ot.GetCsvOptions())
+		if err != nil {
+			return nil, err
+		}
+		rows, err := transformer.CSVToRows(body, opts)
+		if err != nil {
+			return nil, err
+		}
+		converted = rows
+	default:
+		outputFormat := configv1.OutputTransformer_OutputFormat_name[int32(ot.GetFormat())]
+		return parser.Parse(outputFormat, body, ot.GetExtractionRules(), ot.GetJqQuery())
+	}
+	return parser.Extract(converted, ot.GetExtractionRules(), ot.GetJqQuery())
+}
+
+func xmlOptions(cfg *configv1.XmlConversionOptions) transformer.XMLOptions {
+	return transformer.XMLOptions{
+		AttributePrefix:   cfg.GetAttributePrefix(),
+		NoAttributePrefix: cfg.HasAttributePrefix() && cfg.GetAttributePrefix() == "",
+		IgnoreAttributes:  cfg.GetIgnoreAttributes(),
+		TextKey:           cfg.GetTextKey(),
+		ForceList:         cfg.GetForceList(),
+		CoerceTypes:       cfg.GetCoerceTypes(),
+	}
+}
+
+func csvOptions(cfg *configv1.CsvConversionOptions) (transformer.CSVOptions, error) {
+	opts := transformer.CSVOptions{
+		Columns:     cfg.GetColumns(),
+		CoerceTypes: cfg.GetCoerceTypes(),
+	}
+	switch cfg.GetHeader() {
+	case configv1.CsvConversionOptions_HEADER_PRESENT:
+		opts.Header = transformer.CSVHeaderPresent
+	case configv1.CsvConversionOptions_HEADER_ABSENT:
+		opts.Header = transformer.CSVHeaderAbsent
+	default:
+		opts.Header = transformer.CSVHeaderAuto
+	}
+	if d := cfg.GetDelimiter(); d != "" {
+		r, size := utf8.DecodeRuneInString(d)
+		if r == utf8.RuneError || size != len(d) {
+			return opts, fmt.Errorf("csv delimiter must be a single character, got %q", d)
+		}
+		opts.Delimiter = r
+	}
+	return opts, nil
+}
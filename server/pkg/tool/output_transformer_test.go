@@ -0,0 +1,83 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func executeTransformed(t *testing.T, ot *configv1.OutputTransformer, kind string) (any, error) {
+	t.Helper()
+	httpTool, server := setupHTTPToolTest(t, contentServer(t, kind, nil), configv1.HttpCallDefinition_builder{
+		Method:            configv1.HttpCallDefinition_HTTP_METHOD_GET.Enum(),
+		OutputTransformer: ot,
+	}.Build())
+	defer server.Close()
+	return httpTool.Execute(context.Background(), &tool.ExecutionRequest{ToolInputs: json.RawMessage(`{}`)})
+}
+
+func TestHTTPTool_OutputTransformer_XMLToJSON(t *testing.T) {
+	result, err := executeTransformed(t, configv1.OutputTransformer_builder{
+		Format: configv1.OutputTransformer_XML_TO_JSON.Enum(),
+		XmlOptions: configv1.XmlConversionOptions_builder{
+			AttributePrefix: proto.String(""),
+			CoerceTypes:     proto.Bool(true),
+		}.Build(),
+	}.Build(), "xml")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"users": map[string]any{"user": []any{
+		map[string]any{"id": 1, "name": "ada"},
+		map[string]any{"id": 2, "name": "bob"},
+	}}}, result)
+
+	result, err = executeTransformed(t, configv1.OutputTransformer_builder{
+		Format:     configv1.OutputTransformer_XML_TO_JSON.Enum(),
+		XmlOptions: configv1.XmlConversionOptions_builder{IgnoreAttributes: proto.Bool(true)}.Build(),
+		JqQuery:    proto.String("[.users.user[].name]"),
+	}.Build(), "xml")
+	require.NoError(t, err)
+	assert.Equal(t, []any{"ada", "bob"}, result)
+
+	_, err = executeTransformed(t, configv1.OutputTransformer_builder{
+		Format: configv1.OutputTransformer_XML_TO_JSON.Enum(),
+	}.Build(), "text")
+	assert.ErrorContains(t, err, "failed to parse output")
+}
+
+func TestHTTPTool_OutputTransformer_CSV(t *testing.T) {
+	result, err := executeTransformed(t, configv1.OutputTransformer_builder{
+		Format: configv1.OutputTransformer_CSV.Enum(),
+		CsvOptions: configv1.CsvConversionOptions_builder{
+			Delimiter:   proto.String(";"),
+			CoerceTypes: proto.Bool(true),
+		}.Build(),
+	}.Build(), "csv")
+	require.NoError(t, err)
+	assert.Equal(t, []any{map[string]any{"name": "ada", "age": 36}}, result)
+
+	result, err = executeTransformed(t, configv1.OutputTransformer_builder{
+		Format: configv1.OutputTransformer_CSV.Enum(),
+		CsvOptions: configv1.CsvConversionOptions_builder{
+			Delimiter: proto.String(";"),
+			Header:    configv1.CsvConversionOptions_HEADER_ABSENT.Enum(),
+		}.Build(),
+		ExtractionRules: map[string]string{"first": "{[1][0]}"},
+	}.Build(), "csv")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"first": "ada"}, result)
+
+	_, err = executeTransformed(t, configv1.OutputTransformer_builder{
+		Format:     configv1.OutputTransformer_CSV.Enum(),
+		CsvOptions: configv1.CsvConversionOptions_builder{Delimiter: proto.String(";;")}.Build(),
+	}.Build(), "csv")
+	assert.ErrorContains(t, err, "single character")
+}
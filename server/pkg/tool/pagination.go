@@ -0,0 +1,235 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/transformer"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// CursorInputName is the input that selects the page of a paginated tool.
+	CursorInputName = "cursor"
+	// NextCursorMetaKey is the result metadata key that carries the cursor of
+	// the next page.
+	NextCursorMetaKey = "nextCursor"
+)
+
+// paginator maps the pagination of an upstream to opaque MCP cursors.
+//
+// A cursor is the base64url encoding of the upstream's own position: its
+// cursor, page number, or offset.
+type paginator struct {
+	cfg    *configv1.PaginationConfig
+	parser *transformer.TextParser
+}
+
+// pagePosition is the upstream position of the requested page.
+type pagePosition struct {
+	// value is the upstream cursor, page number or offset; empty for the
+	// first page of a cursor or link based listing.
+	value string
+	// number is the page number or offset for STYLE_PAGE and STYLE_OFFSET.
+	number int
+}
+
+func newPaginator(cfg *configv1.PaginationConfig) *paginator {
+	if cfg == nil || cfg.GetStyle() == configv1.PaginationConfig_STYLE_UNSPECIFIED {
+		return nil
+	}
+	return &paginator{cfg: cfg, parser: transformer.NewTextParser()}
+}
+
+// position decodes the cursor of a request.
+func (p *paginator) position(cursor string) (pagePosition, error) {
+	var pos pagePosition
+	if p.cfg.GetStyle() == configv1.PaginationConfig_STYLE_PAGE {
+		pos.number = p.firstPage()
+	}
+	if cursor == "" {
+		return pos, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(decoded) == 0 {
+		return pos, fmt.Errorf("invalid cursor")
+	}
+	pos.value = string(decoded)
+
+	switch p.cfg.GetStyle() {
+	case configv1.PaginationConfig_STYLE_PAGE, configv1.PaginationConfig_STYLE_OFFSET:
+		n, err := strconv.Atoi(pos.value)
+		if err != nil || n < 0 {
+			return pos, fmt.Errorf("invalid cursor")
+		}
+		pos.number = n
+	}
+	return pos, nil
+}
+
+// applyToURL sets the pagination query parameters of the upstream request.
+func (p *paginator) applyToURL(rawURL string, pos pagePosition) string {
+	if p.cfg.GetPageSizeParam() != "" && p.cfg.GetPageSize() > 0 {
+		rawURL = setRawQueryParam(rawURL, p.cfg.GetPageSizeParam(), strconv.Itoa(int(p.cfg.GetPageSize())))
+	}
+	if pos.value != "" {
+		rawURL = setRawQueryParam(rawURL, p.cfg.GetParam(), pos.value)
+	}
+	return rawURL
+}
+
+// nextCursor returns the cursor of the page after pos, or "" if pos is the
+// last page.
+func (p *paginator) nextCursor(pos pagePosition, header http.Header, body []byte) string {
+	var next string
+	switch p.cfg.GetStyle() {
+	case configv1.PaginationConfig_STYLE_CURSOR:
+		v, ok := p.lookup(body, p.cfg.GetNextCursorPath())
+		if !ok {
+			return ""
+		}
+		switch v := v.(type) {
+		case string:
+			next = v
+		case float64:
+			next = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	case configv1.PaginationConfig_STYLE_PAGE, configv1.PaginationConfig_STYLE_OFFSET:
+		items, _ := p.lookup(body, p.cfg.GetItemsPath())
+		list, ok := items.([]any)
+		if !ok || len(list) == 0 || (p.cfg.GetPageSize() > 0 && len(list) < int(p.cfg.GetPageSize())) {
+			return ""
+		}
+		if p.cfg.GetStyle() == configv1.PaginationConfig_STYLE_PAGE {
+			next = strconv.Itoa(pos.number + 1)
+		} else {
+			next = strconv.Itoa(pos.number + len(list))
+		}
+	case configv1.PaginationConfig_STYLE_LINK_HEADER:
+		next = nextLinkParam(header.Values("Link"), p.cfg.GetParam())
+	}
+	if next == "" {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(next))
+}
+
+// wrap attaches the next cursor to the result metadata.
+func (p *paginator) wrap(result any, next string) any {
+	if next == "" {
+		return result
+	}
+	return withResultMeta(result, NextCursorMetaKey, next)
+}
+
+// withResultMeta sets a key of the result metadata, wrapping a plain result
+// in a CallToolResult.
+func withResultMeta(result any, key string, value any) any {
+	if ctr, ok := result.(*mcp.CallToolResult); ok {
+		if ctr.Meta == nil {
+			ctr.Meta = mcp.Meta{}
+		}
+		ctr.Meta[key] = value
+		return ctr
+	}
+	var text string
+	if s, ok := result.(string); ok {
+		text = s
+	} else {
+		b, err := fastJSON.Marshal(result)
+		if err != nil {
+			return result
+		}
+		text = string(b)
+	}
+	return &mcp.CallToolResult{
+		Meta:    mcp.Meta{key: value},
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+	}
+}
+
+func (p *paginator) firstPage() int {
+	if p.cfg.HasFirstPage() {
+		return int(p.cfg.GetFirstPage())
+	}
+	return 1
+}
+
+func (p *paginator) lookup(body []byte, path string) (any, bool) {
+	if path == "" {
+		return nil, false
+	}
+	parsed, err := p.parser.Parse("json", body, map[string]string{"value": path}, "")
+	if err != nil {
+		return nil, false
+	}
+	v, ok := parsed.(map[string]any)["value"]
+	return v, ok
+}
+
+// nextLinkParam returns the value of param in the rel="next" target of RFC
+// 8288 Link headers. Only the parameter is used, never the link itself, so a
+// cursor cannot redirect the tool to another URL.
+func nextLinkParam(links []string, param string) string {
+	for _, header := range links {
+		for _, link := range strings.Split(header, ",") {
+			target, params, ok := strings.Cut(strings.TrimSpace(link), ";")
+			if !ok || !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			isNext := false
+			for _, attr := range strings.Split(params, ";") {
+				name, value, _ := strings.Cut(strings.TrimSpace(attr), "=")
+				if strings.EqualFold(name, "rel") && slicesContainsFold(strings.Fields(strings.Trim(value, `"`)), "next") {
+					isNext = true
+				}
+			}
+			if !isNext {
+				continue
+			}
+			u, err := url.Parse(strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">"))
+			if err != nil {
+				return ""
+			}
+			return u.Query().Get(param)
+		}
+	}
+	return ""
+}
+
+func slicesContainsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// setRawQueryParam sets a query parameter without re-encoding the rest of
+// the URL.
+func setRawQueryParam(rawURL, key, value string) string {
+	base, query, _ := strings.Cut(rawURL, "?")
+	parts := make([]string, 0, strings.Count(query, "&")+2)
+	for _, part := range strings.Split(query, "&") {
+		if part == "" {
+			continue
+		}
+		name, _, _ := strings.Cut(part, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil && unescaped == key {
+			continue
+		}
+		parts = append(parts, part)
+	}
+	parts = append(parts, url.QueryEscape(key)+"="+url.QueryEscape(value))
+	return base + "?" + strings.Join(parts, "&")
+}
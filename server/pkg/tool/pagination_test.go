@@ -0,0 +1,184 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// executePage runs a paginated tool and returns the result text and the next
+// cursor.
+func executePage(t *testing.T, httpTool *tool.HTTPTool, cursor string) (string, string) {
+	t.Helper()
+	inputs := json.RawMessage(`{}`)
+	if cursor != "" {
+		inputs = json.RawMessage(fmt.Sprintf(`{"cursor": %q}`, cursor))
+	}
+	result, err := httpTool.Execute(context.Background(), &tool.ExecutionRequest{ToolInputs: inputs})
+	require.NoError(t, err)
+
+	ctr, ok := result.(*mcp.CallToolResult)
+	if !ok {
+		// The last page is returned as is.
+		b, err := json.Marshal(result)
+		require.NoError(t, err)
+		return string(b), ""
+	}
+	require.Len(t, ctr.Content, 1)
+	next, _ := ctr.Meta[tool.NextCursorMetaKey].(string)
+	return ctr.Content[0].(*mcp.TextContent).Text, next
+}
+
+func paginatedCall(p *configv1.PaginationConfig) *configv1.HttpCallDefinition {
+	return configv1.HttpCallDefinition_builder{
+		Method:     configv1.HttpCallDefinition_HTTP_METHOD_GET.Enum(),
+		Pagination: p,
+	}.Build()
+}
+
+func TestHTTPTool_Pagination_Cursor(t *testing.T) {
+	var queries []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("after") == "" {
+			_, _ = w.Write([]byte(`{"items":[1,2],"meta":{"next":"abc=="}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"items":[3],"meta":{"next":""}}`))
+	})
+	httpTool, server := setupHTTPToolTest(t, handler, paginatedCall(configv1.PaginationConfig_builder{
+		Style:          configv1.PaginationConfig_STYLE_CURSOR.Enum(),
+		Param:          proto.String("after"),
+		NextCursorPath: proto.String("{.meta.next}"),
+		PageSizeParam:  proto.String("limit"),
+		PageSize:       proto.Int32(2),
+	}.Build()))
+	defer server.Close()
+
+	text, next := executePage(t, httpTool, "")
+	assert.JSONEq(t, `{"items":[1,2],"meta":{"next":"abc=="}}`, text)
+	require.NotEmpty(t, next)
+
+	text, next = executePage(t, httpTool, next)
+	assert.Contains(t, text, `"items":[3]`)
+	assert.Empty(t, next, "an empty upstream cursor ends the listing")
+
+	require.Len(t, queries, 2)
+	first, _ := url.ParseQuery(queries[0])
+	assert.Equal(t, url.Values{"limit": {"2"}}, first)
+	second, _ := url.ParseQuery(queries[1])
+	assert.Equal(t, url.Values{"limit": {"2"}, "after": {"abc=="}}, second)
+}
+
+func TestHTTPTool_Pagination_PageAndOffset(t *testing.T) {
+	const total = 5
+	handler := func(param string, first int) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := 0
+			if v := r.URL.Query().Get(param); v != "" {
+				n, _ := strconv.Atoi(v)
+				start = n
+				if param == "page" {
+					start = (n - first) * 2
+				}
+			}
+			items := []int{}
+			for i := start; i < total && i < start+2; i++ {
+				items = append(items, i)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"items": items}})
+		})
+	}
+
+	for _, tc := range []struct {
+		name  string
+		style configv1.PaginationConfig_Style
+		param string
+		first int32
+	}{
+		{name: "page", style: configv1.PaginationConfig_STYLE_PAGE, param: "page", first: 0},
+		{name: "offset", style: configv1.PaginationConfig_STYLE_OFFSET, param: "offset"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			httpTool, server := setupHTTPToolTest(t, handler(tc.param, int(tc.first)), paginatedCall(configv1.PaginationConfig_builder{
+				Style:     tc.style.Enum(),
+				Param:     proto.String(tc.param),
+				ItemsPath: proto.String("{.data.items}"),
+				PageSize:  proto.Int32(2),
+				FirstPage: proto.Int32(tc.first),
+			}.Build()))
+			defer server.Close()
+
+			var pages []string
+			cursor := ""
+			for i := 0; i < total; i++ {
+				text, next := executePage(t, httpTool, cursor)
+				pages = append(pages, text)
+				if next == "" {
+					break
+				}
+				cursor = next
+			}
+			require.Len(t, pages, 3)
+			assert.Contains(t, pages[0], `[0,1]`)
+			assert.Contains(t, pages[1], `[2,3]`)
+			assert.Contains(t, pages[2], `[4]`)
+		})
+	}
+}
+
+func TestHTTPTool_Pagination_LinkHeader(t *testing.T) {
+	var pages []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		if page == "" {
+			w.Header().Add("Link", `<https://evil.example.com/items?page=1>; rel="prev"`)
+			w.Header().Add("Link", `<https://api.example.com/items?page=2&per_page=50>; rel="next", <https://api.example.com/items?page=9>; rel="last"`)
+		}
+		_, _ = w.Write([]byte(`[]`))
+	})
+	httpTool, server := setupHTTPToolTest(t, handler, paginatedCall(configv1.PaginationConfig_builder{
+		Style: configv1.PaginationConfig_STYLE_LINK_HEADER.Enum(),
+		Param: proto.String("page"),
+	}.Build()))
+	defer server.Close()
+
+	_, next := executePage(t, httpTool, "")
+	require.NotEmpty(t, next)
+	_, next = executePage(t, httpTool, next)
+	assert.Empty(t, next)
+	assert.Equal(t, []string{"", "2"}, pages)
+}
+
+func TestHTTPTool_Pagination_InvalidCursor(t *testing.T) {
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		t.Error("upstream must not be called")
+	})
+	httpTool, server := setupHTTPToolTest(t, handler, paginatedCall(configv1.PaginationConfig_builder{
+		Style:     configv1.PaginationConfig_STYLE_OFFSET.Enum(),
+		Param:     proto.String("offset"),
+		ItemsPath: proto.String("{.items}"),
+	}.Build()))
+	defer server.Close()
+
+	for _, inputs := range []string{`{"cursor": "!!"}`, `{"cursor": "LTE"}`, `{"cursor": 3}`} {
+		_, err := httpTool.Execute(context.Background(), &tool.ExecutionRequest{ToolInputs: json.RawMessage(inputs)})
+		assert.ErrorContains(t, err, "invalid cursor", inputs)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/transformer"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// contentHandler returns HTTP response bodies according to their MIME type,
+// as configured by a ResponseContentConfig.
+type contentHandler struct {
+	cfg       *configv1.ResponseContentConfig
+	delimiter rune
+}
+
+func newContentHandler(cfg *configv1.ResponseContentConfig) (*contentHandler, error) {
+	if cfg == nil || cfg.GetMode() == configv1.ResponseContentConfig_MODE_UNSPECIFIED {
+		return nil, nil
+	}
+	h := &contentHandler{cfg: cfg}
+	if d := cfg.GetCsvDelimiter(); d != "" {
+		r, size := utf8.DecodeRuneInString(d)
+		if r == utf8.RuneError || size != len(d) {
+			return nil, fmt.Errorf("csv_delimiter must be a single character, got %q", d)
+		}
+		h.delimiter = r
+	}
+	return h, nil
+}
+
+// accept returns the Accept header of the request.
+func (h *contentHandler) accept() string {
+	if h == nil || h.cfg.GetAccept() == "" {
+		return "*/*"
+	}
+	return h.cfg.GetAccept()
+}
+
+// mimeType returns the declared MIME type, else the media type of the
+// Content-Type header, else the sniffed type of the body.
+func (h *contentHandler) mimeType(header http.Header, body []byte) string {
+	raw := h.cfg.GetMimeType()
+	if raw == "" {
+		raw = header.Get("Content-Type")
+	}
+	if raw == "" {
+		raw = http.DetectContentType(body)
+	}
+	mediaType, _, err := mime.ParseMediaType(raw)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(raw))
+	}
+	return mediaType
+}
+
+// handle returns the body as configured.
+func (h *contentHandler) handle(body []byte, header http.Header, uri string) (any, error) {
+	mimeType := h.mimeType(header, body)
+	switch h.cfg.GetMode() {
+	case configv1.ResponseContentConfig_MODE_AUTO:
+		if strings.HasPrefix(mimeType, "image/") {
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.ImageContent{Data: body, MIMEType: mimeType}}}, nil
+		}
+		if isJSONMimeType(mimeType) || isXMLMimeType(mimeType) || isCSVMimeType(mimeType) {
+			if converted, err := h.convert(body, mimeType); err == nil {
+				return converted, nil
+			}
+		}
+		if isTextMimeType(mimeType) && utf8.Valid(body) {
+			return string(body), nil
+		}
+		return resourceResult(uri, mimeType, body, false), nil
+	case configv1.ResponseContentConfig_MODE_CONVERT:
+		return h.convert(body, mimeType)
+	case configv1.ResponseContentConfig_MODE_RESOURCE:
+		return resourceResult(uri, mimeType, body, isTextMimeType(mimeType) && utf8.Valid(body)), nil
+	case configv1.ResponseContentConfig_MODE_BASE64:
+		return map[string]any{
+			"mimeType": mimeType,
+			"size":     len(body),
+			"data":     base64.StdEncoding.EncodeToString(body),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported response content mode %s", h.cfg.GetMode())
+	}
+}
+
+// convert parses JSON, and converts XML and CSV to JSON values.
+func (h *contentHandler) convert(body []byte, mimeType string) (any, error) {
+	switch {
+	case isJSONMimeType(mimeType):
+		var result any
+		if err := fastJSON.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+		}
+		return result, nil
+	case isXMLMimeType(mimeType):
+		return transformer.XMLToJSON(body, transformer.XMLOptions{})
+	case isCSVMimeType(mimeType):
+		opts := transformer.CSVOptions{Delimiter: h.delimiter, Header: transformer.CSVHeaderPresent}
+		if h.cfg.HasCsvHeader() && !h.cfg.GetCsvHeader() {
+			opts.Header = transformer.CSVHeaderAbsent
+		}
+		return transformer.CSVToRows(body, opts)
+	default:
+		return nil, fmt.Errorf("cannot convert %q responses; use MODE_RESOURCE or MODE_BASE64", mimeType)
+	}
+}
+
+func resourceResult(uri, mimeType string, body []byte, text bool) *mcp.CallToolResult {
+	contents := &mcp.ResourceContents{URI: uri, MIMEType: mimeType}
+	if text {
+		contents.Text = string(body)
+	} else {
+		contents.Blob = body
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.EmbeddedResource{Resource: contents}}}
+}
+
+func isJSONMimeType(mimeType string) bool {
+	return mimeType == contentTypeJSON || strings.HasSuffix(mimeType, "+json")
+}
+
+func isXMLMimeType(mimeType string) bool {
+	return mimeType == "application/xml" || mimeType == "text/xml" || strings.HasSuffix(mimeType, "+xml")
+}
+
+func isCSVMimeType(mimeType string) bool {
+	return mimeType == "text/csv" || mimeType == "application/csv"
+}
+
+func isTextMimeType(mimeType string) bool {
+	if strings.HasPrefix(mimeType, "text/") || isJSONMimeType(mimeType) || isXMLMimeType(mimeType) {
+		return true
+	}
+	switch mimeType {
+	case "application/javascript", "application/yaml", "application/x-yaml", "application/x-ndjson", "application/graphql":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// contentServer serves the body of kind with its content type.
+func contentServer(t *testing.T, kind string, accept *string) http.Handler {
+	t.Helper()
+	bodies := map[string]struct{ contentType, body string }{
+		"xml":    {"application/xml; charset=utf-8", `<users><user id="1"><name>ada</name></user><user id="2"><name>bob</name></user></users>`},
+		"csv":    {"text/csv", "name;age\nada;36\n"},
+		"json":   {"application/json", `{"ok":true}`},
+		"text":   {"text/plain", "hello"},
+		"png":    {"image/png", string(pngHeader)},
+		"binary": {"application/octet-stream", "\x00\x01\x02\xff"},
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if accept != nil {
+			*accept = r.Header.Get("Accept")
+		}
+		b, ok := bodies[kind]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", b.contentType)
+		_, _ = w.Write([]byte(b.body))
+	})
+}
+
+func executeContent(t *testing.T, cfg *configv1.ResponseContentConfig, kind string) (any, error) {
+	t.Helper()
+	httpTool, server := setupHTTPToolTest(t, contentServer(t, kind, nil), configv1.HttpCallDefinition_builder{
+		Method:          configv1.HttpCallDefinition_HTTP_METHOD_GET.Enum(),
+		ResponseContent: cfg,
+	}.Build())
+	defer server.Close()
+	return httpTool.Execute(context.Background(), &tool.ExecutionRequest{ToolInputs: json.RawMessage(`{}`)})
+}
+
+func contentConfig(mode configv1.ResponseContentConfig_Mode) *configv1.ResponseContentConfig {
+	return configv1.ResponseContentConfig_builder{Mode: mode.Enum()}.Build()
+}
+
+func TestHTTPTool_ResponseContent_Convert(t *testing.T) {
+	cfg := configv1.ResponseContentConfig_builder{
+		Mode:         configv1.ResponseContentConfig_MODE_CONVERT.Enum(),
+		CsvDelimiter: proto.String(";"),
+	}.Build()
+
+	result, err := executeContent(t, cfg, "xml")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"users": map[string]any{"user": []any{
+		map[string]any{"@id": "1", "name": "ada"},
+		map[string]any{"@id": "2", "name": "bob"},
+	}}}, result)
+
+	result, err = executeContent(t, cfg, "csv")
+	require.NoError(t, err)
+	assert.Equal(t, []any{map[string]any{"name": "ada", "age": "36"}}, result)
+
+	result, err = executeContent(t, cfg, "json")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"ok": true}, result)
+
+	_, err = executeContent(t, cfg, "binary")
+	assert.ErrorContains(t, err, `cannot convert "application/octet-stream" responses`)
+}
+
+func TestHTTPTool_ResponseContent_Auto(t *testing.T) {
+	cfg := contentConfig(configv1.ResponseContentConfig_MODE_AUTO)
+
+	result, err := executeContent(t, cfg, "text")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", result)
+
+	result, err = executeContent(t, cfg, "csv")
+	require.NoError(t, err)
+	assert.Equal(t, []any{map[string]any{"name;age": "ada;36"}}, result, "the default delimiter is a comma")
+
+	result, err = executeContent(t, cfg, "png")
+	require.NoError(t, err)
+	ctr, ok := result.(*mcp.CallToolResult)
+	require.True(t, ok)
+	image, ok := ctr.Content[0].(*mcp.ImageContent)
+	require.True(t, ok)
+	assert.Equal(t, "image/png", image.MIMEType)
+	assert.Equal(t, pngHeader, image.Data)
+
+	result, err = executeContent(t, cfg, "binary")
+	require.NoError(t, err)
+	ctr, ok = result.(*mcp.CallToolResult)
+	require.True(t, ok)
+	resource, ok := ctr.Content[0].(*mcp.EmbeddedResource)
+	require.True(t, ok)
+	assert.Equal(t, "application/octet-stream", resource.Resource.MIMEType)
+	assert.Equal(t, []byte("\x00\x01\x02\xff"), resource.Resource.Blob)
+	assert.Contains(t, resource.Resource.URI, "http://127.0.0.1")
+}
+
+func TestHTTPTool_ResponseContent_Resource(t *testing.T) {
+	cfg := contentConfig(configv1.ResponseContentConfig_MODE_RESOURCE)
+
+	result, err := executeContent(t, cfg, "xml")
+	require.NoError(t, err)
+	resource := result.(*mcp.CallToolResult).Content[0].(*mcp.EmbeddedResource).Resource
+	assert.Equal(t, "application/xml", resource.MIMEType)
+	assert.Contains(t, resource.Text, "<users>")
+	assert.Nil(t, resource.Blob)
+
+	result, err = executeContent(t, cfg, "binary")
+	require.NoError(t, err)
+	resource = result.(*mcp.CallToolResult).Content[0].(*mcp.EmbeddedResource).Resource
+	assert.Equal(t, []byte("\x00\x01\x02\xff"), resource.Blob)
+}
+
+func TestHTTPTool_ResponseContent_Base64(t *testing.T) {
+	cfg := configv1.ResponseContentConfig_builder{
+		Mode:     configv1.ResponseContentConfig_MODE_BASE64.Enum(),
+		MimeType: proto.String("application/x-protobuf"),
+	}.Build()
+
+	result, err := executeContent(t, cfg, "binary")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"mimeType": "application/x-protobuf",
+		"size":     4,
+		"data":     base64.StdEncoding.EncodeToString([]byte("\x00\x01\x02\xff")),
+	}, result)
+}
+
+func TestHTTPTool_ResponseContent_Accept(t *testing.T) {
+	var accept string
+	httpTool, server := setupHTTPToolTest(t, contentServer(t, "csv", &accept), configv1.HttpCallDefinition_builder{
+		Method: configv1.HttpCallDefinition_HTTP_METHOD_GET.Enum(),
+		ResponseContent: configv1.ResponseContentConfig_builder{
+			Mode:      configv1.ResponseContentConfig_MODE_CONVERT.Enum(),
+			Accept:    proto.String("text/csv"),
+			CsvHeader: proto.Bool(false),
+		}.Build(),
+	}.Build())
+	defer server.Close()
+
+	result, err := httpTool.Execute(context.Background(), &tool.ExecutionRequest{ToolInputs: json.RawMessage(`{}`)})
+	require.NoError(t, err)
+	assert.Equal(t, "text/csv", accept)
+	assert.Equal(t, []any{[]any{"name;age"}, []any{"ada;36"}}, result)
+}
+
+func TestHTTPTool_ResponseContent_InvalidDelimiter(t *testing.T) {
+	_, err := executeContent(t, configv1.ResponseContentConfig_builder{
+		Mode:         configv1.ResponseContentConfig_MODE_CONVERT.Enum(),
+		CsvDelimiter: proto.String(";;"),
+	}.Build(), "csv")
+	assert.ErrorContains(t, err, "csv_delimiter must be a single character")
+}
@@ -0,0 +1,258 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	webhookv1 "github.com/mcpany/core/proto/webhook/v1"
+	"github.com/mcpany/core/server/pkg/util"
+	"github.com/mcpany/core/server/pkg/webhooks"
+	webhook "github.com/standard-webhooks/standard-webhooks/libraries/go"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// reviewChunkSize is the size of the chunks of an object streamed to a gRPC
+// webhook. An object up to this size is sent in one Review call.
+var reviewChunkSize = 1 << 20
+
+// reviewConns holds the connections to the gRPC webhooks, shared by the
+// webhooks with the same target and TLS settings. A connection is closed
+// when the last client using it is garbage collected.
+var reviewConns = struct {
+	sync.Mutex
+	m map[string]*reviewConn
+}{m: make(map[string]*reviewConn)}
+
+// reviewConn is a shared connection to a gRPC webhook.
+type reviewConn struct {
+	conn *grpc.ClientConn
+	refs int
+}
+
+// reviewClient sends the events of a webhook to its ReviewService.
+type reviewClient struct {
+	conn    *grpc.ClientConn
+	client  webhookv1.ReviewServiceClient
+	signers []*webhook.Webhook
+}
+
+// newReviewClient returns the client of the ReviewService of a webhook,
+// reusing the connection to its target if there is one. The connection uses
+// TLS unless the webhook is plaintext.
+func newReviewClient(config *configv1.WebhookConfig, signers []*webhook.Webhook) (*reviewClient, error) {
+	target := config.GetUrl()
+	mtls := config.GetMtls()
+	key := strings.Join([]string{target, strconv.FormatBool(config.GetPlaintext()), mtls.GetClientCertPath(), mtls.GetClientKeyPath(), mtls.GetCaCertPath()}, "\x00")
+
+	reviewConns.Lock()
+	defer reviewConns.Unlock()
+	shared, ok := reviewConns.m[key]
+	if !ok {
+		creds := insecure.NewCredentials()
+		if !config.GetPlaintext() {
+			tlsConfig, err := util.NewTLSClientConfig(util.TLSConfigWithMTLS(nil, mtls))
+			if err != nil {
+				return nil, fmt.Errorf("webhook mtls: %w", err)
+			}
+			creds = credentials.NewTLS(tlsConfig)
+		}
+		conn, err := grpc.NewClient(target,
+			grpc.WithTransportCredentials(creds),
+			grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gRPC webhook client for %q: %w", target, err)
+		}
+		shared = &reviewConn{conn: conn}
+		reviewConns.m[key] = shared
+	}
+	shared.refs++
+	c := &reviewClient{conn: shared.conn, client: webhookv1.NewReviewServiceClient(shared.conn), signers: signers}
+	// The webhooks are replaced on reload without being closed, so the
+	// connection is released with its last client.
+	runtime.AddCleanup(c, releaseReviewConn, reviewConnRef{key: key, shared: shared})
+	return c, nil
+}
+
+// reviewConnRef is the connection of a reviewClient, released when the
+// client is garbage collected.
+type reviewConnRef struct {
+	key    string
+	shared *reviewConn
+}
+
+// releaseReviewConn drops a reference to a shared connection, and closes it
+// when no client uses it any more.
+func releaseReviewConn(ref reviewConnRef) {
+	reviewConns.Lock()
+	defer reviewConns.Unlock()
+	ref.shared.refs--
+	if ref.shared.refs > 0 || reviewConns.m[ref.key] != ref.shared {
+		// Still used, or already closed by CloseReviewConns
+		return
+	}
+	delete(reviewConns.m, ref.key)
+	_ = ref.shared.conn.Close()
+}
+
+// CloseReviewConns closes the connections to the gRPC webhooks. A webhook
+// called afterwards fails its calls.
+//
+// Summary: Closes the connections to the gRPC webhooks.
+//
+// Returns:
+//   - error: The first error closing a connection.
+func CloseReviewConns() error {
+	reviewConns.Lock()
+	defer reviewConns.Unlock()
+	var firstErr error
+	for key, shared := range reviewConns.m {
+		if err := shared.conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(reviewConns.m, key)
+	}
+	return firstErr
+}
+
+// reviewEvent is the data of a webhook event, as sent by the hooks.
+type reviewEvent struct {
+	Kind     configv1.WebhookKind `json:"kind"`
+	ToolName string               `json:"tool_name"`
+	Inputs   json.RawMessage      `json:"inputs"`
+	Result   json.RawMessage      `json:"result"`
+}
+
+// send makes one attempt of a call to the webhook, and returns its response
+// as the CloudEvent the HTTP webhooks answer with.
+func (c *reviewClient) send(ctx context.Context, eventID string, data any) (*cloudevents.Event, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook event: %w", err)
+	}
+	var event reviewEvent
+	if err := json.Unmarshal(b, &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook event: %w", err)
+	}
+	object := event.Inputs
+	if object == nil {
+		object = event.Result
+	}
+	if object == nil {
+		object = json.RawMessage("null")
+	}
+
+	if len(c.signers) > 0 {
+		signature, err := webhooks.Sign(c.signers, eventID, time.Now(), object)
+		if err != nil {
+			return nil, err
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx, signature...)
+	}
+
+	req := webhookv1.ReviewRequest_builder{
+		Uid:      eventID,
+		Kind:     event.Kind,
+		ToolName: event.ToolName,
+	}.Build()
+	var resp *webhookv1.ReviewResponse
+	if len(object) <= reviewChunkSize {
+		req.SetObject(object)
+		resp, err = c.client.Review(ctx, req)
+	} else {
+		resp, err = c.stream(ctx, req, object)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to send webhook event: %w", err)
+	}
+	return reviewResponseEvent(eventID, event.Kind, resp)
+}
+
+// stream reviews an object too large for one message with ReviewStream.
+func (c *reviewClient) stream(ctx context.Context, req *webhookv1.ReviewRequest, object []byte) (*webhookv1.ReviewResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.client.ReviewStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for len(object) > 0 {
+		n := min(reviewChunkSize, len(object))
+		req.SetObject(object[:n])
+		if err := stream.Send(req); err != nil {
+			// The error of the stream is returned by Recv.
+			break
+		}
+		object = object[n:]
+		req = webhookv1.ReviewRequest_builder{}.Build()
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	var resp *webhookv1.ReviewResponse
+	var replacement []byte
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if resp == nil {
+			resp = chunk
+		}
+		replacement = append(replacement, chunk.GetReplacementObject()...)
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("webhook error: no review response received")
+	}
+	resp.SetReplacementObject(replacement)
+	return resp, nil
+}
+
+// reviewResponseEvent converts the response of a gRPC webhook to the
+// CloudEvent an HTTP webhook answers with. An input transformation is
+// answered with the transformed inputs.
+func reviewResponseEvent(eventID string, kind configv1.WebhookKind, resp *webhookv1.ReviewResponse) (*cloudevents.Event, error) {
+	event := cloudevents.NewEvent()
+	event.SetID(eventID)
+	event.SetSource("https://github.com/mcpany/core")
+	event.SetType("com.mcpany.webhook.response")
+
+	if kind == configv1.WebhookKind_WEBHOOK_KIND_TRANSFORM_INPUT {
+		if err := event.SetData(cloudevents.ApplicationJSON, resp.GetReplacementObject()); err != nil {
+			return nil, fmt.Errorf("failed to set response event data: %w", err)
+		}
+		return &event, nil
+	}
+	data := map[string]any{"allowed": resp.GetAllowed()}
+	if resp.HasStatus() {
+		data["status"] = WebhookStatus{Code: int(resp.GetStatus().GetCode()), Message: resp.GetStatus().GetMessage()}
+	}
+	if len(resp.GetReplacementObject()) > 0 {
+		data["replacement_object"] = json.RawMessage(resp.GetReplacementObject())
+	}
+	if err := event.SetData(cloudevents.ApplicationJSON, data); err != nil {
+		return nil, fmt.Errorf("failed to set response event data: %w", err)
+	}
+	return &event, nil
+}
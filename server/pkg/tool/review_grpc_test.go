@@ -0,0 +1,263 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	webhookv1 "github.com/mcpany/core/proto/webhook/v1"
+	webhook "github.com/standard-webhooks/standard-webhooks/libraries/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/metadata"
+)
+
+// testReviewServer is a ReviewService answering with review, and recording
+// the requests it receives.
+type testReviewServer struct {
+	webhookv1.UnimplementedReviewServiceServer
+
+	review func(req *webhookv1.ReviewRequest) *webhookv1.ReviewResponse
+
+	mu       sync.Mutex
+	requests []*webhookv1.ReviewRequest
+	headers  []http.Header
+	streamed int
+}
+
+func (s *testReviewServer) record(ctx context.Context, req *webhookv1.ReviewRequest) {
+	header := http.Header{}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, key := range []string{"webhook-id", "webhook-timestamp", "webhook-signature"} {
+		if v := md.Get(key); len(v) > 0 {
+			header.Set(key, v[0])
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, req)
+	s.headers = append(s.headers, header)
+}
+
+func (s *testReviewServer) Review(ctx context.Context, req *webhookv1.ReviewRequest) (*webhookv1.ReviewResponse, error) {
+	s.record(ctx, req)
+	return s.review(req), nil
+}
+
+func (s *testReviewServer) ReviewStream(stream webhookv1.ReviewService_ReviewStreamServer) error {
+	var req *webhookv1.ReviewRequest
+	var object []byte
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if req == nil {
+			req = chunk
+		}
+		object = append(object, chunk.GetObject()...)
+	}
+	req.SetObject(object)
+	s.record(stream.Context(), req)
+	s.mu.Lock()
+	s.streamed++
+	s.mu.Unlock()
+
+	// The replacement is answered in two chunks.
+	resp := s.review(req)
+	replacement := resp.GetReplacementObject()
+	resp.SetReplacementObject(replacement[:len(replacement)/2])
+	if err := stream.Send(resp); err != nil {
+		return err
+	}
+	return stream.Send(webhookv1.ReviewResponse_builder{ReplacementObject: replacement[len(replacement)/2:]}.Build())
+}
+
+// startReviewServer serves srv on a local port and returns its target.
+func startReviewServer(t *testing.T, srv *testReviewServer) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := grpc.NewServer()
+	webhookv1.RegisterReviewServiceServer(s, srv)
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+	return lis.Addr().String()
+}
+
+func TestWebhookHook_GRPC(t *testing.T) {
+	srv := &testReviewServer{review: func(req *webhookv1.ReviewRequest) *webhookv1.ReviewResponse {
+		switch {
+		case strings.Contains(string(req.GetObject()), "forbidden"):
+			return webhookv1.ReviewResponse_builder{
+				Status: configv1.WebhookStatus_builder{Code: 403, Message: "forbidden path"}.Build(),
+			}.Build()
+		case req.GetKind() == configv1.WebhookKind_WEBHOOK_KIND_POST_CALL:
+			return webhookv1.ReviewResponse_builder{Allowed: true, ReplacementObject: []byte(`{"redacted":true}`)}.Build()
+		}
+		return webhookv1.ReviewResponse_builder{Allowed: true}.Build()
+	}}
+	target := startReviewServer(t, srv)
+	hook := NewWebhookHook(configv1.WebhookConfig_builder{
+		Url:             target,
+		Protocol:        configv1.WebhookConfig_GRPC,
+		Plaintext:       true,
+		WebhookSecret:   testWebhookSecret,
+		PreviousSecrets: []string{testPreviousWebhookSecret},
+	}.Build())
+
+	t.Run("allowed", func(t *testing.T) {
+		action, _, err := hook.ExecutePre(context.Background(), &ExecutionRequest{ToolName: "svc.read", ToolInputs: []byte(`{"path":"/tmp/a"}`)})
+		require.NoError(t, err)
+		assert.Equal(t, ActionAllow, action)
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		action, _, err := hook.ExecutePre(context.Background(), &ExecutionRequest{ToolName: "svc.read", ToolInputs: []byte(`{"path":"/forbidden"}`)})
+		require.Error(t, err)
+		assert.Equal(t, ActionDeny, action)
+		assert.Equal(t, "denied by webhook: forbidden path", err.Error())
+	})
+
+	t.Run("post-call replacement", func(t *testing.T) {
+		result, err := hook.ExecutePost(context.Background(), &ExecutionRequest{ToolName: "svc.read"}, map[string]any{"secret": "s3cr3t"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"redacted": true}, result)
+	})
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	require.Len(t, srv.requests, 3)
+	assert.Equal(t, configv1.WebhookKind_WEBHOOK_KIND_PRE_CALL, srv.requests[0].GetKind())
+	assert.Equal(t, "svc.read", srv.requests[0].GetToolName())
+	assert.JSONEq(t, `{"path":"/tmp/a"}`, string(srv.requests[0].GetObject()))
+	assert.JSONEq(t, `{"secret":"s3cr3t"}`, string(srv.requests[2].GetObject()))
+	for i, req := range srv.requests {
+		assert.Equal(t, req.GetUid(), srv.headers[i].Get("webhook-id"))
+		for _, secret := range []string{testWebhookSecret, testPreviousWebhookSecret} {
+			wh, err := webhook.NewWebhook(secret)
+			require.NoError(t, err)
+			assert.NoError(t, wh.Verify(req.GetObject(), srv.headers[i]), "the object is signed with each secret")
+		}
+	}
+}
+
+func TestWebhookHook_GRPCStream(t *testing.T) {
+	// Not parallel: the test lowers the chunk size shared by the webhooks.
+	defer func(size int) { reviewChunkSize = size }(reviewChunkSize)
+	reviewChunkSize = 16
+
+	srv := &testReviewServer{review: func(_ *webhookv1.ReviewRequest) *webhookv1.ReviewResponse {
+		return webhookv1.ReviewResponse_builder{Allowed: true, ReplacementObject: []byte(`{"summary":"a long result, replaced"}`)}.Build()
+	}}
+	target := startReviewServer(t, srv)
+	hook := NewWebhookHook(configv1.WebhookConfig_builder{Url: target, Protocol: configv1.WebhookConfig_GRPC, Plaintext: true}.Build())
+
+	result, err := hook.ExecutePost(context.Background(), &ExecutionRequest{ToolName: "svc.fetch"}, map[string]any{"body": strings.Repeat("x", 100)})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"summary": "a long result, replaced"}, result)
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	assert.Equal(t, 1, srv.streamed)
+	require.Len(t, srv.requests, 1)
+	assert.Equal(t, "svc.fetch", srv.requests[0].GetToolName())
+	assert.JSONEq(t, `{"body":"`+strings.Repeat("x", 100)+`"}`, string(srv.requests[0].GetObject()))
+}
+
+func TestWebhookClient_GRPCTransformInput(t *testing.T) {
+	t.Parallel()
+	srv := &testReviewServer{review: func(_ *webhookv1.ReviewRequest) *webhookv1.ReviewResponse {
+		return webhookv1.ReviewResponse_builder{Allowed: true, ReplacementObject: []byte(`<query id="1"/>`)}.Build()
+	}}
+	target := startReviewServer(t, srv)
+	client := NewWebhookClient(configv1.WebhookConfig_builder{Url: target, Protocol: configv1.WebhookConfig_GRPC, Plaintext: true}.Build())
+
+	event, err := client.Call(context.Background(), "com.mcpany.tool.transform_input", map[string]any{
+		"kind":      configv1.WebhookKind_WEBHOOK_KIND_TRANSFORM_INPUT,
+		"tool_name": "svc.query",
+		"inputs":    map[string]any{"id": 1},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `<query id="1"/>`, string(event.Data()), "the transformed inputs are the data of the response")
+}
+
+func TestWebhookClient_GRPCConnectionReuse(t *testing.T) {
+	t.Parallel()
+	srv := &testReviewServer{review: func(_ *webhookv1.ReviewRequest) *webhookv1.ReviewResponse {
+		return webhookv1.ReviewResponse_builder{Allowed: true}.Build()
+	}}
+	target := startReviewServer(t, srv)
+	config := configv1.WebhookConfig_builder{Url: target, Protocol: configv1.WebhookConfig_GRPC, Plaintext: true}.Build()
+
+	first := NewWebhookClient(config)
+	second := NewWebhookClient(config)
+	require.NotNil(t, first.review)
+	require.NotNil(t, second.review)
+	assert.Same(t, first.review.conn, second.review.conn, "the webhooks with the same target share their connection")
+
+	_, err := first.Call(context.Background(), "com.mcpany.tool.pre_call", map[string]any{"tool_name": "svc.tool"})
+	require.NoError(t, err)
+}
+
+func TestWebhookClient_GRPCDefaultsToTLS(t *testing.T) {
+	t.Parallel()
+	srv := &testReviewServer{review: func(_ *webhookv1.ReviewRequest) *webhookv1.ReviewResponse {
+		return webhookv1.ReviewResponse_builder{Allowed: true}.Build()
+	}}
+	target := startReviewServer(t, srv)
+
+	client := NewWebhookClient(configv1.WebhookConfig_builder{Url: target, Protocol: configv1.WebhookConfig_GRPC}.Build())
+	_, err := client.Call(context.Background(), "com.mcpany.tool.pre_call", map[string]any{"tool_name": "svc.tool"})
+	require.Error(t, err, "a plaintext server does not complete the TLS handshake")
+}
+
+func TestWebhookClient_GRPCConnectionRelease(t *testing.T) {
+	t.Parallel()
+	srv := &testReviewServer{review: func(_ *webhookv1.ReviewRequest) *webhookv1.ReviewResponse {
+		return webhookv1.ReviewResponse_builder{Allowed: true}.Build()
+	}}
+	target := startReviewServer(t, srv)
+	config := configv1.WebhookConfig_builder{Url: target, Protocol: configv1.WebhookConfig_GRPC, Plaintext: true}.Build()
+
+	conn := func() *grpc.ClientConn {
+		client := NewWebhookClient(config)
+		require.NotNil(t, client.review)
+		return client.review.conn
+	}()
+
+	// The connection is closed once no webhook uses it.
+	require.Eventually(t, func() bool {
+		runtime.GC()
+		return conn.GetState() == connectivity.Shutdown
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestWebhookClient_GRPCUnavailable(t *testing.T) {
+	t.Parallel()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	target := lis.Addr().String()
+	require.NoError(t, lis.Close())
+
+	hook := NewWebhookHook(configv1.WebhookConfig_builder{Url: target, Protocol: configv1.WebhookConfig_GRPC, Plaintext: true}.Build())
+	_, _, err = hook.ExecutePre(context.Background(), &ExecutionRequest{ToolName: "svc.tool"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "webhook error")
+}
@@ -38,6 +38,22 @@ type Session interface {
 	ListRoots(ctx context.Context) (*mcp.ListRootsResult, error)
 }
 
+// KeyedSession is a Session that can be told apart from the other client
+// sessions across its calls, e.g. to route them all to the same upstream
+// session.
+type KeyedSession interface {
+	Session
+
+	// SessionKey returns a comparable key, the same for every call of the
+	// client session.
+	//
+	// Summary: Identifies the client session.
+	//
+	// Returns:
+	//   - any: The key of the session.
+	SessionKey() any
+}
+
 // Sampler is an alias for Session for backward compatibility.
 type Sampler = Session
 
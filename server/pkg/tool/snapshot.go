@@ -0,0 +1,201 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"fmt"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	pb "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/proto"
+)
+
+// SnapshotMetaKey is the tool metadata key that flags a tool listed from the
+// catalog snapshot of a service that is not discovered yet.
+const SnapshotMetaKey = "mcpany/snapshot"
+
+// SnapshotTool is a tool listed from the last-known tool catalog of a
+// service, until the service is discovered again. It cannot be called.
+//
+// Summary: A stale tool served from a catalog snapshot.
+type SnapshotTool struct {
+	tool    *pb.Tool
+	mcpTool *mcp.Tool
+}
+
+// NewSnapshotTool creates a tool from its snapshot.
+//
+// Summary: Creates a SnapshotTool.
+//
+// Parameters:
+//   - serviceID: string. The ID of the service of the tool.
+//   - snapshot: *configv1.ToolSnapshot. The snapshot of the tool.
+//   - capturedAt: string. When the catalog was discovered, in RFC 3339 format.
+//
+// Returns:
+//   - *SnapshotTool: The tool.
+//   - error: An error if the snapshot has no name.
+func NewSnapshotTool(serviceID string, snapshot *configv1.ToolSnapshot, capturedAt string) (*SnapshotTool, error) {
+	annotations := pb.ToolAnnotations_builder{
+		Title:           proto.String(snapshot.GetTitle()),
+		ReadOnlyHint:    proto.Bool(snapshot.GetReadOnlyHint()),
+		DestructiveHint: proto.Bool(snapshot.GetDestructiveHint()),
+		IdempotentHint:  proto.Bool(snapshot.GetIdempotentHint()),
+		OpenWorldHint:   proto.Bool(snapshot.GetOpenWorldHint()),
+		InputSchema:     snapshot.GetInputSchema(),
+		OutputSchema:    snapshot.GetOutputSchema(),
+	}.Build()
+	t := pb.Tool_builder{
+		Name:         proto.String(snapshot.GetName()),
+		ServiceId:    proto.String(serviceID),
+		DisplayName:  proto.String(snapshot.GetTitle()),
+		Description:  proto.String(snapshot.GetDescription()),
+		InputSchema:  snapshot.GetInputSchema(),
+		OutputSchema: snapshot.GetOutputSchema(),
+		Annotations:  annotations,
+	}.Build()
+	mcpTool, err := ConvertProtoToMCPTool(t)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tool snapshot: %w", err)
+	}
+	mcpTool.Meta = mcp.Meta{SnapshotMetaKey: map[string]any{
+		"stale":      true,
+		"capturedAt": capturedAt,
+	}}
+	return &SnapshotTool{tool: t, mcpTool: mcpTool}, nil
+}
+
+// Tool returns the protobuf definition of the tool.
+//
+// Returns:
+//   - *pb.Tool: The tool definition.
+func (t *SnapshotTool) Tool() *pb.Tool {
+	return t.tool
+}
+
+// MCPTool returns the MCP definition of the tool, flagged as stale in its
+// metadata.
+//
+// Returns:
+//   - *mcp.Tool: The MCP tool definition.
+func (t *SnapshotTool) MCPTool() *mcp.Tool {
+	return t.mcpTool
+}
+
+// Execute fails, since the service of the tool is not discovered yet.
+//
+// Parameters:
+//   - _: context.Context. Unused.
+//   - _: *ExecutionRequest. Unused.
+//
+// Returns:
+//   - any: Always nil.
+//   - error: An error wrapping ErrServiceNotDiscovered.
+func (t *SnapshotTool) Execute(_ context.Context, _ *ExecutionRequest) (any, error) {
+	return nil, fmt.Errorf("%w: tool %q is listed from the last-known catalog of service %q, try again once it is connected",
+		ErrServiceNotDiscovered, t.mcpTool.Name, t.tool.GetServiceId())
+}
+
+// GetCacheConfig returns nil, since the tool cannot be called.
+//
+// Returns:
+//   - *configv1.CacheConfig: Always nil.
+func (t *SnapshotTool) GetCacheConfig() *configv1.CacheConfig {
+	return nil
+}
+
+// SnapshotOf captures the snapshot of a tool.
+//
+// Summary: Converts a tool to its snapshot.
+//
+// Parameters:
+//   - t: Tool. The tool.
+//
+// Returns:
+//   - *configv1.ToolSnapshot: The snapshot of the tool.
+func SnapshotOf(t Tool) *configv1.ToolSnapshot {
+	def := t.Tool()
+	annotations := def.GetAnnotations()
+	title := def.GetDisplayName()
+	if title == "" {
+		title = annotations.GetTitle()
+	}
+	inputSchema := def.GetInputSchema()
+	if inputSchema == nil {
+		inputSchema = annotations.GetInputSchema()
+	}
+	outputSchema := def.GetOutputSchema()
+	if outputSchema == nil {
+		outputSchema = annotations.GetOutputSchema()
+	}
+	return configv1.ToolSnapshot_builder{
+		Name:            proto.String(def.GetName()),
+		Title:           proto.String(title),
+		Description:     proto.String(def.GetDescription()),
+		InputSchema:     inputSchema,
+		OutputSchema:    outputSchema,
+		ReadOnlyHint:    proto.Bool(annotations.GetReadOnlyHint()),
+		DestructiveHint: proto.Bool(annotations.GetDestructiveHint()),
+		IdempotentHint:  proto.Bool(annotations.GetIdempotentHint()),
+		OpenWorldHint:   proto.Bool(annotations.GetOpenWorldHint()),
+	}.Build()
+}
+
+// SnapshotToolRemover is implemented by the tool managers that can drop the
+// tools listed from the catalog snapshot of a service once it is discovered.
+//
+// Summary: Interface for removing the snapshot tools of a service.
+type SnapshotToolRemover interface {
+	// RemoveSnapshotTools removes the snapshot tools of a service, keeping the
+	// tools registered by the service itself.
+	//
+	// Summary: Removes the snapshot tools of a service.
+	//
+	// Parameters:
+	//   - serviceID: string. The ID of the service.
+	//
+	// Returns:
+	//   - int: The number of tools removed.
+	RemoveSnapshotTools(serviceID string) int
+}
+
+// RemoveSnapshotTools removes the snapshot tools of a service, keeping the
+// tools registered by the service itself.
+//
+// Summary: Removes the snapshot tools of a service.
+//
+// Parameters:
+//   - serviceID: string. The ID of the service.
+//
+// Returns:
+//   - int: The number of tools removed.
+func (tm *Manager) RemoveSnapshotTools(serviceID string) int {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	removed := 0
+	for toolID := range tm.serviceToolIDs[serviceID] {
+		t, ok := tm.tools.Load(toolID)
+		if !ok {
+			continue
+		}
+		if _, isSnapshot := t.(*SnapshotTool); !isSnapshot {
+			continue
+		}
+		nameKey := serviceID + "." + t.Tool().GetName()
+		tm.tools.Delete(toolID)
+		tm.nameMap.Delete(nameKey)
+		delete(tm.serviceToolIDs[serviceID], toolID)
+		delete(tm.serviceToolNames[serviceID], nameKey)
+		removed++
+	}
+	if removed > 0 {
+		tm.toolsMutex.Lock()
+		tm.cachedTools = nil
+		tm.cachedMCPTools = nil
+		tm.toolsMutex.Unlock()
+	}
+	return removed
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	v1 "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestSnapshotTool(t *testing.T) {
+	schema, err := structpb.NewStruct(map[string]any{"type": "object"})
+	require.NoError(t, err)
+	snapshot := configv1.ToolSnapshot_builder{
+		Name:         proto.String("forecast"),
+		Title:        proto.String("Forecast"),
+		Description:  proto.String("Gets the forecast"),
+		InputSchema:  schema,
+		ReadOnlyHint: proto.Bool(true),
+	}.Build()
+
+	st, err := NewSnapshotTool("weather", snapshot, "2026-01-02T03:04:05Z")
+	require.NoError(t, err)
+	mt := st.MCPTool()
+	assert.Equal(t, "weather.forecast", mt.Name)
+	assert.Equal(t, "Gets the forecast", mt.Description)
+	assert.True(t, mt.Annotations.ReadOnlyHint)
+	assert.Equal(t, map[string]any{"stale": true, "capturedAt": "2026-01-02T03:04:05Z"}, mt.Meta[SnapshotMetaKey])
+
+	_, err = st.Execute(context.Background(), &ExecutionRequest{ToolName: "weather.forecast"})
+	assert.ErrorIs(t, err, ErrServiceNotDiscovered)
+
+	captured := SnapshotOf(st)
+	assert.Equal(t, "forecast", captured.GetName())
+	assert.Equal(t, "Forecast", captured.GetTitle())
+	assert.Equal(t, "Gets the forecast", captured.GetDescription())
+	assert.True(t, proto.Equal(schema, captured.GetInputSchema()))
+	assert.True(t, captured.GetReadOnlyHint())
+
+	_, err = NewSnapshotTool("weather", &configv1.ToolSnapshot{}, "")
+	assert.Error(t, err)
+}
+
+func TestManager_RemoveSnapshotTools(t *testing.T) {
+	tm := NewManager(nil)
+	for _, name := range []string{"forecast", "alerts"} {
+		st, err := NewSnapshotTool("weather", configv1.ToolSnapshot_builder{Name: proto.String(name)}.Build(), "")
+		require.NoError(t, err)
+		require.NoError(t, tm.AddTool(st))
+	}
+	// The service registers its own forecast tool, which replaces the
+	// snapshot one.
+	require.NoError(t, tm.AddTool(&MockTool{ToolFunc: func() *v1.Tool {
+		return v1.Tool_builder{Name: proto.String("forecast"), ServiceId: proto.String("weather")}.Build()
+	}}))
+	require.Len(t, tm.ListTools(), 2)
+
+	assert.Equal(t, 1, tm.RemoveSnapshotTools("weather"))
+	_, ok := tm.GetTool("weather.alerts")
+	assert.False(t, ok, "the tools the service no longer has are removed")
+	_, ok = tm.GetTool("weather.forecast")
+	assert.True(t, ok, "the tools registered by the service are kept")
+	assert.Len(t, tm.ListTools(), 1)
+	assert.Equal(t, 0, tm.RemoveSnapshotTools("weather"))
+}
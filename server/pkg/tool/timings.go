@@ -0,0 +1,157 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// CallPhase is a phase of the execution of a tool call.
+//
+// Summary: Identifies a phase of a tool call in its timing breakdown.
+type CallPhase int
+
+const (
+	// PhaseQueue is the time spent waiting for an upstream connection from
+	// the pool of the service.
+	PhaseQueue CallPhase = iota
+	// PhaseWebhook is the time spent in the pre- and post-call hooks of the
+	// service, such as webhooks and call policies.
+	PhaseWebhook
+	// PhaseUpstream is the time spent waiting for the upstream service,
+	// including retries.
+	PhaseUpstream
+	// PhaseTransform is the time spent mapping the arguments to the upstream
+	// request and transforming its response.
+	PhaseTransform
+
+	numCallPhases
+)
+
+// String returns the name of the phase.
+//
+// Returns:
+//   - string: The name of the phase, e.g. "upstream".
+func (p CallPhase) String() string {
+	switch p {
+	case PhaseQueue:
+		return "queue"
+	case PhaseWebhook:
+		return "webhook"
+	case PhaseUpstream:
+		return "upstream"
+	case PhaseTransform:
+		return "transform"
+	default:
+		return "unknown"
+	}
+}
+
+// CallTimings is the time a tool call spends in each of its phases. The
+// tool manager attaches one to the context of every call; the tools add the
+// time of the phases they go through.
+//
+// Summary: Per-phase timing breakdown of a tool call.
+type CallTimings struct {
+	start  time.Time
+	phases [numCallPhases]atomic.Int64
+}
+
+// NewCallTimings creates the timings of a call starting now.
+//
+// Summary: Initializes empty call timings.
+//
+// Returns:
+//   - *CallTimings: The timings.
+func NewCallTimings() *CallTimings {
+	return &CallTimings{start: time.Now()}
+}
+
+// Start returns the time the call started.
+//
+// Returns:
+//   - time.Time: The start of the call.
+func (t *CallTimings) Start() time.Time {
+	return t.start
+}
+
+// Add adds time to a phase.
+//
+// Parameters:
+//   - phase: CallPhase. The phase.
+//   - d: time.Duration. The time spent in the phase.
+//
+// Side Effects:
+//   - Updates the timings.
+func (t *CallTimings) Add(phase CallPhase, d time.Duration) {
+	if phase < 0 || phase >= numCallPhases {
+		return
+	}
+	t.phases[phase].Add(int64(d))
+}
+
+// Get returns the time spent in a phase.
+//
+// Parameters:
+//   - phase: CallPhase. The phase.
+//
+// Returns:
+//   - time.Duration: The time spent in the phase so far.
+func (t *CallTimings) Get(phase CallPhase) time.Duration {
+	if phase < 0 || phase >= numCallPhases {
+		return 0
+	}
+	return time.Duration(t.phases[phase].Load())
+}
+
+const callTimingsContextKey = contextKey("call_timings")
+
+// NewContextWithCallTimings creates a new context with the given CallTimings.
+//
+// Summary: Embeds CallTimings into the context.
+//
+// Parameters:
+//   - ctx: context.Context. The context to extend.
+//   - t: *CallTimings. The timings of the call.
+//
+// Returns:
+//   - context.Context: A new context containing the timings.
+func NewContextWithCallTimings(ctx context.Context, t *CallTimings) context.Context {
+	return context.WithValue(ctx, callTimingsContextKey, t)
+}
+
+// GetCallTimings retrieves the CallTimings from the context.
+//
+// Summary: Retrieves CallTimings from the context.
+//
+// Parameters:
+//   - ctx: context.Context. The context to search.
+//
+// Returns:
+//   - *CallTimings: The timings if found.
+//   - bool: True if the context carries timings, false otherwise.
+func GetCallTimings(ctx context.Context) (*CallTimings, bool) {
+	t, ok := ctx.Value(callTimingsContextKey).(*CallTimings)
+	return t, ok
+}
+
+// RecordCallPhase adds the time elapsed since start to a phase of the call
+// of the context. It does nothing when the context carries no timings.
+//
+// Summary: Records the time spent in a phase of the current call.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the call.
+//   - phase: CallPhase. The phase.
+//   - start: time.Time. The start of the phase.
+//
+// Side Effects:
+//   - Updates the timings of the call.
+func RecordCallPhase(ctx context.Context, phase CallPhase, start time.Time) {
+	if t, ok := GetCallTimings(ctx); ok {
+		t.Add(phase, time.Since(start))
+	}
+}
@@ -0,0 +1,367 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	v1 "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/mcpany/core/server/pkg/util"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	defaultTruncateMaxChars = 4000
+	defaultPaginatePageSize = 1000
+	defaultPageArgument     = "page"
+	// maxMarkdownInput bounds the HTML converted to Markdown, as converting a
+	// huge document is expensive. Larger strings are left as they are.
+	maxMarkdownInput = 1024 * 1024
+)
+
+// compileToolTransforms builds the hooks of the built-in transforms of the
+// tools of a service, keyed by the tool name without the service prefix.
+func compileToolTransforms(serviceConfig *configv1.UpstreamServiceConfig) map[string][]PostCallHook {
+	var transforms map[string][]PostCallHook
+	for _, def := range serviceToolDefinitions(serviceConfig) {
+		var hooks []PostCallHook
+		for _, cfg := range def.GetTransforms() {
+			if h := newTransformHook(cfg); h != nil {
+				hooks = append(hooks, h)
+			}
+		}
+		if len(hooks) == 0 {
+			continue
+		}
+		name, err := util.SanitizeToolName(def.GetName())
+		if err != nil {
+			name = def.GetName()
+		}
+		if transforms == nil {
+			transforms = make(map[string][]PostCallHook)
+		}
+		transforms[name] = append(transforms[name], hooks...)
+	}
+	return transforms
+}
+
+// serviceToolDefinitions returns the tools declared in the configuration of
+// a service.
+func serviceToolDefinitions(serviceConfig *configv1.UpstreamServiceConfig) []*configv1.ToolDefinition {
+	switch {
+	case serviceConfig.HasHttpService():
+		return serviceConfig.GetHttpService().GetTools()
+	case serviceConfig.HasGrpcService():
+		return serviceConfig.GetGrpcService().GetTools()
+	case serviceConfig.HasOpenapiService():
+		return serviceConfig.GetOpenapiService().GetTools()
+	case serviceConfig.HasCommandLineService():
+		return serviceConfig.GetCommandLineService().GetTools()
+	case serviceConfig.HasMcpService():
+		return serviceConfig.GetMcpService().GetTools()
+	case serviceConfig.HasWebsocketService():
+		return serviceConfig.GetWebsocketService().GetTools()
+	case serviceConfig.HasWebrtcService():
+		return serviceConfig.GetWebrtcService().GetTools()
+	case serviceConfig.HasFilesystemService():
+		return serviceConfig.GetFilesystemService().GetTools()
+	case serviceConfig.HasVectorService():
+		return serviceConfig.GetVectorService().GetTools()
+	}
+	return nil
+}
+
+// newTransformHook returns the hook applying a built-in transform, or nil if
+// the transform sets none.
+func newTransformHook(cfg *configv1.ToolTransform) PostCallHook {
+	switch {
+	case cfg.HasHtmlToMarkdown():
+		return &htmlToMarkdownTransform{converter: md.NewConverter("", true, nil)}
+	case cfg.HasTruncate():
+		maxChars := int(cfg.GetTruncate().GetMaxChars())
+		if maxChars <= 0 {
+			maxChars = defaultTruncateMaxChars
+		}
+		return &truncateTransform{maxChars: maxChars}
+	case cfg.HasPaginate():
+		pageSize := int(cfg.GetPaginate().GetPageSize())
+		if pageSize <= 0 {
+			pageSize = defaultPaginatePageSize
+		}
+		pageArgument := cfg.GetPaginate().GetPageArgument()
+		if pageArgument == "" {
+			pageArgument = defaultPageArgument
+		}
+		return &paginateTransform{pageSize: pageSize, pageArgument: pageArgument}
+	}
+	return nil
+}
+
+// inputTransform is a transform read from an input of its own. The input is
+// advertised in the input schema of the tool, and is not passed upstream.
+type inputTransform interface {
+	// input returns the name of the input and its schema.
+	input() (string, *structpb.Struct)
+}
+
+// addTransformInputs adds the inputs of the transforms of a tool to its
+// input schema.
+func addTransformInputs(t *v1.Tool, transforms []PostCallHook) {
+	for _, h := range transforms {
+		it, ok := h.(inputTransform)
+		if !ok {
+			continue
+		}
+		inputSchema := t.GetInputSchema()
+		if inputSchema == nil {
+			inputSchema = &structpb.Struct{Fields: map[string]*structpb.Value{
+				"type": structpb.NewStringValue("object"),
+			}}
+			t.SetInputSchema(inputSchema)
+		}
+		if inputSchema.Fields == nil {
+			inputSchema.Fields = make(map[string]*structpb.Value)
+		}
+		props := inputSchema.GetFields()["properties"].GetStructValue()
+		if props == nil {
+			props = &structpb.Struct{}
+			inputSchema.Fields["properties"] = structpb.NewStructValue(props)
+		}
+		if props.Fields == nil {
+			props.Fields = make(map[string]*structpb.Value)
+		}
+		name, schema := it.input()
+		props.Fields[name] = structpb.NewStructValue(schema)
+	}
+}
+
+// stripTransformInputs returns the request passed upstream: a copy of the
+// request without the inputs of the transforms, or the request itself if it
+// sets none of them.
+func stripTransformInputs(req *ExecutionRequest, transforms []PostCallHook) *ExecutionRequest {
+	var names []string
+	for _, h := range transforms {
+		if it, ok := h.(inputTransform); ok {
+			name, _ := it.input()
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return req
+	}
+
+	arguments := req.Arguments
+	if arguments == nil && len(req.ToolInputs) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(req.ToolInputs))
+		dec.UseNumber()
+		if err := dec.Decode(&arguments); err != nil {
+			return req
+		}
+	}
+	found := false
+	for _, name := range names {
+		if _, ok := arguments[name]; ok {
+			found = true
+		}
+	}
+	if !found {
+		return req
+	}
+
+	stripped := maps.Clone(arguments)
+	for _, name := range names {
+		delete(stripped, name)
+	}
+	inputs, err := json.Marshal(stripped)
+	if err != nil {
+		return req
+	}
+	modified := *req
+	modified.Arguments = stripped
+	modified.ToolInputs = inputs
+	return &modified
+}
+
+// htmlToMarkdownTransform converts the HTML strings of a result to Markdown.
+type htmlToMarkdownTransform struct {
+	converter *md.Converter
+}
+
+// ExecutePost converts the HTML strings of the result to Markdown.
+//
+// Summary: Converts the HTML of a tool result to Markdown.
+//
+// Parameters:
+//   - _ (context.Context): Unused.
+//   - _ (*ExecutionRequest): Unused.
+//   - result (any): The result of the tool.
+//
+// Returns:
+//   - any: The converted result.
+//   - error: Always nil.
+func (h *htmlToMarkdownTransform) ExecutePost(_ context.Context, _ *ExecutionRequest, result any) (any, error) {
+	return mapStrings(result, func(s string) string {
+		if !strings.Contains(s, "<") || len(s) > maxMarkdownInput {
+			return s
+		}
+		converted, err := h.converter.ConvertString(s)
+		if err != nil {
+			return s
+		}
+		return converted
+	}), nil
+}
+
+// truncateTransform truncates the long strings of a result.
+type truncateTransform struct {
+	maxChars int
+}
+
+// ExecutePost truncates the strings of the result longer than the limit.
+//
+// Summary: Truncates the long strings of a tool result.
+//
+// Parameters:
+//   - _ (context.Context): Unused.
+//   - _ (*ExecutionRequest): Unused.
+//   - result (any): The result of the tool.
+//
+// Returns:
+//   - any: The truncated result.
+//   - error: Always nil.
+func (h *truncateTransform) ExecutePost(_ context.Context, _ *ExecutionRequest, result any) (any, error) {
+	return mapStrings(result, func(s string) string {
+		if len(s) <= h.maxChars {
+			return s
+		}
+		runes := []rune(s)
+		if len(runes) <= h.maxChars {
+			return s
+		}
+		return string(runes[:h.maxChars]) + "..."
+	}), nil
+}
+
+// paginateTransform returns one page of the long strings of a result.
+type paginateTransform struct {
+	pageSize     int
+	pageArgument string
+}
+
+// ExecutePost replaces the strings of the result longer than a page with
+// the page requested by the call.
+//
+// Summary: Paginates the long strings of a tool result.
+//
+// Parameters:
+//   - _ (context.Context): Unused.
+//   - req (*ExecutionRequest): The call, holding the requested page.
+//   - result (any): The result of the tool.
+//
+// Returns:
+//   - any: The paginated result.
+//   - error: Always nil.
+func (h *paginateTransform) ExecutePost(_ context.Context, req *ExecutionRequest, result any) (any, error) {
+	page := h.requestedPage(req)
+	return mapStrings(result, func(s string) string {
+		if len(s) <= h.pageSize {
+			return s
+		}
+		runes := []rune(s)
+		total := len(runes)
+		if total <= h.pageSize {
+			return s
+		}
+		pages := (total + h.pageSize - 1) / h.pageSize
+		if page > pages {
+			return fmt.Sprintf("Page %d (empty). Total length: %d", page, total)
+		}
+		start := (page - 1) * h.pageSize
+		end := min(start+h.pageSize, total)
+		return fmt.Sprintf("Page %d/%d:\n%s\n(Total: %d chars)", page, pages, string(runes[start:end]), total)
+	}), nil
+}
+
+// input returns the page argument, read by the transform.
+func (h *paginateTransform) input() (string, *structpb.Struct) {
+	return h.pageArgument, &structpb.Struct{Fields: map[string]*structpb.Value{
+		"type":        structpb.NewStringValue("integer"),
+		"minimum":     structpb.NewNumberValue(1),
+		"description": structpb.NewStringValue(fmt.Sprintf("The page of the long texts of the result to return, of %d characters each. Defaults to 1.", h.pageSize)),
+	}}
+}
+
+// requestedPage reads the page number from the arguments of the call. It
+// returns 1 if the call does not set a valid one.
+func (h *paginateTransform) requestedPage(req *ExecutionRequest) int {
+	arguments := req.Arguments
+	if arguments == nil && len(req.ToolInputs) > 0 {
+		_ = json.Unmarshal(req.ToolInputs, &arguments)
+	}
+	var page int
+	switch v := arguments[h.pageArgument].(type) {
+	case float64:
+		page = int(v)
+	case int:
+		page = v
+	case int64:
+		page = int(v)
+	case json.Number:
+		n, _ := v.Int64()
+		page = int(n)
+	case string:
+		page, _ = strconv.Atoi(v)
+	}
+	if page < 1 {
+		return 1
+	}
+	return page
+}
+
+// mapStrings returns a copy of a result with its strings rewritten by fn,
+// including the text content of an MCP result. The values of other types
+// are returned as they are.
+func mapStrings(result any, fn func(string) string) any {
+	switch v := result.(type) {
+	case string:
+		return fn(v)
+	case map[string]any:
+		mapped := make(map[string]any, len(v))
+		for k, val := range v {
+			mapped[k] = mapStrings(val, fn)
+		}
+		return mapped
+	case []any:
+		mapped := make([]any, len(v))
+		for i, val := range v {
+			mapped[i] = mapStrings(val, fn)
+		}
+		return mapped
+	case *mcp.CallToolResult:
+		if v == nil {
+			return v
+		}
+		mapped := *v
+		mapped.Content = make([]mcp.Content, len(v.Content))
+		for i, c := range v.Content {
+			if text, ok := c.(*mcp.TextContent); ok {
+				t := *text
+				t.Text = fn(text.Text)
+				c = &t
+			}
+			mapped.Content[i] = c
+		}
+		return &mapped
+	}
+	return result
+}
@@ -0,0 +1,169 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	v1 "github.com/mcpany/core/proto/mcp_router/v1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestTransforms_HTMLToMarkdown(t *testing.T) {
+	h := newTransformHook(configv1.ToolTransform_builder{HtmlToMarkdown: configv1.HtmlToMarkdownTransform_builder{}.Build()}.Build())
+	result := map[string]any{
+		"body":  "<h1>Title</h1><p>Some <b>bold</b> text</p>",
+		"plain": "snake_case",
+		"count": 3.0,
+	}
+	transformed, err := h.ExecutePost(context.Background(), &ExecutionRequest{}, result)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"body":  "# Title\n\nSome **bold** text",
+		"plain": "snake_case",
+		"count": 3.0,
+	}, transformed)
+	assert.Equal(t, "<h1>Title</h1><p>Some <b>bold</b> text</p>", result["body"], "the result is not modified in place")
+}
+
+func TestTransforms_Truncate(t *testing.T) {
+	h := newTransformHook(configv1.ToolTransform_builder{Truncate: configv1.TruncateTransform_builder{MaxChars: proto.Int32(5)}.Build()}.Build())
+	transformed, err := h.ExecutePost(context.Background(), &ExecutionRequest{}, []any{"héllo wörld", "short"})
+	require.NoError(t, err)
+	assert.Equal(t, []any{"héllo...", "short"}, transformed)
+
+	h = newTransformHook(configv1.ToolTransform_builder{Truncate: configv1.TruncateTransform_builder{}.Build()}.Build())
+	transformed, err = h.ExecutePost(context.Background(), &ExecutionRequest{}, strings.Repeat("a", 5000))
+	require.NoError(t, err)
+	assert.Len(t, transformed, defaultTruncateMaxChars+3)
+}
+
+func TestTransforms_Paginate(t *testing.T) {
+	h := newTransformHook(configv1.ToolTransform_builder{Paginate: configv1.PaginateTransform_builder{PageSize: proto.Int32(4)}.Build()}.Build())
+	result := &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "abcdefghij"}, &mcp.TextContent{Text: "abc"}}}
+
+	tests := []struct {
+		name      string
+		arguments map[string]any
+		want      string
+	}{
+		{name: "first page by default", want: "Page 1/3:\nabcd\n(Total: 10 chars)"},
+		{name: "requested page", arguments: map[string]any{"page": 2.0}, want: "Page 2/3:\nefgh\n(Total: 10 chars)"},
+		{name: "last page", arguments: map[string]any{"page": "3"}, want: "Page 3/3:\nij\n(Total: 10 chars)"},
+		{name: "past the end", arguments: map[string]any{"page": 4}, want: "Page 4 (empty). Total length: 10"},
+		{name: "invalid page", arguments: map[string]any{"page": -1.0}, want: "Page 1/3:\nabcd\n(Total: 10 chars)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformed, err := h.ExecutePost(context.Background(), &ExecutionRequest{Arguments: tt.arguments}, result)
+			require.NoError(t, err)
+			ctr, ok := transformed.(*mcp.CallToolResult)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, ctr.Content[0].(*mcp.TextContent).Text)
+			assert.Equal(t, "abc", ctr.Content[1].(*mcp.TextContent).Text, "a string shorter than a page is kept")
+		})
+	}
+	assert.Equal(t, "abcdefghij", result.Content[0].(*mcp.TextContent).Text, "the result is not modified in place")
+
+	h = newTransformHook(configv1.ToolTransform_builder{Paginate: configv1.PaginateTransform_builder{PageSize: proto.Int32(4), PageArgument: proto.String("offset_page")}.Build()}.Build())
+	transformed, err := h.ExecutePost(context.Background(), &ExecutionRequest{ToolInputs: []byte(`{"offset_page": 2}`)}, "abcdefghij")
+	require.NoError(t, err)
+	assert.Equal(t, "Page 2/3:\nefgh\n(Total: 10 chars)", transformed)
+}
+
+func TestToolManager_ExecuteTool_Transforms(t *testing.T) {
+	t.Parallel()
+	tm := NewManager(nil)
+	for _, name := range []string{"fetch", "other"} {
+		require.NoError(t, tm.AddTool(&MockTool{
+			ToolFunc: func() *v1.Tool {
+				return v1.Tool_builder{ServiceId: proto.String("web"), Name: proto.String(name)}.Build()
+			},
+			ExecuteFunc: func(_ context.Context, req *ExecutionRequest) (any, error) {
+				if req.Arguments["fail"] == true {
+					return "<p>partial</p>", errors.New("upstream failed")
+				}
+				return map[string]any{"page": "<p>" + strings.Repeat("x", 20) + "</p>"}, nil
+			},
+		}))
+	}
+	tm.AddServiceInfo("web", &ServiceInfo{
+		Name: "web",
+		Config: configv1.UpstreamServiceConfig_builder{
+			Name: proto.String("web"),
+			HttpService: configv1.HttpUpstreamService_builder{
+				Address: proto.String("http://example.com"),
+				Tools: []*configv1.ToolDefinition{
+					configv1.ToolDefinition_builder{
+						Name: proto.String("fetch"),
+						Transforms: []*configv1.ToolTransform{
+							configv1.ToolTransform_builder{HtmlToMarkdown: configv1.HtmlToMarkdownTransform_builder{}.Build()}.Build(),
+							configv1.ToolTransform_builder{Truncate: configv1.TruncateTransform_builder{MaxChars: proto.Int32(10)}.Build()}.Build(),
+						},
+					}.Build(),
+				},
+			}.Build(),
+		}.Build(),
+	})
+
+	result, err := tm.ExecuteTool(context.Background(), &ExecutionRequest{ToolName: "web.fetch", ToolInputs: []byte(`{}`)})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"page": "xxxxxxxxxx..."}, result)
+
+	result, err = tm.ExecuteTool(context.Background(), &ExecutionRequest{ToolName: "web.other", ToolInputs: []byte(`{}`)})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"page": "<p>" + strings.Repeat("x", 20) + "</p>"}, result, "the transforms only apply to their tool")
+
+	result, err = tm.ExecuteTool(context.Background(), &ExecutionRequest{ToolName: "web.fetch", Arguments: map[string]any{"fail": true}})
+	require.Error(t, err)
+	assert.Equal(t, "<p>partial</p>", result, "a failed call is not transformed")
+}
+
+func TestToolManager_ExecuteTool_PaginateInput(t *testing.T) {
+	t.Parallel()
+	tm := NewManager(nil)
+	tm.AddServiceInfo("web", &ServiceInfo{
+		Name: "web",
+		Config: configv1.UpstreamServiceConfig_builder{
+			Name: proto.String("web"),
+			HttpService: configv1.HttpUpstreamService_builder{
+				Address: proto.String("http://example.com"),
+				Tools: []*configv1.ToolDefinition{
+					configv1.ToolDefinition_builder{
+						Name: proto.String("fetch"),
+						Transforms: []*configv1.ToolTransform{
+							configv1.ToolTransform_builder{Paginate: configv1.PaginateTransform_builder{PageSize: proto.Int32(4)}.Build()}.Build(),
+						},
+					}.Build(),
+				},
+			}.Build(),
+		}.Build(),
+	})
+	fetch := v1.Tool_builder{ServiceId: proto.String("web"), Name: proto.String("fetch")}.Build()
+	var upstream *ExecutionRequest
+	require.NoError(t, tm.AddTool(&MockTool{
+		ToolFunc: func() *v1.Tool { return fetch },
+		ExecuteFunc: func(_ context.Context, req *ExecutionRequest) (any, error) {
+			upstream = req
+			return "abcdefghij", nil
+		},
+	}))
+
+	page := fetch.GetInputSchema().GetFields()["properties"].GetStructValue().GetFields()["page"].GetStructValue()
+	require.NotNil(t, page, "the page argument is advertised")
+	assert.Equal(t, "integer", page.GetFields()["type"].GetStringValue())
+
+	result, err := tm.ExecuteTool(context.Background(), &ExecutionRequest{ToolName: "web.fetch", ToolInputs: []byte(`{"url":"u","page":2}`)})
+	require.NoError(t, err)
+	assert.Equal(t, "Page 2/3:\nefgh\n(Total: 10 chars)", result)
+	assert.Equal(t, map[string]any{"url": "u"}, upstream.Arguments, "the page argument is not passed upstream")
+	assert.JSONEq(t, `{"url":"u"}`, string(upstream.ToolInputs))
+}
@@ -47,6 +47,8 @@ const (
 	contentTypeJSON     = "application/json"
 	redactedPlaceholder = "[REDACTED]"
 
+	// HealthStatusHealthy indicates that a service passes its health checks.
+	HealthStatusHealthy = "healthy"
 	// HealthStatusUnhealthy indicates that a service is in an unhealthy state.
 	HealthStatusUnhealthy = "unhealthy"
 
@@ -138,10 +140,24 @@ type ServiceInfo struct {
 	PreHooks []PreCallHook
 	// PostHooks are the cached post-call hooks for the service.
 	PostHooks []PostCallHook
+	// ToolTransforms are the cached built-in transforms of the tools of the
+	// service, keyed by the tool name without the service prefix.
+	ToolTransforms map[string][]PostCallHook
 
 	// CompiledPolicies are the pre-compiled call policies for the service.
 	CompiledPolicies []*CompiledCallPolicy
 
+	// DeprecatedNames are the old names of renamed tools, keyed by the old
+	// tool name without the service prefix.
+	DeprecatedNames map[string]*DeprecatedToolName
+
+	// AsyncTools are the tools of the service that run as jobs, keyed by the
+	// tool name without the service prefix.
+	AsyncTools map[string]bool
+	// PriorityClasses are the worker priority classes set by the tools of
+	// the service, keyed by the tool name without the service prefix.
+	PriorityClasses map[string]string
+
 	// HealthStatus indicates the health of the service ("healthy", "unhealthy", "unknown").
 	HealthStatus string
 }
@@ -377,7 +393,7 @@ func NewGRPCTool(tool *v1.Tool, poolManager *pool.Manager, serviceID string, met
 		method:            method,
 		requestMessage:    dynamicpb.NewMessage(method.Input()),
 		cache:             callDefinition.GetCache(),
-		resilienceManager: resilience.NewManager(resilienceConfig),
+		resilienceManager: resilience.NewManager(resilienceConfig, resilience.WithName(serviceID)),
 	}
 }
 
@@ -444,7 +460,9 @@ func (t *GRPCTool) Execute(ctx context.Context, req *ExecutionRequest) (any, err
 		return nil, fmt.Errorf("no grpc pool found for service: %s", t.serviceID)
 	}
 
+	queueStart := time.Now()
 	grpcClient, err := grpcPool.Get(ctx)
+	RecordCallPhase(ctx, PhaseQueue, queueStart)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get client from pool: %w", err)
 	}
@@ -482,11 +500,15 @@ func (t *GRPCTool) Execute(ctx context.Context, req *ExecutionRequest) (any, err
 		return grpcClient.Invoke(ctx, grpcMethodName, t.requestMessage, responseMessage)
 	}
 
-	if err := t.resilienceManager.Execute(ctx, work); err != nil {
+	upstreamStart := time.Now()
+	err = t.resilienceManager.Execute(ctx, work)
+	RecordCallPhase(ctx, PhaseUpstream, upstreamStart)
+	if err != nil {
 		metrics.IncrCounter(metricGrpcRequestError, 1)
 		return nil, fmt.Errorf("failed to invoke grpc method: %w", err)
 	}
 	metrics.IncrCounter(metricGrpcRequestSuccess, 1)
+	defer RecordCallPhase(ctx, PhaseTransform, time.Now())
 
 	responseJSON, err := protojson.Marshal(responseMessage)
 	if err != nil {
@@ -525,6 +547,8 @@ type HTTPTool struct {
 	callID            string
 	allowedParams     map[string]bool
 	secretParams      map[string]bool
+	paginator         *paginator
+	content           *contentHandler
 
 	// Cached fields for performance
 	initError            error
@@ -569,10 +593,11 @@ func NewHTTPTool(tool *v1.Tool, poolManager *pool.Manager, serviceID string, aut
 		outputTransformer: callDefinition.GetOutputTransformer(),
 		webhookClient:     webhookClient,
 		cache:             callDefinition.GetCache(),
-		resilienceManager: resilience.NewManager(cfg),
+		resilienceManager: resilience.NewManager(cfg, resilience.WithName(serviceID)),
 		callID:            callID,
 		allowedParams:     make(map[string]bool, len(callDefinition.GetParameters())),
 		secretParams:      make(map[string]bool),
+		paginator:         newPaginator(callDefinition.GetPagination()),
 	}
 
 	for _, param := range callDefinition.GetParameters() {
@@ -587,6 +612,10 @@ func NewHTTPTool(tool *v1.Tool, poolManager *pool.Manager, serviceID string, aut
 	}
 	t.policies = compiled
 
+	if t.content, err = newContentHandler(callDefinition.GetResponseContent()); err != nil {
+		t.initError = fmt.Errorf("invalid response_content: %w", err)
+	}
+
 	// Cache templates
 	if it := t.inputTransformer; it != nil && it.GetTemplate() != "" { //nolint:staticcheck
 		tpl, err := transformer.NewTemplate(it.GetTemplate(), "{{", "}}") //nolint:staticcheck
@@ -728,22 +757,43 @@ func (t *HTTPTool) Execute(ctx context.Context, req *ExecutionRequest) (any, err
 		return nil, fmt.Errorf("no http pool found for service: %s", t.serviceID)
 	}
 
+	queueStart := time.Now()
 	httpClient, err := httpPool.Get(ctx)
+	RecordCallPhase(ctx, PhaseQueue, queueStart)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get client from pool: %w", err)
 	}
 	defer httpPool.Put(httpClient)
 
+	transformStart := time.Now()
 	inputs, urlString, redactedURLString, inputsModified, err := t.prepareInputsAndURL(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
+	var pos pagePosition
+	if t.paginator != nil {
+		var pageReq struct {
+			Cursor string `json:"cursor"`
+		}
+		if len(req.ToolInputs) > 0 {
+			if err := fastJSON.Unmarshal(req.ToolInputs, &pageReq); err != nil {
+				return nil, fmt.Errorf("invalid cursor: %w", err)
+			}
+		}
+		if pos, err = t.paginator.position(pageReq.Cursor); err != nil {
+			return nil, err
+		}
+		urlString = t.paginator.applyToURL(urlString, pos)
+		redactedURLString = t.paginator.applyToURL(redactedURLString, pos)
+	}
+
 	if err := validation.IsSafeURL(urlString); err != nil {
 		return nil, fmt.Errorf("unsafe url: %w", err)
 	}
 
 	body, contentType, err := t.prepareBody(ctx, inputs, t.cachedMethod, req.ToolName, req.ToolInputs, inputsModified)
+	RecordCallPhase(ctx, PhaseTransform, transformStart)
 	if err != nil {
 		return nil, err
 	}
@@ -845,7 +895,10 @@ func (t *HTTPTool) Execute(ctx context.Context, req *ExecutionRequest) (any, err
 		return nil
 	}
 
-	if err := t.resilienceManager.Execute(ctx, work); err != nil {
+	upstreamStart := time.Now()
+	err = t.resilienceManager.Execute(ctx, work)
+	RecordCallPhase(ctx, PhaseUpstream, upstreamStart)
+	if err != nil {
 		metrics.IncrCounter(metricHTTPRequestError, 1)
 		return nil, err
 	}
@@ -853,7 +906,11 @@ func (t *HTTPTool) Execute(ctx context.Context, req *ExecutionRequest) (any, err
 	defer func() { _ = resp.Body.Close() }()
 	metrics.IncrCounter(metricHTTPRequestSuccess, 1)
 
-	return t.processResponse(ctx, resp)
+	result, respBody, err := t.processResponse(ctx, resp, redactedURLString)
+	if err != nil || t.paginator == nil {
+		return result, err
+	}
+	return t.paginator.wrap(result, t.paginator.nextCursor(pos, resp.Header, respBody)), nil
 }
 
 func (t *HTTPTool) createHTTPRequest(ctx context.Context, urlString string, body io.Reader, contentType string, inputs map[string]interface{}) (*http.Request, error) {
@@ -865,7 +922,7 @@ func (t *HTTPTool) createHTTPRequest(ctx context.Context, urlString string, body
 	if contentType != "" {
 		httpReq.Header.Set("Content-Type", contentType)
 	}
-	httpReq.Header.Set("Accept", "*/*")
+	httpReq.Header.Set("Accept", t.content.accept())
 	httpReq.Header.Set("User-Agent", "MCPAny/1.0 (https://github.com/mcpany/core; contact@mcpany.org)")
 
 	if t.authenticator != nil {
@@ -1227,17 +1284,20 @@ func (t *HTTPTool) prepareBody(ctx context.Context, inputs map[string]any, metho
 	return body, contentType, nil
 }
 
-func (t *HTTPTool) processResponse(ctx context.Context, resp *http.Response) (any, error) {
+func (t *HTTPTool) processResponse(ctx context.Context, resp *http.Response, redactedURL string) (any, []byte, error) {
 	maxSize := getMaxHTTPResponseSize()
 	// Read up to maxSize + 1 to detect if it exceeds the limit
 	reader := io.LimitReader(resp.Body, maxSize+1)
+	readStart := time.Now()
 	respBody, err := io.ReadAll(reader)
+	RecordCallPhase(ctx, PhaseUpstream, readStart)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read http response body: %w", err)
+		return nil, nil, fmt.Errorf("failed to read http response body: %w", err)
 	}
 	if int64(len(respBody)) > maxSize {
-		return nil, fmt.Errorf("response body exceeds maximum size of %d bytes", maxSize)
+		return nil, nil, fmt.Errorf("response body exceeds maximum size of %d bytes", maxSize)
 	}
+	defer RecordCallPhase(ctx, PhaseTransform, time.Now())
 
 	if logging.GetLogger().Enabled(ctx, slog.LevelDebug) {
 		// Log headers
@@ -1259,40 +1319,46 @@ func (t *HTTPTool) processResponse(ctx context.Context, resp *http.Response) (an
 
 	if t.outputTransformer != nil {
 		if t.outputTransformer.GetFormat() == configv1.OutputTransformer_RAW_BYTES {
-			return map[string]any{"raw": respBody}, nil
+			return map[string]any{"raw": respBody}, respBody, nil
 		}
 
-		parser := transformer.NewTextParser()
-		outputFormat := configv1.OutputTransformer_OutputFormat_name[int32(t.outputTransformer.GetFormat())]
-		parsedResult, err := parser.Parse(outputFormat, respBody, t.outputTransformer.GetExtractionRules(), t.outputTransformer.GetJqQuery())
+		parsedResult, err := parseOutput(t.outputTransformer, respBody)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse output: %w", err)
+			return nil, nil, fmt.Errorf("failed to parse output: %w", err)
 		}
 
 		if t.outputTransformer.GetTemplate() != "" {
 			if t.cachedOutputTemplate == nil {
-				return nil, fmt.Errorf("output template configured but not cached (initialization error?)")
+				return nil, nil, fmt.Errorf("output template configured but not cached (initialization error?)")
 			}
 			resultMap, ok := parsedResult.(map[string]any)
 			if !ok {
-				return nil, fmt.Errorf("output must be a map to be used with a template, got %T", parsedResult)
+				return nil, nil, fmt.Errorf("output must be a map to be used with a template, got %T", parsedResult)
 			}
 			renderedOutput, err := t.cachedOutputTemplate.Render(resultMap)
 			if err != nil {
-				return nil, fmt.Errorf("failed to render output template: %w", err)
+				return nil, nil, fmt.Errorf("failed to render output template: %w", err)
 			}
-			return map[string]any{"result": renderedOutput}, nil
+			return map[string]any{"result": renderedOutput}, respBody, nil
 		}
-		return parsedResult, nil
+		return parsedResult, respBody, nil
+	}
+
+	if t.content != nil {
+		result, err := t.content.handle(respBody, resp.Header, redactedURL)
+		if err != nil {
+			return nil, nil, err
+		}
+		return result, respBody, nil
 	}
 
 	// ⚡ Bolt: Use json-iterator
 	var result any
 	if err := fastJSON.Unmarshal(respBody, &result); err != nil {
-		return string(respBody), nil //nolint:nilerr
+		return string(respBody), respBody, nil //nolint:nilerr
 	}
 
-	return result, nil
+	return result, respBody, nil
 }
 
 // MCPTool implements the Tool interface for a tool that is exposed via another
@@ -1492,9 +1558,7 @@ func (t *MCPTool) Execute(ctx context.Context, req *ExecutionRequest) (any, erro
 		if t.outputTransformer.GetFormat() == configv1.OutputTransformer_RAW_BYTES {
 			return map[string]any{"raw": responseBytes}, nil
 		}
-		parser := transformer.NewTextParser()
-		outputFormat := configv1.OutputTransformer_OutputFormat_name[int32(t.outputTransformer.GetFormat())]
-		parsedResult, err := parser.Parse(outputFormat, responseBytes, t.outputTransformer.GetExtractionRules(), t.outputTransformer.GetJqQuery())
+		parsedResult, err := parseOutput(t.outputTransformer, responseBytes)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse output: %w", err)
 		}
@@ -1780,9 +1844,7 @@ func (t *OpenAPITool) Execute(ctx context.Context, req *ExecutionRequest) (any,
 		if t.outputTransformer.GetFormat() == configv1.OutputTransformer_RAW_BYTES {
 			return map[string]any{"raw": respBody}, nil
 		}
-		parser := transformer.NewTextParser()
-		outputFormat := configv1.OutputTransformer_OutputFormat_name[int32(t.outputTransformer.GetFormat())]
-		parsedResult, err := parser.Parse(outputFormat, respBody, t.outputTransformer.GetExtractionRules(), t.outputTransformer.GetJqQuery())
+		parsedResult, err := parseOutput(t.outputTransformer, respBody)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse output: %w", err)
 		}
@@ -0,0 +1,101 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+)
+
+// DeadLetterTimeFormat is the format of the times of the dead letters. It is
+// RFC 3339 with a fixed number of fractional digits, so that the times sort
+// as strings.
+const DeadLetterTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// WebhookDeadLetterHandler receives the events of the post-call webhooks that
+// failed after their retries.
+//
+// Parameters:
+//   - ctx: The context of the failed call.
+//   - letter: The dead letter of the event.
+type WebhookDeadLetterHandler func(ctx context.Context, letter *configv1.WebhookDeadLetter)
+
+var webhookDeadLetterHandler atomic.Pointer[WebhookDeadLetterHandler]
+
+// SetWebhookDeadLetterHandler installs the handler of the failed post-call
+// webhook events. Passing nil removes it, and the failed events are only
+// logged.
+//
+// Parameters:
+//   - handler: The handler, or nil.
+//
+// Side Effects:
+//   - Replaces the process-wide handler.
+func SetWebhookDeadLetterHandler(handler WebhookDeadLetterHandler) {
+	if handler == nil {
+		webhookDeadLetterHandler.Store(nil)
+		return
+	}
+	webhookDeadLetterHandler.Store(&handler)
+}
+
+// deadLetter hands the event of a failed post-call webhook to the dead-letter
+// handler.
+func (h *WebhookHook) deadLetter(ctx context.Context, req *ExecutionRequest, eventType string, data any, attempts int, callErr error) {
+	handler := webhookDeadLetterHandler.Load()
+	if handler == nil {
+		return
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		logging.GetLogger().Error("Failed to dead-letter webhook event", "url", h.client.url, "tool", req.ToolName, "error", err)
+		return
+	}
+	letter := configv1.WebhookDeadLetter_builder{
+		Id:        uuid.New().String(),
+		ServiceId: h.serviceID,
+		HookName:  h.name,
+		ToolName:  req.ToolName,
+		EventType: eventType,
+		Url:       h.client.url,
+		Payload:   string(payload),
+		Error:     callErr.Error(),
+		Attempts:  int32(attempts), //nolint:gosec // bounded by max_retries
+		FailedAt:  time.Now().UTC().Format(DeadLetterTimeFormat),
+	}.Build()
+	logging.GetLogger().Warn("Webhook event dead-lettered", "id", letter.GetId(), "url", h.client.url, "tool", req.ToolName)
+	(*handler)(ctx, letter)
+}
+
+// ReplayWebhookDeadLetter sends a dead-lettered event to the webhook again.
+// The response of the webhook is not applied, since the call it was made for
+// is over.
+//
+// Summary: Replays a webhook dead letter.
+//
+// Parameters:
+//   - ctx: context.Context. The request context.
+//   - config: *configv1.WebhookConfig. The current configuration of the webhook.
+//   - letter: *configv1.WebhookDeadLetter. The dead letter to replay.
+//
+// Returns:
+//   - int: The number of attempts made.
+//   - error: An error if the webhook call fails.
+//
+// Side Effects:
+//   - Invokes external webhook.
+func ReplayWebhookDeadLetter(ctx context.Context, config *configv1.WebhookConfig, letter *configv1.WebhookDeadLetter) (int, error) {
+	if !json.Valid([]byte(letter.GetPayload())) {
+		return 0, fmt.Errorf("dead letter %s has an invalid payload", letter.GetId())
+	}
+	_, attempts, err := NewWebhookClient(config).call(ctx, letter.GetEventType(), json.RawMessage(letter.GetPayload()))
+	return attempts, err
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookHook_DeadLetter(t *testing.T) {
+	var letters []*configv1.WebhookDeadLetter
+	SetWebhookDeadLetterHandler(func(_ context.Context, letter *configv1.WebhookDeadLetter) {
+		letters = append(letters, letter)
+	})
+	t.Cleanup(func() { SetWebhookDeadLetterHandler(nil) })
+
+	server, calls := newFlakyWebhook(t, 1, true, 0)
+	config := configv1.WebhookConfig_builder{
+		Url:           server.URL,
+		FailurePolicy: configv1.WebhookConfig_FAIL_OPEN,
+	}.Build()
+	hook := NewWebhookHook(config)
+	hook.serviceID, hook.name = "weather", "audit"
+
+	result, err := hook.ExecutePost(context.Background(), &ExecutionRequest{ToolName: "weather.forecast"}, map[string]any{"temp": 21})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"temp": 21}, result, "the call goes on with the original result")
+	require.Len(t, letters, 1)
+	letter := letters[0]
+	assert.NotEmpty(t, letter.GetId())
+	assert.Equal(t, "weather", letter.GetServiceId())
+	assert.Equal(t, "audit", letter.GetHookName())
+	assert.Equal(t, "weather.forecast", letter.GetToolName())
+	assert.Equal(t, "com.mcpany.tool.post_call", letter.GetEventType())
+	assert.Equal(t, int32(1), letter.GetAttempts())
+	assert.NotEmpty(t, letter.GetError())
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal([]byte(letter.GetPayload()), &payload))
+	assert.Equal(t, map[string]any{"temp": float64(21)}, payload["result"])
+
+	attempts, err := ReplayWebhookDeadLetter(context.Background(), config, letter)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, int32(2), calls.Load())
+
+	_, _, err = hook.ExecutePre(context.Background(), &ExecutionRequest{ToolName: "weather.forecast"})
+	require.NoError(t, err)
+	assert.Len(t, letters, 1, "only the post-call events are dead-lettered")
+
+	letter.SetPayload("{")
+	_, err = ReplayWebhookDeadLetter(context.Background(), config, letter)
+	assert.ErrorContains(t, err, "invalid payload")
+}
@@ -179,9 +179,7 @@ func (t *WebsocketTool) Execute(ctx context.Context, req *ExecutionRequest) (any
 	}
 
 	if t.outputTransformer != nil {
-		parser := transformer.NewTextParser()
-		outputFormat := configv1.OutputTransformer_OutputFormat_name[int32(t.outputTransformer.GetFormat())]
-		return parser.Parse(outputFormat, response, t.outputTransformer.GetExtractionRules(), t.outputTransformer.GetJqQuery())
+		return parseOutput(t.outputTransformer, response)
 	}
 
 	var result map[string]any
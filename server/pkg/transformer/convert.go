@@ -0,0 +1,305 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/antchfx/xmlquery"
+)
+
+// XMLOptions configures XMLToJSON. The zero value gives the default
+// conversion.
+type XMLOptions struct {
+	// AttributePrefix prefixes the keys holding attributes. It is "@" unless
+	// NoAttributePrefix is set.
+	AttributePrefix string
+	// NoAttributePrefix keys attributes by their bare name.
+	NoAttributePrefix bool
+	// IgnoreAttributes drops attributes.
+	IgnoreAttributes bool
+	// TextKey is the key holding the text of an element that also has
+	// attributes or child elements. It defaults to "#text".
+	TextKey string
+	// ForceList names the elements that always become lists.
+	ForceList []string
+	// CoerceTypes turns numeric and boolean text into numbers and booleans,
+	// and empty text into nil.
+	CoerceTypes bool
+}
+
+// XMLToJSON converts an XML document to a JSON compatible value.
+//
+// The document becomes an object keyed by its root element. An element with
+// only text becomes a string. Otherwise it becomes an object holding its
+// attributes as "@name", its child elements by name (a list when repeated)
+// and its text as "#text".
+//
+// Summary: Converts XML to a JSON value.
+//
+// Parameters:
+//   - input: []byte. The XML document.
+//   - opts: XMLOptions. How attributes, text, lists and types are converted.
+//
+// Returns:
+//   - map[string]any: The converted document.
+//   - error: An error if the document cannot be parsed or has no root element.
+func XMLToJSON(input []byte, opts XMLOptions) (map[string]any, error) {
+	doc, err := xmlquery.Parse(bytes.NewReader(input))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse XML: %w", err)
+	}
+	c := newXMLConverter(opts)
+	for n := doc.FirstChild; n != nil; n = n.NextSibling {
+		if n.Type == xmlquery.ElementNode {
+			name := xmlName(n.Prefix, n.Data)
+			return map[string]any{name: c.wrap(name, c.elementValue(n))}, nil
+		}
+	}
+	return nil, errors.New("failed to parse XML: no root element")
+}
+
+type xmlConverter struct {
+	opts       XMLOptions
+	attrPrefix string
+	textKey    string
+	forceList  map[string]bool
+}
+
+func newXMLConverter(opts XMLOptions) *xmlConverter {
+	c := &xmlConverter{opts: opts, attrPrefix: opts.AttributePrefix, textKey: opts.TextKey}
+	if c.attrPrefix == "" && !opts.NoAttributePrefix {
+		c.attrPrefix = "@"
+	}
+	if c.textKey == "" {
+		c.textKey = "#text"
+	}
+	if len(opts.ForceList) > 0 {
+		c.forceList = make(map[string]bool, len(opts.ForceList))
+		for _, name := range opts.ForceList {
+			c.forceList[name] = true
+		}
+	}
+	return c
+}
+
+func xmlName(prefix, local string) string {
+	if prefix == "" {
+		return local
+	}
+	return prefix + ":" + local
+}
+
+// wrap returns the value of an element as a list if its name is forced to
+// be one.
+func (c *xmlConverter) wrap(name string, value any) any {
+	if c.forceList[name] {
+		return []any{value}
+	}
+	return value
+}
+
+func (c *xmlConverter) elementValue(n *xmlquery.Node) any {
+	obj := make(map[string]any)
+	if !c.opts.IgnoreAttributes {
+		for _, attr := range n.Attr {
+			obj[c.attrPrefix+xmlName(attr.Name.Space, attr.Name.Local)] = c.scalar(attr.Value)
+		}
+	}
+	var text strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		switch child.Type {
+		case xmlquery.ElementNode:
+			name := xmlName(child.Prefix, child.Data)
+			value := c.elementValue(child)
+			switch existing := obj[name].(type) {
+			case nil:
+				obj[name] = c.wrap(name, value)
+			case []any:
+				obj[name] = append(existing, value)
+			default:
+				obj[name] = []any{existing, value}
+			}
+		case xmlquery.TextNode, xmlquery.CharDataNode:
+			text.WriteString(child.Data)
+		}
+	}
+	trimmed := strings.TrimSpace(text.String())
+	if len(obj) == 0 {
+		return c.scalar(trimmed)
+	}
+	if trimmed != "" {
+		obj[c.textKey] = c.scalar(trimmed)
+	}
+	return obj
+}
+
+func (c *xmlConverter) scalar(s string) any {
+	if c.opts.CoerceTypes {
+		return CoerceScalar(s)
+	}
+	return s
+}
+
+// CSVHeader says whether the first CSV row names the columns.
+type CSVHeader int
+
+const (
+	// CSVHeaderAuto infers the header: the first row is one if its fields
+	// are non-empty, distinct and neither numbers nor booleans.
+	CSVHeaderAuto CSVHeader = iota
+	// CSVHeaderPresent treats the first row as the header.
+	CSVHeaderPresent
+	// CSVHeaderAbsent treats every row as data.
+	CSVHeaderAbsent
+)
+
+// CSVOptions configures CSVToRows. The zero value infers the header of
+// comma separated data.
+type CSVOptions struct {
+	// Delimiter is the field delimiter. It defaults to a comma.
+	Delimiter rune
+	// Header says whether the first row names the columns.
+	Header CSVHeader
+	// Columns names the columns, overriding the header row.
+	Columns []string
+	// CoerceTypes turns numeric and boolean fields into numbers and
+	// booleans, and empty fields into nil.
+	CoerceTypes bool
+}
+
+// CSVToRows converts CSV data to a list of rows.
+//
+// With column names, from the header row or the options, each row is an
+// object keyed by them; fields beyond the names are keyed by their 1-based
+// position and missing fields are empty. Otherwise each row is a list of its
+// fields.
+//
+// Summary: Converts CSV to a list of rows.
+//
+// Parameters:
+//   - input: []byte. The CSV data.
+//   - opts: CSVOptions. The delimiter, header handling and type coercion.
+//
+// Returns:
+//   - []any: The rows.
+//   - error: An error if the data is not valid CSV.
+func CSVToRows(input []byte, opts CSVOptions) ([]any, error) {
+	r := csv.NewReader(bytes.NewReader(input))
+	if opts.Delimiter != 0 {
+		r.Comma = opts.Delimiter
+	}
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+
+	hasHeader := false
+	if len(records) > 0 {
+		switch opts.Header {
+		case CSVHeaderPresent:
+			hasHeader = true
+		case CSVHeaderAuto:
+			hasHeader = looksLikeHeader(records[0])
+		}
+	}
+	columns := opts.Columns
+	if hasHeader {
+		if columns == nil {
+			columns = records[0]
+		}
+		records = records[1:]
+	}
+
+	field := func(s string) any {
+		if opts.CoerceTypes {
+			return CoerceScalar(s)
+		}
+		return s
+	}
+	rows := make([]any, 0, len(records))
+	for _, record := range records {
+		if columns == nil {
+			fields := make([]any, len(record))
+			for i, f := range record {
+				fields[i] = field(f)
+			}
+			rows = append(rows, fields)
+			continue
+		}
+		row := make(map[string]any, len(columns))
+		for i, f := range record {
+			if i < len(columns) {
+				row[columns[i]] = field(f)
+			} else {
+				row[strconv.Itoa(i+1)] = field(f)
+			}
+		}
+		for i := len(record); i < len(columns); i++ {
+			row[columns[i]] = field("")
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// looksLikeHeader reports whether a row's fields are non-empty, distinct
+// and not numbers or booleans.
+func looksLikeHeader(record []string) bool {
+	seen := make(map[string]bool, len(record))
+	for _, f := range record {
+		f = strings.TrimSpace(f)
+		if f == "" || seen[f] {
+			return false
+		}
+		if _, ok := CoerceScalar(f).(string); !ok {
+			return false
+		}
+		seen[f] = true
+	}
+	return true
+}
+
+var jsonNumberRegex = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// CoerceScalar converts text to the JSON value it denotes.
+//
+// Integers become int and other numbers float64, "true" and "false"
+// booleans, and empty text nil. Anything else, including numbers with
+// leading zeros such as ZIP codes, stays a string.
+//
+// Summary: Converts text to a number, boolean, nil or string.
+//
+// Parameters:
+//   - s: string. The text.
+//
+// Returns:
+//   - any: The converted value.
+func CoerceScalar(s string) any {
+	switch s {
+	case "":
+		return nil
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if !jsonNumberRegex.MatchString(s) {
+		return s
+	}
+	if i, err := strconv.Atoi(s); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	return s
+}
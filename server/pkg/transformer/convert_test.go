@@ -0,0 +1,143 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXMLToJSON(t *testing.T) {
+	input := []byte(`<?xml version="1.0"?>
+<catalog region="eu">
+  <!-- two books -->
+  <book id="1"><title>Go</title><price currency="EUR">30</price></book>
+  <book id="2"><title><![CDATA[MCP & You]]></title></book>
+  <note>in stock</note>
+</catalog>`)
+
+	got, err := XMLToJSON(input, XMLOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"catalog": map[string]any{
+			"@region": "eu",
+			"book": []any{
+				map[string]any{
+					"@id":   "1",
+					"title": "Go",
+					"price": map[string]any{"@currency": "EUR", "#text": "30"},
+				},
+				map[string]any{"@id": "2", "title": "MCP & You"},
+			},
+			"note": "in stock",
+		},
+	}, got)
+
+	_, err = XMLToJSON([]byte("<open>"), XMLOptions{})
+	assert.Error(t, err)
+	_, err = XMLToJSON([]byte("just text"), XMLOptions{})
+	assert.Error(t, err)
+}
+
+func TestCSVToRows(t *testing.T) {
+	input := []byte("name,age\nada,36\n\"lovelace, a\",37,extra\nbob\n")
+
+	rows, err := CSVToRows(input, CSVOptions{Header: CSVHeaderPresent})
+	require.NoError(t, err)
+	assert.Equal(t, []any{
+		map[string]any{"name": "ada", "age": "36"},
+		map[string]any{"name": "lovelace, a", "age": "37", "3": "extra"},
+		map[string]any{"name": "bob", "age": ""},
+	}, rows)
+
+	rows, err = CSVToRows([]byte("a;b\nc;d\n"), CSVOptions{Delimiter: ';', Header: CSVHeaderAbsent})
+	require.NoError(t, err)
+	assert.Equal(t, []any{[]any{"a", "b"}, []any{"c", "d"}}, rows)
+
+	rows, err = CSVToRows(nil, CSVOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, rows)
+
+	_, err = CSVToRows([]byte("a,\"b\nc"), CSVOptions{})
+	assert.Error(t, err)
+}
+
+func TestXMLToJSON_Options(t *testing.T) {
+	input := []byte(`<order id="7" paid="true"><item sku="a1"><qty>2</qty></item><total>19.90</total><zip>01234</zip><note/></order>`)
+
+	got, err := XMLToJSON(input, XMLOptions{
+		AttributePrefix: "_",
+		TextKey:         "value",
+		ForceList:       []string{"item"},
+		CoerceTypes:     true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"order": map[string]any{
+			"_id":   7,
+			"_paid": true,
+			"item":  []any{map[string]any{"_sku": "a1", "qty": 2}},
+			"total": 19.9,
+			"zip":   "01234",
+			"note":  nil,
+		},
+	}, got)
+
+	got, err = XMLToJSON([]byte(`<price currency="EUR">30</price>`), XMLOptions{IgnoreAttributes: true})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"price": "30"}, got)
+
+	got, err = XMLToJSON([]byte(`<price currency="EUR">30</price>`), XMLOptions{NoAttributePrefix: true, TextKey: "amount"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"price": map[string]any{"currency": "EUR", "amount": "30"}}, got)
+}
+
+func TestCSVToRows_HeaderInference(t *testing.T) {
+	rows, err := CSVToRows([]byte("id,name,active\n1,ada,true\n2,bob,\n"), CSVOptions{CoerceTypes: true})
+	require.NoError(t, err)
+	assert.Equal(t, []any{
+		map[string]any{"id": 1, "name": "ada", "active": true},
+		map[string]any{"id": 2, "name": "bob", "active": nil},
+	}, rows)
+
+	// A first row with numbers, empty or repeated fields is data.
+	for _, input := range []string{"1,ada\n2,bob\n", "id,\n1,ada\n", "a,a\n1,2\n"} {
+		rows, err = CSVToRows([]byte(input), CSVOptions{})
+		require.NoError(t, err)
+		assert.Len(t, rows, 2, input)
+		assert.IsType(t, []any{}, rows[0], input)
+	}
+
+	rows, err = CSVToRows([]byte("1,ada\n"), CSVOptions{Columns: []string{"id", "name"}, CoerceTypes: true})
+	require.NoError(t, err)
+	assert.Equal(t, []any{map[string]any{"id": 1, "name": "ada"}}, rows)
+
+	// Columns override a header row.
+	rows, err = CSVToRows([]byte("ID,NAME\n1,ada\n"), CSVOptions{Header: CSVHeaderPresent, Columns: []string{"id", "name"}})
+	require.NoError(t, err)
+	assert.Equal(t, []any{map[string]any{"id": "1", "name": "ada"}}, rows)
+}
+
+func TestCoerceScalar(t *testing.T) {
+	for input, want := range map[string]any{
+		"":          nil,
+		"true":      true,
+		"false":     false,
+		"42":        42,
+		"-7":        -7,
+		"3.5":       3.5,
+		"1e3":       1000.0,
+		"007":       "007",
+		"True":      "True",
+		"NaN":       "NaN",
+		"0x10":      "0x10",
+		"1_000":     "1_000",
+		" 1":        " 1",
+		"12 apples": "12 apples",
+	} {
+		assert.Equal(t, want, CoerceScalar(input), input)
+	}
+}
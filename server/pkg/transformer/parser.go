@@ -108,6 +108,29 @@ func (p *TextParser) Parse(inputType string, input []byte, config map[string]str
 	}
 }
 
+// Extract applies a JQ query, or else JSONPath extraction rules, to a value
+// that is already structured, such as the result of XMLToJSON or CSVToRows.
+//
+// Summary: Extracts data from a structured value with JQ or JSONPath.
+//
+// Parameters:
+//   - data: any. The JSON compatible value.
+//   - config: map[string]string. Extraction rules (key -> JSONPath). Used when jqQuery is empty.
+//   - jqQuery: string. The JQ query string.
+//
+// Returns:
+//   - any: The query result, the extracted map, or data itself when there are neither.
+//   - error: An error if the query or an extraction rule fails.
+func (p *TextParser) Extract(data any, config map[string]string, jqQuery string) (any, error) {
+	if jqQuery != "" {
+		return p.runJQ(data, jqQuery)
+	}
+	if len(config) > 0 {
+		return p.extractJSONPath(data, config)
+	}
+	return data, nil
+}
+
 // parseJSON handles the parsing of JSON data. It uses JSONPath expressions from
 // the config map to extract values from the input JSON.
 func (p *TextParser) parseJSON(input []byte, config map[string]string) (any, error) {
@@ -115,7 +138,12 @@ func (p *TextParser) parseJSON(input []byte, config map[string]string) (any, err
 	if err := json.Unmarshal(input, &data); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
 	}
+	return p.extractJSONPath(data, config)
+}
 
+// extractJSONPath extracts values from decoded JSON data using the JSONPath
+// expressions of the config map.
+func (p *TextParser) extractJSONPath(data any, config map[string]string) (any, error) {
 	result := make(map[string]any)
 	for key, path := range config {
 		var j *jsonpath.JSONPath
@@ -208,7 +236,11 @@ func (p *TextParser) parseJQ(input []byte, query string) (any, error) {
 	if err := json.Unmarshal(input, &data); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal JSON input for JQ: %w", err)
 	}
+	return p.runJQ(data, query)
+}
 
+// runJQ runs a JQ query over decoded JSON data.
+func (p *TextParser) runJQ(data any, query string) (any, error) {
 	var pq *gojq.Query
 	if val, ok := jqCache.Load(query); ok {
 		pq = val.(*gojq.Query)
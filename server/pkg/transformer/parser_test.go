@@ -248,3 +248,25 @@ func TestTextParser_ParseJQ(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestTextParser_Extract(t *testing.T) {
+	t.Parallel()
+	parser := NewTextParser()
+	rows, err := CSVToRows([]byte("name,age\nada,36\nbob,41\n"), CSVOptions{CoerceTypes: true})
+	require.NoError(t, err)
+
+	result, err := parser.Extract(rows, nil, "[.[] | select(.age > 40) | .name]")
+	require.NoError(t, err)
+	assert.Equal(t, []any{"bob"}, result)
+
+	result, err = parser.Extract(rows, map[string]string{"first": "{[0].name}"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"first": "ada"}, result)
+
+	result, err = parser.Extract(rows, nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, rows, result)
+
+	_, err = parser.Extract(rows, nil, "][")
+	assert.Error(t, err)
+}
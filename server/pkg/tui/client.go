@@ -0,0 +1,215 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tui
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/mcpany/core/server/pkg/dashboard"
+	"github.com/mcpany/core/server/pkg/logging"
+)
+
+// maxLogLine caps the size of a streamed log line.
+const maxLogLine = 1 << 20
+
+// Client reads a running server through the API of its built-in dashboard,
+// and calls its tools through the execute API.
+type Client struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+// NewClient creates a client of a running server.
+//
+// Parameters:
+//   - baseURL (string): The base URL of the HTTP listener, e.g. http://localhost:50050.
+//   - apiKey (string): The API key sent in the X-API-Key header, or empty.
+//   - httpClient (*http.Client): The HTTP client; nil uses http.DefaultClient.
+//
+// Returns:
+//   - *Client: The client.
+func NewClient(baseURL, apiKey string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), apiKey: apiKey, http: httpClient}
+}
+
+// Me returns the identity the server sees.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - dashboard.Me: The user and roles.
+//   - error: An error if the server cannot be read or rejects the key.
+func (c *Client) Me(ctx context.Context) (dashboard.Me, error) {
+	var me dashboard.Me
+	return me, c.get(ctx, "/dashboard/api/me", &me)
+}
+
+// Tools returns the tool catalog.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - []dashboard.Tool: The tools with their input schemas.
+//   - error: An error if the server cannot be read.
+func (c *Client) Tools(ctx context.Context) ([]dashboard.Tool, error) {
+	var tools []dashboard.Tool
+	return tools, c.get(ctx, "/dashboard/api/tools", &tools)
+}
+
+// Calls returns the recent tool calls, newest first.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - dashboard.Calls: The calls.
+//   - error: An error if the server cannot be read.
+func (c *Client) Calls(ctx context.Context) (dashboard.Calls, error) {
+	var calls dashboard.Calls
+	return calls, c.get(ctx, "/dashboard/api/calls", &calls)
+}
+
+// Upstreams returns the health and circuit state of the upstream services.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - []dashboard.Upstream: The upstreams.
+//   - error: An error if the server cannot be read.
+func (c *Client) Upstreams(ctx context.Context) ([]dashboard.Upstream, error) {
+	var upstreams []dashboard.Upstream
+	return upstreams, c.get(ctx, "/dashboard/api/upstreams", &upstreams)
+}
+
+// Execute calls a tool.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - name (string): The name of the tool.
+//   - args (map[string]any): The arguments.
+//
+// Returns:
+//   - json.RawMessage: The result of the tool.
+//   - error: An error if the call fails.
+func (c *Client) Execute(ctx context.Context, name string, args map[string]any) (json.RawMessage, error) {
+	body, err := json.Marshal(map[string]any{"name": name, "arguments": args})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the arguments: %w", err)
+	}
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/v1/execute", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return io.ReadAll(resp.Body)
+}
+
+// StreamLogs calls fn with each server log entry, starting with the recent
+// history, until the context is done or the stream ends.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the stream.
+//   - fn (func(logging.LogEntry)): Called with each entry.
+//
+// Returns:
+//   - error: An error if the stream cannot be opened or breaks.
+func (c *Client) StreamLogs(ctx context.Context, fn func(logging.LogEntry)) error {
+	req, err := c.newRequest(ctx, http.MethodGet, "/dashboard/api/logs", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogLine)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var entry logging.LogEntry
+		if err := json.Unmarshal([]byte(data), &entry); err == nil {
+			fn(entry)
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("log stream broken: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	return req, nil
+}
+
+// do sends a request and turns a response other than 200 into an error.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach the server: %w", err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp, nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("the server rejected the request (%s); pass an --api-key with the viewer or admin role", resp.Status)
+	default:
+		msg := strings.TrimSpace(string(body))
+		if msg == "" {
+			return nil, errors.New(resp.Status)
+		}
+		return nil, fmt.Errorf("%s: %s", resp.Status, msg)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Field is an input of a tool call form: a top-level property of the input
+// schema of the tool.
+type Field struct {
+	Name        string
+	Type        string
+	Description string
+	Required    bool
+	// Default is the default of the schema, shown and used on empty input.
+	Default any
+	// Enum lists the allowed values, if the schema restricts them.
+	Enum []any
+}
+
+// FormFields returns the fields of the form for an input schema: the
+// required properties first, then the others, each group by name.
+//
+// Parameters:
+//   - schema (map[string]any): The JSON schema of the tool input.
+//
+// Returns:
+//   - []Field: The fields.
+func FormFields(schema map[string]any) []Field {
+	props, _ := schema["properties"].(map[string]any)
+	required := map[string]bool{}
+	if list, ok := schema["required"].([]any); ok {
+		for _, name := range list {
+			if s, ok := name.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+	fields := make([]Field, 0, len(props))
+	for name, raw := range props {
+		prop, _ := raw.(map[string]any)
+		f := Field{Name: name, Required: required[name], Type: "string"}
+		switch t := prop["type"].(type) {
+		case string:
+			f.Type = t
+		case []any:
+			// A union such as ["string", "null"]: the first non-null type.
+			for _, v := range t {
+				if s, ok := v.(string); ok && s != "null" {
+					f.Type = s
+					break
+				}
+			}
+		}
+		f.Description, _ = prop["description"].(string)
+		f.Default = prop["default"]
+		f.Enum, _ = prop["enum"].([]any)
+		fields = append(fields, f)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		if fields[i].Required != fields[j].Required {
+			return fields[i].Required
+		}
+		return fields[i].Name < fields[j].Name
+	})
+	return fields
+}
+
+// Prompt returns the prompt of the field, e.g. `city (string, required)`.
+//
+// Returns:
+//   - string: The prompt.
+func (f Field) Prompt() string {
+	var b strings.Builder
+	b.WriteString(f.Name)
+	b.WriteString(" (")
+	b.WriteString(f.Type)
+	if f.Required {
+		b.WriteString(", required")
+	}
+	if len(f.Enum) > 0 {
+		values := make([]string, len(f.Enum))
+		for i, v := range f.Enum {
+			values[i] = fmt.Sprint(v)
+		}
+		b.WriteString(", one of ")
+		b.WriteString(strings.Join(values, "|"))
+	}
+	if f.Default != nil {
+		fmt.Fprintf(&b, ", default %v", f.Default)
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// Parse converts the text typed for the field to its JSON value. Arrays and
+// objects are typed as JSON.
+//
+// Parameters:
+//   - text (string): The input, trimmed of spaces.
+//
+// Returns:
+//   - any: The value; nil if the input is empty and the field has no default.
+//   - bool: Whether the field gets a value.
+//   - error: An error if the input does not fit the field.
+func (f Field) Parse(text string) (any, bool, error) {
+	if text == "" {
+		if f.Default != nil {
+			return f.Default, true, nil
+		}
+		if f.Required {
+			return nil, false, fmt.Errorf("%s is required", f.Name)
+		}
+		return nil, false, nil
+	}
+	var v any
+	switch f.Type {
+	case "integer":
+		n, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s must be an integer", f.Name)
+		}
+		v = n
+	case "number":
+		n, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s must be a number", f.Name)
+		}
+		v = n
+	case "boolean":
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s must be true or false", f.Name)
+		}
+		v = b
+	case "array", "object":
+		if err := json.Unmarshal([]byte(text), &v); err != nil {
+			return nil, false, fmt.Errorf("%s must be a JSON %s: %w", f.Name, f.Type, err)
+		}
+	default:
+		v = text
+	}
+	if len(f.Enum) > 0 && !slices.ContainsFunc(f.Enum, func(e any) bool { return fmt.Sprint(e) == fmt.Sprint(v) }) {
+		return nil, false, fmt.Errorf("%s must be one of the listed values", f.Name)
+	}
+	return v, true, nil
+}
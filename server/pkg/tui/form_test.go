@@ -0,0 +1,71 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormFields(t *testing.T) {
+	fields := FormFields(map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"units":   map[string]any{"type": "string", "enum": []any{"metric", "imperial"}, "default": "metric"},
+			"city":    map[string]any{"type": "string", "description": "The city"},
+			"days":    map[string]any{"type": "integer"},
+			"verbose": map[string]any{"type": []any{"null", "boolean"}},
+		},
+		"required": []any{"city", "days"},
+	})
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Name
+	}
+	assert.Equal(t, []string{"city", "days", "units", "verbose"}, names, "required fields first")
+	assert.Equal(t, "The city", fields[0].Description)
+	assert.Equal(t, "boolean", fields[3].Type)
+	assert.Equal(t, "city (string, required)", fields[0].Prompt())
+	assert.Equal(t, "units (string, one of metric|imperial, default metric)", fields[2].Prompt())
+
+	assert.Empty(t, FormFields(nil))
+}
+
+func TestField_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   Field
+		input   string
+		want    any
+		set     bool
+		wantErr string
+	}{
+		{name: "string", field: Field{Name: "city", Type: "string"}, input: "Paris", want: "Paris", set: true},
+		{name: "integer", field: Field{Name: "days", Type: "integer"}, input: "3", want: int64(3), set: true},
+		{name: "bad integer", field: Field{Name: "days", Type: "integer"}, input: "three", wantErr: "days must be an integer"},
+		{name: "number", field: Field{Name: "lat", Type: "number"}, input: "48.85", want: 48.85, set: true},
+		{name: "boolean", field: Field{Name: "verbose", Type: "boolean"}, input: "true", want: true, set: true},
+		{name: "array", field: Field{Name: "tags", Type: "array"}, input: `["a","b"]`, want: []any{"a", "b"}, set: true},
+		{name: "bad object", field: Field{Name: "opts", Type: "object"}, input: "{", wantErr: "opts must be a JSON object"},
+		{name: "empty optional", field: Field{Name: "city", Type: "string"}, input: ""},
+		{name: "empty required", field: Field{Name: "city", Type: "string", Required: true}, input: "", wantErr: "city is required"},
+		{name: "default", field: Field{Name: "units", Type: "string", Required: true, Default: "metric"}, input: "", want: "metric", set: true},
+		{name: "enum", field: Field{Name: "units", Type: "string", Enum: []any{"metric"}}, input: "imperial", wantErr: "units must be one of the listed values"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, set, err := tt.field.Parse(tt.input)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.set, set)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
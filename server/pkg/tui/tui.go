@@ -0,0 +1,486 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tui is the terminal UI of a running server (`mcpany tui`): a live
+// view of the recent tool calls, the health of the upstreams and the logs,
+// and forms to call tools. It is meant for operators on an SSH session,
+// where the web dashboard is not reachable.
+//
+// The UI reads commands line by line, so it needs no raw terminal mode and
+// works on any terminal that understands ANSI escape codes.
+package tui
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/dashboard"
+	"github.com/mcpany/core/server/pkg/logging"
+)
+
+const (
+	clearScreen = "\x1b[H\x1b[2J"
+	bold        = "\x1b[1m"
+	red         = "\x1b[31m"
+	green       = "\x1b[32m"
+	yellow      = "\x1b[33m"
+	reset       = "\x1b[0m"
+
+	// maxLogEntries is the number of log entries kept for the logs view.
+	maxLogEntries = 500
+	// cancelInput cancels a tool call form.
+	cancelInput = ":cancel"
+)
+
+// Options configures the terminal UI.
+type Options struct {
+	// Refresh is how often the current view is reloaded.
+	Refresh time.Duration
+	// Width and Height are the size of the terminal, in characters.
+	Width  int
+	Height int
+}
+
+type view string
+
+const (
+	viewCalls     view = "calls"
+	viewUpstreams view = "upstreams"
+	viewLogs      view = "logs"
+	viewTools     view = "tools"
+	viewResult    view = "result"
+)
+
+var viewKeys = map[string]view{
+	"1": viewCalls, "c": viewCalls,
+	"2": viewUpstreams, "u": viewUpstreams,
+	"3": viewLogs, "l": viewLogs,
+	"4": viewTools, "t": viewTools,
+}
+
+type ui struct {
+	client *Client
+	out    io.Writer
+	lines  <-chan string
+	opts   Options
+
+	me     dashboard.Me
+	view   view
+	status string
+	body   []string
+
+	mu   sync.Mutex
+	logs []logging.LogEntry
+}
+
+// Run runs the terminal UI until the user quits, the input ends or the
+// context is done.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the session.
+//   - client (*Client): The client of the server.
+//   - in (io.Reader): The input, read line by line.
+//   - out (io.Writer): The terminal.
+//   - opts (Options): The options.
+//
+// Returns:
+//   - error: An error if the server cannot be reached.
+func Run(ctx context.Context, client *Client, in io.Reader, out io.Writer, opts Options) error {
+	if opts.Refresh <= 0 {
+		opts.Refresh = 2 * time.Second
+	}
+	if opts.Width <= 0 {
+		opts.Width = 120
+	}
+	if opts.Height <= 0 {
+		opts.Height = 30
+	}
+	me, err := client.Me(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	u := &ui{client: client, out: out, lines: readLines(ctx, in), opts: opts, me: me, view: viewCalls}
+
+	logged := make(chan struct{}, 1)
+	go func() {
+		err := client.StreamLogs(ctx, func(entry logging.LogEntry) {
+			u.mu.Lock()
+			u.logs = append(u.logs, entry)
+			if len(u.logs) > maxLogEntries {
+				u.logs = u.logs[len(u.logs)-maxLogEntries:]
+			}
+			u.mu.Unlock()
+			select {
+			case logged <- struct{}{}:
+			default:
+			}
+		})
+		if err != nil {
+			u.appendLog(logging.LogEntry{Level: "ERROR", Message: err.Error()})
+		}
+	}()
+
+	ticker := time.NewTicker(opts.Refresh)
+	defer ticker.Stop()
+	u.load(ctx)
+	for {
+		u.render()
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			u.load(ctx)
+		case <-logged:
+			if u.view == viewLogs {
+				u.load(ctx)
+			}
+		case line, ok := <-u.lines:
+			if !ok {
+				return nil
+			}
+			if quit := u.handle(ctx, strings.TrimSpace(line)); quit {
+				_, _ = fmt.Fprint(out, clearScreen)
+				return nil
+			}
+		}
+	}
+}
+
+// readLines reads the input line by line on a goroutine, so that the UI can
+// refresh while waiting for a command.
+func readLines(ctx context.Context, in io.Reader) <-chan string {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		r := bufio.NewReader(in)
+		for {
+			line, err := r.ReadString('\n')
+			if line != "" {
+				select {
+				case lines <- strings.TrimRight(line, "\r\n"):
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return lines
+}
+
+// handle runs a command, and reports whether the user quits.
+func (u *ui) handle(ctx context.Context, line string) bool {
+	cmd, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+	switch {
+	case line == "":
+		u.load(ctx)
+	case cmd == "q" || cmd == "quit":
+		return true
+	case cmd == "r":
+		u.load(ctx)
+	case cmd == "i" || cmd == "call":
+		u.call(ctx, arg)
+	case viewKeys[cmd] != "":
+		u.view = viewKeys[cmd]
+		u.status = ""
+		u.load(ctx)
+	default:
+		u.status = fmt.Sprintf("unknown command %q", line)
+	}
+	return false
+}
+
+// load reloads the current view.
+func (u *ui) load(ctx context.Context) {
+	var err error
+	switch u.view {
+	case viewCalls:
+		u.body, err = u.loadCalls(ctx)
+	case viewUpstreams:
+		u.body, err = u.loadUpstreams(ctx)
+	case viewTools:
+		u.body, err = u.loadTools(ctx)
+	case viewLogs:
+		u.body = u.logLines()
+	case viewResult:
+		// The result of the last call stays until another view is chosen.
+	}
+	if err != nil {
+		u.status = red + err.Error() + reset
+	}
+}
+
+func (u *ui) loadCalls(ctx context.Context) ([]string, error) {
+	calls, err := u.client.Calls(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !calls.AuditEnabled {
+		return []string{"Audit logging is not enabled: no calls are recorded."}, nil
+	}
+	rows := [][]string{{"TIME", "TOOL", "USER", "DURATION", "ERROR"}}
+	for _, c := range calls.Calls {
+		ts := c.Timestamp
+		if t, err := time.Parse(time.RFC3339Nano, c.Timestamp); err == nil {
+			ts = t.Local().Format("15:04:05")
+		}
+		rows = append(rows, []string{ts, c.ToolName, c.UserID, c.Duration, c.Error})
+	}
+	lines := table(rows)
+	for i, c := range calls.Calls {
+		if c.Error != "" {
+			lines[i+1] = red + lines[i+1] + reset
+		}
+	}
+	return lines, nil
+}
+
+func (u *ui) loadUpstreams(ctx context.Context) ([]string, error) {
+	upstreams, err := u.client.Upstreams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows := [][]string{{"SERVICE", "STATUS", "CIRCUIT", "FAILURES", "ERROR"}}
+	for _, up := range upstreams {
+		name, circuit := up.Name, up.Circuit
+		if name == "" {
+			name = up.ID
+		}
+		if circuit == "" {
+			circuit = "-"
+		}
+		rows = append(rows, []string{name, up.Status, circuit, fmt.Sprint(up.ConsecutiveFailures), up.Error})
+	}
+	// Colors go on whole lines: escape codes would break the alignment.
+	lines := table(rows)
+	for i, up := range upstreams {
+		switch {
+		case up.Status != "OK" || up.Circuit == "open":
+			lines[i+1] = red + lines[i+1] + reset
+		case up.Circuit == "half-open":
+			lines[i+1] = yellow + lines[i+1] + reset
+		default:
+			lines[i+1] = green + lines[i+1] + reset
+		}
+	}
+	return lines, nil
+}
+
+func (u *ui) loadTools(ctx context.Context) ([]string, error) {
+	tools, err := u.client.Tools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows := [][]string{{"TOOL", "SERVICE", "DESCRIPTION"}}
+	for _, t := range tools {
+		rows = append(rows, []string{t.Name, t.ServiceID, firstLine(t.Description)})
+	}
+	return append(table(rows), "", "Type `i <tool>` to call a tool."), nil
+}
+
+func (u *ui) logLines() []string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	lines := make([]string, 0, len(u.logs))
+	for _, e := range u.logs {
+		level := e.Level
+		switch level {
+		case "ERROR":
+			level = red + level + reset
+		case "WARN":
+			level = yellow + level + reset
+		}
+		line := fmt.Sprintf("%s %s %s", e.Timestamp, level, e.Message)
+		if e.Source != "" {
+			line += " [" + e.Source + "]"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func (u *ui) appendLog(entry logging.LogEntry) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.logs = append(u.logs, entry)
+}
+
+// call asks for the arguments of a tool, one field at a time, and calls it.
+func (u *ui) call(ctx context.Context, name string) {
+	if name == "" {
+		u.status = "usage: i <tool>"
+		return
+	}
+	tools, err := u.client.Tools(ctx)
+	if err != nil {
+		u.status = red + err.Error() + reset
+		return
+	}
+	var tool *dashboard.Tool
+	for i := range tools {
+		if tools[i].Name == name {
+			tool = &tools[i]
+			break
+		}
+	}
+	if tool == nil {
+		u.status = fmt.Sprintf("no tool %q; list the tools with 4", name)
+		return
+	}
+
+	_, _ = fmt.Fprintf(u.out, "%s%sCall %s%s\n", clearScreen, bold, tool.Name, reset)
+	if tool.Description != "" {
+		_, _ = fmt.Fprintln(u.out, tool.Description)
+	}
+	_, _ = fmt.Fprintf(u.out, "Leave a field empty to skip it or use its default; type %s to cancel.\n\n", cancelInput)
+	args := map[string]any{}
+	for _, f := range FormFields(tool.InputSchema) {
+		if f.Description != "" {
+			_, _ = fmt.Fprintf(u.out, "  %s\n", firstLine(f.Description))
+		}
+		for {
+			_, _ = fmt.Fprintf(u.out, "%s: ", f.Prompt())
+			var line string
+			select {
+			case <-ctx.Done():
+				return
+			case l, ok := <-u.lines:
+				if !ok {
+					return
+				}
+				line = strings.TrimSpace(l)
+			}
+			if line == cancelInput {
+				u.status = "call cancelled"
+				return
+			}
+			v, set, err := f.Parse(line)
+			if err != nil {
+				_, _ = fmt.Fprintf(u.out, "%s%s%s\n", red, err, reset)
+				continue
+			}
+			if set {
+				args[f.Name] = v
+			}
+			break
+		}
+	}
+
+	_, _ = fmt.Fprintf(u.out, "\nCalling %s...\n", tool.Name)
+	start := time.Now()
+	result, err := u.client.Execute(ctx, tool.Name, args)
+	u.view = viewResult
+	if err != nil {
+		u.status = red + "call failed" + reset
+		u.body = strings.Split(err.Error(), "\n")
+		return
+	}
+	u.status = fmt.Sprintf("%s returned in %s", tool.Name, time.Since(start).Round(time.Millisecond))
+	var pretty bytes.Buffer
+	if json.Indent(&pretty, result, "", "  ") != nil {
+		pretty.Reset()
+		pretty.Write(result)
+	}
+	u.body = strings.Split(strings.TrimRight(pretty.String(), "\n"), "\n")
+}
+
+// render redraws the screen: the header, the current view, cut to the
+// height of the terminal, and the commands.
+func (u *ui) render() {
+	var b strings.Builder
+	b.WriteString(clearScreen)
+	role := "read-only"
+	if u.me.Admin {
+		role = "admin"
+	}
+	fmt.Fprintf(&b, "%sMCP Any%s  %s  %s (%s)\n", bold, reset, u.client.baseURL, u.me.User, role)
+	for _, v := range []struct {
+		key  string
+		view view
+	}{{"1", viewCalls}, {"2", viewUpstreams}, {"3", viewLogs}, {"4", viewTools}} {
+		label := fmt.Sprintf("[%s] %s", v.key, v.view)
+		if u.view == v.view {
+			label = bold + label + reset
+		}
+		b.WriteString(label + "  ")
+	}
+	b.WriteString("\n\n")
+
+	// Header (3 lines), footer (3 lines).
+	room := max(u.opts.Height-6, 1)
+	body := u.body
+	if len(body) > room {
+		if u.view == viewLogs {
+			body = body[len(body)-room:] // The newest logs.
+		} else {
+			body = append(body[:room-1:room-1], fmt.Sprintf("... %d more", len(u.body)-room+1))
+		}
+	}
+	for _, line := range body {
+		b.WriteString(truncate(line, u.opts.Width))
+		b.WriteString("\n")
+	}
+	for i := len(body); i < room; i++ {
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "%s\n", u.status)
+	b.WriteString("1-4 view  i <tool> call  r refresh  q quit\n> ")
+	_, _ = io.WriteString(u.out, b.String())
+}
+
+// table aligns rows in columns.
+func table(rows [][]string) []string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		_, _ = fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	_ = w.Flush()
+	return strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// truncate cuts a line to the width of the terminal, not counting the
+// escape codes.
+func truncate(line string, width int) string {
+	var b strings.Builder
+	visible := 0
+	escape := false
+	for _, r := range line {
+		switch {
+		case r == '\x1b':
+			escape = true
+		case escape:
+			if r == 'm' {
+				escape = false
+			}
+		default:
+			if visible == width {
+				b.WriteString(reset)
+				return b.String()
+			}
+			visible++
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
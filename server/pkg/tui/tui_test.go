@@ -0,0 +1,165 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package tui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/dashboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer serves the dashboard API and the execute API the way a running
+// server does, with canned data.
+func fakeServer(t *testing.T, executed *map[string]any) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	reply := func(v any) http.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			_ = json.NewEncoder(w).Encode(v)
+		}
+	}
+	mux.HandleFunc("/dashboard/api/me", reply(dashboard.Me{User: "system-admin", Roles: []string{"admin"}, Admin: true}))
+	mux.HandleFunc("/dashboard/api/calls", reply(dashboard.Calls{AuditEnabled: true, Calls: []dashboard.Call{
+		{Timestamp: "2026-10-16T12:00:00Z", ToolName: "weather.get_forecast", UserID: "alice", Duration: "12ms"},
+	}}))
+	mux.HandleFunc("/dashboard/api/upstreams", reply([]dashboard.Upstream{
+		{ID: "s1", Name: "github", Status: "ERROR", Error: "connection refused", Circuit: "open", ConsecutiveFailures: 5},
+	}))
+	mux.HandleFunc("/dashboard/api/tools", reply([]dashboard.Tool{{
+		Name:      "weather.get_forecast",
+		ServiceID: "weather",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"city": map[string]any{"type": "string"}, "days": map[string]any{"type": "integer"}},
+			"required":   []any{"city"},
+		},
+	}}))
+	mux.HandleFunc("/dashboard/api/logs", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"timestamp\":\"12:00:00\",\"level\":\"WARN\",\"message\":\"upstream slow\"}\n\n"))
+	})
+	mux.HandleFunc("/api/v1/execute", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "secret" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var req struct {
+			Name      string         `json:"name"`
+			Arguments map[string]any `json:"arguments"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		*executed = req.Arguments
+		_, _ = w.Write([]byte(`{"forecast":"sunny"}`))
+	})
+	return httptest.NewServer(mux)
+}
+
+// syncBuffer is a buffer the UI writes while the test reads it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func runUI(t *testing.T, serverURL, apiKey, input string) string {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var out bytes.Buffer
+	err := Run(ctx, NewClient(serverURL, apiKey, nil), strings.NewReader(input), &out, Options{Refresh: time.Hour})
+	require.NoError(t, err)
+	return out.String()
+}
+
+func TestRun_Views(t *testing.T) {
+	var executed map[string]any
+	srv := fakeServer(t, &executed)
+	defer srv.Close()
+
+	out := runUI(t, srv.URL, "secret", "2\n4\nq\n")
+	assert.Contains(t, out, "system-admin (admin)")
+	assert.Contains(t, out, "weather.get_forecast", "the calls view is first")
+	assert.Contains(t, out, "connection refused")
+	assert.Contains(t, out, "Type `i <tool>` to call a tool.")
+}
+
+func TestRun_Logs(t *testing.T) {
+	var executed map[string]any
+	srv := fakeServer(t, &executed)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	in, w := io.Pipe()
+	var out syncBuffer
+	done := make(chan error, 1)
+	go func() {
+		done <- Run(ctx, NewClient(srv.URL, "secret", nil), in, &out, Options{Refresh: 10 * time.Millisecond})
+	}()
+	_, err := io.WriteString(w, "3\n")
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return strings.Contains(out.String(), "upstream slow") }, 5*time.Second, 10*time.Millisecond)
+	_, err = io.WriteString(w, "q\n")
+	require.NoError(t, err)
+	require.NoError(t, <-done)
+}
+
+func TestRun_CallTool(t *testing.T) {
+	var executed map[string]any
+	srv := fakeServer(t, &executed)
+	defer srv.Close()
+
+	out := runUI(t, srv.URL, "secret", "i weather.get_forecast\n\nParis\nthree\n3\nq\n")
+	assert.Contains(t, out, "city is required")
+	assert.Contains(t, out, "days must be an integer")
+	assert.Equal(t, map[string]any{"city": "Paris", "days": float64(3)}, executed)
+	assert.Contains(t, out, `"forecast": "sunny"`)
+
+	out = runUI(t, srv.URL, "secret", "i weather.get_forecast\n:cancel\nq\n")
+	assert.Contains(t, out, "call cancelled")
+
+	out = runUI(t, srv.URL, "secret", "i missing\nq\n")
+	assert.Contains(t, out, `no tool "missing"`)
+
+	out = runUI(t, srv.URL, "wrong", "i weather.get_forecast\nParis\n\nq\n")
+	assert.Contains(t, out, "pass an --api-key")
+}
+
+func TestRun_Unauthorized(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	err := Run(context.Background(), NewClient(srv.URL, "", nil), strings.NewReader("q\n"), &bytes.Buffer{}, Options{})
+	assert.ErrorContains(t, err, "pass an --api-key")
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "abc", truncate("abc", 5))
+	assert.Equal(t, "ab"+reset, truncate("abc", 2))
+	assert.Equal(t, red+"ab"+reset, truncate(red+"abc", 2), "escape codes take no room")
+}
@@ -10,6 +10,7 @@ import (
 	"net"
 	"strings"
 
+	otelgrpc "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -70,6 +71,7 @@ func (f *ConnectionFactory) WithDialer(dialer func(context.Context, string) (net
 func (f *ConnectionFactory) NewConnection(_ context.Context, targetAddress string) (*grpc.ClientConn, error) {
 	opts := []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
 	}
 	if f.dialer != nil {
 		opts = append(opts, grpc.WithContextDialer(f.dialer))
@@ -5,11 +5,8 @@ package grpc
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"fmt"
 	"net"
-	"os"
 	"strings"
 	"time"
 
@@ -18,7 +15,8 @@ import (
 	"github.com/mcpany/core/server/pkg/client"
 	healthChecker "github.com/mcpany/core/server/pkg/health"
 	"github.com/mcpany/core/server/pkg/pool"
-	"github.com/mcpany/core/server/pkg/validation"
+	"github.com/mcpany/core/server/pkg/util"
+	otelgrpc "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
@@ -68,7 +66,7 @@ func (p *poolWithChecker[T]) Close() error {
 //   - Returns error if TLS configuration is invalid.
 //
 // Side Effects:
-//   - Reads certificate files if mTLS is configured.
+//   - Reads certificate files if TLS or mTLS is configured.
 //   - Initializes gRPC clients.
 func NewGrpcPool(
 	minSize, maxSize int,
@@ -88,44 +86,27 @@ func NewGrpcPool(
 		return nil, fmt.Errorf("grpc service address is empty")
 	}
 
+	// TLS is used when the service has a tls_config or upstream_auth mtls;
+	// otherwise the connection is plaintext.
+	transportCreds := insecure.NewCredentials()
+	tlsSettings := util.TLSConfigWithMTLS(config.GetGrpcService().GetTlsConfig(), config.GetUpstreamAuth().GetMtls())
+	if tlsSettings != nil {
+		tlsConfig, err := util.NewTLSClientConfig(tlsSettings)
+		if err != nil {
+			return nil, err
+		}
+		transportCreds = credentials.NewTLS(tlsConfig)
+	}
+
 	// Create a shared health checker for all clients in this pool
 	checker := healthChecker.NewChecker(config)
 
 	factory := func(_ context.Context) (*client.GrpcClientWrapper, error) {
-		var transportCreds credentials.TransportCredentials
-		if mtlsConfig := config.GetUpstreamAuth().GetMtls(); mtlsConfig != nil {
-			if err := validation.IsSecurePath(mtlsConfig.GetClientCertPath()); err != nil {
-				return nil, fmt.Errorf("invalid client certificate path: %w", err)
-			}
-			if err := validation.IsSecurePath(mtlsConfig.GetClientKeyPath()); err != nil {
-				return nil, fmt.Errorf("invalid client key path: %w", err)
-			}
-			certificate, err := tls.LoadX509KeyPair(mtlsConfig.GetClientCertPath(), mtlsConfig.GetClientKeyPath())
-			if err != nil {
-				return nil, err
-			}
-
-			if err := validation.IsSecurePath(mtlsConfig.GetCaCertPath()); err != nil {
-				return nil, fmt.Errorf("invalid CA certificate path: %w", err)
-			}
-			caCert, err := os.ReadFile(mtlsConfig.GetCaCertPath())
-			if err != nil {
-				return nil, err
-			}
-
-			caCertPool := x509.NewCertPool()
-			caCertPool.AppendCertsFromPEM(caCert)
-
-			transportCreds = credentials.NewTLS(&tls.Config{
-				Certificates: []tls.Certificate{certificate},
-				RootCAs:      caCertPool,
-				MinVersion:   tls.VersionTLS12,
-			})
-		} else {
-			transportCreds = insecure.NewCredentials()
+		opts := []grpc.DialOption{
+			grpc.WithTransportCredentials(transportCreds),
+			// Trace upstream calls and propagate the trace context.
+			grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
 		}
-
-		opts := []grpc.DialOption{grpc.WithTransportCredentials(transportCreds)}
 		if dialer != nil {
 			opts = append(opts, grpc.WithContextDialer(dialer))
 		}
@@ -731,6 +731,10 @@ func (u *Upstream) createAndRegisterHTTPTools(ctx context.Context, serviceID, ad
 			}
 		}
 
+		if httpDef.GetPagination().GetStyle() != configv1.PaginationConfig_STYLE_UNSPECIFIED {
+			addCursorProperty(inputSchema)
+		}
+
 		newToolProto := pb.Tool_builder{
 			Name:                proto.String(toolNamePart),
 			Description:         proto.String(definition.GetDescription()),
@@ -834,3 +838,22 @@ func (u *Upstream) createAndRegisterPrompts(_ context.Context, serviceID string,
 		log.Info("Registered prompt", "prompt_name", newPrompt.Prompt().Name, "is_reload", isReload)
 	}
 }
+
+// addCursorProperty adds the standardized cursor input of paginated tools to
+// an input schema.
+func addCursorProperty(inputSchema *structpb.Struct) {
+	props := inputSchema.GetFields()["properties"].GetStructValue()
+	if props == nil {
+		props = &structpb.Struct{Fields: make(map[string]*structpb.Value)}
+		inputSchema.Fields["properties"] = structpb.NewStructValue(props)
+	}
+	if props.Fields == nil {
+		props.Fields = make(map[string]*structpb.Value)
+	}
+	props.Fields[tool.CursorInputName] = structpb.NewStructValue(&structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			"type":        structpb.NewStringValue("string"),
+			"description": structpb.NewStringValue("Opaque cursor of the page to fetch, taken from the nextCursor of the previous result. Omit it for the first page."),
+		},
+	})
+}
@@ -5,9 +5,6 @@ package http //nolint:revive,nolintlint // Package name 'http' is intentional fo
 
 import (
 	"context"
-	"crypto/tls"
-	"fmt"
-	"crypto/x509"
 	"net/http"
 	"os"
 	"time"
@@ -17,7 +14,6 @@ import (
 	healthChecker "github.com/mcpany/core/server/pkg/health"
 	"github.com/mcpany/core/server/pkg/pool"
 	"github.com/mcpany/core/server/pkg/util"
-	"github.com/mcpany/core/server/pkg/validation"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
@@ -60,41 +56,19 @@ func (p *httpPool) Close() error {
 //   - Returns error if pool creation fails.
 //
 // Side Effects:
-//   - Reads certificate files if mTLS is configured.
+//   - Reads certificate files if TLS or mTLS is configured.
 //   - Initializes a new http.Transport and http.Client.
 var NewHTTPPool = func(
 	minSize, maxSize int,
 	idleTimeout time.Duration,
 	config *configv1.UpstreamServiceConfig,
 ) (pool.Pool[*client.HTTPClientWrapper], error) {
-	tlsConfig := &tls.Config{
-		MinVersion:         tls.VersionTLS12,
-		InsecureSkipVerify: config.GetHttpService().GetTlsConfig().GetInsecureSkipVerify(), //nolint:gosec
-	}
-
-	if mtlsConfig := config.GetUpstreamAuth().GetMtls(); mtlsConfig != nil {
-		if err := validation.IsSecurePath(mtlsConfig.GetClientCertPath()); err != nil {
-			return nil, fmt.Errorf("invalid client certificate path: %w", err)
-		}
-		if err := validation.IsSecurePath(mtlsConfig.GetClientKeyPath()); err != nil {
-			return nil, fmt.Errorf("invalid client key path: %w", err)
-		}
-		cert, err := tls.LoadX509KeyPair(mtlsConfig.GetClientCertPath(), mtlsConfig.GetClientKeyPath())
-		if err != nil {
-			return nil, err
-		}
-		tlsConfig.Certificates = []tls.Certificate{cert}
-
-		if err := validation.IsSecurePath(mtlsConfig.GetCaCertPath()); err != nil {
-			return nil, fmt.Errorf("invalid CA certificate path: %w", err)
-		}
-		caCert, err := os.ReadFile(mtlsConfig.GetCaCertPath())
-		if err != nil {
-			return nil, err
-		}
-		caCertPool := x509.NewCertPool()
-		caCertPool.AppendCertsFromPEM(caCert)
-		tlsConfig.RootCAs = caCertPool
+	tlsConfig, err := util.NewTLSClientConfig(util.TLSConfigWithMTLS(
+		config.GetHttpService().GetTlsConfig(),
+		config.GetUpstreamAuth().GetMtls(),
+	))
+	if err != nil {
+		return nil, err
 	}
 
 	dialer := util.NewSafeDialer()
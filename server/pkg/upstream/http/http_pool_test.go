@@ -5,8 +5,14 @@ package http //nolint:revive,nolintlint // Package name 'http' is intentional fo
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -15,6 +21,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/durationpb"
 )
 
@@ -177,3 +184,82 @@ func TestHTTPPool_TimeoutConfiguration(t *testing.T) {
 		assert.Equal(t, 10*time.Second, c.Client.Timeout)
 	})
 }
+
+func TestHTTPPool_TLSConfigMTLS(t *testing.T) {
+	t.Setenv("MCPANY_ALLOW_LOOPBACK_RESOURCES", "true")
+	dir := t.TempDir()
+	caPath, certPath, keyPath := generateCertFiles(t, dir)
+
+	caPEM, err := os.ReadFile(caPath) //nolint:gosec // Test file
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	require.True(t, clientCAs.AppendCertsFromPEM(caPEM))
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+		MinVersion: tls.VersionTLS12,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	// The CA bundle holds both the client CA and the server certificate.
+	bundlePath := filepath.Join(dir, "bundle.pem")
+	bundle := append(caPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})...)
+	require.NoError(t, os.WriteFile(bundlePath, bundle, 0o600))
+
+	newConfig := func(withClientCert bool) *configv1.UpstreamServiceConfig {
+		tlsConfig := configv1.TLSConfig_builder{
+			ServerName: proto.String("example.com"),
+			CaCertPath: proto.String(bundlePath),
+			MinVersion: configv1.TLSConfig_TLS_1_3.Enum(),
+		}.Build()
+		if withClientCert {
+			tlsConfig.SetClientCertPath(certPath)
+			tlsConfig.SetClientKeyPath(keyPath)
+		}
+		return configv1.UpstreamServiceConfig_builder{
+			HttpService: configv1.HttpUpstreamService_builder{
+				Address:   proto.String(server.URL),
+				TlsConfig: tlsConfig,
+			}.Build(),
+		}.Build()
+	}
+
+	t.Run("client certificate is presented", func(t *testing.T) {
+		p, err := NewHTTPPool(1, 1, 10, newConfig(true))
+		require.NoError(t, err)
+		defer func() { _ = p.Close() }()
+
+		c, err := p.Get(context.Background())
+		require.NoError(t, err)
+		defer p.Put(c)
+
+		resp, err := c.Get(server.URL)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "Test Client", string(body))
+		assert.Equal(t, uint16(tls.VersionTLS13), resp.TLS.Version)
+	})
+
+	t.Run("handshake fails without client certificate", func(t *testing.T) {
+		p, err := NewHTTPPool(1, 1, 10, newConfig(false))
+		require.NoError(t, err)
+		defer func() { _ = p.Close() }()
+
+		c, err := p.Get(context.Background())
+		require.NoError(t, err)
+		defer p.Put(c)
+
+		resp, err := c.Get(server.URL)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		assert.Error(t, err)
+	})
+}
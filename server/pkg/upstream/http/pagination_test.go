@@ -0,0 +1,63 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"context"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/pool"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/mcpany/core/server/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestHTTPUpstream_PaginatedToolHasCursorInput(t *testing.T) {
+	pm := pool.NewManager()
+	tm := tool.NewManager(nil)
+	upstream := NewUpstream(pm)
+
+	configJSON := `{
+		"name": "paginated-service",
+		"http_service": {
+			"address": "http://127.0.0.1",
+			"tools": [
+				{"name": "list-items", "call_id": "list"},
+				{"name": "get-item", "call_id": "get"}
+			],
+			"calls": {
+				"list": {
+					"method": "HTTP_METHOD_GET",
+					"endpoint_path": "/items",
+					"pagination": {"style": "STYLE_CURSOR", "param": "after", "next_cursor_path": "{.next}"}
+				},
+				"get": {
+					"method": "HTTP_METHOD_GET",
+					"endpoint_path": "/items/1"
+				}
+			}
+		}
+	}`
+	serviceConfig := configv1.UpstreamServiceConfig_builder{}.Build()
+	require.NoError(t, protojson.Unmarshal([]byte(configJSON), serviceConfig))
+
+	serviceID, _, _, err := upstream.Register(context.Background(), serviceConfig, tm, nil, nil, false)
+	require.NoError(t, err)
+
+	properties := func(name string) map[string]any {
+		sanitized, _ := util.SanitizeToolName(name)
+		registered, ok := tm.GetTool(serviceID + "." + sanitized)
+		require.True(t, ok)
+		return registered.Tool().GetAnnotations().GetInputSchema().AsMap()["properties"].(map[string]any)
+	}
+
+	cursor, ok := properties("list-items")[tool.CursorInputName].(map[string]any)
+	require.True(t, ok, "paginated tool should accept a cursor")
+	assert.Equal(t, "string", cursor["type"])
+
+	assert.NotContains(t, properties("get-item"), tool.CursorInputName)
+}
@@ -241,6 +241,7 @@ func (u *Upstream) createAndRegisterMCPItemsFromBundle(
 		client:          mcpSdkClient,
 		bundleTransport: transport,
 		sessionRegistry: u.sessionRegistry,
+		affinity:        u.sessionAffinity(),
 	}
 
 	return u.processMCPItems(ctx, serviceID, listToolsResult, bundleConn, bundleConn, cs, toolManager, promptManager, resourceManager, serviceConfig)
@@ -0,0 +1,243 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultAffinityIdleTimeout = 10 * time.Minute
+	defaultAffinityMaxSessions = 100
+)
+
+// errAffinityClosed is returned when a sticky session is opened while the
+// upstream shuts down.
+var errAffinityClosed = errors.New("the upstream service is shutting down")
+
+// stickySession is an upstream session kept open for a client session.
+type stickySession struct {
+	key any
+	cs  ClientSession
+	// ready is closed once the session is connected, or failed to connect.
+	ready    chan struct{}
+	err      error
+	inUse    int
+	lastUsed time.Time
+	timer    *time.Timer
+}
+
+// sessionAffinity keeps an upstream session per client session, so that
+// upstream servers with per-session state see all the calls of a client in
+// one session. The sessions without calls for the idle timeout are closed.
+type sessionAffinity struct {
+	idleTimeout time.Duration
+	maxSessions int
+	registry    *SessionRegistry
+
+	mu       sync.Mutex
+	sessions map[any]*stickySession
+	closed   bool
+}
+
+// newSessionAffinity returns the sticky sessions of an upstream, or nil if
+// session affinity is not enabled.
+func newSessionAffinity(cfg *configv1.SessionAffinityConfig, registry *SessionRegistry) *sessionAffinity {
+	if !cfg.GetIsEnabled() {
+		return nil
+	}
+	a := &sessionAffinity{
+		idleTimeout: defaultAffinityIdleTimeout,
+		maxSessions: defaultAffinityMaxSessions,
+		registry:    registry,
+		sessions:    make(map[any]*stickySession),
+	}
+	if cfg.HasIdleTimeout() && cfg.GetIdleTimeout().AsDuration() > 0 {
+		a.idleTimeout = cfg.GetIdleTimeout().AsDuration()
+	}
+	if cfg.GetMaxSessions() > 0 {
+		a.maxSessions = int(cfg.GetMaxSessions())
+	}
+	return a
+}
+
+// acquire returns the upstream session of a client session, connecting it on
+// the first call. It returns false if the client session cannot be told
+// apart from the others, or if every session is busy and the limit is
+// reached; the call then gets a session of its own.
+func (a *sessionAffinity) acquire(ctx context.Context, downstream tool.Session, connect func(context.Context) (ClientSession, error)) (*stickySession, bool, error) {
+	keyed, ok := downstream.(tool.KeyedSession)
+	if !ok {
+		return nil, false, nil
+	}
+	key := keyed.SessionKey()
+
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil, false, nil
+	}
+	if s, ok := a.sessions[key]; ok {
+		s.inUse++
+		a.mu.Unlock()
+		select {
+		case <-s.ready:
+		case <-ctx.Done():
+			a.release(s, nil)
+			return nil, false, ctx.Err()
+		}
+		if s.err != nil {
+			a.release(s, nil)
+			return nil, false, s.err
+		}
+		return s, true, nil
+	}
+
+	var victim *stickySession
+	if len(a.sessions) >= a.maxSessions {
+		if victim = a.leastRecentlyUsedIdleLocked(); victim == nil {
+			a.mu.Unlock()
+			return nil, false, nil
+		}
+		a.removeLocked(victim)
+	}
+	s := &stickySession{key: key, ready: make(chan struct{}), inUse: 1, lastUsed: time.Now()}
+	a.sessions[key] = s
+	a.mu.Unlock()
+	if victim != nil {
+		_ = victim.cs.Close()
+	}
+
+	// The upstream session outlives the call that opens it.
+	cs, err := connect(context.WithoutCancel(ctx))
+
+	a.mu.Lock()
+	if err == nil && a.closed {
+		err = errAffinityClosed
+	}
+	s.cs, s.err = cs, err
+	close(s.ready)
+	if err != nil {
+		if a.sessions[key] == s {
+			delete(a.sessions, key)
+		}
+		s.inUse--
+		a.mu.Unlock()
+		if cs != nil {
+			_ = cs.Close()
+		}
+		return nil, false, err
+	}
+	if mcpSession, ok := cs.(mcp.Session); ok && a.registry != nil {
+		a.registry.Register(mcpSession, downstream)
+	}
+	s.timer = time.AfterFunc(a.idleTimeout, func() { a.expire(s) })
+	a.mu.Unlock()
+	logging.GetLogger().Debug("Opened sticky upstream MCP session", "sessions", a.count())
+	return s, true, nil
+}
+
+// release ends a call on a sticky session. A session whose connection was
+// lost is dropped, so that the next call of the client reconnects.
+func (a *sessionAffinity) release(s *stickySession, callErr error) {
+	a.mu.Lock()
+	s.inUse--
+	s.lastUsed = time.Now()
+	broken := callErr != nil && (errors.Is(callErr, mcp.ErrConnectionClosed) || errors.Is(callErr, io.EOF))
+	if !broken || a.sessions[s.key] != s {
+		a.mu.Unlock()
+		return
+	}
+	a.removeLocked(s)
+	a.mu.Unlock()
+	_ = s.cs.Close()
+}
+
+// expire closes a session that has had no calls for the idle timeout.
+func (a *sessionAffinity) expire(s *stickySession) {
+	a.mu.Lock()
+	if a.sessions[s.key] != s {
+		a.mu.Unlock()
+		return
+	}
+	if s.inUse > 0 {
+		s.timer.Reset(a.idleTimeout)
+		a.mu.Unlock()
+		return
+	}
+	if idle := time.Since(s.lastUsed); idle < a.idleTimeout {
+		s.timer.Reset(a.idleTimeout - idle)
+		a.mu.Unlock()
+		return
+	}
+	a.removeLocked(s)
+	a.mu.Unlock()
+	_ = s.cs.Close()
+	logging.GetLogger().Debug("Closed idle sticky upstream MCP session", "idle_timeout", a.idleTimeout)
+}
+
+// close closes every sticky session. The sessions opened afterwards are
+// closed right away.
+func (a *sessionAffinity) close() {
+	a.mu.Lock()
+	a.closed = true
+	var open []*stickySession
+	for _, s := range a.sessions {
+		select {
+		case <-s.ready:
+			if s.err == nil {
+				open = append(open, s)
+			}
+		default:
+		}
+		a.removeLocked(s)
+	}
+	a.mu.Unlock()
+	for _, s := range open {
+		_ = s.cs.Close()
+	}
+}
+
+// count returns the number of sticky sessions.
+func (a *sessionAffinity) count() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.sessions)
+}
+
+// leastRecentlyUsedIdleLocked returns the connected session without calls in
+// flight that was used the longest ago, or nil. The caller must hold a.mu.
+func (a *sessionAffinity) leastRecentlyUsedIdleLocked() *stickySession {
+	var lru *stickySession
+	for _, s := range a.sessions {
+		if s.inUse > 0 || s.cs == nil {
+			continue
+		}
+		if lru == nil || s.lastUsed.Before(lru.lastUsed) {
+			lru = s
+		}
+	}
+	return lru
+}
+
+// removeLocked forgets a session. The caller must hold a.mu and close the
+// session.
+func (a *sessionAffinity) removeLocked(s *stickySession) {
+	delete(a.sessions, s.key)
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	if mcpSession, ok := s.cs.(mcp.Session); ok && a.registry != nil {
+		a.registry.Unregister(mcpSession)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/tool"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// affinityTestSession is a client session told apart by its name.
+type affinityTestSession struct {
+	name string
+}
+
+func (s *affinityTestSession) CreateMessage(_ context.Context, _ *mcp.CreateMessageParams) (*mcp.CreateMessageResult, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (s *affinityTestSession) ListRoots(_ context.Context) (*mcp.ListRootsResult, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (s *affinityTestSession) SessionKey() any {
+	return s.name
+}
+
+// affinityTestUpstream counts the upstream sessions opened and closed, and
+// answers each tool call with the number of the session that served it.
+type affinityTestUpstream struct {
+	opened, closed atomic.Int32
+	callErr        error
+}
+
+func (u *affinityTestUpstream) install(t *testing.T) {
+	original := connectForTesting
+	t.Cleanup(func() { connectForTesting = original })
+	connectForTesting = func(_ *mcp.Client, _ context.Context, _ mcp.Transport, _ []mcp.Root) (ClientSession, error) {
+		n := u.opened.Add(1)
+		return &mockClientSession{
+			callToolFunc: func(_ context.Context, _ *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+				if u.callErr != nil {
+					return nil, u.callErr
+				}
+				return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprint(n)}}}, nil
+			},
+			closeFunc: func() error {
+				u.closed.Add(1)
+				return nil
+			},
+		}, nil
+	}
+}
+
+func newAffinityTestConnection(cfg *configv1.SessionAffinityConfig) *mcpConnection {
+	return &mcpConnection{
+		client:      mcp.NewClient(&mcp.Implementation{Name: "test"}, nil),
+		httpAddress: "http://localhost:8080",
+		affinity:    newSessionAffinity(cfg, NewSessionRegistry()),
+	}
+}
+
+func callAs(t *testing.T, conn *mcpConnection, session tool.Session) string {
+	t.Helper()
+	ctx := context.Background()
+	if session != nil {
+		ctx = tool.NewContextWithSession(ctx, session)
+	}
+	res, err := conn.CallTool(ctx, &mcp.CallToolParams{Name: "counter"})
+	require.NoError(t, err)
+	return res.Content[0].(*mcp.TextContent).Text
+}
+
+func TestSessionAffinity_Disabled(t *testing.T) {
+	assert.Nil(t, newSessionAffinity(nil, nil))
+	assert.Nil(t, newSessionAffinity(configv1.SessionAffinityConfig_builder{}.Build(), nil))
+}
+
+func TestSessionAffinity_RoutesClientSessionsToTheirUpstreamSession(t *testing.T) {
+	upstream := &affinityTestUpstream{}
+	upstream.install(t)
+	conn := newAffinityTestConnection(configv1.SessionAffinityConfig_builder{IsEnabled: proto.Bool(true)}.Build())
+	alice, bob := &affinityTestSession{name: "alice"}, &affinityTestSession{name: "bob"}
+
+	first := callAs(t, conn, alice)
+	assert.Equal(t, first, callAs(t, conn, alice), "the calls of a session share an upstream session")
+	assert.NotEqual(t, first, callAs(t, conn, bob), "other sessions have their own")
+	assert.Equal(t, int32(2), upstream.opened.Load())
+	assert.Equal(t, int32(0), upstream.closed.Load())
+
+	callAs(t, conn, nil)
+	assert.Equal(t, int32(3), upstream.opened.Load())
+	assert.Equal(t, int32(1), upstream.closed.Load(), "calls without a session get a session of their own")
+
+	conn.affinity.close()
+	assert.Equal(t, int32(3), upstream.closed.Load())
+}
+
+func TestSessionAffinity_IdleTimeout(t *testing.T) {
+	upstream := &affinityTestUpstream{}
+	upstream.install(t)
+	conn := newAffinityTestConnection(configv1.SessionAffinityConfig_builder{
+		IsEnabled:   proto.Bool(true),
+		IdleTimeout: durationpb.New(50 * time.Millisecond),
+	}.Build())
+	alice := &affinityTestSession{name: "alice"}
+
+	first := callAs(t, conn, alice)
+	assert.Eventually(t, func() bool { return upstream.closed.Load() == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 0, conn.affinity.count())
+	assert.NotEqual(t, first, callAs(t, conn, alice), "the state of an idle session is dropped")
+}
+
+func TestSessionAffinity_MaxSessions(t *testing.T) {
+	upstream := &affinityTestUpstream{}
+	upstream.install(t)
+	conn := newAffinityTestConnection(configv1.SessionAffinityConfig_builder{
+		IsEnabled:   proto.Bool(true),
+		MaxSessions: proto.Int32(2),
+	}.Build())
+	alice, bob, carol := &affinityTestSession{name: "alice"}, &affinityTestSession{name: "bob"}, &affinityTestSession{name: "carol"}
+
+	aliceUpstream := callAs(t, conn, alice)
+	time.Sleep(time.Millisecond)
+	bobUpstream := callAs(t, conn, bob)
+	callAs(t, conn, carol)
+	assert.Equal(t, 2, conn.affinity.count())
+	assert.Equal(t, int32(1), upstream.closed.Load(), "the least recently used session is closed")
+	assert.Equal(t, bobUpstream, callAs(t, conn, bob))
+	assert.NotEqual(t, aliceUpstream, callAs(t, conn, alice))
+}
+
+func TestSessionAffinity_ReconnectsAfterConnectionLoss(t *testing.T) {
+	upstream := &affinityTestUpstream{}
+	upstream.install(t)
+	conn := newAffinityTestConnection(configv1.SessionAffinityConfig_builder{IsEnabled: proto.Bool(true)}.Build())
+	alice := &affinityTestSession{name: "alice"}
+
+	first := callAs(t, conn, alice)
+	upstream.callErr = fmt.Errorf("calling tool: %w", mcp.ErrConnectionClosed)
+	_, err := conn.CallTool(tool.NewContextWithSession(context.Background(), alice), &mcp.CallToolParams{Name: "counter"})
+	require.ErrorIs(t, err, mcp.ErrConnectionClosed)
+	assert.Equal(t, int32(1), upstream.closed.Load())
+
+	upstream.callErr = nil
+	assert.NotEqual(t, first, callAs(t, conn, alice))
+}
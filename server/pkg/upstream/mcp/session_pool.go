@@ -0,0 +1,232 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/pool"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultSessionPoolMaxSize             = 10
+	defaultSessionPoolIdleTimeout         = 5 * time.Minute
+	defaultSessionPoolHealthCheckInterval = 30 * time.Second
+	sessionPoolPingTimeout                = 5 * time.Second
+)
+
+// pooledSession is an upstream session kept open in a sessionPool between
+// calls.
+type pooledSession struct {
+	cs          ClientSession
+	pool        *sessionPool
+	lastUsed    time.Time
+	lastChecked time.Time
+	broken      bool
+	closeOnce   sync.Once
+}
+
+// Close closes the upstream session.
+//
+// Returns:
+//   - error: An error if the session fails to close.
+func (s *pooledSession) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		s.pool.open.Add(-1)
+		err = s.cs.Close()
+	})
+	return err
+}
+
+// IsHealthy reports whether the session can serve another call. A session
+// idle for longer than the idle timeout is dropped, unless the pool would
+// shrink below its minimum size. A session not used or checked within the
+// health check interval is pinged first.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the check.
+//
+// Returns:
+//   - bool: True if the session can be used.
+func (s *pooledSession) IsHealthy(ctx context.Context) bool {
+	if s.broken {
+		return false
+	}
+	now := time.Now()
+	if now.Sub(s.lastUsed) > s.pool.idleTimeout && int(s.pool.open.Load()) > s.pool.minSize {
+		return false
+	}
+	if now.Sub(s.lastUsed) < s.pool.interval || now.Sub(s.lastChecked) < s.pool.interval {
+		return true
+	}
+	pinger, ok := s.cs.(interface {
+		Ping(ctx context.Context, params *mcp.PingParams) error
+	})
+	if !ok {
+		return true
+	}
+	pingCtx, cancel := context.WithTimeout(ctx, sessionPoolPingTimeout)
+	defer cancel()
+	s.lastChecked = now
+	return pinger.Ping(pingCtx, nil) == nil
+}
+
+// sessionPool keeps upstream sessions (for stdio, processes) open between
+// calls, and a minimum of them warm from the registration of the service on,
+// so that the calls do not wait for a cold start.
+type sessionPool struct {
+	pool        pool.Pool[*pooledSession]
+	minSize     int
+	idleTimeout time.Duration
+	interval    time.Duration
+	// open is the number of open sessions, idle or in use.
+	open atomic.Int32
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// newSessionPool returns a pool of the sessions opened by connect, or nil if
+// the service has no connection pool. It starts warming the pool in the
+// background.
+func newSessionPool(cfg *configv1.ConnectionPoolConfig, connect func(context.Context) (ClientSession, error)) (*sessionPool, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	maxSize := defaultSessionPoolMaxSize
+	if cfg.GetMaxConnections() > 0 {
+		maxSize = int(cfg.GetMaxConnections())
+	}
+	minSize := min(int(cfg.GetMinConnections()), maxSize)
+	maxIdle := maxSize
+	if cfg.GetMaxIdleConnections() > 0 {
+		maxIdle = min(max(int(cfg.GetMaxIdleConnections()), minSize), maxSize)
+	}
+	sp := &sessionPool{
+		minSize:     minSize,
+		idleTimeout: defaultSessionPoolIdleTimeout,
+		interval:    defaultSessionPoolHealthCheckInterval,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	if d := cfg.GetIdleTimeout().AsDuration(); d > 0 {
+		sp.idleTimeout = d
+	}
+	if d := cfg.GetHealthCheckInterval().AsDuration(); d > 0 {
+		sp.interval = d
+	}
+
+	factory := func(ctx context.Context) (*pooledSession, error) {
+		// The session outlives the call that opens it.
+		cs, err := connect(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+		sp.open.Add(1)
+		now := time.Now()
+		return &pooledSession{cs: cs, pool: sp, lastUsed: now, lastChecked: now}, nil
+	}
+	p, err := pool.New(factory, 0, maxIdle, maxSize, sp.idleTimeout, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid connection pool: %w", err)
+	}
+	sp.pool = p
+	go sp.keep()
+	return sp, nil
+}
+
+// get takes a session from the pool, opening one if none is idle. It waits
+// for a session to be returned if max_connections are in use.
+func (sp *sessionPool) get(ctx context.Context) (*pooledSession, error) {
+	s, err := sp.pool.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get an MCP session from the pool: %w", err)
+	}
+	return s, nil
+}
+
+// put returns a session to the pool after a call. A session whose connection
+// was lost is dropped when it is next taken.
+func (sp *sessionPool) put(s *pooledSession, callErr error) {
+	s.lastUsed = time.Now()
+	if callErr != nil && (errors.Is(callErr, mcp.ErrConnectionClosed) || errors.Is(callErr, io.EOF)) {
+		s.broken = true
+	}
+	sp.pool.Put(s)
+}
+
+// keep warms the pool, then checks the idle sessions and tops the pool up to
+// its minimum size at every health check interval.
+func (sp *sessionPool) keep() {
+	defer close(sp.done)
+	sp.maintain()
+	ticker := time.NewTicker(sp.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-sp.stop:
+			return
+		case <-ticker.C:
+			sp.maintain()
+		}
+	}
+}
+
+// maintain checks each idle session once, which pings it or drops it, and
+// opens sessions until the pool has its minimum size.
+func (sp *sessionPool) maintain() {
+	ctx, cancel := context.WithTimeout(context.Background(), sp.interval)
+	defer cancel()
+	go func() {
+		select {
+		case <-sp.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for n := sp.pool.Len(); n > 0; n-- {
+		s, err := sp.pool.Get(ctx)
+		if err != nil {
+			return
+		}
+		sp.pool.Put(s)
+	}
+
+	var held []*pooledSession
+	for int(sp.open.Load()) < sp.minSize && len(held) < sp.minSize {
+		s, err := sp.pool.Get(ctx)
+		if err != nil {
+			if !errors.Is(err, pool.ErrPoolClosed) && ctx.Err() == nil {
+				logging.GetLogger().Warn("Failed to open a warm MCP session", "error", err)
+			}
+			break
+		}
+		held = append(held, s)
+	}
+	for _, s := range held {
+		sp.pool.Put(s)
+	}
+}
+
+// close stops the keeper and closes the idle sessions. The sessions in use
+// are closed when they are returned.
+func (sp *sessionPool) close() {
+	sp.stopOnce.Do(func() {
+		close(sp.stop)
+		<-sp.done
+		_ = sp.pool.Close()
+	})
+}
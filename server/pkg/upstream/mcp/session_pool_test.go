@@ -0,0 +1,159 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// pingableClientSession is a mock session that answers pings.
+type pingableClientSession struct {
+	*mockClientSession
+	ping func() error
+}
+
+func (s *pingableClientSession) Ping(_ context.Context, _ *mcp.PingParams) error {
+	return s.ping()
+}
+
+// poolTestUpstream counts the upstream sessions opened and closed, and
+// answers each tool call with the number of the session that served it.
+type poolTestUpstream struct {
+	opened, closed atomic.Int32
+	pingFails      atomic.Bool
+}
+
+func (u *poolTestUpstream) connect(_ context.Context) (ClientSession, error) {
+	n := u.opened.Add(1)
+	return &pingableClientSession{
+		mockClientSession: &mockClientSession{
+			callToolFunc: func(_ context.Context, _ *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+				return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprint(n)}}}, nil
+			},
+			closeFunc: func() error {
+				u.closed.Add(1)
+				return nil
+			},
+		},
+		ping: func() error {
+			if u.pingFails.Load() {
+				return errors.New("no pong")
+			}
+			return nil
+		},
+	}, nil
+}
+
+func TestSessionPool_NotConfigured(t *testing.T) {
+	sp, err := newSessionPool(nil, (&poolTestUpstream{}).connect)
+	require.NoError(t, err)
+	assert.Nil(t, sp)
+}
+
+func TestSessionPool_WarmStart(t *testing.T) {
+	upstream := &poolTestUpstream{}
+	sp, err := newSessionPool(configv1.ConnectionPoolConfig_builder{
+		MaxConnections: proto.Int32(4),
+		MinConnections: proto.Int32(2),
+	}.Build(), upstream.connect)
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return upstream.opened.Load() == 2 }, 5*time.Second, 10*time.Millisecond,
+		"the minimum of sessions is opened before the first call")
+
+	conn := &mcpConnection{pool: sp}
+	for i := 0; i < 3; i++ {
+		res, err := conn.CallTool(context.Background(), &mcp.CallToolParams{Name: "echo"})
+		require.NoError(t, err)
+		assert.Contains(t, []string{"1", "2"}, res.Content[0].(*mcp.TextContent).Text)
+	}
+	assert.Equal(t, int32(2), upstream.opened.Load(), "the calls reuse the warm sessions")
+	assert.Equal(t, int32(0), upstream.closed.Load())
+
+	sp.close()
+	assert.Equal(t, int32(2), upstream.closed.Load())
+}
+
+func TestSessionPool_IdleTimeout(t *testing.T) {
+	upstream := &poolTestUpstream{}
+	sp, err := newSessionPool(configv1.ConnectionPoolConfig_builder{
+		IdleTimeout: durationpb.New(20 * time.Millisecond),
+	}.Build(), upstream.connect)
+	require.NoError(t, err)
+	defer sp.close()
+
+	s, err := sp.get(context.Background())
+	require.NoError(t, err)
+	sp.put(s, nil)
+	time.Sleep(50 * time.Millisecond)
+
+	s, err = sp.get(context.Background())
+	require.NoError(t, err)
+	sp.put(s, nil)
+	assert.Equal(t, int32(2), upstream.opened.Load())
+	assert.Equal(t, int32(1), upstream.closed.Load(), "the idle session is replaced")
+}
+
+func TestSessionPool_HealthPing(t *testing.T) {
+	upstream := &poolTestUpstream{}
+	sp, err := newSessionPool(configv1.ConnectionPoolConfig_builder{
+		MinConnections:      proto.Int32(1),
+		HealthCheckInterval: durationpb.New(20 * time.Millisecond),
+	}.Build(), upstream.connect)
+	require.NoError(t, err)
+	defer sp.close()
+	require.Eventually(t, func() bool { return upstream.opened.Load() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	upstream.pingFails.Store(true)
+	assert.Eventually(t, func() bool { return upstream.closed.Load() >= 1 }, 5*time.Second, 10*time.Millisecond,
+		"a session that does not answer pings is closed")
+	upstream.pingFails.Store(false)
+	assert.Eventually(t, func() bool { return upstream.opened.Load()-upstream.closed.Load() == 1 }, 5*time.Second, 10*time.Millisecond,
+		"and replaced to keep the pool warm")
+}
+
+func TestSessionPool_DropsBrokenSessions(t *testing.T) {
+	upstream := &poolTestUpstream{}
+	sp, err := newSessionPool(configv1.ConnectionPoolConfig_builder{}.Build(), upstream.connect)
+	require.NoError(t, err)
+	defer sp.close()
+
+	s, err := sp.get(context.Background())
+	require.NoError(t, err)
+	sp.put(s, fmt.Errorf("calling tool: %w", mcp.ErrConnectionClosed))
+
+	s, err = sp.get(context.Background())
+	require.NoError(t, err)
+	sp.put(s, nil)
+	assert.Equal(t, int32(2), upstream.opened.Load())
+	assert.Equal(t, int32(1), upstream.closed.Load())
+}
+
+func TestUpstream_CheckHealthPings(t *testing.T) {
+	upstream := &poolTestUpstream{}
+	sp, err := newSessionPool(configv1.ConnectionPoolConfig_builder{}.Build(), upstream.connect)
+	require.NoError(t, err)
+	defer sp.close()
+	u := &Upstream{
+		ping: configv1.McpHealthCheck_builder{Timeout: durationpb.New(time.Second)}.Build(),
+		conn: &mcpConnection{pool: sp},
+	}
+
+	require.NoError(t, u.CheckHealth(context.Background()))
+	upstream.pingFails.Store(true)
+	assert.ErrorContains(t, u.CheckHealth(context.Background()), "mcp ping failed: no pong")
+	assert.Equal(t, int32(1), upstream.opened.Load(), "the pings use the sessions of the pool")
+}
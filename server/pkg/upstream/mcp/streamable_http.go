@@ -15,6 +15,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"al.essio.dev/pkg/shellescape"
 	"github.com/alexliesenfeld/health"
@@ -22,6 +23,7 @@ import (
 	v1 "github.com/mcpany/core/proto/mcp_router/v1"
 	"github.com/mcpany/core/server/pkg/auth"
 	"github.com/mcpany/core/server/pkg/client"
+	"github.com/mcpany/core/server/pkg/federation"
 	mcphealth "github.com/mcpany/core/server/pkg/health"
 	"github.com/mcpany/core/server/pkg/logging"
 	"github.com/mcpany/core/server/pkg/prompt"
@@ -30,6 +32,7 @@ import (
 	"github.com/mcpany/core/server/pkg/upstream"
 	"github.com/mcpany/core/server/pkg/util"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/structpb"
 )
@@ -163,6 +166,11 @@ type Upstream struct {
 	mu        sync.RWMutex
 	serviceID string
 	checker   health.Checker
+	affinity  *sessionAffinity
+	pool      *sessionPool
+	// ping probes the service with MCP pings over conn, if configured.
+	ping *configv1.McpHealthCheck
+	conn *mcpConnection
 }
 
 // CheckHealth performs a health check on the upstream service.
@@ -181,8 +189,13 @@ type Upstream struct {
 func (u *Upstream) CheckHealth(ctx context.Context) error {
 	u.mu.RLock()
 	checker := u.checker
+	ping := u.ping
+	conn := u.conn
 	u.mu.RUnlock()
 
+	if ping != nil && conn != nil {
+		return pingUpstream(ctx, conn, ping)
+	}
 	if checker != nil {
 		res := checker.Check(ctx)
 		if res.Status != health.StatusUp {
@@ -193,6 +206,29 @@ func (u *Upstream) CheckHealth(ctx context.Context) error {
 	return nil
 }
 
+// pingUpstream sends an MCP ping to the service, over a session of the
+// connection, within the timeout of the health check.
+func pingUpstream(ctx context.Context, conn *mcpConnection, hc *configv1.McpHealthCheck) error {
+	timeout := 5 * time.Second
+	if hc.GetTimeout() != nil {
+		timeout = hc.GetTimeout().AsDuration()
+	}
+	pingCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return conn.withMCPClientSession(pingCtx, func(cs ClientSession) error {
+		pinger, ok := cs.(interface {
+			Ping(ctx context.Context, params *mcp.PingParams) error
+		})
+		if !ok {
+			return nil
+		}
+		if err := pinger.Ping(pingCtx, nil); err != nil {
+			return fmt.Errorf("mcp ping failed: %w", err)
+		}
+		return nil
+	})
+}
+
 // Shutdown cleans up any temporary resources associated with the upstream, such
 // as extracted bundle directories.
 //
@@ -211,6 +247,8 @@ func (u *Upstream) Shutdown(_ context.Context) error {
 	u.mu.RLock()
 	serviceID := u.serviceID
 	checker := u.checker
+	affinity := u.affinity
+	sessionPool := u.pool
 	u.mu.RUnlock()
 
 	if checker != nil {
@@ -218,6 +256,12 @@ func (u *Upstream) Shutdown(_ context.Context) error {
 			c.Stop()
 		}
 	}
+	if affinity != nil {
+		affinity.close()
+	}
+	if sessionPool != nil {
+		sessionPool.close()
+	}
 
 	if serviceID != "" {
 		untrackBundle(serviceID)
@@ -232,6 +276,36 @@ func (u *Upstream) Shutdown(_ context.Context) error {
 	return nil
 }
 
+// sessionAffinity returns the sticky sessions of the upstream, or nil if
+// session affinity is not enabled.
+func (u *Upstream) sessionAffinity() *sessionAffinity {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.affinity
+}
+
+// attachSessionPool gives a connection the pool of sessions configured for
+// the service, if any. The pool is closed with the upstream. The connection
+// also carries the MCP pings of the health check.
+func (u *Upstream) attachSessionPool(conn *mcpConnection, cfg *configv1.ConnectionPoolConfig) error {
+	u.mu.Lock()
+	u.conn = conn
+	u.mu.Unlock()
+	sp, err := newSessionPool(cfg, conn.connect)
+	if err != nil || sp == nil {
+		return err
+	}
+	conn.pool = sp
+	u.mu.Lock()
+	old := u.pool
+	u.pool = sp
+	u.mu.Unlock()
+	if old != nil {
+		old.close()
+	}
+	return nil
+}
+
 // NewUpstream creates a new instance of Upstream.
 //
 // Parameters:
@@ -492,7 +566,18 @@ func (u *Upstream) Register(
 	}
 	u.checker = mcphealth.NewChecker(serviceConfig)
 	u.serviceID = serviceID
+	if u.affinity != nil {
+		u.affinity.close()
+	}
+	u.affinity = newSessionAffinity(serviceConfig.GetMcpService().GetSessionAffinity(), u.sessionRegistry)
+	u.ping = serviceConfig.GetMcpService().GetHealthCheck()
+	u.conn = nil
+	oldPool := u.pool
+	u.pool = nil
 	u.mu.Unlock()
+	if oldPool != nil {
+		oldPool.close()
+	}
 
 	// Track bundle potential usage early to prevent GC race during setup
 	trackBundle(serviceID)
@@ -554,12 +639,80 @@ type mcpConnection struct {
 	httpClient      *http.Client
 	sessionRegistry *SessionRegistry
 	globalSettings  *configv1.GlobalSettings
+	// affinity keeps an upstream session per client session, if enabled.
+	affinity *sessionAffinity
+	// pool keeps upstream sessions open between calls, if configured.
+	pool *sessionPool
 }
 
 // withMCPClientSession is a helper function that abstracts the process of
 // establishing a connection to the downstream MCP service, executing a function
-// with the active session, and ensuring the session is closed afterward.
+// with the active session, and ensuring the session is closed afterward. With
+// session affinity, the calls of a client session share an upstream session
+// that is kept open instead; with a connection pool, the calls take a pooled
+// session.
 func (c *mcpConnection) withMCPClientSession(ctx context.Context, f func(cs ClientSession) error) error {
+	if c.affinity != nil {
+		if downstream, ok := tool.GetSession(ctx); ok {
+			s, ok, err := c.affinity.acquire(ctx, downstream, c.connect)
+			if err != nil {
+				return err
+			}
+			if ok {
+				err = f(s.cs)
+				c.affinity.release(s, err)
+				return err
+			}
+		}
+	}
+
+	if c.pool != nil {
+		s, err := c.pool.get(ctx)
+		if err != nil {
+			return err
+		}
+		unregister := c.registerDownstream(ctx, s.cs)
+		err = f(s.cs)
+		unregister()
+		c.pool.put(s, err)
+		return err
+	}
+
+	cs, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	unregister := c.registerDownstream(ctx, cs)
+	defer func() {
+		unregister()
+		_ = cs.Close()
+	}()
+
+	return f(cs)
+}
+
+// registerDownstream maps an upstream session to the downstream session of
+// the call, if any, so that the requests of the upstream (like sampling) are
+// routed to the right client. It returns the function that removes the
+// mapping.
+func (c *mcpConnection) registerDownstream(ctx context.Context, cs ClientSession) func() {
+	if c.sessionRegistry == nil {
+		return func() {}
+	}
+	downstreamSession, ok := tool.GetSession(ctx)
+	if !ok {
+		return func() {}
+	}
+	mcpSession, ok := cs.(mcp.Session)
+	if !ok {
+		return func() {}
+	}
+	c.sessionRegistry.Register(mcpSession, downstreamSession)
+	return func() { c.sessionRegistry.Unregister(mcpSession) }
+}
+
+// connect opens a session with the downstream MCP service.
+func (c *mcpConnection) connect(ctx context.Context) (ClientSession, error) {
 	var transport mcp.Transport
 	switch {
 	case c.stdioConfig != nil:
@@ -570,7 +723,7 @@ func (c *mcpConnection) withMCPClientSession(ctx context.Context, f func(cs Clie
 					StdioConfig: c.stdioConfig,
 				}
 			} else {
-				return fmt.Errorf("docker socket not accessible, but container_image is specified")
+				return nil, fmt.Errorf("docker socket not accessible, but container_image is specified")
 			}
 		} else {
 			// We need global settings here if we want to support sudo.
@@ -581,7 +734,7 @@ func (c *mcpConnection) withMCPClientSession(ctx context.Context, f func(cs Clie
 			}
 			cmd, err := buildCommandFromStdioConfig(ctx, c.stdioConfig, useSudo)
 			if err != nil {
-				return fmt.Errorf("failed to build command from stdio config: %w", err)
+				return nil, fmt.Errorf("failed to build command from stdio config: %w", err)
 			}
 			transport = &StdioTransport{
 				Command: cmd,
@@ -595,7 +748,7 @@ func (c *mcpConnection) withMCPClientSession(ctx context.Context, f func(cs Clie
 			HTTPClient: c.httpClient,
 		}
 	default:
-		return fmt.Errorf("mcp transport is not configured")
+		return nil, fmt.Errorf("mcp transport is not configured")
 	}
 
 	var cs ClientSession
@@ -606,28 +759,9 @@ func (c *mcpConnection) withMCPClientSession(ctx context.Context, f func(cs Clie
 		cs, err = c.client.Connect(ctx, transport, nil)
 	}
 	if err != nil {
-		return fmt.Errorf("failed to connect to MCP server: %w", err)
-	}
-	defer func() {
-		// Unregister session if registry is present
-		if c.sessionRegistry != nil {
-			if mcpSession, ok := cs.(mcp.Session); ok {
-				c.sessionRegistry.Unregister(mcpSession)
-			}
-		}
-		_ = cs.Close()
-	}()
-
-	// Register session if downstream session is available in context and registry is present
-	if c.sessionRegistry != nil {
-		if downstreamSession, ok := tool.GetSession(ctx); ok {
-			if mcpSession, ok := cs.(mcp.Session); ok {
-				c.sessionRegistry.Register(mcpSession, downstreamSession)
-			}
-		}
+		return nil, fmt.Errorf("failed to connect to MCP server: %w", err)
 	}
-
-	return f(cs)
+	return cs, nil
 }
 
 // CallTool executes a tool on the downstream MCP service by establishing a
@@ -848,6 +982,10 @@ func (u *Upstream) createAndRegisterMCPItemsFromStdio(
 			stdioConfig:     stdio,
 			sessionRegistry: u.sessionRegistry,
 			globalSettings:  u.globalSettings,
+			affinity:        u.sessionAffinity(),
+		}
+		if err := u.attachSessionPool(conn, serviceConfig.GetConnectionPool()); err != nil {
+			return nil, nil, err
 		}
 		toolClient = conn
 		promptConnection = conn
@@ -932,6 +1070,14 @@ func (u *Upstream) registerTools(
 			continue
 		}
 
+		// A tool pinned by its hash is not registered once the upstream changes it.
+		if hasConfig {
+			if err := tool.VerifyMCPToolIntegrity(mcpSDKTool, configTool.GetIntegrity()); err != nil {
+				logging.GetLogger().Error("Skipping tool that changed since it was pinned", "toolName", mcpSDKTool.Name, "error", err)
+				continue
+			}
+		}
+
 		callDef := configv1.MCPCallDefinition_builder{}.Build()
 		if hasConfig {
 			if call, callOk := calls[configTool.GetCallId()]; callOk {
@@ -1196,12 +1342,24 @@ func (u *Upstream) createAndRegisterMCPItemsFromStreamableHTTP(
 			return http.ErrUseLastResponse
 		}
 	}
-	httpClient.Transport = &authenticatedRoundTripper{
+	httpAddress := httpConnection.GetHttpAddress()
+	if peer := serviceConfig.GetMcpService().GetPeer(); peer != nil {
+		peerTransport, err := federation.NewTransport(httpAddress, peer, httpClient.Transport)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to configure mcpany peer: %w", err)
+		}
+		if peerTransport.Authenticates() {
+			// The peer credentials replace upstream_auth.
+			authenticator = nil
+		}
+		httpClient.Transport = peerTransport
+	}
+	// Trace upstream calls and propagate the trace context.
+	httpClient.Transport = otelhttp.NewTransport(&authenticatedRoundTripper{
 		authenticator: authenticator,
 		base:          httpClient.Transport,
-	}
+	})
 
-	httpAddress := httpConnection.GetHttpAddress()
 	if httpAddress == "" {
 		return nil, nil, fmt.Errorf("mcp http service address is required")
 	}
@@ -1267,6 +1425,10 @@ func (u *Upstream) createAndRegisterMCPItemsFromStreamableHTTP(
 			httpAddress:     httpAddress,
 			httpClient:      httpClient,
 			sessionRegistry: u.sessionRegistry,
+			affinity:        u.sessionAffinity(),
+		}
+		if err := u.attachSessionPool(conn, serviceConfig.GetConnectionPool()); err != nil {
+			return nil, nil, err
 		}
 		toolClient = conn
 		promptConnection = conn
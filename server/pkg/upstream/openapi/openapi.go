@@ -7,6 +7,7 @@ package openapi
 import (
 	"context"
 	"crypto/sha256"
+	"crypto/tls"
 	"encoding/hex"
 	"fmt"
 	"io"
@@ -28,6 +29,7 @@ import (
 	"github.com/mcpany/core/server/pkg/tool"
 	"github.com/mcpany/core/server/pkg/upstream"
 	"github.com/mcpany/core/server/pkg/util"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -37,6 +39,7 @@ import (
 type OpenAPIUpstream struct { //nolint:revive
 	openapiCache *ttlcache.Cache[string, *openapi3.T]
 	httpClients  map[string]*http.Client
+	tlsConfigs   map[string]*tls.Config
 	mu           sync.Mutex
 	serviceID    string
 }
@@ -83,6 +86,7 @@ func NewOpenAPIUpstream() upstream.Upstream {
 	return &OpenAPIUpstream{
 		openapiCache: cache,
 		httpClients:  make(map[string]*http.Client),
+		tlsConfigs:   make(map[string]*tls.Config),
 	}
 }
 
@@ -149,6 +153,19 @@ func (u *OpenAPIUpstream) Register(
 		}
 	}
 
+	tlsConfig, err := util.NewTLSClientConfig(openapiService.GetTlsConfig())
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("invalid tls_config for openapi service %s: %w", serviceID, err)
+	}
+	u.mu.Lock()
+	// Drop the cached client so a reload picks up changed TLS settings.
+	if client, ok := u.httpClients[serviceID]; ok {
+		client.CloseIdleConnections()
+		delete(u.httpClients, serviceID)
+	}
+	u.tlsConfigs[serviceID] = tlsConfig
+	u.mu.Unlock()
+
 	info := &tool.ServiceInfo{
 		Name:   serviceConfig.GetName(),
 		Config: serviceConfig,
@@ -265,6 +282,7 @@ func (u *OpenAPIUpstream) getHTTPClient(serviceID string) *http.Client {
 	}
 
 	transport := &http.Transport{
+		TLSClientConfig:     u.tlsConfigs[serviceID],
 		DialContext:         dialer.DialContext,
 		MaxIdleConns:        100,
 		MaxIdleConnsPerHost: 10,
@@ -272,7 +290,8 @@ func (u *OpenAPIUpstream) getHTTPClient(serviceID string) *http.Client {
 	}
 
 	client := &http.Client{
-		Transport: transport,
+		// Trace upstream calls and propagate the trace context.
+		Transport: otelhttp.NewTransport(transport),
 		Timeout:   30 * time.Second,
 	}
 
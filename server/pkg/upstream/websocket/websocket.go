@@ -163,7 +163,11 @@ func (u *Upstream) Register(
 	}
 
 	address := websocketService.GetAddress()
-	wsPool, err := NewPool(10, 300*time.Second, address)
+	tlsConfig, err := util.NewTLSClientConfig(websocketService.GetTlsConfig())
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("invalid tls_config for websocket service %s: %w", serviceID, err)
+	}
+	wsPool, err := NewPool(10, 300*time.Second, address, tlsConfig)
 	if err != nil {
 		return "", nil, nil, fmt.Errorf("failed to create websocket pool for %s: %w", serviceID, err)
 	}
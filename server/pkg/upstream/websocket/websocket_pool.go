@@ -5,6 +5,7 @@ package websocket
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"time"
 
@@ -25,13 +26,16 @@ type Pool = pool.Pool[*client.WebsocketClientWrapper]
 //   - maxSize: The maximum number of connections the pool can hold.
 //   - idleTimeout: The duration after which an idle connection may be closed.
 //   - address: The target URL of the WebSocket server.
+//   - tlsConfig: The TLS configuration for wss:// connections, or nil for the defaults.
 //
 // Returns:
 //   - Pool: A new WebSocket client pool.
 //   - error: An error if the pool cannot be created.
-func NewPool(maxSize int, idleTimeout time.Duration, address string) (Pool, error) {
+func NewPool(maxSize int, idleTimeout time.Duration, address string, tlsConfig *tls.Config) (Pool, error) {
+	dialer := *websocket.DefaultDialer
+	dialer.TLSClientConfig = tlsConfig
 	factory := func(_ context.Context) (*client.WebsocketClientWrapper, error) {
-		conn, resp, err := websocket.DefaultDialer.Dial(address, nil)
+		conn, resp, err := dialer.Dial(address, nil)
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect to websocket server %s: %w", address, err)
 		}
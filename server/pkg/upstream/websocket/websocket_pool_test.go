@@ -41,7 +41,7 @@ func TestNewPool(t *testing.T) {
 		defer server.Close()
 		wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
 
-		pool, err := NewPool(5, 10*time.Second, wsURL)
+		pool, err := NewPool(5, 10*time.Second, wsURL, nil)
 		require.NoError(t, err)
 		assert.NotNil(t, pool)
 		defer func() { _ = pool.Close() }()
@@ -55,7 +55,7 @@ func TestNewPool(t *testing.T) {
 	})
 
 	t.Run("invalid address", func(t *testing.T) {
-		pool, err := NewPool(5, 10*time.Second, "invalid-address")
+		pool, err := NewPool(5, 10*time.Second, "invalid-address", nil)
 		require.NoError(t, err)
 		assert.NotNil(t, pool)
 		defer func() { _ = pool.Close() }()
@@ -68,7 +68,7 @@ func TestNewPool(t *testing.T) {
 	t.Run("connection failure", func(t *testing.T) {
 		// Use a port that is not listening
 		wsURL := "ws://127.0.0.1:9999"
-		pool, err := NewPool(5, 10*time.Second, wsURL)
+		pool, err := NewPool(5, 10*time.Second, wsURL, nil)
 		require.NoError(t, err)
 		assert.NotNil(t, pool)
 		defer func() { _ = pool.Close() }()
@@ -83,7 +83,7 @@ func TestNewPool(t *testing.T) {
 		defer server.Close()
 		wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
 
-		_, err := NewPool(0, 10*time.Second, wsURL)
+		_, err := NewPool(0, 10*time.Second, wsURL, nil)
 		require.Error(t, err)
 	})
 }
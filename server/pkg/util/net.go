@@ -200,6 +200,21 @@ func SafeDialContext(ctx context.Context, network, addr string) (net.Conn, error
 // Returns:
 //   - (*http.Client): A configured HTTP client.
 func NewSafeHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			DialContext: NewEnvSafeDialer().DialContext,
+		},
+	}
+}
+
+// NewEnvSafeDialer creates the SafeDialer of NewSafeHTTPClient, which
+// allows the local addresses enabled by the environment variables listed
+// there.
+//
+// Returns:
+//   - (*SafeDialer): The dialer.
+func NewEnvSafeDialer() *SafeDialer {
 	dialer := NewSafeDialer()
 	if os.Getenv("MCPANY_DANGEROUS_ALLOW_LOCAL_IPS") == TrueStr {
 		dialer.AllowLoopback = true
@@ -212,13 +227,7 @@ func NewSafeHTTPClient() *http.Client {
 		dialer.AllowPrivate = true
 	}
 	// LinkLocal is always blocked by default and cannot be enabled via env var for now (safest default).
-
-	return &http.Client{
-		Timeout: 10 * time.Second,
-		Transport: &http.Transport{
-			DialContext: dialer.DialContext,
-		},
-	}
+	return dialer
 }
 
 // CheckConnection verifies if a TCP connection can be established to the given address.
@@ -103,6 +103,7 @@ func SanitizeAuthentication(a *configv1.Authentication) *configv1.Authentication
 		if oa := a.GetOauth2(); oa != nil {
 			oa.SetClientSecret(SanitizeSecretValue(oa.GetClientSecret()))
 			oa.SetClientId(SanitizeSecretValue(oa.GetClientId()))
+			oa.SetRefreshToken(SanitizeSecretValue(oa.GetRefreshToken()))
 		}
 	case configv1.Authentication_TrustedHeader_case:
 		if th := a.GetTrustedHeader(); th != nil && th.GetHeaderValue() != "" {
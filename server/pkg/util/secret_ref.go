@@ -0,0 +1,89 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package util //nolint:revive,nolintlint // Package name 'util' is common in this codebase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// SecretRefResolver resolves the secret references of one scheme.
+//
+// Parameters:
+//   - ctx: The context of the resolution.
+//   - path: The path of the secret, e.g. "secret/data/my-app/db".
+//   - key: The key of the value within the secret.
+//
+// Returns:
+//   - string: The secret value.
+//   - error: An error if the secret cannot be read.
+type SecretRefResolver func(ctx context.Context, path, key string) (string, error)
+
+var (
+	secretRefResolversMu sync.RWMutex
+	secretRefResolvers   = map[string]SecretRefResolver{}
+)
+
+// SetSecretRefResolver installs the resolver of the secret references with
+// the scheme, e.g. "vault". Passing nil removes it.
+//
+// Parameters:
+//   - scheme: The scheme of the references.
+//   - resolver: The resolver, or nil.
+//
+// Side Effects:
+//   - Replaces the process-wide resolver of the scheme.
+func SetSecretRefResolver(scheme string, resolver SecretRefResolver) {
+	secretRefResolversMu.Lock()
+	defer secretRefResolversMu.Unlock()
+	if resolver == nil {
+		delete(secretRefResolvers, scheme)
+		return
+	}
+	secretRefResolvers[scheme] = resolver
+}
+
+// ParseSecretRef splits a secret reference of the form "scheme:path#key".
+// The key is optional.
+//
+// Parameters:
+//   - ref: The reference, e.g. "vault:secret/data/my-app/db#password".
+//
+// Returns:
+//   - string: The scheme.
+//   - string: The path.
+//   - string: The key, or empty.
+//   - error: An error if the scheme or path is missing.
+func ParseSecretRef(ref string) (string, string, string, error) {
+	scheme, rest, ok := strings.Cut(ref, ":")
+	if !ok || scheme == "" {
+		return "", "", "", fmt.Errorf("invalid secret reference %q: expected scheme:path#key", ref)
+	}
+	path, key, hasKey := strings.Cut(rest, "#")
+	path = strings.Trim(path, "/")
+	if path == "" || (hasKey && key == "") {
+		return "", "", "", fmt.Errorf("invalid secret reference %q: expected %s:path#key", ref, scheme)
+	}
+	return scheme, path, key, nil
+}
+
+func resolveSecretRef(ctx context.Context, ref string) (string, error) {
+	scheme, path, key, err := ParseSecretRef(ref)
+	if err != nil {
+		return "", err
+	}
+	secretRefResolversMu.RLock()
+	resolver := secretRefResolvers[scheme]
+	secretRefResolversMu.RUnlock()
+	if resolver == nil {
+		return "", fmt.Errorf("no secrets backend is configured for %q references", scheme)
+	}
+	value, err := resolver(ctx, path, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve secret reference %s: %w", ref, err)
+	}
+	return strings.TrimSpace(value), nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package util //nolint:revive
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParseSecretRef(t *testing.T) {
+	scheme, path, key, err := ParseSecretRef("vault:/secret/data/foo/#key")
+	require.NoError(t, err)
+	assert.Equal(t, "vault", scheme)
+	assert.Equal(t, "secret/data/foo", path)
+	assert.Equal(t, "key", key)
+
+	scheme, path, key, err = ParseSecretRef("aws-sm:arn:aws:secretsmanager:us-east-1:123456789012:secret:api")
+	require.NoError(t, err)
+	assert.Equal(t, "aws-sm", scheme)
+	assert.Equal(t, "arn:aws:secretsmanager:us-east-1:123456789012:secret:api", path)
+	assert.Empty(t, key)
+
+	for _, ref := range []string{"", "secret/data/foo#key", ":secret#key", "vault:", "vault:#key", "vault:secret#"} {
+		_, _, _, err := ParseSecretRef(ref)
+		assert.Error(t, err, ref)
+	}
+}
+
+func TestResolveSecret_SecretRef(t *testing.T) {
+	secret := configv1.SecretValue_builder{SecretRef: proto.String("test:app/db#password")}.Build()
+
+	_, err := ResolveSecret(context.Background(), secret)
+	assert.ErrorContains(t, err, "no secrets backend is configured")
+
+	SetSecretRefResolver("test", func(_ context.Context, path, key string) (string, error) {
+		if path == "app/db" && key == "password" {
+			return " s3cret\n", nil
+		}
+		return "", errors.New("not found")
+	})
+	t.Cleanup(func() { SetSecretRefResolver("test", nil) })
+
+	value, err := ResolveSecret(context.Background(), secret)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", value)
+
+	_, err = ResolveSecret(context.Background(), configv1.SecretValue_builder{SecretRef: proto.String("test:app/db#user")}.Build())
+	assert.ErrorContains(t, err, "not found")
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package util //nolint:revive,nolintlint // Package name 'util' is common in this codebase
+
+import (
+	"context"
+	"sync/atomic"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+)
+
+type secretConsumerKey struct{}
+
+// SecretUsageObserver is notified when ResolveSecret reads a secret by its
+// key: the environment variable name, the file path, the remote URL, the
+// secret reference, "vault:<path>#<key>" for Vault and
+// "aws:<secret_id>#<json_key>" for AWS Secrets Manager. Plain text values
+// are not reported.
+//
+// Parameters:
+//   - ctx: The context of the resolution, see SecretConsumerFromContext.
+//   - key: The key of the secret.
+type SecretUsageObserver func(ctx context.Context, key string)
+
+var secretUsageObserver atomic.Pointer[SecretUsageObserver]
+
+// SetSecretUsageObserver installs the observer notified of secret reads.
+// Passing nil removes it.
+//
+// Parameters:
+//   - observer: The observer, or nil.
+//
+// Side Effects:
+//   - Replaces the process-wide observer.
+func SetSecretUsageObserver(observer SecretUsageObserver) {
+	if observer == nil {
+		secretUsageObserver.Store(nil)
+		return
+	}
+	secretUsageObserver.Store(&observer)
+}
+
+// ContextWithSecretConsumer returns a context attributing the secrets
+// resolved with it to the consumer, e.g. "service:github".
+//
+// Parameters:
+//   - ctx: The parent context.
+//   - consumer: The consumer name.
+//
+// Returns:
+//   - context.Context: The derived context.
+func ContextWithSecretConsumer(ctx context.Context, consumer string) context.Context {
+	return context.WithValue(ctx, secretConsumerKey{}, consumer)
+}
+
+// SecretConsumerFromContext returns the consumer set by
+// ContextWithSecretConsumer.
+//
+// Parameters:
+//   - ctx: The context.
+//
+// Returns:
+//   - string: The consumer, empty if none is set.
+func SecretConsumerFromContext(ctx context.Context) string {
+	consumer, _ := ctx.Value(secretConsumerKey{}).(string)
+	return consumer
+}
+
+func notifySecretUsage(ctx context.Context, key string) {
+	if observer := secretUsageObserver.Load(); observer != nil {
+		(*observer)(ctx, key)
+	}
+}
+
+// SecretUsageKey returns the key under which a read of the secret value is
+// reported to the SecretUsageObserver.
+//
+// Parameters:
+//   - secret: The secret value.
+//
+// Returns:
+//   - string: The key, empty for plain text.
+func SecretUsageKey(secret *configv1.SecretValue) string {
+	switch secret.WhichValue() {
+	case configv1.SecretValue_EnvironmentVariable_case:
+		return secret.GetEnvironmentVariable()
+	case configv1.SecretValue_FilePath_case:
+		return secret.GetFilePath()
+	case configv1.SecretValue_RemoteContent_case:
+		return secret.GetRemoteContent().GetHttpUrl()
+	case configv1.SecretValue_Vault_case:
+		return refKey("vault", secret.GetVault().GetPath(), secret.GetVault().GetKey())
+	case configv1.SecretValue_AwsSecretManager_case:
+		return refKey("aws", secret.GetAwsSecretManager().GetSecretId(), secret.GetAwsSecretManager().GetJsonKey())
+	case configv1.SecretValue_SecretRef_case:
+		return secret.GetSecretRef()
+	default:
+		return ""
+	}
+}
+
+// refKey formats a key in the "scheme:path#key" form of secret references.
+func refKey(scheme, path, key string) string {
+	if key == "" {
+		return scheme + ":" + path
+	}
+	return scheme + ":" + path + "#" + key
+}
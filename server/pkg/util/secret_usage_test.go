@@ -0,0 +1,65 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestResolveSecret_NotifiesUsage(t *testing.T) {
+	var keys []string
+	SetSecretUsageObserver(func(_ context.Context, key string) { keys = append(keys, key) })
+	t.Cleanup(func() { SetSecretUsageObserver(nil) })
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(path, []byte("from-file"), 0o600))
+	validation.SetAllowedPaths([]string{dir})
+	t.Cleanup(func() { validation.SetAllowedPaths(nil) })
+
+	SetSecretRefResolver("usage", func(_ context.Context, _, _ string) (string, error) { return "from-ref", nil })
+	t.Cleanup(func() { SetSecretRefResolver("usage", nil) })
+
+	t.Setenv("SECRET_USAGE_NOTIFY_TEST", "from-env")
+
+	ctx := context.Background()
+	for _, secret := range []*configv1.SecretValue{
+		configv1.SecretValue_builder{EnvironmentVariable: proto.String("SECRET_USAGE_NOTIFY_TEST")}.Build(),
+		configv1.SecretValue_builder{FilePath: proto.String(path)}.Build(),
+		configv1.SecretValue_builder{SecretRef: proto.String("usage:team/api#token")}.Build(),
+		configv1.SecretValue_builder{PlainText: proto.String("plain")}.Build(),
+	} {
+		_, err := ResolveSecret(ctx, secret)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"SECRET_USAGE_NOTIFY_TEST", path, "usage:team/api#token"}, keys)
+}
+
+func TestSecretUsageKey(t *testing.T) {
+	vault := configv1.SecretValue_builder{Vault: configv1.VaultSecret_builder{
+		Path: proto.String("secret/data/app"),
+		Key:  proto.String("token"),
+	}.Build()}.Build()
+	assert.Equal(t, "vault:secret/data/app#token", SecretUsageKey(vault))
+
+	aws := configv1.SecretValue_builder{AwsSecretManager: configv1.AwsSecretManagerSecret_builder{
+		SecretId: proto.String("prod/app"),
+	}.Build()}.Build()
+	assert.Equal(t, "aws:prod/app", SecretUsageKey(aws))
+
+	remote := configv1.SecretValue_builder{RemoteContent: configv1.RemoteContent_builder{
+		HttpUrl: proto.String("https://secrets.example.com/token"),
+	}.Build()}.Build()
+	assert.Equal(t, "https://secrets.example.com/token", SecretUsageKey(remote))
+}
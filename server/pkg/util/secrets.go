@@ -29,7 +29,7 @@ const maxSecretRecursionDepth = 10
 
 // ResolveSecret resolves a SecretValue configuration object into a concrete string value.
 // It handles various secret types including plain text, environment variables, file paths,
-// remote URLs, Vault, and AWS Secrets Manager.
+// remote URLs, Vault, AWS Secrets Manager, and references to the configured secrets backend.
 //
 // Summary: Resolves a secret configuration into a string value.
 //
@@ -60,6 +60,9 @@ func resolveSecretRecursive(ctx context.Context, secret *configv1.SecretValue, d
 		}
 	}
 
+	if key := SecretUsageKey(secret); key != "" {
+		notifySecretUsage(ctx, key)
+	}
 	return val, nil
 }
 
@@ -293,6 +296,8 @@ func resolveSecretImpl(ctx context.Context, secret *configv1.SecretValue, depth
 		}
 
 		return strings.TrimSpace(secretVal), nil
+	case configv1.SecretValue_SecretRef_case:
+		return resolveSecretRef(ctx, secret.GetSecretRef())
 	default:
 		return "", nil
 	}
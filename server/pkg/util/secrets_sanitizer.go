@@ -7,6 +7,28 @@ import (
 	configv1 "github.com/mcpany/core/proto/config/v1"
 )
 
+// StripSecretsFromConfig removes sensitive information from the services,
+// collections, users and profiles of a configuration.
+//
+// Summary: Removes sensitive information from a whole configuration.
+//
+// Parameters:
+//   - cfg (*configv1.McpAnyServerConfig): The configuration to strip secrets from.
+func StripSecretsFromConfig(cfg *configv1.McpAnyServerConfig) {
+	for _, svc := range cfg.GetUpstreamServices() {
+		StripSecretsFromService(svc)
+	}
+	for _, collection := range cfg.GetCollections() {
+		StripSecretsFromCollection(collection)
+	}
+	for _, user := range cfg.GetUsers() {
+		StripSecretsFromAuth(user.GetAuthentication())
+	}
+	for _, profile := range cfg.GetGlobalSettings().GetProfileDefinitions() {
+		StripSecretsFromProfile(profile)
+	}
+}
+
 // StripSecretsFromService removes sensitive information from the service configuration.
 //
 // Summary: Removes sensitive information from service configuration.
@@ -125,6 +147,7 @@ func StripSecretsFromAuth(auth *configv1.Authentication) {
 	if oauth := auth.GetOauth2(); oauth != nil {
 		scrubSecretValue(oauth.GetClientSecret())
 		scrubSecretValue(oauth.GetClientId())
+		scrubSecretValue(oauth.GetRefreshToken())
 	}
 	// Add other auth types as needed
 }
@@ -236,6 +259,7 @@ func stripSecretsFromHook(h *configv1.CallHook) {
 	if wh := h.GetWebhook(); wh != nil {
 		// WebhookSecret is a string, clear it.
 		wh.SetWebhookSecret("")
+		wh.SetPreviousSecrets(nil)
 	}
 }
 
@@ -407,6 +431,7 @@ func hydrateSecretsInAuth(auth *configv1.Authentication, secrets map[string]*con
 	if oauth := auth.GetOauth2(); oauth != nil {
 		hydrateSecretValue(oauth.GetClientId(), secrets)
 		hydrateSecretValue(oauth.GetClientSecret(), secrets)
+		hydrateSecretValue(oauth.GetRefreshToken(), secrets)
 	}
 }
 
@@ -102,12 +102,14 @@ func TestStripSecretsFromHook(t *testing.T) {
 	// Webhook with secret
 	hook := configv1.CallHook_builder{
 		Webhook: configv1.WebhookConfig_builder{
-			Url:           "http://example.com",
-			WebhookSecret: "secret",
+			Url:             "http://example.com",
+			WebhookSecret:   "secret",
+			PreviousSecrets: []string{"old-secret"},
 		}.Build(),
 	}.Build()
 	stripSecretsFromHook(hook)
 	assert.Equal(t, "", hook.GetWebhook().GetWebhookSecret())
+	assert.Empty(t, hook.GetWebhook().GetPreviousSecrets())
 }
 
 func TestStripSecretsFromCacheConfig(t *testing.T) {
@@ -274,6 +276,36 @@ func TestStripSecretsFromCollection(t *testing.T) {
 	assert.False(t, svc.GetUpstreamAuth().GetBasicAuth().GetPassword().HasValue(), "Plain text secret should be cleared")
 }
 
+func TestStripSecretsFromConfig(t *testing.T) {
+	secret := func() *configv1.SecretValue {
+		return configv1.SecretValue_builder{PlainText: proto.String("secret-value")}.Build()
+	}
+	cfg := configv1.McpAnyServerConfig_builder{
+		GlobalSettings: configv1.GlobalSettings_builder{
+			ProfileDefinitions: []*configv1.ProfileDefinition{
+				configv1.ProfileDefinition_builder{
+					Name:    proto.String("dev"),
+					Secrets: map[string]*configv1.SecretValue{"TOKEN": secret()},
+				}.Build(),
+			},
+		}.Build(),
+		UpstreamServices: []*configv1.UpstreamServiceConfig{
+			configv1.UpstreamServiceConfig_builder{
+				Name: proto.String("svc"),
+				UpstreamAuth: configv1.Authentication_builder{
+					BasicAuth: configv1.BasicAuth_builder{Password: secret()}.Build(),
+				}.Build(),
+			}.Build(),
+		},
+	}.Build()
+
+	StripSecretsFromConfig(cfg)
+
+	assert.False(t, cfg.GetUpstreamServices()[0].GetUpstreamAuth().GetBasicAuth().GetPassword().HasValue())
+	assert.False(t, cfg.GetGlobalSettings().GetProfileDefinitions()[0].GetSecrets()["TOKEN"].HasValue())
+	StripSecretsFromConfig(nil)
+}
+
 func TestHydrateSecretsInService(t *testing.T) {
 	svc := configv1.UpstreamServiceConfig_builder{
 		Name: proto.String("test-service"),
@@ -9,9 +9,12 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sync"
+	"time"
 
 	configv1 "github.com/mcpany/core/proto/config/v1"
 	"github.com/mcpany/core/server/pkg/validation"
+	"google.golang.org/protobuf/proto"
 )
 
 // NewHTTPClientWithTLS creates a new *http.Client configured with the specified
@@ -29,40 +32,10 @@ import (
 //   - error: An error if the TLS configuration is invalid or files cannot be read.
 func NewHTTPClientWithTLS(tlsConfig *configv1.TLSConfig) (*http.Client, error) {
 	var tlsClientConfig *tls.Config
-
 	if tlsConfig != nil {
-		tlsClientConfig = &tls.Config{
-			ServerName:         tlsConfig.GetServerName(),
-			InsecureSkipVerify: tlsConfig.GetInsecureSkipVerify(), //nolint:gosec
-		}
-
-		if tlsConfig.GetCaCertPath() != "" {
-			if err := validation.IsSecurePath(tlsConfig.GetCaCertPath()); err != nil {
-				return nil, fmt.Errorf("invalid CA certificate path: %w", err)
-			}
-			caCert, err := os.ReadFile(tlsConfig.GetCaCertPath())
-			if err != nil {
-				return nil, fmt.Errorf("failed to read CA certificate: %w", err)
-			}
-			caCertPool := x509.NewCertPool()
-			if ok := caCertPool.AppendCertsFromPEM(caCert); !ok {
-				return nil, fmt.Errorf("failed to append CA certs from PEM")
-			}
-			tlsClientConfig.RootCAs = caCertPool
-		}
-
-		if tlsConfig.GetClientCertPath() != "" && tlsConfig.GetClientKeyPath() != "" {
-			if err := validation.IsSecurePath(tlsConfig.GetClientCertPath()); err != nil {
-				return nil, fmt.Errorf("invalid client certificate path: %w", err)
-			}
-			if err := validation.IsSecurePath(tlsConfig.GetClientKeyPath()); err != nil {
-				return nil, fmt.Errorf("invalid client key path: %w", err)
-			}
-			clientCert, err := tls.LoadX509KeyPair(tlsConfig.GetClientCertPath(), tlsConfig.GetClientKeyPath())
-			if err != nil {
-				return nil, fmt.Errorf("failed to load client key pair: %w", err)
-			}
-			tlsClientConfig.Certificates = []tls.Certificate{clientCert}
+		var err error
+		if tlsClientConfig, err = NewTLSClientConfig(tlsConfig); err != nil {
+			return nil, err
 		}
 	}
 
@@ -88,3 +61,224 @@ func NewHTTPClientWithTLS(tlsConfig *configv1.TLSConfig) (*http.Client, error) {
 		Transport: transport,
 	}, nil
 }
+
+// NewTLSClientConfig builds the client-side *tls.Config for an upstream
+// connection from its TLS settings: the SNI server name, the CA bundle used
+// to verify the server, the client certificate presented for mTLS and the
+// minimum protocol version.
+//
+// The client certificate is re-read from disk when its files change, so a
+// rotated certificate is used for the next handshake without a restart.
+//
+// Parameters:
+//   - tlsConfig: The TLS settings of the upstream service.
+//
+// Returns:
+//   - *tls.Config: The TLS configuration, never nil.
+//   - error: An error if a file cannot be read or the settings are inconsistent.
+func NewTLSClientConfig(tlsConfig *configv1.TLSConfig) (*tls.Config, error) {
+	minVersion, err := tlsMinVersion(tlsConfig.GetMinVersion())
+	if err != nil {
+		return nil, err
+	}
+	tlsClientConfig := &tls.Config{
+		ServerName:         tlsConfig.GetServerName(),
+		InsecureSkipVerify: tlsConfig.GetInsecureSkipVerify(), //nolint:gosec
+		MinVersion:         minVersion,
+	}
+
+	if tlsConfig.GetCaCertPath() != "" {
+		if err := validation.IsSecurePath(tlsConfig.GetCaCertPath()); err != nil {
+			return nil, fmt.Errorf("invalid CA certificate path: %w", err)
+		}
+		caCert, err := os.ReadFile(tlsConfig.GetCaCertPath())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+		caCertPool := x509.NewCertPool()
+		if ok := caCertPool.AppendCertsFromPEM(caCert); !ok {
+			return nil, fmt.Errorf("failed to append CA certs from PEM")
+		}
+		tlsClientConfig.RootCAs = caCertPool
+	}
+
+	certPath, keyPath := tlsConfig.GetClientCertPath(), tlsConfig.GetClientKeyPath()
+	if (certPath == "") != (keyPath == "") {
+		return nil, fmt.Errorf("client_cert_path and client_key_path must be set together")
+	}
+	if certPath != "" {
+		if err := validation.IsSecurePath(certPath); err != nil {
+			return nil, fmt.Errorf("invalid client certificate path: %w", err)
+		}
+		if err := validation.IsSecurePath(keyPath); err != nil {
+			return nil, fmt.Errorf("invalid client key path: %w", err)
+		}
+		reloader := &certReloader{certPath: certPath, keyPath: keyPath}
+		clientCert, err := reloader.load()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client key pair: %w", err)
+		}
+		tlsClientConfig.Certificates = []tls.Certificate{*clientCert}
+		tlsClientConfig.GetClientCertificate = reloader.GetClientCertificate
+	}
+	return tlsClientConfig, nil
+}
+
+// NewTLSServerConfig builds the server-side *tls.Config of a listener from a
+// certificate and key file and, for client certificate authentication, a CA
+// bundle the client certificates must chain to.
+//
+// The certificate is re-read from disk when its files change, so a renewed
+// certificate is served for the next handshake without a restart.
+//
+// Parameters:
+//   - certPath: The PEM certificate (chain) served to clients.
+//   - keyPath: The PEM private key of the certificate.
+//   - clientCAPath: The CA bundle verifying client certificates; empty to not ask for them.
+//   - clientAuth: The client certificate policy, used only with a client CA.
+//
+// Returns:
+//   - *tls.Config: The TLS configuration.
+//   - error: An error if a file cannot be read.
+func NewTLSServerConfig(certPath, keyPath, clientCAPath string, clientAuth tls.ClientAuthType) (*tls.Config, error) {
+	reloader := &certReloader{certPath: certPath, keyPath: keyPath}
+	if _, err := reloader.load(); err != nil {
+		return nil, fmt.Errorf("failed to load TLS key pair: %w", err)
+	}
+	tlsServerConfig := &tls.Config{
+		GetCertificate: reloader.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+	if clientCAPath != "" {
+		pool, err := LoadCertPool(clientCAPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TLS client CA: %w", err)
+		}
+		tlsServerConfig.ClientCAs = pool
+		tlsServerConfig.ClientAuth = clientAuth
+	}
+	return tlsServerConfig, nil
+}
+
+// LoadCertPool reads a PEM bundle of one or more CA certificates.
+//
+// Parameters:
+//   - path: The PEM file.
+//
+// Returns:
+//   - *x509.CertPool: The certificates.
+//   - error: An error if the file cannot be read or holds no certificate.
+func LoadCertPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path) //nolint:gosec // Path comes from operator configuration
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+func tlsMinVersion(v configv1.TLSConfig_Version) (uint16, error) {
+	switch v {
+	case configv1.TLSConfig_VERSION_UNSPECIFIED, configv1.TLSConfig_TLS_1_2:
+		return tls.VersionTLS12, nil
+	case configv1.TLSConfig_TLS_1_3:
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported minimum TLS version: %v", v)
+	}
+}
+
+// certReloader serves a certificate from disk and reloads it
+// when the certificate or key file is modified.
+type certReloader struct {
+	certPath, keyPath string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+// load returns the current key pair, reading it again if either file has
+// been modified since it was last loaded.
+func (r *certReloader) load() (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	modTime, statErr := latestModTime(r.certPath, r.keyPath)
+	if statErr == nil && r.cert != nil && !modTime.After(r.modTime) {
+		return r.cert, nil
+	}
+	cert, err := tls.LoadX509KeyPair(r.certPath, r.keyPath)
+	if err != nil {
+		return nil, err
+	}
+	r.cert, r.modTime = &cert, modTime
+	return r.cert, nil
+}
+
+// GetClientCertificate implements tls.Config.GetClientCertificate. If the
+// files cannot be reloaded, for example while they are being replaced, the
+// previously loaded certificate is used.
+func (r *certReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	cert, err := r.load()
+	if err != nil {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.cert != nil {
+			return r.cert, nil
+		}
+		return nil, err
+	}
+	return cert, nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate with the same fallback
+// as GetClientCertificate.
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.GetClientCertificate(nil)
+}
+
+func latestModTime(paths ...string) (time.Time, error) {
+	var latest time.Time
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// TLSConfigWithMTLS returns the TLS settings of an upstream service with the
+// paths of its upstream_auth mtls block filled in where tls_config leaves
+// them empty.
+//
+// Parameters:
+//   - tlsConfig: The tls_config of the service, may be nil.
+//   - mtls: The upstream_auth mtls settings, may be nil.
+//
+// Returns:
+//   - *configv1.TLSConfig: The combined settings, nil if both are nil.
+func TLSConfigWithMTLS(tlsConfig *configv1.TLSConfig, mtls *configv1.MTLSAuth) *configv1.TLSConfig {
+	if mtls == nil {
+		return tlsConfig
+	}
+	merged := &configv1.TLSConfig{}
+	if tlsConfig != nil {
+		merged = proto.Clone(tlsConfig).(*configv1.TLSConfig)
+	}
+	if merged.GetClientCertPath() == "" && merged.GetClientKeyPath() == "" {
+		merged.SetClientCertPath(mtls.GetClientCertPath())
+		merged.SetClientKeyPath(mtls.GetClientKeyPath())
+	}
+	if merged.GetCaCertPath() == "" {
+		merged.SetCaCertPath(mtls.GetCaCertPath())
+	}
+	return merged
+}
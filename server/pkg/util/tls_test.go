@@ -8,6 +8,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
@@ -21,6 +22,7 @@ import (
 	configv1 "github.com/mcpany/core/proto/config/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 )
 
 // Helper function to create temporary cert and key files for testing
@@ -198,3 +200,125 @@ func TestNewHTTPClientWithTLS_Env(t *testing.T) {
 		assert.Contains(t, err.Error(), "ssrf attempt blocked")
 	})
 }
+
+func TestNewTLSClientConfig(t *testing.T) {
+	t.Run("defaults to TLS 1.2", func(t *testing.T) {
+		tlsConfig, err := NewTLSClientConfig(nil)
+		require.NoError(t, err)
+		assert.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+	})
+
+	t.Run("min version TLS 1.3", func(t *testing.T) {
+		tlsConfig, err := NewTLSClientConfig(configv1.TLSConfig_builder{
+			MinVersion: configv1.TLSConfig_TLS_1_3.Enum(),
+		}.Build())
+		require.NoError(t, err)
+		assert.Equal(t, uint16(tls.VersionTLS13), tlsConfig.MinVersion)
+	})
+
+	t.Run("unknown min version", func(t *testing.T) {
+		_, err := NewTLSClientConfig(configv1.TLSConfig_builder{
+			MinVersion: configv1.TLSConfig_Version(9).Enum(),
+		}.Build())
+		require.Error(t, err)
+	})
+
+	t.Run("client cert without key", func(t *testing.T) {
+		certPath, _ := generateTestCerts(t, t.TempDir())
+		_, err := NewTLSClientConfig(configv1.TLSConfig_builder{
+			ClientCertPath: proto.String(certPath),
+		}.Build())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must be set together")
+	})
+
+	t.Run("reloads rotated client certificate", func(t *testing.T) {
+		dir := t.TempDir()
+		certPath, keyPath := generateTestCerts(t, dir)
+		tlsConfig, err := NewTLSClientConfig(configv1.TLSConfig_builder{
+			ClientCertPath: proto.String(certPath),
+			ClientKeyPath:  proto.String(keyPath),
+		}.Build())
+		require.NoError(t, err)
+		require.NotNil(t, tlsConfig.GetClientCertificate)
+
+		first, err := tlsConfig.GetClientCertificate(&tls.CertificateRequestInfo{})
+		require.NoError(t, err)
+		assert.Equal(t, tlsConfig.Certificates[0].Certificate, first.Certificate)
+
+		// Rotate the key pair in place.
+		generateTestCerts(t, dir)
+		later := time.Now().Add(time.Minute)
+		require.NoError(t, os.Chtimes(certPath, later, later))
+		require.NoError(t, os.Chtimes(keyPath, later, later))
+
+		rotated, err := tlsConfig.GetClientCertificate(&tls.CertificateRequestInfo{})
+		require.NoError(t, err)
+		assert.NotEqual(t, first.Certificate, rotated.Certificate)
+
+		// A half-written rotation keeps the last good certificate.
+		require.NoError(t, os.WriteFile(certPath, []byte("partial"), 0o600))
+		require.NoError(t, os.Chtimes(certPath, later.Add(time.Minute), later.Add(time.Minute)))
+		current, err := tlsConfig.GetClientCertificate(&tls.CertificateRequestInfo{})
+		require.NoError(t, err)
+		assert.Equal(t, rotated.Certificate, current.Certificate)
+	})
+}
+
+func TestNewTLSServerConfig(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := generateTestCerts(t, dir)
+
+	t.Run("server certificate", func(t *testing.T) {
+		tlsConfig, err := NewTLSServerConfig(certPath, keyPath, "", tls.RequireAndVerifyClientCert)
+		require.NoError(t, err)
+		assert.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+		assert.Equal(t, tls.NoClientCert, tlsConfig.ClientAuth, "client auth needs a client CA")
+		cert, err := tlsConfig.GetCertificate(&tls.ClientHelloInfo{})
+		require.NoError(t, err)
+		assert.NotEmpty(t, cert.Certificate)
+	})
+
+	t.Run("client CA", func(t *testing.T) {
+		tlsConfig, err := NewTLSServerConfig(certPath, keyPath, certPath, tls.VerifyClientCertIfGiven)
+		require.NoError(t, err)
+		assert.Equal(t, tls.VerifyClientCertIfGiven, tlsConfig.ClientAuth)
+		assert.NotNil(t, tlsConfig.ClientCAs)
+	})
+
+	t.Run("missing key pair", func(t *testing.T) {
+		_, err := NewTLSServerConfig(filepath.Join(dir, "missing.pem"), keyPath, "", tls.NoClientCert)
+		assert.ErrorContains(t, err, "failed to load TLS key pair")
+	})
+
+	t.Run("client CA without certificates", func(t *testing.T) {
+		_, err := NewTLSServerConfig(certPath, keyPath, keyPath, tls.RequireAndVerifyClientCert)
+		assert.ErrorContains(t, err, "no certificates found")
+	})
+}
+
+func TestTLSConfigWithMTLS(t *testing.T) {
+	mtls := configv1.MTLSAuth_builder{
+		ClientCertPath: proto.String("auth/client.pem"),
+		ClientKeyPath:  proto.String("auth/client.key"),
+		CaCertPath:     proto.String("auth/ca.pem"),
+	}.Build()
+
+	assert.Nil(t, TLSConfigWithMTLS(nil, nil))
+
+	merged := TLSConfigWithMTLS(nil, mtls)
+	assert.Equal(t, "auth/client.pem", merged.GetClientCertPath())
+	assert.Equal(t, "auth/client.key", merged.GetClientKeyPath())
+	assert.Equal(t, "auth/ca.pem", merged.GetCaCertPath())
+
+	tlsConfig := configv1.TLSConfig_builder{
+		ServerName:     proto.String("internal"),
+		ClientCertPath: proto.String("tls/client.pem"),
+		ClientKeyPath:  proto.String("tls/client.key"),
+	}.Build()
+	merged = TLSConfigWithMTLS(tlsConfig, mtls)
+	assert.Equal(t, "internal", merged.GetServerName())
+	assert.Equal(t, "tls/client.pem", merged.GetClientCertPath())
+	assert.Equal(t, "auth/ca.pem", merged.GetCaCertPath())
+	assert.Empty(t, tlsConfig.GetCaCertPath(), "the service config must not be modified")
+}
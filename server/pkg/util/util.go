@@ -20,8 +20,8 @@ import (
 	"unicode/utf8"
 	"unsafe"
 
-	"github.com/google/uuid"
 	"github.com/mcpany/core/server/pkg/consts"
+	"github.com/mcpany/core/server/pkg/idgen"
 )
 
 // SanitizeID sanitizes a slice of strings to form a valid ID.
@@ -286,9 +286,10 @@ func init() {
 // Summary: Constant for "true" string.
 const TrueStr = "true"
 
-// GenerateUUID creates a new random (version 4) UUID.
+// GenerateUUID creates a new identifier using the process-wide ID generator,
+// which defaults to time-ordered (version 7) UUIDs.
 //
-// Summary: Generates a random UUID.
+// Summary: Generates a unique ID.
 //
 // Parameters:
 //   - None.
@@ -299,7 +300,7 @@ const TrueStr = "true"
 // Side Effects:
 //   - Generates random data.
 func GenerateUUID() string {
-	return uuid.New().String()
+	return idgen.New()
 }
 
 // ParseToolName deconstructs a fully qualified tool name into its service key
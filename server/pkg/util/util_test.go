@@ -388,7 +388,7 @@ func TestGetDockerCommand(t *testing.T) {
 
 func TestGenerateUUID(t *testing.T) {
 	uuid := GenerateUUID()
-	match, err := regexp.MatchString(`^[a-f0-9]{8}-[a-f0-9]{4}-4[a-f0-9]{3}-[89ab][a-f0-9]{3}-[a-f0-9]{12}$`, uuid)
+	match, err := regexp.MatchString(`^[a-f0-9]{8}-[a-f0-9]{4}-7[a-f0-9]{3}-[89ab][a-f0-9]{3}-[a-f0-9]{12}$`, uuid)
 	if err != nil {
 		t.Fatalf("Error matching UUID regex: %v", err)
 	}
@@ -0,0 +1,509 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package vault resolves "vault:" secret references against a HashiCorp
+// Vault server. It logs in with a token, AppRole or Kubernetes auth, caches
+// read secrets, and renews its token and the leases of cached secrets.
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/logging"
+	"github.com/mcpany/core/server/pkg/util"
+)
+
+// Scheme is the scheme of the secret references resolved by Vault.
+const Scheme = "vault"
+
+const (
+	defaultCacheTTL          = 5 * time.Minute
+	defaultRenewInterval     = 30 * time.Second
+	defaultAppRoleMount      = "approle"
+	defaultKubernetesMount   = "kubernetes"
+	defaultKubernetesJWTPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// Resolver reads secrets from Vault.
+type Resolver struct {
+	cfg           *configv1.VaultConfig
+	client        *api.Client
+	cacheTTL      time.Duration
+	renewInterval time.Duration
+	now           func() time.Time
+
+	// loginMu serializes logins so concurrent reads log in once.
+	loginMu sync.Mutex
+
+	mu    sync.Mutex
+	token *leaseState
+	cache map[string]*cachedSecret
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// leaseState tracks a token or secret lease.
+type leaseState struct {
+	id        string
+	duration  time.Duration
+	renewable bool
+	expires   time.Time
+}
+
+// renewDue reports whether a third or less of the lease remains.
+func (l *leaseState) renewDue(now time.Time) bool {
+	return l.duration > 0 && !now.Before(l.expires.Add(-l.duration/3))
+}
+
+// cachedSecret is a read secret. A secret with a renewable lease stays
+// cached while the lease is renewed; other secrets expire after the cache
+// TTL or their lease, whichever is shorter.
+type cachedSecret struct {
+	data    map[string]any
+	expires time.Time
+	lease   *leaseState
+}
+
+func (c *cachedSecret) valid(now time.Time) bool {
+	if c.lease != nil {
+		return now.Before(c.lease.expires)
+	}
+	return now.Before(c.expires)
+}
+
+// Option configures a Resolver.
+type Option func(*Resolver)
+
+// WithRenewInterval sets how often the token and leases are checked for renewal.
+//
+// Parameters:
+//   - d: time.Duration. The interval; 30 seconds by default.
+//
+// Returns:
+//   - Option: The option.
+func WithRenewInterval(d time.Duration) Option {
+	return func(r *Resolver) { r.renewInterval = d }
+}
+
+// WithClock sets the clock of the resolver.
+//
+// Parameters:
+//   - now: func() time.Time. The clock; time.Now by default.
+//
+// Returns:
+//   - Option: The option.
+func WithClock(now func() time.Time) Option {
+	return func(r *Resolver) { r.now = now }
+}
+
+// NewResolver creates a Resolver for the Vault server. It does not contact
+// the server; the first read logs in.
+//
+// Summary: Initializes a Vault secret resolver.
+//
+// Parameters:
+//   - cfg: *configv1.VaultConfig. The server and auth method.
+//   - opts: ...Option. The options.
+//
+// Returns:
+//   - *Resolver: The resolver.
+//   - error: An error if the configuration is invalid.
+func NewResolver(cfg *configv1.VaultConfig, opts ...Option) (*Resolver, error) {
+	if cfg.GetAddress() == "" {
+		return nil, fmt.Errorf("vault address is required")
+	}
+	apiConfig := api.DefaultConfig()
+	if apiConfig.Error != nil {
+		return nil, fmt.Errorf("failed to read vault environment: %w", apiConfig.Error)
+	}
+	apiConfig.Address = cfg.GetAddress()
+	if cfg.GetCaCertFile() != "" {
+		if err := apiConfig.ConfigureTLS(&api.TLSConfig{CACert: cfg.GetCaCertFile()}); err != nil {
+			return nil, fmt.Errorf("failed to load vault CA certificate: %w", err)
+		}
+	}
+	client, err := api.NewClient(apiConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vault client: %w", err)
+	}
+	// Only the configured auth method is used, not VAULT_TOKEN.
+	client.ClearToken()
+	if cfg.GetNamespace() != "" {
+		client.SetNamespace(cfg.GetNamespace())
+	}
+
+	cacheTTL := defaultCacheTTL
+	if cfg.HasCacheTtl() {
+		cacheTTL = cfg.GetCacheTtl().AsDuration()
+	}
+	r := &Resolver{
+		cfg:           cfg,
+		client:        client,
+		cacheTTL:      cacheTTL,
+		renewInterval: defaultRenewInterval,
+		now:           time.Now,
+		cache:         make(map[string]*cachedSecret),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r, nil
+}
+
+// Resolve returns the value of the key in the secret at the path. KV
+// version 2 secrets, which nest their values under "data", are supported.
+//
+// Summary: Reads a value from Vault.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the read.
+//   - path: string. The path of the secret, e.g. "secret/data/my-app/db".
+//   - key: string. The key of the value.
+//
+// Returns:
+//   - string: The value.
+//   - error: An error if the secret or key does not exist or Vault cannot be reached.
+//
+// Side Effects:
+//   - Logs in to Vault if needed and caches the secret.
+func (r *Resolver) Resolve(ctx context.Context, path, key string) (string, error) {
+	if key == "" {
+		return "", fmt.Errorf("vault secret %s is read without a #key", path)
+	}
+	data, err := r.read(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	if nested, ok := data["data"].(map[string]any); ok {
+		if value, ok := nested[key]; ok {
+			return stringValue(value)
+		}
+	}
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found in vault secret %s", key, path)
+	}
+	return stringValue(value)
+}
+
+func stringValue(value any) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode vault value: %w", err)
+	}
+	return string(b), nil
+}
+
+func (r *Resolver) read(ctx context.Context, path string) (map[string]any, error) {
+	now := r.now()
+	r.mu.Lock()
+	cached, ok := r.cache[path]
+	if ok && cached.valid(now) {
+		r.mu.Unlock()
+		return cached.data, nil
+	}
+	r.mu.Unlock()
+
+	if err := r.ensureToken(ctx); err != nil {
+		return nil, err
+	}
+	secret, err := r.client.Logical().ReadWithContext(ctx, path)
+	if isPermissionDenied(err) {
+		// The token may have expired or been revoked since the last renewal.
+		if err := r.login(ctx); err != nil {
+			return nil, err
+		}
+		secret, err = r.client.Logical().ReadWithContext(ctx, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vault secret %s: %w", path, err)
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("vault secret %s not found", path)
+	}
+
+	entry := &cachedSecret{data: secret.Data, expires: now.Add(r.cacheTTL)}
+	if secret.LeaseDuration > 0 {
+		leaseDuration := time.Duration(secret.LeaseDuration) * time.Second
+		if secret.Renewable && secret.LeaseID != "" {
+			entry.lease = &leaseState{id: secret.LeaseID, duration: leaseDuration, renewable: true, expires: now.Add(leaseDuration)}
+		} else if now.Add(leaseDuration).Before(entry.expires) {
+			entry.expires = now.Add(leaseDuration)
+		}
+	}
+	r.mu.Lock()
+	r.cache[path] = entry
+	r.mu.Unlock()
+	return secret.Data, nil
+}
+
+func isPermissionDenied(err error) bool {
+	var respErr *api.ResponseError
+	return errors.As(err, &respErr) && respErr.StatusCode == http.StatusForbidden
+}
+
+func (r *Resolver) ensureToken(ctx context.Context) error {
+	r.mu.Lock()
+	token := r.token
+	r.mu.Unlock()
+	if token != nil && (token.duration == 0 || r.now().Before(token.expires)) {
+		return nil
+	}
+	return r.login(ctx)
+}
+
+// login obtains a token with the configured auth method.
+func (r *Resolver) login(ctx context.Context) error {
+	r.loginMu.Lock()
+	defer r.loginMu.Unlock()
+
+	var (
+		secret *api.Secret
+		err    error
+	)
+	switch r.cfg.WhichAuth() {
+	case configv1.VaultConfig_Token_case:
+		token, resolveErr := util.ResolveSecret(ctx, r.cfg.GetToken())
+		if resolveErr != nil {
+			return fmt.Errorf("failed to resolve vault token: %w", resolveErr)
+		}
+		r.client.SetToken(token)
+		// The lookup tells whether and when the token must be renewed.
+		secret, err = r.client.Auth().Token().LookupSelfWithContext(ctx)
+		if err != nil {
+			logging.GetLogger().Debug("Failed to look up vault token; it will not be renewed", "error", err)
+			secret, err = nil, nil
+		}
+	case configv1.VaultConfig_AppRole_case:
+		appRole := r.cfg.GetAppRole()
+		secretID, resolveErr := util.ResolveSecret(ctx, appRole.GetSecretId())
+		if resolveErr != nil {
+			return fmt.Errorf("failed to resolve vault approle secret_id: %w", resolveErr)
+		}
+		secret, err = r.write(ctx, loginPath(appRole.GetMountPath(), defaultAppRoleMount), map[string]any{
+			"role_id":   appRole.GetRoleId(),
+			"secret_id": secretID,
+		})
+	case configv1.VaultConfig_Kubernetes_case:
+		k8s := r.cfg.GetKubernetes()
+		jwtPath := k8s.GetTokenPath()
+		if jwtPath == "" {
+			jwtPath = defaultKubernetesJWTPath
+		}
+		jwt, readErr := os.ReadFile(jwtPath) //nolint:gosec // The path is configured by the operator.
+		if readErr != nil {
+			return fmt.Errorf("failed to read kubernetes service account token: %w", readErr)
+		}
+		secret, err = r.write(ctx, loginPath(k8s.GetMountPath(), defaultKubernetesMount), map[string]any{
+			"role": k8s.GetRole(),
+			"jwt":  strings.TrimSpace(string(jwt)),
+		})
+	default:
+		return fmt.Errorf("vault auth method is required")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to log in to vault: %w", err)
+	}
+
+	state := &leaseState{}
+	if secret != nil {
+		if secret.Auth != nil {
+			r.client.SetToken(secret.Auth.ClientToken)
+		}
+		ttl, _ := secret.TokenTTL()
+		renewable, _ := secret.TokenIsRenewable()
+		state.duration = ttl
+		state.renewable = renewable
+		state.expires = r.now().Add(ttl)
+	}
+	r.mu.Lock()
+	r.token = state
+	r.mu.Unlock()
+	return nil
+}
+
+// write logs in without a token, which login endpoints do not accept.
+func (r *Resolver) write(ctx context.Context, path string, data map[string]any) (*api.Secret, error) {
+	client, err := r.client.Clone()
+	if err != nil {
+		return nil, err
+	}
+	client.ClearToken()
+	if r.cfg.GetNamespace() != "" {
+		client.SetNamespace(r.cfg.GetNamespace())
+	}
+	secret, err := client.Logical().WriteWithContext(ctx, path, data)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return nil, fmt.Errorf("vault login returned no token")
+	}
+	return secret, nil
+}
+
+func loginPath(mount, fallback string) string {
+	mount = strings.Trim(mount, "/")
+	if mount == "" {
+		mount = fallback
+	}
+	return "auth/" + mount + "/login"
+}
+
+// Renew renews the token and the leases of cached secrets when a third or
+// less of their time remains, and drops expired secrets from the cache.
+// A token that cannot be renewed is replaced by logging in again.
+//
+// Summary: Renews the Vault token and leases.
+//
+// Parameters:
+//   - ctx: context.Context. The context of the Vault calls.
+//
+// Returns:
+//   - error: An error if the token can neither be renewed nor replaced.
+//
+// Side Effects:
+//   - Calls Vault and updates the cache.
+func (r *Resolver) Renew(ctx context.Context) error {
+	now := r.now()
+	var tokenErr error
+	r.mu.Lock()
+	token := r.token
+	r.mu.Unlock()
+	if token != nil && token.renewDue(now) {
+		tokenErr = r.renewToken(ctx, token)
+	}
+
+	type renewal struct {
+		entry    *cachedSecret
+		id       string
+		duration time.Duration
+	}
+	r.mu.Lock()
+	due := make(map[string]renewal)
+	for path, entry := range r.cache {
+		switch {
+		case !entry.valid(now):
+			delete(r.cache, path)
+		case entry.lease != nil && entry.lease.renewDue(now):
+			due[path] = renewal{entry: entry, id: entry.lease.id, duration: entry.lease.duration}
+		}
+	}
+	r.mu.Unlock()
+
+	for path, due := range due {
+		entry := due.entry
+		secret, err := r.client.Sys().RenewWithContext(ctx, due.id, int(due.duration.Seconds()))
+		r.mu.Lock()
+		if err != nil || secret == nil || secret.LeaseDuration <= 0 {
+			// The secret is read again on its next use.
+			logging.GetLogger().Warn("Failed to renew vault lease", "path", path, "error", err)
+			delete(r.cache, path)
+		} else {
+			entry.lease.duration = time.Duration(secret.LeaseDuration) * time.Second
+			entry.lease.expires = now.Add(entry.lease.duration)
+			entry.lease.renewable = secret.Renewable
+			if !secret.Renewable {
+				entry.lease = nil
+				entry.expires = now.Add(time.Duration(secret.LeaseDuration) * time.Second)
+			}
+		}
+		r.mu.Unlock()
+	}
+	return tokenErr
+}
+
+func (r *Resolver) renewToken(ctx context.Context, token *leaseState) error {
+	if token.renewable {
+		secret, err := r.client.Auth().Token().RenewSelfWithContext(ctx, int(token.duration.Seconds()))
+		if err == nil && secret != nil && secret.Auth != nil {
+			r.mu.Lock()
+			r.token = &leaseState{
+				duration:  time.Duration(secret.Auth.LeaseDuration) * time.Second,
+				renewable: secret.Auth.Renewable,
+				expires:   r.now().Add(time.Duration(secret.Auth.LeaseDuration) * time.Second),
+			}
+			r.mu.Unlock()
+			return nil
+		}
+		logging.GetLogger().Warn("Failed to renew vault token; logging in again", "error", err)
+	}
+	return r.login(ctx)
+}
+
+// Start renews the token and leases periodically until Stop is called.
+//
+// Parameters:
+//   - ctx: context.Context. Renewal also stops when it is cancelled.
+//
+// Returns:
+//   - error: Always nil.
+//
+// Side Effects:
+//   - Starts a goroutine.
+func (r *Resolver) Start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cancel != nil {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	r.cancel = cancel
+	r.done = make(chan struct{})
+	done := r.done
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(r.renewInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.Renew(ctx); err != nil {
+					logging.GetLogger().Warn("Vault renewal failed", "error", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the renewal and clears the cache.
+//
+// Parameters:
+//   - ctx: context.Context. Bounds the wait.
+//
+// Returns:
+//   - error: An error if the context expires first.
+func (r *Resolver) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.cancel, r.done = nil, nil
+	r.cache = make(map[string]*cachedSecret)
+	r.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,331 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// fakeVault implements the parts of the Vault HTTP API used by the resolver.
+type fakeVault struct {
+	mu          sync.Mutex
+	tokens      map[string]bool
+	logins      int
+	tokenRenews int
+	leaseRenews int
+	reads       map[string]int
+	failRenew   bool
+	lastLogin   map[string]any
+}
+
+func newFakeVault(t *testing.T) (*fakeVault, *httptest.Server) {
+	f := &fakeVault{tokens: map[string]bool{"static-token": true}, reads: map[string]int{}}
+	srv := httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeVault) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var body map[string]any
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	reply := func(v any) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+
+	switch r.URL.Path {
+	case "/v1/auth/approle/login", "/v1/auth/kubernetes/login":
+		f.logins++
+		f.lastLogin = body
+		if body["secret_id"] == "wrong" {
+			w.WriteHeader(http.StatusBadRequest)
+			reply(map[string]any{"errors": []string{"invalid secret id"}})
+			return
+		}
+		token := fmt.Sprintf("token-%d", f.logins)
+		f.tokens[token] = true
+		reply(map[string]any{"auth": map[string]any{"client_token": token, "lease_duration": 60, "renewable": true}})
+		return
+	}
+
+	if !f.tokens[r.Header.Get("X-Vault-Token")] {
+		w.WriteHeader(http.StatusForbidden)
+		reply(map[string]any{"errors": []string{"permission denied"}})
+		return
+	}
+	switch r.URL.Path {
+	case "/v1/auth/token/lookup-self":
+		reply(map[string]any{"data": map[string]any{"ttl": 0, "renewable": false}})
+	case "/v1/auth/token/renew-self":
+		f.tokenRenews++
+		reply(map[string]any{"auth": map[string]any{"client_token": r.Header.Get("X-Vault-Token"), "lease_duration": 60, "renewable": true}})
+	case "/v1/sys/leases/renew":
+		f.leaseRenews++
+		if f.failRenew {
+			w.WriteHeader(http.StatusBadRequest)
+			reply(map[string]any{"errors": []string{"lease expired"}})
+			return
+		}
+		reply(map[string]any{"lease_id": body["lease_id"], "lease_duration": 60, "renewable": true})
+	case "/v1/secret/data/app":
+		f.reads[r.URL.Path]++
+		reply(map[string]any{"data": map[string]any{
+			"data":     map[string]any{"password": "s3cret", "port": 5432},
+			"metadata": map[string]any{"version": 1},
+		}})
+	case "/v1/kv/legacy":
+		f.reads[r.URL.Path]++
+		reply(map[string]any{"lease_duration": 30, "data": map[string]any{"token": "legacy-token"}})
+	case "/v1/database/creds/app":
+		f.reads[r.URL.Path]++
+		reply(map[string]any{"lease_id": "database/creds/app/abc", "lease_duration": 60, "renewable": true,
+			"data": map[string]any{"username": "app-user"}})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		reply(map[string]any{"errors": []string{}})
+	}
+}
+
+func (f *fakeVault) revokeAll() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tokens = map[string]bool{}
+}
+
+func (f *fakeVault) counts() (logins, tokenRenews, leaseRenews int, reads map[string]int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	copied := make(map[string]int, len(f.reads))
+	for k, v := range f.reads {
+		copied[k] = v
+	}
+	return f.logins, f.tokenRenews, f.leaseRenews, copied
+}
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func appRoleConfig(addr string) *configv1.VaultConfig {
+	return configv1.VaultConfig_builder{
+		Address: proto.String(addr),
+		AppRole: configv1.VaultAppRoleAuth_builder{
+			RoleId:   proto.String("role"),
+			SecretId: configv1.SecretValue_builder{PlainText: proto.String("secret")}.Build(),
+		}.Build(),
+	}.Build()
+}
+
+func TestResolver_AppRoleAndCache(t *testing.T) {
+	fake, srv := newFakeVault(t)
+	clock := &fakeClock{now: time.Now()}
+	r, err := NewResolver(appRoleConfig(srv.URL), WithClock(clock.Now))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	value, err := r.Resolve(ctx, "secret/data/app", "password")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", value)
+	value, err = r.Resolve(ctx, "secret/data/app", "port")
+	require.NoError(t, err)
+	assert.Equal(t, "5432", value)
+	_, err = r.Resolve(ctx, "secret/data/app", "missing")
+	assert.ErrorContains(t, err, `key "missing" not found`)
+	_, err = r.Resolve(ctx, "secret/data/unknown", "password")
+	assert.Error(t, err)
+
+	logins, _, _, reads := fake.counts()
+	assert.Equal(t, 1, logins)
+	assert.Equal(t, 1, reads["/v1/secret/data/app"])
+	assert.Equal(t, map[string]any{"role_id": "role", "secret_id": "secret"}, fake.lastLogin)
+
+	// The secret is read again once the cache TTL has passed.
+	clock.Advance(defaultCacheTTL + time.Second)
+	_, err = r.Resolve(ctx, "secret/data/app", "password")
+	require.NoError(t, err)
+	_, _, _, reads = fake.counts()
+	assert.Equal(t, 2, reads["/v1/secret/data/app"])
+}
+
+func TestResolver_LeaseShorterThanCacheTTL(t *testing.T) {
+	fake, srv := newFakeVault(t)
+	clock := &fakeClock{now: time.Now()}
+	r, err := NewResolver(appRoleConfig(srv.URL), WithClock(clock.Now))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	value, err := r.Resolve(ctx, "kv/legacy", "token")
+	require.NoError(t, err)
+	assert.Equal(t, "legacy-token", value)
+	clock.Advance(31 * time.Second)
+	_, err = r.Resolve(ctx, "kv/legacy", "token")
+	require.NoError(t, err)
+	_, _, _, reads := fake.counts()
+	assert.Equal(t, 2, reads["/v1/kv/legacy"])
+}
+
+func TestResolver_RelogsInWhenTokenIsRevoked(t *testing.T) {
+	fake, srv := newFakeVault(t)
+	cfg := appRoleConfig(srv.URL)
+	cfg.SetCacheTtl(durationpb.New(0))
+	r, err := NewResolver(cfg)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = r.Resolve(ctx, "secret/data/app", "password")
+	require.NoError(t, err)
+	fake.revokeAll()
+	value, err := r.Resolve(ctx, "secret/data/app", "password")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", value)
+
+	logins, _, _, reads := fake.counts()
+	assert.Equal(t, 2, logins)
+	assert.Equal(t, 2, reads["/v1/secret/data/app"])
+}
+
+func TestResolver_Renew(t *testing.T) {
+	fake, srv := newFakeVault(t)
+	clock := &fakeClock{now: time.Now()}
+	r, err := NewResolver(appRoleConfig(srv.URL), WithClock(clock.Now))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	value, err := r.Resolve(ctx, "database/creds/app", "username")
+	require.NoError(t, err)
+	assert.Equal(t, "app-user", value)
+
+	// Nothing is due while more than a third of the leases remains.
+	require.NoError(t, r.Renew(ctx))
+	_, tokenRenews, leaseRenews, _ := fake.counts()
+	assert.Zero(t, tokenRenews)
+	assert.Zero(t, leaseRenews)
+
+	clock.Advance(45 * time.Second)
+	require.NoError(t, r.Renew(ctx))
+	_, tokenRenews, leaseRenews, _ = fake.counts()
+	assert.Equal(t, 1, tokenRenews)
+	assert.Equal(t, 1, leaseRenews)
+
+	// The renewed lease keeps the credentials cached past their first lease.
+	clock.Advance(30 * time.Second)
+	_, err = r.Resolve(ctx, "database/creds/app", "username")
+	require.NoError(t, err)
+	_, _, _, reads := fake.counts()
+	assert.Equal(t, 1, reads["/v1/database/creds/app"])
+
+	// A lease that cannot be renewed is dropped and read again.
+	fake.mu.Lock()
+	fake.failRenew = true
+	fake.mu.Unlock()
+	clock.Advance(15 * time.Second)
+	require.NoError(t, r.Renew(ctx))
+	_, err = r.Resolve(ctx, "database/creds/app", "username")
+	require.NoError(t, err)
+	_, _, _, reads = fake.counts()
+	assert.Equal(t, 2, reads["/v1/database/creds/app"])
+}
+
+func TestResolver_TokenAuth(t *testing.T) {
+	fake, srv := newFakeVault(t)
+	r, err := NewResolver(configv1.VaultConfig_builder{
+		Address: proto.String(srv.URL),
+		Token:   configv1.SecretValue_builder{PlainText: proto.String("static-token")}.Build(),
+	}.Build())
+	require.NoError(t, err)
+
+	value, err := r.Resolve(context.Background(), "secret/data/app", "password")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", value)
+	logins, _, _, _ := fake.counts()
+	assert.Zero(t, logins)
+
+	bad, err := NewResolver(configv1.VaultConfig_builder{
+		Address: proto.String(srv.URL),
+		Token:   configv1.SecretValue_builder{PlainText: proto.String("wrong")}.Build(),
+	}.Build())
+	require.NoError(t, err)
+	_, err = bad.Resolve(context.Background(), "secret/data/app", "password")
+	assert.Error(t, err)
+}
+
+func TestResolver_KubernetesAuth(t *testing.T) {
+	fake, srv := newFakeVault(t)
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("service-account-jwt\n"), 0o600))
+
+	r, err := NewResolver(configv1.VaultConfig_builder{
+		Address: proto.String(srv.URL),
+		Kubernetes: configv1.VaultKubernetesAuth_builder{
+			Role:      proto.String("mcpany"),
+			TokenPath: proto.String(tokenPath),
+		}.Build(),
+	}.Build())
+	require.NoError(t, err)
+
+	_, err = r.Resolve(context.Background(), "secret/data/app", "password")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"role": "mcpany", "jwt": "service-account-jwt"}, fake.lastLogin)
+}
+
+func TestResolver_LoginFailure(t *testing.T) {
+	_, srv := newFakeVault(t)
+	cfg := appRoleConfig(srv.URL)
+	cfg.GetAppRole().SetSecretId(configv1.SecretValue_builder{PlainText: proto.String("wrong")}.Build())
+	r, err := NewResolver(cfg)
+	require.NoError(t, err)
+
+	_, err = r.Resolve(context.Background(), "secret/data/app", "password")
+	assert.ErrorContains(t, err, "failed to log in to vault")
+
+	_, err = NewResolver(configv1.VaultConfig_builder{}.Build())
+	assert.Error(t, err)
+}
+
+func TestResolver_SecretRef(t *testing.T) {
+	_, srv := newFakeVault(t)
+	r, err := NewResolver(appRoleConfig(srv.URL), WithRenewInterval(time.Hour))
+	require.NoError(t, err)
+	util.SetSecretRefResolver(Scheme, r.Resolve)
+	t.Cleanup(func() { util.SetSecretRefResolver(Scheme, nil) })
+	require.NoError(t, r.Start(context.Background()))
+	t.Cleanup(func() { _ = r.Stop(context.Background()) })
+
+	value, err := util.ResolveSecret(context.Background(), configv1.SecretValue_builder{
+		SecretRef: proto.String("vault:secret/data/app#password"),
+	}.Build())
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", value)
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+
+	"github.com/google/cel-go/cel"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+)
+
+// conditionCostLimit bounds the evaluation of a condition, so that an
+// expression over a large result cannot stall the calls.
+const conditionCostLimit = 1_000_000
+
+// Call describes a tool call to match against the rules of a webhook.
+//
+// Summary: The attributes of a call seen by a webhook matcher.
+type Call struct {
+	// Tool is the fully qualified name of the tool, e.g. "github.create_issue".
+	Tool string
+	// Service is the ID of the service of the tool.
+	Service string
+	// Arguments are the arguments of the call.
+	Arguments map[string]any
+	// Result is the result of the tool, or nil before the call.
+	Result any
+}
+
+// Matcher selects the calls sent to a webhook, by the globs of their tool,
+// their service and a CEL condition. A nil Matcher matches every call.
+//
+// Summary: Evaluates the match rules of a webhook.
+type Matcher struct {
+	tools     []string
+	services  []string
+	condition cel.Program
+}
+
+// NewMatcher compiles the match rules of a webhook.
+//
+// Summary: Creates a Matcher from its configuration.
+//
+// Parameters:
+//   - config: *configv1.WebhookMatchConfig. The rules, or nil.
+//
+// Returns:
+//   - *Matcher: The matcher, or nil if no rule is set.
+//   - error: An error if a glob or the condition is invalid.
+func NewMatcher(config *configv1.WebhookMatchConfig) (*Matcher, error) {
+	if len(config.GetTools()) == 0 && len(config.GetServices()) == 0 && config.GetCondition() == "" {
+		return nil, nil
+	}
+	for _, pattern := range config.GetTools() {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid tool glob %q: %w", pattern, err)
+		}
+	}
+	m := &Matcher{tools: config.GetTools(), services: config.GetServices()}
+	if expr := config.GetCondition(); expr != "" {
+		prg, err := compileCondition(expr)
+		if err != nil {
+			return nil, err
+		}
+		m.condition = prg
+	}
+	return m, nil
+}
+
+// compileCondition compiles a condition into a program returning a bool.
+func compileCondition(expr string) (cel.Program, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("tool", cel.StringType),
+		cel.Variable("service", cel.StringType),
+		cel.Variable("arguments", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("result", cel.DynType),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the condition environment: %w", err)
+	}
+	ast, issues := env.Compile(expr)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("invalid condition: %w", issues.Err())
+	}
+	if t := ast.OutputType(); !t.IsExactType(cel.BoolType) && !t.IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("invalid condition: returns %s, not bool", t)
+	}
+	prg, err := env.Program(ast, cel.CostLimit(conditionCostLimit))
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition: %w", err)
+	}
+	return prg, nil
+}
+
+// Match reports whether a call is sent to the webhook.
+//
+// Summary: Evaluates the rules against a call.
+//
+// Parameters:
+//   - call: Call. The call.
+//
+// Returns:
+//   - bool: True if the call matches all the rules set.
+//   - error: An error if the condition fails to evaluate, in which case the
+//     call is reported as matching.
+func (m *Matcher) Match(call Call) (bool, error) {
+	if m == nil {
+		return true, nil
+	}
+	if len(m.tools) > 0 && !slices.ContainsFunc(m.tools, func(pattern string) bool {
+		matched, _ := path.Match(pattern, call.Tool)
+		return matched
+	}) {
+		return false, nil
+	}
+	if len(m.services) > 0 && !slices.Contains(m.services, call.Service) {
+		return false, nil
+	}
+	if m.condition == nil {
+		return true, nil
+	}
+
+	arguments := call.Arguments
+	if arguments == nil {
+		arguments = map[string]any{}
+	}
+	result, err := plainValue(call.Result)
+	if err != nil {
+		return true, err
+	}
+	out, _, err := m.condition.Eval(map[string]any{
+		"tool":      call.Tool,
+		"service":   call.Service,
+		"arguments": arguments,
+		"result":    result,
+	})
+	if err != nil {
+		return true, fmt.Errorf("failed to evaluate condition: %w", err)
+	}
+	matched, ok := out.Value().(bool)
+	if !ok {
+		return true, fmt.Errorf("condition returned %v, not a bool", out.Value())
+	}
+	return matched, nil
+}
+
+// plainValue converts a result into the JSON values the condition sees,
+// e.g. the fields of a struct into a map.
+func plainValue(v any) (any, error) {
+	switch v.(type) {
+	case nil, string, bool, float64, map[string]any, []any:
+		return v, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert result: %w", err)
+	}
+	var plain any
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return nil, fmt.Errorf("failed to convert result: %w", err)
+	}
+	return plain, nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks
+
+import (
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatcher(t *testing.T) {
+	m, err := NewMatcher(nil)
+	require.NoError(t, err)
+	assert.Nil(t, m, "no rules match every call")
+	matched, err := m.Match(Call{Tool: "any.tool"})
+	require.NoError(t, err)
+	assert.True(t, matched)
+
+	m, err = NewMatcher(configv1.WebhookMatchConfig_builder{
+		Tools:     []string{"github.*", "*.delete_*"},
+		Services:  []string{"github", "gitlab"},
+		Condition: `!has(arguments.branch) || arguments.branch == "main"`,
+	}.Build())
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name string
+		call Call
+		want bool
+	}{
+		{"tool and service", Call{Tool: "github.create_issue", Service: "github"}, true},
+		{"second glob", Call{Tool: "gitlab.delete_repo", Service: "gitlab"}, true},
+		{"other tool", Call{Tool: "gitlab.create_issue", Service: "gitlab"}, false},
+		{"other service", Call{Tool: "github.create_issue", Service: "github-mirror"}, false},
+		{"condition", Call{Tool: "github.push", Service: "github", Arguments: map[string]any{"branch": "main"}}, true},
+		{"condition false", Call{Tool: "github.push", Service: "github", Arguments: map[string]any{"branch": "dev"}}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			matched, err := m.Match(tc.call)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, matched)
+		})
+	}
+}
+
+func TestMatcher_Result(t *testing.T) {
+	type forecast struct {
+		Temp int `json:"temp"`
+	}
+	m, err := NewMatcher(configv1.WebhookMatchConfig_builder{Condition: "result != null && result.temp > 30"}.Build())
+	require.NoError(t, err)
+
+	matched, err := m.Match(Call{Tool: "weather.forecast", Result: forecast{Temp: 35}})
+	require.NoError(t, err)
+	assert.True(t, matched, "the fields of a struct result are seen as a map")
+	matched, err = m.Match(Call{Tool: "weather.forecast", Result: map[string]any{"temp": float64(20)}})
+	require.NoError(t, err)
+	assert.False(t, matched)
+	matched, err = m.Match(Call{Tool: "weather.forecast"})
+	require.NoError(t, err)
+	assert.False(t, matched, "the result is null before the call")
+
+	m, err = NewMatcher(configv1.WebhookMatchConfig_builder{Condition: "arguments.missing == 1"}.Build())
+	require.NoError(t, err)
+	matched, err = m.Match(Call{Tool: "weather.forecast"})
+	assert.Error(t, err)
+	assert.True(t, matched, "a call for which the condition fails is sent")
+}
+
+func TestNewMatcher_Invalid(t *testing.T) {
+	_, err := NewMatcher(configv1.WebhookMatchConfig_builder{Tools: []string{"["}}.Build())
+	assert.ErrorContains(t, err, "invalid tool glob")
+	_, err = NewMatcher(configv1.WebhookMatchConfig_builder{Condition: "unknown_var == 1"}.Build())
+	assert.ErrorContains(t, err, "invalid condition")
+	_, err = NewMatcher(configv1.WebhookMatchConfig_builder{Condition: `tool + "x"`}.Build())
+	assert.ErrorContains(t, err, "not bool")
+}
@@ -0,0 +1,278 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/util"
+	webhook "github.com/standard-webhooks/standard-webhooks/libraries/go"
+)
+
+// SigningTransport signs outgoing webhook requests following Standard
+// Webhooks, with the current secret and the previous secrets while the
+// secret is rotated.
+//
+// Summary: HTTP Transport that adds HMAC signatures to outgoing requests.
+type SigningTransport struct {
+	// Signers sign each request: the current secret first, then the
+	// previous secrets. Requests are sent unsigned if it is empty.
+	Signers []*webhook.Webhook
+	// Base sends the signed requests; http.DefaultTransport if nil.
+	Base http.RoundTripper
+}
+
+// RoundTrip executes the HTTP request with a signature.
+//
+// Summary: Intercepts the request to add Webhook-Id, Webhook-Timestamp, and Webhook-Signature headers.
+//
+// The message ID is the Webhook-Id or Ce-Id header of the request if set,
+// so that it stays the same across the retries of a message, or a new ID.
+//
+// Parameters:
+//   - req: *http.Request. The outgoing request.
+//
+// Returns:
+//   - *http.Response: The received response.
+//   - error: An error if signing or transport fails.
+//
+// Side Effects:
+//   - Reads and buffers the request body for signing.
+//   - Modifies request headers.
+func (t *SigningTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(t.Signers) > 0 {
+		payload := []byte{}
+		if req.Body != nil {
+			var err error
+			payload, err = io.ReadAll(req.Body)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read request body for signing: %w", err)
+			}
+			_ = req.Body.Close()
+			req.Body = io.NopCloser(bytes.NewReader(payload))
+		}
+
+		msgID := req.Header.Get("Webhook-Id")
+		if msgID == "" {
+			msgID = req.Header.Get("Ce-Id")
+		}
+		if msgID == "" {
+			msgID = uuid.New().String()
+		}
+		headers, err := Sign(t.Signers, msgID, time.Now(), payload)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+// Sign returns the webhook-id, webhook-timestamp and webhook-signature
+// headers of a Standard Webhooks message, as alternating names and values.
+// The signature header carries one signature per signer.
+//
+// Parameters:
+//   - signers: []*webhook.Webhook. The signers of the current and previous secrets.
+//   - msgID: string. The message ID.
+//   - now: time.Time. The timestamp of the message.
+//   - payload: []byte. The signed payload.
+//
+// Returns:
+//   - []string: The header names and values.
+//   - error: An error if signing fails.
+func Sign(signers []*webhook.Webhook, msgID string, now time.Time, payload []byte) ([]string, error) {
+	signatures := make([]string, 0, len(signers))
+	for _, signer := range signers {
+		signature, err := signer.Sign(msgID, now, payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign request: %w", err)
+		}
+		signatures = append(signatures, signature)
+	}
+	return []string{
+		"webhook-id", msgID,
+		"webhook-timestamp", strconv.FormatInt(now.Unix(), 10),
+		"webhook-signature", strings.Join(signatures, " "),
+	}, nil
+}
+
+// NewSigners returns the signers of a Standard Webhooks secret and of the
+// previous secrets.
+//
+// Parameters:
+//   - secret: string. The current secret; no signers if empty.
+//   - previous: []string. The previous secrets.
+//
+// Returns:
+//   - []*webhook.Webhook: The signers, the current secret first.
+//   - error: An error if a secret is not a Standard Webhooks secret.
+func NewSigners(secret string, previous []string) ([]*webhook.Webhook, error) {
+	if secret == "" {
+		if len(previous) > 0 {
+			return nil, fmt.Errorf("previous_secrets require a webhook_secret")
+		}
+		return nil, nil
+	}
+	signer, err := webhook.NewWebhook(secret)
+	if err != nil {
+		return nil, fmt.Errorf("webhook_secret is not a Standard Webhooks secret: %w", err)
+	}
+	signers := []*webhook.Webhook{signer}
+	for i, s := range previous {
+		signer, err := webhook.NewWebhook(s)
+		if err != nil {
+			return nil, fmt.Errorf("previous_secrets[%d] is not a Standard Webhooks secret: %w", i, err)
+		}
+		signers = append(signers, signer)
+	}
+	return signers, nil
+}
+
+// NewMTLSTransport returns a transport presenting the client certificate of
+// mtls and verifying the server with its CA bundle.
+//
+// Parameters:
+//   - mtls: *configv1.MTLSAuth. The TLS settings; http.DefaultTransport if nil.
+//
+// Returns:
+//   - http.RoundTripper: The transport.
+//   - error: An error if the certificates cannot be loaded.
+func NewMTLSTransport(mtls *configv1.MTLSAuth) (http.RoundTripper, error) {
+	if mtls == nil {
+		return http.DefaultTransport, nil
+	}
+	tlsConfig, err := util.NewTLSClientConfig(util.TLSConfigWithMTLS(nil, mtls))
+	if err != nil {
+		return nil, fmt.Errorf("webhook mtls: %w", err)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// outboundTransports are the transports of the outbound webhooks: direct
+// for the URLs of the configuration, safe for the URLs set at runtime.
+type outboundTransports struct {
+	direct http.RoundTripper
+	safe   http.RoundTripper
+	signed bool
+}
+
+// outbound holds the transports of the outbound webhooks.
+var outbound atomic.Pointer[outboundTransports]
+
+// ConfigureOutbound sets the signing secrets and TLS settings of the
+// webhooks the server calls on its own, such as the audit and alert
+// webhooks. Until it is called, their requests are sent unsigned.
+//
+// Summary: Configures the shared transport of the outbound webhooks.
+//
+// Parameters:
+//   - config: *configv1.OutboundWebhookConfig. The settings; nil for none.
+//
+// Returns:
+//   - error: An error if a secret or the certificates are invalid. The
+//     previous settings stay in effect then.
+func ConfigureOutbound(config *configv1.OutboundWebhookConfig) error {
+	signers, err := NewSigners(config.GetWebhookSecret(), config.GetPreviousSecrets())
+	if err != nil {
+		return err
+	}
+	base, err := NewMTLSTransport(config.GetMtls())
+	if err != nil {
+		return err
+	}
+	outbound.Store(newOutboundTransports(signers, base))
+	return nil
+}
+
+func newOutboundTransports(signers []*webhook.Webhook, base http.RoundTripper) *outboundTransports {
+	safe := http.DefaultTransport.(*http.Transport).Clone()
+	if t, ok := base.(*http.Transport); ok {
+		safe = t.Clone()
+	}
+	safe.Proxy = nil
+	safe.DialContext = util.NewEnvSafeDialer().DialContext
+	return &outboundTransports{
+		direct: &SigningTransport{Signers: signers, Base: base},
+		safe:   &SigningTransport{Signers: signers, Base: safe},
+		signed: len(signers) > 0,
+	}
+}
+
+// OutboundSigned reports whether the requests of the outbound webhooks are
+// signed, that is whether the last ConfigureOutbound call set a
+// webhook_secret.
+//
+// Returns:
+//   - bool: True if the requests are signed.
+func OutboundSigned() bool {
+	transports := outbound.Load()
+	return transports != nil && transports.signed
+}
+
+// unconfigured are the transports used before ConfigureOutbound is called.
+var unconfigured = newOutboundTransports(nil, http.DefaultTransport)
+
+// outboundTransport sends requests with the current outbound settings.
+type outboundTransport struct {
+	safe bool
+}
+
+func (t outboundTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transports := outbound.Load()
+	if transports == nil {
+		transports = unconfigured
+	}
+	if t.safe {
+		return transports.safe.RoundTrip(req)
+	}
+	return transports.direct.RoundTrip(req)
+}
+
+// NewOutboundClient returns a client for a webhook the server calls on its
+// own. Its requests are signed and sent with the settings of the last
+// ConfigureOutbound call, also when they change later.
+//
+// Summary: Creates an HTTP client for outbound webhooks.
+//
+// Parameters:
+//   - timeout: time.Duration. The timeout of each request.
+//
+// Returns:
+//   - *http.Client: The client.
+func NewOutboundClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: outboundTransport{}}
+}
+
+// NewSafeOutboundClient is NewOutboundClient for webhooks whose URL is set
+// at runtime rather than in the configuration, such as the notify_url of an
+// API key. Like util.NewSafeHTTPClient, it only connects to the addresses
+// the SSRF protection allows.
+//
+// Parameters:
+//   - timeout: time.Duration. The timeout of each request.
+//
+// Returns:
+//   - *http.Client: The client.
+func NewSafeOutboundClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: outboundTransport{safe: true}}
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	webhook "github.com/standard-webhooks/standard-webhooks/libraries/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestOutboundClient_SignsWithCurrentAndPreviousSecrets(t *testing.T) {
+	const current = "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+	const previous = "whsec_dGVzdC1zZWNyZXQtdmFsaWQtYmFzZTY0"
+	t.Cleanup(func() { require.NoError(t, ConfigureOutbound(nil)) })
+
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+	}))
+	defer srv.Close()
+
+	client := NewOutboundClient(5 * time.Second)
+	post := func() (*http.Request, []byte) {
+		req, err := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(`{"event":"test"}`))
+		require.NoError(t, err)
+		req.Header.Set("Ce-Id", "msg-1")
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return <-received, <-bodies
+	}
+
+	// Unsigned until configured.
+	r, _ := post()
+	assert.Empty(t, r.Header.Get("webhook-signature"))
+	assert.False(t, OutboundSigned())
+
+	// The settings apply to clients created before they change.
+	require.NoError(t, ConfigureOutbound(configv1.OutboundWebhookConfig_builder{
+		WebhookSecret:   proto.String(current),
+		PreviousSecrets: []string{previous},
+	}.Build()))
+	assert.True(t, OutboundSigned())
+	r, body := post()
+	assert.Equal(t, "msg-1", r.Header.Get("webhook-id"))
+	for _, secret := range []string{current, previous} {
+		verifier, err := webhook.NewWebhook(secret)
+		require.NoError(t, err)
+		assert.NoError(t, verifier.Verify(body, r.Header), secret)
+	}
+
+	// Invalid settings keep the previous ones.
+	assert.Error(t, ConfigureOutbound(configv1.OutboundWebhookConfig_builder{
+		WebhookSecret: proto.String("whsec_!!"),
+	}.Build()))
+	r, body = post()
+	verifier, err := webhook.NewWebhook(current)
+	require.NoError(t, err)
+	assert.NoError(t, verifier.Verify(body, r.Header))
+}
+
+func TestSafeOutboundClient_BlocksLoopback(t *testing.T) {
+	t.Setenv("MCPANY_DANGEROUS_ALLOW_LOCAL_IPS", "")
+	t.Setenv("MCPANY_ALLOW_LOOPBACK_RESOURCES", "")
+	t.Cleanup(func() { require.NoError(t, ConfigureOutbound(nil)) })
+	require.NoError(t, ConfigureOutbound(configv1.OutboundWebhookConfig_builder{
+		WebhookSecret: proto.String("whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"),
+	}.Build()))
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		calls.Add(1)
+	}))
+	defer srv.Close()
+
+	resp, err := NewSafeOutboundClient(5 * time.Second).Post(srv.URL, "application/json", strings.NewReader("{}"))
+	if err == nil {
+		_ = resp.Body.Close()
+	}
+	assert.Error(t, err)
+	assert.Zero(t, calls.Load())
+
+	// The webhooks of the configuration may be local.
+	resp, err = NewOutboundClient(5 * time.Second).Post(srv.URL, "application/json", strings.NewReader("{}"))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, int32(1), calls.Load())
+}
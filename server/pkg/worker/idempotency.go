@@ -0,0 +1,72 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package worker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/bus"
+)
+
+// completionTTL is how long the result of a request is kept after it is
+// done, to answer the deliveries of the request that come after.
+const completionTTL = 10 * time.Minute
+
+// completion is a request with an idempotency key, and its result once it is
+// done.
+type completion struct {
+	done   chan struct{}
+	result *bus.ToolExecutionResult
+	doneAt time.Time
+}
+
+// completions tracks the requests of the worker by idempotency key, so that
+// a request delivered more than once runs once.
+type completions struct {
+	ttl time.Duration
+
+	mu    sync.Mutex
+	calls map[string]*completion
+}
+
+func newCompletions(ttl time.Duration) *completions {
+	return &completions{ttl: ttl, calls: make(map[string]*completion)}
+}
+
+// begin claims a request. It returns true for its first delivery, and the
+// completion of the first delivery otherwise. The completions that expired
+// are dropped.
+func (c *completions) begin(key string) (*completion, bool) {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, call := range c.calls {
+		if call.result != nil && now.Sub(call.doneAt) > c.ttl {
+			delete(c.calls, k)
+		}
+	}
+	if call, ok := c.calls[key]; ok {
+		return call, false
+	}
+	c.calls[key] = &completion{done: make(chan struct{})}
+	return nil, true
+}
+
+// finish records the result of a request, and releases the deliveries that
+// wait for it. Unless keep is set, the request is forgotten, and runs again
+// when it is delivered again.
+func (c *completions) finish(key string, result *bus.ToolExecutionResult, keep bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	call, ok := c.calls[key]
+	if !ok || call.result != nil {
+		return
+	}
+	call.result, call.doneAt = result, time.Now()
+	close(call.done)
+	if !keep {
+		delete(c.calls, key)
+	}
+}
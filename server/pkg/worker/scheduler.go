@@ -0,0 +1,331 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package worker
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	armonmetrics "github.com/armon/go-metrics"
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/bus"
+	"github.com/mcpany/core/server/pkg/metrics"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrQueueFull is the error of the calls rejected by a full priority class.
+var ErrQueueFull = errors.New("worker queue is full")
+
+// ErrSchedulerStopped is the error of the calls queued when the worker stops.
+var ErrSchedulerStopped = errors.New("worker is stopped")
+
+const (
+	defaultMaxConcurrency = 64
+	defaultMaxQueueSize   = 1000
+	// strideUnit is the stride of a class of weight 1. A class of weight w
+	// advances by strideUnit/w each time it is served.
+	strideUnit = 1 << 20
+)
+
+var (
+	metricWorkerQueueDepth    = []string{"worker", "queue", "depth"}
+	metricWorkerQueueWait     = []string{"worker", "queue", "wait_seconds"}
+	metricWorkerQueueRejected = []string{"worker", "queue", "rejected"}
+)
+
+// task is a queued call.
+type task struct {
+	run      func()
+	reject   func(error)
+	queuedAt time.Time
+}
+
+// tenantQueue holds the queued calls of a tenant in a class.
+type tenantQueue struct {
+	name  string
+	tasks []*task
+}
+
+// priorityClass is a queue of calls, shared in turn by its tenants.
+type priorityClass struct {
+	name         string
+	stride       uint64
+	pass         uint64
+	maxQueue     int
+	maxPerTenant int
+	dropOldest   bool
+
+	tenants map[string]*tenantQueue
+	// turns are the tenants with queued calls, the next one first.
+	turns  []*tenantQueue
+	queued int
+}
+
+// Scheduler queues the calls of the upstream worker by priority class and
+// tenant, and runs them on a fixed number of workers.
+//
+// The classes share the workers by their weight, with stride scheduling: the
+// class served next is the one with queued calls that got the least service
+// for its weight, so a busy class never starves another. Within a class, the
+// tenants take turns, one call each.
+//
+// Summary: Schedules the worker calls fairly.
+type Scheduler struct {
+	concurrency int
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	classes []*priorityClass
+	byName  map[string]*priorityClass
+	// vtime is the pass of the class served last, where the classes that
+	// become busy start, so that an idle class does not bank service.
+	vtime   uint64
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// NewScheduler creates a scheduler from its configuration.
+//
+// Summary: Creates a worker scheduler.
+//
+// Parameters:
+//   - cfg (*configv1.WorkerConfig): The configuration, or nil for the
+//     defaults: an "interactive" class with a weight of 4, and a "batch"
+//     class with a weight of 1.
+//
+// Returns:
+//   - *Scheduler: The scheduler, not yet running.
+func NewScheduler(cfg *configv1.WorkerConfig) *Scheduler {
+	s := &Scheduler{
+		concurrency: int(cfg.GetMaxConcurrency()),
+		byName:      make(map[string]*priorityClass),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	if s.concurrency <= 0 {
+		s.concurrency = defaultMaxConcurrency
+	}
+	classes := cfg.GetPriorityClasses()
+	if len(classes) == 0 {
+		classes = []*configv1.WorkerPriorityClass{
+			configv1.WorkerPriorityClass_builder{Name: proto.String(bus.PriorityInteractive), Weight: proto.Int32(4)}.Build(),
+			configv1.WorkerPriorityClass_builder{Name: proto.String(bus.PriorityBatch), Weight: proto.Int32(1)}.Build(),
+		}
+	}
+	for _, c := range classes {
+		if _, ok := s.byName[c.GetName()]; ok {
+			continue
+		}
+		weight := uint64(c.GetWeight())
+		if weight == 0 {
+			weight = 1
+		}
+		maxQueue := int(c.GetMaxQueueSize())
+		if maxQueue <= 0 {
+			maxQueue = defaultMaxQueueSize
+		}
+		pc := &priorityClass{
+			name:         c.GetName(),
+			stride:       strideUnit / weight,
+			maxQueue:     maxQueue,
+			maxPerTenant: int(c.GetMaxQueueSizePerTenant()),
+			dropOldest:   c.GetRejectionPolicy() == configv1.WorkerPriorityClass_DROP_OLDEST,
+			tenants:      make(map[string]*tenantQueue),
+		}
+		s.classes = append(s.classes, pc)
+		s.byName[pc.name] = pc
+	}
+	return s
+}
+
+// Submit queues a call.
+//
+// Summary: Queues a call.
+//
+// Parameters:
+//   - class (string): The priority class of the call. An empty or unknown
+//     class selects the first class.
+//   - tenant (string): Who the call is made for.
+//   - run (func()): Runs the call, on a worker.
+//   - reject (func(error)): Fails the call if it is dropped from the queue
+//     later, by a DROP_OLDEST class or when the scheduler stops.
+//
+// Returns:
+//   - error: ErrQueueFull if the class is full and rejects new calls, or
+//     ErrSchedulerStopped. The call is not queued then.
+func (s *Scheduler) Submit(class, tenant string, run func(), reject func(error)) error {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return ErrSchedulerStopped
+	}
+	c, ok := s.byName[class]
+	if !ok {
+		c = s.classes[0]
+	}
+	tq := c.tenants[tenant]
+	var dropped *task
+	switch {
+	case c.maxPerTenant > 0 && tq != nil && len(tq.tasks) >= c.maxPerTenant:
+		if !c.dropOldest {
+			s.mu.Unlock()
+			s.recordRejected(c.name)
+			return ErrQueueFull
+		}
+		dropped = c.dropOldestOf(tq)
+	case c.queued >= c.maxQueue:
+		if !c.dropOldest {
+			s.mu.Unlock()
+			s.recordRejected(c.name)
+			return ErrQueueFull
+		}
+		dropped = c.dropOldestOf(c.longestTenant())
+	}
+
+	if c.queued == 0 && c.pass < s.vtime {
+		c.pass = s.vtime
+	}
+	if tq = c.tenants[tenant]; tq == nil {
+		tq = &tenantQueue{name: tenant}
+		c.tenants[tenant] = tq
+		c.turns = append(c.turns, tq)
+	}
+	tq.tasks = append(tq.tasks, &task{run: run, reject: reject, queuedAt: time.Now()})
+	c.queued++
+	c.recordDepth()
+	s.mu.Unlock()
+	s.cond.Signal()
+
+	if dropped != nil {
+		s.recordRejected(c.name)
+		dropped.reject(ErrQueueFull)
+	}
+	return nil
+}
+
+// Start runs the workers.
+//
+// Summary: Starts the workers.
+//
+// Side Effects:
+//   - Starts the worker goroutines, until Stop.
+func (s *Scheduler) Start() {
+	for range s.concurrency {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			for {
+				t, ok := s.next()
+				if !ok {
+					return
+				}
+				t.run()
+			}
+		}()
+	}
+}
+
+// Stop fails the queued calls, and waits for the running ones.
+//
+// Summary: Stops the workers.
+//
+// Side Effects:
+//   - Calls the reject function of the queued calls with ErrSchedulerStopped.
+//   - Blocks until the running calls are done.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	s.stopped = true
+	var queued []*task
+	for _, c := range s.classes {
+		for _, tq := range c.turns {
+			queued = append(queued, tq.tasks...)
+		}
+		c.turns, c.tenants, c.queued = nil, make(map[string]*tenantQueue), 0
+	}
+	s.mu.Unlock()
+	s.cond.Broadcast()
+	for _, t := range queued {
+		t.reject(ErrSchedulerStopped)
+	}
+	s.wg.Wait()
+}
+
+// next waits for the next call to run. It returns false once the scheduler
+// is stopped.
+func (s *Scheduler) next() (*task, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for {
+		if s.stopped {
+			return nil, false
+		}
+		var c *priorityClass
+		for _, candidate := range s.classes {
+			if candidate.queued > 0 && (c == nil || candidate.pass < c.pass) {
+				c = candidate
+			}
+		}
+		if c == nil {
+			s.cond.Wait()
+			continue
+		}
+		s.vtime = c.pass
+		c.pass += c.stride
+		t := c.pop()
+		metrics.AddSampleWithLabels(metricWorkerQueueWait, float32(time.Since(t.queuedAt).Seconds()), []armonmetrics.Label{{Name: "class", Value: c.name}})
+		return t, true
+	}
+}
+
+// pop takes the next call of the tenant whose turn it is.
+func (c *priorityClass) pop() *task {
+	tq := c.turns[0]
+	t := tq.tasks[0]
+	tq.tasks = tq.tasks[1:]
+	c.turns = c.turns[1:]
+	if len(tq.tasks) > 0 {
+		c.turns = append(c.turns, tq)
+	} else {
+		delete(c.tenants, tq.name)
+	}
+	c.queued--
+	c.recordDepth()
+	return t
+}
+
+// longestTenant returns the tenant with the most queued calls.
+func (c *priorityClass) longestTenant() *tenantQueue {
+	var longest *tenantQueue
+	for _, tq := range c.turns {
+		if longest == nil || len(tq.tasks) > len(longest.tasks) {
+			longest = tq
+		}
+	}
+	return longest
+}
+
+// dropOldestOf removes the oldest queued call of a tenant.
+func (c *priorityClass) dropOldestOf(tq *tenantQueue) *task {
+	t := tq.tasks[0]
+	tq.tasks = tq.tasks[1:]
+	c.queued--
+	if len(tq.tasks) == 0 {
+		delete(c.tenants, tq.name)
+		for i, other := range c.turns {
+			if other == tq {
+				c.turns = append(c.turns[:i], c.turns[i+1:]...)
+				break
+			}
+		}
+	}
+	return t
+}
+
+func (c *priorityClass) recordDepth() {
+	armonmetrics.SetGaugeWithLabels(metricWorkerQueueDepth, float32(c.queued), []armonmetrics.Label{{Name: "class", Value: c.name}})
+}
+
+func (s *Scheduler) recordRejected(class string) {
+	metrics.IncrCounterWithLabels(metricWorkerQueueRejected, 1, []armonmetrics.Label{{Name: "class", Value: class}})
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package worker
+
+import (
+	"sync"
+	"testing"
+
+	configv1 "github.com/mcpany/core/proto/config/v1"
+	"github.com/mcpany/core/server/pkg/bus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// runOrder queues the calls on a single worker held busy by a first call,
+// then releases it and returns the order the calls ran in.
+func runOrder(t *testing.T, s *Scheduler, submit func(record func(string) func())) []string {
+	t.Helper()
+	var mu sync.Mutex
+	var order []string
+	var done sync.WaitGroup
+	record := func(name string) func() {
+		done.Add(1)
+		return func() {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			done.Done()
+		}
+	}
+
+	gate, running := make(chan struct{}), make(chan struct{})
+	s.Start()
+	defer s.Stop()
+	require.NoError(t, s.Submit("", "", func() { close(running); <-gate }, nil))
+	<-running
+	submit(record)
+	close(gate)
+	done.Wait()
+	return order
+}
+
+func TestScheduler_ClassWeights(t *testing.T) {
+	s := NewScheduler(configv1.WorkerConfig_builder{MaxConcurrency: proto.Int32(1)}.Build())
+	order := runOrder(t, s, func(record func(string) func()) {
+		for range 10 {
+			require.NoError(t, s.Submit(bus.PriorityBatch, "", record("batch"), nil))
+		}
+		for range 10 {
+			require.NoError(t, s.Submit(bus.PriorityInteractive, "", record("interactive"), nil))
+		}
+	})
+	require.Len(t, order, 20)
+	interactive := 0
+	for _, name := range order[:10] {
+		if name == "interactive" {
+			interactive++
+		}
+	}
+	assert.Equal(t, 8, interactive, "interactive gets 4 calls for each batch call: %v", order)
+	assert.Contains(t, order[:5], "batch", "batch is not starved")
+}
+
+func TestScheduler_TenantsTakeTurns(t *testing.T) {
+	s := NewScheduler(configv1.WorkerConfig_builder{MaxConcurrency: proto.Int32(1)}.Build())
+	order := runOrder(t, s, func(record func(string) func()) {
+		for range 4 {
+			require.NoError(t, s.Submit(bus.PriorityBatch, "alice", record("alice"), nil))
+		}
+		for range 2 {
+			require.NoError(t, s.Submit(bus.PriorityBatch, "bob", record("bob"), nil))
+		}
+	})
+	assert.Equal(t, []string{"alice", "bob", "alice", "bob", "alice", "alice"}, order)
+}
+
+func TestScheduler_UnknownClass(t *testing.T) {
+	s := NewScheduler(nil)
+	require.NoError(t, s.Submit("unknown", "", func() {}, nil))
+	assert.Equal(t, 1, s.byName[bus.PriorityInteractive].queued, "an unknown class selects the first class")
+}
+
+func TestScheduler_RejectNew(t *testing.T) {
+	s := NewScheduler(configv1.WorkerConfig_builder{
+		PriorityClasses: []*configv1.WorkerPriorityClass{
+			configv1.WorkerPriorityClass_builder{Name: proto.String("batch"), MaxQueueSize: proto.Int32(2), MaxQueueSizePerTenant: proto.Int32(1)}.Build(),
+		},
+	}.Build())
+	require.NoError(t, s.Submit("batch", "alice", func() {}, nil))
+	assert.ErrorIs(t, s.Submit("batch", "alice", func() {}, nil), ErrQueueFull, "the tenant is full")
+	require.NoError(t, s.Submit("batch", "bob", func() {}, nil))
+	assert.ErrorIs(t, s.Submit("batch", "carol", func() {}, nil), ErrQueueFull, "the class is full")
+}
+
+func TestScheduler_DropOldest(t *testing.T) {
+	s := NewScheduler(configv1.WorkerConfig_builder{
+		PriorityClasses: []*configv1.WorkerPriorityClass{
+			configv1.WorkerPriorityClass_builder{
+				Name:            proto.String("batch"),
+				MaxQueueSize:    proto.Int32(3),
+				RejectionPolicy: configv1.WorkerPriorityClass_DROP_OLDEST.Enum(),
+			}.Build(),
+		},
+	}.Build())
+	var rejected []string
+	reject := func(name string) func(error) {
+		return func(err error) {
+			assert.ErrorIs(t, err, ErrQueueFull)
+			rejected = append(rejected, name)
+		}
+	}
+	require.NoError(t, s.Submit("batch", "alice", func() {}, reject("alice-1")))
+	require.NoError(t, s.Submit("batch", "alice", func() {}, reject("alice-2")))
+	require.NoError(t, s.Submit("batch", "bob", func() {}, reject("bob-1")))
+	require.NoError(t, s.Submit("batch", "bob", func() {}, reject("bob-2")))
+	assert.Equal(t, []string{"alice-1"}, rejected, "the oldest call of the tenant with the most calls is dropped")
+	assert.Equal(t, 3, s.byName["batch"].queued)
+}
+
+func TestScheduler_Stop(t *testing.T) {
+	s := NewScheduler(nil)
+	var rejected error
+	require.NoError(t, s.Submit("", "", func() { t.Error("a queued call does not run after Stop") }, func(err error) { rejected = err }))
+	s.Stop()
+	assert.ErrorIs(t, rejected, ErrSchedulerStopped)
+	assert.ErrorIs(t, s.Submit("", "", func() {}, nil), ErrSchedulerStopped)
+}
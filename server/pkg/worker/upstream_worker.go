@@ -6,9 +6,11 @@ package worker
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"sync"
 	"time"
 
+	configv1 "github.com/mcpany/core/proto/config/v1"
 	"github.com/mcpany/core/server/pkg/bus"
 	"github.com/mcpany/core/server/pkg/logging"
 	"github.com/mcpany/core/server/pkg/metrics"
@@ -18,10 +20,15 @@ import (
 // UpstreamWorker is a background worker that handles tool execution requests. It
 // listens for ToolExecutionRequest messages on the event bus, uses the
 // tool manager to execute the requested tool, and then publishes the outcome as
-// a ToolExecutionResult message.
+// a ToolExecutionResult message. The requests wait for a worker in the queues
+// of a Scheduler, by priority class and tenant. A request delivered again
+// with the same idempotency key is answered with the result of its first
+// delivery, without running the tool again.
 type UpstreamWorker struct {
 	bus         *bus.Provider
 	toolManager tool.ManagerInterface
+	config      *configv1.WorkerConfig
+	completions *completions
 	wg          sync.WaitGroup
 }
 
@@ -37,6 +44,7 @@ func NewUpstreamWorker(bus *bus.Provider, toolManager tool.ManagerInterface) *Up
 	return &UpstreamWorker{
 		bus:         bus,
 		toolManager: toolManager,
+		completions: newCompletions(completionTTL),
 	}
 }
 
@@ -54,38 +62,25 @@ func (w *UpstreamWorker) Start(ctx context.Context) {
 	requestBus, _ := bus.GetBus[*bus.ToolExecutionRequest](w.bus, bus.ToolExecutionRequestTopic)
 	resultBus, _ := bus.GetBus[*bus.ToolExecutionResult](w.bus, bus.ToolExecutionResultTopic)
 
+	scheduler := NewScheduler(w.config)
+	scheduler.Start()
 	unsubscribe := requestBus.Subscribe(ctx, "request", func(req *bus.ToolExecutionRequest) {
-		start := time.Now()
 		metrics.IncrCounter([]string{"worker", "upstream", "request", "total"}, 1)
-		defer metrics.MeasureSince([]string{"worker", "upstream", "request", "latency"}, start)
-		log.Info("Received tool execution request", "tool", req.ToolName, "correlationID", req.CorrelationID())
-		result, err := w.toolManager.ExecuteTool(req.Context, &tool.ExecutionRequest{
-			ToolName:   req.ToolName,
-			ToolInputs: req.ToolInputs,
-		})
-
-		var resultBytes json.RawMessage
-		if result != nil {
-			var marshalErr error
-			resultBytes, marshalErr = json.Marshal(result)
-			if marshalErr != nil {
-				log.Error("Failed to marshal tool execution result", "error", marshalErr)
-				err = marshalErr
+		log.Info("Received tool execution request", "tool", req.ToolName, "correlationID", req.CorrelationID(), "priorityClass", req.PriorityClass)
+		if req.IdempotencyKey != "" {
+			if first, ok := w.completions.begin(req.IdempotencyKey); !ok {
+				metrics.IncrCounter([]string{"worker", "upstream", "request", "duplicate"}, 1)
+				log.Info("Tool execution request delivered again", "tool", req.ToolName, "correlationID", req.CorrelationID(), "idempotencyKey", req.IdempotencyKey)
+				go w.answerDuplicate(ctx, resultBus, req, first)
+				return
 			}
 		}
-
-		res := &bus.ToolExecutionResult{
-			Result: resultBytes,
-			Error:  err,
-		}
-		if err != nil {
-			metrics.IncrCounter([]string{"worker", "upstream", "request", "error"}, 1)
-		} else {
-			metrics.IncrCounter([]string{"worker", "upstream", "request", "success"}, 1)
+		reject := func(err error) {
+			log.Warn("Tool execution request rejected", "tool", req.ToolName, "correlationID", req.CorrelationID(), "error", err)
+			w.publishResult(ctx, resultBus, req, nil, err)
 		}
-		res.SetCorrelationID(req.CorrelationID())
-		if err := resultBus.Publish(ctx, req.CorrelationID(), res); err != nil {
-			log.Error("Failed to publish tool execution result", "error", err)
+		if err := scheduler.Submit(req.PriorityClass, req.Tenant, func() { w.execute(ctx, resultBus, req) }, reject); err != nil {
+			reject(err)
 		}
 	})
 
@@ -94,9 +89,82 @@ func (w *UpstreamWorker) Start(ctx context.Context) {
 		<-ctx.Done()
 		log.Info("Upstream worker stopping")
 		unsubscribe()
+		scheduler.Stop()
 	}()
 }
 
+// SetConfig sets the scheduling of the calls. It must be called before Start.
+//
+// Parameters:
+//   - cfg: The scheduling configuration, or nil for the defaults.
+func (w *UpstreamWorker) SetConfig(cfg *configv1.WorkerConfig) {
+	w.config = cfg
+}
+
+// execute runs a tool execution request, and publishes its result.
+func (w *UpstreamWorker) execute(ctx context.Context, resultBus bus.Bus[*bus.ToolExecutionResult], req *bus.ToolExecutionRequest) {
+	start := time.Now()
+	defer metrics.MeasureSince([]string{"worker", "upstream", "request", "latency"}, start)
+	// The requests from another process do not carry their context
+	execCtx := req.Context
+	if execCtx == nil {
+		execCtx = ctx
+	}
+	result, err := w.toolManager.ExecuteTool(tool.NewContextWithJob(execCtx, req.CorrelationID()), &tool.ExecutionRequest{
+		ToolName:   req.ToolName,
+		ToolInputs: req.ToolInputs,
+	})
+	w.publishResult(ctx, resultBus, req, result, err)
+}
+
+// publishResult publishes the outcome of a tool execution request.
+func (w *UpstreamWorker) publishResult(ctx context.Context, resultBus bus.Bus[*bus.ToolExecutionResult], req *bus.ToolExecutionRequest, result any, err error) {
+	log := logging.GetLogger().With("component", "UpstreamWorker")
+	var resultBytes json.RawMessage
+	if result != nil {
+		var marshalErr error
+		resultBytes, marshalErr = json.Marshal(result)
+		if marshalErr != nil {
+			log.Error("Failed to marshal tool execution result", "error", marshalErr)
+			err = marshalErr
+		}
+	}
+
+	res := &bus.ToolExecutionResult{
+		Result: resultBytes,
+		Error:  err,
+	}
+	if err != nil {
+		metrics.IncrCounter([]string{"worker", "upstream", "request", "error"}, 1)
+	} else {
+		metrics.IncrCounter([]string{"worker", "upstream", "request", "success"}, 1)
+	}
+	res.SetCorrelationID(req.CorrelationID())
+	if req.IdempotencyKey != "" {
+		// A request rejected by the queues runs if it is delivered again
+		rejected := errors.Is(err, ErrQueueFull) || errors.Is(err, ErrSchedulerStopped)
+		w.completions.finish(req.IdempotencyKey, res, !rejected)
+	}
+	if err := resultBus.Publish(ctx, req.CorrelationID(), res); err != nil {
+		log.Error("Failed to publish tool execution result", "error", err)
+	}
+}
+
+// answerDuplicate publishes the result of the first delivery of a request for
+// a delivery that came after, once it is done.
+func (w *UpstreamWorker) answerDuplicate(ctx context.Context, resultBus bus.Bus[*bus.ToolExecutionResult], req *bus.ToolExecutionRequest, first *completion) {
+	select {
+	case <-first.done:
+	case <-ctx.Done():
+		return
+	}
+	res := &bus.ToolExecutionResult{Result: first.result.Result, Error: first.result.Error}
+	res.SetCorrelationID(req.CorrelationID())
+	if err := resultBus.Publish(ctx, req.CorrelationID(), res); err != nil {
+		logging.GetLogger().With("component", "UpstreamWorker").Error("Failed to publish tool execution result", "error", err)
+	}
+}
+
 // Stop waits for the worker to stop.
 //
 // Parameters:
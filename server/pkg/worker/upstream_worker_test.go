@@ -14,6 +14,7 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 )
 
 // MockToolManager is a simple mock for tool.ManagerInterface
@@ -69,3 +70,126 @@ func (m *MockToolManager) GetAllowedServiceIDs(_ string) (map[string]bool, bool)
 func (m *MockToolManager) GetToolCountForService(serviceID string) int {
 	return 0
 }
+
+// blockingToolManager holds its calls until release is closed.
+type blockingToolManager struct {
+	MockToolManager
+	started chan struct{}
+	release chan struct{}
+}
+
+func (m *blockingToolManager) ExecuteTool(_ context.Context, _ *tool.ExecutionRequest) (any, error) {
+	m.started <- struct{}{}
+	<-m.release
+	return "done", nil
+}
+
+func TestUpstreamWorker_QueueFull(t *testing.T) {
+	b, err := bus.NewProvider(nil)
+	require.NoError(t, err)
+	toolManager := &blockingToolManager{started: make(chan struct{}, 2), release: make(chan struct{})}
+	w := NewUpstreamWorker(b, toolManager)
+	w.SetConfig(configv1.WorkerConfig_builder{
+		MaxConcurrency: proto.Int32(1),
+		PriorityClasses: []*configv1.WorkerPriorityClass{
+			configv1.WorkerPriorityClass_builder{Name: proto.String("batch"), MaxQueueSize: proto.Int32(1)}.Build(),
+		},
+	}.Build())
+	ctx, cancel := context.WithCancel(context.Background())
+	w.Start(ctx)
+	defer func() {
+		cancel()
+		w.Stop()
+	}()
+
+	requestBus, err := bus.GetBus[*bus.ToolExecutionRequest](b, bus.ToolExecutionRequestTopic)
+	require.NoError(t, err)
+	resultBus, err := bus.GetBus[*bus.ToolExecutionResult](b, bus.ToolExecutionResultTopic)
+	require.NoError(t, err)
+	results := make(chan *bus.ToolExecutionResult, 3)
+	for _, id := range []string{"running", "queued", "rejected"} {
+		unsubscribe := resultBus.SubscribeOnce(ctx, id, func(res *bus.ToolExecutionResult) { results <- res })
+		defer unsubscribe()
+	}
+	publish := func(id string) {
+		req := &bus.ToolExecutionRequest{Context: context.Background(), ToolName: "svc.tool", PriorityClass: "batch"}
+		req.SetCorrelationID(id)
+		require.NoError(t, requestBus.Publish(ctx, "request", req))
+	}
+
+	publish("running")
+	<-toolManager.started
+	publish("queued")
+	publish("rejected")
+
+	// The two requests reach the queue in any order; the second one is rejected
+	select {
+	case res := <-results:
+		assert.Contains(t, []string{"queued", "rejected"}, res.CorrelationID())
+		assert.ErrorIs(t, res.Error, ErrQueueFull)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the request is not rejected")
+	}
+	close(toolManager.release)
+	for range 2 {
+		select {
+		case res := <-results:
+			assert.NoError(t, res.Error)
+			assert.JSONEq(t, `"done"`, string(res.Result))
+		case <-time.After(5 * time.Second):
+			t.Fatal("the requests do not run")
+		}
+	}
+}
+
+func TestUpstreamWorker_IdempotencyKey(t *testing.T) {
+	b, err := bus.NewProvider(nil)
+	require.NoError(t, err)
+	toolManager := &blockingToolManager{started: make(chan struct{}, 3), release: make(chan struct{})}
+	w := NewUpstreamWorker(b, toolManager)
+	ctx, cancel := context.WithCancel(context.Background())
+	w.Start(ctx)
+	defer func() {
+		cancel()
+		w.Stop()
+	}()
+
+	requestBus, err := bus.GetBus[*bus.ToolExecutionRequest](b, bus.ToolExecutionRequestTopic)
+	require.NoError(t, err)
+	resultBus, err := bus.GetBus[*bus.ToolExecutionResult](b, bus.ToolExecutionResultTopic)
+	require.NoError(t, err)
+	results := make(chan *bus.ToolExecutionResult, 3)
+	for _, id := range []string{"first", "again", "later"} {
+		unsubscribe := resultBus.SubscribeOnce(ctx, id, func(res *bus.ToolExecutionResult) { results <- res })
+		defer unsubscribe()
+	}
+	publish := func(id string) {
+		req := &bus.ToolExecutionRequest{Context: context.Background(), ToolName: "svc.tool", IdempotencyKey: "job-1"}
+		req.SetCorrelationID(id)
+		require.NoError(t, requestBus.Publish(ctx, "request", req))
+	}
+
+	publish("first")
+	<-toolManager.started
+	publish("again")
+	close(toolManager.release)
+	for range 2 {
+		select {
+		case res := <-results:
+			assert.NoError(t, res.Error)
+			assert.JSONEq(t, `"done"`, string(res.Result))
+		case <-time.After(5 * time.Second):
+			t.Fatal("the deliveries are not answered")
+		}
+	}
+
+	publish("later")
+	select {
+	case res := <-results:
+		assert.Equal(t, "later", res.CorrelationID())
+		assert.JSONEq(t, `"done"`, string(res.Result))
+	case <-time.After(5 * time.Second):
+		t.Fatal("the delivery is not answered")
+	}
+	assert.Empty(t, toolManager.started, "the tool runs once")
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -137,6 +138,8 @@ global_settings:
 	defer conn.Close()
 
 	adminClient := pb_admin.NewAdminServiceClient(conn)
+	// The admin service requires the admin role: authenticate with the global API key.
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", "test-api-key")
 
 	// Create initial user
 	userID := fmt.Sprintf("e2e-test-user-%d", time.Now().UnixNano())
@@ -36,8 +36,7 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 
-	"github.com/mcpany/core/server/pkg/app"
-	"github.com/spf13/afero"
+	"github.com/mcpany/core/server/pkg/testkit"
 )
 
 // CreateTempConfigFile creates a temporary configuration file for the configured upstream service.
@@ -784,11 +783,8 @@ type WebsocketEchoServerInfo struct {
 func StartWebsocketEchoServer(t *testing.T) *WebsocketEchoServerInfo {
 	t.Helper()
 
-	port := FindFreePort(t)
-	addr := net.JoinHostPort(loopbackIP, strconv.Itoa(port))
-
 	upgrader := websocket.Upgrader{}
-	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	server := testkit.NewHTTPUpstream(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		c, err := upgrader.Upgrade(w, r, nil)
 		if err != nil {
 			t.Logf("Websocket upgrade error: %v", err)
@@ -810,32 +806,11 @@ func StartWebsocketEchoServer(t *testing.T) *WebsocketEchoServerInfo {
 				break
 			}
 		}
-	})
-
-	server := &http.Server{
-		Addr:              addr,
-		Handler:           handler,
-		ReadHeaderTimeout: 5 * time.Second,
-	}
-
-	go func() {
-		if err := server.ListenAndServe(); err != http.ErrServerClosed {
-			t.Logf("Websocket echo server ListenAndServe error: %v", err)
-		}
-	}()
-
-	// Wait for the server to be ready
-	WaitForTCPPort(t, port, 5*time.Second)
+	}))
 
 	return &WebsocketEchoServerInfo{
-		URL: "ws://" + addr,
-		CleanupFunc: func() {
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-			if err := server.Shutdown(ctx); err != nil {
-				t.Logf("Websocket echo server shutdown error: %v", err)
-			}
-		},
+		URL:         "ws://" + strings.TrimPrefix(server.URL, "http://"),
+		CleanupFunc: server.Close,
 	}
 }
 
@@ -880,6 +855,8 @@ func StartMCPANYServerWithNoHealthCheck(t *testing.T, testName string, extraArgs
 }
 
 // StartInProcessMCPANYServer starts an in-process MCP Any server for testing.
+// It is a wrapper around testkit.StartInstance; the server is shut down on
+// test cleanup.
 //
 // t is the t.
 // _ is an unused parameter.
@@ -894,124 +871,27 @@ func StartInProcessMCPANYServer(t *testing.T, _ string, apiKey ...string) *MCPAN
 		actualAPIKey = apiKey[0]
 	}
 
-	_, err := GetProjectRoot()
-	require.NoError(t, err, "Failed to get project root")
-
-	// Use port 0 for dynamic allocation to avoid race conditions
-	jsonrpcAddress := ":0"
-	grpcRegAddress := ":0"
-
-	ctx, cancel := context.WithCancel(context.Background())
-
-	// Use unique DB path to avoid SQLITE_BUSY conflicts
-	dbFile, err := os.CreateTemp(t.TempDir(), "mcpany-in-process-*.db")
-	require.NoError(t, err)
-	dbPath := dbFile.Name()
-	require.NoError(t, dbFile.Close())
-	// Do not use t.Setenv("MCPANY_DB_PATH", dbPath) to avoid race conditions in parallel tests
-
-	appRunner := app.NewApplication()
-	runErrCh := make(chan error, 1)
-	go func() {
-		defer cancel() // Ensure WaitForStartup doesn't hang if Run returns
-		opts := app.RunOptions{
-			Ctx:             ctx,
-			Fs:              afero.NewOsFs(),
-			Stdio:           false,
-			JSONRPCPort:     jsonrpcAddress,
-			GRPCPort:        grpcRegAddress,
-			ConfigPaths:     []string{},
-			APIKey:          actualAPIKey,
-			ShutdownTimeout: 5 * time.Second,
-			DBPath:          dbPath,
-		}
-		err := appRunner.Run(opts)
-		if err != nil {
-			if ctx.Err() == nil {
-				t.Logf("Application run error: %v", err)
-			}
-			runErrCh <- err
-		}
-		close(runErrCh)
-	}()
-
-	// Wait for startup or failure
-	startupErrCh := make(chan error, 1)
-	go func() {
-		startupErrCh <- appRunner.WaitForStartup(ctx)
-	}()
+	instance := testkit.StartInstance(t, testkit.InstanceOptions{
+		APIKey:         actualAPIKey,
+		StartupTimeout: McpAnyServerStartupTimeout,
+	})
 
-	select {
-	case err := <-startupErrCh:
-		require.NoError(t, err, "Failed to wait for application startup")
-	case err := <-runErrCh:
-		require.NoError(t, err, "Application run failed prematurely")
-	case <-time.After(McpAnyServerStartupTimeout):
-		require.Fail(t, "Startup timed out")
-	}
-
-	// Retrieve dynamically allocated ports
-	jsonrpcPort := int(appRunner.BoundHTTPPort.Load())
-	grpcRegPort := int(appRunner.BoundGRPCPort.Load())
-
-	// Fallback/Safety check
-	if jsonrpcPort == 0 || grpcRegPort == 0 {
-		// Retry logic or fail? WaitForStartup should guarantee connection, but let's check.
-		// If WaitForStartup uses HTTP health check internally using the configured port...
-		// Wait, WaitForStartup in app.go checks readiness.
-		// But app.Run populates Bound*Port BEFORE calling readiness check loop?
-		// Let's assume so.
-		t.Logf("Warning: Bound ports are 0 after startup. HTTP: %d, gRPC: %d", jsonrpcPort, grpcRegPort)
-	}
-
-	jsonrpcEndpoint := fmt.Sprintf("http://%s:%d", loopbackIP, jsonrpcPort)
-	grpcRegEndpoint := net.JoinHostPort(loopbackIP, strconv.Itoa(grpcRegPort))
-	mcpRequestURL := jsonrpcEndpoint + "/mcp"
+	mcpRequestURL := instance.MCPEndpoint
 	if actualAPIKey != "" {
 		mcpRequestURL += "?api_key=" + actualAPIKey
 	}
 
-	// Verify gRPC connection
-	var grpcRegConn *grpc.ClientConn
-	require.Eventually(t, func() bool {
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-		defer cancel()
-		var errDial error
-		grpcRegConn, errDial = grpc.NewClient(grpcRegEndpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
-		if errDial != nil {
-			t.Logf("MCPANY gRPC registration endpoint at %s not ready, error creating client: %v", grpcRegEndpoint, errDial)
-			return false
-		}
-		state := grpcRegConn.GetState()
-		if state == connectivity.Ready || state == connectivity.Idle {
-			t.Logf("Successfully connected to MCPANY gRPC registration endpoint at %s with state %s", grpcRegEndpoint, state)
-			return true
-		}
-		if !grpcRegConn.WaitForStateChange(ctx, state) {
-			t.Logf("MCPANY gRPC registration endpoint at %s did not transition from %s", grpcRegEndpoint, state)
-			_ = grpcRegConn.Close()
-			return false
-		}
-		t.Logf("Successfully connected to MCPANY gRPC registration endpoint at %s", grpcRegEndpoint)
-		return true
-	}, McpAnyServerStartupTimeout, RetryInterval, "MCPANY gRPC registration endpoint at %s did not become healthy in time", grpcRegEndpoint)
-
-	registrationClient := apiv1.NewRegistrationServiceClient(grpcRegConn)
-
 	return &MCPANYTestServerInfo{
-		JSONRPCEndpoint:          jsonrpcEndpoint,
+		JSONRPCEndpoint:          instance.HTTPEndpoint,
 		HTTPEndpoint:             mcpRequestURL,
-		GrpcRegistrationEndpoint: grpcRegEndpoint,
+		GrpcRegistrationEndpoint: instance.GRPCEndpoint,
 		HTTPClient:               &http.Client{Timeout: 2 * time.Second},
-		GRPCRegConn:              grpcRegConn,
-		RegistrationClient:       registrationClient,
-		CleanupFunc: func() {
-			cancel()
-			if grpcRegConn != nil {
-				_ = grpcRegConn.Close()
-			}
-		},
-		T: t,
+		GRPCRegConn:              instance.GRPCConn,
+		RegistrationClient:       instance.RegistrationClient,
+		// The instance is shut down on test cleanup.
+		CleanupFunc: func() {},
+		T:           t,
+		APIKey:      actualAPIKey,
 	}
 }
 
@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gorilla/websocket"
 	configv1 "github.com/mcpany/core/proto/config/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -61,6 +62,21 @@ func TestWaitForText(t *testing.T) {
 	mp.Stop()
 }
 
+func TestStartWebsocketEchoServer(t *testing.T) {
+	t.Parallel()
+	server := StartWebsocketEchoServer(t)
+	defer server.CleanupFunc()
+
+	conn, resp, err := websocket.DefaultDialer.Dial(server.URL, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	defer func() { _ = conn.Close() }()
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("hello")))
+	_, message, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(message))
+}
+
 func TestDockerHelpers(t *testing.T) {
 	if os.Getenv("CI") == "true" || os.Getenv("GITHUB_ACTIONS") == "true" {
 		t.Log("Skipping TestDockerHelpers in CI environment (CI/GITHUB_ACTIONS=true)")
@@ -4,47 +4,24 @@
 package integration
 
 import (
-	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/mcpany/core/server/pkg/testkit"
 )
 
 // StartMockServer starts a new mock server with the provided handler.
-// The caller is responsible for calling Close() on the returned server.
+// The server is closed automatically on test cleanup; calling Close() earlier is safe.
 func StartMockServer(t *testing.T, handler http.Handler) *httptest.Server {
 	t.Helper()
-	server := httptest.NewServer(handler)
-	t.Logf("Started mock server at %s", server.URL)
-	return server
+	return testkit.NewHTTPUpstream(t, handler)
 }
 
 // DefaultMockHandler provides a simple way to define responses for specific paths.
 // It maps path -> response body (string or bytes).
 func DefaultMockHandler(t *testing.T, responses map[string]string) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		bodyBytes, _ := io.ReadAll(r.Body)
-		t.Logf("Mock server received request: %s %s Body: %s", r.Method, r.URL.RequestURI(), string(bodyBytes))
-
-		// Check exact path match including query string
-		if body, ok := responses[r.URL.RequestURI()]; ok {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte(body))
-			return
-		}
-
-		// Fallback to check path only match
-		if body, ok := responses[r.URL.Path]; ok {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte(body))
-			return
-		}
-
-		t.Logf("Mock server: no response found for %s", r.URL.RequestURI())
-		http.NotFound(w, r)
-	})
+	return testkit.StaticResponses(t, responses)
 }
 
 // CreateMockServerWithResponses is a convenience function to start a server with static responses.
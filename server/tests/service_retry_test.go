@@ -253,6 +253,69 @@ func (m *MockStorage) SaveCredential(ctx context.Context, cred *configv1.Credent
 	return nil
 }
 func (m *MockStorage) DeleteCredential(ctx context.Context, id string) error { return nil }
+func (m *MockStorage) ListAPIKeys(ctx context.Context) ([]*configv1.ClientApiKey, error) {
+	return nil, nil
+}
+func (m *MockStorage) GetAPIKey(ctx context.Context, id string) (*configv1.ClientApiKey, error) {
+	return nil, nil
+}
+func (m *MockStorage) SaveAPIKey(ctx context.Context, key *configv1.ClientApiKey) error {
+	return nil
+}
+func (m *MockStorage) DeleteAPIKey(ctx context.Context, id string) error {
+	return nil
+}
+func (m *MockStorage) SaveConfigVersion(ctx context.Context, version *configv1.ConfigVersion) error {
+	return nil
+}
+func (m *MockStorage) ListConfigVersions(ctx context.Context, limit int) ([]*configv1.ConfigVersion, error) {
+	return nil, nil
+}
+func (m *MockStorage) GetConfigVersion(ctx context.Context, version int64) (*configv1.ConfigVersion, error) {
+	return nil, nil
+}
+func (m *MockStorage) PruneConfigVersions(ctx context.Context, keep int) error {
+	return nil
+}
+func (m *MockStorage) SaveToolCatalogSnapshot(ctx context.Context, snapshot *configv1.ToolCatalogSnapshot) error {
+	return nil
+}
+func (m *MockStorage) ListToolCatalogSnapshots(ctx context.Context) ([]*configv1.ToolCatalogSnapshot, error) {
+	return nil, nil
+}
+func (m *MockStorage) DeleteToolCatalogSnapshot(ctx context.Context, serviceID string) error {
+	return nil
+}
+func (m *MockStorage) SaveWebhookDeadLetter(ctx context.Context, letter *configv1.WebhookDeadLetter) error {
+	return nil
+}
+func (m *MockStorage) ListWebhookDeadLetters(ctx context.Context, limit int) ([]*configv1.WebhookDeadLetter, error) {
+	return nil, nil
+}
+func (m *MockStorage) GetWebhookDeadLetter(ctx context.Context, id string) (*configv1.WebhookDeadLetter, error) {
+	return nil, nil
+}
+func (m *MockStorage) DeleteWebhookDeadLetter(ctx context.Context, id string) error {
+	return nil
+}
+func (m *MockStorage) SaveWorkerJob(ctx context.Context, job *configv1.WorkerJob) error {
+	return nil
+}
+func (m *MockStorage) ListWorkerJobs(ctx context.Context, limit int) ([]*configv1.WorkerJob, error) {
+	return nil, nil
+}
+func (m *MockStorage) GetWorkerJob(ctx context.Context, id string) (*configv1.WorkerJob, error) {
+	return nil, nil
+}
+func (m *MockStorage) ClaimWorkerJob(ctx context.Context, id, owner, now, leaseUntil string) (bool, error) {
+	return false, nil
+}
+func (m *MockStorage) DeleteWorkerJob(ctx context.Context, id string) error {
+	return nil
+}
+func (m *MockStorage) DeleteWorkerJobsCompletedBefore(ctx context.Context, before string) error {
+	return nil
+}
 func (m *MockStorage) SaveGlobalSettings(ctx context.Context, settings *configv1.GlobalSettings) error {
 	return nil
 }
@@ -0,0 +1,144 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build soak
+
+package soak
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mcpany/core/server/pkg/testkit"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSoak_MixedWorkload runs an in-process MCP Any instance against MCP and
+// HTTP upstreams and mixes short-lived sessions, long-lived sessions and
+// tool calls. Because the instance shares the test process, its goroutines,
+// heap and descriptors are what gets sampled.
+//
+// Run with: go test -tags soak -timeout 0 ./tests/soak/ (see MCPANY_SOAK_* for tuning).
+func TestSoak_MixedWorkload(t *testing.T) {
+	cfg, err := ConfigFromEnv()
+	require.NoError(t, err)
+	t.Setenv("MCPANY_ALLOW_LOOPBACK_RESOURCES", "true")
+	t.Setenv("MCPANY_TRUST_PROXY", "true")
+
+	echo := mcp.NewServer(&mcp.Implementation{Name: "echo", Version: "v1"}, nil)
+	type echoArgs struct {
+		Message string `json:"message"`
+	}
+	mcp.AddTool(echo, &mcp.Tool{Name: "echo"}, func(_ context.Context, _ *mcp.CallToolRequest, args echoArgs) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: args.Message}}}, nil, nil
+	})
+	mcpUpstream := testkit.NewMCPUpstream(t, echo)
+	httpUpstream := testkit.NewHTTPUpstream(t, testkit.StaticResponses(t, map[string]string{
+		"/status": `{"status":"ok"}`,
+	}))
+
+	instance := testkit.StartInstance(t, testkit.InstanceOptions{
+		APIKey: "soak-test-api-key",
+		Config: fmt.Sprintf(`
+upstream_services:
+  - name: echo
+    mcp_service:
+      tool_auto_discovery: true
+      http_connection:
+        http_address: %q
+  - name: status
+    http_service:
+      address: %q
+      tools:
+        - name: get_status
+          call_id: get_status
+      calls:
+        get_status:
+          endpoint_path: /status
+          method: HTTP_METHOD_GET
+`, mcpUpstream.URL, httpUpstream.URL),
+	})
+
+	echoTool, statusTool := waitForTools(t, instance.Connect(t))
+
+	// Each worker presents a distinct client address so the per-IP HTTP rate
+	// limit applies per simulated client rather than to the whole run.
+	headers := make([]http.Header, cfg.Concurrency)
+	sessions := make([]*mcp.ClientSession, cfg.Concurrency)
+	for i := range headers {
+		headers[i] = http.Header{}
+		headers[i].Set("X-API-Key", instance.APIKey)
+		headers[i].Set("X-Forwarded-For", fmt.Sprintf("10.0.%d.%d", i/250, i%250+1))
+		if i%3 != 0 {
+			session, err := connect(context.Background(), instance.MCPEndpoint, headers[i])
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = session.Close() })
+			sessions[i] = session
+		}
+	}
+
+	Run(t, cfg, func(ctx context.Context, worker int) error {
+		switch worker % 3 {
+		case 0:
+			// Short-lived session: catches per-connection leaks.
+			session, err := connect(ctx, instance.MCPEndpoint, headers[worker])
+			if err != nil {
+				return err
+			}
+			defer func() { _ = session.Close() }()
+			_, err = session.CallTool(ctx, &mcp.CallToolParams{Name: echoTool, Arguments: map[string]any{"message": "soak"}})
+			return err
+		case 1:
+			_, err := sessions[worker].CallTool(ctx, &mcp.CallToolParams{Name: statusTool, Arguments: map[string]any{}})
+			return err
+		default:
+			_, err := sessions[worker].ListTools(ctx, nil)
+			return err
+		}
+	})
+}
+
+func waitForTools(t *testing.T, session *mcp.ClientSession) (echoTool, statusTool string) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		tools, err := session.ListTools(context.Background(), nil)
+		if err != nil {
+			return false
+		}
+		for _, tool := range tools.Tools {
+			switch tool.Name {
+			case "echo.echo":
+				echoTool = tool.Name
+			case "status.get_status":
+				statusTool = tool.Name
+			}
+		}
+		return echoTool != "" && statusTool != ""
+	}, 30*time.Second, 200*time.Millisecond)
+	return echoTool, statusTool
+}
+
+func connect(ctx context.Context, endpoint string, header http.Header) (*mcp.ClientSession, error) {
+	client := mcp.NewClient(&mcp.Implementation{Name: "soak", Version: "v1"}, nil)
+	return client.Connect(ctx, &mcp.StreamableClientTransport{
+		Endpoint:   endpoint,
+		HTTPClient: &http.Client{Transport: &headerTransport{header: header}},
+		MaxRetries: -1,
+	}, nil)
+}
+
+type headerTransport struct {
+	header http.Header
+}
+
+func (h *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range h.header {
+		req.Header[k] = v
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
@@ -0,0 +1,291 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+// Package soak provides a long-running endurance harness that drives a
+// workload while sampling goroutines, heap and file descriptors, and fails
+// when any of them grows monotonically. It targets leaks that only surface
+// after hours or days of traffic.
+package soak
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Config configures a soak run.
+type Config struct {
+	// Duration is how long the workload runs.
+	Duration time.Duration
+	// SampleInterval is how often resource usage is sampled.
+	SampleInterval time.Duration
+	// WarmUp is excluded from leak detection so caches and pools can fill.
+	WarmUp time.Duration
+	// Concurrency is the number of workers running the workload.
+	Concurrency int
+	// OpsPerSecond caps the total workload rate across workers. Zero means
+	// unlimited.
+	OpsPerSecond float64
+	// Windows is the number of consecutive windows the samples are split
+	// into. A resource leaks if its median grows in every window.
+	Windows int
+	// GoroutineSlack is the absolute goroutine growth tolerated end to end.
+	GoroutineSlack float64
+	// HeapSlack is the relative heap growth tolerated end to end (0.2 = 20%).
+	HeapSlack float64
+	// FDSlack is the absolute file descriptor growth tolerated end to end.
+	FDSlack float64
+}
+
+// DefaultConfig returns the defaults used when no environment overrides are set.
+//
+// Returns:
+//   - Config: The default configuration.
+func DefaultConfig() Config {
+	return Config{
+		Duration:       time.Minute,
+		SampleInterval: time.Second,
+		WarmUp:         10 * time.Second,
+		Concurrency:    8,
+		OpsPerSecond:   20,
+		Windows:        4,
+		GoroutineSlack: 20,
+		HeapSlack:      0.25,
+		FDSlack:        10,
+	}
+}
+
+// ConfigFromEnv returns DefaultConfig overridden by MCPANY_SOAK_DURATION,
+// MCPANY_SOAK_SAMPLE_INTERVAL, MCPANY_SOAK_WARMUP, MCPANY_SOAK_CONCURRENCY and
+// MCPANY_SOAK_OPS_PER_SECOND.
+//
+// Returns:
+//   - Config: The configuration.
+//   - error: An error if an override cannot be parsed.
+func ConfigFromEnv() (Config, error) {
+	cfg := DefaultConfig()
+	durations := map[string]*time.Duration{
+		"MCPANY_SOAK_DURATION":        &cfg.Duration,
+		"MCPANY_SOAK_SAMPLE_INTERVAL": &cfg.SampleInterval,
+		"MCPANY_SOAK_WARMUP":          &cfg.WarmUp,
+	}
+	for env, dst := range durations {
+		if v := os.Getenv(env); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return cfg, fmt.Errorf("invalid %s: %w", env, err)
+			}
+			*dst = d
+		}
+	}
+	if v := os.Getenv("MCPANY_SOAK_CONCURRENCY"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return cfg, fmt.Errorf("invalid MCPANY_SOAK_CONCURRENCY: %q", v)
+		}
+		cfg.Concurrency = n
+	}
+	if v := os.Getenv("MCPANY_SOAK_OPS_PER_SECOND"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 {
+			return cfg, fmt.Errorf("invalid MCPANY_SOAK_OPS_PER_SECOND: %q", v)
+		}
+		cfg.OpsPerSecond = f
+	}
+	return cfg, nil
+}
+
+// Sample is a point-in-time measurement of process resources.
+type Sample struct {
+	Time       time.Time
+	Goroutines int
+	HeapAlloc  uint64
+	// OpenFDs is the number of open file descriptors, or -1 if unsupported.
+	OpenFDs int
+}
+
+// TakeSample measures the current process. It forces a GC first so heap
+// figures reflect live memory rather than garbage.
+//
+// Returns:
+//   - Sample: The measurement.
+func TakeSample() Sample {
+	runtime.GC()
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	return Sample{
+		Time:       time.Now(),
+		Goroutines: runtime.NumGoroutine(),
+		HeapAlloc:  mem.HeapAlloc,
+		OpenFDs:    countOpenFDs(),
+	}
+}
+
+func countOpenFDs() int {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return -1
+	}
+	return len(entries)
+}
+
+// Workload is one unit of work. It is called repeatedly by each worker
+// until the run ends; ctx is cancelled when the run is over.
+type Workload func(ctx context.Context, worker int) error
+
+// Report summarizes a soak run.
+type Report struct {
+	Samples    []Sample
+	Operations int64
+	Errors     int64
+	// Leaks lists the resources that grew monotonically.
+	Leaks []error
+}
+
+// Run drives workload for cfg.Duration, samples resource usage and reports
+// leaks and workload errors as test failures.
+//
+// Parameters:
+//   - t: testing.TB. The test handle.
+//   - cfg: Config. The run configuration.
+//   - workload: Workload. The workload to drive.
+//
+// Returns:
+//   - *Report: The samples and results of the run.
+func Run(t testing.TB, cfg Config, workload Workload) *Report {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Duration)
+	defer cancel()
+
+	report := &Report{}
+	var ops, errs atomic.Int64
+	var firstErr error
+	var firstErrOnce sync.Once
+
+	limiter := rate.NewLimiter(rate.Inf, 0)
+	if cfg.OpsPerSecond > 0 {
+		limiter = rate.NewLimiter(rate.Limit(cfg.OpsPerSecond), 1)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for limiter.Wait(ctx) == nil {
+				if err := workload(ctx, worker); err != nil && ctx.Err() == nil {
+					errs.Add(1)
+					firstErrOnce.Do(func() { firstErr = err })
+				}
+				ops.Add(1)
+			}
+		}(i)
+	}
+
+	ticker := time.NewTicker(cfg.SampleInterval)
+	defer ticker.Stop()
+	start := time.Now()
+	for done := false; !done; {
+		select {
+		case <-ctx.Done():
+			done = true
+		case <-ticker.C:
+			sample := TakeSample()
+			report.Samples = append(report.Samples, sample)
+			t.Logf("soak t=%s goroutines=%d heap=%dKiB fds=%d ops=%d errors=%d",
+				sample.Time.Sub(start).Round(time.Second), sample.Goroutines, sample.HeapAlloc/1024, sample.OpenFDs, ops.Load(), errs.Load())
+		}
+	}
+	wg.Wait()
+
+	report.Operations = ops.Load()
+	report.Errors = errs.Load()
+	report.Leaks = DetectLeaks(cfg, report.Samples)
+
+	if report.Errors > 0 {
+		t.Errorf("soak workload failed %d of %d operations; first error: %v", report.Errors, report.Operations, firstErr)
+	}
+	for _, leak := range report.Leaks {
+		t.Errorf("soak leak detected: %v", leak)
+	}
+	return report
+}
+
+// DetectLeaks checks the post-warm-up samples for monotonic growth in
+// goroutines, heap and file descriptors.
+//
+// Parameters:
+//   - cfg: Config. The run configuration (warm-up, windows and slack).
+//   - samples: []Sample. The samples, in time order.
+//
+// Returns:
+//   - []error: One error per leaking resource.
+func DetectLeaks(cfg Config, samples []Sample) []error {
+	if len(samples) == 0 {
+		return nil
+	}
+	cutoff := samples[0].Time.Add(cfg.WarmUp)
+	var steady []Sample
+	for _, s := range samples {
+		if !s.Time.Before(cutoff) {
+			steady = append(steady, s)
+		}
+	}
+
+	var leaks []error
+	check := func(name string, value func(Sample) float64, exceeds func(first, last float64) bool) {
+		if err := detectGrowth(name, steady, cfg.Windows, value, exceeds); err != nil {
+			leaks = append(leaks, err)
+		}
+	}
+	check("goroutines", func(s Sample) float64 { return float64(s.Goroutines) },
+		func(first, last float64) bool { return last-first > cfg.GoroutineSlack })
+	check("heap", func(s Sample) float64 { return float64(s.HeapAlloc) },
+		func(first, last float64) bool { return last > first*(1+cfg.HeapSlack) })
+	if len(steady) > 0 && steady[0].OpenFDs >= 0 {
+		check("file descriptors", func(s Sample) float64 { return float64(s.OpenFDs) },
+			func(first, last float64) bool { return last-first > cfg.FDSlack })
+	}
+	return leaks
+}
+
+// detectGrowth splits samples into windows and reports growth if the median
+// rises in every window and the end-to-end growth exceeds the slack. Using
+// medians keeps GC and traffic spikes from reading as a trend.
+func detectGrowth(name string, samples []Sample, windows int, value func(Sample) float64, exceeds func(first, last float64) bool) error {
+	if windows < 2 || len(samples) < windows {
+		return nil
+	}
+	size := len(samples) / windows
+	medians := make([]float64, windows)
+	for w := 0; w < windows; w++ {
+		end := (w + 1) * size
+		if w == windows-1 {
+			end = len(samples)
+		}
+		values := make([]float64, 0, end-w*size)
+		for _, s := range samples[w*size : end] {
+			values = append(values, value(s))
+		}
+		sort.Float64s(values)
+		medians[w] = values[len(values)/2]
+	}
+	for w := 1; w < windows; w++ {
+		if medians[w] <= medians[w-1] {
+			return nil
+		}
+	}
+	if !exceeds(medians[0], medians[windows-1]) {
+		return nil
+	}
+	return fmt.Errorf("%s grew monotonically across %d windows: %v", name, windows, medians)
+}
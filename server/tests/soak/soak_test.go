@@ -0,0 +1,118 @@
+// Copyright 2026 Author(s) of MCP Any
+// SPDX-License-Identifier: Apache-2.0
+
+package soak
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func samplesOf(goroutines []int, heap []uint64, fds []int) []Sample {
+	start := time.Unix(0, 0)
+	samples := make([]Sample, len(goroutines))
+	for i := range goroutines {
+		samples[i] = Sample{
+			Time:       start.Add(time.Duration(i) * time.Second),
+			Goroutines: goroutines[i],
+			HeapAlloc:  heap[i],
+			OpenFDs:    fds[i],
+		}
+	}
+	return samples
+}
+
+func repeat[T any](v T, n int) []T {
+	out := make([]T, n)
+	for i := range out {
+		out[i] = v
+	}
+	return out
+}
+
+func TestDetectLeaks(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.WarmUp = 0
+
+	t.Run("steady with noise", func(t *testing.T) {
+		goroutines := []int{50, 70, 52, 49, 75, 51, 50, 68, 50, 52, 71, 49}
+		leaks := DetectLeaks(cfg, samplesOf(goroutines, repeat[uint64](1<<20, 12), repeat(30, 12)))
+		assert.Empty(t, leaks)
+	})
+
+	t.Run("goroutine leak", func(t *testing.T) {
+		var goroutines []int
+		for i := 0; i < 12; i++ {
+			goroutines = append(goroutines, 50+i*10)
+		}
+		leaks := DetectLeaks(cfg, samplesOf(goroutines, repeat[uint64](1<<20, 12), repeat(30, 12)))
+		require.Len(t, leaks, 1)
+		assert.Contains(t, leaks[0].Error(), "goroutines")
+	})
+
+	t.Run("heap and fd leak", func(t *testing.T) {
+		var heap []uint64
+		var fds []int
+		for i := 0; i < 12; i++ {
+			heap = append(heap, uint64(1<<20+i*(1<<19)))
+			fds = append(fds, 30+i*5)
+		}
+		leaks := DetectLeaks(cfg, samplesOf(repeat(50, 12), heap, fds))
+		require.Len(t, leaks, 2)
+		assert.Contains(t, leaks[0].Error(), "heap")
+		assert.Contains(t, leaks[1].Error(), "file descriptors")
+	})
+
+	t.Run("growth within slack", func(t *testing.T) {
+		goroutines := []int{50, 50, 50, 51, 51, 51, 52, 52, 52, 53, 53, 53}
+		leaks := DetectLeaks(cfg, samplesOf(goroutines, repeat[uint64](1<<20, 12), repeat(30, 12)))
+		assert.Empty(t, leaks)
+	})
+
+	t.Run("warm-up is ignored", func(t *testing.T) {
+		cfg := cfg
+		cfg.WarmUp = 4 * time.Second
+		goroutines := []int{10, 20, 40, 80, 100, 100, 100, 100, 100, 100, 100, 100}
+		leaks := DetectLeaks(cfg, samplesOf(goroutines, repeat[uint64](1<<20, 12), repeat(-1, 12)))
+		assert.Empty(t, leaks)
+	})
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("MCPANY_SOAK_DURATION", "2h")
+	t.Setenv("MCPANY_SOAK_CONCURRENCY", "32")
+	cfg, err := ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, cfg.Duration)
+	assert.Equal(t, 32, cfg.Concurrency)
+	assert.Equal(t, DefaultConfig().OpsPerSecond, cfg.OpsPerSecond)
+	assert.Equal(t, DefaultConfig().SampleInterval, cfg.SampleInterval)
+
+	t.Setenv("MCPANY_SOAK_WARMUP", "soon")
+	_, err = ConfigFromEnv()
+	assert.Error(t, err)
+}
+
+func TestRun(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Duration = 300 * time.Millisecond
+	cfg.SampleInterval = 20 * time.Millisecond
+	cfg.WarmUp = 0
+	cfg.Concurrency = 2
+	cfg.OpsPerSecond = 0
+
+	report := Run(t, cfg, func(ctx context.Context, _ int) error {
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Millisecond):
+		}
+		return nil
+	})
+	assert.Positive(t, report.Operations)
+	assert.Zero(t, report.Errors)
+	assert.NotEmpty(t, report.Samples)
+}